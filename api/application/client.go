@@ -113,6 +113,11 @@ type DeployArgs struct {
 	// value being the unique ID of a pre-uploaded resources in
 	// storage.
 	Resources map[string]string
+
+	// AttachStorageSnapshots maps charm storage names to the IDs of
+	// storage snapshots from which the storage of the application's
+	// units should be restored.
+	AttachStorageSnapshots map[string]string
 }
 
 // Deploy obtains the charm, either locally or from the charm store, and deploys
@@ -127,6 +132,9 @@ func (c *Client) Deploy(args DeployArgs) error {
 			return errors.New("this juju controller does not support AttachStorage")
 		}
 	}
+	if len(args.AttachStorageSnapshots) > 0 && c.BestAPIVersion() < 13 {
+		return errors.New("this juju controller does not support AttachStorageSnapshots")
+	}
 	attachStorage := make([]string, len(args.AttachStorage))
 	for i, id := range args.AttachStorage {
 		if !names.IsValidStorage(id) {
@@ -150,6 +158,8 @@ func (c *Client) Deploy(args DeployArgs) error {
 			AttachStorage:    attachStorage,
 			EndpointBindings: args.EndpointBindings,
 			Resources:        args.Resources,
+
+			AttachStorageSnapshots: args.AttachStorageSnapshots,
 		}},
 	}
 	var results params.ErrorResults
@@ -404,6 +414,11 @@ type AddUnitsParams struct {
 	// attached to the application unit that will be deployed. This
 	// may be non-empty only if NumUnits is 1.
 	AttachStorage []string
+
+	// AttachStorageSnapshots maps charm storage names to the IDs of
+	// storage snapshots from which the storage of the new units
+	// should be restored.
+	AttachStorageSnapshots map[string]string
 }
 
 // AddUnits adds a given number of units to an application using the specified
@@ -417,6 +432,9 @@ func (c *Client) AddUnits(args AddUnitsParams) ([]string, error) {
 			return nil, errors.New("this juju controller does not support AttachStorage")
 		}
 	}
+	if len(args.AttachStorageSnapshots) > 0 && c.BestAPIVersion() < 13 {
+		return nil, errors.New("this juju controller does not support AttachStorageSnapshots")
+	}
 	attachStorage := make([]string, len(args.AttachStorage))
 	for i, id := range args.AttachStorage {
 		if !names.IsValidStorage(id) {
@@ -431,6 +449,8 @@ func (c *Client) AddUnits(args AddUnitsParams) ([]string, error) {
 		Placement:       args.Placement,
 		Policy:          args.Policy,
 		AttachStorage:   attachStorage,
+
+		AttachStorageSnapshots: args.AttachStorageSnapshots,
 	}, results)
 	return results.Units, err
 }
@@ -1006,6 +1026,28 @@ func (c *Client) ApplicationsInfo(applications []names.ApplicationTag) ([]params
 	return out.Results, nil
 }
 
+// UnitsInfo retrieves units information, including the relation data
+// of every relation each unit has joined.
+func (c *Client) UnitsInfo(units []names.UnitTag) ([]params.UnitInfoResult, error) {
+	if apiVersion := c.BestAPIVersion(); apiVersion < 12 {
+		return nil, errors.NotSupportedf("UnitsInfo for Application facade v%v", apiVersion)
+	}
+	all := make([]params.Entity, len(units))
+	for i, one := range units {
+		all[i] = params.Entity{Tag: one.String()}
+	}
+	in := params.Entities{Entities: all}
+	var out params.UnitInfoResults
+	err := c.facade.FacadeCall("UnitsInfo", in, &out)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if resultsLen := len(out.Results); resultsLen != len(units) {
+		return nil, errors.Errorf("expected %d results, got %d", len(units), resultsLen)
+	}
+	return out.Results, nil
+}
+
 // MergeBindings merges an operator-defined bindings list with the existing
 // application bindings.
 func (c *Client) MergeBindings(req params.ApplicationMergeBindingsArgs) error {
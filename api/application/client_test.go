@@ -199,6 +199,51 @@ func (s *applicationSuite) TestAddUnits(c *gc.C) {
 	c.Assert(units, jc.DeepEquals, []string{"foo/0"})
 }
 
+func (s *applicationSuite) TestAddUnitsAttachStorageSnapshots(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, "AddUnits")
+				args, ok := a.(params.AddApplicationUnits)
+				c.Assert(ok, jc.IsTrue)
+				c.Assert(args.AttachStorageSnapshots, jc.DeepEquals, map[string]string{"data": "0/1"})
+				result := response.(*params.AddApplicationUnitsResults)
+				result.Units = []string{"foo/0", "foo/1"}
+				return nil
+			},
+		),
+		BestVersion: 13,
+	})
+
+	units, err := client.AddUnits(application.AddUnitsParams{
+		ApplicationName:        "foo",
+		NumUnits:               2,
+		AttachStorageSnapshots: map[string]string{"data": "0/1"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, jc.DeepEquals, []string{"foo/0", "foo/1"})
+}
+
+func (s *applicationSuite) TestAddUnitsAttachStorageSnapshotsV12(c *gc.C) {
+	var called bool
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				return nil
+			},
+		),
+		BestVersion: 12, // v12 does not support AttachStorageSnapshots
+	})
+
+	_, err := client.AddUnits(application.AddUnitsParams{
+		NumUnits:               1,
+		AttachStorageSnapshots: map[string]string{"data": "0/1"},
+	})
+	c.Assert(err, gc.ErrorMatches, "this juju controller does not support AttachStorageSnapshots")
+	c.Assert(called, jc.IsFalse)
+}
+
 func (s *applicationSuite) TestAddUnitsAttachStorageV4(c *gc.C) {
 	var called bool
 	client := application.NewClient(basetesting.BestVersionCaller{
@@ -1559,3 +1604,79 @@ func (s *applicationSuite) TestApplicationsInfoResultMismatch(c *gc.C) {
 	c.Check(called, jc.IsTrue)
 	c.Assert(err, gc.ErrorMatches, "expected 2 results, got 3")
 }
+
+func (s *applicationSuite) TestUnitsInfoPriorV12(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, a, response interface{}) error {
+			called = true
+			return nil
+		},
+	)
+	client := application.NewClient(basetesting.BestVersionCaller{
+		BestVersion:   11,
+		APICallerFunc: apiCaller,
+	})
+	_, err := client.UnitsInfo(nil)
+	c.Assert(err, gc.ErrorMatches, "UnitsInfo for Application facade v11 not supported")
+	c.Assert(called, jc.IsFalse)
+}
+
+func (s *applicationSuite) TestUnitsInfo(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, a, response interface{}) error {
+			called = true
+			c.Assert(request, gc.Equals, "UnitsInfo")
+			c.Assert(a, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{
+					{Tag: "unit-foo-0"},
+					{Tag: "unit-bar-1"},
+				}})
+
+			result, ok := response.(*params.UnitInfoResults)
+			c.Assert(ok, jc.IsTrue)
+			result.Results = []params.UnitInfoResult{
+				{Error: &params.Error{Message: "boom"}},
+				{Result: &params.UnitResult{
+					Tag:             "unit-bar-1",
+					WorkloadVersion: "666",
+					Charm:           "cs:bar-1",
+					Leader:          true,
+					RelationData: []params.EndpointRelationData{{
+						RelationId:      1,
+						Endpoint:        "db",
+						RelatedEndpoint: "server",
+						ApplicationData: map[string]interface{}{"foo": "bar"},
+						UnitRelationData: map[string]params.RelationData{
+							"baz/0": {InScope: true, UnitData: map[string]interface{}{"host": "10.0.0.1"}},
+						},
+					}},
+				}},
+			}
+			return nil
+		},
+	)
+
+	client := application.NewClient(basetesting.BestVersionCaller{
+		BestVersion:   12,
+		APICallerFunc: apiCaller,
+	})
+	results, err := client.UnitsInfo([]names.UnitTag{
+		names.NewUnitTag("foo/0"),
+		names.NewUnitTag("bar/1"),
+	})
+	c.Check(called, jc.IsTrue)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, gc.ErrorMatches, "boom")
+	c.Assert(results[1].Result.RelationData, jc.DeepEquals, []params.EndpointRelationData{{
+		RelationId:      1,
+		Endpoint:        "db",
+		RelatedEndpoint: "server",
+		ApplicationData: map[string]interface{}{"foo": "bar"},
+		UnitRelationData: map[string]params.RelationData{
+			"baz/0": {InScope: true, UnitData: map[string]interface{}{"host": "10.0.0.1"}},
+		},
+	}})
+}
@@ -4,6 +4,7 @@
 package diskmanager
 
 import (
+	"github.com/juju/errors"
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/api/base"
@@ -43,3 +44,46 @@ func (st *State) SetMachineBlockDevices(devices []storage.BlockDevice) error {
 	}
 	return results.OneError()
 }
+
+// FilesystemAttachments returns the provisioned and mounted filesystem
+// attachments of the machine identified by the authenticated machine tag.
+func (st *State) FilesystemAttachments() ([]params.FilesystemAttachment, error) {
+	if st.facade.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("reporting filesystem usage by this version of Juju")
+	}
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: st.tag.String()}},
+	}
+	var results params.FilesystemAttachmentsResults
+	if err := st.facade.FacadeCall("FilesystemAttachments", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if n := len(results.Results); n != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", n)
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results[0].Attachments, nil
+}
+
+// SetFilesystemUsage records the usage of filesystems attached to the
+// machine identified by the authenticated machine tag. The MachineTag
+// field of each of the supplied usages is set to that machine's tag.
+func (st *State) SetFilesystemUsage(usage []params.FilesystemAttachmentUsage) error {
+	if st.facade.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("reporting filesystem usage by this version of Juju")
+	}
+	args := params.SetFilesystemUsage{
+		Usage: make([]params.FilesystemAttachmentUsage, len(usage)),
+	}
+	for i, u := range usage {
+		u.MachineTag = st.tag.String()
+		args.Usage[i] = u
+	}
+	var results params.ErrorResults
+	if err := st.facade.FacadeCall("SetFilesystemUsage", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.Combine()
+}
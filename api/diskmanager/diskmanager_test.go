@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 
+	jujuerrors "github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v3"
@@ -124,3 +125,86 @@ func (s *DiskManagerSuite) TestSetMachineBlockDevicesResultCountInvalid(c *gc.C)
 		c.Check(err, gc.ErrorMatches, fmt.Sprintf("expected 1 result, got %d", n))
 	}
 }
+
+func (s *DiskManagerSuite) TestFilesystemAttachments(c *gc.C) {
+	var callCount int
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "DiskManager")
+			c.Check(request, gc.Equals, "FilesystemAttachments")
+			c.Check(arg, gc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "machine-123"}},
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.FilesystemAttachmentsResults{})
+			*(result.(*params.FilesystemAttachmentsResults)) = params.FilesystemAttachmentsResults{
+				Results: []params.FilesystemAttachmentsResult{{
+					Attachments: []params.FilesystemAttachment{{
+						FilesystemTag: "filesystem-123-0",
+						MachineTag:    "machine-123",
+						Info:          params.FilesystemAttachmentInfo{MountPoint: "/srv"},
+					}},
+				}},
+			}
+			callCount++
+			return nil
+		}),
+		BestVersion: 3,
+	}
+	st := diskmanager.NewState(apiCaller, names.NewMachineTag("123"))
+	attachments, err := st.FilesystemAttachments()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(attachments, jc.DeepEquals, []params.FilesystemAttachment{{
+		FilesystemTag: "filesystem-123-0",
+		MachineTag:    "machine-123",
+		Info:          params.FilesystemAttachmentInfo{MountPoint: "/srv"},
+	}})
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *DiskManagerSuite) TestSetFilesystemUsage(c *gc.C) {
+	var callCount int
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "DiskManager")
+			c.Check(request, gc.Equals, "SetFilesystemUsage")
+			c.Check(arg, gc.DeepEquals, params.SetFilesystemUsage{
+				Usage: []params.FilesystemAttachmentUsage{{
+					FilesystemTag: "filesystem-123-0",
+					MachineTag:    "machine-123",
+					Usage:         params.FilesystemUsage{UsedBytes: 1, FreeBytes: 2},
+				}},
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{
+					Error: &params.Error{Message: "MSG"},
+				}},
+			}
+			callCount++
+			return nil
+		}),
+		BestVersion: 3,
+	}
+	st := diskmanager.NewState(apiCaller, names.NewMachineTag("123"))
+	err := st.SetFilesystemUsage([]params.FilesystemAttachmentUsage{{
+		FilesystemTag: "filesystem-123-0",
+		Usage:         params.FilesystemUsage{UsedBytes: 1, FreeBytes: 2},
+	}})
+	c.Check(err, gc.ErrorMatches, "MSG")
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *DiskManagerSuite) TestFilesystemUsageNotSupported(c *gc.C) {
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		}),
+		BestVersion: 2,
+	}
+	st := diskmanager.NewState(apiCaller, names.NewMachineTag("123"))
+	_, err := st.FilesystemAttachments()
+	c.Check(err, jc.Satisfies, jujuerrors.IsNotSupported)
+	err = st.SetFilesystemUsage(nil)
+	c.Check(err, jc.Satisfies, jujuerrors.IsNotSupported)
+}
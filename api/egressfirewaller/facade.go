@@ -0,0 +1,65 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package egressfirewaller implements the client-side API facade used
+// by the egressfirewaller worker.
+package egressfirewaller
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/api/base"
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/watcher"
+)
+
+// Facade provides access to the EgressFirewaller API facade.
+type Facade struct {
+	caller base.FacadeCaller
+}
+
+// NewFacade creates a new client-side EgressFirewaller facade.
+func NewFacade(caller base.APICaller) *Facade {
+	return &Facade{
+		caller: base.NewFacadeCaller(caller, "EgressFirewaller"),
+	}
+}
+
+// WatchEgressRules returns a NotifyWatcher which notifies whenever
+// the egress rules of the machine may have changed.
+func (f *Facade) WatchEgressRules(tag names.MachineTag) (watcher.NotifyWatcher, error) {
+	args := params.Entities{Entities: []params.Entity{{Tag: tag.String()}}}
+	var results params.NotifyWatchResults
+	if err := f.caller.FacadeCall("WatchEgressRules", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewNotifyWatcher(f.caller.RawAPICaller(), result), nil
+}
+
+// EgressRules returns the egress rules of the machine, whether egress
+// from the machine is restricted, and whether the restriction is
+// enforced by the provider.
+func (f *Facade) EgressRules(tag names.MachineTag) (params.MachineEgressRulesResult, error) {
+	args := params.Entities{Entities: []params.Entity{{Tag: tag.String()}}}
+	var results params.MachineEgressRulesResults
+	if err := f.caller.FacadeCall("EgressRules", args, &results); err != nil {
+		return params.MachineEgressRulesResult{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return params.MachineEgressRulesResult{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.MachineEgressRulesResult{}, result.Error
+	}
+	return result, nil
+}
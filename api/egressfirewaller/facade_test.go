@@ -0,0 +1,92 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package egressfirewaller_test
+
+import (
+	"errors"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/egressfirewaller"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type facadeSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&facadeSuite{})
+
+func (s *facadeSuite) TestEgressRules(c *gc.C) {
+	stub := new(testing.Stub)
+	expected := params.MachineEgressRulesResult{
+		Restricted: true,
+		Rules: []params.EgressRule{{
+			DestinationCIDR: "10.0.0.0/8",
+			PortRange:       params.PortRange{FromPort: 5432, ToPort: 5432, Protocol: "tcp"},
+		}},
+	}
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		c.Check(objType, gc.Equals, "EgressFirewaller")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		stub.AddCall(request, args)
+		*response.(*params.MachineEgressRulesResults) = params.MachineEgressRulesResults{
+			Results: []params.MachineEgressRulesResult{expected},
+		}
+		return nil
+	})
+	facade := egressfirewaller.NewFacade(apiCaller)
+
+	result, err := facade.EgressRules(names.NewMachineTag("42"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, expected)
+
+	stub.CheckCalls(c, []testing.StubCall{{
+		"EgressRules", []interface{}{params.Entities{
+			Entities: []params.Entity{{Tag: "machine-42"}},
+		}},
+	}})
+}
+
+func (s *facadeSuite) TestEgressRulesError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		*response.(*params.MachineEgressRulesResults) = params.MachineEgressRulesResults{
+			Results: []params.MachineEgressRulesResult{{
+				Error: &params.Error{Message: "boom"},
+			}},
+		}
+		return nil
+	})
+	facade := egressfirewaller.NewFacade(apiCaller)
+	_, err := facade.EgressRules(names.NewMachineTag("42"))
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *facadeSuite) TestCallError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		return errors.New("bam")
+	})
+	facade := egressfirewaller.NewFacade(apiCaller)
+	_, err := facade.EgressRules(names.NewMachineTag("42"))
+	c.Assert(err, gc.ErrorMatches, "bam")
+	_, err = facade.WatchEgressRules(names.NewMachineTag("42"))
+	c.Assert(err, gc.ErrorMatches, "bam")
+}
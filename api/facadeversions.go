@@ -18,7 +18,7 @@ var facadeVersions = map[string]int{
 	"AllModelWatcher":              2,
 	"AllWatcher":                   1,
 	"Annotations":                  2,
-	"Application":                  11,
+	"Application":                  13,
 	"ApplicationOffers":            2,
 	"ApplicationScaler":            1,
 	"Backups":                      2,
@@ -41,13 +41,14 @@ var facadeVersions = map[string]int{
 	"CrossController":              1,
 	"CrossModelRelations":          1,
 	"Deployer":                     1,
-	"DiskManager":                  2,
+	"DiskManager":                  3,
+	"EgressFirewaller":             1,
 	"EntityWatcher":                2,
 	"ExternalControllerUpdater":    1,
 	"FanConfigurer":                1,
 	"FilesystemAttachmentsWatcher": 2,
-	"Firewaller":                   5,
-	"FirewallRules":                1,
+	"Firewaller":                   7,
+	"FirewallRules":                3,
 	"HighAvailability":             2,
 	"HostKeyReporter":              1,
 	"ImageManager":                 2,
@@ -62,9 +63,9 @@ var facadeVersions = map[string]int{
 	"LogForwarding":                1,
 	"Logger":                       1,
 	"MachineActions":               1,
-	"MachineManager":               6,
+	"MachineManager":               10,
 	"MachineUndertaker":            1,
-	"Machiner":                     1,
+	"Machiner":                     2,
 	"MeterStatus":                  1,
 	"MetricsAdder":                 2,
 	"MetricsDebug":                 2,
@@ -83,7 +84,7 @@ var facadeVersions = map[string]int{
 	"Payloads":                     1,
 	"PayloadsHookContext":          1,
 	"Pinger":                       1,
-	"Provisioner":                  9,
+	"Provisioner":                  11,
 	"ProxyUpdater":                 2,
 	"Reboot":                       2,
 	"RelationStatusWatcher":        1,
@@ -94,16 +95,16 @@ var facadeVersions = map[string]int{
 	"Resumer":                      2,
 	"RetryStrategy":                1,
 	"Singular":                     2,
-	"Spaces":                       5,
+	"Spaces":                       6,
 	"SSHClient":                    2,
 	"StatusHistory":                2,
-	"Storage":                      6,
-	"StorageProvisioner":           4,
+	"Storage":                      9,
+	"StorageProvisioner":           7,
 	"StringsWatcher":               1,
-	"Subnets":                      3,
+	"Subnets":                      4,
 	"Undertaker":                   1,
 	"UnitAssigner":                 1,
-	"Uniter":                       13,
+	"Uniter":                       14,
 	"Upgrader":                     1,
 	"UpgradeSeries":                1,
 	"UpgradeSteps":                 1,
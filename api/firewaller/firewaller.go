@@ -255,3 +255,85 @@ func (c *Client) FirewallRules(knownServices ...string) ([]params.FirewallRule,
 	}
 	return results.Rules, nil
 }
+
+// WatchFirewallRules returns a NotifyWatcher that notifies of
+// changes to the model's firewall rules.
+func (c *Client) WatchFirewallRules() (watcher.NotifyWatcher, error) {
+	if c.BestAPIVersion() < 6 {
+		return nil, errors.NotSupportedf("watching firewall rules")
+	}
+	var result params.NotifyWatchResult
+	if err := c.facade.FacadeCall("WatchFirewallRules", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewNotifyWatcher(c.facade.RawAPICaller(), result), nil
+}
+
+// ModelIngressRules returns the model's ingress rules.
+func (c *Client) ModelIngressRules() ([]params.FirewallRule, error) {
+	if c.BestAPIVersion() < 6 {
+		return nil, errors.NotSupportedf("model ingress rules")
+	}
+	var results params.ListFirewallRulesResults
+	if err := c.facade.FacadeCall("ModelIngressRules", nil, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Rules, nil
+}
+
+// WatchEgressRules returns a NotifyWatcher that notifies of
+// changes to the egress rules of the model's applications.
+func (c *Client) WatchEgressRules() (watcher.NotifyWatcher, error) {
+	if c.BestAPIVersion() < 7 {
+		return nil, errors.NotSupportedf("watching egress rules")
+	}
+	var result params.NotifyWatchResult
+	if err := c.facade.FacadeCall("WatchEgressRules", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewNotifyWatcher(c.facade.RawAPICaller(), result), nil
+}
+
+// MachineEgressRules returns the egress rules of the machine, and
+// whether egress from the machine is restricted.
+func (c *Client) MachineEgressRules(tag names.MachineTag) ([]params.EgressRule, bool, error) {
+	if c.BestAPIVersion() < 7 {
+		return nil, false, errors.NotSupportedf("egress rules")
+	}
+	args := params.Entities{Entities: []params.Entity{{Tag: tag.String()}}}
+	var results params.MachineEgressRulesResults
+	if err := c.facade.FacadeCall("MachineEgressRules", args, &results); err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, false, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	return result.Rules, result.Restricted, nil
+}
+
+// SetMachineEgressEnforcedByProvider records whether the provider's
+// firewall enforces the egress rules of the machine.
+func (c *Client) SetMachineEgressEnforcedByProvider(tag names.MachineTag, enforced bool) error {
+	if c.BestAPIVersion() < 7 {
+		return errors.NotSupportedf("egress rules")
+	}
+	args := params.MachineEgressEnforcements{Machines: []params.MachineEgressEnforcement{{
+		Tag:              tag.String(),
+		ProviderEnforced: enforced,
+	}}}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetMachineEgressEnforcement", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
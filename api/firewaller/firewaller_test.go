@@ -4,6 +4,7 @@
 package firewaller_test
 
 import (
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
@@ -248,3 +249,127 @@ func (s *firewallerSuite) TestFirewallRules(c *gc.C) {
 	c.Assert(result, gc.HasLen, 1)
 	c.Check(callCount, gc.Equals, 1)
 }
+
+func (s *firewallerSuite) TestModelIngressRules(c *gc.C) {
+	var callCount int
+	apiCaller := testing.BestVersionCaller{
+		BestVersion: 6,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "Firewaller")
+			c.Check(version, gc.Equals, 6)
+			c.Check(request, gc.Equals, "ModelIngressRules")
+			c.Assert(result, gc.FitsTypeOf, &params.ListFirewallRulesResults{})
+			*(result.(*params.ListFirewallRulesResults)) = params.ListFirewallRulesResults{
+				Rules: []params.FirewallRule{{
+					Name:      "web",
+					PortRange: &params.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+				}},
+			}
+			callCount++
+			return nil
+		},
+	}
+	client, err := firewaller.NewClient(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := client.ModelIngressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.HasLen, 1)
+	c.Check(result[0].Name, gc.Equals, "web")
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *firewallerSuite) TestModelIngressRulesNotSupported(c *gc.C) {
+	apiCaller := testing.BestVersionCaller{
+		BestVersion: 5,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		},
+	}
+	client, err := firewaller.NewClient(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.ModelIngressRules()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = client.WatchFirewallRules()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *firewallerSuite) TestMachineEgressRules(c *gc.C) {
+	var callCount int
+	apiCaller := testing.BestVersionCaller{
+		BestVersion: 7,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "Firewaller")
+			c.Check(version, gc.Equals, 7)
+			c.Check(request, gc.Equals, "MachineEgressRules")
+			c.Check(arg, jc.DeepEquals, params.Entities{Entities: []params.Entity{{Tag: "machine-0"}}})
+			c.Assert(result, gc.FitsTypeOf, &params.MachineEgressRulesResults{})
+			*(result.(*params.MachineEgressRulesResults)) = params.MachineEgressRulesResults{
+				Results: []params.MachineEgressRulesResult{{
+					Restricted: true,
+					Rules: []params.EgressRule{{
+						DestinationCIDR: "10.0.0.0/8",
+						PortRange:       params.PortRange{FromPort: 5432, ToPort: 5432, Protocol: "tcp"},
+					}},
+				}},
+			}
+			callCount++
+			return nil
+		},
+	}
+	client, err := firewaller.NewClient(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	rules, restricted, err := client.MachineEgressRules(names.NewMachineTag("0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(restricted, jc.IsTrue)
+	c.Assert(rules, jc.DeepEquals, []params.EgressRule{{
+		DestinationCIDR: "10.0.0.0/8",
+		PortRange:       params.PortRange{FromPort: 5432, ToPort: 5432, Protocol: "tcp"},
+	}})
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *firewallerSuite) TestSetMachineEgressEnforcedByProvider(c *gc.C) {
+	var callCount int
+	apiCaller := testing.BestVersionCaller{
+		BestVersion: 7,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "Firewaller")
+			c.Check(version, gc.Equals, 7)
+			c.Check(request, gc.Equals, "SetMachineEgressEnforcement")
+			c.Check(arg, jc.DeepEquals, params.MachineEgressEnforcements{Machines: []params.MachineEgressEnforcement{{
+				Tag:              "machine-0",
+				ProviderEnforced: true,
+			}}})
+			c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			callCount++
+			return nil
+		},
+	}
+	client, err := firewaller.NewClient(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.SetMachineEgressEnforcedByProvider(names.NewMachineTag("0"), true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *firewallerSuite) TestEgressRulesNotSupported(c *gc.C) {
+	apiCaller := testing.BestVersionCaller{
+		BestVersion: 6,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		},
+	}
+	client, err := firewaller.NewClient(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = client.MachineEgressRules(names.NewMachineTag("0"))
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = client.WatchEgressRules()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = client.SetMachineEgressEnforcedByProvider(names.NewMachineTag("0"), true)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
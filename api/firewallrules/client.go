@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/network"
 )
 
 // Client allows access to the firewall rules API end point.
@@ -44,6 +45,43 @@ func (c *Client) SetFirewallRule(service string, whiteListCidrs []string) error
 	return results.OneError()
 }
 
+// SetIngressRule creates or updates a model ingress rule, which allows
+// ingress to the port range from the whitelisted subnets on the model's
+// machines, or only on those hosting units of the application if it is
+// not empty.
+func (c *Client) SetIngressRule(name string, portRange network.PortRange, whiteListCidrs []string, application string) error {
+	if c.BestAPIVersion() < 2 {
+		return errors.NotSupportedf("model ingress rules")
+	}
+	paramsPortRange := params.FromNetworkPortRange(portRange)
+	args := params.FirewallRuleArgs{
+		Args: []params.FirewallRule{{
+			Name:           name,
+			PortRange:      &paramsPortRange,
+			WhitelistCIDRS: whiteListCidrs,
+			Application:    application,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetFirewallRules", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// RemoveFirewallRule removes a model ingress rule.
+func (c *Client) RemoveFirewallRule(name string) error {
+	if c.BestAPIVersion() < 2 {
+		return errors.NotSupportedf("model ingress rules")
+	}
+	args := params.FirewallRuleNames{Names: []string{name}}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("RemoveFirewallRules", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
 // ListFirewallRules returns all the firewall rules.
 func (c *Client) ListFirewallRules() ([]params.FirewallRule, error) {
 	var results params.ListFirewallRulesResults
@@ -52,3 +90,35 @@ func (c *Client) ListFirewallRules() ([]params.FirewallRule, error) {
 	}
 	return results.Rules, nil
 }
+
+// SetEgressRules replaces the egress rules of the application. Setting
+// no rules lifts the application's egress restriction.
+func (c *Client) SetEgressRules(application string, rules []params.EgressRule) error {
+	if c.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("egress rules")
+	}
+	args := params.SetEgressRulesArgs{
+		Args: []params.ApplicationEgressRules{{
+			Application: application,
+			Rules:       rules,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetEgressRules", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// ListEgressRules returns the egress rules of the applications
+// with restricted egress.
+func (c *Client) ListEgressRules() ([]params.ApplicationEgressRules, error) {
+	if c.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("egress rules")
+	}
+	var results params.ListEgressRulesResults
+	if err := c.facade.FacadeCall("ListEgressRules", nil, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
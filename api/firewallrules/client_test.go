@@ -12,6 +12,7 @@ import (
 	"github.com/juju/juju/api/firewallrules"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/testing"
 )
 
@@ -141,3 +142,126 @@ func (s *FirewallRulesSuite) TestListError(c *gc.C) {
 	c.Assert(errors.Cause(err), gc.ErrorMatches, "fail")
 	c.Assert(called, jc.IsTrue)
 }
+
+func (s *FirewallRulesSuite) TestSetIngressRule(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		BestVersion: 2,
+		APICallerFunc: func(objType string, version int, id, request string, a, result interface{}) error {
+			c.Check(objType, gc.Equals, "FirewallRules")
+			c.Check(request, gc.Equals, "SetFirewallRules")
+			c.Check(a, jc.DeepEquals, params.FirewallRuleArgs{
+				Args: []params.FirewallRule{{
+					Name:           "monitoring",
+					PortRange:      &params.PortRange{FromPort: 9100, ToPort: 9110, Protocol: "tcp"},
+					WhitelistCIDRS: []string{"10.0.0.0/8"},
+					Application:    "mysql",
+				}},
+			})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		},
+	}
+	client := firewallrules.NewClient(apiCaller)
+	err := client.SetIngressRule("monitoring", network.MustParsePortRange("9100-9110/tcp"), []string{"10.0.0.0/8"}, "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *FirewallRulesSuite) TestSetIngressRuleNotSupported(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		BestVersion: 1,
+		APICallerFunc: func(objType string, version int, id, request string, a, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		},
+	}
+	client := firewallrules.NewClient(apiCaller)
+	err := client.SetIngressRule("monitoring", network.MustParsePortRange("9100/tcp"), nil, "")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *FirewallRulesSuite) TestRemoveFirewallRule(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		BestVersion: 2,
+		APICallerFunc: func(objType string, version int, id, request string, a, result interface{}) error {
+			c.Check(objType, gc.Equals, "FirewallRules")
+			c.Check(request, gc.Equals, "RemoveFirewallRules")
+			c.Check(a, jc.DeepEquals, params.FirewallRuleNames{Names: []string{"web"}})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{Error: common.ServerError(errors.NotFoundf(`firewall rule "web"`))}},
+			}
+			return nil
+		},
+	}
+	client := firewallrules.NewClient(apiCaller)
+	err := client.RemoveFirewallRule("web")
+	c.Assert(err, gc.ErrorMatches, `firewall rule "web" not found`)
+}
+
+func (s *FirewallRulesSuite) TestSetEgressRules(c *gc.C) {
+	rules := []params.EgressRule{{
+		DestinationCIDR: "10.0.0.0/8",
+		PortRange:       params.PortRange{FromPort: 5432, ToPort: 5432, Protocol: "tcp"},
+	}}
+	apiCaller := basetesting.BestVersionCaller{
+		BestVersion: 3,
+		APICallerFunc: func(objType string, version int, id, request string, a, result interface{}) error {
+			c.Check(objType, gc.Equals, "FirewallRules")
+			c.Check(request, gc.Equals, "SetEgressRules")
+			c.Check(a, jc.DeepEquals, params.SetEgressRulesArgs{
+				Args: []params.ApplicationEgressRules{{
+					Application: "mysql",
+					Rules:       rules,
+				}},
+			})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		},
+	}
+	client := firewallrules.NewClient(apiCaller)
+	err := client.SetEgressRules("mysql", rules)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *FirewallRulesSuite) TestListEgressRules(c *gc.C) {
+	expected := []params.ApplicationEgressRules{{
+		Application: "mysql",
+		Rules: []params.EgressRule{{
+			DestinationCIDR: "10.0.0.0/8",
+			PortRange:       params.PortRange{FromPort: 5432, ToPort: 5432, Protocol: "tcp"},
+		}},
+	}}
+	apiCaller := basetesting.BestVersionCaller{
+		BestVersion: 3,
+		APICallerFunc: func(objType string, version int, id, request string, a, result interface{}) error {
+			c.Check(objType, gc.Equals, "FirewallRules")
+			c.Check(request, gc.Equals, "ListEgressRules")
+			*(result.(*params.ListEgressRulesResults)) = params.ListEgressRulesResults{
+				Results: expected,
+			}
+			return nil
+		},
+	}
+	client := firewallrules.NewClient(apiCaller)
+	results, err := client.ListEgressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, expected)
+}
+
+func (s *FirewallRulesSuite) TestEgressRulesNotSupported(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		BestVersion: 2,
+		APICallerFunc: func(objType string, version int, id, request string, a, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		},
+	}
+	client := firewallrules.NewClient(apiCaller)
+	err := client.SetEgressRules("mysql", nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = client.ListEgressRules()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -13,6 +13,7 @@ import (
 	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/watcher"
 )
 
@@ -66,13 +67,32 @@ func (client *Client) ForceDestroyMachines(machines ...string) ([]params.Destroy
 // TODO(wallyworld) - for Juju 3.0, this should be the preferred api to use.
 func (client *Client) DestroyMachinesWithParams(force, keep bool, maxWait *time.Duration, machines ...string) ([]params.DestroyMachineResult, error) {
 	args := params.DestroyMachinesParams{
-		Force:       force,
-		Keep:        keep,
-		MachineTags: make([]string, 0, len(machines)),
+		Force: force,
+		Keep:  keep,
 	}
 	if client.BestAPIVersion() > 5 {
 		args.MaxWait = maxWait
 	}
+	return client.destroyMachinesWithParams(args, machines)
+}
+
+// DestroyProtectedMachines removes any termination protection from the
+// given machines, and then removes them from the model.
+func (client *Client) DestroyProtectedMachines(force, keep bool, maxWait *time.Duration, machines ...string) ([]params.DestroyMachineResult, error) {
+	if client.BestAPIVersion() < 7 {
+		return nil, errors.NotSupportedf("removing protected machines")
+	}
+	args := params.DestroyMachinesParams{
+		Force:            force,
+		Keep:             keep,
+		MaxWait:          maxWait,
+		ForceUnprotected: true,
+	}
+	return client.destroyMachinesWithParams(args, machines)
+}
+
+func (client *Client) destroyMachinesWithParams(args params.DestroyMachinesParams, machines []string) ([]params.DestroyMachineResult, error) {
+	args.MachineTags = make([]string, 0, len(machines))
 	allResults := make([]params.DestroyMachineResult, len(machines))
 	index := make([]int, 0, len(machines))
 	for i, machineId := range machines {
@@ -100,6 +120,115 @@ func (client *Client) DestroyMachinesWithParams(force, keep bool, maxWait *time.
 	return allResults, nil
 }
 
+// SetMachineProtection sets whether the cloud instances of the given
+// machines are protected from termination.
+func (client *Client) SetMachineProtection(protected bool, machines ...string) ([]params.ErrorResult, error) {
+	if client.BestAPIVersion() < 7 {
+		return nil, errors.NotSupportedf("machine protection")
+	}
+	args := params.MachineProtectionArgs{
+		Args: make([]params.MachineProtectionArg, len(machines)),
+	}
+	for i, machineId := range machines {
+		if !names.IsValidMachine(machineId) {
+			return nil, errors.NotValidf("machine ID %q", machineId)
+		}
+		args.Args[i] = params.MachineProtectionArg{
+			MachineTag: names.NewMachineTag(machineId).String(),
+			Protected:  protected,
+		}
+	}
+	var results params.ErrorResults
+	if err := client.facade.FacadeCall("SetMachineProtection", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if n := len(results.Results); n != len(machines) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(machines), n)
+	}
+	return results.Results, nil
+}
+
+// ResizeMachines changes the hardware of the cloud instances of the given
+// machines to satisfy the given constraints, which are combined with each
+// machine's existing constraints.
+func (client *Client) ResizeMachines(cons constraints.Value, machines ...string) ([]params.ResizeMachineResult, error) {
+	if client.BestAPIVersion() < 8 {
+		return nil, errors.NotSupportedf("resizing machines")
+	}
+	args := params.ResizeMachineArgs{
+		Args: make([]params.ResizeMachineArg, len(machines)),
+	}
+	for i, machineId := range machines {
+		if !names.IsValidMachine(machineId) {
+			return nil, errors.NotValidf("machine ID %q", machineId)
+		}
+		args.Args[i] = params.ResizeMachineArg{
+			MachineTag:  names.NewMachineTag(machineId).String(),
+			Constraints: cons,
+		}
+	}
+	var results params.ResizeMachineResults
+	if err := client.facade.FacadeCall("ResizeMachines", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if n := len(results.Results); n != len(machines) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(machines), n)
+	}
+	return results.Results, nil
+}
+
+// NetworkConfigDiff returns, for each of the given machines, the netplan
+// configuration rendered from the link-layer devices known to Juju, the
+// configuration last reported by the machine's agent, and the differences
+// between them.
+func (client *Client) NetworkConfigDiff(machines ...string) ([]params.MachineNetworkDiffResult, error) {
+	if client.BestAPIVersion() < 9 {
+		return nil, errors.NotSupportedf("showing machine network config")
+	}
+	args := params.Entities{
+		Entities: make([]params.Entity, len(machines)),
+	}
+	for i, machineId := range machines {
+		if !names.IsValidMachine(machineId) {
+			return nil, errors.NotValidf("machine ID %q", machineId)
+		}
+		args.Entities[i].Tag = names.NewMachineTag(machineId).String()
+	}
+	var results params.MachineNetworkDiffResults
+	if err := client.facade.FacadeCall("NetworkConfigDiff", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if n := len(results.Results); n != len(machines) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(machines), n)
+	}
+	return results.Results, nil
+}
+
+// ProvisioningInstanceTypes returns, for each of the input constraints, the
+// instance types that a new machine with those constraints would be
+// provisioned as in the model's cloud region, cheapest first. The model
+// constraints and the cloud's default constraints are applied as when
+// provisioning.
+func (client *Client) ProvisioningInstanceTypes(cons []constraints.Value) ([]params.InstanceTypesResult, error) {
+	if client.BestAPIVersion() < 10 {
+		return nil, errors.NotSupportedf("estimating instance types")
+	}
+	args := params.ModelInstanceTypesConstraints{
+		Constraints: make([]params.ModelInstanceTypesConstraint, len(cons)),
+	}
+	for i := range cons {
+		args.Constraints[i] = params.ModelInstanceTypesConstraint{Value: &cons[i]}
+	}
+	var results params.InstanceTypesResults
+	if err := client.facade.FacadeCall("ProvisioningInstanceTypes", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if n := len(results.Results); n != len(cons) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(cons), n)
+	}
+	return results.Results, nil
+}
+
 func (client *Client) destroyMachines(method string, machines []string) ([]params.DestroyMachineResult, error) {
 	args := params.Entities{
 		Entities: make([]params.Entity, 0, len(machines)),
@@ -14,6 +14,8 @@ import (
 	basetesting "github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/api/machinemanager"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/storage"
 	coretesting "github.com/juju/juju/testing"
 )
@@ -248,3 +250,191 @@ func (s *MachinemanagerSuite) TestDestroyMachinesWithParamsNilWait(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, jc.DeepEquals, expected)
 }
+
+func (s *MachinemanagerSuite) TestDestroyProtectedMachines(c *gc.C) {
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 7,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, "DestroyMachineWithParams")
+				c.Assert(a, jc.DeepEquals, params.DestroyMachinesParams{
+					MachineTags:      []string{"machine-0"},
+					ForceUnprotected: true,
+				})
+				out := response.(*params.DestroyMachineResults)
+				*out = params.DestroyMachineResults{Results: []params.DestroyMachineResult{{
+					Info: &params.DestroyMachineInfo{},
+				}}}
+				return nil
+			})})
+	results, err := client.DestroyProtectedMachines(false, false, nil, "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.DestroyMachineResult{{Info: &params.DestroyMachineInfo{}}})
+}
+
+func (s *MachinemanagerSuite) TestDestroyProtectedMachinesNotSupported(c *gc.C) {
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 6,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Fail()
+				return nil
+			})})
+	_, err := client.DestroyProtectedMachines(false, false, nil, "0")
+	c.Assert(err, gc.ErrorMatches, "removing protected machines not supported")
+}
+
+func (s *MachinemanagerSuite) TestSetMachineProtection(c *gc.C) {
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 7,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, "SetMachineProtection")
+				c.Assert(a, jc.DeepEquals, params.MachineProtectionArgs{
+					Args: []params.MachineProtectionArg{
+						{MachineTag: "machine-0", Protected: true},
+						{MachineTag: "machine-1", Protected: true},
+					},
+				})
+				out := response.(*params.ErrorResults)
+				*out = params.ErrorResults{Results: []params.ErrorResult{
+					{},
+					{Error: &params.Error{Message: "boom"}},
+				}}
+				return nil
+			})})
+	results, err := client.SetMachineProtection(true, "0", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.ErrorResult{
+		{},
+		{Error: &params.Error{Message: "boom"}},
+	})
+}
+
+func (s *MachinemanagerSuite) TestSetMachineProtectionNotSupported(c *gc.C) {
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 6,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Fail()
+				return nil
+			})})
+	_, err := client.SetMachineProtection(true, "0")
+	c.Assert(err, gc.ErrorMatches, "machine protection not supported")
+}
+
+func (s *MachinemanagerSuite) TestResizeMachines(c *gc.C) {
+	cons := constraints.MustParse("mem=16G")
+	mem := uint64(16384)
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 8,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, "ResizeMachines")
+				c.Assert(a, jc.DeepEquals, params.ResizeMachineArgs{
+					Args: []params.ResizeMachineArg{
+						{MachineTag: "machine-0", Constraints: cons},
+						{MachineTag: "machine-1", Constraints: cons},
+					},
+				})
+				out := response.(*params.ResizeMachineResults)
+				*out = params.ResizeMachineResults{Results: []params.ResizeMachineResult{
+					{HardwareCharacteristics: &instance.HardwareCharacteristics{Mem: &mem}},
+					{Error: &params.Error{Message: "boom"}},
+				}}
+				return nil
+			})})
+	results, err := client.ResizeMachines(cons, "0", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.ResizeMachineResult{
+		{HardwareCharacteristics: &instance.HardwareCharacteristics{Mem: &mem}},
+		{Error: &params.Error{Message: "boom"}},
+	})
+}
+
+func (s *MachinemanagerSuite) TestResizeMachinesNotSupported(c *gc.C) {
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 7,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Fail()
+				return nil
+			})})
+	_, err := client.ResizeMachines(constraints.MustParse("mem=16G"), "0")
+	c.Assert(err, gc.ErrorMatches, "resizing machines not supported")
+}
+
+func (s *MachinemanagerSuite) TestNetworkConfigDiff(c *gc.C) {
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 9,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, "NetworkConfigDiff")
+				c.Assert(a, jc.DeepEquals, params.Entities{
+					Entities: []params.Entity{{Tag: "machine-0"}, {Tag: "machine-1"}},
+				})
+				out := response.(*params.MachineNetworkDiffResults)
+				*out = params.MachineNetworkDiffResults{Results: []params.MachineNetworkDiffResult{
+					{Desired: "network: {}\n", Changes: []params.NetplanDeviceChange{{Type: "bond", Name: "bond0", Change: "added"}}},
+					{Error: &params.Error{Message: "boom"}},
+				}}
+				return nil
+			})})
+	results, err := client.NetworkConfigDiff("0", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.MachineNetworkDiffResult{
+		{Desired: "network: {}\n", Changes: []params.NetplanDeviceChange{{Type: "bond", Name: "bond0", Change: "added"}}},
+		{Error: &params.Error{Message: "boom"}},
+	})
+}
+
+func (s *MachinemanagerSuite) TestNetworkConfigDiffNotSupported(c *gc.C) {
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 8,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Fail()
+				return nil
+			})})
+	_, err := client.NetworkConfigDiff("0")
+	c.Assert(err, gc.ErrorMatches, "showing machine network config not supported")
+}
+
+func (s *MachinemanagerSuite) TestProvisioningInstanceTypes(c *gc.C) {
+	cons := []constraints.Value{constraints.MustParse("mem=4G"), constraints.MustParse("cores=8")}
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 10,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, "ProvisioningInstanceTypes")
+				c.Assert(a, jc.DeepEquals, params.ModelInstanceTypesConstraints{
+					Constraints: []params.ModelInstanceTypesConstraint{
+						{Value: &cons[0]},
+						{Value: &cons[1]},
+					},
+				})
+				out := response.(*params.InstanceTypesResults)
+				*out = params.InstanceTypesResults{Results: []params.InstanceTypesResult{
+					{InstanceTypes: []params.InstanceType{{Name: "m5.large", Cost: 96}}, CostCurrency: "USD", CostDivisor: 1000},
+					{Error: &params.Error{Message: "no instance types"}},
+				}}
+				return nil
+			})})
+	results, err := client.ProvisioningInstanceTypes(cons)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Check(results[0].InstanceTypes[0].Name, gc.Equals, "m5.large")
+	c.Check(results[1].Error, gc.ErrorMatches, "no instance types")
+}
+
+func (s *MachinemanagerSuite) TestProvisioningInstanceTypesNotSupported(c *gc.C) {
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 9,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Fail()
+				return nil
+			})})
+	_, err := client.ProvisioningInstanceTypes([]constraints.Value{{}})
+	c.Assert(err, gc.ErrorMatches, "estimating instance types not supported")
+}
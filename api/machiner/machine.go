@@ -12,6 +12,7 @@ import (
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/network/netplan"
 )
 
 // Machine represents a juju machine as seen by a machiner worker.
@@ -137,3 +138,74 @@ func (m *Machine) SetProviderNetworkConfig() error {
 	}
 	return result.OneError()
 }
+
+// NetplanConfig returns the desired netplan configuration of the machine's
+// network devices, and whether the machine agent should apply it.
+func (m *Machine) NetplanConfig() ([]netplan.DeviceConfig, bool, error) {
+	if m.st.facade.BestAPIVersion() < 2 {
+		return nil, false, errors.NotSupportedf("netplan config")
+	}
+	var results params.MachineNetplanConfigResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: m.tag.String()}},
+	}
+	if err := m.st.facade.FacadeCall("NetplanConfig", args, &results); err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, false, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	devices := make([]netplan.DeviceConfig, len(result.Devices))
+	for i, device := range result.Devices {
+		var routes []netplan.RouteConfig
+		for _, route := range device.Routes {
+			routes = append(routes, netplan.RouteConfig{
+				To:     route.DestinationCIDR,
+				Via:    route.GatewayIP,
+				Metric: route.Metric,
+			})
+		}
+		devices[i] = netplan.DeviceConfig{
+			Name:          device.Name,
+			Type:          netplan.DeviceType(device.Type),
+			MACAddress:    device.MACAddress,
+			MTU:           device.MTU,
+			ParentName:    device.ParentName,
+			VLANTag:       device.VLANTag,
+			BondMode:      device.BondMode,
+			Addresses:     device.Addresses,
+			DHCP4:         device.DHCP4,
+			Gateway4:      device.Gateway4,
+			Gateway6:      device.Gateway6,
+			Nameservers:   device.Nameservers,
+			SearchDomains: device.SearchDomains,
+			Routes:        routes,
+		}
+	}
+	return devices, result.Manage, nil
+}
+
+// SetNetplanStatus records the netplan configuration in effect on the
+// machine, and the reason the desired configuration could not be applied,
+// if any.
+func (m *Machine) SetNetplanStatus(actual, applyError string) error {
+	if m.st.facade.BestAPIVersion() < 2 {
+		return errors.NotSupportedf("netplan status")
+	}
+	var result params.ErrorResults
+	args := params.SetMachinesNetplanStatus{
+		Statuses: []params.MachineNetplanStatus{{
+			Tag:    m.tag.String(),
+			Actual: actual,
+			Error:  applyError,
+		}},
+	}
+	if err := m.st.facade.FacadeCall("SetNetplanStatus", args, &result); err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/core/watcher/watchertest"
 	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network/netplan"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 )
@@ -132,6 +133,51 @@ func (s *machinerSuite) TestRefresh(c *gc.C) {
 	c.Assert(machine.Life(), gc.Equals, params.Dead)
 }
 
+func (s *machinerSuite) TestNetplanConfigAndStatus(c *gc.C) {
+	err := s.machine.SetLinkLayerDevices(state.LinkLayerDeviceArgs{
+		Name:       "eth0",
+		Type:       network.EthernetDevice,
+		MACAddress: "aa:bb:cc:dd:ee:f0",
+		MTU:        9000,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetDevicesAddresses(state.LinkLayerDeviceAddress{
+		DeviceName:   "eth0",
+		ConfigMethod: state.StaticAddress,
+		CIDRAddress:  "10.0.0.5/24",
+		Routes: []state.AddressRoute{{
+			DestinationCIDR: "10.100.0.0/16",
+			GatewayIP:       "10.0.0.1",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	machine, err := s.machiner.Machine(names.NewMachineTag("1"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	devices, manage, err := machine.NetplanConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(manage, jc.IsFalse)
+	c.Check(devices, jc.DeepEquals, []netplan.DeviceConfig{{
+		Name:       "eth0",
+		Type:       netplan.TypeEthernet,
+		MACAddress: "aa:bb:cc:dd:ee:f0",
+		MTU:        9000,
+		Addresses:  []string{"10.0.0.5/24"},
+		Routes: []netplan.RouteConfig{{
+			To:  "10.100.0.0/16",
+			Via: "10.0.0.1",
+		}},
+	}})
+
+	err = machine.SetNetplanStatus("network:\n  version: 2\n", "")
+	c.Assert(err, jc.ErrorIsNil)
+	status, err := s.machine.NetplanStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(status.Actual, gc.Equals, "network:\n  version: 2\n")
+	c.Check(status.Error, gc.Equals, "")
+}
+
 func (s *machinerSuite) TestSetMachineAddresses(c *gc.C) {
 	machine, err := s.machiner.Machine(names.NewMachineTag("1"))
 	c.Assert(err, jc.ErrorIsNil)
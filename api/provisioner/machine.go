@@ -74,6 +74,10 @@ type MachineProvisioner interface {
 	// provider-level resources cleaned up and be removed.
 	MarkForRemoval() error
 
+	// ResetInstance removes the record of the machine's provider
+	// instance, so that a replacement instance can be provisioned.
+	ResetInstance() error
+
 	// AvailabilityZone returns an underlying provider's availability zone
 	// for a machine.
 	AvailabilityZone() (string, error)
@@ -100,6 +104,10 @@ type MachineProvisioner interface {
 	// for the machine.
 	KeepInstance() (bool, error)
 
+	// Protected returns whether the machine's instance is
+	// protected from termination.
+	Protected() (bool, error)
+
 	// SetPassword sets the machine's password.
 	SetPassword(password string) error
 
@@ -322,6 +330,22 @@ func (m *Machine) MarkForRemoval() error {
 	return result.OneError()
 }
 
+// ResetInstance implements MachineProvisioner.ResetInstance.
+func (m *Machine) ResetInstance() error {
+	if m.st.facade.BestAPIVersion() < 10 {
+		return errors.NotSupportedf("resetting instances on this version of Juju")
+	}
+	var result params.ErrorResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: m.tag.String()}},
+	}
+	err := m.st.facade.FacadeCall("ResetInstance", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
 // AvailabilityZone implements MachineProvisioner.AvailabilityZone.
 func (m *Machine) AvailabilityZone() (string, error) {
 	var results params.StringResults
@@ -432,6 +456,30 @@ func (m *Machine) KeepInstance() (bool, error) {
 	return result.Result, nil
 }
 
+// Protected implements MachineProvisioner.Protected.
+func (m *Machine) Protected() (bool, error) {
+	if m.st.facade.BestAPIVersion() < 11 {
+		// Older controllers cannot protect machines.
+		return false, nil
+	}
+	var results params.BoolResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: m.tag.String()}},
+	}
+	err := m.st.facade.FacadeCall("Protected", args, &results)
+	if err != nil {
+		return false, err
+	}
+	if len(results.Results) != 1 {
+		return false, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.Result, nil
+}
+
 // SetPassword implements MachineProvisioner.SetPassword.
 func (m *Machine) SetPassword(password string) error {
 	var result params.ErrorResults
@@ -177,6 +177,19 @@ func (mr *MockMachineProvisionerMockRecorder) ModelAgentVersion() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModelAgentVersion", reflect.TypeOf((*MockMachineProvisioner)(nil).ModelAgentVersion))
 }
 
+// Protected mocks base method
+func (m *MockMachineProvisioner) Protected() (bool, error) {
+	ret := m.ctrl.Call(m, "Protected")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Protected indicates an expected call of Protected
+func (mr *MockMachineProvisionerMockRecorder) Protected() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Protected", reflect.TypeOf((*MockMachineProvisioner)(nil).Protected))
+}
+
 // ProvisioningInfo mocks base method
 func (m *MockMachineProvisioner) ProvisioningInfo() (*params.ProvisioningInfo, error) {
 	ret := m.ctrl.Call(m, "ProvisioningInfo")
@@ -214,6 +227,18 @@ func (mr *MockMachineProvisionerMockRecorder) Remove() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockMachineProvisioner)(nil).Remove))
 }
 
+// ResetInstance mocks base method
+func (m *MockMachineProvisioner) ResetInstance() error {
+	ret := m.ctrl.Call(m, "ResetInstance")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetInstance indicates an expected call of ResetInstance
+func (mr *MockMachineProvisionerMockRecorder) ResetInstance() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetInstance", reflect.TypeOf((*MockMachineProvisioner)(nil).ResetInstance))
+}
+
 // SetCharmProfiles mocks base method
 func (m *MockMachineProvisioner) SetCharmProfiles(arg0 []string) error {
 	ret := m.ctrl.Call(m, "SetCharmProfiles", arg0)
@@ -308,6 +308,7 @@ func (st *State) prepareOrGetContainerInterfaceInfo(
 			InterfaceName:       cfg.InterfaceName,
 			ParentInterfaceName: cfg.ParentInterfaceName,
 			InterfaceType:       network.InterfaceType(cfg.InterfaceType),
+			BondMode:            cfg.BondMode,
 			Disabled:            cfg.Disabled,
 			NoAutoStart:         cfg.NoAutoStart,
 			ConfigType:          network.InterfaceConfigType(cfg.ConfigType),
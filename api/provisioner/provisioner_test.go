@@ -240,6 +240,43 @@ func (s *provisionerSuite) TestMarkForRemoval(c *gc.C) {
 	c.Assert(removals, jc.SameContents, []string{"1"})
 }
 
+func (s *provisionerSuite) TestResetInstance(c *gc.C) {
+	machine, err := s.State.AddMachine("xenial", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	apiMachine := s.assertGetOneMachine(c, machine.MachineTag())
+
+	err = apiMachine.ResetInstance()
+	c.Assert(err, gc.ErrorMatches, `cannot reset instance for machine "1": machine 1 not provisioned`)
+
+	err = machine.SetProvisioned("i-spot", "", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = apiMachine.ResetInstance()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = machine.InstanceId()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+}
+
+func (s *provisionerSuite) TestProtected(c *gc.C) {
+	machine, err := s.State.AddMachine("xenial", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	apiMachine := s.assertGetOneMachine(c, machine.MachineTag())
+	protected, err := apiMachine.Protected()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(protected, jc.IsFalse)
+
+	err = machine.SetProtected(true)
+	c.Assert(err, jc.ErrorIsNil)
+	protected, err = apiMachine.Protected()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(protected, jc.IsTrue)
+}
+
 func (s *provisionerSuite) TestRefreshAndLife(c *gc.C) {
 	// Create a fresh machine to test the complete scenario.
 	otherMachine, err := s.State.AddMachine("quantal", state.JobHostUnits)
@@ -102,3 +102,21 @@ func (api *API) ReloadSpaces() error {
 	}
 	return err
 }
+
+// UnitEndpointsNetworkInfo returns the network information that each
+// of the given unit endpoints would see when running network-get.
+func (api *API) UnitEndpointsNetworkInfo(endpoints []params.UnitEndpoint) ([]params.UnitEndpointNetworkInfoResult, error) {
+	if api.facade.BestAPIVersion() < 6 {
+		return nil, errors.NewNotSupported(nil, "Controller does not support unit endpoint network info")
+	}
+	args := params.UnitEndpoints{Entries: endpoints}
+	var response params.UnitEndpointNetworkInfoResults
+	err := api.facade.FacadeCall("UnitEndpointsNetworkInfo", args, &response)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(response.Results) != len(endpoints) {
+		return nil, errors.Errorf("expected %d results, got %d", len(endpoints), len(response.Results))
+	}
+	return response.Results, nil
+}
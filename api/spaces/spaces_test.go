@@ -30,7 +30,7 @@ var _ = gc.Suite(&SpacesSuite{})
 func (s *SpacesSuite) init(c *gc.C, args apitesting.APICall) {
 	s.apiCaller = apitesting.APICallChecker(c, args)
 	best := &apitesting.BestVersionCaller{
-		BestVersion:   5,
+		BestVersion:   6,
 		APICallerFunc: s.apiCaller.APICallerFunc,
 	}
 	s.api = spaces.NewAPI(best)
@@ -200,3 +200,45 @@ func (s *SpacesSuite) TestListSpacesManyResults(c *gc.C) {
 func (s *SpacesSuite) TestListSpacesServerError(c *gc.C) {
 	s.testListSpaces(c, nil, errors.New("boom"), "boom")
 }
+
+func (s *SpacesSuite) TestUnitEndpointsNetworkInfo(c *gc.C) {
+	endpoints := []params.UnitEndpoint{{UnitTag: "unit-mysql-0", Endpoint: "db"}}
+	results := []params.UnitEndpointNetworkInfoResult{{
+		MachineTag: "machine-0",
+		SpaceName:  "dmz",
+		Info: params.NetworkInfoResult{
+			IngressAddresses: []string{"10.0.0.1"},
+		},
+	}}
+	s.init(c, apitesting.APICall{
+		Facade:  "Spaces",
+		Method:  "UnitEndpointsNetworkInfo",
+		Args:    params.UnitEndpoints{Entries: endpoints},
+		Results: params.UnitEndpointNetworkInfoResults{Results: results},
+	})
+	gotResults, err := s.api.UnitEndpointsNetworkInfo(endpoints)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.apiCaller.CallCount, gc.Equals, 1)
+	c.Assert(gotResults, jc.DeepEquals, results)
+}
+
+func (s *SpacesSuite) TestUnitEndpointsNetworkInfoResultCountMismatch(c *gc.C) {
+	endpoints := []params.UnitEndpoint{{UnitTag: "unit-mysql-0", Endpoint: "db"}}
+	s.init(c, apitesting.APICall{
+		Facade:  "Spaces",
+		Method:  "UnitEndpointsNetworkInfo",
+		Args:    params.UnitEndpoints{Entries: endpoints},
+		Results: params.UnitEndpointNetworkInfoResults{},
+	})
+	_, err := s.api.UnitEndpointsNetworkInfo(endpoints)
+	c.Assert(err, gc.ErrorMatches, "expected 1 results, got 0")
+}
+
+func (s *SpacesSuite) TestUnitEndpointsNetworkInfoNotSupported(c *gc.C) {
+	apiCaller := &apitesting.BestVersionCaller{
+		APICallerFunc: apitesting.APICallChecker(c).APICallerFunc,
+		BestVersion:   5,
+	}
+	_, err := spaces.NewAPI(apiCaller).UnitEndpointsNetworkInfo(nil)
+	c.Assert(err, gc.ErrorMatches, "Controller does not support unit endpoint network info")
+}
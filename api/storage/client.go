@@ -145,6 +145,104 @@ func (c *Client) UpdatePool(pname, provider string, attrs map[string]interface{}
 	return results.OneError()
 }
 
+// ResizeStorage requests that the storage instance with the specified ID
+// be grown to the given size in MiB.
+func (c *Client) ResizeStorage(storageId string, size uint64) error {
+	if c.BestAPIVersion() < 7 {
+		return errors.NotSupportedf("resizing storage by this version of Juju")
+	}
+	if !names.IsValidStorage(storageId) {
+		return errors.NotValidf("storage ID %q", storageId)
+	}
+	var results params.ErrorResults
+	args := params.ResizeStorageParams{
+		Storage: []params.ResizeStorageArg{{
+			StorageTag: names.NewStorageTag(storageId).String(),
+			Size:       size,
+		}},
+	}
+	if err := c.facade.FacadeCall("ResizeStorage", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// CreateStorageSnapshots requests snapshots of the storage instances
+// with the specified IDs, returning the IDs of the new snapshots.
+func (c *Client) CreateStorageSnapshots(storageIds []string) ([]params.StorageSnapshotResult, error) {
+	if c.BestAPIVersion() < 8 {
+		return nil, errors.NotSupportedf("snapshotting storage by this version of Juju")
+	}
+	args := params.Entities{Entities: make([]params.Entity, len(storageIds))}
+	for i, id := range storageIds {
+		if !names.IsValidStorage(id) {
+			return nil, errors.NotValidf("storage ID %q", id)
+		}
+		args.Entities[i].Tag = names.NewStorageTag(id).String()
+	}
+	var results params.StorageSnapshotResults
+	if err := c.facade.FacadeCall("CreateStorageSnapshots", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(storageIds) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(storageIds), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// ListStorageSnapshots returns the details of all storage
+// snapshots in the model.
+func (c *Client) ListStorageSnapshots() ([]params.StorageSnapshotDetails, error) {
+	if c.BestAPIVersion() < 8 {
+		return nil, errors.NotSupportedf("listing storage snapshots by this version of Juju")
+	}
+	var results params.StorageSnapshotDetailsResults
+	if err := c.facade.FacadeCall("ListStorageSnapshots", nil, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
+// MigrateStorage requests that the storage instances with the
+// specified IDs be migrated to new volumes in the named pool,
+// returning the IDs of the new storage migrations.
+func (c *Client) MigrateStorage(storageIds []string, pool string) ([]params.StorageMigrationResult, error) {
+	if c.BestAPIVersion() < 9 {
+		return nil, errors.NotSupportedf("migrating storage by this version of Juju")
+	}
+	args := params.StorageMigrationArgs{Args: make([]params.StorageMigrationArg, len(storageIds))}
+	for i, id := range storageIds {
+		if !names.IsValidStorage(id) {
+			return nil, errors.NotValidf("storage ID %q", id)
+		}
+		args.Args[i] = params.StorageMigrationArg{
+			StorageTag: names.NewStorageTag(id).String(),
+			Pool:       pool,
+		}
+	}
+	var results params.StorageMigrationResults
+	if err := c.facade.FacadeCall("MigrateStorage", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(storageIds) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(storageIds), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// ListStorageMigrations returns the details of all storage
+// migrations in the model.
+func (c *Client) ListStorageMigrations() ([]params.StorageMigrationDetails, error) {
+	if c.BestAPIVersion() < 9 {
+		return nil, errors.NotSupportedf("listing storage migrations by this version of Juju")
+	}
+	var results params.StorageMigrationDetailsResults
+	if err := c.facade.FacadeCall("ListStorageMigrations", nil, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
 // ListVolumes lists volumes for desired machines.
 // If no machines provided, a list of all volumes is returned.
 func (c *Client) ListVolumes(machines []string) ([]params.VolumeDetailsListResult, error) {
@@ -1021,6 +1021,188 @@ func (s *storageMockSuite) TestRemovePoolFacadeCallError(c *gc.C) {
 	c.Assert(errors.Cause(err), gc.ErrorMatches, msg)
 }
 
+func (s *storageMockSuite) TestResizeStorage(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "ResizeStorage")
+			c.Check(a, jc.DeepEquals, params.ResizeStorageParams{
+				Storage: []params.ResizeStorageArg{{
+					StorageTag: "storage-data-0",
+					Size:       2048,
+				}},
+			})
+			results := result.(*params.ErrorResults)
+			results.Results = []params.ErrorResult{{}}
+			return nil
+		})
+	storageClient := storage.NewClient(basetesting.BestVersionCaller{BestVersion: 7, APICallerFunc: apiCaller})
+	err := storageClient.ResizeStorage("data/0", 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *storageMockSuite) TestResizeStorageNotSupported(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		})
+	storageClient := storage.NewClient(basetesting.BestVersionCaller{BestVersion: 6, APICallerFunc: apiCaller})
+	err := storageClient.ResizeStorage("data/0", 2048)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *storageMockSuite) TestCreateStorageSnapshots(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "CreateStorageSnapshots")
+			c.Check(a, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "storage-data-0"}},
+			})
+			results := result.(*params.StorageSnapshotResults)
+			results.Results = []params.StorageSnapshotResult{{Id: "0/0"}}
+			return nil
+		})
+	storageClient := storage.NewClient(basetesting.BestVersionCaller{BestVersion: 8, APICallerFunc: apiCaller})
+	results, err := storageClient.CreateStorageSnapshots([]string{"data/0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.StorageSnapshotResult{{Id: "0/0"}})
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *storageMockSuite) TestCreateStorageSnapshotsNotSupported(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		})
+	storageClient := storage.NewClient(basetesting.BestVersionCaller{BestVersion: 7, APICallerFunc: apiCaller})
+	_, err := storageClient.CreateStorageSnapshots([]string{"data/0"})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *storageMockSuite) TestListStorageSnapshots(c *gc.C) {
+	details := []params.StorageSnapshotDetails{{
+		Id:         "0/0",
+		StorageTag: "storage-data-0",
+		VolumeTag:  "volume-0",
+		Pool:       "ebs",
+		Status:     "pending",
+	}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(request, gc.Equals, "ListStorageSnapshots")
+			c.Check(a, gc.IsNil)
+			results := result.(*params.StorageSnapshotDetailsResults)
+			results.Results = details
+			return nil
+		})
+	storageClient := storage.NewClient(basetesting.BestVersionCaller{BestVersion: 8, APICallerFunc: apiCaller})
+	results, err := storageClient.ListStorageSnapshots()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, details)
+}
+
+func (s *storageMockSuite) TestMigrateStorage(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "MigrateStorage")
+			c.Check(a, jc.DeepEquals, params.StorageMigrationArgs{
+				Args: []params.StorageMigrationArg{{StorageTag: "storage-data-0", Pool: "ebs-ssd"}},
+			})
+			results := result.(*params.StorageMigrationResults)
+			results.Results = []params.StorageMigrationResult{{Id: "0/0"}}
+			return nil
+		})
+	storageClient := storage.NewClient(basetesting.BestVersionCaller{BestVersion: 9, APICallerFunc: apiCaller})
+	results, err := storageClient.MigrateStorage([]string{"data/0"}, "ebs-ssd")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.StorageMigrationResult{{Id: "0/0"}})
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *storageMockSuite) TestMigrateStorageNotSupported(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		})
+	storageClient := storage.NewClient(basetesting.BestVersionCaller{BestVersion: 8, APICallerFunc: apiCaller})
+	_, err := storageClient.MigrateStorage([]string{"data/0"}, "ebs-ssd")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *storageMockSuite) TestListStorageMigrations(c *gc.C) {
+	details := []params.StorageMigrationDetails{{
+		Id:              "0/0",
+		StorageTag:      "storage-data-0",
+		MachineTag:      "machine-0",
+		SourceVolumeTag: "volume-0",
+		SourcePool:      "ebs",
+		TargetVolumeTag: "volume-0-1",
+		TargetPool:      "ebs-ssd",
+		Status:          "pending",
+	}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(request, gc.Equals, "ListStorageMigrations")
+			c.Check(a, gc.IsNil)
+			results := result.(*params.StorageMigrationDetailsResults)
+			results.Results = details
+			return nil
+		})
+	storageClient := storage.NewClient(basetesting.BestVersionCaller{BestVersion: 9, APICallerFunc: apiCaller})
+	results, err := storageClient.ListStorageMigrations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, details)
+}
+
 func (s *storageMockSuite) TestUpdatePool(c *gc.C) {
 	var called bool
 	poolName := "poolName"
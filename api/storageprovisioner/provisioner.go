@@ -94,6 +94,42 @@ func (st *State) WatchFilesystems(scope names.Tag) (watcher.StringsWatcher, erro
 	return st.watchStorageEntities("WatchFilesystems", scope)
 }
 
+// WatchVolumeResizes watches for changes to volumes scoped to the
+// entity with the specified tag, including requests to resize them.
+func (st *State) WatchVolumeResizes(scope names.Tag) (watcher.StringsWatcher, error) {
+	if st.facade.BestAPIVersion() < 5 {
+		return nil, errors.NotSupportedf("resizing volumes by this version of Juju")
+	}
+	return st.watchStorageEntities("WatchVolumeResizes", scope)
+}
+
+// WatchFilesystemResizes watches for changes to filesystems scoped to
+// the entity with the specified tag, including requests to resize them.
+func (st *State) WatchFilesystemResizes(scope names.Tag) (watcher.StringsWatcher, error) {
+	if st.facade.BestAPIVersion() < 5 {
+		return nil, errors.NotSupportedf("resizing filesystems by this version of Juju")
+	}
+	return st.watchStorageEntities("WatchFilesystemResizes", scope)
+}
+
+// WatchVolumeSnapshots watches for changes to snapshots of volumes
+// scoped to the entity with the specified tag.
+func (st *State) WatchVolumeSnapshots(scope names.Tag) (watcher.StringsWatcher, error) {
+	if st.facade.BestAPIVersion() < 6 {
+		return nil, errors.NotSupportedf("snapshotting volumes by this version of Juju")
+	}
+	return st.watchStorageEntities("WatchVolumeSnapshots", scope)
+}
+
+// WatchStorageMigrations watches for changes to storage migrations
+// carried out on the machine with the specified tag.
+func (st *State) WatchStorageMigrations(scope names.MachineTag) (watcher.StringsWatcher, error) {
+	if st.facade.BestAPIVersion() < 7 {
+		return nil, errors.NotSupportedf("migrating storage by this version of Juju")
+	}
+	return st.watchStorageEntities("WatchStorageMigrations", scope)
+}
+
 func (st *State) watchStorageEntities(method string, scope names.Tag) (watcher.StringsWatcher, error) {
 	var results params.StringsWatchResults
 	args := params.Entities{
@@ -341,6 +377,105 @@ func (st *State) RemoveFilesystemParams(tags []names.FilesystemTag) ([]params.Re
 	return results.Results, nil
 }
 
+// VolumeResizeParams returns the parameters for growing the volumes
+// with the specified tags.
+func (st *State) VolumeResizeParams(tags []names.VolumeTag) ([]params.VolumeResizeParamsResult, error) {
+	args := params.Entities{
+		Entities: make([]params.Entity, len(tags)),
+	}
+	for i, tag := range tags {
+		args.Entities[i].Tag = tag.String()
+	}
+	var results params.VolumeResizeParamsResults
+	err := st.facade.FacadeCall("VolumeResizeParams", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(tags) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(tags), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// FilesystemResizeParams returns the parameters for growing the
+// filesystems with the specified tags.
+func (st *State) FilesystemResizeParams(tags []names.FilesystemTag) ([]params.FilesystemResizeParamsResult, error) {
+	args := params.Entities{
+		Entities: make([]params.Entity, len(tags)),
+	}
+	for i, tag := range tags {
+		args.Entities[i].Tag = tag.String()
+	}
+	var results params.FilesystemResizeParamsResults
+	err := st.facade.FacadeCall("FilesystemResizeParams", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(tags) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(tags), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// VolumeSnapshotParams returns the parameters for creating the volume
+// snapshots with the specified IDs.
+func (st *State) VolumeSnapshotParams(ids []string) ([]params.VolumeSnapshotParamsResult, error) {
+	args := params.StorageSnapshotIds{Ids: ids}
+	var results params.VolumeSnapshotParamsResults
+	err := st.facade.FacadeCall("VolumeSnapshotParams", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(ids) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(ids), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// SetVolumeSnapshotInfo records the outcome of creating volume snapshots.
+func (st *State) SetVolumeSnapshotInfo(snapshots []params.VolumeSnapshotInfo) ([]params.ErrorResult, error) {
+	args := params.VolumeSnapshotInfos{Snapshots: snapshots}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("SetVolumeSnapshotInfo", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(snapshots) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(snapshots), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// StorageMigrationParams returns the parameters for copying the data
+// of the storage migrations with the specified IDs.
+func (st *State) StorageMigrationParams(ids []string) ([]params.StorageMigrationParamsResult, error) {
+	args := params.StorageMigrationIds{Ids: ids}
+	var results params.StorageMigrationParamsResults
+	err := st.facade.FacadeCall("StorageMigrationParams", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(ids) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(ids), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// FinishStorageMigrations records the outcome of copying the data
+// of storage migrations.
+func (st *State) FinishStorageMigrations(outcomes []params.StorageMigrationOutcome) ([]params.ErrorResult, error) {
+	args := params.StorageMigrationOutcomes{Outcomes: outcomes}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("FinishStorageMigrations", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(outcomes) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(outcomes), len(results.Results))
+	}
+	return results.Results, nil
+}
+
 // VolumeAttachmentParams returns the parameters for creating the volume
 // attachments with the specified tags.
 func (st *State) VolumeAttachmentParams(ids []params.MachineStorageId) ([]params.VolumeAttachmentParamsResult, error) {
@@ -6,6 +6,7 @@ package storageprovisioner_test
 import (
 	"errors"
 
+	jujuerrors "github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v3"
@@ -516,6 +517,198 @@ func (s *provisionerSuite) TestRemoveVolumeParams(c *gc.C) {
 	}})
 }
 
+func (s *provisionerSuite) TestVolumeResizeParams(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "VolumeResizeParams")
+		c.Check(arg, gc.DeepEquals, params.Entities{Entities: []params.Entity{{"volume-100"}}})
+		c.Assert(result, gc.FitsTypeOf, &params.VolumeResizeParamsResults{})
+		*(result.(*params.VolumeResizeParamsResults)) = params.VolumeResizeParamsResults{
+			Results: []params.VolumeResizeParamsResult{{
+				Result: params.VolumeResizeParams{
+					VolumeTag: "volume-100",
+					Provider:  "foo",
+					Size:      2048,
+					Info:      params.VolumeInfo{VolumeId: "bar", Size: 1024},
+				},
+			}},
+		}
+		return nil
+	})
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	resizeParams, err := st.VolumeResizeParams([]names.VolumeTag{names.NewVolumeTag("100")})
+	c.Check(err, jc.ErrorIsNil)
+	c.Assert(resizeParams, jc.DeepEquals, []params.VolumeResizeParamsResult{{
+		Result: params.VolumeResizeParams{
+			VolumeTag: "volume-100",
+			Provider:  "foo",
+			Size:      2048,
+			Info:      params.VolumeInfo{VolumeId: "bar", Size: 1024},
+		},
+	}})
+}
+
+func (s *provisionerSuite) TestWatchVolumeResizesNotSupported(c *gc.C) {
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		}),
+		BestVersion: 4,
+	}
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.WatchVolumeResizes(names.NewMachineTag("123"))
+	c.Assert(err, jc.Satisfies, jujuerrors.IsNotSupported)
+}
+
+func (s *provisionerSuite) TestVolumeSnapshotParams(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "VolumeSnapshotParams")
+		c.Check(arg, gc.DeepEquals, params.StorageSnapshotIds{Ids: []string{"0/1"}})
+		c.Assert(result, gc.FitsTypeOf, &params.VolumeSnapshotParamsResults{})
+		*(result.(*params.VolumeSnapshotParamsResults)) = params.VolumeSnapshotParamsResults{
+			Results: []params.VolumeSnapshotParamsResult{{
+				Result: params.VolumeSnapshotParams{
+					Id:        "0/1",
+					VolumeTag: "volume-0-0",
+					Provider:  "loop",
+					VolumeId:  "loop0",
+				},
+			}},
+		}
+		return nil
+	})
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	snapshotParams, err := st.VolumeSnapshotParams([]string{"0/1"})
+	c.Check(err, jc.ErrorIsNil)
+	c.Assert(snapshotParams, jc.DeepEquals, []params.VolumeSnapshotParamsResult{{
+		Result: params.VolumeSnapshotParams{
+			Id:        "0/1",
+			VolumeTag: "volume-0-0",
+			Provider:  "loop",
+			VolumeId:  "loop0",
+		},
+	}})
+}
+
+func (s *provisionerSuite) TestSetVolumeSnapshotInfo(c *gc.C) {
+	snapshots := []params.VolumeSnapshotInfo{{
+		Id: "1", SnapshotId: "snap-1", Size: 1024,
+	}}
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "SetVolumeSnapshotInfo")
+		c.Check(arg, jc.DeepEquals, params.VolumeSnapshotInfos{Snapshots: snapshots})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: nil}},
+		}
+		return nil
+	})
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	errorResults, err := st.SetVolumeSnapshotInfo(snapshots)
+	c.Check(err, jc.ErrorIsNil)
+	c.Assert(errorResults, gc.HasLen, 1)
+	c.Assert(errorResults[0].Error, gc.IsNil)
+}
+
+func (s *provisionerSuite) TestWatchVolumeSnapshotsNotSupported(c *gc.C) {
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		}),
+		BestVersion: 5,
+	}
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.WatchVolumeSnapshots(names.NewMachineTag("123"))
+	c.Assert(err, jc.Satisfies, jujuerrors.IsNotSupported)
+}
+
+func (s *provisionerSuite) TestStorageMigrationParams(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "StorageMigrationParams")
+		c.Check(arg, gc.DeepEquals, params.StorageMigrationIds{Ids: []string{"0/1"}})
+		c.Assert(result, gc.FitsTypeOf, &params.StorageMigrationParamsResults{})
+		*(result.(*params.StorageMigrationParamsResults)) = params.StorageMigrationParamsResults{
+			Results: []params.StorageMigrationParamsResult{{
+				Result: params.StorageMigrationParams{
+					Id:               "0/1",
+					MachineTag:       "machine-0",
+					SourceDevicePath: "/dev/sda",
+					TargetDevicePath: "/dev/sdb",
+				},
+			}},
+		}
+		return nil
+	})
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	migrationParams, err := st.StorageMigrationParams([]string{"0/1"})
+	c.Check(err, jc.ErrorIsNil)
+	c.Assert(migrationParams, jc.DeepEquals, []params.StorageMigrationParamsResult{{
+		Result: params.StorageMigrationParams{
+			Id:               "0/1",
+			MachineTag:       "machine-0",
+			SourceDevicePath: "/dev/sda",
+			TargetDevicePath: "/dev/sdb",
+		},
+	}})
+}
+
+func (s *provisionerSuite) TestFinishStorageMigrations(c *gc.C) {
+	outcomes := []params.StorageMigrationOutcome{{
+		Id: "0/1", Error: "copying data: oops",
+	}}
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "FinishStorageMigrations")
+		c.Check(arg, jc.DeepEquals, params.StorageMigrationOutcomes{Outcomes: outcomes})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: nil}},
+		}
+		return nil
+	})
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	errorResults, err := st.FinishStorageMigrations(outcomes)
+	c.Check(err, jc.ErrorIsNil)
+	c.Assert(errorResults, gc.HasLen, 1)
+	c.Assert(errorResults[0].Error, gc.IsNil)
+}
+
+func (s *provisionerSuite) TestWatchStorageMigrationsNotSupported(c *gc.C) {
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		}),
+		BestVersion: 6,
+	}
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.WatchStorageMigrations(names.NewMachineTag("123"))
+	c.Assert(err, jc.Satisfies, jujuerrors.IsNotSupported)
+}
+
 func (s *provisionerSuite) TestFilesystemParams(c *gc.C) {
 	var callCount int
 	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
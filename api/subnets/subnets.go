@@ -121,3 +121,43 @@ func makeAddSubnetsParams(cidr string, providerId network.Id, space names.SpaceT
 		}},
 	}
 }
+
+// ListProviderSubnets fetches all the subnets discovered by the
+// provider, whether or not they have been added to the model.
+func (api *API) ListProviderSubnets() ([]params.Subnet, error) {
+	if api.BestAPIVersion() < 4 {
+		return nil, errors.NewNotSupported(nil, "listing provider subnets requires a newer controller")
+	}
+	var response params.ListSubnetsResults
+	err := api.facade.FacadeCall("ListProviderSubnets", nil, &response)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return response.Results, nil
+}
+
+// MoveSubnets moves the subnets with the given CIDRs, which must
+// already be known to the model, to the specified space. Unless
+// force is true, subnets are not moved out of spaces that are in
+// use by endpoint bindings or constraints.
+func (api *API) MoveSubnets(cidrs []string, space names.SpaceTag, force bool) error {
+	if api.BestAPIVersion() < 4 {
+		return errors.NewNotSupported(nil, "moving subnets requires a newer controller")
+	}
+	args := params.MoveSubnetsParams{
+		Subnets: make([]params.MoveSubnetParams, len(cidrs)),
+		Force:   force,
+	}
+	for i, cidr := range cidrs {
+		args.Subnets[i] = params.MoveSubnetParams{
+			CIDR:     cidr,
+			SpaceTag: space.String(),
+		}
+	}
+	var response params.ErrorResults
+	err := api.facade.FacadeCall("MoveSubnets", args, &response)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return response.Combine()
+}
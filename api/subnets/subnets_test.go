@@ -6,6 +6,7 @@ package subnets_test
 import (
 	"errors"
 
+	jujuerrors "github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v3"
@@ -252,3 +253,70 @@ func (s *SubnetsSuite) TestListSubnetsFails(c *gc.C) {
 	var expectedResults []params.Subnet
 	c.Assert(results, jc.DeepEquals, expectedResults)
 }
+
+func (s *SubnetsSuite) TestListProviderSubnets(c *gc.C) {
+	apicaller := &apitesting.BestVersionCaller{
+		APICallerFunc: apitesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				c.Check(objType, gc.Equals, "Subnets")
+				c.Check(request, gc.Equals, "ListProviderSubnets")
+				c.Check(a, gc.IsNil)
+				*result.(*params.ListSubnetsResults) = params.ListSubnetsResults{
+					Results: []params.Subnet{{CIDR: "10.0.0.0/24", ProviderId: "sn-0"}},
+				}
+				return nil
+			},
+		),
+		BestVersion: 4,
+	}
+	api := subnets.NewAPI(apicaller)
+	results, err := api.ListProviderSubnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.Subnet{{CIDR: "10.0.0.0/24", ProviderId: "sn-0"}})
+}
+
+func (s *SubnetsSuite) TestListProviderSubnetsNotSupported(c *gc.C) {
+	s.prepareAPICall(c, apitesting.APICall{})
+	_, err := s.api.ListProviderSubnets()
+	c.Assert(err, jc.Satisfies, jujuerrors.IsNotSupported)
+	c.Assert(s.apiCaller.CallCount, gc.Equals, 0)
+}
+
+func (s *SubnetsSuite) TestMoveSubnets(c *gc.C) {
+	apicaller := &apitesting.BestVersionCaller{
+		APICallerFunc: apitesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				c.Check(objType, gc.Equals, "Subnets")
+				c.Check(request, gc.Equals, "MoveSubnets")
+				c.Assert(a, jc.DeepEquals, params.MoveSubnetsParams{
+					Subnets: []params.MoveSubnetParams{{
+						CIDR:     "10.0.0.0/24",
+						SpaceTag: "space-db",
+					}, {
+						CIDR:     "10.0.1.0/24",
+						SpaceTag: "space-db",
+					}},
+					Force: true,
+				})
+				*result.(*params.ErrorResults) = params.ErrorResults{
+					Results: []params.ErrorResult{{}, {
+						Error: &params.Error{Message: "bang"},
+					}},
+				}
+				return nil
+			},
+		),
+		BestVersion: 4,
+	}
+	api := subnets.NewAPI(apicaller)
+	err := api.MoveSubnets([]string{"10.0.0.0/24", "10.0.1.0/24"}, names.NewSpaceTag("db"), true)
+	c.Assert(err, gc.ErrorMatches, "bang")
+}
@@ -187,3 +187,25 @@ func (sa *StorageAccessor) RemoveStorageAttachment(storageTag names.StorageTag,
 	}
 	return nil
 }
+
+// AcknowledgeStorageMigration records that the unit has carried out its
+// part in the current stage of the migration of the storage with the
+// specified tag: it has stopped using the storage, or has run its
+// storage-attached hook against the migrated storage.
+func (sa *StorageAccessor) AcknowledgeStorageMigration(storageTag names.StorageTag, unitTag names.UnitTag) error {
+	if sa.facade.BestAPIVersion() < 14 {
+		return errors.NotSupportedf("acknowledging storage migrations")
+	}
+	var results params.ErrorResults
+	args := params.StorageAttachmentIds{
+		Ids: []params.StorageAttachmentId{{
+			StorageTag: storageTag.String(),
+			UnitTag:    unitTag.String(),
+		}},
+	}
+	err := sa.facade.FacadeCall("AcknowledgeStorageMigrations", args, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
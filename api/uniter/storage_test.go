@@ -9,6 +9,7 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v3"
 
+	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
@@ -246,3 +247,40 @@ func (s *storageSuite) TestRemoveStorageAttachment(c *gc.C) {
 	err := st.RemoveStorageAttachment(names.NewStorageTag("data/0"), names.NewUnitTag("mysql/0"))
 	c.Check(err, gc.ErrorMatches, "yoink")
 }
+
+func (s *storageSuite) TestAcknowledgeStorageMigration(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Uniter")
+		c.Check(version, gc.Equals, 14)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "AcknowledgeStorageMigrations")
+		c.Check(arg, gc.DeepEquals, params.StorageAttachmentIds{
+			Ids: []params.StorageAttachmentId{{
+				StorageTag: "storage-data-0",
+				UnitTag:    "unit-mysql-0",
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{
+				Error: &params.Error{Message: "yoink"},
+			}},
+		}
+		return nil
+	})
+
+	sa := uniter.NewStorageAccessor(base.NewFacadeCallerForVersion(apiCaller, "Uniter", 14))
+	err := sa.AcknowledgeStorageMigration(names.NewStorageTag("data/0"), names.NewUnitTag("mysql/0"))
+	c.Check(err, gc.ErrorMatches, "yoink")
+}
+
+func (s *storageSuite) TestAcknowledgeStorageMigrationNotSupported(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected call to %s", request)
+		return nil
+	})
+
+	st := uniter.NewStateV2(apiCaller, names.NewUnitTag("mysql/0"))
+	err := st.AcknowledgeStorageMigration(names.NewStorageTag("data/0"), names.NewUnitTag("mysql/0"))
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/agent/credentialvalidator"
 	"github.com/juju/juju/apiserver/facades/agent/deployer"
 	"github.com/juju/juju/apiserver/facades/agent/diskmanager"
+	"github.com/juju/juju/apiserver/facades/agent/egressfirewaller"
 	"github.com/juju/juju/apiserver/facades/agent/fanconfigurer"
 	"github.com/juju/juju/apiserver/facades/agent/hostkeyreporter"
 	"github.com/juju/juju/apiserver/facades/agent/instancemutater"
@@ -153,6 +154,8 @@ func AllFacades() *facade.Registry {
 	reg("Application", 9, application.NewFacadeV9)   // ApplicationInfo; generational config; Force on App, Relation and Unit Removal.
 	reg("Application", 10, application.NewFacadeV10) // --force and --no-wait parameters
 	reg("Application", 11, application.NewFacadeV11) // Get call returns the endpoint bindings
+	reg("Application", 12, application.NewFacadeV12) // Adds UnitsInfo
+	reg("Application", 13, application.NewFacadeV13) // Adds storage snapshots to Deploy and AddUnits
 
 	reg("ApplicationOffers", 1, applicationoffers.NewOffersAPI)
 	reg("ApplicationOffers", 2, applicationoffers.NewOffersAPIV2)
@@ -199,12 +202,18 @@ func AllFacades() *facade.Registry {
 	reg("ExternalControllerUpdater", 1, externalcontrollerupdater.NewStateAPI)
 
 	reg("Deployer", 1, deployer.NewDeployerAPI)
-	reg("DiskManager", 2, diskmanager.NewDiskManagerAPI)
+	reg("DiskManager", 2, diskmanager.NewDiskManagerAPIV2)
+	reg("DiskManager", 3, diskmanager.NewDiskManagerAPI) // Adds FilesystemAttachments and SetFilesystemUsage.
+	reg("EgressFirewaller", 1, egressfirewaller.NewFacade)
 	reg("FanConfigurer", 1, fanconfigurer.NewFanConfigurerAPI)
 	reg("Firewaller", 3, firewaller.NewStateFirewallerAPIV3)
 	reg("Firewaller", 4, firewaller.NewStateFirewallerAPIV4)
 	reg("Firewaller", 5, firewaller.NewStateFirewallerAPIV5)
-	reg("FirewallRules", 1, firewallrules.NewFacade)
+	reg("Firewaller", 6, firewaller.NewStateFirewallerAPIV6) // Adds WatchFirewallRules and ModelIngressRules.
+	reg("Firewaller", 7, firewaller.NewStateFirewallerAPIV7) // Adds WatchEgressRules and MachineEgressRules.
+	reg("FirewallRules", 1, firewallrules.NewFacadeV1)
+	reg("FirewallRules", 2, firewallrules.NewFacadeV2) // Adds model ingress rules and RemoveFirewallRules.
+	reg("FirewallRules", 3, firewallrules.NewFacade)   // Adds SetEgressRules and ListEgressRules.
 	reg("HighAvailability", 2, highavailability.NewHighAvailabilityAPI)
 	reg("HostKeyReporter", 1, hostkeyreporter.NewFacade)
 	reg("ImageManager", 2, imagemanager.NewImageManagerAPI)
@@ -229,13 +238,18 @@ func AllFacades() *facade.Registry {
 	reg("MachineActions", 1, machineactions.NewExternalFacade)
 
 	reg("MachineManager", 2, machinemanager.NewFacade)
-	reg("MachineManager", 3, machinemanager.NewFacade)   // Adds DestroyMachine and ForceDestroyMachine.
-	reg("MachineManager", 4, machinemanager.NewFacadeV4) // Adds DestroyMachineWithParams.
-	reg("MachineManager", 5, machinemanager.NewFacadeV5) // Adds UpgradeSeriesPrepare, removes UpdateMachineSeries.
-	reg("MachineManager", 6, machinemanager.NewFacadeV6) // DestroyMachinesWithParams gains maxWait.
+	reg("MachineManager", 3, machinemanager.NewFacade)     // Adds DestroyMachine and ForceDestroyMachine.
+	reg("MachineManager", 4, machinemanager.NewFacadeV4)   // Adds DestroyMachineWithParams.
+	reg("MachineManager", 5, machinemanager.NewFacadeV5)   // Adds UpgradeSeriesPrepare, removes UpdateMachineSeries.
+	reg("MachineManager", 6, machinemanager.NewFacadeV6)   // DestroyMachinesWithParams gains maxWait.
+	reg("MachineManager", 7, machinemanager.NewFacadeV7)   // Adds SetMachineProtection.
+	reg("MachineManager", 8, machinemanager.NewFacadeV8)   // Adds ResizeMachines.
+	reg("MachineManager", 9, machinemanager.NewFacadeV9)   // Adds NetworkConfigDiff.
+	reg("MachineManager", 10, machinemanager.NewFacadeV10) // Adds ProvisioningInstanceTypes.
 
 	reg("MachineUndertaker", 1, machineundertaker.NewFacade)
-	reg("Machiner", 1, machine.NewMachinerAPI)
+	reg("Machiner", 1, machine.NewMachinerAPIV1)
+	reg("Machiner", 2, machine.NewMachinerAPI) // Adds NetplanConfig and SetNetplanStatus
 
 	reg("MeterStatus", 1, meterstatus.NewMeterStatusFacade)
 	reg("MetricsAdder", 2, metricsadder.NewMetricsAdderAPI)
@@ -271,11 +285,13 @@ func AllFacades() *facade.Registry {
 	reg("Pinger", 1, NewPinger)
 	reg("Provisioner", 3, provisioner.NewProvisionerAPIV4) // Yes this is weird.
 	reg("Provisioner", 4, provisioner.NewProvisionerAPIV4)
-	reg("Provisioner", 5, provisioner.NewProvisionerAPIV5) // v5 adds DistributionGroupByMachineId()
-	reg("Provisioner", 6, provisioner.NewProvisionerAPIV6) // v6 adds more proxy settings
-	reg("Provisioner", 7, provisioner.NewProvisionerAPIV7) // v7 adds charm profile watcher
-	reg("Provisioner", 8, provisioner.NewProvisionerAPIV8) // v8 adds changes charm profile and modification status
-	reg("Provisioner", 9, provisioner.NewProvisionerAPIV9) // v9 adds supported containers
+	reg("Provisioner", 5, provisioner.NewProvisionerAPIV5)   // v5 adds DistributionGroupByMachineId()
+	reg("Provisioner", 6, provisioner.NewProvisionerAPIV6)   // v6 adds more proxy settings
+	reg("Provisioner", 7, provisioner.NewProvisionerAPIV7)   // v7 adds charm profile watcher
+	reg("Provisioner", 8, provisioner.NewProvisionerAPIV8)   // v8 adds changes charm profile and modification status
+	reg("Provisioner", 9, provisioner.NewProvisionerAPIV9)   // v9 adds supported containers
+	reg("Provisioner", 10, provisioner.NewProvisionerAPIV10) // v10 adds reset instance
+	reg("Provisioner", 11, provisioner.NewProvisionerAPIV11) // v11 adds protected
 
 	reg("ProxyUpdater", 1, proxyupdater.NewFacadeV1)
 	reg("ProxyUpdater", 2, proxyupdater.NewFacadeV2)
@@ -295,19 +311,27 @@ func AllFacades() *facade.Registry {
 	reg("Spaces", 2, spaces.NewAPIv2)
 	reg("Spaces", 3, spaces.NewAPIv3)
 	reg("Spaces", 4, spaces.NewAPIv4)
-	reg("Spaces", 5, spaces.NewAPI)
+	reg("Spaces", 5, spaces.NewAPIv5)
+	reg("Spaces", 6, spaces.NewAPI) // Adds UnitEndpointsNetworkInfo
 
 	reg("StatusHistory", 2, statushistory.NewAPI)
 
 	reg("Storage", 3, storage.NewStorageAPIV3)
 	reg("Storage", 4, storage.NewStorageAPIV4) // changes Destroy() method signature.
 	reg("Storage", 5, storage.NewStorageAPIV5) // Update and Delete storage pools and CreatePool bulk calls.
-	reg("Storage", 6, storage.NewStorageAPI)   // modify Remove to support force and maxWait; adde DetachStorage to support force and maxWait.
+	reg("Storage", 6, storage.NewStorageAPIV6) // modify Remove to support force and maxWait; adde DetachStorage to support force and maxWait.
+	reg("Storage", 7, storage.NewStorageAPIV7) // add ResizeStorage.
+	reg("Storage", 8, storage.NewStorageAPIV8) // add CreateStorageSnapshots and ListStorageSnapshots.
+	reg("Storage", 9, storage.NewStorageAPI)   // add MigrateStorage and ListStorageMigrations.
 
 	reg("StorageProvisioner", 3, storageprovisioner.NewFacadeV3)
 	reg("StorageProvisioner", 4, storageprovisioner.NewFacadeV4)
+	reg("StorageProvisioner", 5, storageprovisioner.NewFacadeV5) // Adds resize watchers and params.
+	reg("StorageProvisioner", 6, storageprovisioner.NewFacadeV6) // Adds volume snapshots.
+	reg("StorageProvisioner", 7, storageprovisioner.NewFacadeV7) // Adds storage migrations.
 	reg("Subnets", 2, subnets.NewAPIv2)
-	reg("Subnets", 3, subnets.NewAPI)
+	reg("Subnets", 3, subnets.NewAPIv3)
+	reg("Subnets", 4, subnets.NewAPI)
 	reg("Undertaker", 1, undertaker.NewUndertakerAPI)
 	reg("UnitAssigner", 1, unitassigner.New)
 
@@ -320,7 +344,8 @@ func AllFacades() *facade.Registry {
 	reg("Uniter", 10, uniter.NewUniterAPIV10)
 	reg("Uniter", 11, uniter.NewUniterAPIV11)
 	reg("Uniter", 12, uniter.NewUniterAPIV12)
-	reg("Uniter", 13, uniter.NewUniterAPI)
+	reg("Uniter", 13, uniter.NewUniterAPIV13)
+	reg("Uniter", 14, uniter.NewUniterAPI) // Adds AcknowledgeStorageMigrations.
 
 	reg("Upgrader", 1, upgrader.NewUpgraderFacade)
 	reg("UpgradeSeries", 1, upgradeseries.NewAPI)
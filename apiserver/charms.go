@@ -437,6 +437,11 @@ func (h *charmsHandler) repackageAndUploadCharm(st *state.State, archive *charm.
 	}
 	bundleSHA256 := hex.EncodeToString(hash.Sum(nil))
 
+	relationSchemas, err := application.ReadRelationSchemas(charmDir)
+	if err != nil {
+		return errors.Annotate(err, "cannot read relation schemas")
+	}
+
 	info := application.CharmArchive{
 		ID:           curl,
 		Charm:        archive,
@@ -444,6 +449,8 @@ func (h *charmsHandler) repackageAndUploadCharm(st *state.State, archive *charm.
 		Size:         int64(repackagedArchive.Len()),
 		SHA256:       bundleSHA256,
 		CharmVersion: version,
+
+		RelationSchemas: relationSchemas,
 	}
 	// Store the charm archive in environment storage.
 	shim := application.NewStateShim(st)
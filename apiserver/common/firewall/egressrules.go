@@ -0,0 +1,78 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall
+
+import (
+	"fmt"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	corenetwork "github.com/juju/juju/core/network"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+// EgressRulesBackend provides the state required to work out
+// the egress rules of a machine.
+type EgressRulesBackend interface {
+	// MachineEgressRules returns the egress rules of the applications
+	// with units on the machine, and whether its egress is restricted.
+	MachineEgressRules(machineId string) ([]state.EgressRule, bool, error)
+
+	// APIHostPortsForAgents returns the controllers' API addresses.
+	APIHostPortsForAgents() ([]corenetwork.SpaceHostPorts, error)
+}
+
+// MachineEgressRules returns the egress rules of the machine with the
+// given id. When egress from the machine is restricted, the rules also
+// allow egress to the controllers' API addresses, so the machine's
+// agent can always reach them.
+func MachineEgressRules(backend EgressRulesBackend, machineId string) (params.MachineEgressRulesResult, error) {
+	var result params.MachineEgressRulesResult
+	rules, restricted, err := backend.MachineEgressRules(machineId)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if !restricted {
+		return result, nil
+	}
+	result.Restricted = true
+	for _, rule := range rules {
+		result.Rules = append(result.Rules, params.EgressRule{
+			DestinationCIDR: rule.DestinationCIDR,
+			PortRange:       params.FromNetworkPortRange(rule.PortRange),
+		})
+	}
+
+	apiHostPorts, err := backend.APIHostPortsForAgents()
+	if err != nil {
+		return params.MachineEgressRulesResult{}, errors.Trace(err)
+	}
+	seen := set.NewStrings()
+	for _, server := range apiHostPorts {
+		for _, hp := range server {
+			cidrs, err := network.FormatAsCIDR([]string{hp.Value})
+			if err != nil {
+				logger.Warningf("cannot allow egress to controller address %q: %v", hp.Value, err)
+				continue
+			}
+			key := fmt.Sprintf("%s:%d", cidrs[0], hp.Port())
+			if seen.Contains(key) {
+				continue
+			}
+			seen.Add(key)
+			result.Rules = append(result.Rules, params.EgressRule{
+				DestinationCIDR: cidrs[0],
+				PortRange: params.PortRange{
+					FromPort: hp.Port(),
+					ToPort:   hp.Port(),
+					Protocol: "tcp",
+				},
+			})
+		}
+	}
+	return result, nil
+}
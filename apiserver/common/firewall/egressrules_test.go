@@ -0,0 +1,91 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common/firewall"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&EgressRulesSuite{})
+
+type EgressRulesSuite struct {
+	coretesting.BaseSuite
+
+	backend *mockEgressRulesBackend
+}
+
+func (s *EgressRulesSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.backend = &mockEgressRulesBackend{
+		apiHostPorts: []network.SpaceHostPorts{
+			network.NewSpaceHostPorts(17070, "10.0.0.1", "fd00::1"),
+			network.NewSpaceHostPorts(17070, "10.0.0.1"),
+		},
+	}
+}
+
+func (s *EgressRulesSuite) TestUnrestricted(c *gc.C) {
+	result, err := firewall.MachineEgressRules(s.backend, "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.MachineEgressRulesResult{})
+	s.backend.CheckCallNames(c, "MachineEgressRules")
+	s.backend.CheckCall(c, 0, "MachineEgressRules", "0")
+}
+
+func (s *EgressRulesSuite) TestRestricted(c *gc.C) {
+	s.backend.restricted = true
+	s.backend.rules = []state.EgressRule{{
+		DestinationCIDR: "192.168.0.0/16",
+		PortRange:       network.MustParsePortRange("5432/tcp"),
+	}}
+	result, err := firewall.MachineEgressRules(s.backend, "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.MachineEgressRulesResult{
+		Restricted: true,
+		Rules: []params.EgressRule{{
+			DestinationCIDR: "192.168.0.0/16",
+			PortRange:       params.PortRange{FromPort: 5432, ToPort: 5432, Protocol: "tcp"},
+		}, {
+			DestinationCIDR: "10.0.0.1/32",
+			PortRange:       params.PortRange{FromPort: 17070, ToPort: 17070, Protocol: "tcp"},
+		}, {
+			DestinationCIDR: "fd00::1/128",
+			PortRange:       params.PortRange{FromPort: 17070, ToPort: 17070, Protocol: "tcp"},
+		}},
+	})
+	s.backend.CheckCallNames(c, "MachineEgressRules", "APIHostPortsForAgents")
+}
+
+func (s *EgressRulesSuite) TestError(c *gc.C) {
+	s.backend.SetErrors(errors.New("boom"))
+	_, err := firewall.MachineEgressRules(s.backend, "0")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type mockEgressRulesBackend struct {
+	testing.Stub
+
+	rules        []state.EgressRule
+	restricted   bool
+	apiHostPorts []network.SpaceHostPorts
+}
+
+func (b *mockEgressRulesBackend) MachineEgressRules(machineId string) ([]state.EgressRule, bool, error) {
+	b.MethodCall(b, "MachineEgressRules", machineId)
+	return b.rules, b.restricted, b.NextErr()
+}
+
+func (b *mockEgressRulesBackend) APIHostPortsForAgents() ([]network.SpaceHostPorts, error) {
+	b.MethodCall(b, "APIHostPortsForAgents")
+	return b.apiHostPorts, b.NextErr()
+}
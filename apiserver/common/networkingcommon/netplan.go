@@ -0,0 +1,209 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package networkingcommon
+
+import (
+	"net"
+	"sort"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	corenetwork "github.com/juju/juju/core/network"
+	"github.com/juju/juju/network/netplan"
+	"github.com/juju/juju/state"
+)
+
+// NetplanMachine describes the methods of a machine needed to render its
+// netplan configuration.
+type NetplanMachine interface {
+	AllLinkLayerDevices() ([]*state.LinkLayerDevice, error)
+	AllAddresses() ([]*state.Address, error)
+}
+
+// MachineNetplanDevices returns the desired netplan configuration of the
+// link-layer devices stored in state for the given machine, ordered by
+// device name. Only devices known to the provider and their parents are
+// included, so that devices created on the machine itself (e.g. lxdbr0,
+// fan or docker bridges) are left alone. Parents on other machines (e.g.
+// the host bridge of a container's device) are dropped.
+func MachineNetplanDevices(machine NetplanMachine) ([]netplan.DeviceConfig, error) {
+	devices, err := machine.AllLinkLayerDevices()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	addresses, err := machine.AllAddresses()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	addressesByDevice := make(map[string][]*state.Address)
+	for _, addr := range addresses {
+		addressesByDevice[addr.DeviceName()] = append(addressesByDevice[addr.DeviceName()], addr)
+	}
+
+	byName := make(map[string]*state.LinkLayerDevice)
+	for _, dev := range devices {
+		byName[dev.Name()] = dev
+	}
+	names := set.NewStrings()
+	for _, dev := range devices {
+		if dev.ProviderID() == "" {
+			continue
+		}
+		for ; dev != nil && !names.Contains(dev.Name()); dev = byName[dev.ParentName()] {
+			names.Add(dev.Name())
+		}
+	}
+
+	var result []netplan.DeviceConfig
+	for _, dev := range devices {
+		if !names.Contains(dev.Name()) {
+			continue
+		}
+		deviceType, ok := netplanDeviceType(dev.Type())
+		if !ok {
+			continue
+		}
+		config := netplan.DeviceConfig{
+			Name:       dev.Name(),
+			Type:       deviceType,
+			MACAddress: dev.MACAddress(),
+			MTU:        int(dev.MTU()),
+			BondMode:   dev.BondMode(),
+		}
+		if parent := dev.ParentName(); names.Contains(parent) {
+			config.ParentName = parent
+		}
+		if deviceType == netplan.TypeVLAN {
+			config.VLANTag = vlanTagFromAddresses(addressesByDevice[dev.Name()])
+		}
+		addNetplanAddresses(&config, addressesByDevice[dev.Name()])
+		result = append(result, config)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// netplanDeviceType returns the netplan device type for the given
+// link-layer device type, and false if netplan does not configure it.
+func netplanDeviceType(deviceType corenetwork.LinkLayerDeviceType) (netplan.DeviceType, bool) {
+	switch deviceType {
+	case corenetwork.EthernetDevice:
+		return netplan.TypeEthernet, true
+	case corenetwork.BondDevice:
+		return netplan.TypeBond, true
+	case corenetwork.VLAN8021QDevice:
+		return netplan.TypeVLAN, true
+	case corenetwork.BridgeDevice:
+		return netplan.TypeBridge, true
+	}
+	return "", false
+}
+
+// vlanTagFromAddresses returns the VLAN tag of the first known subnet of
+// the given addresses, or zero if none is known.
+func vlanTagFromAddresses(addresses []*state.Address) int {
+	for _, addr := range addresses {
+		subnet, err := addr.Subnet()
+		if err != nil {
+			continue
+		}
+		if tag := subnet.VLANTag(); tag > 0 {
+			return tag
+		}
+	}
+	return 0
+}
+
+// addNetplanAddresses sets the addresses, gateways, DNS settings and
+// routes of the given device config from the device's state addresses.
+func addNetplanAddresses(config *netplan.DeviceConfig, addresses []*state.Address) {
+	nameservers := set.NewStrings()
+	searchDomains := set.NewStrings()
+	for _, addr := range addresses {
+		ip := net.ParseIP(addr.Value())
+		switch addr.ConfigMethod() {
+		case state.StaticAddress:
+			_, ipNet, err := net.ParseCIDR(addr.SubnetCIDR())
+			if ip == nil || err != nil {
+				logger.Warningf("not rendering address %q of device %q with subnet %q",
+					addr.Value(), config.Name, addr.SubnetCIDR())
+				continue
+			}
+			ones, _ := ipNet.Mask.Size()
+			ipNet.IP = ip
+			ipNet.Mask = net.CIDRMask(ones, len(ipNet.Mask)*8)
+			config.Addresses = append(config.Addresses, ipNet.String())
+		case state.DynamicAddress:
+			if ip != nil && ip.To4() != nil {
+				config.DHCP4 = true
+			}
+		default:
+			continue
+		}
+
+		if gateway := addr.GatewayAddress(); addr.IsDefaultGateway() && gateway != "" {
+			if gatewayIP := net.ParseIP(gateway); gatewayIP != nil && gatewayIP.To4() != nil {
+				config.Gateway4 = gateway
+			} else {
+				config.Gateway6 = gateway
+			}
+		}
+		for _, nameserver := range addr.DNSServers() {
+			if !nameservers.Contains(nameserver) {
+				nameservers.Add(nameserver)
+				config.Nameservers = append(config.Nameservers, nameserver)
+			}
+		}
+		for _, domain := range addr.DNSSearchDomains() {
+			if !searchDomains.Contains(domain) {
+				searchDomains.Add(domain)
+				config.SearchDomains = append(config.SearchDomains, domain)
+			}
+		}
+		for _, route := range addr.Routes() {
+			config.Routes = append(config.Routes, netplan.RouteConfig{
+				To:     route.DestinationCIDR,
+				Via:    route.GatewayIP,
+				Metric: route.Metric,
+			})
+		}
+	}
+}
+
+// NetplanDevicesToParams converts the given device configs to their wire
+// representation.
+func NetplanDevicesToParams(devices []netplan.DeviceConfig) []params.NetplanDevice {
+	result := make([]params.NetplanDevice, len(devices))
+	for i, device := range devices {
+		var routes []params.NetworkRoute
+		for _, route := range device.Routes {
+			routes = append(routes, params.NetworkRoute{
+				DestinationCIDR: route.To,
+				GatewayIP:       route.Via,
+				Metric:          route.Metric,
+			})
+		}
+		result[i] = params.NetplanDevice{
+			Name:          device.Name,
+			Type:          string(device.Type),
+			MACAddress:    device.MACAddress,
+			MTU:           device.MTU,
+			ParentName:    device.ParentName,
+			VLANTag:       device.VLANTag,
+			BondMode:      device.BondMode,
+			Addresses:     device.Addresses,
+			DHCP4:         device.DHCP4,
+			Gateway4:      device.Gateway4,
+			Gateway6:      device.Gateway6,
+			Nameservers:   device.Nameservers,
+			SearchDomains: device.SearchDomains,
+			Routes:        routes,
+		}
+	}
+	return result
+}
@@ -0,0 +1,178 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package networkingcommon_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common/networkingcommon"
+	"github.com/juju/juju/apiserver/params"
+	corenetwork "github.com/juju/juju/core/network"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network/netplan"
+	"github.com/juju/juju/state"
+)
+
+type netplanSuite struct {
+	jujutesting.JujuConnSuite
+
+	machine *state.Machine
+}
+
+var _ = gc.Suite(&netplanSuite{})
+
+func (s *netplanSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+
+	var err error
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *netplanSuite) TestMachineNetplanDevices(c *gc.C) {
+	_, err := s.State.AddSubnet(corenetwork.SubnetInfo{CIDR: "10.42.0.0/24", VLANTag: 42})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.SetLinkLayerDevices(
+		state.LinkLayerDeviceArgs{
+			Name: "lo",
+			Type: corenetwork.LoopbackDevice,
+		},
+		state.LinkLayerDeviceArgs{
+			Name:       "bond0",
+			Type:       corenetwork.BondDevice,
+			MACAddress: "aa:bb:cc:dd:ee:f0",
+			MTU:        9000,
+			BondMode:   "802.3ad",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetLinkLayerDevices(
+		state.LinkLayerDeviceArgs{
+			Name:       "eth0",
+			Type:       corenetwork.EthernetDevice,
+			MACAddress: "aa:bb:cc:dd:ee:f0",
+			MTU:        9000,
+			ParentName: "bond0",
+			ProviderID: "eth0-id",
+		},
+		state.LinkLayerDeviceArgs{
+			Name:       "eth1",
+			Type:       corenetwork.EthernetDevice,
+			MACAddress: "aa:bb:cc:dd:ee:f1",
+			MTU:        9000,
+			ParentName: "bond0",
+			ProviderID: "eth1-id",
+		},
+		state.LinkLayerDeviceArgs{
+			Name:       "vlan42",
+			Type:       corenetwork.VLAN8021QDevice,
+			MACAddress: "aa:bb:cc:dd:ee:f0",
+			MTU:        1500,
+			ParentName: "bond0",
+			ProviderID: "vlan42-id",
+		},
+		// Bridges created on the machine are not known to the
+		// provider, and are not rendered.
+		state.LinkLayerDeviceArgs{
+			Name:       "lxdbr0",
+			Type:       corenetwork.BridgeDevice,
+			MACAddress: "aa:bb:cc:dd:ee:ff",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetDevicesAddresses(
+		state.LinkLayerDeviceAddress{
+			DeviceName:   "lo",
+			ConfigMethod: state.LoopbackAddress,
+			CIDRAddress:  "127.0.0.1/8",
+		},
+		state.LinkLayerDeviceAddress{
+			DeviceName:       "bond0",
+			ConfigMethod:     state.StaticAddress,
+			CIDRAddress:      "10.0.0.5/24",
+			DNSServers:       []string{"10.0.0.2"},
+			DNSSearchDomains: []string{"maas"},
+			GatewayAddress:   "10.0.0.1",
+			IsDefaultGateway: true,
+		},
+		state.LinkLayerDeviceAddress{
+			DeviceName:     "vlan42",
+			ConfigMethod:   state.StaticAddress,
+			CIDRAddress:    "10.42.0.5/24",
+			GatewayAddress: "10.42.0.1",
+			Routes: []state.AddressRoute{{
+				DestinationCIDR: "10.100.0.0/16",
+				GatewayIP:       "10.42.0.1",
+				Metric:          10,
+			}},
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	devices, err := networkingcommon.MachineNetplanDevices(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(devices, jc.DeepEquals, []netplan.DeviceConfig{{
+		Name:          "bond0",
+		Type:          netplan.TypeBond,
+		MACAddress:    "aa:bb:cc:dd:ee:f0",
+		MTU:           9000,
+		BondMode:      "802.3ad",
+		Addresses:     []string{"10.0.0.5/24"},
+		Gateway4:      "10.0.0.1",
+		Nameservers:   []string{"10.0.0.2"},
+		SearchDomains: []string{"maas"},
+	}, {
+		Name:       "eth0",
+		Type:       netplan.TypeEthernet,
+		MACAddress: "aa:bb:cc:dd:ee:f0",
+		MTU:        9000,
+		ParentName: "bond0",
+	}, {
+		Name:       "eth1",
+		Type:       netplan.TypeEthernet,
+		MACAddress: "aa:bb:cc:dd:ee:f1",
+		MTU:        9000,
+		ParentName: "bond0",
+	}, {
+		Name:       "vlan42",
+		Type:       netplan.TypeVLAN,
+		MACAddress: "aa:bb:cc:dd:ee:f0",
+		MTU:        1500,
+		ParentName: "bond0",
+		VLANTag:    42,
+		Addresses:  []string{"10.42.0.5/24"},
+		Routes: []netplan.RouteConfig{{
+			To:     "10.100.0.0/16",
+			Via:    "10.42.0.1",
+			Metric: 10,
+		}},
+	}})
+
+	// The devices render to valid netplan.
+	_, err = netplan.Render(devices)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *netplanSuite) TestNetplanDevicesToParams(c *gc.C) {
+	devices := []netplan.DeviceConfig{{
+		Name:     "bond0",
+		Type:     netplan.TypeBond,
+		BondMode: "active-backup",
+		Routes: []netplan.RouteConfig{{
+			To:  "10.100.0.0/16",
+			Via: "10.0.0.1",
+		}},
+	}}
+	c.Assert(networkingcommon.NetplanDevicesToParams(devices), jc.DeepEquals, []params.NetplanDevice{{
+		Name:     "bond0",
+		Type:     "bond",
+		BondMode: "active-backup",
+		Routes: []params.NetworkRoute{{
+			DestinationCIDR: "10.100.0.0/16",
+			GatewayIP:       "10.0.0.1",
+		}},
+	}})
+}
@@ -187,6 +187,7 @@ func NetworkConfigFromInterfaceInfo(interfaceInfos []network.InterfaceInfo) []pa
 			InterfaceName:       v.InterfaceName,
 			ParentInterfaceName: v.ParentInterfaceName,
 			InterfaceType:       string(v.InterfaceType),
+			BondMode:            v.BondMode,
 			Disabled:            v.Disabled,
 			NoAutoStart:         v.NoAutoStart,
 			ConfigType:          string(v.ConfigType),
@@ -232,6 +233,7 @@ func NetworkConfigsToStateArgs(networkConfig []params.NetworkConfig) (
 				IsAutoStart: !netConfig.NoAutoStart,
 				IsUp:        !netConfig.Disabled,
 				ParentName:  netConfig.ParentInterfaceName,
+				BondMode:    netConfig.BondMode,
 			}
 			logger.Tracef("state device args for device: %+v", args)
 			devicesArgs = append(devicesArgs, args)
@@ -280,6 +282,13 @@ func NetworkConfigsToStateArgs(networkConfig []params.NetworkConfig) (
 			GatewayAddress:    netConfig.GatewayAddress,
 			IsDefaultGateway:  netConfig.IsDefaultGateway,
 		}
+		for _, route := range netConfig.Routes {
+			addr.Routes = append(addr.Routes, state.AddressRoute{
+				DestinationCIDR: route.DestinationCIDR,
+				GatewayIP:       route.GatewayIP,
+				Metric:          route.Metric,
+			})
+		}
 		logger.Tracef("state address args for device: %+v", addr)
 		devicesAddrs = append(devicesAddrs, addr)
 	}
@@ -397,6 +406,7 @@ func mergeObservedAndProviderInterfaceConfig(observedConfig, providerConfig para
 	finalConfig.ProviderVLANId = providerConfig.ProviderVLANId
 	finalConfig.ProviderSubnetId = providerConfig.ProviderSubnetId
 	finalConfig.ProviderNetworkId = providerConfig.ProviderNetworkId
+	finalConfig.BondMode = providerConfig.BondMode
 
 	// The following few fields are only updated if their observed values are
 	// empty.
@@ -457,6 +467,10 @@ func mergeObservedAndProviderAddressConfig(observedConfig, providerConfig params
 		finalConfig.DNSSearchDomains = providerConfig.DNSSearchDomains
 	}
 
+	if len(observedConfig.Routes) == 0 {
+		finalConfig.Routes = providerConfig.Routes
+	}
+
 	return finalConfig
 }
 
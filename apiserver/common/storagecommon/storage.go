@@ -169,8 +169,9 @@ func volumeStorageAttachmentInfo(
 		return nil, errors.Trace(err)
 	}
 	return &storage.StorageAttachmentInfo{
-		storage.StorageKindBlock,
-		devicePath,
+		Kind:     storage.StorageKindBlock,
+		Location: devicePath,
+		Size:     volumeInfo.Size,
 	}, nil
 }
 
@@ -198,12 +199,68 @@ func filesystemStorageAttachmentInfo(
 	if err != nil {
 		return nil, errors.Annotate(err, "getting filesystem attachment info")
 	}
+	// The filesystem's size is informational only, so don't fail if
+	// the filesystem has not yet recorded its info.
+	var size uint64
+	if filesystemInfo, err := filesystem.Info(); err == nil {
+		size = filesystemInfo.Size
+	} else if !errors.IsNotProvisioned(err) {
+		return nil, errors.Annotate(err, "getting filesystem info")
+	}
 	return &storage.StorageAttachmentInfo{
-		storage.StorageKindFilesystem,
-		filesystemAttachmentInfo.MountPoint,
+		Kind:     storage.StorageKindFilesystem,
+		Location: filesystemAttachmentInfo.MountPoint,
+		Size:     size,
 	}, nil
 }
 
+// VolumeAttachmentDevicePath returns the absolute device path for the
+// attachment of the specified volume to a machine. An error satisfying
+// errors.IsNotProvisioned is returned if the volume or its attachment
+// is not yet provisioned, or if the corresponding block device has not
+// yet been published for the machine.
+func VolumeAttachmentDevicePath(
+	st VolumeAccess,
+	volume state.Volume,
+	machineTag names.MachineTag,
+) (string, error) {
+	volumeInfo, err := volume.Info()
+	if err != nil {
+		return "", errors.Annotate(err, "getting volume info")
+	}
+	volumeAttachment, err := st.VolumeAttachment(machineTag, volume.VolumeTag())
+	if err != nil {
+		return "", errors.Annotate(err, "getting volume attachment")
+	}
+	volumeAttachmentInfo, err := volumeAttachment.Info()
+	if err != nil {
+		return "", errors.Annotate(err, "getting volume attachment info")
+	}
+	blockDeviceInfo := state.BlockDeviceInfo{}
+	if plan, err := st.VolumeAttachmentPlan(machineTag, volume.VolumeTag()); err == nil {
+		blockDeviceInfo, err = plan.BlockDeviceInfo()
+		if err != nil && !errors.IsNotFound(err) {
+			return "", errors.Annotate(err, "getting block device info")
+		}
+	} else if !errors.IsNotFound(err) {
+		return "", errors.Annotate(err, "getting attachment plans")
+	}
+	blockDevices, err := st.BlockDevices(machineTag)
+	if err != nil {
+		return "", errors.Annotate(err, "getting block devices")
+	}
+	blockDevice, ok := MatchingBlockDevice(
+		blockDevices,
+		volumeInfo,
+		volumeAttachmentInfo,
+		blockDeviceInfo,
+	)
+	if !ok {
+		return "", errors.NotProvisionedf("block device for %s", names.ReadableString(volume.VolumeTag()))
+	}
+	return volumeAttachmentDevicePath(volumeInfo, volumeAttachmentInfo, *blockDevice)
+}
+
 // volumeAttachmentDevicePath returns the absolute device path for
 // a volume attachment. The value is only meaningful in the context
 // of the machine that the volume is attached to.
@@ -104,6 +104,7 @@ func (s *VolumeStorageAttachmentInfoSuite) TestStorageAttachmentPlanInfoDeviceNa
 	c.Assert(info, jc.DeepEquals, &storage.StorageAttachmentInfo{
 		Kind:     storage.StorageKindBlock,
 		Location: "/dev/sdb",
+		Size:     1024,
 	})
 }
 
@@ -115,6 +116,7 @@ func (s *VolumeStorageAttachmentInfoSuite) TestStorageAttachmentInfoPersistentDe
 	c.Assert(info, jc.DeepEquals, &storage.StorageAttachmentInfo{
 		Kind:     storage.StorageKindBlock,
 		Location: "/dev/sda",
+		Size:     1024,
 	})
 }
 
@@ -139,6 +141,7 @@ func (s *VolumeStorageAttachmentInfoSuite) TestStorageAttachmentInfoPersistentDe
 	c.Assert(info, jc.DeepEquals, &storage.StorageAttachmentInfo{
 		Kind:     storage.StorageKindBlock,
 		Location: "/dev/sda",
+		Size:     1024,
 	})
 }
 
@@ -150,6 +153,7 @@ func (s *VolumeStorageAttachmentInfoSuite) TestStorageAttachmentInfoPersistentDe
 	c.Assert(info, jc.DeepEquals, &storage.StorageAttachmentInfo{
 		Kind:     storage.StorageKindBlock,
 		Location: "/dev/disk/by-id/verbatim",
+		Size:     1024,
 	})
 }
 
@@ -161,6 +165,7 @@ func (s *VolumeStorageAttachmentInfoSuite) TestStorageAttachmentInfoPersistentHa
 	c.Assert(info, jc.DeepEquals, &storage.StorageAttachmentInfo{
 		Kind:     storage.StorageKindBlock,
 		Location: "/dev/disk/by-id/whatever",
+		Size:     1024,
 	})
 }
 
@@ -172,6 +177,7 @@ func (s *VolumeStorageAttachmentInfoSuite) TestStorageAttachmentInfoPersistentWW
 	c.Assert(info, jc.DeepEquals, &storage.StorageAttachmentInfo{
 		Kind:     storage.StorageKindBlock,
 		Location: "/dev/disk/by-id/wwn-drbr",
+		Size:     1024,
 	})
 }
 
@@ -192,6 +198,7 @@ func (s *VolumeStorageAttachmentInfoSuite) TestStorageAttachmentInfoMatchingBloc
 	c.Assert(info, jc.DeepEquals, &storage.StorageAttachmentInfo{
 		Kind:     storage.StorageKindBlock,
 		Location: "/dev/sdb",
+		Size:     1024,
 	})
 }
 
@@ -214,6 +221,21 @@ func (s *VolumeStorageAttachmentInfoSuite) TestStorageAttachmentInfoVolumeNotFou
 	s.st.CheckCallNames(c, "StorageInstance", "StorageInstanceVolume")
 }
 
+func (s *VolumeStorageAttachmentInfoSuite) TestVolumeAttachmentDevicePath(c *gc.C) {
+	s.volumeAttachment.info.DeviceLink = "/dev/disk/by-id/a-second-device"
+	path, err := storagecommon.VolumeAttachmentDevicePath(s.st, s.volume, s.machineTag)
+	c.Assert(err, jc.ErrorIsNil)
+	s.st.CheckCallNames(c, "VolumeAttachment", "VolumeAttachmentPlan", "BlockDevices")
+	c.Assert(path, gc.Equals, "/dev/disk/by-id/a-second-device")
+}
+
+func (s *VolumeStorageAttachmentInfoSuite) TestVolumeAttachmentDevicePathNoBlockDevice(c *gc.C) {
+	s.volumeAttachment.info.DeviceName = "sdc"
+	_, err := storagecommon.VolumeAttachmentDevicePath(s.st, s.volume, s.machineTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+	c.Assert(err, gc.ErrorMatches, "block device for volume 0 not provisioned")
+}
+
 type FilesystemStorageAttachmentInfoSuite struct {
 	hostTag              names.Tag
 	filsystemTag         names.FilesystemTag
@@ -271,6 +293,7 @@ func (s *FilesystemStorageAttachmentInfoSuite) TestStorageAttachmentInfo(c *gc.C
 	c.Assert(info, jc.DeepEquals, &storage.StorageAttachmentInfo{
 		Kind:     storage.StorageKindFilesystem,
 		Location: "/path/to/here",
+		Size:     1024,
 	})
 }
 
@@ -25,11 +25,12 @@ func VolumeParams(
 	registry storage.ProviderRegistry,
 ) (params.VolumeParams, error) {
 
-	var pool string
+	var pool, snapshotId string
 	var size uint64
 	if stateVolumeParams, ok := v.Params(); ok {
 		pool = stateVolumeParams.Pool
 		size = stateVolumeParams.Size
+		snapshotId = stateVolumeParams.SnapshotId
 	} else {
 		volumeInfo, err := v.Info()
 		if err != nil {
@@ -49,12 +50,13 @@ func VolumeParams(
 		return params.VolumeParams{}, errors.Trace(err)
 	}
 	return params.VolumeParams{
-		v.Tag().String(),
-		size,
-		string(providerType),
-		cfg.Attrs(),
-		volumeTags,
-		nil, // attachment params set by the caller
+		VolumeTag:  v.Tag().String(),
+		Size:       size,
+		Provider:   string(providerType),
+		Attributes: cfg.Attrs(),
+		Tags:       volumeTags,
+		SnapshotId: snapshotId,
+		// attachment params set by the caller
 	}, nil
 }
 
@@ -109,6 +111,7 @@ func VolumeToState(v params.Volume) (names.VolumeTag, state.VolumeInfo, error) {
 		"", // pool is set by state
 		v.Info.VolumeId,
 		v.Info.Persistent,
+		v.Info.Encrypted,
 	}, nil
 }
 
@@ -133,6 +136,7 @@ func VolumeInfoFromState(info state.VolumeInfo) params.VolumeInfo {
 		info.Pool,
 		info.Size,
 		info.Persistent,
+		info.Encrypted,
 	}
 }
 
@@ -61,6 +61,22 @@ func (*volumesSuite) testVolumeParams(c *gc.C, volumeParams *state.VolumeParams,
 	})
 }
 
+func (*volumesSuite) TestVolumeParamsSnapshot(c *gc.C) {
+	p, err := storagecommon.VolumeParams(
+		&fakeVolume{tag: names.NewVolumeTag("100"), params: &state.VolumeParams{
+			Pool: "loop", Size: 1024, SnapshotId: "snap-1",
+		}},
+		nil, // StorageInstance
+		testing.ModelTag.Id(),
+		testing.ControllerTag.Id(),
+		testing.CustomModelConfig(c, nil),
+		&fakePoolManager{},
+		provider.CommonStorageProviders(),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.SnapshotId, gc.Equals, "snap-1")
+}
+
 func (*volumesSuite) TestVolumeParamsStorageTags(c *gc.C) {
 	volumeTag := names.NewVolumeTag("100")
 	storageTag := names.NewStorageTag("mystore/0")
@@ -4,20 +4,38 @@
 package diskmanager
 
 import (
+	"fmt"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/common/storagecommon"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/storage"
 )
 
+// usageWarningKey is the key in a filesystem's status data that marks
+// the status message as a usage warning set by this facade, so that
+// the warning can be cleared once usage drops below the threshold.
+const usageWarningKey = "usage-warning"
+
 // DiskManagerAPI provides access to the DiskManager API facade.
 type DiskManagerAPI struct {
 	st          stateInterface
 	authorizer  facade.Authorizer
 	getAuthFunc common.GetAuthFunc
+	clock       clock.Clock
+}
+
+// DiskManagerAPIV2 provides access to the DiskManager API facade,
+// version 2.
+type DiskManagerAPIV2 struct {
+	*DiskManagerAPI
 }
 
 var getState = func(st *state.State) stateInterface {
@@ -47,9 +65,24 @@ func NewDiskManagerAPI(
 		st:          getState(st),
 		authorizer:  authorizer,
 		getAuthFunc: getAuthFunc,
+		clock:       clock.WallClock,
 	}, nil
 }
 
+// NewDiskManagerAPIV2 creates a new server-side DiskManager API facade,
+// version 2.
+func NewDiskManagerAPIV2(
+	st *state.State,
+	resources facade.Resources,
+	authorizer facade.Authorizer,
+) (*DiskManagerAPIV2, error) {
+	api, err := NewDiskManagerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &DiskManagerAPIV2{api}, nil
+}
+
 func (d *DiskManagerAPI) SetMachineBlockDevices(args params.SetMachineBlockDevices) (params.ErrorResults, error) {
 	result := params.ErrorResults{
 		Results: make([]params.ErrorResult, len(args.MachineBlockDevices)),
@@ -86,6 +119,174 @@ func (d *DiskManagerAPI) SetMachineBlockDevices(args params.SetMachineBlockDevic
 	return result, nil
 }
 
+// FilesystemAttachments returns the provisioned, mounted filesystem
+// attachments of each of the specified machines.
+func (d *DiskManagerAPI) FilesystemAttachments(args params.Entities) (params.FilesystemAttachmentsResults, error) {
+	result := params.FilesystemAttachmentsResults{
+		Results: make([]params.FilesystemAttachmentsResult, len(args.Entities)),
+	}
+	canAccess, err := d.getAuthFunc()
+	if err != nil {
+		return result, err
+	}
+	for i, arg := range args.Entities {
+		tag, err := names.ParseMachineTag(arg.Tag)
+		if err != nil || !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		attachments, err := d.machineFilesystemAttachments(tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Attachments = attachments
+	}
+	return result, nil
+}
+
+func (d *DiskManagerAPI) machineFilesystemAttachments(tag names.MachineTag) ([]params.FilesystemAttachment, error) {
+	attachments, err := d.st.MachineFilesystemAttachments(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []params.FilesystemAttachment
+	for _, attachment := range attachments {
+		if attachment.Life() != state.Alive {
+			continue
+		}
+		info, err := attachment.Info()
+		if errors.IsNotProvisioned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if info.MountPoint == "" {
+			continue
+		}
+		result = append(result, params.FilesystemAttachment{
+			FilesystemTag: attachment.Filesystem().String(),
+			MachineTag:    tag.String(),
+			Info:          storagecommon.FilesystemAttachmentInfoFromState(info),
+		})
+	}
+	return result, nil
+}
+
+// SetFilesystemUsage records the usage of filesystems attached to
+// machines. If a filesystem's space or inode usage exceeds the model's
+// storage usage warning threshold, the filesystem's status message
+// reports a warning; the warning is cleared once usage drops below
+// the threshold again.
+func (d *DiskManagerAPI) SetFilesystemUsage(args params.SetFilesystemUsage) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Usage)),
+	}
+	if len(args.Usage) == 0 {
+		return result, nil
+	}
+	canAccess, err := d.getAuthFunc()
+	if err != nil {
+		return result, err
+	}
+	modelConfig, err := d.st.ModelConfig()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	threshold := modelConfig.StorageUsageWarningThreshold()
+	for i, arg := range args.Usage {
+		machineTag, err := names.ParseMachineTag(arg.MachineTag)
+		if err != nil || !canAccess(machineTag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		filesystemTag, err := names.ParseFilesystemTag(arg.FilesystemTag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		err = d.setFilesystemUsage(machineTag, filesystemTag, arg.Usage, threshold)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (d *DiskManagerAPI) setFilesystemUsage(
+	machineTag names.MachineTag,
+	filesystemTag names.FilesystemTag,
+	usage params.FilesystemUsage,
+	threshold int,
+) error {
+	if err := d.st.SetFilesystemAttachmentUsage(machineTag, filesystemTag, state.FilesystemUsage{
+		UsedBytes:  usage.UsedBytes,
+		FreeBytes:  usage.FreeBytes,
+		UsedInodes: usage.UsedInodes,
+		FreeInodes: usage.FreeInodes,
+		Updated:    d.clock.Now(),
+	}); err != nil {
+		return errors.Trace(err)
+	}
+	filesystem, err := d.st.Filesystem(filesystemTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	current, err := filesystem.Status()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if current.Status != status.Attached {
+		// Don't mask provisioning or error statuses.
+		return nil
+	}
+	warning := usageWarning(usage, threshold)
+	warned, _ := current.Data[usageWarningKey].(bool)
+	if warning == "" && !warned || warning != "" && warned && warning == current.Message {
+		// Only update the status when crossing the threshold,
+		// to avoid churning status history.
+		return nil
+	}
+	now := d.clock.Now()
+	newStatus := status.StatusInfo{
+		Status:  status.Attached,
+		Message: warning,
+		Since:   &now,
+	}
+	if warning != "" {
+		newStatus.Data = map[string]interface{}{usageWarningKey: true}
+	}
+	return errors.Trace(filesystem.SetStatus(newStatus))
+}
+
+// usageWarning returns a warning message if the filesystem's space or
+// inode usage exceeds the given percentage threshold, or the empty
+// string otherwise. A threshold of zero disables the warning.
+func usageWarning(usage params.FilesystemUsage, threshold int) string {
+	if threshold <= 0 {
+		return ""
+	}
+	if exceedsThreshold(usage.UsedBytes, usage.FreeBytes, threshold) {
+		return fmt.Sprintf("filesystem space usage exceeds %d%%", threshold)
+	}
+	if exceedsThreshold(usage.UsedInodes, usage.FreeInodes, threshold) {
+		return fmt.Sprintf("filesystem inode usage exceeds %d%%", threshold)
+	}
+	return ""
+}
+
+func exceedsThreshold(used, free uint64, threshold int) bool {
+	total := used + free
+	if total == 0 {
+		return false
+	}
+	return float64(used)*100 > float64(total)*float64(threshold)
+}
+
+// FilesystemAttachments is not available on version 2 of the facade.
+func (*DiskManagerAPIV2) FilesystemAttachments(_, _ struct{}) {}
+
+// SetFilesystemUsage is not available on version 2 of the facade.
+func (*DiskManagerAPIV2) SetFilesystemUsage(_, _ struct{}) {}
+
 func stateBlockDeviceInfo(devices []storage.BlockDevice) []state.BlockDeviceInfo {
 	result := make([]state.BlockDeviceInfo, len(devices))
 	for i, dev := range devices {
@@ -5,7 +5,9 @@ package diskmanager_test
 
 import (
 	"errors"
+	"time"
 
+	jujuerrors "github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v3"
@@ -14,6 +16,8 @@ import (
 	"github.com/juju/juju/apiserver/facades/agent/diskmanager"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/storage"
 	coretesting "github.com/juju/juju/testing"
@@ -34,7 +38,12 @@ func (s *DiskManagerSuite) SetUpTest(c *gc.C) {
 	s.resources = common.NewResources()
 	tag := names.NewMachineTag("0")
 	s.authorizer = &apiservertesting.FakeAuthorizer{Tag: tag}
-	s.st = &mockState{}
+	s.st = &mockState{
+		filesystem: &mockFilesystem{
+			status: status.StatusInfo{Status: status.Attached},
+		},
+		config: coretesting.ModelConfig(c),
+	}
 	diskmanager.PatchState(s, s.st)
 
 	var err error
@@ -107,10 +116,239 @@ func (s *DiskManagerSuite) TestSetMachineBlockDevicesStateError(c *gc.C) {
 	})
 }
 
+func (s *DiskManagerSuite) TestFilesystemAttachments(c *gc.C) {
+	s.st.attachments = []state.FilesystemAttachment{
+		&mockFilesystemAttachment{
+			tag:  names.NewFilesystemTag("0/0"),
+			life: state.Alive,
+			info: &state.FilesystemAttachmentInfo{MountPoint: "/srv/data"},
+		},
+		&mockFilesystemAttachment{
+			tag:  names.NewFilesystemTag("0/1"),
+			life: state.Alive,
+		},
+		&mockFilesystemAttachment{
+			tag:  names.NewFilesystemTag("0/2"),
+			life: state.Dying,
+			info: &state.FilesystemAttachmentInfo{MountPoint: "/srv/logs"},
+		},
+		&mockFilesystemAttachment{
+			tag:  names.NewFilesystemTag("0/3"),
+			life: state.Alive,
+			info: &state.FilesystemAttachmentInfo{},
+		},
+	}
+	results, err := s.api.FilesystemAttachments(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-0"}, {Tag: "machine-1"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.FilesystemAttachmentsResults{
+		Results: []params.FilesystemAttachmentsResult{{
+			Attachments: []params.FilesystemAttachment{{
+				FilesystemTag: "filesystem-0-0",
+				MachineTag:    "machine-0",
+				Info:          params.FilesystemAttachmentInfo{MountPoint: "/srv/data"},
+			}},
+		}, {
+			Error: &params.Error{Message: "permission denied", Code: "unauthorized access"},
+		}},
+	})
+}
+
+func (s *DiskManagerSuite) TestSetFilesystemUsage(c *gc.C) {
+	results, err := s.api.SetFilesystemUsage(params.SetFilesystemUsage{
+		Usage: []params.FilesystemAttachmentUsage{{
+			FilesystemTag: "filesystem-0-0",
+			MachineTag:    "machine-0",
+			Usage: params.FilesystemUsage{
+				UsedBytes:  50,
+				FreeBytes:  50,
+				UsedInodes: 1,
+				FreeInodes: 99,
+			},
+		}, {
+			FilesystemTag: "filesystem-1-0",
+			MachineTag:    "machine-1",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{
+			Error: nil,
+		}, {
+			Error: &params.Error{Message: "permission denied", Code: "unauthorized access"},
+		}},
+	})
+	usage := s.st.usage[names.NewFilesystemTag("0/0")]
+	c.Assert(usage.Updated.IsZero(), jc.IsFalse)
+	usage.Updated = time.Time{}
+	c.Assert(usage, jc.DeepEquals, state.FilesystemUsage{
+		UsedBytes:  50,
+		FreeBytes:  50,
+		UsedInodes: 1,
+		FreeInodes: 99,
+	})
+	// Usage is below the threshold, so the status is left alone.
+	c.Assert(s.st.filesystem.setStatusCalls, gc.Equals, 0)
+}
+
+func (s *DiskManagerSuite) setFilesystemUsage(c *gc.C, used, free uint64) {
+	results, err := s.api.SetFilesystemUsage(params.SetFilesystemUsage{
+		Usage: []params.FilesystemAttachmentUsage{{
+			FilesystemTag: "filesystem-0-0",
+			MachineTag:    "machine-0",
+			Usage: params.FilesystemUsage{
+				UsedBytes:  used,
+				FreeBytes:  free,
+				UsedInodes: 1,
+				FreeInodes: 99,
+			},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+}
+
+func (s *DiskManagerSuite) TestSetFilesystemUsageWarning(c *gc.C) {
+	s.setFilesystemUsage(c, 95, 5)
+	c.Assert(s.st.filesystem.setStatusCalls, gc.Equals, 1)
+	c.Assert(s.st.filesystem.status.Status, gc.Equals, status.Attached)
+	c.Assert(s.st.filesystem.status.Message, gc.Equals, "filesystem space usage exceeds 90%")
+	c.Assert(s.st.filesystem.status.Data, jc.DeepEquals, map[string]interface{}{"usage-warning": true})
+
+	// Reporting usage above the threshold again does not
+	// update the status.
+	s.setFilesystemUsage(c, 97, 3)
+	c.Assert(s.st.filesystem.setStatusCalls, gc.Equals, 1)
+
+	// Dropping below the threshold clears the warning.
+	s.setFilesystemUsage(c, 50, 50)
+	c.Assert(s.st.filesystem.setStatusCalls, gc.Equals, 2)
+	c.Assert(s.st.filesystem.status.Status, gc.Equals, status.Attached)
+	c.Assert(s.st.filesystem.status.Message, gc.Equals, "")
+	c.Assert(s.st.filesystem.status.Data, gc.HasLen, 0)
+}
+
+func (s *DiskManagerSuite) TestSetFilesystemUsageInodeWarning(c *gc.C) {
+	results, err := s.api.SetFilesystemUsage(params.SetFilesystemUsage{
+		Usage: []params.FilesystemAttachmentUsage{{
+			FilesystemTag: "filesystem-0-0",
+			MachineTag:    "machine-0",
+			Usage: params.FilesystemUsage{
+				UsedBytes:  1,
+				FreeBytes:  99,
+				UsedInodes: 91,
+				FreeInodes: 9,
+			},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+	c.Assert(s.st.filesystem.status.Message, gc.Equals, "filesystem inode usage exceeds 90%")
+}
+
+func (s *DiskManagerSuite) TestSetFilesystemUsageThresholdDisabled(c *gc.C) {
+	s.st.config = coretesting.CustomModelConfig(c, coretesting.Attrs{
+		"storage-usage-warning-threshold": 0,
+	})
+	s.setFilesystemUsage(c, 100, 0)
+	c.Assert(s.st.filesystem.setStatusCalls, gc.Equals, 0)
+}
+
+func (s *DiskManagerSuite) TestSetFilesystemUsageNotAttached(c *gc.C) {
+	s.st.filesystem.status = status.StatusInfo{Status: status.Error, Message: "oops"}
+	s.setFilesystemUsage(c, 100, 0)
+	c.Assert(s.st.filesystem.setStatusCalls, gc.Equals, 0)
+}
+
+func (s *DiskManagerSuite) TestSetFilesystemUsageStateError(c *gc.C) {
+	s.st.err = errors.New("boom")
+	results, err := s.api.SetFilesystemUsage(params.SetFilesystemUsage{
+		Usage: []params.FilesystemAttachmentUsage{{
+			FilesystemTag: "filesystem-0-0",
+			MachineTag:    "machine-0",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{
+			Error: &params.Error{Message: "boom", Code: ""},
+		}},
+	})
+}
+
 type mockState struct {
 	calls   int
 	devices map[string][]state.BlockDeviceInfo
 	err     error
+
+	attachments []state.FilesystemAttachment
+	usage       map[names.FilesystemTag]state.FilesystemUsage
+	filesystem  *mockFilesystem
+	config      *config.Config
+}
+
+func (st *mockState) MachineFilesystemAttachments(names.MachineTag) ([]state.FilesystemAttachment, error) {
+	return st.attachments, st.err
+}
+
+func (st *mockState) SetFilesystemAttachmentUsage(
+	host names.Tag, filesystem names.FilesystemTag, usage state.FilesystemUsage,
+) error {
+	if st.err != nil {
+		return st.err
+	}
+	if st.usage == nil {
+		st.usage = make(map[names.FilesystemTag]state.FilesystemUsage)
+	}
+	st.usage[filesystem] = usage
+	return nil
+}
+
+func (st *mockState) Filesystem(names.FilesystemTag) (state.Filesystem, error) {
+	return st.filesystem, st.err
+}
+
+func (st *mockState) ModelConfig() (*config.Config, error) {
+	return st.config, nil
+}
+
+type mockFilesystem struct {
+	state.Filesystem
+	status         status.StatusInfo
+	setStatusCalls int
+}
+
+func (f *mockFilesystem) Status() (status.StatusInfo, error) {
+	return f.status, nil
+}
+
+func (f *mockFilesystem) SetStatus(info status.StatusInfo) error {
+	f.setStatusCalls++
+	f.status = info
+	return nil
+}
+
+type mockFilesystemAttachment struct {
+	state.FilesystemAttachment
+	tag  names.FilesystemTag
+	life state.Life
+	info *state.FilesystemAttachmentInfo
+}
+
+func (a *mockFilesystemAttachment) Filesystem() names.FilesystemTag {
+	return a.tag
+}
+
+func (a *mockFilesystemAttachment) Life() state.Life {
+	return a.life
+}
+
+func (a *mockFilesystemAttachment) Info() (state.FilesystemAttachmentInfo, error) {
+	if a.info == nil {
+		return state.FilesystemAttachmentInfo{}, jujuerrors.NotProvisionedf("filesystem attachment")
+	}
+	return *a.info, nil
 }
 
 func (st *mockState) SetMachineBlockDevices(machineId string, devices []state.BlockDeviceInfo) error {
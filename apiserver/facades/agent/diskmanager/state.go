@@ -3,10 +3,19 @@
 
 package diskmanager
 
-import "github.com/juju/juju/state"
+import (
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
 
 type stateInterface interface {
 	SetMachineBlockDevices(machineId string, devices []state.BlockDeviceInfo) error
+	MachineFilesystemAttachments(names.MachineTag) ([]state.FilesystemAttachment, error)
+	SetFilesystemAttachmentUsage(names.Tag, names.FilesystemTag, state.FilesystemUsage) error
+	Filesystem(names.FilesystemTag) (state.Filesystem, error)
+	ModelConfig() (*config.Config, error)
 }
 
 type stateShim struct {
@@ -20,3 +29,37 @@ func (s stateShim) SetMachineBlockDevices(machineId string, devices []state.Bloc
 	}
 	return m.SetMachineBlockDevices(devices...)
 }
+
+func (s stateShim) MachineFilesystemAttachments(tag names.MachineTag) ([]state.FilesystemAttachment, error) {
+	sb, err := state.NewStorageBackend(s.State)
+	if err != nil {
+		return nil, err
+	}
+	return sb.MachineFilesystemAttachments(tag)
+}
+
+func (s stateShim) SetFilesystemAttachmentUsage(
+	host names.Tag, filesystem names.FilesystemTag, usage state.FilesystemUsage,
+) error {
+	sb, err := state.NewStorageBackend(s.State)
+	if err != nil {
+		return err
+	}
+	return sb.SetFilesystemAttachmentUsage(host, filesystem, usage)
+}
+
+func (s stateShim) Filesystem(tag names.FilesystemTag) (state.Filesystem, error) {
+	sb, err := state.NewStorageBackend(s.State)
+	if err != nil {
+		return nil, err
+	}
+	return sb.Filesystem(tag)
+}
+
+func (s stateShim) ModelConfig() (*config.Config, error) {
+	m, err := s.State.Model()
+	if err != nil {
+		return nil, err
+	}
+	return m.ModelConfig()
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package egressfirewaller implements the API facade used by the
+// egressfirewaller worker.
+package egressfirewaller
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/common/firewall"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+// Backend defines the State API used by the egressfirewaller facade.
+type Backend interface {
+	firewall.EgressRulesBackend
+
+	// WatchMachineEgressRules returns a watcher which notifies of
+	// changes which may change the egress rules of the machine.
+	WatchMachineEgressRules(machineId string) (state.NotifyWatcher, error)
+
+	// MachineEgressEnforcedByProvider returns whether egress from the
+	// machine is restricted by the provider, as recorded by the
+	// firewaller once it has applied the machine's egress rules,
+	// instead of by the machine agent.
+	MachineEgressEnforcedByProvider(machineId string) (bool, error)
+}
+
+// Facade implements the API required by the egressfirewaller worker.
+type Facade struct {
+	backend      Backend
+	resources    facade.Resources
+	getCanAccess common.GetAuthFunc
+}
+
+// New returns a new API facade for the egressfirewaller worker.
+func New(backend Backend, resources facade.Resources, authorizer facade.Authorizer) (*Facade, error) {
+	if !authorizer.AuthMachineAgent() {
+		return nil, common.ErrPerm
+	}
+	return &Facade{
+		backend:   backend,
+		resources: resources,
+		getCanAccess: func() (common.AuthFunc, error) {
+			return authorizer.AuthOwner, nil
+		},
+	}, nil
+}
+
+// WatchEgressRules returns a NotifyWatcher for each given machine,
+// which triggers whenever the egress rules of the machine may have
+// changed.
+func (f *Facade) WatchEgressRules(args params.Entities) (params.NotifyWatchResults, error) {
+	result := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	canAccess, err := f.getCanAccess()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil || !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		w, err := f.backend.WatchMachineEgressRules(tag.Id())
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		// Consume the initial event.
+		if _, ok := <-w.Changes(); !ok {
+			result.Results[i].Error = common.ServerError(watcher.EnsureErr(w))
+			continue
+		}
+		result.Results[i].NotifyWatcherId = f.resources.Register(w)
+	}
+	return result, nil
+}
+
+// EgressRules returns the egress rules of each given machine, whether
+// egress from it is restricted, and whether the restriction is enforced
+// by the provider instead of by the machine agent.
+func (f *Facade) EgressRules(args params.Entities) (params.MachineEgressRulesResults, error) {
+	result := params.MachineEgressRulesResults{
+		Results: make([]params.MachineEgressRulesResult, len(args.Entities)),
+	}
+	canAccess, err := f.getCanAccess()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil || !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		rules, err := firewall.MachineEgressRules(f.backend, tag.Id())
+		if err == nil && rules.Restricted {
+			rules.ProviderEnforced, err = f.backend.MachineEgressEnforcedByProvider(tag.Id())
+		}
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i] = rules
+	}
+	return result, nil
+}
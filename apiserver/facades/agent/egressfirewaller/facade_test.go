@@ -0,0 +1,152 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package egressfirewaller_test
+
+import (
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/agent/egressfirewaller"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing"
+)
+
+type facadeSuite struct {
+	testing.BaseSuite
+	backend    *mockBackend
+	resources  *common.Resources
+	authorizer *apiservertesting.FakeAuthorizer
+	facade     *egressfirewaller.Facade
+}
+
+var _ = gc.Suite(&facadeSuite{})
+
+func (s *facadeSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	changes := make(chan struct{}, 1)
+	changes <- struct{}{}
+	s.backend = &mockBackend{
+		watcher: statetesting.NewMockNotifyWatcher(changes),
+	}
+	s.resources = common.NewResources()
+	s.AddCleanup(func(_ *gc.C) { s.resources.StopAll() })
+	s.authorizer = &apiservertesting.FakeAuthorizer{Tag: names.NewMachineTag("1")}
+	facade, err := egressfirewaller.New(s.backend, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	s.facade = facade
+}
+
+func (s *facadeSuite) TestNewNotMachineAgent(c *gc.C) {
+	s.authorizer.Tag = names.NewUnitTag("mysql/0")
+	_, err := egressfirewaller.New(s.backend, s.resources, s.authorizer)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *facadeSuite) TestWatchEgressRules(c *gc.C) {
+	result, err := s.facade.WatchEgressRules(params.Entities{Entities: []params.Entity{
+		{Tag: "machine-0"}, {Tag: "machine-1"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.NotifyWatchResults{
+		Results: []params.NotifyWatchResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{NotifyWatcherId: "1"},
+		},
+	})
+	c.Assert(s.resources.Get("1"), gc.Equals, s.backend.watcher)
+	s.backend.stub.CheckCalls(c, []jujutesting.StubCall{{"WatchMachineEgressRules", []interface{}{"1"}}})
+}
+
+func (s *facadeSuite) TestEgressRulesUnrestricted(c *gc.C) {
+	result, err := s.facade.EgressRules(params.Entities{Entities: []params.Entity{{Tag: "machine-1"}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.MachineEgressRulesResults{
+		Results: []params.MachineEgressRulesResult{{}},
+	})
+	s.backend.stub.CheckCallNames(c, "MachineEgressRules")
+}
+
+func (s *facadeSuite) TestEgressRules(c *gc.C) {
+	s.backend.restricted = true
+	s.backend.rules = []state.EgressRule{{
+		DestinationCIDR: "10.0.0.0/8",
+		PortRange:       network.MustParsePortRange("5432/tcp"),
+	}}
+	s.backend.apiHostPorts = []network.SpaceHostPorts{network.NewSpaceHostPorts(17070, "10.1.2.3")}
+	s.backend.providerEnforced = true
+
+	result, err := s.facade.EgressRules(params.Entities{Entities: []params.Entity{
+		{Tag: "machine-0"}, {Tag: "machine-1"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.MachineEgressRulesResults{
+		Results: []params.MachineEgressRulesResult{{
+			Error: apiservertesting.ErrUnauthorized,
+		}, {
+			Restricted:       true,
+			ProviderEnforced: true,
+			Rules: []params.EgressRule{{
+				DestinationCIDR: "10.0.0.0/8",
+				PortRange:       params.PortRange{FromPort: 5432, ToPort: 5432, Protocol: "tcp"},
+			}, {
+				DestinationCIDR: "10.1.2.3/32",
+				PortRange:       params.PortRange{FromPort: 17070, ToPort: 17070, Protocol: "tcp"},
+			}},
+		}},
+	})
+	s.backend.stub.CheckCallNames(c, "MachineEgressRules", "APIHostPortsForAgents", "MachineEgressEnforcedByProvider")
+	s.backend.stub.CheckCall(c, 2, "MachineEgressEnforcedByProvider", "1")
+}
+
+func (s *facadeSuite) TestEgressRulesNotEnforcedByProvider(c *gc.C) {
+	s.backend.restricted = true
+	s.backend.rules = []state.EgressRule{{
+		DestinationCIDR: "10.0.0.0/8",
+		PortRange:       network.MustParsePortRange("5432/tcp"),
+	}}
+
+	result, err := s.facade.EgressRules(params.Entities{Entities: []params.Entity{{Tag: "machine-1"}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Restricted, jc.IsTrue)
+	c.Assert(result.Results[0].ProviderEnforced, jc.IsFalse)
+}
+
+type mockBackend struct {
+	stub jujutesting.Stub
+
+	watcher          state.NotifyWatcher
+	rules            []state.EgressRule
+	restricted       bool
+	apiHostPorts     []network.SpaceHostPorts
+	providerEnforced bool
+}
+
+func (b *mockBackend) MachineEgressRules(machineId string) ([]state.EgressRule, bool, error) {
+	b.stub.AddCall("MachineEgressRules", machineId)
+	return b.rules, b.restricted, b.stub.NextErr()
+}
+
+func (b *mockBackend) APIHostPortsForAgents() ([]network.SpaceHostPorts, error) {
+	b.stub.AddCall("APIHostPortsForAgents")
+	return b.apiHostPorts, b.stub.NextErr()
+}
+
+func (b *mockBackend) WatchMachineEgressRules(machineId string) (state.NotifyWatcher, error) {
+	b.stub.AddCall("WatchMachineEgressRules", machineId)
+	return b.watcher, b.stub.NextErr()
+}
+
+func (b *mockBackend) MachineEgressEnforcedByProvider(machineId string) (bool, error) {
+	b.stub.AddCall("MachineEgressEnforcedByProvider", machineId)
+	return b.providerEnforced, b.stub.NextErr()
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package egressfirewaller
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/state"
+)
+
+// NewFacade wraps New to express the supplied *state.State as a Backend.
+func NewFacade(st *state.State, res facade.Resources, auth facade.Authorizer) (*Facade, error) {
+	facade, err := New(backendShim{st}, res, auth)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return facade, nil
+}
+
+type backendShim struct {
+	st *state.State
+}
+
+func (b backendShim) MachineEgressRules(machineId string) ([]state.EgressRule, bool, error) {
+	m, err := b.st.Machine(machineId)
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	return m.EgressRules()
+}
+
+func (b backendShim) APIHostPortsForAgents() ([]network.SpaceHostPorts, error) {
+	return b.st.APIHostPortsForAgents()
+}
+
+// WatchMachineEgressRules combines the watcher of the model's egress
+// rules with the machine's watcher, which notifies when units are
+// assigned to or removed from the machine.
+func (b backendShim) WatchMachineEgressRules(machineId string) (state.NotifyWatcher, error) {
+	m, err := b.st.Machine(machineId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return common.NewMultiNotifyWatcher(b.st.WatchEgressRules(), m.Watch()), nil
+}
+
+// MachineEgressEnforcedByProvider returns whether the firewaller has
+// recorded that the provider's firewall enforces the machine's egress
+// rules.
+func (b backendShim) MachineEgressEnforcedByProvider(machineId string) (bool, error) {
+	m, err := b.st.Machine(machineId)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return m.EgressEnforcedByProvider(), nil
+}
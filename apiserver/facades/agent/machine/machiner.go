@@ -6,6 +6,9 @@
 package machine
 
 import (
+	"time"
+
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"gopkg.in/juju/names.v3"
 
@@ -19,7 +22,7 @@ import (
 
 var logger = loggo.GetLogger("juju.apiserver.machine")
 
-// MachinerAPI implements the API used by the machiner worker.
+// MachinerAPI implements version 2 of the API used by the machiner worker.
 type MachinerAPI struct {
 	*common.LifeGetter
 	*common.StatusSetter
@@ -34,6 +37,21 @@ type MachinerAPI struct {
 	getCanRead   common.GetAuthFunc
 }
 
+// MachinerAPIV1 implements version 1 of the Machiner API, which does not
+// include NetplanConfig and SetNetplanStatus.
+type MachinerAPIV1 struct {
+	*MachinerAPI
+}
+
+// NewMachinerAPIV1 creates a new instance of version 1 of the Machiner API.
+func NewMachinerAPIV1(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*MachinerAPIV1, error) {
+	api, err := NewMachinerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &MachinerAPIV1{api}, nil
+}
+
 // NewMachinerAPI creates a new instance of the Machiner API.
 func NewMachinerAPI(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*MachinerAPI, error) {
 	if !authorizer.AuthMachineAgent() {
@@ -139,3 +157,94 @@ func (api *MachinerAPI) Jobs(args params.Entities) (params.JobsResults, error) {
 	}
 	return result, nil
 }
+
+// NetplanConfig returns the desired network device configuration of each
+// given machine, rendered from the link-layer devices stored for it, and
+// whether the machine agent should apply it. Containers never apply it,
+// since their devices are configured by the host.
+func (api *MachinerAPI) NetplanConfig(args params.Entities) (params.MachineNetplanConfigResults, error) {
+	result := params.MachineNetplanConfigResults{
+		Results: make([]params.MachineNetplanConfigResult, len(args.Entities)),
+	}
+	canRead, err := api.getCanRead()
+	if err != nil {
+		return result, err
+	}
+	model, err := api.st.Model()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	modelConfig, err := model.Config()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if !canRead(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		machine, err := api.getMachine(tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		devices, err := networkingcommon.MachineNetplanDevices(machine)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Devices = networkingcommon.NetplanDevicesToParams(devices)
+		result.Results[i].Manage = modelConfig.ManageNetplan() && !names.IsContainerMachine(tag.Id())
+	}
+	return result, nil
+}
+
+// SetNetplanStatus records the netplan configuration in effect on each
+// given machine, and the reason the desired configuration could not be
+// applied, if any.
+func (api *MachinerAPI) SetNetplanStatus(args params.SetMachinesNetplanStatus) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Statuses)),
+	}
+	canModify, err := api.getCanModify()
+	if err != nil {
+		return results, err
+	}
+	for i, arg := range args.Statuses {
+		tag, err := names.ParseMachineTag(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !canModify(tag) {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		m, err := api.getMachine(tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		err = m.SetNetplanStatus(state.MachineNetplanStatus{
+			Actual:  arg.Actual,
+			Error:   arg.Error,
+			Updated: time.Now().UTC(),
+		})
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return results, nil
+}
+
+// NetplanConfig isn't on the v1 API.
+func (api *MachinerAPIV1) NetplanConfig(_, _ struct{}) {}
+
+// SetNetplanStatus isn't on the v1 API.
+func (api *MachinerAPIV1) SetNetplanStatus(_, _ struct{}) {}
@@ -6,6 +6,7 @@ package machine_test
 import (
 	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -15,6 +16,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/agent/machine"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
@@ -288,3 +290,110 @@ func (s *machinerSuite) TestWatch(c *gc.C) {
 	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
 	wc.AssertNoChange()
 }
+
+func (s *machinerSuite) TestNetplanConfig(c *gc.C) {
+	err := s.Model.UpdateModelConfig(map[string]interface{}{"manage-netplan": true}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine1.SetLinkLayerDevices(
+		state.LinkLayerDeviceArgs{
+			Name:       "bond0",
+			Type:       network.BondDevice,
+			MACAddress: "aa:bb:cc:dd:ee:f0",
+			BondMode:   "802.3ad",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine1.SetLinkLayerDevices(
+		state.LinkLayerDeviceArgs{
+			Name:       "eth0",
+			Type:       network.EthernetDevice,
+			MACAddress: "aa:bb:cc:dd:ee:f0",
+			ParentName: "bond0",
+			ProviderID: "eth0-id",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine1.SetDevicesAddresses(state.LinkLayerDeviceAddress{
+		DeviceName:   "bond0",
+		ConfigMethod: state.StaticAddress,
+		CIDRAddress:  "10.0.0.5/24",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "machine-1"},
+		{Tag: "machine-0"},
+		{Tag: "machine-42"},
+	}}
+	result, err := s.machiner.NetplanConfig(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.MachineNetplanConfigResults{
+		Results: []params.MachineNetplanConfigResult{
+			{
+				Devices: []params.NetplanDevice{{
+					Name:       "bond0",
+					Type:       "bond",
+					MACAddress: "aa:bb:cc:dd:ee:f0",
+					BondMode:   "802.3ad",
+					Addresses:  []string{"10.0.0.5/24"},
+				}, {
+					Name:       "eth0",
+					Type:       "ethernet",
+					MACAddress: "aa:bb:cc:dd:ee:f0",
+					ParentName: "bond0",
+				}},
+				Manage: true,
+			},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *machinerSuite) TestNetplanConfigNotManagedForContainers(c *gc.C) {
+	err := s.Model.UpdateModelConfig(map[string]interface{}{"manage-netplan": true}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, s.machine1.Id(), instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+	authorizer := s.authorizer
+	authorizer.Tag = container.Tag()
+	machiner, err := machine.NewMachinerAPI(s.State, s.resources, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := machiner.NetplanConfig(params.Entities{Entities: []params.Entity{
+		{Tag: container.Tag().String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Check(result.Results[0].Manage, jc.IsFalse)
+}
+
+func (s *machinerSuite) TestSetNetplanStatus(c *gc.C) {
+	args := params.SetMachinesNetplanStatus{Statuses: []params.MachineNetplanStatus{
+		{Tag: "machine-1", Actual: "network:\n  version: 2\n", Error: "netplan apply error code 1"},
+		{Tag: "machine-0", Actual: "network:\n  version: 2\n"},
+		{Tag: "machine-42", Actual: "network:\n  version: 2\n"},
+	}}
+	result, err := s.machiner.SetNetplanStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	status, err := s.machine1.NetplanStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(status.Actual, gc.Equals, "network:\n  version: 2\n")
+	c.Check(status.Error, gc.Equals, "netplan apply error code 1")
+	c.Check(status.Updated.IsZero(), jc.IsFalse)
+
+	_, err = s.machine0.NetplanStatus()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
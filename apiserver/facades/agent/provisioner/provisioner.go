@@ -190,6 +190,18 @@ type ProvisionerAPIV8 struct {
 // ProvisionerAPIV9 provides v9 of the provisioner facade.
 // Added SupportedContainers
 type ProvisionerAPIV9 struct {
+	*ProvisionerAPIV10
+}
+
+// ProvisionerAPIV10 provides v10 of the provisioner facade.
+// Added ResetInstance
+type ProvisionerAPIV10 struct {
+	*ProvisionerAPIV11
+}
+
+// ProvisionerAPIV11 provides v11 of the provisioner facade.
+// Added Protected
+type ProvisionerAPIV11 struct {
 	*ProvisionerAPI
 }
 
@@ -240,13 +252,31 @@ func NewProvisionerAPIV8(st *state.State, resources facade.Resources, authorizer
 
 // NewProvisionerAPIV9 creates a new server-side Provisioner API facade.
 func NewProvisionerAPIV9(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*ProvisionerAPIV9, error) {
-	provisionerAPI, err := NewProvisionerAPI(st, resources, authorizer)
+	provisionerAPI, err := NewProvisionerAPIV10(st, resources, authorizer)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return &ProvisionerAPIV9{provisionerAPI}, nil
 }
 
+// NewProvisionerAPIV10 creates a new server-side Provisioner API facade.
+func NewProvisionerAPIV10(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*ProvisionerAPIV10, error) {
+	provisionerAPI, err := NewProvisionerAPIV11(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ProvisionerAPIV10{provisionerAPI}, nil
+}
+
+// NewProvisionerAPIV11 creates a new server-side Provisioner API facade.
+func NewProvisionerAPIV11(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*ProvisionerAPIV11, error) {
+	provisionerAPI, err := NewProvisionerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ProvisionerAPIV11{provisionerAPI}, nil
+}
+
 func (api *ProvisionerAPI) getMachine(canAccess common.AuthFunc, tag names.MachineTag) (*state.Machine, error) {
 	if !canAccess(tag) {
 		return nil, common.ErrPerm
@@ -486,16 +516,20 @@ func (api *ProvisionerAPI) MachinesWithTransientErrors() (params.StatusResults,
 		if !canAccessFunc(machine.Tag()) {
 			continue
 		}
-		if _, provisionedErr := machine.InstanceId(); provisionedErr == nil {
-			// Machine may have been provisioned but machiner hasn't set the
-			// status to Started yet.
-			continue
-		}
+		_, provisionedErr := machine.InstanceId()
 		var result params.StatusResult
 		statusInfo, err := machine.InstanceStatus()
 		if err != nil {
 			continue
 		}
+		if provisionedErr == nil {
+			// Machine may have been provisioned but machiner hasn't set the
+			// status to Started yet. Only machines whose instance has been
+			// interrupted by the cloud need to be provisioned again.
+			if interrupted, ok := statusInfo.Data["interrupted"].(bool); !ok || !interrupted {
+				continue
+			}
+		}
 		result.Status = statusInfo.Status.String()
 		result.Info = statusInfo.Message
 		result.Data = statusInfo.Data
@@ -596,6 +630,34 @@ func (api *ProvisionerAPI) KeepInstance(args params.Entities) (params.BoolResult
 	return result, nil
 }
 
+// Protected isn't on the v10 or lower API.
+func (p *ProvisionerAPIV10) Protected(_, _ struct{}) {}
+
+// Protected returns, for each given machine entity, whether its
+// cloud instance is protected from termination.
+func (api *ProvisionerAPI) Protected(args params.Entities) (params.BoolResults, error) {
+	result := params.BoolResults{
+		Results: make([]params.BoolResult, len(args.Entities)),
+	}
+	canAccess, err := api.getAuthFunc()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		machine, err := api.getMachine(canAccess, tag)
+		if err == nil {
+			result.Results[i].Result = machine.IsProtected()
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 // DistributionGroup returns, for each given machine entity,
 // a slice of instance.Ids that belong to the same distribution
 // group as that machine. This information may be used to
@@ -731,7 +793,8 @@ func controllerMachineIds(st *state.State, m *state.Machine) ([]string, error) {
 }
 
 // commonApplicationMachineId returns a slice of machine.Ids with
-// applications in common with the specified machine.
+// applications or an anti-affinity group in common with the specified
+// machine.
 func commonApplicationMachineId(st *state.State, m *state.Machine) ([]string, error) {
 	applications := m.Principals()
 	union := set.NewStrings()
@@ -742,6 +805,17 @@ func commonApplicationMachineId(st *state.State, m *state.Machine) ([]string, er
 		}
 		union = union.Union(set.NewStrings(machines...))
 	}
+	cons, err := m.Constraints()
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if cons.HasAntiAffinity() {
+		machines, err := state.AntiAffinityMachines(st, *cons.AntiAffinity)
+		if err != nil {
+			return nil, err
+		}
+		union = union.Union(set.NewStrings(machines...))
+	}
 	union.Remove(m.Id())
 	return union.SortedValues(), nil
 }
@@ -1484,6 +1558,36 @@ func (api *ProvisionerAPI) setOneModificationStatus(canAccess common.AuthFunc, a
 	return nil
 }
 
+// ResetInstance isn't on the v9 or lower API.
+func (p *ProvisionerAPIV9) ResetInstance(_, _ struct{}) {}
+
+// ResetInstance removes the record of each given machine's provider
+// instance, so that a replacement instance can be provisioned. It is
+// used when the cloud has reclaimed an instance, such as an interrupted
+// spot instance.
+func (api *ProvisionerAPI) ResetInstance(args params.Entities) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := api.getAuthFunc()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		mTag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		machine, err := api.getMachine(canAccess, mTag)
+		if err == nil {
+			err = machine.ResetInstance()
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 // MarkMachinesForRemoval indicates that the specified machines are
 // ready to have any provider-level resources cleaned up and then be
 // removed.
@@ -52,7 +52,7 @@ type provisionerSuite struct {
 
 	authorizer  apiservertesting.FakeAuthorizer
 	resources   *common.Resources
-	provisioner *provisioner.ProvisionerAPIV9
+	provisioner *provisioner.ProvisionerAPIV11
 }
 
 var _ = gc.Suite(&provisionerSuite{})
@@ -93,7 +93,7 @@ func (s *provisionerSuite) setUpTest(c *gc.C, withController bool) {
 	s.resources = common.NewResources()
 
 	// Create a provisioner API for the machine.
-	provisionerAPI, err := provisioner.NewProvisionerAPIV9(
+	provisionerAPI, err := provisioner.NewProvisionerAPIV11(
 		s.State,
 		s.resources,
 		s.authorizer,
@@ -544,6 +544,56 @@ func (s *withoutControllerSuite) TestMachinesWithTransientErrors(c *gc.C) {
 	})
 }
 
+func (s *withoutControllerSuite) TestMachinesWithTransientErrorsInterrupted(c *gc.C) {
+	hwChars := instance.MustParseHardware("arch=i386", "mem=4G")
+	err := s.machines[1].SetProvisioned("i-spot", "", "fake_nonce", &hwChars)
+	c.Assert(err, jc.ErrorIsNil)
+	now := time.Now()
+	sInfo := status.StatusInfo{
+		Status:  status.ProvisioningError,
+		Message: "spot instance interrupted",
+		Data:    map[string]interface{}{"transient": true, "interrupted": true},
+		Since:   &now,
+	}
+	err = s.machines[1].SetInstanceStatus(sInfo)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.provisioner.MachinesWithTransientErrors()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StatusResults{
+		Results: []params.StatusResult{
+			{Id: "1", Life: "alive", Status: "provisioning error", Info: "spot instance interrupted",
+				Data: map[string]interface{}{"transient": true, "interrupted": true}},
+		},
+	})
+}
+
+func (s *withoutControllerSuite) TestResetInstance(c *gc.C) {
+	err := s.machines[0].SetProvisioned("i-am", "", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	res, err := s.provisioner.ResetInstance(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "machine-0"},         // ok
+			{Tag: "machine-1"},         // not provisioned
+			{Tag: "machine-0-lxd-5"},   // unauthorised
+			{Tag: "application-thing"}, // only machines allowed
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	results := res.Results
+	c.Assert(results, gc.HasLen, 4)
+	c.Check(results[0].Error, gc.IsNil)
+	c.Check(results[1].Error, jc.Satisfies, params.IsCodeNotProvisioned)
+	c.Check(*results[2].Error, jc.DeepEquals, *apiservertesting.ErrUnauthorized)
+	c.Check(*results[3].Error, jc.DeepEquals, *apiservertesting.ErrUnauthorized)
+
+	err = s.machines[0].Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.machines[0].InstanceId()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+}
+
 func (s *withoutControllerSuite) TestMachinesWithTransientErrorsPermission(c *gc.C) {
 	// Machines where there's permission issues are omitted.
 	anAuthorizer := s.authorizer
@@ -952,6 +1002,30 @@ func (s *withoutControllerSuite) TestKeepInstance(c *gc.C) {
 	})
 }
 
+func (s *withoutControllerSuite) TestProtected(c *gc.C) {
+	err := s.machines[1].SetProtected(true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: s.machines[0].Tag().String()},
+		{Tag: s.machines[1].Tag().String()},
+		{Tag: "machine-42"},
+		{Tag: "unit-foo-0"},
+		{Tag: "application-bar"},
+	}}
+	result, err := s.provisioner.Protected(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{
+			{Result: false},
+			{Result: true},
+			{Error: apiservertesting.NotFoundError("machine 42")},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
 func (s *withoutControllerSuite) TestDistributionGroup(c *gc.C) {
 	addUnits := func(name string, machines ...*state.Machine) (units []*state.Unit) {
 		app := s.AddTestingApplication(c, name, s.AddTestingCharm(c, name))
@@ -1137,6 +1211,28 @@ func (s *withoutControllerSuite) TestDistributionGroupByMachineId(c *gc.C) {
 	})
 }
 
+func (s *withoutControllerSuite) TestDistributionGroupByMachineIdAntiAffinity(c *gc.C) {
+	for _, m := range []*state.Machine{s.machines[1], s.machines[4]} {
+		err := m.SetConstraints(constraints.MustParse("anti-affinity=db"))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: s.machines[0].Tag().String()},
+		{Tag: s.machines[1].Tag().String()},
+		{Tag: s.machines[4].Tag().String()},
+	}}
+	result, err := s.provisioner.DistributionGroupByMachineId(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringsResults{
+		Results: []params.StringsResult{
+			{Result: []string{}},
+			{Result: []string{"4"}},
+			{Result: []string{"1"}},
+		},
+	})
+}
+
 func (s *withoutControllerSuite) TestDistributionGroupByMachineIdControllerAuth(c *gc.C) {
 	args := params.Entities{Entities: []params.Entity{
 		{Tag: "machine-0"},
@@ -56,6 +56,33 @@ func NewFacadeV4(st *state.State, resources facade.Resources, authorizer facade.
 	return NewStorageProvisionerAPIv4(v3), nil
 }
 
+// NewFacadeV5 provides the signature required for facade registration.
+func NewFacadeV5(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*StorageProvisionerAPIv5, error) {
+	v4, err := NewFacadeV4(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewStorageProvisionerAPIv5(v4), nil
+}
+
+// NewFacadeV6 provides the signature required for facade registration.
+func NewFacadeV6(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*StorageProvisionerAPIv6, error) {
+	v5, err := NewFacadeV5(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewStorageProvisionerAPIv6(v5), nil
+}
+
+// NewFacadeV7 provides the signature required for facade registration.
+func NewFacadeV7(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*StorageProvisionerAPIv7, error) {
+	v6, err := NewFacadeV6(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewStorageProvisionerAPIv7(v6), nil
+}
+
 type Backend interface {
 	state.EntityFinder
 	state.ModelAccessor
@@ -84,6 +111,14 @@ type StorageBackend interface {
 	WatchUnitVolumeAttachments(tag names.ApplicationTag) state.StringsWatcher
 	WatchVolumeAttachment(names.Tag, names.VolumeTag) state.NotifyWatcher
 	WatchMachineAttachmentsPlans(names.MachineTag) state.StringsWatcher
+	WatchModelVolumeResizes() state.StringsWatcher
+	WatchMachineVolumeResizes(names.MachineTag) state.StringsWatcher
+	WatchModelFilesystemResizes() state.StringsWatcher
+	WatchMachineFilesystemResizes(names.MachineTag) state.StringsWatcher
+	WatchUnitFilesystemResizes(names.ApplicationTag) state.StringsWatcher
+	WatchModelVolumeSnapshots() state.StringsWatcher
+	WatchMachineVolumeSnapshots(names.MachineTag) state.StringsWatcher
+	WatchMachineStorageMigrations(names.MachineTag) state.StringsWatcher
 
 	StorageInstance(names.StorageTag) (state.StorageInstance, error)
 	AllStorageInstances() ([]state.StorageInstance, error)
@@ -100,6 +135,8 @@ type StorageBackend interface {
 	VolumeAttachments(names.VolumeTag) ([]state.VolumeAttachment, error)
 	VolumeAttachmentPlan(names.Tag, names.VolumeTag) (state.VolumeAttachmentPlan, error)
 	VolumeAttachmentPlans(volume names.VolumeTag) ([]state.VolumeAttachmentPlan, error)
+	StorageSnapshot(string) (state.StorageSnapshot, error)
+	StorageMigration(string) (state.StorageMigration, error)
 
 	RemoveFilesystem(names.FilesystemTag) error
 	RemoveFilesystemAttachment(names.Tag, names.FilesystemTag) error
@@ -114,6 +151,10 @@ type StorageBackend interface {
 	SetFilesystemAttachmentInfo(names.Tag, names.FilesystemTag, state.FilesystemAttachmentInfo) error
 	SetVolumeInfo(names.VolumeTag, state.VolumeInfo) error
 	SetVolumeAttachmentInfo(names.Tag, names.VolumeTag, state.VolumeAttachmentInfo) error
+	SetStorageSnapshotInfo(id, snapshotId string, size uint64) error
+	SetStorageSnapshotError(id, message string) error
+	CompleteStorageMigration(id string) error
+	SetStorageMigrationError(id, message string) error
 
 	CreateVolumeAttachmentPlan(names.Tag, names.VolumeTag, state.VolumeAttachmentPlanInfo) error
 	RemoveVolumeAttachmentPlan(names.Tag, names.VolumeTag) error
@@ -4,6 +4,8 @@
 package storageprovisioner
 
 import (
+	"fmt"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"gopkg.in/juju/names.v3"
@@ -22,6 +24,21 @@ import (
 
 var logger = loggo.GetLogger("juju.apiserver.storageprovisioner")
 
+// StorageProvisionerAPIv7 provides the StorageProvisioner API v7 facade.
+type StorageProvisionerAPIv7 struct {
+	*StorageProvisionerAPIv6
+}
+
+// StorageProvisionerAPIv6 provides the StorageProvisioner API v6 facade.
+type StorageProvisionerAPIv6 struct {
+	*StorageProvisionerAPIv5
+}
+
+// StorageProvisionerAPIv5 provides the StorageProvisioner API v5 facade.
+type StorageProvisionerAPIv5 struct {
+	*StorageProvisionerAPIv4
+}
+
 // StorageProvisionerAPIv4 provides the StorageProvisioner API v4 facade.
 type StorageProvisionerAPIv4 struct {
 	*StorageProvisionerAPIv3
@@ -47,6 +64,21 @@ type StorageProvisionerAPIv3 struct {
 	getAttachmentAuthFunc    func() (func(names.Tag, names.Tag) bool, error)
 }
 
+// NewStorageProvisionerAPIv7 creates a new server-side StorageProvisioner v7 facade.
+func NewStorageProvisionerAPIv7(v6 *StorageProvisionerAPIv6) *StorageProvisionerAPIv7 {
+	return &StorageProvisionerAPIv7{v6}
+}
+
+// NewStorageProvisionerAPIv6 creates a new server-side StorageProvisioner v6 facade.
+func NewStorageProvisionerAPIv6(v5 *StorageProvisionerAPIv5) *StorageProvisionerAPIv6 {
+	return &StorageProvisionerAPIv6{v5}
+}
+
+// NewStorageProvisionerAPIv5 creates a new server-side StorageProvisioner v5 facade.
+func NewStorageProvisionerAPIv5(v4 *StorageProvisionerAPIv4) *StorageProvisionerAPIv5 {
+	return &StorageProvisionerAPIv5{v4}
+}
+
 // NewStorageProvisionerAPIv4 creates a new server-side StorageProvisioner v4 facade.
 func NewStorageProvisionerAPIv4(v3 *StorageProvisionerAPIv3) *StorageProvisionerAPIv4 {
 	return &StorageProvisionerAPIv4{v3}
@@ -352,8 +384,14 @@ func (s *StorageProvisionerAPIv3) watchStorageEntities(
 		case names.MachineTag:
 			w = watchMachineStorage(tag)
 		case names.ModelTag:
+			if watchEnvironStorage == nil {
+				return "", nil, errors.NotSupportedf("watching storage for %v", tag)
+			}
 			w = watchEnvironStorage()
 		case names.ApplicationTag:
+			if watchApplicationStorage == nil {
+				return "", nil, errors.NotSupportedf("watching storage for %v", tag)
+			}
 			w = watchApplicationStorage(tag)
 		default:
 			return "", nil, common.ServerError(errors.NotSupportedf("watching storage for %v", tag))
@@ -986,6 +1024,372 @@ func (s *StorageProvisionerAPIv4) RemoveFilesystemParams(args params.Entities) (
 	return results, nil
 }
 
+// WatchVolumeResizes watches for changes to volumes scoped to the
+// entity with the tag passed to NewState, including requests to
+// resize them.
+func (s *StorageProvisionerAPIv5) WatchVolumeResizes(args params.Entities) (params.StringsWatchResults, error) {
+	return s.watchStorageEntities(args, s.sb.WatchModelVolumeResizes, s.sb.WatchMachineVolumeResizes, nil)
+}
+
+// WatchFilesystemResizes watches for changes to filesystems scoped
+// to the entity with the tag passed to NewState, including requests
+// to resize them.
+func (s *StorageProvisionerAPIv5) WatchFilesystemResizes(args params.Entities) (params.StringsWatchResults, error) {
+	return s.watchStorageEntities(args,
+		s.sb.WatchModelFilesystemResizes,
+		s.sb.WatchMachineFilesystemResizes,
+		s.sb.WatchUnitFilesystemResizes)
+}
+
+// VolumeResizeParams returns the parameters for growing the volumes
+// with the specified tags. If a volume has no pending resize request,
+// an error satisfying params.IsCodeNotFound is returned for it.
+func (s *StorageProvisionerAPIv5) VolumeResizeParams(args params.Entities) (params.VolumeResizeParamsResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.VolumeResizeParamsResults{}, err
+	}
+	results := params.VolumeResizeParamsResults{
+		Results: make([]params.VolumeResizeParamsResult, len(args.Entities)),
+	}
+	one := func(arg params.Entity) (params.VolumeResizeParams, error) {
+		tag, err := names.ParseVolumeTag(arg.Tag)
+		if err != nil || !canAccess(tag) {
+			return params.VolumeResizeParams{}, common.ErrPerm
+		}
+		volume, err := s.sb.Volume(tag)
+		if errors.IsNotFound(err) {
+			return params.VolumeResizeParams{}, common.ErrPerm
+		} else if err != nil {
+			return params.VolumeResizeParams{}, err
+		}
+		size, ok := volume.RequestedSize()
+		if !ok || volume.Life() != state.Alive {
+			return params.VolumeResizeParams{}, errors.NotFoundf(
+				"resize request for %s", names.ReadableString(tag),
+			)
+		}
+		volumeInfo, err := volume.Info()
+		if err != nil {
+			return params.VolumeResizeParams{}, err
+		}
+		provider, _, err := storagecommon.StoragePoolConfig(
+			volumeInfo.Pool, s.poolManager, s.registry,
+		)
+		if err != nil {
+			return params.VolumeResizeParams{}, err
+		}
+		return params.VolumeResizeParams{
+			VolumeTag: tag.String(),
+			Provider:  string(provider),
+			Size:      size,
+			Info:      storagecommon.VolumeInfoFromState(volumeInfo),
+		}, nil
+	}
+	for i, arg := range args.Entities {
+		var result params.VolumeResizeParamsResult
+		resizeParams, err := one(arg)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Result = resizeParams
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+// FilesystemResizeParams returns the parameters for growing the
+// filesystems with the specified tags. If a filesystem has no pending
+// resize request, an error satisfying params.IsCodeNotFound is returned
+// for it.
+func (s *StorageProvisionerAPIv5) FilesystemResizeParams(args params.Entities) (params.FilesystemResizeParamsResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.FilesystemResizeParamsResults{}, err
+	}
+	results := params.FilesystemResizeParamsResults{
+		Results: make([]params.FilesystemResizeParamsResult, len(args.Entities)),
+	}
+	one := func(arg params.Entity) (params.FilesystemResizeParams, error) {
+		tag, err := names.ParseFilesystemTag(arg.Tag)
+		if err != nil || !canAccess(tag) {
+			return params.FilesystemResizeParams{}, common.ErrPerm
+		}
+		filesystem, err := s.sb.Filesystem(tag)
+		if errors.IsNotFound(err) {
+			return params.FilesystemResizeParams{}, common.ErrPerm
+		} else if err != nil {
+			return params.FilesystemResizeParams{}, err
+		}
+		size, ok := filesystem.RequestedSize()
+		if !ok || filesystem.Life() != state.Alive {
+			return params.FilesystemResizeParams{}, errors.NotFoundf(
+				"resize request for %s", names.ReadableString(tag),
+			)
+		}
+		filesystemInfo, err := filesystem.Info()
+		if err != nil {
+			return params.FilesystemResizeParams{}, err
+		}
+		provider, _, err := storagecommon.StoragePoolConfig(
+			filesystemInfo.Pool, s.poolManager, s.registry,
+		)
+		if err != nil {
+			return params.FilesystemResizeParams{}, err
+		}
+		return params.FilesystemResizeParams{
+			FilesystemTag: tag.String(),
+			Provider:      string(provider),
+			Size:          size,
+			Info:          storagecommon.FilesystemInfoFromState(filesystemInfo),
+		}, nil
+	}
+	for i, arg := range args.Entities {
+		var result params.FilesystemResizeParamsResult
+		resizeParams, err := one(arg)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Result = resizeParams
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+// WatchVolumeSnapshots watches for changes to snapshots of volumes
+// scoped to the entity with the tag passed to NewState.
+func (s *StorageProvisionerAPIv6) WatchVolumeSnapshots(args params.Entities) (params.StringsWatchResults, error) {
+	return s.watchStorageEntities(args, s.sb.WatchModelVolumeSnapshots, s.sb.WatchMachineVolumeSnapshots, nil)
+}
+
+// VolumeSnapshotParams returns the parameters for creating the volume
+// snapshots with the specified IDs. If a snapshot is not pending
+// creation, an error satisfying params.IsCodeNotFound is returned
+// for it.
+func (s *StorageProvisionerAPIv6) VolumeSnapshotParams(args params.StorageSnapshotIds) (params.VolumeSnapshotParamsResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.VolumeSnapshotParamsResults{}, err
+	}
+	modelCfg, err := s.st.ModelConfig()
+	if err != nil {
+		return params.VolumeSnapshotParamsResults{}, err
+	}
+	controllerCfg, err := s.st.ControllerConfig()
+	if err != nil {
+		return params.VolumeSnapshotParamsResults{}, err
+	}
+	results := params.VolumeSnapshotParamsResults{
+		Results: make([]params.VolumeSnapshotParamsResult, len(args.Ids)),
+	}
+	one := func(id string) (params.VolumeSnapshotParams, error) {
+		snapshot, err := s.sb.StorageSnapshot(id)
+		if errors.IsNotFound(err) {
+			return params.VolumeSnapshotParams{}, common.ErrPerm
+		} else if err != nil {
+			return params.VolumeSnapshotParams{}, err
+		}
+		volumeTag := snapshot.VolumeTag()
+		if !canAccess(volumeTag) {
+			return params.VolumeSnapshotParams{}, common.ErrPerm
+		}
+		if status, _ := snapshot.Status(); status != state.StorageSnapshotPending {
+			return params.VolumeSnapshotParams{}, errors.NotFoundf("pending storage snapshot %q", id)
+		}
+		volume, err := s.sb.Volume(volumeTag)
+		if errors.IsNotFound(err) {
+			return params.VolumeSnapshotParams{}, common.ErrPerm
+		} else if err != nil {
+			return params.VolumeSnapshotParams{}, err
+		}
+		volumeInfo, err := volume.Info()
+		if err != nil {
+			return params.VolumeSnapshotParams{}, err
+		}
+		provider, _, err := storagecommon.StoragePoolConfig(
+			volumeInfo.Pool, s.poolManager, s.registry,
+		)
+		if err != nil {
+			return params.VolumeSnapshotParams{}, err
+		}
+		var storageInstance state.StorageInstance
+		if si, err := s.sb.StorageInstance(snapshot.StorageTag()); err == nil {
+			storageInstance = si
+		} else if !errors.IsNotFound(err) {
+			return params.VolumeSnapshotParams{}, err
+		}
+		snapshotTags, err := storagecommon.StorageTags(
+			storageInstance, modelCfg.UUID(), controllerCfg.ControllerUUID(), modelCfg,
+		)
+		if err != nil {
+			return params.VolumeSnapshotParams{}, errors.Annotate(err, "computing storage tags")
+		}
+		return params.VolumeSnapshotParams{
+			Id:        id,
+			VolumeTag: volumeTag.String(),
+			Provider:  string(provider),
+			VolumeId:  volumeInfo.VolumeId,
+			Tags:      snapshotTags,
+		}, nil
+	}
+	for i, id := range args.Ids {
+		var result params.VolumeSnapshotParamsResult
+		snapshotParams, err := one(id)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Result = snapshotParams
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+// SetVolumeSnapshotInfo records the outcome of creating volume snapshots.
+func (s *StorageProvisionerAPIv6) SetVolumeSnapshotInfo(args params.VolumeSnapshotInfos) (params.ErrorResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Snapshots)),
+	}
+	one := func(arg params.VolumeSnapshotInfo) error {
+		snapshot, err := s.sb.StorageSnapshot(arg.Id)
+		if errors.IsNotFound(err) {
+			return common.ErrPerm
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if !canAccess(snapshot.VolumeTag()) {
+			return common.ErrPerm
+		}
+		if arg.Error != "" {
+			return s.sb.SetStorageSnapshotError(arg.Id, arg.Error)
+		}
+		return s.sb.SetStorageSnapshotInfo(arg.Id, arg.SnapshotId, arg.Size)
+	}
+	for i, arg := range args.Snapshots {
+		err := one(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// WatchStorageMigrations watches for changes to storage migrations
+// carried out on the machine with the tag passed to NewState. The data
+// of a storage migration is copied by the machine's agent, so watching
+// the model or an application is not supported.
+func (s *StorageProvisionerAPIv7) WatchStorageMigrations(args params.Entities) (params.StringsWatchResults, error) {
+	return s.watchStorageEntities(args, nil, s.sb.WatchMachineStorageMigrations, nil)
+}
+
+// StorageMigrationParams returns the parameters for copying the data of
+// the storage migrations with the specified IDs. If the units using the
+// storage have not yet stopped using it, or the source or target block
+// device is not yet visible on the machine, an error satisfying
+// params.IsCodeNotProvisioned is returned; if the data of a migration is
+// no longer to be copied, an error satisfying params.IsCodeNotFound is
+// returned for it.
+func (s *StorageProvisionerAPIv7) StorageMigrationParams(args params.StorageMigrationIds) (params.StorageMigrationParamsResults, error) {
+	canAccess, err := s.getScopeAuthFunc()
+	if err != nil {
+		return params.StorageMigrationParamsResults{}, err
+	}
+	results := params.StorageMigrationParamsResults{
+		Results: make([]params.StorageMigrationParamsResult, len(args.Ids)),
+	}
+	devicePath := func(volumeTag names.VolumeTag, machineTag names.MachineTag) (string, error) {
+		volume, err := s.sb.Volume(volumeTag)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		return storagecommon.VolumeAttachmentDevicePath(s.sb, volume, machineTag)
+	}
+	one := func(id string) (params.StorageMigrationParams, error) {
+		migration, err := s.sb.StorageMigration(id)
+		if errors.IsNotFound(err) {
+			return params.StorageMigrationParams{}, common.ErrPerm
+		} else if err != nil {
+			return params.StorageMigrationParams{}, err
+		}
+		machineTag := migration.Machine()
+		if !canAccess(machineTag) {
+			return params.StorageMigrationParams{}, common.ErrPerm
+		}
+		switch status, _ := migration.Status(); status {
+		case state.StorageMigrationCopying:
+		case state.StorageMigrationPending:
+			return params.StorageMigrationParams{}, errors.NewNotProvisioned(nil, fmt.Sprintf(
+				"storage migration %q is waiting for units to stop using the storage", id,
+			))
+		default:
+			return params.StorageMigrationParams{}, errors.NotFoundf("storage migration %q to copy", id)
+		}
+		sourcePath, err := devicePath(migration.SourceVolume(), machineTag)
+		if err != nil {
+			return params.StorageMigrationParams{}, errors.Annotate(err, "getting source device path")
+		}
+		targetPath, err := devicePath(migration.TargetVolume(), machineTag)
+		if err != nil {
+			return params.StorageMigrationParams{}, errors.Annotate(err, "getting target device path")
+		}
+		return params.StorageMigrationParams{
+			Id:               id,
+			MachineTag:       machineTag.String(),
+			SourceDevicePath: sourcePath,
+			TargetDevicePath: targetPath,
+		}, nil
+	}
+	for i, id := range args.Ids {
+		var result params.StorageMigrationParamsResult
+		migrationParams, err := one(id)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Result = migrationParams
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+// FinishStorageMigrations records the outcome of copying the data of
+// storage migrations. A successful copy completes the migration, so
+// that the storage instance is backed by the target volume; otherwise
+// the migration is marked as failed and the target volume destroyed.
+func (s *StorageProvisionerAPIv7) FinishStorageMigrations(args params.StorageMigrationOutcomes) (params.ErrorResults, error) {
+	canAccess, err := s.getScopeAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Outcomes)),
+	}
+	one := func(arg params.StorageMigrationOutcome) error {
+		migration, err := s.sb.StorageMigration(arg.Id)
+		if errors.IsNotFound(err) {
+			return common.ErrPerm
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if !canAccess(migration.Machine()) {
+			return common.ErrPerm
+		}
+		if arg.Error != "" {
+			return s.sb.SetStorageMigrationError(arg.Id, arg.Error)
+		}
+		return s.sb.CompleteStorageMigration(arg.Id)
+	}
+	for i, arg := range args.Outcomes {
+		err := one(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
 // VolumeAttachmentParams returns the parameters for creating the volume
 // attachments with the specified IDs.
 func (s *StorageProvisionerAPIv3) VolumeAttachmentParams(
@@ -4,6 +4,7 @@
 package storageprovisioner_test
 
 import (
+	"fmt"
 	"sort"
 	"time"
 
@@ -534,6 +535,412 @@ const (
 	dontWait = time.Duration(0)
 )
 
+func (s *iaasProvisionerSuite) TestVolumeResizeParams(c *gc.C) {
+	s.setupVolumes(c)
+
+	application := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{
+			Name: "storage-block",
+		}),
+		Storage: map[string]state.StorageConstraints{
+			"data": {
+				Count: 1,
+				Size:  1024,
+				Pool:  "modelscoped",
+			},
+		},
+	})
+	s.Factory.MakeUnit(c, &factory.UnitParams{
+		Application: application,
+	})
+	testStorage, err := s.storageBackend.AllStorageInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testStorage, gc.HasLen, 1)
+	storageVolume, err := s.storageBackend.StorageInstanceVolume(testStorage[0].StorageTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetVolumeInfo(storageVolume.VolumeTag(), state.VolumeInfo{
+		VolumeId: "zing",
+		Size:     1024,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	sb, err := state.NewStorageBackend(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	err = sb.ResizeStorageInstance(testStorage[0].StorageTag(), 4096)
+	c.Assert(err, jc.ErrorIsNil)
+
+	api := storageprovisioner.NewStorageProvisionerAPIv5(s.api)
+	results, err := api.VolumeResizeParams(params.Entities{
+		Entities: []params.Entity{
+			{storageVolume.Tag().String()},
+			{"volume-0-0"},
+			{"volume-42"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.VolumeResizeParamsResults{
+		Results: []params.VolumeResizeParamsResult{{
+			Result: params.VolumeResizeParams{
+				VolumeTag: storageVolume.Tag().String(),
+				Provider:  "modelscoped",
+				Size:      4096,
+				Info: params.VolumeInfo{
+					VolumeId: "zing",
+					Pool:     "modelscoped",
+					Size:     1024,
+				},
+			},
+		}, {
+			Error: &params.Error{Message: `resize request for volume 0/0 not found`, Code: "not found"},
+		}, {
+			Error: &params.Error{Message: "permission denied", Code: "unauthorized access"},
+		}},
+	})
+}
+
+func (s *iaasProvisionerSuite) TestVolumeSnapshotParams(c *gc.C) {
+	s.setupVolumes(c)
+
+	application := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{
+			Name: "storage-block",
+		}),
+		Storage: map[string]state.StorageConstraints{
+			"data": {
+				Count: 1,
+				Size:  1024,
+				Pool:  "modelscoped",
+			},
+		},
+	})
+	s.Factory.MakeUnit(c, &factory.UnitParams{
+		Application: application,
+	})
+	testStorage, err := s.storageBackend.AllStorageInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testStorage, gc.HasLen, 1)
+	storageTag := testStorage[0].StorageTag()
+	storageVolume, err := s.storageBackend.StorageInstanceVolume(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetVolumeInfo(storageVolume.VolumeTag(), state.VolumeInfo{
+		VolumeId: "zing",
+		Size:     1024,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	sb, err := state.NewStorageBackend(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	pending, err := sb.CreateStorageSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	available, err := sb.CreateStorageSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = sb.SetStorageSnapshotInfo(available.Id(), "snap-1", 1024)
+	c.Assert(err, jc.ErrorIsNil)
+
+	api := storageprovisioner.NewStorageProvisionerAPIv6(storageprovisioner.NewStorageProvisionerAPIv5(s.api))
+	results, err := api.VolumeSnapshotParams(params.StorageSnapshotIds{
+		Ids: []string{pending.Id(), available.Id(), "42"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.VolumeSnapshotParamsResults{
+		Results: []params.VolumeSnapshotParamsResult{{
+			Result: params.VolumeSnapshotParams{
+				Id:        pending.Id(),
+				VolumeTag: storageVolume.Tag().String(),
+				Provider:  "modelscoped",
+				VolumeId:  "zing",
+				Tags: map[string]string{
+					tags.JujuController:      testing.ControllerTag.Id(),
+					tags.JujuModel:           testing.ModelTag.Id(),
+					tags.JujuStorageInstance: storageTag.Id(),
+					tags.JujuStorageOwner:    "storage-block/0",
+				},
+			},
+		}, {
+			Error: &params.Error{
+				Message: fmt.Sprintf("pending storage snapshot %q not found", available.Id()),
+				Code:    "not found",
+			},
+		}, {
+			Error: &params.Error{Message: "permission denied", Code: "unauthorized access"},
+		}},
+	})
+}
+
+func (s *iaasProvisionerSuite) TestSetVolumeSnapshotInfo(c *gc.C) {
+	s.setupVolumes(c)
+	sb, err := state.NewStorageBackend(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+
+	application := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{
+			Name: "storage-block",
+		}),
+		Storage: map[string]state.StorageConstraints{
+			"data": {Count: 1, Size: 1024, Pool: "modelscoped"},
+		},
+	})
+	s.Factory.MakeUnit(c, &factory.UnitParams{
+		Application: application,
+	})
+	storageTag := names.NewStorageTag("data/0")
+	storageVolume, err := sb.StorageInstanceVolume(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = sb.SetVolumeInfo(storageVolume.VolumeTag(), state.VolumeInfo{VolumeId: "zing", Size: 1024})
+	c.Assert(err, jc.ErrorIsNil)
+	created, err := sb.CreateStorageSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	failed, err := sb.CreateStorageSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	api := storageprovisioner.NewStorageProvisionerAPIv6(storageprovisioner.NewStorageProvisionerAPIv5(s.api))
+	results, err := api.SetVolumeSnapshotInfo(params.VolumeSnapshotInfos{
+		Snapshots: []params.VolumeSnapshotInfo{
+			{Id: created.Id(), SnapshotId: "snap-1", Size: 2048},
+			{Id: failed.Id(), Error: "badness"},
+			{Id: "42", SnapshotId: "snap-42"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
+		},
+	})
+
+	snapshot, err := sb.StorageSnapshot(created.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	snapshotId, err := snapshot.SnapshotId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshotId, gc.Equals, "snap-1")
+	c.Assert(snapshot.Size(), gc.Equals, uint64(2048))
+
+	snapshot, err = sb.StorageSnapshot(failed.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	status, message := snapshot.Status()
+	c.Assert(status, gc.Equals, state.StorageSnapshotError)
+	c.Assert(message, gc.Equals, "badness")
+}
+
+// setupStorageMigrations creates one application unit with block
+// storage on each of machines 0 and 1, with provisioned and attached
+// volumes, and requests the migration of each unit's storage to the
+// "machinescoped" pool.
+func (s *iaasProvisionerSuite) setupStorageMigrations(c *gc.C) []state.StorageMigration {
+	sb, err := state.NewStorageBackend(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	ch := s.Factory.MakeCharm(c, &factory.CharmParams{Name: "storage-block"})
+	var migrations []state.StorageMigration
+	for i := 0; i < 2; i++ {
+		machine := s.Factory.MakeMachine(c, nil)
+		application := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+			Name:  fmt.Sprintf("storage-block-%d", i),
+			Charm: ch,
+			Storage: map[string]state.StorageConstraints{
+				"data": {Count: 1, Size: 1024, Pool: "modelscoped"},
+			},
+		})
+		unit := s.Factory.MakeUnit(c, &factory.UnitParams{
+			Application: application,
+			Machine:     machine,
+		})
+		attachments, err := sb.UnitStorageAttachments(unit.UnitTag())
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(attachments, gc.HasLen, 1)
+		storageTag := attachments[0].StorageInstance()
+		volume, err := sb.StorageInstanceVolume(storageTag)
+		c.Assert(err, jc.ErrorIsNil)
+		err = sb.SetVolumeInfo(volume.VolumeTag(), state.VolumeInfo{
+			VolumeId:   fmt.Sprintf("vol-%d", i),
+			HardwareId: fmt.Sprintf("source-%d", i),
+			Size:       1024,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		err = sb.SetVolumeAttachmentInfo(machine.MachineTag(), volume.VolumeTag(), state.VolumeAttachmentInfo{})
+		c.Assert(err, jc.ErrorIsNil)
+		migration, err := sb.MigrateStorage(storageTag, "machinescoped")
+		c.Assert(err, jc.ErrorIsNil)
+		migrations = append(migrations, migration)
+	}
+	return migrations
+}
+
+func (s *iaasProvisionerSuite) provisionMigrationTarget(c *gc.C, migration state.StorageMigration) {
+	err := s.storageBackend.SetVolumeInfo(migration.TargetVolume(), state.VolumeInfo{
+		VolumeId:   "target",
+		HardwareId: "target",
+		Size:       1024,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetVolumeAttachmentInfo(
+		migration.Machine(), migration.TargetVolume(), state.VolumeAttachmentInfo{},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// quiesceStorageMigration records that the unit using the storage
+// being migrated has stopped using it, so that the data may be copied.
+func (s *iaasProvisionerSuite) quiesceStorageMigration(c *gc.C, migration state.StorageMigration) {
+	attachments, err := s.storageBackend.StorageAttachments(migration.StorageTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 1)
+	err = s.storageBackend.AcknowledgeStorageMigration(migration.StorageTag(), attachments[0].Unit())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *iaasProvisionerSuite) TestWatchStorageMigrations(c *gc.C) {
+	migrations := s.setupStorageMigrations(c)
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	api := storageprovisioner.NewStorageProvisionerAPIv7(
+		storageprovisioner.NewStorageProvisionerAPIv6(storageprovisioner.NewStorageProvisionerAPIv5(s.api)),
+	)
+	results, err := api.WatchStorageMigrations(params.Entities{Entities: []params.Entity{
+		{"machine-0"},
+		{s.Model.ModelTag().String()},
+		{"machine-1"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.StringsWatchResults{
+		Results: []params.StringsWatchResult{
+			{StringsWatcherId: "1", Changes: []string{migrations[0].Id()}},
+			{Error: &params.Error{
+				Message: fmt.Sprintf("watching storage for %v not supported", s.Model.ModelTag()),
+				Code:    params.CodeNotSupported,
+			}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+}
+
+func (s *iaasProvisionerSuite) TestStorageMigrationParams(c *gc.C) {
+	migrations := s.setupStorageMigrations(c)
+	machine0, err := s.State.Machine("0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine0.SetMachineBlockDevices(state.BlockDeviceInfo{
+		DeviceName: "sda",
+		HardwareId: "source-0",
+	}, state.BlockDeviceInfo{
+		DeviceName: "sdb",
+		HardwareId: "target",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	api := storageprovisioner.NewStorageProvisionerAPIv7(
+		storageprovisioner.NewStorageProvisionerAPIv6(storageprovisioner.NewStorageProvisionerAPIv5(s.api)),
+	)
+	args := params.StorageMigrationIds{
+		Ids: []string{migrations[0].Id(), migrations[1].Id(), "42"},
+	}
+
+	// The unit is still using the storage.
+	s.provisionMigrationTarget(c, migrations[0])
+	results, err := api.StorageMigrationParams(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, jc.Satisfies, params.IsCodeNotProvisioned)
+
+	s.quiesceStorageMigration(c, migrations[0])
+	results, err = api.StorageMigrationParams(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.StorageMigrationParamsResults{
+		Results: []params.StorageMigrationParamsResult{{
+			Result: params.StorageMigrationParams{
+				Id:               migrations[0].Id(),
+				MachineTag:       "machine-0",
+				SourceDevicePath: "/dev/sda",
+				TargetDevicePath: "/dev/sdb",
+			},
+		}, {
+			Error: apiservertesting.ErrUnauthorized,
+		}, {
+			Error: apiservertesting.ErrUnauthorized,
+		}},
+	})
+}
+
+func (s *iaasProvisionerSuite) TestStorageMigrationParamsTargetNotProvisioned(c *gc.C) {
+	migrations := s.setupStorageMigrations(c)
+	s.quiesceStorageMigration(c, migrations[0])
+
+	api := storageprovisioner.NewStorageProvisionerAPIv7(
+		storageprovisioner.NewStorageProvisionerAPIv6(storageprovisioner.NewStorageProvisionerAPIv5(s.api)),
+	)
+	results, err := api.StorageMigrationParams(params.StorageMigrationIds{
+		Ids: []string{migrations[0].Id()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, jc.Satisfies, params.IsCodeNotProvisioned)
+}
+
+func (s *iaasProvisionerSuite) TestFinishStorageMigrations(c *gc.C) {
+	migrations := s.setupStorageMigrations(c)
+	s.provisionMigrationTarget(c, migrations[0])
+	s.quiesceStorageMigration(c, migrations[0])
+
+	api := storageprovisioner.NewStorageProvisionerAPIv7(
+		storageprovisioner.NewStorageProvisionerAPIv6(storageprovisioner.NewStorageProvisionerAPIv5(s.api)),
+	)
+	results, err := api.FinishStorageMigrations(params.StorageMigrationOutcomes{
+		Outcomes: []params.StorageMigrationOutcome{
+			{Id: migrations[0].Id()},
+			{Id: migrations[1].Id(), Error: "badness"},
+			{Id: "42"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	migration, err := s.storageBackend.StorageMigration(migrations[0].Id())
+	c.Assert(err, jc.ErrorIsNil)
+	status, _ := migration.Status()
+	c.Assert(status, gc.Equals, state.StorageMigrationAttaching)
+	volume, err := s.storageBackend.StorageInstanceVolume(migration.StorageTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volume.VolumeTag(), gc.Equals, migration.TargetVolume())
+
+	migration, err = s.storageBackend.StorageMigration(migrations[1].Id())
+	c.Assert(err, jc.ErrorIsNil)
+	status, _ = migration.Status()
+	c.Assert(status, gc.Equals, state.StorageMigrationPending)
+}
+
+func (s *iaasProvisionerSuite) TestWatchVolumeResizes(c *gc.C) {
+	s.setupVolumes(c)
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	api := storageprovisioner.NewStorageProvisionerAPIv5(s.api)
+	args := params.Entities{Entities: []params.Entity{
+		{"machine-0"},
+		{s.Model.ModelTag().String()},
+		{"environ-adb650da-b77b-4ee8-9cbb-d57a9a592847"},
+		{"machine-1"},
+		{"machine-42"}},
+	}
+	result, err := api.WatchVolumeResizes(args)
+	c.Assert(err, jc.ErrorIsNil)
+	sort.Strings(result.Results[1].Changes)
+	c.Assert(result, jc.DeepEquals, params.StringsWatchResults{
+		Results: []params.StringsWatchResult{
+			{StringsWatcherId: "1", Changes: []string{"0/0"}},
+			{StringsWatcherId: "2", Changes: []string{"1", "2", "3", "4"}},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+	c.Assert(s.resources.Count(), gc.Equals, 2)
+}
+
 func (s *iaasProvisionerSuite) TestRemoveVolumeParams(c *gc.C) {
 	// Only IAAS models support block storage right now.
 	s.setupVolumes(c)
@@ -225,11 +225,11 @@ func (n *NetworkInfo) ProcessAPIRequest(args params.NetworkInfoParams) (params.N
 		}
 
 		// If there is no egress subnet explicitly defined for a given binding,
-		// default to the first ingress address. This matches the behaviour when
-		// there's a relation in place.
+		// default to the first ingress address of each IP family. This matches
+		// the behaviour when there's a relation in place.
 		if len(info.EgressSubnets) == 0 && len(info.IngressAddresses) > 0 {
 			var err error
-			info.EgressSubnets, err = network.FormatAsCIDR([]string{info.IngressAddresses[0]})
+			info.EgressSubnets, err = egressFromIngress(info.IngressAddresses)
 			if err != nil {
 				return result, errors.Trace(err)
 			}
@@ -361,7 +361,11 @@ func (n *NetworkInfo) NetworksForRelation(
 
 	// If no egress subnets defined, We default to the ingress address.
 	if len(egress) == 0 && len(ingress) > 0 {
-		egress, err = network.FormatAsCIDR([]string{ingress[0].Value})
+		ingressValues := make([]string, len(ingress))
+		for i, addr := range ingress {
+			ingressValues[i] = addr.Value
+		}
+		egress, err = egressFromIngress(ingressValues)
 		if err != nil {
 			return "", nil, nil, errors.Trace(err)
 		}
@@ -424,6 +428,24 @@ func spaceAddressesFromNetworkInfo(netInfos []network.NetworkInfo) corenetwork.S
 	return addrs
 }
 
+// egressFromIngress returns egress subnets for the input ingress addresses,
+// which are assumed to be sorted in order of preference.
+// The first address of each type is used, so that a unit on a dual-stack
+// network reports both an IPv4 and an IPv6 egress subnet.
+func egressFromIngress(ingress []string) ([]string, error) {
+	var addrs []string
+	seen := set.NewStrings()
+	for _, addr := range ingress {
+		addrType := string(corenetwork.DeriveAddressType(addr))
+		if seen.Contains(addrType) {
+			continue
+		}
+		seen.Add(addrType)
+		addrs = append(addrs, addr)
+	}
+	return network.FormatAsCIDR(addrs)
+}
+
 func pollForAddress(fetcher func() (corenetwork.SpaceAddress, error)) (corenetwork.SpaceAddress, error) {
 	var address corenetwork.SpaceAddress
 	retryArg := PreferredAddressRetryArgs()
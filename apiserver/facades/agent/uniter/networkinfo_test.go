@@ -151,6 +151,38 @@ func (s *networkInfoSuite) TestNetworksForRelationWithSpaces(c *gc.C) {
 	c.Assert(egress, gc.DeepEquals, []string{"3.2.3.4/32"})
 }
 
+func (s *networkInfoSuite) TestNetworksForRelationDualStackSpace(c *gc.C) {
+	subnet4, err := s.State.AddSubnet(network.SubnetInfo{CIDR: "10.2.0.0/16"})
+	c.Assert(err, jc.ErrorIsNil)
+	subnet6, err := s.State.AddSubnet(network.SubnetInfo{CIDR: "2001:db8::/64"})
+	c.Assert(err, jc.ErrorIsNil)
+	space, err := s.State.AddSpace("dual", "pid-dual", []string{subnet4.ID(), subnet6.ID()}, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	prr := s.newProReqRelationWithBindings(c, charm.ScopeGlobal, map[string]string{"": "dual"}, nil)
+	err = prr.pu0.AssignToNewMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := prr.pu0.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.Machine(id)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.addDevicesWithAddresses(c, machine, "2001:db8::4/64", "10.2.3.4/16", "10.2.3.5/16")
+
+	boundSpace, ingress, egress, err := s.newNetworkInfo(c, prr.pu0.UnitTag()).NetworksForRelation("", prr.rel, true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Both address families are returned as ingress, with one egress
+	// subnet for each derived from the first address of that family.
+	c.Assert(boundSpace, gc.Equals, space.Id())
+	c.Assert(ingress, gc.DeepEquals, network.SpaceAddresses{
+		network.NewScopedSpaceAddress("10.2.3.4", network.ScopeCloudLocal),
+		network.NewScopedSpaceAddress("10.2.3.5", network.ScopeCloudLocal),
+		network.NewScopedSpaceAddress("2001:db8::4", network.ScopeCloudLocal),
+	})
+	c.Assert(egress, gc.DeepEquals, []string{"10.2.3.4/32", "2001:db8::4/128"})
+}
+
 func (s *networkInfoSuite) TestNetworksForRelationRemoteRelation(c *gc.C) {
 	prr := s.newRemoteProReqRelation(c)
 	err := prr.ru0.AssignToNewMachine()
@@ -199,12 +199,14 @@ func (s *StorageAPI) fromStateStorageAttachment(stateStorageAttachment state.Sto
 		ownerTag = owner.String()
 	}
 	return params.StorageAttachment{
-		stateStorageAttachment.StorageInstance().String(),
-		ownerTag,
-		stateStorageAttachment.Unit().String(),
-		params.StorageKind(stateStorageInstance.Kind()),
-		info.Location,
-		params.Life(stateStorageAttachment.Life().String()),
+		StorageTag: stateStorageAttachment.StorageInstance().String(),
+		OwnerTag:   ownerTag,
+		UnitTag:    stateStorageAttachment.Unit().String(),
+		Kind:       params.StorageKind(stateStorageInstance.Kind()),
+		Location:   info.Location,
+		Life:       params.Life(stateStorageAttachment.Life().String()),
+		Size:       info.Size,
+		Migration:  string(stateStorageAttachment.Migration()),
 	}, nil
 }
 
@@ -347,6 +349,38 @@ func (s *StorageAPI) removeOneStorageAttachment(id params.StorageAttachmentId, c
 	return err
 }
 
+// AcknowledgeStorageMigrations records that the units have carried out
+// their part in the current stage of the migrations of the specified
+// storage attachments' storage: that they have stopped using the storage,
+// or have run their storage-attached hooks against the migrated storage.
+func (s *StorageAPI) AcknowledgeStorageMigrations(args params.StorageAttachmentIds) (params.ErrorResults, error) {
+	canAccess, err := s.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Ids)),
+	}
+	one := func(id params.StorageAttachmentId) error {
+		unitTag, err := names.ParseUnitTag(id.UnitTag)
+		if err != nil {
+			return err
+		}
+		if !canAccess(unitTag) {
+			return common.ErrPerm
+		}
+		storageTag, err := names.ParseStorageTag(id.StorageTag)
+		if err != nil {
+			return err
+		}
+		return s.storage.AcknowledgeStorageMigration(storageTag, unitTag)
+	}
+	for i, id := range args.Ids {
+		results.Results[i].Error = common.ServerError(one(id))
+	}
+	return results, nil
+}
+
 // AddUnitStorage validates and creates additional storage instances for units.
 // Failures on an individual storage instance do not block remaining
 // instances from being processed.
@@ -434,7 +468,7 @@ func accessUnitTag(tag string, canAccess func(names.Tag) bool) (names.UnitTag, e
 
 // watchStorageAttachment returns a state.NotifyWatcher that reacts to changes
 // to the VolumeAttachmentInfo or FilesystemAttachmentInfo corresponding to the
-// tags specified.
+// tags specified, and to the size of the volume or filesystem.
 func watchStorageAttachment(
 	st storageInterface,
 	stVolume storageVolumeInterface,
@@ -462,6 +496,7 @@ func watchStorageAttachment(
 		// device could change (most likely, become present).
 		watchers = []state.NotifyWatcher{
 			stVolume.WatchVolumeAttachment(hostTag, volume.VolumeTag()),
+			stVolume.WatchVolume(volume.VolumeTag()),
 		}
 
 		// TODO(caas) - we currently only support block devices on machines.
@@ -485,6 +520,7 @@ func watchStorageAttachment(
 		}
 		watchers = []state.NotifyWatcher{
 			stFile.WatchFilesystemAttachment(hostTag, filesystem.FilesystemTag()),
+			stFile.WatchFilesystem(filesystem.FilesystemTag()),
 		}
 	default:
 		return nil, errors.Errorf("invalid storage kind %v", storageInstance.Kind())
@@ -81,6 +81,10 @@ func (s *storageSuite) TestWatchStorageAttachmentVolume(c *gc.C) {
 		changes: make(chan struct{}, 1),
 	}
 	volumeWatcher.changes <- struct{}{}
+	volumeSizeWatcher := &mockNotifyWatcher{
+		changes: make(chan struct{}, 1),
+	}
+	volumeSizeWatcher.changes <- struct{}{}
 	blockDevicesWatcher := &mockNotifyWatcher{
 		changes: make(chan struct{}, 1),
 	}
@@ -110,6 +114,11 @@ func (s *storageSuite) TestWatchStorageAttachmentVolume(c *gc.C) {
 			c.Assert(v, gc.DeepEquals, volumeTag)
 			return volumeWatcher
 		},
+		watchVolume: func(v names.VolumeTag) state.NotifyWatcher {
+			calls = append(calls, "WatchVolume")
+			c.Assert(v, gc.DeepEquals, volumeTag)
+			return volumeSizeWatcher
+		},
 		watchBlockDevices: func(m names.MachineTag) state.NotifyWatcher {
 			calls = append(calls, "WatchBlockDevices")
 			c.Assert(m, gc.DeepEquals, machineTag)
@@ -135,6 +144,7 @@ func (s *storageSuite) TestWatchStorageAttachmentVolume(c *gc.C) {
 		"StorageInstance",
 		"StorageInstanceVolume",
 		"WatchVolumeAttachment",
+		"WatchVolume",
 		"WatchBlockDevices",
 		"WatchStorageAttachment",
 	})
@@ -173,6 +183,10 @@ func (s *storageSuite) assertWatchStorageAttachmentFilesystem(c *gc.C, assignedM
 		changes: make(chan struct{}, 1),
 	}
 	filesystemWatcher.changes <- struct{}{}
+	filesystemSizeWatcher := &mockNotifyWatcher{
+		changes: make(chan struct{}, 1),
+	}
+	filesystemSizeWatcher.changes <- struct{}{}
 	var calls []string
 	st := &mockStorageState{
 		assignedMachine: assignedMachine,
@@ -198,6 +212,11 @@ func (s *storageSuite) assertWatchStorageAttachmentFilesystem(c *gc.C, assignedM
 			c.Assert(f, gc.DeepEquals, filesystemTag)
 			return filesystemWatcher
 		},
+		watchFilesystem: func(f names.FilesystemTag) state.NotifyWatcher {
+			calls = append(calls, "WatchFilesystem")
+			c.Assert(f, gc.DeepEquals, filesystemTag)
+			return filesystemSizeWatcher
+		},
 	}
 
 	storage, err := uniter.NewStorageAPI(st, st, resources, getCanAccess)
@@ -218,6 +237,7 @@ func (s *storageSuite) assertWatchStorageAttachmentFilesystem(c *gc.C, assignedM
 		"StorageInstance",
 		"StorageInstanceFilesystem",
 		"WatchFilesystemAttachment",
+		"WatchFilesystem",
 		"WatchStorageAttachment",
 	})
 }
@@ -315,6 +335,60 @@ const (
 	addStorageCall = "mockAdd"
 )
 
+func (s *storageSuite) TestAcknowledgeStorageMigrations(c *gc.C) {
+	unitTag0 := names.NewUnitTag("mysql/0")
+	unitTag1 := names.NewUnitTag("mysql/1")
+	storageTag0 := names.NewStorageTag("data/0")
+	storageTag1 := names.NewStorageTag("data/1")
+
+	resources := common.NewResources()
+	getCanAccess := func() (common.AuthFunc, error) {
+		return func(tag names.Tag) bool {
+			return tag == unitTag0
+		}, nil
+	}
+
+	var acknowledged []names.StorageTag
+	st := &mockStorageState{
+		acknowledgeMigration: func(s names.StorageTag, u names.UnitTag) error {
+			c.Assert(u, gc.Equals, unitTag0)
+			acknowledged = append(acknowledged, s)
+			if s == storageTag1 {
+				return errors.New("badness")
+			}
+			return nil
+		},
+	}
+
+	storage, err := uniter.NewStorageAPI(st, st, resources, getCanAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := storage.AcknowledgeStorageMigrations(params.StorageAttachmentIds{
+		Ids: []params.StorageAttachmentId{{
+			StorageTag: storageTag0.String(),
+			UnitTag:    unitTag0.String(),
+		}, {
+			StorageTag: storageTag1.String(),
+			UnitTag:    unitTag0.String(),
+		}, {
+			StorageTag: storageTag0.String(),
+			UnitTag:    unitTag1.String(),
+		}, {
+			StorageTag: unitTag0.String(), // oops
+			UnitTag:    unitTag0.String(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{&params.Error{Message: "badness"}},
+			{&params.Error{Code: params.CodeUnauthorized, Message: "permission denied"}},
+			{&params.Error{Message: `"unit-mysql-0" is not a valid storage tag`}},
+		},
+	})
+	c.Assert(acknowledged, jc.DeepEquals, []names.StorageTag{storageTag0, storageTag1})
+}
+
 func (s *storageSuite) TestAddUnitStorageConstraintsErrors(c *gc.C) {
 	unitTag0 := names.NewUnitTag("mysql/0")
 	storageName0 := "data"
@@ -483,6 +557,7 @@ type mockStorageState struct {
 	uniter.StorageFilesystemInterface
 	destroyUnitStorageAttachments func(names.UnitTag) error
 	remove                        func(names.StorageTag, names.UnitTag, bool) error
+	acknowledgeMigration          func(names.StorageTag, names.UnitTag) error
 	storageInstance               func(names.StorageTag) (state.StorageInstance, error)
 	storageInstanceFilesystem     func(names.StorageTag) (state.Filesystem, error)
 	storageInstanceVolume         func(names.StorageTag) (state.Volume, error)
@@ -490,6 +565,8 @@ type mockStorageState struct {
 	watchStorageAttachment        func(names.StorageTag, names.UnitTag) state.NotifyWatcher
 	watchFilesystemAttachment     func(names.Tag, names.FilesystemTag) state.NotifyWatcher
 	watchVolumeAttachment         func(names.Tag, names.VolumeTag) state.NotifyWatcher
+	watchFilesystem               func(names.FilesystemTag) state.NotifyWatcher
+	watchVolume                   func(names.VolumeTag) state.NotifyWatcher
 	watchBlockDevices             func(names.MachineTag) state.NotifyWatcher
 	addUnitStorage                func(u names.UnitTag, name string, cons state.StorageConstraints) error
 }
@@ -516,6 +593,10 @@ func (m *mockStorageState) RemoveStorageAttachment(s names.StorageTag, u names.U
 	return m.remove(s, u, force)
 }
 
+func (m *mockStorageState) AcknowledgeStorageMigration(s names.StorageTag, u names.UnitTag) error {
+	return m.acknowledgeMigration(s, u)
+}
+
 func (m *mockStorageState) StorageInstance(s names.StorageTag) (state.StorageInstance, error) {
 	return m.storageInstance(s)
 }
@@ -544,6 +625,14 @@ func (m *mockStorageState) WatchVolumeAttachment(hostTag names.Tag, v names.Volu
 	return m.watchVolumeAttachment(hostTag, v)
 }
 
+func (m *mockStorageState) WatchFilesystem(f names.FilesystemTag) state.NotifyWatcher {
+	return m.watchFilesystem(f)
+}
+
+func (m *mockStorageState) WatchVolume(v names.VolumeTag) state.NotifyWatcher {
+	return m.watchVolume(v)
+}
+
 func (m *mockStorageState) WatchBlockDevices(mtag names.MachineTag) state.NotifyWatcher {
 	return m.watchBlockDevices(mtag)
 }
@@ -622,6 +711,7 @@ type watchStorageAttachmentSuite struct {
 	storageInstance          *fakeStorageInstance
 	volume                   *fakeVolume
 	volumeAttachmentWatcher  *apiservertesting.FakeNotifyWatcher
+	volumeWatcher            *apiservertesting.FakeNotifyWatcher
 	blockDevicesWatcher      *apiservertesting.FakeNotifyWatcher
 	storageAttachmentWatcher *apiservertesting.FakeNotifyWatcher
 }
@@ -639,6 +729,7 @@ func (s *watchStorageAttachmentSuite) SetUpTest(c *gc.C) {
 	}
 	s.volume = &fakeVolume{tag: names.NewVolumeTag("0")}
 	s.volumeAttachmentWatcher = apiservertesting.NewFakeNotifyWatcher()
+	s.volumeWatcher = apiservertesting.NewFakeNotifyWatcher()
 	s.blockDevicesWatcher = apiservertesting.NewFakeNotifyWatcher()
 	s.storageAttachmentWatcher = apiservertesting.NewFakeNotifyWatcher()
 	s.st = &fakeStorage{
@@ -651,6 +742,9 @@ func (s *watchStorageAttachmentSuite) SetUpTest(c *gc.C) {
 		watchVolumeAttachment: func(names.Tag, names.VolumeTag) state.NotifyWatcher {
 			return s.volumeAttachmentWatcher
 		},
+		watchVolume: func(names.VolumeTag) state.NotifyWatcher {
+			return s.volumeWatcher
+		},
 		watchBlockDevices: func(names.MachineTag) state.NotifyWatcher {
 			return s.blockDevicesWatcher
 		},
@@ -666,6 +760,12 @@ func (s *watchStorageAttachmentSuite) TestWatchStorageAttachmentVolumeAttachment
 	})
 }
 
+func (s *watchStorageAttachmentSuite) TestWatchStorageAttachmentVolumeChanges(c *gc.C) {
+	s.testWatchBlockStorageAttachment(c, func() {
+		s.volumeWatcher.C <- struct{}{}
+	})
+}
+
 func (s *watchStorageAttachmentSuite) TestWatchStorageAttachmentStorageAttachmentChanges(c *gc.C) {
 	s.testWatchBlockStorageAttachment(c, func() {
 		s.storageAttachmentWatcher.C <- struct{}{}
@@ -684,6 +784,7 @@ func (s *watchStorageAttachmentSuite) testWatchBlockStorageAttachment(c *gc.C, c
 		"StorageInstance",
 		"StorageInstanceVolume",
 		"WatchVolumeAttachment",
+		"WatchVolume",
 		"WatchBlockDevices",
 		"WatchStorageAttachment",
 	)
@@ -26,6 +26,7 @@ import (
 	"github.com/juju/juju/core/cache"
 	"github.com/juju/juju/core/leadership"
 	corenetwork "github.com/juju/juju/core/network"
+	corerelation "github.com/juju/juju/core/relation"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
@@ -35,8 +36,8 @@ import (
 
 var logger = loggo.GetLogger("juju.apiserver.uniter")
 
-// UniterAPI implements the latest version (v13) of the Uniter API,
-// which adds UpdateNetworkInfo.
+// UniterAPI implements the latest version (v14) of the Uniter API,
+// which adds AcknowledgeStorageMigrations.
 type UniterAPI struct {
 	*common.LifeGetter
 	*StatusAPI
@@ -73,13 +74,19 @@ type UniterAPI struct {
 	cloudSpec       cloudspec.CloudSpecAPI
 }
 
+// UniterAPIV13 implements version (v13) of the Uniter API,
+// which adds UpdateNetworkInfo.
+type UniterAPIV13 struct {
+	UniterAPI
+}
+
 // UniterAPIV12 implements version (v12) of the Uniter API,
 // Removes the embedded LXDProfileAPI, which in turn removes the following;
 // RemoveUpgradeCharmProfileData, WatchUnitLXDProfileUpgradeNotifications
 // and WatchLXDProfileUpgradeNotifications
 type UniterAPIV12 struct {
 	*LXDProfileAPI
-	UniterAPI
+	UniterAPIV13
 }
 
 // UniterAPIV11 implements version (v11) of the Uniter API, which adds
@@ -221,9 +228,20 @@ func NewUniterAPI(context facade.Context) (*UniterAPI, error) {
 	}, nil
 }
 
+// NewUniterAPIV13 creates an instance of the V13 uniter API.
+func NewUniterAPIV13(context facade.Context) (*UniterAPIV13, error) {
+	uniterAPI, err := NewUniterAPI(context)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV13{
+		UniterAPI: *uniterAPI,
+	}, nil
+}
+
 // NewUniterAPIV12 creates an instance of the V12 uniter API.
 func NewUniterAPIV12(context facade.Context) (*UniterAPIV12, error) {
-	uniterAPI, err := NewUniterAPI(context)
+	uniterAPI, err := NewUniterAPIV13(context)
 	if err != nil {
 		return nil, err
 	}
@@ -233,7 +251,7 @@ func NewUniterAPIV12(context facade.Context) (*UniterAPIV12, error) {
 	accessUnit := unitAccessor(authorizer, st)
 	return &UniterAPIV12{
 		LXDProfileAPI: NewExternalLXDProfileAPI(st, resources, authorizer, accessUnit, logger),
-		UniterAPI:     *uniterAPI,
+		UniterAPIV13:  *uniterAPI,
 	}, nil
 }
 
@@ -1667,11 +1685,15 @@ func (u *UniterAPI) UpdateSettings(args params.RelationUnitsSettings) (params.Er
 		if err != nil {
 			return errors.Trace(err)
 		}
-		err = u.updateApplicationSettings(rel, unit, arg.ApplicationSettings)
+		schema, err := u.relationSettingsSchema(relUnit, unit)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		err = u.updateApplicationSettings(rel, unit, arg.ApplicationSettings, schema)
 		if err != nil {
 			return errors.Trace(err)
 		}
-		err = u.updateUnitSettings(relUnit, arg.Settings)
+		err = u.updateUnitSettings(relUnit, arg.Settings, schema)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -1684,7 +1706,41 @@ func (u *UniterAPI) UpdateSettings(args params.RelationUnitsSettings) (params.Er
 	return result, nil
 }
 
-func (u *UniterAPI) updateUnitSettings(relUnit *state.RelationUnit, newSettings params.Settings) error {
+// relationSettingsSchema returns the settings schema the unit's charm
+// declares for its endpoint in the relation, which may be nil.
+func (u *UniterAPI) relationSettingsSchema(relUnit *state.RelationUnit, unit *state.Unit) (*relationSchema, error) {
+	curl, ok := unit.CharmURL()
+	if !ok {
+		// The unit has not yet set its charm URL.
+		return nil, nil
+	}
+	ch, err := u.st.Charm(curl)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	endpoint := relUnit.Endpoint()
+	schema, err := ch.RelationSchema(endpoint.Name)
+	if err != nil || schema == nil {
+		return nil, errors.Trace(err)
+	}
+	return &relationSchema{schema: schema, role: endpoint.Role}, nil
+}
+
+// relationSchema holds the schema for settings written by one side of
+// a relation.
+type relationSchema struct {
+	schema corerelation.EndpointSchema
+	role   charm.RelationRole
+}
+
+func (s *relationSchema) validate(application bool, settings map[string]interface{}) error {
+	if s == nil {
+		return nil
+	}
+	return errors.Trace(s.schema.ValidateSettings(s.role, application, settings))
+}
+
+func (u *UniterAPI) updateUnitSettings(relUnit *state.RelationUnit, newSettings params.Settings, schema *relationSchema) error {
 	if len(newSettings) == 0 {
 		return nil
 	}
@@ -1699,11 +1755,14 @@ func (u *UniterAPI) updateUnitSettings(relUnit *state.RelationUnit, newSettings
 			settings.Set(k, v)
 		}
 	}
+	if err := schema.validate(false, settings.Map()); err != nil {
+		return errors.Trace(err)
+	}
 	_, err = settings.Write()
 	return errors.Trace(err)
 }
 
-func (u *UniterAPI) updateApplicationSettings(rel *state.Relation, unit *state.Unit, settings params.Settings) error {
+func (u *UniterAPI) updateApplicationSettings(rel *state.Relation, unit *state.Unit, settings params.Settings, schema *relationSchema) error {
 	if len(settings) == 0 {
 		return nil
 	}
@@ -1716,6 +1775,22 @@ func (u *UniterAPI) updateApplicationSettings(rel *state.Relation, unit *state.U
 	for k, v := range settings {
 		settingsMap[k] = v
 	}
+	if schema != nil {
+		current, err := rel.ApplicationSettings(application)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for k, v := range settings {
+			if v == "" {
+				delete(current, k)
+			} else {
+				current[k] = v
+			}
+		}
+		if err := schema.validate(true, current); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	err = rel.UpdateApplicationSettings(application, token, settingsMap)
 	if leadership.IsNotLeaderError(err) {
 		return common.ErrPerm
@@ -3004,6 +3079,9 @@ func (u *UniterAPI) watchOneUnitHashes(tag names.UnitTag, getWatcher func(u *sta
 	return "", nil, watcher.EnsureErr(w)
 }
 
+// AcknowledgeStorageMigrations isn't on the v13 API.
+func (u *UniterAPIV13) AcknowledgeStorageMigrations(_, _ struct{}) {}
+
 // CloudAPIVersion isn't on the v10 API.
 func (u *UniterAPIV10) CloudAPIVersion(_, _ struct{}) {}
 
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/actions"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/watcher"
@@ -259,6 +260,14 @@ func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
 	response := params.ActionResults{Results: make([]params.ActionResult, len(arg.Actions))}
 	for i, action := range arg.Actions {
 		currentResult := &response.Results[i]
+		// Like juju-run, the predefined machine actions open ports
+		// and probe the network as root, so require admin access.
+		if _, ok := actions.PredefinedMachineActionsSpec[action.Name]; ok {
+			if err := a.checkCanAdmin(); err != nil {
+				currentResult.Error = common.ServerError(err)
+				continue
+			}
+		}
 		actionReceiver := action.Receiver
 		if strings.HasSuffix(actionReceiver, "leader") {
 			app := strings.Split(actionReceiver, "/")[0]
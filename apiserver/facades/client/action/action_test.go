@@ -313,6 +313,37 @@ var testCases = []testCase{{
 	},
 }}
 
+func (s *actionSuite) TestEnqueueConnectivityActionRequiresAdmin(c *gc.C) {
+	alpha := names.NewUserTag("alpha@bravo")
+	auth := apiservertesting.FakeAuthorizer{
+		Tag:         alpha,
+		HasWriteTag: alpha,
+	}
+	args := params.Actions{Actions: []params.Action{{
+		Receiver: s.machine1.Tag().String(),
+		Name:     actions.ConnectivityListenActionName,
+		Parameters: map[string]interface{}{
+			"address": "10.0.0.1",
+			"timeout": float64(time.Minute),
+		},
+	}}}
+
+	client, err := action.NewActionAPI(s.State, nil, auth)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := client.Enqueue(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, jc.Satisfies, params.IsCodeUnauthorized)
+
+	auth.AdminTag = alpha
+	client, err = action.NewActionAPI(s.State, nil, auth)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err = client.Enqueue(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+}
+
 func (s *actionSuite) TestListAll(c *gc.C) {
 	for _, testCase := range testCases {
 		// set up query args
@@ -36,8 +36,10 @@ import (
 	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/network"
+	corerelation "github.com/juju/juju/core/relation"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs"
+	jujunetwork "github.com/juju/juju/network"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/stateenvirons"
@@ -88,6 +90,19 @@ type APIv10 struct {
 // The Get call also returns the current endpoint bindings while the SetCharm
 // call access a map of operator-defined bindings.
 type APIv11 struct {
+	*APIv12
+}
+
+// APIv12 provides the Application API facade for version 12.
+// It adds UnitsInfo.
+type APIv12 struct {
+	*APIv13
+}
+
+// APIv13 provides the Application API facade for version 13.
+// Deploy and AddUnits accept storage snapshots from which to
+// restore the units' storage.
+type APIv13 struct {
 	*APIBase
 }
 
@@ -106,6 +121,7 @@ type APIBase struct {
 	modelType state.ModelType
 
 	resources facade.Resources
+	presence  common.ModelPresenceContext
 
 	// TODO(axw) stateCharm only exists because I ran out
 	// of time unwinding all of the tendrils of state. We
@@ -186,13 +202,29 @@ func NewFacadeV10(ctx facade.Context) (*APIv10, error) {
 }
 
 func NewFacadeV11(ctx facade.Context) (*APIv11, error) {
-	api, err := newFacadeBase(ctx)
+	api, err := NewFacadeV12(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return &APIv11{api}, nil
 }
 
+func NewFacadeV12(ctx facade.Context) (*APIv12, error) {
+	api, err := NewFacadeV13(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv12{api}, nil
+}
+
+func NewFacadeV13(ctx facade.Context) (*APIv13, error) {
+	api, err := newFacadeBase(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv13{api}, nil
+}
+
 type caasBrokerInterface interface {
 	ValidateStorageClass(config map[string]interface{}) error
 	Version() (*version.Number, error)
@@ -237,6 +269,7 @@ func newFacadeBase(ctx facade.Context) (*APIBase, error) {
 		storagePoolManager,
 		registry,
 		resources,
+		ctx.Presence().ModelPresence(facadeModel.UUID()),
 		caasBroker,
 	)
 }
@@ -253,6 +286,7 @@ func NewAPIBase(
 	storagePoolManager poolmanager.PoolManager,
 	registry storage.ProviderRegistry,
 	resources facade.Resources,
+	presence facade.ModelPresence,
 	caasBroker caasBrokerInterface,
 ) (*APIBase, error) {
 	if !authorizer.AuthClient() {
@@ -270,6 +304,7 @@ func NewAPIBase(
 		storagePoolManager:    storagePoolManager,
 		registry:              registry,
 		resources:             resources,
+		presence:              common.ModelPresenceContext{Presence: presence},
 		caasBroker:            caasBroker,
 	}, nil
 }
@@ -511,6 +546,11 @@ func caasPrecheck(
 			"AttachStorage may not be specified for k8s models",
 		)
 	}
+	if len(args.AttachStorageSnapshots) > 0 {
+		return errors.Errorf(
+			"AttachStorageSnapshots may not be specified for k8s models",
+		)
+	}
 	if len(args.Placement) > 1 {
 		return errors.Errorf(
 			"only 1 placement directive is supported for k8s models, got %d",
@@ -693,6 +733,7 @@ func deployApplication(
 		Storage:           args.Storage,
 		Devices:           args.Devices,
 		AttachStorage:     attachStorage,
+		StorageSnapshots:  args.AttachStorageSnapshots,
 		EndpointBindings:  bindings.Map(),
 		Resources:         args.Resources,
 	})
@@ -1327,6 +1368,12 @@ func addApplicationUnits(backend Backend, modelType state.ModelType, args params
 				modelType,
 			)
 		}
+		if len(args.AttachStorageSnapshots) > 0 {
+			return nil, errors.Errorf(
+				"AttachStorageSnapshots may not be specified for %s models",
+				modelType,
+			)
+		}
 		if len(args.Placement) > 1 {
 			return nil, errors.Errorf(
 				"only 1 placement directive is supported for %s models, got %d",
@@ -1358,6 +1405,7 @@ func addApplicationUnits(backend Backend, modelType state.ModelType, args params
 		args.NumUnits,
 		args.Placement,
 		attachStorage,
+		args.AttachStorageSnapshots,
 		assignUnits,
 	)
 }
@@ -1795,6 +1843,13 @@ func (api *APIBase) AddRelation(args params.AddRelation) (_ params.AddRelationRe
 	if err != nil {
 		return params.AddRelationResults{}, errors.Trace(err)
 	}
+	// The schemas are compared before the relation is added, so that
+	// failing to read them does not leave behind a relation which the
+	// client is told was not added.
+	warnings, err := api.relationSchemaWarnings(inEps)
+	if err != nil {
+		return params.AddRelationResults{}, errors.Trace(err)
+	}
 	if rel, err = api.backend.AddRelation(inEps...); err != nil {
 		return params.AddRelationResults{}, errors.Trace(err)
 	}
@@ -1817,7 +1872,46 @@ func (api *APIBase) AddRelation(args params.AddRelation) (_ params.AddRelationRe
 			Scope:     string(outEp.Relation.Scope),
 		}
 	}
-	return params.AddRelationResults{Endpoints: outEps}, nil
+	return params.AddRelationResults{Endpoints: outEps, Warnings: warnings}, nil
+}
+
+// relationSchemaWarnings compares the relation settings schemas declared
+// by the charms on either side of a new relation, and describes any
+// incompatibilities found. Remote applications have no local charm
+// and are not checked.
+func (api *APIBase) relationSchemaWarnings(eps []state.Endpoint) ([]string, error) {
+	if len(eps) != 2 {
+		return nil, nil
+	}
+	var schemas []corerelation.EndpointSchema
+	for _, ep := range eps {
+		app, err := api.backend.Application(ep.ApplicationName)
+		if errors.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ch, _, err := app.Charm()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		schema, err := ch.RelationSchema(ep.Name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if schema == nil {
+			return nil, nil
+		}
+		schemas = append(schemas, schema)
+	}
+	var warnings []string
+	for _, problem := range corerelation.CheckCompatibility(schemas[0], schemas[1]) {
+		warnings = append(warnings, fmt.Sprintf(
+			"%s:%s and %s:%s relation schemas are incompatible: %s",
+			eps[0].ApplicationName, eps[0].Name, eps[1].ApplicationName, eps[1].Name, problem,
+		))
+	}
+	return warnings, nil
 }
 
 // DestroyRelation removes the relation between the
@@ -2403,21 +2497,186 @@ func (api *APIBase) ApplicationsInfo(in params.Entities) (params.ApplicationInfo
 			continue
 		}
 
+		statusSummary, versions, err := api.unitsSummary(app)
+		if err != nil {
+			out[i].Error = common.ServerError(err)
+			continue
+		}
+
 		out[i].Result = &params.ApplicationResult{
-			Tag:              tag.String(),
-			Charm:            details.Charm,
-			Series:           details.Series,
-			Channel:          details.Channel,
-			Constraints:      details.Constraints,
-			Principal:        app.IsPrincipal(),
-			Exposed:          app.IsExposed(),
-			Remote:           app.IsRemote(),
-			EndpointBindings: bindingsMap,
+			Tag:               tag.String(),
+			Charm:             details.Charm,
+			Series:            details.Series,
+			Channel:           details.Channel,
+			Constraints:       details.Constraints,
+			Principal:         app.IsPrincipal(),
+			Exposed:           app.IsExposed(),
+			Remote:            app.IsRemote(),
+			EndpointBindings:  bindingsMap,
+			UnitStatusSummary: statusSummary,
+			WorkloadVersions:  versions,
 		}
 	}
 	return params.ApplicationInfoResults{out}, nil
 }
 
+// unitsSummary returns a summary of the workload status of the
+// application's units, and the units grouped by workload version if
+// they do not all report the same version. As with juju status, the
+// summary is only given when the application status is derived from
+// the unit statuses, and units whose agents are lost are counted as
+// unknown.
+func (api *APIBase) unitsSummary(app Application) (string, map[string][]string, error) {
+	derived, err := app.StatusDerived()
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	units, err := app.AllUnits()
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	statuses := make([]status.Status, len(units))
+	unitVersions := make(map[string]string, len(units))
+	for i, unit := range units {
+		_, workload := api.presence.UnitStatus(unit)
+		if workload.Err != nil {
+			return "", nil, errors.Trace(workload.Err)
+		}
+		version, err := unit.WorkloadVersion()
+		if err != nil {
+			return "", nil, errors.Trace(err)
+		}
+		statuses[i] = workload.Status.Status
+		unitVersions[unit.Name()] = version
+	}
+	var summary string
+	if derived {
+		summary = status.SummariseStatuses(statuses)
+	}
+	return summary, status.VersionSkew(unitVersions), nil
+}
+
+// UnitsInfo isn't on the v11 API.
+func (u *APIv11) UnitsInfo(_, _ struct{}) {}
+
+// UnitsInfo returns unit information, including the relation data of
+// every relation each unit has joined.
+func (api *APIBase) UnitsInfo(in params.Entities) (params.UnitInfoResults, error) {
+	if err := api.checkCanRead(); err != nil {
+		return params.UnitInfoResults{}, errors.Trace(err)
+	}
+	leaders, err := api.backend.ApplicationLeaders()
+	if err != nil {
+		return params.UnitInfoResults{}, errors.Trace(err)
+	}
+	out := make([]params.UnitInfoResult, len(in.Entities))
+	for i, one := range in.Entities {
+		tag, err := names.ParseUnitTag(one.Tag)
+		if err != nil {
+			out[i].Error = common.ServerError(err)
+			continue
+		}
+		result, err := api.unitResult(tag, leaders)
+		if err != nil {
+			out[i].Error = common.ServerError(err)
+			continue
+		}
+		out[i].Result = result
+	}
+	return params.UnitInfoResults{out}, nil
+}
+
+func (api *APIBase) unitResult(tag names.UnitTag, leaders map[string]string) (*params.UnitResult, error) {
+	unit, err := api.backend.Unit(tag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := &params.UnitResult{
+		Tag:    tag.String(),
+		Leader: leaders[unit.ApplicationName()] == unit.Name(),
+	}
+	if curl, _ := unit.CharmURL(); curl != nil {
+		result.Charm = curl.String()
+	}
+	if result.WorkloadVersion, err = unit.WorkloadVersion(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	workloadStatus, err := unit.Status()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result.WorkloadStatus = detailedStatus(workloadStatus)
+	agentStatus, err := unit.AgentStatus()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result.AgentStatus = detailedStatus(agentStatus)
+
+	if api.modelType == state.ModelTypeIAAS {
+		result.Machine, err = unit.AssignedMachineId()
+		if err != nil && !errors.IsNotAssigned(err) {
+			return nil, errors.Trace(err)
+		}
+	}
+	addr, err := unit.PublicAddress()
+	if err == nil {
+		result.PublicAddress = addr.Value
+	} else if !jujunetwork.IsNoAddressError(err) {
+		return nil, errors.Trace(err)
+	}
+	ports, err := unit.OpenedPorts()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, port := range ports {
+		result.OpenedPorts = append(result.OpenedPorts, port.String())
+	}
+
+	if result.Leader {
+		app, err := api.backend.Application(unit.ApplicationName())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result.LeaderSettings, err = app.LeaderSettings()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	relationData, err := unit.RelationData()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, data := range relationData {
+		one := params.EndpointRelationData{
+			RelationId:           data.RelationId,
+			Endpoint:             data.Endpoint,
+			CrossModel:           data.CrossModel,
+			RelatedEndpoint:      data.RelatedEndpoint,
+			ApplicationData:      data.ApplicationData,
+			UnitRelationData:     make(map[string]params.RelationData),
+			LocalApplicationData: data.LocalApplicationData,
+		}
+		for unitName, unitData := range data.UnitRelationData {
+			one.UnitRelationData[unitName] = params.RelationData{
+				InScope:  unitData.InScope,
+				UnitData: unitData.UnitData,
+			}
+		}
+		result.RelationData = append(result.RelationData, one)
+	}
+	return result, nil
+}
+
+func detailedStatus(info status.StatusInfo) params.DetailedStatus {
+	return params.DetailedStatus{
+		Status: info.Status.String(),
+		Info:   info.Message,
+		Data:   info.Data,
+		Since:  info.Since,
+	}
+}
+
 // MergeBindings merges operator-defined bindings with the current bindings for
 // one or more applications.
 func (api *APIBase) MergeBindings(in params.ApplicationMergeBindingsArgs) (params.ErrorResults, error) {
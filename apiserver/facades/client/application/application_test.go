@@ -51,7 +51,7 @@ type applicationSuite struct {
 	apiservertesting.CharmStoreSuite
 	commontesting.BlockHelper
 
-	applicationAPI *application.APIv11
+	applicationAPI *application.APIv13
 	application    *state.Application
 	authorizer     *apiservertesting.FakeAuthorizer
 }
@@ -88,7 +88,7 @@ func (s *applicationSuite) TearDownTest(c *gc.C) {
 	s.JujuConnSuite.TearDownTest(c)
 }
 
-func (s *applicationSuite) makeAPI(c *gc.C) *application.APIv11 {
+func (s *applicationSuite) makeAPI(c *gc.C) *application.APIv13 {
 	resources := common.NewResources()
 	c.Assert(resources.RegisterNamed("dataDir", common.StringResource(c.MkDir())), jc.ErrorIsNil)
 	storageAccess, err := application.GetStorageState(s.State)
@@ -109,10 +109,11 @@ func (s *applicationSuite) makeAPI(c *gc.C) *application.APIv11 {
 		pm,
 		registry,
 		common.NewResources(),
+		nil, // Agent presence is read from the units.
 		nil, // CAAS Broker not used in this suite.
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	return &application.APIv11{api}
+	return &application.APIv13{api}
 }
 
 func (s *applicationSuite) TestCharmConfig(c *gc.C) {
@@ -136,7 +137,9 @@ func (s *applicationSuite) TestCharmConfigV8(c *gc.C) {
 	api := &application.APIv8{
 		APIv9: &application.APIv9{
 			APIv10: &application.APIv10{
-				APIv11: s.applicationAPI,
+				APIv11: &application.APIv11{
+					APIv12: &application.APIv12{s.applicationAPI},
+				},
 			},
 		},
 	}
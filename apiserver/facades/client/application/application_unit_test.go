@@ -55,7 +55,7 @@ type ApplicationSuite struct {
 	env          environs.Environ
 	blockChecker mockBlockChecker
 	authorizer   apiservertesting.FakeAuthorizer
-	api          *application.APIv11
+	api          *application.APIv13
 	deployParams map[string]application.DeployApplicationParams
 }
 
@@ -82,10 +82,11 @@ func (s *ApplicationSuite) setAPIUser(c *gc.C, user names.UserTag) {
 		s.storagePoolManager,
 		s.registry,
 		common.NewResources(),
+		nil,
 		s.caasBroker,
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	s.api = &application.APIv11{api}
+	s.api = &application.APIv13{api}
 }
 
 func (s *ApplicationSuite) SetUpTest(c *gc.C) {
@@ -1018,6 +1019,22 @@ func (s *ApplicationSuite) TestAddUnitsAttachStorage(c *gc.C) {
 	})
 }
 
+func (s *ApplicationSuite) TestAddUnitsAttachStorageSnapshots(c *gc.C) {
+	_, err := s.api.AddUnits(params.AddApplicationUnits{
+		ApplicationName:        "postgresql",
+		NumUnits:               2,
+		AttachStorageSnapshots: map[string]string{"pgdata": "0/1"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	app := s.backend.applications["postgresql"]
+	expected := state.AddUnitParams{
+		StorageSnapshots: map[string]string{"pgdata": "0/1"},
+	}
+	app.CheckCall(c, 0, "AddUnit", expected)
+	app.CheckCall(c, 1, "AddUnit", expected)
+}
+
 func (s *ApplicationSuite) TestAddUnitsAttachStorageMultipleUnits(c *gc.C) {
 	_, err := s.api.AddUnits(params.AddApplicationUnits{
 		ApplicationName: "foo",
@@ -1497,6 +1514,19 @@ func (s *ApplicationSuite) TestRemoteRelationDisAllowedCIDR(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `CIDR "0.0.0.0/0" not allowed`)
 }
 
+func (s *ApplicationSuite) TestAddRelationSchemaErrorDoesNotAddRelation(c *gc.C) {
+	s.endpoints = []state.Endpoint{
+		{ApplicationName: "redis", Relation: charm.Relation{Name: "db"}},
+		{ApplicationName: "postgresql", Relation: charm.Relation{Name: "db"}},
+	}
+	s.backend.applications["redis"].charm.SetErrors(errors.New("boom"))
+	_, err := s.api.AddRelation(params.AddRelation{Endpoints: []string{"redis", "postgresql"}})
+	c.Assert(err, gc.ErrorMatches, "boom")
+	for _, call := range s.backend.Calls() {
+		c.Check(call.FuncName, gc.Not(gc.Equals), "AddRelation")
+	}
+}
+
 func (s *ApplicationSuite) TestSetApplicationConfigExplicitMaster(c *gc.C) {
 	s.testSetApplicationConfig(c, model.GenerationMaster)
 }
@@ -1724,7 +1754,38 @@ func (s *ApplicationSuite) TestApplicationsInfoOne(c *gc.C) {
 		},
 	})
 	app := s.backend.applications["postgresql"]
-	app.CheckCallNames(c, "CharmConfig", "Charm", "ApplicationConfig", "IsPrincipal", "Constraints", "EndpointBindings", "Series", "Channel", "EndpointBindings", "IsPrincipal", "IsExposed", "IsRemote")
+	app.CheckCallNames(c, "CharmConfig", "Charm", "ApplicationConfig", "IsPrincipal", "Constraints", "EndpointBindings", "Series", "Channel", "EndpointBindings", "StatusDerived", "AllUnits", "IsPrincipal", "IsExposed", "IsRemote")
+}
+
+func (s *ApplicationSuite) TestApplicationsInfoUnitStatusSummary(c *gc.C) {
+	app := s.backend.applications["postgresql"]
+	app.units[1].status = status.Maintenance
+	entities := params.Entities{[]params.Entity{{Tag: "application-postgresql"}}}
+
+	result, err := s.api.ApplicationsInfo(entities)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Result.UnitStatusSummary, gc.Equals, "")
+
+	app.derived = true
+	result, err = s.api.ApplicationsInfo(entities)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Result.UnitStatusSummary, gc.Equals, "1/2 active, 1 maintenance")
+}
+
+func (s *ApplicationSuite) TestApplicationsInfoUnitStatusSummaryAgentLost(c *gc.C) {
+	app := s.backend.applications["postgresql"]
+	app.derived = true
+	entities := params.Entities{[]params.Entity{{Tag: "application-postgresql"}}}
+
+	// Both units report an active workload, but the agent of one of
+	// them is lost, so juju status shows its workload as unknown.
+	app.units[1].agentLost = true
+	result, err := s.api.ApplicationsInfo(entities)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Result.UnitStatusSummary, gc.Equals, "1/2 active, 1 unknown")
 }
 
 func (s *ApplicationSuite) TestApplicationsInfoDetailsErr(c *gc.C) {
@@ -1775,7 +1836,44 @@ func (s *ApplicationSuite) TestApplicationsInfoMany(c *gc.C) {
 	c.Assert(result.Results[1].Error, gc.ErrorMatches, `application "wordpress" not found`)
 	c.Assert(result.Results[2].Error, gc.ErrorMatches, `"unit-postgresql-0" is not a valid application tag`)
 	app := s.backend.applications["postgresql"]
-	app.CheckCallNames(c, "CharmConfig", "Charm", "ApplicationConfig", "IsPrincipal", "Constraints", "EndpointBindings", "Series", "Channel", "EndpointBindings", "IsPrincipal", "IsExposed", "IsRemote")
+	app.CheckCallNames(c, "CharmConfig", "Charm", "ApplicationConfig", "IsPrincipal", "Constraints", "EndpointBindings", "Series", "Channel", "EndpointBindings", "StatusDerived", "AllUnits", "IsPrincipal", "IsExposed", "IsRemote")
+}
+
+func (s *ApplicationSuite) TestUnitsInfo(c *gc.C) {
+	entities := []params.Entity{{Tag: "unit-postgresql-0"}, {Tag: "unit-mysql-0"}, {Tag: "application-postgresql"}}
+	result, err := s.api.UnitsInfo(params.Entities{entities})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, len(entities))
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(*result.Results[0].Result, jc.DeepEquals, params.UnitResult{
+		Tag:             "unit-postgresql-0",
+		WorkloadVersion: "666",
+		WorkloadStatus:  params.DetailedStatus{Status: "active", Info: "ready"},
+		AgentStatus:     params.DetailedStatus{Status: "idle"},
+		Machine:         "machine-0",
+		OpenedPorts:     []string{"100-102/tcp"},
+		PublicAddress:   "10.0.0.1",
+		Charm:           "cs:postgresql-42",
+		Leader:          true,
+		LeaderSettings:  map[string]string{"password": "secret"},
+		RelationData: []params.EndpointRelationData{{
+			RelationId:           101,
+			Endpoint:             "db",
+			RelatedEndpoint:      "server",
+			ApplicationData:      map[string]interface{}{"foo": "bar"},
+			LocalApplicationData: map[string]interface{}{"baz": "qux"},
+			UnitRelationData: map[string]params.RelationData{
+				"mysql/0": {
+					InScope:  true,
+					UnitData: map[string]interface{}{"mysql": "data"},
+				},
+			},
+		}},
+	})
+	c.Assert(*result.Results[1].Error, gc.ErrorMatches, `unit "mysql/0" not found`)
+	c.Assert(*result.Results[2].Error, gc.ErrorMatches, `"application-postgresql" is not a valid unit tag`)
+	app := s.backend.applications["postgresql"]
+	app.CheckCallNames(c, "LeaderSettings")
 }
 
 func (s *ApplicationSuite) TestApplicationMergeBindingsErr(c *gc.C) {
@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/network"
+	corerelation "github.com/juju/juju/core/relation"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
@@ -31,6 +32,7 @@ import (
 type Backend interface {
 	AllModelUUIDs() ([]string, error)
 	Application(string) (Application, error)
+	ApplicationLeaders() (map[string]string, error)
 	ApplyOperation(state.ModelOperation) error
 	AddApplication(state.AddApplicationArgs) (Application, error)
 	RemoteApplication(string) (RemoteApplication, error)
@@ -81,12 +83,14 @@ type Application interface {
 	IsExposed() bool
 	IsPrincipal() bool
 	IsRemote() bool
+	LeaderSettings() (map[string]string, error)
 	Series() string
 	SetCharm(state.SetCharmConfig) error
 	SetConstraints(constraints.Value) error
 	SetExposed() error
 	SetMetricCredentials([]byte) error
 	SetMinUnits(int) error
+	StatusDerived() (bool, error)
 	UpdateApplicationSeries(string, bool) error
 	UpdateCharmConfig(string, charm.Settings) error
 	UpdateApplicationConfig(application.ConfigAttributes, []string, environschema.Fields, schema.Defaults) error
@@ -111,6 +115,7 @@ type Bindings interface {
 // the same names.
 type Charm interface {
 	charm.Charm
+	RelationSchema(string) (corerelation.EndpointSchema, error)
 }
 
 // Machine defines a subset of the functionality provided by the
@@ -145,6 +150,7 @@ type Unit interface {
 	Name() string
 	Tag() names.Tag
 	UnitTag() names.UnitTag
+	ApplicationName() string
 	Destroy() error
 	DestroyOperation() *state.DestroyUnitOperation
 	IsPrincipal() bool
@@ -152,6 +158,16 @@ type Unit interface {
 	Resolve(retryHooks bool) error
 	AgentTools() (*tools.Tools, error)
 
+	CharmURL() (*charm.URL, bool)
+	WorkloadVersion() (string, error)
+	Status() (status.StatusInfo, error)
+	AgentStatus() (status.StatusInfo, error)
+	AgentPresence() (bool, error)
+	ShouldBeAssigned() bool
+	PublicAddress() (network.SpaceAddress, error)
+	OpenedPorts() ([]network.PortRange, error)
+	RelationData() ([]state.EndpointRelationData, error)
+
 	AssignedMachineId() (string, error)
 	AssignWithPolicy(state.AssignmentPolicy) error
 	AssignWithPlacement(*instance.Placement) error
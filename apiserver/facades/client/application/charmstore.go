@@ -4,10 +4,13 @@
 package application
 
 import (
+	"archive/zip"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/juju/errors"
@@ -23,6 +26,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/lxdprofile"
+	corerelation "github.com/juju/juju/core/relation"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/storage"
@@ -167,6 +171,10 @@ func AddCharmWithAuthorizationAndRepo(st State, args params.AddCharmWithAuthoriz
 	if _, err := archive.Seek(0, 0); err != nil {
 		return errors.Annotate(err, "cannot rewind charm archive")
 	}
+	relationSchemas, err := ReadRelationSchemas(downloadedBundle)
+	if err != nil {
+		return errors.Annotate(err, "cannot add charm")
+	}
 
 	ca := CharmArchive{
 		ID:           charmURL,
@@ -175,6 +183,8 @@ func AddCharmWithAuthorizationAndRepo(st State, args params.AddCharmWithAuthoriz
 		Size:         size,
 		SHA256:       bundleSHA256,
 		CharmVersion: downloadedBundle.Version(),
+
+		RelationSchemas: relationSchemas,
 	}
 	if args.CharmStoreMacaroon != nil {
 		ca.Macaroon = macaroon.Slice{args.CharmStoreMacaroon}
@@ -314,6 +324,10 @@ type CharmArchive struct {
 
 	// Charm Version contains semantic version of charm, typically the output of git describe.
 	CharmVersion string
+
+	// RelationSchemas holds the serialised relation settings schemas
+	// declared in the charm's metadata, keyed by endpoint name.
+	RelationSchemas map[string]string
 }
 
 // StoreCharmArchive stores a charm archive in environment storage.
@@ -334,6 +348,8 @@ func StoreCharmArchive(st State, archive CharmArchive) error {
 		SHA256:      archive.SHA256,
 		Macaroon:    archive.Macaroon,
 		Version:     archive.CharmVersion,
+
+		RelationSchemas: archive.RelationSchemas,
 	}
 
 	// Now update the charm data in state and mark it as no longer pending.
@@ -470,6 +486,52 @@ func (s csStateModelShim) ModelConfig() (*config.Config, error) {
 	return s.Model.ModelConfig()
 }
 
+// ReadRelationSchemas reads the relation settings schemas declared in
+// the metadata.yaml of the supplied charm archive or directory, and
+// returns them serialised for storage.
+func ReadRelationSchemas(ch charm.Charm) (map[string]string, error) {
+	var (
+		metadata []byte
+		err      error
+	)
+	switch ch := ch.(type) {
+	case *charm.CharmDir:
+		metadata, err = ioutil.ReadFile(filepath.Join(ch.Path, "metadata.yaml"))
+	case *charm.CharmArchive:
+		metadata, err = readArchiveFile(ch.Path, "metadata.yaml")
+	default:
+		return nil, errors.NotSupportedf("reading metadata from %T", ch)
+	}
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read charm metadata")
+	}
+	schemas, err := corerelation.ParseSettingsSchemas(metadata)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return corerelation.MarshalSettingsSchemas(schemas)
+}
+
+func readArchiveFile(archivePath, name string) ([]byte, error) {
+	zipr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer zipr.Close()
+	for _, f := range zipr.File {
+		if f.Name != name {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		defer r.Close()
+		return ioutil.ReadAll(r)
+	}
+	return nil, errors.NotFoundf("%q in charm archive", name)
+}
+
 // lxdCharmArchiveProfiler massages a *charm.CharmArchive into a LXDProfiler
 // inside of the core package.
 type lxdCharmArchiveProfiler struct {
@@ -41,6 +41,9 @@ type DeployApplicationParams struct {
 	EndpointBindings map[string]string
 	// Resources is a map of resource name to IDs of pending resources.
 	Resources map[string]string
+	// StorageSnapshots maps charm storage names to the IDs of
+	// storage snapshots from which the units' storage is restored.
+	StorageSnapshots map[string]string
 }
 
 type ApplicationDeployer interface {
@@ -76,6 +79,7 @@ func DeployApplication(st ApplicationDeployer, args DeployApplicationParams) (Ap
 		Storage:           stateStorageConstraints(args.Storage),
 		Devices:           stateDeviceConstraints(args.Devices),
 		AttachStorage:     args.AttachStorage,
+		StorageSnapshots:  args.StorageSnapshots,
 		ApplicationConfig: args.ApplicationConfig,
 		CharmConfig:       charmConfig,
 		NumUnits:          args.NumUnits,
@@ -106,6 +110,7 @@ func addUnits(
 	n int,
 	placement []*instance.Placement,
 	attachStorage []names.StorageTag,
+	storageSnapshots map[string]string,
 	assignUnits bool,
 ) ([]Unit, error) {
 	units := make([]Unit, n)
@@ -114,7 +119,8 @@ func addUnits(
 	// TODO what do we do if we fail half-way through this process?
 	for i := 0; i < n; i++ {
 		unit, err := unitAdder.AddUnit(state.AddUnitParams{
-			AttachStorage: attachStorage,
+			AttachStorage:    attachStorage,
+			StorageSnapshots: storageSnapshots,
 		})
 		if err != nil {
 			return nil, errors.Annotatef(err, "cannot add unit %d/%d to application %q", i+1, n, appName)
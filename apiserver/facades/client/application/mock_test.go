@@ -29,6 +29,7 @@ import (
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/network"
+	corerelation "github.com/juju/juju/core/relation"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
@@ -82,6 +83,11 @@ func (c *mockCharm) LXDProfile() *charm.LXDProfile {
 	return c.lxdProfile
 }
 
+func (c *mockCharm) RelationSchema(endpoint string) (corerelation.EndpointSchema, error) {
+	c.MethodCall(c, "RelationSchema", endpoint)
+	return nil, c.NextErr()
+}
+
 type mockApplication struct {
 	jtesting.Stub
 	application.Application
@@ -101,6 +107,7 @@ type mockApplication struct {
 	channel     csparams.Channel
 	exposed     bool
 	remote      bool
+	derived     bool
 	agentTools  *tools.Tools
 }
 
@@ -134,6 +141,11 @@ func (m *mockApplication) Constraints() (constraints.Value, error) {
 	return m.constraints, nil
 }
 
+func (m *mockApplication) LeaderSettings() (map[string]string, error) {
+	m.MethodCall(m, "LeaderSettings")
+	return map[string]string{"password": "secret"}, m.NextErr()
+}
+
 func (m *mockApplication) Endpoints() ([]state.Endpoint, error) {
 	m.MethodCall(m, "Endpoints")
 	return m.endpoints, nil
@@ -156,6 +168,11 @@ func (a *mockApplication) AllUnits() ([]application.Unit, error) {
 	return units, nil
 }
 
+func (a *mockApplication) StatusDerived() (bool, error) {
+	a.MethodCall(a, "StatusDerived")
+	return a.derived, a.NextErr()
+}
+
 func (a *mockApplication) SetCharm(cfg state.SetCharmConfig) error {
 	a.MethodCall(a, "SetCharm", cfg)
 	return a.NextErr()
@@ -395,6 +412,11 @@ func (m *mockBackend) Charm(curl *charm.URL) (application.Charm, error) {
 	return nil, errors.NotFoundf("charm %q", curl)
 }
 
+func (m *mockBackend) ApplicationLeaders() (map[string]string, error) {
+	m.MethodCall(m, "ApplicationLeaders")
+	return map[string]string{"postgresql": "postgresql/0"}, m.NextErr()
+}
+
 func (m *mockBackend) Unit(name string) (application.Unit, error) {
 	m.MethodCall(m, "Unit", name)
 	if err := m.NextErr(); err != nil {
@@ -770,6 +792,8 @@ type mockUnit struct {
 	machineId  string
 	name       string
 	agentTools *tools.Tools
+	status     status.Status
+	agentLost  bool
 }
 
 func (u *mockUnit) Tag() names.Tag {
@@ -821,6 +845,77 @@ func (u *mockUnit) AgentTools() (*tools.Tools, error) {
 	return u.agentTools, u.NextErr()
 }
 
+func (u *mockUnit) ApplicationName() string {
+	u.MethodCall(u, "ApplicationName")
+	appName, _ := names.UnitApplication(u.tag.Id())
+	return appName
+}
+
+func (u *mockUnit) CharmURL() (*charm.URL, bool) {
+	u.MethodCall(u, "CharmURL")
+	return charm.MustParseURL("cs:postgresql-42"), false
+}
+
+func (u *mockUnit) WorkloadVersion() (string, error) {
+	u.MethodCall(u, "WorkloadVersion")
+	return "666", u.NextErr()
+}
+
+func (u *mockUnit) Status() (status.StatusInfo, error) {
+	u.MethodCall(u, "Status")
+	if u.status != "" {
+		return status.StatusInfo{Status: u.status}, u.NextErr()
+	}
+	return status.StatusInfo{Status: status.Active, Message: "ready"}, u.NextErr()
+}
+
+func (u *mockUnit) AgentStatus() (status.StatusInfo, error) {
+	u.MethodCall(u, "AgentStatus")
+	return status.StatusInfo{Status: status.Idle}, u.NextErr()
+}
+
+func (u *mockUnit) AgentPresence() (bool, error) {
+	u.MethodCall(u, "AgentPresence")
+	return !u.agentLost, u.NextErr()
+}
+
+func (u *mockUnit) ShouldBeAssigned() bool {
+	u.MethodCall(u, "ShouldBeAssigned")
+	return true
+}
+
+func (u *mockUnit) Life() state.Life {
+	u.MethodCall(u, "Life")
+	return state.Alive
+}
+
+func (u *mockUnit) PublicAddress() (network.SpaceAddress, error) {
+	u.MethodCall(u, "PublicAddress")
+	return network.NewScopedSpaceAddress("10.0.0.1", network.ScopePublic), u.NextErr()
+}
+
+func (u *mockUnit) OpenedPorts() ([]network.PortRange, error) {
+	u.MethodCall(u, "OpenedPorts")
+	return []network.PortRange{network.MustParsePortRange("100-102/tcp")}, u.NextErr()
+}
+
+func (u *mockUnit) RelationData() ([]state.EndpointRelationData, error) {
+	u.MethodCall(u, "RelationData")
+	return []state.EndpointRelationData{{
+		RelationId:           101,
+		Endpoint:             "db",
+		RelatedEndpoint:      "server",
+		ApplicationData:      map[string]interface{}{"foo": "bar"},
+		LocalApplicationData: map[string]interface{}{"baz": "qux"},
+		UnitRelationData: map[string]state.RelationData{
+			"mysql/0": {
+				InScope:  true,
+				UnitData: map[string]interface{}{"mysql": "data"},
+			},
+		},
+	}}, u.NextErr()
+}
+
 type mockStorageAttachment struct {
 	state.StorageAttachment
 	jtesting.Stub
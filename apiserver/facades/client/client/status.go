@@ -1162,6 +1162,15 @@ func (context *statusContext) processApplication(application *state.Application)
 	processedStatus.Status.Data = applicationStatus.Data
 	processedStatus.Status.Since = applicationStatus.Since
 
+	derived, err := context.status.ApplicationStatusDerived(application.Name())
+	if err != nil {
+		processedStatus.Err = common.ServerError(err)
+		return processedStatus
+	}
+	if derived {
+		processedStatus.UnitStatusSummary = unitStatusSummary(processedStatus.Units)
+	}
+
 	metrics := applicationCharm.Metrics()
 	planRequired := metrics != nil && metrics.Plan != nil && metrics.Plan.Required
 	if planRequired || len(application.MetricCredentials()) > 0 {
@@ -1171,6 +1180,7 @@ func (context *statusContext) processApplication(application *state.Application)
 	// TODO(caas) - there's no way for a CAAS charm to set workload version yet
 	if context.model.Type() == state.ModelTypeIAAS {
 		versions := make([]status.StatusInfo, 0, len(units))
+		unitVersions := make(map[string]string, len(units))
 		for _, unit := range units {
 			workloadVersion, err := context.status.FullUnitWorkloadVersion(unit.Name())
 			if err != nil {
@@ -1178,11 +1188,13 @@ func (context *statusContext) processApplication(application *state.Application)
 				return processedStatus
 			}
 			versions = append(versions, workloadVersion)
+			unitVersions[unit.Name()] = workloadVersion.Message
 		}
 		if len(versions) > 0 {
 			sort.Sort(bySinceDescending(versions))
 			processedStatus.WorkloadVersion = versions[0].Message
 		}
+		processedStatus.WorkloadVersions = status.VersionSkew(unitVersions)
 	} else {
 		// We'll punt on using the docker image name.
 		caasModel, err := context.model.CAASModel()
@@ -1391,6 +1403,16 @@ func (context *statusContext) unitByName(name string) *state.Unit {
 	return context.allAppsUnitsCharmBindings.units[applicationName][name]
 }
 
+// unitStatusSummary summarises the workload status of the given units,
+// returning an empty string if they all share the same status.
+func unitStatusSummary(units map[string]params.UnitStatus) string {
+	statuses := make([]status.Status, 0, len(units))
+	for _, unit := range units {
+		statuses = append(statuses, status.Status(unit.WorkloadStatus.Status))
+	}
+	return status.SummariseStatuses(statuses)
+}
+
 func (context *statusContext) processApplicationRelations(application *state.Application) (related map[string][]string, subord []string, err error) {
 	subordSet := make(set.Strings)
 	related = make(map[string][]string)
@@ -321,6 +321,52 @@ func (s *statusUnitTestSuite) TestWorkloadVersionLastWins(c *gc.C) {
 	checkUnitVersion(c, appStatus, unit3, "zarkon")
 }
 
+func (s *statusUnitTestSuite) TestWorkloadVersionSkew(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	addUnitWithVersion(c, application, "voltron")
+	addUnitWithVersion(c, application, "voltron")
+	addUnitWithVersion(c, application, "zarkon")
+	addUnitWithVersion(c, application, "")
+
+	appStatus := s.checkAppVersion(c, application, "")
+	c.Check(appStatus.WorkloadVersions, jc.DeepEquals, map[string][]string{
+		"voltron": {application.Name() + "/0", application.Name() + "/1"},
+		"zarkon":  {application.Name() + "/2"},
+	})
+}
+
+func (s *statusUnitTestSuite) TestWorkloadVersionNoSkew(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	addUnitWithVersion(c, application, "voltron")
+	addUnitWithVersion(c, application, "voltron")
+
+	appStatus := s.checkAppVersion(c, application, "voltron")
+	c.Check(appStatus.WorkloadVersions, gc.IsNil)
+}
+
+func (s *statusUnitTestSuite) TestUnitStatusSummary(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	for _, st := range []status.Status{status.Active, status.Maintenance, status.Active} {
+		unit, err := application.AddUnit(state.AddUnitParams{})
+		c.Assert(err, jc.ErrorIsNil)
+		now := time.Now()
+		err = unit.SetStatus(status.StatusInfo{Status: st, Since: &now})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	appStatus := s.checkAppVersion(c, application, "")
+	c.Check(appStatus.Status.Status, gc.Equals, "maintenance")
+	c.Check(appStatus.UnitStatusSummary, gc.Equals, "2/3 active, 1 maintenance")
+
+	// Once the leader sets the application status, it is no longer
+	// derived from the units and no summary is given.
+	now := time.Now()
+	err := application.SetStatus(status.StatusInfo{Status: status.Active, Since: &now})
+	c.Assert(err, jc.ErrorIsNil)
+	appStatus = s.checkAppVersion(c, application, "")
+	c.Check(appStatus.UnitStatusSummary, gc.Equals, "")
+}
+
 func (s *statusUnitTestSuite) TestWorkloadVersionSimple(c *gc.C) {
 	application := s.Factory.MakeApplication(c, nil)
 	unit1 := addUnitWithVersion(c, application, "voltron")
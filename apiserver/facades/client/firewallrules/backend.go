@@ -16,7 +16,10 @@ import (
 type Backend interface {
 	ModelTag() names.ModelTag
 	SaveFirewallRule(state.FirewallRule) error
+	RemoveFirewallRule(name string) error
 	ListFirewallRules() ([]*state.FirewallRule, error)
+	SetEgressRules(application string, rules []state.EgressRule) error
+	AllEgressRules() (map[string][]state.EgressRule, error)
 }
 
 // BlockChecker defines the block-checking functionality required by
@@ -24,6 +27,7 @@ type Backend interface {
 // apiserver/common.BlockChecker.
 type BlockChecker interface {
 	ChangeAllowed() error
+	RemoveAllowed() error
 }
 
 // TODO - CAAS(ericclaudejones): This should contain state alone, model will be
@@ -53,7 +57,20 @@ func (s stateShim) SaveFirewallRule(rule state.FirewallRule) error {
 	return api.Save(rule)
 }
 
+func (s stateShim) RemoveFirewallRule(name string) error {
+	api := state.NewFirewallRules(s.State)
+	return api.RemoveIngressRule(name)
+}
+
 func (s stateShim) ListFirewallRules() ([]*state.FirewallRule, error) {
 	api := state.NewFirewallRules(s.State)
 	return api.AllRules()
 }
+
+func (s stateShim) SetEgressRules(application string, rules []state.EgressRule) error {
+	app, err := s.State.Application(application)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return app.SetEgressRules(rules)
+}
@@ -4,6 +4,8 @@
 package firewallrules
 
 import (
+	"sort"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"gopkg.in/juju/names.v3"
@@ -17,13 +19,45 @@ import (
 
 var logger = loggo.GetLogger("juju.apiserver.firewallrules")
 
-// API provides the firewallrules facade APIs for v1.
+// API provides the firewallrules facade APIs for v3.
 type API struct {
 	backend    Backend
 	authorizer facade.Authorizer
 	check      BlockChecker
 }
 
+// APIV2 provides the firewallrules facade APIs for v2,
+// which doesn't support egress rules.
+type APIV2 struct {
+	*API
+}
+
+// APIV1 provides the firewallrules facade APIs for v1,
+// which only supports rules for well known services.
+type APIV1 struct {
+	*APIV2
+}
+
+// NewFacadeV1 provides the signature required for facade registration
+// of the v1 facade.
+func NewFacadeV1(ctx facade.Context) (*APIV1, error) {
+	api, err := NewFacadeV2(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIV1{api}, nil
+}
+
+// NewFacadeV2 provides the signature required for facade registration
+// of the v2 facade.
+func NewFacadeV2(ctx facade.Context) (*APIV2, error) {
+	api, err := NewFacade(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIV2{api}, nil
+}
+
 // NewFacade provides the signature required for facade registration.
 func NewFacade(ctx facade.Context) (*API, error) {
 	backend, err := NewStateBackend(ctx.State())
@@ -74,6 +108,8 @@ func (api *API) checkCanRead() error {
 }
 
 // SetFirewallRules creates or updates the specified firewall rules.
+// A rule with a name rather than a well known service is a model
+// ingress rule.
 func (api *API) SetFirewallRules(args params.FirewallRuleArgs) (params.ErrorResults, error) {
 	var errResults params.ErrorResults
 	if err := api.checkAdmin(); err != nil {
@@ -86,16 +122,50 @@ func (api *API) SetFirewallRules(args params.FirewallRuleArgs) (params.ErrorResu
 	results := make([]params.ErrorResult, len(args.Args))
 	for i, arg := range args.Args {
 		logger.Debugf("saving firewall rule %+v", arg)
-		err := api.backend.SaveFirewallRule(state.FirewallRule{
+		rule := state.FirewallRule{
 			WellKnownService: state.WellKnownServiceType(arg.KnownService),
 			WhitelistCIDRs:   arg.WhitelistCIDRS,
-		})
+			Name:             arg.Name,
+			Application:      arg.Application,
+		}
+		if arg.Name != "" {
+			if arg.PortRange == nil {
+				results[i].Error = common.ServerError(errors.NotValidf("firewall rule %q without port range", arg.Name))
+				continue
+			}
+			rule.PortRange = arg.PortRange.NetworkPortRange()
+		}
+		err := api.backend.SaveFirewallRule(rule)
 		results[i].Error = common.ServerError(err)
 	}
 	errResults.Results = results
 	return errResults, nil
 }
 
+// RemoveFirewallRules removes the model ingress rules with the
+// specified names.
+func (api *API) RemoveFirewallRules(args params.FirewallRuleNames) (params.ErrorResults, error) {
+	var errResults params.ErrorResults
+	if err := api.checkAdmin(); err != nil {
+		return errResults, errors.Trace(err)
+	}
+	if err := api.check.RemoveAllowed(); err != nil {
+		return errResults, errors.Trace(err)
+	}
+
+	results := make([]params.ErrorResult, len(args.Names))
+	for i, name := range args.Names {
+		logger.Debugf("removing firewall rule %q", name)
+		err := api.backend.RemoveFirewallRule(name)
+		results[i].Error = common.ServerError(err)
+	}
+	errResults.Results = results
+	return errResults, nil
+}
+
+// RemoveFirewallRules isn't on the v1 API.
+func (*APIV1) RemoveFirewallRules(_, _ struct{}) {}
+
 // ListFirewallRules returns all the firewall rules.
 func (api *API) ListFirewallRules() (params.ListFirewallRulesResults, error) {
 	var listResults params.ListFirewallRulesResults
@@ -112,6 +182,97 @@ func (api *API) ListFirewallRules() (params.ListFirewallRulesResults, error) {
 			KnownService:   params.KnownServiceValue(r.WellKnownService),
 			WhitelistCIDRS: r.WhitelistCIDRs,
 		}
+		if r.IsIngressRule() {
+			portRange := params.FromNetworkPortRange(r.PortRange)
+			listResults.Rules[i].Name = r.Name
+			listResults.Rules[i].PortRange = &portRange
+			listResults.Rules[i].Application = r.Application
+		}
+	}
+	return listResults, nil
+}
+
+// ListFirewallRules returns the firewall rules for well known services,
+// which are the only rules v1 clients understand.
+func (api *APIV1) ListFirewallRules() (params.ListFirewallRulesResults, error) {
+	listResults, err := api.API.ListFirewallRules()
+	if err != nil {
+		return listResults, errors.Trace(err)
+	}
+	var rules []params.FirewallRule
+	for _, r := range listResults.Rules {
+		if r.Name == "" {
+			rules = append(rules, r)
+		}
 	}
+	listResults.Rules = rules
 	return listResults, nil
 }
+
+// SetEgressRules replaces the egress rules of the specified
+// applications. Setting no rules for an application lifts its
+// egress restriction.
+func (api *API) SetEgressRules(args params.SetEgressRulesArgs) (params.ErrorResults, error) {
+	var errResults params.ErrorResults
+	if err := api.checkAdmin(); err != nil {
+		return errResults, errors.Trace(err)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return errResults, errors.Trace(err)
+	}
+
+	results := make([]params.ErrorResult, len(args.Args))
+	for i, arg := range args.Args {
+		logger.Debugf("setting egress rules %+v", arg)
+		rules := make([]state.EgressRule, len(arg.Rules))
+		for j, rule := range arg.Rules {
+			rules[j] = state.EgressRule{
+				DestinationCIDR: rule.DestinationCIDR,
+				PortRange:       rule.PortRange.NetworkPortRange(),
+			}
+		}
+		err := api.backend.SetEgressRules(arg.Application, rules)
+		results[i].Error = common.ServerError(err)
+	}
+	errResults.Results = results
+	return errResults, nil
+}
+
+// ListEgressRules returns the egress rules of the applications
+// with restricted egress, sorted by application name.
+func (api *API) ListEgressRules() (params.ListEgressRulesResults, error) {
+	var listResults params.ListEgressRulesResults
+	if err := api.checkCanRead(); err != nil {
+		return listResults, errors.Trace(err)
+	}
+	all, err := api.backend.AllEgressRules()
+	if err != nil {
+		return listResults, errors.Trace(err)
+	}
+	appNames := make([]string, 0, len(all))
+	for appName := range all {
+		appNames = append(appNames, appName)
+	}
+	sort.Strings(appNames)
+	listResults.Results = make([]params.ApplicationEgressRules, len(appNames))
+	for i, appName := range appNames {
+		rules := make([]params.EgressRule, len(all[appName]))
+		for j, rule := range all[appName] {
+			rules[j] = params.EgressRule{
+				DestinationCIDR: rule.DestinationCIDR,
+				PortRange:       params.FromNetworkPortRange(rule.PortRange),
+			}
+		}
+		listResults.Results[i] = params.ApplicationEgressRules{
+			Application: appName,
+			Rules:       rules,
+		}
+	}
+	return listResults, nil
+}
+
+// SetEgressRules isn't on the v2 API.
+func (*APIV2) SetEgressRules(_, _ struct{}) {}
+
+// ListEgressRules isn't on the v2 API.
+func (*APIV2) ListEgressRules(_, _ struct{}) {}
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/firewallrules"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 )
@@ -47,8 +48,9 @@ func (s *FirewallRulesSuite) SetUpTest(c *gc.C) {
 		Tag: names.NewUserTag("admin"),
 	}
 	s.backend = mockBackend{
-		modelUUID: coretesting.ModelTag.Id(),
-		rules:     make(map[string]state.FirewallRule),
+		modelUUID:   coretesting.ModelTag.Id(),
+		rules:       make(map[string]state.FirewallRule),
+		egressRules: make(map[string][]state.EgressRule),
 	}
 	s.blockChecker = mockBlockChecker{}
 	api, err := firewallrules.NewAPI(
@@ -120,3 +122,169 @@ func (s *FirewallRulesSuite) TestListFirewallRulesPermission(c *gc.C) {
 	_, err := s.api.ListFirewallRules()
 	c.Assert(err, gc.ErrorMatches, ".*permission denied.*")
 }
+
+func (s *FirewallRulesSuite) TestSetFirewallRulesIngressRule(c *gc.C) {
+	result, err := s.api.SetFirewallRules(params.FirewallRuleArgs{
+		Args: []params.FirewallRule{{
+			Name:           "monitoring",
+			PortRange:      &params.PortRange{FromPort: 9100, ToPort: 9110, Protocol: "tcp"},
+			WhitelistCIDRS: []string{"10.0.0.0/8"},
+			Application:    "mysql",
+		}, {
+			Name: "no-ports",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `firewall rule "no-ports" without port range not valid`)
+	c.Assert(s.backend.rules, jc.DeepEquals, map[string]state.FirewallRule{
+		"monitoring": {
+			Name:           "monitoring",
+			PortRange:      network.PortRange{FromPort: 9100, ToPort: 9110, Protocol: "tcp"},
+			WhitelistCIDRs: []string{"10.0.0.0/8"},
+			Application:    "mysql",
+		},
+	})
+}
+
+func (s *FirewallRulesSuite) TestRemoveFirewallRules(c *gc.C) {
+	s.backend.rules["web"] = state.FirewallRule{Name: "web"}
+	s.backend.SetErrors(nil, nil, errors.NotFoundf(`firewall rule "other"`))
+	result, err := s.api.RemoveFirewallRules(params.FirewallRuleNames{
+		Names: []string{"web", "other"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+	c.Assert(s.backend.rules, gc.HasLen, 0)
+	s.blockChecker.CheckCallNames(c, "RemoveAllowed")
+}
+
+func (s *FirewallRulesSuite) TestRemoveFirewallRulesPermission(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("mary"))
+	_, err := s.api.RemoveFirewallRules(params.FirewallRuleNames{Names: []string{"web"}})
+	c.Assert(err, gc.ErrorMatches, ".*permission denied.*")
+}
+
+func (s *FirewallRulesSuite) TestRemoveFirewallRulesBlocked(c *gc.C) {
+	s.blockChecker.SetErrors(errors.New("blocked"))
+	_, err := s.api.RemoveFirewallRules(params.FirewallRuleNames{Names: []string{"web"}})
+	c.Assert(err, gc.ErrorMatches, "blocked")
+}
+
+func (s *FirewallRulesSuite) TestListFirewallRulesIngressRules(c *gc.C) {
+	s.backend.ingressRules = []*state.FirewallRule{{
+		Name:           "monitoring",
+		PortRange:      network.PortRange{FromPort: 9100, ToPort: 9110, Protocol: "tcp"},
+		WhitelistCIDRs: []string{"10.0.0.0/8"},
+		Application:    "mysql",
+	}}
+	result, err := s.api.ListFirewallRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ListFirewallRulesResults{
+		Rules: []params.FirewallRule{{
+			KnownService:   params.JujuApplicationOfferRule,
+			WhitelistCIDRS: []string{"1.2.3.4/8"},
+		}, {
+			Name:           "monitoring",
+			PortRange:      &params.PortRange{FromPort: 9100, ToPort: 9110, Protocol: "tcp"},
+			WhitelistCIDRS: []string{"10.0.0.0/8"},
+			Application:    "mysql",
+		}}})
+}
+
+func (s *FirewallRulesSuite) TestListFirewallRulesV1(c *gc.C) {
+	s.backend.ingressRules = []*state.FirewallRule{{
+		Name:      "monitoring",
+		PortRange: network.PortRange{FromPort: 9100, ToPort: 9110, Protocol: "tcp"},
+	}}
+	apiV1 := &firewallrules.APIV1{APIV2: &firewallrules.APIV2{API: s.api}}
+	result, err := apiV1.ListFirewallRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ListFirewallRulesResults{
+		Rules: []params.FirewallRule{{
+			KnownService:   params.JujuApplicationOfferRule,
+			WhitelistCIDRS: []string{"1.2.3.4/8"},
+		}}})
+}
+
+func (s *FirewallRulesSuite) TestSetEgressRules(c *gc.C) {
+	s.backend.SetErrors(nil, nil, errors.NotFoundf(`application "foo"`))
+	result, err := s.api.SetEgressRules(params.SetEgressRulesArgs{
+		Args: []params.ApplicationEgressRules{{
+			Application: "mysql",
+			Rules: []params.EgressRule{{
+				DestinationCIDR: "10.0.0.0/8",
+				PortRange:       params.PortRange{FromPort: 5432, ToPort: 5432, Protocol: "tcp"},
+			}},
+		}, {
+			Application: "foo",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+	c.Assert(s.backend.egressRules, jc.DeepEquals, map[string][]state.EgressRule{
+		"mysql": {{
+			DestinationCIDR: "10.0.0.0/8",
+			PortRange:       network.PortRange{FromPort: 5432, ToPort: 5432, Protocol: "tcp"},
+		}},
+	})
+	s.blockChecker.CheckCallNames(c, "ChangeAllowed")
+}
+
+func (s *FirewallRulesSuite) TestSetEgressRulesPermission(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("mary"))
+	_, err := s.api.SetEgressRules(params.SetEgressRulesArgs{
+		Args: []params.ApplicationEgressRules{{Application: "mysql"}},
+	})
+	c.Assert(err, gc.ErrorMatches, ".*permission denied.*")
+}
+
+func (s *FirewallRulesSuite) TestSetEgressRulesBlocked(c *gc.C) {
+	s.blockChecker.SetErrors(errors.New("blocked"))
+	_, err := s.api.SetEgressRules(params.SetEgressRulesArgs{
+		Args: []params.ApplicationEgressRules{{Application: "mysql"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "blocked")
+	c.Assert(s.backend.egressRules, gc.HasLen, 0)
+}
+
+func (s *FirewallRulesSuite) TestListEgressRules(c *gc.C) {
+	s.backend.egressRules = map[string][]state.EgressRule{
+		"wordpress": {{
+			DestinationCIDR: "0.0.0.0/0",
+			PortRange:       network.PortRange{FromPort: 443, ToPort: 443, Protocol: "tcp"},
+		}},
+		"mysql": {{
+			DestinationCIDR: "10.0.0.0/8",
+			PortRange:       network.PortRange{FromPort: 5432, ToPort: 5432, Protocol: "tcp"},
+		}},
+	}
+	result, err := s.api.ListEgressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ListEgressRulesResults{
+		Results: []params.ApplicationEgressRules{{
+			Application: "mysql",
+			Rules: []params.EgressRule{{
+				DestinationCIDR: "10.0.0.0/8",
+				PortRange:       params.PortRange{FromPort: 5432, ToPort: 5432, Protocol: "tcp"},
+			}},
+		}, {
+			Application: "wordpress",
+			Rules: []params.EgressRule{{
+				DestinationCIDR: "0.0.0.0/0",
+				PortRange:       params.PortRange{FromPort: 443, ToPort: 443, Protocol: "tcp"},
+			}},
+		}},
+	})
+}
+
+func (s *FirewallRulesSuite) TestListEgressRulesPermission(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("mary"))
+	_, err := s.api.ListEgressRules()
+	c.Assert(err, gc.ErrorMatches, ".*permission denied.*")
+}
@@ -15,8 +15,10 @@ type mockBackend struct {
 	jtesting.Stub
 	firewallrules.Backend
 
-	modelUUID string
-	rules     map[string]state.FirewallRule
+	modelUUID    string
+	rules        map[string]state.FirewallRule
+	ingressRules []*state.FirewallRule
+	egressRules  map[string][]state.EgressRule
 }
 
 func (m *mockBackend) GetBlockForType(t state.BlockType) (state.Block, bool, error) {
@@ -32,19 +34,47 @@ func (m *mockBackend) ModelTag() names.ModelTag {
 func (m *mockBackend) SaveFirewallRule(rule state.FirewallRule) error {
 	m.MethodCall(m, "SaveFirewallRule")
 	m.PopNoErr()
-	m.rules[string(rule.WellKnownService)] = rule
+	if rule.Name != "" {
+		m.rules[rule.Name] = rule
+	} else {
+		m.rules[string(rule.WellKnownService)] = rule
+	}
+	return nil
+}
+
+func (m *mockBackend) RemoveFirewallRule(name string) error {
+	m.MethodCall(m, "RemoveFirewallRule", name)
+	if err := m.NextErr(); err != nil {
+		return err
+	}
+	delete(m.rules, name)
 	return nil
 }
 
 func (m *mockBackend) ListFirewallRules() ([]*state.FirewallRule, error) {
 	m.MethodCall(m, "ListFirewallRules")
 	m.PopNoErr()
-	return []*state.FirewallRule{
+	return append([]*state.FirewallRule{
 		{
 			WellKnownService: state.JujuApplicationOfferRule,
 			WhitelistCIDRs:   []string{"1.2.3.4/8"},
 		},
-	}, nil
+	}, m.ingressRules...), nil
+}
+
+func (m *mockBackend) SetEgressRules(application string, rules []state.EgressRule) error {
+	m.MethodCall(m, "SetEgressRules", application, rules)
+	if err := m.NextErr(); err != nil {
+		return err
+	}
+	m.egressRules[application] = rules
+	return nil
+}
+
+func (m *mockBackend) AllEgressRules() (map[string][]state.EgressRule, error) {
+	m.MethodCall(m, "AllEgressRules")
+	m.PopNoErr()
+	return m.egressRules, nil
 }
 
 type mockBlockChecker struct {
@@ -55,3 +85,8 @@ func (c *mockBlockChecker) ChangeAllowed() error {
 	c.MethodCall(c, "ChangeAllowed")
 	return c.NextErr()
 }
+
+func (c *mockBlockChecker) RemoveAllowed() error {
+	c.MethodCall(c, "RemoveAllowed")
+	return c.NextErr()
+}
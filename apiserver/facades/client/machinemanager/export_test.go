@@ -4,4 +4,7 @@
 package machinemanager
 
 var InstanceTypes = instanceTypes
+var ProvisioningInstanceTypes = provisioningInstanceTypes
 var IsSeriesLessThan = isSeriesLessThan
+var SetMachineProtection = setMachineProtection
+var ResizeMachines = resizeMachines
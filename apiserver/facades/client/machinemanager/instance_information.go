@@ -25,23 +25,7 @@ func instanceTypes(mm *MachineManagerAPI,
 	getEnviron environGetFunc,
 	cons params.ModelInstanceTypesConstraints,
 ) (params.InstanceTypesResults, error) {
-	model, err := mm.st.Model()
-	if err != nil {
-		return params.InstanceTypesResults{}, errors.Trace(err)
-	}
-
-	cloudSpec := func() (environs.CloudSpec, error) {
-		cloudName := model.Cloud()
-		regionName := model.CloudRegion()
-		credentialTag, _ := model.CloudCredential()
-		return stateenvirons.CloudSpec(mm.st, cloudName, regionName, credentialTag)
-	}
-	backend := common.EnvironConfigGetterFuncs{
-		CloudSpecFunc:   cloudSpec,
-		ModelConfigFunc: model.Config,
-	}
-
-	env, err := getEnviron(backend, environs.New)
+	env, err := mm.environ(getEnviron)
 	if err != nil {
 		return params.InstanceTypesResults{}, errors.Trace(err)
 	}
@@ -66,3 +50,89 @@ func instanceTypes(mm *MachineManagerAPI,
 
 	return params.InstanceTypesResults{Results: result}, nil
 }
+
+// ProvisioningInstanceTypes returns, for each of the given constraints,
+// the instance types that a new machine with those constraints would be
+// provisioned as, cheapest first. As when provisioning, the constraints
+// are combined with the model constraints, and the cloud's default
+// constraints for non-controller machines are applied.
+func (mm *MachineManagerAPI) ProvisioningInstanceTypes(cons params.ModelInstanceTypesConstraints) (params.InstanceTypesResults, error) {
+	return provisioningInstanceTypes(mm, environs.GetEnviron, cons)
+}
+
+// ProvisioningInstanceTypes isn't on the v9 API.
+func (*MachineManagerAPIV9) ProvisioningInstanceTypes(_, _ struct{}) {}
+
+func provisioningInstanceTypes(mm *MachineManagerAPI,
+	getEnviron environGetFunc,
+	cons params.ModelInstanceTypesConstraints,
+) (params.InstanceTypesResults, error) {
+	if err := mm.checkCanRead(); err != nil {
+		return params.InstanceTypesResults{}, err
+	}
+	env, err := mm.environ(getEnviron)
+	if err != nil {
+		return params.InstanceTypesResults{}, errors.Trace(err)
+	}
+	modelCons, err := mm.st.ModelConstraints()
+	if err != nil {
+		return params.InstanceTypesResults{}, errors.Trace(err)
+	}
+	validator, err := env.ConstraintsValidator(mm.callContext)
+	if err != nil {
+		return params.InstanceTypesResults{}, errors.Trace(err)
+	}
+	defaults, _ := env.(environs.DefaultInstanceConstraintsApplier)
+
+	result := make([]params.InstanceTypesResult, len(cons.Constraints))
+	for i, c := range cons.Constraints {
+		value := constraints.Value{}
+		if c.Value != nil {
+			value = *c.Value
+		}
+		it, err := provisioningInstanceTypesFor(mm, env, validator, defaults, modelCons, value)
+		if err != nil {
+			it = params.InstanceTypesResult{Error: common.ServerError(err)}
+		}
+		result[i] = it
+	}
+	return params.InstanceTypesResults{Results: result}, nil
+}
+
+func provisioningInstanceTypesFor(
+	mm *MachineManagerAPI,
+	env environs.Environ,
+	validator constraints.Validator,
+	defaults environs.DefaultInstanceConstraintsApplier,
+	modelCons, value constraints.Value,
+) (params.InstanceTypesResult, error) {
+	cons, err := validator.Merge(modelCons, value)
+	if err != nil {
+		return params.InstanceTypesResult{}, errors.Trace(err)
+	}
+	if defaults != nil {
+		cons = defaults.ApplyDefaultInstanceConstraints(cons)
+	}
+	itCons := common.NewInstanceTypeConstraints(env, mm.callContext, cons)
+	return common.InstanceTypes(itCons)
+}
+
+// environ returns the Environ for the model, created with getEnviron.
+func (mm *MachineManagerAPI) environ(getEnviron environGetFunc) (environs.Environ, error) {
+	model, err := mm.st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	cloudSpec := func() (environs.CloudSpec, error) {
+		cloudName := model.Cloud()
+		regionName := model.CloudRegion()
+		credentialTag, _ := model.CloudCredential()
+		return stateenvirons.CloudSpec(mm.st, cloudName, regionName, credentialTag)
+	}
+	backend := common.EnvironConfigGetterFuncs{
+		CloudSpecFunc:   cloudSpec,
+		ModelConfigFunc: model.Config,
+	}
+	return getEnviron(backend, environs.New)
+}
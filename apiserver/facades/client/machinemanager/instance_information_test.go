@@ -84,11 +84,66 @@ func (p *instanceTypesSuite) TestInstanceTypes(c *gc.C) {
 	c.Assert(r.Results, gc.DeepEquals, expected)
 }
 
+func (p *instanceTypesSuite) TestProvisioningInstanceTypes(c *gc.C) {
+	backend := &mockBackend{
+		modelConstraints: constraints.MustParse("arch=amd64 mem=2G"),
+	}
+	authorizer := testing.FakeAuthorizer{Tag: names.NewUserTag("admin"),
+		Controller: true}
+	env := &provisioningEnviron{}
+	api, err := machinemanager.NewMachineManagerAPI(backend, backend, &mockPool{}, authorizer, backend.ModelTag(), context.NewCloudCallContext(), common.NewResources())
+	c.Assert(err, jc.ErrorIsNil)
+
+	mem4G := constraints.MustParse("mem=4G")
+	instanceType := constraints.MustParse("instance-type=m5.large")
+	cons := params.ModelInstanceTypesConstraints{
+		Constraints: []params.ModelInstanceTypesConstraint{{Value: &mem4G}, {Value: &instanceType}, {}},
+	}
+	fakeEnvironGet := func(st environs.EnvironConfigGetter,
+		newEnviron environs.NewEnvironFunc,
+	) (environs.Environ, error) {
+		return env, nil
+	}
+	r, err := machinemanager.ProvisioningInstanceTypes(api, fakeEnvironGet, cons)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.Results, gc.HasLen, 3)
+	// The model constraints are combined with those requested, and
+	// the cloud's defaults applied, as when provisioning.
+	expected := []string{
+		constraints.MustParse("arch=amd64 cpu-power=100 mem=4G").String(),
+		constraints.MustParse("arch=amd64 instance-type=m5.large").String(),
+		constraints.MustParse("arch=amd64 cpu-power=100 mem=2G").String(),
+	}
+	c.Assert(env.requested, jc.DeepEquals, expected)
+	for i, result := range r.Results {
+		c.Check(result, jc.DeepEquals, params.InstanceTypesResult{
+			InstanceTypes: []params.InstanceType{{Name: expected[i]}},
+		})
+	}
+}
+
+func (p *instanceTypesSuite) TestProvisioningInstanceTypesPermission(c *gc.C) {
+	backend := &mockBackend{}
+	authorizer := testing.FakeAuthorizer{Tag: names.NewUserTag("nobody")}
+	api, err := machinemanager.NewMachineManagerAPI(backend, backend, &mockPool{}, authorizer, backend.ModelTag(), context.NewCloudCallContext(), common.NewResources())
+	c.Assert(err, jc.ErrorIsNil)
+
+	fakeEnvironGet := func(st environs.EnvironConfigGetter,
+		newEnviron environs.NewEnvironFunc,
+	) (environs.Environ, error) {
+		c.Fail()
+		return nil, nil
+	}
+	_, err = machinemanager.ProvisioningInstanceTypes(api, fakeEnvironGet, params.ModelInstanceTypesConstraints{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
 type mockBackend struct {
 	machinemanager.Backend
 	storagecommon.StorageAccess
 
-	cloudSpec environs.CloudSpec
+	cloudSpec        environs.CloudSpec
+	modelConstraints constraints.Value
 }
 
 func (st *mockBackend) VolumeAccess() storagecommon.VolumeAccess {
@@ -107,6 +162,10 @@ func (b *mockBackend) Model() (machinemanager.Model, error) {
 	return &mockModel{}, nil
 }
 
+func (b *mockBackend) ModelConstraints() (constraints.Value, error) {
+	return b.modelConstraints, nil
+}
+
 func (b *mockBackend) CloudSpec(names.ModelTag) (environs.CloudSpec, error) {
 	return b.cloudSpec, nil
 }
@@ -142,6 +201,35 @@ func (m *mockEnviron) InstanceTypes(ctx context.ProviderCallContext, c constrain
 	return it, nil
 }
 
+// provisioningEnviron is an Environ that applies a default cpu-power
+// constraint, and reports instance types named after the constraints
+// they were requested with.
+type provisioningEnviron struct {
+	environs.Environ
+
+	requested []string
+}
+
+func (e *provisioningEnviron) ConstraintsValidator(context.ProviderCallContext) (constraints.Validator, error) {
+	validator := constraints.NewValidator()
+	validator.RegisterConflicts([]string{constraints.InstanceType}, []string{constraints.Mem})
+	return validator, nil
+}
+
+func (e *provisioningEnviron) ApplyDefaultInstanceConstraints(cons constraints.Value) constraints.Value {
+	if !cons.HasInstanceType() && !cons.HasCpuPower() {
+		cons.CpuPower = instances.CpuPower(100)
+	}
+	return cons
+}
+
+func (e *provisioningEnviron) InstanceTypes(ctx context.ProviderCallContext, c constraints.Value) (instances.InstanceTypesWithCostMetadata, error) {
+	e.requested = append(e.requested, c.String())
+	return instances.InstanceTypesWithCostMetadata{
+		InstanceTypes: []instances.InstanceType{{Name: c.String()}},
+	}, nil
+}
+
 type mockModel struct {
 	machinemanager.Model
 }
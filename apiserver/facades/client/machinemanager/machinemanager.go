@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/context"
 	"github.com/juju/juju/permission"
@@ -71,6 +72,30 @@ type MachineManagerAPIV5 struct {
 // Version 6 of Machine Manager API.
 // Changes input parameters to DestroyMachineWithParams and ForceDestroyMachine.
 type MachineManagerAPIV6 struct {
+	*MachineManagerAPIV7
+}
+
+// Version 7 of Machine Manager API.
+// Adds SetMachineProtection and ForceUnprotected to DestroyMachineWithParams.
+type MachineManagerAPIV7 struct {
+	*MachineManagerAPIV8
+}
+
+// Version 8 of Machine Manager API.
+// Adds ResizeMachines.
+type MachineManagerAPIV8 struct {
+	*MachineManagerAPIV9
+}
+
+// Version 9 of Machine Manager API.
+// Adds NetworkConfigDiff.
+type MachineManagerAPIV9 struct {
+	*MachineManagerAPIV10
+}
+
+// Version 10 of Machine Manager API.
+// Adds ProvisioningInstanceTypes.
+type MachineManagerAPIV10 struct {
 	*MachineManagerAPI
 }
 
@@ -94,11 +119,47 @@ func NewFacadeV5(ctx facade.Context) (*MachineManagerAPIV5, error) {
 
 // NewFacadeV6 creates a new server-side MachineManager API facade.
 func NewFacadeV6(ctx facade.Context) (*MachineManagerAPIV6, error) {
+	machineManagerAPIv7, err := NewFacadeV7(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &MachineManagerAPIV6{machineManagerAPIv7}, nil
+}
+
+// NewFacadeV7 creates a new server-side MachineManager API facade.
+func NewFacadeV7(ctx facade.Context) (*MachineManagerAPIV7, error) {
+	machineManagerAPIv8, err := NewFacadeV8(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &MachineManagerAPIV7{machineManagerAPIv8}, nil
+}
+
+// NewFacadeV8 creates a new server-side MachineManager API facade.
+func NewFacadeV8(ctx facade.Context) (*MachineManagerAPIV8, error) {
+	machineManagerAPIv9, err := NewFacadeV9(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &MachineManagerAPIV8{machineManagerAPIv9}, nil
+}
+
+// NewFacadeV9 creates a new server-side MachineManager API facade.
+func NewFacadeV9(ctx facade.Context) (*MachineManagerAPIV9, error) {
+	machineManagerAPIv10, err := NewFacadeV10(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &MachineManagerAPIV9{machineManagerAPIv10}, nil
+}
+
+// NewFacadeV10 creates a new server-side MachineManager API facade.
+func NewFacadeV10(ctx facade.Context) (*MachineManagerAPIV10, error) {
 	machineManagerAPI, err := NewFacade(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return &MachineManagerAPIV6{machineManagerAPI}, nil
+	return &MachineManagerAPIV10{machineManagerAPI}, nil
 }
 
 // NewMachineManagerAPI creates a new server-side MachineManager API facade.
@@ -270,14 +331,14 @@ func (mm *MachineManagerAPI) addOneMachine(p params.AddMachineParams) (*state.Ma
 
 // DestroyMachine removes a set of machines from the model.
 func (mm *MachineManagerAPI) DestroyMachine(args params.Entities) (params.DestroyMachineResults, error) {
-	return mm.destroyMachine(args, false, false, time.Duration(0))
+	return mm.destroyMachine(args, false, false, false, time.Duration(0))
 }
 
 // ForceDestroyMachine forcibly removes a set of machines from the model.
 // TODO (anastasiamac 2019-4-24) From Juju 3.0 this call will be removed in favour of DestroyMachinesWithParams.
 // Also from ModelManger v6 this call is less useful as it does not support MaxWait customisation.
 func (mm *MachineManagerAPI) ForceDestroyMachine(args params.Entities) (params.DestroyMachineResults, error) {
-	return mm.destroyMachine(args, true, false, time.Duration(0))
+	return mm.destroyMachine(args, true, false, false, time.Duration(0))
 }
 
 // DestroyMachineWithParams removes a set of machines from the model.
@@ -287,7 +348,14 @@ func (mm *MachineManagerAPIV5) DestroyMachineWithParams(args params.DestroyMachi
 	for i, tag := range args.MachineTags {
 		entities.Entities[i].Tag = tag
 	}
-	return mm.destroyMachine(entities, args.Force, args.Keep, time.Duration(0))
+	return mm.destroyMachine(entities, args.Force, args.Keep, false, time.Duration(0))
+}
+
+// DestroyMachineWithParams removes a set of machines from the model.
+// v6 and prior versions did not support ForceUnprotected.
+func (mm *MachineManagerAPIV6) DestroyMachineWithParams(args params.DestroyMachinesParams) (params.DestroyMachineResults, error) {
+	args.ForceUnprotected = false
+	return mm.MachineManagerAPIV7.DestroyMachineWithParams(args)
 }
 
 // DestroyMachineWithParams removes a set of machines from the model.
@@ -296,10 +364,10 @@ func (mm *MachineManagerAPI) DestroyMachineWithParams(args params.DestroyMachine
 	for i, tag := range args.MachineTags {
 		entities.Entities[i].Tag = tag
 	}
-	return mm.destroyMachine(entities, args.Force, args.Keep, common.MaxWait(args.MaxWait))
+	return mm.destroyMachine(entities, args.Force, args.Keep, args.ForceUnprotected, common.MaxWait(args.MaxWait))
 }
 
-func (mm *MachineManagerAPI) destroyMachine(args params.Entities, force, keep bool, maxWait time.Duration) (params.DestroyMachineResults, error) {
+func (mm *MachineManagerAPI) destroyMachine(args params.Entities, force, keep, forceUnprotected bool, maxWait time.Duration) (params.DestroyMachineResults, error) {
 	if err := mm.checkCanWrite(); err != nil {
 		return params.DestroyMachineResults{}, err
 	}
@@ -330,6 +398,12 @@ func (mm *MachineManagerAPI) destroyMachine(args params.Entities, force, keep bo
 				logger.Warningf("could not keep instance for machine %v: %v", machineTag.Id(), err)
 			}
 		}
+		if forceUnprotected && machine.IsProtected() {
+			logger.Infof("removing termination protection from machine %v", machineTag.Id())
+			if err := mm.protectMachine(environs.GetEnviron, machine, false); err != nil {
+				return fail(err)
+			}
+		}
 		var info params.DestroyMachineInfo
 		units, err := machine.Units()
 		if err != nil {
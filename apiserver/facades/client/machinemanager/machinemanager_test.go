@@ -21,9 +21,12 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/network/netplan"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/storage"
 	coretesting "github.com/juju/juju/testing"
@@ -450,7 +453,11 @@ func (s *MachineManagerSuite) setupUpgradeSeries(c *gc.C) {
 }
 
 func (s *MachineManagerSuite) apiV5() machinemanager.MachineManagerAPIV5 {
-	return machinemanager.MachineManagerAPIV5{MachineManagerAPIV6: &machinemanager.MachineManagerAPIV6{s.api}}
+	return machinemanager.MachineManagerAPIV5{
+		MachineManagerAPIV6: &machinemanager.MachineManagerAPIV6{
+			&machinemanager.MachineManagerAPIV7{&machinemanager.MachineManagerAPIV8{s.api}},
+		},
+	}
 }
 
 func (s *MachineManagerSuite) TestUpgradeSeriesValidateOK(c *gc.C) {
@@ -922,15 +929,34 @@ type mockMachine struct {
 	machinemanager.Machine
 
 	keep           bool
+	protected      bool
+	instanceId     instance.Id
+	constraints    constraints.Value
+	hardware       instance.HardwareCharacteristics
 	series         string
 	units          []string
 	unitAgentState status.Status
 	unitState      status.Status
 	isManager      bool
+	netplanDevices []netplan.DeviceConfig
+	netplanStatus  *state.MachineNetplanStatus
 
 	unitsF func() ([]machinemanager.Unit, error)
 }
 
+func (m *mockMachine) NetplanDevices() ([]netplan.DeviceConfig, error) {
+	m.MethodCall(m, "NetplanDevices")
+	return m.netplanDevices, nil
+}
+
+func (m *mockMachine) NetplanStatus() (state.MachineNetplanStatus, error) {
+	m.MethodCall(m, "NetplanStatus")
+	if m.netplanStatus == nil {
+		return state.MachineNetplanStatus{}, errors.NotFoundf("netplan status")
+	}
+	return *m.netplanStatus, nil
+}
+
 func (m *mockMachine) Destroy() error {
 	m.MethodCall(m, "Destroy")
 	return nil
@@ -952,6 +978,43 @@ func (m *mockMachine) SetKeepInstance(keep bool) error {
 	return nil
 }
 
+func (m *mockMachine) IsProtected() bool {
+	m.MethodCall(m, "IsProtected")
+	return m.protected
+}
+
+func (m *mockMachine) SetProtected(protected bool) error {
+	m.MethodCall(m, "SetProtected", protected)
+	m.protected = protected
+	return nil
+}
+
+func (m *mockMachine) InstanceId() (instance.Id, error) {
+	m.MethodCall(m, "InstanceId")
+	if m.instanceId == "" {
+		return "", errors.NotProvisionedf("machine")
+	}
+	return m.instanceId, nil
+}
+
+func (m *mockMachine) Constraints() (constraints.Value, error) {
+	m.MethodCall(m, "Constraints")
+	return m.constraints, nil
+}
+
+func (m *mockMachine) HardwareCharacteristics() (*instance.HardwareCharacteristics, error) {
+	m.MethodCall(m, "HardwareCharacteristics")
+	hc := m.hardware
+	return &hc, nil
+}
+
+func (m *mockMachine) SetInstanceResized(cons constraints.Value, hc instance.HardwareCharacteristics) error {
+	m.MethodCall(m, "SetInstanceResized", cons, hc)
+	m.constraints = cons
+	m.hardware = hc
+	return nil
+}
+
 func (m *mockMachine) Series() string {
 	m.MethodCall(m, "Series")
 	return m.series
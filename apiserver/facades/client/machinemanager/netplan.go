@@ -0,0 +1,92 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinemanager
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network/netplan"
+)
+
+// NetworkConfigDiff returns, for each of the given machines, the netplan
+// configuration rendered from the link-layer devices known to Juju, the
+// configuration last reported by the machine's agent, and the differences
+// between them.
+func (mm *MachineManagerAPI) NetworkConfigDiff(args params.Entities) (params.MachineNetworkDiffResults, error) {
+	if err := mm.checkCanRead(); err != nil {
+		return params.MachineNetworkDiffResults{}, err
+	}
+	results := make([]params.MachineNetworkDiffResult, len(args.Entities))
+	for i, entity := range args.Entities {
+		result, err := mm.networkConfigDiff(entity.Tag)
+		if err != nil {
+			results[i].Error = common.ServerError(err)
+			continue
+		}
+		results[i] = result
+	}
+	return params.MachineNetworkDiffResults{Results: results}, nil
+}
+
+// NetworkConfigDiff isn't on the v8 API.
+func (*MachineManagerAPIV8) NetworkConfigDiff(_, _ struct{}) {}
+
+func (mm *MachineManagerAPI) networkConfigDiff(tag string) (params.MachineNetworkDiffResult, error) {
+	var result params.MachineNetworkDiffResult
+	machineTag, err := names.ParseMachineTag(tag)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	machine, err := mm.st.Machine(machineTag.Id())
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+
+	devices, err := machine.NetplanDevices()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	desired, err := netplan.Render(devices)
+	if err != nil {
+		return result, errors.Annotate(err, "rendering desired netplan")
+	}
+	out, err := netplan.Marshal(&desired)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Desired = string(out)
+
+	// Until the machine's agent reports its netplan configuration,
+	// every desired device is shown as added.
+	var actual netplan.Netplan
+	status, err := machine.NetplanStatus()
+	if err != nil && !errors.IsNotFound(err) {
+		return result, errors.Trace(err)
+	} else if err == nil {
+		if err := netplan.Unmarshal([]byte(status.Actual), &actual); err != nil {
+			return result, errors.Annotate(err, "reading reported netplan")
+		}
+		updated := status.Updated
+		result.Actual = status.Actual
+		result.ApplyError = status.Error
+		result.Updated = &updated
+	}
+
+	changes, err := netplan.MergeDiff(actual, desired)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, change := range changes {
+		result.Changes = append(result.Changes, params.NetplanDeviceChange{
+			Type:    string(change.Type),
+			Name:    change.Name,
+			Change:  string(change.Change),
+			Details: change.Details,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinemanager_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network/netplan"
+	"github.com/juju/juju/state"
+)
+
+var diffDevices = []netplan.DeviceConfig{{
+	Name:       "eno1",
+	Type:       netplan.TypeEthernet,
+	MACAddress: "00:11:22:33:44:55",
+	MTU:        9000,
+}}
+
+func (s *MachineManagerSuite) TestNetworkConfigDiff(c *gc.C) {
+	updated := time.Date(2020, 2, 1, 12, 0, 0, 0, time.UTC)
+	s.st.machines["0"] = &mockMachine{
+		netplanDevices: diffDevices,
+		netplanStatus: &state.MachineNetplanStatus{
+			Actual: `
+network:
+  version: 2
+  ethernets:
+    eno1:
+      match:
+        macaddress: "00:11:22:33:44:55"
+      set-name: eno1
+      mtu: 1500
+`[1:],
+			Error:   "netplan apply error code 1",
+			Updated: updated,
+		},
+	}
+	s.st.machines["1"] = &mockMachine{
+		netplanDevices: diffDevices,
+	}
+
+	results, err := s.api.NetworkConfigDiff(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "machine-0"},
+			{Tag: "machine-1"},
+			{Tag: "machine-2"},
+			{Tag: "application-foo"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+
+	desired := `
+network:
+  version: 2
+  ethernets:
+    eno1:
+      match:
+        macaddress: "00:11:22:33:44:55"
+      set-name: eno1
+      mtu: 9000
+`[1:]
+	c.Check(results.Results[0], jc.DeepEquals, params.MachineNetworkDiffResult{
+		Desired: desired,
+		Actual:  s.st.machines["0"].netplanStatus.Actual,
+		Changes: []params.NetplanDeviceChange{{
+			Type:    "ethernet",
+			Name:    "eno1",
+			Change:  "changed",
+			Details: []string{"mtu: 1500 -> 9000"},
+		}},
+		ApplyError: "netplan apply error code 1",
+		Updated:    &updated,
+	})
+	// Machine 1 has not reported its configuration yet.
+	c.Check(results.Results[1], jc.DeepEquals, params.MachineNetworkDiffResult{
+		Desired: desired,
+		Changes: []params.NetplanDeviceChange{{
+			Type:   "ethernet",
+			Name:   "eno1",
+			Change: "added",
+			Details: []string{
+				"match.macaddress: (none) -> 00:11:22:33:44:55",
+				"mtu: (none) -> 9000",
+				"set-name: (none) -> eno1",
+			},
+		}},
+	})
+	c.Check(results.Results[2].Error, jc.DeepEquals, &params.Error{
+		Message: "machine 2 not found",
+		Code:    params.CodeNotFound,
+	})
+	c.Check(results.Results[3].Error, gc.ErrorMatches, `"application-foo" is not a valid machine tag`)
+}
+
+func (s *MachineManagerSuite) TestNetworkConfigDiffPermission(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("fred"))
+	_, err := s.api.NetworkConfigDiff(params.Entities{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinemanager
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+)
+
+// SetMachineProtection sets whether the cloud instances of the given
+// machines are protected from termination. Where the cloud supports it,
+// the instances are also protected through the cloud's API.
+func (mm *MachineManagerAPI) SetMachineProtection(args params.MachineProtectionArgs) (params.ErrorResults, error) {
+	return setMachineProtection(mm, environs.GetEnviron, args)
+}
+
+// SetMachineProtection isn't on the v6 API.
+func (*MachineManagerAPIV6) SetMachineProtection(_, _ struct{}) {}
+
+func setMachineProtection(
+	mm *MachineManagerAPI,
+	getEnviron environGetFunc,
+	args params.MachineProtectionArgs,
+) (params.ErrorResults, error) {
+	if err := mm.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, err
+	}
+	if err := mm.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := make([]params.ErrorResult, len(args.Args))
+	for i, arg := range args.Args {
+		machineTag, err := names.ParseMachineTag(arg.MachineTag)
+		if err != nil {
+			results[i].Error = common.ServerError(err)
+			continue
+		}
+		machine, err := mm.st.Machine(machineTag.Id())
+		if err != nil {
+			results[i].Error = common.ServerError(err)
+			continue
+		}
+		err = mm.protectMachine(getEnviron, machine, arg.Protected)
+		results[i].Error = common.ServerError(err)
+	}
+	return params.ErrorResults{Results: results}, nil
+}
+
+// protectMachine records whether the machine is protected and updates
+// the protection of its cloud instance. The ordering ensures a failure
+// never leaves the machine recorded as unprotected while its instance
+// is still protected in the cloud.
+func (mm *MachineManagerAPI) protectMachine(getEnviron environGetFunc, machine Machine, protected bool) error {
+	if protected {
+		if err := machine.SetProtected(true); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if err := mm.setInstanceProtection(getEnviron, machine, protected); err != nil {
+		return errors.Trace(err)
+	}
+	if !protected {
+		if err := machine.SetProtected(false); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// setInstanceProtection updates the termination protection of the
+// machine's cloud instance, if the cloud supports it. Machines which
+// are not yet provisioned are protected by the provisioner when their
+// instances are started.
+func (mm *MachineManagerAPI) setInstanceProtection(getEnviron environGetFunc, machine Machine, protected bool) error {
+	instId, err := machine.InstanceId()
+	if errors.IsNotProvisioned(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	env, err := mm.environ(getEnviron)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	protector, ok := env.(environs.InstanceProtector)
+	if !ok {
+		return nil
+	}
+	err = protector.SetInstanceProtection(mm.callContext, []instance.Id{instId}, protected)
+	return errors.Annotatef(err, "setting termination protection for instance %q", instId)
+}
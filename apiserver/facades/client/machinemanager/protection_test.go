@@ -0,0 +1,113 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinemanager_test
+
+import (
+	"github.com/juju/errors"
+	jtesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facades/client/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+)
+
+type mockProtectorEnviron struct {
+	environs.Environ
+	jtesting.Stub
+}
+
+func (e *mockProtectorEnviron) SetInstanceProtection(ctx context.ProviderCallContext, ids []instance.Id, protected bool) error {
+	e.MethodCall(e, "SetInstanceProtection", ids, protected)
+	return e.NextErr()
+}
+
+func (s *MachineManagerSuite) environGetter(env environs.Environ) func(environs.EnvironConfigGetter, environs.NewEnvironFunc) (environs.Environ, error) {
+	return func(environs.EnvironConfigGetter, environs.NewEnvironFunc) (environs.Environ, error) {
+		return env, nil
+	}
+}
+
+func (s *MachineManagerSuite) TestSetMachineProtection(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{instanceId: "i-0"}
+	s.st.machines["1"] = &mockMachine{protected: true}
+	env := &mockProtectorEnviron{}
+
+	results, err := machinemanager.SetMachineProtection(s.api, s.environGetter(env), params.MachineProtectionArgs{
+		Args: []params.MachineProtectionArg{
+			{MachineTag: "machine-0", Protected: true},
+			{MachineTag: "machine-1", Protected: false},
+			{MachineTag: "machine-2", Protected: true},
+			{MachineTag: "application-foo", Protected: true},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{},
+			{Error: &params.Error{Message: "machine 2 not found", Code: params.CodeNotFound}},
+			{Error: &params.Error{Message: `"application-foo" is not a valid machine tag`}},
+		},
+	})
+
+	m0 := s.st.machines["0"]
+	c.Assert(m0.protected, jc.IsTrue)
+	m0.CheckCallNames(c, "SetProtected", "InstanceId")
+	env.CheckCalls(c, []jtesting.StubCall{
+		{"SetInstanceProtection", []interface{}{[]instance.Id{"i-0"}, true}},
+	})
+
+	// Machine 1 is not provisioned, so is only unprotected in state.
+	m1 := s.st.machines["1"]
+	c.Assert(m1.protected, jc.IsFalse)
+	m1.CheckCallNames(c, "InstanceId", "SetProtected")
+}
+
+func (s *MachineManagerSuite) TestSetMachineProtectionCloudFailure(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{instanceId: "i-0", protected: true}
+	env := &mockProtectorEnviron{}
+	env.SetErrors(errors.New("boom"))
+
+	results, err := machinemanager.SetMachineProtection(s.api, s.environGetter(env), params.MachineProtectionArgs{
+		Args: []params.MachineProtectionArg{{MachineTag: "machine-0", Protected: false}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), gc.ErrorMatches, `setting termination protection for instance "i-0": boom`)
+
+	// The machine must remain protected while its instance is.
+	c.Assert(s.st.machines["0"].protected, jc.IsTrue)
+}
+
+func (s *MachineManagerSuite) TestDestroyMachineWithParamsForceUnprotected(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{protected: true}
+	results, err := s.api.DestroyMachineWithParams(params.DestroyMachinesParams{
+		MachineTags:      []string{"machine-0"},
+		ForceUnprotected: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+
+	m := s.st.machines["0"]
+	c.Assert(m.protected, jc.IsFalse)
+	m.CheckCallNames(c, "IsProtected", "InstanceId", "SetProtected", "Units", "Destroy")
+}
+
+func (s *MachineManagerSuite) TestDestroyMachineWithParamsV6IgnoresForceUnprotected(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{protected: true}
+	apiV6 := machinemanager.MachineManagerAPIV6{&machinemanager.MachineManagerAPIV7{&machinemanager.MachineManagerAPIV8{s.api}}}
+	_, err := apiV6.DestroyMachineWithParams(params.DestroyMachinesParams{
+		MachineTags:      []string{"machine-0"},
+		ForceUnprotected: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	m := s.st.machines["0"]
+	c.Assert(m.protected, jc.IsTrue)
+	m.CheckCallNames(c, "Units", "Destroy")
+}
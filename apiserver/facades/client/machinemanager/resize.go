@@ -0,0 +1,110 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinemanager
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+)
+
+// ResizeMachines changes the hardware of the cloud instances of the given
+// machines to satisfy the given constraints, which are combined with each
+// machine's existing constraints. The machines' constraints and hardware
+// characteristics are updated to match.
+func (mm *MachineManagerAPI) ResizeMachines(args params.ResizeMachineArgs) (params.ResizeMachineResults, error) {
+	return resizeMachines(mm, environs.GetEnviron, args)
+}
+
+// ResizeMachines isn't on the v7 API.
+func (*MachineManagerAPIV7) ResizeMachines(_, _ struct{}) {}
+
+func resizeMachines(
+	mm *MachineManagerAPI,
+	getEnviron environGetFunc,
+	args params.ResizeMachineArgs,
+) (params.ResizeMachineResults, error) {
+	if err := mm.checkCanWrite(); err != nil {
+		return params.ResizeMachineResults{}, err
+	}
+	if err := mm.check.ChangeAllowed(); err != nil {
+		return params.ResizeMachineResults{}, errors.Trace(err)
+	}
+	results := make([]params.ResizeMachineResult, len(args.Args))
+	if len(args.Args) == 0 {
+		return params.ResizeMachineResults{Results: results}, nil
+	}
+	env, err := mm.environ(getEnviron)
+	if err != nil {
+		return params.ResizeMachineResults{}, errors.Trace(err)
+	}
+	for i, arg := range args.Args {
+		hc, err := mm.resizeMachine(env, arg)
+		results[i].HardwareCharacteristics = hc
+		results[i].Error = common.ServerError(err)
+	}
+	return params.ResizeMachineResults{Results: results}, nil
+}
+
+// resizeMachine resizes the cloud instance of one machine, and records
+// its new constraints and hardware in state.
+func (mm *MachineManagerAPI) resizeMachine(env environs.Environ, arg params.ResizeMachineArg) (*instance.HardwareCharacteristics, error) {
+	machineTag, err := names.ParseMachineTag(arg.MachineTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if names.IsContainerMachine(machineTag.Id()) {
+		return nil, errors.NotSupportedf("resizing container machine %v", machineTag.Id())
+	}
+	machine, err := mm.st.Machine(machineTag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	instId, err := machine.InstanceId()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	resizer, ok := env.(environs.InstanceResizer)
+	if !ok {
+		return nil, errors.NotSupportedf("resizing machines in this cloud")
+	}
+
+	current, err := machine.Constraints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	validator, err := env.ConstraintsValidator(mm.callContext)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cons, err := validator.Merge(current, arg.Constraints)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// The instance keeps its image, so the new
+	// hardware must have the same architecture.
+	resizeCons := cons
+	if !resizeCons.HasArch() {
+		hc, err := machine.HardwareCharacteristics()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		resizeCons.Arch = hc.Arch
+	}
+
+	logger.Infof("resizing instance %q of machine %v to match constraints %q", instId, machineTag.Id(), resizeCons)
+	hc, err := resizer.ResizeInstance(mm.callContext, instId, resizeCons)
+	if err != nil {
+		return nil, errors.Annotatef(err, "resizing instance %q", instId)
+	}
+	if err := machine.SetInstanceResized(cons, *hc); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return hc, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinemanager_test
+
+import (
+	"github.com/juju/errors"
+	jtesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facades/client/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+)
+
+type mockResizerEnviron struct {
+	environs.Environ
+	jtesting.Stub
+}
+
+func (e *mockResizerEnviron) ConstraintsValidator(ctx context.ProviderCallContext) (constraints.Validator, error) {
+	validator := constraints.NewValidator()
+	validator.RegisterConflicts([]string{constraints.InstanceType}, []string{constraints.Mem, constraints.Cores})
+	return validator, nil
+}
+
+func (e *mockResizerEnviron) ResizeInstance(ctx context.ProviderCallContext, id instance.Id, cons constraints.Value) (*instance.HardwareCharacteristics, error) {
+	e.MethodCall(e, "ResizeInstance", id, cons)
+	if err := e.NextErr(); err != nil {
+		return nil, err
+	}
+	return &instance.HardwareCharacteristics{Mem: cons.Mem, CpuCores: cons.CpuCores}, nil
+}
+
+func (s *MachineManagerSuite) TestResizeMachines(c *gc.C) {
+	arch := "amd64"
+	s.st.machines["0"] = &mockMachine{
+		instanceId:  "i-0",
+		constraints: constraints.MustParse("instance-type=m5.large root-disk=20G"),
+		hardware:    instance.HardwareCharacteristics{Arch: &arch},
+	}
+	s.st.machines["1"] = &mockMachine{}
+	env := &mockResizerEnviron{}
+
+	results, err := machinemanager.ResizeMachines(s.api, s.environGetter(env), params.ResizeMachineArgs{
+		Args: []params.ResizeMachineArg{
+			{MachineTag: "machine-0", Constraints: constraints.MustParse("mem=16G cores=4")},
+			{MachineTag: "machine-1", Constraints: constraints.MustParse("mem=16G")},
+			{MachineTag: "machine-0-lxd-0", Constraints: constraints.MustParse("mem=16G")},
+			{MachineTag: "application-foo", Constraints: constraints.MustParse("mem=16G")},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	mem := uint64(16384)
+	cores := uint64(4)
+	c.Assert(results, jc.DeepEquals, params.ResizeMachineResults{
+		Results: []params.ResizeMachineResult{
+			{HardwareCharacteristics: &instance.HardwareCharacteristics{Mem: &mem, CpuCores: &cores}},
+			{Error: &params.Error{Message: "machine not provisioned", Code: params.CodeNotProvisioned}},
+			{Error: &params.Error{Message: "resizing container machine 0/lxd/0 not supported", Code: params.CodeNotSupported}},
+			{Error: &params.Error{Message: `"application-foo" is not a valid machine tag`}},
+		},
+	})
+
+	// The instance type conflicts with the new constraints, so is
+	// dropped, and the machine's architecture is kept.
+	env.CheckCalls(c, []jtesting.StubCall{
+		{"ResizeInstance", []interface{}{instance.Id("i-0"), constraints.MustParse("arch=amd64 root-disk=20G mem=16G cores=4")}},
+	})
+	m0 := s.st.machines["0"]
+	c.Assert(m0.constraints, jc.DeepEquals, constraints.MustParse("root-disk=20G mem=16G cores=4"))
+	c.Assert(m0.hardware, jc.DeepEquals, instance.HardwareCharacteristics{Mem: &mem, CpuCores: &cores})
+}
+
+func (s *MachineManagerSuite) TestResizeMachinesCloudFailure(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{instanceId: "i-0"}
+	env := &mockResizerEnviron{}
+	env.SetErrors(errors.New("boom"))
+
+	results, err := machinemanager.ResizeMachines(s.api, s.environGetter(env), params.ResizeMachineArgs{
+		Args: []params.ResizeMachineArg{{MachineTag: "machine-0", Constraints: constraints.MustParse("mem=16G")}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `resizing instance "i-0": boom`)
+	s.st.machines["0"].CheckCallNames(c, "InstanceId", "Constraints", "HardwareCharacteristics")
+}
+
+func (s *MachineManagerSuite) TestResizeMachinesNotSupported(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{instanceId: "i-0"}
+	env := &mockProtectorEnviron{}
+
+	results, err := machinemanager.ResizeMachines(s.api, s.environGetter(env), params.ResizeMachineArgs{
+		Args: []params.ResizeMachineArg{{MachineTag: "machine-0", Constraints: constraints.MustParse("mem=16G")}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `resizing machines in this cloud not supported`)
+}
@@ -9,11 +9,14 @@ import (
 	"github.com/juju/errors"
 	"gopkg.in/juju/names.v3"
 
+	"github.com/juju/juju/apiserver/common/networkingcommon"
 	"github.com/juju/juju/apiserver/common/storagecommon"
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/network/netplan"
 	"github.com/juju/juju/state"
 )
 
@@ -23,6 +26,7 @@ type Backend interface {
 
 	Machine(string) (Machine, error)
 	Model() (Model, error)
+	ModelConstraints() (constraints.Value, error)
 	GetBlockForType(t state.BlockType) (state.Block, bool, error)
 	AddOneMachine(template state.MachineTemplate) (*state.Machine, error)
 	AddMachineInsideNewMachine(template, parentTemplate state.MachineTemplate, containerType instance.ContainerType) (*state.Machine, error)
@@ -48,6 +52,12 @@ type Machine interface {
 	Series() string
 	Units() ([]Unit, error)
 	SetKeepInstance(keepInstance bool) error
+	IsProtected() bool
+	SetProtected(protected bool) error
+	InstanceId() (instance.Id, error)
+	Constraints() (constraints.Value, error)
+	HardwareCharacteristics() (*instance.HardwareCharacteristics, error)
+	SetInstanceResized(constraints.Value, instance.HardwareCharacteristics) error
 	CreateUpgradeSeriesLock([]string, string) error
 	RemoveUpgradeSeriesLock() error
 	CompleteUpgradeSeries() error
@@ -56,6 +66,8 @@ type Machine interface {
 	WatchUpgradeSeriesNotifications() (state.NotifyWatcher, error)
 	GetUpgradeSeriesMessages() ([]string, bool, error)
 	IsManager() bool
+	NetplanDevices() ([]netplan.DeviceConfig, error)
+	NetplanStatus() (state.MachineNetplanStatus, error)
 }
 
 type stateShim struct {
@@ -109,6 +121,10 @@ type Unit interface {
 	Status() (status.StatusInfo, error)
 }
 
+func (m machineShim) NetplanDevices() ([]netplan.DeviceConfig, error) {
+	return networkingcommon.MachineNetplanDevices(m.Machine)
+}
+
 func (m machineShim) VerifyUnitsSeries(unitNames []string, series string, force bool) ([]Unit, error) {
 	units, err := m.Machine.VerifyUnitsSeries(unitNames, series, force)
 	if err != nil {
@@ -8,6 +8,8 @@ import (
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/apiserver/common/networkingcommon"
+	"github.com/juju/juju/apiserver/facades/agent/uniter"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/stateenvirons"
@@ -59,3 +61,56 @@ func (s *stateShim) SubnetByCIDR(cidr string) (networkingcommon.BackingSubnet, e
 	}
 	return networkingcommon.NewSubnetShim(result), nil
 }
+
+// UnitEndpointNetworkInfo returns the network information reported by
+// network-get for the given unit endpoint, using the same logic as the
+// uniter facade.
+func (s *stateShim) UnitEndpointNetworkInfo(tag names.UnitTag, endpoint string) (params.UnitEndpointNetworkInfoResult, error) {
+	var result params.UnitEndpointNetworkInfoResult
+	unit, err := s.State.Unit(tag.Id())
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	app, err := unit.Application()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	bindings, err := app.EndpointBindings()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	spaceID, ok := bindings.Map()[endpoint]
+	if !ok {
+		return result, errors.NotFoundf("endpoint %q of unit %q", endpoint, unit.Name())
+	}
+	space, err := s.State.Space(spaceID)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if unit.ShouldBeAssigned() {
+		machineId, err := unit.AssignedMachineId()
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		result.MachineTag = names.NewMachineTag(machineId).String()
+	}
+
+	netInfo, err := uniter.NewNetworkInfo(s.State, tag)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	infos, err := netInfo.ProcessAPIRequest(params.NetworkInfoParams{
+		Unit:      tag.String(),
+		Endpoints: []string{endpoint},
+	})
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	info := infos.Results[endpoint]
+	if info.Error != nil {
+		return result, info.Error
+	}
+	result.SpaceName = space.Name()
+	result.Info = info
+	return result, nil
+}
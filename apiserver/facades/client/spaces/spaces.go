@@ -46,6 +46,10 @@ type Backing interface {
 
 	// ReloadSpaces loads spaces from backing environ.
 	ReloadSpaces(environ environs.BootstrapEnviron) error
+
+	// UnitEndpointNetworkInfo returns the network information reported
+	// by network-get for the given unit endpoint.
+	UnitEndpointNetworkInfo(unit names.UnitTag, endpoint string) (params.UnitEndpointNetworkInfoResult, error)
 }
 
 // APIv2 provides the spaces API facade for versions < 3.
@@ -60,10 +64,15 @@ type APIv3 struct {
 
 // APIv4 provides the spaces API facade for version 4.
 type APIv4 struct {
+	*APIv5
+}
+
+// APIv5 provides the spaces API facade for version 5.
+type APIv5 struct {
 	*API
 }
 
-// API provides the spaces API facade for version 5.
+// API provides the spaces API facade for version 6.
 type API struct {
 	backing    Backing
 	resources  facade.Resources
@@ -93,13 +102,22 @@ func NewAPIv3(st *state.State, res facade.Resources, auth facade.Authorizer) (*A
 
 // NewAPIv4 is a wrapper that creates a V4 spaces API.
 func NewAPIv4(st *state.State, res facade.Resources, auth facade.Authorizer) (*APIv4, error) {
-	api, err := NewAPI(st, res, auth)
+	api, err := NewAPIv5(st, res, auth)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return &APIv4{api}, nil
 }
 
+// NewAPIv5 is a wrapper that creates a V5 spaces API.
+func NewAPIv5(st *state.State, res facade.Resources, auth facade.Authorizer) (*APIv5, error) {
+	api, err := NewAPI(st, res, auth)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv5{api}, nil
+}
+
 // NewAPI creates a new Space API server-side facade with a
 // state.State backing.
 func NewAPI(st *state.State, res facade.Resources, auth facade.Authorizer) (*API, error) {
@@ -313,6 +331,41 @@ func (api *API) ReloadSpaces() error {
 	return errors.Trace(api.backing.ReloadSpaces(env))
 }
 
+// UnitEndpointsNetworkInfo is not available via the V5 API.
+func (u *APIv5) UnitEndpointsNetworkInfo(_, _ struct{}) {}
+
+// UnitEndpointsNetworkInfo returns, for each of the given unit
+// endpoints, the network information the unit would see when running
+// network-get for that endpoint, along with the hosting machine and the
+// space the endpoint is bound to.
+func (api *API) UnitEndpointsNetworkInfo(args params.UnitEndpoints) (params.UnitEndpointNetworkInfoResults, error) {
+	results := params.UnitEndpointNetworkInfoResults{
+		Results: make([]params.UnitEndpointNetworkInfoResult, len(args.Entries)),
+	}
+	canRead, err := api.authorizer.HasPermission(permission.ReadAccess, api.backing.ModelTag())
+	if err != nil && !errors.IsNotFound(err) {
+		return results, errors.Trace(err)
+	}
+	if !canRead {
+		return results, common.ServerError(common.ErrPerm)
+	}
+
+	for i, entry := range args.Entries {
+		unitTag, err := names.ParseUnitTag(entry.UnitTag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result, err := api.backing.UnitEndpointNetworkInfo(unitTag, entry.Endpoint)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
 // checkSupportsSpaces checks if the environment implements NetworkingEnviron
 // and also if it supports spaces.
 func (api *API) checkSupportsSpaces() error {
@@ -452,7 +452,7 @@ func (s *SpacesSuite) TestCreateSpacesBlocked(c *gc.C) {
 }
 
 func (s *SpacesSuite) TestCreateSpacesAPIv4(c *gc.C) {
-	apiV4 := &spaces.APIv4{s.facade}
+	apiV4 := &spaces.APIv4{&spaces.APIv5{s.facade}}
 	results, err := apiV4.CreateSpaces(params.CreateSpacesParamsV4{
 		Spaces: []params.CreateSpaceParamsV4{
 			{
@@ -467,7 +467,7 @@ func (s *SpacesSuite) TestCreateSpacesAPIv4(c *gc.C) {
 }
 
 func (s *SpacesSuite) TestCreateSpacesAPIv4FailCIDR(c *gc.C) {
-	apiV4 := &spaces.APIv4{s.facade}
+	apiV4 := &spaces.APIv4{&spaces.APIv5{s.facade}}
 	results, err := apiV4.CreateSpaces(params.CreateSpacesParamsV4{
 		Spaces: []params.CreateSpaceParamsV4{
 			{
@@ -482,7 +482,7 @@ func (s *SpacesSuite) TestCreateSpacesAPIv4FailCIDR(c *gc.C) {
 }
 
 func (s *SpacesSuite) TestCreateSpacesAPIv4FailTag(c *gc.C) {
-	apiV4 := &spaces.APIv4{s.facade}
+	apiV4 := &spaces.APIv4{&spaces.APIv5{s.facade}}
 	results, err := apiV4.CreateSpaces(params.CreateSpacesParamsV4{
 		Spaces: []params.CreateSpaceParamsV4{
 			{
@@ -567,6 +567,57 @@ func (s *SpacesSuite) TestSuppportsSpaces(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *SpacesSuite) TestUnitEndpointsNetworkInfo(c *gc.C) {
+	results, err := s.facade.UnitEndpointsNetworkInfo(params.UnitEndpoints{
+		Entries: []params.UnitEndpoint{
+			{UnitTag: "unit-mysql-1", Endpoint: "db"},
+			{UnitTag: "unit-mysql-1", Endpoint: "admin"},
+			{UnitTag: "machine-1", Endpoint: "db"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0], jc.DeepEquals, params.UnitEndpointNetworkInfoResult{
+		MachineTag: "machine-1",
+		SpaceName:  "dmz",
+		Info: params.NetworkInfoResult{
+			Info: []params.NetworkInfo{{
+				InterfaceName: "eth0",
+				Addresses: []params.InterfaceAddress{{
+					Address: "10.10.0.11",
+					CIDR:    "10.10.0.0/24",
+				}},
+			}},
+			EgressSubnets:    []string{"10.10.0.11/32"},
+			IngressAddresses: []string{"10.10.0.11"},
+		},
+	})
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `endpoint "admin" of unit "mysql/1" not found`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `"machine-1" is not a valid unit tag`)
+
+	apiservertesting.CheckMethodCalls(c, apiservertesting.SharedStub,
+		apiservertesting.BackingCall("UnitEndpointNetworkInfo", names.NewUnitTag("mysql/1"), "db"),
+		apiservertesting.BackingCall("UnitEndpointNetworkInfo", names.NewUnitTag("mysql/1"), "admin"),
+	)
+}
+
+func (s *SpacesSuite) TestUnitEndpointsNetworkInfoUserDenied(c *gc.C) {
+	authorizer := s.authorizer
+	authorizer.Tag = names.NewUserTag("regular")
+	facade, err := spaces.NewAPIWithBacking(
+		apiservertesting.BackingInstance,
+		&s.blockChecker,
+		context.NewCloudCallContext(),
+		s.resources, authorizer,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = facade.UnitEndpointsNetworkInfo(params.UnitEndpoints{
+		Entries: []params.UnitEndpoint{{UnitTag: "unit-mysql-1", Endpoint: "db"}},
+	})
+	c.Check(err, gc.ErrorMatches, "permission denied")
+	apiservertesting.CheckMethodCalls(c, apiservertesting.SharedStub)
+}
+
 type mockBlockChecker struct {
 	jtesting.Stub
 }
@@ -44,6 +44,8 @@ type baseStorageSuite struct {
 	filesystemTag        names.FilesystemTag
 	filesystem           *mockFilesystem
 	filesystemAttachment *mockFilesystemAttachment
+	storageSnapshots     []state.StorageSnapshot
+	storageMigrations    []state.StorageMigration
 	stub                 testing.Stub
 
 	registry    jujustorage.StaticProviderRegistry
@@ -75,7 +77,13 @@ func (s *baseStorageSuite) SetUpTest(c *gc.C) {
 	s.apiv3 = &storage.StorageAPIv3{
 		StorageAPIv4: storage.StorageAPIv4{
 			StorageAPIv5: storage.StorageAPIv5{
-				StorageAPI: *newAPI,
+				StorageAPIv6: storage.StorageAPIv6{
+					StorageAPIv7: storage.StorageAPIv7{
+						StorageAPIv8: storage.StorageAPIv8{
+							StorageAPI: *newAPI,
+						},
+					},
+				},
 			},
 		},
 	}
@@ -111,6 +119,11 @@ const (
 	detachStorageCall                       = "detachStorage"
 	destroyStorageInstanceCall              = "destroyStorageInstance"
 	releaseStorageInstanceCall              = "releaseStorageInstance"
+	resizeStorageInstanceCall               = "resizeStorageInstance"
+	createStorageSnapshotCall               = "createStorageSnapshot"
+	allStorageSnapshotsCall                 = "allStorageSnapshots"
+	migrateStorageCall                      = "migrateStorage"
+	allStorageMigrationsCall                = "allStorageMigrations"
 	addExistingFilesystemCall               = "addExistingFilesystem"
 )
 
@@ -320,6 +333,32 @@ func (s *baseStorageSuite) constructStorageAccessor() *mockStorageAccessor {
 			s.stub.AddCall(addExistingFilesystemCall, f, v, storageName)
 			return s.storageTag, s.stub.NextErr()
 		},
+		resizeStorageInstance: func(tag names.StorageTag, size uint64) error {
+			s.stub.AddCall(resizeStorageInstanceCall, tag, size)
+			return s.stub.NextErr()
+		},
+		createStorageSnapshot: func(tag names.StorageTag) (state.StorageSnapshot, error) {
+			s.stub.AddCall(createStorageSnapshotCall, tag)
+			if err := s.stub.NextErr(); err != nil {
+				return nil, err
+			}
+			return &mockStorageSnapshot{id: "0/0", storageTag: tag}, nil
+		},
+		allStorageSnapshots: func() ([]state.StorageSnapshot, error) {
+			s.stub.AddCall(allStorageSnapshotsCall)
+			return s.storageSnapshots, s.stub.NextErr()
+		},
+		migrateStorage: func(tag names.StorageTag, pool string) (state.StorageMigration, error) {
+			s.stub.AddCall(migrateStorageCall, tag, pool)
+			if err := s.stub.NextErr(); err != nil {
+				return nil, err
+			}
+			return &mockStorageMigration{id: "0/0", storageTag: tag, targetPool: pool}, nil
+		},
+		allStorageMigrations: func() ([]state.StorageMigration, error) {
+			s.stub.AddCall(allStorageMigrationsCall)
+			return s.storageMigrations, s.stub.NextErr()
+		},
 	}
 }
 
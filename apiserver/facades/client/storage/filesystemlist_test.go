@@ -4,6 +4,8 @@
 package storage_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -146,6 +148,35 @@ func (s *filesystemSuite) TestListFilesystemsAttachmentInfo(c *gc.C) {
 	c.Assert(found.Results[0].Result[0], jc.DeepEquals, expected)
 }
 
+func (s *filesystemSuite) TestListFilesystemsAttachmentUsage(c *gc.C) {
+	updated := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	s.filesystemAttachment.usage = &state.FilesystemUsage{
+		UsedBytes:  1024,
+		FreeBytes:  3072,
+		UsedInodes: 10,
+		FreeInodes: 90,
+		Updated:    updated,
+	}
+	expected := s.expectedFilesystemDetails()
+	expected.MachineAttachments[s.machineTag.String()] = params.FilesystemAttachmentDetails{
+		Life: "dead",
+		Usage: &params.FilesystemUsage{
+			UsedBytes:  1024,
+			FreeBytes:  3072,
+			UsedInodes: 10,
+			FreeInodes: 90,
+			Updated:    &updated,
+		},
+	}
+	found, err := s.api.ListFilesystems(params.FilesystemFilters{
+		[]params.FilesystemFilter{{}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Results, gc.HasLen, 1)
+	c.Assert(found.Results[0].Result, gc.HasLen, 1)
+	c.Assert(found.Results[0].Result[0], jc.DeepEquals, expected)
+}
+
 func (s *filesystemSuite) TestListFilesystemsVolumeBacked(c *gc.C) {
 	s.filesystem.volume = &s.volumeTag
 	expected := s.expectedFilesystemDetails()
@@ -71,6 +71,11 @@ type mockStorageAccessor struct {
 	attachStorage                       func(names.StorageTag, names.UnitTag) error
 	detachStorage                       func(names.StorageTag, names.UnitTag, bool) error
 	addExistingFilesystem               func(state.FilesystemInfo, *state.VolumeInfo, string) (names.StorageTag, error)
+	resizeStorageInstance               func(names.StorageTag, uint64) error
+	createStorageSnapshot               func(names.StorageTag) (state.StorageSnapshot, error)
+	allStorageSnapshots                 func() ([]state.StorageSnapshot, error)
+	migrateStorage                      func(names.StorageTag, string) (state.StorageMigration, error)
+	allStorageMigrations                func() ([]state.StorageMigration, error)
 }
 
 func (st *mockStorageAccessor) VolumeAccess() storage.StorageVolume {
@@ -181,6 +186,26 @@ func (st *mockStorageAccessor) ReleaseStorageInstance(tag names.StorageTag, dest
 	return st.releaseStorageInstance(tag, destroyAttached, force)
 }
 
+func (st *mockStorageAccessor) ResizeStorageInstance(tag names.StorageTag, size uint64) error {
+	return st.resizeStorageInstance(tag, size)
+}
+
+func (st *mockStorageAccessor) CreateStorageSnapshot(tag names.StorageTag) (state.StorageSnapshot, error) {
+	return st.createStorageSnapshot(tag)
+}
+
+func (st *mockStorageAccessor) AllStorageSnapshots() ([]state.StorageSnapshot, error) {
+	return st.allStorageSnapshots()
+}
+
+func (st *mockStorageAccessor) MigrateStorage(tag names.StorageTag, pool string) (state.StorageMigration, error) {
+	return st.migrateStorage(tag, pool)
+}
+
+func (st *mockStorageAccessor) AllStorageMigrations() ([]state.StorageMigration, error) {
+	return st.allStorageMigrations()
+}
+
 func (st *mockStorageAccessor) UnitStorageAttachments(tag names.UnitTag) ([]state.StorageAttachment, error) {
 	panic("should not be called")
 }
@@ -277,6 +302,7 @@ type mockFilesystemAttachment struct {
 	filesystem names.FilesystemTag
 	machine    names.MachineTag
 	info       *state.FilesystemAttachmentInfo
+	usage      *state.FilesystemUsage
 	life       state.Life
 }
 
@@ -295,6 +321,13 @@ func (m *mockFilesystemAttachment) Info() (state.FilesystemAttachmentInfo, error
 	return state.FilesystemAttachmentInfo{}, errors.NotProvisionedf("filesystem attachment")
 }
 
+func (m *mockFilesystemAttachment) Usage() (state.FilesystemUsage, bool) {
+	if m.usage != nil {
+		return *m.usage, true
+	}
+	return state.FilesystemUsage{}, false
+}
+
 func (m *mockFilesystemAttachment) Life() state.Life {
 	return m.life
 }
@@ -464,3 +497,106 @@ func (st *mockState) Unit(unitName string) (storage.Unit, error) {
 	}
 	return nil, errors.NotFoundf(unitName)
 }
+
+type mockStorageSnapshot struct {
+	state.StorageSnapshot
+	id         string
+	storageTag names.StorageTag
+	volumeTag  names.VolumeTag
+	pool       string
+	size       uint64
+	snapshotId string
+	status     state.StorageSnapshotStatus
+	message    string
+	created    time.Time
+}
+
+func (m *mockStorageSnapshot) Id() string {
+	return m.id
+}
+
+func (m *mockStorageSnapshot) StorageTag() names.StorageTag {
+	return m.storageTag
+}
+
+func (m *mockStorageSnapshot) VolumeTag() names.VolumeTag {
+	return m.volumeTag
+}
+
+func (m *mockStorageSnapshot) Pool() string {
+	return m.pool
+}
+
+func (m *mockStorageSnapshot) Size() uint64 {
+	return m.size
+}
+
+func (m *mockStorageSnapshot) SnapshotId() (string, error) {
+	if m.snapshotId == "" {
+		return "", errors.NotProvisionedf("storage snapshot %q", m.id)
+	}
+	return m.snapshotId, nil
+}
+
+func (m *mockStorageSnapshot) Status() (state.StorageSnapshotStatus, string) {
+	return m.status, m.message
+}
+
+func (m *mockStorageSnapshot) Created() time.Time {
+	return m.created
+}
+
+type mockStorageMigration struct {
+	state.StorageMigration
+	id           string
+	storageTag   names.StorageTag
+	machineTag   names.MachineTag
+	sourceVolume names.VolumeTag
+	sourcePool   string
+	targetVolume names.VolumeTag
+	targetPool   string
+	status       state.StorageMigrationStatus
+	message      string
+	created      time.Time
+	completed    time.Time
+}
+
+func (m *mockStorageMigration) Id() string {
+	return m.id
+}
+
+func (m *mockStorageMigration) StorageTag() names.StorageTag {
+	return m.storageTag
+}
+
+func (m *mockStorageMigration) Machine() names.MachineTag {
+	return m.machineTag
+}
+
+func (m *mockStorageMigration) SourceVolume() names.VolumeTag {
+	return m.sourceVolume
+}
+
+func (m *mockStorageMigration) SourcePool() string {
+	return m.sourcePool
+}
+
+func (m *mockStorageMigration) TargetVolume() names.VolumeTag {
+	return m.targetVolume
+}
+
+func (m *mockStorageMigration) TargetPool() string {
+	return m.targetPool
+}
+
+func (m *mockStorageMigration) Status() (state.StorageMigrationStatus, string) {
+	return m.status, m.message
+}
+
+func (m *mockStorageMigration) Created() time.Time {
+	return m.created
+}
+
+func (m *mockStorageMigration) Completed() time.Time {
+	return m.completed
+}
@@ -61,6 +61,24 @@ type storageInterface interface {
 
 	// ReleaseStorageInstance releases the storage instance with the specified tag.
 	ReleaseStorageInstance(names.StorageTag, bool, bool, time.Duration) error
+
+	// ResizeStorageInstance requests that the storage instance with the
+	// specified tag be grown to the given size in MiB.
+	ResizeStorageInstance(names.StorageTag, uint64) error
+
+	// CreateStorageSnapshot requests a snapshot of the volume backing
+	// the storage instance with the specified tag.
+	CreateStorageSnapshot(names.StorageTag) (state.StorageSnapshot, error)
+
+	// AllStorageSnapshots returns all storage snapshots in the model.
+	AllStorageSnapshots() ([]state.StorageSnapshot, error)
+
+	// MigrateStorage requests that the storage instance with the
+	// specified tag be migrated to a new volume in the named pool.
+	MigrateStorage(names.StorageTag, string) (state.StorageMigration, error)
+
+	// AllStorageMigrations returns all storage migrations in the model.
+	AllStorageMigrations() ([]state.StorageMigration, error)
 }
 
 type storageVolume interface {
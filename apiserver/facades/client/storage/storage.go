@@ -29,7 +29,7 @@ import (
 	"github.com/juju/juju/storage/poolmanager"
 )
 
-// StorageAPI implements the latest version (v6) of the Storage API.
+// StorageAPI implements the latest version (v9) of the Storage API.
 type StorageAPI struct {
 	backend       backend
 	storageAccess storageAccess
@@ -40,9 +40,24 @@ type StorageAPI struct {
 	modelType     state.ModelType
 }
 
+// StorageAPIv8 implements the storage v8 API.
+type StorageAPIv8 struct {
+	StorageAPI
+}
+
+// StorageAPIv7 implements the storage v7 API.
+type StorageAPIv7 struct {
+	StorageAPIv8
+}
+
+// StorageAPIv6 implements the storage v6 API.
+type StorageAPIv6 struct {
+	StorageAPIv7
+}
+
 // APIv5 implements the storage v5 API.
 type StorageAPIv5 struct {
-	StorageAPI
+	StorageAPIv6
 }
 
 // APIv4 implements the storage v4 API adding AddToUnit, Import and Remove (replacing Destroy)
@@ -103,14 +118,47 @@ func newStorageAPI(
 	}
 }
 
+// NewStorageAPIV8 returns a new storage v8 API facade.
+func NewStorageAPIV8(context facade.Context) (*StorageAPIv8, error) {
+	storageAPI, err := NewStorageAPI(context)
+	if err != nil {
+		return nil, err
+	}
+	return &StorageAPIv8{
+		StorageAPI: *storageAPI,
+	}, nil
+}
+
+// NewStorageAPIV7 returns a new storage v7 API facade.
+func NewStorageAPIV7(context facade.Context) (*StorageAPIv7, error) {
+	storageAPI, err := NewStorageAPIV8(context)
+	if err != nil {
+		return nil, err
+	}
+	return &StorageAPIv7{
+		StorageAPIv8: *storageAPI,
+	}, nil
+}
+
+// NewStorageAPIV6 returns a new storage v6 API facade.
+func NewStorageAPIV6(context facade.Context) (*StorageAPIv6, error) {
+	storageAPI, err := NewStorageAPIV7(context)
+	if err != nil {
+		return nil, err
+	}
+	return &StorageAPIv6{
+		StorageAPIv7: *storageAPI,
+	}, nil
+}
+
 // NewStorageAPIV5 returns a new storage v5 API facade.
 func NewStorageAPIV5(context facade.Context) (*StorageAPIv5, error) {
-	storageAPI, err := NewStorageAPI(context)
+	storageAPI, err := NewStorageAPIV6(context)
 	if err != nil {
 		return nil, err
 	}
 	return &StorageAPIv5{
-		StorageAPI: *storageAPI,
+		StorageAPIv6: *storageAPI,
 	}, nil
 }
 
@@ -244,7 +292,7 @@ func createStorageDetails(
 	si state.StorageInstance,
 ) (*params.StorageDetails, error) {
 	// Get information from underlying volume or filesystem.
-	var persistent bool
+	var persistent, encrypted bool
 	var statusEntity status.StatusGetter
 	if si.Kind() == state.StorageKindFilesystem {
 		stFile := st.FilesystemAccess()
@@ -259,6 +307,20 @@ func createStorageDetails(
 			return nil, errors.Trace(err)
 		}
 		statusEntity = filesystem
+		// A filesystem is encrypted if its backing volume is.
+		if volumeTag, err := filesystem.Volume(); err == nil {
+			if stVolume := st.VolumeAccess(); stVolume != nil {
+				volume, err := stVolume.Volume(volumeTag)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				if info, err := volume.Info(); err == nil {
+					encrypted = info.Encrypted
+				}
+			}
+		} else if err != state.ErrNoBackingVolume {
+			return nil, errors.Trace(err)
+		}
 	} else {
 		stVolume := st.VolumeAccess()
 		if stVolume == nil {
@@ -270,6 +332,7 @@ func createStorageDetails(
 		}
 		if info, err := volume.Info(); err == nil {
 			persistent = info.Persistent
+			encrypted = info.Encrypted
 		}
 		statusEntity = volume
 	}
@@ -317,6 +380,7 @@ func createStorageDetails(
 		Life:        params.Life(si.Life().String()),
 		Status:      common.EntityStatusFromState(aStatus),
 		Persistent:  persistent,
+		Encrypted:   encrypted,
 		Attachments: storageAttachmentDetails,
 	}, nil
 }
@@ -808,6 +872,10 @@ func createFilesystemDetails(
 					stateInfo,
 				)
 			}
+			if usage, ok := attachment.Usage(); ok {
+				usageDetails := storagecommon.FilesystemUsageFromState(usage)
+				attDetails.Usage = &usageDetails
+			}
 			if attachment.Host().Kind() == names.MachineTagKind {
 				details.MachineAttachments[attachment.Host().String()] = attDetails
 			} else {
@@ -1208,10 +1276,257 @@ func (a *StorageAPI) UpdatePool(p params.StoragePoolArgs) (params.ErrorResults,
 	return results, nil
 }
 
+// ResizeStorage grows the volumes or filesystems assigned to the
+// specified storage instances. The resize is carried out by the
+// storage provisioner; storage-resized hooks are run for attached
+// units once it completes.
+// A "CHANGE" block can block this operation.
+func (a *StorageAPI) ResizeStorage(args params.ResizeStorageParams) (params.ErrorResults, error) {
+	if err := a.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+
+	blockChecker := common.NewBlockChecker(a.backend)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+
+	result := make([]params.ErrorResult, len(args.Storage))
+	for i, arg := range args.Storage {
+		result[i].Error = common.ServerError(a.resizeStorage(arg))
+	}
+	return params.ErrorResults{Results: result}, nil
+}
+
+func (a *StorageAPI) resizeStorage(arg params.ResizeStorageArg) error {
+	tag, err := names.ParseStorageTag(arg.StorageTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := a.checkResizeSupported(tag); err != nil {
+		return errors.Trace(err)
+	}
+	return a.storageAccess.ResizeStorageInstance(tag, arg.Size)
+}
+
+// checkResizeSupported returns an error satisfying errors.IsNotSupported
+// if the provider of the storage with the given tag is known not to
+// support resizing. Machine-scoped providers cannot be checked from the
+// controller, so the storage provisioner reports any failure for those.
+func (a *StorageAPI) checkResizeSupported(tag names.StorageTag) error {
+	si, err := a.storageAccess.StorageInstance(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var pool string
+	var resizeVolume bool
+	switch si.Kind() {
+	case state.StorageKindBlock:
+		v, err := a.storageAccess.VolumeAccess().StorageInstanceVolume(tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		info, err := v.Info()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		pool, resizeVolume = info.Pool, true
+	case state.StorageKindFilesystem:
+		f, err := a.storageAccess.FilesystemAccess().StorageInstanceFilesystem(tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		info, err := f.Info()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		pool = info.Pool
+		_, err = f.Volume()
+		resizeVolume = err == nil
+	default:
+		return errors.NotSupportedf("resizing %s storage", si.Kind())
+	}
+
+	providerType, cfg, err := storagecommon.StoragePoolConfig(pool, a.poolManager, a.registry)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	provider, err := a.registry.StorageProvider(providerType)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if provider.Scope() != storage.ScopeEnviron {
+		return nil
+	}
+	if resizeVolume {
+		source, err := provider.VolumeSource(cfg)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if _, ok := source.(storage.VolumeResizer); !ok {
+			return errors.NotSupportedf("resizing %q volumes", providerType)
+		}
+		return nil
+	}
+	source, err := provider.FilesystemSource(cfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := source.(storage.FilesystemResizer); !ok {
+		return errors.NotSupportedf("resizing %q filesystems", providerType)
+	}
+	return nil
+}
+
+// CreateStorageSnapshots requests point-in-time snapshots of the
+// volumes backing the specified storage instances. The snapshots are
+// created asynchronously by the storage provisioner; the result for
+// each storage instance holds the ID of the new storage snapshot.
+// A "CHANGE" block can block this operation.
+func (a *StorageAPI) CreateStorageSnapshots(args params.Entities) (params.StorageSnapshotResults, error) {
+	if err := a.checkCanWrite(); err != nil {
+		return params.StorageSnapshotResults{}, errors.Trace(err)
+	}
+
+	blockChecker := common.NewBlockChecker(a.backend)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.StorageSnapshotResults{}, errors.Trace(err)
+	}
+
+	result := make([]params.StorageSnapshotResult, len(args.Entities))
+	for i, arg := range args.Entities {
+		tag, err := names.ParseStorageTag(arg.Tag)
+		if err != nil {
+			result[i].Error = common.ServerError(err)
+			continue
+		}
+		snapshot, err := a.storageAccess.CreateStorageSnapshot(tag)
+		if err != nil {
+			result[i].Error = common.ServerError(err)
+			continue
+		}
+		result[i].Id = snapshot.Id()
+	}
+	return params.StorageSnapshotResults{Results: result}, nil
+}
+
+// ListStorageSnapshots returns the details of all storage snapshots
+// in the model.
+func (a *StorageAPI) ListStorageSnapshots() (params.StorageSnapshotDetailsResults, error) {
+	if err := a.checkCanRead(); err != nil {
+		return params.StorageSnapshotDetailsResults{}, errors.Trace(err)
+	}
+	snapshots, err := a.storageAccess.AllStorageSnapshots()
+	if err != nil {
+		return params.StorageSnapshotDetailsResults{}, errors.Trace(err)
+	}
+	result := make([]params.StorageSnapshotDetails, len(snapshots))
+	for i, snapshot := range snapshots {
+		status, message := snapshot.Status()
+		// The provider ID is only available once
+		// the snapshot has been created.
+		snapshotId, _ := snapshot.SnapshotId()
+		result[i] = params.StorageSnapshotDetails{
+			Id:         snapshot.Id(),
+			StorageTag: snapshot.StorageTag().String(),
+			VolumeTag:  snapshot.VolumeTag().String(),
+			Pool:       snapshot.Pool(),
+			Size:       snapshot.Size(),
+			SnapshotId: snapshotId,
+			Status:     string(status),
+			Message:    message,
+			Created:    snapshot.Created(),
+		}
+	}
+	return params.StorageSnapshotDetailsResults{Results: result}, nil
+}
+
+// MigrateStorage requests that the specified storage instances be
+// migrated to new volumes in other storage pools. The new volumes are
+// provisioned and attached alongside the existing ones; once the units
+// using the storage have stopped using it, the data is copied across by
+// the machine's storage provisioner, after which the storage instance
+// is backed by the new volume. The result for
+// each storage instance holds the ID of the new storage migration.
+// A "CHANGE" block can block this operation.
+func (a *StorageAPI) MigrateStorage(args params.StorageMigrationArgs) (params.StorageMigrationResults, error) {
+	if err := a.checkCanWrite(); err != nil {
+		return params.StorageMigrationResults{}, errors.Trace(err)
+	}
+
+	blockChecker := common.NewBlockChecker(a.backend)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.StorageMigrationResults{}, errors.Trace(err)
+	}
+
+	if a.modelType == state.ModelTypeCAAS {
+		return params.StorageMigrationResults{}, errors.NotSupportedf("migrating storage on a k8s model")
+	}
+
+	result := make([]params.StorageMigrationResult, len(args.Args))
+	for i, arg := range args.Args {
+		tag, err := names.ParseStorageTag(arg.StorageTag)
+		if err != nil {
+			result[i].Error = common.ServerError(err)
+			continue
+		}
+		migration, err := a.storageAccess.MigrateStorage(tag, arg.Pool)
+		if err != nil {
+			result[i].Error = common.ServerError(err)
+			continue
+		}
+		result[i].Id = migration.Id()
+	}
+	return params.StorageMigrationResults{Results: result}, nil
+}
+
+// ListStorageMigrations returns the details of all storage migrations
+// in the model, recording the provenance of migrated storage.
+func (a *StorageAPI) ListStorageMigrations() (params.StorageMigrationDetailsResults, error) {
+	if err := a.checkCanRead(); err != nil {
+		return params.StorageMigrationDetailsResults{}, errors.Trace(err)
+	}
+	migrations, err := a.storageAccess.AllStorageMigrations()
+	if err != nil {
+		return params.StorageMigrationDetailsResults{}, errors.Trace(err)
+	}
+	result := make([]params.StorageMigrationDetails, len(migrations))
+	for i, migration := range migrations {
+		status, message := migration.Status()
+		result[i] = params.StorageMigrationDetails{
+			Id:              migration.Id(),
+			StorageTag:      migration.StorageTag().String(),
+			MachineTag:      migration.Machine().String(),
+			SourceVolumeTag: migration.SourceVolume().String(),
+			SourcePool:      migration.SourcePool(),
+			TargetVolumeTag: migration.TargetVolume().String(),
+			TargetPool:      migration.TargetPool(),
+			Status:          string(status),
+			Message:         message,
+			Created:         migration.Created(),
+		}
+		if completed := migration.Completed(); !completed.IsZero() {
+			result[i].Completed = &completed
+		}
+	}
+	return params.StorageMigrationDetailsResults{Results: result}, nil
+}
+
 // Mask out old methods from the new API versions. The API reflection
 // code in rpc/rpcreflect/type.go:newMethod skips 2-argument methods,
 // so this removes the method as far as the RPC machinery is concerned.
 
+// Added in v9 api version
+func (*StorageAPIv8) MigrateStorage(_, _ struct{})        {}
+func (*StorageAPIv8) ListStorageMigrations(_, _ struct{}) {}
+
+// Added in v8 api version
+func (*StorageAPIv7) CreateStorageSnapshots(_, _ struct{}) {}
+func (*StorageAPIv7) ListStorageSnapshots(_, _ struct{})   {}
+
+// Added in v7 api version
+func (*StorageAPIv6) ResizeStorage(_, _ struct{}) {}
+
 // Added in v6 api version
 func (*StorageAPIv5) DetachStorage(_, _ struct{}) {}
 
@@ -5,6 +5,7 @@ package storage_test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/testing"
@@ -307,6 +308,20 @@ func (s *storageSuite) TestShowStorage(c *gc.C) {
 	c.Assert(one.Result, jc.DeepEquals, &expected)
 }
 
+func (s *storageSuite) TestShowStorageEncryptedBackingVolume(c *gc.C) {
+	s.filesystem.volume = &s.volumeTag
+	s.volume.info = &state.VolumeInfo{VolumeId: "vol-0", Encrypted: true}
+	entity := params.Entity{Tag: s.storageTag.String()}
+
+	found, err := s.api.StorageDetails(
+		params.Entities{Entities: []params.Entity{entity}},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Results, gc.HasLen, 1)
+	c.Assert(found.Results[0].Error, gc.IsNil)
+	c.Assert(found.Results[0].Result.Encrypted, jc.IsTrue)
+}
+
 func (s *storageSuite) TestShowStorageInvalidId(c *gc.C) {
 	storageTag := "foo"
 	entity := params.Entity{Tag: storageTag}
@@ -371,7 +386,13 @@ func (s *storageSuite) TestDestroyV3(c *gc.C) {
 
 func (s *storageSuite) TestDetachV5(c *gc.C) {
 	apiv5 := &facadestorage.StorageAPIv5{
-		StorageAPI: *s.api,
+		StorageAPIv6: facadestorage.StorageAPIv6{
+			StorageAPIv7: facadestorage.StorageAPIv7{
+				StorageAPIv8: facadestorage.StorageAPIv8{
+					StorageAPI: *s.api,
+				},
+			},
+		},
 	}
 	results, err := apiv5.Detach(params.StorageAttachmentIds{[]params.StorageAttachmentId{
 		{StorageTag: "storage-data-0", UnitTag: "unit-mysql-0"},
@@ -442,7 +463,13 @@ func (s *storageSuite) TestDetachV6(c *gc.C) {
 
 func (s *storageSuite) TestDetachSpecifiedNotFound(c *gc.C) {
 	apiv5 := &facadestorage.StorageAPIv5{
-		StorageAPI: *s.api,
+		StorageAPIv6: facadestorage.StorageAPIv6{
+			StorageAPIv7: facadestorage.StorageAPIv7{
+				StorageAPIv8: facadestorage.StorageAPIv8{
+					StorageAPI: *s.api,
+				},
+			},
+		},
 	}
 	results, err := apiv5.Detach(params.StorageAttachmentIds{[]params.StorageAttachmentId{
 		{StorageTag: "storage-data-0", UnitTag: "unit-foo-42"},
@@ -512,7 +539,13 @@ func (s *storageSuite) TestDetachAttachmentNotFoundConcurrentv5(c *gc.C) {
 		)
 	}
 	apiv5 := &facadestorage.StorageAPIv5{
-		StorageAPI: *s.api,
+		StorageAPIv6: facadestorage.StorageAPIv6{
+			StorageAPIv7: facadestorage.StorageAPIv7{
+				StorageAPIv8: facadestorage.StorageAPIv8{
+					StorageAPI: *s.api,
+				},
+			},
+		},
 	}
 	results, err := apiv5.Detach(params.StorageAttachmentIds{[]params.StorageAttachmentId{
 		{StorageTag: "storage-data-0"},
@@ -568,7 +601,13 @@ func (s *storageSuite) TestDetachAttachmentNotFoundConcurrentV6(c *gc.C) {
 
 func (s *storageSuite) TestDetachNoAttachmentsStorageNotFoundv5(c *gc.C) {
 	apiv5 := &facadestorage.StorageAPIv5{
-		StorageAPI: *s.api,
+		StorageAPIv6: facadestorage.StorageAPIv6{
+			StorageAPIv7: facadestorage.StorageAPIv7{
+				StorageAPIv8: facadestorage.StorageAPIv8{
+					StorageAPI: *s.api,
+				},
+			},
+		},
 	}
 	results, err := apiv5.Detach(params.StorageAttachmentIds{[]params.StorageAttachmentId{
 		{StorageTag: "storage-foo-42"},
@@ -877,6 +916,235 @@ func (s *storageSuite) TestListStorageAsNonAdminOnNotOwnedModel(c *gc.C) {
 	c.Assert(errors.Cause(err), gc.Equals, common.ErrPerm)
 }
 
+func (s *storageSuite) TestResizeStorage(c *gc.C) {
+	s.filesystem.info = &state.FilesystemInfo{Pool: "radiance", Size: 1024}
+	filesystemSource := filesystemResizer{&dummy.FilesystemSource{}}
+	s.registry.Providers["radiance"] = &dummy.StorageProvider{
+		StorageScope: storage.ScopeEnviron,
+		IsDynamic:    true,
+		FilesystemSourceFunc: func(*storage.Config) (storage.FilesystemSource, error) {
+			return filesystemSource, nil
+		},
+	}
+
+	results, err := s.api.ResizeStorage(params.ResizeStorageParams{[]params.ResizeStorageArg{
+		{StorageTag: "storage-data-0", Size: 2048},
+		{StorageTag: "volume-0", Size: 2048},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{
+		{Error: nil},
+		{Error: &params.Error{Message: `"volume-0" is not a valid storage tag`}},
+	})
+	s.stub.CheckCalls(c, []testing.StubCall{
+		{getBlockForTypeCall, []interface{}{state.ChangeBlock}},
+		{storageInstanceCall, []interface{}{s.storageTag}},
+		{storageInstanceFilesystemCall, nil},
+		{resizeStorageInstanceCall, []interface{}{s.storageTag, uint64(2048)}},
+	})
+}
+
+func (s *storageSuite) TestResizeStorageNotSupported(c *gc.C) {
+	s.filesystem.info = &state.FilesystemInfo{Pool: "radiance", Size: 1024}
+	s.registry.Providers["radiance"] = &dummy.StorageProvider{
+		StorageScope: storage.ScopeEnviron,
+		IsDynamic:    true,
+	}
+
+	results, err := s.api.ResizeStorage(params.ResizeStorageParams{[]params.ResizeStorageArg{
+		{StorageTag: "storage-data-0", Size: 2048},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{{
+		Error: &params.Error{
+			Code:    params.CodeNotSupported,
+			Message: `resizing "radiance" filesystems not supported`,
+		},
+	}})
+	s.stub.CheckCallNames(c, getBlockForTypeCall, storageInstanceCall, storageInstanceFilesystemCall)
+}
+
+func (s *storageSuite) TestResizeStorageBlocked(c *gc.C) {
+	s.blockAllChanges(c, "resizing")
+	_, err := s.api.ResizeStorage(params.ResizeStorageParams{[]params.ResizeStorageArg{
+		{StorageTag: "storage-data-0", Size: 2048},
+	}})
+	s.assertBlocked(c, err, "resizing")
+}
+
+func (s *storageSuite) TestCreateStorageSnapshots(c *gc.C) {
+	s.stub.SetErrors(nil, errors.NotSupportedf("snapshotting filesystem storage"))
+	results, err := s.api.CreateStorageSnapshots(params.Entities{[]params.Entity{
+		{Tag: "storage-data-0"},
+		{Tag: "volume-0"},
+		{Tag: "storage-data-1"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.StorageSnapshotResult{
+		{Id: "0/0"},
+		{Error: &params.Error{Message: `"volume-0" is not a valid storage tag`}},
+		{Error: &params.Error{
+			Code:    params.CodeNotSupported,
+			Message: "snapshotting filesystem storage not supported",
+		}},
+	})
+	s.stub.CheckCalls(c, []testing.StubCall{
+		{getBlockForTypeCall, []interface{}{state.ChangeBlock}},
+		{createStorageSnapshotCall, []interface{}{s.storageTag}},
+		{createStorageSnapshotCall, []interface{}{names.NewStorageTag("data/1")}},
+	})
+}
+
+func (s *storageSuite) TestCreateStorageSnapshotsBlocked(c *gc.C) {
+	s.blockAllChanges(c, "snapshotting")
+	_, err := s.api.CreateStorageSnapshots(params.Entities{[]params.Entity{
+		{Tag: "storage-data-0"},
+	}})
+	s.assertBlocked(c, err, "snapshotting")
+}
+
+func (s *storageSuite) TestListStorageSnapshots(c *gc.C) {
+	created := time.Date(2020, 2, 1, 10, 0, 0, 0, time.UTC)
+	s.storageSnapshots = []state.StorageSnapshot{
+		&mockStorageSnapshot{
+			id:         "0/0",
+			storageTag: s.storageTag,
+			volumeTag:  s.volumeTag,
+			pool:       "ebs",
+			size:       1024,
+			snapshotId: "snap-0123",
+			status:     state.StorageSnapshotAvailable,
+			created:    created,
+		},
+		&mockStorageSnapshot{
+			id:         "0/1",
+			storageTag: s.storageTag,
+			volumeTag:  s.volumeTag,
+			pool:       "ebs",
+			status:     state.StorageSnapshotError,
+			message:    "oops",
+			created:    created,
+		},
+	}
+	results, err := s.api.ListStorageSnapshots()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.StorageSnapshotDetails{{
+		Id:         "0/0",
+		StorageTag: s.storageTag.String(),
+		VolumeTag:  s.volumeTag.String(),
+		Pool:       "ebs",
+		Size:       1024,
+		SnapshotId: "snap-0123",
+		Status:     "available",
+		Created:    created,
+	}, {
+		Id:         "0/1",
+		StorageTag: s.storageTag.String(),
+		VolumeTag:  s.volumeTag.String(),
+		Pool:       "ebs",
+		Status:     "error",
+		Message:    "oops",
+		Created:    created,
+	}})
+	s.stub.CheckCallNames(c, allStorageSnapshotsCall)
+}
+
+func (s *storageSuite) TestMigrateStorage(c *gc.C) {
+	s.stub.SetErrors(nil, errors.NotSupportedf("migrating filesystem storage"))
+	results, err := s.api.MigrateStorage(params.StorageMigrationArgs{[]params.StorageMigrationArg{
+		{StorageTag: "storage-data-0", Pool: "ebs-ssd"},
+		{StorageTag: "volume-0", Pool: "ebs-ssd"},
+		{StorageTag: "storage-data-1", Pool: "ebs-ssd"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.StorageMigrationResult{
+		{Id: "0/0"},
+		{Error: &params.Error{Message: `"volume-0" is not a valid storage tag`}},
+		{Error: &params.Error{
+			Code:    params.CodeNotSupported,
+			Message: "migrating filesystem storage not supported",
+		}},
+	})
+	s.stub.CheckCalls(c, []testing.StubCall{
+		{getBlockForTypeCall, []interface{}{state.ChangeBlock}},
+		{migrateStorageCall, []interface{}{s.storageTag, "ebs-ssd"}},
+		{migrateStorageCall, []interface{}{names.NewStorageTag("data/1"), "ebs-ssd"}},
+	})
+}
+
+func (s *storageSuite) TestMigrateStorageBlocked(c *gc.C) {
+	s.blockAllChanges(c, "migrating")
+	_, err := s.api.MigrateStorage(params.StorageMigrationArgs{[]params.StorageMigrationArg{
+		{StorageTag: "storage-data-0", Pool: "ebs-ssd"},
+	}})
+	s.assertBlocked(c, err, "migrating")
+}
+
+func (s *storageSuite) TestListStorageMigrations(c *gc.C) {
+	created := time.Date(2020, 2, 1, 10, 0, 0, 0, time.UTC)
+	completed := created.Add(time.Hour)
+	s.storageMigrations = []state.StorageMigration{
+		&mockStorageMigration{
+			id:           "0/0",
+			storageTag:   s.storageTag,
+			machineTag:   s.machineTag,
+			sourceVolume: s.volumeTag,
+			sourcePool:   "ebs",
+			targetVolume: names.NewVolumeTag("0/1"),
+			targetPool:   "ebs-ssd",
+			status:       state.StorageMigrationCompleted,
+			created:      created,
+			completed:    completed,
+		},
+		&mockStorageMigration{
+			id:           "0/1",
+			storageTag:   s.storageTag,
+			machineTag:   s.machineTag,
+			sourceVolume: names.NewVolumeTag("0/1"),
+			sourcePool:   "ebs-ssd",
+			targetVolume: names.NewVolumeTag("0/2"),
+			targetPool:   "ebs",
+			status:       state.StorageMigrationPending,
+			created:      created,
+		},
+	}
+	results, err := s.api.ListStorageMigrations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.StorageMigrationDetails{{
+		Id:              "0/0",
+		StorageTag:      s.storageTag.String(),
+		MachineTag:      s.machineTag.String(),
+		SourceVolumeTag: s.volumeTag.String(),
+		SourcePool:      "ebs",
+		TargetVolumeTag: "volume-0-1",
+		TargetPool:      "ebs-ssd",
+		Status:          "completed",
+		Created:         created,
+		Completed:       &completed,
+	}, {
+		Id:              "0/1",
+		StorageTag:      s.storageTag.String(),
+		MachineTag:      s.machineTag.String(),
+		SourceVolumeTag: "volume-0-1",
+		SourcePool:      "ebs-ssd",
+		TargetVolumeTag: "volume-0-2",
+		TargetPool:      "ebs",
+		Status:          "pending",
+		Created:         created,
+	}})
+	s.stub.CheckCallNames(c, allStorageMigrationsCall)
+}
+
+type filesystemResizer struct {
+	*dummy.FilesystemSource
+}
+
+// ResizeFilesystems is part of the storage.FilesystemResizer interface.
+func (f filesystemResizer) ResizeFilesystems(ctx context.ProviderCallContext, args []storage.FilesystemResizeParams) ([]storage.ResizeResult, error) {
+	f.MethodCall(f, "ResizeFilesystems", ctx, args)
+	return nil, f.NextErr()
+}
+
 type filesystemImporter struct {
 	*dummy.FilesystemSource
 }
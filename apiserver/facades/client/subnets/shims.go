@@ -89,3 +89,15 @@ func (s *stateShim) AllSpaces() ([]networkingcommon.BackingSpace, error) {
 	}
 	return spaces, nil
 }
+
+func (s *stateShim) MoveSubnet(cidr, spaceName string, force bool) error {
+	subnet, err := s.State.SubnetByCIDR(cidr)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	space, err := s.State.SpaceByName(spaceName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(subnet.MoveToSpace(space.Id(), force))
+}
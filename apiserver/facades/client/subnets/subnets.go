@@ -16,6 +16,8 @@ import (
 	"github.com/juju/juju/apiserver/common/networkingcommon"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/context"
 	"github.com/juju/juju/permission"
@@ -48,16 +50,26 @@ type Backing interface {
 	// AllSpaces returns all known Juju network spaces.
 	AllSpaces() ([]networkingcommon.BackingSpace, error)
 
+	// MoveSubnet moves the subnet with the given CIDR to the
+	// named space. Unless force is true, the subnet is not moved
+	// out of a space that is in use.
+	MoveSubnet(cidr, spaceName string, force bool) error
+
 	// ModelTag returns the tag of the model this state is associated to.
 	ModelTag() names.ModelTag
 }
 
 // APIv2 provides the subnets API facade for versions < 3.
 type APIv2 struct {
+	*APIv3
+}
+
+// APIv3 provides the subnets API facade for version 3.
+type APIv3 struct {
 	*API
 }
 
-// API provides the subnets API facade for version 3.
+// API provides the subnets API facade for version 4.
 type API struct {
 	backing    Backing
 	resources  facade.Resources
@@ -67,13 +79,22 @@ type API struct {
 
 // NewAPIv2 is a wrapper that creates a V2 subnets API.
 func NewAPIv2(st *state.State, res facade.Resources, auth facade.Authorizer) (*APIv2, error) {
-	api, err := NewAPI(st, res, auth)
+	api, err := NewAPIv3(st, res, auth)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return &APIv2{api}, nil
 }
 
+// NewAPIv3 is a wrapper that creates a V3 subnets API.
+func NewAPIv3(st *state.State, res facade.Resources, auth facade.Authorizer) (*APIv3, error) {
+	api, err := NewAPI(st, res, auth)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv3{api}, nil
+}
+
 // NewAPI creates a new Subnets API server-side facade with a
 // state.State backing.
 func NewAPI(st *state.State, res facade.Resources, auth facade.Authorizer) (*API, error) {
@@ -241,6 +262,68 @@ func (api *API) ListSubnets(args params.SubnetsFilters) (results params.ListSubn
 	return results, nil
 }
 
+// ListProviderSubnets is not available via the V3 API.
+func (api *APIv3) ListProviderSubnets(_, _ struct{}) {}
+
+// ListProviderSubnets returns the subnets discovered by the provider,
+// whether or not they are known to the model. It can be compared with
+// the results of ListSubnets to detect drift between the two.
+func (api *API) ListProviderSubnets() (params.ListSubnetsResults, error) {
+	if err := api.checkCanRead(); err != nil {
+		return params.ListSubnetsResults{}, err
+	}
+	netEnv, err := networkingEnviron(api.backing)
+	if err != nil {
+		return params.ListSubnetsResults{}, errors.Trace(err)
+	}
+	subnetInfo, err := netEnv.Subnets(api.context, instance.UnknownId, nil)
+	if err != nil {
+		return params.ListSubnetsResults{}, errors.Annotate(err, "cannot get provider subnets")
+	}
+	var results params.ListSubnetsResults
+	for _, info := range subnetInfo {
+		results.Results = append(results.Results, params.Subnet{
+			CIDR:              info.CIDR,
+			ProviderId:        string(info.ProviderId),
+			ProviderNetworkId: string(info.ProviderNetworkId),
+			ProviderSpaceId:   string(info.ProviderSpaceId),
+			VLANTag:           info.VLANTag,
+			Zones:             info.AvailabilityZones,
+		})
+	}
+	return results, nil
+}
+
+// MoveSubnets is not available via the V3 API.
+func (api *APIv3) MoveSubnets(_, _ struct{}) {}
+
+// MoveSubnets moves existing subnets to the specified spaces.
+func (api *API) MoveSubnets(args params.MoveSubnetsParams) (params.ErrorResults, error) {
+	if err := api.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Subnets)),
+	}
+	for i, arg := range args.Subnets {
+		if err := api.moveOneSubnet(arg, args.Force); err != nil {
+			results.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return results, nil
+}
+
+func (api *API) moveOneSubnet(arg params.MoveSubnetParams, force bool) error {
+	if !network.IsValidCidr(arg.CIDR) {
+		return errors.NotValidf("CIDR %q", arg.CIDR)
+	}
+	spaceTag, err := names.ParseSpaceTag(arg.SpaceTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(api.backing.MoveSubnet(arg.CIDR, spaceTag.Id(), force))
+}
+
 func convertToAddSubnetsParams(old params.AddSubnetsParamsV2) (params.AddSubnetsParams, int, error) {
 	subnetsParams := params.AddSubnetsParams{
 		Subnets: make([]params.AddSubnetParams, len(old.Subnets)),
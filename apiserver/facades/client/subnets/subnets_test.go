@@ -899,3 +899,71 @@ func (s *SubnetsSuite) TestAddSubnetsParamsCombinations(c *gc.C) {
 
 	apiservertesting.CheckMethodCalls(c, apiservertesting.SharedStub)
 }
+
+func (s *SubnetsSuite) TestListProviderSubnets(c *gc.C) {
+	apiservertesting.BackingInstance.SetUp(
+		c,
+		apiservertesting.StubNetworkingEnvironName,
+		apiservertesting.WithZones,
+		apiservertesting.WithSpaces,
+		apiservertesting.WithSubnets)
+
+	results, err := s.facade.ListProviderSubnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, len(apiservertesting.ProviderInstance.Subnets))
+	c.Assert(results.Results[0], jc.DeepEquals, params.Subnet{
+		CIDR:              "10.10.0.0/24",
+		ProviderId:        "sn-zadf00d",
+		ProviderNetworkId: "godspeed",
+		Zones:             []string{"zone1"},
+	})
+
+	apiservertesting.CheckMethodCalls(c, apiservertesting.SharedStub,
+		apiservertesting.BackingCall("ModelConfig"),
+		apiservertesting.BackingCall("CloudSpec"),
+		apiservertesting.ProviderCall("Open", apiservertesting.BackingInstance.EnvConfig),
+		apiservertesting.NetworkingEnvironCall("Subnets", s.callContext, instance.UnknownId, []network.Id(nil)),
+	)
+}
+
+func (s *SubnetsSuite) TestListProviderSubnetsNetworkingNotSupported(c *gc.C) {
+	apiservertesting.BackingInstance.SetUp(
+		c,
+		apiservertesting.StubEnvironName,
+		apiservertesting.WithoutZones,
+		apiservertesting.WithoutSpaces,
+		apiservertesting.WithoutSubnets)
+
+	_, err := s.facade.ListProviderSubnets()
+	c.Assert(err, gc.ErrorMatches, "model networking features not supported")
+}
+
+func (s *SubnetsSuite) TestMoveSubnets(c *gc.C) {
+	results, err := s.facade.MoveSubnets(params.MoveSubnetsParams{
+		Subnets: []params.MoveSubnetParams{{
+			CIDR:     "10.10.0.0/24",
+			SpaceTag: "space-dmz",
+		}, {
+			CIDR:     "10.99.0.0/24",
+			SpaceTag: "space-dmz",
+		}, {
+			CIDR:     "invalid",
+			SpaceTag: "space-dmz",
+		}, {
+			CIDR:     "10.10.0.0/24",
+			SpaceTag: "dmz",
+		}},
+		Force: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `subnet "10.99.0.0/24" not found`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `CIDR "invalid" not valid`)
+	c.Assert(results.Results[3].Error, gc.ErrorMatches, `"dmz" is not a valid tag`)
+
+	apiservertesting.CheckMethodCalls(c, apiservertesting.SharedStub,
+		apiservertesting.BackingCall("MoveSubnet", "10.10.0.0/24", "dmz", true),
+		apiservertesting.BackingCall("MoveSubnet", "10.99.0.0/24", "dmz", true),
+	)
+}
@@ -51,6 +51,16 @@ type FirewallerAPIV5 struct {
 	*FirewallerAPIV4
 }
 
+// FirewallerAPIV6 provides access to the Firewaller v6 API facade.
+type FirewallerAPIV6 struct {
+	*FirewallerAPIV5
+}
+
+// FirewallerAPIV7 provides access to the Firewaller v7 API facade.
+type FirewallerAPIV7 struct {
+	*FirewallerAPIV6
+}
+
 // NewStateFirewallerAPIV3 creates a new server-side FirewallerAPIV3 facade.
 func NewStateFirewallerAPIV3(context facade.Context) (*FirewallerAPIV3, error) {
 	st := context.State()
@@ -92,6 +102,28 @@ func NewStateFirewallerAPIV5(context facade.Context) (*FirewallerAPIV5, error) {
 	}, nil
 }
 
+// NewStateFirewallerAPIV6 creates a new server-side FirewallerAPIV6 facade.
+func NewStateFirewallerAPIV6(context facade.Context) (*FirewallerAPIV6, error) {
+	facadev5, err := NewStateFirewallerAPIV5(context)
+	if err != nil {
+		return nil, err
+	}
+	return &FirewallerAPIV6{
+		FirewallerAPIV5: facadev5,
+	}, nil
+}
+
+// NewStateFirewallerAPIV7 creates a new server-side FirewallerAPIV7 facade.
+func NewStateFirewallerAPIV7(context facade.Context) (*FirewallerAPIV7, error) {
+	facadev6, err := NewStateFirewallerAPIV6(context)
+	if err != nil {
+		return nil, err
+	}
+	return &FirewallerAPIV7{
+		FirewallerAPIV6: facadev6,
+	}, nil
+}
+
 // NewFirewallerAPI creates a new server-side FirewallerAPIV3 facade.
 func NewFirewallerAPI(
 	st State,
@@ -527,3 +559,104 @@ func (f *FirewallerAPIV5) AreManuallyProvisioned(args params.Entities) (params.B
 	}
 	return result, nil
 }
+
+// WatchFirewallRules returns a NotifyWatcher which triggers whenever
+// the model's firewall rules change.
+func (f *FirewallerAPIV6) WatchFirewallRules() (params.NotifyWatchResult, error) {
+	var result params.NotifyWatchResult
+	w := f.st.WatchFirewallRules()
+	if _, ok := <-w.Changes(); !ok {
+		return result, common.ServerError(watcher.EnsureErr(w))
+	}
+	result.NotifyWatcherId = f.resources.Register(w)
+	return result, nil
+}
+
+// ModelIngressRules returns the model's ingress rules, which allow
+// ingress to a port range from arbitrary subnets.
+func (f *FirewallerAPIV6) ModelIngressRules() (params.ListFirewallRulesResults, error) {
+	var result params.ListFirewallRulesResults
+	rules, err := f.st.ModelIngressRules()
+	if err != nil {
+		return result, common.ServerError(err)
+	}
+	for _, rule := range rules {
+		portRange := params.FromNetworkPortRange(rule.PortRange)
+		result.Rules = append(result.Rules, params.FirewallRule{
+			Name:           rule.Name,
+			PortRange:      &portRange,
+			WhitelistCIDRS: rule.WhitelistCIDRs,
+			Application:    rule.Application,
+		})
+	}
+	return result, nil
+}
+
+// WatchEgressRules returns a NotifyWatcher which triggers whenever
+// the egress rules of any application change.
+func (f *FirewallerAPIV7) WatchEgressRules() (params.NotifyWatchResult, error) {
+	var result params.NotifyWatchResult
+	w := f.st.WatchEgressRules()
+	if _, ok := <-w.Changes(); !ok {
+		return result, common.ServerError(watcher.EnsureErr(w))
+	}
+	result.NotifyWatcherId = f.resources.Register(w)
+	return result, nil
+}
+
+// MachineEgressRules returns the egress rules of each given machine,
+// and whether egress from it is restricted.
+func (f *FirewallerAPIV7) MachineEgressRules(args params.Entities) (params.MachineEgressRulesResults, error) {
+	result := params.MachineEgressRulesResults{
+		Results: make([]params.MachineEgressRulesResult, len(args.Entities)),
+	}
+	canAccess, err := f.accessMachine()
+	if err != nil {
+		return params.MachineEgressRulesResults{}, err
+	}
+	for i, entity := range args.Entities {
+		machineTag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if !canAccess(machineTag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		rules, err := firewall.MachineEgressRules(f.st, machineTag.Id())
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i] = rules
+	}
+	return result, nil
+}
+
+// SetMachineEgressEnforcement records, for each given machine, whether
+// the provider's firewall enforces its egress rules. The machine agent
+// enforces the rules of machines for which the provider does not.
+func (f *FirewallerAPIV7) SetMachineEgressEnforcement(args params.MachineEgressEnforcements) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Machines)),
+	}
+	canAccess, err := f.accessMachine()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Machines {
+		machineTag, err := names.ParseMachineTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if !canAccess(machineTag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = f.st.SetMachineEgressEnforcedByProvider(machineTag.Id(), arg.ProviderEnforced)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/core/crossmodel"
+	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
@@ -134,3 +135,91 @@ func (s *RemoteFirewallerSuite) TestFirewallRules(c *gc.C) {
 	c.Assert(result.Rules[0].KnownService, gc.Equals, params.KnownServiceValue("juju-application-offer"))
 	c.Assert(result.Rules[0].WhitelistCIDRS, jc.SameContents, []string{"192.168.0.0/16"})
 }
+
+func (s *RemoteFirewallerSuite) TestWatchFirewallRules(c *gc.C) {
+	api := &firewaller.FirewallerAPIV6{FirewallerAPIV5: &firewaller.FirewallerAPIV5{FirewallerAPIV4: s.api}}
+	result, err := api.WatchFirewallRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.NotifyWatcherId, gc.Equals, "1")
+
+	resource := s.resources.Get("1")
+	c.Assert(resource, gc.Equals, s.st.rulesWatcher)
+	s.st.CheckCallNames(c, "WatchFirewallRules")
+}
+
+func (s *RemoteFirewallerSuite) TestModelIngressRules(c *gc.C) {
+	s.st.ingressRules = []*state.FirewallRule{{
+		Name:           "node-exporter",
+		PortRange:      network.PortRange{FromPort: 9100, ToPort: 9110, Protocol: "tcp"},
+		WhitelistCIDRs: []string{"10.0.0.0/8"},
+		Application:    "mysql",
+	}}
+	api := &firewaller.FirewallerAPIV6{FirewallerAPIV5: &firewaller.FirewallerAPIV5{FirewallerAPIV4: s.api}}
+	result, err := api.ModelIngressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ListFirewallRulesResults{
+		Rules: []params.FirewallRule{{
+			Name:           "node-exporter",
+			PortRange:      &params.PortRange{FromPort: 9100, ToPort: 9110, Protocol: "tcp"},
+			WhitelistCIDRS: []string{"10.0.0.0/8"},
+			Application:    "mysql",
+		}},
+	})
+}
+
+func (s *RemoteFirewallerSuite) TestWatchEgressRules(c *gc.C) {
+	api := &firewaller.FirewallerAPIV7{FirewallerAPIV6: &firewaller.FirewallerAPIV6{FirewallerAPIV5: &firewaller.FirewallerAPIV5{FirewallerAPIV4: s.api}}}
+	result, err := api.WatchEgressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.NotifyWatcherId, gc.Equals, "1")
+
+	resource := s.resources.Get("1")
+	c.Assert(resource, gc.Equals, s.st.egressWatcher)
+	s.st.CheckCallNames(c, "WatchEgressRules")
+}
+
+func (s *RemoteFirewallerSuite) TestMachineEgressRules(c *gc.C) {
+	s.st.egressRules["0"] = []state.EgressRule{{
+		DestinationCIDR: "10.0.0.0/8",
+		PortRange:       network.MustParsePortRange("5432/tcp"),
+	}}
+	s.st.apiHostPorts = []network.SpaceHostPorts{network.NewSpaceHostPorts(17070, "10.1.2.3")}
+	api := &firewaller.FirewallerAPIV7{FirewallerAPIV6: &firewaller.FirewallerAPIV6{FirewallerAPIV5: &firewaller.FirewallerAPIV5{FirewallerAPIV4: s.api}}}
+	result, err := api.MachineEgressRules(params.Entities{Entities: []params.Entity{
+		{Tag: "machine-0"}, {Tag: "machine-1"}, {Tag: "unit-mysql-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.MachineEgressRulesResults{
+		Results: []params.MachineEgressRulesResult{{
+			Restricted: true,
+			Rules: []params.EgressRule{{
+				DestinationCIDR: "10.0.0.0/8",
+				PortRange:       params.PortRange{FromPort: 5432, ToPort: 5432, Protocol: "tcp"},
+			}, {
+				DestinationCIDR: "10.1.2.3/32",
+				PortRange:       params.PortRange{FromPort: 17070, ToPort: 17070, Protocol: "tcp"},
+			}},
+		}, {}, {
+			Error: &params.Error{Message: `"unit-mysql-0" is not a valid machine tag`},
+		}},
+	})
+}
+
+func (s *RemoteFirewallerSuite) TestSetMachineEgressEnforcement(c *gc.C) {
+	api := &firewaller.FirewallerAPIV7{FirewallerAPIV6: &firewaller.FirewallerAPIV6{FirewallerAPIV5: &firewaller.FirewallerAPIV5{FirewallerAPIV4: s.api}}}
+	result, err := api.SetMachineEgressEnforcement(params.MachineEgressEnforcements{Machines: []params.MachineEgressEnforcement{
+		{Tag: "machine-0", ProviderEnforced: true},
+		{Tag: "machine-1"},
+		{Tag: "unit-mysql-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}, {}, {
+			Error: &params.Error{Message: `"unit-mysql-0" is not a valid machine tag`},
+		}},
+	})
+	s.st.CheckCall(c, 0, "SetMachineEgressEnforcedByProvider", "0", true)
+	s.st.CheckCall(c, 1, "SetMachineEgressEnforcedByProvider", "1", false)
+}
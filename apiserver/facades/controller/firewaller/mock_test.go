@@ -17,6 +17,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/crossmodel"
+	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
@@ -38,6 +39,11 @@ type mockState struct {
 	relations      map[string]*mockRelation
 	controllerInfo map[string]*mockControllerInfo
 	firewallRules  map[state.WellKnownServiceType]*state.FirewallRule
+	ingressRules   []*state.FirewallRule
+	rulesWatcher   *mockNotifyWatcher
+	egressWatcher  *mockNotifyWatcher
+	egressRules    map[string][]state.EgressRule
+	apiHostPorts   []network.SpaceHostPorts
 	subnetsWatcher *mockStringsWatcher
 	modelWatcher   *mockNotifyWatcher
 	configAttrs    map[string]interface{}
@@ -51,6 +57,9 @@ func newMockState(modelUUID string) *mockState {
 		macaroons:      make(map[names.Tag]*macaroon.Macaroon),
 		controllerInfo: make(map[string]*mockControllerInfo),
 		firewallRules:  make(map[state.WellKnownServiceType]*state.FirewallRule),
+		rulesWatcher:   newMockNotifyWatcher(),
+		egressWatcher:  newMockNotifyWatcher(),
+		egressRules:    make(map[string][]state.EgressRule),
 		subnetsWatcher: newMockStringsWatcher(),
 		modelWatcher:   newMockNotifyWatcher(),
 		configAttrs:    coretesting.FakeConfig(),
@@ -118,6 +127,37 @@ func (st *mockState) FirewallRule(service state.WellKnownServiceType) (*state.Fi
 	return r, nil
 }
 
+func (st *mockState) ModelIngressRules() ([]*state.FirewallRule, error) {
+	st.MethodCall(st, "ModelIngressRules")
+	return st.ingressRules, st.NextErr()
+}
+
+func (st *mockState) WatchFirewallRules() state.NotifyWatcher {
+	st.MethodCall(st, "WatchFirewallRules")
+	return st.rulesWatcher
+}
+
+func (st *mockState) WatchEgressRules() state.NotifyWatcher {
+	st.MethodCall(st, "WatchEgressRules")
+	return st.egressWatcher
+}
+
+func (st *mockState) MachineEgressRules(machineId string) ([]state.EgressRule, bool, error) {
+	st.MethodCall(st, "MachineEgressRules", machineId)
+	rules, ok := st.egressRules[machineId]
+	return rules, ok, st.NextErr()
+}
+
+func (st *mockState) SetMachineEgressEnforcedByProvider(machineId string, enforced bool) error {
+	st.MethodCall(st, "SetMachineEgressEnforcedByProvider", machineId, enforced)
+	return st.NextErr()
+}
+
+func (st *mockState) APIHostPortsForAgents() ([]network.SpaceHostPorts, error) {
+	st.MethodCall(st, "APIHostPortsForAgents")
+	return st.apiHostPorts, st.NextErr()
+}
+
 func (st *mockState) SubnetByCIDR(cidr string) (firewaller.Subnet, error) {
 	return nil, errors.NotImplementedf("SubnetByCIDR")
 }
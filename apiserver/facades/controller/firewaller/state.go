@@ -8,6 +8,7 @@ import (
 	"gopkg.in/macaroon.v2-unstable"
 
 	"github.com/juju/juju/apiserver/common/firewall"
+	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/state"
 )
 
@@ -15,6 +16,7 @@ import (
 // remote firewaller facade.
 type State interface {
 	firewall.State
+	firewall.EgressRulesBackend
 
 	ModelUUID() string
 
@@ -26,6 +28,14 @@ type State interface {
 
 	FirewallRule(service state.WellKnownServiceType) (*state.FirewallRule, error)
 
+	ModelIngressRules() ([]*state.FirewallRule, error)
+
+	WatchFirewallRules() state.NotifyWatcher
+
+	WatchEgressRules() state.NotifyWatcher
+
+	SetMachineEgressEnforcedByProvider(machineId string, enforced bool) error
+
 	Subnet(id string) (Subnet, error)
 
 	SubnetByCIDR(cidr string) (Subnet, error)
@@ -63,6 +73,49 @@ func (st stateShim) FirewallRule(service state.WellKnownServiceType) (*state.Fir
 	return api.Rule(service)
 }
 
+func (st stateShim) ModelIngressRules() ([]*state.FirewallRule, error) {
+	api := state.NewFirewallRules(st.st)
+	rules, err := api.AllRules()
+	if err != nil {
+		return nil, err
+	}
+	var ingressRules []*state.FirewallRule
+	for _, rule := range rules {
+		if rule.IsIngressRule() {
+			ingressRules = append(ingressRules, rule)
+		}
+	}
+	return ingressRules, nil
+}
+
+func (st stateShim) WatchFirewallRules() state.NotifyWatcher {
+	return st.st.WatchFirewallRules()
+}
+
+func (st stateShim) WatchEgressRules() state.NotifyWatcher {
+	return st.st.WatchEgressRules()
+}
+
+func (st stateShim) MachineEgressRules(machineId string) ([]state.EgressRule, bool, error) {
+	m, err := st.st.Machine(machineId)
+	if err != nil {
+		return nil, false, err
+	}
+	return m.EgressRules()
+}
+
+func (st stateShim) SetMachineEgressEnforcedByProvider(machineId string, enforced bool) error {
+	m, err := st.st.Machine(machineId)
+	if err != nil {
+		return err
+	}
+	return m.SetEgressEnforcedByProvider(enforced)
+}
+
+func (st stateShim) APIHostPortsForAgents() ([]network.SpaceHostPorts, error) {
+	return st.st.APIHostPortsForAgents()
+}
+
 type Subnet interface {
 	ID() string
 	CIDR() string
@@ -34,6 +34,10 @@ type ApplicationDeploy struct {
 	AttachStorage    []string                       `json:"attach-storage,omitempty"`
 	EndpointBindings map[string]string              `json:"endpoint-bindings,omitempty"`
 	Resources        map[string]string              `json:"resources,omitempty"`
+
+	// AttachStorageSnapshots maps charm storage names to the IDs of
+	// storage snapshots from which the units' storage is restored.
+	AttachStorageSnapshots map[string]string `json:"attach-storage-snapshots,omitempty"`
 }
 
 // ApplicationsDeployV5 holds the parameters for deploying one or more applications.
@@ -434,6 +438,15 @@ type ApplicationResult struct {
 	Exposed          bool              `json:"exposed"`
 	Remote           bool              `json:"remote"`
 	EndpointBindings map[string]string `json:"endpoint-bindings,omitempty"`
+
+	// UnitStatusSummary summarises the workload status of the
+	// application's units, e.g. "3/5 active, 2 maintenance".
+	UnitStatusSummary string `json:"unit-status-summary,omitempty"`
+
+	// WorkloadVersions maps each workload version reported by the
+	// application's units to the names of those units. It is only set
+	// when the units report more than one version.
+	WorkloadVersions map[string][]string `json:"workload-versions,omitempty"`
 }
 
 // ApplicationInfoResults holds an application info result or a retrieval error.
@@ -446,3 +459,49 @@ type ApplicationInfoResult struct {
 type ApplicationInfoResults struct {
 	Results []ApplicationInfoResult `json:"results"`
 }
+
+// UnitResult holds unit info.
+type UnitResult struct {
+	Tag             string                 `json:"tag"`
+	WorkloadVersion string                 `json:"workload-version"`
+	WorkloadStatus  DetailedStatus         `json:"workload-status"`
+	AgentStatus     DetailedStatus         `json:"agent-status"`
+	Machine         string                 `json:"machine,omitempty"`
+	OpenedPorts     []string               `json:"opened-ports"`
+	PublicAddress   string                 `json:"public-address,omitempty"`
+	Charm           string                 `json:"charm"`
+	Leader          bool                   `json:"leader,omitempty"`
+	LeaderSettings  map[string]string      `json:"leader-settings,omitempty"`
+	RelationData    []EndpointRelationData `json:"relation-data,omitempty"`
+}
+
+// EndpointRelationData holds information about a relation to a given endpoint.
+type EndpointRelationData struct {
+	RelationId       int                     `json:"relation-id"`
+	Endpoint         string                  `json:"endpoint"`
+	CrossModel       bool                    `json:"cross-model"`
+	RelatedEndpoint  string                  `json:"related-endpoint"`
+	ApplicationData  map[string]interface{}  `json:"application-data"`
+	UnitRelationData map[string]RelationData `json:"unit-relation-data"`
+
+	// LocalApplicationData holds the settings of the unit's own
+	// application, for relations other than peer relations.
+	LocalApplicationData map[string]interface{} `json:"local-application-data,omitempty"`
+}
+
+// RelationData holds information about a unit's relation.
+type RelationData struct {
+	InScope  bool                   `json:"in-scope"`
+	UnitData map[string]interface{} `json:"data"`
+}
+
+// UnitInfoResult holds a unit info result or a retrieval error.
+type UnitInfoResult struct {
+	Result *UnitResult `json:"result,omitempty"`
+	Error  *Error      `json:"error,omitempty"`
+}
+
+// UnitInfoResults holds units associated with entities.
+type UnitInfoResults struct {
+	Results []UnitInfoResult `json:"results"`
+}
@@ -25,6 +25,85 @@ type FirewallRule struct {
 
 	// WhitelistCIDRS is the ist of subnets allowed access.
 	WhitelistCIDRS []string `json:"whitelist-cidrs,omitempty"`
+
+	// Name is the name of a model ingress rule, which is used
+	// instead of a well known service.
+	Name string `json:"name,omitempty"`
+
+	// PortRange is the port range a model ingress rule
+	// allows access to.
+	PortRange *PortRange `json:"port-range,omitempty"`
+
+	// Application, if set, restricts a model ingress rule to
+	// the machines hosting units of the application.
+	Application string `json:"application,omitempty"`
+}
+
+// FirewallRuleNames holds the names of model ingress rules.
+type FirewallRuleNames struct {
+	Names []string `json:"names"`
+}
+
+// EgressRule allows egress to a port range on a destination subnet.
+type EgressRule struct {
+	// DestinationCIDR is the subnet egress is allowed to.
+	DestinationCIDR string `json:"destination-cidr"`
+
+	// PortRange is the port range egress is allowed to.
+	PortRange PortRange `json:"port-range"`
+}
+
+// ApplicationEgressRules holds the egress rules of an application.
+// An application without egress rules has unrestricted egress.
+type ApplicationEgressRules struct {
+	Application string       `json:"application"`
+	Rules       []EgressRule `json:"rules"`
+}
+
+// SetEgressRulesArgs holds the parameters for setting the
+// egress rules of applications.
+type SetEgressRulesArgs struct {
+	Args []ApplicationEgressRules `json:"args"`
+}
+
+// ListEgressRulesResults holds the egress rules of the applications
+// with restricted egress.
+type ListEgressRulesResults struct {
+	Results []ApplicationEgressRules `json:"results"`
+}
+
+// MachineEgressRulesResult holds the egress rules of a machine.
+type MachineEgressRulesResult struct {
+	// Restricted is true if egress from the machine is
+	// restricted to the rules.
+	Restricted bool `json:"restricted"`
+
+	// Rules are the egress rules of the applications with units on
+	// the machine, and those allowing egress to the controller.
+	Rules []EgressRule `json:"rules,omitempty"`
+
+	// ProviderEnforced is true if the rules are enforced by the
+	// cloud, rather than on the machine.
+	ProviderEnforced bool `json:"provider-enforced"`
+
+	Error *Error `json:"error,omitempty"`
+}
+
+// MachineEgressRulesResults holds the egress rules of machines.
+type MachineEgressRulesResults struct {
+	Results []MachineEgressRulesResult `json:"results"`
+}
+
+// MachineEgressEnforcement records whether the egress rules of a
+// machine are enforced by the provider's firewall.
+type MachineEgressEnforcement struct {
+	Tag              string `json:"tag"`
+	ProviderEnforced bool   `json:"provider-enforced"`
+}
+
+// MachineEgressEnforcements holds the egress enforcement of machines.
+type MachineEgressEnforcements struct {
+	Machines []MachineEgressEnforcement `json:"machines"`
 }
 
 // KnownServiceArgs holds the parameters for retrieving firewall rules.
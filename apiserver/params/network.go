@@ -4,6 +4,8 @@
 package params
 
 import (
+	"time"
+
 	"github.com/juju/juju/core/network"
 )
 
@@ -122,6 +124,9 @@ type NetworkConfig struct {
 	// InterfaceType is the type of the interface.
 	InterfaceType string `json:"interface-type"`
 
+	// BondMode is the bonding mode of a bond interface, if known.
+	BondMode string `json:"bond-mode,omitempty"`
+
 	// Disabled is true when the interface needs to be disabled on the
 	// machine, e.g. not to configure it at all or stop it if running.
 	Disabled bool `json:"disabled"`
@@ -672,6 +677,21 @@ type SubnetsFilters struct {
 	Zone     string `json:"zone,omitempty"`
 }
 
+// MoveSubnetsParams holds the arguments of a MoveSubnets API call.
+// Unless Force is true, subnets are not moved out of spaces that
+// are in use by endpoint bindings or constraints.
+type MoveSubnetsParams struct {
+	Subnets []MoveSubnetParams `json:"subnets"`
+	Force   bool               `json:"force,omitempty"`
+}
+
+// MoveSubnetParams holds the CIDR of a subnet known to the model,
+// and the tag of the space it should be moved to.
+type MoveSubnetParams struct {
+	CIDR     string `json:"cidr"`
+	SpaceTag string `json:"space-tag"`
+}
+
 // AddSubnetsParams holds the arguments of AddSubnets API call.
 type AddSubnetsParams struct {
 	Subnets []AddSubnetParams `json:"subnets"`
@@ -868,6 +888,34 @@ type NetworkInfoParams struct {
 	Endpoints []string `json:"bindings"`
 }
 
+// UnitEndpoint identifies an endpoint of a unit.
+type UnitEndpoint struct {
+	UnitTag  string `json:"unit-tag"`
+	Endpoint string `json:"endpoint"`
+}
+
+// UnitEndpoints holds the arguments of a UnitEndpointsNetworkInfo API
+// call.
+type UnitEndpoints struct {
+	Entries []UnitEndpoint `json:"entries"`
+}
+
+// UnitEndpointNetworkInfoResult holds the network information of a
+// unit endpoint, as reported to the unit by network-get, together with
+// the machine hosting the unit and the space the endpoint is bound to.
+type UnitEndpointNetworkInfoResult struct {
+	MachineTag string            `json:"machine-tag,omitempty"`
+	SpaceName  string            `json:"space-name,omitempty"`
+	Info       NetworkInfoResult `json:"info"`
+	Error      *Error            `json:"error,omitempty"`
+}
+
+// UnitEndpointNetworkInfoResults holds the results of a
+// UnitEndpointsNetworkInfo API call.
+type UnitEndpointNetworkInfoResults struct {
+	Results []UnitEndpointNetworkInfoResult `json:"results"`
+}
+
 // FanConfigEntry holds configuration for a single fan.
 type FanConfigEntry struct {
 	Underlay string `json:"underlay"`
@@ -878,3 +926,75 @@ type FanConfigEntry struct {
 type FanConfigResult struct {
 	Fans []FanConfigEntry `json:"fans"`
 }
+
+// NetplanDevice describes the desired configuration of a single network
+// device on a machine, from which its netplan configuration is rendered.
+type NetplanDevice struct {
+	Name          string         `json:"name"`
+	Type          string         `json:"type"`
+	MACAddress    string         `json:"mac-address,omitempty"`
+	MTU           int            `json:"mtu,omitempty"`
+	ParentName    string         `json:"parent-name,omitempty"`
+	VLANTag       int            `json:"vlan-tag,omitempty"`
+	BondMode      string         `json:"bond-mode,omitempty"`
+	Addresses     []string       `json:"addresses,omitempty"`
+	DHCP4         bool           `json:"dhcp4,omitempty"`
+	Gateway4      string         `json:"gateway4,omitempty"`
+	Gateway6      string         `json:"gateway6,omitempty"`
+	Nameservers   []string       `json:"nameservers,omitempty"`
+	SearchDomains []string       `json:"search-domains,omitempty"`
+	Routes        []NetworkRoute `json:"routes,omitempty"`
+}
+
+// MachineNetplanConfigResult holds the desired network device
+// configuration of a machine, and whether the machine agent should apply
+// it.
+type MachineNetplanConfigResult struct {
+	Devices []NetplanDevice `json:"devices,omitempty"`
+	Manage  bool            `json:"manage"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// MachineNetplanConfigResults holds the results of a NetplanConfig call.
+type MachineNetplanConfigResults struct {
+	Results []MachineNetplanConfigResult `json:"results"`
+}
+
+// MachineNetplanStatus holds the netplan configuration in effect on a
+// machine, and the reason the desired configuration could not be applied,
+// if any.
+type MachineNetplanStatus struct {
+	Tag    string `json:"tag"`
+	Actual string `json:"actual"`
+	Error  string `json:"error,omitempty"`
+}
+
+// SetMachinesNetplanStatus holds the arguments for a SetNetplanStatus call.
+type SetMachinesNetplanStatus struct {
+	Statuses []MachineNetplanStatus `json:"statuses"`
+}
+
+// NetplanDeviceChange describes how the configuration of a network device
+// on a machine differs from the desired configuration.
+type NetplanDeviceChange struct {
+	Type    string   `json:"type"`
+	Name    string   `json:"name"`
+	Change  string   `json:"change"`
+	Details []string `json:"details,omitempty"`
+}
+
+// MachineNetworkDiffResult holds the desired and actual netplan
+// configuration of a machine and the differences between them.
+type MachineNetworkDiffResult struct {
+	Desired    string                `json:"desired"`
+	Actual     string                `json:"actual,omitempty"`
+	Changes    []NetplanDeviceChange `json:"changes,omitempty"`
+	ApplyError string                `json:"apply-error,omitempty"`
+	Updated    *time.Time            `json:"updated,omitempty"`
+	Error      *Error                `json:"error,omitempty"`
+}
+
+// MachineNetworkDiffResults holds the results of a NetworkConfigDiff call.
+type MachineNetworkDiffResults struct {
+	Results []MachineNetworkDiffResult `json:"results"`
+}
@@ -120,6 +120,10 @@ type AddRelation struct {
 // field maps application names to the involved endpoints.
 type AddRelationResults struct {
 	Endpoints map[string]CharmRelation `json:"endpoints"`
+
+	// Warnings describes any incompatibilities between the relation
+	// settings schemas declared by the related charms.
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // DestroyRelation holds the parameters for making the DestroyRelation call.
@@ -253,12 +257,53 @@ type DestroyMachinesParams struct {
 	Force       bool     `json:"force,omitempty"`
 	Keep        bool     `json:"keep,omitempty"`
 
+	// ForceUnprotected removes any termination protection from the
+	// machines before they are destroyed.
+	ForceUnprotected bool `json:"force-unprotected,omitempty"`
+
 	// MaxWait specifies the amount of time that each step in machine destroy process
 	// will wait before forcing the next step to kick-off. This parameter
 	// only makes sense in combination with 'force' set to 'true'.
 	MaxWait *time.Duration `json:"max-wait,omitempty"`
 }
 
+// MachineProtectionArg holds the termination protection setting
+// for one machine.
+type MachineProtectionArg struct {
+	MachineTag string `json:"machine-tag"`
+	Protected  bool   `json:"protected"`
+}
+
+// MachineProtectionArgs holds the parameters for the
+// SetMachineProtection call.
+type MachineProtectionArgs struct {
+	Args []MachineProtectionArg `json:"args"`
+}
+
+// ResizeMachineArg holds the constraints to resize one machine's
+// cloud instance to satisfy.
+type ResizeMachineArg struct {
+	MachineTag  string            `json:"machine-tag"`
+	Constraints constraints.Value `json:"constraints"`
+}
+
+// ResizeMachineArgs holds the parameters for the ResizeMachines call.
+type ResizeMachineArgs struct {
+	Args []ResizeMachineArg `json:"args"`
+}
+
+// ResizeMachineResult holds the hardware characteristics of a resized
+// machine's cloud instance, or an error.
+type ResizeMachineResult struct {
+	HardwareCharacteristics *instance.HardwareCharacteristics `json:"hardware-characteristics,omitempty"`
+	Error                   *Error                            `json:"error,omitempty"`
+}
+
+// ResizeMachineResults holds the results of the ResizeMachines call.
+type ResizeMachineResults struct {
+	Results []ResizeMachineResult `json:"results"`
+}
+
 // UpdateSeriesArg holds the parameters for updating the series for the
 // specified application or machine. For Application, only known by facade
 // version 5 and greater. For MachineManger, only known by facade version
@@ -379,6 +424,10 @@ type AddApplicationUnits struct {
 	Placement       []*instance.Placement `json:"placement"`
 	Policy          string                `json:"policy,omitempty"`
 	AttachStorage   []string              `json:"attach-storage,omitempty"`
+
+	// AttachStorageSnapshots maps charm storage names to the IDs of
+	// storage snapshots from which the units' storage is restored.
+	AttachStorageSnapshots map[string]string `json:"attach-storage-snapshots,omitempty"`
 }
 
 // AddApplicationUnitsV5 holds parameters for the AddUnits call.
@@ -150,6 +150,17 @@ type ApplicationStatus struct {
 	CharmProfile     string                 `json:"charm-profile"`
 	EndpointBindings map[string]string      `json:"endpoint-bindings"`
 
+	// UnitStatusSummary summarises the workload status of the
+	// application's units, e.g. "3/5 active, 2 maintenance". It is
+	// only set when the application status is derived from its units
+	// and the units do not all share the same status.
+	UnitStatusSummary string `json:"unit-status-summary,omitempty"`
+
+	// WorkloadVersions maps each workload version reported by the
+	// application's units to the names of those units. It is only set
+	// when the units report more than one version.
+	WorkloadVersions map[string][]string `json:"workload-versions,omitempty"`
+
 	// The following are for CAAS models.
 	Scale         int    `json:"int,omitempty"`
 	ProviderId    string `json:"provider-id,omitempty"`
@@ -22,6 +22,45 @@ type SetMachineBlockDevices struct {
 	MachineBlockDevices []MachineBlockDevices `json:"machine-block-devices"`
 }
 
+// FilesystemUsage describes how much of a mounted filesystem is in use.
+type FilesystemUsage struct {
+	UsedBytes  uint64 `json:"used-bytes"`
+	FreeBytes  uint64 `json:"free-bytes"`
+	UsedInodes uint64 `json:"used-inodes"`
+	FreeInodes uint64 `json:"free-inodes"`
+
+	// Updated is the time at which the usage was recorded by the
+	// controller. It is not set by agents reporting usage.
+	Updated *time.Time `json:"updated,omitempty"`
+}
+
+// FilesystemAttachmentUsage holds the usage of a filesystem attached
+// to a machine.
+type FilesystemAttachmentUsage struct {
+	FilesystemTag string          `json:"filesystem-tag"`
+	MachineTag    string          `json:"machine-tag"`
+	Usage         FilesystemUsage `json:"usage"`
+}
+
+// SetFilesystemUsage holds the arguments for recording the usage of
+// a set of filesystem attachments.
+type SetFilesystemUsage struct {
+	Usage []FilesystemAttachmentUsage `json:"usage"`
+}
+
+// FilesystemAttachmentsResult holds the filesystem attachments for a
+// single machine, or an error.
+type FilesystemAttachmentsResult struct {
+	Attachments []FilesystemAttachment `json:"attachments,omitempty"`
+	Error       *Error                 `json:"error,omitempty"`
+}
+
+// FilesystemAttachmentsResults holds a set of FilesystemAttachmentsResults
+// for a set of machines.
+type FilesystemAttachmentsResults struct {
+	Results []FilesystemAttachmentsResult `json:"results,omitempty"`
+}
+
 // BlockDeviceResult holds the result of an API call to retrieve details
 // of a block device.
 type BlockDeviceResult struct {
@@ -98,8 +137,33 @@ type StorageAttachment struct {
 	Kind     StorageKind `json:"kind"`
 	Location string      `json:"location"`
 	Life     Life        `json:"life"`
+
+	// Size is the size of the attached storage in MiB,
+	// if it is known.
+	Size uint64 `json:"size,omitempty"`
+
+	// Migration, if non-empty, is the status of the migration of
+	// the storage that the unit is taking part in.
+	Migration string `json:"migration,omitempty"`
 }
 
+// The stages of a storage migration that a unit using the
+// storage takes part in.
+const (
+	// StorageMigrationPending indicates that the unit must stop
+	// using the storage before its data is copied.
+	StorageMigrationPending = "pending"
+
+	// StorageMigrationCopying indicates that the data is being
+	// copied, and the unit must not use the storage.
+	StorageMigrationCopying = "copying"
+
+	// StorageMigrationAttaching indicates that the storage is
+	// backed by the migration's target volume, and the unit
+	// may use it again.
+	StorageMigrationAttaching = "attaching"
+)
+
 // StorageAttachmentId identifies a storage attachment by the tags of the
 // related unit and storage instance.
 type StorageAttachmentId struct {
@@ -199,6 +263,9 @@ type VolumeInfo struct {
 	// Size is the size of the volume in MiB.
 	Size       uint64 `json:"size"`
 	Persistent bool   `json:"persistent"`
+	// Encrypted reports whether the volume's contents
+	// are encrypted at rest.
+	Encrypted bool `json:"encrypted,omitempty"`
 }
 
 // Volumes describes a set of storage volumes in the model.
@@ -258,6 +325,10 @@ type VolumeParams struct {
 	Attributes map[string]interface{}  `json:"attributes,omitempty"`
 	Tags       map[string]string       `json:"tags,omitempty"`
 	Attachment *VolumeAttachmentParams `json:"attachment,omitempty"`
+
+	// SnapshotId, if non-empty, is the provider ID of the snapshot
+	// that the volume should be created from.
+	SnapshotId string `json:"snapshot-id,omitempty"`
 }
 
 // RemoveVolumeParams holds the parameters for destroying or releasing a
@@ -355,6 +426,143 @@ type RemoveVolumeParamsResults struct {
 	Results []RemoveVolumeParamsResult `json:"results,omitempty"`
 }
 
+// VolumeResizeParams holds the parameters for growing a volume.
+type VolumeResizeParams struct {
+	// VolumeTag is the tag of the volume to resize.
+	VolumeTag string `json:"volume-tag"`
+
+	// Provider is the storage provider that manages the volume.
+	Provider string `json:"provider"`
+
+	// Size is the size in MiB that the volume should be grown to.
+	Size uint64 `json:"size"`
+
+	// Info is the current information about the volume.
+	Info VolumeInfo `json:"info"`
+}
+
+// VolumeResizeParamsResult holds the parameters for growing a volume,
+// or an error if the volume does not need resizing.
+type VolumeResizeParamsResult struct {
+	Result VolumeResizeParams `json:"result"`
+	Error  *Error             `json:"error,omitempty"`
+}
+
+// VolumeResizeParamsResults holds the parameters for growing multiple
+// volumes.
+type VolumeResizeParamsResults struct {
+	Results []VolumeResizeParamsResult `json:"results,omitempty"`
+}
+
+// VolumeSnapshotParams holds the parameters for creating a snapshot
+// of a volume.
+type VolumeSnapshotParams struct {
+	// Id is the ID of the storage snapshot in the model.
+	Id string `json:"id"`
+
+	// VolumeTag is the tag of the volume to snapshot.
+	VolumeTag string `json:"volume-tag"`
+
+	// Provider is the storage provider that manages the volume.
+	Provider string `json:"provider"`
+
+	// VolumeId is the storage provider's unique ID for the volume.
+	VolumeId string `json:"volume-id"`
+
+	// Tags are the resource tags to apply to the snapshot.
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// VolumeSnapshotParamsResult holds the parameters for creating a
+// volume snapshot, or an error if the snapshot is not pending.
+type VolumeSnapshotParamsResult struct {
+	Result VolumeSnapshotParams `json:"result"`
+	Error  *Error               `json:"error,omitempty"`
+}
+
+// VolumeSnapshotParamsResults holds the parameters for creating
+// multiple volume snapshots.
+type VolumeSnapshotParamsResults struct {
+	Results []VolumeSnapshotParamsResult `json:"results,omitempty"`
+}
+
+// StorageSnapshotIds holds the IDs of storage snapshots.
+type StorageSnapshotIds struct {
+	Ids []string `json:"ids"`
+}
+
+// VolumeSnapshotInfo records the outcome of creating a volume snapshot.
+type VolumeSnapshotInfo struct {
+	// Id is the ID of the storage snapshot in the model.
+	Id string `json:"id"`
+
+	// SnapshotId is the provider-supplied ID of the created snapshot.
+	SnapshotId string `json:"snapshot-id,omitempty"`
+
+	// Size is the size of the snapshot in MiB, if known.
+	Size uint64 `json:"size,omitempty"`
+
+	// Error, if non-empty, describes why the snapshot could
+	// not be created.
+	Error string `json:"error,omitempty"`
+}
+
+// VolumeSnapshotInfos holds the outcomes of creating volume snapshots.
+type VolumeSnapshotInfos struct {
+	Snapshots []VolumeSnapshotInfo `json:"snapshots"`
+}
+
+// StorageMigrationIds holds the IDs of storage migrations.
+type StorageMigrationIds struct {
+	Ids []string `json:"ids"`
+}
+
+// StorageMigrationParams holds the parameters for copying the data
+// of a storage migration from the source volume to the target volume.
+type StorageMigrationParams struct {
+	// Id is the ID of the storage migration in the model.
+	Id string `json:"id"`
+
+	// MachineTag is the tag of the machine that the source
+	// and target volumes are attached to.
+	MachineTag string `json:"machine-tag"`
+
+	// SourceDevicePath and TargetDevicePath are the paths of
+	// the source and target block devices on the machine.
+	SourceDevicePath string `json:"source-device-path"`
+	TargetDevicePath string `json:"target-device-path"`
+}
+
+// StorageMigrationParamsResult holds the parameters for copying the
+// data of a storage migration, or an error if the parameters are not
+// available.
+type StorageMigrationParamsResult struct {
+	Result StorageMigrationParams `json:"result"`
+	Error  *Error                 `json:"error,omitempty"`
+}
+
+// StorageMigrationParamsResults holds the parameters for copying the
+// data of multiple storage migrations.
+type StorageMigrationParamsResults struct {
+	Results []StorageMigrationParamsResult `json:"results,omitempty"`
+}
+
+// StorageMigrationOutcome records the outcome of copying the data of
+// a storage migration.
+type StorageMigrationOutcome struct {
+	// Id is the ID of the storage migration in the model.
+	Id string `json:"id"`
+
+	// Error, if non-empty, describes why the data could
+	// not be copied.
+	Error string `json:"error,omitempty"`
+}
+
+// StorageMigrationOutcomes holds the outcomes of storage migrations.
+type StorageMigrationOutcomes struct {
+	Outcomes []StorageMigrationOutcome `json:"outcomes"`
+}
+
 // VolumeAttachmentParamsResults holds provisioning parameters for a volume
 // attachment.
 type VolumeAttachmentParamsResult struct {
@@ -494,6 +702,34 @@ type RemoveFilesystemParamsResults struct {
 	Results []RemoveFilesystemParamsResult `json:"results,omitempty"`
 }
 
+// FilesystemResizeParams holds the parameters for growing a filesystem.
+type FilesystemResizeParams struct {
+	// FilesystemTag is the tag of the filesystem to resize.
+	FilesystemTag string `json:"filesystem-tag"`
+
+	// Provider is the storage provider that manages the filesystem.
+	Provider string `json:"provider"`
+
+	// Size is the size in MiB that the filesystem should be grown to.
+	Size uint64 `json:"size"`
+
+	// Info is the current information about the filesystem.
+	Info FilesystemInfo `json:"info"`
+}
+
+// FilesystemResizeParamsResult holds the parameters for growing a
+// filesystem, or an error if the filesystem does not need resizing.
+type FilesystemResizeParamsResult struct {
+	Result FilesystemResizeParams `json:"result"`
+	Error  *Error                 `json:"error,omitempty"`
+}
+
+// FilesystemResizeParamsResults holds the parameters for growing
+// multiple filesystems.
+type FilesystemResizeParamsResults struct {
+	Results []FilesystemResizeParamsResult `json:"results,omitempty"`
+}
+
 // FilesystemAttachmentParamsResults holds provisioning parameters for a filesystem
 // attachment.
 type FilesystemAttachmentParamsResult struct {
@@ -531,6 +767,11 @@ type StorageDetails struct {
 	// the machine that it is attached to.
 	Persistent bool `json:"persistent"`
 
+	// Encrypted reports whether or not the underlying volume, or
+	// the volume backing the underlying filesystem, is encrypted
+	// at rest.
+	Encrypted bool `json:"encrypted,omitempty"`
+
 	// Attachments contains a mapping from unit tag to
 	// storage attachment details.
 	Attachments map[string]StorageAttachmentDetails `json:"attachments,omitempty"`
@@ -805,6 +1046,10 @@ type FilesystemAttachmentDetails struct {
 	// Juju controllers older than 2.2 do not populate this
 	// field, so it may be omitted.
 	Life Life `json:"life,omitempty"`
+
+	// Usage contains the most recently reported usage of the
+	// attached filesystem, if any.
+	Usage *FilesystemUsage `json:"usage,omitempty"`
 }
 
 // FilesystemDetailsResult contains details about a filesystem, its attachments or
@@ -861,6 +1106,141 @@ type StoragesAddParams struct {
 	Storages []StorageAddParams `json:"storages"`
 }
 
+// ResizeStorageParams holds the parameters for resizing storage
+// instances.
+type ResizeStorageParams struct {
+	Storage []ResizeStorageArg `json:"storage"`
+}
+
+// ResizeStorageArg holds the parameters for resizing a storage instance.
+type ResizeStorageArg struct {
+	// StorageTag is the tag of the storage instance to resize.
+	StorageTag string `json:"storage-tag"`
+
+	// Size is the new size of the storage in MiB. Storage may only
+	// grow, so this must be larger than the current size.
+	Size uint64 `json:"size"`
+}
+
+// StorageSnapshotResult holds the result of creating a snapshot of
+// a storage instance.
+type StorageSnapshotResult struct {
+	// Id is the ID of the storage snapshot in the model.
+	Id    string `json:"id,omitempty"`
+	Error *Error `json:"error,omitempty"`
+}
+
+// StorageSnapshotResults holds the results of creating snapshots of
+// multiple storage instances.
+type StorageSnapshotResults struct {
+	Results []StorageSnapshotResult `json:"results"`
+}
+
+// StorageSnapshotDetails holds information about a storage snapshot.
+type StorageSnapshotDetails struct {
+	// Id is the ID of the storage snapshot in the model.
+	Id string `json:"id"`
+
+	// StorageTag is the tag of the storage instance that
+	// the snapshot was taken of.
+	StorageTag string `json:"storage-tag"`
+
+	// VolumeTag is the tag of the volume that the snapshot
+	// was taken of.
+	VolumeTag string `json:"volume-tag"`
+
+	// Pool is the name of the storage pool of the snapshotted volume.
+	Pool string `json:"pool"`
+
+	// Size is the size of the snapshot in MiB, if known.
+	Size uint64 `json:"size,omitempty"`
+
+	// SnapshotId is the provider-supplied ID of the snapshot.
+	SnapshotId string `json:"snapshot-id,omitempty"`
+
+	// Status is the status of the snapshot, and Message
+	// explains why the snapshot could not be created.
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+
+	// Created is the time at which the snapshot was requested.
+	Created time.Time `json:"created"`
+}
+
+// StorageSnapshotDetailsResults holds the details of storage snapshots.
+type StorageSnapshotDetailsResults struct {
+	Results []StorageSnapshotDetails `json:"results"`
+}
+
+// StorageMigrationArgs holds the parameters for migrating storage
+// instances to other storage pools.
+type StorageMigrationArgs struct {
+	Args []StorageMigrationArg `json:"args"`
+}
+
+// StorageMigrationArg holds the parameters for migrating a storage
+// instance to another storage pool.
+type StorageMigrationArg struct {
+	// StorageTag is the tag of the storage instance to migrate.
+	StorageTag string `json:"storage-tag"`
+
+	// Pool is the name of the storage pool to migrate the storage to.
+	Pool string `json:"pool"`
+}
+
+// StorageMigrationResult holds the result of starting a storage
+// migration.
+type StorageMigrationResult struct {
+	// Id is the ID of the storage migration in the model.
+	Id    string `json:"id,omitempty"`
+	Error *Error `json:"error,omitempty"`
+}
+
+// StorageMigrationResults holds the results of starting multiple
+// storage migrations.
+type StorageMigrationResults struct {
+	Results []StorageMigrationResult `json:"results"`
+}
+
+// StorageMigrationDetails holds information about a storage migration.
+type StorageMigrationDetails struct {
+	// Id is the ID of the storage migration in the model.
+	Id string `json:"id"`
+
+	// StorageTag is the tag of the migrated storage instance.
+	StorageTag string `json:"storage-tag"`
+
+	// MachineTag is the tag of the machine that copies the data.
+	MachineTag string `json:"machine-tag"`
+
+	// SourceVolumeTag and SourcePool identify the volume that
+	// backed the storage before the migration.
+	SourceVolumeTag string `json:"source-volume-tag"`
+	SourcePool      string `json:"source-pool"`
+
+	// TargetVolumeTag and TargetPool identify the volume that
+	// backs the storage after the migration.
+	TargetVolumeTag string `json:"target-volume-tag"`
+	TargetPool      string `json:"target-pool"`
+
+	// Status is the status of the migration, and Message
+	// explains why the migration failed.
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+
+	// Created is the time at which the migration was requested.
+	Created time.Time `json:"created"`
+
+	// Completed is the time at which the migration completed or
+	// failed, if it has.
+	Completed *time.Time `json:"completed,omitempty"`
+}
+
+// StorageMigrationDetailsResults holds the details of storage migrations.
+type StorageMigrationDetailsResults struct {
+	Results []StorageMigrationDetails `json:"results"`
+}
+
 // RemoveStorage holds the parameters for removing storage from the model.
 type RemoveStorage struct {
 	Storage []RemoveStorageInstance `json:"storage"`
@@ -591,6 +591,19 @@ func (sb *StubBacking) AddSpace(name string, providerId network.Id, subnets []st
 	return nil
 }
 
+func (sb *StubBacking) MoveSubnet(cidr, spaceName string, force bool) error {
+	sb.MethodCall(sb, "MoveSubnet", cidr, spaceName, force)
+	if err := sb.NextErr(); err != nil {
+		return err
+	}
+	for _, subnet := range sb.Subnets {
+		if subnet.CIDR() == cidr {
+			return nil
+		}
+	}
+	return errors.NewNotFound(nil, fmt.Sprintf("subnet %q", cidr))
+}
+
 func (sb *StubBacking) ReloadSpaces(environ environs.BootstrapEnviron) error {
 	sb.MethodCall(sb, "ReloadSpaces", environ)
 	if err := sb.NextErr(); err != nil {
@@ -599,6 +612,33 @@ func (sb *StubBacking) ReloadSpaces(environ environs.BootstrapEnviron) error {
 	return nil
 }
 
+func (sb *StubBacking) UnitEndpointNetworkInfo(unit names.UnitTag, endpoint string) (params.UnitEndpointNetworkInfoResult, error) {
+	sb.MethodCall(sb, "UnitEndpointNetworkInfo", unit, endpoint)
+	if err := sb.NextErr(); err != nil {
+		return params.UnitEndpointNetworkInfoResult{}, err
+	}
+	if endpoint != "db" {
+		return params.UnitEndpointNetworkInfoResult{}, errors.NotFoundf("endpoint %q of unit %q", endpoint, unit.Id())
+	}
+	// Give each unit an address in 10.10.0.0/24 derived from its number.
+	address := fmt.Sprintf("10.10.0.%d", unit.Number()+10)
+	return params.UnitEndpointNetworkInfoResult{
+		MachineTag: names.NewMachineTag(strconv.Itoa(unit.Number())).String(),
+		SpaceName:  "dmz",
+		Info: params.NetworkInfoResult{
+			Info: []params.NetworkInfo{{
+				InterfaceName: "eth0",
+				Addresses: []params.InterfaceAddress{{
+					Address: address,
+					CIDR:    "10.10.0.0/24",
+				}},
+			}},
+			EgressSubnets:    []string{address + "/32"},
+			IngressAddresses: []string{address},
+		},
+	}, nil
+}
+
 // GoString implements fmt.GoStringer.
 func (se *StubBacking) GoString() string {
 	return "&StubBacking{}"
@@ -12,6 +12,7 @@ import (
 	"github.com/juju/schema"
 	core "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/juju/juju/environs/context"
@@ -139,8 +140,12 @@ func newStorageConfig(attrs map[string]interface{}) (*storageConfig, error) {
 
 // ValidateConfig is defined on the storage.Provider interface.
 func (g *storageProvider) ValidateConfig(cfg *storage.Config) error {
-	_, err := newStorageConfig(cfg.Attrs())
-	return errors.Trace(err)
+	if _, err := newStorageConfig(cfg.Attrs()); err != nil {
+		return errors.Trace(err)
+	}
+	// Encryption is a property of the storage class, configured with
+	// its "parameters.*" attributes, not something Juju can honour.
+	return errors.Trace(storage.ValidateNoEncryption(cfg.Attrs(), "Kubernetes volumes"))
 }
 
 // Supports is defined on the storage.Provider interface.
@@ -185,6 +190,7 @@ type volumeSource struct {
 }
 
 var _ storage.VolumeSource = (*volumeSource)(nil)
+var _ storage.VolumeResizer = (*volumeSource)(nil)
 
 // CreateVolumes is specified on the storage.VolumeSource interface.
 func (v *volumeSource) CreateVolumes(ctx context.ProviderCallContext, params []storage.VolumeParams) (_ []storage.CreateVolumesResult, err error) {
@@ -287,6 +293,56 @@ func (v *volumeSource) DetachVolumes(ctx context.ProviderCallContext, attachPara
 	return make([]error, len(attachParams)), nil
 }
 
+// ResizeVolumes is specified on the storage.VolumeResizer interface.
+// The storage request of the claim bound to each volume is increased;
+// the storage class must allow volume expansion.
+func (v *volumeSource) ResizeVolumes(ctx context.ProviderCallContext, params []storage.VolumeResizeParams) ([]storage.ResizeResult, error) {
+	logger.Debugf("resize k8s volumes: %v", params)
+	volumeIds := make([]string, len(params))
+	sizes := make(map[string]uint64)
+	for i, p := range params {
+		volumeIds[i] = p.VolumeId
+		sizes[p.VolumeId] = p.Size
+	}
+	pVolumes := v.client.client().CoreV1().PersistentVolumes()
+	errs := foreachVolume(volumeIds, func(volumeId string) error {
+		vol, err := pVolumes.Get(volumeId, v1.GetOptions{})
+		if err != nil {
+			return errors.Annotatef(err, "getting volume %v to resize", volumeId)
+		}
+		claimRef := vol.Spec.ClaimRef
+		if claimRef == nil {
+			return errors.NotSupportedf("resizing unclaimed volume %v", volumeId)
+		}
+		pClaims := v.client.client().CoreV1().PersistentVolumeClaims(claimRef.Namespace)
+		pvc, err := pClaims.Get(claimRef.Name, v1.GetOptions{})
+		if err != nil {
+			return errors.Annotatef(err, "getting volume claim %v", claimRef.Name)
+		}
+		if pvc.Spec.Resources.Requests == nil {
+			pvc.Spec.Resources.Requests = core.ResourceList{}
+		}
+		size, err := resource.ParseQuantity(fmt.Sprintf("%dMi", sizes[volumeId]))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		pvc.Spec.Resources.Requests[core.ResourceStorage] = size
+		if _, err := pClaims.Update(pvc); err != nil {
+			return errors.Annotatef(err, "resizing volume claim %v", claimRef.Name)
+		}
+		return nil
+	})
+	results := make([]storage.ResizeResult, len(params))
+	for i, p := range params {
+		if errs[i] != nil {
+			results[i].Error = errs[i]
+			continue
+		}
+		results[i].Size = p.Size
+	}
+	return results, nil
+}
+
 func foreachVolume(volumeIds []string, f func(string) error) []error {
 	results := make([]error, len(volumeIds))
 	var wg sync.WaitGroup
@@ -61,6 +61,20 @@ func (s *storageSuite) TestValidateConfigError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "storage-class must be specified if storage-provisioner is specified")
 }
 
+func (s *storageSuite) TestValidateConfigEncrypted(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	p := s.k8sProvider(c, ctrl)
+	cfg, err := storage.NewConfig("name", provider.K8s_ProviderType, map[string]interface{}{
+		"storage-class": "my-storage",
+		"encrypted":     true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = p.ValidateConfig(cfg)
+	c.Assert(err, gc.ErrorMatches, `"encrypted" for Kubernetes volumes not supported`)
+}
+
 func (s *storageSuite) TestNewStorageConfig(c *gc.C) {
 	ctrl := s.setupController(c)
 	defer ctrl.Finish()
@@ -118,6 +132,54 @@ func (s *storageSuite) TestDestroyVolumes(c *gc.C) {
 	c.Assert(errs, jc.DeepEquals, []error{nil})
 }
 
+func (s *storageSuite) TestResizeVolumes(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	pvc := &core.PersistentVolumeClaim{
+		ObjectMeta: v1.ObjectMeta{Name: "vol-1-pvc", Namespace: "test"},
+		Spec: core.PersistentVolumeClaimSpec{
+			Resources: core.ResourceRequirements{
+				Requests: core.ResourceList{
+					core.ResourceStorage: resource.MustParse("1024Mi"),
+				},
+			},
+		},
+	}
+	resized := &core.PersistentVolumeClaim{
+		ObjectMeta: v1.ObjectMeta{Name: "vol-1-pvc", Namespace: "test"},
+		Spec: core.PersistentVolumeClaimSpec{
+			Resources: core.ResourceRequirements{
+				Requests: core.ResourceList{
+					core.ResourceStorage: resource.MustParse("2048Mi"),
+				},
+			},
+		},
+	}
+	gomock.InOrder(
+		s.mockPersistentVolumes.EXPECT().Get("vol-1", v1.GetOptions{}).
+			Return(&core.PersistentVolume{
+				Spec: core.PersistentVolumeSpec{
+					ClaimRef: &core.ObjectReference{Namespace: "test", Name: "vol-1-pvc"},
+				}}, nil),
+		s.mockPersistentVolumeClaims.EXPECT().Get("vol-1-pvc", v1.GetOptions{}).
+			Return(pvc, nil),
+		s.mockPersistentVolumeClaims.EXPECT().Update(resized).
+			Return(resized, nil),
+	)
+
+	p := s.k8sProvider(c, ctrl)
+	vs, err := p.VolumeSource(&storage.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := vs.(storage.VolumeResizer).ResizeVolumes(&context.CloudCallContext{}, []storage.VolumeResizeParams{{
+		VolumeId: "vol-1",
+		Size:     2048,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.ResizeResult{{Size: 2048}})
+}
+
 func (s *storageSuite) TestDestroyVolumesNotFoundIgnored(c *gc.C) {
 	ctrl := s.setupController(c)
 	defer ctrl.Finish()
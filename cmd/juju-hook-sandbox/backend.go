@@ -0,0 +1,605 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/httprequest"
+	"github.com/juju/utils"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/macaroon-bakery.v2-unstable/httpbakery"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/leadership"
+	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+const (
+	uniterFacade = "Uniter"
+
+	// uniterFacadeVersion is the version of the Uniter facade the
+	// backend claims to implement.
+	uniterFacadeVersion = 14
+
+	sandboxModelName = "sandbox"
+	sandboxMachineId = "0"
+)
+
+// backend is an in-memory implementation of the Uniter API facade,
+// seeded from a fixture, against which the uniter's hook context runs.
+// Every facade call made is recorded, and the state the hook may change
+// is kept so that it can be compared before and after the hook runs.
+type backend struct {
+	unitTag   names.UnitTag
+	appName   string
+	modelUUID string
+	fixture   *Fixture
+	config    charm.Settings
+
+	// mu guards everything below.
+	mu                sync.Mutex
+	calls             []string
+	leaderSettings    map[string]string
+	unitStatus        params.EntityStatusArgs
+	applicationStatus params.EntityStatusArgs
+	workloadVersion   string
+	ports             []params.PortRange
+	rebootRequested   bool
+	relations         map[int]*backendRelation
+}
+
+// backendRelation holds the state of one of the unit's relations.
+type backendRelation struct {
+	id                  int
+	tag                 names.RelationTag
+	endpoint            charm.Relation
+	otherApp            string
+	settings            map[string]string
+	applicationSettings map[string]string
+	units               map[string]map[string]string
+}
+
+var _ base.APICaller = (*backend)(nil)
+
+func newBackend(fixture *Fixture, ch charm.Charm) (*backend, error) {
+	settings, err := fixture.configSettings(ch)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	appName, err := names.UnitApplication(fixture.Unit)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	modelUUID, err := utils.NewUUID()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	b := &backend{
+		unitTag:           names.NewUnitTag(fixture.Unit),
+		appName:           appName,
+		modelUUID:         modelUUID.String(),
+		fixture:           fixture,
+		config:            settings,
+		leaderSettings:    copyStrings(fixture.LeaderSettings),
+		unitStatus:        params.EntityStatusArgs{Status: status.Unknown.String()},
+		applicationStatus: params.EntityStatusArgs{Status: status.Unknown.String()},
+		relations:         make(map[int]*backendRelation),
+	}
+	for _, fixtureRel := range fixture.Relations {
+		endpoint, ok := charmRelation(ch.Meta(), fixtureRel.Endpoint)
+		if !ok {
+			return nil, errors.NotFoundf("charm endpoint %q for relation %d", fixtureRel.Endpoint, fixtureRel.Id)
+		}
+		rel := &backendRelation{
+			id:                  fixtureRel.Id,
+			endpoint:            endpoint,
+			settings:            copyStrings(fixtureRel.Settings),
+			applicationSettings: copyStrings(fixtureRel.ApplicationSettings),
+			units:               make(map[string]map[string]string),
+		}
+		for unitName, unitSettings := range fixtureRel.Units {
+			rel.units[unitName] = copyStrings(unitSettings)
+		}
+		if endpoint.Role == charm.RolePeer {
+			rel.otherApp = appName
+			rel.tag = names.NewRelationTag(fmt.Sprintf("%s:%s", appName, endpoint.Name))
+		} else {
+			if memberNames := rel.memberNames(); len(memberNames) > 0 {
+				rel.otherApp, _ = names.UnitApplication(memberNames[0])
+			} else {
+				rel.otherApp = "remote"
+			}
+			rel.tag = names.NewRelationTag(fmt.Sprintf("%s:%s %s:%s", rel.otherApp, endpoint.Name, appName, endpoint.Name))
+		}
+		b.relations[rel.id] = rel
+	}
+	return b, nil
+}
+
+// memberNames returns the sorted names of the related units.
+func (rel *backendRelation) memberNames() []string {
+	var unitNames []string
+	for unitName := range rel.units {
+		unitNames = append(unitNames, unitName)
+	}
+	sort.Strings(unitNames)
+	return unitNames
+}
+
+// APICall is part of the base.APICaller interface.
+func (b *backend) APICall(objType string, version int, id, request string, args, response interface{}) error {
+	if objType != uniterFacade {
+		return errors.NotSupportedf("facade %q", objType)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.calls = append(b.calls, request)
+
+	switch request {
+	case "Refresh":
+		*response.(*params.UnitRefreshResults) = params.UnitRefreshResults{
+			Results: []params.UnitRefreshResult{{Life: params.Alive}},
+		}
+	case "Life":
+		*response.(*params.LifeResults) = params.LifeResults{
+			Results: []params.LifeResult{{Life: params.Alive}},
+		}
+	case "CurrentModel":
+		*response.(*params.ModelResult) = params.ModelResult{
+			Name: sandboxModelName,
+			UUID: b.modelUUID,
+			Type: model.IAAS.String(),
+		}
+	case "ModelConfig":
+		cfg, err := config.New(config.UseDefaults, map[string]interface{}{
+			"name": sandboxModelName,
+			"type": sandboxName,
+			"uuid": b.modelUUID,
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		*response.(*params.ModelConfigResult) = params.ModelConfigResult{Config: cfg.AllAttrs()}
+	case "APIAddresses":
+		*response.(*params.StringsResult) = params.StringsResult{}
+	case "SLALevel":
+		*response.(*params.StringResult) = params.StringResult{Result: "unsupported"}
+	case "CloudAPIVersion":
+		*response.(*params.StringResult) = params.StringResult{}
+	case "AssignedMachine":
+		b.stringResult(response, names.NewMachineTag(sandboxMachineId).String())
+	case "AvailabilityZone":
+		b.stringResult(response, b.fixture.AvailabilityZone)
+	case "PublicAddress":
+		b.addressResult(response, b.fixture.PublicAddress, "public")
+	case "PrivateAddress":
+		b.addressResult(response, b.fixture.PrivateAddress, "private")
+	case "GetPrincipal":
+		*response.(*params.StringBoolResults) = params.StringBoolResults{
+			Results: []params.StringBoolResult{{}},
+		}
+	case "GetMeterStatus":
+		*response.(*params.MeterStatusResults) = params.MeterStatusResults{
+			Results: []params.MeterStatusResult{{Code: "NOT SET"}},
+		}
+	case "ConfigSettings":
+		*response.(*params.ConfigSettingsResults) = params.ConfigSettingsResults{
+			Results: []params.ConfigSettingsResult{{Settings: params.ConfigSettings(b.config)}},
+		}
+	case "UnitStatus":
+		*response.(*params.StatusResults) = params.StatusResults{
+			Results: []params.StatusResult{statusResult(b.unitStatus)},
+		}
+	case "SetUnitStatus":
+		b.unitStatus = args.(params.SetStatus).Entities[0]
+		b.errorResult(response, nil)
+	case "ApplicationStatus":
+		*response.(*params.ApplicationStatusResults) = params.ApplicationStatusResults{
+			Results: []params.ApplicationStatusResult{{
+				Application: statusResult(b.applicationStatus),
+				Units: map[string]params.StatusResult{
+					b.unitTag.Id(): statusResult(b.unitStatus),
+				},
+			}},
+		}
+	case "SetApplicationStatus":
+		b.applicationStatus = args.(params.SetStatus).Entities[0]
+		b.errorResult(response, nil)
+	case "WorkloadVersion":
+		b.stringResult(response, b.workloadVersion)
+	case "SetWorkloadVersion":
+		b.workloadVersion = args.(params.EntityWorkloadVersions).Entities[0].WorkloadVersion
+		b.errorResult(response, nil)
+	case "AllMachinePorts":
+		var ports []params.MachinePortRange
+		for _, portRange := range b.ports {
+			ports = append(ports, params.MachinePortRange{
+				UnitTag:   b.unitTag.String(),
+				PortRange: portRange,
+			})
+		}
+		*response.(*params.MachinePortsResults) = params.MachinePortsResults{
+			Results: []params.MachinePortsResult{{Ports: ports}},
+		}
+	case "OpenPorts":
+		arg := args.(params.EntitiesPortRanges).Entities[0]
+		b.ports = append(b.ports, params.PortRange{
+			Protocol: arg.Protocol,
+			FromPort: arg.FromPort,
+			ToPort:   arg.ToPort,
+		})
+		b.errorResult(response, nil)
+	case "ClosePorts":
+		arg := args.(params.EntitiesPortRanges).Entities[0]
+		var ports []params.PortRange
+		for _, portRange := range b.ports {
+			if portRange.Protocol != arg.Protocol || portRange.FromPort != arg.FromPort || portRange.ToPort != arg.ToPort {
+				ports = append(ports, portRange)
+			}
+		}
+		b.ports = ports
+		b.errorResult(response, nil)
+	case "RequestReboot":
+		b.rebootRequested = true
+		b.errorResult(response, nil)
+	case "UpdateNetworkInfo":
+		b.errorResult(response, nil)
+	case "Read":
+		*response.(*params.GetLeadershipSettingsBulkResults) = params.GetLeadershipSettingsBulkResults{
+			Results: []params.GetLeadershipSettingsResult{{Settings: copyStrings(b.leaderSettings)}},
+		}
+	case "Merge":
+		if !b.fixture.Leader {
+			b.errorResult(response, errNotLeader())
+			break
+		}
+		mergeSettings(b.leaderSettings, args.(params.MergeLeadershipSettingsBulkParams).Params[0].Settings)
+		b.errorResult(response, nil)
+	case "Relation":
+		arg := args.(params.RelationUnits).RelationUnits[0]
+		b.relationResult(response, b.relationByTag(arg.Relation))
+	case "RelationById":
+		b.relationResult(response, b.relations[args.(params.RelationIds).RelationIds[0]])
+	case "ReadSettings":
+		arg := args.(params.RelationUnits).RelationUnits[0]
+		settings, err := b.readSettings(arg.Relation, arg.Unit)
+		b.settingsResult(response, settings, err)
+	case "ReadRemoteSettings":
+		arg := args.(params.RelationUnitPairs).RelationUnitPairs[0]
+		settings, err := b.readRemoteSettings(arg.Relation, arg.RemoteUnit)
+		b.settingsResult(response, settings, err)
+	case "UpdateSettings":
+		arg := args.(params.RelationUnitsSettings).RelationUnits[0]
+		b.errorResult(response, b.updateSettings(arg))
+	default:
+		return errors.NotSupportedf("%s.%s in %s", objType, request, sandboxName)
+	}
+	return nil
+}
+
+func (b *backend) stringResult(response interface{}, result string) {
+	*response.(*params.StringResults) = params.StringResults{
+		Results: []params.StringResult{{Result: result}},
+	}
+}
+
+func (b *backend) addressResult(response interface{}, address, scope string) {
+	result := params.StringResult{Result: address}
+	if address == "" {
+		result.Error = &params.Error{
+			Code:    params.CodeNoAddressSet,
+			Message: fmt.Sprintf("%q has no %s address set", b.unitTag.Id(), scope),
+		}
+	}
+	*response.(*params.StringResults) = params.StringResults{
+		Results: []params.StringResult{result},
+	}
+}
+
+func (b *backend) errorResult(response interface{}, err *params.Error) {
+	*response.(*params.ErrorResults) = params.ErrorResults{
+		Results: []params.ErrorResult{{Error: err}},
+	}
+}
+
+func (b *backend) relationResult(response interface{}, rel *backendRelation) {
+	result := params.RelationResult{Error: errRelationNotFound()}
+	if rel != nil {
+		result = params.RelationResult{
+			Life: params.Alive,
+			Id:   rel.id,
+			Key:  rel.tag.Id(),
+			Endpoint: params.Endpoint{
+				ApplicationName: b.appName,
+				Relation: params.CharmRelation{
+					Name:      rel.endpoint.Name,
+					Role:      string(rel.endpoint.Role),
+					Interface: rel.endpoint.Interface,
+					Optional:  rel.endpoint.Optional,
+					Limit:     rel.endpoint.Limit,
+					Scope:     string(rel.endpoint.Scope),
+				},
+			},
+			OtherApplication: rel.otherApp,
+		}
+	}
+	*response.(*params.RelationResults) = params.RelationResults{
+		Results: []params.RelationResult{result},
+	}
+}
+
+func (b *backend) settingsResult(response interface{}, settings map[string]string, err *params.Error) {
+	*response.(*params.SettingsResults) = params.SettingsResults{
+		Results: []params.SettingsResult{{Settings: settings, Error: err}},
+	}
+}
+
+func (b *backend) relationByTag(relationTag string) *backendRelation {
+	for _, rel := range b.relations {
+		if rel.tag.String() == relationTag {
+			return rel
+		}
+	}
+	return nil
+}
+
+// readSettings returns the settings of the unit, or of its application,
+// in the relation.
+func (b *backend) readSettings(relationTag, tag string) (map[string]string, *params.Error) {
+	rel := b.relationByTag(relationTag)
+	if rel == nil {
+		return nil, errRelationNotFound()
+	}
+	if tag == b.unitTag.String() {
+		return copyStrings(rel.settings), nil
+	}
+	if !b.fixture.Leader {
+		return nil, errNotLeader()
+	}
+	return copyStrings(rel.applicationSettings), nil
+}
+
+// readRemoteSettings returns the settings of a related unit or
+// application in the relation.
+func (b *backend) readRemoteSettings(relationTag, tag string) (map[string]string, *params.Error) {
+	rel := b.relationByTag(relationTag)
+	if rel == nil {
+		return nil, errRelationNotFound()
+	}
+	parsed, err := names.ParseTag(tag)
+	if err != nil {
+		return nil, &params.Error{Message: err.Error()}
+	}
+	if parsed.Kind() == names.ApplicationTagKind {
+		if parsed.Id() == b.appName {
+			return copyStrings(rel.applicationSettings), nil
+		}
+		return map[string]string{}, nil
+	}
+	settings, ok := rel.units[parsed.Id()]
+	if !ok {
+		return nil, &params.Error{
+			Code:    params.CodeNotFound,
+			Message: fmt.Sprintf("unit %q not found in relation %d", parsed.Id(), rel.id),
+		}
+	}
+	return copyStrings(settings), nil
+}
+
+func (b *backend) updateSettings(arg params.RelationUnitSettings) *params.Error {
+	rel := b.relationByTag(arg.Relation)
+	if rel == nil {
+		return errRelationNotFound()
+	}
+	if len(arg.ApplicationSettings) > 0 && !b.fixture.Leader {
+		return errNotLeader()
+	}
+	mergeSettings(rel.settings, arg.Settings)
+	mergeSettings(rel.applicationSettings, arg.ApplicationSettings)
+	return nil
+}
+
+// BestFacadeVersion is part of the base.APICaller interface.
+func (b *backend) BestFacadeVersion(facade string) int {
+	if facade == uniterFacade {
+		return uniterFacadeVersion
+	}
+	return 0
+}
+
+// ModelTag is part of the base.APICaller interface.
+func (b *backend) ModelTag() (names.ModelTag, bool) {
+	return names.NewModelTag(b.modelUUID), true
+}
+
+// HTTPClient is part of the base.APICaller interface.
+func (b *backend) HTTPClient() (*httprequest.Client, error) {
+	return nil, errors.NotSupportedf("HTTP requests in %s", sandboxName)
+}
+
+// BakeryClient is part of the base.APICaller interface.
+func (b *backend) BakeryClient() *httpbakery.Client {
+	return nil
+}
+
+// ConnectStream is part of the base.APICaller interface.
+func (b *backend) ConnectStream(path string, attrs url.Values) (base.Stream, error) {
+	return nil, errors.NotSupportedf("streams in %s", sandboxName)
+}
+
+// ConnectControllerStream is part of the base.APICaller interface.
+func (b *backend) ConnectControllerStream(path string, attrs url.Values, headers http.Header) (base.Stream, error) {
+	return nil, errors.NotSupportedf("streams in %s", sandboxName)
+}
+
+// callCount returns the number of facade calls made so far.
+func (b *backend) callCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.calls)
+}
+
+// callsSince returns the facade calls made after the first start calls.
+func (b *backend) callsSince(start int) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.calls[start:]...)
+}
+
+// snapshot returns the parts of the backend's state a hook may change.
+func (b *backend) snapshot() unitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := make(unitState)
+	for k, v := range b.leaderSettings {
+		state["leader-settings."+k] = v
+	}
+	state["status"] = strings.TrimSpace(b.unitStatus.Status + " " + b.unitStatus.Info)
+	state["application-status"] = strings.TrimSpace(b.applicationStatus.Status + " " + b.applicationStatus.Info)
+	if b.workloadVersion != "" {
+		state["workload-version"] = b.workloadVersion
+	}
+	var ports []string
+	for _, portRange := range b.ports {
+		ports = append(ports, portRange.NetworkPortRange().String())
+	}
+	if len(ports) > 0 {
+		sort.Strings(ports)
+		state["opened-ports"] = strings.Join(ports, ",")
+	}
+	for id, rel := range b.relations {
+		prefix := fmt.Sprintf("relation.%s:%d.", rel.endpoint.Name, id)
+		for k, v := range rel.settings {
+			state[prefix+"unit."+k] = v
+		}
+		for k, v := range rel.applicationSettings {
+			state[prefix+"application."+k] = v
+		}
+	}
+	if b.rebootRequested {
+		state["reboot"] = "requested"
+	}
+	return state
+}
+
+// tracker is a leadership.Tracker which reports the leadership given
+// in the fixture.
+type tracker struct {
+	appName string
+	leader  bool
+}
+
+var _ leadership.Tracker = (*tracker)(nil)
+
+// ApplicationName is part of the leadership.Tracker interface.
+func (t *tracker) ApplicationName() string {
+	return t.appName
+}
+
+// ClaimDuration is part of the leadership.Tracker interface.
+func (t *tracker) ClaimDuration() time.Duration {
+	return time.Minute
+}
+
+// ClaimLeader is part of the leadership.Tracker interface.
+func (t *tracker) ClaimLeader() leadership.Ticket {
+	return ticket(t.leader)
+}
+
+// WaitLeader is part of the leadership.Tracker interface.
+func (t *tracker) WaitLeader() leadership.Ticket {
+	return ticket(t.leader)
+}
+
+// WaitMinion is part of the leadership.Tracker interface.
+func (t *tracker) WaitMinion() leadership.Ticket {
+	return ticket(!t.leader)
+}
+
+// ticket is a leadership.Ticket whose result is known up front.
+type ticket bool
+
+// Wait is part of the leadership.Ticket interface.
+func (t ticket) Wait() bool {
+	return bool(t)
+}
+
+// Ready is part of the leadership.Ticket interface.
+func (t ticket) Ready() <-chan struct{} {
+	ready := make(chan struct{})
+	close(ready)
+	return ready
+}
+
+// noStorage is a context.StorageContextAccessor for a unit with no
+// storage attached.
+type noStorage struct{}
+
+// StorageTags is part of the context.StorageContextAccessor interface.
+func (noStorage) StorageTags() ([]names.StorageTag, error) {
+	return nil, nil
+}
+
+// Storage is part of the context.StorageContextAccessor interface.
+func (noStorage) Storage(tag names.StorageTag) (jujuc.ContextStorageAttachment, error) {
+	return nil, errors.NotFoundf("storage %s", tag.Id())
+}
+
+// charmRelation returns the charm's relation for the named endpoint.
+func charmRelation(meta *charm.Meta, endpoint string) (charm.Relation, bool) {
+	for _, relations := range []map[string]charm.Relation{meta.Provides, meta.Requires, meta.Peers} {
+		if rel, ok := relations[endpoint]; ok {
+			return rel, true
+		}
+	}
+	return charm.Relation{}, false
+}
+
+// mergeSettings applies changes to settings; an empty value deletes
+// the key, as it does in the controller.
+func mergeSettings(settings, changes map[string]string) {
+	for k, v := range changes {
+		if v == "" {
+			delete(settings, k)
+		} else {
+			settings[k] = v
+		}
+	}
+}
+
+func statusResult(args params.EntityStatusArgs) params.StatusResult {
+	return params.StatusResult{
+		Status: args.Status,
+		Info:   args.Info,
+		Data:   args.Data,
+	}
+}
+
+func errNotLeader() *params.Error {
+	return &params.Error{
+		Code:    params.CodeUnauthorized,
+		Message: "permission denied: this unit is not the leader",
+	}
+}
+
+func errRelationNotFound() *params.Error {
+	return &params.Error{
+		Code:    params.CodeNotFound,
+		Message: "relation not found",
+	}
+}
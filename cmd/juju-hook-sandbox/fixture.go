@@ -0,0 +1,154 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+	"sort"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/yaml.v2"
+)
+
+// Fixture describes the model, as seen by a single unit, in which a
+// hook is run.
+type Fixture struct {
+	// Unit is the name of the unit running the hook.
+	Unit string `yaml:"unit"`
+
+	// Config holds charm config values, overriding the defaults
+	// declared by the charm.
+	Config map[string]interface{} `yaml:"config"`
+
+	// Leader is true if the unit is the leader of its application.
+	Leader bool `yaml:"leader"`
+
+	// LeaderSettings holds the application's leader settings.
+	LeaderSettings map[string]string `yaml:"leader-settings"`
+
+	// AvailabilityZone is the availability zone of the unit's machine.
+	AvailabilityZone string `yaml:"availability-zone"`
+
+	// PublicAddress and PrivateAddress are the unit's addresses.
+	PublicAddress  string `yaml:"public-address"`
+	PrivateAddress string `yaml:"private-address"`
+
+	// Relations holds the relations the unit is participating in.
+	Relations []FixtureRelation `yaml:"relations"`
+
+	// RemoteUnit is the remote unit for relation hooks. If not set,
+	// the first related unit is used.
+	RemoteUnit string `yaml:"remote-unit"`
+}
+
+// FixtureRelation describes one of the unit's relations.
+type FixtureRelation struct {
+	// Id is the relation id.
+	Id int `yaml:"id"`
+
+	// Endpoint is the name of the unit's endpoint in the relation.
+	Endpoint string `yaml:"endpoint"`
+
+	// Settings holds the relation settings of the unit running the hook.
+	Settings map[string]string `yaml:"settings"`
+
+	// ApplicationSettings holds the relation settings of the application.
+	ApplicationSettings map[string]string `yaml:"application-settings"`
+
+	// Units holds the relation settings of each related unit.
+	Units map[string]map[string]string `yaml:"units"`
+}
+
+// ReadFixture reads and validates the fixture in the named file.
+func ReadFixture(path string) (*Fixture, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ParseFixture(data)
+}
+
+// ParseFixture parses and validates the supplied YAML fixture.
+func ParseFixture(data []byte) (*Fixture, error) {
+	var fixture Fixture
+	if err := yaml.UnmarshalStrict(data, &fixture); err != nil {
+		return nil, errors.Annotate(err, "cannot parse fixture")
+	}
+	if err := fixture.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &fixture, nil
+}
+
+// Validate checks that the fixture is consistent.
+func (f *Fixture) Validate() error {
+	if !names.IsValidUnit(f.Unit) {
+		return errors.NotValidf("unit name %q", f.Unit)
+	}
+	ids := make(map[int]bool)
+	for _, rel := range f.Relations {
+		if ids[rel.Id] {
+			return errors.Errorf("relation id %d used more than once", rel.Id)
+		}
+		ids[rel.Id] = true
+		if rel.Endpoint == "" {
+			return errors.Errorf("relation %d has no endpoint", rel.Id)
+		}
+		for unitName := range rel.Units {
+			if !names.IsValidUnit(unitName) {
+				return errors.NotValidf("relation %d unit name %q", rel.Id, unitName)
+			}
+		}
+	}
+	if f.RemoteUnit != "" && !names.IsValidUnit(f.RemoteUnit) {
+		return errors.NotValidf("remote unit name %q", f.RemoteUnit)
+	}
+	return nil
+}
+
+// relation returns the relation for the given endpoint, if any.
+func (f *Fixture) relation(endpoint string) (FixtureRelation, bool) {
+	for _, rel := range f.Relations {
+		if rel.Endpoint == endpoint {
+			return rel, true
+		}
+	}
+	return FixtureRelation{}, false
+}
+
+// remoteUnit returns the remote unit to use when running a hook for
+// the given relation.
+func (f *Fixture) remoteUnit(rel FixtureRelation) string {
+	if f.RemoteUnit != "" {
+		return f.RemoteUnit
+	}
+	var unitNames []string
+	for unitName := range rel.Units {
+		unitNames = append(unitNames, unitName)
+	}
+	if len(unitNames) == 0 {
+		return ""
+	}
+	sort.Strings(unitNames)
+	return unitNames[0]
+}
+
+// configSettings returns the charm's default config overlaid with the
+// values in the fixture.
+func (f *Fixture) configSettings(ch charm.Charm) (charm.Settings, error) {
+	settings := ch.Config().DefaultSettings()
+	if len(f.Config) == 0 {
+		return settings, nil
+	}
+	overrides, err := ch.Config().ValidateSettings(f.Config)
+	if err != nil {
+		return nil, errors.Annotate(err, "invalid config")
+	}
+	for k, v := range overrides {
+		settings[k] = v
+	}
+	return settings, nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/exec"
+
+	"github.com/juju/juju/juju/sockets"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+// symlinkTools creates a link to the running executable in dir for
+// each hook tool, so that hook tool invocations are sent to the sandbox.
+func symlinkTools(dir string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, name := range jujuc.CommandNames() {
+		if err := os.Symlink(executable, filepath.Join(dir, name)); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// hookToolMain asks the sandbox identified by JUJU_CONTEXT_ID and
+// JUJU_AGENT_SOCKET_ADDRESS to run the named hook tool on our behalf.
+func hookToolMain(commandName string, args []string) (int, error) {
+	contextId := os.Getenv("JUJU_CONTEXT_ID")
+	address := os.Getenv("JUJU_AGENT_SOCKET_ADDRESS")
+	if contextId == "" || address == "" {
+		return 1, errors.Errorf("%s must be run by a hook in %s", commandName, sandboxName)
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return 1, errors.Trace(err)
+	}
+	req := jujuc.Request{
+		ContextId:   contextId,
+		Dir:         dir,
+		CommandName: commandName,
+		Args:        args[1:],
+	}
+	client, err := sockets.Dial(sockets.Socket{
+		Network: os.Getenv("JUJU_AGENT_SOCKET_NETWORK"),
+		Address: address,
+	})
+	if err != nil {
+		return 1, errors.Trace(err)
+	}
+	defer client.Close()
+
+	var resp exec.ExecResponse
+	err = client.Call("Jujuc.Main", req, &resp)
+	if err != nil && err.Error() == jujuc.ErrNoStdin.Error() {
+		req.Stdin, err = ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return 1, errors.Annotate(err, "cannot read stdin")
+		}
+		req.StdinSet = true
+		err = client.Call("Jujuc.Main", req, &resp)
+	}
+	if err != nil {
+		return 1, errors.Trace(err)
+	}
+	os.Stdout.Write(resp.Stdout)
+	os.Stderr.Write(resp.Stderr)
+	return resp.Code, nil
+}
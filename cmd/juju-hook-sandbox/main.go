@@ -0,0 +1,38 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/juju/cmd"
+)
+
+// sandboxName is the name of the sandbox executable. When invoked
+// under any other name, it acts as the hook tool of that name.
+const sandboxName = "juju-hook-sandbox"
+
+func main() {
+	os.Exit(Main(os.Args))
+}
+
+// Main is not redundant with main(), because it provides an entry point
+// for testing with arbitrary command line arguments.
+func Main(args []string) int {
+	ctx, err := cmd.DefaultContext()
+	if err != nil {
+		cmd.WriteError(os.Stderr, err)
+		return 2
+	}
+	commandName := filepath.Base(args[0])
+	if commandName == sandboxName {
+		return cmd.Main(newSandboxCommand(), ctx, args[1:])
+	}
+	code, err := hookToolMain(commandName, args)
+	if err != nil {
+		cmd.WriteError(ctx.Stderr, err)
+	}
+	return code
+}
@@ -0,0 +1,17 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"os/exec"
+)
+
+// setProcessGroup does nothing on Windows, which has no process groups
+// that can be killed as one.
+func setProcessGroup(c *exec.Cmd) {}
+
+// killProcessGroup kills the started command.
+func killProcessGroup(c *exec.Cmd) error {
+	return c.Process.Kill()
+}
@@ -0,0 +1,534 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/charm.v6/hooks"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/api/uniter"
+	"github.com/juju/juju/juju/sockets"
+	"github.com/juju/juju/worker/uniter/hook"
+	"github.com/juju/juju/worker/uniter/runner/context"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+const sandboxDoc = `
+Runs a single hook from a local charm directory against a fake unit
+agent, without a deployed model.
+
+The hook runs in the unit agent's own hook context, backed by a fake
+controller API seeded from a YAML fixture, which may include charm
+config, leadership state and relation data. Every hook tool invoked by
+the hook is recorded, along with the API calls it made, and any changes
+the hook made to the unit's state are reported once it completes. As in
+the unit agent, changes are only written if the hook succeeds.
+
+The fixture has the following form:
+
+    unit: mysql/0
+    config:
+      port: 3306
+    leader: true
+    leader-settings:
+      password: secret
+    relations:
+    - id: 1
+      endpoint: db
+      settings:
+        host: 10.0.0.1
+      application-settings:
+        database: wordpress
+      units:
+        wordpress/0:
+          user: wp
+    remote-unit: wordpress/0
+
+Each relation endpoint must be declared by the charm. For relation
+hooks, the hook relation is the fixture relation for the endpoint named
+by the hook, and the remote unit is remote-unit or else the first of
+the relation's units.
+
+If the hook does not complete within the timeout, it is killed along
+with any processes it started.
+
+Examples:
+
+    juju-hook-sandbox --fixture fixture.yaml ./mysql install
+    juju-hook-sandbox --fixture fixture.yaml ./mysql db-relation-changed --format json
+`
+
+// sandboxCommand runs a charm hook against a fake unit agent.
+type sandboxCommand struct {
+	cmd.CommandBase
+	out         cmd.Output
+	fixturePath string
+	timeout     time.Duration
+	charmDir    string
+	hookName    string
+
+	// writeTools creates the hook tools in the supplied directory.
+	writeTools func(dir string) error
+}
+
+func newSandboxCommand() *sandboxCommand {
+	return &sandboxCommand{writeTools: symlinkTools}
+}
+
+// Info implements cmd.Command.
+func (c *sandboxCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    sandboxName,
+		Args:    "<charm dir> <hook name>",
+		Purpose: "Run a charm hook against a fake unit agent.",
+		Doc:     sandboxDoc,
+	}
+}
+
+// SetFlags implements cmd.Command.
+func (c *sandboxCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+	f.StringVar(&c.fixturePath, "fixture", "", "Path to a YAML fixture describing the hook context")
+	f.DurationVar(&c.timeout, "timeout", 5*time.Minute, "How long to wait for the hook to complete")
+}
+
+// Init implements cmd.Command.
+func (c *sandboxCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.New("a charm directory and hook name must be supplied")
+	}
+	c.charmDir, c.hookName = args[0], args[1]
+	return cmd.CheckEmpty(args[2:])
+}
+
+// Run implements cmd.Command.
+func (c *sandboxCommand) Run(ctx *cmd.Context) error {
+	fixture := &Fixture{Unit: "sandbox/0"}
+	if c.fixturePath != "" {
+		var err error
+		fixture, err = ReadFixture(ctx.AbsPath(c.fixturePath))
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	charmDir, err := filepath.Abs(ctx.AbsPath(c.charmDir))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ch, err := charm.ReadCharmDir(charmDir)
+	if err != nil {
+		return errors.Annotate(err, "cannot read charm")
+	}
+	hookPath := filepath.Join(charmDir, "hooks", c.hookName)
+	if _, err := os.Stat(hookPath); err != nil {
+		return errors.Annotatef(err, "cannot find hook %q", c.hookName)
+	}
+
+	sandbox, err := newSandbox(fixture, ch, c.hookName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	report, err := sandbox.run(hookPath, charmDir, c.timeout, c.writeTools)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := c.out.Write(ctx, report); err != nil {
+		return errors.Trace(err)
+	}
+	if report.ExitCode != 0 {
+		return cmd.NewRcPassthroughError(report.ExitCode)
+	}
+	return nil
+}
+
+// Report describes the outcome of running a hook in the sandbox.
+type Report struct {
+	Hook      string       `yaml:"hook" json:"hook"`
+	Unit      string       `yaml:"unit" json:"unit"`
+	ExitCode  int          `yaml:"exit-code" json:"exit-code"`
+	Stdout    string       `yaml:"stdout,omitempty" json:"stdout,omitempty"`
+	Stderr    string       `yaml:"stderr,omitempty" json:"stderr,omitempty"`
+	ToolCalls []ToolCall   `yaml:"tool-calls,omitempty" json:"tool-calls,omitempty"`
+	Changes   []StateDelta `yaml:"changes,omitempty" json:"changes,omitempty"`
+}
+
+// ToolCall records a single hook tool invocation.
+type ToolCall struct {
+	Command string   `yaml:"command" json:"command"`
+	Args    []string `yaml:"args,omitempty" json:"args,omitempty"`
+	// APICalls holds the Uniter API calls made by the tool.
+	APICalls []string `yaml:"api-calls,omitempty" json:"api-calls,omitempty"`
+	Error    string   `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+// StateDelta records a change the hook made to the unit's state.
+type StateDelta struct {
+	Key    string      `yaml:"key" json:"key"`
+	Before interface{} `yaml:"before,omitempty" json:"before,omitempty"`
+	After  interface{} `yaml:"after,omitempty" json:"after,omitempty"`
+}
+
+// sandbox runs a single hook against the uniter's hook context, backed
+// by a fake API seeded from the fixture.
+type sandbox struct {
+	fixture  *Fixture
+	hookName string
+	hookInfo hook.Info
+	backend  *backend
+
+	// mu guards toolCalls, which are recorded by concurrently
+	// running hook tools.
+	mu        sync.Mutex
+	toolCalls []ToolCall
+}
+
+func newSandbox(fixture *Fixture, ch *charm.CharmDir, hookName string) (*sandbox, error) {
+	b, err := newBackend(fixture, ch)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	info := hook.Info{Kind: hooks.Kind(hookName)}
+	if endpoint, ok := relationHookEndpoint(hookName); ok {
+		fixtureRel, ok := fixture.relation(endpoint)
+		if !ok {
+			return nil, errors.NotFoundf("fixture relation for endpoint %q", endpoint)
+		}
+		info = hook.Info{
+			Kind:              hooks.Kind(hookName[len(endpoint)+1:]),
+			RelationId:        fixtureRel.Id,
+			RemoteUnit:        fixture.remoteUnit(fixtureRel),
+			RemoteApplication: b.relations[fixtureRel.Id].otherApp,
+		}
+		if info.RemoteUnit != "" {
+			info.RemoteApplication, _ = names.UnitApplication(info.RemoteUnit)
+		}
+	}
+	return &sandbox{
+		fixture:  fixture,
+		hookName: hookName,
+		hookInfo: info,
+		backend:  b,
+	}, nil
+}
+
+// run runs the hook at hookPath, serving hook tool requests from a hook
+// context while it does so, and flushes the context once it completes.
+func (s *sandbox) run(hookPath, charmDir string, timeout time.Duration, writeTools func(string) error) (*Report, error) {
+	tmpDir, err := ioutil.TempDir("", "juju-hook-sandbox")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	paths := sandboxPaths{baseDir: tmpDir, charmDir: charmDir}
+	if err := os.Mkdir(paths.GetToolsDir(), 0755); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := writeTools(paths.GetToolsDir()); err != nil {
+		return nil, errors.Annotate(err, "cannot create hook tools")
+	}
+
+	before := s.backend.snapshot()
+	hookCtx, err := s.hookContext(paths)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot create hook context")
+	}
+	if err := hookCtx.Prepare(); err != nil {
+		return nil, errors.Annotate(err, "cannot prepare hook context")
+	}
+	env, err := hookCtx.HookVars(paths, false)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	server, err := jujuc.NewServer(s.getCmd(hookCtx), paths.GetJujucServerSocket(false), "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	go server.Run()
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	hookCmd := exec.Command(hookPath)
+	hookCmd.Dir = charmDir
+	hookCmd.Stdout = &stdout
+	hookCmd.Stderr = &stderr
+	hookCmd.Env = append(os.Environ(), env...)
+	hookCmd.Env = append(hookCmd.Env, "PATH="+paths.GetToolsDir()+string(os.PathListSeparator)+os.Getenv("PATH"))
+	setProcessGroup(hookCmd)
+	if err := hookCmd.Start(); err != nil {
+		return nil, errors.Annotatef(err, "cannot run hook %q", s.hookName)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- hookCmd.Wait()
+	}()
+	var exitCode int
+	select {
+	case err := <-done:
+		exitCode, err = exitStatus(err)
+		if err != nil {
+			return nil, errors.Annotatef(err, "running hook %q", s.hookName)
+		}
+	case <-time.After(timeout):
+		// Kill any processes started by the hook along with it, so
+		// that none of them outlive the sandbox.
+		if err := killProcessGroup(hookCmd); err != nil {
+			return nil, errors.Annotatef(err, "cannot kill hook %q", s.hookName)
+		}
+		<-done
+		return nil, errors.Errorf("hook %q did not complete within %v", s.hookName, timeout)
+	}
+
+	// As in the uniter, changes the hook made are only written if it
+	// succeeded.
+	var hookErr error
+	if exitCode != 0 {
+		hookErr = errors.Errorf("exit status %d", exitCode)
+	}
+	switch err := hookCtx.Flush(s.hookName, hookErr); err {
+	case nil, hookErr, context.ErrReboot, context.ErrRequeueAndReboot:
+	default:
+		return nil, errors.Annotatef(err, "cannot write changes made by hook %q", s.hookName)
+	}
+
+	s.mu.Lock()
+	toolCalls := append([]ToolCall(nil), s.toolCalls...)
+	s.mu.Unlock()
+	return &Report{
+		Hook:      s.hookName,
+		Unit:      s.fixture.Unit,
+		ExitCode:  exitCode,
+		Stdout:    stdout.String(),
+		Stderr:    stderr.String(),
+		ToolCalls: toolCalls,
+		Changes:   before.diff(s.backend.snapshot()),
+	}, nil
+}
+
+// hookContext returns a hook context for the sandbox's hook, created
+// by the uniter's context factory from the sandbox's backend.
+func (s *sandbox) hookContext(paths sandboxPaths) (*context.HookContext, error) {
+	st := uniter.NewState(s.backend, s.backend.unitTag)
+	unit, err := st.Unit(s.backend.unitTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	relationInfos := make(map[int]*context.RelationInfo)
+	for id, rel := range s.backend.relations {
+		relation, err := st.RelationById(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		relationUnit, err := relation.Unit(unit)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		relationInfos[id] = &context.RelationInfo{
+			RelationUnit: relationUnit,
+			MemberNames:  rel.memberNames(),
+		}
+	}
+	factory, err := context.NewContextFactory(context.FactoryConfig{
+		State:   st,
+		UnitTag: s.backend.unitTag,
+		Tracker: &tracker{appName: s.backend.appName, leader: s.fixture.Leader},
+		GetRelationInfos: func() map[int]*context.RelationInfo {
+			return relationInfos
+		},
+		Storage: noStorage{},
+		Paths:   paths,
+		Clock:   clock.WallClock,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return factory.HookContext(s.hookInfo)
+}
+
+// getCmd returns a jujuc.CmdGetter which records each hook tool
+// invocation made in the supplied context.
+func (s *sandbox) getCmd(hookCtx *context.HookContext) jujuc.CmdGetter {
+	return func(ctxId, cmdName string) (cmd.Command, error) {
+		if ctxId != hookCtx.Id() {
+			return nil, errors.Errorf("expected context id %q, got %q", hookCtx.Id(), ctxId)
+		}
+		c, err := jujuc.NewCommand(hookCtx, cmdName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return &recordingCommand{Command: c, sandbox: s, name: cmdName}, nil
+	}
+}
+
+// recordingCommand wraps a hook tool, recording its arguments, the
+// API calls it makes and any error it returns.
+type recordingCommand struct {
+	cmd.Command
+	sandbox *sandbox
+	name    string
+	flags   *gnuflag.FlagSet
+	args    []string
+	start   int
+}
+
+// SetFlags implements cmd.Command.
+func (c *recordingCommand) SetFlags(f *gnuflag.FlagSet) {
+	// Some hook tools query the context as they are initialised,
+	// so record API calls from here on.
+	c.start = c.sandbox.backend.callCount()
+	c.flags = f
+	c.Command.SetFlags(f)
+}
+
+// Init implements cmd.Command.
+func (c *recordingCommand) Init(args []string) error {
+	if c.flags != nil {
+		c.flags.Visit(func(f *gnuflag.Flag) {
+			c.args = append(c.args, fmt.Sprintf("--%s=%s", f.Name, f.Value))
+		})
+	}
+	c.args = append(c.args, args...)
+	err := c.Command.Init(args)
+	if err != nil {
+		c.record(err)
+	}
+	return err
+}
+
+// Run implements cmd.Command.
+func (c *recordingCommand) Run(ctx *cmd.Context) error {
+	err := c.Command.Run(ctx)
+	c.record(err)
+	return err
+}
+
+func (c *recordingCommand) record(err error) {
+	call := ToolCall{
+		Command:  c.name,
+		Args:     c.args,
+		APICalls: c.sandbox.backend.callsSince(c.start),
+	}
+	if err != nil {
+		call.Error = err.Error()
+	}
+	c.sandbox.mu.Lock()
+	defer c.sandbox.mu.Unlock()
+	c.sandbox.toolCalls = append(c.sandbox.toolCalls, call)
+}
+
+// sandboxPaths implements context.Paths for a hook run in the sandbox,
+// with everything but the charm kept under a temporary directory.
+type sandboxPaths struct {
+	baseDir  string
+	charmDir string
+}
+
+// GetToolsDir is part of the context.Paths interface.
+func (p sandboxPaths) GetToolsDir() string {
+	return filepath.Join(p.baseDir, "tools")
+}
+
+// GetBaseDir is part of the context.Paths interface.
+func (p sandboxPaths) GetBaseDir() string {
+	return p.baseDir
+}
+
+// GetCharmDir is part of the context.Paths interface.
+func (p sandboxPaths) GetCharmDir() string {
+	return p.charmDir
+}
+
+// GetJujucServerSocket is part of the context.Paths interface.
+func (p sandboxPaths) GetJujucServerSocket(remote bool) sockets.Socket {
+	return sockets.Socket{
+		Network: "unix",
+		Address: filepath.Join(p.baseDir, "agent.socket"),
+	}
+}
+
+// GetJujucClientSocket is part of the context.Paths interface.
+func (p sandboxPaths) GetJujucClientSocket(remote bool) sockets.Socket {
+	return p.GetJujucServerSocket(remote)
+}
+
+// GetMetricsSpoolDir is part of the context.Paths interface.
+func (p sandboxPaths) GetMetricsSpoolDir() string {
+	return filepath.Join(p.baseDir, "metrics")
+}
+
+// ComponentDir is part of the context.Paths interface.
+func (p sandboxPaths) ComponentDir(name string) string {
+	return filepath.Join(p.baseDir, name)
+}
+
+// unitState is a snapshot of the parts of the unit's state a hook
+// may change.
+type unitState map[string]interface{}
+
+// diff returns the changes from the receiver to the supplied state,
+// ordered by key.
+func (before unitState) diff(after unitState) []StateDelta {
+	var deltas []StateDelta
+	for k, v := range after {
+		if old, ok := before[k]; !ok || old != v {
+			deltas = append(deltas, StateDelta{Key: k, Before: before[k], After: v})
+		}
+	}
+	for k, v := range before {
+		if _, ok := after[k]; !ok {
+			deltas = append(deltas, StateDelta{Key: k, Before: v})
+		}
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		return deltas[i].Key < deltas[j].Key
+	})
+	return deltas
+}
+
+// relationHookEndpoint returns the endpoint named by a relation hook.
+func relationHookEndpoint(hookName string) (string, bool) {
+	i := strings.LastIndex(hookName, "-relation-")
+	if i <= 0 {
+		return "", false
+	}
+	return hookName[:i], true
+}
+
+func exitStatus(err error) (int, error) {
+	if err == nil {
+		return 0, nil
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			return status.ExitStatus(), nil
+		}
+	}
+	return 0, err
+}
+
+func copyStrings(in map[string]string) map[string]string {
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,239 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+var flagRunMain = flag.Bool("run-main", false, "Run the application's main function for recursive testing")
+
+// Reentrancy point for running the hook tools invoked by test hooks.
+func TestRunMain(t *stdtesting.T) {
+	if *flagRunMain {
+		os.Exit(Main(flag.Args()))
+	}
+}
+
+type SandboxSuite struct{}
+
+var _ = gc.Suite(&SandboxSuite{})
+
+const metadataYAML = `
+name: mysql
+summary: a database
+description: a database
+provides:
+  db:
+    interface: mysql
+    schema:
+      provider:
+        unit:
+          type: object
+          properties:
+            host: {type: string}
+          required: [host]
+`
+
+const configYAML = `
+options:
+  port:
+    type: int
+    default: 3306
+  name:
+    type: string
+    default: db
+`
+
+const fixtureYAML = `
+unit: mysql/0
+config:
+  name: wordpress
+leader: true
+leader-settings:
+  password: secret
+relations:
+- id: 7
+  endpoint: db
+  settings:
+    host: 10.0.0.1
+  units:
+    wordpress/0:
+      user: wp
+`
+
+func (s *SandboxSuite) writeCharm(c *gc.C, hooks map[string]string) string {
+	dir := c.MkDir()
+	c.Assert(ioutil.WriteFile(filepath.Join(dir, "metadata.yaml"), []byte(metadataYAML), 0644), jc.ErrorIsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(dir, "config.yaml"), []byte(configYAML), 0644), jc.ErrorIsNil)
+	c.Assert(os.Mkdir(filepath.Join(dir, "hooks"), 0755), jc.ErrorIsNil)
+	for name, script := range hooks {
+		c.Assert(ioutil.WriteFile(filepath.Join(dir, "hooks", name), []byte("#!/bin/sh\n"+script), 0755), jc.ErrorIsNil)
+	}
+	return dir
+}
+
+// writeTestTools creates hook tools which re-enter the test binary.
+func writeTestTools(dir string) error {
+	for _, name := range jujuc.CommandNames() {
+		script := fmt.Sprintf("#!/bin/sh\nexec %q -test.run TestRunMain -run-main -- %s \"$@\"\n", os.Args[0], name)
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SandboxSuite) runSandbox(c *gc.C, charmDir string, args ...string) (string, error) {
+	if runtime.GOOS == "windows" {
+		c.Skip("hooks are shell scripts")
+	}
+	fixturePath := filepath.Join(c.MkDir(), "fixture.yaml")
+	c.Assert(ioutil.WriteFile(fixturePath, []byte(fixtureYAML), 0644), jc.ErrorIsNil)
+
+	command := newSandboxCommand()
+	command.writeTools = writeTestTools
+	ctx, err := cmdtesting.RunCommand(c, command, append([]string{"--fixture", fixturePath, charmDir}, args...)...)
+	return cmdtesting.Stdout(ctx), err
+}
+
+func (s *SandboxSuite) TestInitRequiresCharmAndHook(c *gc.C) {
+	err := cmdtesting.InitCommand(newSandboxCommand(), []string{"charm"})
+	c.Assert(err, gc.ErrorMatches, "a charm directory and hook name must be supplied")
+}
+
+func (s *SandboxSuite) TestMissingHook(c *gc.C) {
+	charmDir := s.writeCharm(c, nil)
+	_, err := s.runSandbox(c, charmDir, "install")
+	c.Assert(err, gc.ErrorMatches, `cannot find hook "install": .*`)
+}
+
+func (s *SandboxSuite) TestRunHook(c *gc.C) {
+	charmDir := s.writeCharm(c, map[string]string{
+		"config-changed": `
+echo "port $(config-get port) name $(config-get name)"
+leader-set password=changed
+status-set active ready
+`,
+	})
+	stdout, err := s.runSandbox(c, charmDir, "config-changed")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stdout, gc.Equals, `
+hook: config-changed
+unit: mysql/0
+exit-code: 0
+stdout: |
+  port 3306 name wordpress
+tool-calls:
+- command: config-get
+  args:
+  - port
+  api-calls:
+  - ConfigSettings
+- command: config-get
+  args:
+  - name
+- command: leader-set
+  args:
+  - password=changed
+  api-calls:
+  - Merge
+- command: status-set
+  args:
+  - active
+  - ready
+  api-calls:
+  - SetUnitStatus
+changes:
+- key: leader-settings.password
+  before: secret
+  after: changed
+- key: status
+  before: unknown
+  after: active ready
+`[1:])
+}
+
+func (s *SandboxSuite) TestRunRelationHook(c *gc.C) {
+	charmDir := s.writeCharm(c, map[string]string{
+		"db-relation-changed": `
+echo "$JUJU_RELATION_ID $JUJU_REMOTE_UNIT $(relation-get user)"
+relation-set host=10.0.0.2
+`,
+	})
+	stdout, err := s.runSandbox(c, charmDir, "db-relation-changed", "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stdout, jc.Contains, `"stdout":"db:7 wordpress/0 wp\n"`)
+	c.Assert(stdout, jc.Contains, `{"key":"relation.db:7.unit.host","before":"10.0.0.1","after":"10.0.0.2"}`)
+}
+
+func (s *SandboxSuite) TestRunRelationHookValidatesSchema(c *gc.C) {
+	charmDir := s.writeCharm(c, map[string]string{
+		"db-relation-changed": `
+relation-set host=
+`,
+	})
+	stdout, err := s.runSandbox(c, charmDir, "db-relation-changed")
+	c.Assert(err, gc.ErrorMatches, "subprocess encountered error code 1")
+	c.Assert(stdout, jc.Contains, "exit-code: 1")
+	c.Assert(stdout, jc.Contains, "cannot set relation settings")
+}
+
+func (s *SandboxSuite) TestRunRelationHookFailureDiscardsChanges(c *gc.C) {
+	charmDir := s.writeCharm(c, map[string]string{
+		"db-relation-changed": `
+relation-set host=10.0.0.2
+exit 1
+`,
+	})
+	stdout, err := s.runSandbox(c, charmDir, "db-relation-changed")
+	c.Assert(err, gc.ErrorMatches, "subprocess encountered error code 1")
+	c.Assert(stdout, gc.Not(jc.Contains), "changes:")
+}
+
+func (s *SandboxSuite) TestTimeoutKillsHookProcesses(c *gc.C) {
+	marker := filepath.Join(c.MkDir(), "marker")
+	charmDir := s.writeCharm(c, map[string]string{
+		"install": fmt.Sprintf("(sleep 1; touch %q) &\nsleep 10\n", marker),
+	})
+	_, err := s.runSandbox(c, charmDir, "install", "--timeout", "100ms")
+	c.Assert(err, gc.ErrorMatches, `hook "install" did not complete within 100ms`)
+
+	// The process started by the hook was killed with it.
+	time.Sleep(2 * time.Second)
+	_, err = os.Stat(marker)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *SandboxSuite) TestHookFailure(c *gc.C) {
+	charmDir := s.writeCharm(c, map[string]string{
+		"install": "echo oops >&2\nexit 3\n",
+	})
+	stdout, err := s.runSandbox(c, charmDir, "install")
+	c.Assert(err, gc.ErrorMatches, "subprocess encountered error code 3")
+	c.Assert(stdout, gc.Equals, `
+hook: install
+unit: mysql/0
+exit-code: 3
+stderr: |
+  oops
+`[1:])
+}
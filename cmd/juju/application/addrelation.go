@@ -160,7 +160,12 @@ func (c *addRelationCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 
-	_, err = client.AddRelation(c.endpoints, c.viaCIDRs)
+	result, err := client.AddRelation(c.endpoints, c.viaCIDRs)
+	if err == nil && result != nil {
+		for _, warning := range result.Warnings {
+			ctx.Warningf("%s", warning)
+		}
+	}
 	if params.IsCodeUnauthorized(err) {
 		common.PermissionsMessage(ctx.Stderr, "add a relation")
 	}
@@ -65,6 +65,19 @@ func (s *AddRelationSuite) TestAddRelationSuccess(c *gc.C) {
 	s.mockAPI.CheckCall(c, 1, "Close")
 }
 
+func (s *AddRelationSuite) TestAddRelationSchemaWarnings(c *gc.C) {
+	s.mockAPI.addRelationFunc = func(endpoints, viaCIDRs []string) (*params.AddRelationResults, error) {
+		return &params.AddRelationResults{
+			Warnings: []string{`wordpress:db and mysql:server relation schemas are incompatible: provider unit settings: "host" has type "string" on one side and "integer" on the other`},
+		}, nil
+	}
+	cmd := application.NewAddRelationCommandForTest(s.mockAPI, s.mockAPI)
+	cmd.SetClientStore(jujuclienttesting.MinimalStore())
+	ctx, err := cmdtesting.RunCommand(c, cmd, "wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), jc.Contains, `wordpress:db and mysql:server relation schemas are incompatible`)
+}
+
 func (s *AddRelationSuite) TestAddRelationFail(c *gc.C) {
 	msg := "fail add-relation call at API"
 	s.mockAPI.SetErrors(errors.New(msg))
@@ -85,6 +85,11 @@ Add a unit of mysql to LXD container on a new machine:
 
     juju add-unit mysql --to lxd
 
+Add a unit of postgresql, restoring its "pgdata" storage from
+storage snapshot 0/1 (see "juju storage-snapshots"):
+
+    juju add-unit postgresql --attach-storage-snapshot pgdata=0/1
+
 See also:
     remove-unit
 `[1:]
@@ -100,12 +105,16 @@ type UnitCommandBase struct {
 	// AttachStorage is a list of storage IDs, identifying storage to
 	// attach to the unit created by deploy.
 	AttachStorage []string
+	// AttachStorageSnapshots maps charm storage names to the IDs of
+	// storage snapshots from which the units' storage is restored.
+	AttachStorageSnapshots map[string]string
 }
 
 func (c *UnitCommandBase) SetFlags(f *gnuflag.FlagSet) {
 	f.IntVar(&c.NumUnits, "num-units", 1, "")
 	f.StringVar(&c.PlacementSpec, "to", "", "The machine and/or container to deploy the unit in (bypasses constraints)")
 	f.Var(attachStorageFlag{&c.AttachStorage}, "attach-storage", "Existing storage to attach to the deployed unit (not available on k8s models)")
+	f.Var(stringMap{&c.AttachStorageSnapshots}, "attach-storage-snapshot", "Storage snapshot from which to restore the named storage of the deployed units, as <storage>=<snapshot> (not available on k8s models)")
 }
 
 func (c *UnitCommandBase) Init(args []string) error {
@@ -202,7 +211,7 @@ func (c *addUnitCommand) validateArgsByModelType() error {
 		return err
 	}
 	if modelType == model.CAAS {
-		if c.PlacementSpec != "" || len(c.AttachStorage) != 0 {
+		if c.PlacementSpec != "" || len(c.AttachStorage) != 0 || len(c.AttachStorageSnapshots) != 0 {
 			return errors.New("k8s models only support --num-units")
 		}
 	}
@@ -266,6 +275,11 @@ func (c *addUnitCommand) Run(ctx *cmd.Context) error {
 		// Application API version 5 and onwards.
 		return errors.New("this juju controller does not support --attach-storage")
 	}
+	if len(c.AttachStorageSnapshots) > 0 && apiclient.BestAPIVersion() < 13 {
+		// AddUnitsParams.AttachStorageSnapshots is only supported
+		// from Application API version 13 and onwards.
+		return errors.New("this juju controller does not support --attach-storage-snapshot")
+	}
 
 	for i, p := range c.Placement {
 		if p.Scope == "model-uuid" {
@@ -278,6 +292,8 @@ func (c *addUnitCommand) Run(ctx *cmd.Context) error {
 		NumUnits:        c.NumUnits,
 		Placement:       c.Placement,
 		AttachStorage:   c.AttachStorage,
+
+		AttachStorageSnapshots: c.AttachStorageSnapshots,
 	})
 	if params.IsCodeUnauthorized(err) {
 		common.PermissionsMessage(ctx.Stderr, "add a unit")
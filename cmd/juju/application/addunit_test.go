@@ -31,13 +31,14 @@ type AddUnitSuite struct {
 }
 
 type fakeApplicationAddUnitAPI struct {
-	envType        string
-	application    string
-	numUnits       int
-	placement      []*instance.Placement
-	attachStorage  []string
-	bestAPIVersion int
-	err            error
+	envType                string
+	application            string
+	numUnits               int
+	placement              []*instance.Placement
+	attachStorage          []string
+	attachStorageSnapshots map[string]string
+	bestAPIVersion         int
+	err                    error
 }
 
 func (f *fakeApplicationAddUnitAPI) BestAPIVersion() int {
@@ -63,6 +64,7 @@ func (f *fakeApplicationAddUnitAPI) AddUnits(args apiapplication.AddUnitsParams)
 	f.numUnits += args.NumUnits
 	f.placement = args.Placement
 	f.attachStorage = args.AttachStorage
+	f.attachStorageSnapshots = args.AttachStorageSnapshots
 	return nil, nil
 }
 
@@ -187,6 +189,26 @@ func (s *AddUnitSuite) TestAddUnitAttachStorageNotSupported(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "this juju controller does not support --attach-storage")
 }
 
+func (s *AddUnitSuite) TestAddUnitAttachStorageSnapshot(c *gc.C) {
+	s.fake.bestAPIVersion = 13
+	err := s.runAddUnit(c, "some-application-name", "-n", "2",
+		"--attach-storage-snapshot", "data=0/1",
+		"--attach-storage-snapshot", "logs=0/2",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.numUnits, gc.Equals, 3)
+	c.Assert(s.fake.attachStorageSnapshots, jc.DeepEquals, map[string]string{
+		"data": "0/1",
+		"logs": "0/2",
+	})
+}
+
+func (s *AddUnitSuite) TestAddUnitAttachStorageSnapshotNotSupported(c *gc.C) {
+	s.fake.bestAPIVersion = 12 // v12 does not support attach-storage-snapshot
+	err := s.runAddUnit(c, "some-application-name", "--attach-storage-snapshot", "data=0/1")
+	c.Assert(err, gc.ErrorMatches, "this juju controller does not support --attach-storage-snapshot")
+}
+
 func (s *AddUnitSuite) TestBlockAddUnit(c *gc.C) {
 	// Block operation
 	s.fake.err = common.OperationBlockedError("TestBlockAddUnit")
@@ -163,7 +163,24 @@ func (c *bundleDiffCommand) newAPIRoot() (base.APICallCloser, error) {
 }
 
 func (c *bundleDiffCommand) bundleDataSource(ctx *cmd.Context) (charm.BundleDataSource, error) {
-	ds, err := charm.LocalBundleDataSource(c.bundle)
+	return readBundleDataSource(c.bundle, c.channel, c.charmStore)
+}
+
+func (c *bundleDiffCommand) charmStore() (BundleResolver, error) {
+	if c._charmStore != nil {
+		return c._charmStore, nil
+	}
+	return newBundleResolver(&c.ModelCommandBase, c.channel)
+}
+
+// readBundleDataSource returns the data source for the input bundle, which
+// is either a local bundle file or directory, or the name of a bundle in
+// the charm store. The charm store is only consulted if the bundle is not
+// local.
+func readBundleDataSource(
+	bundle string, channel csparams.Channel, charmStore func() (BundleResolver, error),
+) (charm.BundleDataSource, error) {
+	ds, err := charm.LocalBundleDataSource(bundle)
 
 	// NotValid/NotFound means we should try interpreting it as a charm store
 	// bundle URL.
@@ -175,31 +192,30 @@ func (c *bundleDiffCommand) bundleDataSource(ctx *cmd.Context) (charm.BundleData
 	}
 
 	// Not a local bundle, so it must be from the charmstore.
-	charmStore, err := c.charmStore()
+	store, err := charmStore()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	bundleURL, _, err := resolveBundleURL(charmStore, c.bundle, c.channel)
+	bundleURL, _, err := resolveBundleURL(store, bundle, channel)
 	if err != nil && !errors.IsNotValid(err) {
 		return nil, errors.Trace(err)
 	}
 	if bundleURL == nil {
 		// This isn't a charmstore bundle either! Complain.
-		return nil, errors.Errorf("couldn't interpret %q as a local or charmstore bundle", c.bundle)
+		return nil, errors.Errorf("couldn't interpret %q as a local or charmstore bundle", bundle)
 	}
 
-	bundle, err := charmStore.GetBundle(bundleURL)
+	b, err := store.GetBundle(bundleURL)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 
-	return newResolvedBundle(bundle), nil
+	return newResolvedBundle(b), nil
 }
 
-func (c *bundleDiffCommand) charmStore() (BundleResolver, error) {
-	if c._charmStore != nil {
-		return c._charmStore, nil
-	}
+// newBundleResolver returns a charm store client for resolving bundles,
+// using the charm store of the command's controller.
+func newBundleResolver(c *modelcmd.ModelCommandBase, channel csparams.Channel) (BundleResolver, error) {
 	controllerAPIRoot, err := c.NewControllerAPIRoot()
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -213,7 +229,7 @@ func (c *bundleDiffCommand) charmStore() (BundleResolver, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	cstoreClient := newCharmStoreClient(bakeryClient, csURL).WithChannel(c.channel)
+	cstoreClient := newCharmStoreClient(bakeryClient, csURL).WithChannel(channel)
 	return charmrepo.NewCharmStoreFromClient(cstoreClient), nil
 }
 
@@ -5,6 +5,7 @@ package application
 
 import (
 	"archive/zip"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"sort"
@@ -368,6 +369,10 @@ type DeployCommand struct {
 	// deployed but just output the changes.
 	DryRun bool
 
+	// ShowCost is used with DryRun to specify that the estimated cost of
+	// the machines that the bundle would add should also be output.
+	ShowCost bool
+
 	ApplicationName string
 	ConfigOptions   common.ConfigFlag
 	ConstraintsStr  string
@@ -584,6 +589,13 @@ bundle.
 Only top level machines can be mapped in this way, just as only top level
 machines can be defined in the machines section of the bundle.
 
+The '--dry-run' option shows the changes that deploying a bundle would make,
+without making them. Adding '--show-cost' also shows the estimated hourly and
+monthly cost of the machines that the bundle would add, using the price data
+of the model's cloud. See 'juju help estimate-cost' for details.
+
+  juju deploy mybundle --dry-run --show-cost
+
 When charms that include LXD profiles are deployed the profiles are validated
 for security purposes by allowing only certain configurations and devices. Use
 the '--force' option to bypass this check. Doing so is not recommended as it
@@ -705,7 +717,7 @@ func (c *DeployCommand) Info() *cmd.Info {
 var (
 	// TODO(thumper): support dry-run for apps as well as bundles.
 	bundleOnlyFlags = []string{
-		"overlay", "dry-run", "map-machines",
+		"overlay", "dry-run", "show-cost", "map-machines",
 	}
 )
 
@@ -714,7 +726,7 @@ var (
 func charmOnlyFlags() []string {
 	charmOnlyFlags := []string{
 		"bind", "config", "constraints", "n", "num-units",
-		"series", "to", "resource", "attach-storage",
+		"series", "to", "resource", "attach-storage", "attach-storage-snapshot",
 	}
 
 	return charmOnlyFlags
@@ -736,6 +748,7 @@ func (c *DeployCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.ConstraintsStr, "constraints", "", "Set application constraints")
 	f.StringVar(&c.Series, "series", "", "The series on which to deploy")
 	f.BoolVar(&c.DryRun, "dry-run", false, "Just show what the bundle deploy would do")
+	f.BoolVar(&c.ShowCost, "show-cost", false, "With --dry-run, also show the estimated cost of the machines the bundle would add")
 	f.BoolVar(&c.Force, "force", false, "Allow a charm/bundle to be deployed which bypasses checks such as supported series or LXD profile allow list")
 	f.Var(storageFlag{&c.Storage, &c.BundleStorage}, "storage", "Charm storage constraints")
 	f.Var(devicesFlag{&c.Devices, &c.BundleDevices}, "device", "Charm device constraints")
@@ -777,6 +790,10 @@ func (c *DeployCommand) Init(args []string) error {
 		return cmd.CheckEmpty(args[2:])
 	}
 
+	if c.ShowCost && !c.DryRun {
+		return errors.New("--show-cost requires --dry-run")
+	}
+
 	useExisting, mapping, err := parseMachineMap(c.machineMap)
 	if err != nil {
 		return errors.Annotate(err, "error in --map-machines")
@@ -813,6 +830,9 @@ func (c *DeployCommand) validateStorageByModelType() error {
 	if len(c.AttachStorage) > 0 {
 		return errors.New("--attach-storage cannot be used on k8s models")
 	}
+	if len(c.AttachStorageSnapshots) > 0 {
+		return errors.New("--attach-storage-snapshot cannot be used on k8s models")
+	}
 	return nil
 }
 
@@ -968,6 +988,14 @@ Please repeat the deploy command with the --trust argument if you consent to tru
 	if _, err := deployBundle(bundleData, spec); err != nil {
 		return errors.Annotate(err, "cannot deploy bundle")
 	}
+	if c.ShowCost {
+		estimate, err := estimateBundleCost(newCostEstimateAPI(spec.apiRoot), bundleData)
+		if err != nil {
+			return errors.Annotate(err, "cannot estimate bundle cost")
+		}
+		fmt.Fprintln(spec.ctx.Stdout, "\nEstimated cost:")
+		return errors.Trace(formatCostEstimateTabular(spec.ctx.Stdout, estimate))
+	}
 	return nil
 }
 
@@ -988,9 +1016,14 @@ func (c *DeployCommand) deployCharm(
 		// Application API version 5 and onwards.
 		return errors.New("this juju controller does not support --attach-storage")
 	}
+	if len(c.AttachStorageSnapshots) > 0 && apiRoot.BestFacadeVersion("Application") < 13 {
+		// DeployArgs.AttachStorageSnapshots is only supported
+		// from Application API version 13 and onwards.
+		return errors.New("this juju controller does not support --attach-storage-snapshot")
+	}
 
 	// Storage cannot be added to a container.
-	if len(c.Storage) > 0 || len(c.AttachStorage) > 0 {
+	if len(c.Storage) > 0 || len(c.AttachStorage) > 0 || len(c.AttachStorageSnapshots) > 0 {
 		for _, placement := range c.Placement {
 			if t, err := instance.ParseContainerType(placement.Scope); err == nil {
 				return errors.NotSupportedf("adding storage to %s container", string(t))
@@ -1145,6 +1178,8 @@ func (c *DeployCommand) deployCharm(
 		AttachStorage:    c.AttachStorage,
 		Resources:        ids,
 		EndpointBindings: c.Bindings,
+
+		AttachStorageSnapshots: c.AttachStorageSnapshots,
 	}
 	return errors.Trace(apiRoot.Deploy(args))
 }
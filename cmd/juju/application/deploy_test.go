@@ -222,6 +222,9 @@ var initErrorTests = []struct {
 	}, {
 		args: []string{"bundle", "--map-machines", "foo"},
 		err:  `error in --map-machines: expected "existing" or "<bundle-id>=<machine-id>", got "foo"`,
+	}, {
+		args: []string{"bundle", "--show-cost"},
+		err:  `--show-cost requires --dry-run`,
 	},
 }
 
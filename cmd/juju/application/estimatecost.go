@@ -0,0 +1,386 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/charm.v6"
+	csparams "gopkg.in/juju/charmrepo.v3/csclient/params"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/core/constraints"
+)
+
+const estimateCostDoc = `
+Estimates the cost of the machines that deploying a bundle to the model
+would add, using the price data of the model's cloud.
+
+The constraints of each application and bundle machine, combined with the
+model constraints and the cloud's default constraints (such as the
+cpu-power used on AWS to avoid burstable instances), are resolved to the
+cheapest matching instance type in the model's cloud region, as they would
+be when the machines are provisioned. Units placed on existing or bundle machines, in containers,
+or alongside other applications do not add machines of their own.
+
+Costs are reported per hour, and per month of 730 hours. Clouds without
+price data for their instance types, or with no matching instance type,
+have their costs reported as unknown; the totals then only cover the
+machines with known costs.
+
+Bundle can be a local bundle file or the name of a bundle in the charm
+store. The bundle can also be combined with overlays, in the same way as
+the deploy command.
+
+Examples:
+    juju estimate-cost localbundle.yaml
+    juju estimate-cost canonical-kubernetes --format yaml
+    juju estimate-cost mediawiki-single --overlay local-config.yaml
+
+See also:
+    deploy
+    diff-bundle
+`
+
+// hoursPerMonth is the number of hours in an average month,
+// as used by cloud price lists.
+const hoursPerMonth = 730
+
+// NewEstimateCostCommand returns a command to estimate the cost of
+// deploying a bundle to the selected model.
+func NewEstimateCostCommand() cmd.Command {
+	return modelcmd.Wrap(&estimateCostCommand{})
+}
+
+// estimateCostCommand estimates the cost of deploying a bundle.
+type estimateCostCommand struct {
+	modelcmd.ModelCommandBase
+	out cmd.Output
+
+	bundle         string
+	bundleOverlays []string
+	channel        csparams.Channel
+
+	// These are set in tests to enable mocking out the API and the
+	// charm store.
+	_api        CostEstimateAPI
+	_charmStore BundleResolver
+}
+
+// Info is part of cmd.Command.
+func (c *estimateCostCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "estimate-cost",
+		Args:    "<bundle file or name>",
+		Purpose: "Estimate the cloud cost of deploying a bundle.",
+		Doc:     estimateCostDoc,
+	})
+}
+
+// SetFlags is part of cmd.Command.
+func (c *estimateCostCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar((*string)(&c.channel), "channel", "", "Channel to use when getting the bundle from the charm store")
+	f.Var(cmd.NewAppendStringsValue(&c.bundleOverlays), "overlay", "Bundles to overlay on the primary bundle, applied in order")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatCostEstimateTabular,
+	})
+}
+
+// Init is part of cmd.Command.
+func (c *estimateCostCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("no bundle specified")
+	}
+	c.bundle = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run is part of cmd.Command.
+func (c *estimateCostCommand) Run(ctx *cmd.Context) error {
+	baseSrc, err := readBundleDataSource(c.bundle, c.channel, c.charmStore)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	bundle, err := composeAndVerifyBundle(baseSrc, c.bundleOverlays)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	api, err := c.costEstimateAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	estimate, err := estimateBundleCost(api, bundle)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return c.out.Write(ctx, estimate)
+}
+
+func (c *estimateCostCommand) costEstimateAPI() (CostEstimateAPI, error) {
+	if c._api != nil {
+		return c._api, nil
+	}
+	apiRoot, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newCostEstimateAPI(apiRoot), nil
+}
+
+func (c *estimateCostCommand) charmStore() (BundleResolver, error) {
+	if c._charmStore != nil {
+		return c._charmStore, nil
+	}
+	return newBundleResolver(&c.ModelCommandBase, c.channel)
+}
+
+// CostEstimateAPI provides the model information needed to estimate the
+// cost of deploying to the model.
+type CostEstimateAPI interface {
+	ProvisioningInstanceTypes([]constraints.Value) ([]params.InstanceTypesResult, error)
+	Close() error
+}
+
+// newCostEstimateAPI returns a CostEstimateAPI using the input API root.
+func newCostEstimateAPI(apiRoot base.APICallCloser) CostEstimateAPI {
+	return machinemanager.NewClient(apiRoot)
+}
+
+// machineGroup describes a number of machines that a deployment would add,
+// all provisioned with the same constraints.
+type machineGroup struct {
+	name        string
+	count       int
+	constraints constraints.Value
+}
+
+// bundleMachineGroups returns the machines that deploying the input bundle
+// would add to a model: the bundle's machines, and the machines for units
+// of each application placed on new machines or in containers on new
+// machines.
+func bundleMachineGroups(data *charm.BundleData) ([]machineGroup, error) {
+	var groups []machineGroup
+
+	machineIds := make([]string, 0, len(data.Machines))
+	for id := range data.Machines {
+		machineIds = append(machineIds, id)
+	}
+	sort.Strings(machineIds)
+	for _, id := range machineIds {
+		var consStr string
+		if m := data.Machines[id]; m != nil {
+			consStr = m.Constraints
+		}
+		cons, err := constraints.Parse(consStr)
+		if err != nil {
+			return nil, errors.Annotatef(err, "machine %s", id)
+		}
+		groups = append(groups, machineGroup{name: "machine " + id, count: 1, constraints: cons})
+	}
+
+	appNames := make([]string, 0, len(data.Applications))
+	for name := range data.Applications {
+		appNames = append(appNames, name)
+	}
+	sort.Strings(appNames)
+	for _, name := range appNames {
+		app := data.Applications[name]
+		cons, err := constraints.Parse(app.Constraints)
+		if err != nil {
+			return nil, errors.Annotatef(err, "application %q", name)
+		}
+		machines, hosts, err := newMachinesForUnits(app.NumUnits, app.To)
+		if err != nil {
+			return nil, errors.Annotatef(err, "application %q", name)
+		}
+		if machines > 0 {
+			groups = append(groups, machineGroup{name: name, count: machines, constraints: cons})
+		}
+		// The hosts of new containers are provisioned
+		// without the application's constraints.
+		if hosts > 0 {
+			groups = append(groups, machineGroup{name: name + " (container hosts)", count: hosts})
+		}
+	}
+	return groups, nil
+}
+
+// newMachinesForUnits returns the number of new machines that units with
+// the input placement directives are deployed to, and the number of new
+// machines that host containers for the units. As when deploying a bundle,
+// the last directive applies to any units beyond those with a directive.
+func newMachinesForUnits(numUnits int, placements []string) (machines, hosts int, err error) {
+	for i := 0; i < numUnits; i++ {
+		var directive string
+		if i < len(placements) {
+			directive = placements[i]
+		} else if len(placements) > 0 {
+			directive = placements[len(placements)-1]
+		}
+		if directive == "" {
+			machines++
+			continue
+		}
+		placement, err := charm.ParsePlacement(directive)
+		if err != nil {
+			return 0, 0, errors.Trace(err)
+		}
+		if placement.Machine != "new" {
+			continue
+		}
+		if placement.ContainerType == "" {
+			machines++
+		} else {
+			hosts++
+		}
+	}
+	return machines, hosts, nil
+}
+
+// CostEstimate is the estimated cost of the machines
+// that a deployment would add to a model.
+type CostEstimate struct {
+	Machines []MachineCost `yaml:"machines" json:"machines"`
+	Currency string        `yaml:"currency,omitempty" json:"currency,omitempty"`
+	Hourly   float64       `yaml:"hourly-cost" json:"hourly-cost"`
+	Monthly  float64       `yaml:"monthly-cost" json:"monthly-cost"`
+	// Incomplete is true if the cost of some machines is unknown,
+	// and so not included in the totals.
+	Incomplete bool `yaml:"incomplete,omitempty" json:"incomplete,omitempty"`
+}
+
+// MachineCost is the estimated cost of a group of machines
+// with the same constraints.
+type MachineCost struct {
+	Name         string   `yaml:"name" json:"name"`
+	Count        int      `yaml:"count" json:"count"`
+	Constraints  string   `yaml:"constraints,omitempty" json:"constraints,omitempty"`
+	InstanceType string   `yaml:"instance-type,omitempty" json:"instance-type,omitempty"`
+	Hourly       *float64 `yaml:"hourly-cost,omitempty" json:"hourly-cost,omitempty"`
+	Monthly      *float64 `yaml:"monthly-cost,omitempty" json:"monthly-cost,omitempty"`
+	Error        string   `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+// estimateBundleCost estimates the cost of the machines that deploying the
+// input bundle would add to the model.
+func estimateBundleCost(api CostEstimateAPI, data *charm.BundleData) (*CostEstimate, error) {
+	groups, err := bundleMachineGroups(data)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return estimateCost(api, groups)
+}
+
+// estimateCost estimates the cost of the input groups of machines, by
+// resolving the constraints of each to the instance type that the machines
+// would be provisioned as.
+func estimateCost(api CostEstimateAPI, groups []machineGroup) (*CostEstimate, error) {
+	estimate := &CostEstimate{Machines: []MachineCost{}}
+	if len(groups) == 0 {
+		return estimate, nil
+	}
+
+	// The controller combines the constraints with the model constraints,
+	// and applies the cloud's defaults, as it does when provisioning.
+	cons := make([]constraints.Value, len(groups))
+	for i, group := range groups {
+		cons[i] = group.constraints
+	}
+	results, err := api.ProvisioningInstanceTypes(cons)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting instance types")
+	}
+
+	for i, group := range groups {
+		cost := MachineCost{
+			Name:        group.name,
+			Count:       group.count,
+			Constraints: cons[i].String(),
+		}
+		result := results[i]
+		switch {
+		case result.Error != nil:
+			cost.Error = result.Error.Message
+		case len(result.InstanceTypes) == 0:
+			cost.Error = "no matching instance types"
+		default:
+			// Instance types are ordered cheapest first,
+			// which is the one provisioning selects.
+			itype := result.InstanceTypes[0]
+			cost.InstanceType = itype.Name
+			if itype.Cost > 0 && result.CostCurrency != "" {
+				divisor := result.CostDivisor
+				if divisor == 0 {
+					divisor = 1
+				}
+				hourly := float64(itype.Cost) / float64(divisor) * float64(group.count)
+				monthly := hourly * hoursPerMonth
+				cost.Hourly, cost.Monthly = &hourly, &monthly
+				estimate.Hourly += hourly
+				estimate.Monthly += monthly
+				estimate.Currency = result.CostCurrency
+			}
+		}
+		if cost.Hourly == nil {
+			estimate.Incomplete = true
+		}
+		estimate.Machines = append(estimate.Machines, cost)
+	}
+	return estimate, nil
+}
+
+// formatCostEstimateTabular writes a tabular summary of a cost estimate.
+func formatCostEstimateTabular(writer io.Writer, value interface{}) error {
+	estimate, ok := value.(*CostEstimate)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", estimate, value)
+	}
+	if len(estimate.Machines) == 0 {
+		fmt.Fprintln(writer, "No machines would be added to the model.")
+		return nil
+	}
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Machines", "Count", "Instance type", "Hourly", "Monthly", "Notes")
+	for _, m := range estimate.Machines {
+		instanceType := m.InstanceType
+		if instanceType == "" {
+			instanceType = "-"
+		}
+		w.Println(m.Name, m.Count, instanceType, formatCost(m.Hourly, estimate.Currency), formatCost(m.Monthly, estimate.Currency), m.Error)
+	}
+	w.Println("Total", "", "", formatCost(&estimate.Hourly, estimate.Currency), formatCost(&estimate.Monthly, estimate.Currency), "")
+	if err := tw.Flush(); err != nil {
+		return errors.Trace(err)
+	}
+	if estimate.Incomplete {
+		fmt.Fprintln(writer, "\nSome costs are unknown, and are not included in the totals.")
+	}
+	return nil
+}
+
+func formatCost(cost *float64, currency string) string {
+	if cost == nil {
+		return "unknown"
+	}
+	return strings.TrimSpace(fmt.Sprintf("%.2f %s", *cost, currency))
+}
@@ -0,0 +1,181 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/application"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+)
+
+type estimateCostSuite struct {
+	jujutesting.IsolationSuite
+	api *fakeCostEstimateAPI
+	dir string
+}
+
+var _ = gc.Suite(&estimateCostSuite{})
+
+func (s *estimateCostSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.api = &fakeCostEstimateAPI{
+		instanceTypes: map[string]params.InstanceTypesResult{
+			"": {
+				InstanceTypes: []params.InstanceType{{Name: "m5.large", Cost: 96}, {Name: "m5.xlarge", Cost: 192}},
+				CostCurrency:  "USD",
+				CostDivisor:   1000,
+			},
+			"cores=4": {
+				InstanceTypes: []params.InstanceType{{Name: "m5.xlarge", Cost: 192}},
+				CostCurrency:  "USD",
+				CostDivisor:   1000,
+			},
+			"mem=1024G": {
+				Error: &params.Error{Message: `no instance types in us-east-1 matching constraints "arch=amd64 mem=1024G"`},
+			},
+		},
+	}
+	s.dir = c.MkDir()
+}
+
+type fakeCostEstimateAPI struct {
+	instanceTypes map[string]params.InstanceTypesResult
+	requested     []string
+}
+
+func (f *fakeCostEstimateAPI) ProvisioningInstanceTypes(cons []constraints.Value) ([]params.InstanceTypesResult, error) {
+	results := make([]params.InstanceTypesResult, len(cons))
+	for i, c := range cons {
+		f.requested = append(f.requested, c.String())
+		results[i] = f.instanceTypes[c.String()]
+	}
+	return results, nil
+}
+
+func (f *fakeCostEstimateAPI) Close() error {
+	return nil
+}
+
+func (s *estimateCostSuite) runEstimateCost(c *gc.C, args ...string) (*cmd.Context, error) {
+	store := jujuclienttesting.MinimalStore()
+	store.Models["enz"] = &jujuclient.ControllerModels{
+		CurrentModel: "golden/horse",
+		Models: map[string]jujuclient.ModelDetails{"golden/horse": {
+			ModelType: model.IAAS,
+		}},
+	}
+	command := application.NewEstimateCostCommandForTest(s.api, &mockCharmStore{}, store)
+	return cmdtesting.RunCommandInDir(c, command, args, s.dir)
+}
+
+func (s *estimateCostSuite) writeLocalBundle(c *gc.C, content string) string {
+	path := filepath.Join(s.dir, "bundle.yaml")
+	err := ioutil.WriteFile(path, []byte(content), 0666)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+const costBundle = `
+applications:
+  prometheus:
+    charm: 'cs:xenial/prometheus-7'
+    num_units: 2
+    constraints: 'cores=4'
+    to: [new, 'lxd:new']
+  grafana:
+    charm: 'cs:xenial/grafana-19'
+    num_units: 3
+    to: ['1']
+  haproxy:
+    charm: 'cs:xenial/haproxy-4'
+    num_units: 1
+machines:
+  '1':
+    series: xenial
+`
+
+func (s *estimateCostSuite) TestNoArgs(c *gc.C) {
+	_, err := s.runEstimateCost(c)
+	c.Assert(err, gc.ErrorMatches, "no bundle specified")
+}
+
+func (s *estimateCostSuite) TestEstimateCostYAML(c *gc.C) {
+	ctx, err := s.runEstimateCost(c, s.writeLocalBundle(c, costBundle), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+machines:
+- name: machine 1
+  count: 1
+  instance-type: m5.large
+  hourly-cost: 0.096
+  monthly-cost: 70.08
+- name: haproxy
+  count: 1
+  instance-type: m5.large
+  hourly-cost: 0.096
+  monthly-cost: 70.08
+- name: prometheus
+  count: 1
+  constraints: cores=4
+  instance-type: m5.xlarge
+  hourly-cost: 0.192
+  monthly-cost: 140.16
+- name: prometheus (container hosts)
+  count: 1
+  instance-type: m5.large
+  hourly-cost: 0.096
+  monthly-cost: 70.08
+currency: USD
+hourly-cost: 0.48
+monthly-cost: 350.4
+`[1:])
+	c.Check(s.api.requested, jc.DeepEquals, []string{
+		"", "", "cores=4", "",
+	})
+}
+
+func (s *estimateCostSuite) TestEstimateCostTabularUnknown(c *gc.C) {
+	ctx, err := s.runEstimateCost(c, s.writeLocalBundle(c, `
+applications:
+  haproxy:
+    charm: 'cs:xenial/haproxy-4'
+    num_units: 2
+  postgresql:
+    charm: 'cs:xenial/postgresql-4'
+    num_units: 1
+    constraints: 'mem=1024G'
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+Machines    Count  Instance type  Hourly    Monthly     Notes
+haproxy     2      m5.large       0.19 USD  140.16 USD  
+postgresql  1      -              unknown   unknown     no instance types in us-east-1 matching constraints "arch=amd64 mem=1024G"
+Total                             0.19 USD  140.16 USD  
+
+Some costs are unknown, and are not included in the totals.
+`[1:])
+}
+
+func (s *estimateCostSuite) TestEstimateCostNoMachines(c *gc.C) {
+	ctx, err := s.runEstimateCost(c, s.writeLocalBundle(c, `
+applications:
+  grafana:
+    charm: 'cs:xenial/grafana-19'
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, "No machines would be added to the model.\n")
+	c.Check(s.api.requested, gc.HasLen, 0)
+}
@@ -291,6 +291,15 @@ func NewBundleDiffCommandForTest(api base.APICallCloser, charmStore BundleResolv
 	return modelcmd.Wrap(cmd)
 }
 
+func NewEstimateCostCommandForTest(api CostEstimateAPI, charmStore BundleResolver, store jujuclient.ClientStore) modelcmd.ModelCommand {
+	cmd := &estimateCostCommand{
+		_api:        api,
+		_charmStore: charmStore,
+	}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
 func NewShowCommandForTest(api ApplicationsInfoAPI, store jujuclient.ClientStore) cmd.Command {
 	cmd := &showApplicationCommand{newAPIFunc: func() (ApplicationsInfoAPI, error) {
 		return api, nil
@@ -299,6 +308,14 @@ func NewShowCommandForTest(api ApplicationsInfoAPI, store jujuclient.ClientStore
 	return modelcmd.Wrap(cmd)
 }
 
+func NewShowUnitCommandForTest(api UnitsInfoAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &showUnitCommand{newAPIFunc: func() (UnitsInfoAPI, error) {
+		return api, nil
+	}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
 type charmstoreClientToTestcharmsClientShim struct {
 	*csclient.Client
 }
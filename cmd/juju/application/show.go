@@ -184,6 +184,13 @@ type ApplicationInfo struct {
 	Exposed          bool              `yaml:"exposed" json:"exposed"`
 	Remote           bool              `yaml:"remote" json:"remote"`
 	EndpointBindings map[string]string `yaml:"endpoint-bindings,omitempty" json:"endpoint-bindings,omitempty"`
+
+	// UnitStatus summarises the workload status of the units.
+	UnitStatus string `yaml:"unit-status-summary,omitempty" json:"unit-status-summary,omitempty"`
+
+	// VersionSkew maps each workload version to the units reporting
+	// it, when the units do not all report the same version.
+	VersionSkew map[string][]string `yaml:"version-skew,omitempty" json:"version-skew,omitempty"`
 }
 
 func createApplicationInfo(details params.ApplicationResult) (names.ApplicationTag, ApplicationInfo, error) {
@@ -201,6 +208,8 @@ func createApplicationInfo(details params.ApplicationResult) (names.ApplicationT
 		Exposed:          details.Exposed,
 		Remote:           details.Remote,
 		EndpointBindings: details.EndpointBindings,
+		UnitStatus:       details.UnitStatusSummary,
+		VersionSkew:      details.WorkloadVersions,
 	}
 	return tag, info, nil
 }
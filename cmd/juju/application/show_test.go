@@ -191,6 +191,44 @@ func (s *ShowSuite) TestShowJSON(c *gc.C) {
 	})
 }
 
+func (s *ShowSuite) TestShowUnitSummaryAndVersionSkew(c *gc.C) {
+	s.mockAPI.applicationsInfoFunc = func([]names.ApplicationTag) ([]params.ApplicationInfoResult, error) {
+		info := s.createTestApplicationInfo("wordpress", "")
+		info.UnitStatusSummary = "2/3 active, 1 maintenance"
+		info.WorkloadVersions = map[string][]string{
+			"5.3": {"wordpress/0", "wordpress/2"},
+			"5.4": {"wordpress/1"},
+		}
+		return []params.ApplicationInfoResult{{Result: info}}, nil
+	}
+	s.assertRunShow(c, showTest{
+		args: []string{"wordpress"},
+		stdout: `
+wordpress:
+  charm: charm-wordpress
+  series: quantal
+  channel: development
+  constraints:
+    arch: amd64
+    cores: 1
+    mem: 4096
+    root-disk: 8192
+  principal: true
+  exposed: false
+  remote: false
+  endpoint-bindings:
+    juju-info: myspace
+  unit-status-summary: 2/3 active, 1 maintenance
+  version-skew:
+    "5.3":
+    - wordpress/0
+    - wordpress/2
+    "5.4":
+    - wordpress/1
+`[1:],
+	})
+}
+
 func (s *ShowSuite) TestShowMix(c *gc.C) {
 	s.mockAPI.applicationsInfoFunc = func([]names.ApplicationTag) ([]params.ApplicationInfoResult, error) {
 		return []params.ApplicationInfoResult{
@@ -0,0 +1,286 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const showUnitDoc = `
+The command takes deployed unit names as an argument.
+
+For each unit, the output includes the unit's workload and agent
+status, its charm, and for every relation the unit has joined:
+the settings of the unit itself, the settings of each related unit,
+the application settings of the related application and those of the
+unit's own application. If the unit is the leader of its application,
+the leader settings are also shown.
+
+Optionally, relation data for only a specified endpoint
+or related unit may be shown, or just the application data.
+The unit's own settings are always shown.
+
+Examples:
+    $ juju show-unit mysql/0
+    $ juju show-unit mysql/0 wordpress/1
+    $ juju show-unit mysql/0 --app
+    $ juju show-unit mysql/0 --endpoint db
+    $ juju show-unit mysql/0 --related-unit wordpress/2
+`
+
+// NewShowUnitCommand returns a command that displays unit info.
+func NewShowUnitCommand() cmd.Command {
+	s := &showUnitCommand{}
+	s.newAPIFunc = func() (UnitsInfoAPI, error) {
+		return s.newUnitAPI()
+	}
+	return modelcmd.Wrap(s)
+}
+
+// showUnitCommand displays unit information.
+type showUnitCommand struct {
+	modelcmd.ModelCommandBase
+
+	out         cmd.Output
+	units       []string
+	endpoint    string
+	relatedUnit string
+	appOnly     bool
+	newAPIFunc  func() (UnitsInfoAPI, error)
+}
+
+// Info implements Command.Info.
+func (c *showUnitCommand) Info() *cmd.Info {
+	showCmd := &cmd.Info{
+		Name:    "show-unit",
+		Args:    "<unit name>",
+		Purpose: "Displays information about a unit.",
+		Doc:     showUnitDoc,
+	}
+	return jujucmd.Info(showCmd)
+}
+
+// Init implements Command.Init.
+func (c *showUnitCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.Errorf("a unit name must be supplied")
+	}
+	c.units = args
+	var invalid []string
+	for _, one := range c.units {
+		if !names.IsValidUnit(one) {
+			invalid = append(invalid, one)
+		}
+	}
+	if c.relatedUnit != "" && !names.IsValidUnit(c.relatedUnit) {
+		invalid = append(invalid, c.relatedUnit)
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	plural := "s"
+	if len(invalid) == 1 {
+		plural = ""
+	}
+	return errors.NotValidf(`unit name%v %v`, plural, strings.Join(invalid, `, `))
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *showUnitCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+	f.StringVar(&c.endpoint, "endpoint", "", "Only show relation data for the specified endpoint")
+	f.StringVar(&c.relatedUnit, "related-unit", "", "Only show relation data for the specified unit")
+	f.BoolVar(&c.appOnly, "app", false, "Only show application relation data")
+}
+
+// UnitsInfoAPI defines the API methods that show-unit command uses.
+type UnitsInfoAPI interface {
+	Close() error
+	BestAPIVersion() int
+	UnitsInfo([]names.UnitTag) ([]params.UnitInfoResult, error)
+}
+
+func (c *showUnitCommand) newUnitAPI() (UnitsInfoAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return application.NewClient(root), nil
+}
+
+func (c *showUnitCommand) Run(ctx *cmd.Context) error {
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if v := client.BestAPIVersion(); v < 12 {
+		// old client does not support showing units.
+		return errors.NotSupportedf("show unit on API server version %v", v)
+	}
+
+	tags := make([]names.UnitTag, len(c.units))
+	for i, one := range c.units {
+		tags[i] = names.NewUnitTag(one)
+	}
+
+	results, err := client.UnitsInfo(tags)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	var errs params.ErrorResults
+	var valid []params.UnitResult
+	for _, result := range results {
+		if result.Error != nil {
+			errs.Results = append(errs.Results, params.ErrorResult{Error: result.Error})
+			continue
+		}
+		valid = append(valid, *result.Result)
+	}
+	if len(errs.Results) > 0 {
+		return errs.Combine()
+	}
+
+	output, err := c.formatUnitInfos(valid)
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, output)
+}
+
+// formatUnitInfos takes a set of params.UnitResult and
+// creates a mapping from unit name to unit info.
+func (c *showUnitCommand) formatUnitInfos(all []params.UnitResult) (map[string]UnitInfo, error) {
+	if len(all) == 0 {
+		return nil, nil
+	}
+	output := make(map[string]UnitInfo)
+	for _, one := range all {
+		tag, info, err := c.createUnitInfo(one)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		output[tag.Id()] = info
+	}
+	return output, nil
+}
+
+// UnitInfo defines the serialization behaviour of the unit information.
+type UnitInfo struct {
+	WorkloadVersion string             `yaml:"workload-version,omitempty" json:"workload-version,omitempty"`
+	WorkloadStatus  UnitStatus         `yaml:"workload-status" json:"workload-status"`
+	AgentStatus     UnitStatus         `yaml:"juju-status" json:"juju-status"`
+	Machine         string             `yaml:"machine,omitempty" json:"machine,omitempty"`
+	OpenedPorts     []string           `yaml:"opened-ports,omitempty" json:"opened-ports,omitempty"`
+	PublicAddress   string             `yaml:"public-address,omitempty" json:"public-address,omitempty"`
+	Charm           string             `yaml:"charm" json:"charm"`
+	Leader          bool               `yaml:"leader" json:"leader"`
+	LeaderSettings  map[string]string  `yaml:"leader-settings,omitempty" json:"leader-settings,omitempty"`
+	RelationData    []UnitRelationData `yaml:"relation-info,omitempty" json:"relation-info,omitempty"`
+}
+
+// UnitStatus defines the serialization behaviour of a unit's status.
+type UnitStatus struct {
+	Current string     `yaml:"current,omitempty" json:"current,omitempty"`
+	Message string     `yaml:"message,omitempty" json:"message,omitempty"`
+	Since   *time.Time `yaml:"since,omitempty" json:"since,omitempty"`
+}
+
+// UnitRelationData defines the serialization behaviour of the data
+// in one of the unit's relations.
+type UnitRelationData struct {
+	RelationId           int                         `yaml:"relation-id" json:"relation-id"`
+	Endpoint             string                      `yaml:"endpoint" json:"endpoint"`
+	CrossModel           bool                        `yaml:"cross-model,omitempty" json:"cross-model,omitempty"`
+	RelatedEndpoint      string                      `yaml:"related-endpoint" json:"related-endpoint"`
+	ApplicationData      map[string]interface{}      `yaml:"application-data" json:"application-data"`
+	Data                 map[string]RelationUnitData `yaml:"related-units,omitempty" json:"related-units,omitempty"`
+	LocalUnitData        *RelationUnitData           `yaml:"local-unit,omitempty" json:"local-unit,omitempty"`
+	LocalApplicationData map[string]interface{}      `yaml:"local-application-data,omitempty" json:"local-application-data,omitempty"`
+}
+
+// RelationUnitData defines the serialization behaviour of a single
+// unit's relation settings.
+type RelationUnitData struct {
+	InScope  bool                   `yaml:"in-scope" json:"in-scope"`
+	UnitData map[string]interface{} `yaml:"data" json:"data"`
+}
+
+func (c *showUnitCommand) createUnitInfo(details params.UnitResult) (names.UnitTag, UnitInfo, error) {
+	tag, err := names.ParseUnitTag(details.Tag)
+	if err != nil {
+		return names.UnitTag{}, UnitInfo{}, errors.Trace(err)
+	}
+
+	info := UnitInfo{
+		WorkloadVersion: details.WorkloadVersion,
+		WorkloadStatus: UnitStatus{
+			Current: details.WorkloadStatus.Status,
+			Message: details.WorkloadStatus.Info,
+			Since:   details.WorkloadStatus.Since,
+		},
+		AgentStatus: UnitStatus{
+			Current: details.AgentStatus.Status,
+			Message: details.AgentStatus.Info,
+			Since:   details.AgentStatus.Since,
+		},
+		Machine:        details.Machine,
+		OpenedPorts:    details.OpenedPorts,
+		PublicAddress:  details.PublicAddress,
+		Charm:          details.Charm,
+		Leader:         details.Leader,
+		LeaderSettings: details.LeaderSettings,
+	}
+
+	for _, rd := range details.RelationData {
+		if c.endpoint != "" && rd.Endpoint != c.endpoint {
+			continue
+		}
+		urd := UnitRelationData{
+			RelationId:           rd.RelationId,
+			Endpoint:             rd.Endpoint,
+			CrossModel:           rd.CrossModel,
+			RelatedEndpoint:      rd.RelatedEndpoint,
+			ApplicationData:      rd.ApplicationData,
+			LocalApplicationData: rd.LocalApplicationData,
+		}
+		if !c.appOnly {
+			for unitName, data := range rd.UnitRelationData {
+				unitData := RelationUnitData{
+					InScope:  data.InScope,
+					UnitData: data.UnitData,
+				}
+				// The unit's own settings are shown regardless of
+				// which related unit was asked for.
+				if unitName == tag.Id() {
+					urd.LocalUnitData = &unitData
+					continue
+				}
+				if c.relatedUnit != "" && unitName != c.relatedUnit {
+					continue
+				}
+				if urd.Data == nil {
+					urd.Data = make(map[string]RelationUnitData)
+				}
+				urd.Data[unitName] = unitData
+			}
+		}
+		info.RelationData = append(info.RelationData, urd)
+	}
+	return tag, info, nil
+}
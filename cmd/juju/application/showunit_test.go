@@ -0,0 +1,203 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/application"
+	"github.com/juju/juju/jujuclient"
+	jujutesting "github.com/juju/juju/testing"
+)
+
+type ShowUnitSuite struct {
+	jujutesting.FakeJujuXDGDataHomeSuite
+	store *jujuclient.MemStore
+
+	mockAPI *mockShowUnitAPI
+}
+
+var _ = gc.Suite(&ShowUnitSuite{})
+
+func (s *ShowUnitSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Models["testing"] = &jujuclient.ControllerModels{
+		Models: map[string]jujuclient.ModelDetails{
+			"admin/controller": {},
+		},
+		CurrentModel: "admin/controller",
+	}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+
+	s.mockAPI = &mockShowUnitAPI{
+		version:       12,
+		unitsInfoFunc: func([]names.UnitTag) ([]params.UnitInfoResult, error) { return nil, nil },
+	}
+}
+
+func (s *ShowUnitSuite) runShow(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, application.NewShowUnitCommandForTest(s.mockAPI, s.store), args...)
+}
+
+func (s *ShowUnitSuite) TestShowNoArguments(c *gc.C) {
+	_, err := s.runShow(c)
+	c.Assert(err, gc.ErrorMatches, "a unit name must be supplied")
+}
+
+func (s *ShowUnitSuite) TestShowInvalidName(c *gc.C) {
+	_, err := s.runShow(c, "mysql", "wordpress/0", "~bad")
+	c.Assert(err, gc.ErrorMatches, `unit names mysql, ~bad not valid`)
+}
+
+func (s *ShowUnitSuite) TestShowInvalidRelatedUnit(c *gc.C) {
+	_, err := s.runShow(c, "mysql/0", "--related-unit", "wordpress")
+	c.Assert(err, gc.ErrorMatches, `unit name wordpress not valid`)
+}
+
+func (s *ShowUnitSuite) TestShowUnsupported(c *gc.C) {
+	s.mockAPI.version = 11
+	_, err := s.runShow(c, "mysql/0")
+	c.Assert(err, gc.ErrorMatches, "show unit on API server version 11 not supported")
+}
+
+func (s *ShowUnitSuite) TestShowApiError(c *gc.C) {
+	s.mockAPI.unitsInfoFunc = func([]names.UnitTag) ([]params.UnitInfoResult, error) {
+		return []params.UnitInfoResult{
+			{Error: &params.Error{Message: "boom"}},
+		}, nil
+	}
+	_, err := s.runShow(c, "mysql/0")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ShowUnitSuite) setupUnitResult() {
+	s.mockAPI.unitsInfoFunc = func(tags []names.UnitTag) ([]params.UnitInfoResult, error) {
+		if len(tags) != 1 || tags[0].Id() != "mysql/0" {
+			return nil, nil
+		}
+		return []params.UnitInfoResult{{
+			Result: &params.UnitResult{
+				Tag:             "unit-mysql-0",
+				WorkloadVersion: "5.7",
+				WorkloadStatus:  params.DetailedStatus{Status: "active", Info: "ready"},
+				AgentStatus:     params.DetailedStatus{Status: "idle"},
+				Machine:         "0",
+				OpenedPorts:     []string{"3306/tcp"},
+				PublicAddress:   "10.0.0.1",
+				Charm:           "cs:mysql-42",
+				Leader:          true,
+				LeaderSettings:  map[string]string{"password": "secret"},
+				RelationData: []params.EndpointRelationData{{
+					RelationId:           1,
+					Endpoint:             "db",
+					RelatedEndpoint:      "server",
+					ApplicationData:      map[string]interface{}{"database": "wordpress"},
+					LocalApplicationData: map[string]interface{}{"port": "3306"},
+					UnitRelationData: map[string]params.RelationData{
+						"mysql/0": {
+							InScope:  true,
+							UnitData: map[string]interface{}{"host": "10.0.0.1"},
+						},
+						"wordpress/0": {
+							InScope:  true,
+							UnitData: map[string]interface{}{"user": "wp"},
+						},
+					},
+				}, {
+					RelationId:      2,
+					Endpoint:        "cluster",
+					RelatedEndpoint: "cluster",
+				}},
+			},
+		}}, nil
+	}
+}
+
+func (s *ShowUnitSuite) TestShow(c *gc.C) {
+	s.setupUnitResult()
+	ctx, err := s.runShow(c, "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+mysql/0:
+  workload-version: "5.7"
+  workload-status:
+    current: active
+    message: ready
+  juju-status:
+    current: idle
+  machine: "0"
+  opened-ports:
+  - 3306/tcp
+  public-address: 10.0.0.1
+  charm: cs:mysql-42
+  leader: true
+  leader-settings:
+    password: secret
+  relation-info:
+  - relation-id: 1
+    endpoint: db
+    related-endpoint: server
+    application-data:
+      database: wordpress
+    related-units:
+      wordpress/0:
+        in-scope: true
+        data:
+          user: wp
+    local-unit:
+      in-scope: true
+      data:
+        host: 10.0.0.1
+    local-application-data:
+      port: "3306"
+  - relation-id: 2
+    endpoint: cluster
+    related-endpoint: cluster
+    application-data: {}
+`[1:])
+}
+
+func (s *ShowUnitSuite) TestShowEndpointAppOnly(c *gc.C) {
+	s.setupUnitResult()
+	ctx, err := s.runShow(c, "mysql/0", "--endpoint", "db", "--app", "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `{"mysql/0":{"workload-version":"5.7","workload-status":{"current":"active","message":"ready"},"juju-status":{"current":"idle"},"machine":"0","opened-ports":["3306/tcp"],"public-address":"10.0.0.1","charm":"cs:mysql-42","leader":true,"leader-settings":{"password":"secret"},"relation-info":[{"relation-id":1,"endpoint":"db","related-endpoint":"server","application-data":{"database":"wordpress"},"local-application-data":{"port":"3306"}}]}}
+`)
+}
+
+func (s *ShowUnitSuite) TestShowRelatedUnit(c *gc.C) {
+	s.setupUnitResult()
+	ctx, err := s.runShow(c, "mysql/0", "--endpoint", "db", "--related-unit", "wordpress/0", "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), jc.Contains, `"related-units":{"wordpress/0":{"in-scope":true,"data":{"user":"wp"}}}`)
+	c.Assert(cmdtesting.Stdout(ctx), jc.Contains, `"local-unit":{"in-scope":true,"data":{"host":"10.0.0.1"}}`)
+}
+
+type mockShowUnitAPI struct {
+	version       int
+	unitsInfoFunc func([]names.UnitTag) ([]params.UnitInfoResult, error)
+}
+
+func (s mockShowUnitAPI) Close() error {
+	return nil
+}
+
+func (s mockShowUnitAPI) BestAPIVersion() int {
+	return s.version
+}
+
+func (s mockShowUnitAPI) UnitsInfo(tags []names.UnitTag) ([]params.UnitInfoResult, error) {
+	return s.unitsInfoFunc(tags)
+}
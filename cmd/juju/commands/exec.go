@@ -12,8 +12,10 @@ import (
 	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
+	"github.com/juju/naturalsort"
 	"github.com/juju/utils"
 	"github.com/juju/utils/featureflag"
 	"gopkg.in/juju/names.v3"
@@ -58,9 +60,21 @@ type execCommand struct {
 	applications []string
 	units        []string
 	commands     string
+	parallel     int
+	onFailure    string
 	timeAfter    func(time.Duration) <-chan time.Time
 }
 
+const (
+	// onFailureContinue runs the commands on every target, regardless
+	// of whether they fail on some of them.
+	onFailureContinue = "continue"
+
+	// onFailureAbort stops running the commands on further batches of
+	// targets once they have failed on any target.
+	onFailureAbort = "abort"
+)
+
 const execDoc = `
 Run a shell command on the specified targets. Only admin users of a model
 are able to use this command.
@@ -102,6 +116,22 @@ targets.
 Since juju exec creates actions, you can query for the status of commands
 started with juju run by calling "juju show-action-status --name juju-run".
 
+By default the commands are run on all targets at once. To run them on a
+limited number of targets at a time, use --parallel. Targets are then run in
+batches of the given size, in a stable order: machines first, followed by
+units, and each batch is allowed to complete before the next one starts.
+Applications and --all are expanded into their units and machines.
+With --on-failure=abort, which requires --parallel, no further batches are
+started once the commands fail on any target; a failure is an error queuing or
+running the commands, a non-zero exit code, or a timeout.
+
+When --format is yaml or json, the result for each target includes the
+machine or unit id, stdout, any stderr, the status of the commands, their exit
+code, the times at which the commands were enqueued, started and completed as
+RFC3339 timestamps, and how long the commands took to run. For example:
+
+    juju exec --format json --application mysql --parallel 1 --on-failure abort -- service mysql restart
+
 If you need to pass options to the command being run, you must precede the
 command and its arguments with "--", to tell "juju exec" to stop processing
 those arguments. For example:
@@ -143,6 +173,8 @@ func (c *execCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(cmd.NewStringsValue(nil, &c.applications), "application", "")
 	f.Var(cmd.NewStringsValue(nil, &c.units), "u", "One or more unit ids")
 	f.Var(cmd.NewStringsValue(nil, &c.units), "unit", "")
+	f.IntVar(&c.parallel, "parallel", 0, "Run the commands on at most this many targets at a time (0 means all at once)")
+	f.StringVar(&c.onFailure, "on-failure", onFailureContinue, "What to do when the commands fail on a target: continue or abort")
 }
 
 func (c *execCommand) Init(args []string) error {
@@ -176,6 +208,18 @@ func (c *execCommand) Init(args []string) error {
 		}
 	}
 
+	if c.parallel < 0 {
+		return errors.Errorf("--parallel must not be negative")
+	}
+	switch c.onFailure {
+	case onFailureContinue, onFailureAbort:
+	default:
+		return errors.Errorf("--on-failure must be %q or %q", onFailureContinue, onFailureAbort)
+	}
+	if c.onFailure == onFailureAbort && c.parallel == 0 {
+		return errors.Errorf("--on-failure=%s requires --parallel", onFailureAbort)
+	}
+
 	var nameErrors []string
 	for _, machineId := range c.machines {
 		if !names.IsValidMachine(machineId) {
@@ -243,15 +287,83 @@ func ConvertActionResults(result params.ActionResult, query actionQuery) map[str
 			values["Stderr.encoding"] = res
 		}
 	}
-	if res, ok := result.Output["Code"].(string); ok {
-		code, err := strconv.Atoi(res)
-		if err == nil && code != 0 {
-			values["ReturnCode"] = code
-		}
+	if code, ok := actionReturnCode(result); ok && code != 0 {
+		values["ReturnCode"] = code
 	}
 	return values
 }
 
+// convertStructuredActionResults is like ConvertActionResults, but also
+// includes the status and timing of the action and, whenever the
+// commands ran, their exit code, so that scripts reading yaml or json
+// output can rely on them being present.
+func convertStructuredActionResults(result params.ActionResult, query actionQuery) map[string]interface{} {
+	values := ConvertActionResults(result, query)
+	if _, ok := values["Error"]; ok {
+		return values
+	}
+	if result.Status != "" {
+		values["Status"] = result.Status
+	}
+	if timing := actionTiming(result); len(timing) > 0 {
+		values["Timing"] = timing
+	}
+	if code, ok := actionReturnCode(result); ok {
+		values["ReturnCode"] = code
+	}
+	return values
+}
+
+// actionReturnCode returns the exit code of the commands run by the
+// action, if they ran.
+func actionReturnCode(result params.ActionResult) (int, bool) {
+	res, ok := result.Output["Code"].(string)
+	if !ok {
+		return 0, false
+	}
+	code, err := strconv.Atoi(res)
+	if err != nil {
+		return 0, false
+	}
+	return code, true
+}
+
+// actionTiming returns the times at which the action was enqueued,
+// started and completed, formatted as RFC3339 timestamps, along with
+// how long it took to run.
+func actionTiming(result params.ActionResult) map[string]string {
+	timing := make(map[string]string)
+	for k, t := range map[string]time.Time{
+		"Enqueued":  result.Enqueued,
+		"Started":   result.Started,
+		"Completed": result.Completed,
+	} {
+		if !t.IsZero() {
+			timing[k] = t.Format(time.RFC3339)
+		}
+	}
+	if !result.Started.IsZero() && !result.Completed.IsZero() {
+		timing["Duration"] = result.Completed.Sub(result.Started).String()
+	}
+	return timing
+}
+
+// execFailed reports whether the result of running the commands on a
+// target represents a failure.
+func execFailed(result params.ActionResult) bool {
+	if result.Error != nil {
+		return true
+	}
+	if code, ok := actionReturnCode(result); ok && code != 0 {
+		return true
+	}
+	switch result.Status {
+	case params.ActionFailed, params.ActionCancelled:
+		return true
+	}
+	return false
+}
+
 func (c *execCommand) Run(ctx *cmd.Context) error {
 	client, err := getExecAPIClient(c)
 	if err != nil {
@@ -274,56 +386,186 @@ func (c *execCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 
-	var runResults []params.ActionResult
-	if c.all {
-		runResults, err = client.RunOnAllMachines(c.commands, c.timeout)
-	} else {
-		// Make sure the server supports <application>/leader syntax
-		for _, unit := range c.units {
-			if validLeader.MatchString(unit) && client.BestAPIVersion() < 3 {
-				app := strings.Split(unit, "/")[0]
-				return errors.Errorf("unable to determine leader for application %q"+
-					"\nleader determination is unsupported by this API"+
-					"\neither upgrade your controller, or explicitly specify a unit", app)
+	// Make sure the server supports <application>/leader syntax
+	for _, unit := range c.units {
+		if validLeader.MatchString(unit) && client.BestAPIVersion() < 3 {
+			app := strings.Split(unit, "/")[0]
+			return errors.Errorf("unable to determine leader for application %q"+
+				"\nleader determination is unsupported by this API"+
+				"\neither upgrade your controller, or explicitly specify a unit", app)
+		}
+	}
+	if !c.all && c.operator && modelType != model.CAAS {
+		return errors.Errorf("only k8s models support the --operator flag")
+	}
+
+	runParams := params.RunParams{
+		Commands:     c.commands,
+		Timeout:      c.timeout,
+		Machines:     c.machines,
+		Applications: c.applications,
+		Units:        c.units,
+	}
+	if modelType == model.CAAS {
+		runParams.WorkloadContext = !c.operator
+	}
+
+	if c.parallel == 0 {
+		values, pending, _, err := c.execBatch(ctx, client, runParams, c.all)
+		if err != nil {
+			return err
+		}
+		return c.writeResults(ctx, values, pending, nil)
+	}
+
+	targets, err := c.resolveTargets()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(targets) == 0 {
+		return errors.New("no targets found to run the commands on")
+	}
+	var (
+		values  []interface{}
+		pending []actionQuery
+		skipped []string
+	)
+	for len(targets) > 0 {
+		n := c.parallel
+		if n > len(targets) {
+			n = len(targets)
+		}
+		batch := runParams
+		batch.Machines, batch.Applications, batch.Units = nil, nil, nil
+		for _, target := range targets[:n] {
+			if names.IsValidMachine(target) {
+				batch.Machines = append(batch.Machines, target)
+			} else {
+				batch.Units = append(batch.Units, target)
 			}
 		}
+		targets = targets[n:]
 
-		params := params.RunParams{
-			Commands:     c.commands,
-			Timeout:      c.timeout,
-			Machines:     c.machines,
-			Applications: c.applications,
-			Units:        c.units,
+		batchValues, batchPending, failed, err := c.execBatch(ctx, client, batch, false)
+		if err != nil {
+			// The results of the earlier batches are still written.
+			if writeErr := c.writePartialResults(ctx, values, pending); writeErr != nil {
+				return writeErr
+			}
+			return err
+		}
+		values = append(values, batchValues...)
+		pending = append(pending, batchPending...)
+		// Commands that did not complete in time count as failed.
+		if c.onFailure == onFailureAbort && len(targets) > 0 && (failed || len(batchPending) > 0) {
+			skipped = targets
+			break
 		}
-		if c.operator {
-			if modelType != model.CAAS {
-				return errors.Errorf("only k8s models support the --operator flag")
+	}
+	return c.writeResults(ctx, values, pending, skipped)
+}
+
+// resolveTargets returns the machines and units to run the commands on,
+// in the order in which they are to be run. Applications, and all
+// machines when --all is specified, are expanded using the model status.
+func (c *execCommand) resolveTargets() ([]string, error) {
+	seen := set.NewStrings()
+	var targets []string
+	add := func(ids ...string) {
+		for _, id := range ids {
+			if !seen.Contains(id) {
+				seen.Add(id)
+				targets = append(targets, id)
 			}
 		}
-		if modelType == model.CAAS {
-			params.WorkloadContext = !c.operator
+	}
+	add(c.machines...)
+	add(c.units...)
+	if !c.all && len(c.applications) == 0 {
+		return targets, nil
+	}
+
+	client, err := getExecStatusClient(c)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer client.Close()
+
+	var patterns []string
+	if !c.all {
+		patterns = c.applications
+	}
+	status, err := client.Status(patterns)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if c.all {
+		var machines []string
+		var addMachines func(map[string]params.MachineStatus)
+		addMachines = func(all map[string]params.MachineStatus) {
+			for id, machine := range all {
+				machines = append(machines, id)
+				addMachines(machine.Containers)
+			}
+		}
+		addMachines(status.Machines)
+		naturalsort.Sort(machines)
+		add(machines...)
+		return targets, nil
+	}
+	for _, appName := range c.applications {
+		app, ok := status.Applications[appName]
+		if !ok {
+			return nil, errors.NotFoundf("application %q", appName)
+		}
+		units := make([]string, 0, len(app.Units))
+		for unitName := range app.Units {
+			units = append(units, unitName)
 		}
-		runResults, err = client.Run(params)
+		naturalsort.Sort(units)
+		add(units...)
 	}
+	return targets, nil
+}
 
+// execBatch enqueues the commands on the targets described by runParams,
+// or on all machines, and waits for them to complete. It returns the
+// converted results along with the actions that did not complete before
+// the timeout, and whether the commands failed on any target.
+func (c *execCommand) execBatch(
+	ctx *cmd.Context, client ExecClient, runParams params.RunParams, all bool,
+) ([]interface{}, []actionQuery, bool, error) {
+	var runResults []params.ActionResult
+	var err error
+	if all {
+		runResults, err = client.RunOnAllMachines(runParams.Commands, runParams.Timeout)
+	} else {
+		runResults, err = client.Run(runParams)
+	}
 	if err != nil {
-		return block.ProcessBlockedError(err, block.BlockChange)
+		return nil, nil, false, block.ProcessBlockedError(err, block.BlockChange)
 	}
 
+	// Targets on which the commands could not be enqueued never ran
+	// them, so they count as failed.
+	failed := false
 	actionsToQuery := []actionQuery{}
 	for _, result := range runResults {
 		if result.Error != nil {
 			fmt.Fprintf(ctx.GetStderr(), "couldn't queue one action: %v\n", result.Error)
+			failed = true
 			continue
 		}
 		actionTag, err := names.ParseActionTag(result.Action.Tag)
 		if err != nil {
 			fmt.Fprintf(ctx.GetStderr(), "got invalid action tag %v for receiver %v\n", result.Action.Tag, result.Action.Receiver)
+			failed = true
 			continue
 		}
 		receiverTag, err := names.ActionReceiverFromTag(result.Action.Receiver)
 		if err != nil {
 			fmt.Fprintf(ctx.GetStderr(), "got invalid action receiver tag %v for action %v\n", result.Action.Receiver, result.Action.Tag)
+			failed = true
 			continue
 		}
 		var receiverType string
@@ -344,7 +586,7 @@ func (c *execCommand) Run(ctx *cmd.Context) error {
 	}
 
 	if len(actionsToQuery) == 0 {
-		return errors.New("no actions were successfully enqueued, aborting")
+		return nil, nil, true, errors.New("no actions were successfully enqueued, aborting")
 	}
 
 	timeout := c.timeAfter(c.timeout)
@@ -352,7 +594,7 @@ func (c *execCommand) Run(ctx *cmd.Context) error {
 	for len(actionsToQuery) > 0 {
 		actionResults, err := client.Actions(entities(actionsToQuery))
 		if err != nil {
-			return errors.Trace(err)
+			return nil, nil, false, errors.Trace(err)
 		}
 
 		newActionsToQuery := []actionQuery{}
@@ -365,7 +607,12 @@ func (c *execCommand) Run(ctx *cmd.Context) error {
 				}
 			}
 
-			values = append(values, ConvertActionResults(result, actionsToQuery[i]))
+			failed = failed || execFailed(result)
+			if c.out.Name() == "default" {
+				values = append(values, ConvertActionResults(result, actionsToQuery[i]))
+			} else {
+				values = append(values, convertStructuredActionResults(result, actionsToQuery[i]))
+			}
 		}
 		actionsToQuery = newActionsToQuery
 
@@ -385,10 +632,16 @@ func (c *execCommand) Run(ctx *cmd.Context) error {
 			}
 		}
 	}
+	return values, actionsToQuery, failed, nil
+}
 
+// writeResults writes the results of running the commands, and returns
+// an error if any of them timed out, or if any targets were skipped
+// because the commands failed on an earlier batch.
+func (c *execCommand) writeResults(ctx *cmd.Context, values []interface{}, actionsToQuery []actionQuery, skipped []string) error {
 	// If we are just dealing with one result, AND we are using the default
 	// format, then pretend we were running it locally.
-	if len(actionsToQuery) == 0 && len(values) == 1 && c.out.Name() == "default" {
+	if len(actionsToQuery) == 0 && len(skipped) == 0 && len(values) == 1 && c.out.Name() == "default" {
 		result, ok := values[0].(map[string]interface{})
 		if !ok {
 			return errors.New("couldn't read action output")
@@ -415,24 +668,50 @@ func (c *execCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 
-	if n := len(actionsToQuery); n > 0 {
+	if len(actionsToQuery) > 0 {
 		// There are action results remaining, so return an error.
-		suffix := ""
-		if n > 1 {
-			suffix = "s"
-		}
-		receivers := make([]string, n)
-		for i, actionToQuery := range actionsToQuery {
-			receivers[i] = names.ReadableString(actionToQuery.receiver.tag)
-		}
+		return timedOutError(actionsToQuery)
+	}
+	if len(skipped) > 0 {
 		return errors.Errorf(
-			"timed out waiting for result%s from: %s",
-			suffix, strings.Join(receivers, ", "),
+			"commands failed, not run on: %s", strings.Join(skipped, ", "),
 		)
 	}
 	return nil
 }
 
+// writePartialResults writes the results of the batches that ran before
+// a later batch could not be run. Any commands which timed out are
+// reported on stderr, as the error of the later batch is returned.
+func (c *execCommand) writePartialResults(ctx *cmd.Context, values []interface{}, actionsToQuery []actionQuery) error {
+	if len(values) > 0 {
+		if err := c.out.Write(ctx, values); err != nil {
+			return err
+		}
+	}
+	if len(actionsToQuery) > 0 {
+		fmt.Fprintf(ctx.GetStderr(), "%v\n", timedOutError(actionsToQuery))
+	}
+	return nil
+}
+
+// timedOutError returns an error naming the receivers of the actions
+// whose results were not available before the timeout.
+func timedOutError(actionsToQuery []actionQuery) error {
+	suffix := ""
+	if len(actionsToQuery) > 1 {
+		suffix = "s"
+	}
+	receivers := make([]string, len(actionsToQuery))
+	for i, actionToQuery := range actionsToQuery {
+		receivers[i] = names.ReadableString(actionToQuery.receiver.tag)
+	}
+	return errors.Errorf(
+		"timed out waiting for result%s from: %s",
+		suffix, strings.Join(receivers, ", "),
+	)
+}
+
 type actionReceiver struct {
 	receiverType string
 	tag          names.Tag
@@ -460,6 +739,19 @@ var getExecAPIClient = func(c *execCommand) (ExecClient, error) {
 	return actionapi.NewClient(root), errors.Trace(err)
 }
 
+// ExecStatusClient exposes the model status, used to expand applications
+// and --all into individual targets when running in batches.
+type ExecStatusClient interface {
+	Close() error
+	Status(patterns []string) (*params.FullStatus, error)
+}
+
+// getExecStatusClient is a variable so the status API can be mocked out
+// for testing.
+var getExecStatusClient = func(c *execCommand) (ExecStatusClient, error) {
+	return c.NewAPIClient()
+}
+
 // getActionResult abstracts over the action CLI function that we use here to fetch results
 var getActionResult = func(c ExecClient, actionId string, wait *time.Timer) (params.ActionResult, error) {
 	return action.GetActionResult(c, actionId, wait)
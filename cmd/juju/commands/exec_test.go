@@ -242,6 +242,47 @@ func (*ExecSuite) TestTimeoutArgParsing(c *gc.C) {
 	}
 }
 
+func (*ExecSuite) TestParallelArgParsing(c *gc.C) {
+	for i, test := range []struct {
+		message   string
+		args      []string
+		errMatch  string
+		parallel  int
+		onFailure string
+	}{{
+		message:   "defaults",
+		args:      []string{"--all", "sudo reboot"},
+		onFailure: "continue",
+	}, {
+		message:   "parallel with abort",
+		args:      []string{"--parallel=2", "--on-failure=abort", "--all", "sudo reboot"},
+		parallel:  2,
+		onFailure: "abort",
+	}, {
+		message:  "negative parallel",
+		args:     []string{"--parallel=-1", "--all", "sudo reboot"},
+		errMatch: "--parallel must not be negative",
+	}, {
+		message:  "invalid on-failure",
+		args:     []string{"--on-failure=retry", "--all", "sudo reboot"},
+		errMatch: `--on-failure must be "continue" or "abort"`,
+	}, {
+		message:  "abort without parallel",
+		args:     []string{"--on-failure=abort", "--all", "sudo reboot"},
+		errMatch: "--on-failure=abort requires --parallel",
+	}} {
+		c.Log(fmt.Sprintf("%v: %s", i, test.message))
+		cmd := &execCommand{}
+		cmd.SetClientStore(minimalStore(model.IAAS))
+		runCmd := modelcmd.Wrap(cmd)
+		cmdtesting.TestInit(c, runCmd, test.args, test.errMatch)
+		if test.errMatch == "" {
+			c.Check(cmd.parallel, gc.Equals, test.parallel)
+			c.Check(cmd.onFailure, gc.Equals, test.onFailure)
+		}
+	}
+}
+
 func (s *ExecSuite) TestConvertRunResults(c *gc.C) {
 	for i, test := range []struct {
 		message  string
@@ -304,6 +345,14 @@ func (s *ExecSuite) TestConvertRunResults(c *gc.C) {
 			"Message":    "msg",
 			"ReturnCode": 42,
 		},
+	}, {
+		message: "status, timing and a zero return code are omitted",
+		results: makeTimedActionResult(),
+		query:   makeActionQuery(validUUID, "MachineId", names.NewMachineTag("1")),
+		expected: map[string]interface{}{
+			"MachineId": "1",
+			"Stdout":    "stdout",
+		},
 	}} {
 		c.Log(fmt.Sprintf("%v: %s", i, test.message))
 		result := ConvertActionResults(test.results, test.query)
@@ -311,6 +360,38 @@ func (s *ExecSuite) TestConvertRunResults(c *gc.C) {
 	}
 }
 
+func makeTimedActionResult() params.ActionResult {
+	result := makeActionResult(mockResponse{
+		machineTag: "machine-1",
+		stdout:     "stdout",
+		code:       "0",
+		status:     params.ActionCompleted,
+	}, "action-"+validUUID)
+	result.Enqueued = time.Date(2019, 1, 1, 10, 0, 0, 0, time.UTC)
+	result.Started = time.Date(2019, 1, 1, 10, 0, 1, 0, time.UTC)
+	result.Completed = time.Date(2019, 1, 1, 10, 0, 4, 0, time.UTC)
+	return result
+}
+
+func (s *ExecSuite) TestConvertStructuredRunResults(c *gc.C) {
+	result := convertStructuredActionResults(
+		makeTimedActionResult(),
+		makeActionQuery(validUUID, "MachineId", names.NewMachineTag("1")),
+	)
+	c.Check(result, jc.DeepEquals, map[string]interface{}{
+		"MachineId":  "1",
+		"Stdout":     "stdout",
+		"Status":     "completed",
+		"ReturnCode": 0,
+		"Timing": map[string]string{
+			"Enqueued":  "2019-01-01T10:00:00Z",
+			"Started":   "2019-01-01T10:00:01Z",
+			"Completed": "2019-01-01T10:00:04Z",
+			"Duration":  "3s",
+		},
+	})
+}
+
 func (s *ExecSuite) TestExecForMachineAndUnit(c *gc.C) {
 	mock := s.setupMockAPI()
 	machineResponse := mockResponse{
@@ -334,8 +415,8 @@ func (s *ExecSuite) TestExecForMachineAndUnit(c *gc.C) {
 	machineQuery := makeActionQuery(mock.receiverIdMap["0"], "MachineId", names.NewMachineTag("0"))
 	unitQuery := makeActionQuery(mock.receiverIdMap["unit/0"], "UnitId", names.NewUnitTag("unit/0"))
 	unformatted := []interface{}{
-		ConvertActionResults(machineResult, machineQuery),
-		ConvertActionResults(unitResult, unitQuery),
+		convertStructuredActionResults(machineResult, machineQuery),
+		convertStructuredActionResults(unitResult, unitQuery),
 	}
 
 	buff := &bytes.Buffer{}
@@ -389,8 +470,8 @@ func (s *ExecSuite) TestAllMachines(c *gc.C) {
 	machine0Query := makeActionQuery(mock.receiverIdMap["0"], "MachineId", names.NewMachineTag("0"))
 	machine1Query := makeActionQuery(mock.receiverIdMap["1"], "MachineId", names.NewMachineTag("1"))
 	unformatted := []interface{}{
-		ConvertActionResults(machine0Result, machine0Query),
-		ConvertActionResults(machine1Result, machine1Query),
+		convertStructuredActionResults(machine0Result, machine0Query),
+		convertStructuredActionResults(machine1Result, machine1Query),
 		map[string]interface{}{
 			"Action":    mock.receiverIdMap["2"],
 			"MachineId": "2",
@@ -441,7 +522,7 @@ func (s *ExecSuite) TestTimeout(c *gc.C) {
 
 	var buf bytes.Buffer
 	err := cmd.FormatJson(&buf, []interface{}{
-		ConvertActionResults(machine0Result, machine0Query),
+		convertStructuredActionResults(machine0Result, machine0Query),
 	})
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -537,7 +618,7 @@ func (s *ExecSuite) TestCAASExecOnOperator(c *gc.C) {
 
 	unitQuery := makeActionQuery(mock.receiverIdMap["unit/0"], "UnitId", names.NewUnitTag("unit/0"))
 	unformatted := []interface{}{
-		ConvertActionResults(unitResult, unitQuery),
+		convertStructuredActionResults(unitResult, unitQuery),
 	}
 
 	buff := &bytes.Buffer{}
@@ -574,7 +655,7 @@ func (s *ExecSuite) TestCAASExecOnWorkload(c *gc.C) {
 
 	unitQuery := makeActionQuery(mock.receiverIdMap["unit/0"], "UnitId", names.NewUnitTag("unit/0"))
 	unformatted := []interface{}{
-		ConvertActionResults(unitResult, unitQuery),
+		convertStructuredActionResults(unitResult, unitQuery),
 	}
 
 	buff := &bytes.Buffer{}
@@ -654,7 +735,7 @@ func (s *ExecSuite) TestSingleResponse(c *gc.C) {
 
 	query := makeActionQuery(mock.receiverIdMap["0"], "MachineId", names.NewMachineTag("0"))
 	unformatted := []interface{}{
-		ConvertActionResults(machineResult, query),
+		convertStructuredActionResults(machineResult, query),
 	}
 
 	jsonFormatted := &bytes.Buffer{}
@@ -702,6 +783,203 @@ func (s *ExecSuite) TestSingleResponse(c *gc.C) {
 	}
 }
 
+func (s *ExecSuite) setupParallelMockAPI() *mockExecAPI {
+	mock := s.setupMockAPI()
+	for _, id := range []string{"0", "1"} {
+		mock.setResponse(id, mockResponse{
+			stdout:     "machine " + id,
+			code:       "0",
+			machineTag: "machine-" + id,
+		})
+	}
+	for _, id := range []string{"mysql/0", "mysql/1", "mysql/2"} {
+		mock.setResponse(id, mockResponse{
+			stdout:  id,
+			code:    "0",
+			unitTag: names.NewUnitTag(id).String(),
+		})
+	}
+	mock.actionResponses = make(map[string]params.ActionResult)
+	for id, result := range mock.execResponses {
+		mock.actionResponses[mock.receiverIdMap[id]] = result
+	}
+	s.PatchValue(&getExecStatusClient, func(_ *execCommand) (ExecStatusClient, error) {
+		return &mockExecStatusAPI{status: &params.FullStatus{
+			Machines: map[string]params.MachineStatus{
+				"0": {},
+				"1": {},
+			},
+			Applications: map[string]params.ApplicationStatus{
+				"mysql": {Units: map[string]params.UnitStatus{
+					"mysql/2": {},
+					"mysql/0": {},
+					"mysql/1": {},
+				}},
+			},
+		}}, nil
+	})
+	return mock
+}
+
+func (s *ExecSuite) TestParallelBatches(c *gc.C) {
+	mock := s.setupParallelMockAPI()
+	_, err := cmdtesting.RunCommand(c, newExecCommand(minimalStore(model.IAAS), time.After),
+		"--format=json", "--parallel=2", "--machine=1", "--application=mysql", "hostname",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mock.runCalls, gc.HasLen, 2)
+	c.Check(mock.runCalls[0].Machines, jc.DeepEquals, []string{"1"})
+	c.Check(mock.runCalls[0].Units, jc.DeepEquals, []string{"mysql/0"})
+	c.Check(mock.runCalls[1].Machines, gc.HasLen, 0)
+	c.Check(mock.runCalls[1].Units, jc.DeepEquals, []string{"mysql/1", "mysql/2"})
+}
+
+func (s *ExecSuite) TestParallelAll(c *gc.C) {
+	mock := s.setupParallelMockAPI()
+	_, err := cmdtesting.RunCommand(c, newExecCommand(minimalStore(model.IAAS), time.After),
+		"--format=json", "--parallel=1", "--all", "hostname",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mock.runCalls, gc.HasLen, 2)
+	c.Check(mock.runCalls[0].Machines, jc.DeepEquals, []string{"0"})
+	c.Check(mock.runCalls[1].Machines, jc.DeepEquals, []string{"1"})
+}
+
+func (s *ExecSuite) TestParallelOnFailureAbort(c *gc.C) {
+	mock := s.setupParallelMockAPI()
+	failed := makeActionResult(mockResponse{
+		stdout:  "",
+		stderr:  "oops",
+		code:    "1",
+		unitTag: "unit-mysql-0",
+	}, names.NewActionTag(mock.receiverIdMap["mysql/0"]).String())
+	mock.execResponses["mysql/0"] = failed
+	mock.actionResponses[mock.receiverIdMap["mysql/0"]] = failed
+
+	query := makeActionQuery(mock.receiverIdMap["mysql/0"], "UnitId", names.NewUnitTag("mysql/0"))
+	buff := &bytes.Buffer{}
+	err := cmd.FormatJson(buff, []interface{}{convertStructuredActionResults(failed, query)})
+	c.Assert(err, jc.ErrorIsNil)
+
+	context, err := cmdtesting.RunCommand(c, newExecCommand(minimalStore(model.IAAS), time.After),
+		"--format=json", "--parallel=1", "--on-failure=abort", "--application=mysql", "hostname",
+	)
+	c.Assert(err, gc.ErrorMatches, "commands failed, not run on: mysql/1, mysql/2")
+	c.Assert(mock.runCalls, gc.HasLen, 1)
+	c.Check(cmdtesting.Stdout(context), gc.Equals, buff.String())
+}
+
+func (s *ExecSuite) TestParallelOnFailureContinue(c *gc.C) {
+	mock := s.setupParallelMockAPI()
+	failed := makeActionResult(mockResponse{
+		code:    "1",
+		unitTag: "unit-mysql-0",
+	}, names.NewActionTag(mock.receiverIdMap["mysql/0"]).String())
+	mock.execResponses["mysql/0"] = failed
+	mock.actionResponses[mock.receiverIdMap["mysql/0"]] = failed
+
+	_, err := cmdtesting.RunCommand(c, newExecCommand(minimalStore(model.IAAS), time.After),
+		"--format=json", "--parallel=1", "--application=mysql", "hostname",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mock.runCalls, gc.HasLen, 3)
+}
+
+func (s *ExecSuite) TestParallelOnFailureAbortFailedStatus(c *gc.C) {
+	mock := s.setupParallelMockAPI()
+	failed := makeActionResult(mockResponse{
+		code:    "0",
+		status:  params.ActionFailed,
+		unitTag: "unit-mysql-0",
+	}, names.NewActionTag(mock.receiverIdMap["mysql/0"]).String())
+	mock.execResponses["mysql/0"] = failed
+	mock.actionResponses[mock.receiverIdMap["mysql/0"]] = failed
+
+	_, err := cmdtesting.RunCommand(c, newExecCommand(minimalStore(model.IAAS), time.After),
+		"--format=json", "--parallel=1", "--on-failure=abort", "--application=mysql", "hostname",
+	)
+	c.Assert(err, gc.ErrorMatches, "commands failed, not run on: mysql/1, mysql/2")
+	c.Assert(mock.runCalls, gc.HasLen, 1)
+}
+
+func (s *ExecSuite) TestParallelOnFailureAbortEnqueueError(c *gc.C) {
+	mock := s.setupParallelMockAPI()
+	mock.execResponses["mysql/0"] = params.ActionResult{
+		Error: &params.Error{Message: "boom"},
+	}
+
+	context, err := cmdtesting.RunCommand(c, newExecCommand(minimalStore(model.IAAS), time.After),
+		"--format=json", "--parallel=2", "--on-failure=abort", "--application=mysql", "hostname",
+	)
+	c.Assert(err, gc.ErrorMatches, "commands failed, not run on: mysql/2")
+	c.Assert(mock.runCalls, gc.HasLen, 1)
+	c.Check(cmdtesting.Stderr(context), gc.Equals, "couldn't queue one action: boom\n")
+}
+
+func (s *ExecSuite) TestParallelEnqueueErrorWritesEarlierResults(c *gc.C) {
+	mock := s.setupParallelMockAPI()
+	mock.execResponses["mysql/1"] = params.ActionResult{
+		Error: &params.Error{Message: "boom"},
+	}
+
+	query := makeActionQuery(mock.receiverIdMap["mysql/0"], "UnitId", names.NewUnitTag("mysql/0"))
+	buff := &bytes.Buffer{}
+	err := cmd.FormatJson(buff, []interface{}{
+		convertStructuredActionResults(mock.actionResponses[mock.receiverIdMap["mysql/0"]], query),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	context, err := cmdtesting.RunCommand(c, newExecCommand(minimalStore(model.IAAS), time.After),
+		"--format=json", "--parallel=1", "--application=mysql", "hostname",
+	)
+	c.Assert(err, gc.ErrorMatches, "no actions were successfully enqueued, aborting")
+	c.Assert(mock.runCalls, gc.HasLen, 2)
+	c.Check(cmdtesting.Stdout(context), gc.Equals, buff.String())
+}
+
+func (s *ExecSuite) TestStructuredOutput(c *gc.C) {
+	mock := s.setupParallelMockAPI()
+	result := mock.actionResponses[mock.receiverIdMap["0"]]
+	result.Status = params.ActionCompleted
+	result.Enqueued = time.Date(2019, 1, 1, 10, 0, 0, 0, time.UTC)
+	result.Started = time.Date(2019, 1, 1, 10, 0, 1, 0, time.UTC)
+	result.Completed = time.Date(2019, 1, 1, 10, 0, 4, 0, time.UTC)
+	mock.actionResponses[mock.receiverIdMap["0"]] = result
+
+	// The status, timing and exit code, even when it is zero,
+	// are always included in yaml and json output.
+	expected := []interface{}{map[string]interface{}{
+		"MachineId":  "0",
+		"Stdout":     "machine 0",
+		"Status":     "completed",
+		"ReturnCode": 0,
+		"Timing": map[string]string{
+			"Enqueued":  "2019-01-01T10:00:00Z",
+			"Started":   "2019-01-01T10:00:01Z",
+			"Completed": "2019-01-01T10:00:04Z",
+			"Duration":  "3s",
+		},
+	}}
+	for _, test := range []struct {
+		format    string
+		formatter cmd.Formatter
+	}{
+		{"json", cmd.FormatJson},
+		{"yaml", cmd.FormatYaml},
+	} {
+		c.Logf("format %s", test.format)
+		buff := &bytes.Buffer{}
+		err := test.formatter(buff, expected)
+		c.Assert(err, jc.ErrorIsNil)
+
+		context, err := cmdtesting.RunCommand(c, newExecCommand(minimalStore(model.IAAS), time.After),
+			"--format="+test.format, "--machine=0", "hostname",
+		)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(cmdtesting.Stdout(context), gc.Equals, buff.String())
+	}
+}
+
 func (s *ExecSuite) setupMockAPI() *mockExecAPI {
 	mock := &mockExecAPI{
 		bestAPIVersion: 4,
@@ -727,6 +1005,19 @@ type mockExecAPI struct {
 	bestAPIVersion int
 	// recevied values
 	execParams *params.RunParams
+	runCalls   []params.RunParams
+}
+
+type mockExecStatusAPI struct {
+	status *params.FullStatus
+}
+
+func (*mockExecStatusAPI) Close() error {
+	return nil
+}
+
+func (m *mockExecStatusAPI) Status(patterns []string) (*params.FullStatus, error) {
+	return m.status, nil
 }
 
 type mockResponse struct {
@@ -836,6 +1127,7 @@ func (m *mockExecAPI) Run(runParams params.RunParams) ([]params.ActionResult, er
 	var result []params.ActionResult
 
 	m.execParams = &runParams
+	m.runCalls = append(m.runCalls, runParams)
 
 	if m.block {
 		return result, common.OperationBlockedError("the operation has been blocked")
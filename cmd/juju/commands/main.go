@@ -281,7 +281,9 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 
 	// Firewall rule commands.
 	r.Register(firewall.NewSetFirewallRuleCommand())
+	r.Register(firewall.NewSetEgressRuleCommand())
 	r.Register(firewall.NewListFirewallRulesCommand())
+	r.Register(firewall.NewRemoveFirewallRuleCommand())
 
 	// Destruction commands.
 	r.Register(application.NewRemoveRelationCommand())
@@ -355,6 +357,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(machine.NewListMachinesCommand())
 	r.Register(machine.NewShowMachineCommand())
 	r.Register(machine.NewUpgradeSeriesCommand())
+	r.Register(machine.NewSetProtectionCommand())
+	r.Register(machine.NewResizeCommand())
 
 	// Manage model
 	r.Register(model.NewConfigCommand())
@@ -407,7 +411,9 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(application.NewApplicationGetConstraintsCommand())
 	r.Register(application.NewApplicationSetConstraintsCommand())
 	r.Register(application.NewBundleDiffCommand())
+	r.Register(application.NewEstimateCostCommand())
 	r.Register(application.NewShowApplicationCommand())
+	r.Register(application.NewShowUnitCommand())
 
 	// Operation protection commands
 	r.Register(block.NewDisableCommand())
@@ -425,12 +431,19 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(storage.NewRemoveStorageCommandWithAPI())
 	r.Register(storage.NewDetachStorageCommandWithAPI())
 	r.Register(storage.NewAttachStorageCommandWithAPI())
+	r.Register(storage.NewResizeStorageCommand())
+	r.Register(storage.NewCreateStorageSnapshotCommand())
+	r.Register(storage.NewListStorageSnapshotsCommand())
+	r.Register(storage.NewMigrateStorageCommand())
+	r.Register(storage.NewListStorageMigrationsCommand())
 	r.Register(storage.NewImportFilesystemCommand(storage.NewStorageImporter, nil))
 
 	// Manage spaces
 	r.Register(space.NewAddCommand())
 	r.Register(space.NewListCommand())
 	r.Register(space.NewReloadCommand())
+	r.Register(space.NewApplyTopologyCommand())
+	r.Register(space.NewCheckConnectivityCommand())
 	if featureflag.Enabled(feature.PostNetCLIMVP) {
 		r.Register(space.NewRemoveCommand())
 		r.Register(space.NewUpdateCommand())
@@ -439,6 +439,7 @@ var commandNames = []string{
 	"add-user",
 	"agree",
 	"agreements",
+	"apply-network-topology",
 	"attach",
 	"attach-resource",
 	"attach-storage",
@@ -452,6 +453,7 @@ var commandNames = []string{
 	"change-user-password",
 	"charm",
 	"charm-resources",
+	"check-connectivity",
 	"clouds",
 	"collect-metrics",
 	"config",
@@ -460,6 +462,7 @@ var commandNames = []string{
 	"controllers",
 	"create-backup",
 	"create-storage-pool",
+	"create-storage-snapshot",
 	"create-wallet",
 	"credentials",
 	"debug-hook",
@@ -480,6 +483,7 @@ var commandNames = []string{
 	"enable-destroy-controller",
 	"enable-ha",
 	"enable-user",
+	"estimate-cost",
 	"exec",
 	"export-bundle",
 	"expose",
@@ -517,7 +521,9 @@ var commandNames = []string{
 	"list-spaces",
 	"list-ssh-keys",
 	"list-storage",
+	"list-storage-migrations",
 	"list-storage-pools",
+	"list-storage-snapshots",
 	"list-subnets",
 	"list-users",
 	"list-wallets",
@@ -526,6 +532,7 @@ var commandNames = []string{
 	"machines",
 	"metrics",
 	"migrate",
+	"migrate-storage",
 	"model-config",
 	"model-default",
 	"model-defaults",
@@ -544,6 +551,7 @@ var commandNames = []string{
 	"remove-cloud",
 	"remove-consumed-application",
 	"remove-credential",
+	"remove-firewall-rule",
 	"remove-k8s",
 	"remove-machine",
 	"remove-offer",
@@ -554,6 +562,8 @@ var commandNames = []string{
 	"remove-storage-pool",
 	"remove-unit",
 	"remove-user",
+	"resize-machine",
+	"resize-storage",
 	"resolved",
 	"resolve",
 	"resources",
@@ -569,7 +579,9 @@ var commandNames = []string{
 	"set-constraints",
 	"set-default-credential",
 	"set-default-region",
+	"set-egress-rule",
 	"set-firewall-rule",
+	"set-machine-protection",
 	"set-meter-status",
 	"set-model-constraints",
 	"set-plan",
@@ -588,6 +600,7 @@ var commandNames = []string{
 	"show-status",
 	"show-status-log",
 	"show-storage",
+	"show-unit",
 	"show-user",
 	"show-wallet",
 	"sla",
@@ -596,7 +609,9 @@ var commandNames = []string{
 	"ssh-keys",
 	"status",
 	"storage",
+	"storage-migrations",
 	"storage-pools",
+	"storage-snapshots",
 	"subnets",
 	"suspend-relation",
 	"switch",
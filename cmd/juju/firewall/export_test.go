@@ -33,3 +33,27 @@ func NewSetRulesCommandForTest(
 	aCmd.SetClientStore(jujuclienttesting.MinimalStore())
 	return modelcmd.Wrap(aCmd)
 }
+
+func NewRemoveRuleCommandForTest(
+	api RemoveFirewallRuleAPI,
+) cmd.Command {
+	aCmd := &removeFirewallRuleCommand{
+		newAPIFunc: func() (RemoveFirewallRuleAPI, error) {
+			return api, nil
+		},
+	}
+	aCmd.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(aCmd)
+}
+
+func NewSetEgressRuleCommandForTest(
+	api SetEgressRuleAPI,
+) cmd.Command {
+	aCmd := &setEgressRuleCommand{
+		newAPIFunc: func() (SetEgressRuleAPI, error) {
+			return api, nil
+		},
+	}
+	aCmd.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(aCmd)
+}
@@ -14,16 +14,37 @@ import (
 )
 
 type firewallRule struct {
-	KnownService   string   `yaml:"known-service" json:"known-service"`
-	WhitelistCIDRS []string `yaml:"whitelist-subnets,omitempty" json:"whitelist-subnets,omitempty"`
+	Direction        string   `yaml:"direction,omitempty" json:"direction,omitempty"`
+	KnownService     string   `yaml:"known-service,omitempty" json:"known-service,omitempty"`
+	Name             string   `yaml:"name,omitempty" json:"name,omitempty"`
+	Ports            string   `yaml:"ports,omitempty" json:"ports,omitempty"`
+	Application      string   `yaml:"application,omitempty" json:"application,omitempty"`
+	WhitelistCIDRS   []string `yaml:"whitelist-subnets,omitempty" json:"whitelist-subnets,omitempty"`
+	DestinationCIDRS []string `yaml:"destination-subnets,omitempty" json:"destination-subnets,omitempty"`
 }
 
+// egressDirection is the direction of egress rules; the
+// direction of the other rules is omitted.
+const egressDirection = "egress"
+
 type firewallRules []firewallRule
 
 func (o firewallRules) Len() int      { return len(o) }
 func (o firewallRules) Swap(i, j int) { o[i], o[j] = o[j], o[i] }
 func (o firewallRules) Less(i, j int) bool {
-	return o[i].KnownService < o[j].KnownService
+	if o[i].Direction != o[j].Direction {
+		return o[i].Direction < o[j].Direction
+	}
+	if o[i].KnownService != o[j].KnownService {
+		return o[i].KnownService < o[j].KnownService
+	}
+	if o[i].Name != o[j].Name {
+		return o[i].Name < o[j].Name
+	}
+	if o[i].Application != o[j].Application {
+		return o[i].Application < o[j].Application
+	}
+	return o[i].Ports < o[j].Ports
 }
 
 func formatListTabular(writer io.Writer, value interface{}) error {
@@ -36,15 +57,40 @@ func formatListTabular(writer io.Writer, value interface{}) error {
 }
 
 // formatFirewallRulesTabular returns a tabular summary of firewall rules.
+// Model ingress rules are listed after the rules for well known services,
+// followed by the egress rules.
 func formatFirewallRulesTabular(writer io.Writer, rules firewallRules) {
 	tw := output.TabWriter(writer)
 	w := output.Wrapper{tw}
 
 	sort.Sort(rules)
 
+	var ingressRules, egressRules firewallRules
 	w.Println("Service", "Whitelist subnets")
 	for _, rule := range rules {
+		if rule.Direction == egressDirection {
+			egressRules = append(egressRules, rule)
+			continue
+		}
+		if rule.Name != "" {
+			ingressRules = append(ingressRules, rule)
+			continue
+		}
 		w.Println(rule.KnownService, strings.Join(rule.WhitelistCIDRS, ","))
 	}
+	if len(ingressRules) > 0 {
+		w.Println()
+		w.Println("Ingress rule", "Ports", "Application", "Whitelist subnets")
+		for _, rule := range ingressRules {
+			w.Println(rule.Name, rule.Ports, rule.Application, strings.Join(rule.WhitelistCIDRS, ","))
+		}
+	}
+	if len(egressRules) > 0 {
+		w.Println()
+		w.Println("Egress rule", "Ports", "Destination subnets")
+		for _, rule := range egressRules {
+			w.Println(rule.Application, rule.Ports, strings.Join(rule.DestinationCIDRS, ","))
+		}
+	}
 	tw.Flush()
 }
@@ -19,14 +19,16 @@ Prints the firewall rules.`[1:]
 
 var listRulesHelpDetails = `
 Lists the firewall rules which control ingress to well known services
-within a Juju model.
+within a Juju model, the model's ingress rules, and the egress rules of
+applications with restricted egress.
 
 Examples:
     juju list-firewall-rules
     juju firewall-rules
 
 See also: 
-    set-firewall-rule`
+    set-firewall-rule
+    set-egress-rule`
 
 // NewListFirewallRulesCommand returns a command to list firewall rules.
 func NewListFirewallRulesCommand() cmd.Command {
@@ -78,6 +80,7 @@ func (c *listFirewallRulesCommand) Init(args []string) (err error) {
 type ListFirewallRulesAPI interface {
 	Close() error
 	ListFirewallRules() ([]params.FirewallRule, error)
+	ListEgressRules() ([]params.ApplicationEgressRules, error)
 }
 
 // Run implements cmd.Command.
@@ -96,8 +99,43 @@ func (c *listFirewallRulesCommand) Run(ctx *cmd.Context) error {
 	for i, r := range rulesResult {
 		rules[i] = firewallRule{
 			KnownService:   string(r.KnownService),
+			Name:           r.Name,
+			Application:    r.Application,
 			WhitelistCIDRS: r.WhitelistCIDRS,
 		}
+		if r.PortRange != nil {
+			rules[i].Ports = r.PortRange.NetworkPortRange().String()
+		}
+	}
+
+	egressResult, err := client.ListEgressRules()
+	if err != nil && !errors.IsNotSupported(err) {
+		return err
+	}
+	for _, appRules := range egressResult {
+		rules = append(rules, egressFirewallRules(appRules)...)
 	}
 	return c.out.Write(ctx, rules)
 }
+
+// egressFirewallRules returns the egress rules of the application,
+// with one rule for each port range listing all its destinations.
+func egressFirewallRules(appRules params.ApplicationEgressRules) []firewallRule {
+	var rules []firewallRule
+	index := make(map[string]int)
+	for _, r := range appRules.Rules {
+		ports := r.PortRange.NetworkPortRange().String()
+		i, ok := index[ports]
+		if !ok {
+			i = len(rules)
+			index[ports] = i
+			rules = append(rules, firewallRule{
+				Direction:   egressDirection,
+				Ports:       ports,
+				Application: appRules.Application,
+			})
+		}
+		rules[i].DestinationCIDRS = append(rules[i].DestinationCIDRS, r.DestinationCIDR)
+	}
+	return rules
+}
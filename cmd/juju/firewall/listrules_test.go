@@ -76,6 +76,123 @@ func (s *ListSuite) TestListYAML(c *gc.C) {
 	)
 }
 
+func (s *ListSuite) TestListTabularIngressRules(c *gc.C) {
+	s.mockAPI.rules = append(s.mockAPI.rules, params.FirewallRule{
+		Name:           "web",
+		PortRange:      &params.PortRange{FromPort: 8080, ToPort: 8081, Protocol: "tcp"},
+		WhitelistCIDRS: []string{"10.0.0.0/8"},
+	}, params.FirewallRule{
+		Name:        "node-exporter",
+		PortRange:   &params.PortRange{FromPort: 9100, ToPort: 9100, Protocol: "tcp"},
+		Application: "mysql",
+	})
+	s.assertValidList(
+		c,
+		[]string{"--format", "tabular"},
+		`
+Service          Whitelist subnets
+juju-controller  10.2.0.0/16
+ssh              192.168.1.0/16,10.0.0.0/8
+
+Ingress rule   Ports          Application  Whitelist subnets
+node-exporter  9100/tcp       mysql        
+web            8080-8081/tcp               10.0.0.0/8
+
+`[1:],
+		"",
+	)
+}
+
+func (s *ListSuite) TestListYAMLIngressRules(c *gc.C) {
+	s.mockAPI.rules = []params.FirewallRule{{
+		Name:           "web",
+		PortRange:      &params.PortRange{FromPort: 8080, ToPort: 8081, Protocol: "tcp"},
+		WhitelistCIDRS: []string{"10.0.0.0/8"},
+		Application:    "mysql",
+	}}
+	s.assertValidList(
+		c,
+		[]string{"--format", "yaml"},
+		`
+- name: web
+  ports: 8080-8081/tcp
+  application: mysql
+  whitelist-subnets:
+  - 10.0.0.0/8
+`[1:],
+		"",
+	)
+}
+
+func (s *ListSuite) TestListTabularEgressRules(c *gc.C) {
+	s.mockAPI.egressRules = []params.ApplicationEgressRules{{
+		Application: "mysql",
+		Rules: []params.EgressRule{{
+			DestinationCIDR: "10.0.0.0/8",
+			PortRange:       params.PortRange{FromPort: 5432, ToPort: 5432, Protocol: "tcp"},
+		}, {
+			DestinationCIDR: "192.168.0.0/16",
+			PortRange:       params.PortRange{FromPort: 5432, ToPort: 5432, Protocol: "tcp"},
+		}, {
+			DestinationCIDR: "10.1.0.0/16",
+			PortRange:       params.PortRange{FromPort: 53, ToPort: 53, Protocol: "udp"},
+		}},
+	}}
+	s.assertValidList(
+		c,
+		[]string{"--format", "tabular"},
+		`
+Service          Whitelist subnets
+juju-controller  10.2.0.0/16
+ssh              192.168.1.0/16,10.0.0.0/8
+
+Egress rule  Ports     Destination subnets
+mysql        5432/tcp  10.0.0.0/8,192.168.0.0/16
+mysql        53/udp    10.1.0.0/16
+
+`[1:],
+		"",
+	)
+}
+
+func (s *ListSuite) TestListYAMLEgressRules(c *gc.C) {
+	s.mockAPI.rules = nil
+	s.mockAPI.egressRules = []params.ApplicationEgressRules{{
+		Application: "mysql",
+		Rules: []params.EgressRule{{
+			DestinationCIDR: "10.0.0.0/8",
+			PortRange:       params.PortRange{FromPort: 5432, ToPort: 5432, Protocol: "tcp"},
+		}},
+	}}
+	s.assertValidList(
+		c,
+		[]string{"--format", "yaml"},
+		`
+- direction: egress
+  ports: 5432/tcp
+  application: mysql
+  destination-subnets:
+  - 10.0.0.0/8
+`[1:],
+		"",
+	)
+}
+
+func (s *ListSuite) TestListEgressRulesNotSupported(c *gc.C) {
+	s.mockAPI.egressErr = errors.NotSupportedf("egress rules")
+	s.assertValidList(
+		c,
+		[]string{"--format", "tabular"},
+		`
+Service          Whitelist subnets
+juju-controller  10.2.0.0/16
+ssh              192.168.1.0/16,10.0.0.0/8
+
+`[1:],
+		"",
+	)
+}
+
 func (s *ListSuite) runList(c *gc.C, args []string) (*cmd.Context, error) {
 	return cmdtesting.RunCommand(c, firewall.NewListRulesCommandForTest(s.mockAPI), args...)
 }
@@ -92,8 +209,10 @@ func (s *ListSuite) assertValidList(c *gc.C, args []string, expectedValid, expec
 }
 
 type mockListAPI struct {
-	rules []params.FirewallRule
-	err   error
+	rules       []params.FirewallRule
+	egressRules []params.ApplicationEgressRules
+	egressErr   error
+	err         error
 }
 
 func (s *mockListAPI) Close() error {
@@ -106,3 +225,10 @@ func (s *mockListAPI) ListFirewallRules() ([]params.FirewallRule, error) {
 	}
 	return s.rules, nil
 }
+
+func (s *mockListAPI) ListEgressRules() ([]params.ApplicationEgressRules, error) {
+	if s.egressErr != nil {
+		return nil, s.egressErr
+	}
+	return s.egressRules, nil
+}
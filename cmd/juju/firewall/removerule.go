@@ -0,0 +1,91 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/firewallrules"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+var removeRuleHelpSummary = `
+Removes a model ingress rule.`[1:]
+
+var removeRuleHelpDetails = `
+Removes a model ingress rule created with set-firewall-rule. Juju
+closes the rule's port range unless it is opened for another reason,
+such as an exposed application or another rule.
+
+Rules for well known services cannot be removed; use set-firewall-rule
+to change their whitelisted subnets.
+
+Examples:
+    juju remove-firewall-rule node-exporter
+
+See also: 
+    list-firewall-rules
+    set-firewall-rule`
+
+// NewRemoveFirewallRuleCommand returns a command to remove model
+// ingress rules.
+func NewRemoveFirewallRuleCommand() cmd.Command {
+	cmd := &removeFirewallRuleCommand{}
+	cmd.newAPIFunc = func() (RemoveFirewallRuleAPI, error) {
+		root, err := cmd.NewAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return firewallrules.NewClient(root), nil
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+type removeFirewallRuleCommand struct {
+	modelcmd.ModelCommandBase
+	modelcmd.IAASOnlyCommand
+	name string
+
+	newAPIFunc func() (RemoveFirewallRuleAPI, error)
+}
+
+// Info implements cmd.Command.
+func (c *removeFirewallRuleCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "remove-firewall-rule",
+		Args:    "<rule-name>",
+		Purpose: removeRuleHelpSummary,
+		Doc:     removeRuleHelpDetails,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *removeFirewallRuleCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no rule name specified")
+	}
+	c.name = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// RemoveFirewallRuleAPI defines the API methods that the remove
+// firewall rule command uses.
+type RemoveFirewallRuleAPI interface {
+	Close() error
+	RemoveFirewallRule(name string) error
+}
+
+// Run implements cmd.Command.
+func (c *removeFirewallRuleCommand) Run(_ *cmd.Context) error {
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	err = client.RemoveFirewallRule(c.name)
+	return block.ProcessBlockedError(err, block.BlockRemove)
+}
@@ -0,0 +1,70 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/firewall"
+	"github.com/juju/juju/testing"
+)
+
+type RemoveRuleSuite struct {
+	testing.BaseSuite
+
+	mockAPI *mockRemoveRuleAPI
+}
+
+var _ = gc.Suite(&RemoveRuleSuite{})
+
+func (s *RemoveRuleSuite) SetUpTest(c *gc.C) {
+	s.mockAPI = &mockRemoveRuleAPI{}
+}
+
+func (s *RemoveRuleSuite) TestInitMissingName(c *gc.C) {
+	_, err := s.runRemoveRule(c)
+	c.Assert(err, gc.ErrorMatches, "no rule name specified")
+}
+
+func (s *RemoveRuleSuite) TestInitTooManyArgs(c *gc.C) {
+	_, err := s.runRemoveRule(c, "web", "other")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["other"\]`)
+}
+
+func (s *RemoveRuleSuite) TestRemoveRule(c *gc.C) {
+	_, err := s.runRemoveRule(c, "web")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.name, gc.Equals, "web")
+}
+
+func (s *RemoveRuleSuite) TestRemoveError(c *gc.C) {
+	s.mockAPI.err = errors.New("fail")
+	_, err := s.runRemoveRule(c, "web")
+	c.Assert(err, gc.ErrorMatches, ".*fail.*")
+}
+
+func (s *RemoveRuleSuite) runRemoveRule(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, firewall.NewRemoveRuleCommandForTest(s.mockAPI), args...)
+}
+
+type mockRemoveRuleAPI struct {
+	name string
+	err  error
+}
+
+func (s *mockRemoveRuleAPI) Close() error {
+	return nil
+}
+
+func (s *mockRemoveRuleAPI) RemoveFirewallRule(name string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.name = name
+	return nil
+}
@@ -0,0 +1,154 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall
+
+import (
+	"net"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/api/firewallrules"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/network"
+)
+
+var setEgressRuleHelpSummary = `
+Restricts egress from the machines hosting an application.`[1:]
+
+var setEgressRuleHelpDetails = `
+Egress rules control which destinations the machines hosting an
+application's units may connect to. Each rule allows egress to a
+port range on a subnet, given as <cidr>:<port>[-<port>][/<protocol>];
+the protocol defaults to tcp. Once an application has egress rules,
+all other egress from its machines is denied, except to the Juju
+controllers. A machine hosting units of several applications with
+egress rules may connect to the destinations allowed by any of them.
+
+Setting the rules replaces any rules the application already has.
+Use --unrestricted to remove them and allow all egress again.
+
+Egress rules are enforced with the cloud's security groups where
+the cloud supports egress rules and the model uses the "instance"
+firewall mode, and otherwise with iptables rules managed by the
+machine agent.
+
+Examples:
+    juju set-egress-rule postgresql-client --allow 10.0.0.0/8:5432
+    juju set-egress-rule web --allow 10.0.0.0/8:80-443,192.168.0.10/32:53/udp
+    juju set-egress-rule web --unrestricted
+
+See also: 
+    list-firewall-rules`
+
+// NewSetEgressRuleCommand returns a command to set the egress rules
+// of an application.
+func NewSetEgressRuleCommand() cmd.Command {
+	cmd := &setEgressRuleCommand{}
+	cmd.newAPIFunc = func() (SetEgressRuleAPI, error) {
+		root, err := cmd.NewAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return firewallrules.NewClient(root), nil
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+type setEgressRuleCommand struct {
+	modelcmd.ModelCommandBase
+	modelcmd.IAASOnlyCommand
+	application  string
+	allowValue   string
+	unrestricted bool
+
+	rules      []params.EgressRule
+	newAPIFunc func() (SetEgressRuleAPI, error)
+}
+
+// Info implements cmd.Command.
+func (c *setEgressRuleCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "set-egress-rule",
+		Args:    "<application> (--allow <cidr>:<port>[-<port>][/<protocol>][,...] | --unrestricted)",
+		Purpose: setEgressRuleHelpSummary,
+		Doc:     setEgressRuleHelpDetails,
+	})
+}
+
+// SetFlags implements cmd.Command.
+func (c *setEgressRuleCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.allowValue, "allow", "", "list of destinations to allow egress to")
+	f.BoolVar(&c.unrestricted, "unrestricted", false, "remove the egress rules of the application")
+}
+
+// Init implements cmd.Command.
+func (c *setEgressRuleCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no application specified")
+	}
+	c.application = args[0]
+	if !names.IsValidApplication(c.application) {
+		return errors.Errorf("invalid application name %q", c.application)
+	}
+	switch {
+	case c.unrestricted && c.allowValue != "":
+		return errors.New("--allow and --unrestricted cannot be used together")
+	case !c.unrestricted && c.allowValue == "":
+		return errors.New("no egress destinations specified")
+	}
+	if err := c.parseRules(c.allowValue); err != nil {
+		return errors.Annotate(err, "invalid egress destination")
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *setEgressRuleCommand) parseRules(value string) error {
+	if value == "" {
+		return nil
+	}
+	for _, rawRule := range strings.Split(value, ",") {
+		rawRule = strings.TrimSpace(rawRule)
+		// Split on the last colon, so IPv6 subnets can be given.
+		sep := strings.LastIndex(rawRule, ":")
+		if sep < 0 {
+			return errors.Errorf("expected <cidr>:<port>, got %q", rawRule)
+		}
+		cidr, ports := rawRule[:sep], rawRule[sep+1:]
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return err
+		}
+		portRange, err := network.ParsePortRange(ports)
+		if err != nil {
+			return err
+		}
+		c.rules = append(c.rules, params.EgressRule{
+			DestinationCIDR: cidr,
+			PortRange:       params.FromNetworkPortRange(portRange),
+		})
+	}
+	return nil
+}
+
+// SetEgressRuleAPI defines the API methods that the set egress rule command uses.
+type SetEgressRuleAPI interface {
+	Close() error
+	SetEgressRules(application string, rules []params.EgressRule) error
+}
+
+func (c *setEgressRuleCommand) Run(_ *cmd.Context) error {
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	err = client.SetEgressRules(c.application, c.rules)
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/firewall"
+	"github.com/juju/juju/testing"
+)
+
+type SetEgressRuleSuite struct {
+	testing.BaseSuite
+
+	mockAPI *mockSetEgressRuleAPI
+}
+
+var _ = gc.Suite(&SetEgressRuleSuite{})
+
+func (s *SetEgressRuleSuite) SetUpTest(c *gc.C) {
+	s.mockAPI = &mockSetEgressRuleAPI{}
+}
+
+func (s *SetEgressRuleSuite) TestInitMissingApplication(c *gc.C) {
+	_, err := s.runSetEgressRule(c, "--allow", "10.0.0.0/8:5432")
+	c.Assert(err, gc.ErrorMatches, "no application specified")
+}
+
+func (s *SetEgressRuleSuite) TestInitInvalidApplication(c *gc.C) {
+	_, err := s.runSetEgressRule(c, "--allow", "10.0.0.0/8:5432", "Bad_App")
+	c.Assert(err, gc.ErrorMatches, `invalid application name "Bad_App"`)
+}
+
+func (s *SetEgressRuleSuite) TestInitMissingAllow(c *gc.C) {
+	_, err := s.runSetEgressRule(c, "mysql")
+	c.Assert(err, gc.ErrorMatches, "no egress destinations specified")
+}
+
+func (s *SetEgressRuleSuite) TestInitAllowAndUnrestricted(c *gc.C) {
+	_, err := s.runSetEgressRule(c, "--allow", "10.0.0.0/8:5432", "--unrestricted", "mysql")
+	c.Assert(err, gc.ErrorMatches, "--allow and --unrestricted cannot be used together")
+}
+
+func (s *SetEgressRuleSuite) TestInitInvalidDestination(c *gc.C) {
+	_, err := s.runSetEgressRule(c, "--allow", "10.0.0.0/8", "mysql")
+	c.Assert(err, gc.ErrorMatches, `invalid egress destination: expected <cidr>:<port>, got "10.0.0.0/8"`)
+
+	_, err = s.runSetEgressRule(c, "--allow", "10.0.0:5432", "mysql")
+	c.Assert(err, gc.ErrorMatches, `invalid egress destination: invalid CIDR address: 10.0.0`)
+
+	_, err = s.runSetEgressRule(c, "--allow", "10.0.0.0/8:foo", "mysql")
+	c.Assert(err, gc.ErrorMatches, `invalid egress destination: .*`)
+}
+
+func (s *SetEgressRuleSuite) TestInitExtraArgs(c *gc.C) {
+	_, err := s.runSetEgressRule(c, "--allow", "10.0.0.0/8:5432", "mysql", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *SetEgressRuleSuite) TestSetEgressRules(c *gc.C) {
+	_, err := s.runSetEgressRule(c, "--allow", "10.0.0.0/8:5432, 192.168.0.0/16:53/udp,fd00::/8:80-443", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.application, gc.Equals, "mysql")
+	c.Assert(s.mockAPI.rules, jc.DeepEquals, []params.EgressRule{{
+		DestinationCIDR: "10.0.0.0/8",
+		PortRange:       params.PortRange{FromPort: 5432, ToPort: 5432, Protocol: "tcp"},
+	}, {
+		DestinationCIDR: "192.168.0.0/16",
+		PortRange:       params.PortRange{FromPort: 53, ToPort: 53, Protocol: "udp"},
+	}, {
+		DestinationCIDR: "fd00::/8",
+		PortRange:       params.PortRange{FromPort: 80, ToPort: 443, Protocol: "tcp"},
+	}})
+}
+
+func (s *SetEgressRuleSuite) TestSetUnrestricted(c *gc.C) {
+	_, err := s.runSetEgressRule(c, "--unrestricted", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.application, gc.Equals, "mysql")
+	c.Assert(s.mockAPI.rules, gc.HasLen, 0)
+}
+
+func (s *SetEgressRuleSuite) TestSetError(c *gc.C) {
+	s.mockAPI.err = errors.New("fail")
+	_, err := s.runSetEgressRule(c, "--allow", "10.0.0.0/8:5432", "mysql")
+	c.Assert(err, gc.ErrorMatches, ".*fail.*")
+}
+
+func (s *SetEgressRuleSuite) runSetEgressRule(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, firewall.NewSetEgressRuleCommandForTest(s.mockAPI), args...)
+}
+
+type mockSetEgressRuleAPI struct {
+	application string
+	rules       []params.EgressRule
+	err         error
+}
+
+func (s *mockSetEgressRuleAPI) Close() error {
+	return nil
+}
+
+func (s *mockSetEgressRuleAPI) SetEgressRules(application string, rules []params.EgressRule) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.application = application
+	s.rules = rules
+	return nil
+}
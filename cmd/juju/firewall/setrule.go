@@ -11,12 +11,14 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/api/firewallrules"
 	"github.com/juju/juju/apiserver/params"
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/network"
 )
 
 var setRuleHelpSummary = `
@@ -29,13 +31,24 @@ and a whitelist of allowed ingress subnets.
 The currently supported services are:
 %v
 
+A rule with any other name, given with --port, is a model ingress
+rule. It allows ingress to the port range from the whitelisted
+subnets on all of the model's machines; whitelist 0.0.0.0/0 to
+allow ingress from anywhere. With --application, ingress is only
+allowed on the machines hosting units of the application. Model
+ingress rules are enforced by Juju alongside the ports opened by
+charms, so they are not lost when Juju updates the cloud's firewall.
+
 Examples:
     juju set-firewall-rule ssh --whitelist 192.168.1.0/16
     juju set-firewall-rule juju-controller --whitelist 192.168.1.0/16
     juju set-firewall-rule juju-application-offer --whitelist 192.168.1.0/16
+    juju set-firewall-rule node-exporter --port 9100 --whitelist 10.0.0.0/8
+    juju set-firewall-rule mysql-admin --port 33060-33062/tcp --whitelist 0.0.0.0/0 --application mysql
 
 See also: 
-    list-firewall-rules`
+    list-firewall-rules
+    remove-firewall-rule`
 
 // NewSetFirewallRuleCommand returns a command to set firewall rules.
 func NewSetFirewallRuleCommand() cmd.Command {
@@ -56,8 +69,11 @@ type setFirewallRuleCommand struct {
 	modelcmd.IAASOnlyCommand
 	service        string
 	whitelistValue string
+	portValue      string
+	application    string
 
 	whiteList  []string
+	portRange  *network.PortRange
 	newAPIFunc func() (SetFirewallRuleAPI, error)
 }
 
@@ -70,7 +86,7 @@ func (c *setFirewallRuleCommand) Info() *cmd.Info {
 	}
 	return jujucmd.Info(&cmd.Info{
 		Name:    "set-firewall-rule",
-		Args:    "<service-name>, --whitelist <cidr>[,<cidr>...]",
+		Args:    "<service-name>|<rule-name> [--port <port>[-<port>][/<protocol>]] --whitelist <cidr>[,<cidr>...]",
 		Purpose: setRuleHelpSummary,
 		Doc:     fmt.Sprintf(setRuleHelpDetails, strings.Join(supportedRules, "\n")),
 	})
@@ -79,12 +95,26 @@ func (c *setFirewallRuleCommand) Info() *cmd.Info {
 // SetFlags implements cmd.Command.
 func (c *setFirewallRuleCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.whitelistValue, "whitelist", "", "list of subnets to whitelist")
+	f.StringVar(&c.portValue, "port", "", "port range of a model ingress rule")
+	f.StringVar(&c.application, "application", "", "application a model ingress rule applies to")
 }
 
 // Init implements cmd.Command.
 func (c *setFirewallRuleCommand) Init(args []string) (err error) {
 	if len(args) == 1 {
 		c.service = args[0]
+		if c.portValue != "" {
+			portRange, err := network.ParsePortRange(c.portValue)
+			if err != nil {
+				return errors.Annotate(err, "invalid port range")
+			}
+			c.portRange = &portRange
+		} else if c.application != "" {
+			return errors.New("--application can only be used with --port")
+		}
+		if c.application != "" && !names.IsValidApplication(c.application) {
+			return errors.Errorf("invalid application name %q", c.application)
+		}
 		if c.whitelistValue == "" {
 			return errors.New("no whitelist subnets specified")
 		}
@@ -94,7 +124,7 @@ func (c *setFirewallRuleCommand) Init(args []string) (err error) {
 		return nil
 	}
 	if len(args) == 0 {
-		return errors.New("no well known service specified")
+		return errors.New("no well known service or rule name specified")
 	}
 	return cmd.CheckEmpty(args[1:])
 }
@@ -118,6 +148,7 @@ func (c *setFirewallRuleCommand) parseCIDRs(cidrs *[]string, value string) error
 type SetFirewallRuleAPI interface {
 	Close() error
 	SetFirewallRule(service string, whiteListCidrs []string) error
+	SetIngressRule(name string, portRange network.PortRange, whiteListCidrs []string, application string) error
 }
 
 func (c *setFirewallRuleCommand) Run(_ *cmd.Context) error {
@@ -126,6 +157,10 @@ func (c *setFirewallRuleCommand) Run(_ *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
-	err = client.SetFirewallRule(c.service, c.whiteList)
+	if c.portRange != nil {
+		err = client.SetIngressRule(c.service, *c.portRange, c.whiteList, c.application)
+	} else {
+		err = client.SetFirewallRule(c.service, c.whiteList)
+	}
 	return block.ProcessBlockedError(err, block.BlockChange)
 }
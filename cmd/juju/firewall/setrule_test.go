@@ -13,6 +13,7 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/firewall"
+	"github.com/juju/juju/core/network"
 )
 
 type SetRuleSuite struct {
@@ -29,7 +30,7 @@ func (s *SetRuleSuite) SetUpTest(c *gc.C) {
 
 func (s *SetRuleSuite) TestInitMissingService(c *gc.C) {
 	_, err := s.runSetRule(c, "--whitelist", "10.0.0.0/8")
-	c.Assert(err, gc.ErrorMatches, "no well known service specified")
+	c.Assert(err, gc.ErrorMatches, "no well known service or rule name specified")
 }
 
 func (s *SetRuleSuite) TestInitInvalidWhitelist(c *gc.C) {
@@ -51,6 +52,37 @@ func (s *SetRuleSuite) TestSetRule(c *gc.C) {
 	})
 }
 
+func (s *SetRuleSuite) TestInitInvalidPort(c *gc.C) {
+	_, err := s.runSetRule(c, "--port", "80-foo", "web")
+	c.Assert(err, gc.ErrorMatches, `invalid port range: .*`)
+}
+
+func (s *SetRuleSuite) TestInitApplicationWithoutPort(c *gc.C) {
+	_, err := s.runSetRule(c, "--application", "mysql", "--whitelist", "10.0.0.0/8", "ssh")
+	c.Assert(err, gc.ErrorMatches, `--application can only be used with --port`)
+}
+
+func (s *SetRuleSuite) TestInitInvalidApplication(c *gc.C) {
+	_, err := s.runSetRule(c, "--port", "80", "--application", "Bad_App", "web")
+	c.Assert(err, gc.ErrorMatches, `invalid application name "Bad_App"`)
+}
+
+func (s *SetRuleSuite) TestSetIngressRule(c *gc.C) {
+	_, err := s.runSetRule(c, "--port", "9100-9110/tcp", "--whitelist", "10.0.0.0/8", "--application", "mysql", "node-exporter")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.rule, jc.DeepEquals, params.FirewallRule{
+		Name:           "node-exporter",
+		PortRange:      &params.PortRange{FromPort: 9100, ToPort: 9110, Protocol: "tcp"},
+		WhitelistCIDRS: []string{"10.0.0.0/8"},
+		Application:    "mysql",
+	})
+}
+
+func (s *SetRuleSuite) TestInitIngressRuleNoWhitelist(c *gc.C) {
+	_, err := s.runSetRule(c, "--port", "8080", "web")
+	c.Assert(err, gc.ErrorMatches, "no whitelist subnets specified")
+}
+
 func (s *SetRuleSuite) TestSetError(c *gc.C) {
 	s.mockAPI.err = errors.New("fail")
 	_, err := s.runSetRule(c, "ssh", "--whitelist", "10.0.0.0/8")
@@ -80,3 +112,17 @@ func (s *mockSetRuleAPI) SetFirewallRule(service string, whiteListCidrs []string
 	}
 	return nil
 }
+
+func (s *mockSetRuleAPI) SetIngressRule(name string, portRange network.PortRange, whiteListCidrs []string, application string) error {
+	if s.err != nil {
+		return s.err
+	}
+	paramsPortRange := params.FromNetworkPortRange(portRange)
+	s.rule = params.FirewallRule{
+		Name:           name,
+		PortRange:      &paramsPortRange,
+		WhitelistCIDRS: whiteListCidrs,
+		Application:    application,
+	}
+	return nil
+}
@@ -83,5 +83,8 @@ func (c *baselistMachinesCommand) Run(ctx *cmd.Context) error {
 }
 
 func (c *baselistMachinesCommand) tabular(writer io.Writer, value interface{}) error {
+	if diffs, ok := value.(machineNetworkDiffs); ok {
+		return formatNetworkDiffTabular(writer, diffs)
+	}
 	return status.FormatMachineTabular(writer, c.color, value)
 }
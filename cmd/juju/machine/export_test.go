@@ -46,6 +46,15 @@ func NewShowCommandForTest(api statusAPI) cmd.Command {
 	return modelcmd.Wrap(command)
 }
 
+// NewShowNetworkCommandForTest returns a showMachineCommand with the
+// specified api for showing network config.
+func NewShowNetworkCommandForTest(api NetworkConfigAPI) cmd.Command {
+	command := newShowMachineCommand(nil)
+	command.networkAPI = api
+	command.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(command)
+}
+
 type RemoveCommand struct {
 	*removeCommand
 }
@@ -60,6 +69,30 @@ func NewRemoveCommandForTest(apiRoot api.Connection, machineAPI RemoveMachineAPI
 	return modelcmd.Wrap(command), &RemoveCommand{command}
 }
 
+type SetProtectionCommand struct {
+	*setProtectionCommand
+}
+
+// NewSetProtectionCommandForTest returns a SetProtectionCommand with the
+// api provided as specified.
+func NewSetProtectionCommandForTest(api SetProtectionAPI) (cmd.Command, *SetProtectionCommand) {
+	command := &setProtectionCommand{api: api}
+	command.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(command), &SetProtectionCommand{command}
+}
+
+type ResizeCommand struct {
+	*resizeCommand
+}
+
+// NewResizeCommandForTest returns a ResizeCommand with the
+// api provided as specified.
+func NewResizeCommandForTest(api ResizeAPI) (cmd.Command, *ResizeCommand) {
+	command := &resizeCommand{api: api}
+	command.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(command), &ResizeCommand{command}
+}
+
 // NewUpgradeSeriesCommand returns an upgrade series command for test
 func NewUpgradeSeriesCommandForTest(upgradeAPI UpgradeMachineSeriesAPI) cmd.Command {
 	command := &upgradeSeriesCommand{
@@ -0,0 +1,138 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"strconv"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+// NewSetProtectionCommand returns a command used to protect machines
+// from termination.
+func NewSetProtectionCommand() cmd.Command {
+	return modelcmd.Wrap(&setProtectionCommand{})
+}
+
+// SetProtectionAPI defines the API methods used by the
+// set-machine-protection command.
+type SetProtectionAPI interface {
+	SetMachineProtection(protected bool, machines ...string) ([]params.ErrorResult, error)
+	Close() error
+}
+
+// setProtectionCommand sets whether machines are protected from termination.
+type setProtectionCommand struct {
+	baseMachinesCommand
+	api        SetProtectionAPI
+	MachineIds []string
+	Protected  bool
+}
+
+const setProtectionDoc = `
+Machines are specified by their numbers, which may be retrieved from the
+output of ` + "`juju status`." + `
+
+The cloud instance of a protected machine is never terminated by Juju.
+Removing a protected machine fails unless its cloud instance is kept with
+'juju remove-machine --keep-instance', or its protection is removed with
+'juju remove-machine --force-unprotected'. A model containing protected
+machines cannot be destroyed.
+
+Where the cloud supports it, the cloud instance is also protected from
+termination through the cloud's own API.
+
+Examples:
+
+    juju set-machine-protection 3 true
+    juju set-machine-protection 3 4 false
+
+See also:
+    remove-machine
+`
+
+// Info implements Command.Info.
+func (c *setProtectionCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "set-machine-protection",
+		Args:    "<machine number> ... true|false",
+		Purpose: "Protects machines from having their cloud instances terminated.",
+		Doc:     setProtectionDoc,
+	})
+}
+
+// Init implements Command.Init.
+func (c *setProtectionCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.Errorf("expected machines and a protection value")
+	}
+	last := len(args) - 1
+	protected, err := strconv.ParseBool(args[last])
+	if err != nil {
+		return errors.Errorf("invalid protection value %q, expected true or false", args[last])
+	}
+	for _, id := range args[:last] {
+		if !names.IsValidMachine(id) {
+			return errors.Errorf("invalid machine id %q", id)
+		}
+	}
+	c.MachineIds = args[:last]
+	c.Protected = protected
+	return nil
+}
+
+func (c *setProtectionCommand) getAPI() (SetProtectionAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if root.BestFacadeVersion("MachineManager") < 7 {
+		root.Close()
+		return nil, errors.New("this version of Juju doesn't support machine protection")
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+// Run implements Command.Run.
+func (c *setProtectionCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	results, err := client.SetMachineProtection(c.Protected, c.MachineIds...)
+	if err := block.ProcessBlockedError(err, block.BlockChange); err != nil {
+		return err
+	}
+
+	anyFailed := false
+	for i, id := range c.MachineIds {
+		if err := results[i].Error; err != nil {
+			anyFailed = true
+			ctx.Infof("setting protection for machine %s failed: %s", id, err)
+			continue
+		}
+		if c.Protected {
+			ctx.Infof("machine %s is protected from termination", id)
+		} else {
+			ctx.Infof("machine %s is no longer protected from termination", id)
+		}
+	}
+	if anyFailed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/testing"
+)
+
+type SetProtectionSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake *fakeSetProtectionAPI
+}
+
+var _ = gc.Suite(&SetProtectionSuite{})
+
+func (s *SetProtectionSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = &fakeSetProtectionAPI{}
+}
+
+func (s *SetProtectionSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command, _ := machine.NewSetProtectionCommandForTest(s.fake)
+	return cmdtesting.RunCommand(c, command, args...)
+}
+
+func (s *SetProtectionSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		machines    []string
+		protected   bool
+		errorString string
+	}{
+		{
+			errorString: "expected machines and a protection value",
+		}, {
+			args:        []string{"1"},
+			errorString: "expected machines and a protection value",
+		}, {
+			args:      []string{"1", "true"},
+			machines:  []string{"1"},
+			protected: true,
+		}, {
+			args:     []string{"1", "2/lxd/0", "false"},
+			machines: []string{"1", "2/lxd/0"},
+		}, {
+			args:        []string{"1", "maybe"},
+			errorString: `invalid protection value "maybe", expected true or false`,
+		}, {
+			args:        []string{"lxd", "true"},
+			errorString: `invalid machine id "lxd"`,
+		},
+	} {
+		c.Logf("test %d", i)
+		wrappedCommand, protectionCmd := machine.NewSetProtectionCommandForTest(s.fake)
+		err := cmdtesting.InitCommand(wrappedCommand, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(protectionCmd.MachineIds, jc.DeepEquals, test.machines)
+			c.Check(protectionCmd.Protected, gc.Equals, test.protected)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *SetProtectionSuite) TestSetProtection(c *gc.C) {
+	ctx, err := s.run(c, "1", "2", "true")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.protected, jc.IsTrue)
+	c.Assert(s.fake.machines, jc.DeepEquals, []string{"1", "2"})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+machine 1 is protected from termination
+machine 2 is protected from termination
+`[1:])
+}
+
+func (s *SetProtectionSuite) TestRemoveProtectionOutput(c *gc.C) {
+	s.fake.results = []params.ErrorResult{
+		{Error: &params.Error{Message: "oy vey"}},
+		{},
+	}
+	ctx, err := s.run(c, "1", "2", "false")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(s.fake.protected, jc.IsFalse)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+setting protection for machine 1 failed: oy vey
+machine 2 is no longer protected from termination
+`[1:])
+}
+
+func (s *SetProtectionSuite) TestBlockedError(c *gc.C) {
+	s.fake.err = common.OperationBlockedError("TestBlockedError")
+	_, err := s.run(c, "1", "true")
+	testing.AssertOperationWasBlocked(c, err, ".*TestBlockedError.*")
+}
+
+type fakeSetProtectionAPI struct {
+	protected bool
+	machines  []string
+	results   []params.ErrorResult
+	err       error
+}
+
+func (f *fakeSetProtectionAPI) Close() error {
+	return nil
+}
+
+func (f *fakeSetProtectionAPI) SetMachineProtection(protected bool, machines ...string) ([]params.ErrorResult, error) {
+	f.protected = protected
+	f.machines = machines
+	if f.err != nil || f.results != nil {
+		return f.results, f.err
+	}
+	return make([]params.ErrorResult, len(machines)), nil
+}
@@ -27,13 +27,14 @@ func NewRemoveCommand() cmd.Command {
 // removeCommand causes an existing machine to be destroyed.
 type removeCommand struct {
 	baseMachinesCommand
-	apiRoot      api.Connection
-	machineAPI   RemoveMachineAPI
-	MachineIds   []string
-	Force        bool
-	KeepInstance bool
-	NoWait       bool
-	fs           *gnuflag.FlagSet
+	apiRoot          api.Connection
+	machineAPI       RemoveMachineAPI
+	MachineIds       []string
+	Force            bool
+	KeepInstance     bool
+	ForceUnprotected bool
+	NoWait           bool
+	fs               *gnuflag.FlagSet
 }
 
 const destroyMachineDoc = `
@@ -45,6 +46,10 @@ the corresponding cloud instnace by using --keep-instance option.
 
 Machines responsible for the model cannot be removed.
 
+Machines protected from termination with set-machine-protection cannot
+be removed unless their cloud instances are kept, or the protection is
+removed with the '--force-unprotected' option.
+
 Machines running units or containers can be removed using the '--force'
 option; this will also remove those units and containers without giving
 them an opportunity to shut down cleanly.
@@ -60,9 +65,11 @@ Examples:
     juju remove-machine 6 --force
     juju remove-machine 6 --force --no-wait
     juju remove-machine 7 --keep-instance
+    juju remove-machine 8 --force-unprotected
 
 See also:
     add-machine
+    set-machine-protection
 `
 
 // Info implements Command.Info.
@@ -80,6 +87,7 @@ func (c *removeCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ModelCommandBase.SetFlags(f)
 	f.BoolVar(&c.Force, "force", false, "Completely remove a machine and all its dependencies")
 	f.BoolVar(&c.KeepInstance, "keep-instance", false, "Do not stop the running cloud instance")
+	f.BoolVar(&c.ForceUnprotected, "force-unprotected", false, "Remove termination protection from the machine before removing it")
 	f.BoolVar(&c.NoWait, "no-wait", false, "Rush through machine removal without waiting for each individual step to complete")
 	c.fs = f
 }
@@ -101,6 +109,7 @@ type RemoveMachineAPI interface {
 	// TODO (anastasiamac 2019-4-24) From Juju 3.0 this call will be removed in favour of DestroyMachinesWithParams.
 	DestroyMachines(machines ...string) ([]params.DestroyMachineResult, error)
 	DestroyMachinesWithParams(force, keep bool, maxWait *time.Duration, machines ...string) ([]params.DestroyMachineResult, error)
+	DestroyProtectedMachines(force, keep bool, maxWait *time.Duration, machines ...string) ([]params.DestroyMachineResult, error)
 	Close() error
 }
 
@@ -118,6 +127,17 @@ func (a removeMachineAdapter) DestroyMachinesWithParams(force, keep bool, maxWai
 	return a.destroyMachines(a.Client.ForceDestroyMachines, machines)
 }
 
+// DestroyProtectedMachines destroys the machines with the Client facade.
+// Controllers without the MachineManager facade predate termination
+// protection, so there is no protection to remove first.
+func (a removeMachineAdapter) DestroyProtectedMachines(force, keep bool, maxWait *time.Duration, machines ...string) ([]params.DestroyMachineResult, error) {
+	destroy := a.Client.DestroyMachines
+	if force {
+		destroy = a.Client.ForceDestroyMachines
+	}
+	return a.destroyMachines(destroy, machines)
+}
+
 func (a removeMachineAdapter) destroyMachines(f func(...string) error, machines []string) ([]params.DestroyMachineResult, error) {
 	if err := f(machines...); err != nil {
 		return nil, err
@@ -144,6 +164,9 @@ func (c *removeCommand) getRemoveMachineAPI() (RemoveMachineAPI, error) {
 	if root.BestFacadeVersion("MachineManager") < 4 && c.KeepInstance {
 		return nil, errors.New("this version of Juju doesn't support --keep-instance")
 	}
+	if root.BestFacadeVersion("MachineManager") < 7 && c.ForceUnprotected {
+		return nil, errors.New("this version of Juju doesn't support --force-unprotected")
+	}
 	if root.BestFacadeVersion("MachineManager") >= 3 && c.machineAPI == nil {
 		return machinemanager.NewClient(root), nil
 	}
@@ -183,7 +206,9 @@ func (c *removeCommand) Run(ctx *cmd.Context) error {
 
 	var results []params.DestroyMachineResult
 
-	if c.KeepInstance || c.Force {
+	if c.ForceUnprotected {
+		results, err = client.DestroyProtectedMachines(c.Force, c.KeepInstance, maxWait, c.MachineIds...)
+	} else if c.KeepInstance || c.Force {
 		results, err = client.DestroyMachinesWithParams(c.Force, c.KeepInstance, maxWait, c.MachineIds...)
 	} else {
 		results, err = client.DestroyMachines(c.MachineIds...)
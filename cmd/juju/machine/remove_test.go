@@ -171,9 +171,26 @@ func (s *RemoveMachineSuite) TestOldFacadeRemoveKeep(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "this version of Juju doesn't support --keep-instance")
 }
 
+func (s *RemoveMachineSuite) TestRemoveForceUnprotected(c *gc.C) {
+	s.apiConnection.bestFacadeVersion = 7
+	_, err := s.run(c, "--force-unprotected", "1", "2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.unprotect, jc.IsTrue)
+	c.Assert(s.fake.forced, jc.IsFalse)
+	c.Assert(s.fake.keep, jc.IsFalse)
+	c.Assert(s.fake.machines, jc.DeepEquals, []string{"1", "2"})
+}
+
+func (s *RemoveMachineSuite) TestOldFacadeRemoveForceUnprotected(c *gc.C) {
+	s.apiConnection.bestFacadeVersion = 6
+	_, err := s.run(c, "--force-unprotected", "1")
+	c.Assert(err, gc.ErrorMatches, "this version of Juju doesn't support --force-unprotected")
+}
+
 type fakeRemoveMachineAPI struct {
 	forced      bool
 	keep        bool
+	unprotect   bool
 	machines    []string
 	removeError error
 	results     []params.DestroyMachineResult
@@ -194,6 +211,13 @@ func (f *fakeRemoveMachineAPI) DestroyMachinesWithParams(force, keep bool, maxWa
 	return f.destroyMachines(machines)
 }
 
+func (f *fakeRemoveMachineAPI) DestroyProtectedMachines(force, keep bool, maxWait *time.Duration, machines ...string) ([]params.DestroyMachineResult, error) {
+	f.forced = force
+	f.keep = keep
+	f.unprotect = true
+	return f.destroyMachines(machines)
+}
+
 func (f *fakeRemoveMachineAPI) destroyMachines(machines []string) ([]params.DestroyMachineResult, error) {
 	f.machines = machines
 	if f.removeError != nil || f.results != nil {
@@ -0,0 +1,147 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/constraints"
+)
+
+// NewResizeCommand returns a command used to resize the cloud
+// instances of machines.
+func NewResizeCommand() cmd.Command {
+	return modelcmd.Wrap(&resizeCommand{})
+}
+
+// ResizeAPI defines the API methods used by the resize-machine command.
+type ResizeAPI interface {
+	ResizeMachines(cons constraints.Value, machines ...string) ([]params.ResizeMachineResult, error)
+	Close() error
+}
+
+// resizeCommand changes the hardware of machines' cloud instances.
+type resizeCommand struct {
+	baseMachinesCommand
+	api            ResizeAPI
+	MachineIds     []string
+	ConstraintsStr string
+}
+
+const resizeDoc = `
+Machines are specified by their numbers, which may be retrieved from the
+output of ` + "`juju status`." + `
+
+Changing constraints with 'juju set-constraints' only affects machines
+provisioned afterwards. This command instead changes the hardware of the
+cloud instances of existing machines, to satisfy the given constraints
+combined with each machine's current constraints. The machines' constraints
+and hardware characteristics are updated to match.
+
+How an instance is resized depends on the cloud. Some clouds, such as AWS
+and OpenStack, stop or rebuild the instance with a new instance type, so
+the machine is briefly unavailable; LXD applies new CPU and memory limits
+to a running container. Clouds that cannot resize instances, and machines
+in containers, are not supported.
+
+Examples:
+
+    juju resize-machine 3 --constraints mem=16G
+    juju resize-machine 3 4 --constraints "cores=8 mem=32G"
+    juju resize-machine 5 --constraints instance-type=m5.xlarge
+
+See also:
+    set-constraints
+    show-machine
+`
+
+// Info implements Command.Info.
+func (c *resizeCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "resize-machine",
+		Args:    "<machine number> ...",
+		Purpose: "Changes the hardware of machines' cloud instances.",
+		Doc:     resizeDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *resizeCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.baseMachinesCommand.SetFlags(f)
+	f.StringVar(&c.ConstraintsStr, "constraints", "", "Constraints the resized machines must satisfy")
+}
+
+// Init implements Command.Init.
+func (c *resizeCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.Errorf("no machines specified")
+	}
+	for _, id := range args {
+		if !names.IsValidMachine(id) {
+			return errors.Errorf("invalid machine id %q", id)
+		}
+	}
+	if c.ConstraintsStr == "" {
+		return errors.Errorf("no constraints specified")
+	}
+	c.MachineIds = args
+	return nil
+}
+
+func (c *resizeCommand) getAPI() (ResizeAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if root.BestFacadeVersion("MachineManager") < 8 {
+		root.Close()
+		return nil, errors.New("this version of Juju doesn't support resizing machines")
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+// Run implements Command.Run.
+func (c *resizeCommand) Run(ctx *cmd.Context) error {
+	cons, err := common.ParseConstraints(ctx, c.ConstraintsStr)
+	if err != nil {
+		return err
+	}
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	results, err := client.ResizeMachines(cons, c.MachineIds...)
+	if err := block.ProcessBlockedError(err, block.BlockChange); err != nil {
+		return err
+	}
+
+	anyFailed := false
+	for i, id := range c.MachineIds {
+		result := results[i]
+		if result.Error != nil {
+			anyFailed = true
+			ctx.Infof("resizing machine %s failed: %s", id, result.Error)
+			continue
+		}
+		ctx.Infof("resized machine %s: %s", id, result.HardwareCharacteristics)
+	}
+	if anyFailed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/testing"
+)
+
+type ResizeSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake *fakeResizeAPI
+}
+
+var _ = gc.Suite(&ResizeSuite{})
+
+func (s *ResizeSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = &fakeResizeAPI{}
+}
+
+func (s *ResizeSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command, _ := machine.NewResizeCommandForTest(s.fake)
+	return cmdtesting.RunCommand(c, command, args...)
+}
+
+func (s *ResizeSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		machines    []string
+		errorString string
+	}{
+		{
+			errorString: "no machines specified",
+		}, {
+			args:        []string{"1"},
+			errorString: "no constraints specified",
+		}, {
+			args:     []string{"1", "2", "--constraints", "mem=16G"},
+			machines: []string{"1", "2"},
+		}, {
+			args:        []string{"lxd", "--constraints", "mem=16G"},
+			errorString: `invalid machine id "lxd"`,
+		},
+	} {
+		c.Logf("test %d", i)
+		wrappedCommand, resizeCmd := machine.NewResizeCommandForTest(s.fake)
+		err := cmdtesting.InitCommand(wrappedCommand, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(resizeCmd.MachineIds, jc.DeepEquals, test.machines)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *ResizeSuite) TestResize(c *gc.C) {
+	mem := uint64(16384)
+	cores := uint64(4)
+	s.fake.results = []params.ResizeMachineResult{
+		{HardwareCharacteristics: &instance.HardwareCharacteristics{Mem: &mem, CpuCores: &cores}},
+		{Error: &params.Error{Message: "oy vey"}},
+	}
+	ctx, err := s.run(c, "1", "2", "--constraints", "mem=16G cores=4")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(s.fake.cons, jc.DeepEquals, constraints.MustParse("mem=16G cores=4"))
+	c.Assert(s.fake.machines, jc.DeepEquals, []string{"1", "2"})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+resized machine 1: cores=4 mem=16384M
+resizing machine 2 failed: oy vey
+`[1:])
+}
+
+func (s *ResizeSuite) TestInvalidConstraints(c *gc.C) {
+	_, err := s.run(c, "1", "--constraints", "mem=lots")
+	c.Assert(err, gc.ErrorMatches, `bad "mem" constraint: must be a non-negative float with optional M/G/T/P suffix`)
+}
+
+func (s *ResizeSuite) TestBlockedError(c *gc.C) {
+	s.fake.err = common.OperationBlockedError("TestBlockedError")
+	_, err := s.run(c, "1", "--constraints", "mem=16G")
+	testing.AssertOperationWasBlocked(c, err, ".*TestBlockedError.*")
+}
+
+type fakeResizeAPI struct {
+	cons     constraints.Value
+	machines []string
+	results  []params.ResizeMachineResult
+	err      error
+}
+
+func (f *fakeResizeAPI) Close() error {
+	return nil
+}
+
+func (f *fakeResizeAPI) ResizeMachines(cons constraints.Value, machines ...string) ([]params.ResizeMachineResult, error) {
+	f.cons = cons
+	f.machines = machines
+	if f.err != nil || f.results != nil {
+		return f.results, f.err
+	}
+	return make([]params.ResizeMachineResult, len(machines)), nil
+}
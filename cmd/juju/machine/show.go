@@ -4,10 +4,21 @@
 package machine
 
 import (
+	"fmt"
+	"io"
+	"sort"
+
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v3"
 
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/apiserver/params"
 	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
 )
 
 const showMachineCommandDoc = `
@@ -15,12 +26,28 @@ Show a specified machine on a model.  Default format is in yaml,
 other formats can be specified with the "--format" option.
 Available formats are yaml, tabular, and json
 
+With --network, the netplan configuration rendered from the network
+devices the provider knows about for each machine is compared with the
+configuration last reported by the machine's agent, and the devices that
+differ are shown. Devices created on the machine itself, such as the LXD
+or fan bridges, are left alone. When the model's "manage-netplan" setting is enabled,
+machine agents apply the desired configuration themselves, and any failure
+to do so is shown.
+
 Examples:
     juju show-machine 0
     juju show-machine 1 2 3
+    juju show-machine 0 --network
+    juju show-machine 0 --network --format tabular
 
 `
 
+// NetworkConfigAPI defines the API methods used by show-machine --network.
+type NetworkConfigAPI interface {
+	NetworkConfigDiff(machines ...string) ([]params.MachineNetworkDiffResult, error)
+	Close() error
+}
+
 // NewShowMachineCommand returns a command that shows details on the specified machine[s].
 func NewShowMachineCommand() cmd.Command {
 	return modelcmd.Wrap(newShowMachineCommand(nil))
@@ -36,6 +63,9 @@ func newShowMachineCommand(api statusAPI) *showMachineCommand {
 // showMachineCommand struct holds details on the specified machine[s].
 type showMachineCommand struct {
 	baselistMachinesCommand
+
+	network    bool
+	networkAPI NetworkConfigAPI
 }
 
 // Info implements Command.Info.
@@ -48,8 +78,143 @@ func (c *showMachineCommand) Info() *cmd.Info {
 	})
 }
 
+// SetFlags implements Command.SetFlags.
+func (c *showMachineCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.baselistMachinesCommand.SetFlags(f)
+	f.BoolVar(&c.network, "network", false, "Show the differences between the desired and actual network configuration")
+}
+
 // Init captures machineId's to show from CL args.
 func (c *showMachineCommand) Init(args []string) error {
+	if c.network {
+		if len(args) == 0 {
+			return errors.Errorf("no machines specified")
+		}
+		for _, id := range args {
+			if !names.IsValidMachine(id) {
+				return errors.Errorf("invalid machine id %q", id)
+			}
+		}
+	}
 	c.machineIds = args
 	return nil
 }
+
+// Run implements Command.Run.
+func (c *showMachineCommand) Run(ctx *cmd.Context) error {
+	if !c.network {
+		return c.baselistMachinesCommand.Run(ctx)
+	}
+	client, err := c.getNetworkAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	results, err := client.NetworkConfigDiff(c.machineIds...)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	diffs := make(machineNetworkDiffs)
+	for i, id := range c.machineIds {
+		diffs[id] = c.formatNetworkDiff(results[i])
+	}
+	return c.out.Write(ctx, diffs)
+}
+
+func (c *showMachineCommand) getNetworkAPI() (NetworkConfigAPI, error) {
+	if c.networkAPI != nil {
+		return c.networkAPI, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if root.BestFacadeVersion("MachineManager") < 9 {
+		root.Close()
+		return nil, errors.New("this version of Juju doesn't support showing machine network config")
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+// machineNetworkDiffs holds the network configuration of machines, keyed
+// by machine ID, as shown by show-machine --network.
+type machineNetworkDiffs map[string]machineNetworkDiff
+
+type machineNetworkDiff struct {
+	Changes    []networkDeviceChange `yaml:"changes,omitempty" json:"changes,omitempty"`
+	ApplyError string                `yaml:"apply-error,omitempty" json:"apply-error,omitempty"`
+	Updated    string                `yaml:"updated,omitempty" json:"updated,omitempty"`
+	Desired    string                `yaml:"desired,omitempty" json:"desired,omitempty"`
+	Actual     string                `yaml:"actual,omitempty" json:"actual,omitempty"`
+	Error      string                `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+type networkDeviceChange struct {
+	Device  string   `yaml:"device" json:"device"`
+	Type    string   `yaml:"type" json:"type"`
+	Change  string   `yaml:"change" json:"change"`
+	Details []string `yaml:"details,omitempty" json:"details,omitempty"`
+}
+
+func (c *showMachineCommand) formatNetworkDiff(result params.MachineNetworkDiffResult) machineNetworkDiff {
+	if result.Error != nil {
+		return machineNetworkDiff{Error: result.Error.Error()}
+	}
+	diff := machineNetworkDiff{
+		ApplyError: result.ApplyError,
+		Desired:    result.Desired,
+		Actual:     result.Actual,
+	}
+	if result.Updated != nil {
+		diff.Updated = common.FormatTime(result.Updated, c.isoTime)
+	}
+	for _, change := range result.Changes {
+		diff.Changes = append(diff.Changes, networkDeviceChange{
+			Device:  change.Name,
+			Type:    change.Type,
+			Change:  change.Change,
+			Details: change.Details,
+		})
+	}
+	return diff
+}
+
+// formatNetworkDiffTabular writes a row for each setting of each device
+// that differs between the desired and actual network configuration of
+// the machines, followed by any errors.
+func formatNetworkDiffTabular(writer io.Writer, diffs machineNetworkDiffs) error {
+	ids := make([]string, 0, len(diffs))
+	for id := range diffs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Machine", "Device", "Type", "Change", "Details")
+	for _, id := range ids {
+		for _, change := range diffs[id].Changes {
+			details := change.Details
+			if len(details) == 0 {
+				details = []string{""}
+			}
+			w.Println(id, change.Device, change.Type, change.Change, details[0])
+			for _, detail := range details[1:] {
+				w.Println("", "", "", "", detail)
+			}
+		}
+	}
+	tw.Flush()
+
+	for _, id := range ids {
+		diff := diffs[id]
+		switch {
+		case diff.Error != "":
+			fmt.Fprintf(writer, "\nmachine %s: %s\n", id, diff.Error)
+		case diff.ApplyError != "":
+			fmt.Fprintf(writer, "\nmachine %s failed to apply network config: %s\n", id, diff.ApplyError)
+		}
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/machine"
 	"github.com/juju/juju/testing"
 )
@@ -243,3 +244,82 @@ func (s *MachineShowCommandSuite) TestShowJsonMachine(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(actualJSON, gc.DeepEquals, expectedJSON)
 }
+
+type fakeNetworkConfigAPI struct {
+	machines []string
+}
+
+func (f *fakeNetworkConfigAPI) NetworkConfigDiff(machines ...string) ([]params.MachineNetworkDiffResult, error) {
+	f.machines = machines
+	return []params.MachineNetworkDiffResult{{
+		Desired: "network:\n  version: 2\n",
+		Actual:  "network:\n  version: 2\n",
+		Changes: []params.NetplanDeviceChange{{
+			Type:    "bond",
+			Name:    "bond0",
+			Change:  "changed",
+			Details: []string{"mtu: 1500 -> 9000", "parameters.mode: active-backup -> 802.3ad"},
+		}, {
+			Type:   "vlan",
+			Name:   "bond0.42",
+			Change: "added",
+		}},
+		ApplyError: "netplan apply error code 1",
+	}, {
+		Error: &params.Error{Message: "machine 1 not found", Code: params.CodeNotFound},
+	}}, nil
+}
+
+func (*fakeNetworkConfigAPI) Close() error {
+	return nil
+}
+
+func (s *MachineShowCommandSuite) TestShowNetworkInit(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, machine.NewShowNetworkCommandForTest(&fakeNetworkConfigAPI{}), "--network")
+	c.Assert(err, gc.ErrorMatches, "no machines specified")
+	_, err = cmdtesting.RunCommand(c, machine.NewShowNetworkCommandForTest(&fakeNetworkConfigAPI{}), "--network", "lxd")
+	c.Assert(err, gc.ErrorMatches, `invalid machine id "lxd"`)
+}
+
+func (s *MachineShowCommandSuite) TestShowNetwork(c *gc.C) {
+	api := &fakeNetworkConfigAPI{}
+	context, err := cmdtesting.RunCommand(c, machine.NewShowNetworkCommandForTest(api), "--network", "0", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(api.machines, jc.DeepEquals, []string{"0", "1"})
+	c.Assert(cmdtesting.Stdout(context), gc.Equals, ""+
+		"\"0\":\n"+
+		"  changes:\n"+
+		"  - device: bond0\n"+
+		"    type: bond\n"+
+		"    change: changed\n"+
+		"    details:\n"+
+		"    - 'mtu: 1500 -> 9000'\n"+
+		"    - 'parameters.mode: active-backup -> 802.3ad'\n"+
+		"  - device: bond0.42\n"+
+		"    type: vlan\n"+
+		"    change: added\n"+
+		"  apply-error: netplan apply error code 1\n"+
+		"  desired: |\n"+
+		"    network:\n"+
+		"      version: 2\n"+
+		"  actual: |\n"+
+		"    network:\n"+
+		"      version: 2\n"+
+		"\"1\":\n"+
+		"  error: machine 1 not found\n")
+}
+
+func (s *MachineShowCommandSuite) TestShowNetworkTabular(c *gc.C) {
+	context, err := cmdtesting.RunCommand(c, machine.NewShowNetworkCommandForTest(&fakeNetworkConfigAPI{}),
+		"--network", "--format", "tabular", "0", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(context), gc.Equals, ""+
+		"Machine  Device    Type  Change   Details\n"+
+		"0        bond0     bond  changed  mtu: 1500 -> 9000\n"+
+		"                                  parameters.mode: active-backup -> 802.3ad\n"+
+		"0        bond0.42  vlan  added    \n"+
+		"\n"+
+		"machine 0 failed to apply network config: netplan apply error code 1\n"+
+		"\n"+
+		"machine 1: machine 1 not found\n")
+}
@@ -0,0 +1,516 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/action"
+	"github.com/juju/juju/api/spaces"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/core/actions"
+)
+
+// ConnectivityAPI defines the API methods needed by the
+// check-connectivity command.
+type ConnectivityAPI interface {
+	io.Closer
+
+	// UnitEndpointsNetworkInfo returns the network information that
+	// network-get reports for each of the given unit endpoints.
+	UnitEndpointsNetworkInfo([]params.UnitEndpoint) ([]params.UnitEndpointNetworkInfoResult, error)
+
+	// Enqueue queues up actions to be run by their receivers.
+	Enqueue(params.Actions) (params.ActionResults, error)
+
+	// Actions fetches actions by tag.
+	Actions(params.Entities) (params.ActionResults, error)
+}
+
+// NewCheckConnectivityCommand returns a command used to check the
+// network connectivity between two units.
+func NewCheckConnectivityCommand() modelcmd.ModelCommand {
+	return modelcmd.Wrap(&CheckConnectivityCommand{clock: clock.WallClock})
+}
+
+// CheckConnectivityCommand checks whether two units can reach each
+// other on the addresses of one of their endpoints.
+type CheckConnectivityCommand struct {
+	modelcmd.ModelCommandBase
+	modelcmd.IAASOnlyCommand
+
+	api   ConnectivityAPI
+	clock clock.Clock
+	out   cmd.Output
+
+	units    []string
+	endpoint string
+	port     int
+	timeout  time.Duration
+	wait     time.Duration
+}
+
+const checkConnectivityCommandDoc = `
+Checks whether two units can reach each other over TCP and UDP on the
+ingress addresses of the given endpoint, as reported to the units by
+network-get.
+
+A temporary listener is opened on the machine of each unit, and probed
+from the machine of the other unit. For each direction the result of
+the TCP and UDP probes is reported, along with the latency or the
+reason for a failure. The MTU of the interface each unit's ingress
+address is on is also reported, and a warning is given if they differ.
+The ingress addresses must be configured on the machines, so addresses
+translated by the provider cannot be checked.
+
+By default the listeners use ports chosen by the machine agent. Use
+--port to check a specific port, which must not be privileged or in
+use on either machine. The listeners are kept open for --wait plus
+twice --timeout, which must not exceed 10 minutes.
+
+The command exits with an error status if any of the probes fail.
+
+Examples:
+
+    juju check-connectivity wordpress/0 mysql/0 --endpoint db
+    juju check-connectivity wordpress/0 mysql/0 --endpoint db --port 3306
+
+See also:
+    spaces
+    show-space
+`
+
+// Info is defined on the cmd.Command interface.
+func (c *CheckConnectivityCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "check-connectivity",
+		Args:    "<unit> <unit>",
+		Purpose: "Check the network connectivity between two units.",
+		Doc:     strings.TrimSpace(checkConnectivityCommandDoc),
+	})
+}
+
+// SetFlags is defined on the cmd.Command interface.
+func (c *CheckConnectivityCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": c.printTabular,
+	})
+	f.StringVar(&c.endpoint, "endpoint", "", "The endpoint whose ingress addresses are checked")
+	f.IntVar(&c.port, "port", 0, "The port to check, instead of one chosen by the machine agents")
+	f.DurationVar(&c.timeout, "timeout", 5*time.Second, "How long to wait for a response to each probe")
+	f.DurationVar(&c.wait, "wait", time.Minute, "How long to wait for the machine agents to run the checks")
+}
+
+// Init is defined on the cmd.Command interface.
+func (c *CheckConnectivityCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.New("two units are required")
+	}
+	for _, unit := range args[:2] {
+		if !names.IsValidUnit(unit) {
+			return errors.NotValidf("unit name %q", unit)
+		}
+	}
+	if args[0] == args[1] {
+		return errors.New("cannot check the connectivity of a unit with itself")
+	}
+	if c.endpoint == "" {
+		return errors.New("--endpoint is required")
+	}
+	if c.port < 0 || c.port > 65535 {
+		return errors.NotValidf("port %d", c.port)
+	}
+	if c.port > 0 && c.port < 1024 {
+		return errors.NotValidf("privileged port %d", c.port)
+	}
+	if c.timeout <= 0 {
+		return errors.NotValidf("timeout %v", c.timeout)
+	}
+	if c.wait <= 0 {
+		return errors.NotValidf("wait %v", c.wait)
+	}
+	if c.wait+2*c.timeout > actions.MaxConnectivityListenTimeout {
+		return errors.Errorf("--wait plus twice --timeout must not exceed %v", actions.MaxConnectivityListenTimeout)
+	}
+	c.units = args[:2]
+	return cmd.CheckEmpty(args[2:])
+}
+
+// connectivityReport holds the results of a connectivity check.
+type connectivityReport struct {
+	Endpoint string              `yaml:"endpoint" json:"endpoint"`
+	Units    []connectivityUnit  `yaml:"units" json:"units"`
+	Checks   []connectivityCheck `yaml:"checks" json:"checks"`
+	Warnings []string            `yaml:"warnings,omitempty" json:"warnings,omitempty"`
+}
+
+// connectivityUnit describes the endpoint of one of the units checked.
+type connectivityUnit struct {
+	Name      string `yaml:"name" json:"name"`
+	Machine   string `yaml:"machine" json:"machine"`
+	Space     string `yaml:"space" json:"space"`
+	Address   string `yaml:"ingress-address" json:"ingress-address"`
+	Interface string `yaml:"interface,omitempty" json:"interface,omitempty"`
+	MTU       int    `yaml:"interface-mtu,omitempty" json:"interface-mtu,omitempty"`
+}
+
+// connectivityCheck holds the results of probing the listener of one
+// unit from the machine of the other.
+type connectivityCheck struct {
+	From    string      `yaml:"from" json:"from"`
+	To      string      `yaml:"to" json:"to"`
+	Address string      `yaml:"address" json:"address"`
+	TCP     probeResult `yaml:"tcp" json:"tcp"`
+	UDP     probeResult `yaml:"udp" json:"udp"`
+}
+
+// probeResult holds the result of a single TCP or UDP probe.
+type probeResult struct {
+	Port    int    `yaml:"port" json:"port"`
+	Status  string `yaml:"status" json:"status"`
+	Latency string `yaml:"latency,omitempty" json:"latency,omitempty"`
+	Error   string `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+func (r probeResult) ok() bool {
+	return r.Status == "ok"
+}
+
+// Run implements Command.Run.
+func (c *CheckConnectivityCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPI()
+	if err != nil {
+		return errors.Annotate(err, "cannot connect to the API server")
+	}
+	defer api.Close()
+
+	units, machines, err := c.resolveEndpoints(api)
+	if err != nil {
+		if params.IsCodeUnauthorized(err) {
+			common.PermissionsMessage(ctx.Stderr, "check connectivity")
+		}
+		return errors.Trace(err)
+	}
+
+	// Open a listener on the machine of each unit. They are kept open
+	// long enough for the probes to be queued and run.
+	listenParams := make([]params.Action, len(units))
+	for i, unit := range units {
+		listenParams[i] = params.Action{
+			Receiver: machines[i].String(),
+			Name:     actions.ConnectivityListenActionName,
+			Parameters: map[string]interface{}{
+				"address": unit.Address,
+				"port":    c.port,
+				"timeout": (c.wait + 2*c.timeout).Nanoseconds(),
+			},
+		}
+	}
+	ctx.Verbosef("opening listeners on machines %s and %s", units[0].Machine, units[1].Machine)
+	listens, err := c.runActions(api, listenParams)
+	if err != nil {
+		return errors.Annotate(err, "cannot open listeners")
+	}
+
+	// Probe the listener of each unit from the machine of the other.
+	probeParams := make([]params.Action, len(units))
+	ports := make([][2]int, len(units))
+	for i := range units {
+		target := 1 - i
+		if ports[target], err = listenerPorts(listens[target]); err != nil {
+			return errors.Annotatef(err, "listener on machine %s", units[target].Machine)
+		}
+		probeParams[i] = params.Action{
+			Receiver: machines[i].String(),
+			Name:     actions.ConnectivityProbeActionName,
+			Parameters: map[string]interface{}{
+				"address":  units[target].Address,
+				"tcp-port": ports[target][0],
+				"udp-port": ports[target][1],
+				"timeout":  c.timeout.Nanoseconds(),
+			},
+		}
+	}
+	ctx.Verbosef("probing listeners")
+	probes, err := c.runActions(api, probeParams)
+	if err != nil {
+		return errors.Annotate(err, "cannot probe listeners")
+	}
+
+	report := buildConnectivityReport(c.endpoint, units, listens, probes, ports)
+	if err := c.out.Write(ctx, report); err != nil {
+		return errors.Trace(err)
+	}
+	for _, check := range report.Checks {
+		if !check.TCP.ok() || !check.UDP.ok() {
+			return cmd.ErrSilent
+		}
+	}
+	return nil
+}
+
+// resolveEndpoints returns the network information of the endpoint of
+// both units, and the tags of the machines hosting them.
+func (c *CheckConnectivityCommand) resolveEndpoints(api ConnectivityAPI) ([]connectivityUnit, []names.MachineTag, error) {
+	args := make([]params.UnitEndpoint, len(c.units))
+	for i, unit := range c.units {
+		args[i] = params.UnitEndpoint{
+			UnitTag:  names.NewUnitTag(unit).String(),
+			Endpoint: c.endpoint,
+		}
+	}
+	results, err := api.UnitEndpointsNetworkInfo(args)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "cannot get endpoint network info")
+	}
+
+	units := make([]connectivityUnit, len(results))
+	machines := make([]names.MachineTag, len(results))
+	for i, result := range results {
+		unit := c.units[i]
+		if result.Error != nil {
+			return nil, nil, errors.Annotatef(result.Error, "cannot get network info for endpoint %q of unit %s", c.endpoint, unit)
+		}
+		if len(result.Info.IngressAddresses) == 0 {
+			return nil, nil, errors.Errorf("endpoint %q of unit %s has no ingress addresses", c.endpoint, unit)
+		}
+		machine, err := names.ParseMachineTag(result.MachineTag)
+		if err != nil {
+			return nil, nil, errors.Errorf("unit %s is not assigned to a machine", unit)
+		}
+		machines[i] = machine
+		units[i] = connectivityUnit{
+			Name:    unit,
+			Machine: machine.Id(),
+			Space:   result.SpaceName,
+			Address: result.Info.IngressAddresses[0],
+		}
+	}
+	return units, machines, nil
+}
+
+// pollInterval is how often the results of the actions run by
+// check-connectivity are fetched.
+const pollInterval = time.Second
+
+// runActions enqueues the given actions and waits for all of them to
+// complete, returning their output in the same order.
+func (c *CheckConnectivityCommand) runActions(api ConnectivityAPI, args []params.Action) ([]map[string]interface{}, error) {
+	enqueued, err := api.Enqueue(params.Actions{Actions: args})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(enqueued.Results) != len(args) {
+		return nil, errors.Errorf("expected %d results, got %d", len(args), len(enqueued.Results))
+	}
+	entities := make([]params.Entity, len(args))
+	for i, result := range enqueued.Results {
+		if result.Error != nil {
+			return nil, errors.Annotatef(result.Error, "cannot enqueue %s on %s", args[i].Name, machineName(args[i].Receiver))
+		}
+		if result.Action == nil {
+			return nil, errors.Errorf("cannot enqueue %s on %s: no action returned", args[i].Name, machineName(args[i].Receiver))
+		}
+		entities[i] = params.Entity{Tag: result.Action.Tag}
+	}
+
+	outputs := make([]map[string]interface{}, len(args))
+	deadline := c.clock.After(c.wait)
+	for {
+		results, err := api.Actions(params.Entities{Entities: entities})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(results.Results) != len(args) {
+			return nil, errors.Errorf("expected %d results, got %d", len(args), len(results.Results))
+		}
+		var pending []int
+		for i, result := range results.Results {
+			if result.Error != nil {
+				return nil, errors.Trace(result.Error)
+			}
+			switch result.Status {
+			case params.ActionCompleted:
+				outputs[i] = result.Output
+			case params.ActionPending, params.ActionRunning:
+				pending = append(pending, i)
+			default:
+				return nil, errors.Errorf("%s on %s %s: %s", args[i].Name, machineName(args[i].Receiver), result.Status, result.Message)
+			}
+		}
+		if len(pending) == 0 {
+			return outputs, nil
+		}
+		select {
+		case <-deadline:
+			i := pending[0]
+			return nil, errors.Errorf("timed out waiting for %s on %s", args[i].Name, machineName(args[i].Receiver))
+		case <-c.clock.After(pollInterval):
+		}
+	}
+}
+
+func machineName(tag string) string {
+	if machine, err := names.ParseMachineTag(tag); err == nil {
+		return "machine " + machine.Id()
+	}
+	return tag
+}
+
+// listenerPorts returns the TCP and UDP ports reported by a
+// juju-connectivity-listen action.
+func listenerPorts(output map[string]interface{}) ([2]int, error) {
+	var ports [2]int
+	for i, key := range []string{"tcp-port", "udp-port"} {
+		port, err := strconv.Atoi(outputString(output, key))
+		if err != nil {
+			return ports, errors.Errorf("invalid %s %q reported", key, output[key])
+		}
+		ports[i] = port
+	}
+	return ports, nil
+}
+
+func outputString(output map[string]interface{}, key string) string {
+	value, _ := output[key].(string)
+	return value
+}
+
+// buildConnectivityReport combines the endpoint network info of the
+// units with the results of the listen and probe actions run on their
+// machines.
+func buildConnectivityReport(
+	endpoint string,
+	units []connectivityUnit,
+	listens, probes []map[string]interface{},
+	ports [][2]int,
+) connectivityReport {
+	report := connectivityReport{
+		Endpoint: endpoint,
+		Units:    units,
+	}
+	for i := range units {
+		unit := &report.Units[i]
+		// The listener reports the interface the ingress address is on.
+		unit.Interface = outputString(listens[i], "interface")
+		unit.MTU, _ = strconv.Atoi(outputString(listens[i], "interface-mtu"))
+
+		target := units[1-i]
+		report.Checks = append(report.Checks, connectivityCheck{
+			From:    unit.Name,
+			To:      target.Name,
+			Address: target.Address,
+			TCP:     newProbeResult(probes[i], "tcp", ports[1-i][0]),
+			UDP:     newProbeResult(probes[i], "udp", ports[1-i][1]),
+		})
+	}
+	if a, b := report.Units[0], report.Units[1]; a.MTU != 0 && b.MTU != 0 && a.MTU != b.MTU {
+		report.Warnings = append(report.Warnings, fmt.Sprintf(
+			"MTU mismatch: %s has %d on %s, %s has %d on %s",
+			a.Name, a.MTU, a.Interface, b.Name, b.MTU, b.Interface,
+		))
+	}
+	return report
+}
+
+func newProbeResult(output map[string]interface{}, proto string, port int) probeResult {
+	return probeResult{
+		Port:    port,
+		Status:  outputString(output, proto),
+		Latency: outputString(output, proto+"-latency"),
+		Error:   outputString(output, proto+"-error"),
+	}
+}
+
+func (c *CheckConnectivityCommand) printTabular(writer io.Writer, value interface{}) error {
+	report, ok := value.(connectivityReport)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", report, value)
+	}
+	tw := output.TabWriter(writer)
+	_, _ = fmt.Fprintf(tw, "Endpoint\t%s\n\n", report.Endpoint)
+
+	_, _ = fmt.Fprintln(tw, "Unit\tMachine\tSpace\tAddress\tInterface\tMTU")
+	for _, unit := range report.Units {
+		mtu := ""
+		if unit.MTU != 0 {
+			mtu = strconv.Itoa(unit.MTU)
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			unit.Name, unit.Machine, unit.Space, unit.Address, unit.Interface, mtu)
+	}
+
+	_, _ = fmt.Fprintln(tw, "\nFrom\tTo\tProtocol\tPort\tStatus\tLatency\tMessage")
+	for _, check := range report.Checks {
+		for _, probe := range []struct {
+			proto  string
+			result probeResult
+		}{{"tcp", check.TCP}, {"udp", check.UDP}} {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%s\n",
+				check.From, check.To, probe.proto, probe.result.Port,
+				probe.result.Status, probe.result.Latency, probe.result.Error)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if len(report.Warnings) > 0 {
+		_, _ = fmt.Fprintln(writer)
+	}
+	for _, warning := range report.Warnings {
+		_, _ = fmt.Fprintf(writer, "Warning: %s\n", warning)
+	}
+	return nil
+}
+
+func (c *CheckConnectivityCommand) newAPI() (ConnectivityAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &connectivityAPIShim{
+		apiState: root,
+		spaces:   spaces.NewAPI(root),
+		Client:   action.NewClient(root),
+	}, nil
+}
+
+// connectivityAPIShim forwards ConnectivityAPI methods to the Spaces
+// and Action API facades.
+type connectivityAPIShim struct {
+	*action.Client
+
+	apiState api.Connection
+	spaces   *spaces.API
+}
+
+func (m *connectivityAPIShim) Close() error {
+	return m.apiState.Close()
+}
+
+func (m *connectivityAPIShim) UnitEndpointsNetworkInfo(endpoints []params.UnitEndpoint) ([]params.UnitEndpointNetworkInfoResult, error) {
+	return m.spaces.UnitEndpointsNetworkInfo(endpoints)
+}
@@ -0,0 +1,326 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/space"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ConnectivitySuite struct {
+	coretesting.FakeJujuXDGDataHomeSuite
+
+	api   *stubConnectivityAPI
+	clock *testclock.Clock
+}
+
+var _ = gc.Suite(&ConnectivitySuite{})
+
+func (s *ConnectivitySuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Now())
+	s.api = &stubConnectivityAPI{
+		Stub: &testing.Stub{},
+		netInfo: []params.UnitEndpointNetworkInfoResult{{
+			MachineTag: "machine-0",
+			SpaceName:  "dmz",
+			Info:       params.NetworkInfoResult{IngressAddresses: []string{"10.0.0.10"}},
+		}, {
+			MachineTag: "machine-1",
+			SpaceName:  "dmz",
+			Info:       params.NetworkInfoResult{IngressAddresses: []string{"10.0.1.20", "10.0.1.21"}},
+		}},
+		status: params.ActionCompleted,
+		outputs: map[string]map[string]interface{}{
+			"juju-connectivity-listen machine-0": {
+				"tcp-port": "40001", "udp-port": "40002", "interface": "eth0", "interface-mtu": "1500",
+			},
+			"juju-connectivity-listen machine-1": {
+				"tcp-port": "40011", "udp-port": "40012", "interface": "eth1", "interface-mtu": "1500",
+			},
+			"juju-connectivity-probe machine-0": {
+				"tcp": "ok", "tcp-latency": "1.2ms", "udp": "ok", "udp-latency": "1.5ms",
+				"interface": "eth0", "interface-mtu": "1500",
+			},
+			"juju-connectivity-probe machine-1": {
+				"tcp": "ok", "tcp-latency": "1.1ms", "udp": "ok", "udp-latency": "1.4ms",
+				"interface": "eth1", "interface-mtu": "1500",
+			},
+		},
+	}
+}
+
+func (s *ConnectivitySuite) run(c *gc.C, args ...string) (string, string, error) {
+	command := space.NewCheckConnectivityCommandForTest(s.api, s.clock)
+	ctx, err := cmdtesting.RunCommand(c, command, args...)
+	return cmdtesting.Stdout(ctx), cmdtesting.Stderr(ctx), err
+}
+
+func (s *ConnectivitySuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args      []string
+		expectErr string
+	}{{
+		args:      []string{"wordpress/0"},
+		expectErr: "two units are required",
+	}, {
+		args:      []string{"wordpress", "mysql/0", "--endpoint", "db"},
+		expectErr: `unit name "wordpress" not valid`,
+	}, {
+		args:      []string{"mysql/0", "mysql/0", "--endpoint", "db"},
+		expectErr: "cannot check the connectivity of a unit with itself",
+	}, {
+		args:      []string{"wordpress/0", "mysql/0"},
+		expectErr: "--endpoint is required",
+	}, {
+		args:      []string{"wordpress/0", "mysql/0", "--endpoint", "db", "--port", "70000"},
+		expectErr: "port 70000 not valid",
+	}, {
+		args:      []string{"wordpress/0", "mysql/0", "--endpoint", "db", "--port", "80"},
+		expectErr: "privileged port 80 not valid",
+	}, {
+		args:      []string{"wordpress/0", "mysql/0", "--endpoint", "db", "--timeout", "0s"},
+		expectErr: "timeout 0s not valid",
+	}, {
+		args:      []string{"wordpress/0", "mysql/0", "--endpoint", "db", "--wait", "10m"},
+		expectErr: "--wait plus twice --timeout must not exceed 10m0s",
+	}, {
+		args:      []string{"wordpress/0", "mysql/0", "mysql/1", "--endpoint", "db"},
+		expectErr: `unrecognized args: \["mysql/1"\]`,
+	}, {
+		args: []string{"wordpress/0", "mysql/0", "--endpoint", "db", "--port", "3306"},
+	}} {
+		c.Logf("test #%d: %v", i, test.args)
+		err := cmdtesting.InitCommand(space.NewCheckConnectivityCommandForTest(s.api, s.clock), test.args)
+		if test.expectErr == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.expectErr)
+		}
+	}
+}
+
+func (s *ConnectivitySuite) TestCheckConnectivity(c *gc.C) {
+	stdout, _, err := s.run(c, "wordpress/0", "mysql/0", "--endpoint", "db", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stdout, gc.Equals, `
+endpoint: db
+units:
+- name: wordpress/0
+  machine: "0"
+  space: dmz
+  ingress-address: 10.0.0.10
+  interface: eth0
+  interface-mtu: 1500
+- name: mysql/0
+  machine: "1"
+  space: dmz
+  ingress-address: 10.0.1.20
+  interface: eth1
+  interface-mtu: 1500
+checks:
+- from: wordpress/0
+  to: mysql/0
+  address: 10.0.1.20
+  tcp:
+    port: 40011
+    status: ok
+    latency: 1.2ms
+  udp:
+    port: 40012
+    status: ok
+    latency: 1.5ms
+- from: mysql/0
+  to: wordpress/0
+  address: 10.0.0.10
+  tcp:
+    port: 40001
+    status: ok
+    latency: 1.1ms
+  udp:
+    port: 40002
+    status: ok
+    latency: 1.4ms
+`[1:])
+
+	s.api.CheckCallNames(c, "UnitEndpointsNetworkInfo", "Enqueue", "Actions", "Enqueue", "Actions", "Close")
+	s.api.CheckCall(c, 0, "UnitEndpointsNetworkInfo", []params.UnitEndpoint{
+		{UnitTag: "unit-wordpress-0", Endpoint: "db"},
+		{UnitTag: "unit-mysql-0", Endpoint: "db"},
+	})
+	timeout := (time.Minute + 10*time.Second).Nanoseconds()
+	s.api.CheckCall(c, 1, "Enqueue", params.Actions{Actions: []params.Action{{
+		Receiver:   "machine-0",
+		Name:       "juju-connectivity-listen",
+		Parameters: map[string]interface{}{"address": "10.0.0.10", "port": 0, "timeout": timeout},
+	}, {
+		Receiver:   "machine-1",
+		Name:       "juju-connectivity-listen",
+		Parameters: map[string]interface{}{"address": "10.0.1.20", "port": 0, "timeout": timeout},
+	}}})
+	timeout = (5 * time.Second).Nanoseconds()
+	s.api.CheckCall(c, 3, "Enqueue", params.Actions{Actions: []params.Action{{
+		Receiver: "machine-0",
+		Name:     "juju-connectivity-probe",
+		Parameters: map[string]interface{}{
+			"address": "10.0.1.20", "tcp-port": 40011, "udp-port": 40012, "timeout": timeout,
+		},
+	}, {
+		Receiver: "machine-1",
+		Name:     "juju-connectivity-probe",
+		Parameters: map[string]interface{}{
+			"address": "10.0.0.10", "tcp-port": 40001, "udp-port": 40002, "timeout": timeout,
+		},
+	}}})
+}
+
+func (s *ConnectivitySuite) TestCheckConnectivityFailures(c *gc.C) {
+	s.api.outputs["juju-connectivity-listen machine-1"] = map[string]interface{}{
+		"tcp-port": "40011", "udp-port": "40012", "interface": "ens5", "interface-mtu": "9001",
+	}
+	s.api.outputs["juju-connectivity-probe machine-1"] = map[string]interface{}{
+		"tcp": "failed", "tcp-error": "connection refused",
+		"udp": "failed", "udp-error": "no response within 5s, traffic may be filtered",
+		"interface": "ens5", "interface-mtu": "9001",
+	}
+
+	stdout, _, err := s.run(c, "wordpress/0", "mysql/0", "--endpoint", "db")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(stdout, gc.Equals, `
+Endpoint  db
+
+Unit         Machine  Space  Address    Interface  MTU
+wordpress/0  0        dmz    10.0.0.10  eth0       1500
+mysql/0      1        dmz    10.0.1.20  ens5       9001
+
+From         To           Protocol  Port   Status  Latency  Message
+wordpress/0  mysql/0      tcp       40011  ok      1.2ms    
+wordpress/0  mysql/0      udp       40012  ok      1.5ms    
+mysql/0      wordpress/0  tcp       40001  failed           connection refused
+mysql/0      wordpress/0  udp       40002  failed           no response within 5s, traffic may be filtered
+
+Warning: MTU mismatch: wordpress/0 has 1500 on eth0, mysql/0 has 9001 on ens5
+`[1:])
+}
+
+func (s *ConnectivitySuite) TestEndpointError(c *gc.C) {
+	s.api.netInfo[1] = params.UnitEndpointNetworkInfoResult{
+		Error: &params.Error{Message: `endpoint "db" of unit "mysql/0" not found`, Code: params.CodeNotFound},
+	}
+	_, _, err := s.run(c, "wordpress/0", "mysql/0", "--endpoint", "db")
+	c.Assert(err, gc.ErrorMatches, `cannot get network info for endpoint "db" of unit mysql/0: endpoint "db" of unit "mysql/0" not found`)
+	s.api.CheckCallNames(c, "UnitEndpointsNetworkInfo", "Close")
+}
+
+func (s *ConnectivitySuite) TestNotAssigned(c *gc.C) {
+	s.api.netInfo[1].MachineTag = ""
+	_, _, err := s.run(c, "wordpress/0", "mysql/0", "--endpoint", "db")
+	c.Assert(err, gc.ErrorMatches, "unit mysql/0 is not assigned to a machine")
+}
+
+func (s *ConnectivitySuite) TestActionFailed(c *gc.C) {
+	s.api.status = params.ActionFailed
+	_, _, err := s.run(c, "wordpress/0", "mysql/0", "--endpoint", "db")
+	c.Assert(err, gc.ErrorMatches, "cannot open listeners: juju-connectivity-listen on machine 0 failed: boom")
+}
+
+func (s *ConnectivitySuite) TestEnqueueError(c *gc.C) {
+	s.api.SetErrors(nil, errors.New("boom"))
+	_, _, err := s.run(c, "wordpress/0", "mysql/0", "--endpoint", "db")
+	c.Assert(err, gc.ErrorMatches, "cannot open listeners: boom")
+}
+
+func (s *ConnectivitySuite) TestTimeout(c *gc.C) {
+	s.api.status = params.ActionPending
+
+	errc := make(chan error, 1)
+	go func() {
+		_, _, err := s.run(c, "wordpress/0", "mysql/0", "--endpoint", "db", "--wait", "10s")
+		errc <- err
+	}()
+	c.Assert(s.clock.WaitAdvance(10*time.Second, coretesting.LongWait, 2), jc.ErrorIsNil)
+
+	select {
+	case err := <-errc:
+		c.Assert(err, gc.ErrorMatches, "cannot open listeners: timed out waiting for juju-connectivity-listen on machine 0")
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for command to finish")
+	}
+}
+
+// stubConnectivityAPI is a testing stub for the ConnectivityAPI interface.
+type stubConnectivityAPI struct {
+	*testing.Stub
+
+	netInfo []params.UnitEndpointNetworkInfoResult
+	status  string
+	outputs map[string]map[string]interface{}
+	actions []params.Action
+}
+
+var _ space.ConnectivityAPI = (*stubConnectivityAPI)(nil)
+
+func (s *stubConnectivityAPI) Close() error {
+	s.MethodCall(s, "Close")
+	return s.NextErr()
+}
+
+func (s *stubConnectivityAPI) UnitEndpointsNetworkInfo(endpoints []params.UnitEndpoint) ([]params.UnitEndpointNetworkInfoResult, error) {
+	s.MethodCall(s, "UnitEndpointsNetworkInfo", endpoints)
+	return s.netInfo, s.NextErr()
+}
+
+func (s *stubConnectivityAPI) Enqueue(args params.Actions) (params.ActionResults, error) {
+	s.MethodCall(s, "Enqueue", args)
+	if err := s.NextErr(); err != nil {
+		return params.ActionResults{}, err
+	}
+	var results params.ActionResults
+	for _, action := range args.Actions {
+		action := action
+		action.Tag = fmt.Sprintf("action-%d", len(s.actions))
+		s.actions = append(s.actions, action)
+		results.Results = append(results.Results, params.ActionResult{
+			Action: &action,
+			Status: params.ActionPending,
+		})
+	}
+	return results, nil
+}
+
+func (s *stubConnectivityAPI) Actions(args params.Entities) (params.ActionResults, error) {
+	s.MethodCall(s, "Actions", args)
+	if err := s.NextErr(); err != nil {
+		return params.ActionResults{}, err
+	}
+	var results params.ActionResults
+	for _, entity := range args.Entities {
+		for _, action := range s.actions {
+			if action.Tag != entity.Tag {
+				continue
+			}
+			result := params.ActionResult{Status: s.status}
+			switch s.status {
+			case params.ActionCompleted:
+				result.Output = s.outputs[action.Name+" "+action.Receiver]
+			case params.ActionFailed:
+				result.Message = "boom"
+			}
+			results.Results = append(results.Results, result)
+		}
+	}
+	return results, nil
+}
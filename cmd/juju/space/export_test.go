@@ -3,6 +3,13 @@
 
 package space
 
+import (
+	"github.com/juju/clock"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+)
+
 func (base *SpaceCommandBase) SetAPI(api SpaceAPI) {
 	base.api = api
 }
@@ -14,3 +21,15 @@ func (c *RemoveCommand) Name() string {
 func (c *ListCommand) ListFormat() string {
 	return c.out.Name()
 }
+
+func NewApplyTopologyCommandForTest(api TopologyAPI) modelcmd.ModelCommand {
+	cmd := &ApplyTopologyCommand{api: api}
+	cmd.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(cmd)
+}
+
+func NewCheckConnectivityCommandForTest(api ConnectivityAPI, clock clock.Clock) modelcmd.ModelCommand {
+	cmd := &CheckConnectivityCommand{api: api, clock: clock}
+	cmd.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(cmd)
+}
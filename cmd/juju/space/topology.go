@@ -0,0 +1,490 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/spaces"
+	"github.com/juju/juju/api/subnets"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/network"
+)
+
+// TopologyAPI defines the API methods needed by the
+// apply-network-topology command.
+type TopologyAPI interface {
+	io.Closer
+
+	// ListSpaces returns all Juju network spaces and their subnets.
+	ListSpaces() ([]params.Space, error)
+
+	// AddSpace adds a new Juju network space, associating the
+	// specified subnets with it.
+	AddSpace(name string, subnetIds []string, public bool) error
+
+	// ListSubnets returns the subnets known to the model.
+	ListSubnets(spaceTag *names.SpaceTag, zone string) ([]params.Subnet, error)
+
+	// ListProviderSubnets returns the subnets discovered
+	// by the provider.
+	ListProviderSubnets() ([]params.Subnet, error)
+
+	// AddSubnet adds a subnet discovered by the
+	// provider to the model, in the given space.
+	AddSubnet(cidr string, providerId network.Id, space names.SpaceTag, zones []string) error
+
+	// MoveSubnets moves subnets already known to the model to
+	// the given space. Unless force is true, subnets are not moved
+	// out of spaces that are in use.
+	MoveSubnets(cidrs []string, space names.SpaceTag, force bool) error
+}
+
+// Topology describes the desired spaces of a model,
+// and the subnets in each of them.
+type Topology struct {
+	Spaces map[string]TopologySpace `yaml:"spaces"`
+}
+
+// TopologySpace describes a single space in a Topology.
+type TopologySpace struct {
+	Subnets []string `yaml:"subnets"`
+}
+
+// ParseTopology parses and validates the YAML network topology
+// in data. Every subnet must be listed in at most one space.
+func ParseTopology(data []byte) (*Topology, error) {
+	var topology Topology
+	if err := yaml.UnmarshalStrict(data, &topology); err != nil {
+		return nil, errors.Annotate(err, "parsing network topology")
+	}
+	if len(topology.Spaces) == 0 {
+		return nil, errors.New("network topology has no spaces")
+	}
+	spacesByCIDR := make(map[string]string)
+	for _, name := range topology.spaceNames() {
+		if _, err := CheckName(name); err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, cidr := range topology.Spaces[name].Subnets {
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return nil, errors.Errorf("%q is not a valid CIDR", cidr)
+			}
+			if ipNet.String() != cidr {
+				return nil, errors.Errorf("subnet %q should be specified as %q", cidr, ipNet.String())
+			}
+			if other, ok := spacesByCIDR[cidr]; ok {
+				return nil, errors.Errorf(
+					"subnet %q specified in both space %q and space %q",
+					cidr, other, name,
+				)
+			}
+			spacesByCIDR[cidr] = name
+		}
+	}
+	return &topology, nil
+}
+
+func (t *Topology) spaceNames() []string {
+	result := make([]string, 0, len(t.Spaces))
+	for name := range t.Spaces {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// topologyChangeKind identifies the kind of a topologyChange.
+type topologyChangeKind int
+
+const (
+	addSpaceChange topologyChangeKind = iota
+	addSubnetChange
+	moveSubnetChange
+)
+
+// topologyChange is a single change required to bring
+// the model in line with a network topology.
+type topologyChange struct {
+	kind  topologyChangeKind
+	space string
+
+	// subnet is the subnet added or moved.
+	// It is empty for addSpaceChange.
+	subnet params.Subnet
+
+	// fromSpace is the space the subnet
+	// is moved from, for moveSubnetChange.
+	fromSpace string
+}
+
+func (ch topologyChange) String() string {
+	switch ch.kind {
+	case addSpaceChange:
+		return fmt.Sprintf("add space %q", ch.space)
+	case addSubnetChange:
+		return fmt.Sprintf("add subnet %s to space %q", ch.subnet.CIDR, ch.space)
+	default:
+		return fmt.Sprintf("move subnet %s from space %q to space %q", ch.subnet.CIDR, ch.fromSpace, ch.space)
+	}
+}
+
+// topologyPlan holds the changes needed to apply a network topology
+// to a model, and any drift found between the model, the provider
+// and the topology that the changes will not resolve.
+type topologyPlan struct {
+	changes []topologyChange
+	drift   []string
+}
+
+// planTopology compares the topology with the spaces and subnets in
+// the model, and the subnets discovered by the provider, and returns
+// the plan for applying the topology. If providerSubnets is nil, the
+// provider's subnets are not known, and subnets missing from the model
+// are assumed to exist in the provider.
+//
+// Spaces and subnets are never removed: those in the model that are
+// not in the topology are reported as drift.
+func planTopology(
+	topology *Topology,
+	modelSpaces []params.Space,
+	modelSubnets []params.Subnet,
+	providerSubnets []params.Subnet,
+) (*topologyPlan, error) {
+	var plan topologyPlan
+
+	existingSpaces := set.NewStrings()
+	for _, space := range modelSpaces {
+		existingSpaces.Add(space.Name)
+	}
+	modelSubnetsByCIDR := make(map[string]params.Subnet)
+	for _, subnet := range modelSubnets {
+		modelSubnetsByCIDR[subnet.CIDR] = subnet
+	}
+	providerSubnetsByCIDR := make(map[string]params.Subnet)
+	for _, subnet := range providerSubnets {
+		if subnet.CIDR != "" {
+			providerSubnetsByCIDR[subnet.CIDR] = subnet
+		}
+	}
+
+	topologyCIDRs := set.NewStrings()
+	var addSubnets, moveSubnets []topologyChange
+	for _, name := range topology.spaceNames() {
+		if !existingSpaces.Contains(name) {
+			plan.changes = append(plan.changes, topologyChange{
+				kind:  addSpaceChange,
+				space: name,
+			})
+		}
+		for _, cidr := range topology.Spaces[name].Subnets {
+			topologyCIDRs.Add(cidr)
+			if subnet, ok := modelSubnetsByCIDR[cidr]; ok {
+				if fromSpace := subnetSpaceName(subnet); fromSpace != name {
+					moveSubnets = append(moveSubnets, topologyChange{
+						kind:      moveSubnetChange,
+						space:     name,
+						subnet:    subnet,
+						fromSpace: fromSpace,
+					})
+				}
+				continue
+			}
+			subnet, ok := providerSubnetsByCIDR[cidr]
+			if !ok && providerSubnets != nil {
+				return nil, errors.NotFoundf("subnet %q in the model or provider", cidr)
+			} else if !ok {
+				subnet = params.Subnet{CIDR: cidr}
+			}
+			addSubnets = append(addSubnets, topologyChange{
+				kind:   addSubnetChange,
+				space:  name,
+				subnet: subnet,
+			})
+		}
+	}
+	// Spaces must exist before subnets can be added to them.
+	plan.changes = append(plan.changes, addSubnets...)
+	plan.changes = append(plan.changes, moveSubnets...)
+
+	for _, space := range modelSpaces {
+		if space.Name == network.AlphaSpaceName {
+			continue
+		}
+		if _, ok := topology.Spaces[space.Name]; !ok {
+			plan.drift = append(plan.drift, fmt.Sprintf(
+				"space %q is in the model but not in the topology", space.Name,
+			))
+		}
+	}
+	for _, subnet := range modelSubnets {
+		if !topologyCIDRs.Contains(subnet.CIDR) {
+			plan.drift = append(plan.drift, fmt.Sprintf(
+				"subnet %s in space %q is in the model but not in the topology",
+				subnet.CIDR, subnetSpaceName(subnet),
+			))
+		}
+		if _, ok := providerSubnetsByCIDR[subnet.CIDR]; !ok && providerSubnets != nil {
+			plan.drift = append(plan.drift, fmt.Sprintf(
+				"subnet %s is in the model but was not discovered by the provider",
+				subnet.CIDR,
+			))
+		}
+	}
+	for _, subnet := range providerSubnets {
+		if subnet.CIDR == "" {
+			continue
+		}
+		_, inModel := modelSubnetsByCIDR[subnet.CIDR]
+		if !inModel && !topologyCIDRs.Contains(subnet.CIDR) {
+			plan.drift = append(plan.drift, fmt.Sprintf(
+				"subnet %s was discovered by the provider but is not in the model or the topology",
+				subnet.CIDR,
+			))
+		}
+	}
+	return &plan, nil
+}
+
+// subnetSpaceName returns the name of the space the subnet is in.
+func subnetSpaceName(subnet params.Subnet) string {
+	tag, err := names.ParseSpaceTag(subnet.SpaceTag)
+	if err != nil {
+		return ""
+	}
+	return tag.Id()
+}
+
+// applyTopologyChanges makes the changes in the plan, in order,
+// stopping at the first failure.
+func applyTopologyChanges(ctx *cmd.Context, api TopologyAPI, changes []topologyChange, force bool) error {
+	for _, ch := range changes {
+		var err error
+		switch ch.kind {
+		case addSpaceChange:
+			err = api.AddSpace(ch.space, nil, true)
+		case addSubnetChange:
+			// The provider ID, when known, identifies the
+			// subnet even if its CIDR is not unique.
+			err = api.AddSubnet(
+				ch.subnet.CIDR, network.Id(ch.subnet.ProviderId),
+				names.NewSpaceTag(ch.space), nil,
+			)
+		case moveSubnetChange:
+			err = api.MoveSubnets([]string{ch.subnet.CIDR}, names.NewSpaceTag(ch.space), force)
+		}
+		if err != nil {
+			return errors.Annotatef(err, "cannot %s", ch)
+		}
+		ctx.Infof("%s", upperFirst(ch.String()))
+	}
+	return nil
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// NewApplyTopologyCommand returns a command used to apply
+// a network topology to a model.
+func NewApplyTopologyCommand() modelcmd.ModelCommand {
+	return modelcmd.Wrap(&ApplyTopologyCommand{})
+}
+
+// ApplyTopologyCommand brings the spaces and subnets of a model
+// in line with those described in a network topology file.
+type ApplyTopologyCommand struct {
+	modelcmd.ModelCommandBase
+	modelcmd.IAASOnlyCommand
+
+	api TopologyAPI
+
+	filename string
+	dryRun   bool
+	force    bool
+}
+
+const applyTopologyCommandDoc = `
+Applies the spaces and subnets described in a network topology file
+to the model, adding the spaces that do not exist, adding subnets
+discovered by the provider to their spaces, and moving subnets that
+are in a different space.
+
+The topology file is YAML, listing the subnets in each space by CIDR:
+
+    spaces:
+      db:
+        subnets:
+          - 10.0.1.0/24
+          - 10.0.2.0/24
+      public:
+        subnets:
+          - 10.0.0.0/24
+
+Spaces and subnets are never removed. Any found in the model but not
+in the topology are reported as drift, as are differences between
+the subnets in the model and those discovered by the provider.
+
+Subnets are not moved out of a space that applications are bound to,
+or that constraints refer to, unless --force is specified.
+
+With --dry-run, the changes and drift are reported, but the changes
+are not made.
+
+Examples:
+
+    juju apply-network-topology topology.yaml
+    juju apply-network-topology --dry-run topology.yaml
+    juju apply-network-topology --force topology.yaml
+
+See also:
+    spaces
+    subnets
+    add-space
+    add-subnet
+    reload-spaces
+`
+
+// Info is defined on the cmd.Command interface.
+func (c *ApplyTopologyCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "apply-network-topology",
+		Args:    "<topology file>",
+		Purpose: "Apply a network topology of spaces and subnets to the model.",
+		Doc:     strings.TrimSpace(applyTopologyCommandDoc),
+	})
+}
+
+// SetFlags is defined on the cmd.Command interface.
+func (c *ApplyTopologyCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.dryRun, "dry-run", false, "Report the changes and drift without making any changes")
+	f.BoolVar(&c.force, "force", false, "Move subnets out of spaces that are in use")
+}
+
+// Init is defined on the cmd.Command interface.
+func (c *ApplyTopologyCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("network topology file is required")
+	}
+	c.filename = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *ApplyTopologyCommand) Run(ctx *cmd.Context) error {
+	data, err := ioutil.ReadFile(ctx.AbsPath(c.filename))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	topology, err := ParseTopology(data)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	api, err := c.newAPI()
+	if err != nil {
+		return errors.Annotate(err, "cannot connect to the API server")
+	}
+	defer api.Close()
+
+	modelSpaces, err := api.ListSpaces()
+	if err != nil {
+		return errors.Annotate(err, "cannot list spaces")
+	}
+	modelSubnets, err := api.ListSubnets(nil, "")
+	if err != nil {
+		return errors.Annotate(err, "cannot list subnets")
+	}
+	providerSubnets, err := api.ListProviderSubnets()
+	if errors.IsNotSupported(err) {
+		ctx.Warningf("cannot list provider subnets, drift will not be reported: %v", err)
+	} else if err != nil {
+		return errors.Annotate(err, "cannot list provider subnets")
+	} else if providerSubnets == nil {
+		providerSubnets = []params.Subnet{}
+	}
+
+	plan, err := planTopology(topology, modelSpaces, modelSubnets, providerSubnets)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, drift := range plan.drift {
+		fmt.Fprintf(ctx.Stdout, "drift: %s\n", drift)
+	}
+	if len(plan.changes) == 0 {
+		ctx.Infof("Network topology is up to date")
+		return nil
+	}
+	if c.dryRun {
+		for _, ch := range plan.changes {
+			fmt.Fprintf(ctx.Stdout, "would %s\n", ch)
+		}
+		return nil
+	}
+	if err := applyTopologyChanges(ctx, api, plan.changes, c.force); err != nil {
+		if params.IsCodeUnauthorized(err) {
+			common.PermissionsMessage(ctx.Stderr, "apply a network topology")
+		}
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	return nil
+}
+
+func (c *ApplyTopologyCommand) newAPI() (TopologyAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &topologyAPIShim{
+		apiState: root,
+		spaces:   spaces.NewAPI(root),
+		API:      subnets.NewAPI(root),
+	}, nil
+}
+
+// topologyAPIShim forwards TopologyAPI methods to the
+// Spaces and Subnets API facades.
+type topologyAPIShim struct {
+	*subnets.API
+
+	apiState api.Connection
+	spaces   *spaces.API
+}
+
+func (m *topologyAPIShim) Close() error {
+	return m.apiState.Close()
+}
+
+func (m *topologyAPIShim) ListSpaces() ([]params.Space, error) {
+	return m.spaces.ListSpaces()
+}
+
+func (m *topologyAPIShim) AddSpace(name string, subnetIds []string, public bool) error {
+	return m.spaces.CreateSpace(name, subnetIds, public)
+}
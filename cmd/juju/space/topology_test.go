@@ -0,0 +1,295 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/space"
+	"github.com/juju/juju/core/network"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type TopologySuite struct {
+	coretesting.FakeJujuXDGDataHomeSuite
+
+	api      *stubTopologyAPI
+	filename string
+}
+
+var _ = gc.Suite(&TopologySuite{})
+
+const testTopology = `
+spaces:
+  db:
+    subnets:
+      - 10.0.1.0/24
+      - 10.0.2.0/24
+  public:
+    subnets:
+      - 10.0.3.0/24
+      - 10.0.9.0/24
+`
+
+func (s *TopologySuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = &stubTopologyAPI{
+		Stub: &testing.Stub{},
+		spaces: []params.Space{
+			{Id: "0", Name: network.AlphaSpaceName},
+			{Id: "1", Name: "db"},
+			{Id: "2", Name: "legacy"},
+		},
+		subnets: []params.Subnet{
+			{CIDR: "10.0.1.0/24", SpaceTag: "space-db"},
+			{CIDR: "10.0.2.0/24", SpaceTag: "space-alpha"},
+			{CIDR: "10.0.9.0/24", SpaceTag: "space-legacy"},
+			{CIDR: "10.0.7.0/24", SpaceTag: "space-alpha"},
+		},
+		providerSubnets: []params.Subnet{
+			{CIDR: "10.0.1.0/24", ProviderId: "sn-1"},
+			{CIDR: "10.0.2.0/24", ProviderId: "sn-2"},
+			{CIDR: "10.0.3.0/24", ProviderId: "sn-3"},
+			{CIDR: "10.0.9.0/24", ProviderId: "sn-9"},
+			{CIDR: "10.0.8.0/24", ProviderId: "sn-8"},
+		},
+	}
+	s.filename = s.writeTopology(c, testTopology)
+}
+
+func (s *TopologySuite) writeTopology(c *gc.C, content string) string {
+	filename := filepath.Join(c.MkDir(), "topology.yaml")
+	err := ioutil.WriteFile(filename, []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return filename
+}
+
+func (s *TopologySuite) run(c *gc.C, args ...string) (string, string, error) {
+	ctx, err := cmdtesting.RunCommand(c, space.NewApplyTopologyCommandForTest(s.api), args...)
+	return cmdtesting.Stdout(ctx), cmdtesting.Stderr(ctx), err
+}
+
+const expectedDrift = `
+drift: space "legacy" is in the model but not in the topology
+drift: subnet 10.0.7.0/24 in space "alpha" is in the model but not in the topology
+drift: subnet 10.0.7.0/24 is in the model but was not discovered by the provider
+drift: subnet 10.0.8.0/24 was discovered by the provider but is not in the model or the topology
+`[1:]
+
+func (s *TopologySuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args      []string
+		expectErr string
+	}{{
+		expectErr: "network topology file is required",
+	}, {
+		args:      []string{"a.yaml", "b.yaml"},
+		expectErr: `unrecognized args: \["b.yaml"\]`,
+	}, {
+		args: []string{"--dry-run", "a.yaml"},
+	}, {
+		args: []string{"--force", "a.yaml"},
+	}} {
+		c.Logf("test #%d: %v", i, test.args)
+		err := cmdtesting.InitCommand(space.NewApplyTopologyCommandForTest(s.api), test.args)
+		if test.expectErr == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.expectErr)
+		}
+	}
+}
+
+func (s *TopologySuite) TestParseTopology(c *gc.C) {
+	for i, test := range []struct {
+		content   string
+		expectErr string
+	}{{
+		content:   "",
+		expectErr: "network topology has no spaces",
+	}, {
+		content:   "spaces:\n  db:\n    cidrs: [10.0.0.0/24]\n",
+		expectErr: "parsing network topology: .*field cidrs not found.*",
+	}, {
+		content:   "spaces:\n  Bad_Name:\n    subnets: []\n",
+		expectErr: `"Bad_Name" is not a valid space name`,
+	}, {
+		content:   "spaces:\n  db:\n    subnets: [foo]\n",
+		expectErr: `"foo" is not a valid CIDR`,
+	}, {
+		content:   "spaces:\n  db:\n    subnets: [10.0.0.1/24]\n",
+		expectErr: `subnet "10.0.0.1/24" should be specified as "10.0.0.0/24"`,
+	}, {
+		content:   "spaces:\n  db:\n    subnets: [10.0.0.0/24]\n  web:\n    subnets: [10.0.0.0/24]\n",
+		expectErr: `subnet "10.0.0.0/24" specified in both space "db" and space "web"`,
+	}} {
+		c.Logf("test #%d", i)
+		_, err := space.ParseTopology([]byte(test.content))
+		c.Check(err, gc.ErrorMatches, test.expectErr)
+	}
+
+	topology, err := space.ParseTopology([]byte(testTopology))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(topology, jc.DeepEquals, &space.Topology{
+		Spaces: map[string]space.TopologySpace{
+			"db":     {Subnets: []string{"10.0.1.0/24", "10.0.2.0/24"}},
+			"public": {Subnets: []string{"10.0.3.0/24", "10.0.9.0/24"}},
+		},
+	})
+}
+
+func (s *TopologySuite) TestDryRun(c *gc.C) {
+	stdout, _, err := s.run(c, "--dry-run", s.filename)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stdout, gc.Equals, expectedDrift+`
+would add space "public"
+would add subnet 10.0.3.0/24 to space "public"
+would move subnet 10.0.2.0/24 from space "alpha" to space "db"
+would move subnet 10.0.9.0/24 from space "legacy" to space "public"
+`[1:])
+	s.api.CheckCallNames(c, "ListSpaces", "ListSubnets", "ListProviderSubnets", "Close")
+}
+
+func (s *TopologySuite) TestApply(c *gc.C) {
+	stdout, stderr, err := s.run(c, s.filename)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stdout, gc.Equals, expectedDrift)
+	c.Assert(stderr, gc.Equals, `
+Add space "public"
+Add subnet 10.0.3.0/24 to space "public"
+Move subnet 10.0.2.0/24 from space "alpha" to space "db"
+Move subnet 10.0.9.0/24 from space "legacy" to space "public"
+`[1:])
+	s.api.CheckCalls(c, []testing.StubCall{
+		{FuncName: "ListSpaces", Args: nil},
+		{FuncName: "ListSubnets", Args: []interface{}{(*names.SpaceTag)(nil), ""}},
+		{FuncName: "ListProviderSubnets", Args: nil},
+		{FuncName: "AddSpace", Args: []interface{}{"public", []string(nil), true}},
+		{FuncName: "AddSubnet", Args: []interface{}{"10.0.3.0/24", network.Id("sn-3"), names.NewSpaceTag("public"), []string(nil)}},
+		{FuncName: "MoveSubnets", Args: []interface{}{[]string{"10.0.2.0/24"}, names.NewSpaceTag("db"), false}},
+		{FuncName: "MoveSubnets", Args: []interface{}{[]string{"10.0.9.0/24"}, names.NewSpaceTag("public"), false}},
+		{FuncName: "Close", Args: nil},
+	})
+}
+
+func (s *TopologySuite) TestApplyForce(c *gc.C) {
+	_, _, err := s.run(c, "--force", s.filename)
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCall(c, 5, "MoveSubnets", []string{"10.0.2.0/24"}, names.NewSpaceTag("db"), true)
+	s.api.CheckCall(c, 6, "MoveSubnets", []string{"10.0.9.0/24"}, names.NewSpaceTag("public"), true)
+}
+
+func (s *TopologySuite) TestApplyFails(c *gc.C) {
+	s.api.SetErrors(nil, nil, nil, nil, errors.New("boom"))
+	_, _, err := s.run(c, s.filename)
+	c.Assert(err, gc.ErrorMatches, `cannot add subnet 10.0.3.0/24 to space "public": boom`)
+	s.api.CheckCallNames(c, "ListSpaces", "ListSubnets", "ListProviderSubnets", "AddSpace", "AddSubnet", "Close")
+}
+
+func (s *TopologySuite) TestUpToDate(c *gc.C) {
+	filename := s.writeTopology(c, `
+spaces:
+  db:
+    subnets:
+      - 10.0.1.0/24
+`)
+	s.api.spaces = s.api.spaces[:2]
+	s.api.subnets = s.api.subnets[:1]
+	s.api.providerSubnets = s.api.providerSubnets[:1]
+
+	stdout, stderr, err := s.run(c, filename)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stdout, gc.Equals, "")
+	c.Assert(stderr, gc.Equals, "Network topology is up to date\n")
+}
+
+func (s *TopologySuite) TestUnknownSubnet(c *gc.C) {
+	filename := s.writeTopology(c, `
+spaces:
+  db:
+    subnets:
+      - 192.168.0.0/24
+`)
+	_, _, err := s.run(c, filename)
+	c.Assert(err, gc.ErrorMatches, `subnet "192.168.0.0/24" in the model or provider not found`)
+}
+
+func (s *TopologySuite) TestProviderSubnetsNotSupported(c *gc.C) {
+	s.api.SetErrors(nil, nil, errors.NotSupportedf("listing provider subnets"))
+	filename := s.writeTopology(c, `
+spaces:
+  db:
+    subnets:
+      - 10.0.1.0/24
+      - 192.168.0.0/24
+`)
+	stdout, stderr, err := s.run(c, "--dry-run", filename)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stdout, gc.Equals, `
+drift: space "legacy" is in the model but not in the topology
+drift: subnet 10.0.2.0/24 in space "alpha" is in the model but not in the topology
+drift: subnet 10.0.9.0/24 in space "legacy" is in the model but not in the topology
+drift: subnet 10.0.7.0/24 in space "alpha" is in the model but not in the topology
+would add subnet 192.168.0.0/24 to space "db"
+`[1:])
+	c.Assert(stderr, gc.Matches, ".*cannot list provider subnets, drift will not be reported: listing provider subnets not supported\n")
+}
+
+// stubTopologyAPI is a testing stub for the TopologyAPI interface.
+type stubTopologyAPI struct {
+	*testing.Stub
+
+	spaces          []params.Space
+	subnets         []params.Subnet
+	providerSubnets []params.Subnet
+}
+
+var _ space.TopologyAPI = (*stubTopologyAPI)(nil)
+
+func (s *stubTopologyAPI) Close() error {
+	s.MethodCall(s, "Close")
+	return s.NextErr()
+}
+
+func (s *stubTopologyAPI) ListSpaces() ([]params.Space, error) {
+	s.MethodCall(s, "ListSpaces")
+	return s.spaces, s.NextErr()
+}
+
+func (s *stubTopologyAPI) AddSpace(name string, subnetIds []string, public bool) error {
+	s.MethodCall(s, "AddSpace", name, subnetIds, public)
+	return s.NextErr()
+}
+
+func (s *stubTopologyAPI) ListSubnets(spaceTag *names.SpaceTag, zone string) ([]params.Subnet, error) {
+	s.MethodCall(s, "ListSubnets", spaceTag, zone)
+	return s.subnets, s.NextErr()
+}
+
+func (s *stubTopologyAPI) ListProviderSubnets() ([]params.Subnet, error) {
+	s.MethodCall(s, "ListProviderSubnets")
+	if err := s.NextErr(); err != nil {
+		return nil, err
+	}
+	return s.providerSubnets, nil
+}
+
+func (s *stubTopologyAPI) AddSubnet(cidr string, providerId network.Id, space names.SpaceTag, zones []string) error {
+	s.MethodCall(s, "AddSubnet", cidr, providerId, space, zones)
+	return s.NextErr()
+}
+
+func (s *stubTopologyAPI) MoveSubnets(cidrs []string, space names.SpaceTag, force bool) error {
+	s.MethodCall(s, "MoveSubnets", cidrs, space, force)
+	return s.NextErr()
+}
@@ -134,6 +134,8 @@ type applicationStatus struct {
 	SubordinateTo    []string              `json:"subordinate-to,omitempty" yaml:"subordinate-to,omitempty"`
 	Units            map[string]unitStatus `json:"units,omitempty" yaml:"units,omitempty"`
 	Version          string                `json:"version,omitempty" yaml:"version,omitempty"`
+	VersionSkew      map[string][]string   `json:"version-skew,omitempty" yaml:"version-skew,omitempty"`
+	UnitStatus       string                `json:"unit-status-summary,omitempty" yaml:"unit-status-summary,omitempty"`
 	EndpointBindings map[string]string     `json:"endpoint-bindings,omitempty" yaml:"endpoint-bindings,omitempty"`
 }
 
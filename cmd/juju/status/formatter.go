@@ -277,6 +277,8 @@ func (sf *statusFormatter) formatApplication(name string, application params.App
 		Units:            make(map[string]unitStatus),
 		StatusInfo:       sf.getApplicationStatusInfo(application),
 		Version:          application.WorkloadVersion,
+		VersionSkew:      application.WorkloadVersions,
+		UnitStatus:       application.UnitStatusSummary,
 		EndpointBindings: application.EndpointBindings,
 	}
 
@@ -151,17 +151,22 @@ func printApplications(tw *ansiterm.TabWriter, fs formattedStatus) {
 		if len(version) > maxVersionWidth {
 			version = version[:truncatedWidth] + ellipsis
 		}
-		// Notes may well contain other things later.
-		notes := ""
+		var notes []string
 		if app.Exposed {
-			notes = "exposed"
+			notes = append(notes, "exposed")
 		}
 		// Expose any operator messages.
 		if fs.Model.Type == caasModelType {
 			if app.StatusInfo.Message != "" {
-				notes = app.StatusInfo.Message
+				notes = []string{app.StatusInfo.Message}
 			}
 		}
+		if app.UnitStatus != "" {
+			notes = append(notes, app.UnitStatus)
+		}
+		if len(app.VersionSkew) > 0 {
+			notes = append(notes, fmt.Sprintf("%d workload versions", len(app.VersionSkew)))
+		}
 		w.Print(appName, version)
 		w.PrintStatus(app.StatusInfo.Current)
 		scale, warn := fs.applicationScale(appName)
@@ -179,7 +184,7 @@ func printApplications(tw *ansiterm.TabWriter, fs formattedStatus) {
 			w.Print(app.Address)
 		}
 
-		w.Println(notes)
+		w.Println(strings.Join(notes, "; "))
 		for un, u := range app.Units {
 			units[un] = u
 			if u.MeterStatus != nil {
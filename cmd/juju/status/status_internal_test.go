@@ -3196,6 +3196,10 @@ var statusTests = []testCase{
 				"applications": M{
 					"mysql": mysqlCharm(M{
 						"version": "not as good",
+						"version-skew": M{
+							"the best!":   L{"mysql/0"},
+							"not as good": L{"mysql/1"},
+						},
 						"application-status": M{
 							"current": "waiting",
 							"message": "waiting for machine",
@@ -5045,7 +5049,7 @@ hosted-riak  unknown  local  me/model.riak
 
 App        Version          Status       Scale  Charm      Store       Rev  OS      Notes
 logging    a bit too lo...  error            2  logging    jujucharms    1  ubuntu  exposed
-mysql      5.7.13           maintenance    1/2  mysql      jujucharms    1  ubuntu  exposed
+mysql      5.7.13           maintenance    1/2  mysql      jujucharms    1  ubuntu  exposed; 1/2 maintenance, 1 terminated
 wordpress  4.5.3            active           1  wordpress  jujucharms    3  ubuntu  exposed
 
 Unit          Workload     Agent  Machine  Public address  Ports  Message
@@ -5288,6 +5292,57 @@ foo/0  waiting   idle                            80/TCP
 `[1:])
 }
 
+func (s *StatusSuite) TestFormatTabularStatusNotesUnitSummaryAndVersionSkew(c *gc.C) {
+	status := formattedStatus{
+		Applications: map[string]applicationStatus{
+			"foo": {
+				Exposed: true,
+				Version: "1.1",
+				StatusInfo: statusInfoContents{
+					Current: status.Maintenance,
+				},
+				UnitStatus: "1/2 active, 1 maintenance",
+				VersionSkew: map[string][]string{
+					"1.0": {"foo/0"},
+					"1.1": {"foo/1"},
+				},
+				Units: map[string]unitStatus{
+					"foo/0": {
+						JujuStatusInfo: statusInfoContents{
+							Current: status.Idle,
+						},
+						WorkloadStatusInfo: statusInfoContents{
+							Current: status.Active,
+						},
+					},
+					"foo/1": {
+						JujuStatusInfo: statusInfoContents{
+							Current: status.Executing,
+						},
+						WorkloadStatusInfo: statusInfoContents{
+							Current: status.Maintenance,
+						},
+					},
+				},
+			},
+		},
+	}
+	out := &bytes.Buffer{}
+	err := FormatTabular(out, false, status)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.String(), gc.Equals, `
+Model  Controller  Cloud/Region  Version
+                                 
+
+App  Version  Status       Scale  Charm  Store  Rev  OS  Notes
+foo  1.1      maintenance      2                  0      exposed; 1/2 active, 1 maintenance; 2 workload versions
+
+Unit   Workload     Agent      Machine  Public address  Ports  Message
+foo/0  active       idle                                       
+foo/1  maintenance  executing                                  
+`[1:])
+}
+
 func (s *StatusSuite) TestStatusWithNilStatusAPI(c *gc.C) {
 	ctx := s.newContext(c)
 	defer s.resetContext(c, ctx)
@@ -54,9 +54,20 @@ type FilesystemAttachments struct {
 }
 
 type FilesystemAttachment struct {
-	MountPoint string `yaml:"mount-point" json:"mount-point"`
-	ReadOnly   bool   `yaml:"read-only" json:"read-only"`
-	Life       string `yaml:"life,omitempty" json:"life,omitempty"`
+	MountPoint string           `yaml:"mount-point" json:"mount-point"`
+	ReadOnly   bool             `yaml:"read-only" json:"read-only"`
+	Life       string           `yaml:"life,omitempty" json:"life,omitempty"`
+	Usage      *FilesystemUsage `yaml:"usage,omitempty" json:"usage,omitempty"`
+}
+
+// FilesystemUsage defines the serialization behaviour for the usage
+// of an attached filesystem, as last reported by the machine agent.
+type FilesystemUsage struct {
+	UsedBytes  uint64 `yaml:"used-bytes" json:"used-bytes"`
+	FreeBytes  uint64 `yaml:"free-bytes" json:"free-bytes"`
+	UsedInodes uint64 `yaml:"used-inodes" json:"used-inodes"`
+	FreeInodes uint64 `yaml:"free-inodes" json:"free-inodes"`
+	Updated    string `yaml:"updated,omitempty" json:"updated,omitempty"`
 }
 
 // generateListFilesystemOutput returns a map filesystem IDs to filesystem info
@@ -132,9 +143,10 @@ func createFilesystemInfo(details params.FilesystemDetails) (names.FilesystemTag
 				return errors.Trace(err)
 			}
 			out[id] = FilesystemAttachment{
-				attachment.MountPoint,
-				attachment.ReadOnly,
-				string(attachment.Life),
+				MountPoint: attachment.MountPoint,
+				ReadOnly:   attachment.ReadOnly,
+				Life:       string(attachment.Life),
+				Usage:      filesystemUsageFromDetails(attachment.Usage),
 			}
 		}
 		return nil
@@ -176,3 +188,19 @@ func createFilesystemInfo(details params.FilesystemDetails) (names.FilesystemTag
 
 	return filesystemTag, info, nil
 }
+
+func filesystemUsageFromDetails(usage *params.FilesystemUsage) *FilesystemUsage {
+	if usage == nil {
+		return nil
+	}
+	result := &FilesystemUsage{
+		UsedBytes:  usage.UsedBytes,
+		FreeBytes:  usage.FreeBytes,
+		UsedInodes: usage.UsedInodes,
+		FreeInodes: usage.FreeInodes,
+	}
+	if usage.Updated != nil {
+		result.Updated = common.FormatTime(usage.Updated, false)
+	}
+	return result
+}
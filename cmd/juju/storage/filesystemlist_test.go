@@ -5,6 +5,7 @@ package storage_test
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
@@ -14,6 +15,7 @@ import (
 	goyaml "gopkg.in/yaml.v2"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/juju/storage"
 	"github.com/juju/juju/core/status"
 )
@@ -70,6 +72,50 @@ func (s *ListSuite) TestFilesystemListJSON(c *gc.C) {
 		"--format", "json")
 }
 
+func (s *ListSuite) TestFilesystemListYamlUsage(c *gc.C) {
+	updated := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	s.mockAPI.listFilesystems = func([]string) ([]params.FilesystemDetailsListResult, error) {
+		return []params.FilesystemDetailsListResult{{Result: []params.FilesystemDetails{{
+			FilesystemTag: "filesystem-0-0",
+			Info:          params.FilesystemInfo{Size: 512},
+			Life:          "alive",
+			Status:        createTestStatus(status.Attached, "filesystem space usage exceeds 90%", s.mockAPI.time),
+			MachineAttachments: map[string]params.FilesystemAttachmentDetails{
+				"machine-0": {
+					FilesystemAttachmentInfo: params.FilesystemAttachmentInfo{
+						MountPoint: "/srv",
+					},
+					Life: "alive",
+					Usage: &params.FilesystemUsage{
+						UsedBytes:  460,
+						FreeBytes:  52,
+						UsedInodes: 10,
+						FreeInodes: 90,
+						Updated:    &updated,
+					},
+				},
+			},
+		}}}}, nil
+	}
+	context, err := s.runFilesystemList(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+
+	var result struct {
+		Filesystems map[string]storage.FilesystemInfo
+	}
+	err = goyaml.Unmarshal([]byte(cmdtesting.Stdout(context)), &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Filesystems["0/0"].Attachments, gc.NotNil)
+	c.Assert(result.Filesystems["0/0"].Attachments.Machines["0"].Usage, jc.DeepEquals, &storage.FilesystemUsage{
+		UsedBytes:  460,
+		FreeBytes:  52,
+		UsedInodes: 10,
+		FreeInodes: 90,
+		Updated:    common.FormatTime(&updated, false),
+	})
+	c.Assert(result.Filesystems["0/0"].Status.Message, gc.Equals, "filesystem space usage exceeds 90%")
+}
+
 func (s *ListSuite) TestFilesystemListWithErrorResults(c *gc.C) {
 	s.mockAPI.listFilesystems = func([]string) ([]params.FilesystemDetailsListResult, error) {
 		var emptyMockAPI mockListAPI
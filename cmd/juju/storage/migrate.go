@@ -0,0 +1,295 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"io"
+	"sort"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+// NewMigrateStorageCommand returns a command used to migrate storage
+// instances between storage pools.
+func NewMigrateStorageCommand() cmd.Command {
+	cmd := &migrateStorageCommand{}
+	cmd.newAPIFunc = func() (StorageMigrationAPI, error) {
+		return cmd.NewStorageAPI()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+const (
+	migrateStorageCommandDoc = `
+Migrates a storage instance to a new volume in another storage pool,
+for example to move data from a loop device onto a cloud volume, or
+between volume types.
+
+A new volume is provisioned in the specified pool and attached to the
+machine that the storage's current volume is attached to. The unit
+using the storage first runs its storage-detaching hook, so that the
+charm stops writing to the storage. Once it has done so and both
+volumes are visible on the machine, the machine agent copies the data
+block for block from the old volume to the new one, and the storage
+instance is then switched over to the new volume. The unit runs its
+storage-attached hook against the new volume, after which the old
+volume is detached and destroyed. The unit continues to see the
+storage at its existing storage ID.
+
+Only block storage attached to a single machine may be migrated. If
+the copy fails, the storage remains on its original volume, the new
+volume is destroyed, and the unit runs its storage-attached hook
+against the original volume again.
+
+Use "juju storage-migrations" to follow the progress of migrations,
+and to see the pools that storage has been migrated between.
+
+Examples:
+    juju migrate-storage pgdata/0 --pool ebs-ssd
+
+See also:
+    storage-migrations
+    storage
+    storage-pools
+`
+
+	migrateStorageCommandArgs = `<storage ID> --pool <pool>`
+)
+
+// StorageMigrationAPI defines the API methods that the storage
+// migration commands use.
+type StorageMigrationAPI interface {
+	Close() error
+	MigrateStorage(storageIds []string, pool string) ([]params.StorageMigrationResult, error)
+	ListStorageMigrations() ([]params.StorageMigrationDetails, error)
+}
+
+// migrateStorageCommand migrates a storage instance to another pool.
+type migrateStorageCommand struct {
+	StorageCommandBase
+	modelcmd.IAASOnlyCommand
+	newAPIFunc func() (StorageMigrationAPI, error)
+
+	storageId string
+	pool      string
+}
+
+// Info implements Command.Info.
+func (c *migrateStorageCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "migrate-storage",
+		Purpose: "Migrates storage to another storage pool.",
+		Doc:     migrateStorageCommandDoc,
+		Args:    migrateStorageCommandArgs,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *migrateStorageCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.StorageCommandBase.SetFlags(f)
+	f.StringVar(&c.pool, "pool", "", "The storage pool to migrate the storage to")
+}
+
+// Init implements Command.Init.
+func (c *migrateStorageCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("migrate-storage requires a storage ID")
+	}
+	if c.pool == "" {
+		return errors.New("--pool must be specified")
+	}
+	if !names.IsValidStorage(args[0]) {
+		return errors.NotValidf("storage ID %q", args[0])
+	}
+	c.storageId = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *migrateStorageCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	results, err := api.MigrateStorage([]string{c.storageId}, c.pool)
+	if err != nil {
+		if params.IsCodeUnauthorized(err) {
+			common.PermissionsMessage(ctx.Stderr, "migrate storage")
+		}
+		return errors.Trace(err)
+	}
+	if results[0].Error != nil {
+		return errors.Annotatef(results[0].Error, "failed to migrate %s", c.storageId)
+	}
+	ctx.Infof(
+		"migrating %s to pool %q as storage migration %s",
+		c.storageId, c.pool, results[0].Id,
+	)
+	return nil
+}
+
+// NewListStorageMigrationsCommand returns a command used to list
+// storage migrations.
+func NewListStorageMigrationsCommand() cmd.Command {
+	cmd := &listStorageMigrationsCommand{}
+	cmd.newAPIFunc = func() (StorageMigrationAPI, error) {
+		return cmd.NewStorageAPI()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+const listStorageMigrationsCommandDoc = `
+Lists the storage migrations in the model, along with the storage,
+the volumes and pools it was migrated between, and their status.
+Completed migrations are kept as a record of where each storage
+instance's data came from.
+`
+
+// listStorageMigrationsCommand lists storage migrations.
+type listStorageMigrationsCommand struct {
+	StorageCommandBase
+	modelcmd.IAASOnlyCommand
+	newAPIFunc func() (StorageMigrationAPI, error)
+	out        cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *listStorageMigrationsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "storage-migrations",
+		Purpose: "Lists storage migrations.",
+		Doc:     listStorageMigrationsCommandDoc,
+		Aliases: []string{"list-storage-migrations"},
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listStorageMigrationsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.StorageCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatStorageMigrationsTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *listStorageMigrationsCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *listStorageMigrationsCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	results, err := api.ListStorageMigrations()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(results) == 0 {
+		ctx.Infof("No storage migrations to display.")
+		return nil
+	}
+	info, err := formatStorageMigrations(results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return c.out.Write(ctx, info)
+}
+
+// StorageMigrationInfo defines the serialization behaviour of
+// storage migration information.
+type StorageMigrationInfo struct {
+	Storage      string     `yaml:"storage" json:"storage"`
+	Machine      string     `yaml:"machine" json:"machine"`
+	SourceVolume string     `yaml:"source-volume" json:"source-volume"`
+	SourcePool   string     `yaml:"source-pool" json:"source-pool"`
+	TargetVolume string     `yaml:"target-volume" json:"target-volume"`
+	TargetPool   string     `yaml:"target-pool" json:"target-pool"`
+	Status       string     `yaml:"status" json:"status"`
+	Message      string     `yaml:"message,omitempty" json:"message,omitempty"`
+	Created      *time.Time `yaml:"created,omitempty" json:"created,omitempty"`
+	Completed    *time.Time `yaml:"completed,omitempty" json:"completed,omitempty"`
+}
+
+func formatStorageMigrations(all []params.StorageMigrationDetails) (map[string]StorageMigrationInfo, error) {
+	output := make(map[string]StorageMigrationInfo)
+	for _, one := range all {
+		storageTag, err := names.ParseStorageTag(one.StorageTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		machineTag, err := names.ParseMachineTag(one.MachineTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		sourceTag, err := names.ParseVolumeTag(one.SourceVolumeTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		targetTag, err := names.ParseVolumeTag(one.TargetVolumeTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		info := StorageMigrationInfo{
+			Storage:      storageTag.Id(),
+			Machine:      machineTag.Id(),
+			SourceVolume: sourceTag.Id(),
+			SourcePool:   one.SourcePool,
+			TargetVolume: targetTag.Id(),
+			TargetPool:   one.TargetPool,
+			Status:       one.Status,
+			Message:      one.Message,
+			Completed:    one.Completed,
+		}
+		if !one.Created.IsZero() {
+			created := one.Created
+			info.Created = &created
+		}
+		output[one.Id] = info
+	}
+	return output, nil
+}
+
+// formatStorageMigrationsTabular writes a tabular summary of
+// storage migrations.
+func formatStorageMigrationsTabular(writer io.Writer, value interface{}) error {
+	migrations, ok := value.(map[string]StorageMigrationInfo)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", migrations, value)
+	}
+	ids := make([]string, 0, len(migrations))
+	for id := range migrations {
+		ids = append(ids, id)
+	}
+	sort.Sort(slashSeparatedIds(ids))
+
+	w := output.Wrapper{output.TabWriter(writer)}
+	w.Println("Migration", "Storage", "Machine", "From", "To", "Status", "Message")
+	for _, id := range ids {
+		info := migrations[id]
+		w.Print(id, info.Storage, info.Machine)
+		w.Print(info.SourcePool + " (" + info.SourceVolume + ")")
+		w.Print(info.TargetPool + " (" + info.TargetVolume + ")")
+		w.Print(info.Status)
+		w.Println(info.Message)
+	}
+	return w.Flush()
+}
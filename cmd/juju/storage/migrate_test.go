@@ -0,0 +1,158 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/storage"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+)
+
+type StorageMigrationSuite struct {
+	testing.IsolationSuite
+	api *mockMigrationAPI
+}
+
+var _ = gc.Suite(&StorageMigrationSuite{})
+
+func (s *StorageMigrationSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.api = &mockMigrationAPI{}
+}
+
+func (s *StorageMigrationSuite) runMigrate(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := storage.NewMigrateStorageCommandForTest(s.api, jujuclienttesting.MinimalStore())
+	return cmdtesting.RunCommand(c, command, args...)
+}
+
+func (s *StorageMigrationSuite) runList(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := storage.NewListStorageMigrationsCommandForTest(s.api, jujuclienttesting.MinimalStore())
+	return cmdtesting.RunCommand(c, command, args...)
+}
+
+func (s *StorageMigrationSuite) TestMigrate(c *gc.C) {
+	s.api.migrateResults = []params.StorageMigrationResult{{Id: "0/1"}}
+	ctx, err := s.runMigrate(c, "pgdata/0", "--pool", "ebs-ssd")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "migrating pgdata/0 to pool \"ebs-ssd\" as storage migration 0/1\n")
+	s.api.CheckCallNames(c, "MigrateStorage", "Close")
+	s.api.CheckCall(c, 0, "MigrateStorage", []string{"pgdata/0"}, "ebs-ssd")
+}
+
+func (s *StorageMigrationSuite) TestMigrateFailure(c *gc.C) {
+	s.api.migrateResults = []params.StorageMigrationResult{{
+		Error: &params.Error{Message: `storage is already in pool "ebs-ssd"`},
+	}}
+	_, err := s.runMigrate(c, "pgdata/0", "--pool", "ebs-ssd")
+	c.Assert(err, gc.ErrorMatches, `failed to migrate pgdata/0: storage is already in pool "ebs-ssd"`)
+}
+
+func (s *StorageMigrationSuite) TestMigrateInitErrors(c *gc.C) {
+	_, err := s.runMigrate(c)
+	c.Assert(err, gc.ErrorMatches, "migrate-storage requires a storage ID")
+	_, err = s.runMigrate(c, "pgdata/0")
+	c.Assert(err, gc.ErrorMatches, "--pool must be specified")
+	_, err = s.runMigrate(c, "pgdata", "--pool", "ebs-ssd")
+	c.Assert(err, gc.ErrorMatches, `storage ID "pgdata" not valid`)
+	_, err = s.runMigrate(c, "pgdata/0", "pgdata/1", "--pool", "ebs-ssd")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["pgdata/1"\]`)
+	s.api.CheckNoCalls(c)
+}
+
+func (s *StorageMigrationSuite) TestListTabular(c *gc.C) {
+	s.api.listResults = []params.StorageMigrationDetails{{
+		Id:              "0/2",
+		StorageTag:      "storage-pgdata-0",
+		MachineTag:      "machine-0",
+		SourceVolumeTag: "volume-1",
+		SourcePool:      "ebs-ssd",
+		TargetVolumeTag: "volume-2",
+		TargetPool:      "ebs",
+		Status:          "error",
+		Message:         "copying data: dd failed",
+	}, {
+		Id:              "0/1",
+		StorageTag:      "storage-pgdata-0",
+		MachineTag:      "machine-0",
+		SourceVolumeTag: "volume-0-0",
+		SourcePool:      "loop",
+		TargetVolumeTag: "volume-1",
+		TargetPool:      "ebs-ssd",
+		Status:          "completed",
+	}}
+	ctx, err := s.runList(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Migration  Storage   Machine  From         To           Status     Message
+0/1        pgdata/0  0        loop (0/0)   ebs-ssd (1)  completed  
+0/2        pgdata/0  0        ebs-ssd (1)  ebs (2)      error      copying data: dd failed
+`[1:])
+}
+
+func (s *StorageMigrationSuite) TestListYAML(c *gc.C) {
+	created := time.Date(2020, 2, 1, 10, 0, 0, 0, time.UTC)
+	completed := created.Add(time.Hour)
+	s.api.listResults = []params.StorageMigrationDetails{{
+		Id:              "0/1",
+		StorageTag:      "storage-pgdata-0",
+		MachineTag:      "machine-0",
+		SourceVolumeTag: "volume-0-0",
+		SourcePool:      "loop",
+		TargetVolumeTag: "volume-1",
+		TargetPool:      "ebs-ssd",
+		Status:          "completed",
+		Created:         created,
+		Completed:       &completed,
+	}}
+	ctx, err := s.runList(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+0/1:
+  storage: pgdata/0
+  machine: "0"
+  source-volume: 0/0
+  source-pool: loop
+  target-volume: "1"
+  target-pool: ebs-ssd
+  status: completed
+  created: 2020-02-01T10:00:00Z
+  completed: 2020-02-01T11:00:00Z
+`[1:])
+}
+
+func (s *StorageMigrationSuite) TestListEmpty(c *gc.C) {
+	ctx, err := s.runList(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No storage migrations to display.\n")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+}
+
+type mockMigrationAPI struct {
+	testing.Stub
+	migrateResults []params.StorageMigrationResult
+	listResults    []params.StorageMigrationDetails
+}
+
+func (m *mockMigrationAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return m.NextErr()
+}
+
+func (m *mockMigrationAPI) MigrateStorage(storageIds []string, pool string) ([]params.StorageMigrationResult, error) {
+	m.MethodCall(m, "MigrateStorage", storageIds, pool)
+	return m.migrateResults, m.NextErr()
+}
+
+func (m *mockMigrationAPI) ListStorageMigrations() ([]params.StorageMigrationDetails, error) {
+	m.MethodCall(m, "ListStorageMigrations")
+	return m.listResults, m.NextErr()
+}
@@ -45,6 +45,13 @@ configuration as space-separated pairs, e.g. tags, size, path, etc.
 For Kubernetes models, the provider type defaults to "kubernetes"
 unless otherwise specified.
 
+The "encrypted" attribute requests that volumes be encrypted at rest; it
+is an error for providers that cannot encrypt (e.g. rootfs, tmpfs, maas,
+lxd and kubernetes). The "kms-key" attribute selects a customer-managed
+key, and is only supported by the gce provider; ebs, cinder, azure and
+oci always use the cloud's default key, and loop and lvm generate a LUKS
+key on the machine (see "juju show-storage").
+
 Examples:
 
     juju create-storage-pool ebsrotary ebs volume-type=standard
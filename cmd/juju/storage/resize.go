@@ -0,0 +1,115 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+// NewResizeStorageCommand returns a command used to resize storage.
+func NewResizeStorageCommand() cmd.Command {
+	cmd := &resizeStorageCommand{}
+	cmd.newAPIFunc = func() (StorageResizeAPI, error) {
+		return cmd.NewStorageAPI()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+const (
+	resizeStorageCommandDoc = `
+Grows the volume or filesystem backing a storage instance to the
+specified size. Storage can only be grown; a size no larger than the
+current size of the storage is rejected.
+
+SIZE is a floating point number and multiplier from the set
+(M, G, T, P, E, Z, Y), which are all treated as powers of 1024.
+If no multiplier is given, the size is in MiB.
+
+The resize is carried out by the storage provisioner responsible for
+the storage. Once the storage has grown, the "storage-resized" hook
+runs on the unit the storage is attached to, so the charm can grow
+any filesystem it manages on a block device.
+
+Not all storage providers support resizing; "juju storage" reports
+an error against the storage if resizing fails.
+
+Examples:
+    juju resize-storage pgdata/0 20G
+`
+
+	resizeStorageCommandArgs = `<storage> <size>`
+)
+
+// StorageResizeAPI defines the API methods that the resize-storage
+// command uses.
+type StorageResizeAPI interface {
+	Close() error
+	ResizeStorage(storageId string, size uint64) error
+}
+
+// resizeStorageCommand resizes a storage instance.
+type resizeStorageCommand struct {
+	StorageCommandBase
+	modelcmd.IAASOnlyCommand
+	newAPIFunc func() (StorageResizeAPI, error)
+
+	storageId string
+	size      uint64
+}
+
+// Init implements Command.Init.
+func (c *resizeStorageCommand) Init(args []string) error {
+	if len(args) != 2 {
+		return errors.New("resize-storage requires a storage ID and a size")
+	}
+	if !names.IsValidStorage(args[0]) {
+		return errors.NotValidf("storage ID %q", args[0])
+	}
+	size, err := utils.ParseSize(args[1])
+	if err != nil {
+		return errors.Annotate(err, "cannot parse size")
+	}
+	if size == 0 {
+		return errors.New("size must be greater than zero")
+	}
+	c.storageId = args[0]
+	c.size = size
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *resizeStorageCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "resize-storage",
+		Purpose: "Grows the volume or filesystem backing a storage instance.",
+		Doc:     resizeStorageCommandDoc,
+		Args:    resizeStorageCommandArgs,
+	})
+}
+
+// Run implements Command.Run.
+func (c *resizeStorageCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	if err := api.ResizeStorage(c.storageId, c.size); err != nil {
+		if params.IsCodeUnauthorized(err) {
+			common.PermissionsMessage(ctx.Stderr, "resize storage")
+		}
+		return errors.Trace(err)
+	}
+	ctx.Infof("resizing %s to %dMiB", c.storageId, c.size)
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/storage"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+)
+
+type ResizeStorageSuite struct {
+	testing.IsolationSuite
+	api *mockResizeAPI
+}
+
+var _ = gc.Suite(&ResizeStorageSuite{})
+
+func (s *ResizeStorageSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.api = &mockResizeAPI{}
+}
+
+func (s *ResizeStorageSuite) run(c *gc.C, args ...string) (string, error) {
+	command := storage.NewResizeStorageCommandForTest(s.api, jujuclienttesting.MinimalStore())
+	ctx, err := cmdtesting.RunCommand(c, command, args...)
+	if err != nil {
+		return "", err
+	}
+	return cmdtesting.Stderr(ctx), nil
+}
+
+func (s *ResizeStorageSuite) TestResize(c *gc.C) {
+	stderr, err := s.run(c, "pgdata/0", "20G")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stderr, gc.Equals, "resizing pgdata/0 to 20480MiB\n")
+	s.api.CheckCallNames(c, "ResizeStorage", "Close")
+	s.api.CheckCall(c, 0, "ResizeStorage", "pgdata/0", uint64(20480))
+}
+
+func (s *ResizeStorageSuite) TestResizeError(c *gc.C) {
+	s.api.SetErrors(&params.Error{Message: "new size 1024MiB must be larger than current size 2048MiB"})
+	_, err := s.run(c, "pgdata/0", "1G")
+	c.Assert(err, gc.ErrorMatches, "new size 1024MiB must be larger than current size 2048MiB")
+}
+
+func (s *ResizeStorageSuite) TestResizeUnauthorizedError(c *gc.C) {
+	s.api.SetErrors(&params.Error{Code: params.CodeUnauthorized, Message: "nope"})
+	command := storage.NewResizeStorageCommandForTest(s.api, jujuclienttesting.MinimalStore())
+	ctx, err := cmdtesting.RunCommand(c, command, "pgdata/0", "20G")
+	c.Assert(err, gc.ErrorMatches, "nope")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+You do not have permission to resize storage.
+You may ask an administrator to grant you access with "juju grant".
+
+`)
+}
+
+func (s *ResizeStorageSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"pgdata/0"},
+		err:  "resize-storage requires a storage ID and a size",
+	}, {
+		args: []string{"pgdata", "20G"},
+		err:  `storage ID "pgdata" not valid`,
+	}, {
+		args: []string{"pgdata/0", "big"},
+		err:  `cannot parse size: .*`,
+	}, {
+		args: []string{"pgdata/0", "0"},
+		err:  "size must be greater than zero",
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		_, err := s.run(c, t.args...)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+	s.api.CheckNoCalls(c)
+}
+
+type mockResizeAPI struct {
+	testing.Stub
+}
+
+func (m *mockResizeAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return m.NextErr()
+}
+
+func (m *mockResizeAPI) ResizeStorage(storageId string, size uint64) error {
+	m.MethodCall(m, "ResizeStorage", storageId, size)
+	return m.NextErr()
+}
@@ -31,6 +31,14 @@ Storage instances to display are specified by storage ids.
 Storage ids are positional arguments to the command and do not need to be comma
 separated when more than one id is desired.
 
+Volumes created from pools with "encrypted=true" are shown as encrypted.
+Cloud volumes are encrypted at rest by the cloud. Loop and LVM volumes are
+encrypted with LUKS on the machine, with a key generated by the machine
+agent and kept only on that machine, outside the storage directory and
+readable only by root. This protects the data if the disk or backing file
+is read elsewhere, but not from root on the running machine, and the data
+cannot be recovered if the machine's root disk is lost.
+
 `
 
 // showCommand attempts to release storage instance.
@@ -126,6 +126,28 @@ shared-fs/0:
 	)
 }
 
+func (s *ShowSuite) TestShowEncrypted(c *gc.C) {
+	now := time.Now()
+	s.mockAPI.time = now
+	s.mockAPI.encrypted = true
+	s.assertValidShow(
+		c,
+		[]string{"db-dir/1000"},
+		fmt.Sprintf(`
+db-dir/1000:
+  kind: block
+  status:
+    current: pending
+    since: %s
+  persistent: false
+  encrypted: true
+  attachments:
+    units:
+      postgresql/0: {}
+`[1:], common.FormatTime(&now, false)),
+	)
+}
+
 func (s *ShowSuite) assertValidShow(c *gc.C, args []string, expected string) {
 	context, err := s.runShow(c, args)
 	c.Assert(err, jc.ErrorIsNil)
@@ -135,8 +157,9 @@ func (s *ShowSuite) assertValidShow(c *gc.C, args []string, expected string) {
 }
 
 type mockShowAPI struct {
-	noMatch bool
-	time    time.Time
+	noMatch   bool
+	encrypted bool
+	time      time.Time
 }
 
 func (s mockShowAPI) Close() error {
@@ -185,6 +208,7 @@ func (s mockShowAPI) StorageDetails(tags []names.StorageTag) ([]params.StorageDe
 			if i == 1 {
 				all[i].Result.Persistent = true
 			}
+			all[i].Result.Encrypted = s.encrypted
 		}
 	}
 	return all, nil
@@ -0,0 +1,268 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"io"
+	"sort"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+// NewCreateStorageSnapshotCommand returns a command used to snapshot
+// storage instances.
+func NewCreateStorageSnapshotCommand() cmd.Command {
+	cmd := &createStorageSnapshotCommand{}
+	cmd.newAPIFunc = func() (StorageSnapshotAPI, error) {
+		return cmd.NewStorageAPI()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+const (
+	createStorageSnapshotCommandDoc = `
+Requests a point-in-time snapshot of the volume backing each of the
+specified storage instances. Snapshots are created asynchronously by
+the storage provisioner; use "juju storage-snapshots" to follow their
+progress.
+
+Only block storage backed by a provider that supports snapshots may
+be snapshotted. Snapshots are crash-consistent; quiesce the workload
+first if it requires application-consistent snapshots.
+
+A snapshot that is available may be used to provision the storage of
+a new unit, using the --attach-storage-snapshot option of "juju deploy"
+or "juju add-unit".
+
+Examples:
+    juju create-storage-snapshot pgdata/0
+
+See also:
+    storage-snapshots
+    deploy
+    add-unit
+`
+
+	createStorageSnapshotCommandArgs = `<storage ID> [<storage ID> ...]`
+)
+
+// StorageSnapshotAPI defines the API methods that the storage
+// snapshot commands use.
+type StorageSnapshotAPI interface {
+	Close() error
+	CreateStorageSnapshots(storageIds []string) ([]params.StorageSnapshotResult, error)
+	ListStorageSnapshots() ([]params.StorageSnapshotDetails, error)
+}
+
+// createStorageSnapshotCommand snapshots storage instances.
+type createStorageSnapshotCommand struct {
+	StorageCommandBase
+	modelcmd.IAASOnlyCommand
+	newAPIFunc func() (StorageSnapshotAPI, error)
+
+	storageIds []string
+}
+
+// Init implements Command.Init.
+func (c *createStorageSnapshotCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("create-storage-snapshot requires at least one storage ID")
+	}
+	for _, id := range args {
+		if !names.IsValidStorage(id) {
+			return errors.NotValidf("storage ID %q", id)
+		}
+	}
+	c.storageIds = args
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *createStorageSnapshotCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "create-storage-snapshot",
+		Purpose: "Snapshots the volumes backing storage instances.",
+		Doc:     createStorageSnapshotCommandDoc,
+		Args:    createStorageSnapshotCommandArgs,
+	})
+}
+
+// Run implements Command.Run.
+func (c *createStorageSnapshotCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	results, err := api.CreateStorageSnapshots(c.storageIds)
+	if err != nil {
+		if params.IsCodeUnauthorized(err) {
+			common.PermissionsMessage(ctx.Stderr, "snapshot storage")
+		}
+		return errors.Trace(err)
+	}
+	anyFailed := false
+	for i, result := range results {
+		if result.Error != nil {
+			ctx.Infof("failed to snapshot %s: %v", c.storageIds[i], result.Error)
+			anyFailed = true
+			continue
+		}
+		ctx.Infof("snapshotting %s as storage snapshot %s", c.storageIds[i], result.Id)
+	}
+	if anyFailed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
+
+// NewListStorageSnapshotsCommand returns a command used to list
+// storage snapshots.
+func NewListStorageSnapshotsCommand() cmd.Command {
+	cmd := &listStorageSnapshotsCommand{}
+	cmd.newAPIFunc = func() (StorageSnapshotAPI, error) {
+		return cmd.NewStorageAPI()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+const listStorageSnapshotsCommandDoc = `
+Lists the storage snapshots in the model, along with the storage and
+volume they were taken of, and their status. The snapshot ID reported
+by the storage provider is shown once the snapshot has been created.
+`
+
+// listStorageSnapshotsCommand lists storage snapshots.
+type listStorageSnapshotsCommand struct {
+	StorageCommandBase
+	modelcmd.IAASOnlyCommand
+	newAPIFunc func() (StorageSnapshotAPI, error)
+	out        cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *listStorageSnapshotsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "storage-snapshots",
+		Purpose: "Lists storage snapshots.",
+		Doc:     listStorageSnapshotsCommandDoc,
+		Aliases: []string{"list-storage-snapshots"},
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listStorageSnapshotsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.StorageCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatStorageSnapshotsTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *listStorageSnapshotsCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *listStorageSnapshotsCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	results, err := api.ListStorageSnapshots()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(results) == 0 {
+		ctx.Infof("No storage snapshots to display.")
+		return nil
+	}
+	info, err := formatStorageSnapshots(results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return c.out.Write(ctx, info)
+}
+
+// StorageSnapshotInfo defines the serialization behaviour of
+// storage snapshot information.
+type StorageSnapshotInfo struct {
+	Storage    string     `yaml:"storage" json:"storage"`
+	Volume     string     `yaml:"volume" json:"volume"`
+	Pool       string     `yaml:"pool" json:"pool"`
+	Size       uint64     `yaml:"size,omitempty" json:"size,omitempty"`
+	SnapshotId string     `yaml:"snapshot-id,omitempty" json:"snapshot-id,omitempty"`
+	Status     string     `yaml:"status" json:"status"`
+	Message    string     `yaml:"message,omitempty" json:"message,omitempty"`
+	Created    *time.Time `yaml:"created,omitempty" json:"created,omitempty"`
+}
+
+func formatStorageSnapshots(all []params.StorageSnapshotDetails) (map[string]StorageSnapshotInfo, error) {
+	output := make(map[string]StorageSnapshotInfo)
+	for _, one := range all {
+		storageTag, err := names.ParseStorageTag(one.StorageTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		volumeTag, err := names.ParseVolumeTag(one.VolumeTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		info := StorageSnapshotInfo{
+			Storage:    storageTag.Id(),
+			Volume:     volumeTag.Id(),
+			Pool:       one.Pool,
+			Size:       one.Size,
+			SnapshotId: one.SnapshotId,
+			Status:     one.Status,
+			Message:    one.Message,
+		}
+		if !one.Created.IsZero() {
+			created := one.Created
+			info.Created = &created
+		}
+		output[one.Id] = info
+	}
+	return output, nil
+}
+
+// formatStorageSnapshotsTabular writes a tabular summary of
+// storage snapshots.
+func formatStorageSnapshotsTabular(writer io.Writer, value interface{}) error {
+	snapshots, ok := value.(map[string]StorageSnapshotInfo)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", snapshots, value)
+	}
+	ids := make([]string, 0, len(snapshots))
+	for id := range snapshots {
+		ids = append(ids, id)
+	}
+	sort.Sort(slashSeparatedIds(ids))
+
+	w := output.Wrapper{output.TabWriter(writer)}
+	w.Println("Snapshot", "Storage", "Volume", "Pool", "Size", "Provider id", "Status", "Message")
+	for _, id := range ids {
+		info := snapshots[id]
+		w.Print(id, info.Storage, info.Volume, info.Pool)
+		w.Print(humanizeStorageSize(info.Size))
+		w.Print(info.SnapshotId, info.Status)
+		w.Println(info.Message)
+	}
+	return w.Flush()
+}
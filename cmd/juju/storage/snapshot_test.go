@@ -0,0 +1,150 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/storage"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+)
+
+type StorageSnapshotSuite struct {
+	testing.IsolationSuite
+	api *mockSnapshotAPI
+}
+
+var _ = gc.Suite(&StorageSnapshotSuite{})
+
+func (s *StorageSnapshotSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.api = &mockSnapshotAPI{}
+}
+
+func (s *StorageSnapshotSuite) runCreate(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := storage.NewCreateStorageSnapshotCommandForTest(s.api, jujuclienttesting.MinimalStore())
+	return cmdtesting.RunCommand(c, command, args...)
+}
+
+func (s *StorageSnapshotSuite) runList(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := storage.NewListStorageSnapshotsCommandForTest(s.api, jujuclienttesting.MinimalStore())
+	return cmdtesting.RunCommand(c, command, args...)
+}
+
+func (s *StorageSnapshotSuite) TestCreate(c *gc.C) {
+	s.api.createResults = []params.StorageSnapshotResult{{Id: "0/1"}}
+	ctx, err := s.runCreate(c, "pgdata/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "snapshotting pgdata/0 as storage snapshot 0/1\n")
+	s.api.CheckCallNames(c, "CreateStorageSnapshots", "Close")
+	s.api.CheckCall(c, 0, "CreateStorageSnapshots", []string{"pgdata/0"})
+}
+
+func (s *StorageSnapshotSuite) TestCreatePartialFailure(c *gc.C) {
+	s.api.createResults = []params.StorageSnapshotResult{
+		{Id: "0/1"},
+		{Error: &params.Error{Message: "snapshotting filesystem storage not supported"}},
+	}
+	ctx, err := s.runCreate(c, "pgdata/0", "logs/1")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+snapshotting pgdata/0 as storage snapshot 0/1
+failed to snapshot logs/1: snapshotting filesystem storage not supported
+`[1:])
+}
+
+func (s *StorageSnapshotSuite) TestCreateInitErrors(c *gc.C) {
+	_, err := s.runCreate(c)
+	c.Assert(err, gc.ErrorMatches, "create-storage-snapshot requires at least one storage ID")
+	_, err = s.runCreate(c, "pgdata")
+	c.Assert(err, gc.ErrorMatches, `storage ID "pgdata" not valid`)
+	s.api.CheckNoCalls(c)
+}
+
+func (s *StorageSnapshotSuite) TestListTabular(c *gc.C) {
+	s.api.listResults = []params.StorageSnapshotDetails{{
+		Id:         "0/10",
+		StorageTag: "storage-pgdata-0",
+		VolumeTag:  "volume-0",
+		Pool:       "ebs",
+		Status:     "error",
+		Message:    "snapshotting \"loop\" volumes not supported",
+	}, {
+		Id:         "0/2",
+		StorageTag: "storage-pgdata-0",
+		VolumeTag:  "volume-0",
+		Pool:       "ebs",
+		Size:       2048,
+		SnapshotId: "snap-0123",
+		Status:     "available",
+	}}
+	ctx, err := s.runList(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Snapshot  Storage   Volume  Pool  Size    Provider id  Status     Message
+0/2       pgdata/0  0       ebs   2.0GiB  snap-0123    available  
+0/10      pgdata/0  0       ebs                        error      snapshotting "loop" volumes not supported
+`[1:])
+}
+
+func (s *StorageSnapshotSuite) TestListYAML(c *gc.C) {
+	created := time.Date(2020, 2, 1, 10, 0, 0, 0, time.UTC)
+	s.api.listResults = []params.StorageSnapshotDetails{{
+		Id:         "0/2",
+		StorageTag: "storage-pgdata-0",
+		VolumeTag:  "volume-0",
+		Pool:       "ebs",
+		Size:       2048,
+		SnapshotId: "snap-0123",
+		Status:     "available",
+		Created:    created,
+	}}
+	ctx, err := s.runList(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+0/2:
+  storage: pgdata/0
+  volume: "0"
+  pool: ebs
+  size: 2048
+  snapshot-id: snap-0123
+  status: available
+  created: 2020-02-01T10:00:00Z
+`[1:])
+}
+
+func (s *StorageSnapshotSuite) TestListEmpty(c *gc.C) {
+	ctx, err := s.runList(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No storage snapshots to display.\n")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+}
+
+type mockSnapshotAPI struct {
+	testing.Stub
+	createResults []params.StorageSnapshotResult
+	listResults   []params.StorageSnapshotDetails
+}
+
+func (m *mockSnapshotAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return m.NextErr()
+}
+
+func (m *mockSnapshotAPI) CreateStorageSnapshots(storageIds []string) ([]params.StorageSnapshotResult, error) {
+	m.MethodCall(m, "CreateStorageSnapshots", storageIds)
+	return m.createResults, m.NextErr()
+}
+
+func (m *mockSnapshotAPI) ListStorageSnapshots() ([]params.StorageSnapshotDetails, error) {
+	m.MethodCall(m, "ListStorageSnapshots")
+	return m.listResults, m.NextErr()
+}
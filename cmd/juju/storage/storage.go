@@ -38,6 +38,7 @@ type StorageInfo struct {
 	Life        string              `yaml:"life,omitempty" json:"life,omitempty"`
 	Status      EntityStatus        `yaml:"status" json:"status"`
 	Persistent  bool                `yaml:"persistent" json:"persistent"`
+	Encrypted   bool                `yaml:"encrypted,omitempty" json:"encrypted,omitempty"`
 	Attachments *StorageAttachments `yaml:"attachments,omitempty" json:"attachments,omitempty"`
 }
 
@@ -98,6 +99,7 @@ func createStorageInfo(details params.StorageDetails) (names.StorageTag, Storage
 			common.FormatTime(details.Status.Since, false),
 		},
 		Persistent: details.Persistent,
+		Encrypted:  details.Encrypted,
 	}
 
 	if len(details.Attachments) > 0 {
@@ -48,6 +48,7 @@ var (
 		"migration-master",        // secondary dependency: will be inactive because depends on model-upgrader
 		"model-upgrader",
 		"remote-relations",      // tertiary dependency: will be inactive because migration workers will be inactive
+		"resource-tagger",       // tertiary dependency: will be inactive because migration workers will be inactive
 		"state-cleaner",         // tertiary dependency: will be inactive because migration workers will be inactive
 		"status-history-pruner", // tertiary dependency: will be inactive because migration workers will be inactive
 		"storage-provisioner",   // tertiary dependency: will be inactive because migration workers will be inactive
@@ -71,6 +72,7 @@ var (
 		"migration-inactive-flag",
 		"migration-master",
 		"remote-relations",
+		"resource-tagger",
 		"state-cleaner",
 		"status-history-pruner",
 		"storage-provisioner",
@@ -142,6 +144,7 @@ var (
 	notMigratingMachineWorkers = []string{
 		"api-address-updater",
 		"disk-manager",
+		// "egress-firewaller", needs root to apply iptables rules
 		"fan-configurer",
 		// "host-key-reporter", not stable, exits when done
 		"log-sender",
@@ -53,6 +53,7 @@ import (
 	"github.com/juju/juju/worker/credentialvalidator"
 	"github.com/juju/juju/worker/deployer"
 	"github.com/juju/juju/worker/diskmanager"
+	"github.com/juju/juju/worker/egressfirewaller"
 	"github.com/juju/juju/worker/externalcontrollerupdater"
 	"github.com/juju/juju/worker/fanconfigurer"
 	"github.com/juju/juju/worker/featureflag"
@@ -855,6 +856,16 @@ func IAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 			NewWorker:     hostkeyreporter.NewWorker,
 		})),
 
+		egressFirewallerName: ifNotMigrating(egressfirewaller.Manifold(egressfirewaller.ManifoldConfig{
+			AgentName:     agentName,
+			APICallerName: apiCallerName,
+			NewFacade:     egressfirewaller.NewFacade,
+			NewWorker:     egressfirewaller.NewWorker,
+			RunCommand:    egressfirewaller.RunCommand,
+			Resolvers:     egressfirewaller.Resolvers,
+			NTPServers:    egressfirewaller.NTPServers,
+		})),
+
 		fanConfigurerName: ifNotMigrating(fanconfigurer.Manifold(fanconfigurer.ManifoldConfig{
 			APICallerName: apiCallerName,
 			Clock:         config.Clock,
@@ -878,11 +889,13 @@ func IAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 		})),
 
 		// The diskmanager worker periodically lists block devices on the
-		// machine it runs on. This worker will be run on all Juju-managed
+		// machine it runs on, and reports the usage of filesystems
+		// attached to it. This worker will be run on all Juju-managed
 		// machines (one per machine agent).
 		diskManagerName: ifNotMigrating(diskmanager.Manifold(diskmanager.ManifoldConfig{
-			AgentName:     agentName,
-			APICallerName: apiCallerName,
+			AgentName:            agentName,
+			APICallerName:        apiCallerName,
+			PrometheusRegisterer: config.PrometheusRegisterer,
 		})),
 
 		// The api address updater is a leaf worker that rewrites agent config
@@ -1128,6 +1141,7 @@ const (
 	toolsVersionCheckerName       = "tools-version-checker"
 	machineActionName             = "machine-action-runner"
 	hostKeyReporterName           = "host-key-reporter"
+	egressFirewallerName          = "egress-firewaller"
 	fanConfigurerName             = "fan-configurer"
 	externalControllerUpdaterName = "external-controller-updater"
 	globalClockUpdaterName        = "global-clock-updater"
@@ -72,6 +72,7 @@ func (ms *ManifoldsSuite) TestManifoldNamesIAAS(c *gc.C) {
 			"clock",
 			"controller-port",
 			"disk-manager",
+			"egress-firewaller",
 			"external-controller-updater",
 			"fan-configurer",
 			"global-clock-updater",
@@ -544,6 +545,18 @@ var expectedMachineManifoldsWithDependencies = map[string][]string{
 		"upgrade-steps-gate",
 	},
 
+	"egress-firewaller": {
+		"agent",
+		"api-caller",
+		"api-config-watcher",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"upgrade-check-flag",
+		"upgrade-check-gate",
+		"upgrade-steps-flag",
+		"upgrade-steps-gate",
+	},
+
 	"global-clock-updater": {
 		"agent",
 		"is-controller-flag",
@@ -820,7 +820,12 @@ func (s *MachineLegacyLeasesSuite) TestMachineAgentRunsAPIAddressUpdaterWorker(c
 func (s *MachineLegacyLeasesSuite) TestMachineAgentRunsDiskManagerWorker(c *gc.C) {
 	// Patch out the worker func before starting the agent.
 	started := newSignal()
-	newWorker := func(diskmanager.ListBlockDevicesFunc, diskmanager.BlockDeviceSetter) worker.Worker {
+	newWorker := func(
+		diskmanager.ListBlockDevicesFunc,
+		diskmanager.BlockDeviceSetter,
+		diskmanager.FilesystemUsageSetter,
+		*diskmanager.Collector,
+	) worker.Worker {
 		started.trigger()
 		return jworker.NewNoOpWorker()
 	}
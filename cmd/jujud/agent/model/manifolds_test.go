@@ -60,6 +60,7 @@ func (s *ManifoldsSuite) TestIAASNames(c *gc.C) {
 		"not-alive-flag",
 		"not-dead-flag",
 		"remote-relations",
+		"resource-tagger",
 		"state-cleaner",
 		"status-history-pruner",
 		"storage-provisioner",
@@ -569,6 +570,19 @@ var expectedIAASModelManifoldsWithDependencies = map[string][]string{
 		"model-upgraded-flag",
 		"not-dead-flag"},
 
+	"resource-tagger": {
+		"agent",
+		"api-caller",
+		"environ-tracker",
+		"is-responsible-flag",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"model-upgrade-gate",
+		"model-upgraded-flag",
+		"not-dead-flag",
+		"valid-credential-flag",
+	},
+
 	"state-cleaner": {
 		"agent",
 		"api-caller",
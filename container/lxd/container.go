@@ -38,6 +38,13 @@ type ContainerSpec struct {
 	Config       map[string]string
 	Profiles     []string
 	InstanceType string
+
+	// VirtType is the type of LXD instance to create; a container unless
+	// it is VirtTypeVirtualMachine. Virtual machines are created from
+	// VMImage rather than Image.
+	VirtType    string
+	VMImage     VirtualMachineImage
+	RootDiskMiB uint64
 }
 
 // minMiBVersion is the minimum LXD version that we are sure will recognise the
@@ -49,7 +56,15 @@ var minMiBVersion = &version.DottedVersion{Major: 3, Minor: 10}
 // Note that we pass these through as supplied. If an instance type constraint
 // has been specified along with specific cores/mem constraints,
 // LXD behaviour is to override with the specific ones even when lower.
+// The root disk size is only applied to virtual machines; containers share
+// the storage pool of their root disk.
 func (c *ContainerSpec) ApplyConstraints(serverVersion string, cons constraints.Value) {
+	if IsVirtualMachine(cons) {
+		c.VirtType = VirtTypeVirtualMachine
+		if cons.HasRootDisk() {
+			c.RootDiskMiB = *cons.RootDisk
+		}
+	}
 	if cons.HasInstanceType() {
 		c.InstanceType = *cons.InstanceType
 	}
@@ -168,6 +183,13 @@ func (s *Server) FilterContainers(prefix string, statuses ...string) ([]Containe
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if s.vmAPISupport {
+		vms, err := s.virtualMachines()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		containers = append(containers, vms...)
+	}
 
 	var results []Container
 	for _, c := range containers {
@@ -186,6 +208,9 @@ func (s *Server) FilterContainers(prefix string, statuses ...string) ([]Containe
 // identified by the input name.
 func (s *Server) ContainerAddresses(name string) ([]corenetwork.ProviderAddress, error) {
 	state, _, err := s.GetContainerState(name)
+	if IsLXDNotFound(errors.Cause(err)) && s.vmAPISupport {
+		state, err = virtualMachineState(s, name)
+	}
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -216,7 +241,12 @@ func (s *Server) ContainerAddresses(name string) ([]corenetwork.ProviderAddress,
 // and starts it immediately.
 // If the container fails to be started, it is removed.
 // Upon successful creation and start, the container is returned.
+// Virtual machines are created if the spec requests one.
 func (s *Server) CreateContainerFromSpec(spec ContainerSpec) (*Container, error) {
+	if spec.VirtType == VirtTypeVirtualMachine {
+		c, err := s.createVirtualMachine(spec)
+		return c, errors.Trace(err)
+	}
 	logger.Infof("starting new container %q (image %q)", spec.Name, spec.Image.Image.Filename)
 	logger.Debugf("new container has profiles %v", spec.Profiles)
 	req := api.ContainersPost{
@@ -299,9 +329,12 @@ func (s *Server) RemoveContainers(names []string) error {
 }
 
 // Remove container first ensures that the container is stopped,
-// then deletes it.
+// then deletes it. Virtual machines are removed in the same way.
 func (s *Server) RemoveContainer(name string) error {
 	state, eTag, err := s.GetContainerState(name)
+	if IsLXDNotFound(errors.Cause(err)) && s.vmAPISupport {
+		return errors.Trace(s.removeVirtualMachine(name))
+	}
 	if err != nil {
 		return errors.Trace(err)
 	}
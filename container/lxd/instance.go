@@ -41,6 +41,13 @@ func (lxd *lxdInstance) Addresses(ctx context.ProviderCallContext) (corenetwork.
 // Status implements instances.Instance.Status.
 func (lxd *lxdInstance) Status(ctx context.ProviderCallContext) instance.Status {
 	instStatus, _, err := lxd.server.GetContainerState(lxd.id)
+	if IsLXDNotFound(errors.Cause(err)) {
+		// The instance may be a virtual machine,
+		// which is not listed amongst the containers.
+		if vmStatus, vmErr := virtualMachineState(lxd.server, lxd.id); vmErr == nil {
+			instStatus, err = vmStatus, nil
+		}
+	}
 	if err != nil {
 		return instance.Status{
 			Status:  status.Empty,
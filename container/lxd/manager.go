@@ -159,15 +159,28 @@ func (m *containerManager) getContainerSpec(
 		return ContainerSpec{}, errors.Trace(err)
 	}
 
-	// Lock around finding an image.
-	// The provisioner works concurrently to create containers.
-	// If an image needs to be copied from a remote, we don't want many
-	// goroutines attempting to do it at once.
-	m.imageMutex.Lock()
-	found, err := m.server.FindImage(series, jujuarch.HostArch(), imageSources, true, callback)
-	m.imageMutex.Unlock()
-	if err != nil {
-		return ContainerSpec{}, errors.Annotatef(err, "acquiring LXD image")
+	// Virtual machine images are downloaded by the LXD server
+	// when the virtual machine is created.
+	var found SourcedImage
+	var vmImage VirtualMachineImage
+	if IsVirtualMachine(cons) {
+		if !m.server.VirtualMachinesSupported() {
+			return ContainerSpec{}, errors.NotSupportedf("virtual machines on LXD server version %s", m.server.serverVersion)
+		}
+		if vmImage, err = FindVirtualMachineImage(series, jujuarch.HostArch(), imageSources); err != nil {
+			return ContainerSpec{}, errors.Annotatef(err, "finding LXD virtual machine image")
+		}
+	} else {
+		// Lock around finding an image.
+		// The provisioner works concurrently to create containers.
+		// If an image needs to be copied from a remote, we don't want many
+		// goroutines attempting to do it at once.
+		m.imageMutex.Lock()
+		found, err = m.server.FindImage(series, jujuarch.HostArch(), imageSources, true, callback)
+		m.imageMutex.Unlock()
+		if err != nil {
+			return ContainerSpec{}, errors.Annotatef(err, "acquiring LXD image")
+		}
 	}
 
 	name, err := m.namespace.Hostname(instanceConfig.MachineId)
@@ -229,6 +242,7 @@ func (m *containerManager) getContainerSpec(
 	spec := ContainerSpec{
 		Name:     name,
 		Image:    found,
+		VMImage:  vmImage,
 		Config:   cfg,
 		Profiles: instanceConfig.Profiles,
 		Devices:  nics,
@@ -59,7 +59,9 @@ func (s *Server) EnableHTTPSListener() error {
 }
 
 // EnsureIPv4 retrieves the network for the input name and checks its IPv4
-// configuration. If none is detected, it is set to "auto".
+// configuration. If none is detected, it is set to "auto", unless the
+// network is configured for IPv6, in which case it is left as an IPv6-only
+// network.
 // The boolean return indicates if modification was necessary.
 func (s *Server) EnsureIPv4(netName string) (bool, error) {
 	var modified bool
@@ -70,7 +72,7 @@ func (s *Server) EnsureIPv4(netName string) (bool, error) {
 	}
 
 	cfg, ok := net.Config["ipv4.address"]
-	if !ok || cfg == "none" {
+	if (!ok || cfg == "none") && !hasIPv6(net) {
 		if net.Config == nil {
 			net.Config = make(device, 2)
 		}
@@ -126,9 +128,7 @@ func (s *Server) VerifyNetworkDevice(profile *api.Profile, eTag string) error {
 }
 
 // ensureDefaultNetworking ensures that the default LXD bridge exists,
-// that it is not configured to use IPv6, and that a NIC device exists in
-// the input profile.
-// An error is returned if the bridge exists with IPv6 configuration.
+// and that a NIC device exists in the input profile.
 // If the bridge does not exist, it is created.
 func (s *Server) ensureDefaultNetworking(profile *api.Profile, eTag string) error {
 	net, _, err := s.GetNetwork(network.DefaultLXDBridge)
@@ -155,10 +155,6 @@ func (s *Server) ensureDefaultNetworking(profile *api.Profile, eTag string) erro
 		if err != nil {
 			return errors.Trace(err)
 		}
-	} else {
-		if err := verifyNoIPv6(net); err != nil {
-			return errors.Trace(err)
-		}
 	}
 
 	s.localBridgeName = network.DefaultLXDBridge
@@ -190,7 +186,6 @@ func (s *Server) ensureDefaultNetworking(profile *api.Profile, eTag string) erro
 // devices is suitable for LXD to work with Juju.
 func (s *Server) verifyNICsWithAPI(nics map[string]device) error {
 	checked := make([]string, 0, len(nics))
-	var ipV6ErrMsg error
 	for name, nic := range nics {
 		checked = append(checked, name)
 
@@ -207,27 +202,17 @@ func (s *Server) verifyNICsWithAPI(nics map[string]device) error {
 		if err != nil {
 			return errors.Annotatef(err, "retrieving network %q", netName)
 		}
-		if err := verifyNoIPv6(net); err != nil {
-			ipV6ErrMsg = err
-			continue
-		}
 
-		logger.Tracef("found usable network device %q with parent %q", name, netName)
+		logger.Tracef("found usable network device %q with parent %q (IPv6: %v)", name, netName, hasIPv6(net))
 		s.localBridgeName = netName
 		return nil
 	}
 
-	// A nic with valid type found, but the network configures IPv6.
-	if ipV6ErrMsg != nil {
-		return ipV6ErrMsg
-	}
-
 	// No nics with a nictype of nicTypeBridged, nicTypeMACVLAN was found.
 	return errors.Errorf(fmt.Sprintf(
 		"no network device found with nictype %q or %q"+
 			"\n\tthe following devices were checked: %s"+
-			"\nNote: juju does not support IPv6."+
-			"\nReconfigure lxd to use a network of type %q or %q, disabling IPv6.",
+			"\nReconfigure lxd to use a network of type %q or %q.",
 		nicTypeBridged, nicTypeMACVLAN, strings.Join(checked, ", "), nicTypeBridged, nicTypeMACVLAN))
 }
 
@@ -294,25 +279,15 @@ func getProfileNICs(profile *api.Profile) map[string]device {
 	return nics
 }
 
-// verifyNoIPv6 checks that the input network has no IPv6 configuration.
-// An error is returned when it does.
-// TODO (manadart 2018-05-28) The intention is to support IPv6, so this
-// restriction is temporary.
-func verifyNoIPv6(net *api.Network) error {
+// hasIPv6 returns true if the input network is managed by LXD and has
+// IPv6 address configuration, either alongside IPv4 (dual-stack) or on
+// its own (IPv6-only).
+func hasIPv6(net *api.Network) bool {
 	if !net.Managed {
-		return nil
+		return false
 	}
 	cfg, ok := net.Config["ipv6.address"]
-	if !ok {
-		return nil
-	}
-	if cfg == "none" {
-		return nil
-	}
-
-	return errors.Errorf("juju does not support IPv6. Disable IPv6 in LXD via:\n"+
-		"\tlxc network set %s ipv6.address none\n"+
-		"and run the command again", net.Name)
+	return ok && cfg != "" && cfg != "none"
 }
 
 func isValidNICType(nic device) bool {
@@ -324,7 +299,7 @@ const SnapBridgeConfigFile = "/var/snap/lxd/common/lxd-bridge/config"
 
 // checkBridgeConfigFile verifies that the file configuration for the LXD
 // bridge has a a bridge name, that it is set to be used by LXD and that
-// it has (only) IPv4 configuration.
+// it has IPv4 and/or IPv6 subnet configuration.
 // TODO (manadart 2018-05-28) The error messages are invalid for LXD
 // installations that pre-date the network API support and that were installed
 // via Snap. The question of the correct user action was posed on the #lxd IRC
@@ -370,14 +345,12 @@ func checkBridgeConfigFile(reader func(string) ([]byte, error)) (string, error)
 		} else if strings.HasPrefix(line, "LXD_IPV6_ADDR=") {
 			contents := strings.Trim(line[len("LXD_IPV6_ADDR="):], " \"")
 			if len(contents) > 0 {
-				return "", ipv6BridgeConfigError(fileName, installedViaSnap)
+				foundSubnetConfig = true
 			}
 		}
 	}
 
 	if !foundSubnetConfig {
-		// TODO (hml) 2018-08-09 Question
-		// Should the error mention ipv6 is not enabled if juju doesn't support it?
 		return "", bridgeConfigError(bridgeName+" has no ipv4 or ipv6 subnet enabled", installedViaSnap)
 	}
 	return bridgeName, nil
@@ -393,17 +366,6 @@ func bridgeConfigError(err string, installedViaSnap bool) error {
 	return errors.Errorf(errMsg, err)
 }
 
-func ipv6BridgeConfigError(fileName string, installedViaSnap bool) error {
-	errMsg := "%s has IPv6 enabled.\nJuju doesn't currently support IPv6."
-	if !installedViaSnap {
-		errMsg += "\n" +
-			"Disable IPv6 via:\n\n" +
-			"\tsudo dpkg-reconfigure -p medium lxd\n\n" +
-			"and run the command again."
-	}
-	return errors.Errorf(errMsg, fileName)
-}
-
 // InterfaceInfoFromDevices returns a slice of interface info congruent with the
 // input LXD NIC devices.
 // The output is used to generate cloud-init user-data congruent with the NICs
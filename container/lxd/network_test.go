@@ -63,6 +63,30 @@ func (s *networkSuite) TestEnsureIPv4NoChange(c *gc.C) {
 	c.Check(mod, jc.IsFalse)
 }
 
+func (s *networkSuite) TestEnsureIPv4IPv6Only(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "network")
+
+	net := &lxdapi.Network{
+		Managed: true,
+		NetworkPut: lxdapi.NetworkPut{
+			Config: map[string]string{
+				"ipv4.address": "none",
+				"ipv6.address": "fd42:1a2b:3c4d:5e6f::1/64",
+			},
+		},
+	}
+	cSvr.EXPECT().GetNetwork(network.DefaultLXDBridge).Return(net, lxdtesting.ETag, nil)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	mod, err := jujuSvr.EnsureIPv4(network.DefaultLXDBridge)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(mod, jc.IsFalse)
+}
+
 func (s *networkSuite) TestEnsureIPv4Modified(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
@@ -175,8 +199,7 @@ func (s *networkSuite) TestVerifyNetworkDevicePresentBadNicType(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches,
 		`profile "default": no network device found with nictype "bridged" or "macvlan"\n`+
 			`\tthe following devices were checked: eth0\n`+
-			`Note: juju does not support IPv6.\n`+
-			`Reconfigure lxd to use a network of type "bridged" or "macvlan", disabling IPv6.`)
+			`Reconfigure lxd to use a network of type "bridged" or "macvlan".`)
 }
 
 func (s *networkSuite) TestVerifyNetworkDeviceIPv6Present(c *gc.C) {
@@ -199,10 +222,8 @@ func (s *networkSuite) TestVerifyNetworkDeviceIPv6Present(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 
 	err = jujuSvr.VerifyNetworkDevice(defaultProfileWithNIC(), "")
-	c.Assert(err, gc.ErrorMatches,
-		`profile "default": juju does not support IPv6. Disable IPv6 in LXD via:\n`+
-			`\tlxc network set lxdbr0 ipv6.address none\n`+
-			`and run the command again`)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(jujuSvr.LocalBridgeName(), gc.Equals, network.DefaultLXDBridge)
 }
 
 func (s *networkSuite) TestVerifyNetworkDeviceNotPresentCreated(c *gc.C) {
@@ -388,14 +409,9 @@ LXD_IPV6_ADDR="2001:470:b368:4242::1"
 `), nil
 	}
 
-	_, err = lxd.CheckBridgeConfigFile(ipv6)
-	c.Assert(err.Error(), gc.Equals, lxd.BridgeConfigFile+` has IPv6 enabled.
-Juju doesn't currently support IPv6.
-Disable IPv6 via:
-
-	sudo dpkg-reconfigure -p medium lxd
-
-and run the command again.`)
+	bridgeName, err = lxd.CheckBridgeConfigFile(ipv6)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(bridgeName, gc.Equals, "lxdbr0")
 }
 
 func (s *networkSuite) TestCheckSnapLXDBridgeConfiguration(c *gc.C) {
@@ -432,9 +448,9 @@ LXD_IPV6_ADDR="2001:470:b368:4242::1"
 `), nil
 	}
 
-	_, err = lxd.CheckBridgeConfigFile(ipv6)
-	c.Assert(err.Error(), gc.Equals, lxd.SnapBridgeConfigFile+` has IPv6 enabled.
-Juju doesn't currently support IPv6.`)
+	bridgeName, err = lxd.CheckBridgeConfigFile(ipv6)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(bridgeName, gc.Equals, "lxdbr0")
 }
 
 func (s *networkSuite) TestVerifyNICsWithConfigFileNICFound(c *gc.C) {
@@ -44,6 +44,7 @@ type Server struct {
 	networkAPISupport bool
 	clusterAPISupport bool
 	storageAPISupport bool
+	vmAPISupport      bool
 
 	localBridgeName string
 
@@ -120,6 +121,7 @@ func NewServer(svr lxd.ContainerServer) (*Server, error) {
 		networkAPISupport: shared.StringInSlice("network", apiExt),
 		clusterAPISupport: shared.StringInSlice("clustering", apiExt),
 		storageAPISupport: shared.StringInSlice("storage", apiExt),
+		vmAPISupport:      shared.StringInSlice("virtual-machines", apiExt),
 		serverVersion:     info.Environment.ServerVersion,
 		clock:             clock.WallClock,
 	}, nil
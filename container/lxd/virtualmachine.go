@@ -0,0 +1,315 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/juju/errors"
+	lxd "github.com/lxc/lxd/client"
+	"github.com/lxc/lxd/shared/api"
+
+	"github.com/juju/juju/core/constraints"
+)
+
+// The LXD client library used by Juju predates the instances API, through
+// which LXD creates and manages virtual machines. Virtual machines are
+// therefore handled with raw requests against that API, which the LXD
+// server advertises with the "virtual-machines" API extension.
+
+const (
+	// VirtTypeContainer is the virt-type constraint value
+	// for a system container. It is the default.
+	VirtTypeContainer = "container"
+
+	// VirtTypeVirtualMachine is the virt-type constraint value
+	// for a virtual machine.
+	VirtTypeVirtualMachine = "virtual-machine"
+)
+
+// VirtTypes are the values of the virt-type constraint supported for LXD
+// instances.
+var VirtTypes = []string{VirtTypeContainer, VirtTypeVirtualMachine}
+
+// IsVirtualMachine returns true if the input constraints
+// request a virtual machine rather than a container.
+func IsVirtualMachine(cons constraints.Value) bool {
+	return cons.HasVirtType() && *cons.VirtType == VirtTypeVirtualMachine
+}
+
+// cloudInitConfigDevice is the disk device through which LXD supplies
+// cloud-init user data to a virtual machine. Containers read it from the
+// instance config directly.
+var cloudInitConfigDevice = device{
+	"type":   "disk",
+	"source": "cloud-init:config",
+}
+
+// VirtualMachineImage identifies the image from which to create a
+// virtual machine. The LXD server selects the virtual machine variant of
+// the image, and downloads it from the remote itself.
+type VirtualMachineImage struct {
+	// Remote is the simplestreams server supplying the image.
+	Remote ServerSpec
+	// Alias is the alias of the image on the remote.
+	Alias string
+}
+
+// FindVirtualMachineImage returns the image from which to create a virtual
+// machine running the input series and architecture, from the first of the
+// input sources using the simplestreams protocol.
+func FindVirtualMachineImage(series, arch string, sources []ServerSpec) (VirtualMachineImage, error) {
+	aliases, err := seriesRemoteAliases(series, arch)
+	if err != nil {
+		return VirtualMachineImage{}, errors.Trace(err)
+	}
+	for _, remote := range sources {
+		if remote.Protocol == SimpleStreamsProtocol {
+			return VirtualMachineImage{Remote: remote, Alias: aliases[0]}, nil
+		}
+	}
+	return VirtualMachineImage{}, errors.NotFoundf("simplestreams source for virtual machine image %q", aliases[0])
+}
+
+// VirtualMachinesSupported returns true if the LXD server can
+// run virtual machines.
+func (s *Server) VirtualMachinesSupported() bool {
+	return s.vmAPISupport
+}
+
+// instancesPost is the request body for creating an instance.
+type instancesPost struct {
+	api.ContainerPut
+
+	Name         string              `json:"name"`
+	Type         string              `json:"type"`
+	InstanceType string              `json:"instance_type"`
+	Source       api.ContainerSource `json:"source"`
+}
+
+// instanceEntry is an entry in the server's list of instances.
+type instanceEntry struct {
+	api.Container
+
+	Type string `json:"type"`
+}
+
+func instancePath(name string) string {
+	return "/instances/" + url.PathEscape(name)
+}
+
+// createVirtualMachine creates a virtual machine based on the input spec,
+// and starts it immediately.
+// If the virtual machine fails to be started, it is removed.
+func (s *Server) createVirtualMachine(spec ContainerSpec) (*Container, error) {
+	if !s.vmAPISupport {
+		return nil, errors.NotSupportedf("virtual machines on LXD server %q", s.name)
+	}
+	logger.Infof("starting new virtual machine %q (image %q from %q)", spec.Name, spec.VMImage.Alias, spec.VMImage.Remote.Host)
+	logger.Debugf("new virtual machine has profiles %v", spec.Profiles)
+
+	devices, err := s.virtualMachineDevices(spec)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	req := instancesPost{
+		Name:         spec.Name,
+		Type:         VirtTypeVirtualMachine,
+		InstanceType: spec.InstanceType,
+		ContainerPut: api.ContainerPut{
+			Profiles:  spec.Profiles,
+			Devices:   devices,
+			Config:    spec.Config,
+			Ephemeral: false,
+		},
+		Source: api.ContainerSource{
+			Type:     "image",
+			Mode:     "pull",
+			Server:   spec.VMImage.Remote.Host,
+			Protocol: string(spec.VMImage.Remote.Protocol),
+			Alias:    spec.VMImage.Alias,
+		},
+	}
+	op, _, err := s.RawOperation("POST", "/instances", req, "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := op.Wait(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if opInfo := op.Get(); opInfo.StatusCode != api.Success {
+		return nil, fmt.Errorf("virtual machine creation failed: %s", opInfo.Err)
+	}
+
+	logger.Debugf("created virtual machine %q, waiting for start...", spec.Name)
+
+	if err := s.updateVirtualMachineState(spec.Name, "start", false); err != nil {
+		if remErr := s.removeVirtualMachine(spec.Name); remErr != nil {
+			logger.Errorf("failed to remove virtual machine after unsuccessful start: %s", remErr.Error())
+		}
+		return nil, errors.Trace(err)
+	}
+
+	resp, _, err := s.RawQuery("GET", instancePath(spec.Name), nil, "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var vm api.Container
+	if err := resp.MetadataAsStruct(&vm); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Container{vm}, nil
+}
+
+// virtualMachineDevices returns the devices for a new virtual machine.
+// These are the devices from the spec, with the cloud-init config disk,
+// and the root disk from the spec's profiles sized according to the spec.
+func (s *Server) virtualMachineDevices(spec ContainerSpec) (map[string]device, error) {
+	devices := map[string]device{"config": cloudInitConfigDevice}
+	for name, dev := range spec.Devices {
+		devices[name] = dev
+	}
+	if spec.RootDiskMiB == 0 {
+		return devices, nil
+	}
+
+	// The root disk is overridden in the instance's own devices, so it
+	// must be a copy of the one it would otherwise inherit. Later profiles
+	// take precedence over earlier ones. LXD applies the default profile to
+	// instances created without any.
+	profiles := spec.Profiles
+	if len(profiles) == 0 {
+		profiles = []string{lxdDefaultProfileName}
+	}
+	var rootName string
+	var root device
+	for _, name := range profiles {
+		profile, _, err := s.GetProfile(name)
+		if err != nil {
+			return nil, errors.Annotatef(err, "getting profile %q", name)
+		}
+		for devName, dev := range profile.Devices {
+			if dev["type"] == "disk" && dev["path"] == "/" {
+				rootName, root = devName, dev
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.Errorf("no root disk device in profiles %v", profiles)
+	}
+	sized := device{"size": fmt.Sprintf("%dMiB", spec.RootDiskMiB)}
+	for k, v := range root {
+		if k != "size" {
+			sized[k] = v
+		}
+	}
+	devices[rootName] = sized
+	return devices, nil
+}
+
+// virtualMachines returns the virtual machines on the server.
+func (s *Server) virtualMachines() ([]api.Container, error) {
+	resp, _, err := s.RawQuery("GET", "/instances?recursion=1", nil, "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var insts []instanceEntry
+	if err := resp.MetadataAsStruct(&insts); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var vms []api.Container
+	for _, inst := range insts {
+		if inst.Type == VirtTypeVirtualMachine {
+			vms = append(vms, inst.Container)
+		}
+	}
+	return vms, nil
+}
+
+// updateVirtualMachineState applies the input action to the named
+// virtual machine.
+func (s *Server) updateVirtualMachineState(name, action string, force bool) error {
+	req := api.ContainerStatePut{
+		Action:  action,
+		Timeout: -1,
+		Force:   force,
+	}
+	op, _, err := s.RawOperation("PUT", instancePath(name)+"/state", req, "")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(op.Wait())
+}
+
+// UpdateVirtualMachineConfig updates the configuration of the named
+// virtual machine with the input values. LXD only applies resource limits
+// to a virtual machine as it boots, so a running virtual machine is
+// restarted for the new values to take effect.
+func (s *Server) UpdateVirtualMachineConfig(name string, cfg map[string]string) error {
+	if !s.vmAPISupport {
+		return errors.NotSupportedf("virtual machines on LXD server %q", s.name)
+	}
+	resp, eTag, err := s.RawQuery("GET", instancePath(name), nil, "")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var vm api.Container
+	if err := resp.MetadataAsStruct(&vm); err != nil {
+		return errors.Trace(err)
+	}
+	if vm.Config == nil {
+		vm.Config = make(map[string]string)
+	}
+	for k, v := range cfg {
+		vm.Config[k] = v
+	}
+	op, _, err := s.RawOperation("PUT", instancePath(name), vm.Writable(), eTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := op.Wait(); err != nil {
+		return errors.Trace(err)
+	}
+	if vm.StatusCode == api.Stopped {
+		return nil
+	}
+	logger.Infof("restarting virtual machine %q to apply its new configuration", name)
+	return errors.Trace(s.updateVirtualMachineState(name, "restart", false))
+}
+
+// removeVirtualMachine ensures that the named virtual machine is stopped,
+// then deletes it.
+func (s *Server) removeVirtualMachine(name string) error {
+	state, err := virtualMachineState(s, name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if state.StatusCode != api.Stopped {
+		if err := s.updateVirtualMachineState(name, "stop", true); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	op, _, err := s.RawOperation("DELETE", instancePath(name), nil, "")
+	if err != nil {
+		if IsLXDNotFound(errors.Cause(err)) {
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	return errors.Trace(op.Wait())
+}
+
+// virtualMachineState returns the state of the named virtual machine.
+func virtualMachineState(svr lxd.ContainerServer, name string) (*api.ContainerState, error) {
+	resp, _, err := svr.RawQuery("GET", instancePath(name)+"/state", nil, "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var state api.ContainerState
+	if err := resp.MetadataAsStruct(&state); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &state, nil
+}
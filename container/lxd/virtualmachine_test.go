@@ -0,0 +1,231 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/golang/mock/gomock"
+	jc "github.com/juju/testing/checkers"
+	"github.com/lxc/lxd/shared/api"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container/lxd"
+	lxdtesting "github.com/juju/juju/container/lxd/testing"
+	"github.com/juju/juju/core/constraints"
+)
+
+type virtualMachineSuite struct {
+	lxdtesting.BaseSuite
+}
+
+var _ = gc.Suite(&virtualMachineSuite{})
+
+func (s *virtualMachineSuite) TestSpecApplyConstraintsVirtualMachine(c *gc.C) {
+	spec := lxd.ContainerSpec{Config: map[string]string{}}
+	spec.ApplyConstraints("3.19", constraints.MustParse("virt-type=virtual-machine root-disk=20G cores=2"))
+	c.Check(spec.VirtType, gc.Equals, lxd.VirtTypeVirtualMachine)
+	c.Check(spec.RootDiskMiB, gc.Equals, uint64(20480))
+	c.Check(spec.Config["limits.cpu"], gc.Equals, "2")
+
+	// The root disk size does not apply to containers.
+	spec = lxd.ContainerSpec{Config: map[string]string{}}
+	spec.ApplyConstraints("3.19", constraints.MustParse("virt-type=container root-disk=20G"))
+	c.Check(spec.VirtType, gc.Equals, "")
+	c.Check(spec.RootDiskMiB, gc.Equals, uint64(0))
+}
+
+func (s *virtualMachineSuite) TestFindVirtualMachineImage(c *gc.C) {
+	sources := []lxd.ServerSpec{
+		{Name: "local", Protocol: lxd.LXDProtocol},
+		lxd.CloudImagesRemote,
+		lxd.CloudImagesDailyRemote,
+	}
+	image, err := lxd.FindVirtualMachineImage("bionic", "amd64", sources)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(image, gc.DeepEquals, lxd.VirtualMachineImage{Remote: lxd.CloudImagesRemote, Alias: "bionic/amd64"})
+
+	_, err = lxd.FindVirtualMachineImage("bionic", "amd64", sources[:1])
+	c.Check(err, gc.ErrorMatches, `simplestreams source for virtual machine image "bionic/amd64" not found`)
+}
+
+func (s *virtualMachineSuite) TestCreateVirtualMachineNotSupported(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServer(ctrl)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = jujuSvr.CreateContainerFromSpec(lxd.ContainerSpec{Name: "vm1", VirtType: lxd.VirtTypeVirtualMachine})
+	c.Assert(err, gc.ErrorMatches, `virtual machines on LXD server "none" not supported`)
+}
+
+func (s *virtualMachineSuite) TestCreateVirtualMachine(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "virtual-machines")
+
+	createOp := lxdtesting.NewMockOperation(ctrl)
+	createOp.EXPECT().Wait().Return(nil)
+	createOp.EXPECT().Get().Return(api.Operation{StatusCode: api.Success})
+
+	startOp := lxdtesting.NewMockOperation(ctrl)
+	startOp.EXPECT().Wait().Return(nil)
+
+	spec := lxd.ContainerSpec{
+		Name:        "vm1",
+		VirtType:    lxd.VirtTypeVirtualMachine,
+		VMImage:     lxd.VirtualMachineImage{Remote: lxd.CloudImagesRemote, Alias: "bionic/amd64"},
+		Profiles:    []string{"default", "juju-model"},
+		Config:      map[string]string{"limits.cpu": "2"},
+		RootDiskMiB: 20480,
+	}
+
+	var createReq interface{}
+	vm, _ := json.Marshal(api.Container{Name: "vm1"})
+	exp := cSvr.EXPECT()
+	gomock.InOrder(
+		exp.GetProfile("default").Return(&api.Profile{ProfilePut: api.ProfilePut{
+			Devices: map[string]map[string]string{
+				"root": {"type": "disk", "path": "/", "pool": "default"},
+			},
+		}}, lxdtesting.ETag, nil),
+		exp.GetProfile("juju-model").Return(&api.Profile{}, lxdtesting.ETag, nil),
+		exp.RawOperation("POST", "/instances", gomock.Any(), "").DoAndReturn(
+			func(method, path string, req interface{}, eTag string) (*lxdtesting.MockOperation, string, error) {
+				createReq = req
+				return createOp, "", nil
+			}),
+		exp.RawOperation("PUT", "/instances/vm1/state", api.ContainerStatePut{Action: "start", Timeout: -1}, "").Return(startOp, "", nil),
+		exp.RawQuery("GET", "/instances/vm1", nil, "").Return(&api.Response{Metadata: vm}, lxdtesting.ETag, nil),
+	)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	container, err := jujuSvr.CreateContainerFromSpec(spec)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(container.Name, gc.Equals, "vm1")
+
+	data, err := json.Marshal(createReq)
+	c.Assert(err, jc.ErrorIsNil)
+	var req map[string]interface{}
+	c.Assert(json.Unmarshal(data, &req), jc.ErrorIsNil)
+	c.Check(req["type"], gc.Equals, "virtual-machine")
+	c.Check(req["devices"], jc.DeepEquals, map[string]interface{}{
+		"root":   map[string]interface{}{"type": "disk", "path": "/", "pool": "default", "size": "20480MiB"},
+		"config": map[string]interface{}{"type": "disk", "source": "cloud-init:config"},
+	})
+	source := req["source"].(map[string]interface{})
+	c.Check(source["mode"], gc.Equals, "pull")
+	c.Check(source["protocol"], gc.Equals, "simplestreams")
+	c.Check(source["server"], gc.Equals, lxd.CloudImagesRemote.Host)
+	c.Check(source["alias"], gc.Equals, "bionic/amd64")
+}
+
+func (s *virtualMachineSuite) TestFilterContainersIncludesVirtualMachines(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "virtual-machines")
+
+	instances, _ := json.Marshal([]map[string]interface{}{
+		{"name": "prefix-c1", "type": "container", "status_code": api.Running},
+		{"name": "prefix-vm1", "type": "virtual-machine", "status_code": api.Running},
+	})
+	exp := cSvr.EXPECT()
+	exp.GetContainers().Return([]api.Container{{Name: "prefix-c1", StatusCode: api.Running}}, nil)
+	exp.RawQuery("GET", "/instances?recursion=1", nil, "").Return(&api.Response{Metadata: instances}, "", nil)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	filtered, err := jujuSvr.FilterContainers("prefix", "Running")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(filtered, gc.HasLen, 2)
+	c.Check(filtered[0].Name, gc.Equals, "prefix-c1")
+	c.Check(filtered[1].Name, gc.Equals, "prefix-vm1")
+}
+
+func (s *virtualMachineSuite) TestRemoveContainerVirtualMachine(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "virtual-machines")
+
+	stopOp := lxdtesting.NewMockOperation(ctrl)
+	stopOp.EXPECT().Wait().Return(nil)
+	deleteOp := lxdtesting.NewMockOperation(ctrl)
+	deleteOp.EXPECT().Wait().Return(nil)
+
+	state, _ := json.Marshal(api.ContainerState{StatusCode: api.Running})
+	stopReq := api.ContainerStatePut{Action: "stop", Timeout: -1, Force: true}
+	exp := cSvr.EXPECT()
+	gomock.InOrder(
+		exp.GetContainerState("vm1").Return(nil, "", errors.New("not found")),
+		exp.RawQuery("GET", "/instances/vm1/state", nil, "").Return(&api.Response{Metadata: state}, "", nil),
+		exp.RawOperation("PUT", "/instances/vm1/state", stopReq, "").Return(stopOp, "", nil),
+		exp.RawOperation("DELETE", "/instances/vm1", nil, "").Return(deleteOp, "", nil),
+	)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.RemoveContainer("vm1")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *virtualMachineSuite) TestUpdateVirtualMachineConfigRestartsRunning(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "virtual-machines")
+
+	updateOp := lxdtesting.NewMockOperation(ctrl)
+	updateOp.EXPECT().Wait().Return(nil)
+	restartOp := lxdtesting.NewMockOperation(ctrl)
+	restartOp.EXPECT().Wait().Return(nil)
+
+	vm, _ := json.Marshal(api.Container{
+		Name:         "vm1",
+		StatusCode:   api.Running,
+		ContainerPut: api.ContainerPut{Config: map[string]string{"limits.cpu": "2"}},
+	})
+	updated := api.ContainerPut{Config: map[string]string{"limits.cpu": "4"}}
+	restartReq := api.ContainerStatePut{Action: "restart", Timeout: -1}
+	exp := cSvr.EXPECT()
+	gomock.InOrder(
+		exp.RawQuery("GET", "/instances/vm1", nil, "").Return(&api.Response{Metadata: vm}, lxdtesting.ETag, nil),
+		exp.RawOperation("PUT", "/instances/vm1", updated, lxdtesting.ETag).Return(updateOp, "", nil),
+		exp.RawOperation("PUT", "/instances/vm1/state", restartReq, "").Return(restartOp, "", nil),
+	)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.UpdateVirtualMachineConfig("vm1", map[string]string{"limits.cpu": "4"})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *virtualMachineSuite) TestUpdateVirtualMachineConfigStopped(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "virtual-machines")
+
+	updateOp := lxdtesting.NewMockOperation(ctrl)
+	updateOp.EXPECT().Wait().Return(nil)
+
+	vm, _ := json.Marshal(api.Container{Name: "vm1", StatusCode: api.Stopped})
+	updated := api.ContainerPut{Config: map[string]string{"limits.memory": "4096MiB"}}
+	exp := cSvr.EXPECT()
+	gomock.InOrder(
+		exp.RawQuery("GET", "/instances/vm1", nil, "").Return(&api.Response{Metadata: vm}, lxdtesting.ETag, nil),
+		exp.RawOperation("PUT", "/instances/vm1", updated, lxdtesting.ETag).Return(updateOp, "", nil),
+	)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.UpdateVirtualMachineConfig("vm1", map[string]string{"limits.memory": "4096MiB"})
+	c.Assert(err, jc.ErrorIsNil)
+}
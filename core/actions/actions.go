@@ -5,6 +5,8 @@
 package actions
 
 import (
+	"time"
+
 	"gopkg.in/juju/charm.v6"
 )
 
@@ -37,3 +39,83 @@ var PredefinedActionsSpec = map[string]charm.ActionSpec{
 		},
 	},
 }
+
+const (
+	// ConnectivityListenActionName defines the action name used to open
+	// temporary TCP and UDP listeners on a machine.
+	ConnectivityListenActionName = "juju-connectivity-listen"
+
+	// ConnectivityProbeActionName defines the action name used to probe
+	// the listeners opened by ConnectivityListenActionName from another
+	// machine.
+	ConnectivityProbeActionName = "juju-connectivity-probe"
+)
+
+// MaxConnectivityListenTimeout is the longest the listeners opened by
+// ConnectivityListenActionName may be kept open.
+const MaxConnectivityListenTimeout = 10 * time.Minute
+
+// PredefinedMachineActionsSpec defines a spec for each predefined action
+// that may only be run by a machine agent.
+var PredefinedMachineActionsSpec = map[string]charm.ActionSpec{
+	ConnectivityListenActionName: {
+		Description: "open temporary listeners for a connectivity check",
+		Params: map[string]interface{}{
+			"type":        "object",
+			"title":       ConnectivityListenActionName,
+			"description": "predefined juju-connectivity-listen action params",
+			"required":    []interface{}{"address", "timeout"},
+			"properties": map[string]interface{}{
+				"address": map[string]interface{}{
+					"type":        "string",
+					"description": "address to listen on, which must be configured on the machine",
+				},
+				"port": map[string]interface{}{
+					"type":        "integer",
+					"description": "unprivileged port to listen on; a free port is chosen if not set",
+				},
+				"timeout": map[string]interface{}{
+					"type":        "number",
+					"description": "how long the listeners stay open",
+				},
+			},
+		},
+	},
+	ConnectivityProbeActionName: {
+		Description: "probe the listeners of a connectivity check",
+		Params: map[string]interface{}{
+			"type":        "object",
+			"title":       ConnectivityProbeActionName,
+			"description": "predefined juju-connectivity-probe action params",
+			"required":    []interface{}{"address", "tcp-port", "udp-port", "timeout"},
+			"properties": map[string]interface{}{
+				"address": map[string]interface{}{
+					"type":        "string",
+					"description": "address to probe",
+				},
+				"tcp-port": map[string]interface{}{
+					"type":        "integer",
+					"description": "TCP port to probe",
+				},
+				"udp-port": map[string]interface{}{
+					"type":        "integer",
+					"description": "UDP port to probe",
+				},
+				"timeout": map[string]interface{}{
+					"type":        "number",
+					"description": "timeout for each probe",
+				},
+			},
+		},
+	},
+}
+
+// MachineActionSpec returns the spec of the named predefined action if
+// it can be run by a machine agent.
+func MachineActionSpec(name string) (charm.ActionSpec, bool) {
+	if spec, ok := PredefinedActionsSpec[name]; ok {
+		return spec, true
+	}
+	spec, ok := PredefinedMachineActionsSpec[name]
+	return spec, ok
+}
@@ -34,6 +34,9 @@ const (
 	Spaces         = "spaces"
 	VirtType       = "virt-type"
 	Zones          = "zones"
+	Spot           = "spot"
+	MaxPrice       = "max-price"
+	AntiAffinity   = "anti-affinity"
 )
 
 // Value describes a user's requirements of the hardware on which units
@@ -97,6 +100,22 @@ type Value struct {
 	// Zones, if not nil, holds a list of availability zones limiting where
 	// the machine can be located.
 	Zones *[]string `json:"zones,omitempty" yaml:"zones,omitempty"`
+
+	// Spot, if not nil, indicates whether a machine should be started as
+	// a spot (preemptible) instance. Only valid for clouds which support
+	// spot instances.
+	Spot *bool `json:"spot,omitempty" yaml:"spot,omitempty"`
+
+	// MaxPrice, if not nil or empty, holds the maximum hourly price, in
+	// the cloud's currency, that may be paid for a spot instance. Setting
+	// a maximum price implies a spot instance.
+	MaxPrice *string `json:"max-price,omitempty" yaml:"max-price,omitempty"`
+
+	// AntiAffinity, if not nil or empty, holds the name of an anti-affinity
+	// group, typically that of an application. Machines in the same group
+	// are never placed on the same host. Only valid for clouds which can
+	// guarantee this, such as by using placement or server groups.
+	AntiAffinity *string `json:"anti-affinity,omitempty" yaml:"anti-affinity,omitempty"`
 }
 
 var rawAliases = map[string]string{
@@ -208,6 +227,27 @@ func (v *Value) HasZones() bool {
 	return v.Zones != nil && len(*v.Zones) > 0
 }
 
+// HasSpot returns true if the constraints.Value requests a spot instance,
+// either explicitly or by specifying a maximum price.
+func (v *Value) HasSpot() bool {
+	if v.Spot != nil {
+		return *v.Spot
+	}
+	return v.HasMaxPrice()
+}
+
+// HasMaxPrice returns true if the constraints.Value specifies a maximum
+// spot price.
+func (v *Value) HasMaxPrice() bool {
+	return v.MaxPrice != nil && *v.MaxPrice != ""
+}
+
+// HasAntiAffinity returns true if the constraints.Value specifies an
+// anti-affinity group.
+func (v *Value) HasAntiAffinity() bool {
+	return v.AntiAffinity != nil && *v.AntiAffinity != ""
+}
+
 // String expresses a constraints.Value in the language in which it was specified.
 func (v Value) String() string {
 	var strs []string
@@ -259,6 +299,15 @@ func (v Value) String() string {
 		s := strings.Join(*v.Zones, ",")
 		strs = append(strs, "zones="+s)
 	}
+	if v.Spot != nil {
+		strs = append(strs, "spot="+strconv.FormatBool(*v.Spot))
+	}
+	if v.MaxPrice != nil {
+		strs = append(strs, "max-price="+(*v.MaxPrice))
+	}
+	if v.AntiAffinity != nil {
+		strs = append(strs, "anti-affinity="+(*v.AntiAffinity))
+	}
 	return strings.Join(strs, " ")
 }
 
@@ -305,6 +354,15 @@ func (v Value) GoString() string {
 	} else if v.Zones != nil {
 		values = append(values, "Zones: (*[]string)(nil)")
 	}
+	if v.Spot != nil {
+		values = append(values, fmt.Sprintf("Spot: %v", *v.Spot))
+	}
+	if v.MaxPrice != nil {
+		values = append(values, fmt.Sprintf("MaxPrice: %q", *v.MaxPrice))
+	}
+	if v.AntiAffinity != nil {
+		values = append(values, fmt.Sprintf("AntiAffinity: %q", *v.AntiAffinity))
+	}
 	return fmt.Sprintf("{%s}", strings.Join(values, ", "))
 }
 
@@ -465,6 +523,12 @@ func (v *Value) setRaw(name, str string) error {
 		err = v.setVirtType(str)
 	case Zones:
 		err = v.setZones(str)
+	case Spot:
+		err = v.setSpot(str)
+	case MaxPrice:
+		err = v.setMaxPrice(str)
+	case AntiAffinity:
+		err = v.setAntiAffinity(str)
 	default:
 		return errors.Errorf("unknown constraint %q", name)
 	}
@@ -532,6 +596,12 @@ func (v *Value) UnmarshalYAML(unmarshal func(interface{}) error) error {
 			v.VirtType = &vstr
 		case Zones:
 			v.Zones, err = parseYamlStrings("zones", val)
+		case Spot:
+			v.Spot, err = parseBool(vstr)
+		case MaxPrice:
+			v.MaxPrice, err = parsePrice(vstr)
+		case AntiAffinity:
+			v.AntiAffinity, err = parseAntiAffinity(vstr)
 		default:
 			return errors.Errorf("unknown constraint value: %v", k)
 		}
@@ -672,6 +742,61 @@ func (v *Value) setZones(str string) error {
 	return nil
 }
 
+func (v *Value) setSpot(str string) (err error) {
+	if v.Spot != nil {
+		return errors.Errorf("already set")
+	}
+	v.Spot, err = parseBool(str)
+	return
+}
+
+func (v *Value) setMaxPrice(str string) (err error) {
+	if v.MaxPrice != nil {
+		return errors.Errorf("already set")
+	}
+	v.MaxPrice, err = parsePrice(str)
+	return
+}
+
+func (v *Value) setAntiAffinity(str string) (err error) {
+	if v.AntiAffinity != nil {
+		return errors.Errorf("already set")
+	}
+	v.AntiAffinity, err = parseAntiAffinity(str)
+	return
+}
+
+func parseBool(str string) (*bool, error) {
+	var value bool
+	if str != "" {
+		val, err := strconv.ParseBool(str)
+		if err != nil {
+			return nil, errors.Errorf("must be true or false")
+		}
+		value = val
+	}
+	return &value, nil
+}
+
+func parsePrice(str string) (*string, error) {
+	if str != "" {
+		val, err := strconv.ParseFloat(str, 64)
+		if err != nil || val <= 0 || math.IsInf(val, 0) {
+			return nil, errors.Errorf("must be a positive decimal number")
+		}
+	}
+	return &str, nil
+}
+
+// parseAntiAffinity checks that an anti-affinity group is named in the same
+// way as an application, so that the name can be used in cloud resources.
+func parseAntiAffinity(str string) (*string, error) {
+	if str != "" && !names.IsValidApplication(str) {
+		return nil, errors.Errorf("%q is not a valid anti-affinity group name", str)
+	}
+	return &str, nil
+}
+
 func parseUint64(str string) (*uint64, error) {
 	var value uint64
 	if str != "" {
@@ -342,6 +342,64 @@ var parseConstraintsTests = []struct {
 		args:    []string{"zones="},
 	},
 
+	// Spot
+	{
+		summary: "set spot",
+		args:    []string{"spot=true"},
+	}, {
+		summary: "clear spot",
+		args:    []string{"spot=false"},
+	}, {
+		summary: "set empty spot",
+		args:    []string{"spot="},
+	}, {
+		summary: "set invalid spot",
+		args:    []string{"spot=maybe"},
+		err:     `bad "spot" constraint: must be true or false`,
+	}, {
+		summary: "double set spot",
+		args:    []string{"spot=true", "spot=false"},
+		err:     `bad "spot" constraint: already set`,
+	},
+
+	// MaxPrice
+	{
+		summary: "set max-price",
+		args:    []string{"max-price=0.05"},
+	}, {
+		summary: "set empty max-price",
+		args:    []string{"max-price="},
+	}, {
+		summary: "set zero max-price",
+		args:    []string{"max-price=0"},
+		err:     `bad "max-price" constraint: must be a positive decimal number`,
+	}, {
+		summary: "set invalid max-price",
+		args:    []string{"max-price=cheap"},
+		err:     `bad "max-price" constraint: must be a positive decimal number`,
+	}, {
+		summary: "double set max-price",
+		args:    []string{"max-price=1", "max-price=2"},
+		err:     `bad "max-price" constraint: already set`,
+	},
+
+	// AntiAffinity
+	{
+		summary: "set anti-affinity",
+		args:    []string{"anti-affinity=postgresql"},
+	}, {
+		summary: "set empty anti-affinity",
+		args:    []string{"anti-affinity="},
+	}, {
+		summary: "set invalid anti-affinity",
+		args:    []string{"anti-affinity=Not_Valid"},
+		err:     `bad "anti-affinity" constraint: "Not_Valid" is not a valid anti-affinity group name`,
+	}, {
+		summary: "double set anti-affinity",
+		args:    []string{"anti-affinity=a", "anti-affinity=b"},
+		err:     `bad "anti-affinity" constraint: already set`,
+	},
+
 	// Everything at once.
 	{
 		summary: "kitchen sink together",
@@ -480,6 +538,37 @@ func (s *ConstraintsSuite) TestHasZones(c *gc.C) {
 	c.Check(con.HasZones(), jc.IsFalse)
 }
 
+func (s *ConstraintsSuite) TestHasSpot(c *gc.C) {
+	con := constraints.MustParse("spot=true")
+	c.Check(con.HasSpot(), jc.IsTrue)
+	c.Check(con.HasMaxPrice(), jc.IsFalse)
+
+	con = constraints.MustParse("max-price=0.1")
+	c.Check(con.HasSpot(), jc.IsTrue)
+	c.Check(con.HasMaxPrice(), jc.IsTrue)
+
+	con = constraints.MustParse("spot=false")
+	c.Check(con.HasSpot(), jc.IsFalse)
+
+	con = constraints.MustParse("max-price=")
+	c.Check(con.HasSpot(), jc.IsFalse)
+	c.Check(con.HasMaxPrice(), jc.IsFalse)
+
+	con = constraints.MustParse("mem=4G")
+	c.Check(con.HasSpot(), jc.IsFalse)
+}
+
+func (s *ConstraintsSuite) TestHasAntiAffinity(c *gc.C) {
+	con := constraints.MustParse("anti-affinity=postgresql")
+	c.Check(con.HasAntiAffinity(), jc.IsTrue)
+
+	con = constraints.MustParse("anti-affinity=")
+	c.Check(con.HasAntiAffinity(), jc.IsFalse)
+
+	con = constraints.MustParse("mem=4G")
+	c.Check(con.HasAntiAffinity(), jc.IsFalse)
+}
+
 func (s *ConstraintsSuite) TestHasRootDiskSource(c *gc.C) {
 	con := constraints.MustParse("root-disk-source=pilgrim")
 	c.Check(con.HasRootDiskSource(), jc.IsTrue)
@@ -531,6 +620,10 @@ func uint64p(i uint64) *uint64 {
 	return &i
 }
 
+func boolp(b bool) *bool {
+	return &b
+}
+
 func strp(s string) *string {
 	return &s
 }
@@ -577,6 +670,12 @@ var constraintsRoundtripTests = []roundTrip{
 	{"Zones1", constraints.Value{Zones: nil}},
 	{"Zones2", constraints.Value{Zones: &[]string{}}},
 	{"Zones3", constraints.Value{Zones: &[]string{"az1", "az2"}}},
+	{"Spot1", constraints.Value{Spot: boolp(true)}},
+	{"Spot2", constraints.Value{Spot: boolp(false)}},
+	{"MaxPrice1", constraints.Value{MaxPrice: strp("")}},
+	{"MaxPrice2", constraints.Value{MaxPrice: strp("0.25")}},
+	{"AntiAffinity1", constraints.Value{AntiAffinity: strp("")}},
+	{"AntiAffinity2", constraints.Value{AntiAffinity: strp("postgresql")}},
 	{"All", constraints.Value{
 		Arch:           strp("i386"),
 		Container:      ctypep("lxd"),
@@ -589,6 +688,9 @@ var constraintsRoundtripTests = []roundTrip{
 		Spaces:         &[]string{"space1", "^space2"},
 		InstanceType:   strp("foo"),
 		Zones:          &[]string{"az1", "az2"},
+		Spot:           boolp(true),
+		MaxPrice:       strp("0.25"),
+		AntiAffinity:   strp("postgresql"),
 	}},
 }
 
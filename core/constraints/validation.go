@@ -259,9 +259,36 @@ func (v *validator) Validate(cons Value) ([]string, error) {
 	if err := v.checkValidValues(cons); err != nil {
 		return unsupported, err
 	}
+	if err := checkSpot(cons); err != nil {
+		return unsupported, err
+	}
+	if err := checkAntiAffinity(unsupported); err != nil {
+		return unsupported, err
+	}
 	return unsupported, nil
 }
 
+// checkAntiAffinity returns an error if anti-affinity is among the
+// unsupported attributes. Unlike other constraints, anti-affinity is
+// a guarantee, so it cannot be ignored with a warning.
+func checkAntiAffinity(unsupported []string) error {
+	for _, attr := range unsupported {
+		if attr == AntiAffinity {
+			return fmt.Errorf("%q constraint not supported by this cloud", AntiAffinity)
+		}
+	}
+	return nil
+}
+
+// checkSpot returns an error if a maximum spot price is specified for
+// a machine which is explicitly not a spot instance.
+func checkSpot(cons Value) error {
+	if cons.Spot != nil && !*cons.Spot && cons.HasMaxPrice() {
+		return fmt.Errorf("ambiguous constraints: %q requires %q", MaxPrice, "spot=true")
+	}
+	return nil
+}
+
 // Merge is defined on Validator.
 func (v *validator) Merge(consFallback, cons Value) (Value, error) {
 	// First ensure both constraints are valid. We don't care if there
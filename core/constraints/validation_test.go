@@ -134,6 +134,29 @@ var validationTests = []struct {
 		cons:  "virt-type=bar",
 		vocab: map[string][]interface{}{"virt-type": {"bar"}},
 	},
+	{
+		desc: "spot with max-price",
+		cons: "spot=true max-price=0.1",
+	},
+	{
+		desc: "max-price without spot",
+		cons: "max-price=0.1",
+	},
+	{
+		desc: "max-price with spot disabled",
+		cons: "spot=false max-price=0.1",
+		err:  `ambiguous constraints: "max-price" requires "spot=true"`,
+	},
+	{
+		desc: "anti-affinity",
+		cons: "anti-affinity=postgresql",
+	},
+	{
+		desc:        "unsupported anti-affinity",
+		cons:        "mem=4G anti-affinity=postgresql",
+		unsupported: []string{"anti-affinity"},
+		err:         `"anti-affinity" constraint not supported by this cloud`,
+	},
 }
 
 func (s *validationSuite) TestValidation(c *gc.C) {
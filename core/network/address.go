@@ -671,6 +671,10 @@ func filterAndCollateAddressIndexes(
 	return matches
 }
 
+// scopeMatchHierarchy returns the order in which scope matches are
+// preferred. IPv4 addresses win over IPv6 addresses of the same scope, so
+// dual-stack machines are addressed over IPv4; IPv6 addresses are only
+// selected on machines with no suitable IPv4 address.
 func scopeMatchHierarchy() []ScopeMatch {
 	return []ScopeMatch{
 		exactScopeIPv4, exactScope,
@@ -0,0 +1,370 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package relation
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/gojsonschema"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/yaml.v2"
+)
+
+// SettingsSchema holds the JSON schemas that relation settings written
+// by one side of a relation must satisfy.
+type SettingsSchema struct {
+	// Unit is the JSON schema for the unit settings.
+	Unit map[string]interface{} `json:"unit,omitempty"`
+
+	// Application is the JSON schema for the application settings.
+	Application map[string]interface{} `json:"app,omitempty"`
+}
+
+// EndpointSchema holds the settings schemas a charm declares for one
+// of its relation endpoints, keyed by the role of the side writing the
+// settings. A charm may describe what it expects of the remote side as
+// well as what it writes itself.
+//
+// In metadata.yaml the schema is declared alongside the interface:
+//
+//	provides:
+//	  db:
+//	    interface: mysql
+//	    schema:
+//	      provider:
+//	        unit:
+//	          type: object
+//	          properties:
+//	            host: {type: string}
+//	          required: [host]
+//	      requirer:
+//	        app:
+//	          type: object
+//	          properties:
+//	            database: {type: string}
+type EndpointSchema map[charm.RelationRole]SettingsSchema
+
+// ParseSettingsSchemas extracts the relation settings schemas declared
+// in the supplied metadata.yaml content, keyed by endpoint name.
+// Endpoints without a schema are not included in the result.
+func ParseSettingsSchemas(metadata []byte) (map[string]EndpointSchema, error) {
+	var meta struct {
+		Provides map[string]interface{} `yaml:"provides"`
+		Requires map[string]interface{} `yaml:"requires"`
+		Peers    map[string]interface{} `yaml:"peers"`
+	}
+	if err := yaml.Unmarshal(metadata, &meta); err != nil {
+		return nil, errors.Annotate(err, "cannot parse charm metadata")
+	}
+	result := make(map[string]EndpointSchema)
+	for _, endpoints := range []map[string]interface{}{meta.Provides, meta.Requires, meta.Peers} {
+		for name, rel := range endpoints {
+			// A relation may be declared as a bare interface name.
+			relMap, ok := rel.(map[interface{}]interface{})
+			if !ok {
+				continue
+			}
+			raw, ok := relMap["schema"]
+			if !ok {
+				continue
+			}
+			schema, err := parseEndpointSchema(raw)
+			if err != nil {
+				return nil, errors.Annotatef(err, "relation %q schema", name)
+			}
+			result[name] = schema
+		}
+	}
+	return result, nil
+}
+
+func parseEndpointSchema(raw interface{}) (EndpointSchema, error) {
+	roles, ok := normaliseYAML(raw).(map[string]interface{})
+	if !ok {
+		return nil, errors.NotValidf("schema of type %T", raw)
+	}
+	result := make(EndpointSchema)
+	for role, value := range roles {
+		switch charm.RelationRole(role) {
+		case charm.RoleProvider, charm.RoleRequirer, charm.RolePeer:
+		default:
+			return nil, errors.NotValidf("role %q", role)
+		}
+		sides, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, errors.NotValidf("%s schema of type %T", role, value)
+		}
+		var schema SettingsSchema
+		for side, value := range sides {
+			doc, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, errors.NotValidf("%s %s schema of type %T", role, side, value)
+			}
+			if _, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(doc)); err != nil {
+				return nil, errors.Annotatef(err, "invalid %s %s schema", role, side)
+			}
+			switch side {
+			case "unit":
+				schema.Unit = doc
+			case "app":
+				schema.Application = doc
+			default:
+				return nil, errors.NotValidf("%s settings kind %q", role, side)
+			}
+		}
+		result[charm.RelationRole(role)] = schema
+	}
+	return result, nil
+}
+
+// normaliseYAML converts the maps produced by the yaml decoder into
+// the string-keyed maps expected by the JSON schema validator.
+func normaliseYAML(in interface{}) interface{} {
+	switch in := in.(type) {
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(in))
+		for k, v := range in {
+			out[fmt.Sprint(k)] = normaliseYAML(v)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(in))
+		for i, v := range in {
+			out[i] = normaliseYAML(v)
+		}
+		return out
+	}
+	return in
+}
+
+// MarshalSettingsSchemas serialises the supplied schemas to JSON text,
+// keyed by endpoint name, so they can be stored without concern for
+// characters that are not valid in document keys.
+func MarshalSettingsSchemas(schemas map[string]EndpointSchema) (map[string]string, error) {
+	if len(schemas) == 0 {
+		return nil, nil
+	}
+	result := make(map[string]string, len(schemas))
+	for name, schema := range schemas {
+		data, err := json.Marshal(schema)
+		if err != nil {
+			return nil, errors.Annotatef(err, "relation %q schema", name)
+		}
+		result[name] = string(data)
+	}
+	return result, nil
+}
+
+// UnmarshalEndpointSchema parses an endpoint schema previously serialised
+// by MarshalSettingsSchemas.
+func UnmarshalEndpointSchema(data string) (EndpointSchema, error) {
+	var schema EndpointSchema
+	if err := json.Unmarshal([]byte(data), &schema); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return schema, nil
+}
+
+// ValidateSettings checks the supplied settings, written by the side of
+// a relation with the given role, against the schema. Unit settings are
+// checked unless application is true. Settings are valid if no schema
+// was declared for them. As relation settings are always stored as
+// strings, string values are first converted to the type the schema
+// declares for them.
+func (s EndpointSchema) ValidateSettings(role charm.RelationRole, application bool, settings map[string]interface{}) error {
+	side, ok := s[role]
+	if !ok {
+		return nil
+	}
+	doc, kind := side.Unit, "unit"
+	if application {
+		doc, kind = side.Application, "application"
+	}
+	if doc == nil {
+		return nil
+	}
+	settings = coerceSettings(doc, settings)
+	result, err := gojsonschema.Validate(
+		gojsonschema.NewGoLoader(doc),
+		gojsonschema.NewGoLoader(settings),
+	)
+	if err != nil {
+		return errors.Annotatef(err, "validating %s settings", kind)
+	}
+	if result.Valid() {
+		return nil
+	}
+	var messages []string
+	for _, resultErr := range result.Errors() {
+		messages = append(messages, resultErr.Description)
+	}
+	sort.Strings(messages)
+	return errors.NotValidf("%s settings: %s", kind, strings.Join(messages, "; "))
+}
+
+// coerceSettings returns a copy of the supplied settings in which each
+// string value is converted to the first type the schema declares for
+// its property that the value can be parsed as. Values that cannot be
+// converted are left as they are, to be reported by validation.
+func coerceSettings(schema map[string]interface{}, settings map[string]interface{}) map[string]interface{} {
+	props := schemaProperties(schema)
+	result := make(map[string]interface{}, len(settings))
+	for key, value := range settings {
+		result[key] = value
+		s, ok := value.(string)
+		if !ok {
+			continue
+		}
+		for _, t := range propertyTypes(props[key]) {
+			if coerced, ok := coerceString(t, s); ok {
+				result[key] = coerced
+				break
+			}
+		}
+	}
+	return result
+}
+
+func coerceString(schemaType, value string) (interface{}, bool) {
+	switch schemaType {
+	case "string":
+		return value, true
+	case "integer":
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i, true
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f, true
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b, true
+		}
+	case "object", "array", "null":
+		var v interface{}
+		if err := json.Unmarshal([]byte(value), &v); err != nil {
+			return nil, false
+		}
+		switch v.(type) {
+		case map[string]interface{}:
+			return v, schemaType == "object"
+		case []interface{}:
+			return v, schemaType == "array"
+		case nil:
+			return v, schemaType == "null"
+		}
+	}
+	return nil, false
+}
+
+// CheckCompatibility compares the schemas declared by the two charms on
+// either side of a relation, and returns a description of every way in
+// which they disagree. Any role or settings kind not described by both
+// sides is considered compatible.
+func CheckCompatibility(local, remote EndpointSchema) []string {
+	var problems []string
+	for _, role := range []charm.RelationRole{charm.RoleProvider, charm.RoleRequirer, charm.RolePeer} {
+		localSide, ok := local[role]
+		if !ok {
+			continue
+		}
+		remoteSide, ok := remote[role]
+		if !ok {
+			continue
+		}
+		problems = append(problems, compareSchemas(
+			fmt.Sprintf("%s unit settings", role), localSide.Unit, remoteSide.Unit)...)
+		problems = append(problems, compareSchemas(
+			fmt.Sprintf("%s application settings", role), localSide.Application, remoteSide.Application)...)
+	}
+	return problems
+}
+
+func compareSchemas(what string, a, b map[string]interface{}) []string {
+	if a == nil || b == nil {
+		return nil
+	}
+	aProps, bProps := schemaProperties(a), schemaProperties(b)
+	var problems []string
+	for _, key := range schemaRequired(a) {
+		if _, ok := bProps[key]; !ok && !allowsAdditional(b) {
+			problems = append(problems, fmt.Sprintf("%s: %q is required by one side but not allowed by the other", what, key))
+		}
+	}
+	for _, key := range schemaRequired(b) {
+		if _, ok := aProps[key]; !ok && !allowsAdditional(a) {
+			problems = append(problems, fmt.Sprintf("%s: %q is required by one side but not allowed by the other", what, key))
+		}
+	}
+	for key, aProp := range aProps {
+		bProp, ok := bProps[key]
+		if !ok {
+			continue
+		}
+		aType, bType := propertyType(aProp), propertyType(bProp)
+		if aType != "" && bType != "" && aType != bType {
+			problems = append(problems, fmt.Sprintf("%s: %q has type %q on one side and %q on the other", what, key, aType, bType))
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+func schemaProperties(schema map[string]interface{}) map[string]interface{} {
+	props, _ := schema["properties"].(map[string]interface{})
+	return props
+}
+
+func schemaRequired(schema map[string]interface{}) []string {
+	raw, _ := schema["required"].([]interface{})
+	required := make([]string, 0, len(raw))
+	for _, key := range raw {
+		if s, ok := key.(string); ok {
+			required = append(required, s)
+		}
+	}
+	return required
+}
+
+func allowsAdditional(schema map[string]interface{}) bool {
+	allowed, ok := schema["additionalProperties"].(bool)
+	return !ok || allowed
+}
+
+// propertyTypes returns the types a property's schema allows, which
+// may be given as a single type or a list of types.
+func propertyTypes(prop interface{}) []string {
+	propMap, ok := prop.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	switch t := propMap["type"].(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, one := range t {
+			if s, ok := one.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+func propertyType(prop interface{}) string {
+	propMap, ok := prop.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	t, _ := propMap["type"].(string)
+	return t
+}
@@ -0,0 +1,213 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package relation_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6"
+
+	"github.com/juju/juju/core/relation"
+)
+
+type schemaSuite struct{}
+
+var _ = gc.Suite(&schemaSuite{})
+
+const schemaMetadata = `
+name: mysql
+summary: a database
+description: a database
+provides:
+  db:
+    interface: mysql
+    schema:
+      provider:
+        unit:
+          type: object
+          properties:
+            host: {type: string}
+            port: {type: string, pattern: "^[0-9]+$"}
+          required: [host]
+      requirer:
+        app:
+          type: object
+          properties:
+            database: {type: string}
+  metrics: prometheus
+peers:
+  cluster:
+    interface: mysql-ha
+`
+
+func (*schemaSuite) TestParseSettingsSchemas(c *gc.C) {
+	schemas, err := relation.ParseSettingsSchemas([]byte(schemaMetadata))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(schemas, gc.HasLen, 1)
+	db := schemas["db"]
+	c.Assert(db, gc.HasLen, 2)
+	c.Check(db[charm.RoleProvider].Unit["required"], jc.DeepEquals, []interface{}{"host"})
+	c.Check(db[charm.RoleProvider].Application, gc.IsNil)
+	c.Check(db[charm.RoleRequirer].Unit, gc.IsNil)
+	c.Check(db[charm.RoleRequirer].Application, gc.NotNil)
+}
+
+func (*schemaSuite) TestParseSettingsSchemasInvalidRole(c *gc.C) {
+	_, err := relation.ParseSettingsSchemas([]byte(`
+provides:
+  db:
+    interface: mysql
+    schema:
+      server:
+        unit: {type: object}
+`))
+	c.Assert(err, gc.ErrorMatches, `relation "db" schema: role "server" not valid`)
+}
+
+func (*schemaSuite) TestParseSettingsSchemasInvalidKind(c *gc.C) {
+	_, err := relation.ParseSettingsSchemas([]byte(`
+requires:
+  db:
+    interface: mysql
+    schema:
+      requirer:
+        model: {type: object}
+`))
+	c.Assert(err, gc.ErrorMatches, `relation "db" schema: requirer settings kind "model" not valid`)
+}
+
+func (*schemaSuite) TestMarshalRoundTrip(c *gc.C) {
+	schemas, err := relation.ParseSettingsSchemas([]byte(schemaMetadata))
+	c.Assert(err, jc.ErrorIsNil)
+	serialised, err := relation.MarshalSettingsSchemas(schemas)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(serialised, gc.HasLen, 1)
+
+	db, err := relation.UnmarshalEndpointSchema(serialised["db"])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(db[charm.RoleProvider].Unit["required"], jc.DeepEquals, []interface{}{"host"})
+}
+
+func (*schemaSuite) TestValidateSettings(c *gc.C) {
+	schemas, err := relation.ParseSettingsSchemas([]byte(schemaMetadata))
+	c.Assert(err, jc.ErrorIsNil)
+	db := schemas["db"]
+
+	err = db.ValidateSettings(charm.RoleProvider, false, map[string]interface{}{
+		"host": "10.0.0.1",
+		"port": "3306",
+	})
+	c.Check(err, jc.ErrorIsNil)
+
+	err = db.ValidateSettings(charm.RoleProvider, false, map[string]interface{}{
+		"port": "3306",
+	})
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, `unit settings: .*host.* not valid`)
+
+	err = db.ValidateSettings(charm.RoleProvider, false, map[string]interface{}{
+		"host": "10.0.0.1",
+		"port": "mysql",
+	})
+	c.Check(err, gc.ErrorMatches, `unit settings: .* not valid`)
+
+	// No schema is declared for provider application settings.
+	err = db.ValidateSettings(charm.RoleProvider, true, map[string]interface{}{
+		"anything": "goes",
+	})
+	c.Check(err, jc.ErrorIsNil)
+
+	// Nor for peers.
+	err = db.ValidateSettings(charm.RolePeer, false, nil)
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (*schemaSuite) TestValidateSettingsCoercesStrings(c *gc.C) {
+	schemas, err := relation.ParseSettingsSchemas([]byte(`
+name: db-client
+summary: a client
+description: a client
+requires:
+  db:
+    interface: mysql
+    schema:
+      requirer:
+        unit:
+          type: object
+          properties:
+            replicas: {type: integer, minimum: 1}
+            weight: {type: number}
+            readonly: {type: boolean}
+            tables: {type: array, items: {type: string}}
+            timeout: {type: [integer, "null"]}
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	db := schemas["db"]
+
+	err = db.ValidateSettings(charm.RoleRequirer, false, map[string]interface{}{
+		"replicas": "3",
+		"weight":   "0.5",
+		"readonly": "true",
+		"tables":   `["users", "orders"]`,
+		"timeout":  "null",
+	})
+	c.Check(err, jc.ErrorIsNil)
+
+	err = db.ValidateSettings(charm.RoleRequirer, false, map[string]interface{}{
+		"timeout": "30",
+	})
+	c.Check(err, jc.ErrorIsNil)
+
+	// Converted values are still checked against the schema.
+	err = db.ValidateSettings(charm.RoleRequirer, false, map[string]interface{}{
+		"replicas": "0",
+	})
+	c.Check(err, gc.ErrorMatches, `unit settings: .* not valid`)
+
+	// Values that cannot be converted fail validation.
+	err = db.ValidateSettings(charm.RoleRequirer, false, map[string]interface{}{
+		"replicas": "three",
+	})
+	c.Check(err, gc.ErrorMatches, `unit settings: .* not valid`)
+	err = db.ValidateSettings(charm.RoleRequirer, false, map[string]interface{}{
+		"tables": `{"users": true}`,
+	})
+	c.Check(err, gc.ErrorMatches, `unit settings: .* not valid`)
+}
+
+func (*schemaSuite) TestCheckCompatibility(c *gc.C) {
+	local := relation.EndpointSchema{
+		charm.RoleProvider: {
+			Unit: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"host": map[string]interface{}{"type": "string"},
+					"port": map[string]interface{}{"type": "string"},
+				},
+				"additionalProperties": false,
+			},
+		},
+	}
+	remote := relation.EndpointSchema{
+		charm.RoleProvider: {
+			Unit: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"port":     map[string]interface{}{"type": "integer"},
+					"password": map[string]interface{}{"type": "string"},
+				},
+				"required": []interface{}{"password"},
+			},
+		},
+		charm.RoleRequirer: {
+			Unit: map[string]interface{}{"type": "object"},
+		},
+	}
+	c.Check(relation.CheckCompatibility(local, remote), jc.DeepEquals, []string{
+		`provider unit settings: "password" is required by one side but not allowed by the other`,
+		`provider unit settings: "port" has type "string" on one side and "integer" on the other`,
+	})
+	c.Check(relation.CheckCompatibility(local, nil), gc.HasLen, 0)
+}
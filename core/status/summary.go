@@ -0,0 +1,65 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SummariseStatuses returns a summary of the given unit statuses, such
+// as "3/5 active, 2 maintenance". The most common status is listed
+// first, together with the total number of units. An empty string is
+// returned unless there are at least two distinct statuses, as a single
+// status is already conveyed by the application status.
+func SummariseStatuses(statuses []Status) string {
+	counts := make(map[Status]int)
+	var distinct []Status
+	for _, s := range statuses {
+		if counts[s] == 0 {
+			distinct = append(distinct, s)
+		}
+		counts[s]++
+	}
+	if len(distinct) < 2 {
+		return ""
+	}
+	sort.Slice(distinct, func(i, j int) bool {
+		if counts[distinct[i]] != counts[distinct[j]] {
+			return counts[distinct[i]] > counts[distinct[j]]
+		}
+		return distinct[i] < distinct[j]
+	})
+	parts := make([]string, len(distinct))
+	for i, s := range distinct {
+		if i == 0 {
+			parts[i] = fmt.Sprintf("%d/%d %s", counts[s], len(statuses), s)
+		} else {
+			parts[i] = fmt.Sprintf("%d %s", counts[s], s)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// VersionSkew groups unit names by the workload version reported by
+// each unit. Units which have not reported a version are ignored. If
+// fewer than two distinct versions are reported, there is no skew and
+// nil is returned.
+func VersionSkew(unitVersions map[string]string) map[string][]string {
+	skew := make(map[string][]string)
+	for unitName, version := range unitVersions {
+		if version == "" {
+			continue
+		}
+		skew[version] = append(skew[version], unitName)
+	}
+	if len(skew) < 2 {
+		return nil
+	}
+	for _, unitNames := range skew {
+		sort.Strings(unitNames)
+	}
+	return skew
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/status"
+)
+
+type SummarySuite struct{}
+
+var _ = gc.Suite(&SummarySuite{})
+
+func (s *SummarySuite) TestSummariseStatuses(c *gc.C) {
+	c.Check(status.SummariseStatuses(nil), gc.Equals, "")
+	c.Check(status.SummariseStatuses([]status.Status{
+		status.Active, status.Active, status.Active,
+	}), gc.Equals, "")
+	c.Check(status.SummariseStatuses([]status.Status{
+		status.Maintenance, status.Active, status.Active, status.Maintenance, status.Active,
+	}), gc.Equals, "3/5 active, 2 maintenance")
+	c.Check(status.SummariseStatuses([]status.Status{
+		status.Waiting, status.Blocked,
+	}), gc.Equals, "1/2 blocked, 1 waiting")
+}
+
+func (s *SummarySuite) TestVersionSkew(c *gc.C) {
+	c.Check(status.VersionSkew(nil), gc.IsNil)
+	c.Check(status.VersionSkew(map[string]string{
+		"mysql/0": "5.7",
+		"mysql/1": "5.7",
+		"mysql/2": "",
+	}), gc.IsNil)
+	c.Check(status.VersionSkew(map[string]string{
+		"mysql/0": "5.7",
+		"mysql/1": "8.0",
+		"mysql/2": "5.7",
+		"mysql/3": "",
+	}), jc.DeepEquals, map[string][]string{
+		"5.7": {"mysql/0", "mysql/2"},
+		"8.0": {"mysql/1"},
+	})
+}
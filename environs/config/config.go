@@ -168,6 +168,11 @@ const (
 	// The default filesystem storage source.
 	StorageDefaultFilesystemSourceKey = "storage-default-filesystem-source"
 
+	// StorageUsageWarningThreshold is the percentage of a filesystem's
+	// space or inodes in use above which the filesystem's status reports
+	// a warning. A value of 0 disables the warning.
+	StorageUsageWarningThreshold = "storage-usage-warning-threshold"
+
 	// ResourceTagsKey is an optional list or space-separated string
 	// of k=v pairs, defining the tags for ResourceTags.
 	ResourceTagsKey = "resource-tags"
@@ -253,6 +258,12 @@ const (
 	// machine worker not to discover any machine addresses
 	// on start up.
 	IgnoreMachineAddresses = "ignore-machine-addresses"
+
+	// ManageNetplanKey, when true, will cause the machine worker
+	// to apply the netplan configuration rendered from the machine's
+	// provider network devices, rolling back if applying it fails.
+	// Containers never apply it.
+	ManageNetplanKey = "manage-netplan"
 )
 
 // ParseHarvestMode parses description of harvesting method and
@@ -415,6 +426,10 @@ const (
 	DefaultActionResultsAge = "336h" // 2 weeks
 
 	DefaultActionResultsSize = "5G"
+
+	// DefaultStorageUsageWarningThreshold is the default value for
+	// StorageUsageWarningThreshold.
+	DefaultStorageUsageWarningThreshold = 90
 )
 
 var defaultConfigValues = map[string]interface{}{
@@ -422,6 +437,7 @@ var defaultConfigValues = map[string]interface{}{
 	"firewall-mode":              FwInstance,
 	"disable-network-management": false,
 	IgnoreMachineAddresses:       false,
+	ManageNetplanKey:             false,
 	"ssl-hostname-verification":  true,
 	"proxy-ssh":                  false,
 	DefaultSpace:                 "",
@@ -461,6 +477,7 @@ var defaultConfigValues = map[string]interface{}{
 	CloudInitUserDataKey:          "",
 	ContainerInheritPropertiesKey: "",
 	BackupDirKey:                  "",
+	StorageUsageWarningThreshold:  DefaultStorageUsageWarningThreshold,
 
 	// Image and agent streams and URLs.
 	"image-stream":               "released",
@@ -655,6 +672,12 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	if v, ok := cfg.defined[StorageUsageWarningThreshold].(int); ok {
+		if v < 0 || v > 100 {
+			return errors.Errorf("storage usage warning threshold %d must be between 0 and 100", v)
+		}
+	}
+
 	if v, ok := cfg.defined[EgressSubnets].(string); ok && v != "" {
 		cidrs := strings.Split(v, ",")
 		for _, cidr := range cidrs {
@@ -883,6 +906,16 @@ func (c *Config) ProxySSH() bool {
 	return value
 }
 
+// StorageUsageWarningThreshold returns the percentage of a filesystem's
+// space or inodes in use above which the filesystem's status reports a
+// warning. A value of 0 means no warning is reported.
+func (c *Config) StorageUsageWarningThreshold() int {
+	if value, ok := c.defined[StorageUsageWarningThreshold].(int); ok {
+		return value
+	}
+	return DefaultStorageUsageWarningThreshold
+}
+
 // NetBondReconfigureDelay returns the duration in seconds that should be
 // passed to the bridge script when bridging bonded interfaces.
 func (c *Config) NetBondReconfigureDelay() int {
@@ -1286,6 +1319,14 @@ func (c *Config) DisableNetworkManagement() (bool, bool) {
 	return v, ok
 }
 
+// ManageNetplan reports whether the machine agents apply the netplan
+// configuration rendered from the network devices the provider knows
+// about.
+func (c *Config) ManageNetplan() bool {
+	v, _ := c.defined[ManageNetplanKey].(bool)
+	return v
+}
+
 // IgnoreMachineAddresses reports whether Juju will discover
 // and store machine addresses on startup.
 func (c *Config) IgnoreMachineAddresses() (bool, bool) {
@@ -1529,6 +1570,7 @@ var alwaysOptional = schema.Defaults{
 	"proxy-ssh":                   schema.Omit,
 	"disable-network-management":  schema.Omit,
 	IgnoreMachineAddresses:        schema.Omit,
+	ManageNetplanKey:              schema.Omit,
 	AutomaticallyRetryHooks:       schema.Omit,
 	"test-mode":                   schema.Omit,
 	TransmitVendorMetricsKey:      schema.Omit,
@@ -1539,6 +1581,7 @@ var alwaysOptional = schema.Defaults{
 	MaxActionResultsAge:           schema.Omit,
 	MaxActionResultsSize:          schema.Omit,
 	UpdateStatusHookInterval:      schema.Omit,
+	StorageUsageWarningThreshold:  schema.Omit,
 	EgressSubnets:                 schema.Omit,
 	FanConfig:                     schema.Omit,
 	CloudInitUserDataKey:          schema.Omit,
@@ -1776,6 +1819,11 @@ var configSchema = environschema.Fields{
 		Type:        environschema.Tbool,
 		Group:       environschema.EnvironGroup,
 	},
+	ManageNetplanKey: {
+		Description: "Whether the machine worker should apply the netplan configuration rendered from the machine's link-layer devices (default false)",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
 	"enable-os-refresh-update": {
 		Description: `Whether newly provisioned instances should run their respective OS's update capability.`,
 		Type:        environschema.Tbool,
@@ -2023,6 +2071,11 @@ data of the store. (default false)`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	StorageUsageWarningThreshold: {
+		Description: "The percentage of a filesystem's space or inodes in use above which its status reports a warning (default 90, 0 to disable)",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
 	UpdateStatusHookInterval: {
 		Description: "How often to run the charm update-status hook, in human-readable time format (default 5m, range 1-60m)",
 		Type:        environschema.Tstring,
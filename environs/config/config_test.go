@@ -1328,6 +1328,37 @@ func (s *ConfigSuite) TestUpdateStatusHookIntervalConfigValue(c *gc.C) {
 	c.Assert(cfg.UpdateStatusHookInterval(), gc.Equals, 30*time.Minute)
 }
 
+func (s *ConfigSuite) TestManageNetplanDefault(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.ManageNetplan(), jc.IsFalse)
+}
+
+func (s *ConfigSuite) TestManageNetplanValue(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"manage-netplan": true,
+	})
+	c.Assert(cfg.ManageNetplan(), jc.IsTrue)
+}
+
+func (s *ConfigSuite) TestStorageUsageWarningThresholdConfigDefault(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.StorageUsageWarningThreshold(), gc.Equals, 90)
+}
+
+func (s *ConfigSuite) TestStorageUsageWarningThresholdConfigValue(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"storage-usage-warning-threshold": 75,
+	})
+	c.Assert(cfg.StorageUsageWarningThreshold(), gc.Equals, 75)
+}
+
+func (s *ConfigSuite) TestStorageUsageWarningThresholdConfigInvalid(c *gc.C) {
+	_, err := config.New(config.UseDefaults, testing.FakeConfig().Merge(testing.Attrs{
+		"storage-usage-warning-threshold": 101,
+	}))
+	c.Assert(err, gc.ErrorMatches, "storage usage warning threshold 101 must be between 0 and 100")
+}
+
 func (s *ConfigSuite) TestEgressSubnets(c *gc.C) {
 	cfg := newTestConfig(c, testing.Attrs{
 		"egress-subnets": "10.0.0.1/32, 192.168.1.1/16",
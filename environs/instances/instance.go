@@ -41,3 +41,19 @@ type InstanceFirewaller interface {
 	// address rules for that port range.
 	IngressRules(ctx context.ProviderCallContext, machineId string) ([]network.IngressRule, error)
 }
+
+// InterruptibleInstance is implemented by instances which the cloud may
+// reclaim at any time, such as spot instances.
+type InterruptibleInstance interface {
+	// Interrupted returns whether the cloud has reclaimed the instance.
+	// An interrupted instance will not return, and the machine it
+	// realises must be provisioned again.
+	Interrupted(context.ProviderCallContext) bool
+
+	// InterruptionNotice returns the notice the cloud has given that it
+	// is about to reclaim the instance, or an empty string if it has
+	// given none. The boolean result is false if the cloud will never
+	// reclaim the instance, in which case there is no notice to watch
+	// for.
+	InterruptionNotice(context.ProviderCallContext) (string, bool)
+}
@@ -489,6 +489,20 @@ type Firewaller interface {
 	IngressRules(ctx context.ProviderCallContext) ([]network.IngressRule, error)
 }
 
+// EgressFirewaller is an interface that can be implemented by an Environ
+// which can restrict egress from the instances of the model's machines.
+// It is only used with the FwInstance firewall mode; elsewhere egress is
+// restricted by the machine agent.
+type EgressFirewaller interface {
+	// SetEgressRules restricts egress from the given instance, which
+	// should have been started with the given machine id, to the given
+	// rules. Egress is unrestricted if no rules are given. A nil error
+	// must only be returned once egress is restricted by every security
+	// group of the instance, as the machine agent stops enforcing the
+	// rules itself.
+	SetEgressRules(ctx context.ProviderCallContext, machineId string, id instance.Id, rules []network.EgressRule) error
+}
+
 // InstanceTagger is an interface that can be used for tagging instances.
 type InstanceTagger interface {
 	// TagInstance tags the given instance with the specified tags.
@@ -498,6 +512,38 @@ type InstanceTagger interface {
 	TagInstance(ctx context.ProviderCallContext, id instance.Id, tags map[string]string) error
 }
 
+// InstanceProtector is an interface that can be implemented by an Environ
+// whose cloud can prevent instances from being terminated through its API.
+type InstanceProtector interface {
+	// SetInstanceProtection enables or disables termination protection
+	// for the instances with the given ids.
+	SetInstanceProtection(ctx context.ProviderCallContext, ids []instance.Id, protected bool) error
+}
+
+// InstanceResizer is an interface that can be implemented by an Environ
+// whose cloud can change the hardware of existing instances.
+type InstanceResizer interface {
+	// ResizeInstance changes the hardware of the instance with the given
+	// id to satisfy the given constraints, and returns the instance's
+	// resulting hardware characteristics. The instance may be restarted
+	// in the process.
+	ResizeInstance(ctx context.ProviderCallContext, id instance.Id, cons constraints.Value) (*instance.HardwareCharacteristics, error)
+}
+
+// ResourceTagUpdater is an interface that can be implemented by an Environ
+// which can update the tags of the cloud resources it has created for the
+// model, so that changes to the model's resource-tags config are applied
+// to existing resources as well as new ones.
+type ResourceTagUpdater interface {
+	// UpdateResourceTags sets the given tags on all of the model's
+	// cloud resources, and removes the tags with the given keys.
+	UpdateResourceTags(ctx context.ProviderCallContext, tags map[string]string, removed []string) error
+
+	// ResourceTagKeys returns the distinct keys of the tags with
+	// the given prefix on the model's cloud resources.
+	ResourceTagKeys(ctx context.ProviderCallContext, prefix string) ([]string, error)
+}
+
 // InstanceTypesFetcher is an interface that allows for instance information from
 // a provider to be obtained.
 type InstanceTypesFetcher interface {
@@ -555,3 +601,11 @@ type DefaultConstraintsChecker interface {
 	// use default constraints
 	ShouldApplyControllerConstraints() bool
 }
+
+// DefaultInstanceConstraintsApplier defines an interface for Environs that
+// apply default constraints when provisioning non-controller machines.
+type DefaultInstanceConstraintsApplier interface {
+	// ApplyDefaultInstanceConstraints returns the given constraints with
+	// the defaults used to provision a non-controller machine applied.
+	ApplyDefaultInstanceConstraints(cons constraints.Value) constraints.Value
+}
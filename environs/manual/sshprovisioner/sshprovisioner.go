@@ -98,9 +98,19 @@ fi`
 // by connecting to the machine and executing a bash script.
 var DetectSeriesAndHardwareCharacteristics = detectSeriesAndHardwareCharacteristics
 
+// DetectSeriesAndHardwareCharacteristicsAt is like
+// DetectSeriesAndHardwareCharacteristics, but connects to the
+// given "[user@]host" rather than as the ubuntu user, so that
+// it may be used before the ubuntu user is initialised.
+var DetectSeriesAndHardwareCharacteristicsAt = detectSeriesAndHardwareCharacteristicsAt
+
 func detectSeriesAndHardwareCharacteristics(host string) (hc instance.HardwareCharacteristics, series string, err error) {
+	return detectSeriesAndHardwareCharacteristicsAt("ubuntu@" + host)
+}
+
+func detectSeriesAndHardwareCharacteristicsAt(host string) (hc instance.HardwareCharacteristics, series string, err error) {
 	logger.Infof("Detecting series and characteristics on %s", host)
-	cmd := ssh.Command("ubuntu@"+host, []string{"/bin/bash"}, nil)
+	cmd := ssh.Command(host, []string{"/bin/bash"}, nil)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -163,12 +173,20 @@ func detectSeriesAndHardwareCharacteristics(host string) (hc instance.HardwareCh
 // exist on the host machine.
 var CheckProvisioned = checkProvisioned
 
+// CheckProvisionedAt is like CheckProvisioned, but connects to
+// the given "[user@]host" rather than as the ubuntu user.
+var CheckProvisionedAt = checkProvisionedAt
+
 func checkProvisioned(host string) (bool, error) {
+	return checkProvisionedAt("ubuntu@" + host)
+}
+
+func checkProvisionedAt(host string) (bool, error) {
 	logger.Infof("Checking if %s is already provisioned", host)
 
 	script := service.ListServicesScript()
 
-	cmd := ssh.Command("ubuntu@"+host, []string{"/bin/bash"}, nil)
+	cmd := ssh.Command(host, []string{"/bin/bash"}, nil)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -254,6 +272,10 @@ func gatherMachineParams(hostname string) (*params.AddMachineParams, error) {
 	return machineParams, nil
 }
 
+// RunProvisioningScript runs the given provisioning script
+// on the host, as the ubuntu user.
+var RunProvisioningScript = runProvisionScript
+
 func runProvisionScript(script, host string, progressWriter io.Writer) error {
 	params := sshinit.ConfigureParams{
 		Host:           "ubuntu@" + host,
@@ -40,6 +40,12 @@ const (
 	// the model and machine id corresponding to the
 	// provisioned machine instance.
 	JujuMachine = JujuTagPrefix + "machine-id"
+
+	// JujuResourceTagPrefix is the prefix of the tag names used for
+	// recording which of the model's resource-tags have been applied
+	// to a resource. For each resource tag applied, a tag named with
+	// the prefix followed by the resource tag's key is also set.
+	JujuResourceTagPrefix = JujuTagPrefix + "resource-tag-"
 )
 
 // ResourceTagger is an interface that can provide resource tags.
@@ -228,6 +228,8 @@ block:
     it: works
 loop:
   provider: loop
+lvm:
+  provider: lvm
 machinescoped:
   provider: machinescoped
 modelscoped:
@@ -253,6 +255,7 @@ func (s *cmdStorageSuite) TestListPoolsTabular(c *gc.C) {
 Name                      Provider                  Attrs
 block                     loop                      it=works
 loop                      loop                      
+lvm                       lvm                       
 machinescoped             machinescoped             
 modelscoped               modelscoped               
 modelscoped-block         modelscoped-block         
@@ -2,3 +2,5 @@
 // Licensed under the AGPLv3, see LICENCE file for details.
 
 package migration
+
+var UnmigratableConstraints = unmigratableConstraints
@@ -5,6 +5,7 @@ package migration
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/version"
@@ -35,6 +36,11 @@ type PrecheckBackend interface {
 	ControllerBackend() (PrecheckBackend, error)
 	CloudCredential(tag names.CloudCredentialTag) (state.Credential, error)
 	ListPendingResources(string) ([]resource.Resource, error)
+
+	// UnmigratableSettings describes the settings of the model
+	// that the model description cannot carry to the target
+	// controller.
+	UnmigratableSettings() ([]string, error)
 }
 
 // Pool defines the interface to a StatePool used by the migration
@@ -65,6 +71,12 @@ type PrecheckMachine interface {
 	AgentPresence() (bool, error)
 	InstanceStatus() (status.StatusInfo, error)
 	ShouldRebootOrShutdown() (state.RebootAction, error)
+	IsProtected() bool
+
+	// UnmigratableSettings describes the settings of the machine
+	// that the model description cannot carry to the target
+	// controller.
+	UnmigratableSettings() ([]string, error)
 }
 
 // PrecheckApplication describes the state interface for an
@@ -75,6 +87,11 @@ type PrecheckApplication interface {
 	CharmURL() (*charm.URL, bool)
 	AllUnits() ([]PrecheckUnit, error)
 	MinUnits() int
+
+	// UnmigratableSettings describes the settings of the application
+	// that the model description cannot carry to the target
+	// controller.
+	UnmigratableSettings() ([]string, error)
 }
 
 // PrecheckUnit describes state interface for a unit needed by
@@ -129,6 +146,22 @@ func SourcePrecheck(
 		return errors.Trace(err)
 	}
 
+	if err := ctx.checkMachineProtection(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if err := ctx.checkMachineSettings(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if err := ctx.checkModelSettings(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if err := ctx.checkApplicationSettings(); err != nil {
+		return errors.Trace(err)
+	}
+
 	appUnits, err := ctx.checkApplications()
 	if err != nil {
 		return errors.Trace(err)
@@ -328,6 +361,87 @@ func (ctx *precheckContext) checkMachines() error {
 	return nil
 }
 
+// checkMachineProtection refuses to migrate a model with machines
+// protected from termination, as the model description cannot carry
+// the protection to the target controller.
+func (ctx *precheckContext) checkMachineProtection() error {
+	machines, err := ctx.backend.AllMachines()
+	if err != nil {
+		return errors.Annotate(err, "retrieving machines")
+	}
+	for _, machine := range machines {
+		if machine.IsProtected() {
+			return errors.Errorf(
+				"machine %s is protected from termination (run 'juju set-machine-protection %s false' before migrating)",
+				machine.Id(), machine.Id(),
+			)
+		}
+	}
+	return nil
+}
+
+// checkMachineSettings refuses to migrate a model with machines that
+// have settings the model description cannot carry, rather than
+// silently dropping them.
+func (ctx *precheckContext) checkMachineSettings() error {
+	machines, err := ctx.backend.AllMachines()
+	if err != nil {
+		return errors.Annotate(err, "retrieving machines")
+	}
+	for _, machine := range machines {
+		settings, err := machine.UnmigratableSettings()
+		if err != nil {
+			return errors.Annotatef(err, "retrieving machine %s settings", machine.Id())
+		}
+		if len(settings) > 0 {
+			return errors.Errorf(
+				"machine %s has settings that cannot be migrated: %s",
+				machine.Id(), strings.Join(settings, ", "),
+			)
+		}
+	}
+	return nil
+}
+
+// checkModelSettings refuses to migrate a model with settings that the
+// model description cannot carry, rather than silently dropping them.
+func (ctx *precheckContext) checkModelSettings() error {
+	settings, err := ctx.backend.UnmigratableSettings()
+	if err != nil {
+		return errors.Annotate(err, "retrieving model settings")
+	}
+	if len(settings) > 0 {
+		return errors.Errorf(
+			"model has settings that cannot be migrated: %s",
+			strings.Join(settings, ", "),
+		)
+	}
+	return nil
+}
+
+// checkApplicationSettings refuses to migrate a model with applications
+// that have settings the model description cannot carry, rather than
+// silently dropping them.
+func (ctx *precheckContext) checkApplicationSettings() error {
+	apps, err := ctx.backend.AllApplications()
+	if err != nil {
+		return errors.Annotate(err, "retrieving applications")
+	}
+	for _, app := range apps {
+		settings, err := app.UnmigratableSettings()
+		if err != nil {
+			return errors.Annotatef(err, "retrieving application %s settings", app.Name())
+		}
+		if len(settings) > 0 {
+			return errors.Errorf(
+				"application %s has settings that cannot be migrated: %s",
+				app.Name(), strings.Join(settings, ", "),
+			)
+		}
+	}
+	return nil
+}
+
 func (ctx *precheckContext) checkApplications() (map[string][]PrecheckUnit, error) {
 	modelVersion, err := ctx.backend.AgentVersion()
 	if err != nil {
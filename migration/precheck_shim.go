@@ -4,9 +4,12 @@
 package migration
 
 import (
+	"fmt"
+
 	"github.com/juju/errors"
 	"github.com/juju/version"
 
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/resource"
 	"github.com/juju/juju/state"
 )
@@ -72,7 +75,7 @@ func (s *precheckShim) AllMachines() ([]PrecheckMachine, error) {
 	}
 	out := make([]PrecheckMachine, 0, len(machines))
 	for _, machine := range machines {
-		out = append(out, machine)
+		out = append(out, &precheckMachineShim{machine})
 	}
 	return out, nil
 }
@@ -111,6 +114,55 @@ func (s *precheckShim) ListPendingResources(app string) ([]resource.Resource, er
 	return resources, nil
 }
 
+// UnmigratableSettings implements PrecheckBackend.
+func (s *precheckShim) UnmigratableSettings() ([]string, error) {
+	cons, err := s.State.ModelConstraints()
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+	settings := unmigratableConstraints(cons)
+	sb, err := state.NewStorageBackend(s.State)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	volumes, err := sb.AllVolumes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, volume := range volumes {
+		// The description package cannot yet record that a volume
+		// is encrypted, so it would be imported as unencrypted.
+		info, err := volume.Info()
+		if errors.IsNotProvisioned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if info.Encrypted {
+			settings = append(settings, fmt.Sprintf("encrypted volume %s", volume.VolumeTag().Id()))
+		}
+	}
+	// Storage migration records, and the provenance of the storage
+	// attachments they leave behind, are not yet exported.
+	migrations, err := sb.AllStorageMigrations()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, m := range migrations {
+		settings = append(settings, fmt.Sprintf("storage migration %s", m.Id()))
+	}
+	// The snapshot a volume is to be restored from is not yet
+	// exported, so the volume would be provisioned empty.
+	restores, err := sb.PendingSnapshotRestores()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, tag := range restores {
+		settings = append(settings, fmt.Sprintf("snapshot restore of storage %s", tag.Id()))
+	}
+	return settings, nil
+}
+
 // ControllerBackend implements PrecheckBackend.
 func (s *precheckShim) ControllerBackend() (PrecheckBackend, error) {
 	return PrecheckShim(s.controllerState, s.controllerState)
@@ -151,6 +203,24 @@ func (s *precheckAppShim) AllUnits() ([]PrecheckUnit, error) {
 	return out, nil
 }
 
+// UnmigratableSettings implements PrecheckApplication.
+func (s *precheckAppShim) UnmigratableSettings() ([]string, error) {
+	cons, err := s.Application.Constraints()
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+	settings := unmigratableConstraints(cons)
+	// Egress rules are not yet exported.
+	rules, err := s.Application.EgressRules()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(rules) > 0 {
+		settings = append(settings, "egress rules")
+	}
+	return settings, nil
+}
+
 // precheckRelationShim implements PrecheckRelation.
 type precheckRelationShim struct {
 	*state.Relation
@@ -174,3 +244,53 @@ func (s *precheckRelationShim) IsCrossModel() (bool, error) {
 	_, result, err := s.Relation.RemoteApplication()
 	return result, err
 }
+
+// precheckMachineShim wraps a *state.Machine to implement
+// PrecheckMachine.
+type precheckMachineShim struct {
+	*state.Machine
+}
+
+// UnmigratableSettings implements PrecheckMachine.
+func (m *precheckMachineShim) UnmigratableSettings() ([]string, error) {
+	var settings []string
+	devices, err := m.AllLinkLayerDevices()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, dev := range devices {
+		if dev.BondMode() != "" {
+			settings = append(settings, fmt.Sprintf("bond mode of device %q", dev.Name()))
+		}
+	}
+	addresses, err := m.AllAddresses()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, addr := range addresses {
+		if len(addr.Routes()) > 0 {
+			settings = append(settings, fmt.Sprintf("routes of address %q", addr.Value()))
+		}
+	}
+	cons, err := m.Constraints()
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+	return append(settings, unmigratableConstraints(cons)...), nil
+}
+
+// unmigratableConstraints describes the constraints that the model
+// description cannot carry to the target controller.
+func unmigratableConstraints(cons constraints.Value) []string {
+	var settings []string
+	if cons.Spot != nil {
+		settings = append(settings, "spot constraint")
+	}
+	if cons.MaxPrice != nil {
+		settings = append(settings, "max-price constraint")
+	}
+	if cons.AntiAffinity != nil {
+		settings = append(settings, "anti-affinity constraint")
+	}
+	return settings
+}
@@ -11,6 +11,7 @@ import (
 	"gopkg.in/juju/charm.v6"
 	"gopkg.in/juju/names.v3"
 
+	"github.com/juju/juju/core/constraints"
 	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/core/presence"
 	"github.com/juju/juju/core/status"
@@ -322,6 +323,95 @@ func (s *SourcePrecheckSuite) TestControllerMachineRequiresReboot(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "controller: machine 0 is scheduled to reboot")
 }
 
+func (s *SourcePrecheckSuite) TestProtectedMachine(c *gc.C) {
+	backend := newFakeBackend()
+	backend.machines = []migration.PrecheckMachine{
+		&fakeMachine{id: "0"},
+		&fakeMachine{id: "1", protected: true},
+	}
+	err := sourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, `machine 1 is protected from termination \(run 'juju set-machine-protection 1 false' before migrating\)`)
+}
+
+func (s *SourcePrecheckSuite) TestMachineWithUnmigratableSettings(c *gc.C) {
+	backend := newFakeBackend()
+	backend.machines = []migration.PrecheckMachine{
+		&fakeMachine{id: "0"},
+		&fakeMachine{id: "1", unmigratable: []string{
+			`bond mode of device "bond0"`,
+			`routes of address "10.0.0.5"`,
+		}},
+	}
+	err := sourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, `machine 1 has settings that cannot be migrated: bond mode of device "bond0", routes of address "10.0.0.5"`)
+}
+
+func (s *SourcePrecheckSuite) TestModelWithUnmigratableSettings(c *gc.C) {
+	backend := newFakeBackend()
+	backend.unmigratable = []string{"spot constraint", "max-price constraint"}
+	err := sourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, `model has settings that cannot be migrated: spot constraint, max-price constraint`)
+}
+
+func (s *SourcePrecheckSuite) TestModelWithEncryptedVolume(c *gc.C) {
+	backend := newFakeBackend()
+	backend.unmigratable = []string{"encrypted volume 0/1"}
+	err := sourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, `model has settings that cannot be migrated: encrypted volume 0/1`)
+}
+
+func (s *SourcePrecheckSuite) TestModelWithStorageMigration(c *gc.C) {
+	backend := newFakeBackend()
+	backend.unmigratable = []string{"storage migration 0/0"}
+	err := sourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, `model has settings that cannot be migrated: storage migration 0/0`)
+}
+
+func (s *SourcePrecheckSuite) TestApplicationWithUnmigratableSettings(c *gc.C) {
+	backend := newFakeBackend()
+	backend.apps = []migration.PrecheckApplication{
+		&fakeApp{name: "foo"},
+		&fakeApp{name: "bar", unmigratable: []string{"spot constraint"}},
+	}
+	err := sourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, `application bar has settings that cannot be migrated: spot constraint`)
+}
+
+func (s *SourcePrecheckSuite) TestApplicationWithAntiAffinity(c *gc.C) {
+	backend := newFakeBackend()
+	backend.apps = []migration.PrecheckApplication{
+		&fakeApp{name: "foo", unmigratable: []string{"anti-affinity constraint"}},
+	}
+	err := sourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, `application foo has settings that cannot be migrated: anti-affinity constraint`)
+}
+
+func (s *SourcePrecheckSuite) TestApplicationWithEgressRules(c *gc.C) {
+	backend := newFakeBackend()
+	backend.apps = []migration.PrecheckApplication{
+		&fakeApp{name: "foo", unmigratable: []string{"egress rules"}},
+	}
+	err := sourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, `application foo has settings that cannot be migrated: egress rules`)
+}
+
+func (s *SourcePrecheckSuite) TestUnmigratableConstraints(c *gc.C) {
+	c.Check(migration.UnmigratableConstraints(constraints.MustParse("mem=4G")), gc.HasLen, 0)
+	settings := migration.UnmigratableConstraints(constraints.MustParse("spot=true max-price=0.5 anti-affinity=db"))
+	c.Check(settings, jc.DeepEquals, []string{"spot constraint", "max-price constraint", "anti-affinity constraint"})
+}
+
+func (s *SourcePrecheckSuite) TestProtectedControllerMachine(c *gc.C) {
+	backend := newFakeBackend()
+	backend.controllerBackend = &fakeBackend{
+		machines: []migration.PrecheckMachine{
+			&fakeMachine{id: "0", protected: true},
+		},
+	}
+	err := sourcePrecheck(backend)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *SourcePrecheckSuite) TestDyingControllerMachine(c *gc.C) {
 	backend := &fakeBackend{
 		controllerBackend: newBackendWithDyingMachine(),
@@ -784,6 +874,8 @@ type fakeBackend struct {
 	pendingResources    []resource.Resource
 	pendingResourcesErr error
 
+	unmigratable []string
+
 	controllerBackend *fakeBackend
 }
 
@@ -831,6 +923,10 @@ func (b *fakeBackend) ListPendingResources(app string) ([]resource.Resource, err
 	return b.pendingResources, b.pendingResourcesErr
 }
 
+func (b *fakeBackend) UnmigratableSettings() ([]string, error) {
+	return b.unmigratable, nil
+}
+
 func (b *fakeBackend) ControllerBackend() (migration.PrecheckBackend, error) {
 	if b.controllerBackend == nil {
 		return b, nil
@@ -908,6 +1004,8 @@ type fakeMachine struct {
 	instanceStatus status.Status
 	lost           bool
 	rebootAction   state.RebootAction
+	protected      bool
+	unmigratable   []string
 }
 
 func (m *fakeMachine) Id() string {
@@ -959,12 +1057,21 @@ func (m *fakeMachine) ShouldRebootOrShutdown() (state.RebootAction, error) {
 	return m.rebootAction, nil
 }
 
+func (m *fakeMachine) IsProtected() bool {
+	return m.protected
+}
+
+func (m *fakeMachine) UnmigratableSettings() ([]string, error) {
+	return m.unmigratable, nil
+}
+
 type fakeApp struct {
-	name     string
-	life     state.Life
-	charmURL string
-	units    []migration.PrecheckUnit
-	minunits int
+	name         string
+	life         state.Life
+	charmURL     string
+	units        []migration.PrecheckUnit
+	minunits     int
+	unmigratable []string
 }
 
 func (a *fakeApp) Name() string {
@@ -991,6 +1098,10 @@ func (a *fakeApp) MinUnits() int {
 	return a.minunits
 }
 
+func (a *fakeApp) UnmigratableSettings() ([]string, error) {
+	return a.unmigratable, nil
+}
+
 type fakeUnit struct {
 	name        string
 	version     version.Binary
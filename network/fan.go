@@ -40,6 +40,10 @@ func ParseFanConfig(line string) (config FanConfig, err error) {
 		if _, config[i].Overlay, err = net.ParseCIDR(strings.TrimSpace(cidrs[1])); err != nil {
 			return nil, errors.Annotatef(err, "invalid address in FAN config")
 		}
+		// The fan only maps IPv4 underlays onto IPv4 overlays.
+		if config[i].Underlay.IP.To4() == nil || config[i].Overlay.IP.To4() == nil {
+			return nil, fmt.Errorf("invalid FAN config, only IPv4 is supported: %s", line)
+		}
 		underlaySize, _ := config[i].Underlay.Mask.Size()
 		overlaySize, _ := config[i].Overlay.Mask.Size()
 		if underlaySize <= overlaySize {
@@ -67,6 +67,14 @@ func (*FanConfigSuite) TestFanConfigErrors(c *gc.C) {
 	c.Check(config, gc.IsNil)
 	c.Check(err, gc.ErrorMatches, "invalid address in FAN config:.*")
 
+	// IPv6 underlay or overlay.
+	config, err = network.ParseFanConfig("2001:db8::/32=253.0.0.0/8")
+	c.Check(config, gc.IsNil)
+	c.Check(err, gc.ErrorMatches, "invalid FAN config, only IPv4 is supported:.*")
+	config, err = network.ParseFanConfig("172.31.0.0/16=2001:db8::/32")
+	c.Check(config, gc.IsNil)
+	c.Check(err, gc.ErrorMatches, "invalid FAN config, only IPv4 is supported:.*")
+
 	// Underlay mask smaller than overlay.
 	config, err = network.ParseFanConfig("1.0.0.0/8=2.0.0.0/16")
 	c.Check(config, gc.IsNil)
@@ -72,3 +72,37 @@ func (*FirewallSuite) TestNewIngressRuleBadCIDR(c *gc.C) {
 	_, err := network.NewIngressRule("tcp", 80, 100, "0.0.0.0/0", "192.168.0/24")
 	c.Assert(err, gc.ErrorMatches, "invalid CIDR address: 192.168.0/24")
 }
+
+func (*FirewallSuite) TestEgressRuleStrings(c *gc.C) {
+	rule := network.MustNewEgressRule("tcp", 5432, 5432, "10.0.0.0/8")
+	c.Assert(rule.String(), gc.Equals, "5432/tcp to 10.0.0.0/8")
+	c.Assert(rule.GoString(), gc.Equals, "5432/tcp to 10.0.0.0/8")
+
+	rule = network.MustNewEgressRule("tcp", 80, 443, "10.0.0.0/8", "192.168.1.0/24")
+	c.Assert(rule.String(), gc.Equals, "80-443/tcp to 10.0.0.0/8,192.168.1.0/24")
+}
+
+func (*FirewallSuite) TestNewEgressRule(c *gc.C) {
+	rule, err := network.NewEgressRule("udp", 53, 53, "10.0.0.2/32")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rule.Protocol, gc.Equals, "udp")
+	c.Assert(rule.FromPort, gc.Equals, 53)
+	c.Assert(rule.ToPort, gc.Equals, 53)
+	c.Assert(rule.DestinationCIDRs, jc.DeepEquals, []string{"10.0.0.2/32"})
+
+	_, err = network.NewEgressRule("tcp", 80, 80)
+	c.Assert(err, gc.ErrorMatches, "egress rule without destinations")
+	_, err = network.NewEgressRule("tcp", 80, 80, "192.168.0/24")
+	c.Assert(err, gc.ErrorMatches, "invalid CIDR address: 192.168.0/24")
+}
+
+func (*FirewallSuite) TestSortEgressRules(c *gc.C) {
+	rule1 := network.MustNewEgressRule("udp", 53, 53, "10.0.0.2/32")
+	rule2 := network.MustNewEgressRule("tcp", 5432, 5432, "10.0.0.0/8")
+	rule3 := network.MustNewEgressRule("tcp", 443, 443, "192.168.1.0/24")
+	rule4 := network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8")
+
+	rules := []network.EgressRule{rule1, rule2, rule3, rule4}
+	network.SortEgressRules(rules)
+	c.Assert(rules, gc.DeepEquals, []network.EgressRule{rule4, rule3, rule2, rule1})
+}
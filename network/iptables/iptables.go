@@ -7,6 +7,7 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"net"
 	"strconv"
 	"strings"
 
@@ -26,6 +27,14 @@ const (
 	// iptablesInternalCommand is the comment attached to iptables
 	// rules that are not directly related to ingress rules.
 	iptablesInternalComment = "juju internal"
+
+	// iptablesEgressComment is the comment attached to iptables
+	// rules directly related to egress rules.
+	iptablesEgressComment = "juju egress"
+
+	// EgressChain is the iptables chain, jumped to from the OUTPUT
+	// chain, which holds the rules restricting egress.
+	EgressChain = "JUJU-EGRESS"
 )
 
 // DropCommand represents an iptables DROP target command.
@@ -145,6 +154,146 @@ func (c IngressRuleCommand) render(commandFlag string) string {
 	return strings.Join(args, " ")
 }
 
+// EgressRulesCommand represents the iptables and ip6tables commands
+// which restrict egress from the machine to the given rules. The rules
+// are held in EgressChain, which is flushed and refilled each time the
+// command is run, so the command replaces any previous restriction.
+// Loopback traffic, replies on established connections and DHCP are
+// always allowed, as are DNS queries to the resolvers and NTP queries
+// to the NTP servers, so the machine keeps its address, name
+// resolution and clock.
+type EgressRulesCommand struct {
+	Rules []network.EgressRule
+
+	// Resolvers holds the IP addresses of the machine's DNS
+	// resolvers.
+	Resolvers []string
+
+	// NTPServers holds the IP addresses of the machine's NTP
+	// servers.
+	NTPServers []string
+
+	// Remove, if true, removes EgressChain instead, lifting
+	// any restriction of egress.
+	Remove bool
+}
+
+// Render renders the command to a string which can be executed via
+// bash in order to install or remove the iptables rules.
+func (c EgressRulesCommand) Render() string {
+	if c.Remove {
+		return strings.Join([]string{
+			renderRemoveEgressChain("iptables"),
+			renderRemoveEgressChain("ip6tables"),
+		}, " && ")
+	}
+	var ipv4Rules, ipv6Rules []network.EgressRule
+	for _, rule := range append(c.serviceRules(), c.Rules...) {
+		for _, cidr := range rule.DestinationCIDRs {
+			r := rule
+			r.DestinationCIDRs = []string{cidr}
+			if strings.Contains(cidr, ":") {
+				ipv6Rules = append(ipv6Rules, r)
+			} else {
+				ipv4Rules = append(ipv4Rules, r)
+			}
+		}
+	}
+	return strings.Join([]string{
+		renderEgressChain("iptables", ipv4Rules),
+		renderEgressChain("ip6tables", ipv6Rules),
+	}, " && ")
+}
+
+// serviceRules returns the rules allowing DNS queries to the
+// resolvers and NTP queries to the NTP servers. Addresses which
+// are not IP addresses are skipped.
+func (c EgressRulesCommand) serviceRules() []network.EgressRule {
+	var rules []network.EgressRule
+	for _, addr := range c.Resolvers {
+		if cidr, ok := hostCIDR(addr); ok {
+			rules = append(rules,
+				network.MustNewEgressRule("udp", 53, 53, cidr),
+				network.MustNewEgressRule("tcp", 53, 53, cidr),
+			)
+		}
+	}
+	for _, addr := range c.NTPServers {
+		if cidr, ok := hostCIDR(addr); ok {
+			rules = append(rules, network.MustNewEgressRule("udp", 123, 123, cidr))
+		}
+	}
+	return rules
+}
+
+// hostCIDR returns the single host CIDR of the given IP address.
+func hostCIDR(addr string) (string, bool) {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		logger.Warningf("ignoring invalid IP address %q", addr)
+		return "", false
+	}
+	if ip.To4() != nil {
+		return ip.String() + "/32", true
+	}
+	return ip.String() + "/128", true
+}
+
+func renderEgressChain(command string, rules []network.EgressRule) string {
+	iptables := "sudo " + command
+	comment := fmt.Sprintf("-m comment --comment '%s'", iptablesEgressComment)
+	cmds := []string{
+		fmt.Sprintf("(%s -N %s || %s -F %s)", iptables, EgressChain, iptables, EgressChain),
+		fmt.Sprintf("%s -A %s -o lo -j RETURN %s", iptables, EgressChain, comment),
+		fmt.Sprintf("%s -A %s -m state --state ESTABLISHED,RELATED -j RETURN %s", iptables, EgressChain, comment),
+	}
+	// DHCP clients broadcast, or multicast, their requests.
+	dhcpPort := 67
+	if command == "ip6tables" {
+		dhcpPort = 547
+	}
+	cmds = append(cmds, fmt.Sprintf("%s -A %s -j RETURN -p udp --dport %d %s", iptables, EgressChain, dhcpPort, comment))
+	for _, rule := range rules {
+		args := []string{
+			iptables,
+			"-A", EgressChain,
+			"-j RETURN",
+		}
+		protocol := rule.Protocol
+		if protocol == "icmp" && command == "ip6tables" {
+			protocol = "icmpv6"
+		}
+		args = append(args, "-p", protocol, "-d", rule.DestinationCIDRs[0])
+		if rule.Protocol != "icmp" {
+			if rule.ToPort-rule.FromPort > 0 {
+				args = append(args,
+					"-m multiport --dports",
+					fmt.Sprintf("%d:%d", rule.FromPort, rule.ToPort),
+				)
+			} else {
+				args = append(args, "--dport", fmt.Sprint(rule.FromPort))
+			}
+		}
+		// Comment always comes last.
+		args = append(args, comment)
+		cmds = append(cmds, strings.Join(args, " "))
+	}
+	cmds = append(cmds,
+		fmt.Sprintf("%s -A %s -j REJECT %s", iptables, EgressChain, comment),
+		fmt.Sprintf("(%s -C OUTPUT -j %s || %s -I OUTPUT -j %s)", iptables, EgressChain, iptables, EgressChain),
+	)
+	return strings.Join(cmds, " && ")
+}
+
+func renderRemoveEgressChain(command string) string {
+	iptables := "sudo " + command
+	return fmt.Sprintf(
+		"((%s -C OUTPUT -j %s && %s -D OUTPUT -j %s) || true) && ((%s -F %s && %s -X %s) || true)",
+		iptables, EgressChain, iptables, EgressChain,
+		iptables, EgressChain, iptables, EgressChain,
+	)
+}
+
 // ParseIngressRules parses the output of "iptables -L INPUT -n",
 // extracting previously added ingress rules, as rendered by
 // IngressRuleCommand.
@@ -120,6 +120,87 @@ func (*IptablesSuite) TestIngressRuleCommand(c *gc.C) {
 	)
 }
 
+func (*IptablesSuite) TestEgressRulesCommand(c *gc.C) {
+	assertRender(c,
+		iptables.EgressRulesCommand{
+			Rules: []network.EgressRule{
+				network.MustNewEgressRule("tcp", 5432, 5432, "10.0.0.0/8", "fd00::/8"),
+				network.MustNewEgressRule("udp", 6000, 6007, "192.168.0.1/32"),
+			},
+		},
+		"(sudo iptables -N JUJU-EGRESS || sudo iptables -F JUJU-EGRESS) && "+
+			"sudo iptables -A JUJU-EGRESS -o lo -j RETURN -m comment --comment 'juju egress' && "+
+			"sudo iptables -A JUJU-EGRESS -m state --state ESTABLISHED,RELATED -j RETURN -m comment --comment 'juju egress' && "+
+			"sudo iptables -A JUJU-EGRESS -j RETURN -p udp --dport 67 -m comment --comment 'juju egress' && "+
+			"sudo iptables -A JUJU-EGRESS -j RETURN -p tcp -d 10.0.0.0/8 --dport 5432 -m comment --comment 'juju egress' && "+
+			"sudo iptables -A JUJU-EGRESS -j RETURN -p udp -d 192.168.0.1/32 -m multiport --dports 6000:6007 -m comment --comment 'juju egress' && "+
+			"sudo iptables -A JUJU-EGRESS -j REJECT -m comment --comment 'juju egress' && "+
+			"(sudo iptables -C OUTPUT -j JUJU-EGRESS || sudo iptables -I OUTPUT -j JUJU-EGRESS) && "+
+			"(sudo ip6tables -N JUJU-EGRESS || sudo ip6tables -F JUJU-EGRESS) && "+
+			"sudo ip6tables -A JUJU-EGRESS -o lo -j RETURN -m comment --comment 'juju egress' && "+
+			"sudo ip6tables -A JUJU-EGRESS -m state --state ESTABLISHED,RELATED -j RETURN -m comment --comment 'juju egress' && "+
+			"sudo ip6tables -A JUJU-EGRESS -j RETURN -p udp --dport 547 -m comment --comment 'juju egress' && "+
+			"sudo ip6tables -A JUJU-EGRESS -j RETURN -p tcp -d fd00::/8 --dport 5432 -m comment --comment 'juju egress' && "+
+			"sudo ip6tables -A JUJU-EGRESS -j REJECT -m comment --comment 'juju egress' && "+
+			"(sudo ip6tables -C OUTPUT -j JUJU-EGRESS || sudo ip6tables -I OUTPUT -j JUJU-EGRESS)",
+	)
+
+	// ICMP rules have no ports, and use icmpv6 with ip6tables.
+	assertRender(c,
+		iptables.EgressRulesCommand{
+			Rules: []network.EgressRule{
+				network.MustNewEgressRule("icmp", -1, -1, "fd00::/8"),
+			},
+		},
+		"(sudo iptables -N JUJU-EGRESS || sudo iptables -F JUJU-EGRESS) && "+
+			"sudo iptables -A JUJU-EGRESS -o lo -j RETURN -m comment --comment 'juju egress' && "+
+			"sudo iptables -A JUJU-EGRESS -m state --state ESTABLISHED,RELATED -j RETURN -m comment --comment 'juju egress' && "+
+			"sudo iptables -A JUJU-EGRESS -j RETURN -p udp --dport 67 -m comment --comment 'juju egress' && "+
+			"sudo iptables -A JUJU-EGRESS -j REJECT -m comment --comment 'juju egress' && "+
+			"(sudo iptables -C OUTPUT -j JUJU-EGRESS || sudo iptables -I OUTPUT -j JUJU-EGRESS) && "+
+			"(sudo ip6tables -N JUJU-EGRESS || sudo ip6tables -F JUJU-EGRESS) && "+
+			"sudo ip6tables -A JUJU-EGRESS -o lo -j RETURN -m comment --comment 'juju egress' && "+
+			"sudo ip6tables -A JUJU-EGRESS -m state --state ESTABLISHED,RELATED -j RETURN -m comment --comment 'juju egress' && "+
+			"sudo ip6tables -A JUJU-EGRESS -j RETURN -p udp --dport 547 -m comment --comment 'juju egress' && "+
+			"sudo ip6tables -A JUJU-EGRESS -j RETURN -p icmpv6 -d fd00::/8 -m comment --comment 'juju egress' && "+
+			"sudo ip6tables -A JUJU-EGRESS -j REJECT -m comment --comment 'juju egress' && "+
+			"(sudo ip6tables -C OUTPUT -j JUJU-EGRESS || sudo ip6tables -I OUTPUT -j JUJU-EGRESS)",
+	)
+
+	// DNS and NTP queries to the machine's servers are allowed.
+	assertRender(c,
+		iptables.EgressRulesCommand{
+			Resolvers:  []string{"10.0.0.2", "fd00::2", "not-an-ip"},
+			NTPServers: []string{"10.0.0.3"},
+		},
+		"(sudo iptables -N JUJU-EGRESS || sudo iptables -F JUJU-EGRESS) && "+
+			"sudo iptables -A JUJU-EGRESS -o lo -j RETURN -m comment --comment 'juju egress' && "+
+			"sudo iptables -A JUJU-EGRESS -m state --state ESTABLISHED,RELATED -j RETURN -m comment --comment 'juju egress' && "+
+			"sudo iptables -A JUJU-EGRESS -j RETURN -p udp --dport 67 -m comment --comment 'juju egress' && "+
+			"sudo iptables -A JUJU-EGRESS -j RETURN -p udp -d 10.0.0.2/32 --dport 53 -m comment --comment 'juju egress' && "+
+			"sudo iptables -A JUJU-EGRESS -j RETURN -p tcp -d 10.0.0.2/32 --dport 53 -m comment --comment 'juju egress' && "+
+			"sudo iptables -A JUJU-EGRESS -j RETURN -p udp -d 10.0.0.3/32 --dport 123 -m comment --comment 'juju egress' && "+
+			"sudo iptables -A JUJU-EGRESS -j REJECT -m comment --comment 'juju egress' && "+
+			"(sudo iptables -C OUTPUT -j JUJU-EGRESS || sudo iptables -I OUTPUT -j JUJU-EGRESS) && "+
+			"(sudo ip6tables -N JUJU-EGRESS || sudo ip6tables -F JUJU-EGRESS) && "+
+			"sudo ip6tables -A JUJU-EGRESS -o lo -j RETURN -m comment --comment 'juju egress' && "+
+			"sudo ip6tables -A JUJU-EGRESS -m state --state ESTABLISHED,RELATED -j RETURN -m comment --comment 'juju egress' && "+
+			"sudo ip6tables -A JUJU-EGRESS -j RETURN -p udp --dport 547 -m comment --comment 'juju egress' && "+
+			"sudo ip6tables -A JUJU-EGRESS -j RETURN -p udp -d fd00::2/128 --dport 53 -m comment --comment 'juju egress' && "+
+			"sudo ip6tables -A JUJU-EGRESS -j RETURN -p tcp -d fd00::2/128 --dport 53 -m comment --comment 'juju egress' && "+
+			"sudo ip6tables -A JUJU-EGRESS -j REJECT -m comment --comment 'juju egress' && "+
+			"(sudo ip6tables -C OUTPUT -j JUJU-EGRESS || sudo ip6tables -I OUTPUT -j JUJU-EGRESS)",
+	)
+
+	assertRender(c,
+		iptables.EgressRulesCommand{Remove: true},
+		"((sudo iptables -C OUTPUT -j JUJU-EGRESS && sudo iptables -D OUTPUT -j JUJU-EGRESS) || true) && "+
+			"((sudo iptables -F JUJU-EGRESS && sudo iptables -X JUJU-EGRESS) || true) && "+
+			"((sudo ip6tables -C OUTPUT -j JUJU-EGRESS && sudo ip6tables -D OUTPUT -j JUJU-EGRESS) || true) && "+
+			"((sudo ip6tables -F JUJU-EGRESS && sudo ip6tables -X JUJU-EGRESS) || true)",
+	)
+}
+
 func (*IptablesSuite) TestParseIngressRulesEmpty(c *gc.C) {
 	assertParseIngressRules(c, ``, []network.IngressRule{})
 }
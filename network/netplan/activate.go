@@ -6,6 +6,7 @@ package netplan
 import (
 	"fmt"
 	"os"
+	"path"
 	"time"
 
 	"github.com/juju/clock"
@@ -111,3 +112,114 @@ func BridgeAndActivate(params ActivationParams) (*ActivationResult, error) {
 	}
 	return nil, nil
 }
+
+// jujuNetplanFile is the name of the file that ApplyWithRollback writes
+// the desired configuration to. Netplan reads the files in a directory in
+// lexical order, so the file amends or overrides the configuration of
+// the devices it contains, and leaves the other devices alone.
+const jujuNetplanFile = "99-juju.yaml"
+
+// ApplyParams contains options to use when applying a netplan
+// configuration.
+type ApplyParams struct {
+	Clock     clock.Clock
+	Directory string
+	RunPrefix string
+	Timeout   time.Duration
+
+	// Netplan is the desired configuration. It is written to its own
+	// file in Directory, merging with the other configuration files,
+	// which are left in place.
+	Netplan Netplan
+
+	// Verify, if set, is called once the configuration has been applied
+	// to check that the controller can still be reached over a new
+	// connection. If it does not succeed by the given deadline, the
+	// previous configuration is restored.
+	Verify func(deadline time.Time) error
+
+	// VerifyTimeout is how long Verify has to succeed once the
+	// configuration has been applied.
+	VerifyTimeout time.Duration
+}
+
+// ApplyWithRollback writes the desired netplan configuration to Juju's
+// own file in a directory and applies it. If applying the configuration
+// or verifying it afterwards fails, the file Juju previously wrote, if
+// any, is put back and applied again. Configuration files written by
+// others (e.g. cloud-init) are never moved or changed.
+func ApplyWithRollback(params ApplyParams) (*ActivationResult, error) {
+	clk := params.Clock
+	if clk == nil {
+		clk = clock.WallClock
+	}
+	jujuFile := path.Join(params.Directory, jujuNetplanFile)
+	previous := Netplan{sourceDirectory: params.Directory}
+	if _, err := os.Stat(jujuFile); err == nil {
+		previous.sourceFiles = []string{jujuNetplanFile}
+	} else if !os.IsNotExist(err) {
+		return nil, errors.Trace(err)
+	}
+	desired := Netplan{
+		Network:         params.Netplan.Network,
+		sourceDirectory: params.Directory,
+	}
+
+	if err := previous.MoveYamlsToBak(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	rollback := func() {
+		desired.Rollback()
+		previous.Rollback()
+	}
+	if _, err := desired.Write(jujuFile); err != nil {
+		rollback()
+		return nil, errors.Trace(err)
+	}
+
+	environ := os.Environ()
+	command := fmt.Sprintf("%snetplan generate && netplan apply", params.RunPrefix)
+	result, err := scriptrunner.RunCommand(command, environ, clk, params.Timeout)
+
+	var activationResult ActivationResult
+	if result != nil {
+		activationResult = ActivationResult{
+			Stderr: string(result.Stderr),
+			Stdout: string(result.Stdout),
+			Code:   result.Code,
+		}
+		logger.Debugf("Netplan apply result %q %q %d", result.Stderr, result.Stdout, result.Code)
+	}
+
+	switch {
+	case err != nil:
+		err = errors.Errorf("netplan apply error: %s", err)
+	case activationResult.Code != 0:
+		err = errors.Errorf("netplan apply error code %d", result.Code)
+	case params.Verify != nil:
+		// Rather than waiting a fixed time for the configuration to
+		// settle, the controller must be reachable by the deadline.
+		deadline := clk.Now().Add(params.VerifyTimeout)
+		if verifyErr := params.Verify(deadline); verifyErr != nil {
+			err = errors.Annotate(verifyErr, "verifying applied netplan")
+		}
+	}
+	if err == nil {
+		for _, backup := range previous.backedFiles {
+			if err := os.Remove(backup); err != nil {
+				logger.Warningf("cannot remove netplan backup %q: %v", backup, err)
+			}
+		}
+		return &activationResult, nil
+	}
+
+	rollback()
+	restore := fmt.Sprintf("%snetplan generate && netplan apply", params.RunPrefix)
+	restoreResult, restoreErr := scriptrunner.RunCommand(restore, environ, clk, params.Timeout)
+	if restoreErr != nil {
+		logger.Errorf("cannot restore previous netplan configuration: %v", restoreErr)
+	} else if restoreResult.Code != 0 {
+		logger.Errorf("cannot restore previous netplan configuration: error code %d", restoreResult.Code)
+	}
+	return &activationResult, err
+}
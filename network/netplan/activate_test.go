@@ -7,8 +7,11 @@ import (
 	"io/ioutil"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/juju/clock"
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -196,3 +199,151 @@ func (s *ActivateSuite) TestActivateTimeout(c *gc.C) {
 	c.Check(result, gc.NotNil)
 	c.Check(err, gc.ErrorMatches, "bridge activation error: command cancelled")
 }
+
+func (s *ActivateSuite) writeBackupTestdata(c *gc.C, dir string) ([]string, [][]byte) {
+	files := []string{"00.yaml", "01.yaml"}
+	contents := make([][]byte, len(files))
+	for i, file := range files {
+		var err error
+		contents[i], err = ioutil.ReadFile(path.Join("testdata/TestReadWriteBackup", file))
+		c.Assert(err, jc.ErrorIsNil)
+		err = ioutil.WriteFile(path.Join(dir, file), contents[i], 0644)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	return files, contents
+}
+
+func (s *ActivateSuite) desiredNetplan(c *gc.C) netplan.Netplan {
+	np, err := netplan.Render([]netplan.DeviceConfig{{
+		Name:       "eno1",
+		Type:       netplan.TypeEthernet,
+		MACAddress: "00:11:22:33:44:55",
+		Addresses:  []string{"10.0.0.5/24"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	return np
+}
+
+func (s *ActivateSuite) TestApplyWithRollbackSuccess(c *gc.C) {
+	coretesting.SkipIfWindowsBug(c, "lp:1771077")
+	tempDir := c.MkDir()
+	files, contents := s.writeBackupTestdata(c, tempDir)
+	err := ioutil.WriteFile(path.Join(tempDir, "99-juju.yaml"), []byte("network:\n  version: 2\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	desired := s.desiredNetplan(c)
+	result, err := netplan.ApplyWithRollback(netplan.ApplyParams{
+		Directory: tempDir,
+		Netplan:   desired,
+		RunPrefix: "exit 0 &&",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Code, gc.Equals, 0)
+
+	// The other configuration files are left in place, and the desired
+	// configuration replaces the one previously written by Juju.
+	s.checkFiles(c, tempDir, files, contents)
+	out, err := netplan.Marshal(&desired)
+	c.Assert(err, jc.ErrorIsNil)
+	content, err := ioutil.ReadFile(path.Join(tempDir, "99-juju.yaml"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(content), gc.Equals, string(out))
+	s.checkDirectory(c, tempDir, append(files, "99-juju.yaml"))
+}
+
+func (s *ActivateSuite) TestApplyWithRollbackFailure(c *gc.C) {
+	coretesting.SkipIfWindowsBug(c, "lp:1771077")
+	tempDir := c.MkDir()
+	files, contents := s.writeBackupTestdata(c, tempDir)
+
+	result, err := netplan.ApplyWithRollback(netplan.ApplyParams{
+		Directory: tempDir,
+		Netplan:   s.desiredNetplan(c),
+		RunPrefix: `echo -n "This is stderr" >&2 && exit 1 && `,
+	})
+	c.Assert(result, gc.NotNil)
+	c.Check(result.Stderr, gc.Equals, "This is stderr")
+	c.Check(result.Code, gc.Equals, 1)
+	c.Check(err, gc.ErrorMatches, "netplan apply error code 1")
+	s.checkFiles(c, tempDir, files, contents)
+	s.checkDirectory(c, tempDir, files)
+}
+
+func (s *ActivateSuite) TestApplyWithRollbackRestoresPrevious(c *gc.C) {
+	coretesting.SkipIfWindowsBug(c, "lp:1771077")
+	tempDir := c.MkDir()
+	files, contents := s.writeBackupTestdata(c, tempDir)
+	previous := []byte("network:\n  version: 2\n")
+	err := ioutil.WriteFile(path.Join(tempDir, "99-juju.yaml"), previous, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = netplan.ApplyWithRollback(netplan.ApplyParams{
+		Directory: tempDir,
+		Netplan:   s.desiredNetplan(c),
+		RunPrefix: "exit 1 && ",
+	})
+	c.Check(err, gc.ErrorMatches, "netplan apply error code 1")
+	files = append(files, "99-juju.yaml")
+	s.checkFiles(c, tempDir, files, append(contents, previous))
+	s.checkDirectory(c, tempDir, files)
+}
+
+func (s *ActivateSuite) TestApplyWithRollbackNilClock(c *gc.C) {
+	coretesting.SkipIfWindowsBug(c, "lp:1771077")
+	tempDir := c.MkDir()
+	files, contents := s.writeBackupTestdata(c, tempDir)
+
+	// Without a clock, the wall clock is used to time out both the
+	// apply and the restore of the previous configuration.
+	_, err := netplan.ApplyWithRollback(netplan.ApplyParams{
+		Directory: tempDir,
+		Netplan:   s.desiredNetplan(c),
+		RunPrefix: "exit 1 && ",
+		Timeout:   time.Minute,
+	})
+	c.Check(err, gc.ErrorMatches, "netplan apply error code 1")
+	s.checkFiles(c, tempDir, files, contents)
+	s.checkDirectory(c, tempDir, files)
+}
+
+func (s *ActivateSuite) TestApplyWithRollbackVerifyFailure(c *gc.C) {
+	coretesting.SkipIfWindowsBug(c, "lp:1771077")
+	tempDir := c.MkDir()
+	files, contents := s.writeBackupTestdata(c, tempDir)
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var deadline time.Time
+	_, err := netplan.ApplyWithRollback(netplan.ApplyParams{
+		Clock:         testclock.NewClock(now),
+		Directory:     tempDir,
+		Netplan:       s.desiredNetplan(c),
+		RunPrefix:     "exit 0 &&",
+		VerifyTimeout: time.Minute,
+		Verify: func(d time.Time) error {
+			deadline = d
+			return errors.New("controller unreachable")
+		},
+	})
+	c.Check(err, gc.ErrorMatches, "verifying applied netplan: controller unreachable")
+	c.Check(deadline, gc.Equals, now.Add(time.Minute))
+	s.checkFiles(c, tempDir, files, contents)
+	s.checkDirectory(c, tempDir, files)
+}
+
+func (s *ActivateSuite) checkFiles(c *gc.C, dir string, files []string, contents [][]byte) {
+	for i, file := range files {
+		content, err := ioutil.ReadFile(path.Join(dir, file))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(string(content), gc.Equals, string(contents[i]))
+	}
+}
+
+func (s *ActivateSuite) checkDirectory(c *gc.C, dir string, files []string) {
+	fileInfos, err := ioutil.ReadDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	var names []string
+	for _, fileInfo := range fileInfos {
+		names = append(names, fileInfo.Name())
+	}
+	c.Check(names, jc.SameContents, files)
+}
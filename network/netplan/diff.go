@@ -0,0 +1,244 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package netplan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	goyaml "gopkg.in/yaml.v2"
+)
+
+// ChangeType describes how a device differs between two netplan
+// configurations.
+type ChangeType string
+
+const (
+	// DeviceAdded is used for devices only in the desired configuration.
+	DeviceAdded ChangeType = "added"
+
+	// DeviceRemoved is used for devices only in the actual configuration.
+	DeviceRemoved ChangeType = "removed"
+
+	// DeviceChanged is used for devices in both configurations whose
+	// settings differ.
+	DeviceChanged ChangeType = "changed"
+)
+
+// DeviceChange describes the difference of a single device between the
+// actual and the desired netplan configuration.
+type DeviceChange struct {
+	Type   DeviceType
+	Name   string
+	Change ChangeType
+
+	// Details holds a line for each setting of the device that differs,
+	// in the form "<setting>: <actual> -> <desired>".
+	Details []string
+}
+
+// String is part of fmt.Stringer.
+func (c DeviceChange) String() string {
+	return fmt.Sprintf("%s %s %s", c.Type, c.Name, c.Change)
+}
+
+// Diff returns the differences between the actual netplan configuration
+// of a machine and the desired one, ordered by device type and name. Only
+// ethernets, bonds, VLANs and bridges are compared.
+func Diff(actual, desired Netplan) ([]DeviceChange, error) {
+	actualDevices, err := flattenDevices(actual.Network)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading actual netplan")
+	}
+	desiredDevices, err := flattenDevices(desired.Network)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading desired netplan")
+	}
+
+	var changes []DeviceChange
+	for _, deviceType := range []DeviceType{TypeEthernet, TypeBond, TypeVLAN, TypeBridge} {
+		actualByName := actualDevices[deviceType]
+		desiredByName := desiredDevices[deviceType]
+		for _, name := range sortedNames(actualByName, desiredByName) {
+			actualSettings, inActual := actualByName[name]
+			desiredSettings, inDesired := desiredByName[name]
+			change := DeviceChange{
+				Type:    deviceType,
+				Name:    name,
+				Details: diffSettings(actualSettings, desiredSettings),
+			}
+			switch {
+			case !inActual:
+				change.Change = DeviceAdded
+			case !inDesired:
+				change.Change = DeviceRemoved
+			case len(change.Details) > 0:
+				change.Change = DeviceChanged
+			default:
+				continue
+			}
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// MergeDiff returns the differences between the actual netplan
+// configuration of a machine and the desired one once merged with it, as
+// ApplyWithRollback does. Devices missing from the desired configuration
+// are left alone, so they are not reported as removed.
+func MergeDiff(actual, desired Netplan) ([]DeviceChange, error) {
+	changes, err := Diff(actual, desired)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var merged []DeviceChange
+	for _, change := range changes {
+		if change.Change != DeviceRemoved {
+			merged = append(merged, change)
+		}
+	}
+	return merged, nil
+}
+
+// flattenDevices returns the settings of each device in the given network,
+// keyed by device type and name.
+func flattenDevices(network Network) (map[DeviceType]map[string]map[string]string, error) {
+	result := make(map[DeviceType]map[string]map[string]string)
+	add := func(deviceType DeviceType, name string, device interface{}) error {
+		settings, err := flattenDevice(device)
+		if err != nil {
+			return errors.Annotatef(err, "%s %q", deviceType, name)
+		}
+		if result[deviceType] == nil {
+			result[deviceType] = make(map[string]map[string]string)
+		}
+		result[deviceType][name] = settings
+		return nil
+	}
+	for name, device := range network.Ethernets {
+		if err := add(TypeEthernet, name, device); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	for name, device := range network.Bonds {
+		if err := add(TypeBond, name, device); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	for name, device := range network.VLANs {
+		if err := add(TypeVLAN, name, device); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	for name, device := range network.Bridges {
+		if err := add(TypeBridge, name, device); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return result, nil
+}
+
+// flattenDevice returns the settings of a device as they would be written
+// to YAML, keyed by their dotted path (e.g. "parameters.mode").
+func flattenDevice(device interface{}) (map[string]string, error) {
+	out, err := goyaml.Marshal(device)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var value interface{}
+	if err := goyaml.Unmarshal(out, &value); err != nil {
+		return nil, errors.Trace(err)
+	}
+	settings := make(map[string]string)
+	flattenValue("", value, settings)
+	return settings, nil
+}
+
+func flattenValue(key string, value interface{}, settings map[string]string) {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		for k, v := range value {
+			childKey := fmt.Sprint(k)
+			if key != "" {
+				childKey = key + "." + childKey
+			}
+			flattenValue(childKey, v, settings)
+		}
+	case []interface{}:
+		if scalars, ok := scalarList(value); ok {
+			settings[key] = strings.Join(scalars, ", ")
+			return
+		}
+		for i, v := range value {
+			flattenValue(fmt.Sprintf("%s[%d]", key, i), v, settings)
+		}
+	case nil:
+	default:
+		settings[key] = fmt.Sprint(value)
+	}
+}
+
+// scalarList returns the given values as strings, if none of them are
+// maps or lists themselves.
+func scalarList(values []interface{}) ([]string, bool) {
+	scalars := make([]string, len(values))
+	for i, v := range values {
+		switch v.(type) {
+		case map[interface{}]interface{}, []interface{}:
+			return nil, false
+		}
+		scalars[i] = fmt.Sprint(v)
+	}
+	return scalars, true
+}
+
+// diffSettings returns a line for each setting that differs between the
+// actual and desired settings of a device, ordered by setting.
+func diffSettings(actual, desired map[string]string) []string {
+	var details []string
+	for _, key := range sortedSettings(actual, desired) {
+		actualValue, inActual := actual[key]
+		desiredValue, inDesired := desired[key]
+		if inActual && inDesired && actualValue == desiredValue {
+			continue
+		}
+		if !inActual {
+			actualValue = "(none)"
+		}
+		if !inDesired {
+			desiredValue = "(none)"
+		}
+		details = append(details, fmt.Sprintf("%s: %s -> %s", key, actualValue, desiredValue))
+	}
+	return details
+}
+
+// sortedNames returns the sorted union of the device names in the given
+// maps.
+func sortedNames(actual, desired map[string]map[string]string) []string {
+	names := set.NewStrings()
+	for name := range actual {
+		names.Add(name)
+	}
+	for name := range desired {
+		names.Add(name)
+	}
+	return names.SortedValues()
+}
+
+// sortedSettings returns the sorted union of the settings in the given
+// maps.
+func sortedSettings(actual, desired map[string]string) []string {
+	keys := set.NewStrings()
+	for key := range actual {
+		keys.Add(key)
+	}
+	for key := range desired {
+		keys.Add(key)
+	}
+	return keys.SortedValues()
+}
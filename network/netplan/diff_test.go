@@ -0,0 +1,180 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package netplan_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network/netplan"
+)
+
+type DiffSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&DiffSuite{})
+
+func unmarshalNetplan(c *gc.C, input string) netplan.Netplan {
+	var np netplan.Netplan
+	err := netplan.Unmarshal([]byte(input), &np)
+	c.Assert(err, jc.ErrorIsNil)
+	return np
+}
+
+func (s *DiffSuite) TestDiffNoChanges(c *gc.C) {
+	np := unmarshalNetplan(c, `
+network:
+  version: 2
+  ethernets:
+    eno1:
+      match:
+        macaddress: "00:11:22:33:44:55"
+      set-name: eno1
+      addresses:
+      - 10.0.0.5/24
+`)
+	changes, err := netplan.Diff(np, np)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(changes, gc.HasLen, 0)
+}
+
+func (s *DiffSuite) TestDiff(c *gc.C) {
+	actual := unmarshalNetplan(c, `
+network:
+  version: 2
+  ethernets:
+    eno1:
+      match:
+        macaddress: "00:11:22:33:44:55"
+      set-name: eno1
+      mtu: 1500
+    eno2:
+      match:
+        macaddress: "00:11:22:33:44:66"
+      set-name: eno2
+  bonds:
+    bond0:
+      interfaces: [eno1]
+      addresses:
+      - 10.0.0.5/24
+      parameters:
+        mode: active-backup
+`)
+	desired := unmarshalNetplan(c, `
+network:
+  version: 2
+  ethernets:
+    eno1:
+      match:
+        macaddress: "00:11:22:33:44:55"
+      set-name: eno1
+      mtu: 9000
+  bonds:
+    bond0:
+      interfaces: [eno1]
+      addresses:
+      - 10.0.0.5/24
+      parameters:
+        mode: 802.3ad
+  vlans:
+    bond0.42:
+      id: 42
+      link: bond0
+`)
+	changes, err := netplan.Diff(actual, desired)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(changes, jc.DeepEquals, []netplan.DeviceChange{{
+		Type:    netplan.TypeEthernet,
+		Name:    "eno1",
+		Change:  netplan.DeviceChanged,
+		Details: []string{"mtu: 1500 -> 9000"},
+	}, {
+		Type:   netplan.TypeEthernet,
+		Name:   "eno2",
+		Change: netplan.DeviceRemoved,
+		Details: []string{
+			"match.macaddress: 00:11:22:33:44:66 -> (none)",
+			"set-name: eno2 -> (none)",
+		},
+	}, {
+		Type:    netplan.TypeBond,
+		Name:    "bond0",
+		Change:  netplan.DeviceChanged,
+		Details: []string{"parameters.mode: active-backup -> 802.3ad"},
+	}, {
+		Type:   netplan.TypeVLAN,
+		Name:   "bond0.42",
+		Change: netplan.DeviceAdded,
+		Details: []string{
+			"id: (none) -> 42",
+			"link: (none) -> bond0",
+		},
+	}})
+}
+
+func (s *DiffSuite) TestMergeDiff(c *gc.C) {
+	actual := unmarshalNetplan(c, `
+network:
+  version: 2
+  ethernets:
+    eno1:
+      match:
+        macaddress: "00:11:22:33:44:55"
+      set-name: eno1
+      mtu: 1500
+  bridges:
+    lxdbr0:
+      addresses:
+      - 10.200.0.1/24
+`)
+	desired := unmarshalNetplan(c, `
+network:
+  version: 2
+  ethernets:
+    eno1:
+      match:
+        macaddress: "00:11:22:33:44:55"
+      set-name: eno1
+      mtu: 9000
+`)
+	changes, err := netplan.MergeDiff(actual, desired)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(changes, jc.DeepEquals, []netplan.DeviceChange{{
+		Type:    netplan.TypeEthernet,
+		Name:    "eno1",
+		Change:  netplan.DeviceChanged,
+		Details: []string{"mtu: 1500 -> 9000"},
+	}})
+}
+
+func (s *DiffSuite) TestDiffRoutes(c *gc.C) {
+	actual := unmarshalNetplan(c, `
+network:
+  version: 2
+  ethernets:
+    eno1:
+      routes:
+      - to: 10.100.0.0/16
+        via: 10.0.0.1
+`)
+	desired := unmarshalNetplan(c, `
+network:
+  version: 2
+  ethernets:
+    eno1:
+      routes:
+      - to: 10.100.0.0/16
+        via: 10.0.0.254
+        metric: 10
+`)
+	changes, err := netplan.Diff(actual, desired)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes, gc.HasLen, 1)
+	c.Check(changes[0].Details, jc.DeepEquals, []string{
+		"routes[0].metric: (none) -> 10",
+		"routes[0].via: 10.0.0.1 -> 10.0.0.254",
+	})
+}
@@ -548,6 +548,7 @@ const (
 	TypeEthernet = DeviceType("ethernet")
 	TypeVLAN     = DeviceType("vlan")
 	TypeBond     = DeviceType("bond")
+	TypeBridge   = DeviceType("bridge")
 )
 
 // FindDeviceByMACOrName will look for an Ethernet, VLAN or Bond matching the Name of the device or its MAC address.
@@ -0,0 +1,155 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package netplan
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// Render returns the netplan configuration for the given devices. Bond and
+// bridge members are listed as interfaces of the device they name as their
+// parent, and VLANs are linked to their parent device. Devices of types
+// that netplan does not configure (e.g. loopback) are skipped.
+func Render(devices []DeviceConfig) (Netplan, error) {
+	np := Netplan{Network: Network{Version: 2}}
+
+	types := make(map[string]DeviceType, len(devices))
+	for _, device := range devices {
+		if device.Name == "" {
+			return Netplan{}, errors.NotValidf("device without a name")
+		}
+		types[device.Name] = device.Type
+	}
+
+	members := make(map[string][]string)
+	for _, device := range devices {
+		if device.ParentName == "" || device.Type == TypeVLAN {
+			continue
+		}
+		switch types[device.ParentName] {
+		case TypeBond, TypeBridge:
+			members[device.ParentName] = append(members[device.ParentName], device.Name)
+		default:
+			return Netplan{}, errors.NotValidf("parent %q of device %q", device.ParentName, device.Name)
+		}
+	}
+	for _, names := range members {
+		sort.Strings(names)
+	}
+
+	for _, device := range devices {
+		intf := renderInterface(device)
+		switch device.Type {
+		case TypeEthernet:
+			ethernet := Ethernet{Interface: intf}
+			if device.MACAddress != "" {
+				ethernet.Match = map[string]string{"macaddress": device.MACAddress}
+				ethernet.SetName = device.Name
+			}
+			if np.Network.Ethernets == nil {
+				np.Network.Ethernets = make(map[string]Ethernet)
+			}
+			np.Network.Ethernets[device.Name] = ethernet
+		case TypeBond:
+			bond := Bond{
+				Interfaces: members[device.Name],
+				Interface:  intf,
+			}
+			bond.MACAddress = device.MACAddress
+			if device.BondMode != "" {
+				mode := device.BondMode
+				bond.Parameters.Mode = IntString{String: &mode}
+			}
+			if np.Network.Bonds == nil {
+				np.Network.Bonds = make(map[string]Bond)
+			}
+			np.Network.Bonds[device.Name] = bond
+		case TypeVLAN:
+			vlan, err := renderVLAN(device, intf, types)
+			if err != nil {
+				return Netplan{}, errors.Trace(err)
+			}
+			if np.Network.VLANs == nil {
+				np.Network.VLANs = make(map[string]VLAN)
+			}
+			np.Network.VLANs[device.Name] = vlan
+		case TypeBridge:
+			if np.Network.Bridges == nil {
+				np.Network.Bridges = make(map[string]Bridge)
+			}
+			bridge := Bridge{
+				Interfaces: members[device.Name],
+				Interface:  intf,
+			}
+			bridge.MACAddress = device.MACAddress
+			np.Network.Bridges[device.Name] = bridge
+		default:
+			logger.Debugf("not rendering device %q of type %q", device.Name, device.Type)
+		}
+	}
+	return np, nil
+}
+
+// renderVLAN returns the netplan VLAN definition for the given device,
+// taking the tag and link from the name of the device if they are not
+// set explicitly.
+func renderVLAN(device DeviceConfig, intf Interface, types map[string]DeviceType) (VLAN, error) {
+	id := device.VLANTag
+	link := device.ParentName
+	if dot := strings.LastIndex(device.Name, "."); dot > 0 {
+		if id == 0 {
+			id, _ = strconv.Atoi(device.Name[dot+1:])
+		}
+		if link == "" {
+			link = device.Name[:dot]
+		}
+	}
+	if id < 1 || id > 4094 {
+		return VLAN{}, errors.NotValidf("VLAN tag of device %q", device.Name)
+	}
+	if _, ok := types[link]; !ok {
+		return VLAN{}, errors.NotValidf("link %q of VLAN device %q", link, device.Name)
+	}
+	return VLAN{
+		Id:        &id,
+		Link:      link,
+		Interface: intf,
+	}, nil
+}
+
+// renderInterface returns the settings that are common to all device
+// types. The MAC address is left to the callers, as ethernets are matched
+// by it and VLANs inherit it from their link.
+func renderInterface(device DeviceConfig) Interface {
+	intf := Interface{
+		Addresses: device.Addresses,
+		Gateway4:  device.Gateway4,
+		Gateway6:  device.Gateway6,
+		MTU:       device.MTU,
+		Nameservers: Nameservers{
+			Search:    device.SearchDomains,
+			Addresses: device.Nameservers,
+		},
+	}
+	if device.DHCP4 {
+		dhcp4 := true
+		intf.DHCP4 = &dhcp4
+	}
+	for _, route := range device.Routes {
+		r := Route{
+			To:  route.To,
+			Via: route.Via,
+		}
+		if route.Metric != 0 {
+			metric := route.Metric
+			r.Metric = &metric
+		}
+		intf.Routes = append(intf.Routes, r)
+	}
+	return intf
+}
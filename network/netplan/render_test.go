@@ -0,0 +1,182 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package netplan_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network/netplan"
+)
+
+type RenderSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&RenderSuite{})
+
+func (s *RenderSuite) TestRenderBondVLANBridge(c *gc.C) {
+	devices := []netplan.DeviceConfig{{
+		Name:       "eno1",
+		Type:       netplan.TypeEthernet,
+		MACAddress: "00:11:22:33:44:55",
+		MTU:        9000,
+		ParentName: "bond0",
+	}, {
+		Name:       "eno2",
+		Type:       netplan.TypeEthernet,
+		MACAddress: "00:11:22:33:44:66",
+		MTU:        9000,
+		ParentName: "bond0",
+	}, {
+		Name:       "bond0",
+		Type:       netplan.TypeBond,
+		MACAddress: "00:11:22:33:44:55",
+		MTU:        9000,
+		BondMode:   "802.3ad",
+		Addresses:  []string{"10.0.0.5/24"},
+		Gateway4:   "10.0.0.1",
+		Nameservers: []string{
+			"10.0.0.2",
+		},
+		SearchDomains: []string{"maas"},
+	}, {
+		Name:       "bond0.42",
+		Type:       netplan.TypeVLAN,
+		ParentName: "bond0",
+		MTU:        1500,
+		Addresses:  []string{"10.42.0.5/24"},
+		Routes: []netplan.RouteConfig{{
+			To:     "10.100.0.0/16",
+			Via:    "10.42.0.1",
+			Metric: 10,
+		}},
+	}, {
+		Name:       "br-eno3",
+		Type:       netplan.TypeBridge,
+		MACAddress: "00:11:22:33:44:77",
+		DHCP4:      true,
+	}, {
+		Name:       "eno3",
+		Type:       netplan.TypeEthernet,
+		MACAddress: "00:11:22:33:44:77",
+		ParentName: "br-eno3",
+	}, {
+		Name: "lo",
+		Type: "loopback",
+	}}
+
+	np, err := netplan.Render(devices)
+	c.Assert(err, jc.ErrorIsNil)
+	out, err := netplan.Marshal(&np)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(out), gc.Equals, `
+network:
+  version: 2
+  ethernets:
+    eno1:
+      match:
+        macaddress: "00:11:22:33:44:55"
+      set-name: eno1
+      mtu: 9000
+    eno2:
+      match:
+        macaddress: "00:11:22:33:44:66"
+      set-name: eno2
+      mtu: 9000
+    eno3:
+      match:
+        macaddress: "00:11:22:33:44:77"
+      set-name: eno3
+  bridges:
+    br-eno3:
+      interfaces: [eno3]
+      dhcp4: true
+      macaddress: "00:11:22:33:44:77"
+  bonds:
+    bond0:
+      interfaces: [eno1, eno2]
+      addresses:
+      - 10.0.0.5/24
+      gateway4: 10.0.0.1
+      nameservers:
+        search: [maas]
+        addresses: [10.0.0.2]
+      macaddress: "00:11:22:33:44:55"
+      mtu: 9000
+      parameters:
+        mode: 802.3ad
+  vlans:
+    bond0.42:
+      id: 42
+      link: bond0
+      addresses:
+      - 10.42.0.5/24
+      mtu: 1500
+      routes:
+      - to: 10.100.0.0/16
+        via: 10.42.0.1
+        metric: 10
+`[1:])
+}
+
+func (s *RenderSuite) TestRenderVLANExplicitTag(c *gc.C) {
+	devices := []netplan.DeviceConfig{{
+		Name:       "eno1",
+		Type:       netplan.TypeEthernet,
+		MACAddress: "00:11:22:33:44:55",
+	}, {
+		Name:       "storage",
+		Type:       netplan.TypeVLAN,
+		ParentName: "eno1",
+		VLANTag:    100,
+	}}
+
+	np, err := netplan.Render(devices)
+	c.Assert(err, jc.ErrorIsNil)
+	vlan, ok := np.Network.VLANs["storage"]
+	c.Assert(ok, jc.IsTrue)
+	c.Check(*vlan.Id, gc.Equals, 100)
+	c.Check(vlan.Link, gc.Equals, "eno1")
+}
+
+func (s *RenderSuite) TestRenderVLANWithoutTag(c *gc.C) {
+	devices := []netplan.DeviceConfig{{
+		Name: "eno1",
+		Type: netplan.TypeEthernet,
+	}, {
+		Name:       "storage",
+		Type:       netplan.TypeVLAN,
+		ParentName: "eno1",
+	}}
+
+	_, err := netplan.Render(devices)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, `VLAN tag of device "storage" not valid`)
+}
+
+func (s *RenderSuite) TestRenderVLANUnknownLink(c *gc.C) {
+	devices := []netplan.DeviceConfig{{
+		Name: "eno1.42",
+		Type: netplan.TypeVLAN,
+	}}
+
+	_, err := netplan.Render(devices)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, `link "eno1" of VLAN device "eno1.42" not valid`)
+}
+
+func (s *RenderSuite) TestRenderUnknownParent(c *gc.C) {
+	devices := []netplan.DeviceConfig{{
+		Name:       "eno1",
+		Type:       netplan.TypeEthernet,
+		ParentName: "bond0",
+	}}
+
+	_, err := netplan.Render(devices)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, `parent "bond0" of device "eno1" not valid`)
+}
@@ -16,3 +16,64 @@ type DeviceToBridge struct {
 	// MACAddress is the MAC address of the device to be bridged
 	MACAddress string
 }
+
+// DeviceConfig describes the desired configuration of a single network
+// device, from which netplan configuration can be rendered.
+type DeviceConfig struct {
+	// Name is the name of the device on the machine.
+	Name string
+
+	// Type is the type of the device. Devices of other types than
+	// ethernets, bonds, VLANs and bridges are not rendered.
+	Type DeviceType
+
+	// MACAddress is the hardware address of the device, used to match
+	// ethernets to their names.
+	MACAddress string
+
+	// MTU is the maximum transmission unit of the device, if known.
+	MTU int
+
+	// ParentName is the name of the bond or bridge the device is a member
+	// of, or for a VLAN the name of the device it is defined on.
+	ParentName string
+
+	// VLANTag is the 802.1Q tag of a VLAN device. If it is zero, the tag
+	// is taken from the suffix of the device name (e.g. "eth0.42").
+	VLANTag int
+
+	// BondMode is the bonding mode of a bond device (e.g. "802.3ad").
+	BondMode string
+
+	// Addresses holds the static addresses of the device in CIDR
+	// notation (e.g. "10.0.0.5/24").
+	Addresses []string
+
+	// DHCP4 is true when the device should get its IPv4 address by DHCP.
+	DHCP4 bool
+
+	// Gateway4 and Gateway6 hold the default gateways for the device,
+	// if it is the default route of the machine.
+	Gateway4 string
+	Gateway6 string
+
+	// Nameservers and SearchDomains hold the DNS configuration of the
+	// device.
+	Nameservers   []string
+	SearchDomains []string
+
+	// Routes holds the additional routes to add when the device is up.
+	Routes []RouteConfig
+}
+
+// RouteConfig describes a single route to a subnet via a gateway.
+type RouteConfig struct {
+	// To is the destination subnet in CIDR notation.
+	To string
+
+	// Via is the address of the gateway.
+	Via string
+
+	// Metric is the weight of the route, which is not set if zero.
+	Metric int
+}
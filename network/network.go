@@ -175,6 +175,10 @@ type InterfaceInfo struct {
 	// InterfaceType is the type of the interface.
 	InterfaceType InterfaceType
 
+	// BondMode is the bonding mode of a bond interface (e.g. "802.3ad"
+	// or "active-backup"), if known.
+	BondMode string
+
 	// Disabled is true when the interface needs to be disabled on the
 	// machine, e.g. not to configure it.
 	Disabled bool
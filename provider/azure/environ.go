@@ -382,6 +382,9 @@ func (env *azureEnviron) ConstraintsValidator(ctx context.ProviderCallContext) (
 		constraints.CpuPower,
 		constraints.Tags,
 		constraints.VirtType,
+		constraints.Spot,
+		constraints.MaxPrice,
+		constraints.AntiAffinity,
 	})
 	validator.RegisterVocabulary(
 		constraints.Arch,
@@ -1853,6 +1853,73 @@ func (s *environSuite) TestAdoptResources(c *gc.C) {
 	c.Check(gTags[tags.JujuController], gc.Equals, "new-controller")
 }
 
+func (s *environSuite) TestUpdateResourceTags(c *gc.C) {
+	providersResult := makeProvidersResult()
+	resourcesResult := makeResourcesResult()
+	res1 := (*resourcesResult.Value)[0]
+	res1.Properties = &map[string]interface{}{"has-properties": true}
+	res2 := (*resourcesResult.Value)[1]
+	res2.Properties = &map[string]interface{}{"has-properties": true}
+
+	env := s.openEnviron(c)
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/resourcegroups/juju-testmodel-.*", makeResourceGroupResult()),
+		s.makeSender(".*/resourcegroups/juju-testmodel-.*", nil),
+		s.makeSender(".*/providers", providersResult),
+		s.makeSender(".*/resourceGroups/juju-testmodel-.*/resources", resourcesResult),
+		s.makeSender(".*/resourcegroups/.*/providers/Beck.Replica/liars/scissor/boxing-day-blues", res1),
+		s.makeSender(".*/resourcegroups/.*/providers/Beck.Replica/liars/scissor/boxing-day-blues", res1),
+		s.makeSender(".*/resourcegroups/.*/providers/Tuneyards.Bizness/micachu/drop-dead", res2),
+		s.makeSender(".*/resourcegroups/.*/providers/Tuneyards.Bizness/micachu/drop-dead", res2),
+	}
+
+	err := env.(environs.ResourceTagUpdater).UpdateResourceTags(s.callCtx, map[string]string{"owner": "fred"}, []string{"something else"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.requests, gc.HasLen, 8)
+
+	checkTags := func(ix int) {
+		req := s.requests[ix]
+		c.Check(req.Method, gc.Equals, "PUT")
+		data := make([]byte, req.ContentLength)
+		_, err := req.Body.Read(data)
+		c.Assert(err, jc.ErrorIsNil)
+		var resource resources.GenericResource
+		err = json.Unmarshal(data, &resource)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(to.StringMap(resource.Tags), jc.DeepEquals, map[string]string{
+			tags.JujuController: "old-controller",
+			"owner":             "fred",
+		})
+	}
+	checkTags(1)
+	checkTags(5)
+	checkTags(7)
+}
+
+func (s *environSuite) TestUpdateResourceTagsUnchanged(c *gc.C) {
+	env := s.openEnviron(c)
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/resourcegroups/juju-testmodel-.*", makeResourceGroupResult()),
+		s.makeSender(".*/providers", makeProvidersResult()),
+		s.makeSender(".*/resourceGroups/juju-testmodel-.*/resources", makeResourcesResult()),
+	}
+
+	// Neither the group nor the resources need updating.
+	err := env.(environs.ResourceTagUpdater).UpdateResourceTags(s.callCtx, map[string]string{"something else": "good"}, []string{"owner"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.requests, gc.HasLen, 3)
+}
+
+func (s *environSuite) TestResourceTagKeys(c *gc.C) {
+	env := s.openEnviron(c)
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/resourcegroups/juju-testmodel-.*", makeResourceGroupResult()),
+	}
+	keys, err := env.(environs.ResourceTagUpdater).ResourceTagKeys(s.callCtx, "something")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, jc.DeepEquals, []string{"something else"})
+}
+
 func makeProvidersResult() resources.ProviderListResult {
 	providers := []resources.Provider{{
 		Namespace: to.StringPtr("Beck.Replica"),
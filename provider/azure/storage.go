@@ -102,8 +102,13 @@ func newAzureStorageConfig(attrs map[string]interface{}) (*azureStorageConfig, e
 
 // ValidateConfig is part of the Provider interface.
 func (e *azureStorageProvider) ValidateConfig(cfg *storage.Config) error {
-	_, err := newAzureStorageConfig(cfg.Attrs())
-	return errors.Trace(err)
+	if _, err := newAzureStorageConfig(cfg.Attrs()); err != nil {
+		return errors.Trace(err)
+	}
+	// Managed disks are always encrypted at rest with platform-managed
+	// keys; customer-managed keys require a disk encryption set, which
+	// Juju does not create.
+	return errors.Trace(storage.ValidateNoKMSKey(cfg.Attrs(), "Azure disks"))
 }
 
 // Supports is part of the Provider interface.
@@ -155,6 +155,23 @@ func (s *storageSuite) TestFilesystemSource(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
 }
 
+func (s *storageSuite) TestValidateConfigEncrypted(c *gc.C) {
+	storageConfig, err := storage.NewConfig("azure", "azure", map[string]interface{}{
+		"encrypted": true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.provider.ValidateConfig(storageConfig)
+	c.Assert(err, jc.ErrorIsNil)
+
+	storageConfig, err = storage.NewConfig("azure", "azure", map[string]interface{}{
+		"encrypted": true,
+		"kms-key":   "key-id",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.provider.ValidateConfig(storageConfig)
+	c.Assert(err, gc.ErrorMatches, `"kms-key" for Azure disks not supported`)
+}
+
 func (s *storageSuite) TestSupports(c *gc.C) {
 	c.Assert(s.provider.Supports(storage.StorageKindBlock), jc.IsTrue)
 	c.Assert(s.provider.Supports(storage.StorageKindFilesystem), jc.IsFalse)
@@ -0,0 +1,131 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package azure
+
+import (
+	stdcontext "context"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2018-05-01/resources"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	internalazureresources "github.com/juju/juju/provider/azure/internal/azureresources"
+	"github.com/juju/juju/provider/azure/internal/errorutils"
+)
+
+var _ environs.ResourceTagUpdater = (*azureEnviron)(nil)
+
+// UpdateResourceTags is part of the environs.ResourceTagUpdater interface.
+// The tags are set on the model's resource group, and on every resource
+// in it, which includes the virtual machines and their disks, network
+// interfaces, public IP addresses, security groups and load balancers.
+func (env *azureEnviron) UpdateResourceTags(ctx context.ProviderCallContext, tags map[string]string, removed []string) error {
+	sdkCtx := stdcontext.Background()
+	groupClient := resources.GroupsClient{env.resources}
+	group, err := groupClient.Get(sdkCtx, env.resourceGroup)
+	if err != nil {
+		return errorutils.HandleCredentialError(errors.Annotate(err, "getting resource group"), ctx)
+	}
+	if group.Tags == nil {
+		group.Tags = make(map[string]*string)
+	}
+	if updateTags(group.Tags, tags, removed) {
+		// The Azure API forbids specifying ProvisioningState on the update.
+		if group.Properties != nil {
+			(*group.Properties).ProvisioningState = nil
+		}
+		if _, err := groupClient.CreateOrUpdate(sdkCtx, env.resourceGroup, group); err != nil {
+			return errorutils.HandleCredentialError(errors.Annotate(err, "updating resource group tags"), ctx)
+		}
+	}
+
+	apiVersions, err := collectAPIVersions(ctx, sdkCtx, resources.ProvidersClient{env.resources})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resourceClient := resources.Client{env.resources}
+	client := internalazureresources.ResourcesClient{&resourceClient}
+	res, err := resourceClient.ListByResourceGroupComplete(sdkCtx, env.resourceGroup, "", "", nil)
+	if err != nil {
+		return errorutils.HandleCredentialError(errors.Annotate(err, "listing resources"), ctx)
+	}
+	var failed []string
+	for ; res.NotDone(); err = res.NextWithContext(sdkCtx) {
+		if err != nil {
+			return errors.Annotate(err, "listing resources")
+		}
+		stubResource := res.Value()
+		stubTags := make(map[string]*string)
+		for k, v := range stubResource.Tags {
+			stubTags[k] = v
+		}
+		if !updateTags(stubTags, tags, removed) {
+			continue
+		}
+
+		// Need to get the resource individually to ensure that the
+		// properties are populated.
+		id := to.String(stubResource.ID)
+		apiVersion := apiVersions[to.String(stubResource.Type)]
+		resource, err := client.GetByID(sdkCtx, id, apiVersion)
+		if err == nil {
+			if resource.Tags == nil {
+				resource.Tags = make(map[string]*string)
+			}
+			updateTags(resource.Tags, tags, removed)
+			_, err = client.CreateOrUpdateByID(sdkCtx, id, resource, apiVersion)
+		}
+		if err != nil {
+			name := to.String(stubResource.Name)
+			logger.Errorf("error updating resource tags for %q: %v", name, errorutils.HandleCredentialError(err, ctx))
+			failed = append(failed, name)
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("failed to update tags for some resources: %v", failed)
+	}
+	return nil
+}
+
+// ResourceTagKeys is part of the environs.ResourceTagUpdater interface.
+// All of the model's resources are tagged along with its resource group,
+// so only the group's tags are consulted.
+func (env *azureEnviron) ResourceTagKeys(ctx context.ProviderCallContext, prefix string) ([]string, error) {
+	groupClient := resources.GroupsClient{env.resources}
+	group, err := groupClient.Get(stdcontext.Background(), env.resourceGroup)
+	if err != nil {
+		return nil, errorutils.HandleCredentialError(errors.Annotate(err, "getting resource group"), ctx)
+	}
+	var keys []string
+	for key := range group.Tags {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// updateTags sets the given tags in the resource tags, and removes
+// the removed tags, reporting whether the resource tags changed.
+func updateTags(resourceTags map[string]*string, tags map[string]string, removed []string) bool {
+	var changed bool
+	for k, v := range tags {
+		if current, ok := resourceTags[k]; !ok || to.String(current) != v {
+			resourceTags[k] = to.StringPtr(v)
+			changed = true
+		}
+	}
+	for _, k := range removed {
+		if _, ok := resourceTags[k]; ok {
+			delete(resourceTags, k)
+			changed = true
+		}
+	}
+	return changed
+}
@@ -13,6 +13,9 @@ var unsupportedConstraints = []string{
 	constraints.InstanceType,
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.Spot,
+	constraints.MaxPrice,
+	constraints.AntiAffinity,
 }
 
 // ConstraintsValidator returns a Validator instance which
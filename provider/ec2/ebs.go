@@ -41,7 +41,7 @@ const (
 	EBS_IOPS = "iops"
 
 	// Specifies whether the volume should be encrypted.
-	EBS_Encrypted = "encrypted"
+	EBS_Encrypted = storage.EncryptedAttribute
 
 	// Volume Aliases
 	volumeAliasMagnetic        = "magnetic"         // standard
@@ -201,6 +201,16 @@ func newEbsConfig(attrs map[string]interface{}) (*ebsConfig, error) {
 	if err != nil {
 		return nil, errors.Annotate(err, "validating EBS storage config")
 	}
+	encryption, err := storage.ParseEncryptionConfig(attrs)
+	if err != nil {
+		return nil, errors.Annotate(err, "validating EBS storage config")
+	}
+	if encryption.KMSKey != "" {
+		// The EC2 client does not support specifying a
+		// customer master key; volumes are encrypted with
+		// the account's default EBS key.
+		return nil, errors.NotSupportedf("%q for EBS volumes", storage.KMSKeyAttribute)
+	}
 	coerced := out.(map[string]interface{})
 	iops, _ := coerced[EBS_IOPS].(int)
 	volumeType := coerced[EBS_VolumeType].(string)
@@ -387,6 +397,7 @@ func (v *ebsVolumeSource) createVolume(ctx context.ProviderCallContext, p storag
 	}
 	vol, _ := parseVolumeOptions(p.Size, p.Attributes)
 	vol.AvailZone = inst.AvailZone
+	vol.SnapshotId = p.SnapshotId
 	resp, err := v.env.ec2.CreateVolume(vol)
 	if err != nil {
 		return nil, nil, errors.Trace(maybeConvertCredentialError(err, ctx))
@@ -409,6 +420,7 @@ func (v *ebsVolumeSource) createVolume(ctx context.ProviderCallContext, p storag
 			VolumeId:   volumeId,
 			Size:       gibToMib(uint64(resp.Size)),
 			Persistent: true,
+			Encrypted:  vol.Encrypted,
 		},
 	}
 	return &volume, nil, nil
@@ -0,0 +1,56 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/storage"
+)
+
+var _ storage.VolumeResizer = (*ebsVolumeSource)(nil)
+
+// ResizeVolumes is specified on the storage.VolumeResizer interface.
+// EBS volumes are sized in GiB, so the requested size is rounded up to
+// the nearest GiB.
+func (v *ebsVolumeSource) ResizeVolumes(ctx context.ProviderCallContext, params []storage.VolumeResizeParams) ([]storage.ResizeResult, error) {
+	results := make([]storage.ResizeResult, len(params))
+	for i, p := range params {
+		sizeGiB := mibToGib(p.Size)
+		if err := modifyVolumeSize(v.env.ec2, p.VolumeId, sizeGiB); err != nil {
+			results[i].Error = maybeConvertCredentialError(err, ctx)
+			continue
+		}
+		results[i].Size = gibToMib(sizeGiB)
+	}
+	return results, nil
+}
+
+// modifyVolumeSize changes the size of the given volume. The EC2
+// client library does not support modifying volumes, so the request
+// is made directly against the EC2 query API.
+func modifyVolumeSize(client *ec2.EC2, volumeId string, sizeGiB uint64) error {
+	params := url.Values{
+		"Action":   {"ModifyVolume"},
+		"Version":  {spotAPIVersion},
+		"VolumeId": {volumeId},
+		"Size":     {strconv.FormatUint(sizeGiB, 10)},
+	}
+	var resp struct {
+		RequestId string `xml:"requestId"`
+		State     string `xml:"volumeModification>modificationState"`
+	}
+	if err := ec2Query(client, params, &resp); err != nil {
+		return errors.Annotatef(err, "resizing volume %q", volumeId)
+	}
+	if resp.State == "failed" {
+		return errors.Errorf("resizing volume %q failed (request %s)", volumeId, resp.RequestId)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+)
+
+type ebsResizeSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&ebsResizeSuite{})
+
+func (s *ebsResizeSuite) TestModifyVolumeSize(c *gc.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		c.Check(query.Get("Action"), gc.Equals, "ModifyVolume")
+		c.Check(query.Get("VolumeId"), gc.Equals, "vol-0")
+		c.Check(query.Get("Size"), gc.Equals, "3")
+		fmt.Fprint(w, `<ModifyVolumeResponse><requestId>req-1</requestId><volumeModification><modificationState>modifying</modificationState></volumeModification></ModifyVolumeResponse>`)
+	}))
+	defer srv.Close()
+
+	err := modifyVolumeSize(newSpotTestClient(srv.URL), "vol-0", mibToGib(2049))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ebsResizeSuite) TestModifyVolumeSizeFailed(c *gc.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<ModifyVolumeResponse><requestId>req-1</requestId><volumeModification><modificationState>failed</modificationState></volumeModification></ModifyVolumeResponse>`)
+	}))
+	defer srv.Close()
+
+	err := modifyVolumeSize(newSpotTestClient(srv.URL), "vol-0", 3)
+	c.Assert(err, gc.ErrorMatches, `resizing volume "vol-0" failed \(request req-1\)`)
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/storage"
+)
+
+var _ storage.VolumeSnapshotter = (*ebsVolumeSource)(nil)
+
+// CreateVolumeSnapshots is specified on the storage.VolumeSnapshotter
+// interface. EBS snapshots are created asynchronously; volumes created
+// from a snapshot that is still pending will fail, and be retried by
+// the storage provisioner.
+func (v *ebsVolumeSource) CreateVolumeSnapshots(ctx context.ProviderCallContext, params []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+	results := make([]storage.CreateVolumeSnapshotsResult, len(params))
+	for i, p := range params {
+		description := fmt.Sprintf("juju snapshot %s of %s", p.Snapshot, resourceName(p.Tag, v.envName))
+		snapshotId, sizeGiB, err := createSnapshot(v.env.ec2, p.VolumeId, description)
+		if err != nil {
+			results[i].Error = maybeConvertCredentialError(err, ctx)
+			continue
+		}
+		resourceTags := make(map[string]string)
+		for k, v := range p.ResourceTags {
+			resourceTags[k] = v
+		}
+		resourceTags[tagName] = description
+		if err := tagResources(v.env.ec2, ctx, resourceTags, snapshotId); err != nil {
+			results[i].Error = errors.Annotate(err, "tagging snapshot")
+			continue
+		}
+		results[i].SnapshotId = snapshotId
+		results[i].Size = gibToMib(sizeGiB)
+	}
+	return results, nil
+}
+
+// createSnapshot creates a snapshot of the given volume, returning the
+// snapshot ID and the size of the volume in GiB. The request is made
+// directly against the EC2 query API, so that the volume size is
+// reported.
+func createSnapshot(client *ec2.EC2, volumeId, description string) (string, uint64, error) {
+	params := url.Values{
+		"Action":      {"CreateSnapshot"},
+		"Version":     {spotAPIVersion},
+		"VolumeId":    {volumeId},
+		"Description": {description},
+	}
+	var resp struct {
+		RequestId  string `xml:"requestId"`
+		SnapshotId string `xml:"snapshotId"`
+		VolumeSize uint64 `xml:"volumeSize"`
+		Status     string `xml:"status"`
+	}
+	if err := ec2Query(client, params, &resp); err != nil {
+		return "", 0, errors.Annotatef(err, "snapshotting volume %q", volumeId)
+	}
+	if resp.Status == "error" {
+		return "", 0, errors.Errorf("snapshotting volume %q failed (request %s)", volumeId, resp.RequestId)
+	}
+	return resp.SnapshotId, resp.VolumeSize, nil
+}
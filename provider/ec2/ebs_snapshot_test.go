@@ -0,0 +1,47 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+)
+
+type ebsSnapshotSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&ebsSnapshotSuite{})
+
+func (s *ebsSnapshotSuite) TestCreateSnapshot(c *gc.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		c.Check(query.Get("Action"), gc.Equals, "CreateSnapshot")
+		c.Check(query.Get("VolumeId"), gc.Equals, "vol-0")
+		c.Check(query.Get("Description"), gc.Equals, "backup")
+		fmt.Fprint(w, `<CreateSnapshotResponse><requestId>req-1</requestId><snapshotId>snap-0</snapshotId><volumeId>vol-0</volumeId><status>pending</status><volumeSize>8</volumeSize></CreateSnapshotResponse>`)
+	}))
+	defer srv.Close()
+
+	snapshotId, sizeGiB, err := createSnapshot(newSpotTestClient(srv.URL), "vol-0", "backup")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshotId, gc.Equals, "snap-0")
+	c.Assert(sizeGiB, gc.Equals, uint64(8))
+}
+
+func (s *ebsSnapshotSuite) TestCreateSnapshotFailed(c *gc.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<CreateSnapshotResponse><requestId>req-1</requestId><snapshotId>snap-0</snapshotId><status>error</status></CreateSnapshotResponse>`)
+	}))
+	defer srv.Close()
+
+	_, _, err := createSnapshot(newSpotTestClient(srv.URL), "vol-0", "backup")
+	c.Assert(err, gc.ErrorMatches, `snapshotting volume "vol-0" failed \(request req-1\)`)
+}
@@ -102,6 +102,31 @@ func (s *ebsSuite) TestValidateConfigUnknownConfig(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil) // unknown attrs ignored
 }
 
+func (s *ebsSuite) TestValidateConfigEncryption(c *gc.C) {
+	p := s.ebsProvider(c)
+	cfg, err := storage.NewConfig("foo", ec2.EBS_ProviderType, map[string]interface{}{
+		"encrypted": "true",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.ValidateConfig(cfg), jc.ErrorIsNil)
+
+	cfg, err = storage.NewConfig("foo", ec2.EBS_ProviderType, map[string]interface{}{
+		"encrypted": true,
+		"kms-key":   "alias/juju",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = p.ValidateConfig(cfg)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, `"kms-key" for EBS volumes not supported`)
+
+	cfg, err = storage.NewConfig("foo", ec2.EBS_ProviderType, map[string]interface{}{
+		"kms-key": "alias/juju",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = p.ValidateConfig(cfg)
+	c.Assert(err, gc.ErrorMatches, `validating EBS storage config: "kms-key" specified, but "encrypted" is not true`)
+}
+
 func (s *ebsSuite) TestSupports(c *gc.C) {
 	p := s.ebsProvider(c)
 	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsTrue)
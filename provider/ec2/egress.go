@@ -0,0 +1,229 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/network"
+)
+
+var _ environs.EgressFirewaller = (*environ)(nil)
+
+// SetEgressRules is part of the environs.EgressFirewaller interface.
+// The egress permissions of the machine's security group are replaced
+// with the given rules, or with EC2's default permission allowing all
+// egress if there are none. Security group permissions are a union, so
+// the egress permissions of every other group the instance belongs to,
+// such as the model group, are revoked. New permissions are authorized
+// before the old ones are revoked, so egress that is allowed before and
+// after the change is never interrupted.
+func (e *environ) SetEgressRules(ctx context.ProviderCallContext, machineId string, instId instance.Id, rules []network.EgressRule) error {
+	if e.Config().FirewallMode() != config.FwInstance {
+		return errors.Errorf("invalid firewall mode %q for setting egress rules", e.Config().FirewallMode())
+	}
+	name := e.machineGroupName(machineId)
+	err := setInstanceEgressPermissions(e.ec2, instId, name, egressRulesToPermissions(rules))
+	if err != nil {
+		return maybeConvertCredentialError(err, ctx)
+	}
+	logger.Infof("set egress rules of security group %s: %v", name, rules)
+	return nil
+}
+
+// setInstanceEgressPermissions gives the named machine security group
+// of the instance the given egress permissions, and removes the egress
+// permissions of all other security groups of the instance.
+func setInstanceEgressPermissions(client *ec2.EC2, instId instance.Id, machineGroupName string, perms []egressPermission) error {
+	resp, err := client.Instances([]string{string(instId)}, nil)
+	if err != nil {
+		return errors.Annotate(err, "describing instance")
+	}
+	var groups []ec2.SecurityGroup
+	for _, res := range resp.Reservations {
+		for _, inst := range res.Instances {
+			groups = append(groups, inst.SecurityGroups...)
+		}
+	}
+	var machineGroup *ec2.SecurityGroup
+	var others []ec2.SecurityGroup
+	for i, g := range groups {
+		if g.Name == machineGroupName {
+			machineGroup = &groups[i]
+		} else {
+			others = append(others, g)
+		}
+	}
+	if machineGroup == nil {
+		return errors.NotFoundf("security group %q of instance %q", machineGroupName, instId)
+	}
+
+	current, err := egressPermissions(client, machineGroup.Id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	toAuthorize, toRevoke := diffEgressPermissions(current, perms)
+	if err := updateEgressPermissions(client, "AuthorizeSecurityGroupEgress", machineGroup.Id, toAuthorize); err != nil {
+		return errors.Trace(err)
+	}
+	for _, g := range others {
+		otherPerms, err := egressPermissions(client, g.Id)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := updateEgressPermissions(client, "RevokeSecurityGroupEgress", g.Id, otherPerms); err != nil {
+			return errors.Annotatef(err, "security group %q", g.Name)
+		}
+	}
+	return errors.Trace(updateEgressPermissions(client, "RevokeSecurityGroupEgress", machineGroup.Id, toRevoke))
+}
+
+// egressPermission is a single egress permission of a security
+// group. Protocol "-1" allows all protocols and ports.
+type egressPermission struct {
+	Protocol string
+	FromPort int
+	ToPort   int
+	CIDR     string
+}
+
+func (p egressPermission) String() string {
+	return fmt.Sprintf("%s:%d-%d:%s", p.Protocol, p.FromPort, p.ToPort, p.CIDR)
+}
+
+// allowAllEgress is the permission EC2 gives new security groups.
+var allowAllEgress = egressPermission{Protocol: "-1", CIDR: defaultRouteCIDRBlock}
+
+// egressRulesToPermissions returns the permissions which allow the
+// given egress rules, or all egress if there are no rules.
+func egressRulesToPermissions(rules []network.EgressRule) []egressPermission {
+	if len(rules) == 0 {
+		return []egressPermission{allowAllEgress}
+	}
+	var perms []egressPermission
+	for _, rule := range rules {
+		for _, cidr := range rule.DestinationCIDRs {
+			perms = append(perms, egressPermission{
+				Protocol: rule.Protocol,
+				FromPort: rule.FromPort,
+				ToPort:   rule.ToPort,
+				CIDR:     cidr,
+			})
+		}
+	}
+	return perms
+}
+
+// diffEgressPermissions returns the permissions which are wanted but
+// not current, and those which are current but not wanted.
+func diffEgressPermissions(current, want []egressPermission) (toAuthorize, toRevoke []egressPermission) {
+	currentSet := make(map[egressPermission]bool)
+	for _, p := range current {
+		currentSet[p] = true
+	}
+	wantSet := make(map[egressPermission]bool)
+	for _, p := range want {
+		if !currentSet[p] && !wantSet[p] {
+			toAuthorize = append(toAuthorize, p)
+		}
+		wantSet[p] = true
+	}
+	for _, p := range current {
+		if !wantSet[p] {
+			toRevoke = append(toRevoke, p)
+		}
+	}
+	sortEgressPermissions(toAuthorize)
+	sortEgressPermissions(toRevoke)
+	return toAuthorize, toRevoke
+}
+
+func sortEgressPermissions(perms []egressPermission) {
+	sort.Slice(perms, func(i, j int) bool {
+		return perms[i].String() < perms[j].String()
+	})
+}
+
+// The EC2 client library does not support egress permissions, so the
+// requests are made directly against the EC2 query API.
+
+// egressPermissions returns the egress permissions of the security
+// group with the given id.
+func egressPermissions(client *ec2.EC2, groupId string) ([]egressPermission, error) {
+	params := url.Values{
+		"Action":    {"DescribeSecurityGroups"},
+		"Version":   {spotAPIVersion},
+		"GroupId.1": {groupId},
+	}
+	var resp struct {
+		Groups []struct {
+			Egress []struct {
+				Protocol string   `xml:"ipProtocol"`
+				FromPort int      `xml:"fromPort"`
+				ToPort   int      `xml:"toPort"`
+				CIDRs    []string `xml:"ipRanges>item>cidrIp"`
+			} `xml:"ipPermissionsEgress>item"`
+		} `xml:"securityGroupInfo>item"`
+	}
+	if err := ec2Query(client, params, &resp); err != nil {
+		return nil, errors.Annotate(err, "describing security group")
+	}
+	if len(resp.Groups) != 1 {
+		return nil, errors.NotFoundf("security group %q", groupId)
+	}
+	var perms []egressPermission
+	for _, p := range resp.Groups[0].Egress {
+		for _, cidr := range p.CIDRs {
+			perm := egressPermission{Protocol: p.Protocol, CIDR: cidr}
+			if p.Protocol != "-1" {
+				perm.FromPort = p.FromPort
+				perm.ToPort = p.ToPort
+			}
+			perms = append(perms, perm)
+		}
+	}
+	return perms, nil
+}
+
+// updateEgressPermissions authorizes or revokes the given egress
+// permissions of the security group with the given id.
+func updateEgressPermissions(client *ec2.EC2, action, groupId string, perms []egressPermission) error {
+	if len(perms) == 0 {
+		return nil
+	}
+	params := url.Values{
+		"Action":  {action},
+		"Version": {spotAPIVersion},
+		"GroupId": {groupId},
+	}
+	for i, p := range perms {
+		prefix := fmt.Sprintf("IpPermissions.%d.", i+1)
+		params.Set(prefix+"IpProtocol", p.Protocol)
+		if p.Protocol != "-1" {
+			params.Set(prefix+"FromPort", fmt.Sprint(p.FromPort))
+			params.Set(prefix+"ToPort", fmt.Sprint(p.ToPort))
+		}
+		params.Set(prefix+"IpRanges.1.CidrIp", p.CIDR)
+	}
+	var resp struct {
+		RequestId string `xml:"requestId"`
+		Return    bool   `xml:"return"`
+	}
+	if err := ec2Query(client, params, &resp); err != nil {
+		return errors.Annotatef(err, "%s", action)
+	}
+	if !resp.Return {
+		return errors.Errorf("%s request %s failed", action, resp.RequestId)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type egressSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&egressSuite{})
+
+func (s *egressSuite) TestEgressRulesToPermissions(c *gc.C) {
+	c.Assert(egressRulesToPermissions(nil), jc.DeepEquals, []egressPermission{allowAllEgress})
+
+	perms := egressRulesToPermissions([]network.EgressRule{
+		network.MustNewEgressRule("tcp", 5432, 5432, "10.0.0.0/8", "192.168.0.0/16"),
+	})
+	c.Assert(perms, jc.DeepEquals, []egressPermission{
+		{Protocol: "tcp", FromPort: 5432, ToPort: 5432, CIDR: "10.0.0.0/8"},
+		{Protocol: "tcp", FromPort: 5432, ToPort: 5432, CIDR: "192.168.0.0/16"},
+	})
+}
+
+func (s *egressSuite) TestDiffEgressPermissions(c *gc.C) {
+	postgres := egressPermission{Protocol: "tcp", FromPort: 5432, ToPort: 5432, CIDR: "10.0.0.0/8"}
+	api := egressPermission{Protocol: "tcp", FromPort: 17070, ToPort: 17070, CIDR: "10.0.0.1/32"}
+
+	toAuthorize, toRevoke := diffEgressPermissions(
+		[]egressPermission{allowAllEgress, api},
+		[]egressPermission{postgres, api, postgres},
+	)
+	c.Assert(toAuthorize, jc.DeepEquals, []egressPermission{postgres})
+	c.Assert(toRevoke, jc.DeepEquals, []egressPermission{allowAllEgress})
+}
+
+func (s *egressSuite) TestEgressPermissions(c *gc.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.URL.Query().Get("Action"), gc.Equals, "DescribeSecurityGroups")
+		c.Check(r.URL.Query().Get("GroupId.1"), gc.Equals, "sg-1")
+		fmt.Fprint(w, `<DescribeSecurityGroupsResponse><securityGroupInfo><item>
+<groupId>sg-1</groupId>
+<ipPermissionsEgress>
+<item><ipProtocol>-1</ipProtocol><ipRanges><item><cidrIp>0.0.0.0/0</cidrIp></item></ipRanges></item>
+<item><ipProtocol>tcp</ipProtocol><fromPort>5432</fromPort><toPort>5432</toPort><ipRanges><item><cidrIp>10.0.0.0/8</cidrIp></item><item><cidrIp>192.168.0.0/16</cidrIp></item></ipRanges></item>
+</ipPermissionsEgress>
+</item></securityGroupInfo></DescribeSecurityGroupsResponse>`)
+	}))
+	defer srv.Close()
+
+	perms, err := egressPermissions(newSpotTestClient(srv.URL), "sg-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(perms, jc.DeepEquals, []egressPermission{
+		allowAllEgress,
+		{Protocol: "tcp", FromPort: 5432, ToPort: 5432, CIDR: "10.0.0.0/8"},
+		{Protocol: "tcp", FromPort: 5432, ToPort: 5432, CIDR: "192.168.0.0/16"},
+	})
+}
+
+func (s *egressSuite) TestUpdateEgressPermissions(c *gc.C) {
+	var requests []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		query.Del("Version")
+		requests = append(requests, query)
+		fmt.Fprint(w, `<Response><requestId>req-1</requestId><return>true</return></Response>`)
+	}))
+	defer srv.Close()
+	client := newSpotTestClient(srv.URL)
+
+	err := updateEgressPermissions(client, "AuthorizeSecurityGroupEgress", "sg-1", []egressPermission{
+		{Protocol: "tcp", FromPort: 5432, ToPort: 5432, CIDR: "10.0.0.0/8"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = updateEgressPermissions(client, "RevokeSecurityGroupEgress", "sg-1", []egressPermission{allowAllEgress})
+	c.Assert(err, jc.ErrorIsNil)
+	err = updateEgressPermissions(client, "RevokeSecurityGroupEgress", "sg-1", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(requests, jc.DeepEquals, []url.Values{{
+		"Action":                            {"AuthorizeSecurityGroupEgress"},
+		"GroupId":                           {"sg-1"},
+		"IpPermissions.1.IpProtocol":        {"tcp"},
+		"IpPermissions.1.FromPort":          {"5432"},
+		"IpPermissions.1.ToPort":            {"5432"},
+		"IpPermissions.1.IpRanges.1.CidrIp": {"10.0.0.0/8"},
+	}, {
+		"Action":                            {"RevokeSecurityGroupEgress"},
+		"GroupId":                           {"sg-1"},
+		"IpPermissions.1.IpProtocol":        {"-1"},
+		"IpPermissions.1.IpRanges.1.CidrIp": {"0.0.0.0/0"},
+	}})
+}
+
+const egressInstanceResponse = `<DescribeInstancesResponse><reservationSet><item>
+<instancesSet><item>
+<instanceId>i-1</instanceId>
+<groupSet>
+<item><groupId>sg-model</groupId><groupName>juju-model</groupName></item>
+<item><groupId>sg-machine</groupId><groupName>juju-model-0</groupName></item>
+</groupSet>
+</item></instancesSet>
+</item></reservationSet></DescribeInstancesResponse>`
+
+const egressAllowAllResponse = `<DescribeSecurityGroupsResponse><securityGroupInfo><item>
+<ipPermissionsEgress>
+<item><ipProtocol>-1</ipProtocol><ipRanges><item><cidrIp>0.0.0.0/0</cidrIp></item></ipRanges></item>
+</ipPermissionsEgress>
+</item></securityGroupInfo></DescribeSecurityGroupsResponse>`
+
+func (s *egressSuite) TestSetInstanceEgressPermissions(c *gc.C) {
+	var requests []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch query.Get("Action") {
+		case "DescribeInstances":
+			c.Check(query.Get("InstanceId.1"), gc.Equals, "i-1")
+			fmt.Fprint(w, egressInstanceResponse)
+		case "DescribeSecurityGroups":
+			// Every group of the instance has EC2's default egress.
+			fmt.Fprint(w, egressAllowAllResponse)
+		default:
+			query.Del("Version")
+			requests = append(requests, query)
+			fmt.Fprint(w, `<Response><requestId>req-1</requestId><return>true</return></Response>`)
+		}
+	}))
+	defer srv.Close()
+
+	err := setInstanceEgressPermissions(newSpotTestClient(srv.URL), instance.Id("i-1"), "juju-model-0", []egressPermission{
+		{Protocol: "tcp", FromPort: 5432, ToPort: 5432, CIDR: "10.0.0.0/8"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The model group's egress is revoked as well as the machine
+	// group's, as either would otherwise allow all egress.
+	c.Assert(requests, jc.DeepEquals, []url.Values{{
+		"Action":                            {"AuthorizeSecurityGroupEgress"},
+		"GroupId":                           {"sg-machine"},
+		"IpPermissions.1.IpProtocol":        {"tcp"},
+		"IpPermissions.1.FromPort":          {"5432"},
+		"IpPermissions.1.ToPort":            {"5432"},
+		"IpPermissions.1.IpRanges.1.CidrIp": {"10.0.0.0/8"},
+	}, {
+		"Action":                            {"RevokeSecurityGroupEgress"},
+		"GroupId":                           {"sg-model"},
+		"IpPermissions.1.IpProtocol":        {"-1"},
+		"IpPermissions.1.IpRanges.1.CidrIp": {"0.0.0.0/0"},
+	}, {
+		"Action":                            {"RevokeSecurityGroupEgress"},
+		"GroupId":                           {"sg-machine"},
+		"IpPermissions.1.IpProtocol":        {"-1"},
+		"IpPermissions.1.IpRanges.1.CidrIp": {"0.0.0.0/0"},
+	}})
+}
+
+func (s *egressSuite) TestSetInstanceEgressPermissionsNoMachineGroup(c *gc.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.URL.Query().Get("Action"), gc.Equals, "DescribeInstances")
+		fmt.Fprint(w, egressInstanceResponse)
+	}))
+	defer srv.Close()
+
+	err := setInstanceEgressPermissions(newSpotTestClient(srv.URL), instance.Id("i-1"), "juju-model-1", nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
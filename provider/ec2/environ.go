@@ -83,6 +83,7 @@ type environ struct {
 
 var _ environs.Environ = (*environ)(nil)
 var _ environs.Networking = (*environ)(nil)
+var _ environs.DefaultInstanceConstraintsApplier = (*environ)(nil)
 
 func (e *environ) Config() *config.Config {
 	return e.ecfg().Config
@@ -558,6 +559,14 @@ func (e *environ) StartInstance(ctx context.ProviderCallContext, args environs.S
 		ImageId:             spec.Image.Id,
 	}
 
+	if args.Constraints.HasAntiAffinity() {
+		callback(status.Allocating, "Setting up placement group", nil)
+		commonRunArgs.PlacementGroupName, err = e.ensurePlacementGroup(ctx, *args.Constraints.AntiAffinity)
+		if err != nil {
+			return nil, common.ZoneIndependentError(err)
+		}
+	}
+
 	runArgs := commonRunArgs
 	runArgs.AvailZone = availabilityZone
 
@@ -606,7 +615,16 @@ func (e *environ) StartInstance(ctx context.ProviderCallContext, args environs.S
 	}
 
 	callback(status.Allocating, fmt.Sprintf("Trying to start instance in availability zone %q", availabilityZone), nil)
-	instResp, err = runInstances(e.ec2, ctx, runArgs, callback)
+	spot := args.Constraints.HasSpot()
+	if spot {
+		var maxPrice string
+		if args.Constraints.HasMaxPrice() {
+			maxPrice = *args.Constraints.MaxPrice
+		}
+		instResp, err = runSpotInstances(e.ec2, ctx, runArgs, maxPrice, callback)
+	} else {
+		instResp, err = runInstances(e.ec2, ctx, runArgs, callback)
+	}
 	if err != nil {
 		if !isZoneOrSubnetConstrainedError(err) {
 			err = annotateWrapError(err, "cannot run instances")
@@ -635,6 +653,9 @@ func (e *environ) StartInstance(ctx context.ProviderCallContext, args environs.S
 		names.NewMachineTag(args.InstanceConfig.MachineId), e.Config().Name(),
 	)
 	args.InstanceConfig.Tags[tagName] = instanceName
+	if spot {
+		args.InstanceConfig.Tags[tagSpotInstance] = "true"
+	}
 	if err := tagResources(e.ec2, ctx, args.InstanceConfig.Tags, string(inst.Id())); err != nil {
 		return nil, annotateWrapError(err, "tagging instance")
 	}
@@ -1436,6 +1457,7 @@ func (e *environ) Destroy(ctx context.ProviderCallContext) error {
 	if err := e.cleanEnvironmentSecurityGroups(ctx); err != nil {
 		return errors.Annotate(maybeConvertCredentialError(err, ctx), "cannot delete environment security groups")
 	}
+	e.deletePlacementGroups(ctx)
 	return nil
 }
 
@@ -1506,19 +1528,27 @@ func (e *environ) allModelVolumes(ctx context.ProviderCallContext, includeRootDi
 	return listVolumes(e.ec2, ctx, filter, includeRootDisks)
 }
 
+// rulesToIPPerms returns the permissions for the IPv4 source ranges of
+// the given rules; rules without source ranges are open to all IPv4
+// traffic. Rules with only IPv6 source ranges have no IPv4 permissions.
 func rulesToIPPerms(rules []network.IngressRule) []ec2.IPPerm {
-	ipPerms := make([]ec2.IPPerm, len(rules))
-	for i, r := range rules {
-		ipPerms[i] = ec2.IPPerm{
+	var ipPerms []ec2.IPPerm
+	for _, r := range rules {
+		ipPerm := ec2.IPPerm{
 			Protocol: r.Protocol,
 			FromPort: r.FromPort,
 			ToPort:   r.ToPort,
 		}
 		if len(r.SourceCIDRs) == 0 {
-			ipPerms[i].SourceIPs = []string{defaultRouteCIDRBlock}
-		} else {
-			ipPerms[i].SourceIPs = make([]string, len(r.SourceCIDRs))
-			copy(ipPerms[i].SourceIPs, r.SourceCIDRs)
+			ipPerm.SourceIPs = []string{defaultRouteCIDRBlock}
+		}
+		for _, cidr := range r.SourceCIDRs {
+			if !isIPv6CIDR(cidr) {
+				ipPerm.SourceIPs = append(ipPerm.SourceIPs, cidr)
+			}
+		}
+		if len(ipPerm.SourceIPs) > 0 {
+			ipPerms = append(ipPerms, ipPerm)
 		}
 	}
 	return ipPerms
@@ -1533,10 +1563,22 @@ func (e *environ) openPortsInGroup(ctx context.ProviderCallContext, name string,
 	if err != nil {
 		return err
 	}
-	ipPerms := rulesToIPPerms(rules)
-	_, err = e.ec2.AuthorizeSecurityGroup(g, ipPerms)
+	if err := e.authorizeIPPerms(ctx, g, rulesToIPPerms(rules)); err != nil {
+		return errors.Trace(err)
+	}
+	if err := authorizeIPv6Permissions(e.ec2, g.Id, rulesToIPv6Perms(rules)); err != nil {
+		return errors.Annotate(maybeConvertCredentialError(err, ctx), "cannot open ports")
+	}
+	return nil
+}
+
+func (e *environ) authorizeIPPerms(ctx context.ProviderCallContext, g ec2.SecurityGroup, ipPerms []ec2.IPPerm) error {
+	if len(ipPerms) == 0 {
+		return nil
+	}
+	_, err := e.ec2.AuthorizeSecurityGroup(g, ipPerms)
 	if err != nil && ec2ErrCode(err) == "InvalidPermission.Duplicate" {
-		if len(rules) == 1 {
+		if len(ipPerms) == 1 {
 			return nil
 		}
 		// If there's more than one port and we get a duplicate error,
@@ -1568,30 +1610,26 @@ func (e *environ) closePortsInGroup(ctx context.ProviderCallContext, name string
 	if err != nil {
 		return err
 	}
-	_, err = e.ec2.RevokeSecurityGroup(g, rulesToIPPerms(rules))
-	if err != nil {
+	if ipPerms := rulesToIPPerms(rules); len(ipPerms) > 0 {
+		if _, err := e.ec2.RevokeSecurityGroup(g, ipPerms); err != nil {
+			return errors.Annotate(maybeConvertCredentialError(err, ctx), "cannot close ports")
+		}
+	}
+	if err := revokeIPv6Permissions(e.ec2, g.Id, rulesToIPv6Perms(rules)); err != nil {
 		return errors.Annotate(maybeConvertCredentialError(err, ctx), "cannot close ports")
 	}
 	return nil
 }
 
 func (e *environ) ingressRulesInGroup(ctx context.ProviderCallContext, name string) (rules []network.IngressRule, err error) {
-	group, err := e.groupInfoByName(ctx, name)
+	group, err := e.groupByName(ctx, name)
 	if err != nil {
 		return nil, err
 	}
-	for _, p := range group.IPPerms {
-		ips := p.SourceIPs
-		if len(ips) == 0 {
-			ips = []string{defaultRouteCIDRBlock}
-		}
-		rule, err := network.NewIngressRule(p.Protocol, p.FromPort, p.ToPort, ips...)
-		if err != nil {
-			return nil, errors.Trace(err)
-		}
-		rules = append(rules, rule)
+	rules, err = ingressRules(e.ec2, group.Id)
+	if err != nil {
+		return nil, maybeConvertCredentialError(err, ctx)
 	}
-	network.SortIngressRules(rules)
 	return rules, nil
 }
 
@@ -155,6 +155,19 @@ func (*Suite) TestPortsToIPPerms(c *gc.C) {
 			ToPort:    82,
 			SourceIPs: []string{"192.168.1.0/24", "0.0.0.0/0"},
 		}},
+	}, {
+		about: "IPv4 and IPv6 source ranges",
+		rules: []network.IngressRule{network.MustNewIngressRule("tcp", 80, 82, "192.168.1.0/24", "2001:db8::/64")},
+		expected: []amzec2.IPPerm{{
+			Protocol:  "tcp",
+			FromPort:  80,
+			ToPort:    82,
+			SourceIPs: []string{"192.168.1.0/24"},
+		}},
+	}, {
+		about:    "IPv6 source ranges only",
+		rules:    []network.IngressRule{network.MustNewIngressRule("tcp", 80, 82, "::/0")},
+		expected: nil,
 	}}
 
 	for i, t := range testCases {
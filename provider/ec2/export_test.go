@@ -52,6 +52,7 @@ func AllModelGroups(e environs.Environ, ctx context.ProviderCallContext) ([]stri
 var (
 	EC2AvailabilityZones           = &ec2AvailabilityZones
 	RunInstances                   = &runInstances
+	RunSpotInstances               = &runSpotInstances
 	BlockDeviceNamer               = blockDeviceNamer
 	GetBlockDeviceMappings         = getBlockDeviceMappings
 	IsVPCNotUsableError            = isVPCNotUsableError
@@ -68,3 +68,9 @@ func withDefaultNonControllerConstraints(cons constraints.Value) constraints.Val
 	}
 	return cons
 }
+
+// ApplyDefaultInstanceConstraints is part of the
+// environs.DefaultInstanceConstraintsApplier interface.
+func (e *environ) ApplyDefaultInstanceConstraints(cons constraints.Value) constraints.Value {
+	return withDefaultNonControllerConstraints(cons)
+}
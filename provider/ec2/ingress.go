@@ -0,0 +1,157 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/network"
+)
+
+// The EC2 client library only supports IPv4 source ranges, so ingress
+// permissions for IPv6 source ranges are authorized and revoked, and
+// the ingress permissions of security groups read, directly against
+// the EC2 query API.
+
+// ipv6Permission is a single ingress permission of a security group
+// for an IPv6 source range.
+type ipv6Permission struct {
+	Protocol string
+	FromPort int
+	ToPort   int
+	CIDR     string
+}
+
+func (p ipv6Permission) String() string {
+	return fmt.Sprintf("%s:%d-%d:%s", p.Protocol, p.FromPort, p.ToPort, p.CIDR)
+}
+
+// isIPv6CIDR reports whether the given CIDR is an IPv6 address block.
+func isIPv6CIDR(cidr string) bool {
+	ip, _, err := net.ParseCIDR(cidr)
+	return err == nil && ip.To4() == nil
+}
+
+// rulesToIPv6Perms returns the permissions for the IPv6 source ranges
+// of the given rules. Rules without source ranges are only opened to
+// IPv4 traffic, as they always have been.
+func rulesToIPv6Perms(rules []network.IngressRule) []ipv6Permission {
+	var perms []ipv6Permission
+	for _, r := range rules {
+		for _, cidr := range r.SourceCIDRs {
+			if !isIPv6CIDR(cidr) {
+				continue
+			}
+			perms = append(perms, ipv6Permission{
+				Protocol: r.Protocol,
+				FromPort: r.FromPort,
+				ToPort:   r.ToPort,
+				CIDR:     cidr,
+			})
+		}
+	}
+	return perms
+}
+
+// authorizeIPv6Permissions authorizes the given IPv6 ingress
+// permissions of the security group with the given id. Permissions
+// that are already authorized are ignored.
+func authorizeIPv6Permissions(client *ec2.EC2, groupId string, perms []ipv6Permission) error {
+	err := updateIPv6Permissions(client, "AuthorizeSecurityGroupIngress", groupId, perms)
+	if err == nil || ec2ErrCode(err) != "InvalidPermission.Duplicate" {
+		return errors.Trace(err)
+	}
+	// The permissions that were not duplicates have been
+	// ignored, so authorize each permission individually.
+	for i := range perms {
+		err := updateIPv6Permissions(client, "AuthorizeSecurityGroupIngress", groupId, perms[i:i+1])
+		if err != nil && ec2ErrCode(err) != "InvalidPermission.Duplicate" {
+			return errors.Annotatef(err, "permission %v", perms[i])
+		}
+	}
+	return nil
+}
+
+// revokeIPv6Permissions revokes the given IPv6 ingress permissions
+// of the security group with the given id.
+func revokeIPv6Permissions(client *ec2.EC2, groupId string, perms []ipv6Permission) error {
+	return errors.Trace(updateIPv6Permissions(client, "RevokeSecurityGroupIngress", groupId, perms))
+}
+
+func updateIPv6Permissions(client *ec2.EC2, action, groupId string, perms []ipv6Permission) error {
+	if len(perms) == 0 {
+		return nil
+	}
+	params := url.Values{
+		"Action":  {action},
+		"Version": {spotAPIVersion},
+		"GroupId": {groupId},
+	}
+	for i, p := range perms {
+		prefix := fmt.Sprintf("IpPermissions.%d.", i+1)
+		params.Set(prefix+"IpProtocol", p.Protocol)
+		params.Set(prefix+"FromPort", fmt.Sprint(p.FromPort))
+		params.Set(prefix+"ToPort", fmt.Sprint(p.ToPort))
+		params.Set(prefix+"Ipv6Ranges.1.CidrIpv6", p.CIDR)
+	}
+	var resp struct {
+		RequestId string `xml:"requestId"`
+		Return    bool   `xml:"return"`
+	}
+	if err := ec2Query(client, params, &resp); err != nil {
+		return errors.Annotatef(err, "%s", action)
+	}
+	if !resp.Return {
+		return errors.Errorf("%s request %s failed", action, resp.RequestId)
+	}
+	return nil
+}
+
+// ingressRules returns the ingress rules of the security group with
+// the given id, with both their IPv4 and IPv6 source ranges. Permissions
+// without any source ranges, such as those for other security groups,
+// are reported as open to all IPv4 traffic.
+func ingressRules(client *ec2.EC2, groupId string) ([]network.IngressRule, error) {
+	params := url.Values{
+		"Action":    {"DescribeSecurityGroups"},
+		"Version":   {spotAPIVersion},
+		"GroupId.1": {groupId},
+	}
+	var resp struct {
+		Groups []struct {
+			Ingress []struct {
+				Protocol  string   `xml:"ipProtocol"`
+				FromPort  int      `xml:"fromPort"`
+				ToPort    int      `xml:"toPort"`
+				CIDRs     []string `xml:"ipRanges>item>cidrIp"`
+				IPv6CIDRs []string `xml:"ipv6Ranges>item>cidrIpv6"`
+			} `xml:"ipPermissions>item"`
+		} `xml:"securityGroupInfo>item"`
+	}
+	if err := ec2Query(client, params, &resp); err != nil {
+		return nil, errors.Annotate(err, "describing security group")
+	}
+	if len(resp.Groups) != 1 {
+		return nil, errors.NotFoundf("security group %q", groupId)
+	}
+	var rules []network.IngressRule
+	for _, p := range resp.Groups[0].Ingress {
+		cidrs := append(p.CIDRs, p.IPv6CIDRs...)
+		if len(cidrs) == 0 {
+			cidrs = []string{defaultRouteCIDRBlock}
+		}
+		rule, err := network.NewIngressRule(p.Protocol, p.FromPort, p.ToPort, cidrs...)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		rules = append(rules, rule)
+	}
+	network.SortIngressRules(rules)
+	return rules, nil
+}
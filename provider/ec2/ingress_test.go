@@ -0,0 +1,134 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type ingressSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&ingressSuite{})
+
+func (s *ingressSuite) TestRulesToIPv6Perms(c *gc.C) {
+	perms := rulesToIPv6Perms([]network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80),
+		network.MustNewIngressRule("tcp", 443, 443, "0.0.0.0/0", "::/0"),
+		network.MustNewIngressRule("udp", 53, 53, "2001:db8::/64"),
+	})
+	c.Assert(perms, jc.DeepEquals, []ipv6Permission{
+		{Protocol: "tcp", FromPort: 443, ToPort: 443, CIDR: "::/0"},
+		{Protocol: "udp", FromPort: 53, ToPort: 53, CIDR: "2001:db8::/64"},
+	})
+}
+
+func (s *ingressSuite) TestAuthorizeIPv6Permissions(c *gc.C) {
+	var requests []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		query.Del("Version")
+		requests = append(requests, query)
+		fmt.Fprint(w, `<Response><requestId>req-1</requestId><return>true</return></Response>`)
+	}))
+	defer srv.Close()
+
+	err := authorizeIPv6Permissions(newSpotTestClient(srv.URL), "sg-1", []ipv6Permission{
+		{Protocol: "tcp", FromPort: 443, ToPort: 443, CIDR: "::/0"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(requests, jc.DeepEquals, []url.Values{{
+		"Action":                                {"AuthorizeSecurityGroupIngress"},
+		"GroupId":                               {"sg-1"},
+		"IpPermissions.1.IpProtocol":            {"tcp"},
+		"IpPermissions.1.FromPort":              {"443"},
+		"IpPermissions.1.ToPort":                {"443"},
+		"IpPermissions.1.Ipv6Ranges.1.CidrIpv6": {"::/0"},
+	}})
+}
+
+func (s *ingressSuite) TestAuthorizeIPv6PermissionsDuplicate(c *gc.C) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		query := r.URL.Query()
+		if query.Get("IpPermissions.2.IpProtocol") != "" || query.Get("IpPermissions.1.FromPort") == "80" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `<Response><Errors><Error><Code>InvalidPermission.Duplicate</Code><Message>duplicate</Message></Error></Errors><RequestID>req-1</RequestID></Response>`)
+			return
+		}
+		fmt.Fprint(w, `<Response><requestId>req-2</requestId><return>true</return></Response>`)
+	}))
+	defer srv.Close()
+
+	// The permission that is not a duplicate is
+	// authorized on its own.
+	err := authorizeIPv6Permissions(newSpotTestClient(srv.URL), "sg-1", []ipv6Permission{
+		{Protocol: "tcp", FromPort: 80, ToPort: 80, CIDR: "::/0"},
+		{Protocol: "tcp", FromPort: 443, ToPort: 443, CIDR: "::/0"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(requests, gc.Equals, 3)
+}
+
+func (s *ingressSuite) TestRevokeIPv6Permissions(c *gc.C) {
+	var requests []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		query.Del("Version")
+		requests = append(requests, query)
+		fmt.Fprint(w, `<Response><requestId>req-1</requestId><return>true</return></Response>`)
+	}))
+	defer srv.Close()
+	client := newSpotTestClient(srv.URL)
+
+	err := revokeIPv6Permissions(client, "sg-1", []ipv6Permission{
+		{Protocol: "udp", FromPort: 53, ToPort: 53, CIDR: "2001:db8::/64"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = revokeIPv6Permissions(client, "sg-1", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(requests, jc.DeepEquals, []url.Values{{
+		"Action":                                {"RevokeSecurityGroupIngress"},
+		"GroupId":                               {"sg-1"},
+		"IpPermissions.1.IpProtocol":            {"udp"},
+		"IpPermissions.1.FromPort":              {"53"},
+		"IpPermissions.1.ToPort":                {"53"},
+		"IpPermissions.1.Ipv6Ranges.1.CidrIpv6": {"2001:db8::/64"},
+	}})
+}
+
+func (s *ingressSuite) TestIngressRules(c *gc.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.URL.Query().Get("Action"), gc.Equals, "DescribeSecurityGroups")
+		c.Check(r.URL.Query().Get("GroupId.1"), gc.Equals, "sg-1")
+		fmt.Fprint(w, `<DescribeSecurityGroupsResponse><securityGroupInfo><item>
+<groupId>sg-1</groupId>
+<ipPermissions>
+<item><ipProtocol>tcp</ipProtocol><fromPort>22</fromPort><toPort>22</toPort><groups><item><groupId>sg-2</groupId></item></groups></item>
+<item><ipProtocol>tcp</ipProtocol><fromPort>80</fromPort><toPort>80</toPort><ipv6Ranges><item><cidrIpv6>::/0</cidrIpv6></item></ipv6Ranges></item>
+<item><ipProtocol>tcp</ipProtocol><fromPort>443</fromPort><toPort>443</toPort><ipRanges><item><cidrIp>0.0.0.0/0</cidrIp></item></ipRanges><ipv6Ranges><item><cidrIpv6>2001:db8::/64</cidrIpv6></item></ipv6Ranges></item>
+</ipPermissions>
+</item></securityGroupInfo></DescribeSecurityGroupsResponse>`)
+	}))
+	defer srv.Close()
+
+	rules, err := ingressRules(newSpotTestClient(srv.URL), "sg-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []network.IngressRule{
+		network.MustNewIngressRule("tcp", 22, 22, "0.0.0.0/0"),
+		network.MustNewIngressRule("tcp", 80, 80, "::/0"),
+		network.MustNewIngressRule("tcp", 443, 443, "0.0.0.0/0", "2001:db8::/64"),
+	})
+}
@@ -21,13 +21,24 @@ type ec2Instance struct {
 	e *environ
 
 	*ec2.Instance
+
+	// stateReason caches the code of the reason for the instance's
+	// last state change, once it has been looked up.
+	stateReason *string
+
+	// spotRequestStatus caches the status of the instance's spot
+	// request, once it has been looked up.
+	spotRequestStatus *spotRequestStatus
 }
 
 func (inst *ec2Instance) String() string {
 	return string(inst.Id())
 }
 
-var _ instances.Instance = (*ec2Instance)(nil)
+var (
+	_ instances.Instance              = (*ec2Instance)(nil)
+	_ instances.InterruptibleInstance = (*ec2Instance)(nil)
+)
 
 func (inst *ec2Instance) Id() instance.Id {
 	return instance.Id(inst.InstanceId)
@@ -46,9 +57,13 @@ func (inst *ec2Instance) Status(ctx context.ProviderCallContext) instance.Status
 	default:
 		jujuStatus = status.Empty
 	}
+	message := inst.State.Name
+	if inst.Interrupted(ctx) {
+		message = fmt.Sprintf("spot instance interrupted (%s)", inst.State.Name)
+	}
 	return instance.Status{
 		Status:  jujuStatus,
-		Message: inst.State.Name,
+		Message: message,
 	}
 
 }
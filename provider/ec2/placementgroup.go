@@ -0,0 +1,85 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"net/url"
+
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/environs/context"
+)
+
+// placementGroupName returns the name of the EC2 placement group
+// realising the given anti-affinity group in a model.
+func placementGroupName(modelUUID, group string) string {
+	return "juju-" + modelUUID + "-" + group
+}
+
+// ensurePlacementGroup creates a placement group with the spread
+// strategy, which places each of its instances on distinct hardware,
+// for the given anti-affinity group. The group's name is returned.
+//
+// EC2 allows a spread placement group at most seven running instances
+// in each availability zone, so an anti-affinity group can hold no more
+// than seven machines per zone; starting any more in a zone fails.
+func (e *environ) ensurePlacementGroup(ctx context.ProviderCallContext, group string) (string, error) {
+	name := placementGroupName(e.uuid(), group)
+	if err := createPlacementGroup(e.ec2, name); err != nil {
+		return "", errors.Annotatef(maybeConvertCredentialError(err, ctx), "creating placement group %q", name)
+	}
+	return name, nil
+}
+
+// createPlacementGroup creates a spread placement group with the given
+// name, if one does not already exist. The EC2 client library does not
+// support placement groups, so the request is made directly against the
+// EC2 query API.
+func createPlacementGroup(e *ec2.EC2, name string) error {
+	params := url.Values{
+		"Action":    {"CreatePlacementGroup"},
+		"Version":   {spotAPIVersion},
+		"GroupName": {name},
+		"Strategy":  {"spread"},
+	}
+	var resp ec2.SimpleResp
+	err := ec2Query(e, params, &resp)
+	if ec2ErrCode(err) == "InvalidPlacementGroup.Duplicate" {
+		return nil
+	}
+	return err
+}
+
+// deletePlacementGroups deletes the model's placement groups. They can
+// only be deleted once all of their instances have terminated, so failures
+// are logged rather than returned.
+func (e *environ) deletePlacementGroups(ctx context.ProviderCallContext) {
+	params := url.Values{
+		"Action":           {"DescribePlacementGroups"},
+		"Version":          {spotAPIVersion},
+		"Filter.1.Name":    {"group-name"},
+		"Filter.1.Value.1": {placementGroupName(e.uuid(), "*")},
+	}
+	var resp struct {
+		Groups []struct {
+			Name string `xml:"groupName"`
+		} `xml:"placementGroupSet>item"`
+	}
+	if err := ec2Query(e.ec2, params, &resp); err != nil {
+		logger.Warningf("cannot list placement groups: %v", maybeConvertCredentialError(err, ctx))
+		return
+	}
+	for _, group := range resp.Groups {
+		params := url.Values{
+			"Action":    {"DeletePlacementGroup"},
+			"Version":   {spotAPIVersion},
+			"GroupName": {group.Name},
+		}
+		var deleteResp ec2.SimpleResp
+		if err := ec2Query(e.ec2, params, &deleteResp); err != nil {
+			logger.Warningf("cannot delete placement group %q: %v", group.Name, maybeConvertCredentialError(err, ctx))
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	jc "github.com/juju/testing/checkers"
+	amzec2 "gopkg.in/amz.v3/ec2"
+	gc "gopkg.in/check.v1"
+)
+
+type placementGroupSuite struct{}
+
+var _ = gc.Suite(&placementGroupSuite{})
+
+func (*placementGroupSuite) TestPlacementGroupName(c *gc.C) {
+	name := placementGroupName("deadbeef-0bad-400d-8000-4b1d0d06f00d", "postgresql")
+	c.Assert(name, gc.Equals, "juju-deadbeef-0bad-400d-8000-4b1d0d06f00d-postgresql")
+}
+
+func (*placementGroupSuite) TestCreatePlacementGroup(c *gc.C) {
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprint(w, `<CreatePlacementGroupResponse><requestId>req-1</requestId><return>true</return></CreatePlacementGroupResponse>`)
+	}))
+	defer srv.Close()
+
+	err := createPlacementGroup(newSpotTestClient(srv.URL), "juju-model-postgresql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(query.Get("Action"), gc.Equals, "CreatePlacementGroup")
+	c.Assert(query.Get("GroupName"), gc.Equals, "juju-model-postgresql")
+	c.Assert(query.Get("Strategy"), gc.Equals, "spread")
+}
+
+func (*placementGroupSuite) TestCreatePlacementGroupExists(c *gc.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `<Response><Errors><Error><Code>InvalidPlacementGroup.Duplicate</Code><Message>already exists</Message></Error></Errors><RequestID>req-2</RequestID></Response>`)
+	}))
+	defer srv.Close()
+
+	err := createPlacementGroup(newSpotTestClient(srv.URL), "juju-model-postgresql")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (*placementGroupSuite) TestSpotRunInstancesParamsPlacementGroup(c *gc.C) {
+	params := spotRunInstancesParams(&amzec2.RunInstances{
+		MinCount:           1,
+		MaxCount:           1,
+		PlacementGroupName: "juju-model-postgresql",
+	}, "")
+	c.Assert(params.Get("Placement.GroupName"), gc.Equals, "juju-model-postgresql")
+}
@@ -0,0 +1,54 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+)
+
+var _ environs.InstanceProtector = (*environ)(nil)
+
+// SetInstanceProtection is part of the environs.InstanceProtector interface.
+// Protection is applied by setting the DisableApiTermination attribute of
+// each instance, which causes EC2 to refuse TerminateInstances requests.
+func (e *environ) SetInstanceProtection(ctx context.ProviderCallContext, ids []instance.Id, protected bool) error {
+	for _, id := range ids {
+		if err := modifyDisableAPITermination(e.ec2, id, protected); err != nil {
+			return errors.Annotatef(maybeConvertCredentialError(err, ctx), "instance %q", id)
+		}
+	}
+	return nil
+}
+
+// modifyDisableAPITermination sets the DisableApiTermination attribute of
+// the given instance. The EC2 client library does not support modifying
+// instance attributes, so the request is made directly against the EC2
+// query API.
+func modifyDisableAPITermination(e *ec2.EC2, id instance.Id, disable bool) error {
+	params := url.Values{
+		"Action":                      {"ModifyInstanceAttribute"},
+		"Version":                     {spotAPIVersion},
+		"InstanceId":                  {string(id)},
+		"DisableApiTermination.Value": {strconv.FormatBool(disable)},
+	}
+	var resp struct {
+		RequestId string `xml:"requestId"`
+		Return    bool   `xml:"return"`
+	}
+	if err := ec2Query(e, params, &resp); err != nil {
+		return err
+	}
+	if !resp.Return {
+		return errors.Errorf("ModifyInstanceAttribute request %s failed", resp.RequestId)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs/context"
+)
+
+type protectionSuite struct{}
+
+var _ = gc.Suite(&protectionSuite{})
+
+func (*protectionSuite) TestSetInstanceProtection(c *gc.C) {
+	var queries []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		fmt.Fprint(w, `<ModifyInstanceAttributeResponse><requestId>req-1</requestId><return>true</return></ModifyInstanceAttributeResponse>`)
+	}))
+	defer srv.Close()
+
+	env := &environ{ec2: newSpotTestClient(srv.URL)}
+	err := env.SetInstanceProtection(context.NewCloudCallContext(), []instance.Id{"i-0", "i-1"}, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(queries, gc.HasLen, 2)
+	for i, id := range []string{"i-0", "i-1"} {
+		c.Check(queries[i].Get("Action"), gc.Equals, "ModifyInstanceAttribute")
+		c.Check(queries[i].Get("InstanceId"), gc.Equals, id)
+		c.Check(queries[i].Get("DisableApiTermination.Value"), gc.Equals, "true")
+	}
+}
+
+func (*protectionSuite) TestSetInstanceProtectionError(c *gc.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `<Response><Errors><Error><Code>InvalidInstanceID.NotFound</Code><Message>no such instance</Message></Error></Errors><RequestID>req-2</RequestID></Response>`)
+	}))
+	defer srv.Close()
+
+	env := &environ{ec2: newSpotTestClient(srv.URL)}
+	err := env.SetInstanceProtection(context.NewCloudCallContext(), []instance.Id{"i-0"}, false)
+	c.Assert(err, gc.ErrorMatches, `instance "i-0": no such instance \(InvalidInstanceID.NotFound\)`)
+}
@@ -0,0 +1,195 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/instances"
+	"github.com/juju/juju/environs/tags"
+)
+
+var _ environs.InstanceResizer = (*environ)(nil)
+
+// resizeAttempt is used to poll for an instance to stop before its
+// instance type is changed.
+var resizeAttempt = utils.AttemptStrategy{
+	Total: 10 * time.Minute,
+	Delay: 5 * time.Second,
+}
+
+// ResizeInstance is part of the environs.InstanceResizer interface.
+// The instance is changed to the cheapest instance type satisfying the
+// constraints, with the same defaults applied as when the instance was
+// started. EC2 only changes the instance type of a stopped instance,
+// so a running instance is stopped, changed and started again.
+func (e *environ) ResizeInstance(ctx context.ProviderCallContext, id instance.Id, cons constraints.Value) (*instance.HardwareCharacteristics, error) {
+	inst, err := describeInstanceState(e.ec2, id)
+	if err != nil {
+		return nil, maybeConvertCredentialError(err, ctx)
+	}
+	if !inst.isController() {
+		cons = withDefaultNonControllerConstraints(cons)
+	}
+	instanceTypes, err := e.supportedInstanceTypes(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	matching, err := instances.MatchingInstanceTypes(instanceTypes, e.cloud.Region, cons)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	itype := matching[0]
+	if err := changeInstanceType(e.ec2, id, itype.Name); err != nil {
+		return nil, maybeConvertCredentialError(err, ctx)
+	}
+	return &instance.HardwareCharacteristics{
+		Mem:      &itype.Mem,
+		CpuCores: &itype.CpuCores,
+		CpuPower: itype.CpuPower,
+	}, nil
+}
+
+// ec2InstanceState holds the attributes of an instance
+// relevant to changing its instance type.
+type ec2InstanceState struct {
+	InstanceType string `xml:"instanceType"`
+	State        string `xml:"instanceState>name"`
+	Tags         []struct {
+		Key   string `xml:"key"`
+		Value string `xml:"value"`
+	} `xml:"tagSet>item"`
+}
+
+// isController reports whether the instance is tagged as a controller.
+func (inst ec2InstanceState) isController() bool {
+	for _, tag := range inst.Tags {
+		if tag.Key == tags.JujuIsController {
+			return tag.Value == "true"
+		}
+	}
+	return false
+}
+
+// changeInstanceType changes the instance type of the given instance,
+// stopping it first if necessary. An instance which was running is
+// started again afterwards.
+//
+// The EC2 client library does not support stopping and starting instances
+// or modifying instance attributes, so the requests are made directly
+// against the EC2 query API.
+func changeInstanceType(client *ec2.EC2, id instance.Id, instanceType string) error {
+	inst, err := describeInstanceState(client, id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if inst.InstanceType == instanceType {
+		logger.Debugf("instance %q already has instance type %q", id, instanceType)
+		return nil
+	}
+
+	wasRunning := inst.State != "stopped"
+	if wasRunning {
+		logger.Infof("stopping instance %q to change its instance type to %q", id, instanceType)
+		if err := instanceAction(client, "StopInstances", id); err != nil {
+			return errors.Annotate(err, "stopping instance")
+		}
+		if err := waitInstanceStopped(client, id); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	params := url.Values{
+		"Action":             {"ModifyInstanceAttribute"},
+		"Version":            {spotAPIVersion},
+		"InstanceId":         {string(id)},
+		"InstanceType.Value": {instanceType},
+	}
+	var resp struct {
+		RequestId string `xml:"requestId"`
+		Return    bool   `xml:"return"`
+	}
+	err = ec2Query(client, params, &resp)
+	if err == nil && !resp.Return {
+		err = errors.Errorf("ModifyInstanceAttribute request %s failed", resp.RequestId)
+	}
+	if err != nil {
+		err = errors.Annotatef(err, "changing instance type to %q", instanceType)
+	}
+
+	// The instance is started again even if its type could not be
+	// changed, so that it is not left stopped.
+	if wasRunning {
+		if startErr := instanceAction(client, "StartInstances", id); startErr != nil {
+			if err != nil {
+				logger.Errorf("cannot start instance %q: %v", id, startErr)
+				return err
+			}
+			return errors.Annotate(startErr, "starting instance")
+		}
+	}
+	return err
+}
+
+// describeInstanceState returns the instance type and state of the
+// given instance.
+func describeInstanceState(client *ec2.EC2, id instance.Id) (ec2InstanceState, error) {
+	params := url.Values{
+		"Action":       {"DescribeInstances"},
+		"Version":      {spotAPIVersion},
+		"InstanceId.1": {string(id)},
+	}
+	var resp struct {
+		Reservations []struct {
+			Instances []ec2InstanceState `xml:"instancesSet>item"`
+		} `xml:"reservationSet>item"`
+	}
+	if err := ec2Query(client, params, &resp); err != nil {
+		return ec2InstanceState{}, errors.Trace(err)
+	}
+	for _, r := range resp.Reservations {
+		if len(r.Instances) > 0 {
+			return r.Instances[0], nil
+		}
+	}
+	return ec2InstanceState{}, errors.NotFoundf("instance %q", id)
+}
+
+// waitInstanceStopped waits for the given instance to stop.
+func waitInstanceStopped(client *ec2.EC2, id instance.Id) error {
+	var state string
+	for a := resizeAttempt.Start(); a.Next(); {
+		inst, err := describeInstanceState(client, id)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if state = inst.State; state == "stopped" {
+			return nil
+		}
+	}
+	return errors.Errorf("timed out waiting for instance %q to stop, last state %q", id, state)
+}
+
+// instanceAction sends a StopInstances or StartInstances
+// request for the given instance.
+func instanceAction(client *ec2.EC2, action string, id instance.Id) error {
+	params := url.Values{
+		"Action":       {action},
+		"Version":      {spotAPIVersion},
+		"InstanceId.1": {string(id)},
+	}
+	var resp struct {
+		RequestId string `xml:"requestId"`
+	}
+	return errors.Trace(ec2Query(client, params, &resp))
+}
@@ -0,0 +1,136 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+)
+
+type resizeSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&resizeSuite{})
+
+func (s *resizeSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.PatchValue(&resizeAttempt, utils.AttemptStrategy{Min: 5})
+}
+
+const describeInstanceStateResponse = `
+<DescribeInstancesResponse>
+  <reservationSet><item><instancesSet><item>
+    <instanceId>i-0</instanceId>
+    <instanceType>%s</instanceType>
+    <instanceState><name>%s</name></instanceState>
+  </item></instancesSet></item></reservationSet>
+</DescribeInstancesResponse>`
+
+// fakeResizeServer responds to the EC2 queries made when resizing an
+// instance, describing the instance with each of its states in turn.
+func fakeResizeServer(c *gc.C, instanceType string, states ...string) (*httptest.Server, *[]string) {
+	var actions []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		action := query.Get("Action")
+		actions = append(actions, action)
+		switch action {
+		case "DescribeInstances":
+			c.Check(query.Get("InstanceId.1"), gc.Equals, "i-0")
+			state := states[0]
+			if len(states) > 1 {
+				states = states[1:]
+			}
+			fmt.Fprintf(w, describeInstanceStateResponse, instanceType, state)
+		case "ModifyInstanceAttribute":
+			c.Check(query.Get("InstanceId"), gc.Equals, "i-0")
+			c.Check(query.Get("InstanceType.Value"), gc.Equals, "m5.xlarge")
+			fmt.Fprint(w, `<ModifyInstanceAttributeResponse><requestId>req-1</requestId><return>true</return></ModifyInstanceAttributeResponse>`)
+		default:
+			c.Check(query.Get("InstanceId.1"), gc.Equals, "i-0")
+			fmt.Fprintf(w, `<%sResponse><requestId>req-2</requestId></%sResponse>`, action, action)
+		}
+	}))
+	return srv, &actions
+}
+
+func (s *resizeSuite) TestChangeInstanceTypeRunning(c *gc.C) {
+	srv, actions := fakeResizeServer(c, "m5.large", "running", "stopping", "stopped")
+	defer srv.Close()
+
+	err := changeInstanceType(newSpotTestClient(srv.URL), "i-0", "m5.xlarge")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*actions, jc.DeepEquals, []string{
+		"DescribeInstances",
+		"StopInstances",
+		"DescribeInstances",
+		"DescribeInstances",
+		"ModifyInstanceAttribute",
+		"StartInstances",
+	})
+}
+
+func (s *resizeSuite) TestChangeInstanceTypeStopped(c *gc.C) {
+	srv, actions := fakeResizeServer(c, "m5.large", "stopped")
+	defer srv.Close()
+
+	err := changeInstanceType(newSpotTestClient(srv.URL), "i-0", "m5.xlarge")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*actions, jc.DeepEquals, []string{"DescribeInstances", "ModifyInstanceAttribute"})
+}
+
+func (s *resizeSuite) TestChangeInstanceTypeUnchanged(c *gc.C) {
+	srv, actions := fakeResizeServer(c, "m5.xlarge", "running")
+	defer srv.Close()
+
+	err := changeInstanceType(newSpotTestClient(srv.URL), "i-0", "m5.xlarge")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*actions, jc.DeepEquals, []string{"DescribeInstances"})
+}
+
+func (s *resizeSuite) TestChangeInstanceTypeTimeout(c *gc.C) {
+	srv, actions := fakeResizeServer(c, "m5.large", "running", "stopping")
+	defer srv.Close()
+
+	err := changeInstanceType(newSpotTestClient(srv.URL), "i-0", "m5.xlarge")
+	c.Assert(err, gc.ErrorMatches, `timed out waiting for instance "i-0" to stop, last state "stopping"`)
+	c.Assert((*actions)[:2], jc.DeepEquals, []string{"DescribeInstances", "StopInstances"})
+}
+
+func (s *resizeSuite) TestDescribeInstanceStateController(c *gc.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `
+<DescribeInstancesResponse>
+  <reservationSet><item><instancesSet><item>
+    <instanceId>i-0</instanceId>
+    <instanceType>m5.large</instanceType>
+    <instanceState><name>running</name></instanceState>
+    <tagSet><item><key>juju-is-controller</key><value>true</value></item></tagSet>
+  </item></instancesSet></item></reservationSet>
+</DescribeInstancesResponse>`)
+	}))
+	defer srv.Close()
+
+	inst, err := describeInstanceState(newSpotTestClient(srv.URL), "i-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(inst.InstanceType, gc.Equals, "m5.large")
+	c.Assert(inst.isController(), jc.IsTrue)
+}
+
+func (s *resizeSuite) TestDescribeInstanceStateNotController(c *gc.C) {
+	srv, _ := fakeResizeServer(c, "m5.large", "running")
+	defer srv.Close()
+
+	inst, err := describeInstanceState(newSpotTestClient(srv.URL), "i-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(inst.isController(), jc.IsFalse)
+}
@@ -0,0 +1,340 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+)
+
+const (
+	// spotAPIVersion is the first version of the EC2 API which
+	// accepts instance market options when running instances.
+	spotAPIVersion = "2016-11-15"
+
+	// tagSpotInstance is the tag applied to instances which Juju has
+	// started as spot instances, so that interruptions can be detected.
+	tagSpotInstance = "juju-spot-instance"
+)
+
+// spotHTTPClient is the client used to send spot instance requests.
+var spotHTTPClient = http.DefaultClient
+
+var runSpotInstances = _runSpotInstances
+
+// runSpotInstances starts one-time spot instances with the given maximum
+// hourly price, retrying in the same way as runInstances. An empty price
+// defaults to the on-demand price of the instance type.
+//
+// The EC2 client library predates instance market options, so the request
+// is made directly against the EC2 query API.
+func _runSpotInstances(e *ec2.EC2, ctx context.ProviderCallContext, ri *ec2.RunInstances, maxPrice string, c environs.StatusCallbackFunc) (*ec2.RunInstancesResp, error) {
+	params, err := spotRunInstancesParams(ri, maxPrice)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	try := 1
+	for a := shortAttempt.Start(); a.Next(); {
+		c(status.Allocating, fmt.Sprintf("Start spot instance attempt %d", try), nil)
+		var resp ec2.RunInstancesResp
+		if err = ec2Query(e, params, &resp); err == nil {
+			return &resp, nil
+		}
+		if !isNotFoundError(err) {
+			break
+		}
+		try++
+	}
+	return nil, maybeConvertCredentialError(err, ctx)
+}
+
+// spotRunInstancesParams returns the query parameters for a RunInstances
+// request which starts the given instances on the spot market.
+func spotRunInstancesParams(ri *ec2.RunInstances, maxPrice string) (url.Values, error) {
+	params, err := runInstancesParams(ri)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	params.Set("Version", spotAPIVersion)
+	params.Set("InstanceMarketOptions.MarketType", "spot")
+	params.Set("InstanceMarketOptions.SpotOptions.SpotInstanceType", "one-time")
+	params.Set("InstanceMarketOptions.SpotOptions.InstanceInterruptionBehavior", "terminate")
+	if maxPrice != "" {
+		params.Set("InstanceMarketOptions.SpotOptions.MaxPrice", maxPrice)
+	}
+	return params, nil
+}
+
+// runInstancesParams returns the query parameters for a RunInstances
+// request, encoded in the same way as by the EC2 client library, so that
+// a spot instance is started exactly as an on-demand one would be.
+// Explicit network interfaces are never requested by Juju, and are not
+// supported.
+func runInstancesParams(ri *ec2.RunInstances) (url.Values, error) {
+	if len(ri.NetworkInterfaces) > 0 {
+		return nil, errors.NotSupportedf("network interfaces for spot instances")
+	}
+	params := url.Values{
+		"Action":   {"RunInstances"},
+		"ImageId":  {ri.ImageId},
+		"MinCount": {strconv.Itoa(ri.MinCount)},
+		"MaxCount": {strconv.Itoa(ri.MaxCount)},
+	}
+	setNonEmpty := func(key, value string) {
+		if value != "" {
+			params.Set(key, value)
+		}
+	}
+	setNonEmpty("InstanceType", ri.InstanceType)
+	setNonEmpty("KeyName", ri.KeyName)
+	setNonEmpty("KernelId", ri.KernelId)
+	setNonEmpty("RamdiskId", ri.RamdiskId)
+	setNonEmpty("IamInstanceProfile.Name", ri.IAMInstanceProfile)
+	setNonEmpty("Placement.AvailabilityZone", ri.AvailZone)
+	setNonEmpty("Placement.GroupName", ri.PlacementGroupName)
+	setNonEmpty("SubnetId", ri.SubnetId)
+	setNonEmpty("PrivateIpAddress", ri.PrivateIPAddress)
+	setNonEmpty("InstanceInitiatedShutdownBehavior", ri.ShutdownBehavior)
+	if len(ri.UserData) > 0 {
+		params.Set("UserData", base64.StdEncoding.EncodeToString(ri.UserData))
+	}
+	if ri.Monitoring {
+		params.Set("Monitoring.Enabled", "true")
+	}
+	if ri.DisableAPITermination {
+		params.Set("DisableApiTermination", "true")
+	}
+	ids, names := 1, 1
+	for _, g := range ri.SecurityGroups {
+		if g.Id != "" {
+			params.Set("SecurityGroupId."+strconv.Itoa(ids), g.Id)
+			ids++
+		} else {
+			params.Set("SecurityGroup."+strconv.Itoa(names), g.Name)
+			names++
+		}
+	}
+	for i, b := range ri.BlockDeviceMappings {
+		prefix := "BlockDeviceMapping." + strconv.Itoa(i+1) + "."
+		setNonEmpty(prefix+"DeviceName", b.DeviceName)
+		setNonEmpty(prefix+"VirtualName", b.VirtualName)
+		setNonEmpty(prefix+"Ebs.SnapshotId", b.SnapshotId)
+		setNonEmpty(prefix+"Ebs.VolumeType", b.VolumeType)
+		if b.VolumeSize != 0 {
+			params.Set(prefix+"Ebs.VolumeSize", strconv.FormatInt(b.VolumeSize, 10))
+		}
+		if b.IOPS != 0 {
+			params.Set(prefix+"Ebs.Iops", strconv.FormatInt(b.IOPS, 10))
+		}
+		if b.DeleteOnTermination {
+			params.Set(prefix+"Ebs.DeleteOnTermination", "true")
+		}
+	}
+	return params, nil
+}
+
+// ec2Query sends a signed request to the EC2 query API, decoding the
+// response into resp. Failures are returned as *ec2.Error so that they
+// are handled in the same way as those from the EC2 client library.
+func ec2Query(e *ec2.EC2, params url.Values, resp interface{}) error {
+	endpoint, err := url.Parse(e.Region.EC2Endpoint)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if endpoint.Path == "" {
+		endpoint.Path = "/"
+	}
+	endpoint.RawQuery = params.Encode()
+	req, err := http.NewRequest("GET", endpoint.String(), nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := e.Sign(req, e.Auth); err != nil {
+		return errors.Annotate(err, "signing request")
+	}
+	r, err := spotHTTPClient.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		var xmlErrors struct {
+			RequestId string `xml:"RequestID"`
+			Errors    []struct {
+				Code    string `xml:"Code"`
+				Message string `xml:"Message"`
+			} `xml:"Errors>Error"`
+		}
+		ec2err := &ec2.Error{StatusCode: r.StatusCode}
+		if err := xml.NewDecoder(r.Body).Decode(&xmlErrors); err == nil && len(xmlErrors.Errors) > 0 {
+			ec2err.Code = xmlErrors.Errors[0].Code
+			ec2err.Message = xmlErrors.Errors[0].Message
+			ec2err.RequestId = xmlErrors.RequestId
+		} else {
+			ec2err.Message = r.Status
+		}
+		return ec2err
+	}
+	return errors.Trace(xml.NewDecoder(r.Body).Decode(resp))
+}
+
+// isSpot returns whether Juju started the instance as a spot instance.
+func (inst *ec2Instance) isSpot() bool {
+	for _, tag := range inst.Tags {
+		if tag.Key == tagSpotInstance {
+			return tag.Value == "true"
+		}
+	}
+	return false
+}
+
+// spotInterruptionCodes are the state reason codes with which EC2 stops
+// or terminates a spot instance that it has reclaimed.
+var spotInterruptionCodes = set.NewStrings(
+	"Server.SpotInstanceTermination",
+	"Server.SpotInstanceShutdown",
+)
+
+// Interrupted is part of the instances.InterruptibleInstance interface.
+// A spot instance has been interrupted only if EC2 gives the reclaiming
+// of the instance as the reason it is no longer running; spot instances
+// stopped by a user or terminated by Juju are not interrupted.
+func (inst *ec2Instance) Interrupted(ctx context.ProviderCallContext) bool {
+	if !inst.isSpot() {
+		return false
+	}
+	switch inst.State.Name {
+	case "shutting-down", "terminated", "stopping", "stopped":
+	default:
+		return false
+	}
+	code, err := inst.stateReasonCode(ctx)
+	if err != nil {
+		logger.Warningf("cannot determine why spot instance %q is %s: %v", inst.InstanceId, inst.State.Name, err)
+		return false
+	}
+	return spotInterruptionCodes.Contains(code)
+}
+
+// stateReasonCode returns the code of the reason for the instance's last
+// state change. The EC2 client library does not decode state reasons, so
+// the instance is described directly against the EC2 query API, once.
+func (inst *ec2Instance) stateReasonCode(ctx context.ProviderCallContext) (string, error) {
+	if inst.stateReason != nil {
+		return *inst.stateReason, nil
+	}
+	params := url.Values{
+		"Action":       {"DescribeInstances"},
+		"Version":      {spotAPIVersion},
+		"InstanceId.1": {inst.InstanceId},
+	}
+	var resp struct {
+		Reservations []struct {
+			Instances []struct {
+				InstanceId  string `xml:"instanceId"`
+				StateReason struct {
+					Code string `xml:"code"`
+				} `xml:"stateReason"`
+			} `xml:"instancesSet>item"`
+		} `xml:"reservationSet>item"`
+	}
+	if err := ec2Query(inst.e.ec2, params, &resp); err != nil {
+		return "", maybeConvertCredentialError(err, ctx)
+	}
+	for _, r := range resp.Reservations {
+		for _, i := range r.Instances {
+			if i.InstanceId == inst.InstanceId {
+				code := i.StateReason.Code
+				inst.stateReason = &code
+				return code, nil
+			}
+		}
+	}
+	return "", errors.NotFoundf("instance %q", inst.InstanceId)
+}
+
+// spotInterruptionNoticeCodes are the spot request status codes with
+// which EC2 gives notice that it is about to stop or terminate a spot
+// instance, or that it has begun to do so.
+var spotInterruptionNoticeCodes = set.NewStrings(
+	"marked-for-stop",
+	"marked-for-termination",
+	"instance-stopped-by-price",
+	"instance-stopped-no-capacity",
+	"instance-terminated-by-price",
+	"instance-terminated-no-capacity",
+	"instance-terminated-capacity-oversubscribed",
+)
+
+// spotRequestStatus is the status of a spot instance request.
+type spotRequestStatus struct {
+	Code    string `xml:"code"`
+	Message string `xml:"message"`
+}
+
+// InterruptionNotice is part of the instances.InterruptibleInstance
+// interface. EC2 gives notice that it is about to reclaim a spot
+// instance by updating the status of the instance's spot request,
+// about two minutes before the instance is stopped or terminated.
+func (inst *ec2Instance) InterruptionNotice(ctx context.ProviderCallContext) (string, bool) {
+	if !inst.isSpot() {
+		return "", false
+	}
+	if inst.State.Name != "running" {
+		return "", true
+	}
+	requestStatus, err := inst.spotRequestStatusOf(ctx)
+	if err != nil {
+		logger.Warningf("cannot get spot request status of instance %q: %v", inst.InstanceId, err)
+		return "", true
+	}
+	if !spotInterruptionNoticeCodes.Contains(requestStatus.Code) {
+		return "", true
+	}
+	return fmt.Sprintf("spot instance interruption notice (%s): %s", requestStatus.Code, requestStatus.Message), true
+}
+
+// spotRequestStatusOf returns the status of the spot request for the
+// instance. The EC2 client library does not support spot requests, so
+// they are described directly against the EC2 query API, once.
+func (inst *ec2Instance) spotRequestStatusOf(ctx context.ProviderCallContext) (spotRequestStatus, error) {
+	if inst.spotRequestStatus != nil {
+		return *inst.spotRequestStatus, nil
+	}
+	params := url.Values{
+		"Action":           {"DescribeSpotInstanceRequests"},
+		"Version":          {spotAPIVersion},
+		"Filter.1.Name":    {"instance-id"},
+		"Filter.1.Value.1": {inst.InstanceId},
+	}
+	var resp struct {
+		Requests []struct {
+			InstanceId string            `xml:"instanceId"`
+			Status     spotRequestStatus `xml:"status"`
+		} `xml:"spotInstanceRequestSet>item"`
+	}
+	if err := ec2Query(inst.e.ec2, params, &resp); err != nil {
+		return spotRequestStatus{}, maybeConvertCredentialError(err, ctx)
+	}
+	for _, r := range resp.Requests {
+		if r.InstanceId == inst.InstanceId {
+			inst.spotRequestStatus = &r.Status
+			return r.Status, nil
+		}
+	}
+	return spotRequestStatus{}, errors.NotFoundf("spot request for instance %q", inst.InstanceId)
+}
@@ -0,0 +1,311 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	jc "github.com/juju/testing/checkers"
+	"gopkg.in/amz.v3/aws"
+	amzec2 "gopkg.in/amz.v3/ec2"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs/context"
+)
+
+type spotSuite struct{}
+
+var _ = gc.Suite(&spotSuite{})
+
+func (*spotSuite) TestSpotRunInstancesParams(c *gc.C) {
+	params, err := spotRunInstancesParams(&amzec2.RunInstances{
+		MinCount:           1,
+		MaxCount:           1,
+		ImageId:            "ami-1",
+		InstanceType:       "m3.medium",
+		IAMInstanceProfile: "juju-profile",
+		UserData:           []byte("hello"),
+		AvailZone:          "us-east-1a",
+		SubnetId:           "subnet-1",
+		SecurityGroups: []amzec2.SecurityGroup{
+			{Id: "sg-1", Name: "juju-model"},
+			{Name: "juju-machine"},
+		},
+		BlockDeviceMappings: []amzec2.BlockDeviceMapping{
+			{DeviceName: "/dev/sda1", VolumeSize: 8, VolumeType: "gp2", DeleteOnTermination: true},
+			{DeviceName: "/dev/sdb", VirtualName: "ephemeral0"},
+		},
+	}, "0.05")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(params, jc.DeepEquals, url.Values{
+		"Action":                              {"RunInstances"},
+		"Version":                             {"2016-11-15"},
+		"ImageId":                             {"ami-1"},
+		"MinCount":                            {"1"},
+		"MaxCount":                            {"1"},
+		"InstanceType":                        {"m3.medium"},
+		"UserData":                            {"aGVsbG8="},
+		"Placement.AvailabilityZone":          {"us-east-1a"},
+		"SubnetId":                            {"subnet-1"},
+		"SecurityGroupId.1":                   {"sg-1"},
+		"SecurityGroup.1":                     {"juju-machine"},
+		"BlockDeviceMapping.1.DeviceName":     {"/dev/sda1"},
+		"BlockDeviceMapping.1.Ebs.VolumeSize": {"8"},
+		"BlockDeviceMapping.1.Ebs.VolumeType": {"gp2"},
+		"BlockDeviceMapping.1.Ebs.DeleteOnTermination":                   {"true"},
+		"IamInstanceProfile.Name":                                        {"juju-profile"},
+		"BlockDeviceMapping.2.DeviceName":                                {"/dev/sdb"},
+		"BlockDeviceMapping.2.VirtualName":                               {"ephemeral0"},
+		"InstanceMarketOptions.MarketType":                               {"spot"},
+		"InstanceMarketOptions.SpotOptions.SpotInstanceType":             {"one-time"},
+		"InstanceMarketOptions.SpotOptions.InstanceInterruptionBehavior": {"terminate"},
+		"InstanceMarketOptions.SpotOptions.MaxPrice":                     {"0.05"},
+	})
+}
+
+func (*spotSuite) TestSpotRunInstancesParamsNoMaxPrice(c *gc.C) {
+	params, err := spotRunInstancesParams(&amzec2.RunInstances{MinCount: 1, MaxCount: 1}, "")
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := params["InstanceMarketOptions.SpotOptions.MaxPrice"]
+	c.Assert(ok, jc.IsFalse)
+}
+
+func newSpotTestClient(url string) *amzec2.EC2 {
+	region := aws.Region{Name: "test", EC2Endpoint: url}
+	return amzec2.New(aws.Auth{AccessKey: "access", SecretKey: "secret"}, region, aws.SignV4Factory(region.Name, "ec2"))
+}
+
+const spotRunInstancesResponse = `
+<RunInstancesResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">
+  <requestId>req-1</requestId>
+  <reservationId>r-1</reservationId>
+  <instancesSet>
+    <item>
+      <instanceId>i-spot</instanceId>
+      <instanceState><code>0</code><name>pending</name></instanceState>
+    </item>
+  </instancesSet>
+</RunInstancesResponse>
+`
+
+func (*spotSuite) TestRunSpotInstances(c *gc.C) {
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		c.Check(r.Header.Get("Authorization"), gc.Matches, "AWS4-HMAC-SHA256 Credential=access/.*")
+		fmt.Fprint(w, spotRunInstancesResponse)
+	}))
+	defer srv.Close()
+
+	var messages []string
+	callback := func(_ status.Status, msg string, _ map[string]interface{}) error {
+		messages = append(messages, msg)
+		return nil
+	}
+	resp, err := runSpotInstances(newSpotTestClient(srv.URL), context.NewCloudCallContext(), &amzec2.RunInstances{
+		MinCount: 1,
+		MaxCount: 1,
+		ImageId:  "ami-1",
+	}, "0.1", callback)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Instances, gc.HasLen, 1)
+	c.Assert(resp.Instances[0].InstanceId, gc.Equals, "i-spot")
+	c.Assert(resp.Instances[0].State.Name, gc.Equals, "pending")
+	c.Assert(query.Get("InstanceMarketOptions.SpotOptions.MaxPrice"), gc.Equals, "0.1")
+	c.Assert(messages, jc.DeepEquals, []string{"Start spot instance attempt 1"})
+}
+
+func (*spotSuite) TestRunSpotInstancesError(c *gc.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `<Response><Errors><Error><Code>SpotMaxPriceTooLow</Code><Message>price too low</Message></Error></Errors><RequestID>req-2</RequestID></Response>`)
+	}))
+	defer srv.Close()
+
+	_, err := runSpotInstances(newSpotTestClient(srv.URL), context.NewCloudCallContext(), &amzec2.RunInstances{
+		MinCount: 1,
+		MaxCount: 1,
+	}, "0.001", func(status.Status, string, map[string]interface{}) error { return nil })
+	c.Assert(err, gc.ErrorMatches, `price too low \(SpotMaxPriceTooLow\)`)
+	c.Assert(ec2ErrCode(err), gc.Equals, "SpotMaxPriceTooLow")
+}
+
+func (*spotSuite) TestInterrupted(c *gc.C) {
+	var reason string
+	var queries []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		fmt.Fprintf(w, describeStateReasonResponse, reason)
+	}))
+	defer srv.Close()
+
+	for i, test := range []struct {
+		spot        bool
+		state       string
+		reason      string
+		interrupted bool
+		message     string
+	}{
+		{spot: false, state: "terminated", message: "terminated"},
+		{spot: true, state: "running", message: "running"},
+		{spot: true, state: "shutting-down", reason: "Server.SpotInstanceTermination", interrupted: true, message: "spot instance interrupted (shutting-down)"},
+		{spot: true, state: "terminated", reason: "Server.SpotInstanceTermination", interrupted: true, message: "spot instance interrupted (terminated)"},
+		{spot: true, state: "terminated", reason: "Client.UserInitiatedShutdown", message: "terminated"},
+		{spot: true, state: "stopped", reason: "Client.UserInitiatedShutdown", message: "stopped"},
+	} {
+		c.Logf("test %d: spot %v, state %q, reason %q", i, test.spot, test.state, test.reason)
+		reason = test.reason
+		queries = nil
+		inst := &ec2Instance{
+			e: &environ{ec2: newSpotTestClient(srv.URL)},
+			Instance: &amzec2.Instance{
+				InstanceId: "i-1",
+				State:      amzec2.InstanceState{Name: test.state},
+			},
+		}
+		if test.spot {
+			inst.Tags = []amzec2.Tag{{Key: tagSpotInstance, Value: "true"}}
+		}
+		ctx := context.NewCloudCallContext()
+		c.Check(inst.Interrupted(ctx), gc.Equals, test.interrupted)
+		c.Check(inst.Status(ctx).Message, gc.Equals, test.message)
+		// The state reason is only looked up once, and only for spot
+		// instances that are no longer running.
+		if test.reason == "" {
+			c.Check(queries, gc.HasLen, 0)
+		} else {
+			c.Assert(queries, gc.HasLen, 1)
+			c.Check(queries[0].Get("Action"), gc.Equals, "DescribeInstances")
+			c.Check(queries[0].Get("InstanceId.1"), gc.Equals, "i-1")
+		}
+	}
+}
+
+const describeStateReasonResponse = `
+<DescribeInstancesResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">
+  <requestId>req-1</requestId>
+  <reservationSet>
+    <item>
+      <reservationId>r-1</reservationId>
+      <instancesSet>
+        <item>
+          <instanceId>i-1</instanceId>
+          <stateReason><code>%s</code><message>reason</message></stateReason>
+        </item>
+      </instancesSet>
+    </item>
+  </reservationSet>
+</DescribeInstancesResponse>
+`
+
+func (*spotSuite) TestInterruptedError(c *gc.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `<Response><Errors><Error><Code>RequestLimitExceeded</Code><Message>slow down</Message></Error></Errors><RequestID>req-2</RequestID></Response>`)
+	}))
+	defer srv.Close()
+
+	inst := &ec2Instance{
+		e: &environ{ec2: newSpotTestClient(srv.URL)},
+		Instance: &amzec2.Instance{
+			InstanceId: "i-1",
+			State:      amzec2.InstanceState{Name: "terminated"},
+			Tags:       []amzec2.Tag{{Key: tagSpotInstance, Value: "true"}},
+		},
+	}
+	c.Check(inst.Interrupted(context.NewCloudCallContext()), jc.IsFalse)
+}
+
+func (*spotSuite) TestInterruptionNotice(c *gc.C) {
+	var code string
+	var queries []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		fmt.Fprintf(w, describeSpotRequestStatusResponse, code)
+	}))
+	defer srv.Close()
+
+	for i, test := range []struct {
+		spot          bool
+		state         string
+		code          string
+		notice        string
+		interruptible bool
+		queried       bool
+	}{
+		{spot: false, state: "running"},
+		{spot: true, state: "pending", interruptible: true},
+		{spot: true, state: "running", code: "fulfilled", interruptible: true, queried: true},
+		{spot: true, state: "running", code: "marked-for-termination", interruptible: true, queried: true,
+			notice: "spot instance interruption notice (marked-for-termination): status message"},
+		{spot: true, state: "running", code: "instance-terminated-by-price", interruptible: true, queried: true,
+			notice: "spot instance interruption notice (instance-terminated-by-price): status message"},
+	} {
+		c.Logf("test %d: spot %v, state %q, code %q", i, test.spot, test.state, test.code)
+		code = test.code
+		queries = nil
+		inst := &ec2Instance{
+			e: &environ{ec2: newSpotTestClient(srv.URL)},
+			Instance: &amzec2.Instance{
+				InstanceId: "i-1",
+				State:      amzec2.InstanceState{Name: test.state},
+			},
+		}
+		if test.spot {
+			inst.Tags = []amzec2.Tag{{Key: tagSpotInstance, Value: "true"}}
+		}
+		ctx := context.NewCloudCallContext()
+		notice, interruptible := inst.InterruptionNotice(ctx)
+		c.Check(notice, gc.Equals, test.notice)
+		c.Check(interruptible, gc.Equals, test.interruptible)
+		// The spot request status is only looked up once, and only
+		// for running spot instances.
+		inst.InterruptionNotice(ctx)
+		if !test.queried {
+			c.Check(queries, gc.HasLen, 0)
+		} else {
+			c.Assert(queries, gc.HasLen, 1)
+			c.Check(queries[0].Get("Action"), gc.Equals, "DescribeSpotInstanceRequests")
+			c.Check(queries[0].Get("Filter.1.Name"), gc.Equals, "instance-id")
+			c.Check(queries[0].Get("Filter.1.Value.1"), gc.Equals, "i-1")
+		}
+	}
+}
+
+const describeSpotRequestStatusResponse = `
+<DescribeSpotInstanceRequestsResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">
+  <requestId>req-1</requestId>
+  <spotInstanceRequestSet>
+    <item>
+      <spotInstanceRequestId>sir-1</spotInstanceRequestId>
+      <instanceId>i-1</instanceId>
+      <status><code>%s</code><message>status message</message></status>
+    </item>
+  </spotInstanceRequestSet>
+</DescribeSpotInstanceRequestsResponse>
+`
+
+func (*spotSuite) TestInterruptionNoticeError(c *gc.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `<Response><Errors><Error><Code>RequestLimitExceeded</Code><Message>slow down</Message></Error></Errors><RequestID>req-2</RequestID></Response>`)
+	}))
+	defer srv.Close()
+
+	inst := &ec2Instance{
+		e: &environ{ec2: newSpotTestClient(srv.URL)},
+		Instance: &amzec2.Instance{
+			InstanceId: "i-1",
+			State:      amzec2.InstanceState{Name: "running"},
+			Tags:       []amzec2.Tag{{Key: tagSpotInstance, Value: "true"}},
+		},
+	}
+	notice, interruptible := inst.InterruptionNotice(context.NewCloudCallContext())
+	c.Check(notice, gc.Equals, "")
+	c.Check(interruptible, jc.IsTrue)
+}
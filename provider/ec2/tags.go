@@ -0,0 +1,196 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/tags"
+)
+
+var _ environs.ResourceTagUpdater = (*environ)(nil)
+
+// maxTagResources is the number of resources tagged in
+// each CreateTags or DeleteTags request.
+var maxTagResources = 100
+
+// UpdateResourceTags is part of the environs.ResourceTagUpdater interface.
+// Every resource tagged with the model's UUID, which includes the model's
+// instances, their root disks, EBS volumes and security groups, has the
+// given tags set and the removed tags deleted.
+func (e *environ) UpdateResourceTags(ctx context.ProviderCallContext, tags map[string]string, removed []string) error {
+	resourceIds, err := modelResourceIds(e.ec2, e.Config().UUID())
+	if err != nil {
+		return maybeConvertCredentialError(err, ctx)
+	}
+	if len(resourceIds) == 0 {
+		return nil
+	}
+	logger.Debugf("updating tags of %d resources", len(resourceIds))
+	if err := updateResourceTags(e.ec2, resourceIds, tags, removed); err != nil {
+		return maybeConvertCredentialError(err, ctx)
+	}
+	return nil
+}
+
+// ResourceTagKeys is part of the environs.ResourceTagUpdater interface.
+func (e *environ) ResourceTagKeys(ctx context.ProviderCallContext, prefix string) ([]string, error) {
+	keys, err := modelResourceTagKeys(e.ec2, e.Config().UUID(), prefix)
+	if err != nil {
+		return nil, maybeConvertCredentialError(err, ctx)
+	}
+	return keys, nil
+}
+
+// The EC2 client library does not support describing or deleting tags,
+// so the requests are made directly against the EC2 query API.
+
+// modelResourceIds returns the ids of all resources
+// tagged with the given model UUID.
+func modelResourceIds(client *ec2.EC2, modelUUID string) ([]string, error) {
+	found, err := describeTags(client, tags.JujuModel, modelUUID)
+	if err != nil {
+		return nil, errors.Annotate(err, "describing model resources")
+	}
+	ids := make([]string, len(found))
+	for i, tag := range found {
+		ids[i] = tag.ResourceId
+	}
+	return ids, nil
+}
+
+// modelResourceTagKeys returns the distinct keys of the tags with the
+// given prefix on the resources tagged with the given model UUID.
+func modelResourceTagKeys(client *ec2.EC2, modelUUID, prefix string) ([]string, error) {
+	ids, err := modelResourceIds(client, modelUUID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	modelIds := set.NewStrings(ids...)
+	found, err := describeTags(client, prefix+"*", "")
+	if err != nil {
+		return nil, errors.Annotatef(err, "describing %q tags", prefix)
+	}
+	keys := set.NewStrings()
+	for _, tag := range found {
+		if modelIds.Contains(tag.ResourceId) {
+			keys.Add(tag.Key)
+		}
+	}
+	return keys.SortedValues(), nil
+}
+
+// resourceTag is a tag of a resource, as described by DescribeTags.
+type resourceTag struct {
+	ResourceId string `xml:"resourceId"`
+	Key        string `xml:"key"`
+	Value      string `xml:"value"`
+}
+
+// describeTags returns the tags with the given key, which may include
+// wildcards, and, if it is not empty, the given value.
+func describeTags(client *ec2.EC2, key, value string) ([]resourceTag, error) {
+	var found []resourceTag
+	var nextToken string
+	for {
+		params := url.Values{
+			"Action":           {"DescribeTags"},
+			"Version":          {spotAPIVersion},
+			"Filter.1.Name":    {"key"},
+			"Filter.1.Value.1": {key},
+		}
+		if value != "" {
+			params.Set("Filter.2.Name", "value")
+			params.Set("Filter.2.Value.1", value)
+		}
+		if nextToken != "" {
+			params.Set("NextToken", nextToken)
+		}
+		var resp struct {
+			Tags      []resourceTag `xml:"tagSet>item"`
+			NextToken string        `xml:"nextToken"`
+		}
+		if err := ec2Query(client, params, &resp); err != nil {
+			return nil, errors.Trace(err)
+		}
+		found = append(found, resp.Tags...)
+		if resp.NextToken == "" {
+			return found, nil
+		}
+		nextToken = resp.NextToken
+	}
+}
+
+// updateResourceTags sets the given tags on the given resources, and
+// deletes the removed tags from them.
+func updateResourceTags(client *ec2.EC2, resourceIds []string, tags map[string]string, removed []string) error {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for start := 0; start < len(resourceIds); start += maxTagResources {
+		end := start + maxTagResources
+		if end > len(resourceIds) {
+			end = len(resourceIds)
+		}
+		batch := resourceIds[start:end]
+		if len(keys) > 0 {
+			params := tagsQuery("CreateTags", batch)
+			for i, k := range keys {
+				params.Set(fmt.Sprintf("Tag.%d.Key", i+1), k)
+				params.Set(fmt.Sprintf("Tag.%d.Value", i+1), tags[k])
+			}
+			if err := tagsRequest(client, params); err != nil {
+				return errors.Annotate(err, "creating tags")
+			}
+		}
+		if len(removed) > 0 {
+			params := tagsQuery("DeleteTags", batch)
+			for i, k := range removed {
+				params.Set(fmt.Sprintf("Tag.%d.Key", i+1), k)
+			}
+			if err := tagsRequest(client, params); err != nil {
+				return errors.Annotate(err, "deleting tags")
+			}
+		}
+	}
+	return nil
+}
+
+// tagsQuery returns the parameters of a CreateTags or
+// DeleteTags request for the given resources.
+func tagsQuery(action string, resourceIds []string) url.Values {
+	params := url.Values{
+		"Action":  {action},
+		"Version": {spotAPIVersion},
+	}
+	for i, id := range resourceIds {
+		params.Set(fmt.Sprintf("ResourceId.%d", i+1), id)
+	}
+	return params
+}
+
+// tagsRequest sends a CreateTags or DeleteTags request.
+func tagsRequest(client *ec2.EC2, params url.Values) error {
+	var resp struct {
+		RequestId string `xml:"requestId"`
+		Return    bool   `xml:"return"`
+	}
+	if err := ec2Query(client, params, &resp); err != nil {
+		return errors.Trace(err)
+	}
+	if !resp.Return {
+		return errors.Errorf("%s request %s failed", params.Get("Action"), resp.RequestId)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+)
+
+type tagsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&tagsSuite{})
+
+// fakeTagsServer responds to DescribeTags requests for the model's
+// resources with the given pages of resource ids, and to those for
+// other tags with the given tag keys, keyed by resource id. The other
+// requests made are recorded.
+func fakeTagsServer(c *gc.C, tagKeys map[string][]string, pages ...[]string) (*httptest.Server, *[]url.Values) {
+	var requests []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		action := query.Get("Action")
+		if action != "DescribeTags" {
+			query.Del("Version")
+			requests = append(requests, query)
+			fmt.Fprintf(w, `<%sResponse><requestId>req-1</requestId><return>true</return></%sResponse>`, action, action)
+			return
+		}
+		key := query.Get("Filter.1.Value.1")
+		fmt.Fprint(w, `<DescribeTagsResponse><tagSet>`)
+		if key != "juju-model-uuid" {
+			c.Check(key, gc.Equals, "juju-resource-tag-*")
+			c.Check(query.Get("Filter.2.Value.1"), gc.Equals, "")
+			for id, keys := range tagKeys {
+				for _, k := range keys {
+					fmt.Fprintf(w, `<item><resourceId>%s</resourceId><key>%s</key><value>true</value></item>`, id, k)
+				}
+			}
+			fmt.Fprint(w, `</tagSet></DescribeTagsResponse>`)
+			return
+		}
+		c.Check(query.Get("Filter.2.Value.1"), gc.Equals, "model-uuid")
+		page := 0
+		if token := query.Get("NextToken"); token != "" {
+			fmt.Sscanf(token, "page-%d", &page)
+		}
+		if page < len(pages) {
+			for _, id := range pages[page] {
+				fmt.Fprintf(w, `<item><resourceId>%s</resourceId><key>juju-model-uuid</key><value>model-uuid</value></item>`, id)
+			}
+		}
+		fmt.Fprint(w, `</tagSet>`)
+		if page+1 < len(pages) {
+			fmt.Fprintf(w, `<nextToken>page-%d</nextToken>`, page+1)
+		}
+		fmt.Fprint(w, `</DescribeTagsResponse>`)
+	}))
+	return srv, &requests
+}
+
+func (s *tagsSuite) TestModelResourceIds(c *gc.C) {
+	srv, _ := fakeTagsServer(c, nil, []string{"i-0", "vol-0"}, []string{"sg-0"})
+	defer srv.Close()
+
+	ids, err := modelResourceIds(newSpotTestClient(srv.URL), "model-uuid")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ids, jc.DeepEquals, []string{"i-0", "vol-0", "sg-0"})
+}
+
+func (s *tagsSuite) TestModelResourceTagKeys(c *gc.C) {
+	srv, _ := fakeTagsServer(c, map[string][]string{
+		"i-0":   {"juju-resource-tag-dept", "juju-resource-tag-owner"},
+		"vol-0": {"juju-resource-tag-dept", "juju-resource-tag-owner"},
+		"sg-0":  {"juju-resource-tag-owner"},
+		// Resources of other models are ignored.
+		"i-1": {"juju-resource-tag-cost-centre"},
+	}, []string{"i-0", "vol-0"}, []string{"sg-0"})
+	defer srv.Close()
+
+	keys, err := modelResourceTagKeys(newSpotTestClient(srv.URL), "model-uuid", "juju-resource-tag-")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, jc.DeepEquals, []string{"juju-resource-tag-dept", "juju-resource-tag-owner"})
+}
+
+func (s *tagsSuite) TestUpdateResourceTags(c *gc.C) {
+	srv, requests := fakeTagsServer(c, nil)
+	defer srv.Close()
+
+	err := updateResourceTags(newSpotTestClient(srv.URL), []string{"i-0", "sg-0"}, map[string]string{
+		"owner": "fred",
+		"dept":  "eng",
+	}, []string{"cost-centre"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*requests, jc.DeepEquals, []url.Values{{
+		"Action":       {"CreateTags"},
+		"ResourceId.1": {"i-0"},
+		"ResourceId.2": {"sg-0"},
+		"Tag.1.Key":    {"dept"},
+		"Tag.1.Value":  {"eng"},
+		"Tag.2.Key":    {"owner"},
+		"Tag.2.Value":  {"fred"},
+	}, {
+		"Action":       {"DeleteTags"},
+		"ResourceId.1": {"i-0"},
+		"ResourceId.2": {"sg-0"},
+		"Tag.1.Key":    {"cost-centre"},
+	}})
+}
+
+func (s *tagsSuite) TestUpdateResourceTagsBatches(c *gc.C) {
+	srv, requests := fakeTagsServer(c, nil)
+	defer srv.Close()
+	s.PatchValue(&maxTagResources, 1)
+
+	err := updateResourceTags(newSpotTestClient(srv.URL), []string{"i-0", "i-1"}, nil, []string{"owner"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*requests, gc.HasLen, 2)
+	c.Assert((*requests)[0].Get("ResourceId.1"), gc.Equals, "i-0")
+	c.Assert((*requests)[1].Get("ResourceId.1"), gc.Equals, "i-1")
+}
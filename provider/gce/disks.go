@@ -42,7 +42,11 @@ type storageProvider struct {
 var _ storage.Provider = (*storageProvider)(nil)
 
 func (g *storageProvider) ValidateConfig(cfg *storage.Config) error {
-	return nil
+	// Persistent disks are always encrypted at rest, so the
+	// "encrypted" attribute only needs validating; "kms-key"
+	// selects a customer-managed Cloud KMS key.
+	_, err := storage.ParseEncryptionConfig(cfg.Attrs())
+	return errors.Trace(err)
 }
 
 func (g *storageProvider) Supports(k storage.StorageKind) bool {
@@ -76,6 +80,8 @@ type volumeSource struct {
 	modelUUID string
 }
 
+var _ storage.VolumeResizer = (*volumeSource)(nil)
+
 func (g *storageProvider) VolumeSource(cfg *storage.Config) (storage.VolumeSource, error) {
 	environConfig := g.env.Config()
 	source := &volumeSource{
@@ -208,6 +214,10 @@ func (v *volumeSource) createOneVolume(ctx context.ProviderCallContext, p storag
 	if !ok {
 		persistentType = google.DiskPersistentStandard
 	}
+	encryption, err := storage.ParseEncryptionConfig(p.Attributes)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
 
 	zone = inst.ZoneName
 	volumeName, err = nameVolume(zone)
@@ -221,6 +231,7 @@ func (v *volumeSource) createOneVolume(ctx context.ProviderCallContext, p storag
 		Name:               volumeName,
 		PersistentDiskType: persistentType,
 		Labels:             resourceTagsToDiskLabels(p.ResourceTags),
+		KMSKeyName:         encryption.KMSKey,
 	}
 
 	gceDisks, err := v.gce.CreateDisks(zone, []google.DiskSpec{disk})
@@ -243,6 +254,7 @@ func (v *volumeSource) createOneVolume(ctx context.ProviderCallContext, p storag
 			VolumeId:   gceDisk.Name,
 			Size:       gceDisk.Size,
 			Persistent: true,
+			Encrypted:  true,
 		},
 	}
 
@@ -339,6 +351,27 @@ func (v *volumeSource) releaseOneVolume(ctx context.ProviderCallContext, volName
 	return nil
 }
 
+// ResizeVolumes is specified on the storage.VolumeResizer interface.
+// GCE disks are sized in GiB, so the requested size is rounded up to
+// the nearest GiB.
+func (v *volumeSource) ResizeVolumes(ctx context.ProviderCallContext, params []storage.VolumeResizeParams) ([]storage.ResizeResult, error) {
+	results := make([]storage.ResizeResult, len(params))
+	for i, p := range params {
+		zone, _, err := parseVolumeId(p.VolumeId)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "invalid volume id %q", p.VolumeId)
+			continue
+		}
+		sizeGb := mibToGib(p.Size)
+		if err := v.gce.ResizeDisk(zone, p.VolumeId, int64(sizeGb)); err != nil {
+			results[i].Error = google.HandleCredentialError(errors.Trace(err), ctx)
+			continue
+		}
+		results[i].Size = sizeGb * 1024
+	}
+	return results, nil
+}
+
 func (v *volumeSource) ListVolumes(ctx context.ProviderCallContext) ([]string, error) {
 	var volumes []string
 	disks, err := v.gce.Disks()
@@ -38,6 +38,22 @@ func (s *storageProviderSuite) TestValidateConfig(c *gc.C) {
 	c.Check(err, jc.ErrorIsNil)
 }
 
+func (s *storageProviderSuite) TestValidateConfigEncryption(c *gc.C) {
+	cfg, err := storage.NewConfig("foo", "gce", map[string]interface{}{
+		"encrypted": "true",
+		"kms-key":   "projects/spam/locations/global/keyRings/juju/cryptoKeys/disks",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.provider.ValidateConfig(cfg), jc.ErrorIsNil)
+
+	cfg, err = storage.NewConfig("foo", "gce", map[string]interface{}{
+		"kms-key": "projects/spam/locations/global/keyRings/juju/cryptoKeys/disks",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.provider.ValidateConfig(cfg)
+	c.Check(err, gc.ErrorMatches, `"kms-key" specified, but "encrypted" is not true`)
+}
+
 func (s *storageProviderSuite) TestBlockStorageSupport(c *gc.C) {
 	supports := s.provider.Supports(storage.StorageKindBlock)
 	c.Check(supports, jc.IsTrue)
@@ -142,6 +158,7 @@ func (s *volumeSourceSuite) TestCreateVolumes(c *gc.C) {
 	c.Assert(res[0].Error, jc.ErrorIsNil)
 	c.Assert(res[0].Volume.VolumeId, gc.Equals, s.BaseDisk.Name)
 	c.Assert(res[0].Volume.HardwareId, gc.Equals, "")
+	c.Assert(res[0].Volume.Encrypted, jc.IsTrue)
 
 	// Volume was also attached as indicated by Attachment in params.
 	c.Assert(res[0].VolumeAttachment.DeviceName, gc.Equals, "")
@@ -157,6 +174,7 @@ func (s *volumeSourceSuite) TestCreateVolumes(c *gc.C) {
 	c.Assert(createCalled, jc.IsTrue)
 	c.Assert(call[0].ZoneName, gc.Equals, "home-zone")
 	c.Assert(call[0].Disks[0].Name, jc.HasPrefix, "home-zone--")
+	c.Assert(call[0].Disks[0].KMSKeyName, gc.Equals, "")
 
 	// Instance existence Checking
 	instanceDisksCalled, call := s.FakeConn.WasCalled("InstanceDisks")
@@ -174,6 +192,31 @@ func (s *volumeSourceSuite) TestCreateVolumes(c *gc.C) {
 	c.Assert(call[0].InstanceId, gc.Equals, string(s.instId))
 }
 
+func (s *volumeSourceSuite) TestCreateVolumesKMSKey(c *gc.C) {
+	s.FakeConn.Insts = []google.Instance{*s.BaseInstance}
+	s.FakeConn.GoogleDisks = []*google.Disk{s.BaseDisk}
+	s.FakeConn.GoogleDisk = s.BaseDisk
+	s.FakeConn.AttachedDisk = &google.AttachedDisk{
+		VolumeName: s.BaseDisk.Name,
+		DeviceName: "home-zone-1234567",
+		Mode:       "READ_WRITE",
+	}
+	s.params[0].Attributes = map[string]interface{}{
+		"encrypted": true,
+		"kms-key":   "projects/spam/locations/global/keyRings/juju/cryptoKeys/disks",
+	}
+	res, err := s.source.CreateVolumes(s.CallCtx, s.params)
+	c.Check(err, jc.ErrorIsNil)
+	c.Assert(res, gc.HasLen, 1)
+	c.Assert(res[0].Error, jc.ErrorIsNil)
+	c.Assert(res[0].Volume.Encrypted, jc.IsTrue)
+
+	createCalled, call := s.FakeConn.WasCalled("CreateDisks")
+	c.Assert(createCalled, jc.IsTrue)
+	c.Assert(call, gc.HasLen, 1)
+	c.Assert(call[0].Disks[0].KMSKeyName, gc.Equals, "projects/spam/locations/global/keyRings/juju/cryptoKeys/disks")
+}
+
 func (s *volumeSourceSuite) TestDestroyVolumesInvalidCredentialError(c *gc.C) {
 	s.FakeConn.Err = gce.InvalidCredentialError
 	c.Assert(s.InvalidatedCredentials, jc.IsFalse)
@@ -195,6 +238,22 @@ func (s *volumeSourceSuite) TestDestroyVolumes(c *gc.C) {
 	c.Assert(call[0].ID, gc.Equals, "a--volume-name")
 }
 
+func (s *volumeSourceSuite) TestResizeVolumes(c *gc.C) {
+	results, err := s.source.(storage.VolumeResizer).ResizeVolumes(s.CallCtx, []storage.VolumeResizeParams{{
+		VolumeId: "a--volume-name",
+		Size:     2049,
+	}})
+	c.Check(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.ResizeResult{{Size: 3072}})
+
+	resizeCalled, call := s.FakeConn.WasCalled("ResizeDisk")
+	c.Assert(resizeCalled, jc.IsTrue)
+	c.Assert(call, gc.HasLen, 1)
+	c.Assert(call[0].ZoneName, gc.Equals, "a")
+	c.Assert(call[0].ID, gc.Equals, "a--volume-name")
+	c.Assert(call[0].SizeGb, gc.Equals, int64(3))
+}
+
 func (s *volumeSourceSuite) TestReleaseVolumesInvalidCredentialError(c *gc.C) {
 	s.FakeConn.Err = gce.InvalidCredentialError
 	c.Assert(s.InvalidatedCredentials, jc.IsFalse)
@@ -31,6 +31,8 @@ type gceConnection interface {
 	AddInstance(spec google.InstanceSpec) (*google.Instance, error)
 	RemoveInstances(prefix string, ids ...string) error
 	UpdateMetadata(key, value string, ids ...string) error
+	UpdateMetadataItems(items map[string]string, removed []string, ids ...string) error
+	SetDeletionProtection(protected bool, ids ...string) error
 
 	IngressRules(fwname string) ([]network.IngressRule, error)
 	OpenPorts(fwname string, rules ...network.IngressRule) error
@@ -59,6 +61,9 @@ type gceConnection interface {
 	// SetDiskLabels sets the labels on a disk, ensuring that the disk's
 	// label fingerprint matches the one supplied.
 	SetDiskLabels(zone, id, labelFingerprint string, labels map[string]string) error
+	// ResizeDisk grows the disk identified by <id> in <zone> to the
+	// given size in GiB.
+	ResizeDisk(zone, id string, sizeGb int64) error
 	// AttachDisk will attach the volume identified by <volumeName> into the instance
 	// <instanceId> and return an AttachedDisk representing it or error.
 	AttachDisk(zone, volumeName, instanceId string, mode google.DiskMode) (*google.AttachedDisk, error)
@@ -86,6 +91,7 @@ type environ struct {
 
 var _ environs.Environ = (*environ)(nil)
 var _ environs.NetworkingEnviron = (*environ)(nil)
+var _ environs.InstanceProtector = (*environ)(nil)
 
 // Function entry points defined as variables so they can be overridden
 // for testing purposes.
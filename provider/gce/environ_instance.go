@@ -146,6 +146,21 @@ func (env *environ) AdoptResources(ctx context.ProviderCallContext, controllerUU
 	return nil
 }
 
+// SetInstanceProtection is part of the environs.InstanceProtector interface.
+// Protection is applied using GCE's deletion protection, which causes
+// requests to delete the instances to be refused.
+func (env *environ) SetInstanceProtection(ctx context.ProviderCallContext, ids []instance.Id, protected bool) error {
+	var stringIds []string
+	for _, id := range ids {
+		stringIds = append(stringIds, string(id))
+	}
+	err := env.gce.SetDeletionProtection(protected, stringIds...)
+	if err != nil {
+		return google.HandleCredentialError(errors.Trace(err), ctx)
+	}
+	return nil
+}
+
 // TODO(ericsnow) Turn into an interface.
 type instPlacement struct {
 	Zone *google.AvailabilityZone
@@ -231,6 +231,16 @@ func (s *environInstSuite) TestAdoptResources(c *gc.C) {
 	c.Check(call.Value, gc.Equals, "other-uuid")
 }
 
+func (s *environInstSuite) TestSetInstanceProtection(c *gc.C) {
+	err := s.Env.SetInstanceProtection(s.CallCtx, []instance.Id{"john", "misty"}, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 1)
+	call := s.FakeConn.Calls[0]
+	c.Check(call.FuncName, gc.Equals, "SetDeletionProtection")
+	c.Check(call.IDs, gc.DeepEquals, []string{"john", "misty"})
+	c.Check(call.Protected, jc.IsTrue)
+}
+
 func (s *environInstSuite) TestAdoptResourcesInvalidCredentialError(c *gc.C) {
 	s.FakeConn.Err = gce.InvalidCredentialError
 	c.Assert(s.InvalidatedCredentials, jc.IsFalse)
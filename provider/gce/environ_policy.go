@@ -34,6 +34,9 @@ func (env *environ) PrecheckInstance(ctx context.ProviderCallContext, args envir
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.Spot,
+	constraints.MaxPrice,
+	constraints.AntiAffinity,
 }
 
 // instanceTypeConstraints defines the fields defined on each of the
@@ -41,6 +41,11 @@ type rawConnectionWrapper interface {
 	// completed or fails.
 	SetMetadata(projectID, zone, instanceID string, metadata *compute.Metadata) error
 
+	// SetDeletionProtection sends a request to the GCE API to set
+	// whether one instance is protected from deletion. The call
+	// blocks until the request is completed or fails.
+	SetDeletionProtection(projectID, zone, instanceID string, protected bool) error
+
 	// GetFirewalls sends an API request to GCE for the information about
 	// the firewalls with the namePrefix and returns them.
 	// If no firewalls are not found, errors.NotFound is returned.
@@ -84,6 +89,9 @@ type rawConnectionWrapper interface {
 	// label fingerprint matches the one supplied.
 	SetDiskLabels(project, zone, id, labelFingerprint string, labels map[string]string) error
 
+	// ResizeDisk grows the disk identified by id to the given size in GiB.
+	ResizeDisk(project, zone, id string, sizeGb int64) error
+
 	// AttachDisk will attach the disk described in attachedDisks (if it exists) into
 	// the instance with id instanceId.
 	AttachDisk(project, zone, instanceId string, attachedDisk *compute.AttachedDisk) error
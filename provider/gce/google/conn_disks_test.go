@@ -40,6 +40,21 @@ func (s *connSuite) TestConnectionCreateDisks(c *gc.C) {
 	c.Check(s.FakeConn.Calls[0].ComputeDisk.Name, gc.Equals, fakeVolName)
 }
 
+func (s *connSuite) TestConnectionCreateDisksKMSKey(c *gc.C) {
+	spec, _, err := fakeDiskAndSpec()
+	c.Check(err, jc.ErrorIsNil)
+	spec.KMSKeyName = "projects/spam/locations/global/keyRings/juju/cryptoKeys/disks"
+
+	_, err = s.Conn.CreateDisks("home-zone", []google.DiskSpec{spec})
+	c.Check(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "CreateDisk")
+	c.Check(s.FakeConn.Calls[0].ComputeDisk.DiskEncryptionKey, jc.DeepEquals, &compute.CustomerEncryptionKey{
+		KmsKeyName: "projects/spam/locations/global/keyRings/juju/cryptoKeys/disks",
+	})
+}
+
 func (s *connSuite) TestConnectionDisks(c *gc.C) {
 	_, fakeDisk, err := fakeDiskAndSpec()
 	c.Check(err, jc.ErrorIsNil)
@@ -75,6 +90,18 @@ func (s *connSuite) TestConnectionDisk(c *gc.C) {
 	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "home-zone")
 }
 
+func (s *connSuite) TestConnectionResizeDisk(c *gc.C) {
+	err := s.Conn.ResizeDisk("home-zone", fakeVolName, 3)
+	c.Check(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "ResizeDisk")
+	c.Check(s.FakeConn.Calls[0].ProjectID, gc.Equals, "spam")
+	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "home-zone")
+	c.Check(s.FakeConn.Calls[0].ID, gc.Equals, fakeVolName)
+	c.Check(s.FakeConn.Calls[0].SizeGb, gc.Equals, int64(3))
+}
+
 func (s *connSuite) TestConnectionSetDiskLabels(c *gc.C) {
 	_, fakeDisk, err := fakeDiskAndSpec()
 	c.Check(err, jc.ErrorIsNil)
@@ -5,7 +5,9 @@ package google
 
 import (
 	"path"
+	"sort"
 
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"google.golang.org/api/compute/v1"
 )
@@ -191,6 +193,111 @@ func (gce *Connection) updateInstanceMetadata(instance *compute.Instance, key, v
 	return errors.Trace(gce.raw.SetMetadata(gce.projectID, zoneName, instance.Name, metadata))
 }
 
+// UpdateMetadataItems sets the given metadata items, and removes the
+// items with the given keys, for all of the instance ids given. The
+// call blocks until all of the instances are updated or the request
+// fails.
+func (gce *Connection) UpdateMetadataItems(items map[string]string, removed []string, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	instances, err := gce.raw.ListInstances(gce.projectID, "")
+	if err != nil {
+		return errors.Annotatef(err, "updating metadata for instances %v", ids)
+	}
+	var failed []string
+	for _, instID := range ids {
+		for _, inst := range instances {
+			if inst.Name == instID {
+				if err := gce.updateInstanceMetadataItems(inst, items, removed); err != nil {
+					failed = append(failed, instID)
+					logger.Errorf("while updating metadata for instance %q: %v", instID, err)
+				}
+				break
+			}
+		}
+	}
+	if len(failed) != 0 {
+		return errors.Errorf("some metadata updates failed: %v", failed)
+	}
+	return nil
+}
+
+func (gce *Connection) updateInstanceMetadataItems(instance *compute.Instance, items map[string]string, removed []string) error {
+	metadata := instance.Metadata
+	var changed bool
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := items[key]
+		existingItem := findMetadataItem(metadata.Items, key)
+		if existingItem == nil {
+			metadata.Items = append(metadata.Items, &compute.MetadataItems{Key: key, Value: &value})
+			changed = true
+		} else if existingItem.Value == nil || *existingItem.Value != value {
+			existingItem.Value = &value
+			changed = true
+		}
+	}
+	if len(removed) > 0 {
+		removedKeys := set.NewStrings(removed...)
+		var kept []*compute.MetadataItems
+		for _, item := range metadata.Items {
+			if item != nil && removedKeys.Contains(item.Key) {
+				changed = true
+				continue
+			}
+			kept = append(kept, item)
+		}
+		metadata.Items = kept
+	}
+	if !changed {
+		return nil
+	}
+	// The GCE API won't accept a full URL for the zone (lp:1667172).
+	zoneName := path.Base(instance.Zone)
+	return errors.Trace(gce.raw.SetMetadata(gce.projectID, zoneName, instance.Name, metadata))
+}
+
+// SetDeletionProtection sets whether each of the instance ids given is
+// protected from deletion. The call blocks until all of the instances
+// are updated or the request fails.
+func (gce *Connection) SetDeletionProtection(protected bool, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	instances, err := gce.raw.ListInstances(gce.projectID, "")
+	if err != nil {
+		return errors.Annotatef(err, "setting deletion protection for instances %v", ids)
+	}
+	var failed []string
+	for _, instID := range ids {
+		for _, inst := range instances {
+			if inst.Name == instID {
+				if inst.DeletionProtection == protected {
+					break
+				}
+				// The GCE API won't accept a full URL for the zone (lp:1667172).
+				zoneName := path.Base(inst.Zone)
+				if err := gce.raw.SetDeletionProtection(gce.projectID, zoneName, inst.Name, protected); err != nil {
+					failed = append(failed, instID)
+					logger.Errorf("while setting deletion protection for instance %q: %v", instID, err)
+				}
+				break
+			}
+		}
+	}
+	if len(failed) != 0 {
+		return errors.Errorf("some deletion protection updates failed: %v", failed)
+	}
+	return nil
+}
+
 func findMetadataItem(items []*compute.MetadataItems, key string) *compute.MetadataItems {
 	for _, item := range items {
 		if item == nil {
@@ -425,6 +425,48 @@ func (s *connSuite) TestUpdateMetadataMultipleInstances(c *gc.C) {
 	checkMetadataItems(c, md.Items[1], "rick", "morty")
 }
 
+func (s *connSuite) TestUpdateMetadataItems(c *gc.C) {
+	s.RawInstanceFull.Zone = "http://eels/lone/wolf/a-zone"
+	s.RawInstanceFull.Metadata = &compute.Metadata{
+		Fingerprint: "heymumwatchthis",
+		Items: []*compute.MetadataItems{
+			makeMetadataItems("eggs", "steak"),
+			makeMetadataItems("rick", "moranis"),
+		},
+	}
+	s.FakeConn.Instances = []*compute.Instance{&s.RawInstanceFull}
+
+	err := s.Conn.UpdateMetadataItems(map[string]string{
+		"eggs":     "beans",
+		"business": "time",
+	}, []string{"rick"}, s.RawInstanceFull.Name)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "ListInstances")
+
+	call := s.FakeConn.Calls[1]
+	c.Check(call.FuncName, gc.Equals, "SetMetadata")
+	c.Check(call.ZoneName, gc.Equals, "a-zone")
+	c.Check(call.InstanceId, gc.Equals, "spam")
+
+	md := call.Metadata
+	c.Check(md.Fingerprint, gc.Equals, "heymumwatchthis")
+	c.Assert(md.Items, gc.HasLen, 2)
+	checkMetadataItems(c, md.Items[0], "eggs", "beans")
+	checkMetadataItems(c, md.Items[1], "business", "time")
+}
+
+func (s *connSuite) TestUpdateMetadataItemsUnchanged(c *gc.C) {
+	s.FakeConn.Instances = []*compute.Instance{&s.RawInstanceFull}
+
+	err := s.Conn.UpdateMetadataItems(map[string]string{"eggs": "steak"}, []string{"rick"}, s.RawInstanceFull.Name)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "ListInstances")
+}
+
 func (s *connSuite) TestUpdateMetadataError(c *gc.C) {
 	instance2 := s.RawInstanceFull
 	instance2.Name = "trucks"
@@ -464,6 +506,36 @@ func (s *connSuite) TestUpdateMetadataChecksCurrentValue(c *gc.C) {
 	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "ListInstances")
 }
 
+func (s *connSuite) TestSetDeletionProtection(c *gc.C) {
+	s.RawInstanceFull.Zone = "http://eels/lone/wolf/a-zone"
+	instance2 := s.RawInstanceFull
+	instance2.Name = "trucks"
+	instance2.DeletionProtection = true
+	s.FakeConn.Instances = []*compute.Instance{&s.RawInstanceFull, &instance2}
+
+	err := s.Conn.SetDeletionProtection(true, "spam", "trucks")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Instance "trucks" is already protected, so is not updated.
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "ListInstances")
+	call := s.FakeConn.Calls[1]
+	c.Check(call.FuncName, gc.Equals, "SetDeletionProtection")
+	c.Check(call.ProjectID, gc.Equals, "spam")
+	c.Check(call.ZoneName, gc.Equals, "a-zone")
+	c.Check(call.InstanceId, gc.Equals, "spam")
+	c.Check(call.Protected, jc.IsTrue)
+}
+
+func (s *connSuite) TestSetDeletionProtectionError(c *gc.C) {
+	s.FakeConn.Instances = []*compute.Instance{&s.RawInstanceFull}
+	s.FakeConn.Err = errors.New("kablooey")
+	s.FakeConn.FailOnCall = 1
+
+	err := s.Conn.SetDeletionProtection(true, "spam")
+	c.Assert(err, gc.ErrorMatches, `some deletion protection updates failed: \[spam\]`)
+}
+
 func makeMetadataItems(key, value string) *compute.MetadataItems {
 	return &compute.MetadataItems{Key: key, Value: google.StringPtr(value)}
 }
@@ -88,7 +88,7 @@ func (gce Connection) OpenPortsWithNamer(target string, namer FirewallNamer, rul
 		inputFirewall := inputRuleSet[key]
 
 		// First check to see if there's any existing firewall with the same ports as what we want.
-		existingFirewall, ok := currentRuleSet.matchProtocolPorts(inputFirewall.AllowedPorts)
+		existingFirewall, ok := currentRuleSet.matchProtocolPorts(inputFirewall.AllowedPorts, inputFirewall.SourceCIDRs)
 		if !ok {
 			// If not, look for any existing firewall with the same source CIDRs.
 			existingFirewall, ok = currentRuleSet.matchSourceCIDRs(inputFirewall.SourceCIDRs)
@@ -169,7 +169,7 @@ func (gce Connection) ClosePorts(target string, rules ...network.IngressRule) er
 	// For each input firewall, find an existing firewall including it
 	// and update or remove it.
 	for _, inputFirewall := range inputRuleSet {
-		existingFirewall, allPortsMatch := currentRuleSet.matchProtocolPorts(inputFirewall.AllowedPorts)
+		existingFirewall, allPortsMatch := currentRuleSet.matchProtocolPorts(inputFirewall.AllowedPorts, inputFirewall.SourceCIDRs)
 		if allPortsMatch {
 			// All the ports match so it may be that just a CIDR needs to be removed.
 			cidrs := set.NewStrings(existingFirewall.SourceCIDRs...)
@@ -170,6 +170,70 @@ func (s *connSuite) TestConnectionOpenPortsAdd(c *gc.C) {
 	})
 }
 
+func (s *connSuite) TestConnectionOpenPortsIPv6(c *gc.C) {
+	s.FakeConn.Err = errors.NotFoundf("spam")
+
+	rule := network.MustNewIngressRule("tcp", 80, 81, "10.0.0.0/24", "2001:db8::/64")
+	err := s.Conn.OpenPortsWithNamer("spam", google.HashSuffixNamer, rule)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// IPv4 and IPv6 source ranges cannot be mixed
+	// in a firewall, so each family gets its own.
+	c.Check(s.FakeConn.Calls, gc.HasLen, 3)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetFirewalls")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "AddFirewall")
+	c.Check(s.FakeConn.Calls[1].Firewall, jc.DeepEquals, &compute.Firewall{
+		Name:         "spam-a34d80f7b6",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"10.0.0.0/24"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"80-81"},
+		}},
+	})
+	c.Check(s.FakeConn.Calls[2].FuncName, gc.Equals, "AddFirewall")
+	c.Check(s.FakeConn.Calls[2].Firewall, jc.DeepEquals, &compute.Firewall{
+		Name:         "spam-b22e912162",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"2001:db8::/64"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"80-81"},
+		}},
+	})
+}
+
+func (s *connSuite) TestConnectionOpenPortsIPv6SamePorts(c *gc.C) {
+	s.FakeConn.Firewalls = []*compute.Firewall{{
+		Name:         "spam",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"0.0.0.0/0"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"80-81"},
+		}},
+	}}
+
+	// The IPv6 source range is not merged into the
+	// IPv4 firewall that allows the same ports.
+	rule := network.MustNewIngressRule("tcp", 80, 81, "::/0")
+	err := s.Conn.OpenPortsWithNamer("spam", google.HashSuffixNamer, rule)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetFirewalls")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "AddFirewall")
+	c.Check(s.FakeConn.Calls[1].Firewall, jc.DeepEquals, &compute.Firewall{
+		Name:         "spam-6d0e7095bc",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"::/0"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"80-81"},
+		}},
+	})
+}
+
 func (s *connSuite) TestConnectionOpenPortsUpdateSameCIDR(c *gc.C) {
 	s.FakeConn.Firewalls = []*compute.Firewall{{
 		Name:         "spam-ad7554",
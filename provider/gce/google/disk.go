@@ -111,6 +111,10 @@ type DiskSpec struct {
 	// Labels holds labels/metadata for the disk. Labels are used for
 	// storing volume resource tags.
 	Labels map[string]string
+	// KMSKeyName is the resource name of the Cloud KMS key used to
+	// encrypt the disk. If empty, the disk is encrypted with a key
+	// managed by Google.
+	KMSKeyName string
 }
 
 // TooSmall checks the spec's size hint and indicates whether or not
@@ -172,13 +176,19 @@ func (ds *DiskSpec) newDetached() (*compute.Disk, error) {
 	if ds.PersistentDiskType == DiskLocalSSD {
 		return nil, errors.New("cannot create local ssd disks detached")
 	}
-	return &compute.Disk{
+	disk := &compute.Disk{
 		Name:        ds.Name,
 		SizeGb:      int64(ds.SizeGB()),
 		SourceImage: ds.ImageURL,
 		Type:        string(ds.PersistentDiskType),
 		Labels:      ds.Labels,
-	}, nil
+	}
+	if ds.KMSKeyName != "" {
+		disk.DiskEncryptionKey = &compute.CustomerEncryptionKey{
+			KmsKeyName: ds.KMSKeyName,
+		}
+	}
+	return disk, nil
 }
 
 // AttachedDisk represents a disk that is attached to an instance.
@@ -292,6 +292,18 @@ func (rc *rawConn) SetDiskLabels(project, zone, id, labelFingerprint string, lab
 	return errors.Trace(err)
 }
 
+func (rc *rawConn) ResizeDisk(project, zone, id string, sizeGb int64) error {
+	ds := rc.Service.Disks
+	call := ds.Resize(project, zone, id, &compute.DisksResizeRequest{
+		SizeGb: sizeGb,
+	})
+	op, err := call.Do()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(rc.waitOperation(project, op, attemptsLong, logOperationErrors))
+}
+
 func (rc *rawConn) AttachDisk(project, zone, instanceId string, disk *compute.AttachedDisk) error {
 	call := rc.Instances.AttachDisk(project, zone, instanceId, disk)
 	_, err := call.Do() // Perhaps return something from the Op
@@ -425,6 +437,16 @@ func (rc *rawConn) SetMetadata(projectID, zone, instanceID string, metadata *com
 	return errors.Trace(err)
 }
 
+func (rc *rawConn) SetDeletionProtection(projectID, zone, instanceID string, protected bool) error {
+	call := rc.Instances.SetDeletionProtection(projectID, zone, instanceID).DeletionProtection(protected)
+	op, err := call.Do()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = rc.waitOperation(projectID, op, attemptsLong, logOperationErrors)
+	return errors.Trace(err)
+}
+
 func (rc *rawConn) ListSubnetworks(projectID, region string) ([]*compute.Subnetwork, error) {
 	ctx := context.Background()
 	call := rc.Subnetworks.List(projectID, region)
@@ -6,6 +6,7 @@ package google
 import (
 	"crypto/sha256"
 	"fmt"
+	"net"
 	"sort"
 	"strings"
 
@@ -35,6 +36,24 @@ func (rs ruleSet) addRule(rule network.IngressRule) {
 	if len(sourceCIDRs) == 0 {
 		sourceCIDRs = []string{"0.0.0.0/0"}
 	}
+	// GCE firewalls cannot mix IPv4 and IPv6 source ranges, so
+	// the source ranges of each family get their own firewall.
+	var ipv4CIDRs, ipv6CIDRs []string
+	for _, cidr := range sourceCIDRs {
+		if sourcecidrs([]string{cidr}).ipv6() {
+			ipv6CIDRs = append(ipv6CIDRs, cidr)
+		} else {
+			ipv4CIDRs = append(ipv4CIDRs, cidr)
+		}
+	}
+	for _, cidrs := range [][]string{ipv4CIDRs, ipv6CIDRs} {
+		if len(cidrs) > 0 {
+			rs.addPortRange(cidrs, rule.PortRange)
+		}
+	}
+}
+
+func (rs ruleSet) addPortRange(sourceCIDRs []string, portRange corenetwork.PortRange) {
 	key := sourcecidrs(sourceCIDRs).key()
 	fw, ok := rs[key]
 	if !ok {
@@ -45,7 +64,7 @@ func (rs ruleSet) addRule(rule network.IngressRule) {
 		rs[key] = fw
 	}
 	ports := fw.AllowedPorts
-	ports[rule.Protocol] = append(ports[rule.Protocol], rule.PortRange)
+	ports[portRange.Protocol] = append(ports[portRange.Protocol], portRange)
 }
 
 func newRuleSetFromFirewalls(firewalls ...*compute.Firewall) (ruleSet, error) {
@@ -107,8 +126,14 @@ func (rs ruleSet) addFirewall(fw *compute.Firewall) error {
 	return nil
 }
 
-func (rs ruleSet) matchProtocolPorts(ports protocolPorts) (*firewall, bool) {
+// matchProtocolPorts returns a firewall allowing exactly the given
+// ports from source ranges of the same family as the given ones.
+func (rs ruleSet) matchProtocolPorts(ports protocolPorts, cidrs []string) (*firewall, bool) {
+	ipv6 := sourcecidrs(cidrs).ipv6()
 	for _, fw := range rs {
+		if sourcecidrs(fw.SourceCIDRs).ipv6() != ipv6 {
+			continue
+		}
 		if fw.AllowedPorts.String() == ports.String() {
 			return fw, true
 		}
@@ -159,6 +184,16 @@ func (s sourcecidrs) key() string {
 	return hashStr[:10]
 }
 
+// ipv6 reports whether the source CIDRs are IPv6 address blocks.
+// The source CIDRs of a firewall are all of the same family.
+func (s sourcecidrs) ipv6() bool {
+	if len(s) == 0 {
+		return false
+	}
+	ip, _, err := net.ParseCIDR(s[0])
+	return err == nil && ip.To4() == nil
+}
+
 func (s sourcecidrs) sorted() []string {
 	values := make([]string, len(s))
 	copy(values, s)
@@ -148,7 +148,7 @@ func (s *RuleSetSuite) TestMatchPorts(c *gc.C) {
 	ruleset := makeRuleSet()
 	fw, ok := ruleset.matchProtocolPorts(protocolPorts{
 		"udp": {{5123, 8099, "udp"}},
-	})
+	}, []string{"10.0.0.0/8"})
 	c.Assert(ok, jc.IsTrue)
 	c.Assert(fw, gc.DeepEquals, &firewall{
 		AllowedPorts: protocolPorts{
@@ -159,7 +159,13 @@ func (s *RuleSetSuite) TestMatchPorts(c *gc.C) {
 	// No partial matches.
 	fw, ok = ruleset.matchProtocolPorts(protocolPorts{
 		"tcp": {{80, 80, "tcp"}},
-	})
+	}, []string{"0.0.0.0/0"})
+	c.Assert(ok, jc.IsFalse)
+	c.Assert(fw, gc.IsNil)
+	// No matches with source ranges of another family.
+	fw, ok = ruleset.matchProtocolPorts(protocolPorts{
+		"udp": {{5123, 8099, "udp"}},
+	}, []string{"::/0"})
 	c.Assert(ok, jc.IsFalse)
 	c.Assert(fw, gc.IsNil)
 }
@@ -168,6 +168,8 @@ type fakeCall struct {
 	Metadata         *compute.Metadata
 	LabelFingerprint string
 	Labels           map[string]string
+	Protected        bool
+	SizeGb           int64
 }
 
 type fakeConn struct {
@@ -404,6 +406,23 @@ func (rc *fakeConn) GetDisk(project, zone, id string) (*compute.Disk, error) {
 	return rc.Disk, err
 }
 
+func (rc *fakeConn) ResizeDisk(project, zone, id string, sizeGb int64) error {
+	call := fakeCall{
+		FuncName:  "ResizeDisk",
+		ProjectID: project,
+		ZoneName:  zone,
+		ID:        id,
+		SizeGb:    sizeGb,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return err
+}
+
 func (rc *fakeConn) SetDiskLabels(project, zone, id, labelFingerprint string, labels map[string]string) error {
 	call := fakeCall{
 		FuncName:         "SetDiskLabels",
@@ -506,6 +525,23 @@ func (rc *fakeConn) SetMetadata(projectID, zone, instanceID string, metadata *co
 	return err
 }
 
+func (rc *fakeConn) SetDeletionProtection(projectID, zone, instanceID string, protected bool) error {
+	call := fakeCall{
+		FuncName:   "SetDeletionProtection",
+		ProjectID:  projectID,
+		ZoneName:   zone,
+		InstanceId: instanceID,
+		Protected:  protected,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return err
+}
+
 func (rc *fakeConn) ListNetworks(projectID string) ([]*compute.Network, error) {
 	call := fakeCall{
 		FuncName:  "ListNetworks",
@@ -0,0 +1,54 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gce
+
+import (
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/provider/gce/google"
+)
+
+var _ environs.ResourceTagUpdater = (*environ)(nil)
+
+// UpdateResourceTags is part of the environs.ResourceTagUpdater interface.
+// The tags are kept in the metadata of the model's instances. Disks are
+// only labelled with the model and controller UUIDs, as GCE labels cannot
+// hold arbitrary values, and firewall rules cannot be labelled at all.
+func (env *environ) UpdateResourceTags(ctx context.ProviderCallContext, tags map[string]string, removed []string) error {
+	insts, err := env.AllInstances(ctx)
+	if err != nil {
+		return errors.Annotate(err, "all instances")
+	}
+	ids := make([]string, len(insts))
+	for i, inst := range insts {
+		ids[i] = string(inst.Id())
+	}
+	err = env.gce.UpdateMetadataItems(tags, removed, ids...)
+	if err != nil {
+		return google.HandleCredentialError(errors.Trace(err), ctx)
+	}
+	return nil
+}
+
+// ResourceTagKeys is part of the environs.ResourceTagUpdater interface.
+func (env *environ) ResourceTagKeys(ctx context.ProviderCallContext, prefix string) ([]string, error) {
+	insts, err := env.AllInstances(ctx)
+	if err != nil {
+		return nil, errors.Annotate(err, "all instances")
+	}
+	keys := set.NewStrings()
+	for _, inst := range insts {
+		for key := range inst.(*environInstance).base.Metadata() {
+			if strings.HasPrefix(key, prefix) {
+				keys.Add(key)
+			}
+		}
+	}
+	return keys.SortedValues(), nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gce_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/instances"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/provider/gce"
+)
+
+type tagsSuite struct {
+	gce.BaseSuite
+}
+
+var _ = gc.Suite(&tagsSuite{})
+
+func (s *tagsSuite) TestUpdateResourceTags(c *gc.C) {
+	john := s.NewInstance(c, "john")
+	misty := s.NewInstance(c, "misty")
+	s.FakeEnviron.Insts = []instances.Instance{john, misty}
+
+	err := s.Env.UpdateResourceTags(s.CallCtx, map[string]string{"owner": "fred"}, []string{"cost-centre"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 1)
+	call := s.FakeConn.Calls[0]
+	c.Check(call.FuncName, gc.Equals, "UpdateMetadataItems")
+	c.Check(call.IDs, gc.DeepEquals, []string{"john", "misty"})
+	c.Check(call.Items, gc.DeepEquals, map[string]string{"owner": "fred"})
+	c.Check(call.Removed, gc.DeepEquals, []string{"cost-centre"})
+}
+
+func (s *tagsSuite) TestUpdateResourceTagsInvalidCredentialError(c *gc.C) {
+	s.FakeConn.Err = gce.InvalidCredentialError
+	c.Assert(s.InvalidatedCredentials, jc.IsFalse)
+	john := s.NewInstance(c, "john")
+	s.FakeEnviron.Insts = []instances.Instance{john}
+
+	err := s.Env.UpdateResourceTags(s.CallCtx, map[string]string{"owner": "fred"}, nil)
+	c.Check(err, gc.NotNil)
+	c.Assert(s.InvalidatedCredentials, jc.IsTrue)
+}
+
+func (s *tagsSuite) TestResourceTagKeys(c *gc.C) {
+	john := s.NewInstance(c, "john")
+	misty := s.NewInstance(c, "misty")
+	s.FakeEnviron.Insts = []instances.Instance{john, misty}
+
+	keys, err := s.Env.ResourceTagKeys(s.CallCtx, tags.JujuController)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(keys, jc.DeepEquals, []string{tags.JujuController})
+
+	keys, err = s.Env.ResourceTagKeys(s.CallCtx, tags.JujuResourceTagPrefix)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(keys, gc.HasLen, 0)
+}
@@ -500,8 +500,12 @@ type fakeConnCall struct {
 	Mode             string
 	Key              string
 	Value            string
+	Items            map[string]string
+	Removed          []string
 	LabelFingerprint string
 	Labels           map[string]string
+	Protected        bool
+	SizeGb           int64
 }
 
 type fakeConn struct {
@@ -582,6 +586,25 @@ func (fc *fakeConn) UpdateMetadata(key, value string, ids ...string) error {
 	return fc.err()
 }
 
+func (fc *fakeConn) UpdateMetadataItems(items map[string]string, removed []string, ids ...string) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "UpdateMetadataItems",
+		Items:    items,
+		Removed:  removed,
+		IDs:      ids,
+	})
+	return fc.err()
+}
+
+func (fc *fakeConn) SetDeletionProtection(protected bool, ids ...string) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName:  "SetDeletionProtection",
+		Protected: protected,
+		IDs:       ids,
+	})
+	return fc.err()
+}
+
 func (fc *fakeConn) IngressRules(fwname string) ([]network.IngressRule, error) {
 	fc.Calls = append(fc.Calls, fakeConnCall{
 		FuncName:     "Ports",
@@ -676,6 +699,16 @@ func (fc *fakeConn) SetDiskLabels(zone, id, labelFingerprint string, labels map[
 	return fc.err()
 }
 
+func (fc *fakeConn) ResizeDisk(zone, id string, sizeGb int64) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "ResizeDisk",
+		ZoneName: zone,
+		ID:       id,
+		SizeGb:   sizeGb,
+	})
+	return fc.err()
+}
+
 func (fc *fakeConn) AttachDisk(zone, volumeName, instanceId string, mode google.DiskMode) (*google.AttachedDisk, error) {
 	fc.Calls = append(fc.Calls, fakeConnCall{
 		FuncName:   "AttachDisk",
@@ -49,6 +49,9 @@ var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.Spot,
+	constraints.MaxPrice,
+	constraints.AntiAffinity,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
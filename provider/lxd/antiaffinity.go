@@ -0,0 +1,103 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/provider/common"
+)
+
+// antiAffinityKey is the container metadata key recording the
+// anti-affinity group of the machine a container realises.
+const antiAffinityKey = "juju-anti-affinity"
+
+// antiAffinityTarget returns the name of the cluster member on which to
+// start a machine with an anti-affinity constraint. The member must not
+// host any other machine in the same group.
+//
+// The member requested by placement is used if there is one, and then
+// the availability zone chosen by the provisioner. If that member is not
+// suitable, a zone-specific error is returned so that the provisioner
+// tries another.
+//
+// The chosen member is reserved for the group until the returned release
+// function is called, so that machines in the same group which are
+// started concurrently are not placed together before their containers
+// exist.
+func (env *environ) antiAffinityTarget(args environs.StartInstanceParams, nodeName string) (string, func(), error) {
+	group := *args.Constraints.AntiAffinity
+	env.antiAffinityMutex.Lock()
+	defer env.antiAffinityMutex.Unlock()
+
+	insts, err := env.allInstances()
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	occupied := set.NewStrings(env.antiAffinityPending[group].Values()...)
+	for _, inst := range insts {
+		if inst.container.Metadata(antiAffinityKey) == group {
+			occupied.Add(inst.container.Location)
+		}
+	}
+
+	server := env.server()
+	if !server.IsClustered() {
+		if !occupied.IsEmpty() {
+			return "", nil, common.ZoneIndependentError(errors.Errorf(
+				"anti-affinity group %q already has a machine on LXD server %q, which is not clustered",
+				group, server.Name(),
+			))
+		}
+		return nodeName, env.reserveAntiAffinityMember(group, nodeName), nil
+	}
+
+	if nodeName == "" {
+		nodeName = args.AvailabilityZone
+	}
+	if nodeName != "" {
+		if occupied.Contains(nodeName) {
+			return "", nil, errors.Errorf("anti-affinity group %q already has a machine on cluster member %q", group, nodeName)
+		}
+		return nodeName, env.reserveAntiAffinityMember(group, nodeName), nil
+	}
+
+	members, err := server.GetClusterMembers()
+	if err != nil {
+		return "", nil, errors.Annotate(err, "listing cluster members")
+	}
+	for _, member := range members {
+		if strings.ToLower(member.Status) == "online" && !occupied.Contains(member.ServerName) {
+			return member.ServerName, env.reserveAntiAffinityMember(group, member.ServerName), nil
+		}
+	}
+	return "", nil, common.ZoneIndependentError(errors.Errorf(
+		"no cluster member is available for another machine in anti-affinity group %q", group,
+	))
+}
+
+// reserveAntiAffinityMember records that a machine in the anti-affinity
+// group is being started on the member, returning a function which
+// removes the reservation. It must be called with antiAffinityMutex held.
+func (env *environ) reserveAntiAffinityMember(group, member string) func() {
+	if env.antiAffinityPending == nil {
+		env.antiAffinityPending = make(map[string]set.Strings)
+	}
+	if env.antiAffinityPending[group] == nil {
+		env.antiAffinityPending[group] = set.NewStrings()
+	}
+	env.antiAffinityPending[group].Add(member)
+	return func() {
+		env.antiAffinityMutex.Lock()
+		defer env.antiAffinityMutex.Unlock()
+		env.antiAffinityPending[group].Remove(member)
+		if env.antiAffinityPending[group].IsEmpty() {
+			delete(env.antiAffinityPending, group)
+		}
+	}
+}
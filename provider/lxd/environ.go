@@ -7,6 +7,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/lxc/lxd/shared/api"
 	"gopkg.in/juju/charm.v6"
@@ -48,6 +49,12 @@ type environ struct {
 
 	// profileMutex is used when writing profiles via the server.
 	profileMutex sync.Mutex
+
+	// antiAffinityMutex protects antiAffinityPending, which holds the
+	// cluster members on which machines are being started, keyed by
+	// their anti-affinity group.
+	antiAffinityMutex   sync.Mutex
+	antiAffinityPending map[string]set.Strings
 }
 
 func newEnviron(
@@ -115,10 +115,15 @@ func (env *environ) newContainer(
 	}
 	defer cleanupCallback()
 
-	target, err := env.getTargetServer(ctx, args)
+	target, release, err := env.getTargetServer(ctx, args)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	defer release()
+
+	if lxd.IsVirtualMachine(args.Constraints) {
+		return env.newVirtualMachine(target, args, arch, imageSources, statusCallback)
+	}
 
 	image, err := target.FindImage(args.InstanceConfig.Series, arch, imageSources, true, statusCallback)
 	if err != nil {
@@ -140,6 +145,40 @@ func (env *environ) newContainer(
 	return container, nil
 }
 
+// newVirtualMachine provisions a virtual machine rather than a container
+// on the target server. The server downloads the virtual machine image
+// itself while creating the virtual machine.
+func (env *environ) newVirtualMachine(
+	target Server,
+	args environs.StartInstanceParams,
+	arch string,
+	imageSources []lxd.ServerSpec,
+	statusCallback environs.StatusCallbackFunc,
+) (*lxd.Container, error) {
+	if !target.VirtualMachinesSupported() {
+		return nil, common.ZoneIndependentError(errors.NotSupportedf(
+			"virtual machines on LXD server version %s", target.ServerVersion()))
+	}
+	image, err := lxd.FindVirtualMachineImage(args.InstanceConfig.Series, arch, imageSources)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	cSpec, err := env.getContainerSpec(lxd.SourcedImage{}, target.ServerVersion(), args)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cSpec.VMImage = image
+
+	statusCallback(status.Allocating, "Creating virtual machine", nil)
+	container, err := target.CreateContainerFromSpec(cSpec)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	statusCallback(status.Running, "Virtual machine started", nil)
+	return container, nil
+}
+
 func (env *environ) getImageSources() ([]lxd.ServerSpec, error) {
 	metadataSources, err := environs.ImageMetadataSources(env)
 	if err != nil {
@@ -238,25 +277,43 @@ func (env *environ) getContainerSpec(
 		}
 		cSpec.Config[lxd.UserNamespacePrefix+k] = v
 	}
+	if args.Constraints.HasAntiAffinity() {
+		cSpec.Config[lxd.UserNamespacePrefix+antiAffinityKey] = *args.Constraints.AntiAffinity
+	}
 
 	return cSpec, nil
 }
 
 // getTargetServer checks to see if a valid zone was passed as a placement
 // directive in the start-up start-up arguments. If so, a server for the
-// specific node is returned.
+// specific node is returned. Machines with an anti-affinity constraint
+// are always targeted at a node outside of their group; the node stays
+// reserved for the group until the returned release function is called.
 func (env *environ) getTargetServer(
 	ctx context.ProviderCallContext, args environs.StartInstanceParams,
-) (Server, error) {
+) (Server, func(), error) {
 	p, err := env.parsePlacement(ctx, args.Placement)
 	if err != nil {
-		return nil, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
 
-	if p.nodeName == "" {
-		return env.server(), nil
+	nodeName := p.nodeName
+	release := func() {}
+	if args.Constraints.HasAntiAffinity() {
+		if nodeName, release, err = env.antiAffinityTarget(args, nodeName); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+
+	if nodeName == "" {
+		return env.server(), release, nil
+	}
+	target, err := env.server().UseTargetServer(nodeName)
+	if err != nil {
+		release()
+		return nil, nil, errors.Trace(err)
 	}
-	return env.server().UseTargetServer(p.nodeName)
+	return target, release, nil
 }
 
 type lxdPlacement struct {
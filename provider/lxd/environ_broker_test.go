@@ -17,6 +17,8 @@ import (
 	containerlxd "github.com/juju/juju/container/lxd"
 	lxdtesting "github.com/juju/juju/container/lxd/testing"
 	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/context"
 	"github.com/juju/juju/provider/lxd"
 )
@@ -102,6 +104,56 @@ func (s *environBrokerSuite) TestStartInstanceDefaultNIC(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *environBrokerSuite) TestStartInstanceVirtualMachine(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	svr := lxd.NewMockServer(ctrl)
+
+	check := func(spec containerlxd.ContainerSpec) bool {
+		return spec.VirtType == containerlxd.VirtTypeVirtualMachine &&
+			spec.VMImage.Alias == "bionic/amd64" &&
+			spec.RootDiskMiB == 10240
+	}
+
+	exp := svr.EXPECT()
+	gomock.InOrder(
+		exp.HostArch().Return(arch.AMD64),
+		exp.VirtualMachinesSupported().Return(true),
+		exp.ServerVersion().Return("3.19"),
+		exp.GetNICsFromProfile("default").Return(s.defaultProfile.Devices, nil),
+		exp.CreateContainerFromSpec(matchesContainerSpec(check)).Return(&containerlxd.Container{}, nil),
+		exp.HostArch().Return(arch.AMD64),
+	)
+
+	args := s.GetStartInstanceArgs(c, "bionic")
+	args.Constraints = constraints.MustParse("virt-type=virtual-machine root-disk=10G")
+
+	env := s.NewEnviron(c, svr, nil)
+	_, err := env.StartInstance(s.callCtx, args)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *environBrokerSuite) TestStartInstanceVirtualMachineNotSupported(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	svr := lxd.NewMockServer(ctrl)
+
+	exp := svr.EXPECT()
+	gomock.InOrder(
+		exp.HostArch().Return(arch.AMD64),
+		exp.VirtualMachinesSupported().Return(false),
+		exp.ServerVersion().Return("3.0.3"),
+	)
+
+	args := s.GetStartInstanceArgs(c, "bionic")
+	args.Constraints = constraints.MustParse("virt-type=virtual-machine")
+
+	env := s.NewEnviron(c, svr, nil)
+	_, err := env.StartInstance(s.callCtx, args)
+	c.Assert(err, gc.ErrorMatches, "virtual machines on LXD server version 3.0.3 not supported")
+	c.Check(environs.IsAvailabilityZoneIndependent(err), jc.IsTrue)
+}
+
 func (s *environBrokerSuite) TestStartInstanceNonDefaultNIC(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
@@ -271,6 +323,126 @@ func (s *environBrokerSuite) TestStartInstanceWithPlacementBadArgument(c *gc.C)
 	c.Assert(err, gc.ErrorMatches, "unknown placement directive.*")
 }
 
+func antiAffinityContainer(name, location, group string) containerlxd.Container {
+	return containerlxd.Container{Container: api.Container{
+		Name:     name,
+		Location: location,
+		ContainerPut: api.ContainerPut{
+			Config: map[string]string{"user.juju-anti-affinity": group},
+		},
+	}}
+}
+
+func (s *environBrokerSuite) TestStartInstanceAntiAffinity(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	svr := lxd.NewMockServer(ctrl)
+
+	// Check that the anti-affinity group is recorded on the container.
+	check := func(spec containerlxd.ContainerSpec) bool {
+		return spec.Config["user.juju-anti-affinity"] == "db"
+	}
+
+	existing := []containerlxd.Container{antiAffinityContainer("juju-model-machine-1", "none", "web")}
+	exp := svr.EXPECT()
+	gomock.InOrder(
+		exp.HostArch().Return(arch.AMD64),
+		exp.AliveContainers(gomock.Any()).Return(existing, nil),
+		exp.IsClustered().Return(false),
+		exp.FindImage("bionic", arch.AMD64, gomock.Any(), true, gomock.Any()).Return(containerlxd.SourcedImage{}, nil),
+		exp.ServerVersion().Return("3.10.0"),
+		exp.GetNICsFromProfile("default").Return(s.defaultProfile.Devices, nil),
+		exp.CreateContainerFromSpec(matchesContainerSpec(check)).Return(&containerlxd.Container{}, nil),
+		exp.HostArch().Return(arch.AMD64),
+	)
+
+	args := s.GetStartInstanceArgs(c, "bionic")
+	args.Constraints = constraints.MustParse("anti-affinity=db")
+
+	env := s.NewEnviron(c, svr, nil)
+	_, err := env.StartInstance(s.callCtx, args)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *environBrokerSuite) TestStartInstanceAntiAffinityNotClustered(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	svr := lxd.NewMockServer(ctrl)
+
+	existing := []containerlxd.Container{antiAffinityContainer("juju-model-machine-1", "none", "db")}
+	exp := svr.EXPECT()
+	gomock.InOrder(
+		exp.HostArch().Return(arch.AMD64),
+		exp.AliveContainers(gomock.Any()).Return(existing, nil),
+		exp.IsClustered().Return(false),
+		exp.Name().Return("server"),
+	)
+
+	args := s.GetStartInstanceArgs(c, "bionic")
+	args.Constraints = constraints.MustParse("anti-affinity=db")
+
+	env := s.NewEnviron(c, svr, nil)
+	_, err := env.StartInstance(s.callCtx, args)
+	c.Assert(err, gc.ErrorMatches, `anti-affinity group "db" already has a machine on LXD server "server", which is not clustered`)
+	c.Assert(environs.IsAvailabilityZoneIndependent(err), jc.IsTrue)
+}
+
+func (s *environBrokerSuite) TestStartInstanceAntiAffinityConcurrent(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	svr := lxd.NewMockServer(ctrl)
+
+	args := s.GetStartInstanceArgs(c, "bionic")
+	args.Constraints = constraints.MustParse("anti-affinity=db")
+	env := s.NewEnviron(c, svr, nil)
+
+	// A machine in the same group started while the first container is
+	// being created must not be placed on the same server.
+	var concurrentErr error
+	exp := svr.EXPECT()
+	exp.HostArch().Return(arch.AMD64).Times(3)
+	exp.AliveContainers(gomock.Any()).Return(nil, nil).Times(2)
+	exp.IsClustered().Return(false).Times(2)
+	exp.Name().Return("server")
+	exp.FindImage("bionic", arch.AMD64, gomock.Any(), true, gomock.Any()).Return(containerlxd.SourcedImage{}, nil)
+	exp.ServerVersion().Return("3.10.0")
+	exp.GetNICsFromProfile("default").Return(s.defaultProfile.Devices, nil)
+	exp.CreateContainerFromSpec(gomock.Any()).DoAndReturn(
+		func(containerlxd.ContainerSpec) (*containerlxd.Container, error) {
+			_, concurrentErr = env.StartInstance(s.callCtx, args)
+			return &containerlxd.Container{}, nil
+		})
+
+	_, err := env.StartInstance(s.callCtx, args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(concurrentErr, gc.ErrorMatches, `anti-affinity group "db" already has a machine on LXD server "server", which is not clustered`)
+}
+
+func (s *environBrokerSuite) TestStartInstanceAntiAffinityZoneOccupied(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	svr := lxd.NewMockServer(ctrl)
+
+	existing := []containerlxd.Container{antiAffinityContainer("juju-model-machine-1", "node01", "db")}
+	exp := svr.EXPECT()
+	gomock.InOrder(
+		exp.HostArch().Return(arch.AMD64),
+		exp.AliveContainers(gomock.Any()).Return(existing, nil),
+		exp.IsClustered().Return(true),
+	)
+
+	args := s.GetStartInstanceArgs(c, "bionic")
+	args.Constraints = constraints.MustParse("anti-affinity=db")
+	args.AvailabilityZone = "node01"
+
+	env := s.NewEnviron(c, svr, nil)
+	_, err := env.StartInstance(s.callCtx, args)
+	c.Assert(err, gc.ErrorMatches, `anti-affinity group "db" already has a machine on cluster member "node01"`)
+
+	// The provisioner should try another cluster member.
+	c.Assert(environs.IsAvailabilityZoneIndependent(err), jc.IsFalse)
+}
+
 func (s *environBrokerSuite) TestStartInstanceWithConstraints(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
@@ -507,3 +679,68 @@ func (s *environBrokerSuite) checkSources(c *gc.C, sources []containerlxd.Server
 	}
 	c.Check(sourceURLs, gc.DeepEquals, expectedURLs)
 }
+
+func (s *environBrokerSuite) TestResizeInstance(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	svr := lxd.NewMockServer(ctrl)
+
+	exp := svr.EXPECT()
+	exp.ServerVersion().Return("3.10.0")
+	exp.UpdateContainerConfig("juju-f75cba-1", map[string]string{
+		"limits.cpu":    "4",
+		"limits.memory": "16384MiB",
+	}).Return(nil)
+
+	env := s.NewEnviron(c, svr, nil).(environs.InstanceResizer)
+	hc, err := env.ResizeInstance(s.callCtx, "juju-f75cba-1", constraints.MustParse("arch=amd64 cores=4 mem=16G"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(*hc, jc.DeepEquals, instance.MustParseHardware("cores=4 mem=16G"))
+}
+
+func (s *environBrokerSuite) TestResizeInstanceVirtualMachine(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	svr := lxd.NewMockServer(ctrl)
+
+	cfg := map[string]string{
+		"limits.cpu":    "4",
+		"limits.memory": "16384MiB",
+	}
+	exp := svr.EXPECT()
+	exp.ServerVersion().Return("3.19")
+	exp.UpdateContainerConfig("juju-f75cba-1", cfg).Return(fmt.Errorf("not found"))
+	exp.VirtualMachinesSupported().Return(true)
+	exp.UpdateVirtualMachineConfig("juju-f75cba-1", cfg).Return(nil)
+
+	env := s.NewEnviron(c, svr, nil).(environs.InstanceResizer)
+	hc, err := env.ResizeInstance(s.callCtx, "juju-f75cba-1", constraints.MustParse("cores=4 mem=16G"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(*hc, jc.DeepEquals, instance.MustParseHardware("cores=4 mem=16G"))
+}
+
+func (s *environBrokerSuite) TestResizeInstanceNoLimits(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	svr := lxd.NewMockServer(ctrl)
+
+	env := s.NewEnviron(c, svr, nil).(environs.InstanceResizer)
+	_, err := env.ResizeInstance(s.callCtx, "juju-f75cba-1", constraints.MustParse("root-disk=20G"))
+	c.Assert(err, gc.ErrorMatches, "resizing LXD instances without cores or mem constraints not supported")
+}
+
+func (s *environBrokerSuite) TestResizeInstanceInvalidCredentials(c *gc.C) {
+	c.Assert(s.invalidCredential, jc.IsFalse)
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	svr := lxd.NewMockServer(ctrl)
+
+	exp := svr.EXPECT()
+	exp.ServerVersion().Return("3.10.0")
+	exp.UpdateContainerConfig("juju-f75cba-1", gomock.Any()).Return(fmt.Errorf("not authorized"))
+
+	env := s.NewEnviron(c, svr, nil).(environs.InstanceResizer)
+	_, err := env.ResizeInstance(s.callCtx, "juju-f75cba-1", constraints.MustParse("mem=16G"))
+	c.Assert(err, gc.ErrorMatches, "not authorized")
+	c.Assert(s.invalidCredential, jc.IsTrue)
+}
@@ -170,7 +170,6 @@ func (s *environPolicySuite) TestConstraintsValidatorUnsupported(c *gc.C) {
 		"instance-type=some-type",
 		"cores=2",
 		"cpu-power=250",
-		"virt-type=kvm",
 	}, " "))
 	unsupported, err := validator.Validate(cons)
 	c.Assert(err, jc.ErrorIsNil)
@@ -178,7 +177,6 @@ func (s *environPolicySuite) TestConstraintsValidatorUnsupported(c *gc.C) {
 	expected := []string{
 		"tags",
 		"cpu-power",
-		"virt-type",
 	}
 	c.Check(unsupported, jc.SameContents, expected)
 }
@@ -221,6 +219,26 @@ func (s *environPolicySuite) TestConstraintsValidatorVocabArchUnknown(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, "invalid constraint value: arch=ppc64el\nvalid values are: \\[amd64\\]")
 }
 
+func (s *environPolicySuite) TestConstraintsValidatorVocabVirtType(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	svr := lxd.NewMockServer(ctrl)
+
+	env := s.NewEnviron(c, svr, nil)
+
+	exp := svr.EXPECT()
+	exp.HostArch().Return(arch.AMD64)
+
+	validator, err := env.ConstraintsValidator(context.NewCloudCallContext())
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = validator.Validate(constraints.MustParse("virt-type=virtual-machine"))
+	c.Check(err, jc.ErrorIsNil)
+
+	_, err = validator.Validate(constraints.MustParse("virt-type=kvm"))
+	c.Check(err, gc.ErrorMatches, "invalid constraint value: virt-type=kvm\nvalid values are: \\[container virtual-machine\\]")
+}
+
 func (s *environPolicySuite) TestConstraintsValidatorVocabContainerUnknown(c *gc.C) {
 	c.Skip("this will fail until we add a container vocabulary")
 	ctrl := gomock.NewController(c)
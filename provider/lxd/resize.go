@@ -0,0 +1,46 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/container/lxd"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/provider/common"
+)
+
+var _ environs.InstanceResizer = (*environ)(nil)
+
+// ResizeInstance is part of the environs.InstanceResizer interface.
+// The CPU and memory limits of the instance are changed to match the
+// constraints. LXD applies them to a running container straight away,
+// but a virtual machine only picks them up as it boots, so a running
+// virtual machine is restarted.
+func (env *environ) ResizeInstance(ctx context.ProviderCallContext, id instance.Id, cons constraints.Value) (*instance.HardwareCharacteristics, error) {
+	if !cons.HasCpuCores() && !cons.HasMem() {
+		return nil, errors.NotSupportedf("resizing LXD instances without cores or mem constraints")
+	}
+	server := env.server()
+	spec := lxd.ContainerSpec{Config: make(map[string]string)}
+	spec.ApplyConstraints(server.ServerVersion(), constraints.Value{
+		CpuCores: cons.CpuCores,
+		Mem:      cons.Mem,
+	})
+	err := server.UpdateContainerConfig(string(id), spec.Config)
+	if lxd.IsLXDNotFound(errors.Cause(err)) && server.VirtualMachinesSupported() {
+		err = server.UpdateVirtualMachineConfig(string(id), spec.Config)
+	}
+	if err != nil {
+		common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
+		return nil, errors.Trace(err)
+	}
+	return &instance.HardwareCharacteristics{
+		CpuCores: cons.CpuCores,
+		Mem:      cons.Mem,
+	}, nil
+}
@@ -38,6 +38,7 @@ type Server interface {
 	GetConnectionInfo() (info *lxdclient.ConnectionInfo, err error)
 	UpdateServerConfig(map[string]string) error
 	UpdateContainerConfig(string, map[string]string) error
+	UpdateVirtualMachineConfig(string, map[string]string) error
 	CreateCertificate(lxdapi.CertificatesPost) error
 	GetCertificate(fingerprint string) (certificate *lxdapi.Certificate, ETag string, err error)
 	DeleteCertificate(fingerprint string) (err error)
@@ -61,6 +62,7 @@ type Server interface {
 	VerifyNetworkDevice(*lxdapi.Profile, string) error
 	EnsureDefaultStorage(*lxdapi.Profile, string) error
 	StorageSupported() bool
+	VirtualMachinesSupported() bool
 	GetStoragePool(name string) (pool *lxdapi.StoragePool, ETag string, err error)
 	GetStoragePools() (pools []lxdapi.StoragePool, err error)
 	CreatePool(name, driver string, attrs map[string]string) error
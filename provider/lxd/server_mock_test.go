@@ -568,6 +568,18 @@ func (mr *MockServerMockRecorder) UpdateStoragePoolVolume(arg0, arg1, arg2, arg3
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStoragePoolVolume", reflect.TypeOf((*MockServer)(nil).UpdateStoragePoolVolume), arg0, arg1, arg2, arg3, arg4)
 }
 
+// UpdateVirtualMachineConfig mocks base method
+func (m *MockServer) UpdateVirtualMachineConfig(arg0 string, arg1 map[string]string) error {
+	ret := m.ctrl.Call(m, "UpdateVirtualMachineConfig", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateVirtualMachineConfig indicates an expected call of UpdateVirtualMachineConfig
+func (mr *MockServerMockRecorder) UpdateVirtualMachineConfig(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVirtualMachineConfig", reflect.TypeOf((*MockServer)(nil).UpdateVirtualMachineConfig), arg0, arg1)
+}
+
 // UseTargetServer mocks base method
 func (m *MockServer) UseTargetServer(arg0 string) (*lxd.Server, error) {
 	ret := m.ctrl.Call(m, "UseTargetServer", arg0)
@@ -593,6 +605,18 @@ func (mr *MockServerMockRecorder) VerifyNetworkDevice(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyNetworkDevice", reflect.TypeOf((*MockServer)(nil).VerifyNetworkDevice), arg0, arg1)
 }
 
+// VirtualMachinesSupported mocks base method
+func (m *MockServer) VirtualMachinesSupported() bool {
+	ret := m.ctrl.Call(m, "VirtualMachinesSupported")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// VirtualMachinesSupported indicates an expected call of VirtualMachinesSupported
+func (mr *MockServerMockRecorder) VirtualMachinesSupported() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VirtualMachinesSupported", reflect.TypeOf((*MockServer)(nil).VirtualMachinesSupported))
+}
+
 // WriteContainer mocks base method
 func (m *MockServer) WriteContainer(arg0 *lxd.Container) error {
 	ret := m.ctrl.Call(m, "WriteContainer", arg0)
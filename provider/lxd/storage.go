@@ -136,6 +136,10 @@ func (e *lxdStorageProvider) ValidateConfig(cfg *storage.Config) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	// LXD storage pools have no encryption support.
+	if err := storage.ValidateNoEncryption(cfg.Attrs(), "LXD filesystems"); err != nil {
+		return errors.Trace(err)
+	}
 	return ensureLXDStoragePool(e.env, lxdStorageConfig)
 }
 
@@ -231,6 +235,8 @@ type lxdFilesystemSource struct {
 	env *environ
 }
 
+var _ storage.FilesystemResizer = (*lxdFilesystemSource)(nil)
+
 // CreateFilesystems is specified on the storage.FilesystemSource interface.
 func (s *lxdFilesystemSource) CreateFilesystems(ctx context.ProviderCallContext, args []storage.FilesystemParams) (_ []storage.CreateFilesystemsResult, err error) {
 	results := make([]storage.CreateFilesystemsResult, len(args))
@@ -406,6 +412,45 @@ func (s *lxdFilesystemSource) releaseFilesystem(filesystemId string) error {
 	return nil
 }
 
+// ResizeFilesystems is specified on the storage.FilesystemResizer interface.
+func (s *lxdFilesystemSource) ResizeFilesystems(ctx context.ProviderCallContext, args []storage.FilesystemResizeParams) ([]storage.ResizeResult, error) {
+	results := make([]storage.ResizeResult, len(args))
+	for i, arg := range args {
+		if err := s.resizeFilesystem(arg.FilesystemId, arg.Size); err != nil {
+			results[i].Error = err
+			common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
+			continue
+		}
+		results[i].Size = arg.Size
+	}
+	return results, nil
+}
+
+func (s *lxdFilesystemSource) resizeFilesystem(filesystemId string, size uint64) error {
+	poolName, volumeName, err := parseFilesystemId(filesystemId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	server := s.env.server()
+	volume, eTag, err := server.GetStoragePoolVolume(poolName, storagePoolVolumeType, volumeName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Volumes created with the "dir" driver have no size.
+	if _, ok := volume.Config["size"]; !ok {
+		return errors.NotSupportedf("resizing volume %q in pool %q", volumeName, poolName)
+	}
+	volume.Config["size"] = fmt.Sprintf("%dMiB", size)
+	if err := server.UpdateStoragePoolVolume(
+		poolName, storagePoolVolumeType, volumeName, volume.Writable(), eTag); err != nil {
+		return errors.Annotatef(
+			err, "resizing volume %q in pool %q",
+			volumeName, poolName,
+		)
+	}
+	return nil
+}
+
 // ValidateFilesystemParams is specified on the storage.FilesystemSource interface.
 func (s *lxdFilesystemSource) ValidateFilesystemParams(params storage.FilesystemParams) error {
 	// TODO(axw) sanity check params
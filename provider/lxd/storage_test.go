@@ -285,6 +285,50 @@ func (s *storageSuite) TestReleaseFilesystems(c *gc.C) {
 	})
 }
 
+func (s *storageSuite) TestResizeFilesystems(c *gc.C) {
+	s.Client.Volumes = map[string][]api.StorageVolume{
+		"foo": {{
+			Name: "filesystem-0",
+			StorageVolumePut: api.StorageVolumePut{
+				Config: map[string]string{
+					"size":                 "1024MiB",
+					"user.juju-model-uuid": "baz",
+				},
+			},
+		}, {
+			Name: "filesystem-1",
+			StorageVolumePut: api.StorageVolumePut{
+				Config: map[string]string{},
+			},
+		}},
+	}
+
+	source := s.filesystemSource(c, "source")
+	results, err := source.(storage.FilesystemResizer).ResizeFilesystems(s.callCtx, []storage.FilesystemResizeParams{{
+		FilesystemId: "foo:filesystem-0",
+		Size:         2048,
+	}, {
+		FilesystemId: "foo:filesystem-1",
+		Size:         2048,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0], jc.DeepEquals, storage.ResizeResult{Size: 2048})
+	c.Assert(results[1].Error, gc.ErrorMatches, `resizing volume "filesystem-1" in pool "foo" not supported`)
+
+	update0 := api.StorageVolumePut{
+		Config: map[string]string{
+			"size":                 "2048MiB",
+			"user.juju-model-uuid": "baz",
+		},
+	}
+	s.Stub.CheckCalls(c, []testing.StubCall{
+		{"GetStoragePoolVolume", []interface{}{"foo", "custom", "filesystem-0"}},
+		{"UpdateStoragePoolVolume", []interface{}{"foo", "custom", "filesystem-0", update0, "eTag"}},
+		{"GetStoragePoolVolume", []interface{}{"foo", "custom", "filesystem-1"}},
+	})
+}
+
 func (s *storageSuite) TestReleaseFilesystemsInvalidCredentials(c *gc.C) {
 	c.Assert(s.invalidCredential, jc.IsFalse)
 	s.Stub.SetErrors(errTestUnAuth)
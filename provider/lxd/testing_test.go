@@ -522,6 +522,11 @@ func (conn *StubClient) UpdateContainerConfig(container string, cfg map[string]s
 	return conn.NextErr()
 }
 
+func (conn *StubClient) UpdateVirtualMachineConfig(name string, cfg map[string]string) error {
+	conn.AddCall("UpdateVirtualMachineConfig", name, cfg)
+	return conn.NextErr()
+}
+
 func (conn *StubClient) LocalBridgeName() string {
 	conn.AddCall("LocalBridgeName")
 	return "test-bridge"
@@ -570,6 +575,11 @@ func (conn *StubClient) StorageSupported() bool {
 	return conn.StorageIsSupported
 }
 
+func (conn *StubClient) VirtualMachinesSupported() bool {
+	conn.AddCall("VirtualMachinesSupported")
+	return false
+}
+
 func (conn *StubClient) EnsureDefaultStorage(profile *api.Profile, ETag string) error {
 	conn.AddCall("EnsureDefaultStorage", profile, ETag)
 	return conn.NextErr()
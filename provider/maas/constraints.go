@@ -21,6 +21,9 @@ var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.InstanceType,
 	constraints.VirtType,
+	constraints.Spot,
+	constraints.MaxPrice,
+	constraints.AntiAffinity,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -64,9 +64,31 @@ type maasInterface struct {
 	Parents  []string `json:"parents"`
 	Children []string `json:"children"`
 
+	Params maasInterfaceParams `json:"params"`
+
 	ResourceURI string `json:"resource_uri"`
 }
 
+// maasInterfaceParams holds the type-specific parameters of an interface
+// that Juju is interested in.
+type maasInterfaceParams struct {
+	BondMode string `json:"bond_mode"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler. MAAS reports the params of
+// interfaces without any as an empty string rather than an object, so
+// anything other than an object is treated as empty.
+func (p *maasInterfaceParams) UnmarshalJSON(data []byte) error {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		*p = maasInterfaceParams{}
+		return nil
+	}
+	bondMode, _ := fields["bond_mode"].(string)
+	*p = maasInterfaceParams{BondMode: bondMode}
+	return nil
+}
+
 type maasVLAN struct {
 	ID          int    `json:"id"`
 	Name        string `json:"name"`
@@ -208,6 +230,7 @@ func maasObjectNetworkInterfaces(
 		// The below works for all types except bonds and their members.
 		parentName := strings.Join(iface.Parents, "")
 		var nicType network.InterfaceType
+		var bondMode string
 		switch iface.Type {
 		case typePhysical:
 			nicType = network.EthernetInterface
@@ -221,6 +244,7 @@ func maasObjectNetworkInterfaces(
 		case typeBond:
 			parentName = ""
 			nicType = network.BondInterface
+			bondMode = iface.Params.BondMode
 		case typeVLAN:
 			nicType = network.VLAN_8021QInterface
 		case typeBridge:
@@ -234,6 +258,7 @@ func maasObjectNetworkInterfaces(
 			VLANTag:             iface.VLAN.VID,
 			InterfaceName:       iface.Name,
 			InterfaceType:       nicType,
+			BondMode:            bondMode,
 			ParentInterfaceName: parentName,
 			Disabled:            !iface.Enabled,
 			NoAutoStart:         !iface.Enabled,
@@ -795,6 +795,39 @@ func (s *interfacesSuite) TestMAASObjectNetworkInterfaces(c *gc.C) {
 	c.Check(infos, jc.DeepEquals, exampleParsedInterfaceSetJSON)
 }
 
+func (s *interfacesSuite) TestParseInterfacesBondParams(c *gc.C) {
+	result, err := parseInterfaces([]byte(`[
+        {"id": 1, "name": "bond0", "type": "bond", "params": {"bond_mode": "802.3ad", "bond_miimon": 100}},
+        {"id": 2, "name": "eth0", "type": "physical", "params": ""}
+]`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.HasLen, 2)
+	c.Check(result[0].Params.BondMode, gc.Equals, "802.3ad")
+	c.Check(result[1].Params.BondMode, gc.Equals, "")
+}
+
+func (s *interfacesSuite) TestMAASObjectNetworkInterfacesBondMode(c *gc.C) {
+	nodeJSON := `{
+        "system_id": "foo",
+        "interface_set": [
+            {"id": 1, "name": "bond0", "type": "bond", "enabled": true, "mac_address": "52:54:00:70:9b:fe",
+             "parents": ["eth0"], "children": [], "links": [], "params": {"bond_mode": "active-backup"}},
+            {"id": 2, "name": "eth0", "type": "physical", "enabled": true, "mac_address": "52:54:00:70:9b:fe",
+             "parents": [], "children": ["bond0"], "links": [], "params": ""}
+        ]
+    }`
+	obj := s.testMAASObject.TestServer.NewNode(nodeJSON)
+
+	infos, err := maasObjectNetworkInterfaces(s.callCtx, &obj, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, gc.HasLen, 2)
+	c.Check(infos[0].InterfaceType, gc.Equals, network.BondInterface)
+	c.Check(infos[0].BondMode, gc.Equals, "active-backup")
+	c.Check(infos[0].ParentInterfaceName, gc.Equals, "")
+	c.Check(infos[1].BondMode, gc.Equals, "")
+	c.Check(infos[1].ParentInterfaceName, gc.Equals, "bond0")
+}
+
 func (s *interfacesSuite) TestMAAS2NetworkInterfaces(c *gc.C) {
 	vlan0 := fakeVLAN{
 		id:  5001,
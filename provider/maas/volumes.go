@@ -96,8 +96,11 @@ func newStorageConfig(attrs map[string]interface{}) (*storageConfig, error) {
 
 // ValidateConfig is defined on the Provider interface.
 func (maasStorageProvider) ValidateConfig(cfg *storage.Config) error {
-	_, err := newStorageConfig(cfg.Attrs())
-	return errors.Trace(err)
+	if _, err := newStorageConfig(cfg.Attrs()); err != nil {
+		return errors.Trace(err)
+	}
+	// MAAS allocates physical disks, which it cannot encrypt.
+	return errors.Trace(storage.ValidateNoEncryption(cfg.Attrs(), "MAAS volumes"))
 }
 
 // Supports is defined on the Provider interface.
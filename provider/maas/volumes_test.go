@@ -384,6 +384,16 @@ func (*storageProviderSuite) TestValidateConfigUnknownAttribute(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil) // unknown attributes are ignored
 }
 
+func (*storageProviderSuite) TestValidateConfigEncrypted(c *gc.C) {
+	p := maasStorageProvider{}
+	cfg, err := storage.NewConfig("foo", maasStorageProviderType, map[string]interface{}{
+		"encrypted": true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = p.ValidateConfig(cfg)
+	c.Assert(err, gc.ErrorMatches, `"encrypted" for MAAS volumes not supported`)
+}
+
 func (s *storageProviderSuite) TestSupports(c *gc.C) {
 	p := maasStorageProvider{}
 	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsTrue)
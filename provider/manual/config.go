@@ -4,14 +4,31 @@
 package manual
 
 import (
+	"strings"
+
+	"github.com/juju/errors"
 	"github.com/juju/schema"
 
+	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/environs/config"
 )
 
+const (
+	// MachinePoolKey is the model config attribute holding the
+	// inventory of hosts from which the manual provider may start
+	// instances. Each entry has the form "[user@]host [hardware]",
+	// where the optional hardware characteristics are specified as
+	// for "juju add-machine", e.g. "ubuntu@10.0.0.5 cores=4 tags=ssd".
+	MachinePoolKey = "machine-pool"
+)
+
 var (
-	configFields   = schema.Fields{}
-	configDefaults = schema.Defaults{}
+	configFields = schema.Fields{
+		MachinePoolKey: schema.List(schema.String()),
+	}
+	configDefaults = schema.Defaults{
+		MachinePoolKey: schema.Omit,
+	}
 )
 
 type environConfig struct {
@@ -22,3 +39,68 @@ type environConfig struct {
 func newModelConfig(config *config.Config, attrs map[string]interface{}) *environConfig {
 	return &environConfig{Config: config, attrs: attrs}
 }
+
+// poolHost is a host in the manual provider's machine pool.
+type poolHost struct {
+	// user is the user to log in as when initialising the host.
+	user string
+
+	// host is the hostname or address of the host.
+	host string
+
+	// hardware holds the declared hardware characteristics of the
+	// host, if any. Characteristics which are not declared are
+	// detected when the host is provisioned.
+	hardware instance.HardwareCharacteristics
+}
+
+// sshTarget returns the "[user@]host" to connect to when checking
+// the host before the ubuntu user has been initialised.
+func (h poolHost) sshTarget() string {
+	if h.user == "" {
+		return h.host
+	}
+	return h.user + "@" + h.host
+}
+
+// machinePool returns the hosts in the machine pool.
+func (c *environConfig) machinePool() ([]poolHost, error) {
+	entries, _ := c.attrs[MachinePoolKey].([]interface{})
+	hosts := make([]poolHost, 0, len(entries))
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		host, err := parsePoolHost(entry.(string))
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid %s entry %q", MachinePoolKey, entry)
+		}
+		if seen[host.host] {
+			return nil, errors.Errorf("host %q appears more than once in %s", host.host, MachinePoolKey)
+		}
+		seen[host.host] = true
+		hosts = append(hosts, host)
+	}
+	return hosts, nil
+}
+
+// parsePoolHost parses a machine pool entry of the
+// form "[user@]host [hardware]".
+func parsePoolHost(entry string) (poolHost, error) {
+	fields := strings.Fields(entry)
+	if len(fields) == 0 {
+		return poolHost{}, errors.New("missing host")
+	}
+	var result poolHost
+	result.host = fields[0]
+	if i := strings.IndexRune(result.host, '@'); i >= 0 {
+		result.user, result.host = result.host[:i], result.host[i+1:]
+	}
+	if result.host == "" {
+		return poolHost{}, errors.New("missing host")
+	}
+	hw, err := instance.ParseHardware(fields[1:]...)
+	if err != nil {
+		return poolHost{}, errors.Trace(err)
+	}
+	result.hardware = hw
+	return result, nil
+}
@@ -46,3 +46,48 @@ func MinimalConfig(c *gc.C) *config.Config {
 	c.Assert(err, jc.ErrorIsNil)
 	return testConfig
 }
+
+func (s *configSuite) TestMachinePool(c *gc.C) {
+	attrs := MinimalConfigValues()
+	attrs[MachinePoolKey] = []interface{}{
+		"10.0.0.1",
+		"admin@10.0.0.2 arch=arm64 cores=4 tags=ssd,db",
+	}
+	testConfig, err := config.New(config.UseDefaults, attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	envConfig, err := ManualProvider{}.validate(testConfig, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	pool, err := envConfig.machinePool()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pool, gc.HasLen, 2)
+	c.Assert(pool[0].user, gc.Equals, "")
+	c.Assert(pool[0].host, gc.Equals, "10.0.0.1")
+	c.Assert(pool[0].hardware.String(), gc.Equals, "")
+	c.Assert(pool[1].user, gc.Equals, "admin")
+	c.Assert(pool[1].host, gc.Equals, "10.0.0.2")
+	c.Assert(pool[1].hardware.String(), gc.Equals, "arch=arm64 cores=4 tags=ssd,db")
+}
+
+func (s *configSuite) TestMachinePoolInvalid(c *gc.C) {
+	for i, test := range []struct {
+		pool []interface{}
+		err  string
+	}{{
+		pool: []interface{}{"admin@"},
+		err:  `invalid machine-pool entry "admin@": missing host`,
+	}, {
+		pool: []interface{}{"10.0.0.1 flavour=large"},
+		err:  `invalid machine-pool entry "10.0.0.1 flavour=large": unknown characteristic "flavour"`,
+	}, {
+		pool: []interface{}{"10.0.0.1", "admin@10.0.0.1"},
+		err:  `host "10.0.0.1" appears more than once in machine-pool`,
+	}} {
+		c.Logf("test %d", i)
+		attrs := MinimalConfigValues()
+		attrs[MachinePoolKey] = test.pool
+		testConfig, err := config.New(config.UseDefaults, attrs)
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = ManualProvider{}.validate(testConfig, nil)
+		c.Assert(err, gc.ErrorMatches, test.err)
+	}
+}
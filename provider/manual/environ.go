@@ -12,6 +12,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/utils"
@@ -56,6 +57,15 @@ type manualEnviron struct {
 	// target machine. We cache these, as they should not change.
 	hw     *instance.HardwareCharacteristics
 	series string
+	// pool holds the hosts in the machine pool, parsed
+	// from the config when it is set.
+	pool []poolHost
+	// reserved holds the machine pool hosts currently being
+	// provisioned.
+	reserved set.Strings
+	// allocated records whether each machine pool host is
+	// allocated to the model, for the hosts checked so far.
+	allocated map[string]bool
 }
 
 var errNoStartInstance = errors.New("manual provider cannot start instances")
@@ -66,17 +76,45 @@ func (*manualEnviron) MaintainInstance(ctx context.ProviderCallContext, args env
 	return nil
 }
 
-func (*manualEnviron) StartInstance(ctx context.ProviderCallContext, args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
-	return nil, errNoStartInstance
+// StartInstance is specified in the InstanceBroker interface.
+// Instances can only be started if the model has a machine pool,
+// in which case a free host from the pool is provisioned.
+func (e *manualEnviron) StartInstance(ctx context.ProviderCallContext, args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	pool := e.machinePool()
+	if len(pool) == 0 {
+		return nil, errNoStartInstance
+	}
+	return e.startPoolInstance(pool, args)
 }
 
-func (*manualEnviron) StopInstances(context.ProviderCallContext, ...instance.Id) error {
-	return errNoStopInstance
+// StopInstances is specified in the InstanceBroker interface.
+// Only instances started on hosts from the machine pool can be
+// stopped; their hosts are returned to the pool.
+func (e *manualEnviron) StopInstances(ctx context.ProviderCallContext, ids ...instance.Id) error {
+	return e.stopPoolInstances(ids)
 }
 
 // AllInstances implements environs.InstanceBroker.
+//
+// Only the machine pool hosts allocated to the model are included.
+// Each host is contacted the first time it is checked; hosts which
+// cannot be reached are logged and left out.
 func (e *manualEnviron) AllInstances(ctx context.ProviderCallContext) ([]instances.Instance, error) {
-	return e.Instances(ctx, []instance.Id{BootstrapInstanceId})
+	result := []instances.Instance{manualBootstrapInstance{e.host}}
+	for _, host := range e.machinePool() {
+		if host.host == e.host {
+			continue
+		}
+		allocated, err := e.poolHostAllocated(host)
+		if err != nil {
+			logger.Warningf("checking allocation of pool host %q: %v", host.host, err)
+			continue
+		}
+		if allocated {
+			result = append(result, manualPoolInstance{manualBootstrapInstance{host.host}})
+		}
+	}
+	return result, nil
 }
 
 // AllRunningInstances implements environs.InstanceBroker.
@@ -85,6 +123,13 @@ func (e *manualEnviron) AllRunningInstances(ctx context.ProviderCallContext) ([]
 	return e.AllInstances(ctx)
 }
 
+// machinePool returns the hosts in the machine pool.
+func (e *manualEnviron) machinePool() []poolHost {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.pool
+}
+
 func (e *manualEnviron) envConfig() (cfg *environConfig) {
 	e.mu.Lock()
 	cfg = e.cfg
@@ -195,16 +240,26 @@ func (e *manualEnviron) SetConfig(cfg *config.Config) error {
 	if err != nil {
 		return err
 	}
-	e.cfg = newModelConfig(cfg, cfg.UnknownAttrs())
+	envConfig := newModelConfig(cfg, cfg.UnknownAttrs())
+	pool, err := envConfig.machinePool()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	e.cfg, e.pool = envConfig, pool
 	return nil
 }
 
 // Instances implements environs.Environ.
 //
 // This method will only ever return an Instance for the Id
-// BootstrapInstanceId. If any others are specified, then
+// BootstrapInstanceId, or for the Id of an instance started on
+// a host in the machine pool. If any others are specified, then
 // ErrPartialInstances or ErrNoInstances will result.
 func (e *manualEnviron) Instances(ctx context.ProviderCallContext, ids []instance.Id) ([]instances.Instance, error) {
+	poolHosts := set.NewStrings()
+	for _, host := range e.machinePool() {
+		poolHosts.Add(host.host)
+	}
 	result := make([]instances.Instance, len(ids))
 	var found bool
 	var err error
@@ -212,6 +267,9 @@ func (e *manualEnviron) Instances(ctx context.ProviderCallContext, ids []instanc
 		if id == BootstrapInstanceId {
 			result[i] = manualBootstrapInstance{e.host}
 			found = true
+		} else if host, ok := poolInstanceHost(id); ok && poolHosts.Contains(host) {
+			result[i] = manualPoolInstance{manualBootstrapInstance{host}}
+			found = true
 		} else {
 			err = environs.ErrPartialInstances
 		}
@@ -329,16 +387,26 @@ func (e *manualEnviron) PrecheckInstance(ctx context.ProviderCallContext, params
 var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.InstanceType,
-	constraints.Tags,
 	constraints.VirtType,
+	constraints.Spot,
+	constraints.MaxPrice,
+	constraints.AntiAffinity,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
 func (e *manualEnviron) ConstraintsValidator(ctx context.ProviderCallContext) (constraints.Validator, error) {
+	pool := e.machinePool()
 	validator := constraints.NewValidator()
-	validator.RegisterUnsupported(unsupportedConstraints)
+	unsupported := unsupportedConstraints
+	if len(pool) == 0 {
+		// Tags can only be matched against the
+		// hosts declared in the machine pool.
+		unsupported = append(unsupported, constraints.Tags)
+	}
+	validator.RegisterUnsupported(unsupported)
+	var arches []string
 	if isRunningController() {
-		validator.UpdateVocabulary(constraints.Arch, []string{arch.HostArch()})
+		arches = []string{arch.HostArch()}
 	} else {
 		// We're running outside of the Juju controller, so we must
 		// SSH to the machine and detect its architecture.
@@ -346,8 +414,14 @@ func (e *manualEnviron) ConstraintsValidator(ctx context.ProviderCallContext) (c
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		validator.UpdateVocabulary(constraints.Arch, []string{*hw.Arch})
+		arches = []string{*hw.Arch}
+	}
+	for _, host := range pool {
+		if host.hardware.Arch != nil {
+			arches = append(arches, *host.hardware.Arch)
+		}
 	}
+	validator.UpdateVocabulary(constraints.Arch, arches)
 	return validator, nil
 }
 
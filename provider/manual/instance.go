@@ -51,3 +51,13 @@ func (manualBootstrapInstance) ClosePorts(ctx context.ProviderCallContext, machi
 func (manualBootstrapInstance) IngressRules(ctx context.ProviderCallContext, machineId string) ([]network.IngressRule, error) {
 	return nil, nil
 }
+
+// manualPoolInstance is an instance started on a host
+// from the machine pool.
+type manualPoolInstance struct {
+	manualBootstrapInstance
+}
+
+func (inst manualPoolInstance) Id() instance.Id {
+	return poolInstanceId(inst.host)
+}
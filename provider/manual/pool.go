@@ -0,0 +1,386 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package manual
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/manual/sshprovisioner"
+	"github.com/juju/juju/tools"
+)
+
+// PoolInstancePrefix is the prefix of the ids of instances started
+// on hosts from the machine pool. Unlike machines added with
+// "juju add-machine ssh:...", these are managed by the provisioner,
+// and the hosts are returned to the pool when the instances are
+// stopped.
+const PoolInstancePrefix = "manual-pool:"
+
+// poolInstanceId returns the id of the instance started on the host.
+func poolInstanceId(host string) instance.Id {
+	return instance.Id(PoolInstancePrefix + host)
+}
+
+// poolInstanceHost returns the host of the pool instance with the
+// given id, or false if the id is not that of a pool instance.
+func poolInstanceHost(id instance.Id) (string, bool) {
+	if !strings.HasPrefix(string(id), PoolInstancePrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(string(id), PoolInstancePrefix), true
+}
+
+// poolHostModelFile is the file on a machine pool host which records
+// the UUID of the model the host is allocated to. Only hosts allocated
+// to the model are reported as its instances, or released when those
+// instances are stopped, so that hosts added with "juju add-machine
+// ssh:..." or used by other models are never touched.
+const poolHostModelFile = "/var/lib/juju-machine-pool-model"
+
+// notAllocatedMessage is written by the release script if the host is
+// not allocated to the model.
+const notAllocatedMessage = "host not allocated to model"
+
+var runProvisioningScript = sshprovisioner.RunProvisioningScript
+
+// startPoolInstance starts an instance on a free host from the machine
+// pool matching the constraints, by running the same provisioning
+// script used when manually adding a machine.
+func (e *manualEnviron) startPoolInstance(pool []poolHost, args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	series := args.Tools.OneSeries()
+	host, hw, err := e.allocatePoolHost(pool, series, args.Constraints)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer e.releasePoolHost(host.host)
+
+	logger.Infof("starting machine %s on pool host %q", args.InstanceConfig.MachineId, host.host)
+	tools, err := args.Tools.Match(tools.Filter{Arch: *hw.Arch})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := args.InstanceConfig.SetTools(tools); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := instancecfg.FinishInstanceConfig(args.InstanceConfig, e.Config()); err != nil {
+		return nil, errors.Trace(err)
+	}
+	script, err := sshprovisioner.ProvisioningScript(args.InstanceConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := e.markPoolHost(host); err != nil {
+		return nil, errors.Annotatef(err, "allocating pool host %q", host.host)
+	}
+	if err := runProvisioningScript(script, host.host, ioutil.Discard); err != nil {
+		if releaseErr := e.releasePoolInstance(host); releaseErr != nil {
+			logger.Errorf("cannot release pool host %q: %v", host.host, releaseErr)
+		}
+		return nil, errors.Annotatef(err, "provisioning pool host %q", host.host)
+	}
+	return &environs.StartInstanceResult{
+		Instance: manualPoolInstance{manualBootstrapInstance{host.host}},
+		Hardware: hw,
+	}, nil
+}
+
+// allocatePoolHost finds a host in the pool which is not already
+// provisioned, and which runs the given series and satisfies the
+// constraints. The host is reserved until releasePoolHost is called,
+// so that concurrent calls to StartInstance do not pick the same host.
+func (e *manualEnviron) allocatePoolHost(
+	pool []poolHost, series string, cons constraints.Value,
+) (poolHost, *instance.HardwareCharacteristics, error) {
+	for _, host := range pool {
+		if host.host == e.host || !e.reservePoolHost(host.host) {
+			continue
+		}
+		hw, err := e.checkPoolHost(host, series, cons)
+		if err == nil {
+			return host, hw, nil
+		}
+		e.releasePoolHost(host.host)
+		logger.Debugf("skipping pool host %q: %v", host.host, err)
+	}
+	return poolHost{}, nil, errors.Errorf(
+		"no free host in %s running %q matches constraints %q", MachinePoolKey, series, cons,
+	)
+}
+
+// checkPoolHost returns the hardware characteristics of the host
+// if it is free to use and matches the series and constraints. The
+// host is only initialised for use by Juju once it has been found
+// to match, so that hosts which are unsuitable are left untouched.
+func (e *manualEnviron) checkPoolHost(host poolHost, series string, cons constraints.Value) (*instance.HardwareCharacteristics, error) {
+	hw, hostSeries, err := sshprovisioner.DetectSeriesAndHardwareCharacteristicsAt(host.sshTarget())
+	if err != nil {
+		return nil, errors.Annotate(err, "detecting hardware characteristics")
+	}
+	if hostSeries != series {
+		return nil, errors.Errorf("series %q does not match %q", hostSeries, series)
+	}
+	overlayHardware(&hw, host.hardware)
+	if err := checkHardware(hw, cons); err != nil {
+		return nil, errors.Trace(err)
+	}
+	provisioned, err := sshprovisioner.CheckProvisionedAt(host.sshTarget())
+	if err != nil {
+		return nil, errors.Annotate(err, "checking provisioned status")
+	}
+	if provisioned {
+		return nil, errors.New("already in use")
+	}
+	err = initUbuntuUser(host.host, host.user, e.envConfig().AuthorizedKeys(), strings.NewReader(""), ioutil.Discard)
+	if err != nil {
+		return nil, errors.Annotate(err, "initialising ubuntu user")
+	}
+	return &hw, nil
+}
+
+// overlayHardware overrides the detected hardware characteristics with
+// those declared for the host in the pool.
+func overlayHardware(hw *instance.HardwareCharacteristics, declared instance.HardwareCharacteristics) {
+	if declared.Arch != nil {
+		hw.Arch = declared.Arch
+	}
+	if declared.Mem != nil {
+		hw.Mem = declared.Mem
+	}
+	if declared.RootDisk != nil {
+		hw.RootDisk = declared.RootDisk
+	}
+	if declared.CpuCores != nil {
+		hw.CpuCores = declared.CpuCores
+	}
+	if declared.CpuPower != nil {
+		hw.CpuPower = declared.CpuPower
+	}
+	if declared.RootDiskSource != nil {
+		hw.RootDiskSource = declared.RootDiskSource
+	}
+	if declared.Tags != nil {
+		hw.Tags = declared.Tags
+	}
+	if declared.AvailabilityZone != nil {
+		hw.AvailabilityZone = declared.AvailabilityZone
+	}
+}
+
+// checkHardware returns an error if the hardware characteristics do
+// not satisfy the constraints.
+func checkHardware(hw instance.HardwareCharacteristics, cons constraints.Value) error {
+	atLeast := func(name string, want, have *uint64) error {
+		if want == nil || *want == 0 {
+			return nil
+		}
+		if have == nil || *have < *want {
+			return errors.Errorf("%s constraint not satisfied", name)
+		}
+		return nil
+	}
+	if cons.HasArch() && (hw.Arch == nil || *hw.Arch != *cons.Arch) {
+		return errors.Errorf("%s constraint not satisfied", constraints.Arch)
+	}
+	if err := atLeast(constraints.CpuCores, cons.CpuCores, hw.CpuCores); err != nil {
+		return err
+	}
+	if err := atLeast(constraints.CpuPower, cons.CpuPower, hw.CpuPower); err != nil {
+		return err
+	}
+	if err := atLeast(constraints.Mem, cons.Mem, hw.Mem); err != nil {
+		return err
+	}
+	if err := atLeast(constraints.RootDisk, cons.RootDisk, hw.RootDisk); err != nil {
+		return err
+	}
+	if cons.Tags != nil {
+		var hostTags []string
+		if hw.Tags != nil {
+			hostTags = *hw.Tags
+		}
+		have := set.NewStrings(hostTags...)
+		for _, tag := range *cons.Tags {
+			if strings.HasPrefix(tag, "^") {
+				if have.Contains(tag[1:]) {
+					return errors.Errorf("%s constraint not satisfied", constraints.Tags)
+				}
+			} else if !have.Contains(tag) {
+				return errors.Errorf("%s constraint not satisfied", constraints.Tags)
+			}
+		}
+	}
+	if cons.HasZones() {
+		if hw.AvailabilityZone == nil || !set.NewStrings(*cons.Zones...).Contains(*hw.AvailabilityZone) {
+			return errors.Errorf("%s constraint not satisfied", constraints.Zones)
+		}
+	}
+	return nil
+}
+
+// reservePoolHost marks the host as being provisioned, returning
+// false if it is already reserved.
+func (e *manualEnviron) reservePoolHost(host string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.reserved == nil {
+		e.reserved = set.NewStrings()
+	}
+	if e.reserved.Contains(host) {
+		return false
+	}
+	e.reserved.Add(host)
+	return true
+}
+
+func (e *manualEnviron) releasePoolHost(host string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.reserved.Remove(host)
+}
+
+// stopPoolInstances removes the Juju agents from the hosts of the
+// given pool instances, returning the hosts to the pool.
+func (e *manualEnviron) stopPoolInstances(ids []instance.Id) error {
+	hosts := make([]string, len(ids))
+	for i, id := range ids {
+		host, ok := poolInstanceHost(id)
+		if !ok {
+			return errNoStopInstance
+		}
+		hosts[i] = host
+	}
+	for _, host := range hosts {
+		logger.Infof("returning host %q to the machine pool", host)
+		if err := e.releasePoolInstance(e.poolHost(host)); err != nil {
+			return errors.Annotatef(err, "releasing pool host %q", host)
+		}
+	}
+	return nil
+}
+
+// poolHost returns the machine pool entry for the host. Hosts which
+// have since been removed from the pool are logged in to with the
+// default user.
+func (e *manualEnviron) poolHost(host string) poolHost {
+	for _, h := range e.machinePool() {
+		if h.host == host {
+			return h
+		}
+	}
+	return poolHost{host: host}
+}
+
+// markPoolHost records on the host that it is allocated to the model.
+func (e *manualEnviron) markPoolHost(host poolHost) error {
+	_, stderr, err := runSSHCommand(
+		host.sshTarget(),
+		[]string{"sudo", "/bin/bash"}, markHostScript(e.Config().UUID()),
+	)
+	if err != nil {
+		logger.Debugf("script stderr: \n%s", stderr)
+		return errors.Trace(err)
+	}
+	e.setPoolHostAllocated(host.host, true)
+	return nil
+}
+
+// releasePoolInstance removes the Juju agents from the host, if it is
+// allocated to the model.
+func (e *manualEnviron) releasePoolInstance(host poolHost) error {
+	stdout, stderr, err := runSSHCommand(
+		host.sshTarget(),
+		[]string{"sudo", "/bin/bash"}, releaseHostScript(e.Config().UUID()),
+	)
+	logger.Debugf("script stdout: \n%s", stdout)
+	logger.Debugf("script stderr: \n%s", stderr)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if strings.Contains(stdout, notAllocatedMessage) {
+		logger.Warningf("pool host %q is not allocated to this model, leaving it untouched", host.host)
+	}
+	e.setPoolHostAllocated(host.host, false)
+	return nil
+}
+
+// poolHostAllocated reports whether the host is allocated to the model.
+// The result is remembered, so that each host is only contacted once;
+// the model's own allocations and releases keep it up to date.
+func (e *manualEnviron) poolHostAllocated(host poolHost) (bool, error) {
+	e.mu.Lock()
+	allocated, ok := e.allocated[host.host]
+	e.mu.Unlock()
+	if ok {
+		return allocated, nil
+	}
+	stdout, _, err := runSSHCommand(
+		host.sshTarget(),
+		[]string{"/bin/bash"}, hostModelScript(),
+	)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	allocated = strings.TrimSpace(stdout) == e.Config().UUID()
+	e.setPoolHostAllocated(host.host, allocated)
+	return allocated, nil
+}
+
+func (e *manualEnviron) setPoolHostAllocated(host string, allocated bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.allocated == nil {
+		e.allocated = make(map[string]bool)
+	}
+	e.allocated[host] = allocated
+}
+
+// markHostScript returns a script which records that the host is
+// allocated to the model with the given UUID.
+func markHostScript(modelUUID string) string {
+	return fmt.Sprintf(`
+set -e
+echo %s > %s
+`, utils.ShQuote(modelUUID), utils.ShQuote(poolHostModelFile))
+}
+
+// hostModelScript returns a script which prints the UUID of the model
+// the host is allocated to, if any.
+func hostModelScript() string {
+	return fmt.Sprintf("cat %s 2>/dev/null || true\n", utils.ShQuote(poolHostModelFile))
+}
+
+// releaseHostScript returns a script which stops and removes all of
+// the Juju agents on a host, along with their data and logs, if the
+// host is allocated to the model with the given UUID.
+func releaseHostScript(modelUUID string) string {
+	return fmt.Sprintf(`
+set -x
+if [ "$(cat %[1]s 2>/dev/null)" != %[2]s ]; then
+    echo %[3]s
+    exit 0
+fi
+for unit in $(systemctl list-unit-files --no-legend 'jujud-*' | awk '{print $1}'); do
+    systemctl stop "$unit"
+    systemctl disable "$unit"
+done
+rm -f /etc/systemd/system/jujud-* /lib/systemd/system/jujud-*
+systemctl daemon-reload
+rm -rf %[4]s %[5]s
+rm -f %[1]s
+exit 0
+`, utils.ShQuote(poolHostModelFile), utils.ShQuote(modelUUID), utils.ShQuote(notAllocatedMessage),
+		utils.ShQuote(agent.DefaultPaths.DataDir), utils.ShQuote(agent.DefaultPaths.LogDir))
+}
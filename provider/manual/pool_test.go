@@ -0,0 +1,316 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package manual
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/arch"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/manual/sshprovisioner"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/tools"
+)
+
+type poolSuite struct {
+	baseEnvironSuite
+
+	provisioned map[string]bool
+	initialised []string
+	scripts     map[string]string
+
+	// models holds the model UUID recorded on each host, keyed
+	// by the ssh target used to reach it.
+	models      map[string]string
+	released    []string
+	unreachable map[string]bool
+	sshCalls    int
+}
+
+var _ = gc.Suite(&poolSuite{})
+
+func (s *poolSuite) SetUpTest(c *gc.C) {
+	s.baseEnvironSuite.SetUpTest(c)
+	attrs := MinimalConfigValues()
+	attrs[MachinePoolKey] = []interface{}{
+		"admin@10.0.0.1 tags=db",
+		"10.0.0.2 cores=8 tags=db,ssd availability-zone=rack2",
+	}
+	cfg, err := config.New(config.UseDefaults, attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	env, err := ManualProvider{}.Open(environs.OpenParams{
+		Cloud:  CloudSpec(),
+		Config: cfg,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.env = env.(*manualEnviron)
+
+	s.provisioned = make(map[string]bool)
+	s.initialised = nil
+	s.scripts = make(map[string]string)
+	s.models = make(map[string]string)
+	s.released = nil
+	s.unreachable = make(map[string]bool)
+	s.sshCalls = 0
+	s.PatchValue(&runSSHCommand, s.runSSHCommand)
+	s.PatchValue(&initUbuntuUser, func(host, login, authorizedKeys string, read io.Reader, write io.Writer) error {
+		s.initialised = append(s.initialised, host)
+		return nil
+	})
+	s.PatchValue(&sshprovisioner.CheckProvisionedAt, func(host string) (bool, error) {
+		return s.provisioned[host], nil
+	})
+	s.PatchValue(&sshprovisioner.DetectSeriesAndHardwareCharacteristicsAt,
+		func(host string) (instance.HardwareCharacteristics, string, error) {
+			amd64 := arch.AMD64
+			cores := uint64(2)
+			mem := uint64(4096)
+			return instance.HardwareCharacteristics{
+				Arch:     &amd64,
+				CpuCores: &cores,
+				Mem:      &mem,
+			}, "bionic", nil
+		},
+	)
+	s.PatchValue(&runProvisioningScript, func(script, host string, progressWriter io.Writer) error {
+		s.scripts[host] = script
+		return nil
+	})
+}
+
+// runSSHCommand runs the machine pool scripts against fake hosts.
+func (s *poolSuite) runSSHCommand(host string, command []string, stdin string) (string, string, error) {
+	s.sshCalls++
+	if s.unreachable[host] {
+		return "", "", errors.New("no route to host")
+	}
+	modelUUID := s.env.Config().UUID()
+	wantCommand := "[sudo /bin/bash]"
+	if stdin == hostModelScript() {
+		wantCommand = "[/bin/bash]"
+	}
+	if fmt.Sprint(command) != wantCommand {
+		return "", "", errors.Errorf("unexpected command %v", command)
+	}
+	switch stdin {
+	case markHostScript(modelUUID):
+		s.models[host] = modelUUID
+		return "", "", nil
+	case hostModelScript():
+		return s.models[host] + "\n", "", nil
+	case releaseHostScript(modelUUID):
+		if s.models[host] != modelUUID {
+			return notAllocatedMessage + "\n", "", nil
+		}
+		delete(s.models, host)
+		s.released = append(s.released, host)
+		return "", "", nil
+	}
+	return "", "", errors.Errorf("unexpected script %q", stdin)
+}
+
+func (s *poolSuite) startInstanceParams(c *gc.C, cons string) environs.StartInstanceParams {
+	apiInfo := &api.Info{
+		Addrs:    []string{"localhost:17070"},
+		CACert:   coretesting.CACert,
+		Tag:      names.NewMachineTag("1"),
+		ModelTag: coretesting.ModelTag,
+	}
+	icfg, err := instancecfg.NewInstanceConfig(
+		coretesting.ControllerTag, "1", "nonce", imagemetadata.ReleasedStream, "bionic", apiInfo,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	toolsVersion := version.MustParseBinary("2.8.0-bionic-amd64")
+	return environs.StartInstanceParams{
+		ControllerUUID: coretesting.ControllerTag.Id(),
+		Constraints:    constraints.MustParse(cons),
+		Tools: tools.List{{
+			Version: toolsVersion,
+			URL:     fmt.Sprintf("http://example.com/tools/juju-%s.tgz", toolsVersion),
+			SHA256:  "1234567890abcdef",
+			Size:    1024,
+		}},
+		InstanceConfig: icfg,
+	}
+}
+
+func (s *poolSuite) TestStartInstance(c *gc.C) {
+	result, err := s.env.StartInstance(s.callCtx, s.startInstanceParams(c, ""))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Instance.Id(), gc.Equals, instance.Id("manual-pool:10.0.0.1"))
+	c.Assert(result.Hardware.String(), gc.Equals, "arch=amd64 cores=2 mem=4096M tags=db")
+	c.Assert(s.scripts["10.0.0.1"], gc.Not(gc.Equals), "")
+	c.Assert(s.initialised, jc.DeepEquals, []string{"10.0.0.1"})
+	// The host is recorded as allocated to the model.
+	c.Assert(s.models, jc.DeepEquals, map[string]string{
+		"admin@10.0.0.1": s.env.Config().UUID(),
+	})
+}
+
+func (s *poolSuite) TestStartInstanceMatchesConstraints(c *gc.C) {
+	result, err := s.env.StartInstance(s.callCtx, s.startInstanceParams(c, "cores=4 tags=ssd zones=rack2"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Instance.Id(), gc.Equals, instance.Id("manual-pool:10.0.0.2"))
+	c.Assert(result.Hardware.String(), gc.Equals, "arch=amd64 cores=8 mem=4096M tags=db,ssd availability-zone=rack2")
+	c.Assert(s.scripts, gc.HasLen, 1)
+	// Only the chosen host is initialised.
+	c.Assert(s.initialised, jc.DeepEquals, []string{"10.0.0.2"})
+}
+
+func (s *poolSuite) TestStartInstanceSkipsProvisionedHosts(c *gc.C) {
+	s.provisioned["admin@10.0.0.1"] = true
+	result, err := s.env.StartInstance(s.callCtx, s.startInstanceParams(c, "tags=db"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Instance.Id(), gc.Equals, instance.Id("manual-pool:10.0.0.2"))
+	c.Assert(s.initialised, jc.DeepEquals, []string{"10.0.0.2"})
+}
+
+func (s *poolSuite) TestStartInstanceNoMatchingHost(c *gc.C) {
+	_, err := s.env.StartInstance(s.callCtx, s.startInstanceParams(c, "tags=^db"))
+	c.Assert(err, gc.ErrorMatches, `no free host in machine-pool running "bionic" matches constraints "tags=\^db"`)
+	c.Assert(s.scripts, gc.HasLen, 0)
+	c.Assert(s.initialised, gc.HasLen, 0)
+
+	// Hosts are not left reserved after a failed allocation.
+	_, err = s.env.StartInstance(s.callCtx, s.startInstanceParams(c, ""))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *poolSuite) TestStartInstanceProvisioningError(c *gc.C) {
+	s.PatchValue(&runProvisioningScript, func(script, host string, progressWriter io.Writer) error {
+		return errors.New("boom")
+	})
+	_, err := s.env.StartInstance(s.callCtx, s.startInstanceParams(c, ""))
+	c.Assert(err, gc.ErrorMatches, `provisioning pool host "10.0.0.1": boom`)
+	// The host is returned to the pool.
+	c.Assert(s.released, jc.DeepEquals, []string{"admin@10.0.0.1"})
+	c.Assert(s.models, gc.HasLen, 0)
+}
+
+func (s *poolSuite) TestStartInstanceWithoutPool(c *gc.C) {
+	env, err := ManualProvider{}.Open(environs.OpenParams{
+		Cloud:  CloudSpec(),
+		Config: MinimalConfig(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = env.StartInstance(s.callCtx, s.startInstanceParams(c, ""))
+	c.Assert(err, gc.Equals, errNoStartInstance)
+}
+
+func (s *poolSuite) TestStopInstances(c *gc.C) {
+	s.models["admin@10.0.0.1"] = s.env.Config().UUID()
+	s.models["10.0.0.2"] = s.env.Config().UUID()
+	err := s.env.StopInstances(s.callCtx, "manual-pool:10.0.0.1", "manual-pool:10.0.0.2")
+	c.Assert(err, jc.ErrorIsNil)
+	// Each host is reached as the user given in the pool.
+	c.Assert(s.released, jc.DeepEquals, []string{"admin@10.0.0.1", "10.0.0.2"})
+	c.Assert(s.models, gc.HasLen, 0)
+}
+
+func (s *poolSuite) TestStopInstancesNotAllocated(c *gc.C) {
+	// Hosts allocated to another model, or not allocated at all,
+	// are left untouched.
+	s.models["admin@10.0.0.1"] = "another-model"
+	err := s.env.StopInstances(s.callCtx, "manual-pool:10.0.0.1", "manual-pool:10.0.0.2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.released, gc.HasLen, 0)
+	c.Assert(s.models, jc.DeepEquals, map[string]string{"admin@10.0.0.1": "another-model"})
+}
+
+func (s *poolSuite) TestStopInstancesNotPool(c *gc.C) {
+	err := s.env.StopInstances(s.callCtx, "manual-pool:10.0.0.1", BootstrapInstanceId)
+	c.Assert(err, gc.Equals, errNoStopInstance)
+}
+
+func (s *poolSuite) TestInstances(c *gc.C) {
+	instances, err := s.env.Instances(s.callCtx, []instance.Id{
+		BootstrapInstanceId, "manual-pool:10.0.0.2", "manual-pool:10.0.0.3",
+	})
+	c.Assert(err, gc.Equals, environs.ErrPartialInstances)
+	c.Assert(instances, gc.HasLen, 3)
+	c.Assert(instances[0].Id(), gc.Equals, BootstrapInstanceId)
+	c.Assert(instances[1].Id(), gc.Equals, instance.Id("manual-pool:10.0.0.2"))
+	c.Assert(instances[2], gc.IsNil)
+}
+
+func (s *poolSuite) TestAllInstances(c *gc.C) {
+	// A host provisioned by other means is not an instance of the
+	// model unless it is allocated to the model.
+	s.provisioned["admin@10.0.0.1"] = true
+	s.provisioned["10.0.0.2"] = true
+	s.models["admin@10.0.0.1"] = "another-model"
+	s.models["10.0.0.2"] = s.env.Config().UUID()
+	instances, err := s.env.AllInstances(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 2)
+	c.Assert(instances[0].Id(), gc.Equals, BootstrapInstanceId)
+	c.Assert(instances[1].Id(), gc.Equals, instance.Id("manual-pool:10.0.0.2"))
+	c.Assert(s.sshCalls, gc.Equals, 2)
+
+	// The hosts are not contacted again.
+	instances, err = s.env.AllInstances(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 2)
+	c.Assert(s.sshCalls, gc.Equals, 2)
+}
+
+func (s *poolSuite) TestAllInstancesTracksAllocations(c *gc.C) {
+	instances, err := s.env.AllInstances(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 1)
+
+	_, err = s.env.StartInstance(s.callCtx, s.startInstanceParams(c, ""))
+	c.Assert(err, jc.ErrorIsNil)
+	instances, err = s.env.AllInstances(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 2)
+	c.Assert(instances[1].Id(), gc.Equals, instance.Id("manual-pool:10.0.0.1"))
+
+	err = s.env.StopInstances(s.callCtx, "manual-pool:10.0.0.1")
+	c.Assert(err, jc.ErrorIsNil)
+	instances, err = s.env.AllInstances(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 1)
+}
+
+func (s *poolSuite) TestAllInstancesSkipsUnreachableHosts(c *gc.C) {
+	s.unreachable["admin@10.0.0.1"] = true
+	s.models["10.0.0.2"] = s.env.Config().UUID()
+	instances, err := s.env.AllInstances(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 2)
+	c.Assert(instances[1].Id(), gc.Equals, instance.Id("manual-pool:10.0.0.2"))
+
+	// Unreachable hosts are checked again.
+	delete(s.unreachable, "admin@10.0.0.1")
+	s.models["admin@10.0.0.1"] = s.env.Config().UUID()
+	instances, err = s.env.AllInstances(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 3)
+}
+
+func (s *poolSuite) TestConstraintsValidatorSupportsTags(c *gc.C) {
+	// Patch os.Args so it appears that we're running in "jujud".
+	s.PatchValue(&os.Args, []string{"/some/where/containing/jujud", "whatever"})
+	s.PatchValue(&arch.HostArch, func() string { return arch.AMD64 })
+	validator, err := s.env.ConstraintsValidator(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	unsupported, err := validator.Validate(constraints.MustParse("tags=ssd"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unsupported, gc.HasLen, 0)
+}
@@ -171,6 +171,9 @@ func (p ManualProvider) validate(cfg, old *config.Config) (*environConfig, error
 		return nil, err
 	}
 	envConfig := newModelConfig(cfg, validated)
+	if _, err := envConfig.machinePool(); err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	// If the user hasn't already specified a value, set it to the
 	// given value.
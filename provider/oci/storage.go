@@ -86,6 +86,11 @@ func (s *storageProvider) DefaultPools() []*storage.Config {
 
 func (s *storageProvider) ValidateConfig(cfg *storage.Config) error {
 	attrs := cfg.Attrs()
+	// Block volumes are always encrypted at rest with Oracle-managed
+	// keys; Juju does not pass a Vault key when creating them.
+	if err := storage.ValidateNoKMSKey(attrs, "OCI volumes"); err != nil {
+		return errors.Trace(err)
+	}
 	var pool string
 	if volType, ok := attrs[ociVolumeType]; ok {
 		switch kind := volType.(type) {
@@ -53,6 +53,7 @@ var cinderConfigChecker = schema.FieldMap(
 
 type cinderConfig struct {
 	volumeType string
+	encrypted  bool
 }
 
 func newCinderConfig(attrs map[string]interface{}) (*cinderConfig, error) {
@@ -62,8 +63,26 @@ func newCinderConfig(attrs map[string]interface{}) (*cinderConfig, error) {
 	}
 	coerced := out.(map[string]interface{})
 	volumeType, _ := coerced[cinderVolumeType].(string)
+	encryption, err := storage.ParseEncryptionConfig(attrs)
+	if err != nil {
+		return nil, errors.Annotate(err, "validating Cinder storage config")
+	}
+	// Cinder encrypts volumes according to their volume type, so
+	// encrypted volumes must be created with a volume type that the
+	// cloud administrator has configured for encryption. The keys
+	// are managed by Cinder, and cannot be chosen per pool.
+	if encryption.Encrypted && volumeType == "" {
+		return nil, errors.Errorf(
+			"%q requires an encrypted %q to be specified",
+			storage.EncryptedAttribute, cinderVolumeType,
+		)
+	}
+	if encryption.KMSKey != "" {
+		return nil, errors.NotSupportedf("%q for Cinder volumes", storage.KMSKeyAttribute)
+	}
 	cinderConfig := &cinderConfig{
 		volumeType: volumeType,
+		encrypted:  encryption.Encrypted,
 	}
 	return cinderConfig, nil
 }
@@ -133,6 +152,7 @@ var newOpenstackStorage = func(env *Environ) (OpenstackStorage, error) {
 	return &openstackStorageAdapter{
 		cinderCl,
 		novaClient{env.novaUnlocked},
+		client,
 	}, nil
 }
 
@@ -282,6 +302,7 @@ func (s *cinderVolumeSource) createVolume(
 		VolumeType:       cinderConfig.volumeType,
 		AvailabilityZone: az,
 		Metadata:         metadata,
+		SnapshotId:       arg.SnapshotId,
 	})
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -301,7 +322,9 @@ func (s *cinderVolumeSource) createVolume(
 		return nil, errors.Errorf("waiting for volume to be provisioned: %s", err)
 	}
 	logger.Debugf("created volume: %+v", cinderVolume)
-	return &storage.Volume{Tag: arg.Tag, VolumeInfo: cinderToJujuVolumeInfo(cinderVolume)}, nil
+	info := cinderToJujuVolumeInfo(cinderVolume)
+	info.Encrypted = cinderConfig.encrypted
+	return &storage.Volume{Tag: arg.Tag, VolumeInfo: info}, nil
 }
 
 // ListVolumes is specified on the storage.VolumeSource interface.
@@ -656,6 +679,9 @@ type OpenstackStorage interface {
 	DetachVolume(serverId, attachmentId string) error
 	ListVolumeAttachments(serverId string) ([]nova.VolumeAttachment, error)
 	SetVolumeMetadata(volumeId string, metadata map[string]string) (map[string]string, error)
+	DeleteVolumeMetadata(volumeId, key string) error
+	ExtendVolume(volumeId string, newSizeGiB int) error
+	CreateSnapshot(volumeId, name string, metadata map[string]string) (snapshotId string, sizeGiB int, err error)
 }
 
 type endpointResolver interface {
@@ -686,6 +712,10 @@ func getVolumeEndpointURL(client endpointResolver, region string) (*url.URL, err
 type openstackStorageAdapter struct {
 	cinderClient
 	novaClient
+
+	// volumeRequester is used to send volume requests
+	// not supported by the cinder client.
+	volumeRequester requestSender
 }
 
 type cinderClient struct {
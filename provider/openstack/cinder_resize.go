@@ -0,0 +1,54 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"net/http"
+
+	"github.com/juju/errors"
+	"gopkg.in/goose.v2/client"
+	goosehttp "gopkg.in/goose.v2/http"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/storage"
+)
+
+var _ storage.VolumeResizer = (*cinderVolumeSource)(nil)
+
+// ResizeVolumes implements storage.VolumeResizer. Cinder volumes are
+// sized in GiB, so the requested size is rounded up to the nearest GiB.
+func (s *cinderVolumeSource) ResizeVolumes(ctx context.ProviderCallContext, params []storage.VolumeResizeParams) ([]storage.ResizeResult, error) {
+	results := make([]storage.ResizeResult, len(params))
+	for i, p := range params {
+		sizeGiB := int((p.Size + 1023) / 1024)
+		if err := s.storageAdapter.ExtendVolume(p.VolumeId, sizeGiB); err != nil {
+			handleCredentialError(err, ctx)
+			results[i].Error = errors.Annotatef(err, "extending volume %q", p.VolumeId)
+			continue
+		}
+		results[i].Size = uint64(sizeGiB * 1024)
+	}
+	return results, nil
+}
+
+// ExtendVolume is part of the OpenstackStorage interface. The
+// cinder client does not support extending volumes, so the
+// os-extend action is sent directly.
+func (ga *openstackStorageAdapter) ExtendVolume(volumeId string, newSizeGiB int) error {
+	var req struct {
+		Extend struct {
+			NewSize int `json:"new_size"`
+		} `json:"os-extend"`
+	}
+	req.Extend.NewSize = newSizeGiB
+	requestData := goosehttp.RequestData{
+		ReqValue:       req,
+		ExpectedStatus: []int{http.StatusAccepted},
+	}
+	err := ga.volumeRequester.SendRequest(client.POST, "volumev2", "v2", "volumes/"+volumeId+"/action", &requestData)
+	if IsNotFoundError(err) {
+		return errors.NotFoundf("volume %q", volumeId)
+	}
+	return err
+}
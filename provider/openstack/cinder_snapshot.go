@@ -0,0 +1,71 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"net/http"
+
+	"github.com/juju/errors"
+	"gopkg.in/goose.v2/client"
+	goosehttp "gopkg.in/goose.v2/http"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/storage"
+)
+
+var _ storage.VolumeSnapshotter = (*cinderVolumeSource)(nil)
+
+// CreateVolumeSnapshots implements storage.VolumeSnapshotter.
+func (s *cinderVolumeSource) CreateVolumeSnapshots(ctx context.ProviderCallContext, params []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+	results := make([]storage.CreateVolumeSnapshotsResult, len(params))
+	for i, p := range params {
+		name := resourceName(s.namespace, s.envName, p.Tag.String()+"-snapshot-"+p.Snapshot)
+		snapshotId, sizeGiB, err := s.storageAdapter.CreateSnapshot(p.VolumeId, name, p.ResourceTags)
+		if err != nil {
+			handleCredentialError(err, ctx)
+			results[i].Error = errors.Annotatef(err, "snapshotting volume %q", p.VolumeId)
+			continue
+		}
+		results[i].SnapshotId = snapshotId
+		results[i].Size = uint64(sizeGiB * 1024)
+	}
+	return results, nil
+}
+
+// CreateSnapshot is part of the OpenstackStorage interface. The cinder
+// client does not support creating snapshots, so the request is sent
+// directly. Snapshots are forced, so that volumes may be snapshotted
+// while they are attached.
+func (ga *openstackStorageAdapter) CreateSnapshot(volumeId, name string, metadata map[string]string) (string, int, error) {
+	var req struct {
+		Snapshot struct {
+			VolumeId string            `json:"volume_id"`
+			Name     string            `json:"name"`
+			Force    bool              `json:"force"`
+			Metadata map[string]string `json:"metadata,omitempty"`
+		} `json:"snapshot"`
+	}
+	req.Snapshot.VolumeId = volumeId
+	req.Snapshot.Name = name
+	req.Snapshot.Force = true
+	req.Snapshot.Metadata = metadata
+	var resp struct {
+		Snapshot struct {
+			Id   string `json:"id"`
+			Size int    `json:"size"`
+		} `json:"snapshot"`
+	}
+	requestData := goosehttp.RequestData{
+		ReqValue:       req,
+		RespValue:      &resp,
+		ExpectedStatus: []int{http.StatusAccepted},
+	}
+	err := ga.volumeRequester.SendRequest(client.POST, "volumev2", "v2", "snapshots", &requestData)
+	if IsNotFoundError(err) {
+		return "", 0, errors.NotFoundf("volume %q", volumeId)
+	} else if err != nil {
+		return "", 0, err
+	}
+	return resp.Snapshot.Id, resp.Snapshot.Size, nil
+}
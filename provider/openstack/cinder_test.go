@@ -274,6 +274,47 @@ func (s *cinderVolumeSourceSuite) TestCreateVolumeVolumeType(c *gc.C) {
 	c.Assert(created, jc.IsTrue)
 }
 
+func (s *cinderVolumeSourceSuite) TestCreateVolumeEncrypted(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+
+	mockAdapter := &mockAdapter{
+		createVolume: func(args cinder.CreateVolumeVolumeParams) (*cinder.Volume, error) {
+			c.Assert(args.VolumeType, gc.Equals, "LUKS")
+			return &cinder.Volume{ID: mockVolId}, nil
+		},
+		getVolume: func(volumeId string) (*cinder.Volume, error) {
+			return &cinder.Volume{
+				ID:     volumeId,
+				Size:   1,
+				Status: "available",
+			}, nil
+		},
+	}
+
+	volSource := openstack.NewCinderVolumeSource(mockAdapter, s.env)
+	results, err := volSource.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Provider: openstack.CinderProviderType,
+		Tag:      mockVolumeTag,
+		Size:     1024,
+		Attributes: map[string]interface{}{
+			"volume-type": "LUKS",
+			"encrypted":   "true",
+		},
+	}, {
+		Provider: openstack.CinderProviderType,
+		Tag:      mockVolumeTag,
+		Size:     1024,
+		Attributes: map[string]interface{}{
+			"encrypted": true,
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume.Encrypted, jc.IsTrue)
+	c.Assert(results[1].Error, gc.ErrorMatches, `"encrypted" requires an encrypted "volume-type" to be specified`)
+}
+
 func (s *cinderVolumeSourceSuite) TestCreateVolumeInvalidCredential(c *gc.C) {
 	defer s.setupMocks(c).Finish()
 
@@ -436,6 +477,88 @@ func (s *cinderVolumeSourceSuite) TestDestroyVolumes(c *gc.C) {
 	})
 }
 
+func (s *cinderVolumeSourceSuite) TestResizeVolumes(c *gc.C) {
+	mockAdapter := &mockAdapter{
+		extendVolume: func(volId string, size int) error {
+			if volId == "bad" {
+				return errors.New("badness")
+			}
+			return nil
+		},
+	}
+	volSource := openstack.NewCinderVolumeSource(mockAdapter, s.env)
+	results, err := volSource.(storage.VolumeResizer).ResizeVolumes(s.callCtx, []storage.VolumeResizeParams{{
+		VolumeId: mockVolId, Size: 2049,
+	}, {
+		VolumeId: "bad", Size: 2048,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0], jc.DeepEquals, storage.ResizeResult{Size: 3072})
+	c.Assert(results[1].Error, gc.ErrorMatches, `extending volume "bad": badness`)
+	mockAdapter.CheckCalls(c, []gitjujutesting.StubCall{
+		{"ExtendVolume", []interface{}{mockVolId, 3}},
+		{"ExtendVolume", []interface{}{"bad", 2}},
+	})
+}
+
+func (s *cinderVolumeSourceSuite) TestCreateVolumeSnapshots(c *gc.C) {
+	mockAdapter := &mockAdapter{
+		createSnapshot: func(volId, name string, metadata map[string]string) (string, int, error) {
+			if volId == "bad" {
+				return "", 0, errors.New("badness")
+			}
+			return "snap-0", 2, nil
+		},
+	}
+	volSource := openstack.NewCinderVolumeSource(mockAdapter, s.env)
+	results, err := volSource.(storage.VolumeSnapshotter).CreateVolumeSnapshots(s.callCtx, []storage.VolumeSnapshotParams{{
+		Tag:          mockVolumeTag,
+		VolumeId:     mockVolId,
+		Snapshot:     "1",
+		ResourceTags: map[string]string{"foo": "bar"},
+	}, {
+		Tag:      mockVolumeTag,
+		VolumeId: "bad",
+		Snapshot: "2",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0], jc.DeepEquals, storage.CreateVolumeSnapshotsResult{SnapshotId: "snap-0", Size: 2048})
+	c.Assert(results[1].Error, gc.ErrorMatches, `snapshotting volume "bad": badness`)
+	mockAdapter.CheckCallNames(c, "CreateSnapshot", "CreateSnapshot")
+	c.Assert(mockAdapter.Calls()[0].Args[0], gc.Equals, mockVolId)
+	c.Assert(mockAdapter.Calls()[0].Args[2], jc.DeepEquals, map[string]string{"foo": "bar"})
+}
+
+func (s *cinderVolumeSourceSuite) TestCreateVolumeFromSnapshot(c *gc.C) {
+	var created cinder.CreateVolumeVolumeParams
+	mockAdapter := &mockAdapter{
+		createVolume: func(args cinder.CreateVolumeVolumeParams) (*cinder.Volume, error) {
+			created = args
+			return &cinder.Volume{ID: mockVolId}, nil
+		},
+		getVolume: func(volumeId string) (*cinder.Volume, error) {
+			return &cinder.Volume{
+				ID:     volumeId,
+				Size:   1,
+				Status: "available",
+			}, nil
+		},
+	}
+	volSource := openstack.NewCinderVolumeSource(mockAdapter, s.env)
+	results, err := volSource.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Provider:   openstack.CinderProviderType,
+		Tag:        mockVolumeTag,
+		Size:       1024,
+		SnapshotId: "snap-0",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(created.SnapshotId, gc.Equals, "snap-0")
+}
+
 func (s *cinderVolumeSourceSuite) TestDestroyVolumesNotFound(c *gc.C) {
 	mockAdapter := &mockAdapter{
 		getVolume: func(volId string) (*cinder.Volume, error) {
@@ -770,6 +893,9 @@ type mockAdapter struct {
 	detachVolume          func(string, string) error
 	listVolumeAttachments func(string) ([]nova.VolumeAttachment, error)
 	setVolumeMetadata     func(string, map[string]string) (map[string]string, error)
+	deleteVolumeMetadata  func(string, string) error
+	extendVolume          func(string, int) error
+	createSnapshot        func(string, string, map[string]string) (string, int, error)
 }
 
 func (ma *mockAdapter) GetVolume(volumeId string) (*cinder.Volume, error) {
@@ -839,6 +965,30 @@ func (ma *mockAdapter) SetVolumeMetadata(volumeId string, metadata map[string]st
 	return nil, nil
 }
 
+func (ma *mockAdapter) DeleteVolumeMetadata(volumeId, key string) error {
+	ma.MethodCall(ma, "DeleteVolumeMetadata", volumeId, key)
+	if ma.deleteVolumeMetadata != nil {
+		return ma.deleteVolumeMetadata(volumeId, key)
+	}
+	return nil
+}
+
+func (ma *mockAdapter) ExtendVolume(volumeId string, newSizeGiB int) error {
+	ma.MethodCall(ma, "ExtendVolume", volumeId, newSizeGiB)
+	if ma.extendVolume != nil {
+		return ma.extendVolume(volumeId, newSizeGiB)
+	}
+	return nil
+}
+
+func (ma *mockAdapter) CreateSnapshot(volumeId, name string, metadata map[string]string) (string, int, error) {
+	ma.MethodCall(ma, "CreateSnapshot", volumeId, name, metadata)
+	if ma.createSnapshot != nil {
+		return ma.createSnapshot(volumeId, name, metadata)
+	}
+	return "", 0, nil
+}
+
 type testEndpointResolver struct {
 	authenticated   bool
 	regionEndpoints map[string]identity.ServiceURLs
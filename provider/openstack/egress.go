@@ -0,0 +1,195 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+
+	"github.com/juju/errors"
+	"gopkg.in/goose.v2/neutron"
+
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/network"
+)
+
+var _ environs.EgressFirewaller = (*Environ)(nil)
+
+// egressFirewaller is implemented by Firewallers which can restrict
+// egress from instances.
+type egressFirewaller interface {
+	// SetInstanceEgressRules restricts egress from the instance started
+	// with the given machine id to the given rules, or lifts the
+	// restriction if there are no rules.
+	SetInstanceEgressRules(ctx context.ProviderCallContext, machineId string, instId instance.Id, rules []network.EgressRule) error
+}
+
+// SetEgressRules is part of the environs.EgressFirewaller interface.
+func (e *Environ) SetEgressRules(ctx context.ProviderCallContext, machineId string, instId instance.Id, rules []network.EgressRule) error {
+	fw, ok := e.firewaller.(egressFirewaller)
+	if !ok {
+		return errors.NotSupportedf("egress rules")
+	}
+	if err := fw.SetInstanceEgressRules(ctx, machineId, instId, rules); err != nil {
+		handleCredentialError(err, ctx)
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// SetInstanceEgressRules implements egressFirewaller.
+func (f *switchingFirewaller) SetInstanceEgressRules(ctx context.ProviderCallContext, machineId string, instId instance.Id, rules []network.EgressRule) error {
+	if err := f.initFirewaller(ctx); err != nil {
+		return errors.Trace(err)
+	}
+	fw, ok := f.fw.(egressFirewaller)
+	if !ok {
+		return errors.NotSupportedf("egress rules without neutron")
+	}
+	return fw.SetInstanceEgressRules(ctx, machineId, instId, rules)
+}
+
+// SetInstanceEgressRules implements egressFirewaller. The egress rules
+// of the machine's security group are replaced with the given rules,
+// or with the rules Neutron gives new groups, which allow all egress,
+// if there are none. Security group rules are a union, so the egress
+// rules of the model's security group are deleted too; instances in
+// groups not managed by Juju, such as the tenant's default group, are
+// refused as their egress cannot be restricted without affecting other
+// servers. New rules are created before the old ones are deleted, so
+// egress that is allowed before and after the change is never
+// interrupted.
+func (c *neutronFirewaller) SetInstanceEgressRules(ctx context.ProviderCallContext, machineId string, instId instance.Id, rules []network.EgressRule) error {
+	if c.environ.Config().FirewallMode() != config.FwInstance {
+		return errors.Errorf("invalid firewall mode %q for setting egress rules",
+			c.environ.Config().FirewallMode())
+	}
+	machineGroup, otherGroups, err := c.instanceEgressGroups(machineId, instId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	want := newRuleInfoSetFromRuleInfo(egressRulesToRuleInfo(rules))
+
+	neutronClient := c.environ.neutron()
+	have := egressRuleInfoSet(machineGroup)
+	for k := range want {
+		if _, ok := have[k]; ok {
+			continue
+		}
+		k.ParentGroupId = machineGroup.Id
+		if _, err := neutronClient.CreateSecurityGroupRuleV2(k); err != nil {
+			return errors.Annotate(err, "creating egress rule")
+		}
+	}
+	for _, group := range otherGroups {
+		for _, id := range egressRuleInfoSet(group) {
+			if err := neutronClient.DeleteSecurityGroupRuleV2(id); err != nil {
+				return errors.Annotatef(err, "deleting egress rule of security group %q", group.Name)
+			}
+		}
+	}
+	for k, id := range have {
+		if _, ok := want[k]; ok {
+			continue
+		}
+		if err := neutronClient.DeleteSecurityGroupRuleV2(id); err != nil {
+			return errors.Annotate(err, "deleting egress rule")
+		}
+	}
+	logger.Infof("set egress rules of security group %s: %v", machineGroup.Name, rules)
+	return nil
+}
+
+// instanceEgressGroups returns the machine security group of the given
+// instance, and the other security groups it belongs to. An error
+// satisfying errors.IsNotSupported is returned if the instance belongs
+// to a group not managed by Juju.
+func (c *neutronFirewaller) instanceEgressGroups(machineId string, instId instance.Id) (neutron.SecurityGroupV2, []neutron.SecurityGroupV2, error) {
+	serverGroups, err := c.environ.nova().GetServerSecurityGroups(string(instId))
+	if err != nil {
+		return neutron.SecurityGroupV2{}, nil, errors.Annotate(err, "getting instance security groups")
+	}
+	allGroups, err := c.environ.neutron().ListSecurityGroupsV2()
+	if err != nil {
+		return neutron.SecurityGroupV2{}, nil, errors.Trace(err)
+	}
+	machineRe := regexp.MustCompile(c.machineGroupRegexp(machineId))
+	modelRe := regexp.MustCompile(fmt.Sprintf("^%s$", c.jujuGroupRegexp()))
+	var (
+		machineGroup *neutron.SecurityGroupV2
+		otherGroups  []neutron.SecurityGroupV2
+	)
+	for _, serverGroup := range serverGroups {
+		isMachineGroup := machineRe.MatchString(serverGroup.Name)
+		if !isMachineGroup && !modelRe.MatchString(serverGroup.Name) {
+			return neutron.SecurityGroupV2{}, nil, errors.NotSupportedf(
+				"egress rules for instances in security group %q", serverGroup.Name)
+		}
+		var matching []neutron.SecurityGroupV2
+		for _, group := range allGroups {
+			if group.Name == serverGroup.Name {
+				matching = append(matching, group)
+			}
+		}
+		if len(matching) != 1 {
+			return neutron.SecurityGroupV2{}, nil, errors.Errorf(
+				"%d security groups found named %q, expected 1", len(matching), serverGroup.Name)
+		}
+		if isMachineGroup {
+			machineGroup = &matching[0]
+		} else {
+			otherGroups = append(otherGroups, matching[0])
+		}
+	}
+	if machineGroup == nil {
+		return neutron.SecurityGroupV2{}, nil, errors.NotFoundf("machine security group of instance %q", instId)
+	}
+	return *machineGroup, otherGroups, nil
+}
+
+// egressRuleInfoSet returns the egress rules of the security group.
+func egressRuleInfoSet(group neutron.SecurityGroupV2) ruleInfoSet {
+	result := make(ruleInfoSet)
+	for k, id := range newRuleInfoSetFromRules(group.Rules) {
+		if k.Direction == "egress" {
+			result[k] = id
+		}
+	}
+	return result
+}
+
+// egressRulesToRuleInfo returns the security group rules which allow
+// the given egress rules, or all egress if there are none.
+func egressRulesToRuleInfo(rules []network.EgressRule) []neutron.RuleInfoV2 {
+	if len(rules) == 0 {
+		return []neutron.RuleInfoV2{
+			{Direction: "egress", EthernetType: "IPv4"},
+			{Direction: "egress", EthernetType: "IPv6"},
+		}
+	}
+	var result []neutron.RuleInfoV2
+	for _, r := range rules {
+		for _, cidr := range r.DestinationCIDRs {
+			ruleInfo := neutron.RuleInfoV2{
+				Direction:      "egress",
+				IPProtocol:     r.Protocol,
+				RemoteIPPrefix: cidr,
+				EthernetType:   "IPv4",
+			}
+			if r.Protocol != "icmp" {
+				ruleInfo.PortRangeMin = r.FromPort
+				ruleInfo.PortRangeMax = r.ToPort
+			}
+			if ip, _, err := net.ParseCIDR(cidr); err == nil && ip.To4() == nil {
+				ruleInfo.EthernetType = "IPv6"
+			}
+			result = append(result, ruleInfo)
+		}
+	}
+	return result
+}
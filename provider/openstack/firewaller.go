@@ -930,12 +930,20 @@ func secGroupMatchesIngressRule(secGroupRule neutron.SecurityGroupRuleV2, rule n
 	// The ports match, so if the security group RemoteIPPrefix matches *any* of the
 	// rule's source ranges, then that's a match.
 	if len(rule.SourceCIDRs) == 0 {
-		return secGroupRule.RemoteIPPrefix == "" || secGroupRule.RemoteIPPrefix == "0.0.0.0/0"
+		switch secGroupRule.RemoteIPPrefix {
+		case "", "0.0.0.0/0", "::/0":
+			return true
+		}
+		return false
 	}
 	for _, r := range rule.SourceCIDRs {
 		if r == secGroupRule.RemoteIPPrefix {
 			return true
 		}
+		// Rules open to all IPv4 traffic are also opened to IPv6.
+		if r == "0.0.0.0/0" && secGroupRule.RemoteIPPrefix == "::/0" {
+			return true
+		}
 	}
 	return false
 }
@@ -991,6 +999,9 @@ func (c *neutronFirewaller) ingressRulesInGroup(ctx context.ProviderCallContext,
 		remotePrefix := p.RemoteIPPrefix
 		if remotePrefix == "" {
 			remotePrefix = "0.0.0.0/0"
+			if p.EthernetType == "IPv6" {
+				remotePrefix = "::/0"
+			}
 		}
 		sourceCIDRs, ok := portSourceCIDRs[portRange]
 		if !ok {
@@ -1001,6 +1012,9 @@ func (c *neutronFirewaller) ingressRulesInGroup(ctx context.ProviderCallContext,
 	}
 	// Combine all the port ranges and remote prefixes.
 	for portRange, sourceCIDRs := range portSourceCIDRs {
+		// Ports opened to all traffic have rules for both IPv4 and
+		// IPv6, but are reported as they were requested.
+		*sourceCIDRs = foldOpenIPv6(*sourceCIDRs)
 		rule, err := network.NewIngressRule(
 			portRange.Protocol,
 			portRange.FromPort,
@@ -1015,6 +1029,27 @@ func (c *neutronFirewaller) ingressRulesInGroup(ctx context.ProviderCallContext,
 	return rules, nil
 }
 
+// foldOpenIPv6 removes "::/0" from the source CIDRs if they
+// also include "0.0.0.0/0".
+func foldOpenIPv6(sourceCIDRs []string) []string {
+	openIPv4 := false
+	for _, cidr := range sourceCIDRs {
+		if cidr == "0.0.0.0/0" {
+			openIPv4 = true
+		}
+	}
+	if !openIPv4 {
+		return sourceCIDRs
+	}
+	var result []string
+	for _, cidr := range sourceCIDRs {
+		if cidr != "::/0" {
+			result = append(result, cidr)
+		}
+	}
+	return result
+}
+
 func replaceControllerUUID(oldName, controllerUUID string) (string, error) {
 	if !extractControllerRe.MatchString(oldName) {
 		return "", errors.Errorf("unexpected security group name format for %q", oldName)
@@ -6,6 +6,7 @@ package openstack
 import (
 	"gopkg.in/goose.v2/nova"
 
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/instances"
 )
@@ -53,6 +54,25 @@ func findInstanceSpec(
 		ic.Constraints.RootDisk = nil
 	}
 
+	allInstanceTypes := e.flavorInstanceTypes(flavors, ic.Arches, ic.Constraints)
+	images := instances.ImageMetadataToImages(imageMetadata)
+	spec, err := instances.FindInstanceSpec(images, &ic, allInstanceTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	// If instance constraints did not have a virtualisation type,
+	// but image metadata did, we will have an instance type
+	// with virtualisation type of an image.
+	if !ic.Constraints.HasVirtType() && spec.Image.VirtType != "" {
+		spec.InstanceType.VirtType = &spec.Image.VirtType
+	}
+	return spec, nil
+}
+
+// flavorInstanceTypes returns the instance types corresponding to the
+// acceptable flavors.
+func (e *Environ) flavorInstanceTypes(flavors []nova.FlavorDetail, arches []string, cons constraints.Value) []instances.InstanceType {
 	// Not all needed information is available in flavors,
 	// for e.g. architectures or virtualisation types.
 	// For these properties, we assume that all instance types support
@@ -65,31 +85,18 @@ func findInstanceSpec(
 		instanceType := instances.InstanceType{
 			Id:       flavor.Id,
 			Name:     flavor.Name,
-			Arches:   ic.Arches,
+			Arches:   arches,
 			Mem:      uint64(flavor.RAM),
 			CpuCores: uint64(flavor.VCPUs),
 			RootDisk: uint64(flavor.Disk * 1024),
 			// tags not currently supported on openstack
 		}
-		if ic.Constraints.HasVirtType() {
+		if cons.HasVirtType() {
 			// Instance Type virtual type depends on the virtual type of the selected image, i.e.
 			// picking an image with a virt type gives a machine with this virt type.
-			instanceType.VirtType = ic.Constraints.VirtType
+			instanceType.VirtType = cons.VirtType
 		}
 		allInstanceTypes = append(allInstanceTypes, instanceType)
 	}
-
-	images := instances.ImageMetadataToImages(imageMetadata)
-	spec, err := instances.FindInstanceSpec(images, &ic, allInstanceTypes)
-	if err != nil {
-		return nil, err
-	}
-
-	// If instance constraints did not have a virtualisation type,
-	// but image metadata did, we will have an instance type
-	// with virtualisation type of an image.
-	if !ic.Constraints.HasVirtType() && spec.Image.VirtType != "" {
-		spec.InstanceType.VirtType = &spec.Image.VirtType
-	}
-	return spec, nil
+	return allInstanceTypes
 }
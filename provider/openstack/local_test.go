@@ -64,6 +64,7 @@ import (
 	"github.com/juju/juju/juju/keys"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/jujuclient"
+	jujunetwork "github.com/juju/juju/network"
 	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/provider/openstack"
 	"github.com/juju/juju/storage"
@@ -1794,6 +1795,71 @@ func (s *localServerSuite) TestMatchingGroup(c *gc.C) {
 	c.Assert(group2.Id, gc.Equals, groupMatched.Id)
 }
 
+func (s *localServerSuite) TestSetEgressRules(c *gc.C) {
+	env := s.openEnviron(c, coretesting.Attrs{"firewall-mode": config.FwInstance})
+	err := bootstrapEnv(c, env)
+	c.Assert(err, jc.ErrorIsNil)
+	inst, _ := testing.AssertStartInstance(c, env, s.callCtx, s.ControllerUUID, "1")
+	groupEgressRules := func(nameRegexp string) []neutron.RuleInfoV2 {
+		group, err := openstack.MatchingGroup(env, s.callCtx, nameRegexp)
+		c.Assert(err, jc.ErrorIsNil)
+		var rules []neutron.RuleInfoV2
+		for _, rule := range ruleToRuleInfo(group.Rules) {
+			if rule.Direction == "egress" {
+				rules = append(rules, rule)
+			}
+		}
+		return rules
+	}
+	machineGroupRegexp := openstack.MachineGroupRegexp(env, "1")
+	modelGroupRegexp := fmt.Sprintf("^juju-%v-%v$", s.ControllerUUID, env.Config().UUID())
+	defaultRules := []neutron.RuleInfoV2{
+		{Direction: "egress", EthernetType: "IPv4"},
+		{Direction: "egress", EthernetType: "IPv6"},
+	}
+	c.Assert(groupEgressRules(machineGroupRegexp), jc.SameContents, defaultRules)
+	c.Assert(groupEgressRules(modelGroupRegexp), jc.SameContents, defaultRules)
+
+	egressFirewaller, ok := env.(environs.EgressFirewaller)
+	c.Assert(ok, jc.IsTrue)
+	err = egressFirewaller.SetEgressRules(s.callCtx, "1", inst.Id(), []jujunetwork.EgressRule{
+		jujunetwork.MustNewEgressRule("tcp", 5432, 5432, "10.0.0.0/8"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groupEgressRules(machineGroupRegexp), jc.SameContents, []neutron.RuleInfoV2{{
+		Direction:      "egress",
+		IPProtocol:     "tcp",
+		PortRangeMin:   5432,
+		PortRangeMax:   5432,
+		RemoteIPPrefix: "10.0.0.0/8",
+		EthernetType:   "IPv4",
+	}})
+	// The model group would otherwise allow all egress from the instance.
+	c.Assert(groupEgressRules(modelGroupRegexp), gc.HasLen, 0)
+
+	err = egressFirewaller.SetEgressRules(s.callCtx, "1", inst.Id(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groupEgressRules(machineGroupRegexp), jc.SameContents, defaultRules)
+	c.Assert(groupEgressRules(modelGroupRegexp), gc.HasLen, 0)
+}
+
+func (s *localServerSuite) TestSetEgressRulesDefaultSecurityGroup(c *gc.C) {
+	env := s.openEnviron(c, coretesting.Attrs{
+		"firewall-mode":        config.FwInstance,
+		"use-default-secgroup": true,
+	})
+	err := bootstrapEnv(c, env)
+	c.Assert(err, jc.ErrorIsNil)
+	inst, _ := testing.AssertStartInstance(c, env, s.callCtx, s.ControllerUUID, "1")
+
+	// The tenant's default group is shared with servers outside the
+	// model, so its egress cannot be restricted.
+	err = env.(environs.EgressFirewaller).SetEgressRules(s.callCtx, "1", inst.Id(), []jujunetwork.EgressRule{
+		jujunetwork.MustNewEgressRule("tcp", 5432, 5432, "10.0.0.0/8"),
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 // localHTTPSServerSuite contains tests that run against an Openstack service
 // double connected on an HTTPS port with a self-signed certificate. This
 // service is set up and torn down for every test.  This should only test
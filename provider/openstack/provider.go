@@ -9,6 +9,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"net"
 	"net/url"
 	"path"
 	"sort"
@@ -543,6 +544,8 @@ func (e *Environ) neutron() *neutron.Client {
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.CpuPower,
+	constraints.Spot,
+	constraints.MaxPrice,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -1230,13 +1233,21 @@ func (e *Environ) startInstance(
 		return server, nil
 	}
 
+	// serverGroupId, if set, holds the id of the server group
+	// realising the machine's anti-affinity constraint.
+	var serverGroupId string
+
 	tryStartNovaInstance := func(
 		attempts utils.AttemptStrategy,
 		client *nova.Client,
 		instanceOpts nova.RunServerOpts,
 	) (server *nova.Entity, err error) {
 		for a := attempts.Start(); a.Next(); {
-			server, err = client.RunServer(instanceOpts)
+			if serverGroupId != "" {
+				server, err = runServerInGroup(e.client(), instanceOpts, serverGroupId)
+			} else {
+				server, err = client.RunServer(instanceOpts)
+			}
 			if err != nil {
 				break
 			}
@@ -1287,6 +1298,13 @@ func (e *Environ) startInstance(
 	}
 	e.configurator.ModifyRunServerOptions(&opts)
 
+	if args.Constraints.HasAntiAffinity() {
+		serverGroupId, err = e.ensureServerGroup(ctx, *args.Constraints.AntiAffinity)
+		if err != nil {
+			return nil, common.ZoneIndependentError(err)
+		}
+	}
+
 	server, err := tryStartNovaInstance(shortAttempt, e.nova(), opts)
 	if err != nil || server == nil {
 		// 'No valid host available' is typically a resource error,
@@ -1823,6 +1841,7 @@ func (e *Environ) Destroy(ctx context.ProviderCallContext) error {
 		handleCredentialError(err, ctx)
 		return errors.Trace(err)
 	}
+	e.deleteServerGroups(ctx)
 	return nil
 }
 
@@ -1914,18 +1933,42 @@ func rulesToRuleInfo(groupId string, rules []network.IngressRule) []neutron.Rule
 			PortRangeMax:  r.ToPort,
 			IPProtocol:    r.Protocol,
 		}
-		sourceCIDRs := r.SourceCIDRs
-		if len(sourceCIDRs) == 0 {
-			sourceCIDRs = []string{"0.0.0.0/0"}
-		}
+		sourceCIDRs := openIPv6(r.SourceCIDRs)
 		for _, sr := range sourceCIDRs {
 			ruleInfo.RemoteIPPrefix = sr
+			ruleInfo.EthernetType = ""
+			// Neutron rejects IPv6 prefixes unless the rule
+			// is explicitly for IPv6 traffic.
+			if ip, _, err := net.ParseCIDR(sr); err == nil && ip.To4() == nil {
+				ruleInfo.EthernetType = "IPv6"
+			}
 			result = append(result, ruleInfo)
 		}
 	}
 	return result
 }
 
+// openIPv6 returns the source CIDRs of a rule, adding "::/0" if it
+// is open to all IPv4 traffic, so that it is open to IPv6 traffic too.
+func openIPv6(sourceCIDRs []string) []string {
+	if len(sourceCIDRs) == 0 {
+		return []string{"0.0.0.0/0", "::/0"}
+	}
+	var openIPv4, openIPv6 bool
+	for _, cidr := range sourceCIDRs {
+		switch cidr {
+		case "0.0.0.0/0":
+			openIPv4 = true
+		case "::/0":
+			openIPv6 = true
+		}
+	}
+	if !openIPv4 || openIPv6 {
+		return sourceCIDRs
+	}
+	return append(append([]string(nil), sourceCIDRs...), "::/0")
+}
+
 func (e *Environ) OpenPorts(ctx context.ProviderCallContext, rules []network.IngressRule) error {
 	if err := e.firewaller.OpenPorts(ctx, rules); err != nil {
 		handleCredentialError(err, ctx)
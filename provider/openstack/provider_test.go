@@ -211,6 +211,14 @@ func (*localTests) TestPortsToRuleInfo(c *gc.C) {
 			PortRangeMax:   80,
 			RemoteIPPrefix: "0.0.0.0/0",
 			ParentGroupId:  groupId,
+		}, {
+			Direction:      "ingress",
+			IPProtocol:     "tcp",
+			PortRangeMin:   80,
+			PortRangeMax:   80,
+			RemoteIPPrefix: "::/0",
+			EthernetType:   "IPv6",
+			ParentGroupId:  groupId,
 		}},
 	}, {
 		about: "multiple ports",
@@ -222,6 +230,14 @@ func (*localTests) TestPortsToRuleInfo(c *gc.C) {
 			PortRangeMax:   82,
 			RemoteIPPrefix: "0.0.0.0/0",
 			ParentGroupId:  groupId,
+		}, {
+			Direction:      "ingress",
+			IPProtocol:     "tcp",
+			PortRangeMin:   80,
+			PortRangeMax:   82,
+			RemoteIPPrefix: "::/0",
+			EthernetType:   "IPv6",
+			ParentGroupId:  groupId,
 		}},
 	}, {
 		about: "multiple port ranges",
@@ -236,6 +252,14 @@ func (*localTests) TestPortsToRuleInfo(c *gc.C) {
 			PortRangeMax:   82,
 			RemoteIPPrefix: "0.0.0.0/0",
 			ParentGroupId:  groupId,
+		}, {
+			Direction:      "ingress",
+			IPProtocol:     "tcp",
+			PortRangeMin:   80,
+			PortRangeMax:   82,
+			RemoteIPPrefix: "::/0",
+			EthernetType:   "IPv6",
+			ParentGroupId:  groupId,
 		}, {
 			Direction:      "ingress",
 			IPProtocol:     "tcp",
@@ -243,6 +267,14 @@ func (*localTests) TestPortsToRuleInfo(c *gc.C) {
 			PortRangeMax:   120,
 			RemoteIPPrefix: "0.0.0.0/0",
 			ParentGroupId:  groupId,
+		}, {
+			Direction:      "ingress",
+			IPProtocol:     "tcp",
+			PortRangeMin:   100,
+			PortRangeMax:   120,
+			RemoteIPPrefix: "::/0",
+			EthernetType:   "IPv6",
+			ParentGroupId:  groupId,
 		}},
 	}, {
 		about: "source range",
@@ -262,6 +294,34 @@ func (*localTests) TestPortsToRuleInfo(c *gc.C) {
 			PortRangeMax:   100,
 			RemoteIPPrefix: "0.0.0.0/0",
 			ParentGroupId:  groupId,
+		}, {
+			Direction:      "ingress",
+			IPProtocol:     "tcp",
+			PortRangeMin:   80,
+			PortRangeMax:   100,
+			RemoteIPPrefix: "::/0",
+			EthernetType:   "IPv6",
+			ParentGroupId:  groupId,
+		}},
+	}, {
+		about: "dual-stack source range",
+		rules: []network.IngressRule{network.MustNewIngressRule(
+			"tcp", 443, 443, "192.168.1.0/24", "2001:db8::/64")},
+		expected: []neutron.RuleInfoV2{{
+			Direction:      "ingress",
+			IPProtocol:     "tcp",
+			PortRangeMin:   443,
+			PortRangeMax:   443,
+			RemoteIPPrefix: "192.168.1.0/24",
+			ParentGroupId:  groupId,
+		}, {
+			Direction:      "ingress",
+			IPProtocol:     "tcp",
+			PortRangeMin:   443,
+			PortRangeMax:   443,
+			RemoteIPPrefix: "2001:db8::/64",
+			EthernetType:   "IPv6",
+			ParentGroupId:  groupId,
 		}},
 	}}
 
@@ -350,6 +410,26 @@ func (*localTests) TestSecGroupMatchesIngressRule(c *gc.C) {
 			RemoteIPPrefix: "0.0.0.0/0",
 		},
 		expected: true,
+	}, {
+		about: "default IPv6 RemoteIPPrefix",
+		rule:  network.MustNewIngressRule(proto_tcp, 80, 85),
+		secGroupRule: neutron.SecurityGroupRuleV2{
+			IPProtocol:     &proto_tcp,
+			PortRangeMin:   &port_80,
+			PortRangeMax:   &port_85,
+			RemoteIPPrefix: "::/0",
+		},
+		expected: true,
+	}, {
+		about: "IPv6 RemoteIPPrefix of rule open to all IPv4",
+		rule:  network.MustNewIngressRule(proto_tcp, 80, 85, "0.0.0.0/0", "192.168.1.0/24"),
+		secGroupRule: neutron.SecurityGroupRuleV2{
+			IPProtocol:     &proto_tcp,
+			PortRangeMin:   &port_80,
+			PortRangeMax:   &port_85,
+			RemoteIPPrefix: "::/0",
+		},
+		expected: true,
 	}, {
 		about: "matching RemoteIPPrefix",
 		rule:  network.MustNewIngressRule(proto_tcp, 80, 85, "0.0.0.0/0", "192.168.1.0/24"),
@@ -0,0 +1,147 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/goose.v2/client"
+	goosehttp "gopkg.in/goose.v2/http"
+
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/instances"
+)
+
+var _ environs.InstanceResizer = (*Environ)(nil)
+
+// resizeAttempt is used to poll for a server resize to be ready
+// for confirmation.
+var resizeAttempt = utils.AttemptStrategy{
+	Total: 10 * time.Minute,
+	Delay: 5 * time.Second,
+}
+
+// ResizeInstance is part of the environs.InstanceResizer interface.
+// The server is resized to the smallest flavor satisfying the constraints,
+// which nova does by migrating and restarting it.
+func (e *Environ) ResizeInstance(ctx context.ProviderCallContext, id instance.Id, cons constraints.Value) (*instance.HardwareCharacteristics, error) {
+	flavors, err := e.nova().ListFlavorsDetail()
+	if err != nil {
+		handleCredentialError(err, ctx)
+		return nil, errors.Trace(err)
+	}
+	usingVolumeRootDisk := cons.HasRootDiskSource() && *cons.RootDiskSource == rootDiskSourceVolume
+	if usingVolumeRootDisk {
+		// As when starting an instance, the root disk
+		// volume is not part of the flavor.
+		cons.RootDisk = nil
+	}
+	var arches []string
+	if cons.HasArch() {
+		arches = []string{*cons.Arch}
+	}
+	matching, err := instances.MatchingInstanceTypes(e.flavorInstanceTypes(flavors, arches, cons), e.cloud().Region, cons)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	flavor := matching[0]
+	if err := resizeServer(e.client(), string(id), flavor.Id); err != nil {
+		handleCredentialError(err, ctx)
+		return nil, errors.Trace(err)
+	}
+	hc := &instance.HardwareCharacteristics{
+		Mem:      &flavor.Mem,
+		CpuCores: &flavor.CpuCores,
+	}
+	if !usingVolumeRootDisk {
+		hc.RootDisk = &flavor.RootDisk
+	}
+	return hc, nil
+}
+
+// serverResizeState holds the attributes of a server
+// relevant to resizing it.
+type serverResizeState struct {
+	Status string `json:"status"`
+	Flavor struct {
+		Id string `json:"id"`
+	} `json:"flavor"`
+}
+
+// The nova client library does not support resizing servers, so the
+// requests are made directly against the compute API.
+
+// resizeServer resizes the given server to the given flavor, and confirms
+// the resize once nova has completed it.
+func resizeServer(c requestSender, serverId, flavorId string) error {
+	server, err := getServerResizeState(c, serverId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if server.Flavor.Id == flavorId {
+		logger.Debugf("server %q already has flavor %q", serverId, flavorId)
+		return nil
+	}
+
+	logger.Infof("resizing server %q to flavor %q", serverId, flavorId)
+	var req struct {
+		Resize struct {
+			FlavorRef string `json:"flavorRef"`
+		} `json:"resize"`
+	}
+	req.Resize.FlavorRef = flavorId
+	if err := serverAction(c, serverId, req); err != nil {
+		return errors.Annotatef(err, "resizing server %q", serverId)
+	}
+
+	var status string
+	for a := resizeAttempt.Start(); a.Next(); {
+		server, err := getServerResizeState(c, serverId)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		status = server.Status
+		switch status {
+		case "VERIFY_RESIZE":
+			confirm := map[string]interface{}{"confirmResize": nil}
+			return errors.Annotatef(serverAction(c, serverId, confirm), "confirming resize of server %q", serverId)
+		case "ERROR":
+			return errors.Errorf("resizing server %q failed", serverId)
+		case "ACTIVE", "SHUTOFF":
+			// The resize has not started yet, or nova
+			// confirmed it automatically.
+			if server.Flavor.Id == flavorId {
+				return nil
+			}
+		}
+	}
+	return errors.Errorf("timed out waiting for server %q to resize, last status %q", serverId, status)
+}
+
+// getServerResizeState returns the status and flavor of the given server.
+func getServerResizeState(c requestSender, serverId string) (serverResizeState, error) {
+	var resp struct {
+		Server serverResizeState `json:"server"`
+	}
+	requestData := goosehttp.RequestData{RespValue: &resp}
+	if err := c.SendRequest(client.GET, "compute", "v2", apiServers+"/"+serverId, &requestData); err != nil {
+		return serverResizeState{}, errors.Annotatef(err, "getting server %q", serverId)
+	}
+	return resp.Server, nil
+}
+
+// serverAction sends the given action request for a server.
+func serverAction(c requestSender, serverId string, action interface{}) error {
+	requestData := goosehttp.RequestData{
+		ReqValue:       action,
+		ExpectedStatus: []int{http.StatusAccepted, http.StatusNoContent},
+	}
+	return c.SendRequest(client.POST, "compute", "v2", apiServers+"/"+serverId+"/action", &requestData)
+}
@@ -0,0 +1,69 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+)
+
+type resizeSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&resizeSuite{})
+
+func (s *resizeSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.PatchValue(&resizeAttempt, utils.AttemptStrategy{Min: 3})
+}
+
+func (s *resizeSuite) TestResizeServer(c *gc.C) {
+	sender := &fakeRequestSender{responses: []string{
+		`{"server": {"status": "ACTIVE", "flavor": {"id": "small"}}}`,
+		`{"server": {"status": "RESIZE", "flavor": {"id": "small"}}}`,
+		`{"server": {"status": "VERIFY_RESIZE", "flavor": {"id": "large"}}}`,
+	}}
+	err := resizeServer(sender, "server-1", "large")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sender.requests, jc.DeepEquals, []sentRequest{
+		{"GET", "servers/server-1", ""},
+		{"POST", "servers/server-1/action", `{"resize":{"flavorRef":"large"}}`},
+		{"GET", "servers/server-1", ""},
+		{"GET", "servers/server-1", ""},
+		{"POST", "servers/server-1/action", `{"confirmResize":null}`},
+	})
+}
+
+func (s *resizeSuite) TestResizeServerUnchanged(c *gc.C) {
+	sender := &fakeRequestSender{responses: []string{
+		`{"server": {"status": "ACTIVE", "flavor": {"id": "large"}}}`,
+	}}
+	err := resizeServer(sender, "server-1", "large")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sender.requests, gc.HasLen, 1)
+}
+
+func (s *resizeSuite) TestResizeServerError(c *gc.C) {
+	sender := &fakeRequestSender{responses: []string{
+		`{"server": {"status": "ACTIVE", "flavor": {"id": "small"}}}`,
+		`{"server": {"status": "ERROR", "flavor": {"id": "small"}}}`,
+	}}
+	err := resizeServer(sender, "server-1", "large")
+	c.Assert(err, gc.ErrorMatches, `resizing server "server-1" failed`)
+}
+
+func (s *resizeSuite) TestResizeServerTimeout(c *gc.C) {
+	sender := &fakeRequestSender{responses: []string{
+		`{"server": {"status": "ACTIVE", "flavor": {"id": "small"}}}`,
+		`{"server": {"status": "RESIZE", "flavor": {"id": "small"}}}`,
+		`{"server": {"status": "RESIZE", "flavor": {"id": "small"}}}`,
+		`{"server": {"status": "RESIZE", "flavor": {"id": "small"}}}`,
+	}}
+	err := resizeServer(sender, "server-1", "large")
+	c.Assert(err, gc.ErrorMatches, `timed out waiting for server "server-1" to resize, last status "RESIZE"`)
+}
@@ -0,0 +1,146 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/goose.v2/client"
+	goosehttp "gopkg.in/goose.v2/http"
+	"gopkg.in/goose.v2/nova"
+
+	"github.com/juju/juju/environs/context"
+)
+
+// The nova client library does not support server groups or scheduler
+// hints, so the requests for them are made directly against the compute
+// API using the authenticated client.
+
+const (
+	apiServerGroups = "os-server-groups"
+	apiServers      = "servers"
+
+	antiAffinityPolicy = "anti-affinity"
+)
+
+// requestSender is the part of the goose client used to send compute
+// API requests.
+type requestSender interface {
+	SendRequest(method, svcType, apiVersion, url string, requestData *goosehttp.RequestData) error
+}
+
+// serverGroup describes a nova server group.
+type serverGroup struct {
+	Id       string   `json:"id,omitempty"`
+	Name     string   `json:"name"`
+	Policies []string `json:"policies"`
+}
+
+// serverGroupName returns the name of the nova server group realising
+// the given anti-affinity group in a model.
+func serverGroupName(modelUUID, group string) string {
+	return "juju-" + modelUUID + "-" + group
+}
+
+// listServerGroups returns the server groups whose names have the given
+// prefix.
+func listServerGroups(c requestSender, prefix string) ([]serverGroup, error) {
+	var resp struct {
+		ServerGroups []serverGroup `json:"server_groups"`
+	}
+	requestData := goosehttp.RequestData{RespValue: &resp}
+	if err := c.SendRequest(client.GET, "compute", "v2", apiServerGroups, &requestData); err != nil {
+		return nil, errors.Annotate(err, "listing server groups")
+	}
+	var groups []serverGroup
+	for _, group := range resp.ServerGroups {
+		if strings.HasPrefix(group.Name, prefix) {
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+// findOrCreateServerGroup returns the id of the anti-affinity server group with
+// the given name, creating it if it does not exist.
+func findOrCreateServerGroup(c requestSender, name string) (string, error) {
+	groups, err := listServerGroups(c, name)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	for _, group := range groups {
+		if group.Name == name {
+			return group.Id, nil
+		}
+	}
+	var req struct {
+		ServerGroup serverGroup `json:"server_group"`
+	}
+	req.ServerGroup = serverGroup{Name: name, Policies: []string{antiAffinityPolicy}}
+	var resp struct {
+		ServerGroup serverGroup `json:"server_group"`
+	}
+	requestData := goosehttp.RequestData{ReqValue: req, RespValue: &resp}
+	if err := c.SendRequest(client.POST, "compute", "v2", apiServerGroups, &requestData); err != nil {
+		return "", errors.Annotatef(err, "creating server group %q", name)
+	}
+	return resp.ServerGroup.Id, nil
+}
+
+// runServerInGroup starts a server as a member of the given server group,
+// so that nova's scheduler applies the group's policy when placing it.
+func runServerInGroup(c requestSender, opts nova.RunServerOpts, groupId string) (*nova.Entity, error) {
+	var req struct {
+		Server         nova.RunServerOpts `json:"server"`
+		SchedulerHints struct {
+			Group string `json:"group"`
+		} `json:"os:scheduler_hints"`
+	}
+	req.Server = opts
+	req.SchedulerHints.Group = groupId
+	var resp struct {
+		Server nova.Entity `json:"server"`
+	}
+	requestData := goosehttp.RequestData{
+		ReqValue:       req,
+		RespValue:      &resp,
+		ExpectedStatus: []int{http.StatusAccepted},
+	}
+	if err := c.SendRequest(client.POST, "compute", "v2", apiServers, &requestData); err != nil {
+		return nil, errors.Annotatef(err, "failed to run a server in server group %q", groupId)
+	}
+	return &resp.Server, nil
+}
+
+// ensureServerGroup returns the id of the server group realising the
+// given anti-affinity group in the model, creating it if necessary.
+func (e *Environ) ensureServerGroup(ctx context.ProviderCallContext, group string) (string, error) {
+	id, err := findOrCreateServerGroup(e.client(), serverGroupName(e.ecfg().UUID(), group))
+	if err != nil {
+		handleCredentialError(err, ctx)
+		return "", errors.Trace(err)
+	}
+	return id, nil
+}
+
+// deleteServerGroups deletes the model's server groups. Failures are
+// logged rather than returned, as not all clouds support server groups.
+func (e *Environ) deleteServerGroups(ctx context.ProviderCallContext) {
+	c := e.client()
+	groups, err := listServerGroups(c, serverGroupName(e.ecfg().UUID(), ""))
+	if err != nil {
+		handleCredentialError(err, ctx)
+		logger.Warningf("cannot delete server groups: %v", err)
+		return
+	}
+	for _, group := range groups {
+		requestData := goosehttp.RequestData{ExpectedStatus: []int{http.StatusNoContent}}
+		if err := c.SendRequest(client.DELETE, "compute", "v2", apiServerGroups+"/"+group.Id, &requestData); err != nil {
+			handleCredentialError(err, ctx)
+			logger.Warningf("cannot delete server group %q: %v", group.Name, err)
+		}
+	}
+}
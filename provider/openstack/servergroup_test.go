@@ -0,0 +1,92 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"encoding/json"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	goosehttp "gopkg.in/goose.v2/http"
+	"gopkg.in/goose.v2/nova"
+
+	"github.com/juju/juju/testing"
+)
+
+type serverGroupSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&serverGroupSuite{})
+
+type sentRequest struct {
+	method string
+	url    string
+	body   string
+}
+
+// fakeRequestSender records requests, replying to each which expects a
+// response with the next of its canned JSON responses.
+type fakeRequestSender struct {
+	requests  []sentRequest
+	responses []string
+}
+
+func (f *fakeRequestSender) SendRequest(method, svcType, apiVersion, url string, requestData *goosehttp.RequestData) error {
+	var body []byte
+	if requestData.ReqValue != nil {
+		var err error
+		if body, err = json.Marshal(requestData.ReqValue); err != nil {
+			return err
+		}
+	}
+	f.requests = append(f.requests, sentRequest{method, url, string(body)})
+	if requestData.RespValue == nil {
+		return nil
+	}
+	response := f.responses[0]
+	f.responses = f.responses[1:]
+	return json.Unmarshal([]byte(response), requestData.RespValue)
+}
+
+func (s *serverGroupSuite) TestFindOrCreateServerGroupExists(c *gc.C) {
+	sender := &fakeRequestSender{responses: []string{
+		`{"server_groups": [{"id": "sg-1", "name": "juju-uuid-other"}, {"id": "sg-2", "name": "juju-uuid-db"}]}`,
+	}}
+	id, err := findOrCreateServerGroup(sender, "juju-uuid-db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, "sg-2")
+	c.Assert(sender.requests, jc.DeepEquals, []sentRequest{{"GET", "os-server-groups", ""}})
+}
+
+func (s *serverGroupSuite) TestFindOrCreateServerGroupCreates(c *gc.C) {
+	sender := &fakeRequestSender{responses: []string{
+		`{"server_groups": []}`,
+		`{"server_group": {"id": "sg-3", "name": "juju-uuid-db", "policies": ["anti-affinity"]}}`,
+	}}
+	id, err := findOrCreateServerGroup(sender, "juju-uuid-db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, "sg-3")
+	c.Assert(sender.requests, gc.HasLen, 2)
+	c.Assert(sender.requests[1].method, gc.Equals, "POST")
+	c.Assert(sender.requests[1].url, gc.Equals, "os-server-groups")
+	c.Assert(sender.requests[1].body, gc.Equals, `{"server_group":{"name":"juju-uuid-db","policies":["anti-affinity"]}}`)
+}
+
+func (s *serverGroupSuite) TestRunServerInGroup(c *gc.C) {
+	sender := &fakeRequestSender{responses: []string{
+		`{"server": {"id": "server-1", "name": "juju-machine-0"}}`,
+	}}
+	server, err := runServerInGroup(sender, nova.RunServerOpts{Name: "juju-machine-0"}, "sg-3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(server.Id, gc.Equals, "server-1")
+	c.Assert(sender.requests, gc.HasLen, 1)
+	c.Assert(sender.requests[0].url, gc.Equals, "servers")
+
+	var body map[string]interface{}
+	err = json.Unmarshal([]byte(sender.requests[0].body), &body)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(body["os:scheduler_hints"], jc.DeepEquals, map[string]interface{}{"group": "sg-3"})
+	c.Assert(body["server"].(map[string]interface{})["name"], gc.Equals, "juju-machine-0")
+}
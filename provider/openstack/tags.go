@@ -0,0 +1,132 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"gopkg.in/goose.v2/client"
+	goosehttp "gopkg.in/goose.v2/http"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+)
+
+var _ environs.ResourceTagUpdater = (*Environ)(nil)
+
+// UpdateResourceTags is part of the environs.ResourceTagUpdater interface.
+// The tags are kept in the metadata of the model's servers and cinder
+// volumes. Security groups cannot be given key/value tags in OpenStack,
+// so are not tagged.
+func (e *Environ) UpdateResourceTags(ctx context.ProviderCallContext, tags map[string]string, removed []string) error {
+	insts, err := e.AllInstances(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, inst := range insts {
+		id := inst.Id()
+		if len(tags) > 0 {
+			if err := e.TagInstance(ctx, id, tags); err != nil {
+				return errors.Annotatef(err, "tagging server %q", id)
+			}
+		}
+		for _, key := range removed {
+			if err := deleteServerMetadata(e.client(), string(id), key); err != nil {
+				handleCredentialError(err, ctx)
+				return errors.Annotatef(err, "removing tag %q from server %q", key, id)
+			}
+		}
+	}
+
+	storageAdapter, err := newOpenstackStorage(e)
+	if errors.IsNotSupported(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	volumes, err := modelCinderVolumes(storageAdapter, e.uuid)
+	if err != nil {
+		handleCredentialError(err, ctx)
+		return errors.Trace(err)
+	}
+	for _, v := range volumes {
+		if len(tags) > 0 {
+			if _, err := storageAdapter.SetVolumeMetadata(v.ID, tags); err != nil {
+				handleCredentialError(err, ctx)
+				return errors.Annotatef(err, "tagging volume %q", v.ID)
+			}
+		}
+		for _, key := range removed {
+			if _, ok := v.Metadata[key]; !ok {
+				continue
+			}
+			if err := storageAdapter.DeleteVolumeMetadata(v.ID, key); err != nil {
+				handleCredentialError(err, ctx)
+				return errors.Annotatef(err, "removing tag %q from volume %q", key, v.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// ResourceTagKeys is part of the environs.ResourceTagUpdater interface.
+func (e *Environ) ResourceTagKeys(ctx context.ProviderCallContext, prefix string) ([]string, error) {
+	insts, err := e.AllInstances(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	keys := set.NewStrings()
+	for _, inst := range insts {
+		addPrefixedKeys(keys, inst.(*openstackInstance).getServerDetail().Metadata, prefix)
+	}
+
+	storageAdapter, err := newOpenstackStorage(e)
+	if errors.IsNotSupported(err) {
+		return keys.SortedValues(), nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	volumes, err := modelCinderVolumes(storageAdapter, e.uuid)
+	if err != nil {
+		handleCredentialError(err, ctx)
+		return nil, errors.Trace(err)
+	}
+	for _, v := range volumes {
+		addPrefixedKeys(keys, v.Metadata, prefix)
+	}
+	return keys.SortedValues(), nil
+}
+
+// addPrefixedKeys adds the keys of the metadata with the given prefix
+// to keys.
+func addPrefixedKeys(keys set.Strings, metadata map[string]string, prefix string) {
+	for key := range metadata {
+		if strings.HasPrefix(key, prefix) {
+			keys.Add(key)
+		}
+	}
+}
+
+// deleteServerMetadata removes the metadata item with the given key from
+// a server. The nova client library does not support removing metadata,
+// so the request is made directly against the compute API.
+func deleteServerMetadata(c requestSender, serverId, key string) error {
+	requestData := goosehttp.RequestData{
+		ExpectedStatus: []int{http.StatusNoContent, http.StatusNotFound},
+	}
+	return c.SendRequest(client.DELETE, "compute", "v2", apiServers+"/"+serverId+"/metadata/"+key, &requestData)
+}
+
+// DeleteVolumeMetadata is part of the OpenstackStorage interface. The
+// cinder client does not support removing metadata, so the request is
+// sent directly.
+func (ga *openstackStorageAdapter) DeleteVolumeMetadata(volumeId, key string) error {
+	requestData := goosehttp.RequestData{
+		ExpectedStatus: []int{http.StatusOK, http.StatusNotFound},
+	}
+	return ga.volumeRequester.SendRequest(client.DELETE, "volumev2", "v2", "volumes/"+volumeId+"/metadata/"+key, &requestData)
+}
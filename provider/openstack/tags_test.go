@@ -0,0 +1,43 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"github.com/juju/collections/set"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+)
+
+type tagsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&tagsSuite{})
+
+func (s *tagsSuite) TestDeleteServerMetadata(c *gc.C) {
+	sender := &fakeRequestSender{}
+	err := deleteServerMetadata(sender, "server-1", "owner")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sender.requests, jc.DeepEquals, []sentRequest{{"DELETE", "servers/server-1/metadata/owner", ""}})
+}
+
+func (s *tagsSuite) TestDeleteVolumeMetadata(c *gc.C) {
+	sender := &fakeRequestSender{}
+	adapter := &openstackStorageAdapter{volumeRequester: sender}
+	err := adapter.DeleteVolumeMetadata("volume-1", "owner")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sender.requests, jc.DeepEquals, []sentRequest{{"DELETE", "volumes/volume-1/metadata/owner", ""}})
+}
+
+func (s *tagsSuite) TestAddPrefixedKeys(c *gc.C) {
+	keys := set.NewStrings("juju-resource-tag-dept")
+	addPrefixedKeys(keys, map[string]string{
+		"juju-resource-tag-owner": "true",
+		"juju-model-uuid":         "model-uuid",
+		"owner":                   "fred",
+	}, "juju-resource-tag-")
+	c.Assert(keys.SortedValues(), jc.DeepEquals, []string{"juju-resource-tag-dept", "juju-resource-tag-owner"})
+}
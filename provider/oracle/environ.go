@@ -662,6 +662,9 @@ func (o *OracleEnviron) ConstraintsValidator(ctx context.ProviderCallContext) (c
 		constraints.CpuPower,
 		constraints.RootDisk,
 		constraints.VirtType,
+		constraints.Spot,
+		constraints.MaxPrice,
+		constraints.AntiAffinity,
 	}
 
 	// we choose to use the default validator implementation
@@ -71,6 +71,9 @@ func (s storageProvider) DefaultPools() []*storage.Config {
 // ValidateConfig  is defined on the storage.Provider interface.
 func (s storageProvider) ValidateConfig(cfg *storage.Config) error {
 	attrs := cfg.Attrs()
+	if err := storage.ValidateNoEncryption(attrs, "Oracle volumes"); err != nil {
+		return errors.Trace(err)
+	}
 	if volType, ok := attrs[oracleVolumeType]; ok {
 		switch kind := volType.(type) {
 		case string:
@@ -115,6 +115,17 @@ func (s *storageProviderSuite) TestValidateConfig(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 }
 
+func (s *storageProviderSuite) TestValidateConfigEncrypted(c *gc.C) {
+	provider := s.NewStorageProvider(c)
+	cfg, err := storage.NewConfig("oracle-latency", oracle.DefaultTypes[0],
+		map[string]interface{}{
+			"encrypted": true,
+		})
+	c.Assert(err, gc.IsNil)
+	err = provider.ValidateConfig(cfg)
+	c.Assert(err, gc.ErrorMatches, `"encrypted" for Oracle volumes not supported`)
+}
+
 func (s *storageProviderSuite) TestValidateConfigWithError(c *gc.C) {
 	provider := s.NewStorageProvider(c)
 	cfg, err := storage.NewConfig("some-test-name", oracle.DefaultTypes[0],
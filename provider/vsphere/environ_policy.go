@@ -96,6 +96,9 @@ func (env *sessionEnviron) checkExtendPermissions(ctx context.ProviderCallContex
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.Spot,
+	constraints.MaxPrice,
+	constraints.AntiAffinity,
 }
 
 // ConstraintsValidator returns a Validator value which is used to
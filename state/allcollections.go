@@ -328,8 +328,9 @@ func allCollections() CollectionSchema {
 				Key: []string{"model-uuid", "machineid"},
 			}},
 		},
-		rebootC:      {},
-		sshHostKeysC: {},
+		rebootC:         {},
+		sshHostKeysC:    {},
+		machineNetplanC: {},
 
 		// This collection contains information from removed machines
 		// that needs to be cleaned up in the provider.
@@ -380,6 +381,16 @@ func allCollections() CollectionSchema {
 		},
 		volumeAttachmentsC:    {},
 		volumeAttachmentPlanC: {},
+		storageSnapshotsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "storageid"},
+			}},
+		},
+		storageMigrationsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "storageid"},
+			}},
+		},
 
 		// -----
 
@@ -514,6 +525,9 @@ func allCollections() CollectionSchema {
 		// firewallRulesC holds firewall rules for defined service types.
 		firewallRulesC: {},
 
+		// egressRulesC holds the egress rules of applications.
+		egressRulesC: {},
+
 		// podSpecsC holds the CAAS pod specifications,
 		// for applications.
 		podSpecsC: {},
@@ -595,6 +609,7 @@ const (
 	podSpecsC                  = "podSpecs"
 	providerIDsC               = "providerIDs"
 	rebootC                    = "reboot"
+	machineNetplanC            = "machinenetplan"
 	relationScopesC            = "relationscopes"
 	relationsC                 = "relations"
 	restoreInfoC               = "restoreInfo"
@@ -612,6 +627,8 @@ const (
 	storageConstraintsC        = "storageconstraints"
 	deviceConstraintsC         = "deviceConstraints"
 	storageInstancesC          = "storageinstances"
+	storageMigrationsC         = "storagemigrations"
+	storageSnapshotsC          = "storagesnapshots"
 	subnetsC                   = "subnets"
 	linkLayerDevicesC          = "linklayerdevices"
 	linkLayerDevicesRefsC      = "linklayerdevicesrefs"
@@ -638,4 +655,5 @@ const (
 	externalControllersC = "externalControllers"
 	relationNetworksC    = "relationNetworks"
 	firewallRulesC       = "firewallRules"
+	egressRulesC         = "egressRules"
 )
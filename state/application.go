@@ -578,6 +578,7 @@ func (a *Application) removeOps(asserts bson.D, op *ForcedOperation) ([]txn.Op,
 		removeSettingsOp(settingsC, a.applicationConfigKey()),
 		removeModelApplicationRefOp(a.st, name),
 		removePodSpecOp(a.ApplicationTag()),
+		removeEgressRulesOp(name),
 	)
 	return ops, nil
 }
@@ -1741,13 +1742,14 @@ func (a *Application) addUnitOps(
 		return "", nil, errors.Trace(err)
 	}
 	uNames, ops, err := a.addUnitOpsWithCons(applicationAddUnitOpsArgs{
-		cons:          cons,
-		principalName: principalName,
-		storageCons:   storageCons,
-		attachStorage: args.AttachStorage,
-		providerId:    args.ProviderId,
-		address:       args.Address,
-		ports:         args.Ports,
+		cons:             cons,
+		principalName:    principalName,
+		storageCons:      storageCons,
+		attachStorage:    args.AttachStorage,
+		storageSnapshots: args.StorageSnapshots,
+		providerId:       args.ProviderId,
+		address:          args.Address,
+		ports:            args.Ports,
 	})
 	if err != nil {
 		return uNames, ops, errors.Trace(err)
@@ -1764,6 +1766,10 @@ type applicationAddUnitOpsArgs struct {
 	storageCons   map[string]StorageConstraints
 	attachStorage []names.StorageTag
 
+	// storageSnapshots maps charm storage names to the IDs of
+	// storage snapshots that the unit's storage is restored from.
+	storageSnapshots map[string]string
+
 	// These optional attributes are relevant to CAAS models.
 	providerId *string
 	address    *string
@@ -1909,13 +1915,9 @@ func (a *Application) addUnitStorageOps(
 	}
 
 	// Reduce the count of new storage created for each existing storage
-	// being attached.
+	// being attached, and for each storage restored from a snapshot.
 	var storageCons map[string]StorageConstraints
-	for _, tag := range args.attachStorage {
-		storageName, err := names.StorageName(tag.Id())
-		if err != nil {
-			return nil, -1, errors.Trace(err)
-		}
+	decrementCount := func(storageName string) {
 		if cons, ok := args.storageCons[storageName]; ok && cons.Count > 0 {
 			if storageCons == nil {
 				// We must not modify the contents of the original
@@ -1931,6 +1933,31 @@ func (a *Application) addUnitStorageOps(
 			storageCons[storageName] = cons
 		}
 	}
+	for _, tag := range args.attachStorage {
+		storageName, err := names.StorageName(tag.Id())
+		if err != nil {
+			return nil, -1, errors.Trace(err)
+		}
+		decrementCount(storageName)
+	}
+	var snapshotCons map[string]StorageConstraints
+	var snapshotOps []txn.Op
+	for storageName, snapshotId := range args.storageSnapshots {
+		cons, op, err := sb.restoreStorageSnapshotOps(
+			charm.Meta(), storageName, snapshotId, args.storageCons[storageName],
+		)
+		if err != nil {
+			return nil, -1, errors.Annotatef(
+				err, "restoring storage %q from snapshot", storageName,
+			)
+		}
+		decrementCount(storageName)
+		if snapshotCons == nil {
+			snapshotCons = make(map[string]StorageConstraints)
+		}
+		snapshotCons[storageName] = cons
+		snapshotOps = append(snapshotOps, op)
+	}
 
 	// Add storage instances/attachments for the unit. If the
 	// application is subordinate, we'll add the machine storage
@@ -1950,12 +1977,33 @@ func (a *Application) addUnitStorageOps(
 		unitTag,
 		charm.Meta(),
 		args.storageCons,
+		nil,
 		a.doc.Series,
 		machineAssignable,
 	)
 	if err != nil {
 		return nil, -1, errors.Trace(err)
 	}
+	if len(snapshotCons) > 0 {
+		ops, tags, n, err := createStorageOps(
+			sb,
+			unitTag,
+			charm.Meta(),
+			snapshotCons,
+			args.storageSnapshots,
+			a.doc.Series,
+			machineAssignable,
+		)
+		if err != nil {
+			return nil, -1, errors.Trace(err)
+		}
+		storageOps = append(storageOps, snapshotOps...)
+		storageOps = append(storageOps, ops...)
+		numStorageAttachments += n
+		for name, tags := range tags {
+			storageTags[name] = append(storageTags[name], tags...)
+		}
+	}
 	for _, storageTag := range args.attachStorage {
 		si, err := sb.storageInstance(storageTag)
 		if err != nil {
@@ -2050,6 +2098,10 @@ type AddUnitParams struct {
 	// AttachStorage identifies storage instances to attach to the unit.
 	AttachStorage []names.StorageTag
 
+	// StorageSnapshots maps charm storage names to the IDs of storage
+	// snapshots that the unit's storage should be restored from.
+	StorageSnapshots map[string]string
+
 	// These attributes are relevant to CAAS models.
 
 	// ProviderId identifies the unit for a given provider.
@@ -2639,12 +2691,9 @@ func (a *Application) DeviceConstraints() (map[string]DeviceConstraints, error)
 // If no status is recorded, then there are no unit leaders and the
 // status is derived from the unit status values.
 func (a *Application) Status() (status.StatusInfo, error) {
-	statuses, closer := a.st.db().GetCollection(statusesC)
-	defer closer()
-	query := statuses.Find(bson.D{{"_id", a.globalKey()}, {"neverset", true}})
-	if count, err := query.Count(); err != nil {
+	if derived, err := a.StatusDerived(); err != nil {
 		return status.StatusInfo{}, errors.Trace(err)
-	} else if count != 0 {
+	} else if derived {
 		// This indicates that SetStatus has never been called on this application.
 		// This in turn implies the application status document is likely to be
 		// inaccurate, so we return aggregated unit statuses instead.
@@ -2675,6 +2724,20 @@ func (a *Application) Status() (status.StatusInfo, error) {
 	return getStatus(a.st.db(), a.globalKey(), "application")
 }
 
+// StatusDerived reports whether the status of the application is
+// derived from the status of its units, because the leader has never
+// set an application status.
+func (a *Application) StatusDerived() (bool, error) {
+	statuses, closer := a.st.db().GetCollection(statusesC)
+	defer closer()
+	query := statuses.Find(bson.D{{"_id", a.globalKey()}, {"neverset", true}})
+	count, err := query.Count()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return count != 0, nil
+}
+
 func expectWorkload(st *State, appName string) (bool, error) {
 	m, err := st.Model()
 	if err != nil {
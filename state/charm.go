@@ -18,6 +18,7 @@ import (
 	"gopkg.in/mgo.v2/txn"
 
 	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/core/relation"
 	"github.com/juju/juju/mongo"
 	mongoutils "github.com/juju/juju/mongo/utils"
 	"github.com/juju/juju/state/storage"
@@ -86,6 +87,11 @@ type charmDoc struct {
 	Actions    *charm.Actions    `bson:"actions"`
 	Metrics    *charm.Metrics    `bson:"metrics"`
 	LXDProfile *charm.LXDProfile `bson:"lxd-profile"`
+
+	// RelationSchemas holds the JSON-serialised relation settings
+	// schemas declared in the charm's metadata.yaml, keyed by
+	// endpoint name.
+	RelationSchemas map[string]string `bson:"relation-schemas,omitempty"`
 }
 
 // CharmInfo contains all the data necessary to store a charm's metadata.
@@ -96,6 +102,10 @@ type CharmInfo struct {
 	SHA256      string
 	Macaroon    macaroon.Slice
 	Version     string
+
+	// RelationSchemas holds the JSON-serialised relation settings
+	// schemas declared by the charm, keyed by endpoint name.
+	RelationSchemas map[string]string
 }
 
 // insertCharmOps returns the txn operations necessary to insert the supplied
@@ -115,6 +125,8 @@ func insertCharmOps(mb modelBackend, info CharmInfo) ([]txn.Op, error) {
 		Actions:      info.Charm.Actions(),
 		BundleSha256: info.SHA256,
 		StoragePath:  info.StoragePath,
+
+		RelationSchemas: info.RelationSchemas,
 	}
 	lpc, ok := info.Charm.(charm.LXDProfiler)
 	if !ok {
@@ -228,6 +240,7 @@ func updateCharmOps(mb modelBackend, info CharmInfo, assert bson.D) ([]txn.Op, e
 		{"bundlesha256", info.SHA256},
 		{"pendingupload", false},
 		{"placeholder", false},
+		{"relation-schemas", info.RelationSchemas},
 	}
 
 	lpc, ok := info.Charm.(charm.LXDProfiler)
@@ -547,6 +560,21 @@ func (c *Charm) LXDProfile() *charm.LXDProfile {
 	return c.doc.LXDProfile
 }
 
+// RelationSchema returns the relation settings schema the charm
+// declares for the named endpoint. A nil schema is returned if the
+// charm does not declare one.
+func (c *Charm) RelationSchema(endpoint string) (relation.EndpointSchema, error) {
+	data, ok := c.doc.RelationSchemas[endpoint]
+	if !ok {
+		return nil, nil
+	}
+	schema, err := relation.UnmarshalEndpointSchema(data)
+	if err != nil {
+		return nil, errors.Annotatef(err, "charm %q relation %q schema", c.doc.URL, endpoint)
+	}
+	return schema, nil
+}
+
 // StoragePath returns the storage path of the charm bundle.
 func (c *Charm) StoragePath() string {
 	return c.doc.StoragePath
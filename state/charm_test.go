@@ -289,6 +289,26 @@ func (s *CharmSuite) TestAddCharm(c *gc.C) {
 	c.Assert(doc.CharmVersion, gc.Equals, expVersion)
 }
 
+func (s *CharmSuite) TestAddCharmWithRelationSchemas(c *gc.C) {
+	info := s.dummyCharm(c, "")
+	info.RelationSchemas = map[string]string{
+		"foo": `{"provider":{"unit":{"type":"object","required":["host"]}}}`,
+	}
+	dummy, err := s.State.AddCharm(info)
+	c.Assert(err, jc.ErrorIsNil)
+
+	schema, err := dummy.RelationSchema("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(schema[charm.RoleProvider].Unit, jc.DeepEquals, map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"host"},
+	})
+
+	schema, err = dummy.RelationSchema("bar")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(schema, gc.IsNil)
+}
+
 func (s *CharmSuite) TestAddCharmWithAuth(c *gc.C) {
 	// Check that adding charms from scratch works correctly.
 	info := s.dummyCharm(c, "")
@@ -30,6 +30,9 @@ type constraintsDoc struct {
 	Spaces         *[]string
 	VirtType       *string
 	Zones          *[]string
+	Spot           *bool
+	MaxPrice       *string
+	AntiAffinity   *string
 }
 
 func (doc constraintsDoc) value() constraints.Value {
@@ -46,6 +49,9 @@ func (doc constraintsDoc) value() constraints.Value {
 		Spaces:         doc.Spaces,
 		VirtType:       doc.VirtType,
 		Zones:          doc.Zones,
+		Spot:           doc.Spot,
+		MaxPrice:       doc.MaxPrice,
+		AntiAffinity:   doc.AntiAffinity,
 	}
 	return result
 }
@@ -64,6 +70,9 @@ func newConstraintsDoc(cons constraints.Value) constraintsDoc {
 		Spaces:         cons.Spaces,
 		VirtType:       cons.VirtType,
 		Zones:          cons.Zones,
+		Spot:           cons.Spot,
+		MaxPrice:       cons.MaxPrice,
+		AntiAffinity:   cons.AntiAffinity,
 	}
 	return result
 }
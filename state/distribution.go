@@ -5,9 +5,12 @@ package state
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
 
 	"github.com/juju/juju/core/instance"
 )
@@ -95,3 +98,29 @@ func ApplicationMachines(st *State, application string) ([]string, error) {
 	}
 	return machineIds, nil
 }
+
+// AntiAffinityMachines returns the machine IDs of machines which are
+// constrained to the specified anti-affinity group. The constraints are
+// queried directly rather than read for each machine in turn.
+func AntiAffinityMachines(st *State, group string) ([]string, error) {
+	constraintsCollection, closer := st.db().GetCollection(constraintsC)
+	defer closer()
+
+	var docs []struct {
+		DocID string `bson:"_id"`
+	}
+	query := constraintsCollection.Find(bson.D{{"antiaffinity", group}})
+	if err := query.Select(bson.D{{"_id", 1}}).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get constraints")
+	}
+	var machineIds []string
+	for _, doc := range docs {
+		if key := st.localID(doc.DocID); strings.HasPrefix(key, "m#") {
+			machineIds = append(machineIds, strings.TrimPrefix(key, "m#"))
+		}
+	}
+	sort.Slice(machineIds, func(i, j int) bool {
+		return machineIdLessThan(machineIds[i], machineIds[j])
+	})
+	return machineIds, nil
+}
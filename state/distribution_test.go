@@ -268,6 +268,23 @@ func (s *ApplicationMachinesSuite) SetUpTest(c *gc.C) {
 	}
 }
 
+func (s *ApplicationMachinesSuite) TestAntiAffinityMachines(c *gc.C) {
+	for _, i := range []int{1, 3} {
+		err := s.machines[i].SetConstraints(constraints.MustParse("anti-affinity=db"))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := s.machines[4].SetConstraints(constraints.MustParse("anti-affinity=web"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	machines, err := state.AntiAffinityMachines(s.State, "db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.DeepEquals, []string{"1", "3"})
+
+	machines, err = state.AntiAffinityMachines(s.State, "fred")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 0)
+}
+
 func (s *ApplicationMachinesSuite) TestApplicationMachines(c *gc.C) {
 	machines, err := state.ApplicationMachines(s.State, "mysql")
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,242 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"net"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/network"
+)
+
+// EgressRule allows egress from the machines hosting an application's
+// units to a port range on a destination subnet. Once an application
+// has egress rules, all other egress from its machines is denied.
+type EgressRule struct {
+	// DestinationCIDR is the subnet egress is allowed to.
+	DestinationCIDR string
+
+	// PortRange is the port range egress is allowed to.
+	PortRange network.PortRange
+}
+
+// egressRulesDoc holds the egress rules of an application.
+type egressRulesDoc struct {
+	// DocID is the application name.
+	DocID       string          `bson:"_id"`
+	ModelUUID   string          `bson:"model-uuid"`
+	Application string          `bson:"application"`
+	Rules       []egressRuleDoc `bson:"rules"`
+}
+
+type egressRuleDoc struct {
+	DestinationCIDR string `bson:"destination-cidr"`
+	Protocol        string `bson:"protocol"`
+	FromPort        int    `bson:"from-port"`
+	ToPort          int    `bson:"to-port"`
+}
+
+func (doc *egressRulesDoc) toRules() []EgressRule {
+	rules := make([]EgressRule, len(doc.Rules))
+	for i, rule := range doc.Rules {
+		rules[i] = EgressRule{
+			DestinationCIDR: rule.DestinationCIDR,
+			PortRange: network.PortRange{
+				Protocol: rule.Protocol,
+				FromPort: rule.FromPort,
+				ToPort:   rule.ToPort,
+			},
+		}
+	}
+	return rules
+}
+
+func (r EgressRule) validate() error {
+	if _, _, err := net.ParseCIDR(r.DestinationCIDR); err != nil {
+		return errors.NotValidf("CIDR %q", r.DestinationCIDR)
+	}
+	if err := r.PortRange.Validate(); err != nil {
+		return errors.NewNotValid(err, "egress rule")
+	}
+	return nil
+}
+
+// SetEgressRules replaces the egress rules of the application. Setting
+// no rules lifts any egress restriction from the application's machines.
+func (a *Application) SetEgressRules(rules []EgressRule) error {
+	docRules := make([]egressRuleDoc, len(rules))
+	for i, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return errors.Trace(err)
+		}
+		docRules[i] = egressRuleDoc{
+			DestinationCIDR: rule.DestinationCIDR,
+			Protocol:        rule.PortRange.Protocol,
+			FromPort:        rule.PortRange.FromPort,
+			ToPort:          rule.PortRange.ToPort,
+		}
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := a.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if a.Life() != Alive {
+			return nil, applicationNotAliveErr
+		}
+		_, err := a.egressRules()
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		exists := err == nil
+		if !exists && len(rules) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		ops := []txn.Op{{
+			C:      applicationsC,
+			Id:     a.doc.DocID,
+			Assert: isAliveDoc,
+		}}
+		switch {
+		case len(rules) == 0:
+			ops = append(ops, removeEgressRulesOp(a.Name()))
+		case exists:
+			ops = append(ops, txn.Op{
+				C:      egressRulesC,
+				Id:     a.Name(),
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{{"rules", docRules}}}},
+			})
+		default:
+			ops = append(ops, txn.Op{
+				C:      egressRulesC,
+				Id:     a.Name(),
+				Assert: txn.DocMissing,
+				Insert: &egressRulesDoc{
+					Application: a.Name(),
+					Rules:       docRules,
+				},
+			})
+		}
+		return ops, nil
+	}
+	if err := a.st.db().Run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot set egress rules for application %q", a)
+	}
+	return nil
+}
+
+// EgressRules returns the egress rules of the application, or
+// no rules if egress from the application's machines is unrestricted.
+func (a *Application) EgressRules() ([]EgressRule, error) {
+	rules, err := a.egressRules()
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	return rules, errors.Trace(err)
+}
+
+func (a *Application) egressRules() ([]EgressRule, error) {
+	coll, closer := a.st.db().GetCollection(egressRulesC)
+	defer closer()
+
+	var doc egressRulesDoc
+	err := coll.FindId(a.Name()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("egress rules for application %q", a)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return doc.toRules(), nil
+}
+
+// AllEgressRules returns the egress rules of every application
+// with restricted egress, keyed by application name.
+func (st *State) AllEgressRules() (map[string][]EgressRule, error) {
+	coll, closer := st.db().GetCollection(egressRulesC)
+	defer closer()
+
+	var docs []egressRulesDoc
+	if err := coll.Find(nil).All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make(map[string][]EgressRule)
+	for _, doc := range docs {
+		result[doc.Application] = doc.toRules()
+	}
+	return result, nil
+}
+
+// EgressRules returns the egress rules of the applications with units
+// on the machine, and whether egress from the machine is restricted,
+// which it is if any of the applications has egress rules.
+func (m *Machine) EgressRules() ([]EgressRule, bool, error) {
+	units, err := m.Units()
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	if len(units) == 0 {
+		return nil, false, nil
+	}
+	all, err := m.st.AllEgressRules()
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	var rules []EgressRule
+	var restricted bool
+	seen := make(map[string]bool)
+	for _, unit := range units {
+		appName := unit.ApplicationName()
+		appRules, ok := all[appName]
+		if !ok || seen[appName] {
+			continue
+		}
+		seen[appName] = true
+		restricted = true
+		rules = append(rules, appRules...)
+	}
+	return rules, restricted, nil
+}
+
+func removeEgressRulesOp(application string) txn.Op {
+	return txn.Op{
+		C:      egressRulesC,
+		Id:     application,
+		Remove: true,
+	}
+}
+
+// EgressEnforcedByProvider reports whether the provider's firewall
+// enforces the egress rules of the machine's cloud instance, as last
+// recorded by the firewaller. When it does not, the machine agent
+// must enforce them itself.
+func (m *Machine) EgressEnforcedByProvider() bool {
+	return m.doc.EgressEnforcedByProvider
+}
+
+// SetEgressEnforcedByProvider records whether the provider's firewall
+// enforces the egress rules of the machine's cloud instance.
+func (m *Machine) SetEgressEnforcedByProvider(enforced bool) error {
+	if m.doc.EgressEnforcedByProvider == enforced {
+		return nil
+	}
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: notDeadDoc,
+		Update: bson.D{{"$set", bson.D{{"egress-provider-enforced", enforced}}}},
+	}}
+	if err := m.st.db().RunTransaction(ops); err != nil {
+		return errors.Annotatef(onAbort(err, ErrDead), "cannot set egress enforcement for machine %v", m)
+	}
+	m.doc.EgressEnforcedByProvider = enforced
+	return nil
+}
@@ -0,0 +1,182 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type EgressRulesSuite struct {
+	ConnSuite
+	mysql *state.Application
+}
+
+var _ = gc.Suite(&EgressRulesSuite{})
+
+func (s *EgressRulesSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.mysql = s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+}
+
+func (s *EgressRulesSuite) TestSetEgressRules(c *gc.C) {
+	rules, err := s.mysql.EgressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+
+	expected := []state.EgressRule{{
+		DestinationCIDR: "10.0.0.0/8",
+		PortRange:       network.MustParsePortRange("5432/tcp"),
+	}}
+	err = s.mysql.SetEgressRules(expected)
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err = s.mysql.EgressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, expected)
+
+	expected = append(expected, state.EgressRule{
+		DestinationCIDR: "192.168.0.0/16",
+		PortRange:       network.MustParsePortRange("80-443/tcp"),
+	})
+	err = s.mysql.SetEgressRules(expected)
+	c.Assert(err, jc.ErrorIsNil)
+	all, err := s.State.AllEgressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, jc.DeepEquals, map[string][]state.EgressRule{"mysql": expected})
+
+	err = s.mysql.SetEgressRules(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err = s.mysql.EgressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+
+	// Lifting the restriction again is a no-op.
+	err = s.mysql.SetEgressRules(nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *EgressRulesSuite) TestSetEgressRulesInvalid(c *gc.C) {
+	err := s.mysql.SetEgressRules([]state.EgressRule{{
+		DestinationCIDR: "10.0.0",
+		PortRange:       network.MustParsePortRange("5432/tcp"),
+	}})
+	c.Assert(err, gc.ErrorMatches, `CIDR "10.0.0" not valid`)
+
+	err = s.mysql.SetEgressRules([]state.EgressRule{{
+		DestinationCIDR: "10.0.0.0/8",
+		PortRange:       network.PortRange{Protocol: "tcp", FromPort: 10, ToPort: 5},
+	}})
+	c.Assert(err, gc.ErrorMatches, `egress rule: .*`)
+}
+
+func (s *EgressRulesSuite) TestSetEgressRulesApplicationNotAlive(c *gc.C) {
+	_, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.SetEgressRules([]state.EgressRule{{
+		DestinationCIDR: "10.0.0.0/8",
+		PortRange:       network.MustParsePortRange("5432/tcp"),
+	}})
+	c.Assert(err, gc.ErrorMatches, `cannot set egress rules for application "mysql": application is not found or not alive`)
+}
+
+func (s *EgressRulesSuite) TestEgressRulesRemovedWithApplication(c *gc.C) {
+	err := s.mysql.SetEgressRules([]state.EgressRule{{
+		DestinationCIDR: "10.0.0.0/8",
+		PortRange:       network.MustParsePortRange("5432/tcp"),
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	all, err := s.State.AllEgressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 0)
+}
+
+func (s *EgressRulesSuite) TestWatchEgressRules(c *gc.C) {
+	w := s.State.WatchEgressRules()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err := s.mysql.SetEgressRules([]state.EgressRule{{
+		DestinationCIDR: "10.0.0.0/8",
+		PortRange:       network.MustParsePortRange("5432/tcp"),
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.mysql.SetEgressRules(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
+func (s *EgressRulesSuite) TestMachineEgressRules(c *gc.C) {
+	unit, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+
+	rules, restricted, err := m.EgressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(restricted, jc.IsFalse)
+	c.Assert(rules, gc.HasLen, 0)
+
+	expected := []state.EgressRule{{
+		DestinationCIDR: "10.0.0.0/8",
+		PortRange:       network.MustParsePortRange("5432/tcp"),
+	}}
+	err = s.mysql.SetEgressRules(expected)
+	c.Assert(err, jc.ErrorIsNil)
+	wordpress := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err = wordpress.SetEgressRules([]state.EgressRule{{
+		DestinationCIDR: "192.168.0.0/16",
+		PortRange:       network.MustParsePortRange("80/tcp"),
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	rules, restricted, err = m.EgressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(restricted, jc.IsTrue)
+	c.Assert(rules, jc.DeepEquals, expected)
+}
+
+func (s *EgressRulesSuite) TestSetEgressEnforcedByProvider(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.EgressEnforcedByProvider(), jc.IsFalse)
+
+	err = m.SetEgressEnforcedByProvider(true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.EgressEnforcedByProvider(), jc.IsTrue)
+
+	err = m.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.EgressEnforcedByProvider(), jc.IsTrue)
+
+	err = m.SetEgressEnforcedByProvider(false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.EgressEnforcedByProvider(), jc.IsFalse)
+}
+
+func (s *EgressRulesSuite) TestSetEgressEnforcedByProviderDeadMachine(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = m.SetEgressEnforcedByProvider(true)
+	c.Assert(err, gc.ErrorMatches, `cannot set egress enforcement for machine 0: not found or dead`)
+}
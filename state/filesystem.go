@@ -8,6 +8,7 @@ import (
 	"path"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	jujutxn "github.com/juju/txn"
@@ -67,6 +68,10 @@ type Filesystem interface {
 	// Releasing reports whether or not the filesystem is to be released
 	// from the model when it is Dying/Dead.
 	Releasing() bool
+
+	// RequestedSize returns the size in MiB that the filesystem has
+	// been requested to grow to, and whether or not a resize is pending.
+	RequestedSize() (uint64, bool)
 }
 
 // FilesystemAttachment describes an attachment of a filesystem to a machine.
@@ -92,6 +97,11 @@ type FilesystemAttachment interface {
 	// if it has not already been made. Params returns true if the returned
 	// parameters are usable for creating an attachment, otherwise false.
 	Params() (FilesystemAttachmentParams, bool)
+
+	// Usage returns the most recently reported usage of the attached
+	// filesystem. Usage returns true if usage has been reported,
+	// otherwise false.
+	Usage() (FilesystemUsage, bool)
 }
 
 type filesystem struct {
@@ -116,6 +126,11 @@ type filesystemDoc struct {
 	Info            *FilesystemInfo   `bson:"info,omitempty"`
 	Params          *FilesystemParams `bson:"params,omitempty"`
 
+	// RequestedSize is the size in MiB that a provisioned filesystem
+	// has been requested to grow to. It is cleared once the storage
+	// provisioner records info with at least this size.
+	RequestedSize uint64 `bson:"requested-size,omitempty"`
+
 	// HostId is the ID of the host that a non-detachable
 	// volume is initially attached to. We use this to identify
 	// the filesystem as being non-detachable, and to determine
@@ -134,6 +149,7 @@ type filesystemAttachmentDoc struct {
 	Life   Life                        `bson:"life"`
 	Info   *FilesystemAttachmentInfo   `bson:"info,omitempty"`
 	Params *FilesystemAttachmentParams `bson:"params,omitempty"`
+	Usage  *FilesystemUsage            `bson:"usage,omitempty"`
 }
 
 // FilesystemParams records parameters for provisioning a new filesystem.
@@ -177,6 +193,16 @@ type FilesystemAttachmentInfo struct {
 	ReadOnly   bool   `bson:"read-only"`
 }
 
+// FilesystemUsage describes how much of a mounted filesystem is in use,
+// as reported by the agent of the machine it is attached to.
+type FilesystemUsage struct {
+	UsedBytes  uint64    `bson:"used-bytes"`
+	FreeBytes  uint64    `bson:"free-bytes"`
+	UsedInodes uint64    `bson:"used-inodes"`
+	FreeInodes uint64    `bson:"free-inodes"`
+	Updated    time.Time `bson:"updated"`
+}
+
 // FilesystemAttachmentParams records parameters for attaching a filesystem to a
 // machine.
 type FilesystemAttachmentParams struct {
@@ -247,11 +273,24 @@ func (f *filesystem) Params() (FilesystemParams, bool) {
 	return *f.doc.Params, true
 }
 
+// Usage is required to implement FilesystemAttachment.
+func (f *filesystemAttachment) Usage() (FilesystemUsage, bool) {
+	if f.doc.Usage == nil {
+		return FilesystemUsage{}, false
+	}
+	return *f.doc.Usage, true
+}
+
 // Releasing is required to implement Filesystem.
 func (f *filesystem) Releasing() bool {
 	return f.doc.Releasing
 }
 
+// RequestedSize is required to implement Filesystem.
+func (f *filesystem) RequestedSize() (uint64, bool) {
+	return f.doc.RequestedSize, f.doc.RequestedSize != 0
+}
+
 // Status is required to implement StatusGetter.
 func (f *filesystem) Status() (status.StatusInfo, error) {
 	return getStatus(f.mb.db(), filesystemGlobalKey(f.FilesystemTag().Id()), "filesystem")
@@ -1080,10 +1119,10 @@ func (sb *storageBackend) addFilesystemOps(params FilesystemParams, hostId strin
 			params.filesystemId = filesystemTag.String()
 		}
 		volumeParams := VolumeParams{
-			params.storage,
-			params.volumeInfo,
-			params.Pool,
-			params.Size,
+			storage:    params.storage,
+			volumeInfo: params.volumeInfo,
+			Pool:       params.Pool,
+			Size:       params.Size,
 		}
 		volumeOps, volumeTag, err = sb.addVolumeOps(volumeParams, hostId)
 		if err != nil {
@@ -1265,7 +1304,11 @@ func (sb *storageBackend) SetFilesystemInfo(tag names.FilesystemTag, info Filesy
 				return nil, err
 			}
 		}
-		ops := setFilesystemInfoOps(tag, info, unsetParams)
+		// Once the filesystem has reached the requested
+		// size, the resize request has been satisfied.
+		requestedSize, resizing := fs.RequestedSize()
+		unsetRequestedSize := resizing && info.Size >= requestedSize
+		ops := setFilesystemInfoOps(tag, info, unsetParams, unsetRequestedSize)
 		return ops, nil
 	}
 	return sb.mb.db().Run(buildTxn)
@@ -1287,15 +1330,22 @@ func validateFilesystemInfoChange(newInfo, oldInfo FilesystemInfo) error {
 	return nil
 }
 
-func setFilesystemInfoOps(tag names.FilesystemTag, info FilesystemInfo, unsetParams bool) []txn.Op {
+func setFilesystemInfoOps(tag names.FilesystemTag, info FilesystemInfo, unsetParams, unsetRequestedSize bool) []txn.Op {
 	asserts := isAliveDoc
 	update := bson.D{
 		{"$set", bson.D{{"info", &info}}},
 	}
+	var unset bson.D
 	if unsetParams {
 		asserts = append(asserts, bson.DocElem{"info", bson.D{{"$exists", false}}})
 		asserts = append(asserts, bson.DocElem{"params", bson.D{{"$exists", true}}})
-		update = append(update, bson.DocElem{"$unset", bson.D{{"params", nil}}})
+		unset = append(unset, bson.DocElem{"params", nil})
+	}
+	if unsetRequestedSize {
+		unset = append(unset, bson.DocElem{"requested-size", nil})
+	}
+	if len(unset) > 0 {
+		update = append(update, bson.DocElem{"$unset", unset})
 	}
 	return []txn.Op{{
 		C:      filesystemsC,
@@ -1371,6 +1421,33 @@ func setFilesystemAttachmentInfoOps(
 	}}
 }
 
+// SetFilesystemAttachmentUsage records the usage of the filesystem
+// attached to the specified host, as reported by the host's agent.
+// The filesystem attachment must be provisioned.
+func (sb *storageBackend) SetFilesystemAttachmentUsage(
+	hostTag names.Tag,
+	filesystemTag names.FilesystemTag,
+	usage FilesystemUsage,
+) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set usage for filesystem attachment %s:%s", filesystemTag.Id(), hostTag.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		fsa, err := sb.FilesystemAttachment(hostTag, filesystemTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if _, err := fsa.Info(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:      filesystemAttachmentsC,
+			Id:     filesystemAttachmentId(hostTag.Id(), filesystemTag.Id()),
+			Assert: bson.D{{"info", bson.D{{"$exists", true}}}},
+			Update: bson.D{{"$set", bson.D{{"usage", &usage}}}},
+		}}, nil
+	}
+	return sb.mb.db().Run(buildTxn)
+}
+
 // FilesystemMountPoint returns a mount point to use for the given charm
 // storage. For stores with potentially multiple instances, the instance
 // name is appended to the location.
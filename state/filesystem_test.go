@@ -4,6 +4,8 @@
 package state_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -840,6 +842,48 @@ func (s *FilesystemIAASModelSuite) TestSetFilesystemAttachmentInfoMachineNotProv
 	c.Assert(err, gc.ErrorMatches, `cannot set info for filesystem attachment 0/0:0: machine 0 not provisioned`)
 }
 
+func (s *FilesystemIAASModelSuite) TestSetFilesystemAttachmentUsage(c *gc.C) {
+	_, filesystemAttachment, _ := s.addUnitWithFilesystem(c, "rootfs", false)
+	_, ok := filesystemAttachment.Usage()
+	c.Assert(ok, jc.IsFalse)
+
+	usage := state.FilesystemUsage{
+		UsedBytes:  1024,
+		FreeBytes:  3072,
+		UsedInodes: 10,
+		FreeInodes: 90,
+		Updated:    time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	err := s.storageBackend.SetFilesystemAttachmentUsage(
+		filesystemAttachment.Host(),
+		filesystemAttachment.Filesystem(),
+		usage,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	filesystemAttachment, err = s.storageBackend.FilesystemAttachment(
+		filesystemAttachment.Host(),
+		filesystemAttachment.Filesystem(),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	stored, ok := filesystemAttachment.Usage()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(stored.Updated.Equal(usage.Updated), jc.IsTrue)
+	stored.Updated = usage.Updated
+	c.Assert(stored, jc.DeepEquals, usage)
+}
+
+func (s *FilesystemIAASModelSuite) TestSetFilesystemAttachmentUsageNotProvisioned(c *gc.C) {
+	_, filesystemAttachment, _ := s.addUnitWithFilesystemUnprovisioned(c, "rootfs", false)
+	err := s.storageBackend.SetFilesystemAttachmentUsage(
+		filesystemAttachment.Host(),
+		filesystemAttachment.Filesystem(),
+		state.FilesystemUsage{UsedBytes: 1024},
+	)
+	c.Assert(err, gc.ErrorMatches, `cannot set usage for filesystem attachment 0/0:0: filesystem attachment "0/0" on "machine 0" not provisioned`)
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+}
+
 func (s *FilesystemIAASModelSuite) TestSetFilesystemInfoVolumeAttachmentNotProvisioned(c *gc.C) {
 	filesystem, _, _ := s.addUnitWithFilesystemUnprovisioned(c, "modelscoped-block", true)
 	err := s.storageBackend.SetFilesystemInfo(
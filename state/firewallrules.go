@@ -4,12 +4,17 @@
 package state
 
 import (
+	"fmt"
 	"net"
+	"regexp"
 
 	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/network"
 )
 
 // FirewallRule instances describe the ingress networks
@@ -25,24 +30,58 @@ import (
 // - ssh
 // - juju-controller
 // - juju-application-offer
+//
+// A FirewallRule with a Name instead of a WellKnownService is a
+// model ingress rule, which allows ingress to a port range on the
+// model's machines, or only on the machines hosting units of an
+// application, from the whitelisted subnets.
 type FirewallRule struct {
 	// WellKnownService is the known service for the firewall rules entity.
 	WellKnownService WellKnownServiceType
 
 	// WhitelistCIDRS is the whitelist CIDRs for the rule.
 	WhitelistCIDRs []string
+
+	// Name is the name of a model ingress rule.
+	Name string
+
+	// PortRange is the port range a model ingress rule allows
+	// ingress to.
+	PortRange network.PortRange
+
+	// Application, if set, restricts a model ingress rule to the
+	// machines hosting units of the application.
+	Application string
+}
+
+// IsIngressRule returns whether the rule is a model ingress rule
+// rather than a rule for a well known service.
+func (r *FirewallRule) IsIngressRule() bool {
+	return r.Name != ""
 }
 
 type firewallRulesDoc struct {
 	Id               string   `bson:"_id"`
-	WellKnownService string   `bson:"known-service"`
+	WellKnownService string   `bson:"known-service,omitempty"`
 	WhitelistCIDRS   []string `bson:"whitelist-cidrs"`
+	Name             string   `bson:"name,omitempty"`
+	Protocol         string   `bson:"protocol,omitempty"`
+	FromPort         int      `bson:"from-port,omitempty"`
+	ToPort           int      `bson:"to-port,omitempty"`
+	Application      string   `bson:"application,omitempty"`
 }
 
 func (r *firewallRulesDoc) toRule() *FirewallRule {
 	return &FirewallRule{
 		WellKnownService: WellKnownServiceType(r.WellKnownService),
 		WhitelistCIDRs:   r.WhitelistCIDRS,
+		Name:             r.Name,
+		PortRange: network.PortRange{
+			Protocol: r.Protocol,
+			FromPort: r.FromPort,
+			ToPort:   r.ToPort,
+		},
+		Application: r.Application,
 	}
 }
 
@@ -75,6 +114,41 @@ func (v WellKnownServiceType) validate() error {
 	return errors.NotValidf("well known service type %q", v)
 }
 
+var validIngressRuleName = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+
+// validate returns an error if the rule is not a valid rule for
+// a well known service, or a valid model ingress rule.
+func (r *FirewallRule) validate() error {
+	if !r.IsIngressRule() {
+		return r.WellKnownService.validate()
+	}
+	if r.WellKnownService != "" {
+		return errors.NotValidf("rule %q with well known service %q", r.Name, r.WellKnownService)
+	}
+	if WellKnownServiceType(r.Name).validate() == nil || !validIngressRuleName.MatchString(r.Name) {
+		return errors.NotValidf("firewall rule name %q", r.Name)
+	}
+	if err := r.PortRange.Validate(); err != nil {
+		return errors.NewNotValid(err, fmt.Sprintf("firewall rule %q", r.Name))
+	}
+	if len(r.WhitelistCIDRs) == 0 {
+		// Ingress from anywhere must be asked for explicitly.
+		return errors.NotValidf("firewall rule %q without whitelist", r.Name)
+	}
+	if r.Application != "" && !names.IsValidApplication(r.Application) {
+		return errors.NotValidf("application name %q", r.Application)
+	}
+	return nil
+}
+
+// id returns the document id of the rule.
+func (r *FirewallRule) id() string {
+	if r.IsIngressRule() {
+		return r.Name
+	}
+	return string(r.WellKnownService)
+}
+
 type firewallRulesState struct {
 	st *State
 }
@@ -86,7 +160,7 @@ func NewFirewallRules(st *State) *firewallRulesState {
 
 // Save stores the specified firewall rule.
 func (fw *firewallRulesState) Save(rule FirewallRule) error {
-	if err := rule.WellKnownService.validate(); err != nil {
+	if err := rule.validate(); err != nil {
 		return errors.Trace(err)
 	}
 	for _, cidr := range rule.WhitelistCIDRs {
@@ -94,11 +168,16 @@ func (fw *firewallRulesState) Save(rule FirewallRule) error {
 			return errors.NotValidf("CIDR %q", cidr)
 		}
 	}
-	serviceStr := string(rule.WellKnownService)
+	id := rule.id()
 	doc := firewallRulesDoc{
-		Id:               serviceStr,
-		WellKnownService: serviceStr,
+		Id:               id,
+		WellKnownService: string(rule.WellKnownService),
 		WhitelistCIDRS:   rule.WhitelistCIDRs,
+		Name:             rule.Name,
+		Protocol:         rule.PortRange.Protocol,
+		FromPort:         rule.PortRange.FromPort,
+		ToPort:           rule.PortRange.ToPort,
+		Application:      rule.Application,
 	}
 	buildTxn := func(int) ([]txn.Op, error) {
 		model, err := fw.st.Model()
@@ -109,19 +188,29 @@ func (fw *firewallRulesState) Save(rule FirewallRule) error {
 			return nil, errors.Trace(err)
 		}
 
-		_, err = fw.Rule(rule.WellKnownService)
+		existing, err := fw.rule(id)
 		if err != nil && !errors.IsNotFound(err) {
 			return nil, errors.Trace(err)
 		}
+		if err == nil && existing.IsIngressRule() != rule.IsIngressRule() {
+			return nil, errors.AlreadyExistsf("firewall rule %q", id)
+		}
 		var ops []txn.Op
 		if err == nil {
+			set := bson.D{{"whitelist-cidrs", rule.WhitelistCIDRs}}
+			if rule.IsIngressRule() {
+				set = append(set,
+					bson.DocElem{"protocol", doc.Protocol},
+					bson.DocElem{"from-port", doc.FromPort},
+					bson.DocElem{"to-port", doc.ToPort},
+					bson.DocElem{"application", doc.Application},
+				)
+			}
 			ops = []txn.Op{{
 				C:      firewallRulesC,
-				Id:     serviceStr,
+				Id:     id,
 				Assert: txn.DocExists,
-				Update: bson.D{
-					{"$set", bson.D{{"whitelist-cidrs", rule.WhitelistCIDRs}}},
-				},
+				Update: bson.D{{"$set", set}},
 			}, model.assertActiveOp()}
 		} else {
 			doc.WhitelistCIDRS = rule.WhitelistCIDRs
@@ -143,13 +232,30 @@ func (fw *firewallRulesState) Save(rule FirewallRule) error {
 
 // Rule returns the firewall rule for the specified service.
 func (fw *firewallRulesState) Rule(service WellKnownServiceType) (*FirewallRule, error) {
+	rule, err := fw.rule(string(service))
+	if errors.IsNotFound(err) {
+		return nil, errors.NotFoundf("firewall rules for service %v", service)
+	}
+	return rule, err
+}
+
+// IngressRule returns the model ingress rule with the specified name.
+func (fw *firewallRulesState) IngressRule(name string) (*FirewallRule, error) {
+	rule, err := fw.rule(name)
+	if err == nil && !rule.IsIngressRule() {
+		err = errors.NotFoundf("firewall rule %q", name)
+	}
+	return rule, err
+}
+
+func (fw *firewallRulesState) rule(id string) (*FirewallRule, error) {
 	coll, closer := fw.st.db().GetCollection(firewallRulesC)
 	defer closer()
 
 	var doc firewallRulesDoc
-	err := coll.FindId(string(service)).One(&doc)
+	err := coll.FindId(id).One(&doc)
 	if err == mgo.ErrNotFound {
-		return nil, errors.NotFoundf("firewall rules for service %v", service)
+		return nil, errors.NotFoundf("firewall rule %q", id)
 	}
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -157,6 +263,25 @@ func (fw *firewallRulesState) Rule(service WellKnownServiceType) (*FirewallRule,
 	return doc.toRule(), nil
 }
 
+// RemoveIngressRule removes the model ingress rule with the specified name.
+func (fw *firewallRulesState) RemoveIngressRule(name string) error {
+	if _, err := fw.IngressRule(name); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      firewallRulesC,
+		Id:     name,
+		Assert: bson.D{{"name", name}},
+		Remove: true,
+	}}
+	if err := fw.st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("firewall rule %q", name)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot remove firewall rule %q", name)
+	}
+	return nil
+}
+
 // AllRules returns all the firewall rules.
 func (fw *firewallRulesState) AllRules() ([]*FirewallRule, error) {
 	coll, closer := fw.st.db().GetCollection(firewallRulesC)
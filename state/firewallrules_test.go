@@ -11,7 +11,9 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/mgo.v2/bson"
 
+	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
 )
 
 type FirewallRulesSuite struct {
@@ -132,3 +134,117 @@ func (s *FirewallRulesSuite) TestUpdate(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	s.assertSavedRules(c, state.JujuApplicationOfferRule, []string{"192.168.2.0/16"})
 }
+
+func (s *FirewallRulesSuite) TestSaveIngressRule(c *gc.C) {
+	rules := state.NewFirewallRules(s.State)
+	rule := state.FirewallRule{
+		Name:           "monitoring",
+		PortRange:      network.MustParsePortRange("9100-9110/tcp"),
+		WhitelistCIDRs: []string{"10.0.0.0/8"},
+		Application:    "mysql",
+	}
+	err := rules.Save(rule)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := rules.IngressRule("monitoring")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*result, jc.DeepEquals, rule)
+}
+
+func (s *FirewallRulesSuite) TestUpdateIngressRule(c *gc.C) {
+	rules := state.NewFirewallRules(s.State)
+	err := rules.Save(state.FirewallRule{
+		Name:           "monitoring",
+		PortRange:      network.MustParsePortRange("9100/tcp"),
+		WhitelistCIDRs: []string{"0.0.0.0/0"},
+		Application:    "mysql",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	rule := state.FirewallRule{
+		Name:           "monitoring",
+		PortRange:      network.MustParsePortRange("9100-9200/udp"),
+		WhitelistCIDRs: []string{"10.0.0.0/8"},
+	}
+	err = rules.Save(rule)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := rules.IngressRule("monitoring")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*result, jc.DeepEquals, rule)
+}
+
+func (s *FirewallRulesSuite) TestSaveIngressRuleInvalid(c *gc.C) {
+	rules := state.NewFirewallRules(s.State)
+	for i, test := range []struct {
+		rule state.FirewallRule
+		err  string
+	}{{
+		rule: state.FirewallRule{Name: "ssh", PortRange: network.MustParsePortRange("22/tcp")},
+		err:  `firewall rule name "ssh" not valid`,
+	}, {
+		rule: state.FirewallRule{Name: "Bad_Name", PortRange: network.MustParsePortRange("22/tcp")},
+		err:  `firewall rule name "Bad_Name" not valid`,
+	}, {
+		rule: state.FirewallRule{Name: "web", PortRange: network.PortRange{FromPort: 90, ToPort: 80, Protocol: "tcp"}},
+		err:  `firewall rule "web": invalid port range 90-80/tcp`,
+	}, {
+		rule: state.FirewallRule{Name: "web", PortRange: network.MustParsePortRange("80/tcp")},
+		err:  `firewall rule "web" without whitelist not valid`,
+	}, {
+		rule: state.FirewallRule{Name: "web", PortRange: network.MustParsePortRange("80/tcp"), WhitelistCIDRs: []string{"0.0.0.0/0"}, Application: "Bad"},
+		err:  `application name "Bad" not valid`,
+	}, {
+		rule: state.FirewallRule{Name: "web", WellKnownService: state.SSHRule, PortRange: network.MustParsePortRange("80/tcp")},
+		err:  `rule "web" with well known service "ssh" not valid`,
+	}} {
+		c.Logf("test %d", i)
+		err := rules.Save(test.rule)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+		c.Check(err, gc.ErrorMatches, regexp.QuoteMeta(test.err))
+	}
+}
+
+func (s *FirewallRulesSuite) TestIngressRuleWellKnownService(c *gc.C) {
+	rules := state.NewFirewallRules(s.State)
+	err := rules.Save(state.FirewallRule{
+		WellKnownService: state.SSHRule,
+		WhitelistCIDRs:   []string{"192.168.1.0/16"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = rules.IngressRule("ssh")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *FirewallRulesSuite) TestRemoveIngressRule(c *gc.C) {
+	rules := state.NewFirewallRules(s.State)
+	err := rules.Save(state.FirewallRule{
+		Name:           "web",
+		PortRange:      network.MustParsePortRange("80/tcp"),
+		WhitelistCIDRs: []string{"0.0.0.0/0"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = rules.RemoveIngressRule("web")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = rules.IngressRule("web")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = rules.RemoveIngressRule("web")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *FirewallRulesSuite) TestWatchFirewallRules(c *gc.C) {
+	w := s.State.WatchFirewallRules()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	rules := state.NewFirewallRules(s.State)
+	err := rules.Save(state.FirewallRule{
+		Name:           "web",
+		PortRange:      network.MustParsePortRange("80/tcp"),
+		WhitelistCIDRs: []string{"0.0.0.0/0"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = rules.RemoveIngressRule("web")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
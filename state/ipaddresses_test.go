@@ -565,6 +565,11 @@ func (s *ipAddressesStateSuite) TestSetDevicesAddressesUpdatesExistingDocs(c *gc
 		DNSServers:       []string{"ns1.example.com", "ns2.example.org"},
 		DNSSearchDomains: []string{"example.com", "example.org"},
 		GatewayAddress:   "0.1.2.1",
+		Routes: []state.AddressRoute{{
+			DestinationCIDR: "10.100.0.0/16",
+			GatewayIP:       "0.1.2.254",
+			Metric:          10,
+		}},
 	}, {
 		// No changed fields, just the required values are set: CIDRAddress +
 		// DeviceName (and s.machine.Id) are used to construct the DocID.
@@ -652,6 +657,7 @@ func (s *ipAddressesStateSuite) checkAddressMatchesArgs(c *gc.C, address *state.
 	c.Check(address.DNSServers(), jc.DeepEquals, args.DNSServers)
 	c.Check(address.DNSSearchDomains(), jc.DeepEquals, args.DNSSearchDomains)
 	c.Check(address.GatewayAddress(), gc.Equals, args.GatewayAddress)
+	c.Check(address.Routes(), jc.DeepEquals, args.Routes)
 }
 
 func (s *ipAddressesStateSuite) TestSetDevicesAddressesWithMultipleUpdatesOfSameDocLastUpdateWins(c *gc.C) {
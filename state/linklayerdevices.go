@@ -54,6 +54,10 @@ type linkLayerDeviceDoc struct {
 	// is inside a container, in which case ParentName can be a global key of a
 	// BridgeDevice on the host machine of the container.
 	ParentName string `bson:"parent-name"`
+
+	// BondMode is the bonding mode (e.g. "802.3ad" or "active-backup") of a
+	// BondDevice. It is empty for other device types, or when not known.
+	BondMode string `bson:"bond-mode,omitempty"`
 }
 
 // LinkLayerDevice represents the state of a link-layer network device for a
@@ -148,6 +152,11 @@ func (dev *LinkLayerDevice) ParentName() string {
 	return dev.doc.ParentName
 }
 
+// BondMode returns the bonding mode of a bond device, if known.
+func (dev *LinkLayerDevice) BondMode() string {
+	return dev.doc.BondMode
+}
+
 func (dev *LinkLayerDevice) parentDeviceNameAndMachineID() (string, string) {
 	if dev.doc.ParentName == "" {
 		// No parent set, so no ID and name to return.
@@ -348,6 +357,11 @@ func updateLinkLayerDeviceDocOp(existingDoc, newDoc *linkLayerDeviceDoc) (txn.Op
 	if existingDoc.ParentName != newDoc.ParentName {
 		changes["parent-name"] = newDoc.ParentName
 	}
+	// The bond mode is usually only known to the provider, so it is not
+	// cleared by updates from observed config that do not include it.
+	if newDoc.BondMode != "" && existingDoc.BondMode != newDoc.BondMode {
+		changes["bond-mode"] = newDoc.BondMode
+	}
 
 	var updates bson.D
 	if len(changes) > 0 {
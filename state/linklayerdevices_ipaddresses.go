@@ -63,6 +63,31 @@ type ipAddressDoc struct {
 
 	// IsDefaultGateway is set to true if that device/subnet is the default gw for the machine
 	IsDefaultGateway bool `bson:"is-default-gateway,omitempty"`
+
+	// Routes contains the additional routes to configure on this IP
+	// address's device. Can be empty.
+	Routes []ipAddressRouteDoc `bson:"routes,omitempty"`
+}
+
+// ipAddressRouteDoc describes a single route of an IP address's device.
+type ipAddressRouteDoc struct {
+	DestinationCIDR string `bson:"destination-cidr"`
+	GatewayIP       string `bson:"gateway-ip"`
+	Metric          int    `bson:"metric,omitempty"`
+}
+
+// AddressRoute defines a route to a subnet via a gateway, to be added when
+// the device of an address is brought up.
+type AddressRoute struct {
+	// DestinationCIDR is the subnet reached by the route.
+	DestinationCIDR string
+
+	// GatewayIP is the address of the gateway for traffic bound for
+	// DestinationCIDR.
+	GatewayIP string
+
+	// Metric is the weight of the route.
+	Metric int
 }
 
 // AddressConfigMethod is the method used to configure a link-layer device's IP
@@ -201,6 +226,23 @@ func (addr *Address) IsDefaultGateway() bool {
 	return addr.doc.IsDefaultGateway
 }
 
+// Routes returns the additional routes to configure on the address's
+// device, which can be empty.
+func (addr *Address) Routes() []AddressRoute {
+	if len(addr.doc.Routes) == 0 {
+		return nil
+	}
+	routes := make([]AddressRoute, len(addr.doc.Routes))
+	for i, route := range addr.doc.Routes {
+		routes[i] = AddressRoute{
+			DestinationCIDR: route.DestinationCIDR,
+			GatewayIP:       route.GatewayIP,
+			Metric:          route.Metric,
+		}
+	}
+	return routes
+}
+
 // String returns a human-readable representation of the IP address.
 func (addr *Address) String() string {
 	return fmt.Sprintf(
@@ -311,6 +353,12 @@ func updateIPAddressDocOp(existingDoc, newDoc *ipAddressDoc) (txn.Op, bool) {
 		changes["gateway-address"] = newDoc.GatewayAddress
 	}
 
+	// Routes are not observed on the machine, so they are not cleared by
+	// updates from observed config that do not include them.
+	if len(newDoc.Routes) > 0 && routesDiffer(newDoc.Routes, existingDoc.Routes) {
+		changes["routes"] = newDoc.Routes
+	}
+
 	var updates bson.D
 	if len(changes) > 0 {
 		updates = append(updates, bson.DocElem{Name: "$set", Value: changes})
@@ -387,3 +435,15 @@ func (st *State) AllIPAddresses() (addresses []*Address, err error) {
 	}
 	return addresses, nil
 }
+
+func routesDiffer(a, b []ipAddressRouteDoc) bool {
+	if len(a) != len(b) {
+		return true
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return true
+		}
+	}
+	return false
+}
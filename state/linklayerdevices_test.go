@@ -216,6 +216,7 @@ func (s *linkLayerDevicesStateSuite) checkSetDeviceMatchesArgs(c *gc.C, setDevic
 	c.Check(setDevice.IsAutoStart(), gc.Equals, args.IsAutoStart)
 	c.Check(setDevice.IsUp(), gc.Equals, args.IsUp)
 	c.Check(setDevice.ParentName(), gc.Equals, args.ParentName)
+	c.Check(setDevice.BondMode(), gc.Equals, args.BondMode)
 }
 
 func (s *linkLayerDevicesStateSuite) checkSetDeviceMatchesMachineIDAndModelUUID(c *gc.C, setDevice *state.LinkLayerDevice, machineID, modelUUID string) {
@@ -312,6 +313,28 @@ func (s *linkLayerDevicesStateSuite) TestSetLinkLayerDevicesDoesNotClearProvider
 	c.Assert(device.ProviderID(), gc.Equals, corenetwork.Id("42"))
 }
 
+func (s *linkLayerDevicesStateSuite) TestSetLinkLayerDevicesBondMode(c *gc.C) {
+	args := state.LinkLayerDeviceArgs{
+		Name:       "bond0",
+		Type:       corenetwork.BondDevice,
+		MACAddress: "aa:bb:cc:dd:ee:f0",
+		BondMode:   "802.3ad",
+	}
+	s.assertSetLinkLayerDevicesSucceedsAndResultMatchesArgs(c, args)
+
+	// Updates without a bond mode, such as from observed
+	// config, do not clear it.
+	args.BondMode = ""
+	err := s.machine.SetLinkLayerDevices(args)
+	c.Assert(err, jc.ErrorIsNil)
+	device, err := s.machine.LinkLayerDevice(args.Name)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(device.BondMode(), gc.Equals, "802.3ad")
+
+	args.BondMode = "active-backup"
+	s.assertSetLinkLayerDevicesSucceedsAndResultMatchesArgs(c, args)
+}
+
 func (s *linkLayerDevicesStateSuite) TestSetLinkLayerDevicesMultipleArgsWithSameNameFails(c *gc.C) {
 	foo1 := state.LinkLayerDeviceArgs{
 		Name: "foo",
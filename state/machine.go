@@ -140,6 +140,14 @@ type machineDoc struct {
 	// StopMongoUntilVersion holds the version that must be checked to
 	// know if mongo must be stopped.
 	StopMongoUntilVersion string `bson:",omitempty"`
+
+	// Protected is set if the machine's cloud instance must not be
+	// terminated when the machine is removed from Juju.
+	Protected bool `bson:"protected,omitempty"`
+
+	// EgressEnforcedByProvider is set if the provider's firewall
+	// enforces the egress rules of the machine's cloud instance.
+	EgressEnforcedByProvider bool `bson:"egress-provider-enforced,omitempty"`
 }
 
 func newMachine(st *State, doc *machineDoc) *Machine {
@@ -325,6 +333,69 @@ func (m *Machine) KeepInstance() (bool, error) {
 	return instData.KeepInstance, nil
 }
 
+// IsProtected reports whether the machine's cloud instance is
+// protected from termination.
+func (m *Machine) IsProtected() bool {
+	return m.doc.Protected
+}
+
+// SetProtected sets whether the machine's cloud instance is protected
+// from termination. A protected machine cannot be destroyed unless its
+// cloud instance is to be kept. Only alive machines may be protected.
+func (m *Machine) SetProtected(protected bool) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set protection for machine %v", m)
+	var assert interface{} = txn.DocExists
+	abortErr := errors.NotFoundf("machine")
+	if protected {
+		assert = isAliveDoc
+		abortErr = machineNotAliveErr
+	}
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: assert,
+		Update: bson.D{{"$set", bson.D{{"protected", protected}}}},
+	}}
+	if err := m.st.db().RunTransaction(ops); err != nil {
+		return onAbort(err, abortErr)
+	}
+	m.doc.Protected = protected
+	return nil
+}
+
+// protectionOps returns the operations which ensure that destroying the
+// machine will not terminate a protected cloud instance. It returns a
+// MachineProtectedError if the machine is protected, provisioned, and
+// its instance is not to be kept.
+func (m *Machine) protectionOps() ([]txn.Op, error) {
+	if !m.doc.Protected {
+		return []txn.Op{{
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: bson.D{{"protected", bson.D{{"$ne", true}}}},
+		}}, nil
+	}
+	keep, err := m.KeepInstance()
+	if errors.IsNotFound(err) {
+		// There is no instance to terminate yet.
+		return []txn.Op{{
+			C:      instanceDataC,
+			Id:     m.doc.DocID,
+			Assert: txn.DocMissing,
+		}}, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !keep {
+		return nil, &MachineProtectedError{MachineId: m.doc.Id}
+	}
+	return []txn.Op{{
+		C:      instanceDataC,
+		Id:     m.doc.DocID,
+		Assert: bson.D{{"keep-instance", true}},
+	}}, nil
+}
+
 // CharmProfiles returns the names of any LXD profiles used by the machine,
 // which were defined in the charm deployed to that machine.
 func (m *Machine) CharmProfiles() ([]string, error) {
@@ -547,6 +618,10 @@ func (m *Machine) ForceDestroy(maxWait time.Duration) error {
 }
 
 func (m *Machine) forceDestroyOps(maxWait time.Duration) ([]txn.Op, error) {
+	protectionOps, err := m.protectionOps()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	if m.IsManager() {
 		controllerIds, err := m.st.ControllerIds()
 		if err != nil {
@@ -575,20 +650,20 @@ func (m *Machine) forceDestroyOps(maxWait time.Duration) ([]txn.Op, error) {
 		// Note that ForceDestroy does *not* cleanup the replicaset, so it might cause problems.
 		// However, we're letting the user handle times when the machine agent isn't running, etc.
 		// We may need to update the peergrouper for this.
-		return []txn.Op{
+		return append([]txn.Op{
 			machineOp,
 			controllerOp,
 			setControllerWantsVoteOp(m.st, m.Id(), false),
 			newCleanupOp(cleanupForceDestroyedMachine, m.doc.Id, maxWait),
-		}, nil
+		}, protectionOps...), nil
 	} else {
 		// Make sure the machine doesn't become a manager while we're destroying it
-		return []txn.Op{{
+		return append([]txn.Op{{
 			C:      machinesC,
 			Id:     m.doc.DocID,
 			Assert: bson.D{{"jobs", bson.D{{"$nin", []MachineJob{JobManageModel}}}}},
 		}, newCleanupOp(cleanupForceDestroyedMachine, m.doc.Id, maxWait),
-		}, nil
+		}, protectionOps...), nil
 	}
 }
 
@@ -601,6 +676,24 @@ func (m *Machine) EnsureDead() error {
 	return m.advanceLifecycle(Dead, false, 0)
 }
 
+// MachineProtectedError is returned when an attempt is made to destroy
+// a machine whose cloud instance is protected from termination.
+type MachineProtectedError struct {
+	MachineId string
+}
+
+func (e *MachineProtectedError) Error() string {
+	return fmt.Sprintf("machine %s is protected from termination", e.MachineId)
+}
+
+// IsMachineProtectedError reports whether or not the error is a
+// MachineProtectedError, indicating that an attempt to destroy a
+// machine failed due to its instance being protected from termination.
+func IsMachineProtectedError(err error) bool {
+	_, ok := errors.Cause(err).(*MachineProtectedError)
+	return ok
+}
+
 type HasAssignedUnitsError struct {
 	MachineId string
 	UnitNames []string
@@ -756,6 +849,11 @@ func (original *Machine) advanceLifecycle(life Life, force bool, maxWait time.Du
 			if m.doc.Life != Alive {
 				return nil, jujutxn.ErrNoOperations
 			}
+			protectionOps, err := m.protectionOps()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, protectionOps...)
 			// Manager nodes are allowed to go to dying even when they have the vote, as that is used as the signal
 			// that they should lose their vote
 			asserts = append(asserts, isAliveDoc...)
@@ -993,6 +1091,7 @@ func (m *Machine) removeOps() ([]txn.Op, error) {
 		removeMachineBlockDevicesOp(m.Id()),
 		removeModelMachineRefOp(m.st, m.Id()),
 		removeSSHHostKeyOp(m.globalKey()),
+		removeMachineNetplanOp(m.globalKey()),
 	}
 	linkLayerDevicesOps, err := m.removeAllLinkLayerDevicesOps()
 	if err != nil {
@@ -1417,6 +1516,60 @@ func (m *Machine) SetInstanceInfo(
 	return m.SetCharmProfiles(charmProfiles)
 }
 
+// ResetInstance removes the record of the machine's provider instance,
+// so that a replacement instance may be provisioned for it. It is used
+// when the cloud has reclaimed the instance, such as when a spot instance
+// is interrupted. Controller and manually provisioned machines cannot be
+// reset.
+func (m *Machine) ResetInstance() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot reset instance for machine %q", m)
+
+	if m.IsManager() {
+		return errors.NotSupportedf("resetting a controller machine")
+	}
+	if manual, err := m.IsManual(); err != nil {
+		return errors.Trace(err)
+	} else if manual {
+		return errors.NotSupportedf("resetting a manually provisioned machine")
+	}
+	if m.doc.Nonce == "" {
+		return errors.NotProvisionedf("machine %v", m.Id())
+	}
+
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: append(isAliveDoc, bson.DocElem{Name: "nonce", Value: m.doc.Nonce}),
+		Update: bson.D{
+			{"$set", bson.D{{"nonce", ""}}},
+			{"$unset", bson.D{
+				{"addresses", nil},
+				{"preferredpublicaddress", nil},
+				{"preferredprivateaddress", nil},
+			}},
+		},
+	}, {
+		C:      instanceDataC,
+		Id:     m.doc.DocID,
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	if err = m.st.db().RunTransaction(ops); err == nil {
+		m.doc.Nonce = ""
+		m.doc.Addresses = nil
+		m.doc.PreferredPublicAddress = address{}
+		m.doc.PreferredPrivateAddress = address{}
+		return nil
+	} else if err != txn.ErrAborted {
+		return errors.Trace(err)
+	} else if alive, err := isAlive(m.st, machinesC, m.doc.DocID); err != nil {
+		return errors.Trace(err)
+	} else if !alive {
+		return machineNotAliveErr
+	}
+	return errors.New("instance has changed")
+}
+
 // Addresses returns any hostnames and ips associated with a machine,
 // determined both by the machine itself, and by asking the provider.
 //
@@ -1809,6 +1962,69 @@ func (m *Machine) setConstraintsOps(cons constraints.Value) ([]txn.Op, error) {
 	return ops, nil
 }
 
+// SetInstanceResized records that the machine's cloud instance has been
+// resized to satisfy the given constraints, replacing the machine's
+// constraints and the non-nil hardware characteristics of its instance.
+func (m *Machine) SetInstanceResized(cons constraints.Value, characteristics instance.HardwareCharacteristics) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot record resize of machine %v", m)
+	unsupported, err := m.st.validateConstraints(cons)
+	if len(unsupported) > 0 {
+		logger.Warningf(
+			"setting constraints on machine %q: unsupported constraints: %v",
+			m.Id(), strings.Join(unsupported, ","),
+		)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	mcons, err := m.st.resolveMachineConstraints(cons)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	var hardware bson.D
+	if characteristics.Arch != nil {
+		hardware = append(hardware, bson.DocElem{Name: "arch", Value: *characteristics.Arch})
+	}
+	if characteristics.Mem != nil {
+		hardware = append(hardware, bson.DocElem{Name: "mem", Value: *characteristics.Mem})
+	}
+	if characteristics.RootDisk != nil {
+		hardware = append(hardware, bson.DocElem{Name: "rootdisk", Value: *characteristics.RootDisk})
+	}
+	if characteristics.CpuCores != nil {
+		hardware = append(hardware, bson.DocElem{Name: "cpucores", Value: *characteristics.CpuCores})
+	}
+	if characteristics.CpuPower != nil {
+		hardware = append(hardware, bson.DocElem{Name: "cpupower", Value: *characteristics.CpuPower})
+	}
+
+	instanceOp := txn.Op{
+		C:      instanceDataC,
+		Id:     m.doc.DocID,
+		Assert: txn.DocExists,
+	}
+	if len(hardware) > 0 {
+		instanceOp.Update = bson.D{{"$set", hardware}}
+	}
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: isAliveDoc,
+	},
+		instanceOp,
+		setConstraintsOp(m.globalKey(), mcons),
+	}
+	if err := m.st.db().RunTransaction(ops); err != txn.ErrAborted {
+		return errors.Trace(err)
+	}
+	if alive, err := isAlive(m.st, machinesC, m.doc.DocID); err != nil {
+		return errors.Trace(err)
+	} else if !alive {
+		return machineNotAliveErr
+	}
+	return errors.NotProvisionedf("machine %v", m.Id())
+}
+
 // Status returns the status of the machine.
 func (m *Machine) Status() (status.StatusInfo, error) {
 	mStatus, err := getStatus(m.st.db(), m.globalKey(), "machine")
@@ -2003,7 +2219,7 @@ func (m *Machine) VolumeAttachments() ([]VolumeAttachment, error) {
 
 // AddAction is part of the ActionReceiver interface.
 func (m *Machine) AddAction(name string, payload map[string]interface{}) (Action, error) {
-	spec, ok := actions.PredefinedActionsSpec[name]
+	spec, ok := actions.MachineActionSpec(name)
 	if !ok {
 		return nil, errors.Errorf("cannot add action %q to a machine; only predefined actions allowed", name)
 	}
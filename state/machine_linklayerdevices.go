@@ -161,6 +161,10 @@ type LinkLayerDeviceArgs struct {
 	// key of a BridgeDevice on the host machine of the container. Traffic
 	// originating from a device egresses from its parent device.
 	ParentName string
+
+	// BondMode is the bonding mode of a BondDevice, which may be empty
+	// if not known.
+	BondMode string
 }
 
 // SetLinkLayerDevices sets link-layer devices on the machine, adding or
@@ -423,6 +427,7 @@ func (m *Machine) newLinkLayerDeviceDocFromArgs(args *LinkLayerDeviceArgs) *link
 		IsAutoStart: args.IsAutoStart,
 		IsUp:        args.IsUp,
 		ParentName:  args.ParentName,
+		BondMode:    args.BondMode,
 	}
 }
 
@@ -596,6 +601,10 @@ type LinkLayerDeviceAddress struct {
 	// IsDefaultGateway is set to true if this address on this device is the
 	// default gw on a machine.
 	IsDefaultGateway bool
+
+	// Routes contains the additional routes to configure on the device,
+	// which can be empty.
+	Routes []AddressRoute
 }
 
 // SetDevicesAddresses sets the addresses of all devices in devicesAddresses,
@@ -769,10 +778,26 @@ func (m *Machine) newIPAddressDocFromArgs(args *LinkLayerDeviceAddress) (*ipAddr
 		DNSSearchDomains: args.DNSSearchDomains,
 		GatewayAddress:   args.GatewayAddress,
 		IsDefaultGateway: args.IsDefaultGateway,
+		Routes:           ipAddressRouteDocs(args.Routes),
 	}
 	return newDoc, nil
 }
 
+func ipAddressRouteDocs(routes []AddressRoute) []ipAddressRouteDoc {
+	if len(routes) == 0 {
+		return nil
+	}
+	docs := make([]ipAddressRouteDoc, len(routes))
+	for i, route := range routes {
+		docs[i] = ipAddressRouteDoc{
+			DestinationCIDR: route.DestinationCIDR,
+			GatewayIP:       route.GatewayIP,
+			Metric:          route.Metric,
+		}
+	}
+	return docs
+}
+
 func (m *Machine) verifySubnetAlive(subnet *Subnet) error {
 	if subnet.Life() != Alive {
 		return errors.Errorf("subnet %q is not alive", subnet.CIDR())
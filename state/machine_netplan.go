@@ -0,0 +1,115 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// MachineNetplanStatus holds the netplan configuration last reported by
+// a machine agent, which can be compared with the configuration rendered
+// from the link-layer devices Juju knows about for the machine.
+type MachineNetplanStatus struct {
+	// Actual is the netplan YAML in effect on the machine.
+	Actual string
+
+	// Error is the reason that the machine agent could not apply the
+	// desired configuration, if it tried and failed.
+	Error string
+
+	// Updated is the time at which the status was reported.
+	Updated time.Time
+}
+
+// machineNetplanDoc represents the MongoDB document that stores the
+// netplan status of a machine.
+type machineNetplanDoc struct {
+	Actual  string    `bson:"actual"`
+	Error   string    `bson:"error,omitempty"`
+	Updated time.Time `bson:"updated"`
+}
+
+// NetplanStatus returns the netplan status last reported by the
+// machine's agent. An error satisfying errors.IsNotFound is returned
+// if none has been reported.
+func (m *Machine) NetplanStatus() (MachineNetplanStatus, error) {
+	coll, closer := m.st.db().GetCollection(machineNetplanC)
+	defer closer()
+
+	var doc machineNetplanDoc
+	err := coll.FindId(m.globalKey()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return MachineNetplanStatus{}, errors.NotFoundf("netplan status for machine %q", m.Id())
+	} else if err != nil {
+		return MachineNetplanStatus{}, errors.Annotatef(err, "getting netplan status for machine %q", m.Id())
+	}
+	return MachineNetplanStatus{
+		Actual:  doc.Actual,
+		Error:   doc.Error,
+		Updated: doc.Updated,
+	}, nil
+}
+
+// SetNetplanStatus records the netplan status reported by the machine's
+// agent, replacing any previously reported status.
+func (m *Machine) SetNetplanStatus(status MachineNetplanStatus) error {
+	coll, closer := m.st.db().GetCollection(machineNetplanC)
+	defer closer()
+
+	id := m.globalKey()
+	doc := machineNetplanDoc{
+		Actual:  status.Actual,
+		Error:   status.Error,
+		Updated: status.Updated,
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.isStillAlive(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		ops := []txn.Op{m.assertAliveOp()}
+		n, err := coll.FindId(id).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if n == 0 {
+			return append(ops, txn.Op{
+				C:      machineNetplanC,
+				Id:     id,
+				Assert: txn.DocMissing,
+				Insert: doc,
+			}), nil
+		}
+		return append(ops, txn.Op{
+			C:      machineNetplanC,
+			Id:     id,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{
+				{"actual", doc.Actual},
+				{"error", doc.Error},
+				{"updated", doc.Updated},
+			}}},
+		}), nil
+	}
+	if err := m.st.db().Run(buildTxn); err != nil {
+		return errors.Annotatef(err, "setting netplan status for machine %q", m.Id())
+	}
+	return nil
+}
+
+// removeMachineNetplanOp returns the operation needed to remove the
+// netplan status document associated with the given machine global key.
+func removeMachineNetplanOp(globalKey string) txn.Op {
+	return txn.Op{
+		C:      machineNetplanC,
+		Id:     globalKey,
+		Remove: true,
+	}
+}
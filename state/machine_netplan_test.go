@@ -0,0 +1,88 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type MachineNetplanSuite struct {
+	ConnSuite
+	machine *state.Machine
+}
+
+var _ = gc.Suite(&MachineNetplanSuite{})
+
+func (s *MachineNetplanSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.machine = s.Factory.MakeMachine(c, nil)
+}
+
+func (s *MachineNetplanSuite) TestNetplanStatusNotFound(c *gc.C) {
+	_, err := s.machine.NetplanStatus()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *MachineNetplanSuite) TestSetNetplanStatus(c *gc.C) {
+	now := time.Now().UTC().Round(time.Second)
+	status := state.MachineNetplanStatus{
+		Actual:  "network:\n  version: 2\n",
+		Updated: now,
+	}
+	err := s.machine.SetNetplanStatus(status)
+	c.Assert(err, jc.ErrorIsNil)
+
+	got, err := s.machine.NetplanStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got.Actual, gc.Equals, status.Actual)
+	c.Assert(got.Error, gc.Equals, "")
+	c.Assert(got.Updated.Equal(now), jc.IsTrue)
+
+	// A subsequent report replaces the first.
+	status.Error = "netplan apply failed"
+	status.Updated = now.Add(time.Minute)
+	err = s.machine.SetNetplanStatus(status)
+	c.Assert(err, jc.ErrorIsNil)
+
+	got, err = s.machine.NetplanStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got.Error, gc.Equals, "netplan apply failed")
+	c.Assert(got.Updated.Equal(now.Add(time.Minute)), jc.IsTrue)
+
+	// A successful report clears the previous error.
+	status.Error = ""
+	err = s.machine.SetNetplanStatus(status)
+	c.Assert(err, jc.ErrorIsNil)
+
+	got, err = s.machine.NetplanStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got.Error, gc.Equals, "")
+}
+
+func (s *MachineNetplanSuite) TestSetNetplanStatusDeadMachine(c *gc.C) {
+	err := s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.SetNetplanStatus(state.MachineNetplanStatus{Actual: "network: {}\n"})
+	c.Assert(err, gc.ErrorMatches, `setting netplan status for machine "0": machine not found or not alive`)
+}
+
+func (s *MachineNetplanSuite) TestNetplanStatusRemovedWithMachine(c *gc.C) {
+	err := s.machine.SetNetplanStatus(state.MachineNetplanStatus{Actual: "network: {}\n"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.machine.NetplanStatus()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -139,6 +139,66 @@ func (s *MachineSuite) TestSetKeepInstance(c *gc.C) {
 	c.Assert(keep, jc.IsTrue)
 }
 
+func (s *MachineSuite) TestSetProtected(c *gc.C) {
+	c.Assert(s.machine.IsProtected(), jc.IsFalse)
+	err := s.machine.SetProtected(true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.IsProtected(), jc.IsTrue)
+
+	m, err := s.State.Machine(s.machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.IsProtected(), jc.IsTrue)
+
+	err = s.machine.SetProtected(false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.IsProtected(), jc.IsFalse)
+}
+
+func (s *MachineSuite) TestSetProtectedNotAlive(c *gc.C) {
+	err := s.machine.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetProtected(true)
+	c.Assert(err, gc.ErrorMatches, `cannot set protection for machine 1: machine is not found or not alive`)
+
+	// Protection may always be removed.
+	err = s.machine.SetProtected(false)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MachineSuite) TestDestroyProtectedMachine(c *gc.C) {
+	err := s.machine.SetProvisioned("1234", "", "nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetProtected(true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.Destroy()
+	c.Assert(err, gc.ErrorMatches, `machine 1 is protected from termination`)
+	c.Assert(state.IsMachineProtectedError(err), jc.IsTrue)
+	err = s.machine.ForceDestroy(time.Minute)
+	c.Assert(state.IsMachineProtectedError(err), jc.IsTrue)
+	c.Assert(s.machine.Refresh(), jc.ErrorIsNil)
+	c.Assert(s.machine.Life(), gc.Equals, state.Alive)
+
+	// Keeping the instance means it will not be terminated.
+	err = s.machine.SetKeepInstance(true)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.Refresh(), jc.ErrorIsNil)
+	c.Assert(s.machine.Life(), gc.Equals, state.Dying)
+}
+
+func (s *MachineSuite) TestDestroyProtectedMachineNotProvisioned(c *gc.C) {
+	err := s.machine.SetProtected(true)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.Refresh(), jc.ErrorIsNil)
+	c.Assert(s.machine.Life(), gc.Equals, state.Dying)
+}
+
 func (s *MachineSuite) TestAddMachineInsideMachineModelDying(c *gc.C) {
 	model, err := s.State.Model()
 	c.Assert(err, jc.ErrorIsNil)
@@ -970,6 +1030,57 @@ func (s *MachineSuite) TestMachineSetProvisionedWhenNotAlive(c *gc.C) {
 	})
 }
 
+func (s *MachineSuite) TestMachineResetInstance(c *gc.C) {
+	err := s.machine.SetProvisioned("umbrella/0", "", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetProviderAddresses(corenetwork.NewSpaceAddress("10.0.0.1"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.ResetInstance()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.CheckProvisioned("fake_nonce"), jc.IsFalse)
+	c.Assert(s.machine.ProviderAddresses(), gc.HasLen, 0)
+
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.machine.InstanceId()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+	c.Assert(s.machine.ProviderAddresses(), gc.HasLen, 0)
+
+	// A replacement instance may now be recorded.
+	err = s.machine.SetProvisioned("umbrella/1", "", "fake_nonce2", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	iid, err := s.machine.InstanceId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(iid, gc.Equals, instance.Id("umbrella/1"))
+}
+
+func (s *MachineSuite) TestMachineResetInstanceNotProvisioned(c *gc.C) {
+	err := s.machine.ResetInstance()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+}
+
+func (s *MachineSuite) TestMachineResetInstanceController(c *gc.C) {
+	err := s.machine0.ResetInstance()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *MachineSuite) TestMachineResetInstanceManual(c *gc.C) {
+	err := s.machine.SetProvisioned("umbrella/0", "", "manual:fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.ResetInstance()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *MachineSuite) TestMachineResetInstanceWhenNotAlive(c *gc.C) {
+	err := s.machine.SetProvisioned("umbrella/0", "", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.ResetInstance()
+	c.Assert(err, gc.ErrorMatches, `cannot reset instance for machine "1": machine is not found or not alive`)
+}
+
 func (s *MachineSuite) TestMachineSetInstanceStatus(c *gc.C) {
 	// Machine needs to be provisioned first.
 	err := s.machine.SetProvisioned("umbrella/0", "", "fake_nonce", nil)
@@ -1588,6 +1699,57 @@ func (s *MachineSuite) TestSetConstraints(c *gc.C) {
 	c.Assert(mcons, gc.DeepEquals, cons1)
 }
 
+func (s *MachineSuite) TestSetInstanceResized(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	arch := "amd64"
+	mem := uint64(1024)
+	cores := uint64(1)
+	err = machine.SetProvisioned("i-resize", "", "fake_nonce", &instance.HardwareCharacteristics{
+		Arch:     &arch,
+		Mem:      &mem,
+		CpuCores: &cores,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	cons := constraints.MustParse("mem=16G cores=4")
+	newMem := uint64(16384)
+	newCores := uint64(4)
+	err = machine.SetInstanceResized(cons, instance.HardwareCharacteristics{
+		Mem:      &newMem,
+		CpuCores: &newCores,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	mcons, err := machine.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mcons, gc.DeepEquals, cons)
+	hc, err := machine.HardwareCharacteristics()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*hc, jc.DeepEquals, instance.HardwareCharacteristics{
+		Arch:     &arch,
+		Mem:      &newMem,
+		CpuCores: &newCores,
+	})
+}
+
+func (s *MachineSuite) TestSetInstanceResizedNotProvisioned(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetInstanceResized(constraints.MustParse("mem=16G"), instance.HardwareCharacteristics{})
+	c.Assert(err, gc.ErrorMatches, `cannot record resize of machine 2: machine 2 not provisioned`)
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+}
+
+func (s *MachineSuite) TestSetInstanceResizedNotAlive(c *gc.C) {
+	err := s.machine.SetProvisioned("i-resize", "", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetInstanceResized(constraints.MustParse("mem=16G"), instance.HardwareCharacteristics{})
+	c.Assert(err, gc.ErrorMatches, `cannot record resize of machine 1: machine is not found or not alive`)
+}
+
 func (s *MachineSuite) TestSetAmbiguousConstraints(c *gc.C) {
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -2467,6 +2629,11 @@ func (s *MachineSuite) TestMachineValidActions(c *gc.C) {
 			givenPayload:    map[string]interface{}{"command": "allyourbasearebelongtous", "timeout": 5.0},
 			expectedPayload: map[string]interface{}{"command": "allyourbasearebelongtous", "timeout": 5.0},
 		},
+		{
+			actionName:      "juju-connectivity-listen",
+			givenPayload:    map[string]interface{}{"address": "10.0.0.1", "timeout": 5.0},
+			expectedPayload: map[string]interface{}{"address": "10.0.0.1", "timeout": 5.0},
+		},
 		{
 			actionName: "baiku",
 			errString:  `cannot add action "baiku" to a machine; only predefined actions allowed`,
@@ -199,6 +199,14 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 
 		// Resources are transferred separately
 		"storedResources",
+
+		// Storage snapshots refer to provider resources that may
+		// not be usable from the target controller's cloud.
+		storageSnapshotsC,
+
+		// Netplan status is reported again by the machine agents
+		// once they connect to the target controller.
+		machineNetplanC,
 	)
 
 	// THIS SET WILL BE REMOVED WHEN MIGRATIONS ARE COMPLETE
@@ -212,7 +220,13 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		externalControllersC,
 		relationNetworksC,
 		firewallRulesC,
+		// Egress rules are not yet exported; models with any are
+		// refused by the migration prechecks.
+		egressRulesC,
 		dockerResourcesC,
+		// Storage migration records are not yet exported; models
+		// with any are refused by the migration prechecks.
+		storageMigrationsC,
 		// TODO(raftlease)
 		// This collection shouldn't be migrated, but we need to make
 		// sure the leader units' leases are claimed in the target
@@ -320,6 +334,12 @@ func (s *MigrationSuite) TestMachineDocFields(c *gc.C) {
 		// Ignored at this stage, could be an issue if mongo 3.0 isn't
 		// available.
 		"StopMongoUntilVersion",
+		// Protected is not supported by the model description;
+		// migration prechecks refuse models with protected machines.
+		"Protected",
+		// EgressEnforcedByProvider is re-established by the
+		// firewaller in the target controller.
+		"EgressEnforcedByProvider",
 	)
 	migrated := set.NewStrings(
 		"Addresses",
@@ -573,7 +593,16 @@ func (s *MigrationSuite) TestConstraintsDocFields(c *gc.C) {
 		"VirtType",
 		"Zones",
 	)
-	s.AssertExportedFields(c, constraintsDoc{}, fields)
+	ignored := set.NewStrings(
+		// Spot, MaxPrice and AntiAffinity are not yet supported
+		// by the model description, so are not migrated. The
+		// migration prechecks refuse machines with spot,
+		// max-price or anti-affinity constraints.
+		"Spot",
+		"MaxPrice",
+		"AntiAffinity",
+	)
+	s.AssertExportedFields(c, constraintsDoc{}, fields.Union(ignored))
 }
 
 func (s *MigrationSuite) TestHistoricalStatusDocFields(c *gc.C) {
@@ -665,6 +694,9 @@ func (s *MigrationSuite) TestIPAddressDocFields(c *gc.C) {
 	ignored := set.NewStrings(
 		"DocID",
 		"ModelUUID",
+		// Routes are not yet supported by the model description, so
+		// models with routes are refused by the migration precheck.
+		"Routes",
 	)
 	migrated := set.NewStrings(
 		"DeviceName",
@@ -686,6 +718,9 @@ func (s *MigrationSuite) TestLinkLayerDeviceDocFields(c *gc.C) {
 	ignored := set.NewStrings(
 		"ModelUUID",
 		"DocID",
+		// BondMode is not yet supported by the model description, so
+		// models with bond modes are refused by the migration precheck.
+		"BondMode",
 	)
 	migrated := set.NewStrings(
 		"MachineID",
@@ -734,8 +769,9 @@ func (s *MigrationSuite) TestVolumeDocFields(c *gc.C) {
 		"ModelUUID",
 		"DocID",
 		"Life",
-		"HostId",    // recreated from pool properties
-		"Releasing", // only when dying; can't migrate dying storage
+		"HostId",        // recreated from pool properties
+		"Releasing",     // only when dying; can't migrate dying storage
+		"RequestedSize", // pending resize requests are not migrated
 	)
 	migrated := set.NewStrings(
 		"Name",
@@ -747,9 +783,17 @@ func (s *MigrationSuite) TestVolumeDocFields(c *gc.C) {
 	s.AssertExportedFields(c, volumeDoc{}, migrated.Union(ignored))
 	// The info and params fields ar structs.
 	s.AssertExportedFields(c, VolumeInfo{}, set.NewStrings(
-		"HardwareId", "WWN", "Size", "Pool", "VolumeId", "Persistent"))
+		"HardwareId", "WWN", "Size", "Pool", "VolumeId", "Persistent",
+		// Encrypted is not yet supported by the description
+		// package; models with encrypted volumes are refused by
+		// the migration prechecks.
+		"Encrypted"))
 	s.AssertExportedFields(c, VolumeParams{}, set.NewStrings(
-		"Size", "Pool"))
+		"Size", "Pool",
+		// SnapshotId is not yet supported by the description
+		// package; models with volumes still to be restored from
+		// a snapshot are refused by the migration prechecks.
+		"SnapshotId"))
 }
 
 func (s *MigrationSuite) TestVolumeAttachmentDocFields(c *gc.C) {
@@ -777,8 +821,9 @@ func (s *MigrationSuite) TestFilesystemDocFields(c *gc.C) {
 		"ModelUUID",
 		"DocID",
 		"Life",
-		"HostId",    // recreated from pool properties
-		"Releasing", // only when dying; can't migrate dying storage
+		"HostId",        // recreated from pool properties
+		"Releasing",     // only when dying; can't migrate dying storage
+		"RequestedSize", // pending resize requests are not migrated
 	)
 	migrated := set.NewStrings(
 		"FilesystemId",
@@ -801,6 +846,7 @@ func (s *MigrationSuite) TestFilesystemAttachmentDocFields(c *gc.C) {
 		"ModelUUID",
 		"DocID",
 		"Life",
+		"Usage", // reported periodically by the machine agent
 	)
 	migrated := set.NewStrings(
 		"Filesystem",
@@ -822,6 +868,10 @@ func (s *MigrationSuite) TestStorageInstanceDocFields(c *gc.C) {
 		"DocID",
 		"Life",
 		"Releasing", // only when dying; can't migrate dying storage
+		// Storage snapshots are not migrated; models with storage
+		// still to be restored from a snapshot are refused by the
+		// migration prechecks.
+		"SourceSnapshot",
 	)
 	migrated := set.NewStrings(
 		"Id",
@@ -839,6 +889,9 @@ func (s *MigrationSuite) TestStorageAttachmentDocFields(c *gc.C) {
 		"ModelUUID",
 		"DocID",
 		"Life",
+		// Storage migration records are not yet exported; models
+		// with any are refused by the migration prechecks.
+		"Migration",
 	)
 	migrated := set.NewStrings(
 		"Unit",
@@ -1044,6 +1044,39 @@ func (m *Model) Destroy(args DestroyModelParams) (err error) {
 	return m.st.db().Run(buildTxn)
 }
 
+// protectedMachineIds returns the ids of the machines in the model
+// which are protected from termination, and the operations which
+// assert that the other machines are still unprotected.
+func (st *State) protectedMachineIds() ([]string, []txn.Op, error) {
+	machines, closer := st.db().GetCollection(machinesC)
+	defer closer()
+
+	var docs []struct {
+		DocID     string `bson:"_id"`
+		Id        string `bson:"machineid"`
+		Protected bool   `bson:"protected"`
+	}
+	query := bson.D{{"life", bson.D{{"$ne", Dead}}}}
+	fields := bson.D{{"_id", 1}, {"machineid", 1}, {"protected", 1}}
+	if err := machines.Find(query).Select(fields).Sort("machineid").All(&docs); err != nil {
+		return nil, nil, errors.Annotate(err, "cannot read protected machines")
+	}
+	var ids []string
+	var ops []txn.Op
+	for _, doc := range docs {
+		if doc.Protected {
+			ids = append(ids, doc.Id)
+			continue
+		}
+		ops = append(ops, txn.Op{
+			C:      machinesC,
+			Id:     doc.DocID,
+			Assert: bson.D{{"protected", bson.D{{"$ne", true}}}},
+		})
+	}
+	return ids, ops, nil
+}
+
 // errModelNotAlive is a signal emitted from destroyOps to indicate
 // that model destruction is already underway.
 var errModelNotAlive = errors.New("model is no longer alive")
@@ -1188,6 +1221,19 @@ func (m *Model) destroyOps(
 			}
 			prereqOps = storageOps
 		}
+		// Machines protected from termination must be unprotected
+		// before the model is destroyed, even if destruction is forced.
+		protected, protectionOps, err := m.st.protectedMachineIds()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(protected) > 0 {
+			return nil, errors.Errorf(
+				"model %q has machines protected from termination: %s",
+				m.Name(), strings.Join(protected, ", "),
+			)
+		}
+		prereqOps = append(prereqOps, protectionOps...)
 	}
 
 	if m.IsControllerModel() && (!args.DestroyHostedModels || args.DestroyStorage == nil || !*args.DestroyStorage) {
@@ -632,6 +632,45 @@ func (s *ModelSuite) TestDestroyOtherModel(c *gc.C) {
 	c.Assert(model.UniqueIndexExists(), jc.IsFalse)
 }
 
+func (s *ModelSuite) TestDestroyModelWithProtectedMachines(c *gc.C) {
+	st2 := s.Factory.MakeModel(c, nil)
+	defer st2.Close()
+	m := factory.NewFactory(st2, s.StatePool).MakeMachine(c, nil)
+	c.Assert(m.SetProtected(true), jc.ErrorIsNil)
+
+	model, err := st2.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	force := true
+	err = model.Destroy(state.DestroyModelParams{Force: &force})
+	c.Assert(err, gc.ErrorMatches, `failed to destroy model: model ".*" has machines protected from termination: 0`)
+	c.Assert(model.Refresh(), jc.ErrorIsNil)
+	c.Assert(model.Life(), gc.Equals, state.Alive)
+
+	c.Assert(m.SetProtected(false), jc.ErrorIsNil)
+	c.Assert(model.Destroy(state.DestroyModelParams{}), jc.ErrorIsNil)
+	c.Assert(model.Refresh(), jc.ErrorIsNil)
+	c.Assert(model.Life(), gc.Equals, state.Dying)
+}
+
+func (s *ModelSuite) TestDestroyModelMachineProtectedRace(c *gc.C) {
+	st2 := s.Factory.MakeModel(c, nil)
+	defer st2.Close()
+	m := factory.NewFactory(st2, s.StatePool).MakeMachine(c, nil)
+
+	// Simulate the machine being protected just before the
+	// destroy txn is run.
+	defer state.SetBeforeHooks(c, st2, func() {
+		c.Assert(m.SetProtected(true), jc.ErrorIsNil)
+	}).Check()
+
+	model, err := st2.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	err = model.Destroy(state.DestroyModelParams{})
+	c.Assert(err, gc.ErrorMatches, `failed to destroy model: model ".*" has machines protected from termination: 0`)
+	c.Assert(model.Refresh(), jc.ErrorIsNil)
+	c.Assert(model.Life(), gc.Equals, state.Alive)
+}
+
 func (s *ModelSuite) TestDestroyControllerNonEmptyModelFails(c *gc.C) {
 	s.assertDestroyControllerNonEmptyModelFails(c, nil)
 }
@@ -6,6 +6,8 @@ package state
 import (
 	stderrors "errors"
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -555,6 +557,33 @@ func watchRelationScope(
 	return newRelationScopeWatcher(st, scope, ignore)
 }
 
+// counterpartUnitsInScope returns the names of the counterpart units
+// in the unit's relation scope that are not preparing to leave it; that
+// is, the units reported by WatchScope.
+func (ru *RelationUnit) counterpartUnitsInScope() ([]string, error) {
+	relationScopes, closer := ru.st.db().GetCollection(relationScopesC)
+	defer closer()
+
+	prefix := ru._key(string(counterpartRole(ru.endpoint.Role)), "")
+	sel := bson.D{
+		{"key", bson.D{{"$regex", "^" + regexp.QuoteMeta(prefix)}}},
+		{"departing", bson.D{{"$ne", true}}},
+	}
+	var docs []relationScopeDoc
+	if err := relationScopes.Find(sel).All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var unitNames []string
+	for _, doc := range docs {
+		// Peers share the unit's role, so skip the unit itself.
+		if unitName := doc.unitName(); unitName != ru.unitName {
+			unitNames = append(unitNames, unitName)
+		}
+	}
+	sort.Strings(unitNames)
+	return unitNames, nil
+}
+
 // Settings returns a Settings which allows access to the unit's settings
 // within the relation.
 func (ru *RelationUnit) Settings() (*Settings, error) {
@@ -1697,3 +1697,72 @@ func changeSettings(c *gc.C, ru *state.RelationUnit) {
 	_, err = node.Write()
 	c.Assert(err, jc.ErrorIsNil)
 }
+
+func (s *RelationUnitSuite) TestUnitRelationDataProReq(c *gc.C) {
+	prr := newProReqRelation(c, &s.ConnSuite, charm.ScopeGlobal)
+	err := prr.pru0.EnterScope(map[string]interface{}{"host": "mysql-0"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = prr.pru1.EnterScope(map[string]interface{}{"host": "mysql-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = prr.rru0.EnterScope(map[string]interface{}{"user": "wp-0"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = prr.rru1.EnterScope(map[string]interface{}{"user": "wp-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = prr.rru1.PrepareLeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	updateAppSettings(c, s.State, prr.rel, prr.rapp, "wordpress/0", map[string]interface{}{"db": "wordpress"})
+	updateAppSettings(c, s.State, prr.rel, prr.papp, "mysql/0", map[string]interface{}{"port": "3306"})
+
+	data, err := prr.pu0.RelationData()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, []state.EndpointRelationData{{
+		RelationId:           prr.rel.Id(),
+		Endpoint:             "server",
+		RelatedEndpoint:      "db",
+		ApplicationData:      map[string]interface{}{"db": "wordpress"},
+		LocalApplicationData: map[string]interface{}{"port": "3306"},
+		UnitRelationData: map[string]state.RelationData{
+			"mysql/0": {
+				InScope:  true,
+				UnitData: map[string]interface{}{"host": "mysql-0"},
+			},
+			"wordpress/0": {
+				InScope:  true,
+				UnitData: map[string]interface{}{"user": "wp-0"},
+			},
+		},
+	}})
+}
+
+func (s *RelationUnitSuite) TestUnitRelationDataPeer(c *gc.C) {
+	pr := newPeerRelation(c, s.State)
+	err := pr.ru0.EnterScope(map[string]interface{}{"id": "0"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = pr.ru1.EnterScope(map[string]interface{}{"id": "1"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	data, err := pr.u1.RelationData()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, gc.HasLen, 1)
+	c.Check(data[0].Endpoint, gc.Equals, "ring")
+	c.Check(data[0].RelatedEndpoint, gc.Equals, "ring")
+	c.Check(data[0].LocalApplicationData, gc.IsNil)
+	c.Check(data[0].UnitRelationData, jc.DeepEquals, map[string]state.RelationData{
+		"riak/0": {InScope: true, UnitData: map[string]interface{}{"id": "0"}},
+		"riak/1": {InScope: true, UnitData: map[string]interface{}{"id": "1"}},
+	})
+}
+
+func (s *RelationUnitSuite) TestUnitRelationDataContainerScope(c *gc.C) {
+	prr := newProReqRelation(c, &s.ConnSuite, charm.ScopeContainer)
+	prr.allEnterScope(c)
+
+	// Only the units in the same container are related.
+	data, err := prr.pu0.RelationData()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, gc.HasLen, 1)
+	c.Check(data[0].UnitRelationData, jc.DeepEquals, map[string]state.RelationData{
+		"mysql/0":   {InScope: true, UnitData: map[string]interface{}{}},
+		"logging/0": {InScope: true, UnitData: map[string]interface{}{}},
+	})
+}
@@ -472,11 +472,11 @@ func (s *SpacesDiscoverySuite) TestReloadSubnetsIgnoredWithFAN(c *gc.C) {
 	}
 	s.usedEnviron = &s.environ
 
-	// This is just a test configuration. This configuration may be
-	// considered invalid in the future. Here we show that this
-	// configuration is ignored.
+	// The fan underlay covers none of the subnets, so no overlay
+	// subnets are added. Here we show that the ignored subnets stay
+	// ignored with a fan configured.
 	err := s.Model.UpdateModelConfig(
-		map[string]interface{}{"fan-config": "fe80:dead:beef::/48=fe80:dead:beef::/24"}, nil)
+		map[string]interface{}{"fan-config": "172.16.0.0/16=253.0.0.0/8"}, nil)
 	c.Assert(err, jc.ErrorIsNil)
 
 	err = s.State.ReloadSpaces(s.usedEnviron)
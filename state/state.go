@@ -1181,6 +1181,7 @@ type AddApplicationArgs struct {
 	Storage           map[string]StorageConstraints
 	Devices           map[string]DeviceConstraints
 	AttachStorage     []names.StorageTag
+	StorageSnapshots  map[string]string
 	EndpointBindings  map[string]string
 	ApplicationConfig *application.Config
 	CharmConfig       charm.Settings
@@ -1424,9 +1425,10 @@ func (st *State) AddApplication(args AddApplicationArgs) (_ *Application, err er
 		// Collect unit-adding operations.
 		for x := 0; x < args.NumUnits; x++ {
 			unitName, unitOps, err := app.addApplicationUnitOps(applicationAddUnitOpsArgs{
-				cons:          args.Constraints,
-				storageCons:   args.Storage,
-				attachStorage: args.AttachStorage,
+				cons:             args.Constraints,
+				storageCons:      args.Storage,
+				attachStorage:    args.AttachStorage,
+				storageSnapshots: args.StorageSnapshots,
 			})
 			if err != nil {
 				return nil, errors.Trace(err)
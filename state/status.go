@@ -117,6 +117,17 @@ func (m *ModelStatus) Application(appName string, unitNames []string) (status.St
 	return caasApplicationDisplayStatus(appStatus, operatorStatusDoc.asStatusInfo(), expectWorkload), nil
 }
 
+// ApplicationStatusDerived reports whether the status of the application
+// is derived from the status of its units, because the leader has never
+// set an application status.
+func (m *ModelStatus) ApplicationStatusDerived(appName string) (bool, error) {
+	doc, err := m.getDoc(applicationGlobalKey(appName), "application")
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return doc.NeverSet, nil
+}
+
 // MachineAgent returns the status of the machine agent.
 func (m *ModelStatus) MachineAgent(machineID string) (status.StatusInfo, error) {
 	return m.getStatus(machineGlobalKey(machineID), "machine")
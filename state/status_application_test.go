@@ -36,6 +36,22 @@ func (s *ApplicationStatusSuite) checkInitialStatus(c *gc.C) {
 	checkInitialWorkloadStatus(c, statusInfo)
 }
 
+func (s *ApplicationStatusSuite) TestStatusDerived(c *gc.C) {
+	derived, err := s.application.StatusDerived()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(derived, jc.IsTrue)
+
+	now := testing.ZeroTime()
+	err = s.application.SetStatus(status.StatusInfo{
+		Status: status.Active,
+		Since:  &now,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	derived, err = s.application.StatusDerived()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(derived, jc.IsFalse)
+}
+
 func (s *ApplicationStatusSuite) TestSetUnknownStatus(c *gc.C) {
 	now := testing.ZeroTime()
 	sInfo := status.StatusInfo{
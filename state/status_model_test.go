@@ -185,6 +185,26 @@ func (s *ModelStatusSuite) TestMachineStatus(c *gc.C) {
 	c.Assert(msInstance, jc.DeepEquals, mInstance)
 }
 
+func (s *ModelStatusSuite) TestApplicationStatusDerived(c *gc.C) {
+	app := s.factory.MakeApplication(c, nil)
+
+	ms, err := s.model.LoadModelStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	derived, err := ms.ApplicationStatusDerived(app.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(derived, jc.IsTrue)
+
+	now := testing.ZeroTime()
+	err = app.SetStatus(status.StatusInfo{Status: status.Active, Since: &now})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ms, err = s.model.LoadModelStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	derived, err = ms.ApplicationStatusDerived(app.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(derived, jc.IsFalse)
+}
+
 func (s *ModelStatusSuite) TestUnitStatus(c *gc.C) {
 	unit := s.factory.MakeUnit(c, nil)
 
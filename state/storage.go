@@ -70,6 +70,11 @@ type StorageAttachment interface {
 
 	// Life reports whether the storage attachment is Alive, Dying or Dead.
 	Life() Life
+
+	// Migration returns the status of the migration of the storage
+	// instance that the unit is taking part in, or the empty string
+	// if the storage instance is not being migrated.
+	Migration() StorageMigrationStatus
 }
 
 // StorageKind defines the type of a store: whether it is a block device
@@ -213,6 +218,10 @@ type storageInstanceDoc struct {
 	StorageName     string                     `bson:"storagename"`
 	AttachmentCount int                        `bson:"attachmentcount"`
 	Constraints     storageInstanceConstraints `bson:"constraints"`
+
+	// SourceSnapshot, if non-empty, is the ID of the storage snapshot
+	// that the storage instance's volume is to be restored from.
+	SourceSnapshot string `bson:"source-snapshot,omitempty"`
 }
 
 // storageInstanceConstraints contains a subset of StorageConstraints,
@@ -238,6 +247,10 @@ func (s *storageAttachment) Life() Life {
 	return s.doc.Life
 }
 
+func (s *storageAttachment) Migration() StorageMigrationStatus {
+	return s.doc.Migration
+}
+
 // storageAttachmentDoc describes a unit's attachment to a charm storage
 // instance.
 type storageAttachmentDoc struct {
@@ -247,6 +260,12 @@ type storageAttachmentDoc struct {
 	Unit            string `bson:"unitid"`
 	StorageInstance string `bson:"storageid"`
 	Life            Life   `bson:"life"`
+
+	// Migration, if non-empty, is the status of the migration of the
+	// storage instance that the unit's agent is taking part in. It is
+	// recorded here so that the unit's storage attachment watcher is
+	// notified as the migration progresses.
+	Migration StorageMigrationStatus `bson:"migration,omitempty"`
 }
 
 // newStorageInstanceId returns a unique storage instance name. The name
@@ -745,6 +764,10 @@ type machineAssignable interface {
 // will be correlated with the charm storage metadata for validation
 // and supplementing.
 //
+// The supplied snapshots, if non-nil, map storage names to the IDs of
+// storage snapshots that the created storage instances are to be
+// restored from.
+//
 // maybeMachineAssignable may be nil, or an machineAssignable which
 // describes the entity's machine assignment. If the entity is assigned
 // to a machine, then machine storage will be created.
@@ -753,6 +776,7 @@ func createStorageOps(
 	entityTag names.Tag,
 	charmMeta *charm.Meta,
 	cons map[string]StorageConstraints,
+	snapshots map[string]string,
 	series string,
 	maybeMachineAssignable machineAssignable,
 ) (ops []txn.Op, storageTags map[string][]names.StorageTag, numStorageAttachments int, err error) {
@@ -835,6 +859,7 @@ func createStorageOps(
 					Pool: cons.Pool,
 					Size: cons.Size,
 				},
+				SourceSnapshot: snapshots[t.storageName],
 			}
 			var hostStorageOps []txn.Op
 			if unitTag, ok := entityTag.(names.UnitTag); ok {
@@ -2183,6 +2208,7 @@ func (sb *storageBackend) addUnitStorageOps(
 		u.Tag(),
 		charmMeta,
 		map[string]StorageConstraints{storageName: cons},
+		nil,
 		u.Series(),
 		u,
 	)
@@ -0,0 +1,666 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// StorageMigrationStatus describes the state of a storage migration.
+type StorageMigrationStatus string
+
+const (
+	// StorageMigrationPending indicates that the migration has been
+	// requested, and the units using the storage have not yet stopped
+	// using it; each unit runs its storage-detaching hook before the
+	// data is copied.
+	StorageMigrationPending StorageMigrationStatus = "pending"
+
+	// StorageMigrationCopying indicates that the storage is no longer
+	// in use, and the data is being copied to the target volume.
+	StorageMigrationCopying StorageMigrationStatus = "copying"
+
+	// StorageMigrationAttaching indicates that the data has been
+	// copied, and the storage instance is now backed by the target
+	// volume. The source volume remains attached until the units
+	// using the storage have run their storage-attached hooks against
+	// the target volume.
+	StorageMigrationAttaching StorageMigrationStatus = "attaching"
+
+	// StorageMigrationCompleted indicates that the migration is
+	// complete, and the source volume has been destroyed.
+	StorageMigrationCompleted StorageMigrationStatus = "completed"
+
+	// StorageMigrationError indicates that the migration failed.
+	// The storage instance remains backed by the source volume.
+	StorageMigrationError StorageMigrationStatus = "error"
+)
+
+// activeStorageMigrationStatuses holds the statuses of migrations
+// that have neither completed nor failed.
+var activeStorageMigrationStatuses = []StorageMigrationStatus{
+	StorageMigrationPending,
+	StorageMigrationCopying,
+	StorageMigrationAttaching,
+}
+
+// StorageMigration describes the migration of a storage instance from
+// the volume it is backed by to a new volume in another storage pool.
+// Completed migrations are retained as a record of the provenance of
+// the storage instance's data.
+type StorageMigration interface {
+	// Id returns the unique ID of the migration in the model.
+	Id() string
+
+	// StorageTag returns the tag of the storage instance
+	// being migrated.
+	StorageTag() names.StorageTag
+
+	// Machine returns the tag of the machine to which the source
+	// and target volumes are attached, and on which the data is
+	// copied.
+	Machine() names.MachineTag
+
+	// SourceVolume returns the tag of the volume being migrated from.
+	SourceVolume() names.VolumeTag
+
+	// SourcePool returns the name of the storage pool being
+	// migrated from.
+	SourcePool() string
+
+	// TargetVolume returns the tag of the volume being migrated to.
+	TargetVolume() names.VolumeTag
+
+	// TargetPool returns the name of the storage pool being
+	// migrated to.
+	TargetPool() string
+
+	// Status returns the status of the migration, and an
+	// accompanying message if the migration failed.
+	Status() (StorageMigrationStatus, string)
+
+	// Created returns the time at which the migration was requested.
+	Created() time.Time
+
+	// Completed returns the time at which the migration completed
+	// or failed, or the zero time if it is still in progress.
+	Completed() time.Time
+}
+
+type storageMigration struct {
+	doc storageMigrationDoc
+}
+
+// storageMigrationDoc records information about the migration of
+// a storage instance between storage pools.
+type storageMigrationDoc struct {
+	DocID          string                 `bson:"_id"`
+	Id             string                 `bson:"id"`
+	ModelUUID      string                 `bson:"model-uuid"`
+	StorageId      string                 `bson:"storageid"`
+	MachineId      string                 `bson:"machineid"`
+	SourceVolumeId string                 `bson:"source-volumeid"`
+	SourcePool     string                 `bson:"source-pool"`
+	TargetVolumeId string                 `bson:"target-volumeid"`
+	TargetPool     string                 `bson:"target-pool"`
+	Status         StorageMigrationStatus `bson:"status"`
+	Message        string                 `bson:"message,omitempty"`
+	Created        int64                  `bson:"created"`
+	Completed      int64                  `bson:"completed,omitempty"`
+}
+
+// Id is required to implement StorageMigration.
+func (m *storageMigration) Id() string {
+	return m.doc.Id
+}
+
+// StorageTag is required to implement StorageMigration.
+func (m *storageMigration) StorageTag() names.StorageTag {
+	return names.NewStorageTag(m.doc.StorageId)
+}
+
+// Machine is required to implement StorageMigration.
+func (m *storageMigration) Machine() names.MachineTag {
+	return names.NewMachineTag(m.doc.MachineId)
+}
+
+// SourceVolume is required to implement StorageMigration.
+func (m *storageMigration) SourceVolume() names.VolumeTag {
+	return names.NewVolumeTag(m.doc.SourceVolumeId)
+}
+
+// SourcePool is required to implement StorageMigration.
+func (m *storageMigration) SourcePool() string {
+	return m.doc.SourcePool
+}
+
+// TargetVolume is required to implement StorageMigration.
+func (m *storageMigration) TargetVolume() names.VolumeTag {
+	return names.NewVolumeTag(m.doc.TargetVolumeId)
+}
+
+// TargetPool is required to implement StorageMigration.
+func (m *storageMigration) TargetPool() string {
+	return m.doc.TargetPool
+}
+
+// Status is required to implement StorageMigration.
+func (m *storageMigration) Status() (StorageMigrationStatus, string) {
+	return m.doc.Status, m.doc.Message
+}
+
+// Created is required to implement StorageMigration.
+func (m *storageMigration) Created() time.Time {
+	return time.Unix(0, m.doc.Created).UTC()
+}
+
+// Completed is required to implement StorageMigration.
+func (m *storageMigration) Completed() time.Time {
+	if m.doc.Completed == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, m.doc.Completed).UTC()
+}
+
+// MigrateStorage records a request to migrate the block storage
+// instance with the specified tag to a new volume in the named pool.
+// The new volume is created and attached to the machine that the
+// storage instance's volume is attached to. The units using the
+// storage are asked to stop using it, by running their
+// storage-detaching hooks; once they have done so and both volumes
+// are attached, the machine's storage provisioner copies the data
+// across. The source volume is destroyed once the units have run
+// their storage-attached hooks against the target volume.
+func (sb *storageBackend) MigrateStorage(tag names.StorageTag, pool string) (_ StorageMigration, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot migrate %s to pool %q", names.ReadableString(tag), pool)
+
+	var doc storageMigrationDoc
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		s, err := sb.storageInstance(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.Life() != Alive {
+			return nil, errors.New("storage is not alive")
+		}
+		if s.Kind() != StorageKindBlock {
+			return nil, errors.NotSupportedf("migrating %s storage", s.Kind())
+		}
+		active, err := sb.storageMigrations(bson.D{
+			{"storageid", tag.Id()},
+			{"status", bson.D{{"$in", activeStorageMigrationStatuses}}},
+		})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(active) > 0 {
+			return nil, errors.Errorf("storage migration %q is in progress", active[0].Id())
+		}
+		v, err := sb.storageInstanceVolume(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		info, err := v.Info()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if info.Pool == pool {
+			return nil, errors.Errorf("storage is already in pool %q", pool)
+		}
+		attachments, err := sb.VolumeAttachments(v.VolumeTag())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(attachments) != 1 || attachments[0].Host().Kind() != names.MachineTagKind {
+			return nil, errors.New("volume must be attached to exactly one machine")
+		}
+		machineTag := attachments[0].Host().(names.MachineTag)
+		if _, err := attachments[0].Info(); err != nil {
+			return nil, errors.Annotate(err, "volume attachment")
+		}
+		machine, err := sb.machine(machineTag.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if machine.Life() != Alive {
+			return nil, errors.Errorf("%s is not alive", names.ReadableString(machineTag))
+		}
+
+		// Each unit using the storage must stop using it before
+		// the data is copied. If there are none, there is nothing
+		// to wait for.
+		storageAttachments, err := sb.StorageAttachments(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		status := StorageMigrationCopying
+		var storageAttachmentOps []txn.Op
+		for _, a := range storageAttachments {
+			if a.Life() != Alive {
+				return nil, errors.Errorf("storage is being detached from %s", names.ReadableString(a.Unit()))
+			}
+			status = StorageMigrationPending
+			storageAttachmentOps = append(storageAttachmentOps, txn.Op{
+				C:      storageAttachmentsC,
+				Id:     storageAttachmentId(a.Unit().Id(), tag.Id()),
+				Assert: isAliveDoc,
+				Update: bson.D{{"$set", bson.D{{"migration", StorageMigrationPending}}}},
+			})
+		}
+
+		volumeOps, targetTag, err := sb.addVolumeOps(VolumeParams{
+			Pool: pool,
+			Size: info.Size,
+		}, machineTag.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		id, err := newStorageMigrationId(sb.mb, machineTag.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		doc = storageMigrationDoc{
+			Id:             id,
+			StorageId:      tag.Id(),
+			MachineId:      machineTag.Id(),
+			SourceVolumeId: v.doc.Name,
+			SourcePool:     info.Pool,
+			TargetVolumeId: targetTag.Id(),
+			TargetPool:     pool,
+			Status:         status,
+			Created:        sb.mb.clock().Now().UnixNano(),
+		}
+		ops := []txn.Op{{
+			C:      storageInstancesC,
+			Id:     tag.Id(),
+			Assert: append(bson.D{{"attachmentcount", len(storageAttachments)}}, isAliveDoc...),
+		}, {
+			C:      volumesC,
+			Id:     v.doc.Name,
+			Assert: append(bson.D{{"storageid", tag.Id()}}, isAliveDoc...),
+		}, {
+			C:      machinesC,
+			Id:     machineTag.Id(),
+			Assert: isAliveDoc,
+			Update: bson.D{{"$addToSet", bson.D{{"volumes", targetTag.Id()}}}},
+		}}
+		ops = append(ops, storageAttachmentOps...)
+		ops = append(ops, volumeOps...)
+		ops = append(ops, createMachineVolumeAttachmentsOps(machineTag.Id(), []volumeAttachmentTemplate{{
+			tag: targetTag,
+		}})...)
+		ops = append(ops, txn.Op{
+			C:      storageMigrationsC,
+			Id:     id,
+			Assert: txn.DocMissing,
+			Insert: &doc,
+		})
+		return ops, nil
+	}
+	if err := sb.mb.db().Run(buildTxn); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &storageMigration{doc}, nil
+}
+
+// newStorageMigrationId returns a unique ID for a migration of storage
+// attached to the specified machine. The ID is prefixed with the machine
+// ID, so that the machine's storage provisioner can watch for them.
+func newStorageMigrationId(mb modelBackend, machineId string) (string, error) {
+	seq, err := sequence(mb, "storagemigration")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return fmt.Sprintf("%s/%d", machineId, seq), nil
+}
+
+// StorageMigration returns the StorageMigration with the specified ID.
+func (sb *storageBackend) StorageMigration(id string) (StorageMigration, error) {
+	return sb.storageMigration(id)
+}
+
+func (sb *storageBackend) storageMigration(id string) (*storageMigration, error) {
+	coll, cleanup := sb.mb.db().GetCollection(storageMigrationsC)
+	defer cleanup()
+
+	var doc storageMigrationDoc
+	err := coll.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("storage migration %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "getting storage migration %q", id)
+	}
+	return &storageMigration{doc}, nil
+}
+
+// AllStorageMigrations returns all storage migrations in the model.
+func (sb *storageBackend) AllStorageMigrations() ([]StorageMigration, error) {
+	return sb.storageMigrations(nil)
+}
+
+// StorageInstanceMigrations returns the migrations of the specified
+// storage instance, oldest first.
+func (sb *storageBackend) StorageInstanceMigrations(tag names.StorageTag) ([]StorageMigration, error) {
+	return sb.storageMigrations(bson.D{{"storageid", tag.Id()}})
+}
+
+func (sb *storageBackend) storageMigrations(query bson.D) ([]StorageMigration, error) {
+	coll, cleanup := sb.mb.db().GetCollection(storageMigrationsC)
+	defer cleanup()
+
+	var docs []storageMigrationDoc
+	if err := coll.Find(query).Sort("created").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "querying storage migrations")
+	}
+	migrations := make([]StorageMigration, len(docs))
+	for i, doc := range docs {
+		migrations[i] = &storageMigration{doc}
+	}
+	return migrations, nil
+}
+
+// migratingStorageAttachments returns the storage attachments of the
+// specified storage instance whose units are at the given stage of a
+// migration of the storage.
+func (sb *storageBackend) migratingStorageAttachments(tag names.StorageTag, status StorageMigrationStatus) ([]*storageAttachment, error) {
+	coll, closer := sb.mb.db().GetCollection(storageAttachmentsC)
+	defer closer()
+
+	var docs []storageAttachmentDoc
+	if err := coll.Find(bson.D{
+		{"storageid", tag.Id()},
+		{"migration", status},
+	}).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get storage attachments for storage %s", tag.Id())
+	}
+	attachments := make([]*storageAttachment, len(docs))
+	for i, doc := range docs {
+		attachments[i] = &storageAttachment{doc}
+	}
+	return attachments, nil
+}
+
+// setStorageAttachmentsMigrationOps returns operations to move the
+// specified storage attachments from one stage of a migration to
+// another. If to is empty, the attachments no longer take part in
+// the migration.
+func setStorageAttachmentsMigrationOps(attachments []*storageAttachment, from, to StorageMigrationStatus) []txn.Op {
+	update := bson.D{{"$set", bson.D{{"migration", to}}}}
+	if to == "" {
+		update = bson.D{{"$unset", bson.D{{"migration", nil}}}}
+	}
+	ops := make([]txn.Op, len(attachments))
+	for i, a := range attachments {
+		ops[i] = txn.Op{
+			C:      storageAttachmentsC,
+			Id:     storageAttachmentId(a.doc.Unit, a.doc.StorageInstance),
+			Assert: bson.D{{"migration", from}},
+			Update: update,
+		}
+	}
+	return ops
+}
+
+// activeStorageMigration returns the migration of the specified storage
+// instance that has the given status.
+func (sb *storageBackend) activeStorageMigration(tag names.StorageTag, status StorageMigrationStatus) (*storageMigration, error) {
+	migrations, err := sb.storageMigrations(bson.D{
+		{"storageid", tag.Id()},
+		{"status", status},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(migrations) == 0 {
+		return nil, errors.NotFoundf("%s migration of %s", status, names.ReadableString(tag))
+	}
+	return migrations[0].(*storageMigration), nil
+}
+
+// destroyMigratedVolumeOps returns operations to destroy the source
+// volume of a migration, once the storage instance is no longer backed
+// by it.
+func (sb *storageBackend) destroyMigratedVolumeOps(m *storageMigration) ([]txn.Op, error) {
+	source, err := getVolumeByTag(sb.mb, m.SourceVolume())
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if source.Life() != Alive {
+		return nil, nil
+	}
+	return destroyVolumeOps(sb, source, false, bson.D{{"storageid", bson.D{{"$exists", false}}}})
+}
+
+// AcknowledgeStorageMigration records that the specified unit has
+// carried out its part in the current stage of the migration of the
+// specified storage instance. Once every unit using the storage has
+// run its storage-detaching hook, the data may be copied; once every
+// unit has run its storage-attached hook against the target volume,
+// the migration is completed and the source volume destroyed.
+func (sb *storageBackend) AcknowledgeStorageMigration(storage names.StorageTag, unit names.UnitTag) (err error) {
+	defer errors.DeferredAnnotatef(
+		&err, "cannot acknowledge migration of %s for %s",
+		names.ReadableString(storage), names.ReadableString(unit),
+	)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		a, err := sb.storageAttachment(storage, unit)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		from := a.doc.Migration
+		var to StorageMigrationStatus
+		switch from {
+		case StorageMigrationPending:
+			to = StorageMigrationCopying
+		case StorageMigrationAttaching:
+			to = StorageMigrationCompleted
+		default:
+			// The unit has nothing to acknowledge.
+			return nil, jujutxn.ErrNoOperations
+		}
+		m, err := sb.activeStorageMigration(storage, from)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		others, err := sb.migratingStorageAttachments(storage, from)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var next StorageMigrationStatus
+		if to == StorageMigrationCopying {
+			next = to
+		}
+		ops := setStorageAttachmentsMigrationOps([]*storageAttachment{a}, from, next)
+		if len(others) > 1 {
+			// Other units have yet to acknowledge this
+			// stage of the migration.
+			return ops, nil
+		}
+		update := bson.D{{"status", to}}
+		if to == StorageMigrationCompleted {
+			update = append(update, bson.DocElem{"completed", sb.mb.clock().Now().UnixNano()})
+			sourceOps, err := sb.destroyMigratedVolumeOps(m)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, sourceOps...)
+		}
+		return append(ops, txn.Op{
+			C:      storageMigrationsC,
+			Id:     m.doc.Id,
+			Assert: bson.D{{"status", from}},
+			Update: bson.D{{"$set", update}},
+		}), nil
+	}
+	return sb.mb.db().Run(buildTxn)
+}
+
+// CompleteStorageMigration records that the data has been copied to the
+// target volume of a migration. The storage instance is reassigned to the
+// target volume. If units are using the storage, the source volume remains
+// attached until they have run their storage-attached hooks against the
+// target volume; otherwise, the source volume is destroyed immediately.
+func (sb *storageBackend) CompleteStorageMigration(id string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot complete storage migration %q", id)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		m, err := sb.storageMigration(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		switch m.doc.Status {
+		case StorageMigrationCopying:
+		case StorageMigrationAttaching, StorageMigrationCompleted:
+			if attempt > 0 {
+				return nil, jujutxn.ErrNoOperations
+			}
+			fallthrough
+		default:
+			return nil, errors.Errorf("migration is %s", m.doc.Status)
+		}
+		s, err := sb.storageInstance(m.StorageTag())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.Life() != Alive {
+			return nil, errors.New("storage is not alive")
+		}
+		target, err := getVolumeByTag(sb.mb, m.TargetVolume())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		targetInfo, err := target.Info()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		attachments, err := sb.migratingStorageAttachments(m.StorageTag(), StorageMigrationCopying)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		update := bson.D{{"status", StorageMigrationAttaching}}
+		if len(attachments) == 0 {
+			update = bson.D{
+				{"status", StorageMigrationCompleted},
+				{"completed", sb.mb.clock().Now().UnixNano()},
+			}
+		}
+		ops := []txn.Op{{
+			C:      storageMigrationsC,
+			Id:     id,
+			Assert: bson.D{{"status", StorageMigrationCopying}},
+			Update: bson.D{{"$set", update}},
+		}, {
+			C:      storageInstancesC,
+			Id:     m.doc.StorageId,
+			Assert: isAliveDoc,
+			Update: bson.D{{"$set", bson.D{{"constraints.pool", targetInfo.Pool}}}},
+		}, {
+			C:  volumesC,
+			Id: target.doc.Name,
+			Assert: append(bson.D{
+				{"info", bson.D{{"$exists", true}}},
+				{"storageid", bson.D{{"$exists", false}}},
+			}, isAliveDoc...),
+			Update: bson.D{{"$set", bson.D{{"storageid", m.doc.StorageId}}}},
+		}}
+		ops = append(ops, setStorageAttachmentsMigrationOps(
+			attachments, StorageMigrationCopying, StorageMigrationAttaching,
+		)...)
+
+		source, err := getVolumeByTag(sb.mb, m.SourceVolume())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(attachments) > 0 {
+			// The source volume is unassigned from the storage
+			// instance, but remains attached to the machine
+			// until the units have stopped using it.
+			return append(ops, txn.Op{
+				C:      volumesC,
+				Id:     source.doc.Name,
+				Assert: bson.D{{"storageid", m.doc.StorageId}},
+				Update: bson.D{{"$unset", bson.D{{"storageid", nil}}}},
+			}), nil
+		}
+		// The source volume is unassigned from the storage instance
+		// and destroyed in the same transaction; destroyVolumeOps
+		// asserts and updates the volume document, so we extend its
+		// first operation rather than adding another for the same
+		// document.
+		sourceOps, err := destroyVolumeOps(sb, source, false, bson.D{{"storageid", m.doc.StorageId}})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		sourceOps[0].Update = append(sourceOps[0].Update.(bson.D), bson.DocElem{
+			"$unset", bson.D{{"storageid", nil}},
+		})
+		return append(ops, sourceOps...), nil
+	}
+	return sb.mb.db().Run(buildTxn)
+}
+
+// SetStorageMigrationError records that the migration failed. The target
+// volume is destroyed, and the storage instance remains backed by the
+// source volume; the units using the storage run their storage-attached
+// hooks against it again.
+func (sb *storageBackend) SetStorageMigrationError(id, message string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set error for storage migration %q", id)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		m, err := sb.storageMigration(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		status := m.doc.Status
+		if status != StorageMigrationPending && status != StorageMigrationCopying {
+			return nil, errors.Errorf("migration is %s", status)
+		}
+		ops := []txn.Op{{
+			C:      storageMigrationsC,
+			Id:     id,
+			Assert: bson.D{{"status", status}},
+			Update: bson.D{{"$set", bson.D{
+				{"status", StorageMigrationError},
+				{"message", message},
+				{"completed", sb.mb.clock().Now().UnixNano()},
+			}}},
+		}}
+		for _, stage := range []StorageMigrationStatus{StorageMigrationPending, StorageMigrationCopying} {
+			attachments, err := sb.migratingStorageAttachments(m.StorageTag(), stage)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, setStorageAttachmentsMigrationOps(attachments, stage, "")...)
+		}
+		target, err := getVolumeByTag(sb.mb, m.TargetVolume())
+		if errors.IsNotFound(err) {
+			return ops, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if target.Life() != Alive {
+			return ops, nil
+		}
+		targetOps, err := destroyVolumeOps(sb, target, false, bson.D{{"storageid", bson.D{{"$exists", false}}}})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, targetOps...), nil
+	}
+	return sb.mb.db().Run(buildTxn)
+}
+
+// WatchMachineStorageMigrations returns a StringsWatcher that notifies
+// of changes to migrations of storage attached to the specified machine.
+func (sb *storageBackend) WatchMachineStorageMigrations(m names.MachineTag) StringsWatcher {
+	return sb.watchHostStorageEntities(m, storageMigrationsC)
+}
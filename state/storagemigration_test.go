@@ -0,0 +1,243 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+)
+
+type StorageMigrationSuite struct {
+	StorageStateSuiteBase
+}
+
+var _ = gc.Suite(&StorageMigrationSuite{})
+
+func (s *StorageMigrationSuite) setupAttachedVolume(c *gc.C) (*state.Machine, names.StorageTag) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machine := unitMachine(c, s.State, u)
+	err = machine.SetProvisioned("inst-id", "", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	volumeTag := s.storageInstanceVolume(c, storageTag).VolumeTag()
+	err = s.storageBackend.SetVolumeInfo(volumeTag, state.VolumeInfo{
+		Size: 1024, Pool: "loop-pool", VolumeId: "loop0",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetVolumeAttachmentInfo(
+		machine.MachineTag(), volumeTag,
+		state.VolumeAttachmentInfo{DeviceName: "loop0"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	return machine, storageTag
+}
+
+func (s *StorageMigrationSuite) provisionTargetVolume(c *gc.C, m state.StorageMigration) {
+	err := s.storageBackend.SetVolumeInfo(m.TargetVolume(), state.VolumeInfo{
+		Size: 1024, Pool: "persistent-block", VolumeId: "vol-123",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetVolumeAttachmentInfo(
+		m.Machine(), m.TargetVolume(),
+		state.VolumeAttachmentInfo{DeviceName: "sdc"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *StorageMigrationSuite) TestMigrateStorage(c *gc.C) {
+	machine, storageTag := s.setupAttachedVolume(c)
+	sourceTag := s.storageInstanceVolume(c, storageTag).VolumeTag()
+
+	m, err := s.storageBackend.MigrateStorage(storageTag, "persistent-block")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Id(), gc.Equals, "0/0")
+	c.Assert(m.StorageTag(), gc.Equals, storageTag)
+	c.Assert(m.Machine(), gc.Equals, machine.MachineTag())
+	c.Assert(m.SourceVolume(), gc.Equals, sourceTag)
+	c.Assert(m.SourcePool(), gc.Equals, "loop-pool")
+	c.Assert(m.TargetPool(), gc.Equals, "persistent-block")
+	c.Assert(m.Completed().IsZero(), jc.IsTrue)
+	status, _ := m.Status()
+	c.Assert(status, gc.Equals, state.StorageMigrationPending)
+
+	// The target volume is created unassigned, and
+	// attached to the source volume's machine.
+	target := s.volume(c, m.TargetVolume())
+	_, err = target.StorageInstance()
+	c.Assert(err, jc.Satisfies, errors.IsNotAssigned)
+	params, ok := target.Params()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(params.Pool, gc.Equals, "persistent-block")
+	c.Assert(params.Size, gc.Equals, uint64(1024))
+	s.volumeAttachment(c, machine.MachineTag(), m.TargetVolume())
+
+	_, err = s.storageBackend.MigrateStorage(storageTag, "persistent-block")
+	c.Assert(err, gc.ErrorMatches, `cannot migrate storage data/0 to pool "persistent-block": storage migration "0/0" is in progress`)
+}
+
+func (s *StorageMigrationSuite) storageAttachmentMigration(c *gc.C, storageTag names.StorageTag) state.StorageMigrationStatus {
+	a, err := s.storageBackend.StorageAttachment(storageTag, names.NewUnitTag("storage-block/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	return a.Migration()
+}
+
+func (s *StorageMigrationSuite) assertMigrationStatus(c *gc.C, id string, expect state.StorageMigrationStatus) state.StorageMigration {
+	m, err := s.storageBackend.StorageMigration(id)
+	c.Assert(err, jc.ErrorIsNil)
+	status, _ := m.Status()
+	c.Assert(status, gc.Equals, expect)
+	return m
+}
+
+func (s *StorageMigrationSuite) TestMigrateStorageWaitsForUnit(c *gc.C) {
+	_, storageTag := s.setupAttachedVolume(c)
+	m, err := s.storageBackend.MigrateStorage(storageTag, "persistent-block")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.storageAttachmentMigration(c, storageTag), gc.Equals, state.StorageMigrationPending)
+
+	// The data must not be copied until the unit
+	// has stopped using the storage.
+	s.provisionTargetVolume(c, m)
+	err = s.storageBackend.CompleteStorageMigration(m.Id())
+	c.Assert(err, gc.ErrorMatches, `cannot complete storage migration "0/0": migration is pending`)
+
+	err = s.storageBackend.AcknowledgeStorageMigration(storageTag, names.NewUnitTag("storage-block/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMigrationStatus(c, m.Id(), state.StorageMigrationCopying)
+	c.Assert(s.storageAttachmentMigration(c, storageTag), gc.Equals, state.StorageMigrationCopying)
+
+	// Acknowledging again is a no-op.
+	err = s.storageBackend.AcknowledgeStorageMigration(storageTag, names.NewUnitTag("storage-block/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMigrationStatus(c, m.Id(), state.StorageMigrationCopying)
+}
+
+func (s *StorageMigrationSuite) TestCompleteStorageMigration(c *gc.C) {
+	machine, storageTag := s.setupAttachedVolume(c)
+	unitTag := names.NewUnitTag("storage-block/0")
+	m, err := s.storageBackend.MigrateStorage(storageTag, "persistent-block")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.AcknowledgeStorageMigration(storageTag, unitTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.CompleteStorageMigration(m.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+
+	s.provisionTargetVolume(c, m)
+	err = s.storageBackend.CompleteStorageMigration(m.Id())
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.storageInstanceVolume(c, storageTag).VolumeTag(), gc.Equals, m.TargetVolume())
+	si, err := s.storageBackend.StorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(si.Pool(), gc.Equals, "persistent-block")
+
+	// The source volume remains attached until the unit has
+	// run its storage-attached hook against the target volume.
+	m = s.assertMigrationStatus(c, m.Id(), state.StorageMigrationAttaching)
+	c.Assert(m.Completed().IsZero(), jc.IsTrue)
+	c.Assert(s.storageAttachmentMigration(c, storageTag), gc.Equals, state.StorageMigrationAttaching)
+	source := s.volume(c, m.SourceVolume())
+	c.Assert(source.Life(), gc.Equals, state.Alive)
+	_, err = source.StorageInstance()
+	c.Assert(err, jc.Satisfies, errors.IsNotAssigned)
+	sourceAttachment := s.volumeAttachment(c, machine.MachineTag(), m.SourceVolume())
+	c.Assert(sourceAttachment.Life(), gc.Equals, state.Alive)
+
+	err = s.storageBackend.SetStorageMigrationError(m.Id(), "oops")
+	c.Assert(err, gc.ErrorMatches, `cannot set error for storage migration "0/0": migration is attaching`)
+
+	err = s.storageBackend.AcknowledgeStorageMigration(storageTag, unitTag)
+	c.Assert(err, jc.ErrorIsNil)
+	m = s.assertMigrationStatus(c, m.Id(), state.StorageMigrationCompleted)
+	c.Assert(m.Completed().IsZero(), jc.IsFalse)
+	c.Assert(s.storageAttachmentMigration(c, storageTag), gc.Equals, state.StorageMigrationStatus(""))
+
+	source = s.volume(c, m.SourceVolume())
+	c.Assert(source.Life(), gc.Equals, state.Dying)
+	sourceAttachment = s.volumeAttachment(c, machine.MachineTag(), m.SourceVolume())
+	c.Assert(sourceAttachment.Life(), gc.Equals, state.Dying)
+
+	migrations, err := s.storageBackend.StorageInstanceMigrations(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(migrations, gc.HasLen, 1)
+	c.Assert(migrations[0].Id(), gc.Equals, m.Id())
+
+	err = s.storageBackend.SetStorageMigrationError(m.Id(), "oops")
+	c.Assert(err, gc.ErrorMatches, `cannot set error for storage migration "0/0": migration is completed`)
+}
+
+func (s *StorageMigrationSuite) TestSetStorageMigrationError(c *gc.C) {
+	_, storageTag := s.setupAttachedVolume(c)
+	sourceTag := s.storageInstanceVolume(c, storageTag).VolumeTag()
+	m, err := s.storageBackend.MigrateStorage(storageTag, "persistent-block")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.AcknowledgeStorageMigration(storageTag, names.NewUnitTag("storage-block/0"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.SetStorageMigrationError(m.Id(), "copying data: oops")
+	c.Assert(err, jc.ErrorIsNil)
+
+	m, err = s.storageBackend.StorageMigration(m.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	status, message := m.Status()
+	c.Assert(status, gc.Equals, state.StorageMigrationError)
+	c.Assert(message, gc.Equals, "copying data: oops")
+
+	// The storage remains backed by the source volume,
+	// and the target volume is destroyed.
+	c.Assert(s.storageInstanceVolume(c, storageTag).VolumeTag(), gc.Equals, sourceTag)
+	c.Assert(s.volume(c, m.TargetVolume()).Life(), gc.Equals, state.Dying)
+	c.Assert(s.storageAttachmentMigration(c, storageTag), gc.Equals, state.StorageMigrationStatus(""))
+
+	err = s.storageBackend.CompleteStorageMigration(m.Id())
+	c.Assert(err, gc.ErrorMatches, `cannot complete storage migration "0/0": migration is error`)
+}
+
+func (s *StorageMigrationSuite) TestMigrateStorageSamePool(c *gc.C) {
+	_, storageTag := s.setupAttachedVolume(c)
+	_, err := s.storageBackend.MigrateStorage(storageTag, "loop-pool")
+	c.Assert(err, gc.ErrorMatches, `cannot migrate storage data/0 to pool "loop-pool": storage is already in pool "loop-pool"`)
+}
+
+func (s *StorageMigrationSuite) TestMigrateStorageFilesystem(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "filesystem", "rootfs")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.storageBackend.MigrateStorage(storageTag, "persistent-block")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *StorageMigrationSuite) TestMigrateStorageVolumeNotProvisioned(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.storageBackend.MigrateStorage(storageTag, "persistent-block")
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+}
+
+func (s *StorageMigrationSuite) TestWatchMachineStorageMigrations(c *gc.C) {
+	machine, storageTag := s.setupAttachedVolume(c)
+	s.WaitForModelWatchersIdle(c, s.Model.UUID())
+
+	w := s.storageBackend.WatchMachineStorageMigrations(machine.MachineTag())
+	defer testing.AssertStop(c, w)
+	wc := testing.NewStringsWatcherC(c, s.st, w)
+	wc.AssertChangeInSingleEvent() // initial
+	wc.AssertNoChange()
+
+	m, err := s.storageBackend.MigrateStorage(storageTag, "persistent-block")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent(m.Id())
+	wc.AssertNoChange()
+}
@@ -0,0 +1,224 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// ResizeStorageInstance records a request to grow the volume or
+// filesystem assigned to the specified storage instance to the
+// given size in MiB. The storage provisioner responsible for the
+// volume or filesystem will carry out the resize, and record the
+// new size once complete.
+//
+// Storage may only grow; requests for a size that is not larger
+// than the current size are rejected. Filesystems that are backed
+// by a volume are resized by resizing the volume.
+func (sb *storageBackend) ResizeStorageInstance(tag names.StorageTag, size uint64) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot resize %s", names.ReadableString(tag))
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		s, err := sb.storageInstance(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.Life() != Alive {
+			return nil, errors.New("storage is not alive")
+		}
+		switch s.Kind() {
+		case StorageKindBlock:
+			v, err := sb.storageInstanceVolume(tag)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return resizeVolumeOps(v, size)
+		case StorageKindFilesystem:
+			f, err := sb.storageInstanceFilesystem(tag)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if volumeTag, err := f.Volume(); err == nil {
+				v, err := getVolumeByTag(sb.mb, volumeTag)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				return resizeVolumeOps(v, size)
+			} else if errors.Cause(err) != ErrNoBackingVolume {
+				return nil, errors.Trace(err)
+			}
+			return resizeFilesystemOps(f, size)
+		}
+		return nil, errors.NotSupportedf("resizing %s storage", s.Kind())
+	}
+	return sb.mb.db().Run(buildTxn)
+}
+
+func resizeVolumeOps(v *volume, size uint64) ([]txn.Op, error) {
+	if v.Life() != Alive {
+		return nil, errors.Errorf("%s is not alive", names.ReadableString(v.Tag()))
+	}
+	info, err := v.Info()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := validateResize(info.Size, size); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if requested, ok := v.RequestedSize(); ok && requested == size {
+		return nil, jujutxn.ErrNoOperations
+	}
+	return []txn.Op{{
+		C:  volumesC,
+		Id: v.doc.Name,
+		Assert: append(bson.D{
+			{"info.size", info.Size},
+		}, isAliveDoc...),
+		Update: bson.D{{"$set", bson.D{{"requested-size", size}}}},
+	}}, nil
+}
+
+func resizeFilesystemOps(f *filesystem, size uint64) ([]txn.Op, error) {
+	if f.Life() != Alive {
+		return nil, errors.Errorf("%s is not alive", names.ReadableString(f.Tag()))
+	}
+	info, err := f.Info()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := validateResize(info.Size, size); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if requested, ok := f.RequestedSize(); ok && requested == size {
+		return nil, jujutxn.ErrNoOperations
+	}
+	return []txn.Op{{
+		C:  filesystemsC,
+		Id: f.doc.FilesystemId,
+		Assert: append(bson.D{
+			{"info.size", info.Size},
+		}, isAliveDoc...),
+		Update: bson.D{{"$set", bson.D{{"requested-size", size}}}},
+	}}, nil
+}
+
+func validateResize(current, requested uint64) error {
+	if requested <= current {
+		return errors.NewNotValid(nil, fmt.Sprintf(
+			"new size %dMiB must be larger than current size %dMiB",
+			requested, current,
+		))
+	}
+	return nil
+}
+
+// growVolumeFilesystemOps returns the operations required to record
+// the new size of a provisioned filesystem backed by the specified
+// volume, which has grown to the given size.
+func (sb *storageBackend) growVolumeFilesystemOps(tag names.VolumeTag, size uint64) ([]txn.Op, error) {
+	f, err := sb.volumeFilesystem(tag)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	info, err := f.Info()
+	if errors.IsNotProvisioned(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if info.Size >= size {
+		return nil, nil
+	}
+	return []txn.Op{{
+		C:      filesystemsC,
+		Id:     f.doc.FilesystemId,
+		Assert: bson.D{{"info.size", info.Size}},
+		Update: bson.D{{"$set", bson.D{{"info.size", size}}}},
+	}}, nil
+}
+
+// WatchModelVolumeResizes returns a StringsWatcher that notifies of
+// changes to any model-scoped volumes, including requests to resize
+// them.
+func (sb *storageBackend) WatchModelVolumeResizes() StringsWatcher {
+	return sb.watchModelStorageEntities(volumesC)
+}
+
+// WatchModelFilesystemResizes returns a StringsWatcher that notifies
+// of changes to any model-scoped filesystems, including requests to
+// resize them.
+func (sb *storageBackend) WatchModelFilesystemResizes() StringsWatcher {
+	return sb.watchModelStorageEntities(filesystemsC)
+}
+
+// WatchMachineVolumeResizes returns a StringsWatcher that notifies of
+// changes to any volumes scoped to the specified machine, including
+// requests to resize them.
+func (sb *storageBackend) WatchMachineVolumeResizes(m names.MachineTag) StringsWatcher {
+	return sb.watchHostStorageEntities(m, volumesC)
+}
+
+// WatchMachineFilesystemResizes returns a StringsWatcher that notifies
+// of changes to any filesystems scoped to the specified machine,
+// including requests to resize them.
+func (sb *storageBackend) WatchMachineFilesystemResizes(m names.MachineTag) StringsWatcher {
+	return sb.watchHostStorageEntities(m, filesystemsC)
+}
+
+// WatchUnitFilesystemResizes returns a StringsWatcher that notifies
+// of changes to any filesystems scoped to units of the specified
+// application, including requests to resize them.
+func (sb *storageBackend) WatchUnitFilesystemResizes(app names.ApplicationTag) StringsWatcher {
+	return sb.watchHostStorageEntities(app, filesystemsC)
+}
+
+func (sb *storageBackend) watchModelStorageEntities(collection string) StringsWatcher {
+	mb := sb.mb
+	return newCollectionWatcher(mb, colWCfg{
+		col: collection,
+		filter: func(id interface{}) bool {
+			k, err := mb.strictLocalID(id.(string))
+			if err != nil {
+				return false
+			}
+			return !strings.Contains(k, "/")
+		},
+	})
+}
+
+func (sb *storageBackend) watchHostStorageEntities(host names.Tag, collection string) StringsWatcher {
+	mb := sb.mb
+	prefix := host.Id() + "/"
+	return newCollectionWatcher(mb, colWCfg{
+		col: collection,
+		filter: func(id interface{}) bool {
+			k, err := mb.strictLocalID(id.(string))
+			if err != nil {
+				return false
+			}
+			return strings.HasPrefix(k, prefix)
+		},
+	})
+}
+
+// WatchVolume returns a NotifyWatcher that notifies of changes to
+// the specified volume.
+func (sb *storageBackend) WatchVolume(tag names.VolumeTag) NotifyWatcher {
+	return newEntityWatcher(sb.mb, volumesC, sb.mb.docID(tag.Id()))
+}
+
+// WatchFilesystem returns a NotifyWatcher that notifies of changes to
+// the specified filesystem.
+func (sb *storageBackend) WatchFilesystem(tag names.FilesystemTag) NotifyWatcher {
+	return newEntityWatcher(sb.mb, filesystemsC, sb.mb.docID(tag.Id()))
+}
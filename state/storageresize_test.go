@@ -0,0 +1,152 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+)
+
+type StorageResizeSuite struct {
+	StorageStateSuiteBase
+}
+
+var _ = gc.Suite(&StorageResizeSuite{})
+
+func (s *StorageResizeSuite) TestResizeStorageInstanceVolume(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	volumeTag := s.storageInstanceVolume(c, storageTag).VolumeTag()
+	err = s.storageBackend.SetVolumeInfo(volumeTag, state.VolumeInfo{Size: 1024, VolumeId: "vol-ume"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.ResizeStorageInstance(storageTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	size, ok := s.volume(c, volumeTag).RequestedSize()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(size, gc.Equals, uint64(2048))
+
+	// Requesting the same size again is a no-op.
+	err = s.storageBackend.ResizeStorageInstance(storageTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Once the provisioner records the new size, the
+	// request is cleared.
+	err = s.storageBackend.SetVolumeInfo(volumeTag, state.VolumeInfo{
+		Size: 2048, VolumeId: "vol-ume", Pool: "loop-pool",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = s.volume(c, volumeTag).RequestedSize()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *StorageResizeSuite) TestResizeStorageInstanceVolumeShrink(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	volumeTag := s.storageInstanceVolume(c, storageTag).VolumeTag()
+	err = s.storageBackend.SetVolumeInfo(volumeTag, state.VolumeInfo{Size: 1024, VolumeId: "vol-ume"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.ResizeStorageInstance(storageTag, 1024)
+	c.Assert(err, gc.ErrorMatches, `cannot resize storage data/0: new size 1024MiB must be larger than current size 1024MiB`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *StorageResizeSuite) TestResizeStorageInstanceVolumeNotProvisioned(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.ResizeStorageInstance(storageTag, 2048)
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+}
+
+func (s *StorageResizeSuite) TestResizeStorageInstanceFilesystem(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "filesystem", "rootfs")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machine := unitMachine(c, s.st, u)
+	err = machine.SetProvisioned("inst-id", "", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	filesystemTag := s.storageInstanceFilesystem(c, storageTag).FilesystemTag()
+	err = s.storageBackend.SetFilesystemInfo(filesystemTag, state.FilesystemInfo{Size: 1024, FilesystemId: "fs-id"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.ResizeStorageInstance(storageTag, 4096)
+	c.Assert(err, jc.ErrorIsNil)
+	size, ok := s.filesystem(c, filesystemTag).RequestedSize()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(size, gc.Equals, uint64(4096))
+
+	err = s.storageBackend.SetFilesystemInfo(filesystemTag, state.FilesystemInfo{
+		Size: 4096, FilesystemId: "fs-id", Pool: "rootfs",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = s.filesystem(c, filesystemTag).RequestedSize()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *StorageResizeSuite) TestResizeStorageInstanceBackingVolume(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "filesystem", "loop-pool")
+	s.provisionStorageVolume(c, u, storageTag)
+	filesystem := s.storageInstanceFilesystem(c, storageTag)
+	volumeTag, err := filesystem.Volume()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetVolumeInfo(volumeTag, state.VolumeInfo{
+		Size: 1024, VolumeId: "vol-123", Pool: "loop-pool",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetFilesystemInfo(filesystem.FilesystemTag(), state.FilesystemInfo{
+		Size: 1024, FilesystemId: "fs-id",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.ResizeStorageInstance(storageTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	size, ok := s.volume(c, volumeTag).RequestedSize()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(size, gc.Equals, uint64(2048))
+	_, ok = s.filesystem(c, filesystem.FilesystemTag()).RequestedSize()
+	c.Assert(ok, jc.IsFalse)
+
+	// Growing the volume grows the filesystem it backs.
+	err = s.storageBackend.SetVolumeInfo(volumeTag, state.VolumeInfo{
+		Size: 2048, VolumeId: "vol-123", Pool: "loop-pool",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	info, err := s.filesystem(c, filesystem.FilesystemTag()).Info()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Size, gc.Equals, uint64(2048))
+}
+
+func (s *StorageResizeSuite) TestWatchMachineVolumeResizes(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	volumeTag := s.storageInstanceVolume(c, storageTag).VolumeTag()
+	machine := unitMachine(c, s.st, u)
+	s.WaitForModelWatchersIdle(c, s.Model.UUID())
+
+	w := s.storageBackend.WatchMachineVolumeResizes(machine.MachineTag())
+	defer testing.AssertStop(c, w)
+	wc := testing.NewStringsWatcherC(c, s.st, w)
+	wc.AssertChangeInSingleEvent("0/0") // initial
+	wc.AssertNoChange()
+
+	err = s.storageBackend.SetVolumeInfo(volumeTag, state.VolumeInfo{Size: 1024, VolumeId: "vol-ume"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent("0/0")
+	wc.AssertNoChange()
+
+	err = s.storageBackend.ResizeStorageInstance(storageTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent("0/0")
+	wc.AssertNoChange()
+}
@@ -0,0 +1,399 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// StorageSnapshotStatus describes the state of a storage snapshot.
+type StorageSnapshotStatus string
+
+const (
+	// StorageSnapshotPending indicates that the snapshot has been
+	// requested, but not yet created by the storage provisioner.
+	StorageSnapshotPending StorageSnapshotStatus = "pending"
+
+	// StorageSnapshotAvailable indicates that the snapshot has been
+	// created, and may be used to provision new storage.
+	StorageSnapshotAvailable StorageSnapshotStatus = "available"
+
+	// StorageSnapshotError indicates that the storage provisioner
+	// failed to create the snapshot.
+	StorageSnapshotError StorageSnapshotStatus = "error"
+)
+
+// StorageSnapshot describes a point-in-time snapshot of the volume
+// backing a storage instance.
+type StorageSnapshot interface {
+	// Id returns the unique ID of the snapshot in the model.
+	Id() string
+
+	// StorageTag returns the tag of the storage instance that
+	// the snapshot was taken of.
+	StorageTag() names.StorageTag
+
+	// VolumeTag returns the tag of the volume that the snapshot
+	// was taken of.
+	VolumeTag() names.VolumeTag
+
+	// Pool returns the name of the storage pool of the volume
+	// that the snapshot was taken of.
+	Pool() string
+
+	// Size returns the size of the snapshot in MiB, if known.
+	Size() uint64
+
+	// SnapshotId returns the provider-supplied ID of the snapshot,
+	// or a NotProvisioned error if the snapshot has not yet been
+	// created.
+	SnapshotId() (string, error)
+
+	// Status returns the status of the snapshot, and an
+	// accompanying message if the snapshot could not be created.
+	Status() (StorageSnapshotStatus, string)
+
+	// Created returns the time at which the snapshot was requested.
+	Created() time.Time
+}
+
+type storageSnapshot struct {
+	doc storageSnapshotDoc
+}
+
+// storageSnapshotDoc records information about a snapshot of the
+// volume backing a storage instance.
+type storageSnapshotDoc struct {
+	DocID     string `bson:"_id"`
+	Id        string `bson:"id"`
+	ModelUUID string `bson:"model-uuid"`
+	StorageId string `bson:"storageid"`
+	VolumeId  string `bson:"volumeid"`
+	Pool      string `bson:"pool"`
+	Size      uint64 `bson:"size,omitempty"`
+
+	// SnapshotId is the provider-supplied ID of the snapshot. It is
+	// set by the storage provisioner once the snapshot is created.
+	SnapshotId string `bson:"snapshotid,omitempty"`
+
+	Status  StorageSnapshotStatus `bson:"status"`
+	Message string                `bson:"message,omitempty"`
+	Created int64                 `bson:"created"`
+}
+
+// Id is required to implement StorageSnapshot.
+func (s *storageSnapshot) Id() string {
+	return s.doc.Id
+}
+
+// StorageTag is required to implement StorageSnapshot.
+func (s *storageSnapshot) StorageTag() names.StorageTag {
+	return names.NewStorageTag(s.doc.StorageId)
+}
+
+// VolumeTag is required to implement StorageSnapshot.
+func (s *storageSnapshot) VolumeTag() names.VolumeTag {
+	return names.NewVolumeTag(s.doc.VolumeId)
+}
+
+// Pool is required to implement StorageSnapshot.
+func (s *storageSnapshot) Pool() string {
+	return s.doc.Pool
+}
+
+// Size is required to implement StorageSnapshot.
+func (s *storageSnapshot) Size() uint64 {
+	return s.doc.Size
+}
+
+// SnapshotId is required to implement StorageSnapshot.
+func (s *storageSnapshot) SnapshotId() (string, error) {
+	if s.doc.SnapshotId == "" {
+		return "", errors.NotProvisionedf("storage snapshot %q", s.doc.Id)
+	}
+	return s.doc.SnapshotId, nil
+}
+
+// Status is required to implement StorageSnapshot.
+func (s *storageSnapshot) Status() (StorageSnapshotStatus, string) {
+	return s.doc.Status, s.doc.Message
+}
+
+// Created is required to implement StorageSnapshot.
+func (s *storageSnapshot) Created() time.Time {
+	return time.Unix(0, s.doc.Created).UTC()
+}
+
+// newStorageSnapshotId returns a unique ID for a snapshot of the
+// specified volume. Snapshots of volumes that are scoped to a host
+// are given IDs with the same host prefix as the volume, so that
+// the host's storage provisioner can watch for them.
+func newStorageSnapshotId(mb modelBackend, volumeName string) (string, error) {
+	seq, err := sequence(mb, "storagesnapshot")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	id := fmt.Sprint(seq)
+	if i := strings.LastIndex(volumeName, "/"); i >= 0 {
+		id = volumeName[:i+1] + id
+	}
+	return id, nil
+}
+
+// CreateStorageSnapshot records a request to snapshot the volume
+// backing the specified storage instance. The storage provisioner
+// responsible for the volume will create the snapshot, and record
+// its provider ID once complete.
+func (sb *storageBackend) CreateStorageSnapshot(tag names.StorageTag) (_ StorageSnapshot, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot snapshot %s", names.ReadableString(tag))
+
+	s, err := sb.storageInstance(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if s.Life() != Alive {
+		return nil, errors.New("storage is not alive")
+	}
+	v, err := sb.storageInstanceVolume(tag)
+	if errors.IsNotFound(err) {
+		return nil, errors.NotSupportedf("snapshotting storage that is not backed by a volume")
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	info, err := v.Info()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	id, err := newStorageSnapshotId(sb.mb, v.doc.Name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	doc := storageSnapshotDoc{
+		Id:        id,
+		StorageId: tag.Id(),
+		VolumeId:  v.doc.Name,
+		Pool:      info.Pool,
+		Size:      info.Size,
+		Status:    StorageSnapshotPending,
+		Created:   sb.mb.clock().Now().UnixNano(),
+	}
+	ops := []txn.Op{{
+		C:      volumesC,
+		Id:     v.doc.Name,
+		Assert: append(bson.D{{"info", bson.D{{"$exists", true}}}}, isAliveDoc...),
+	}, {
+		C:      storageSnapshotsC,
+		Id:     id,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := sb.mb.db().RunTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			return nil, errors.Errorf("%s is not alive", names.ReadableString(v.Tag()))
+		}
+		return nil, errors.Trace(err)
+	}
+	return &storageSnapshot{doc}, nil
+}
+
+// StorageSnapshot returns the StorageSnapshot with the specified ID.
+func (sb *storageBackend) StorageSnapshot(id string) (StorageSnapshot, error) {
+	return sb.storageSnapshot(id)
+}
+
+func (sb *storageBackend) storageSnapshot(id string) (*storageSnapshot, error) {
+	coll, cleanup := sb.mb.db().GetCollection(storageSnapshotsC)
+	defer cleanup()
+
+	var doc storageSnapshotDoc
+	err := coll.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("storage snapshot %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "getting storage snapshot %q", id)
+	}
+	return &storageSnapshot{doc}, nil
+}
+
+// AllStorageSnapshots returns all storage snapshots in the model.
+func (sb *storageBackend) AllStorageSnapshots() ([]StorageSnapshot, error) {
+	return sb.storageSnapshots(nil)
+}
+
+// StorageInstanceSnapshots returns the snapshots taken of the
+// specified storage instance.
+func (sb *storageBackend) StorageInstanceSnapshots(tag names.StorageTag) ([]StorageSnapshot, error) {
+	return sb.storageSnapshots(bson.D{{"storageid", tag.Id()}})
+}
+
+// PendingSnapshotRestores returns the tags of the storage instances
+// that are to be restored from a snapshot, but whose volumes have not
+// yet been provisioned.
+func (sb *storageBackend) PendingSnapshotRestores() ([]names.StorageTag, error) {
+	storageInstances, err := sb.storageInstances(bson.D{
+		{"source-snapshot", bson.D{{"$exists", true}}},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var pending []names.StorageTag
+	for _, s := range storageInstances {
+		volume, err := sb.StorageInstanceVolume(s.StorageTag())
+		if errors.IsNotFound(err) {
+			pending = append(pending, s.StorageTag())
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if _, err := volume.Info(); errors.IsNotProvisioned(err) {
+			pending = append(pending, s.StorageTag())
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return pending, nil
+}
+
+func (sb *storageBackend) storageSnapshots(query bson.D) ([]StorageSnapshot, error) {
+	coll, cleanup := sb.mb.db().GetCollection(storageSnapshotsC)
+	defer cleanup()
+
+	var docs []storageSnapshotDoc
+	if err := coll.Find(query).Sort("created").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "querying storage snapshots")
+	}
+	snapshots := make([]StorageSnapshot, len(docs))
+	for i, doc := range docs {
+		snapshots[i] = &storageSnapshot{doc}
+	}
+	return snapshots, nil
+}
+
+// SetStorageSnapshotInfo records the provider-supplied ID and size of
+// a created snapshot, marking it as available.
+func (sb *storageBackend) SetStorageSnapshotInfo(id, snapshotId string, size uint64) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set info for storage snapshot %q", id)
+	if snapshotId == "" {
+		return errors.New("snapshot ID not set")
+	}
+	set := bson.D{
+		{"snapshotid", snapshotId},
+		{"status", StorageSnapshotAvailable},
+	}
+	if size > 0 {
+		set = append(set, bson.DocElem{"size", size})
+	}
+	return sb.updateStorageSnapshot(id, bson.D{
+		{"$set", set},
+		{"$unset", bson.D{{"message", nil}}},
+	})
+}
+
+// SetStorageSnapshotError records that the storage provisioner failed
+// to create the snapshot.
+func (sb *storageBackend) SetStorageSnapshotError(id, message string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set error for storage snapshot %q", id)
+	return sb.updateStorageSnapshot(id, bson.D{
+		{"$set", bson.D{
+			{"status", StorageSnapshotError},
+			{"message", message},
+		}},
+	})
+}
+
+func (sb *storageBackend) updateStorageSnapshot(id string, update bson.D) error {
+	ops := []txn.Op{{
+		C:      storageSnapshotsC,
+		Id:     id,
+		Assert: bson.D{{"snapshotid", bson.D{{"$exists", false}}}},
+		Update: update,
+	}}
+	if err := sb.mb.db().RunTransaction(ops); err == txn.ErrAborted {
+		if _, err := sb.storageSnapshot(id); err != nil {
+			return errors.Trace(err)
+		}
+		return errors.New("snapshot already created")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// WatchModelVolumeSnapshots returns a StringsWatcher that notifies of
+// changes to snapshots of model-scoped volumes.
+func (sb *storageBackend) WatchModelVolumeSnapshots() StringsWatcher {
+	return sb.watchModelStorageEntities(storageSnapshotsC)
+}
+
+// WatchMachineVolumeSnapshots returns a StringsWatcher that notifies
+// of changes to snapshots of volumes scoped to the specified machine.
+func (sb *storageBackend) WatchMachineVolumeSnapshots(m names.MachineTag) StringsWatcher {
+	return sb.watchHostStorageEntities(m, storageSnapshotsC)
+}
+
+// sourceSnapshotVolumeParams returns the volume parameters for
+// provisioning the volume of a storage instance from the snapshot
+// it is to be restored from.
+func (sb *storageBackend) sourceSnapshotVolumeParams(id string, params VolumeParams) (VolumeParams, error) {
+	snap, err := sb.storageSnapshot(id)
+	if err != nil {
+		return VolumeParams{}, errors.Trace(err)
+	}
+	snapshotId, err := snap.SnapshotId()
+	if err != nil {
+		return VolumeParams{}, errors.Errorf("storage snapshot %q is not available", id)
+	}
+	params.SnapshotId = snapshotId
+	params.Pool = snap.Pool()
+	if params.Size < snap.Size() {
+		params.Size = snap.Size()
+	}
+	return params, nil
+}
+
+// restoreStorageSnapshotOps validates that the snapshot with the
+// specified ID may be used to restore the named charm storage, and
+// returns the constraints for the storage instance to create, along
+// with an operation asserting that the snapshot remains available.
+func (sb *storageBackend) restoreStorageSnapshotOps(
+	charmMeta *charm.Meta,
+	storageName, id string,
+	cons StorageConstraints,
+) (StorageConstraints, txn.Op, error) {
+	charmStorage, ok := charmMeta.Storage[storageName]
+	if !ok {
+		return StorageConstraints{}, txn.Op{}, errors.NotFoundf("charm storage %q", storageName)
+	}
+	if charmStorage.Type != charm.StorageBlock || charmStorage.Shared {
+		return StorageConstraints{}, txn.Op{}, errors.NotSupportedf(
+			"restoring %s storage %q from a snapshot", charmStorage.Type, storageName,
+		)
+	}
+	snap, err := sb.storageSnapshot(id)
+	if err != nil {
+		return StorageConstraints{}, txn.Op{}, errors.Trace(err)
+	}
+	if snap.doc.SnapshotId == "" {
+		return StorageConstraints{}, txn.Op{}, errors.Errorf("storage snapshot %q is not available", id)
+	}
+	size := cons.Size
+	if size < snap.doc.Size {
+		size = snap.doc.Size
+	}
+	op := txn.Op{
+		C:      storageSnapshotsC,
+		Id:     id,
+		Assert: bson.D{{"snapshotid", bson.D{{"$exists", true}}}},
+	}
+	return StorageConstraints{Pool: snap.doc.Pool, Size: size, Count: 1}, op, nil
+}
@@ -0,0 +1,213 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+)
+
+type StorageSnapshotSuite struct {
+	StorageStateSuiteBase
+}
+
+var _ = gc.Suite(&StorageSnapshotSuite{})
+
+func (s *StorageSnapshotSuite) setupProvisionedVolume(c *gc.C) (*state.Application, *state.Unit, names.StorageTag) {
+	app, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	volumeTag := s.storageInstanceVolume(c, storageTag).VolumeTag()
+	err = s.storageBackend.SetVolumeInfo(volumeTag, state.VolumeInfo{
+		Size: 1024, Pool: "loop-pool", VolumeId: "vol-ume",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return app, u, storageTag
+}
+
+func (s *StorageSnapshotSuite) TestCreateStorageSnapshot(c *gc.C) {
+	_, _, storageTag := s.setupProvisionedVolume(c)
+	volumeTag := s.storageInstanceVolume(c, storageTag).VolumeTag()
+
+	snap, err := s.storageBackend.CreateStorageSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snap.Id(), gc.Equals, "0/0")
+	c.Assert(snap.StorageTag(), gc.Equals, storageTag)
+	c.Assert(snap.VolumeTag(), gc.Equals, volumeTag)
+	c.Assert(snap.Pool(), gc.Equals, "loop-pool")
+	c.Assert(snap.Size(), gc.Equals, uint64(1024))
+	status, message := snap.Status()
+	c.Assert(status, gc.Equals, state.StorageSnapshotPending)
+	c.Assert(message, gc.Equals, "")
+	_, err = snap.SnapshotId()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+
+	err = s.storageBackend.SetStorageSnapshotInfo(snap.Id(), "snap-1", 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	snap, err = s.storageBackend.StorageSnapshot(snap.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	snapshotId, err := snap.SnapshotId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshotId, gc.Equals, "snap-1")
+	c.Assert(snap.Size(), gc.Equals, uint64(2048))
+	status, _ = snap.Status()
+	c.Assert(status, gc.Equals, state.StorageSnapshotAvailable)
+
+	err = s.storageBackend.SetStorageSnapshotError(snap.Id(), "oops")
+	c.Assert(err, gc.ErrorMatches, `cannot set error for storage snapshot "0/0": snapshot already created`)
+
+	snapshots, err := s.storageBackend.StorageInstanceSnapshots(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshots, gc.HasLen, 1)
+	c.Assert(snapshots[0].Id(), gc.Equals, "0/0")
+}
+
+func (s *StorageSnapshotSuite) TestCreateStorageSnapshotError(c *gc.C) {
+	_, _, storageTag := s.setupProvisionedVolume(c)
+	snap, err := s.storageBackend.CreateStorageSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.SetStorageSnapshotError(snap.Id(), "oops")
+	c.Assert(err, jc.ErrorIsNil)
+	snap, err = s.storageBackend.StorageSnapshot(snap.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	status, message := snap.Status()
+	c.Assert(status, gc.Equals, state.StorageSnapshotError)
+	c.Assert(message, gc.Equals, "oops")
+}
+
+func (s *StorageSnapshotSuite) TestCreateStorageSnapshotVolumeNotProvisioned(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.storageBackend.CreateStorageSnapshot(storageTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+}
+
+func (s *StorageSnapshotSuite) TestCreateStorageSnapshotFilesystem(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "filesystem", "rootfs")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.storageBackend.CreateStorageSnapshot(storageTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *StorageSnapshotSuite) TestAddUnitFromStorageSnapshot(c *gc.C) {
+	app, _, storageTag := s.setupProvisionedVolume(c)
+	snap, err := s.storageBackend.CreateStorageSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetStorageSnapshotInfo(snap.Id(), "snap-1", 2048)
+	c.Assert(err, jc.ErrorIsNil)
+
+	u, err := app.AddUnit(state.AddUnitParams{
+		StorageSnapshots: map[string]string{"data": snap.Id()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	attachments, err := s.storageBackend.UnitStorageAttachments(u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 1)
+	restoredTag := attachments[0].StorageInstance()
+	c.Assert(restoredTag, gc.Equals, names.NewStorageTag("data/1"))
+
+	err = s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	params, ok := s.storageInstanceVolume(c, restoredTag).Params()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(params, jc.DeepEquals, state.VolumeParams{
+		Pool:       "loop-pool",
+		Size:       2048,
+		SnapshotId: "snap-1",
+	})
+}
+
+func (s *StorageSnapshotSuite) TestPendingSnapshotRestores(c *gc.C) {
+	app, _, storageTag := s.setupProvisionedVolume(c)
+	snap, err := s.storageBackend.CreateStorageSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetStorageSnapshotInfo(snap.Id(), "snap-1", 2048)
+	c.Assert(err, jc.ErrorIsNil)
+
+	pending, err := s.storageBackend.PendingSnapshotRestores()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.HasLen, 0)
+
+	u, err := app.AddUnit(state.AddUnitParams{
+		StorageSnapshots: map[string]string{"data": snap.Id()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	restoredTag := names.NewStorageTag("data/1")
+
+	// The restore is pending until the volume is provisioned.
+	pending, err = s.storageBackend.PendingSnapshotRestores()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, jc.DeepEquals, []names.StorageTag{restoredTag})
+
+	err = s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	pending, err = s.storageBackend.PendingSnapshotRestores()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, jc.DeepEquals, []names.StorageTag{restoredTag})
+
+	volumeTag := s.storageInstanceVolume(c, restoredTag).VolumeTag()
+	err = s.storageBackend.SetVolumeInfo(volumeTag, state.VolumeInfo{
+		Size: 2048, Pool: "loop-pool", VolumeId: "vol-restored",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	pending, err = s.storageBackend.PendingSnapshotRestores()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.HasLen, 0)
+}
+
+func (s *StorageSnapshotSuite) TestAddUnitFromStorageSnapshotNotAvailable(c *gc.C) {
+	app, _, storageTag := s.setupProvisionedVolume(c)
+	snap, err := s.storageBackend.CreateStorageSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = app.AddUnit(state.AddUnitParams{
+		StorageSnapshots: map[string]string{"data": snap.Id()},
+	})
+	c.Assert(err, gc.ErrorMatches, `.*restoring storage "data" from snapshot: storage snapshot "0/0" is not available`)
+}
+
+func (s *StorageSnapshotSuite) TestAddUnitFromStorageSnapshotUnknownStorage(c *gc.C) {
+	app, _, storageTag := s.setupProvisionedVolume(c)
+	snap, err := s.storageBackend.CreateStorageSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetStorageSnapshotInfo(snap.Id(), "snap-1", 1024)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = app.AddUnit(state.AddUnitParams{
+		StorageSnapshots: map[string]string{"unknown": snap.Id()},
+	})
+	c.Assert(err, gc.ErrorMatches, `.*restoring storage "unknown" from snapshot: charm storage "unknown" not found`)
+}
+
+func (s *StorageSnapshotSuite) TestWatchMachineVolumeSnapshots(c *gc.C) {
+	_, u, storageTag := s.setupProvisionedVolume(c)
+	machine := unitMachine(c, s.st, u)
+	s.WaitForModelWatchersIdle(c, s.Model.UUID())
+
+	w := s.storageBackend.WatchMachineVolumeSnapshots(machine.MachineTag())
+	defer testing.AssertStop(c, w)
+	wc := testing.NewStringsWatcherC(c, s.st, w)
+	wc.AssertChangeInSingleEvent() // initial
+	wc.AssertNoChange()
+
+	snap, err := s.storageBackend.CreateStorageSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent(snap.Id())
+	wc.AssertNoChange()
+
+	err = s.storageBackend.SetStorageSnapshotInfo(snap.Id(), "snap-1", 1024)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent(snap.Id())
+	wc.AssertNoChange()
+}
@@ -4,7 +4,9 @@
 package state
 
 import (
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
@@ -301,6 +303,110 @@ func (s *Subnet) updateSpaceName(spaceName string) (bool, error) {
 	return spaceNameChange && spaceName != "" && s.doc.FanLocalUnderlay == "", nil
 }
 
+// MoveToSpace moves the subnet to the space with the given ID.
+// Unlike Update, the subnet may be moved out of any space, not just
+// the default one. FAN overlay subnets cannot be moved, as their
+// space is always inherited from the underlay. Unless force is true,
+// the subnet cannot be moved out of a space that applications are
+// bound to, or that constraints refer to.
+func (s *Subnet) MoveToSpace(spaceID string, force bool) (err error) {
+	defer errors.DeferredAnnotatef(&err, "moving subnet %q to space %q", s.doc.CIDR, spaceID)
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt != 0 {
+			if err := s.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if s.doc.Life != Alive {
+			return nil, errors.Errorf("subnet is not alive")
+		}
+		if s.doc.FanLocalUnderlay != "" {
+			return nil, errors.Errorf("space of FAN subnet is always inherited from underlay")
+		}
+		sp, err := s.st.Space(spaceID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if sp.Life() != Alive {
+			return nil, errors.Errorf("space is not alive")
+		}
+		if s.doc.SpaceID == spaceID {
+			return nil, jujutxn.ErrNoOperations
+		}
+		if !force {
+			if err := s.st.checkSpaceNotInUse(s.doc.SpaceID); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		return []txn.Op{{
+			C:      spacesC,
+			Id:     sp.doc.DocId,
+			Assert: isAliveDoc,
+		}, {
+			C:      subnetsC,
+			Id:     s.doc.DocID,
+			Assert: bson.D{{"txn-revno", s.doc.TxnRevno}},
+			Update: bson.D{{"$set", bson.D{{"space-id", spaceID}}}},
+		}}, nil
+	}
+	if err := s.st.db().Run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	s.doc.SpaceID = spaceID
+	s.spaceID = spaceID
+	return nil
+}
+
+// checkSpaceNotInUse returns an error if any application endpoints
+// are bound to the space with the given ID, or any constraints
+// include or exclude it.
+func (st *State) checkSpaceNotInUse(spaceID string) error {
+	space, err := st.Space(spaceID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	bindingsColl, closer := st.db().GetCollection(endpointBindingsC)
+	defer closer()
+	var bindingsDocs []endpointBindingsDoc
+	if err := bindingsColl.Find(nil).All(&bindingsDocs); err != nil {
+		return errors.Annotate(err, "cannot get endpoint bindings")
+	}
+	var apps []string
+	for _, doc := range bindingsDocs {
+		for _, id := range doc.Bindings {
+			if id == spaceID {
+				apps = append(apps, strings.TrimPrefix(st.localID(doc.DocID), "a#"))
+				break
+			}
+		}
+	}
+	if len(apps) > 0 {
+		sort.Strings(apps)
+		return errors.Errorf(
+			"space %q is used by applications %s (use force to move the subnet anyway)",
+			space.Name(), strings.Join(apps, ", "),
+		)
+	}
+
+	consColl, closer := st.db().GetCollection(constraintsC)
+	defer closer()
+	n, err := consColl.Find(bson.D{{"spaces", bson.D{{"$in", []string{
+		space.Name(), "^" + space.Name(),
+	}}}}}).Count()
+	if err != nil {
+		return errors.Annotate(err, "cannot get constraints")
+	}
+	if n > 0 {
+		return errors.Errorf(
+			"space %q is used in constraints (use force to move the subnet anyway)",
+			space.Name(),
+		)
+	}
+	return nil
+}
+
 // SubnetUpdate adds new info to the subnet based on provided info.
 func (st *State) SubnetUpdate(args network.SubnetInfo) error {
 	s, err := st.SubnetByCIDR(args.CIDR)
@@ -11,8 +11,10 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v3"
 
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
 )
 
 type SubnetSuite struct {
@@ -390,3 +392,96 @@ func (s *SubnetSuite) TestUpdateNonEmpty(c *gc.C) {
 	c.Assert(subnet.VLANTag(), gc.Equals, expectedSubnetInfo.VLANTag)
 	c.Assert(subnet.AvailabilityZones(), gc.DeepEquals, expectedSubnetInfo.AvailabilityZones)
 }
+
+func (s *SubnetSuite) TestMoveToSpace(c *gc.C) {
+	subnet, err := s.State.AddSubnet(network.SubnetInfo{CIDR: "8.8.8.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("changeme", network.Id("2"), []string{subnet.ID()}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	expectedSpace, err := s.State.AddSpace("testme", network.Id("7"), []string{}, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = subnet.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	err = subnet.MoveToSpace(expectedSpace.Id(), false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.SpaceID(), gc.Equals, expectedSpace.Id())
+
+	err = subnet.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.SpaceID(), gc.Equals, expectedSpace.Id())
+	c.Assert(subnet.SpaceName(), gc.Equals, "testme")
+
+	// Moving to the same space is a no-op.
+	err = subnet.MoveToSpace(expectedSpace.Id(), false)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SubnetSuite) TestMoveToSpaceBoundSpace(c *gc.C) {
+	subnet, err := s.State.AddSubnet(network.SubnetInfo{CIDR: "8.8.8.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	bound, err := s.State.AddSpace("bound", network.Id("2"), []string{subnet.ID()}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	space, err := s.State.AddSpace("testme", network.Id("7"), []string{}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		EndpointBindings: map[string]string{"server": bound.Id()},
+	})
+
+	err = subnet.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	err = subnet.MoveToSpace(space.Id(), false)
+	c.Assert(err, gc.ErrorMatches, `moving subnet "8.8.8.0/24" to space ".*": space "bound" is used by applications mysql \(use force to move the subnet anyway\)`)
+	c.Assert(subnet.SpaceID(), gc.Equals, bound.Id())
+
+	err = subnet.MoveToSpace(space.Id(), true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.SpaceID(), gc.Equals, space.Id())
+}
+
+func (s *SubnetSuite) TestMoveToSpaceConstrainedSpace(c *gc.C) {
+	subnet, err := s.State.AddSubnet(network.SubnetInfo{CIDR: "8.8.8.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("constrained", network.Id("2"), []string{subnet.ID()}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	space, err := s.State.AddSpace("testme", network.Id("7"), []string{}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetModelConstraints(constraints.MustParse("spaces=^constrained"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = subnet.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	err = subnet.MoveToSpace(space.Id(), false)
+	c.Assert(err, gc.ErrorMatches, `moving subnet "8.8.8.0/24" to space ".*": space "constrained" is used in constraints \(use force to move the subnet anyway\)`)
+
+	err = subnet.MoveToSpace(space.Id(), true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.SpaceID(), gc.Equals, space.Id())
+}
+
+func (s *SubnetSuite) TestMoveToSpaceNotFound(c *gc.C) {
+	subnet, err := s.State.AddSubnet(network.SubnetInfo{CIDR: "8.8.8.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = subnet.MoveToSpace("42", false)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `moving subnet "8.8.8.0/24" to space "42": space id "42" not found`)
+}
+
+func (s *SubnetSuite) TestMoveToSpaceFanOverlay(c *gc.C) {
+	_, err := s.State.AddSubnet(network.SubnetInfo{CIDR: "10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	overlay, err := s.State.AddSubnet(network.SubnetInfo{
+		CIDR: "253.0.0.0/16",
+		FanInfo: &network.FanCIDRs{
+			FanLocalUnderlay: "10.0.0.0/24",
+			FanOverlay:       "253.0.0.0/8",
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	space, err := s.State.AddSpace("testme", network.Id("7"), []string{}, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = overlay.MoveToSpace(space.Id(), false)
+	c.Assert(err, gc.ErrorMatches, `moving subnet "253.0.0.0/16" to space ".*": space of FAN subnet is always inherited from underlay`)
+}
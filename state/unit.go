@@ -1077,6 +1077,131 @@ func (u *Unit) RelationsInScope() ([]*Relation, error) {
 	})
 }
 
+// EndpointRelationData holds the relation settings visible to a unit
+// in one of the relations it has joined.
+type EndpointRelationData struct {
+	// RelationId is the id of the relation.
+	RelationId int
+
+	// Endpoint is the name of the unit's endpoint in the relation.
+	Endpoint string
+
+	// RelatedEndpoint is the name of the endpoint at the other end
+	// of the relation.
+	RelatedEndpoint string
+
+	// CrossModel is true if the related application is in another model.
+	CrossModel bool
+
+	// ApplicationData holds the application settings written by the
+	// related application.
+	ApplicationData map[string]interface{}
+
+	// LocalApplicationData holds the application settings written by
+	// the unit's own application. It is not set for peer relations,
+	// in which ApplicationData holds them.
+	LocalApplicationData map[string]interface{}
+
+	// UnitRelationData holds the settings of the unit itself and of
+	// each related unit in scope, keyed by unit name.
+	UnitRelationData map[string]RelationData
+}
+
+// RelationData holds the settings of a single unit in a relation.
+type RelationData struct {
+	// InScope is true if the unit is in the relation scope.
+	InScope bool
+
+	// UnitData holds the unit's relation settings.
+	UnitData map[string]interface{}
+}
+
+// RelationData returns the relation settings of every relation the unit
+// has joined, including the settings of the unit itself, of the related
+// units in scope and of the related application.
+func (u *Unit) RelationData() ([]EndpointRelationData, error) {
+	relations, err := u.RelationsJoined()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]EndpointRelationData, len(relations))
+	for i, rel := range relations {
+		data, err := u.relationData(rel)
+		if err != nil {
+			return nil, errors.Annotatef(err, "relation %q", rel)
+		}
+		result[i] = data
+	}
+	return result, nil
+}
+
+func (u *Unit) relationData(rel *Relation) (EndpointRelationData, error) {
+	ep, err := rel.Endpoint(u.doc.Application)
+	if err != nil {
+		return EndpointRelationData{}, errors.Trace(err)
+	}
+	relatedEps, err := rel.RelatedEndpoints(u.doc.Application)
+	if err != nil {
+		return EndpointRelationData{}, errors.Trace(err)
+	}
+	// There is always exactly one related endpoint; peer relations
+	// relate an application to itself.
+	related := relatedEps[0]
+	_, crossModel, err := rel.RemoteApplication()
+	if err != nil {
+		return EndpointRelationData{}, errors.Trace(err)
+	}
+	ru, err := rel.Unit(u)
+	if err != nil {
+		return EndpointRelationData{}, errors.Trace(err)
+	}
+
+	appSettings, err := readSettings(u.st.db(), settingsC, relationApplicationSettingsKey(rel.Id(), related.ApplicationName))
+	if err != nil && !errors.IsNotFound(err) {
+		return EndpointRelationData{}, errors.Trace(err)
+	}
+	data := EndpointRelationData{
+		RelationId:       rel.Id(),
+		Endpoint:         ep.Name,
+		RelatedEndpoint:  related.Name,
+		CrossModel:       crossModel,
+		UnitRelationData: make(map[string]RelationData),
+	}
+	if appSettings != nil {
+		data.ApplicationData = appSettings.Map()
+	}
+	if related.ApplicationName != u.doc.Application {
+		localSettings, err := readSettings(u.st.db(), settingsC, relationApplicationSettingsKey(rel.Id(), u.doc.Application))
+		if err != nil && !errors.IsNotFound(err) {
+			return EndpointRelationData{}, errors.Trace(err)
+		}
+		if localSettings != nil {
+			data.LocalApplicationData = localSettings.Map()
+		}
+	}
+
+	unitNames, err := ru.counterpartUnitsInScope()
+	if err != nil {
+		return EndpointRelationData{}, errors.Trace(err)
+	}
+	unitNames = append(unitNames, u.doc.Name)
+	for _, unitName := range unitNames {
+		settings, err := ru.ReadSettings(unitName)
+		if errors.IsNotFound(errors.Cause(err)) {
+			// The unit has left scope since the query above.
+			data.UnitRelationData[unitName] = RelationData{}
+			continue
+		} else if err != nil {
+			return EndpointRelationData{}, errors.Trace(err)
+		}
+		data.UnitRelationData[unitName] = RelationData{
+			InScope:  true,
+			UnitData: settings,
+		}
+	}
+	return data, nil
+}
+
 type relationPredicate func(ru *RelationUnit) (bool, error)
 
 // relations implements RelationsJoined and RelationsInScope.
@@ -2540,6 +2665,14 @@ func storageParamsForStorageInstance(
 				Pool:    storage.doc.Constraints.Pool,
 				Size:    storage.doc.Constraints.Size,
 			}
+			if storage.doc.SourceSnapshot != "" {
+				volumeParams, err = sb.sourceSnapshotVolumeParams(
+					storage.doc.SourceSnapshot, volumeParams,
+				)
+				if err != nil {
+					return nil, errors.Annotatef(err, "restoring storage %q", storage.Tag().Id())
+				}
+			}
 			volumes = append(volumes, HostVolumeParams{
 				volumeParams, volumeAttachmentParams,
 			})
@@ -52,6 +52,10 @@ type Volume interface {
 	// Releasing reports whether or not the volume is to be released
 	// from the model when it is Dying/Dead.
 	Releasing() bool
+
+	// RequestedSize returns the size in MiB that the volume has been
+	// requested to grow to, and whether or not a resize is pending.
+	RequestedSize() (uint64, bool)
 }
 
 // VolumeAttachment describes an attachment of a volume to a machine.
@@ -127,6 +131,11 @@ type volumeDoc struct {
 	Info            *VolumeInfo   `bson:"info,omitempty"`
 	Params          *VolumeParams `bson:"params,omitempty"`
 
+	// RequestedSize is the size in MiB that a provisioned volume
+	// has been requested to grow to. It is cleared once the storage
+	// provisioner records info with at least this size.
+	RequestedSize uint64 `bson:"requested-size,omitempty"`
+
 	// HostId is the ID of the host that a non-detachable
 	// volume is initially attached to. We use this to identify
 	// the volume as being non-detachable, and to determine
@@ -176,6 +185,10 @@ type VolumeParams struct {
 
 	Pool string `bson:"pool"`
 	Size uint64 `bson:"size"`
+
+	// SnapshotId, if non-empty, is the provider ID of the snapshot
+	// that the volume is to be created from.
+	SnapshotId string `bson:"snapshot-id,omitempty"`
 }
 
 // VolumeInfo describes information about a volume.
@@ -186,6 +199,7 @@ type VolumeInfo struct {
 	Pool       string `bson:"pool"`
 	VolumeId   string `bson:"volumeid"`
 	Persistent bool   `bson:"persistent"`
+	Encrypted  bool   `bson:"encrypted,omitempty"`
 }
 
 // VolumeAttachmentInfo describes information about a volume attachment.
@@ -278,6 +292,11 @@ func (v *volume) Releasing() bool {
 	return v.doc.Releasing
 }
 
+// RequestedSize is required to implement Volume.
+func (v *volume) RequestedSize() (uint64, bool) {
+	return v.doc.RequestedSize, v.doc.RequestedSize != 0
+}
+
 // Status is required to implement StatusGetter.
 func (v *volume) Status() (status.StatusInfo, error) {
 	return getStatus(v.mb.db(), volumeGlobalKey(v.VolumeTag().Id()), "volume")
@@ -1438,8 +1457,21 @@ func (sb *storageBackend) SetVolumeInfo(tag names.VolumeTag, info VolumeInfo) (e
 			if err := validateVolumeInfoChange(info, oldInfo); err != nil {
 				return nil, err
 			}
+			if info.Size > oldInfo.Size {
+				// The volume has grown, so any filesystem
+				// backed by it grows along with it.
+				growOps, err := sb.growVolumeFilesystemOps(tag, info.Size)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				ops = append(ops, growOps...)
+			}
 		}
-		ops = append(ops, setVolumeInfoOps(tag, info, unsetParams)...)
+		// Once the volume has reached the requested size,
+		// the resize request has been satisfied.
+		requestedSize, resizing := v.RequestedSize()
+		unsetRequestedSize := resizing && info.Size >= requestedSize
+		ops = append(ops, setVolumeInfoOps(tag, info, unsetParams, unsetRequestedSize)...)
 		return ops, nil
 	}
 	return sb.mb.db().Run(buildTxn)
@@ -1461,15 +1493,22 @@ func validateVolumeInfoChange(newInfo, oldInfo VolumeInfo) error {
 	return nil
 }
 
-func setVolumeInfoOps(tag names.VolumeTag, info VolumeInfo, unsetParams bool) []txn.Op {
+func setVolumeInfoOps(tag names.VolumeTag, info VolumeInfo, unsetParams, unsetRequestedSize bool) []txn.Op {
 	asserts := isAliveDoc
 	update := bson.D{
 		{"$set", bson.D{{"info", &info}}},
 	}
+	var unset bson.D
 	if unsetParams {
 		asserts = append(asserts, bson.DocElem{"info", bson.D{{"$exists", false}}})
 		asserts = append(asserts, bson.DocElem{"params", bson.D{{"$exists", true}}})
-		update = append(update, bson.DocElem{"$unset", bson.D{{"params", nil}}})
+		unset = append(unset, bson.DocElem{"params", nil})
+	}
+	if unsetRequestedSize {
+		unset = append(unset, bson.DocElem{"requested-size", nil})
+	}
+	if len(unset) > 0 {
+		update = append(update, bson.DocElem{"$unset", unset})
 	}
 	return []txn.Op{{
 		C:      volumesC,
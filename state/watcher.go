@@ -3450,6 +3450,18 @@ func (st *State) WatchMachineRemovals() NotifyWatcher {
 	return newNotifyCollWatcher(st, machineRemovalsC, isLocalID(st))
 }
 
+// WatchFirewallRules returns a NotifyWatcher which triggers
+// whenever the model's firewall rules change.
+func (st *State) WatchFirewallRules() NotifyWatcher {
+	return newNotifyCollWatcher(st, firewallRulesC, isLocalID(st))
+}
+
+// WatchEgressRules returns a NotifyWatcher which triggers
+// whenever the egress rules of any application change.
+func (st *State) WatchEgressRules() NotifyWatcher {
+	return newNotifyCollWatcher(st, egressRulesC, isLocalID(st))
+}
+
 // notifyCollWatcher implements NotifyWatcher, triggering when a
 // change is seen in a specific collection matching the provided
 // filter function.
@@ -19,6 +19,16 @@ const (
 	// should not be relied upon until a storage source is
 	// constructed.
 	ConfigStorageDir = "storage-dir"
+
+	// ConfigKeyDir is the path to the directory in which a
+	// machine-scoped storage source keeps the keys of the volumes
+	// it encrypts. It is kept apart from ConfigStorageDir, so that
+	// storage artifacts (e.g. loop backing files and snapshots)
+	// never sit beside the keys that decrypt them.
+	//
+	// ConfigKeyDir is set by the storage provisioner, so should
+	// not be relied upon until a storage source is constructed.
+	ConfigKeyDir = "key-dir"
 )
 
 // Config defines the configuration for a storage source.
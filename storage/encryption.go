@@ -0,0 +1,106 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+)
+
+const (
+	// EncryptedAttribute is the name of the storage pool attribute
+	// that requests that volumes be encrypted at rest. Providers with
+	// native encryption support use that; machine-scoped block device
+	// providers encrypt their volumes with LUKS on the machine.
+	EncryptedAttribute = "encrypted"
+
+	// KMSKeyAttribute is the name of the optional storage pool
+	// attribute that identifies the key management service key
+	// used to encrypt volumes. It may only be specified along
+	// with EncryptedAttribute, and only for providers that
+	// support customer-managed keys.
+	KMSKeyAttribute = "kms-key"
+)
+
+var encryptionFields = schema.Fields{
+	EncryptedAttribute: schema.Bool(),
+	KMSKeyAttribute:    schema.String(),
+}
+
+var encryptionChecker = schema.FieldMap(
+	encryptionFields,
+	schema.Defaults{
+		EncryptedAttribute: false,
+		KMSKeyAttribute:    "",
+	},
+)
+
+// EncryptionConfig describes the encryption requested
+// by a storage pool's attributes.
+type EncryptionConfig struct {
+	// Encrypted reports whether volumes should be encrypted.
+	Encrypted bool
+
+	// KMSKey is the key management service key used to
+	// encrypt volumes. If empty, the provider's default
+	// key is used.
+	KMSKey string
+}
+
+// ParseEncryptionConfig returns the encryption configuration described
+// by the given storage pool attributes. Attributes not related to
+// encryption are ignored.
+func ParseEncryptionConfig(attrs map[string]interface{}) (EncryptionConfig, error) {
+	in := make(map[string]interface{})
+	for _, name := range []string{EncryptedAttribute, KMSKeyAttribute} {
+		if v, ok := attrs[name]; ok {
+			in[name] = v
+		}
+	}
+	out, err := encryptionChecker.Coerce(in, nil)
+	if err != nil {
+		return EncryptionConfig{}, errors.Annotate(err, "validating encryption config")
+	}
+	coerced := out.(map[string]interface{})
+	cfg := EncryptionConfig{
+		Encrypted: coerced[EncryptedAttribute].(bool),
+		KMSKey:    coerced[KMSKeyAttribute].(string),
+	}
+	if cfg.KMSKey != "" && !cfg.Encrypted {
+		return EncryptionConfig{}, errors.Errorf(
+			"%q specified, but %q is not true",
+			KMSKeyAttribute, EncryptedAttribute,
+		)
+	}
+	return cfg, nil
+}
+
+// ValidateNoEncryption validates the encryption attributes of a storage
+// pool for a provider that cannot encrypt its volumes or filesystems,
+// described by what. Requesting encryption is an error satisfying
+// errors.IsNotSupported, rather than being silently ignored.
+func ValidateNoEncryption(attrs map[string]interface{}, what string) error {
+	cfg, err := ParseEncryptionConfig(attrs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if cfg.Encrypted {
+		return errors.NotSupportedf("%q for %s", EncryptedAttribute, what)
+	}
+	return nil
+}
+
+// ValidateNoKMSKey validates the encryption attributes of a storage pool
+// for a provider that always encrypts its volumes at rest, but cannot
+// use a customer-managed key to do so.
+func ValidateNoKMSKey(attrs map[string]interface{}, what string) error {
+	cfg, err := ParseEncryptionConfig(attrs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if cfg.KMSKey != "" {
+		return errors.NotSupportedf("%q for %s", KMSKeyAttribute, what)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/storage"
+)
+
+type EncryptionSuite struct{}
+
+var _ = gc.Suite(&EncryptionSuite{})
+
+func (s *EncryptionSuite) TestParseEncryptionConfig(c *gc.C) {
+	for i, test := range []struct {
+		attrs    map[string]interface{}
+		expected storage.EncryptionConfig
+		err      string
+	}{{
+		attrs: nil,
+	}, {
+		attrs: map[string]interface{}{"volume-type": "ssd"},
+	}, {
+		attrs:    map[string]interface{}{"encrypted": true},
+		expected: storage.EncryptionConfig{Encrypted: true},
+	}, {
+		attrs:    map[string]interface{}{"encrypted": "true"},
+		expected: storage.EncryptionConfig{Encrypted: true},
+	}, {
+		attrs:    map[string]interface{}{"encrypted": "true", "kms-key": "key-id"},
+		expected: storage.EncryptionConfig{Encrypted: true, KMSKey: "key-id"},
+	}, {
+		attrs: map[string]interface{}{"kms-key": "key-id"},
+		err:   `"kms-key" specified, but "encrypted" is not true`,
+	}, {
+		attrs: map[string]interface{}{"encrypted": "maybe"},
+		err:   `validating encryption config: encrypted: expected bool, got string\("maybe"\)`,
+	}} {
+		c.Logf("test %d: %v", i, test.attrs)
+		cfg, err := storage.ParseEncryptionConfig(test.attrs)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(cfg, jc.DeepEquals, test.expected)
+	}
+}
+
+func (s *EncryptionSuite) TestValidateNoEncryption(c *gc.C) {
+	err := storage.ValidateNoEncryption(map[string]interface{}{"encrypted": false}, "tmpfs filesystems")
+	c.Check(err, jc.ErrorIsNil)
+	err = storage.ValidateNoEncryption(map[string]interface{}{"encrypted": true}, "tmpfs filesystems")
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	c.Check(err, gc.ErrorMatches, `"encrypted" for tmpfs filesystems not supported`)
+	err = storage.ValidateNoEncryption(map[string]interface{}{"kms-key": "key-id"}, "tmpfs filesystems")
+	c.Check(err, gc.ErrorMatches, `"kms-key" specified, but "encrypted" is not true`)
+}
+
+func (s *EncryptionSuite) TestValidateNoKMSKey(c *gc.C) {
+	err := storage.ValidateNoKMSKey(map[string]interface{}{"encrypted": true}, "Azure disks")
+	c.Check(err, jc.ErrorIsNil)
+	err = storage.ValidateNoKMSKey(map[string]interface{}{"encrypted": true, "kms-key": "key-id"}, "Azure disks")
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	c.Check(err, gc.ErrorMatches, `"kms-key" for Azure disks not supported`)
+}
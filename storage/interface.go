@@ -216,6 +216,37 @@ type VolumeImporter interface {
 	) (VolumeInfo, error)
 }
 
+// VolumeResizer is an optional interface that a VolumeSource may
+// implement if the provider is able to grow existing volumes.
+type VolumeResizer interface {
+	// ResizeVolumes grows the volumes with the specified parameters
+	// to at least the requested size. The size of each volume after
+	// resizing is returned, as providers may round the requested
+	// size up to a supported increment.
+	ResizeVolumes(ctx context.ProviderCallContext, params []VolumeResizeParams) ([]ResizeResult, error)
+}
+
+// FilesystemResizer is an optional interface that a FilesystemSource
+// may implement if the provider is able to grow existing filesystems.
+type FilesystemResizer interface {
+	// ResizeFilesystems grows the filesystems with the specified
+	// parameters to at least the requested size. The size of each
+	// filesystem after resizing is returned.
+	ResizeFilesystems(ctx context.ProviderCallContext, params []FilesystemResizeParams) ([]ResizeResult, error)
+}
+
+// VolumeSnapshotter is an optional interface that a VolumeSource may
+// implement if the provider is able to take point-in-time snapshots of
+// volumes. A VolumeSource that implements VolumeSnapshotter must also
+// support creating volumes from its snapshots, through the SnapshotId
+// field of VolumeParams.
+type VolumeSnapshotter interface {
+	// CreateVolumeSnapshots creates snapshots of the volumes with
+	// the specified parameters, returning the provider-supplied
+	// ID of each snapshot.
+	CreateVolumeSnapshots(ctx context.ProviderCallContext, params []VolumeSnapshotParams) ([]CreateVolumeSnapshotsResult, error)
+}
+
 // VolumeParams is a fully specified set of parameters for volume creation,
 // derived from one or more of user-specified storage constraints, a
 // storage pool definition, and charm storage metadata.
@@ -239,6 +270,11 @@ type VolumeParams struct {
 	// storage provider supports tags.
 	ResourceTags map[string]string
 
+	// SnapshotId, if non-empty, is the provider-supplied ID of a
+	// snapshot from which the volume should be created. Only volume
+	// sources that implement VolumeSnapshotter support this.
+	SnapshotId string
+
 	// Attachment identifies the machine that the volume should be attached
 	// to initially, or nil if the volume should not be attached to any
 	// machine. Some providers, such as MAAS, do not support dynamic
@@ -339,6 +375,48 @@ type FilesystemAttachmentParams struct {
 	Path string
 }
 
+// VolumeResizeParams is a set of parameters for growing a volume.
+type VolumeResizeParams struct {
+	// Tag is the unique tag assigned by Juju for the volume.
+	Tag names.VolumeTag
+
+	// VolumeId is the unique provider-supplied ID for the volume.
+	VolumeId string
+
+	// Size is the minimum size of the volume after resizing, in MiB.
+	Size uint64
+}
+
+// FilesystemResizeParams is a set of parameters for growing a filesystem.
+type FilesystemResizeParams struct {
+	// Tag is the unique tag assigned by Juju for the filesystem.
+	Tag names.FilesystemTag
+
+	// FilesystemId is the unique provider-supplied ID for the filesystem.
+	FilesystemId string
+
+	// Size is the minimum size of the filesystem after resizing, in MiB.
+	Size uint64
+}
+
+// VolumeSnapshotParams is a set of parameters for creating a snapshot
+// of a volume.
+type VolumeSnapshotParams struct {
+	// Tag is the unique tag assigned by Juju for the volume.
+	Tag names.VolumeTag
+
+	// VolumeId is the unique provider-supplied ID for the volume.
+	VolumeId string
+
+	// Snapshot is the ID assigned by Juju for the snapshot. Providers
+	// may use it to name or tag the snapshot.
+	Snapshot string
+
+	// ResourceTags is a set of tags to set on the created snapshot,
+	// if the storage provider supports tags.
+	ResourceTags map[string]string
+}
+
 // CreateVolumesResult contains the result of a VolumeSource.CreateVolumes call
 // for one volume. Volume and VolumeAttachment should only be used if Error is
 // nil.
@@ -375,3 +453,20 @@ type AttachFilesystemsResult struct {
 	FilesystemAttachment *FilesystemAttachment
 	Error                error
 }
+
+// ResizeResult contains the result of resizing one volume or filesystem
+// with VolumeResizer.ResizeVolumes or FilesystemResizer.ResizeFilesystems.
+// Size, in MiB, should only be used if Error is nil.
+type ResizeResult struct {
+	Size  uint64
+	Error error
+}
+
+// CreateVolumeSnapshotsResult contains the result of creating one
+// snapshot with VolumeSnapshotter.CreateVolumeSnapshots. SnapshotId
+// and Size, in MiB, should only be used if Error is nil.
+type CreateVolumeSnapshotsResult struct {
+	SnapshotId string
+	Size       uint64
+	Error      error
+}
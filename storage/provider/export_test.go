@@ -19,6 +19,7 @@ var Getpagesize = &getpagesize
 func LoopVolumeSource(
 	etcDir string,
 	storageDir string,
+	keyDir string,
 	run func(string, ...string) (string, error),
 ) (storage.VolumeSource, *MockDirFuncs) {
 	dirFuncs := &MockDirFuncs{
@@ -26,7 +27,7 @@ func LoopVolumeSource(
 		etcDir,
 		set.NewStrings(),
 	}
-	return &loopVolumeSource{dirFuncs, run, storageDir}, dirFuncs
+	return &loopVolumeSource{dirFuncs, run, storageDir, keyDir}, dirFuncs
 }
 
 func LoopProvider(
@@ -35,6 +36,19 @@ func LoopProvider(
 	return &loopProvider{run}
 }
 
+func LVMVolumeSource(
+	run func(string, ...string) (string, error),
+	volumeGroup, thinPool, keyDir string,
+) storage.VolumeSource {
+	return &lvmVolumeSource{run, volumeGroup, thinPool, keyDir}
+}
+
+func LVMProvider(
+	run func(string, ...string) (string, error),
+) storage.Provider {
+	return &lvmProvider{run}
+}
+
 func NewMockManagedFilesystemSource(
 	etcDir string,
 	run func(string, ...string) (string, error),
@@ -32,9 +32,10 @@ type loopProvider struct {
 var _ storage.Provider = (*loopProvider)(nil)
 
 // ValidateConfig is defined on the Provider interface.
-func (*loopProvider) ValidateConfig(*storage.Config) error {
-	// Loop provider has no configuration.
-	return nil
+func (*loopProvider) ValidateConfig(cfg *storage.Config) error {
+	// Loop provider has no configuration other
+	// than the common encryption attributes.
+	return validateLUKSConfig(cfg.Attrs())
 }
 
 // validateFullConfig validates a fully-constructed storage config,
@@ -58,10 +59,12 @@ func (lp *loopProvider) VolumeSource(sourceConfig *storage.Config) (storage.Volu
 	}
 	// storageDir is validated by validateFullConfig.
 	storageDir, _ := sourceConfig.ValueString(storage.ConfigStorageDir)
+	keyDir, _ := sourceConfig.ValueString(storage.ConfigKeyDir)
 	return &loopVolumeSource{
 		&osDirFuncs{lp.run},
 		lp.run,
 		storageDir,
+		keyDir,
 	}, nil
 }
 
@@ -101,6 +104,10 @@ type loopVolumeSource struct {
 	dirFuncs   dirFuncs
 	run        runCommandFunc
 	storageDir string
+
+	// keyDir is the directory in which the keys
+	// for LUKS-encrypted volumes are kept.
+	keyDir string
 }
 
 var _ storage.VolumeSource = (*loopVolumeSource)(nil)
@@ -120,18 +127,54 @@ func (lvs *loopVolumeSource) CreateVolumes(ctx context.ProviderCallContext, args
 
 func (lvs *loopVolumeSource) createVolume(params storage.VolumeParams) (storage.Volume, error) {
 	volumeId := params.Tag.String()
+	encryption, err := storage.ParseEncryptionConfig(params.Attributes)
+	if err != nil {
+		return storage.Volume{}, errors.Trace(err)
+	}
 	loopFilePath := lvs.volumeFilePath(params.Tag)
 	if err := ensureDir(lvs.dirFuncs, filepath.Dir(loopFilePath)); err != nil {
 		return storage.Volume{}, errors.Trace(err)
 	}
+	if encryption.Encrypted && lvs.keyDir == "" {
+		return storage.Volume{}, errors.New("key directory not specified")
+	}
+	keyFile := lvs.keyFilePath(volumeId)
+	var encrypted bool
+	if params.SnapshotId != "" {
+		// Restore the snapshot, and then grow the
+		// file to the requested size if necessary.
+		snapshotFilePath, err := lvs.snapshotFilePath(params.SnapshotId)
+		if err != nil {
+			return storage.Volume{}, errors.Trace(err)
+		}
+		if err := copySparseFile(lvs.run, snapshotFilePath, loopFilePath); err != nil {
+			return storage.Volume{}, errors.Annotate(err, "could not restore snapshot")
+		}
+		if lvs.keyDir != "" {
+			encrypted, err = restoreLUKSKey(lvs.keyDir, params.SnapshotId, keyFile, encryption)
+			if err != nil {
+				return storage.Volume{}, errors.Trace(err)
+			}
+		}
+	}
 	if err := createBlockFile(lvs.run, loopFilePath, params.Size); err != nil {
 		return storage.Volume{}, errors.Annotate(err, "could not create block file")
 	}
+	if encryption.Encrypted && !encrypted {
+		if err := createLUKSKey(keyFile); err != nil {
+			return storage.Volume{}, errors.Trace(err)
+		}
+		if err := formatLUKS(lvs.run, keyFile, loopFilePath); err != nil {
+			return storage.Volume{}, errors.Trace(err)
+		}
+		encrypted = true
+	}
 	return storage.Volume{
 		params.Tag,
 		storage.VolumeInfo{
-			VolumeId: volumeId,
-			Size:     params.Size,
+			VolumeId:  volumeId,
+			Size:      params.Size,
+			Encrypted: encrypted,
 		},
 	}, nil
 }
@@ -140,6 +183,83 @@ func (lvs *loopVolumeSource) volumeFilePath(tag names.VolumeTag) string {
 	return filepath.Join(lvs.storageDir, tag.String())
 }
 
+// snapshotFilePath returns the path of the file holding the
+// snapshot with the specified ID.
+func (lvs *loopVolumeSource) snapshotFilePath(snapshotId string) (string, error) {
+	if snapshotId != filepath.Base(snapshotId) || strings.HasPrefix(snapshotId, ".") {
+		return "", errors.NotValidf("loop snapshot ID %q", snapshotId)
+	}
+	return filepath.Join(lvs.storageDir, "snapshots", snapshotId), nil
+}
+
+var _ storage.VolumeSnapshotter = (*loopVolumeSource)(nil)
+
+// CreateVolumeSnapshots is defined on the VolumeSnapshotter interface.
+// Snapshots are sparse copies of the loop backing files, and are
+// intended for testing snapshot and restore locally.
+func (lvs *loopVolumeSource) CreateVolumeSnapshots(ctx context.ProviderCallContext, args []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+	results := make([]storage.CreateVolumeSnapshotsResult, len(args))
+	for i, arg := range args {
+		snapshotId, size, err := lvs.createSnapshot(arg)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "snapshotting volume %s", arg.Tag.Id())
+			continue
+		}
+		results[i].SnapshotId = snapshotId
+		results[i].Size = size
+	}
+	return results, nil
+}
+
+func (lvs *loopVolumeSource) createSnapshot(params storage.VolumeSnapshotParams) (string, uint64, error) {
+	loopFilePath := lvs.volumeFilePath(params.Tag)
+	info, err := os.Stat(loopFilePath)
+	if err != nil {
+		return "", 0, errors.Annotate(err, "reading loop backing file")
+	}
+	snapshotId := fmt.Sprintf(
+		"%s-snapshot-%s", params.Tag.String(),
+		strings.Replace(params.Snapshot, "/", "-", -1),
+	)
+	snapshotFilePath, err := lvs.snapshotFilePath(snapshotId)
+	if err != nil {
+		return "", 0, errors.Trace(err)
+	}
+	if err := ensureDir(lvs.dirFuncs, filepath.Dir(snapshotFilePath)); err != nil {
+		return "", 0, errors.Trace(err)
+	}
+	if err := copySparseFile(lvs.run, loopFilePath, snapshotFilePath); err != nil {
+		return "", 0, errors.Trace(err)
+	}
+	// Snapshots of encrypted volumes can only be
+	// restored with the volume's key.
+	if lvs.keyDir != "" {
+		if _, err := copyLUKSKey(
+			lvs.keyFilePath(params.Tag.String()),
+			lvs.keyFilePath(snapshotId),
+		); err != nil {
+			return "", 0, errors.Trace(err)
+		}
+	}
+	sizeInMiB := (uint64(info.Size()) + (1 << 20) - 1) >> 20
+	return snapshotId, sizeInMiB, nil
+}
+
+// keyFilePath returns the path of the LUKS key file
+// for the volume or snapshot with the specified ID.
+func (lvs *loopVolumeSource) keyFilePath(id string) string {
+	return luksKeyFilePath(lvs.keyDir, id)
+}
+
+// encrypted reports whether or not the volume with
+// the specified ID is encrypted with LUKS.
+func (lvs *loopVolumeSource) encrypted(volumeId string) (bool, error) {
+	if lvs.keyDir == "" {
+		return false, nil
+	}
+	return luksKeyExists(lvs.keyFilePath(volumeId))
+}
+
 // ListVolumes is defined on the VolumeSource interface.
 func (lvs *loopVolumeSource) ListVolumes(ctx context.ProviderCallContext) ([]string, error) {
 	// TODO(axw) implement this when we need it.
@@ -178,7 +298,10 @@ func (lvs *loopVolumeSource) destroyVolume(volumeId string) error {
 	if err != nil && !os.IsNotExist(err) {
 		return errors.Annotate(err, "removing loop backing file")
 	}
-	return nil
+	if lvs.keyDir == "" {
+		return nil
+	}
+	return errors.Trace(removeLUKSKey(lvs.keyFilePath(volumeId)))
 }
 
 // ValidateVolumeParams is defined on the VolumeSource interface.
@@ -210,13 +333,35 @@ func (lvs *loopVolumeSource) attachVolume(arg storage.VolumeAttachmentParams) (*
 		os.Remove(loopFilePath)
 		return nil, errors.Annotate(err, "attaching loop device")
 	}
+	info := storage.VolumeAttachmentInfo{
+		DeviceName: deviceName,
+		ReadOnly:   arg.ReadOnly,
+	}
+	keyFile := lvs.keyFilePath(arg.Volume.String())
+	encrypted, err := lvs.encrypted(arg.Volume.String())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if encrypted {
+		// The volume is exposed to the machine as the
+		// decrypted device-mapper device, rather than as
+		// the loop device that backs it.
+		devicePath, err := openLUKS(
+			lvs.run, keyFile, path.Join("/dev", deviceName),
+			luksMapperName(arg.Volume), arg.ReadOnly,
+		)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		info = storage.VolumeAttachmentInfo{
+			DeviceLink: devicePath,
+			ReadOnly:   arg.ReadOnly,
+		}
+	}
 	return &storage.VolumeAttachment{
 		arg.Volume,
 		arg.Machine,
-		storage.VolumeAttachmentInfo{
-			DeviceName: deviceName,
-			ReadOnly:   arg.ReadOnly,
-		},
+		info,
 	}, nil
 }
 
@@ -232,6 +377,15 @@ func (lvs *loopVolumeSource) DetachVolumes(ctx context.ProviderCallContext, args
 }
 
 func (lvs *loopVolumeSource) detachVolume(tag names.VolumeTag) error {
+	encrypted, err := lvs.encrypted(tag.String())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if encrypted {
+		if err := closeLUKS(lvs.run, luksMapperName(tag)); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	loopFilePath := lvs.volumeFilePath(tag)
 	deviceNames, err := associatedLoopDevices(lvs.run, loopFilePath)
 	if err != nil {
@@ -259,6 +413,16 @@ func createBlockFile(run runCommandFunc, filePath string, sizeInMiB uint64) erro
 	return nil
 }
 
+// copySparseFile copies the file at the source path to the
+// destination path, preserving holes in the file.
+func copySparseFile(run runCommandFunc, sourcePath, destPath string) error {
+	_, err := run("cp", "--sparse=always", sourcePath, destPath)
+	if err != nil {
+		return errors.Annotatef(err, "copying %q to %q", sourcePath, destPath)
+	}
+	return nil
+}
+
 // attachLoopDevice attaches a loop device to the file with the
 // specified path, and returns the loop device's name (e.g. "loop0").
 // losetup will create additional loop devices as necessary.
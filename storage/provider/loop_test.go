@@ -87,6 +87,7 @@ func (s *loopSuite) loopVolumeSource(c *gc.C) (storage.VolumeSource, *provider.M
 	return provider.LoopVolumeSource(
 		c.MkDir(),
 		s.storageDir,
+		c.MkDir(),
 		s.commands.run,
 	)
 }
@@ -119,6 +120,67 @@ func (s *loopSuite) TestCreateVolumes(c *gc.C) {
 	})
 }
 
+func (s *loopSuite) TestCreateVolumesFromSnapshot(c *gc.C) {
+	source, _ := s.loopVolumeSource(c)
+	volumeFile := filepath.Join(s.storageDir, "volume-1")
+	s.commands.expect("cp", "--sparse=always",
+		filepath.Join(s.storageDir, "snapshots", "volume-0-snapshot-0-1"),
+		volumeFile,
+	)
+	s.commands.expect("fallocate", "-l", "4MiB", volumeFile)
+
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("1"),
+		Size:       4,
+		SnapshotId: "volume-0-snapshot-0-1",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume.VolumeId, gc.Equals, "volume-1")
+}
+
+func (s *loopSuite) TestCreateVolumesFromSnapshotInvalidId(c *gc.C) {
+	source, _ := s.loopVolumeSource(c)
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("1"),
+		Size:       4,
+		SnapshotId: "../volume-0",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, gc.ErrorMatches, `.*loop snapshot ID "../volume-0" not valid`)
+}
+
+func (s *loopSuite) TestCreateVolumeSnapshots(c *gc.C) {
+	source, dirFuncs := s.loopVolumeSource(c)
+	volumeFile := filepath.Join(s.storageDir, "volume-0")
+	err := ioutil.WriteFile(volumeFile, make([]byte, 1024*1024+1), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	snapshotsDir := filepath.Join(s.storageDir, "snapshots")
+	s.commands.expect("cp", "--sparse=always", volumeFile,
+		filepath.Join(snapshotsDir, "volume-0-snapshot-0-1"),
+	)
+
+	snapshotter, ok := source.(storage.VolumeSnapshotter)
+	c.Assert(ok, jc.IsTrue)
+	results, err := snapshotter.CreateVolumeSnapshots(s.callCtx, []storage.VolumeSnapshotParams{{
+		Tag:      names.NewVolumeTag("0"),
+		VolumeId: "volume-0",
+		Snapshot: "0/1",
+	}, {
+		Tag:      names.NewVolumeTag("1"),
+		VolumeId: "volume-1",
+		Snapshot: "1/2",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].SnapshotId, gc.Equals, "volume-0-snapshot-0-1")
+	c.Assert(results[0].Size, gc.Equals, uint64(2))
+	c.Assert(results[1].Error, gc.ErrorMatches, "snapshotting volume 1: reading loop backing file: .*")
+	c.Assert(dirFuncs.Dirs.Contains(snapshotsDir), jc.IsTrue)
+}
+
 func (s *loopSuite) TestCreateVolumesNoAttachment(c *gc.C) {
 	source, _ := s.loopVolumeSource(c)
 	s.commands.expect("fallocate", "-l", "2MiB", filepath.Join(s.storageDir, "volume-0"))
@@ -0,0 +1,201 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/storage"
+)
+
+// luksKeySize is the size, in bytes, of the randomly
+// generated keys used to encrypt volumes with LUKS.
+const luksKeySize = 64
+
+// validateLUKSConfig validates the encryption attributes of a storage
+// pool for a provider that encrypts volumes with LUKS on the machine.
+// The keys are generated by the machine agent, so a key management
+// service key cannot be specified.
+func validateLUKSConfig(attrs map[string]interface{}) error {
+	encryption, err := storage.ParseEncryptionConfig(attrs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if encryption.KMSKey != "" {
+		return errors.NotSupportedf("%q with LUKS encryption", storage.KMSKeyAttribute)
+	}
+	return nil
+}
+
+// luksKeyFilePath returns the path of the file holding the key
+// for the volume or snapshot with the specified ID. The keys are
+// kept in the key directory, which is only readable by root and
+// is not within the storage directory holding the volumes.
+//
+// LUKS protects the volumes from being read off the machine's
+// disks or backing files without the keys; it does not protect
+// them from root on the machine while it is running.
+func luksKeyFilePath(keyDir, id string) string {
+	return filepath.Join(keyDir, id+".key")
+}
+
+// luksMapperName returns the device-mapper name under which the
+// LUKS-encrypted volume with the specified tag is opened.
+func luksMapperName(tag names.VolumeTag) string {
+	return "juju-" + tag.String()
+}
+
+// luksMapperPath returns the path of the device node
+// for the device-mapper device with the given name.
+func luksMapperPath(name string) string {
+	return path.Join("/dev/mapper", name)
+}
+
+// luksKeyExists reports whether or not a key file exists at
+// the specified path, and hence whether the corresponding
+// volume is encrypted.
+func luksKeyExists(keyFile string) (bool, error) {
+	_, err := os.Stat(keyFile)
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, errors.Trace(err)
+}
+
+// createLUKSKey writes a new random key to the specified path.
+func createLUKSKey(keyFile string) error {
+	key := make([]byte, luksKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return errors.Annotate(err, "generating LUKS key")
+	}
+	return errors.Trace(writeLUKSKey(keyFile, key))
+}
+
+// copyLUKSKey copies the key at the source path to the destination
+// path, if it exists, and reports whether or not it was copied.
+func copyLUKSKey(sourcePath, destPath string) (bool, error) {
+	key, err := ioutil.ReadFile(sourcePath)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Annotate(err, "reading LUKS key")
+	}
+	if err := writeLUKSKey(destPath, key); err != nil {
+		return false, errors.Trace(err)
+	}
+	return true, nil
+}
+
+// restoreLUKSKey copies the key for the snapshot with the specified
+// ID, if the snapshot is encrypted, to the key file for the volume
+// being restored from it, and reports whether or not the restored
+// volume is encrypted.
+func restoreLUKSKey(keyDir, snapshotId, keyFile string, encryption storage.EncryptionConfig) (bool, error) {
+	encrypted, err := copyLUKSKey(luksKeyFilePath(keyDir, snapshotId), keyFile)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if encryption.Encrypted && !encrypted {
+		return false, errors.Errorf(
+			"cannot restore unencrypted snapshot %q to an encrypted volume",
+			snapshotId,
+		)
+	}
+	return encrypted, nil
+}
+
+func writeLUKSKey(keyFile string, key []byte) error {
+	if err := os.MkdirAll(filepath.Dir(keyFile), 0700); err != nil {
+		return errors.Annotate(err, "creating LUKS key directory")
+	}
+	if err := ioutil.WriteFile(keyFile, key, 0600); err != nil {
+		return errors.Annotate(err, "writing LUKS key")
+	}
+	return nil
+}
+
+// removeLUKSKey removes the key at the specified path, if it exists.
+func removeLUKSKey(keyFile string) error {
+	if err := os.Remove(keyFile); err != nil && !os.IsNotExist(err) {
+		return errors.Annotate(err, "removing LUKS key")
+	}
+	return nil
+}
+
+// formatLUKS initialises the block device or file at the
+// specified path as a LUKS container, encrypted with the
+// key in the specified key file.
+func formatLUKS(run runCommandFunc, keyFile, devicePath string) error {
+	_, err := run(
+		"cryptsetup", "luksFormat", "--batch-mode",
+		"--type", "luks2", "--key-file", keyFile,
+		devicePath,
+	)
+	if err != nil {
+		return errors.Annotatef(err, "formatting %q as LUKS", devicePath)
+	}
+	return nil
+}
+
+// openLUKS opens the LUKS container at the specified path as a
+// device-mapper device with the given name, unless it is already
+// open, and returns the path of the mapped device.
+func openLUKS(run runCommandFunc, keyFile, devicePath, name string, readOnly bool) (string, error) {
+	if luksActive(run, name) {
+		logger.Debugf("%s already opened as %s", devicePath, name)
+		return luksMapperPath(name), nil
+	}
+	args := []string{"open", "--type", "luks", "--key-file", keyFile}
+	if readOnly {
+		args = append(args, "--readonly")
+	}
+	args = append(args, devicePath, name)
+	if _, err := run("cryptsetup", args...); err != nil {
+		return "", errors.Annotatef(err, "opening LUKS device %q", devicePath)
+	}
+	return luksMapperPath(name), nil
+}
+
+// closeLUKS closes the device-mapper device with the given
+// name, if it is open.
+func closeLUKS(run runCommandFunc, name string) error {
+	if !luksActive(run, name) {
+		return nil
+	}
+	if _, err := run("cryptsetup", "close", name); err != nil {
+		return errors.Annotatef(err, "closing LUKS device %q", name)
+	}
+	return nil
+}
+
+// resizeLUKS grows the open device-mapper device with the given
+// name to fill the underlying device, after that has been extended.
+func resizeLUKS(run runCommandFunc, keyFile, name string) error {
+	if !luksActive(run, name) {
+		// The mapping is sized when it is next opened.
+		return nil
+	}
+	if _, err := run("cryptsetup", "resize", "--key-file", keyFile, name); err != nil {
+		return errors.Annotatef(err, "resizing LUKS device %q", name)
+	}
+	return nil
+}
+
+// luksActive reports whether or not the device-mapper
+// device with the given name is open.
+func luksActive(run runCommandFunc, name string) bool {
+	// "cryptsetup status" exits non-zero if the device is inactive.
+	_, err := run("cryptsetup", "status", name)
+	return err == nil
+}
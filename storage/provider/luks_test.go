@@ -0,0 +1,376 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&luksSuite{})
+
+// luksSuite tests the encryption of volumes created
+// by the loop and lvm providers.
+type luksSuite struct {
+	testing.BaseSuite
+	storageDir string
+	keyDir     string
+	commands   *mockRunCommand
+
+	callCtx context.ProviderCallContext
+}
+
+func (s *luksSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.storageDir = c.MkDir()
+	s.keyDir = c.MkDir()
+	s.commands = &mockRunCommand{c: c}
+	s.callCtx = context.NewCloudCallContext()
+}
+
+func (s *luksSuite) TearDownTest(c *gc.C) {
+	s.commands.assertDrained()
+	s.BaseSuite.TearDownTest(c)
+}
+
+func (s *luksSuite) keyFile(id string) string {
+	return filepath.Join(s.keyDir, id+".key")
+}
+
+func (s *luksSuite) writeKey(c *gc.C, id string) {
+	err := os.MkdirAll(filepath.Dir(s.keyFile(id)), 0700)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(s.keyFile(id), []byte("sekrit"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *luksSuite) assertKey(c *gc.C, id string) {
+	info, err := os.Stat(s.keyFile(id))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Mode().Perm(), gc.Equals, os.FileMode(0600))
+	c.Assert(info.Size(), gc.Equals, int64(64))
+}
+
+func (s *luksSuite) TestLoopValidateConfig(c *gc.C) {
+	p := provider.LoopProvider(s.commands.run)
+	cfg, err := storage.NewConfig("name", provider.LoopProviderType, map[string]interface{}{
+		"encrypted": "true",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.ValidateConfig(cfg), jc.ErrorIsNil)
+
+	cfg, err = storage.NewConfig("name", provider.LoopProviderType, map[string]interface{}{
+		"encrypted": "true",
+		"kms-key":   "key",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = p.ValidateConfig(cfg)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, `"kms-key" with LUKS encryption not supported`)
+}
+
+func (s *luksSuite) TestLoopCreateVolumesEncrypted(c *gc.C) {
+	source, _ := provider.LoopVolumeSource(c.MkDir(), s.storageDir, s.keyDir, s.commands.run)
+	fileName := filepath.Join(s.storageDir, "volume-0")
+	s.commands.expect("fallocate", "-l", "2MiB", fileName)
+	s.commands.expect(
+		"cryptsetup", "luksFormat", "--batch-mode", "--type", "luks2",
+		"--key-file", s.keyFile("volume-0"), fileName,
+	)
+
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("0"),
+		Size:       2,
+		Attributes: map[string]interface{}{"encrypted": true},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume.VolumeInfo, jc.DeepEquals, storage.VolumeInfo{
+		VolumeId:  "volume-0",
+		Size:      2,
+		Encrypted: true,
+	})
+	s.assertKey(c, "volume-0")
+}
+
+func (s *luksSuite) TestLoopCreateVolumesFromEncryptedSnapshot(c *gc.C) {
+	source, _ := provider.LoopVolumeSource(c.MkDir(), s.storageDir, s.keyDir, s.commands.run)
+	s.writeKey(c, "volume-0-snapshot-1")
+	fileName := filepath.Join(s.storageDir, "volume-1")
+	s.commands.expect("cp", "--sparse=always", filepath.Join(s.storageDir, "snapshots", "volume-0-snapshot-1"), fileName)
+	s.commands.expect("fallocate", "-l", "2MiB", fileName)
+
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("1"),
+		Size:       2,
+		SnapshotId: "volume-0-snapshot-1",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume.Encrypted, jc.IsTrue)
+
+	key, err := ioutil.ReadFile(s.keyFile("volume-1"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(key), gc.Equals, "sekrit")
+}
+
+func (s *luksSuite) TestLoopCreateVolumesFromUnencryptedSnapshot(c *gc.C) {
+	source, _ := provider.LoopVolumeSource(c.MkDir(), s.storageDir, s.keyDir, s.commands.run)
+	fileName := filepath.Join(s.storageDir, "volume-1")
+	s.commands.expect("cp", "--sparse=always", filepath.Join(s.storageDir, "snapshots", "volume-0-snapshot-1"), fileName)
+
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("1"),
+		Size:       2,
+		SnapshotId: "volume-0-snapshot-1",
+		Attributes: map[string]interface{}{"encrypted": true},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches,
+		`creating volume: cannot restore unencrypted snapshot "volume-0-snapshot-1" to an encrypted volume`)
+}
+
+func (s *luksSuite) TestLoopAttachVolumesEncrypted(c *gc.C) {
+	source, _ := provider.LoopVolumeSource(c.MkDir(), s.storageDir, s.keyDir, s.commands.run)
+	s.writeKey(c, "volume-0")
+	fileName := filepath.Join(s.storageDir, "volume-0")
+	s.commands.expect("losetup", "-j", fileName)
+	s.commands.expect("losetup", "-f", "--show", fileName).respond("/dev/loop98\n", nil)
+	s.commands.expect("cryptsetup", "status", "juju-volume-0").respond("", errors.New("inactive"))
+	s.commands.expect(
+		"cryptsetup", "open", "--type", "luks", "--key-file", s.keyFile("volume-0"),
+		"/dev/loop98", "juju-volume-0",
+	)
+
+	results, err := source.AttachVolumes(s.callCtx, []storage.VolumeAttachmentParams{{
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "volume-0",
+		AttachmentParams: storage.AttachmentParams{
+			Machine: names.NewMachineTag("0"),
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.AttachVolumesResult{{
+		VolumeAttachment: &storage.VolumeAttachment{
+			names.NewVolumeTag("0"),
+			names.NewMachineTag("0"),
+			storage.VolumeAttachmentInfo{
+				DeviceLink: "/dev/mapper/juju-volume-0",
+			},
+		},
+	}})
+}
+
+func (s *luksSuite) TestLoopDetachVolumesEncrypted(c *gc.C) {
+	source, _ := provider.LoopVolumeSource(c.MkDir(), s.storageDir, s.keyDir, s.commands.run)
+	s.writeKey(c, "volume-0")
+	fileName := filepath.Join(s.storageDir, "volume-0")
+	s.commands.expect("cryptsetup", "status", "juju-volume-0")
+	s.commands.expect("cryptsetup", "close", "juju-volume-0")
+	s.commands.expect("losetup", "-j", fileName).respond("/dev/loop0: foo\n", nil)
+	s.commands.expect("losetup", "-d", "/dev/loop0")
+
+	errs, err := source.DetachVolumes(s.callCtx, []storage.VolumeAttachmentParams{{
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "volume-0",
+		AttachmentParams: storage.AttachmentParams{
+			Machine: names.NewMachineTag("0"),
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil})
+}
+
+func (s *luksSuite) TestLoopDestroyVolumesRemovesKey(c *gc.C) {
+	source, _ := provider.LoopVolumeSource(c.MkDir(), s.storageDir, s.keyDir, s.commands.run)
+	s.writeKey(c, "volume-0")
+
+	errs, err := source.DestroyVolumes(s.callCtx, []string{"volume-0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil})
+	_, err = os.Stat(s.keyFile("volume-0"))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *luksSuite) TestLVMCreateVolumesEncrypted(c *gc.C) {
+	source := provider.LVMVolumeSource(s.commands.run, "vg0", "", s.keyDir)
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "--separator", ":", "-o", "lv_name,lv_size", "vg0")
+	s.commands.expect("lvcreate", "-y", "-n", "volume-0", "-L", "4M", "vg0")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "-o", "lv_size", "vg0/volume-0").respond("4.00", nil)
+	s.commands.expect(
+		"cryptsetup", "luksFormat", "--batch-mode", "--type", "luks2",
+		"--key-file", s.keyFile("volume-0"), "/dev/vg0/volume-0",
+	)
+
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("0"),
+		Size:       4,
+		Attributes: map[string]interface{}{"encrypted": "true"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume.VolumeInfo, jc.DeepEquals, storage.VolumeInfo{
+		VolumeId:  "volume-0",
+		Size:      4,
+		Encrypted: true,
+	})
+	s.assertKey(c, "volume-0")
+}
+
+func (s *luksSuite) TestLVMCreateVolumesEncryptedExisting(c *gc.C) {
+	source := provider.LVMVolumeSource(s.commands.run, "vg0", "", s.keyDir)
+	s.writeKey(c, "volume-0")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "--separator", ":", "-o", "lv_name,lv_size", "vg0").respond(
+		"  volume-0:4.00\n", nil,
+	)
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "-o", "lv_size", "vg0/volume-0").respond("4.00", nil)
+
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("0"),
+		Size:       4,
+		Attributes: map[string]interface{}{"encrypted": "true"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume.Encrypted, jc.IsTrue)
+	key, err := ioutil.ReadFile(s.keyFile("volume-0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(key), gc.Equals, "sekrit")
+}
+
+func (s *luksSuite) TestLVMCreateVolumesEncryptedNoStorageDir(c *gc.C) {
+	source := provider.LVMVolumeSource(s.commands.run, "vg0", "", "")
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("0"),
+		Size:       4,
+		Attributes: map[string]interface{}{"encrypted": true},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches, "creating volume: key directory not specified")
+}
+
+func (s *luksSuite) TestLVMAttachVolumesEncrypted(c *gc.C) {
+	source := provider.LVMVolumeSource(s.commands.run, "vg0", "", s.keyDir)
+	s.writeKey(c, "volume-0")
+	s.commands.expect("lvchange", "-ay", "-p", "r", "vg0/volume-0")
+	s.commands.expect("cryptsetup", "status", "juju-volume-0").respond("", errors.New("inactive"))
+	s.commands.expect(
+		"cryptsetup", "open", "--type", "luks", "--key-file", s.keyFile("volume-0"),
+		"--readonly", "/dev/vg0/volume-0", "juju-volume-0",
+	)
+
+	results, err := source.AttachVolumes(s.callCtx, []storage.VolumeAttachmentParams{{
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "volume-0",
+		AttachmentParams: storage.AttachmentParams{
+			Machine:  names.NewMachineTag("0"),
+			ReadOnly: true,
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.AttachVolumesResult{{
+		VolumeAttachment: &storage.VolumeAttachment{
+			names.NewVolumeTag("0"),
+			names.NewMachineTag("0"),
+			storage.VolumeAttachmentInfo{
+				DeviceLink: "/dev/mapper/juju-volume-0",
+				ReadOnly:   true,
+			},
+		},
+	}})
+}
+
+func (s *luksSuite) TestLVMAttachVolumesEncryptedAlreadyOpen(c *gc.C) {
+	source := provider.LVMVolumeSource(s.commands.run, "vg0", "", s.keyDir)
+	s.writeKey(c, "volume-0")
+	s.commands.expect("lvchange", "-ay", "-p", "rw", "vg0/volume-0")
+	s.commands.expect("cryptsetup", "status", "juju-volume-0")
+
+	results, err := source.AttachVolumes(s.callCtx, []storage.VolumeAttachmentParams{{
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "volume-0",
+		AttachmentParams: storage.AttachmentParams{
+			Machine: names.NewMachineTag("0"),
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].VolumeAttachment.DeviceLink, gc.Equals, "/dev/mapper/juju-volume-0")
+}
+
+func (s *luksSuite) TestLVMDetachVolumesEncrypted(c *gc.C) {
+	source := provider.LVMVolumeSource(s.commands.run, "vg0", "", s.keyDir)
+	s.writeKey(c, "volume-0")
+	s.commands.expect("cryptsetup", "status", "juju-volume-0")
+	s.commands.expect("cryptsetup", "close", "juju-volume-0")
+	s.commands.expect("lvchange", "-an", "vg0/volume-0")
+
+	errs, err := source.DetachVolumes(s.callCtx, []storage.VolumeAttachmentParams{{
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "volume-0",
+		AttachmentParams: storage.AttachmentParams{
+			Machine: names.NewMachineTag("0"),
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil})
+}
+
+func (s *luksSuite) TestLVMResizeVolumesEncrypted(c *gc.C) {
+	source := provider.LVMVolumeSource(s.commands.run, "vg0", "", s.keyDir)
+	s.writeKey(c, "volume-0")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "-o", "lv_size", "vg0/volume-0").respond("4.00", nil)
+	s.commands.expect("lvextend", "-L", "8M", "vg0/volume-0")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "-o", "lv_size", "vg0/volume-0").respond("8.00", nil)
+	s.commands.expect("cryptsetup", "status", "juju-volume-0")
+	s.commands.expect("cryptsetup", "resize", "--key-file", s.keyFile("volume-0"), "juju-volume-0")
+
+	resizer := source.(storage.VolumeResizer)
+	results, err := resizer.ResizeVolumes(s.callCtx, []storage.VolumeResizeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		VolumeId: "volume-0",
+		Size:     8,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.ResizeResult{{Size: 8}})
+}
+
+func (s *luksSuite) TestLVMCreateVolumeSnapshotsCopiesKey(c *gc.C) {
+	source := provider.LVMVolumeSource(s.commands.run, "vg0", "pool0", s.keyDir)
+	s.writeKey(c, "volume-0")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "-o", "lv_size", "vg0/volume-0").respond("4.00", nil)
+	s.commands.expect("lvcreate", "-y", "-s", "-n", "volume-0-snapshot-1", "vg0/volume-0")
+
+	snapshotter := source.(storage.VolumeSnapshotter)
+	results, err := snapshotter.CreateVolumeSnapshots(s.callCtx, []storage.VolumeSnapshotParams{{
+		Tag:      names.NewVolumeTag("0"),
+		VolumeId: "volume-0",
+		Snapshot: "1",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+
+	key, err := ioutil.ReadFile(s.keyFile("volume-0-snapshot-1"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(key), gc.Equals, "sekrit")
+}
@@ -0,0 +1,578 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	// LVMProviderType is the storage provider type for logical
+	// volumes carved from an LVM volume group on the machine.
+	LVMProviderType = storage.ProviderType("lvm")
+
+	// LVMVolumeGroup is the name of the pool attribute that
+	// identifies the volume group that volumes are created in.
+	LVMVolumeGroup = "volume-group"
+
+	// LVMThinPool is the name of the optional pool attribute that
+	// identifies a thin pool in the volume group. If specified,
+	// volumes are thinly provisioned from the thin pool.
+	LVMThinPool = "thin-pool"
+)
+
+// lvmNameRE matches valid LVM volume group and logical volume names.
+var lvmNameRE = regexp.MustCompile(`^[a-zA-Z0-9+_.][a-zA-Z0-9+_.-]*$`)
+
+// lvmProvider creates volume sources which carve logical
+// volumes from a volume group on the local machine.
+type lvmProvider struct {
+	// run is a function used for running commands on the local machine.
+	run runCommandFunc
+}
+
+var _ storage.Provider = (*lvmProvider)(nil)
+
+// ValidateConfig is defined on the Provider interface.
+func (*lvmProvider) ValidateConfig(cfg *storage.Config) error {
+	volumeGroup, _ := cfg.ValueString(LVMVolumeGroup)
+	if volumeGroup == "" {
+		return errors.Errorf("%q must be specified", LVMVolumeGroup)
+	}
+	if !lvmNameRE.MatchString(volumeGroup) {
+		return errors.NotValidf("volume group name %q", volumeGroup)
+	}
+	if thinPool, ok := cfg.ValueString(LVMThinPool); ok && thinPool != "" {
+		if !lvmNameRE.MatchString(thinPool) {
+			return errors.NotValidf("thin pool name %q", thinPool)
+		}
+	}
+	return validateLUKSConfig(cfg.Attrs())
+}
+
+// VolumeSource is defined on the Provider interface.
+func (p *lvmProvider) VolumeSource(sourceConfig *storage.Config) (storage.VolumeSource, error) {
+	if err := p.ValidateConfig(sourceConfig); err != nil {
+		return nil, err
+	}
+	volumeGroup, _ := sourceConfig.ValueString(LVMVolumeGroup)
+	thinPool, _ := sourceConfig.ValueString(LVMThinPool)
+	keyDir, _ := sourceConfig.ValueString(storage.ConfigKeyDir)
+	return &lvmVolumeSource{
+		run:         p.run,
+		volumeGroup: volumeGroup,
+		thinPool:    thinPool,
+		keyDir:      keyDir,
+	}, nil
+}
+
+// FilesystemSource is defined on the Provider interface.
+func (p *lvmProvider) FilesystemSource(providerConfig *storage.Config) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+// Supports is defined on the Provider interface.
+func (*lvmProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// Scope is defined on the Provider interface.
+func (*lvmProvider) Scope() storage.Scope {
+	return storage.ScopeMachine
+}
+
+// Dynamic is defined on the Provider interface.
+func (*lvmProvider) Dynamic() bool {
+	return true
+}
+
+// Releasable is defined on the Provider interface.
+func (*lvmProvider) Releasable() bool {
+	return false
+}
+
+// DefaultPools is defined on the Provider interface.
+func (*lvmProvider) DefaultPools() []*storage.Config {
+	// The volume group must be specified by the operator,
+	// so there are no default pools.
+	return nil
+}
+
+// lvmVolumeSource creates logical volumes in a volume group
+// on the local machine. Logical volumes are named after the
+// tags of the volumes they back.
+type lvmVolumeSource struct {
+	run         runCommandFunc
+	volumeGroup string
+	thinPool    string
+
+	// keyDir is the directory in which the keys
+	// for LUKS-encrypted volumes are kept.
+	keyDir string
+}
+
+var (
+	_ storage.VolumeSource      = (*lvmVolumeSource)(nil)
+	_ storage.VolumeResizer     = (*lvmVolumeSource)(nil)
+	_ storage.VolumeSnapshotter = (*lvmVolumeSource)(nil)
+)
+
+// CreateVolumes is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) CreateVolumes(ctx context.ProviderCallContext, args []storage.VolumeParams) ([]storage.CreateVolumesResult, error) {
+	results := make([]storage.CreateVolumesResult, len(args))
+	for i, arg := range args {
+		volume, err := s.createVolume(arg)
+		if err != nil {
+			results[i].Error = errors.Annotate(err, "creating volume")
+			continue
+		}
+		results[i].Volume = volume
+	}
+	return results, nil
+}
+
+func (s *lvmVolumeSource) createVolume(params storage.VolumeParams) (*storage.Volume, error) {
+	lvName := params.Tag.String()
+	encryption, err := storage.ParseEncryptionConfig(params.Attributes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if encryption.Encrypted && s.keyDir == "" {
+		return nil, errors.New("key directory not specified")
+	}
+	keyFile := s.keyFilePath(lvName)
+	// The logical volume may have been created by an earlier attempt
+	// that did not complete, in which case lvcreate would fail.
+	existing, err := s.listVolumes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	_, exists := existing[lvName]
+	var encrypted bool
+	switch {
+	case exists && params.SnapshotId != "":
+		// There is no telling whether the snapshot was completely
+		// restored, so start again.
+		if _, err := s.run("lvremove", "-f", s.lvPath(lvName)); err != nil {
+			return nil, errors.Annotatef(err, "removing incomplete logical volume %q", lvName)
+		}
+		fallthrough
+	case params.SnapshotId != "":
+		if err := s.restoreSnapshot(lvName, params.SnapshotId); err != nil {
+			return nil, errors.Annotate(err, "could not restore snapshot")
+		}
+		encrypted, err = restoreLUKSKey(s.keyDir, params.SnapshotId, keyFile, encryption)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	case exists:
+		// Reuse the logical volume, which will have been encrypted
+		// if its key was written.
+		encrypted, err = s.encrypted(lvName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	default:
+		args := []string{"-y", "-n", lvName}
+		if s.thinPool != "" {
+			args = append(args,
+				"-V", fmt.Sprintf("%dM", params.Size),
+				"-T", s.lvPath(s.thinPool),
+			)
+		} else {
+			args = append(args, "-L", fmt.Sprintf("%dM", params.Size), s.volumeGroup)
+		}
+		if _, err := s.run("lvcreate", args...); err != nil {
+			return nil, errors.Annotatef(err, "creating logical volume %q", lvName)
+		}
+	}
+	size, err := s.extendVolume(lvName, params.Size)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if encryption.Encrypted && !encrypted {
+		if err := createLUKSKey(keyFile); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := formatLUKS(s.run, keyFile, s.devicePath(lvName)); err != nil {
+			return nil, errors.Trace(err)
+		}
+		encrypted = true
+	}
+	return &storage.Volume{
+		params.Tag,
+		storage.VolumeInfo{
+			VolumeId:  lvName,
+			Size:      size,
+			Encrypted: encrypted,
+		},
+	}, nil
+}
+
+// restoreSnapshot creates the named logical volume from the
+// snapshot with the specified ID. Thin snapshots are restored by
+// taking a writable thin snapshot of the snapshot; thick snapshots
+// are copied into a newly created logical volume.
+func (s *lvmVolumeSource) restoreSnapshot(lvName, snapshotId string) error {
+	if !lvmNameRE.MatchString(snapshotId) {
+		return errors.NotValidf("LVM snapshot ID %q", snapshotId)
+	}
+	if s.thinPool != "" {
+		// -kn clears the activation skip flag
+		// set on thin snapshots by default.
+		_, err := s.run("lvcreate", "-y", "-s", "-kn", "-n", lvName, s.lvPath(snapshotId))
+		return errors.Annotatef(err, "creating logical volume %q from snapshot", lvName)
+	}
+	size, err := s.lvSize(snapshotId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := s.run("lvcreate", "-y", "-n", lvName, "-L", fmt.Sprintf("%dM", size), s.volumeGroup); err != nil {
+		return errors.Annotatef(err, "creating logical volume %q", lvName)
+	}
+	if _, err := s.run(
+		"dd",
+		"if="+s.devicePath(snapshotId),
+		"of="+s.devicePath(lvName),
+		"bs=4M", "conv=fsync",
+	); err != nil {
+		return errors.Annotatef(err, "copying snapshot %q to %q", snapshotId, lvName)
+	}
+	return nil
+}
+
+// extendVolume grows the named logical volume to at least the
+// specified size in MiB, and returns the resulting size.
+func (s *lvmVolumeSource) extendVolume(lvName string, size uint64) (uint64, error) {
+	current, err := s.lvSize(lvName)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if current >= size {
+		return current, nil
+	}
+	if _, err := s.run("lvextend", "-L", fmt.Sprintf("%dM", size), s.lvPath(lvName)); err != nil {
+		return 0, errors.Annotatef(err, "extending logical volume %q", lvName)
+	}
+	// LVM rounds sizes up to a multiple of the extent size,
+	// so report the actual size of the logical volume.
+	return s.lvSize(lvName)
+}
+
+// lvSize returns the size of the named logical volume in MiB.
+func (s *lvmVolumeSource) lvSize(lvName string) (uint64, error) {
+	out, err := s.run(
+		"lvs", "--noheadings", "--nosuffix", "--units", "m",
+		"-o", "lv_size", s.lvPath(lvName),
+	)
+	if err != nil {
+		return 0, errors.Annotatef(err, "getting size of logical volume %q", lvName)
+	}
+	return parseLVMSize(out)
+}
+
+// parseLVMSize parses a size in MiB, as reported by lvs with
+// "--nosuffix --units m", rounding up to the nearest MiB.
+func parseLVMSize(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	size, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, errors.Errorf("invalid logical volume size %q", s)
+	}
+	return uint64(math.Ceil(size)), nil
+}
+
+// lvPath returns the "<vg>/<lv>" path used to identify the
+// named logical volume to LVM commands.
+func (s *lvmVolumeSource) lvPath(lvName string) string {
+	return s.volumeGroup + "/" + lvName
+}
+
+// devicePath returns the path of the device node for the
+// named logical volume.
+func (s *lvmVolumeSource) devicePath(lvName string) string {
+	return path.Join("/dev", s.volumeGroup, lvName)
+}
+
+// keyFilePath returns the path of the LUKS key file for the
+// named logical volume or snapshot.
+func (s *lvmVolumeSource) keyFilePath(lvName string) string {
+	return luksKeyFilePath(s.keyDir, lvName)
+}
+
+// encrypted reports whether or not the named logical
+// volume is encrypted with LUKS.
+func (s *lvmVolumeSource) encrypted(lvName string) (bool, error) {
+	if s.keyDir == "" {
+		return false, nil
+	}
+	return luksKeyExists(s.keyFilePath(lvName))
+}
+
+// ListVolumes is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) ListVolumes(ctx context.ProviderCallContext) ([]string, error) {
+	volumes, err := s.listVolumes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	volumeIds := make([]string, 0, len(volumes))
+	for volumeId := range volumes {
+		volumeIds = append(volumeIds, volumeId)
+	}
+	return volumeIds, nil
+}
+
+// listVolumes returns the sizes in MiB of the logical volumes
+// in the volume group that were created by Juju, keyed by name.
+func (s *lvmVolumeSource) listVolumes() (map[string]uint64, error) {
+	out, err := s.run(
+		"lvs", "--noheadings", "--nosuffix", "--units", "m",
+		"--separator", ":", "-o", "lv_name,lv_size", s.volumeGroup,
+	)
+	if err != nil {
+		return nil, errors.Annotatef(err, "listing logical volumes in %q", s.volumeGroup)
+	}
+	volumes := make(map[string]uint64)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), ":", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		if _, err := names.ParseVolumeTag(fields[0]); err != nil {
+			// Ignore snapshots, thin pools and any
+			// logical volumes not managed by Juju.
+			continue
+		}
+		size, err := parseLVMSize(fields[1])
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		volumes[fields[0]] = size
+	}
+	return volumes, nil
+}
+
+// DescribeVolumes is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) DescribeVolumes(ctx context.ProviderCallContext, volumeIds []string) ([]storage.DescribeVolumesResult, error) {
+	volumes, err := s.listVolumes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]storage.DescribeVolumesResult, len(volumeIds))
+	for i, volumeId := range volumeIds {
+		size, ok := volumes[volumeId]
+		if !ok {
+			results[i].Error = errors.NotFoundf("logical volume %q", volumeId)
+			continue
+		}
+		results[i].VolumeInfo = &storage.VolumeInfo{
+			VolumeId: volumeId,
+			Size:     size,
+		}
+	}
+	return results, nil
+}
+
+// DestroyVolumes is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) DestroyVolumes(ctx context.ProviderCallContext, volumeIds []string) ([]error, error) {
+	results := make([]error, len(volumeIds))
+	for i, volumeId := range volumeIds {
+		if err := s.destroyVolume(volumeId); err != nil {
+			results[i] = errors.Annotatef(err, "destroying %q", volumeId)
+		}
+	}
+	return results, nil
+}
+
+func (s *lvmVolumeSource) destroyVolume(volumeId string) error {
+	if _, err := names.ParseVolumeTag(volumeId); err != nil {
+		return errors.Errorf("invalid LVM volume ID %q", volumeId)
+	}
+	_, err := s.run("lvremove", "-f", s.lvPath(volumeId))
+	if err != nil && !strings.Contains(err.Error(), "Failed to find logical volume") {
+		return errors.Annotate(err, "removing logical volume")
+	}
+	if s.keyDir == "" {
+		return nil
+	}
+	return errors.Trace(removeLUKSKey(s.keyFilePath(volumeId)))
+}
+
+// ReleaseVolumes is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) ReleaseVolumes(ctx context.ProviderCallContext, volumeIds []string) ([]error, error) {
+	return make([]error, len(volumeIds)), nil
+}
+
+// ValidateVolumeParams is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	// ValidateVolumeParams may be called on a machine other than
+	// the machine where the volume will be created, so we cannot
+	// check the free space in the volume group here.
+	return nil
+}
+
+// AttachVolumes is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) AttachVolumes(ctx context.ProviderCallContext, args []storage.VolumeAttachmentParams) ([]storage.AttachVolumesResult, error) {
+	results := make([]storage.AttachVolumesResult, len(args))
+	for i, arg := range args {
+		attachment, err := s.attachVolume(arg)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "attaching volume %v", arg.Volume.Id())
+			continue
+		}
+		results[i].VolumeAttachment = attachment
+	}
+	return results, nil
+}
+
+func (s *lvmVolumeSource) attachVolume(arg storage.VolumeAttachmentParams) (*storage.VolumeAttachment, error) {
+	lvName := arg.VolumeId
+	permission := "rw"
+	if arg.ReadOnly {
+		permission = "r"
+	}
+	if _, err := s.run("lvchange", "-ay", "-p", permission, s.lvPath(lvName)); err != nil {
+		return nil, errors.Annotatef(err, "activating logical volume %q", lvName)
+	}
+	// The diskmanager reports logical volumes with udev's
+	// /dev/<vg>/<lv> link, which we use to identify the device.
+	deviceLink := s.devicePath(lvName)
+	encrypted, err := s.encrypted(lvName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if encrypted {
+		// Encrypted volumes are exposed to the machine as
+		// the decrypted device-mapper device.
+		deviceLink, err = openLUKS(
+			s.run, s.keyFilePath(lvName), deviceLink,
+			luksMapperName(arg.Volume), arg.ReadOnly,
+		)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return &storage.VolumeAttachment{
+		arg.Volume,
+		arg.Machine,
+		storage.VolumeAttachmentInfo{
+			DeviceLink: deviceLink,
+			ReadOnly:   arg.ReadOnly,
+		},
+	}, nil
+}
+
+// DetachVolumes is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) DetachVolumes(ctx context.ProviderCallContext, args []storage.VolumeAttachmentParams) ([]error, error) {
+	results := make([]error, len(args))
+	for i, arg := range args {
+		if err := s.detachVolume(arg); err != nil {
+			results[i] = errors.Annotatef(err, "detaching volume %s", arg.Volume.Id())
+		}
+	}
+	return results, nil
+}
+
+func (s *lvmVolumeSource) detachVolume(arg storage.VolumeAttachmentParams) error {
+	encrypted, err := s.encrypted(arg.VolumeId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if encrypted {
+		if err := closeLUKS(s.run, luksMapperName(arg.Volume)); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	_, err = s.run("lvchange", "-an", s.lvPath(arg.VolumeId))
+	return errors.Trace(err)
+}
+
+// ResizeVolumes is defined on the VolumeResizer interface.
+func (s *lvmVolumeSource) ResizeVolumes(ctx context.ProviderCallContext, args []storage.VolumeResizeParams) ([]storage.ResizeResult, error) {
+	results := make([]storage.ResizeResult, len(args))
+	for i, arg := range args {
+		size, err := s.resizeVolume(arg)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "resizing volume %s", arg.Tag.Id())
+			continue
+		}
+		results[i].Size = size
+	}
+	return results, nil
+}
+
+func (s *lvmVolumeSource) resizeVolume(arg storage.VolumeResizeParams) (uint64, error) {
+	size, err := s.extendVolume(arg.VolumeId, arg.Size)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	encrypted, err := s.encrypted(arg.VolumeId)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if encrypted {
+		// Grow the decrypted device along with the logical
+		// volume, so the filesystem on it can be resized.
+		if err := resizeLUKS(s.run, s.keyFilePath(arg.VolumeId), luksMapperName(arg.Tag)); err != nil {
+			return 0, errors.Trace(err)
+		}
+	}
+	return size, nil
+}
+
+// CreateVolumeSnapshots is defined on the VolumeSnapshotter interface.
+// Snapshots of thinly provisioned volumes are thin snapshots; other
+// volumes are snapshotted with a copy-on-write snapshot as large as
+// the origin, so the snapshot cannot be invalidated by writes.
+func (s *lvmVolumeSource) CreateVolumeSnapshots(ctx context.ProviderCallContext, args []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+	results := make([]storage.CreateVolumeSnapshotsResult, len(args))
+	for i, arg := range args {
+		snapshotId, size, err := s.createSnapshot(arg)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "snapshotting volume %s", arg.Tag.Id())
+			continue
+		}
+		results[i].SnapshotId = snapshotId
+		results[i].Size = size
+	}
+	return results, nil
+}
+
+func (s *lvmVolumeSource) createSnapshot(params storage.VolumeSnapshotParams) (string, uint64, error) {
+	size, err := s.lvSize(params.VolumeId)
+	if err != nil {
+		return "", 0, errors.Trace(err)
+	}
+	snapshotId := fmt.Sprintf(
+		"%s-snapshot-%s", params.VolumeId,
+		strings.Replace(params.Snapshot, "/", "-", -1),
+	)
+	args := []string{"-y", "-s", "-n", snapshotId}
+	if s.thinPool == "" {
+		args = append(args, "-L", fmt.Sprintf("%dM", size))
+	}
+	args = append(args, s.lvPath(params.VolumeId))
+	if _, err := s.run("lvcreate", args...); err != nil {
+		return "", 0, errors.Annotatef(err, "creating snapshot of %q", params.VolumeId)
+	}
+	if s.keyDir != "" {
+		// Snapshots of encrypted volumes can only be
+		// restored with the volume's key.
+		if _, err := copyLUKSKey(s.keyFilePath(params.VolumeId), s.keyFilePath(snapshotId)); err != nil {
+			return "", 0, errors.Trace(err)
+		}
+	}
+	return snapshotId, size, nil
+}
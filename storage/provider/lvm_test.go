@@ -0,0 +1,396 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&lvmSuite{})
+
+type lvmSuite struct {
+	testing.BaseSuite
+	commands *mockRunCommand
+	keyDir   string
+
+	callCtx context.ProviderCallContext
+}
+
+func (s *lvmSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.commands = &mockRunCommand{c: c}
+	s.keyDir = c.MkDir()
+	s.callCtx = context.NewCloudCallContext()
+}
+
+func (s *lvmSuite) TearDownTest(c *gc.C) {
+	s.commands.assertDrained()
+	s.BaseSuite.TearDownTest(c)
+}
+
+func (s *lvmSuite) lvmVolumeSource(thinPool string) storage.VolumeSource {
+	return provider.LVMVolumeSource(s.commands.run, "vg0", thinPool, s.keyDir)
+}
+
+func (s *lvmSuite) TestValidateConfig(c *gc.C) {
+	p := provider.LVMProvider(s.commands.run)
+	for _, test := range []struct {
+		attrs map[string]interface{}
+		err   string
+	}{{
+		attrs: map[string]interface{}{},
+		err:   `"volume-group" must be specified`,
+	}, {
+		attrs: map[string]interface{}{"volume-group": "-vg0"},
+		err:   `volume group name "-vg0" not valid`,
+	}, {
+		attrs: map[string]interface{}{"volume-group": "vg0", "thin-pool": "pool/0"},
+		err:   `thin pool name "pool/0" not valid`,
+	}, {
+		attrs: map[string]interface{}{"volume-group": "vg0"},
+	}, {
+		attrs: map[string]interface{}{"volume-group": "vg0", "thin-pool": "pool0"},
+	}, {
+		attrs: map[string]interface{}{"volume-group": "vg0", "encrypted": "true"},
+	}, {
+		attrs: map[string]interface{}{"volume-group": "vg0", "encrypted": "true", "kms-key": "key"},
+		err:   `"kms-key" with LUKS encryption not supported`,
+	}} {
+		cfg, err := storage.NewConfig("name", provider.LVMProviderType, test.attrs)
+		c.Assert(err, jc.ErrorIsNil)
+		err = p.ValidateConfig(cfg)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *lvmSuite) TestSupports(c *gc.C) {
+	p := provider.LVMProvider(s.commands.run)
+	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsTrue)
+	c.Assert(p.Supports(storage.StorageKindFilesystem), jc.IsFalse)
+}
+
+func (s *lvmSuite) TestScope(c *gc.C) {
+	p := provider.LVMProvider(s.commands.run)
+	c.Assert(p.Scope(), gc.Equals, storage.ScopeMachine)
+}
+
+func (s *lvmSuite) TestCreateVolumes(c *gc.C) {
+	source := s.lvmVolumeSource("")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "--separator", ":", "-o", "lv_name,lv_size", "vg0")
+	s.commands.expect("lvcreate", "-y", "-n", "volume-0", "-L", "2M", "vg0")
+	// LVM rounds the size up to the extent size.
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "-o", "lv_size", "vg0/volume-0").respond("  4.00\n", nil)
+
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 2,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.CreateVolumesResult{{
+		Volume: &storage.Volume{
+			names.NewVolumeTag("0"),
+			storage.VolumeInfo{
+				VolumeId: "volume-0",
+				Size:     4,
+			},
+		},
+	}})
+}
+
+func (s *lvmSuite) TestCreateVolumesThin(c *gc.C) {
+	source := s.lvmVolumeSource("pool0")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "--separator", ":", "-o", "lv_name,lv_size", "vg0")
+	s.commands.expect("lvcreate", "-y", "-n", "volume-0", "-V", "1024M", "-T", "vg0/pool0")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "-o", "lv_size", "vg0/volume-0").respond("1024.00", nil)
+
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume.Size, gc.Equals, uint64(1024))
+}
+
+func (s *lvmSuite) TestCreateVolumesError(c *gc.C) {
+	source := s.lvmVolumeSource("")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "--separator", ":", "-o", "lv_name,lv_size", "vg0")
+	s.commands.expect("lvcreate", "-y", "-n", "volume-0", "-L", "1024M", "vg0").respond(
+		"", errors.New(`Volume group "vg0" has insufficient free space`),
+	)
+
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches, `creating volume: creating logical volume "volume-0": Volume group "vg0" has insufficient free space`)
+}
+
+func (s *lvmSuite) TestCreateVolumesExisting(c *gc.C) {
+	source := s.lvmVolumeSource("")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "--separator", ":", "-o", "lv_name,lv_size", "vg0").respond(
+		"  volume-0:1024.00\n", nil,
+	)
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "-o", "lv_size", "vg0/volume-0").respond("1024.00", nil)
+
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume.VolumeId, gc.Equals, "volume-0")
+	c.Assert(results[0].Volume.Size, gc.Equals, uint64(1024))
+}
+
+func (s *lvmSuite) TestCreateVolumesFromSnapshotExisting(c *gc.C) {
+	source := s.lvmVolumeSource("pool0")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "--separator", ":", "-o", "lv_name,lv_size", "vg0").respond(
+		"  volume-1:1024.00\n", nil,
+	)
+	s.commands.expect("lvremove", "-f", "vg0/volume-1")
+	s.commands.expect("lvcreate", "-y", "-s", "-kn", "-n", "volume-1", "vg0/volume-0-snapshot-0-1")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "-o", "lv_size", "vg0/volume-1").respond("1024.00", nil)
+
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("1"),
+		Size:       1024,
+		SnapshotId: "volume-0-snapshot-0-1",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume.Size, gc.Equals, uint64(1024))
+}
+
+func (s *lvmSuite) TestCreateVolumesFromSnapshot(c *gc.C) {
+	source := s.lvmVolumeSource("")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "--separator", ":", "-o", "lv_name,lv_size", "vg0")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "-o", "lv_size", "vg0/volume-0-snapshot-0-1").respond("1024.00", nil)
+	s.commands.expect("lvcreate", "-y", "-n", "volume-1", "-L", "1024M", "vg0")
+	s.commands.expect("dd", "if=/dev/vg0/volume-0-snapshot-0-1", "of=/dev/vg0/volume-1", "bs=4M", "conv=fsync")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "-o", "lv_size", "vg0/volume-1").respond("1024.00", nil)
+	s.commands.expect("lvextend", "-L", "2048M", "vg0/volume-1")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "-o", "lv_size", "vg0/volume-1").respond("2048.00", nil)
+
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("1"),
+		Size:       2048,
+		SnapshotId: "volume-0-snapshot-0-1",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume.VolumeId, gc.Equals, "volume-1")
+	c.Assert(results[0].Volume.Size, gc.Equals, uint64(2048))
+}
+
+func (s *lvmSuite) TestCreateVolumesFromThinSnapshot(c *gc.C) {
+	source := s.lvmVolumeSource("pool0")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "--separator", ":", "-o", "lv_name,lv_size", "vg0")
+	s.commands.expect("lvcreate", "-y", "-s", "-kn", "-n", "volume-1", "vg0/volume-0-snapshot-0-1")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "-o", "lv_size", "vg0/volume-1").respond("1024.00", nil)
+
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("1"),
+		Size:       1024,
+		SnapshotId: "volume-0-snapshot-0-1",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume.Size, gc.Equals, uint64(1024))
+}
+
+func (s *lvmSuite) TestCreateVolumesFromSnapshotInvalidId(c *gc.C) {
+	source := s.lvmVolumeSource("")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "--separator", ":", "-o", "lv_name,lv_size", "vg0")
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("1"),
+		Size:       1024,
+		SnapshotId: "../vg1/volume-0",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches, `creating volume: could not restore snapshot: LVM snapshot ID "../vg1/volume-0" not valid`)
+}
+
+func (s *lvmSuite) TestListVolumes(c *gc.C) {
+	source := s.lvmVolumeSource("pool0")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "--separator", ":", "-o", "lv_name,lv_size", "vg0").respond(`
+  pool0:10240.00
+  root:20480.00
+  volume-0:1024.00
+  volume-0-snapshot-0-1:1024.00
+  volume-1:2048.00
+`, nil)
+
+	volumeIds, err := source.ListVolumes(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeIds, jc.SameContents, []string{"volume-0", "volume-1"})
+}
+
+func (s *lvmSuite) TestDescribeVolumes(c *gc.C) {
+	source := s.lvmVolumeSource("")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "--separator", ":", "-o", "lv_name,lv_size", "vg0").respond(
+		"  volume-0:1024.00\n", nil,
+	)
+
+	results, err := source.DescribeVolumes(s.callCtx, []string{"volume-0", "volume-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].VolumeInfo, jc.DeepEquals, &storage.VolumeInfo{
+		VolumeId: "volume-0",
+		Size:     1024,
+	})
+	c.Assert(results[1].Error, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *lvmSuite) TestDestroyVolumes(c *gc.C) {
+	source := s.lvmVolumeSource("")
+	s.commands.expect("lvremove", "-f", "vg0/volume-0")
+	s.commands.expect("lvremove", "-f", "vg0/volume-1").respond(
+		"", errors.New(`Failed to find logical volume "vg0/volume-1"`),
+	)
+	s.commands.expect("lvremove", "-f", "vg0/volume-2").respond(
+		"", errors.New(`Logical volume vg0/volume-2 in use.`),
+	)
+
+	errs, err := source.DestroyVolumes(s.callCtx, []string{"volume-0", "volume-1", "volume-2", "root"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, gc.HasLen, 4)
+	c.Assert(errs[0], jc.ErrorIsNil)
+	c.Assert(errs[1], jc.ErrorIsNil)
+	c.Assert(errs[2], gc.ErrorMatches, `destroying "volume-2": removing logical volume: Logical volume vg0/volume-2 in use.`)
+	c.Assert(errs[3], gc.ErrorMatches, `destroying "root": invalid LVM volume ID "root"`)
+}
+
+func (s *lvmSuite) TestAttachVolumes(c *gc.C) {
+	source := s.lvmVolumeSource("")
+	s.commands.expect("lvchange", "-ay", "-p", "rw", "vg0/volume-0")
+	s.commands.expect("lvchange", "-ay", "-p", "r", "vg0/volume-1")
+
+	results, err := source.AttachVolumes(s.callCtx, []storage.VolumeAttachmentParams{{
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "volume-0",
+		AttachmentParams: storage.AttachmentParams{
+			Machine: names.NewMachineTag("0"),
+		},
+	}, {
+		Volume:   names.NewVolumeTag("1"),
+		VolumeId: "volume-1",
+		AttachmentParams: storage.AttachmentParams{
+			Machine:  names.NewMachineTag("0"),
+			ReadOnly: true,
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.AttachVolumesResult{{
+		VolumeAttachment: &storage.VolumeAttachment{
+			names.NewVolumeTag("0"),
+			names.NewMachineTag("0"),
+			storage.VolumeAttachmentInfo{
+				DeviceLink: "/dev/vg0/volume-0",
+			},
+		},
+	}, {
+		VolumeAttachment: &storage.VolumeAttachment{
+			names.NewVolumeTag("1"),
+			names.NewMachineTag("0"),
+			storage.VolumeAttachmentInfo{
+				DeviceLink: "/dev/vg0/volume-1",
+				ReadOnly:   true,
+			},
+		},
+	}})
+}
+
+func (s *lvmSuite) TestDetachVolumes(c *gc.C) {
+	source := s.lvmVolumeSource("")
+	s.commands.expect("lvchange", "-an", "vg0/volume-0")
+
+	errs, err := source.DetachVolumes(s.callCtx, []storage.VolumeAttachmentParams{{
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "volume-0",
+		AttachmentParams: storage.AttachmentParams{
+			Machine: names.NewMachineTag("0"),
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil})
+}
+
+func (s *lvmSuite) TestResizeVolumes(c *gc.C) {
+	source := s.lvmVolumeSource("")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "-o", "lv_size", "vg0/volume-0").respond("1024.00", nil)
+	s.commands.expect("lvextend", "-L", "1500M", "vg0/volume-0")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "-o", "lv_size", "vg0/volume-0").respond("1500.00", nil)
+	// Shrinking is not supported, so the current size is reported.
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "-o", "lv_size", "vg0/volume-1").respond("2048.00", nil)
+
+	resizer := source.(storage.VolumeResizer)
+	results, err := resizer.ResizeVolumes(s.callCtx, []storage.VolumeResizeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		VolumeId: "volume-0",
+		Size:     1500,
+	}, {
+		Tag:      names.NewVolumeTag("1"),
+		VolumeId: "volume-1",
+		Size:     1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.ResizeResult{{Size: 1500}, {Size: 2048}})
+}
+
+func (s *lvmSuite) TestCreateVolumeSnapshots(c *gc.C) {
+	source := s.lvmVolumeSource("")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "-o", "lv_size", "vg0/volume-0").respond("1024.00", nil)
+	s.commands.expect("lvcreate", "-y", "-s", "-n", "volume-0-snapshot-0-1", "-L", "1024M", "vg0/volume-0")
+
+	snapshotter := source.(storage.VolumeSnapshotter)
+	results, err := snapshotter.CreateVolumeSnapshots(s.callCtx, []storage.VolumeSnapshotParams{{
+		Tag:      names.NewVolumeTag("0"),
+		VolumeId: "volume-0",
+		Snapshot: "0/1",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.CreateVolumeSnapshotsResult{{
+		SnapshotId: "volume-0-snapshot-0-1",
+		Size:       1024,
+	}})
+}
+
+func (s *lvmSuite) TestCreateVolumeSnapshotsThin(c *gc.C) {
+	source := s.lvmVolumeSource("pool0")
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m", "-o", "lv_size", "vg0/volume-0").respond("1024.00", nil)
+	s.commands.expect("lvcreate", "-y", "-s", "-n", "volume-0-snapshot-0-1", "vg0/volume-0")
+
+	snapshotter := source.(storage.VolumeSnapshotter)
+	results, err := snapshotter.CreateVolumeSnapshots(s.callCtx, []storage.VolumeSnapshotParams{{
+		Tag:      names.NewVolumeTag("0"),
+		VolumeId: "volume-0",
+		Snapshot: "0/1",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.CreateVolumeSnapshotsResult{{
+		SnapshotId: "volume-0-snapshot-0-1",
+		Size:       1024,
+	}})
+}
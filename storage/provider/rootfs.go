@@ -30,8 +30,9 @@ var (
 
 // ValidateConfig is defined on the Provider interface.
 func (p *rootfsProvider) ValidateConfig(cfg *storage.Config) error {
-	// Rootfs provider has no configuration.
-	return nil
+	// Rootfs provider has no configuration, and the
+	// root filesystem cannot be encrypted by Juju.
+	return storage.ValidateNoEncryption(cfg.Attrs(), "rootfs filesystems")
 }
 
 // validateFullConfig validates a fully-constructed storage config,
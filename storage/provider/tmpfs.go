@@ -32,8 +32,9 @@ var (
 
 // ValidateConfig is defined on the Provider interface.
 func (p *tmpfsProvider) ValidateConfig(cfg *storage.Config) error {
-	// Tmpfs provider has no configuration.
-	return nil
+	// Tmpfs provider has no configuration, and its
+	// filesystems are never written to disk.
+	return storage.ValidateNoEncryption(cfg.Attrs(), "tmpfs filesystems")
 }
 
 // validateFullConfig validates a fully-constructed storage config,
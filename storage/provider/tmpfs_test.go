@@ -74,6 +74,16 @@ func (s *tmpfsSuite) TestValidateConfig(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *tmpfsSuite) TestValidateConfigEncrypted(c *gc.C) {
+	p := s.tmpfsProvider(c)
+	cfg, err := storage.NewConfig("name", provider.TmpfsProviderType, map[string]interface{}{
+		"encrypted": true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = p.ValidateConfig(cfg)
+	c.Assert(err, gc.ErrorMatches, `"encrypted" for tmpfs filesystems not supported`)
+}
+
 func (s *tmpfsSuite) TestSupports(c *gc.C) {
 	p := s.tmpfsProvider(c)
 	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsFalse)
@@ -47,4 +47,7 @@ type StorageAttachmentInfo struct {
 	// for a filesystem-kind storage attachment, and the device path
 	// for a block-kind.
 	Location string
+
+	// Size is the size of the volume or filesystem in MiB.
+	Size uint64
 }
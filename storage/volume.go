@@ -41,6 +41,10 @@ type VolumeInfo struct {
 	// Persistent reflects whether the volume is destroyed with the
 	// machine to which it is attached.
 	Persistent bool
+
+	// Encrypted reflects whether the volume's contents are
+	// encrypted at rest.
+	Encrypted bool
 }
 
 // VolumeAttachment identifies and describes machine-specific volume
@@ -8,9 +8,11 @@ import (
 	"sort"
 	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"gopkg.in/juju/worker.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/storage"
 	jworker "github.com/juju/juju/worker"
 )
@@ -23,6 +25,10 @@ const (
 	// polling it is.
 	listBlockDevicesPeriod = time.Second * 30
 
+	// filesystemUsagePeriod is the minimum time period between reports
+	// of the usage of filesystems attached to the machine.
+	filesystemUsagePeriod = time.Minute * 5
+
 	// bytesInMiB is the number of bytes in a MiB.
 	bytesInMiB = 1024 * 1024
 )
@@ -41,12 +47,51 @@ type ListBlockDevicesFunc func() ([]storage.BlockDevice, error)
 // devices for the operating system of the local host.
 var DefaultListBlockDevices ListBlockDevicesFunc
 
-// NewWorker returns a worker that lists block devices
-// attached to the machine, and records them in state.
-var NewWorker = func(l ListBlockDevicesFunc, b BlockDeviceSetter) worker.Worker {
+// FilesystemUsageSetter is an interface that is supplied to NewWorker
+// for reporting the usage of filesystems attached to the local host.
+type FilesystemUsageSetter interface {
+	FilesystemAttachments() ([]params.FilesystemAttachment, error)
+	SetFilesystemUsage([]params.FilesystemAttachmentUsage) error
+}
+
+// FilesystemUsageFunc is the type of a function that returns the usage
+// of the filesystem mounted at the specified path. If no filesystem is
+// mounted at the path, an error satisfying errors.IsNotFound is returned.
+type FilesystemUsageFunc func(mountPoint string) (params.FilesystemUsage, error)
+
+// DefaultFilesystemUsage is the default function for getting filesystem
+// usage for the operating system of the local host.
+var DefaultFilesystemUsage FilesystemUsageFunc
+
+// NewWorker returns a worker that lists block devices attached to the
+// machine, and records them in state. If u is non-nil, the worker also
+// periodically reports the usage of filesystems attached to the machine,
+// and records it in the supplied metrics collector if that is non-nil.
+var NewWorker = func(
+	l ListBlockDevicesFunc,
+	b BlockDeviceSetter,
+	u FilesystemUsageSetter,
+	collector *Collector,
+) worker.Worker {
 	var old []storage.BlockDevice
+	var lastUsageReport time.Time
 	f := func(stop <-chan struct{}) error {
-		return doWork(l, b, &old)
+		if err := doWork(l, b, &old); err != nil {
+			return err
+		}
+		if u == nil || time.Since(lastUsageReport) < filesystemUsagePeriod {
+			return nil
+		}
+		err := reportFilesystemUsage(DefaultFilesystemUsage, u, collector)
+		if errors.IsNotSupported(err) {
+			logger.Infof("not reporting filesystem usage: %v", err)
+			u = nil
+			return nil
+		} else if err != nil {
+			return errors.Annotate(err, "reporting filesystem usage")
+		}
+		lastUsageReport = time.Now()
+		return nil
 	}
 	return jworker.NewPeriodicWorker(f, listBlockDevicesPeriod, jworker.NewTimer)
 }
@@ -71,3 +116,42 @@ func doWork(listf ListBlockDevicesFunc, b BlockDeviceSetter, old *[]storage.Bloc
 	*old = blockDevices
 	return nil
 }
+
+// reportFilesystemUsage reports the usage of the filesystems attached
+// and mounted on the machine. Filesystems that are not yet mounted are
+// skipped, and reported on a later pass.
+func reportFilesystemUsage(
+	usagef FilesystemUsageFunc,
+	u FilesystemUsageSetter,
+	collector *Collector,
+) error {
+	attachments, err := u.FilesystemAttachments()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var usage []params.FilesystemAttachmentUsage
+	for _, attachment := range attachments {
+		mountPoint := attachment.Info.MountPoint
+		fsUsage, err := usagef(mountPoint)
+		if errors.IsNotFound(err) {
+			logger.Debugf("%s is not mounted at %q yet", attachment.FilesystemTag, mountPoint)
+			continue
+		} else if errors.IsNotSupported(err) {
+			return errors.Trace(err)
+		} else if err != nil {
+			logger.Warningf("cannot get usage of filesystem mounted at %q: %v", mountPoint, err)
+			continue
+		}
+		usage = append(usage, params.FilesystemAttachmentUsage{
+			FilesystemTag: attachment.FilesystemTag,
+			Usage:         fsUsage,
+		})
+	}
+	if collector != nil {
+		collector.update(attachments, usage)
+	}
+	if len(usage) == 0 {
+		return nil
+	}
+	return errors.Trace(u.SetFilesystemUsage(usage))
+}
@@ -6,9 +6,13 @@ package diskmanager_test
 import (
 	"time"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/storage"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/diskmanager"
@@ -38,7 +42,7 @@ func (s *DiskManagerWorkerSuite) TestWorker(c *gc.C) {
 		return []storage.BlockDevice{{DeviceName: "whatever"}}, nil
 	}
 
-	w := diskmanager.NewWorker(listDevices, setDevices)
+	w := diskmanager.NewWorker(listDevices, setDevices, nil, nil)
 	defer w.Wait()
 	defer w.Kill()
 
@@ -116,6 +120,135 @@ func (s *DiskManagerWorkerSuite) TestBlockDevicesSorted(c *gc.C) {
 	}}})
 }
 
+func (s *DiskManagerWorkerSuite) TestWorkerReportsFilesystemUsage(c *gc.C) {
+	s.PatchValue(&diskmanager.DefaultFilesystemUsage, func(string) (params.FilesystemUsage, error) {
+		return params.FilesystemUsage{UsedBytes: 1, FreeBytes: 2}, nil
+	})
+	done := make(chan struct{})
+	setter := &mockFilesystemUsageSetter{
+		attachments: []params.FilesystemAttachment{{
+			FilesystemTag: "filesystem-0-0",
+			Info:          params.FilesystemAttachmentInfo{MountPoint: "/srv"},
+		}},
+		set: func([]params.FilesystemAttachmentUsage) error {
+			close(done)
+			return nil
+		},
+	}
+	var setDevices BlockDeviceSetterFunc = func([]storage.BlockDevice) error {
+		return nil
+	}
+	var listDevices diskmanager.ListBlockDevicesFunc = func() ([]storage.BlockDevice, error) {
+		return nil, nil
+	}
+
+	w := diskmanager.NewWorker(listDevices, setDevices, setter, nil)
+	defer w.Wait()
+	defer w.Kill()
+
+	select {
+	case <-done:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for diskmanager to report usage")
+	}
+}
+
+func (s *DiskManagerWorkerSuite) TestReportFilesystemUsage(c *gc.C) {
+	var usageSet [][]params.FilesystemAttachmentUsage
+	setter := &mockFilesystemUsageSetter{
+		attachments: []params.FilesystemAttachment{{
+			FilesystemTag: "filesystem-0-0",
+			Info:          params.FilesystemAttachmentInfo{MountPoint: "/srv/data"},
+		}, {
+			FilesystemTag: "filesystem-0-1",
+			Info:          params.FilesystemAttachmentInfo{MountPoint: "/srv/logs"},
+		}, {
+			FilesystemTag: "filesystem-0-2",
+			Info:          params.FilesystemAttachmentInfo{MountPoint: "/srv/broken"},
+		}},
+		set: func(usage []params.FilesystemAttachmentUsage) error {
+			usageSet = append(usageSet, usage)
+			return nil
+		},
+	}
+	usagef := func(mountPoint string) (params.FilesystemUsage, error) {
+		switch mountPoint {
+		case "/srv/data":
+			return params.FilesystemUsage{
+				UsedBytes:  100,
+				FreeBytes:  300,
+				UsedInodes: 10,
+				FreeInodes: 30,
+			}, nil
+		case "/srv/logs":
+			return params.FilesystemUsage{}, errors.NotFoundf("filesystem mounted at %q", mountPoint)
+		}
+		return params.FilesystemUsage{}, errors.New("boom")
+	}
+	collector := diskmanager.NewMetricsCollector()
+
+	err := diskmanager.ReportFilesystemUsage(usagef, setter, collector)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usageSet, jc.DeepEquals, [][]params.FilesystemAttachmentUsage{{{
+		FilesystemTag: "filesystem-0-0",
+		Usage: params.FilesystemUsage{
+			UsedBytes:  100,
+			FreeBytes:  300,
+			UsedInodes: 10,
+			FreeInodes: 30,
+		},
+	}}})
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		defer close(ch)
+		collector.Collect(ch)
+	}()
+	var values []float64
+	for metric := range ch {
+		var m dto.Metric
+		c.Assert(metric.Write(&m), jc.ErrorIsNil)
+		c.Assert(m.Label, gc.HasLen, 2)
+		c.Assert(m.Label[0].GetName(), gc.Equals, "filesystem")
+		c.Assert(m.Label[0].GetValue(), gc.Equals, "0/0")
+		c.Assert(m.Label[1].GetName(), gc.Equals, "mount_point")
+		c.Assert(m.Label[1].GetValue(), gc.Equals, "/srv/data")
+		values = append(values, m.Gauge.GetValue())
+	}
+	c.Assert(values, jc.DeepEquals, []float64{100, 300, 10, 30})
+}
+
+func (s *DiskManagerWorkerSuite) TestReportFilesystemUsageNotSupported(c *gc.C) {
+	setter := &mockFilesystemUsageSetter{
+		attachments: []params.FilesystemAttachment{{
+			FilesystemTag: "filesystem-0-0",
+			Info:          params.FilesystemAttachmentInfo{MountPoint: "/srv/data"},
+		}},
+		set: func([]params.FilesystemAttachmentUsage) error {
+			c.Fatalf("unexpected call to SetFilesystemUsage")
+			return nil
+		},
+	}
+	usagef := func(string) (params.FilesystemUsage, error) {
+		return params.FilesystemUsage{}, errors.NotSupportedf("filesystem usage")
+	}
+	err := diskmanager.ReportFilesystemUsage(usagef, setter, nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+type mockFilesystemUsageSetter struct {
+	attachments []params.FilesystemAttachment
+	set         func([]params.FilesystemAttachmentUsage) error
+}
+
+func (m *mockFilesystemUsageSetter) FilesystemAttachments() ([]params.FilesystemAttachment, error) {
+	return m.attachments, nil
+}
+
+func (m *mockFilesystemUsageSetter) SetFilesystemUsage(usage []params.FilesystemAttachmentUsage) error {
+	return m.set(usage)
+}
+
 type BlockDeviceSetterFunc func([]storage.BlockDevice) error
 
 func (f BlockDeviceSetterFunc) SetMachineBlockDevices(devices []storage.BlockDevice) error {
@@ -8,6 +8,9 @@ package diskmanager
 import (
 	"runtime"
 
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/storage"
 )
 
@@ -26,4 +29,9 @@ func init() {
 		runtime.GOOS,
 	)
 	DefaultListBlockDevices = listBlockDevices
+	DefaultFilesystemUsage = filesystemUsage
+}
+
+func filesystemUsage(string) (params.FilesystemUsage, error) {
+	return params.FilesystemUsage{}, errors.NotSupportedf("filesystem usage on %s", runtime.GOOS)
 }
@@ -2,6 +2,7 @@
 // Licensed under the AGPLv3, see LICENCE file for details.
 
 // Package diskmanager defines a worker that periodically lists block devices
-// on the machine it runs on. This worker will be run on all Juju-managed
+// on the machine it runs on, and reports the usage of the filesystems
+// attached to the machine. This worker will be run on all Juju-managed
 // machines (one per machine agent).
 package diskmanager
@@ -7,5 +7,7 @@ var (
 	ListBlockDevices = listBlockDevices
 	BlockDeviceInUse = &blockDeviceInUse
 	DoWork           = doWork
-	NewWorkerFunc    = newWorker
+	NewWorkerFunc    = ManifoldConfig{}.newWorker
+
+	ReportFilesystemUsage = reportFilesystemUsage
 )
@@ -0,0 +1,68 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build linux
+
+package diskmanager
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+func init() {
+	DefaultFilesystemUsage = filesystemUsage
+}
+
+// isMountPoint reports whether or not a filesystem is mounted at the
+// specified path, by comparing its device with that of its parent.
+func isMountPoint(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	parentInfo, err := os.Stat(filepath.Dir(path))
+	if err != nil {
+		return false, err
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return false, errors.Errorf("cannot determine device for %q", path)
+	}
+	parentStat, ok := parentInfo.Sys().(*syscall.Stat_t)
+	if !ok {
+		return false, errors.Errorf("cannot determine device for %q", filepath.Dir(path))
+	}
+	return stat.Dev != parentStat.Dev || stat.Ino == parentStat.Ino, nil
+}
+
+// filesystemUsage returns the space and inode usage of the filesystem
+// mounted at the specified path. Free space is that available to
+// unprivileged users, matching the output of df.
+func filesystemUsage(mountPoint string) (params.FilesystemUsage, error) {
+	mounted, err := isMountPoint(mountPoint)
+	if os.IsNotExist(err) {
+		return params.FilesystemUsage{}, errors.NotFoundf("mount point %q", mountPoint)
+	} else if err != nil {
+		return params.FilesystemUsage{}, errors.Trace(err)
+	}
+	if !mounted {
+		return params.FilesystemUsage{}, errors.NotFoundf("filesystem mounted at %q", mountPoint)
+	}
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(mountPoint, &st); err != nil {
+		return params.FilesystemUsage{}, errors.Annotatef(err, "getting usage of %q", mountPoint)
+	}
+	blockSize := uint64(st.Bsize)
+	return params.FilesystemUsage{
+		UsedBytes:  (st.Blocks - st.Bfree) * blockSize,
+		FreeBytes:  st.Bavail * blockSize,
+		UsedInodes: st.Files - st.Ffree,
+		FreeInodes: st.Ffree,
+	}, nil
+}
@@ -0,0 +1,38 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build linux
+
+package diskmanager_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/diskmanager"
+)
+
+var _ = gc.Suite(&FilesystemUsageSuite{})
+
+type FilesystemUsageSuite struct {
+	coretesting.BaseSuite
+}
+
+func (s *FilesystemUsageSuite) TestFilesystemUsage(c *gc.C) {
+	usage, err := diskmanager.DefaultFilesystemUsage("/")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage.UsedBytes+usage.FreeBytes, jc.GreaterThan, uint64(0))
+	c.Assert(usage.Updated, gc.IsNil)
+}
+
+func (s *FilesystemUsageSuite) TestFilesystemUsageNotMounted(c *gc.C) {
+	_, err := diskmanager.DefaultFilesystemUsage(c.MkDir())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *FilesystemUsageSuite) TestFilesystemUsageNotExist(c *gc.C) {
+	_, err := diskmanager.DefaultFilesystemUsage("/no/such/mount/point")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -17,6 +17,7 @@ import (
 	"syscall"
 
 	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/storage"
 )
@@ -26,9 +27,11 @@ var pairsRE = regexp.MustCompile(`([A-Z:]+)=(?:"(.*?)")`)
 const (
 	// values for the TYPE column that we care about
 
-	typeDisk = "disk"
-	typeLoop = "loop"
-	typePart = "part"
+	typeCrypt = "crypt"
+	typeDisk  = "disk"
+	typeLoop  = "loop"
+	typeLVM   = "lvm"
+	typePart  = "part"
 )
 
 func init() {
@@ -97,11 +100,16 @@ func listBlockDevices() ([]storage.BlockDevice, error) {
 			}
 		}
 
-		// We may later want to expand this, e.g. to handle lvm,
-		// dmraid, crypt, etc., but this is enough to cover bases
-		// for now.
+		// We may later want to expand this, e.g. to handle dmraid,
+		// but this is enough to cover bases for now. Logical volumes
+		// created by the lvm storage provider (see below), and LUKS
+		// mappings of volumes encrypted by the machine-scoped
+		// providers, are reported so they can be matched by device
+		// link.
 		switch deviceType {
+		case typeCrypt:
 		case typeLoop:
+		case typeLVM:
 		case typePart:
 		case typeDisk:
 			// Floppy disks, which have major device number 2,
@@ -131,12 +139,21 @@ func listBlockDevices() ([]storage.BlockDevice, error) {
 		}
 
 		// Add additional information from sysfs.
-		if err := addHardwareInfo(&dev); err != nil {
+		lvName, err := addHardwareInfo(&dev)
+		if err != nil {
 			logger.Errorf(
 				"error getting hardware info for %q from sysfs: %v",
 				dev.DeviceName, err,
 			)
 		}
+		if deviceType == typeLVM {
+			// The lvm storage provider names logical volumes after
+			// their volume tags; any others belong to the machine.
+			if _, err := names.ParseVolumeTag(lvName); err != nil {
+				logger.Tracef("ignoring logical volume %q: %+v", lvName, dev)
+				continue
+			}
+		}
 		devices = append(devices, dev)
 	}
 	if err := s.Err(); err != nil {
@@ -172,8 +189,9 @@ var blockDeviceInUse = func(dev storage.BlockDevice) (bool, error) {
 }
 
 // addHardwareInfo adds additional information about the hardware, and how it is
-// attached to the machine, to the given BlockDevice.
-func addHardwareInfo(dev *storage.BlockDevice) error {
+// attached to the machine, to the given BlockDevice. If the device is a
+// logical volume, its name is returned.
+func addHardwareInfo(dev *storage.BlockDevice) (string, error) {
 	logger.Tracef(`executing "udevadm info" for %s`, dev.DeviceName)
 	output, err := exec.Command(
 		"udevadm", "info",
@@ -185,10 +203,10 @@ func addHardwareInfo(dev *storage.BlockDevice) error {
 		if output := bytes.TrimSpace(output); len(output) > 0 {
 			msg += fmt.Sprintf(" (%s)", output)
 		}
-		return errors.Annotate(err, msg)
+		return "", errors.Annotate(err, msg)
 	}
 
-	var devpath, idBus, idSerial, wwnWithExtension string
+	var devpath, idBus, idSerial, wwnWithExtension, lvName string
 
 	s := bufio.NewScanner(bytes.NewReader(output))
 	for s.Scan() {
@@ -204,6 +222,8 @@ func addHardwareInfo(dev *storage.BlockDevice) error {
 			devpath = value
 		case "DEVLINKS":
 			dev.DeviceLinks = strings.Split(value, " ")
+		case "DM_LV_NAME":
+			lvName = value
 		case "ID_BUS":
 			idBus = value
 		case "ID_SERIAL":
@@ -217,7 +237,7 @@ func addHardwareInfo(dev *storage.BlockDevice) error {
 		}
 	}
 	if err := s.Err(); err != nil {
-		return errors.Annotate(err, "cannot parse udevadm output")
+		return "", errors.Annotate(err, "cannot parse udevadm output")
 	}
 
 	// For cases where there are logical disks attached to a
@@ -266,5 +286,5 @@ func addHardwareInfo(dev *storage.BlockDevice) error {
 		}
 	}
 
-	return nil
+	return lvName, nil
 }
@@ -129,6 +129,49 @@ DEVLINKS=/dev/disk/by-id/abc /dev/disk/by-id/def
 	})
 }
 
+func (s *ListBlockDevicesSuite) TestListBlockDevicesLogicalVolumes(c *gc.C) {
+	testing.PatchExecutable(c, s, "lsblk", `#!/bin/bash --norc
+cat <<EOF
+KNAME="dm-0" SIZE="1073741824" LABEL="" UUID="" TYPE="lvm"
+KNAME="dm-1" SIZE="1073741824" LABEL="" UUID="" TYPE="lvm"
+KNAME="dm-2" SIZE="1073741824" LABEL="" UUID="" TYPE="crypt"
+EOF`)
+	testing.PatchExecutable(c, s, "udevadm", `#!/bin/bash --norc
+case "$5" in
+dm-0)
+cat <<EOF
+DEVLINKS=/dev/mapper/vg0-volume--0 /dev/vg0/volume-0
+DM_VG_NAME=vg0
+DM_LV_NAME=volume-0
+EOF
+;;
+dm-1)
+cat <<EOF
+DEVLINKS=/dev/mapper/ubuntu--vg-root /dev/ubuntu-vg/root
+DM_VG_NAME=ubuntu-vg
+DM_LV_NAME=root
+EOF
+;;
+dm-2)
+cat <<EOF
+DEVLINKS=/dev/mapper/volume-1
+EOF
+;;
+esac`)
+
+	devices, err := diskmanager.ListBlockDevices()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(devices, jc.DeepEquals, []storage.BlockDevice{{
+		DeviceName:  "dm-0",
+		DeviceLinks: []string{"/dev/mapper/vg0-volume--0", "/dev/vg0/volume-0"},
+		Size:        1024,
+	}, {
+		DeviceName:  "dm-2",
+		DeviceLinks: []string{"/dev/mapper/volume-1"},
+		Size:        1024,
+	}})
+}
+
 func (s *ListBlockDevicesSuite) TestListBlockDevicesAll(c *gc.C) {
 	s.testListBlockDevicesExtended(c, `
 DEVPATH=/a/b/c/d/1:2:3:4/block/sda
@@ -5,6 +5,7 @@ package diskmanager
 
 import (
 	"github.com/juju/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	"gopkg.in/juju/names.v3"
 	"gopkg.in/juju/worker.v1"
 	"gopkg.in/juju/worker.v1/dependency"
@@ -13,20 +14,31 @@ import (
 	"github.com/juju/juju/api/base"
 	apidiskmanager "github.com/juju/juju/api/diskmanager"
 	"github.com/juju/juju/cmd/jujud/agent/engine"
+	"github.com/juju/juju/worker/common"
 )
 
 // ManifoldConfig defines the names of the manifolds on which a Manifold will depend.
-type ManifoldConfig engine.AgentAPIManifoldConfig
+type ManifoldConfig struct {
+	AgentName     string
+	APICallerName string
+
+	// PrometheusRegisterer, if non-nil, is used to register the
+	// collector of filesystem usage metrics.
+	PrometheusRegisterer prometheus.Registerer
+}
 
 // Manifold returns a dependency manifold that runs a diskmanager worker,
 // using the resource names defined in the supplied config.
 func Manifold(config ManifoldConfig) dependency.Manifold {
-	typedConfig := engine.AgentAPIManifoldConfig(config)
-	return engine.AgentAPIManifold(typedConfig, newWorker)
+	typedConfig := engine.AgentAPIManifoldConfig{
+		AgentName:     config.AgentName,
+		APICallerName: config.APICallerName,
+	}
+	return engine.AgentAPIManifold(typedConfig, config.newWorker)
 }
 
 // newWorker trivially wraps NewWorker for use in a engine.AgentAPIManifold.
-func newWorker(a agent.Agent, apiCaller base.APICaller) (worker.Worker, error) {
+func (config ManifoldConfig) newWorker(a agent.Agent, apiCaller base.APICaller) (worker.Worker, error) {
 	t := a.CurrentConfig().Tag()
 	tag, ok := t.(names.MachineTag)
 	if !ok {
@@ -35,5 +47,15 @@ func newWorker(a agent.Agent, apiCaller base.APICaller) (worker.Worker, error) {
 
 	api := apidiskmanager.NewState(apiCaller, tag)
 
-	return NewWorker(DefaultListBlockDevices, api), nil
+	if config.PrometheusRegisterer == nil {
+		return NewWorker(DefaultListBlockDevices, api, api, nil), nil
+	}
+	collector := NewMetricsCollector()
+	if err := config.PrometheusRegisterer.Register(collector); err != nil {
+		return nil, errors.Annotate(err, "registering filesystem usage metrics")
+	}
+	w := NewWorker(DefaultListBlockDevices, api, api, collector)
+	return common.NewCleanupWorker(w, func() {
+		config.PrometheusRegisterer.Unregister(collector)
+	}), nil
 }
@@ -39,7 +39,12 @@ func (s *manifoldSuite) TestMachineDiskmanager(c *gc.C) {
 			return nil
 		})
 
-	s.PatchValue(&diskmanager.NewWorker, func(l diskmanager.ListBlockDevicesFunc, b diskmanager.BlockDeviceSetter) worker.Worker {
+	s.PatchValue(&diskmanager.NewWorker, func(
+		l diskmanager.ListBlockDevicesFunc,
+		b diskmanager.BlockDeviceSetter,
+		u diskmanager.FilesystemUsageSetter,
+		collector *diskmanager.Collector,
+	) worker.Worker {
 		called = true
 
 		c.Assert(l, gc.FitsTypeOf, diskmanager.DefaultListBlockDevices)
@@ -48,6 +53,8 @@ func (s *manifoldSuite) TestMachineDiskmanager(c *gc.C) {
 		api, ok := b.(*apidiskmanager.State)
 		c.Assert(ok, jc.IsTrue)
 		c.Assert(api, gc.NotNil)
+		c.Assert(u, gc.Equals, api)
+		c.Assert(collector, gc.IsNil)
 
 		return nil
 	})
@@ -0,0 +1,107 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package diskmanager
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+const (
+	metricsNamespace = "juju"
+	metricsSubsystem = "filesystem"
+
+	filesystemLabel = "filesystem"
+	mountPointLabel = "mount_point"
+)
+
+var filesystemLabelNames = []string{
+	filesystemLabel,
+	mountPointLabel,
+}
+
+// Collector is a prometheus.Collector that exposes the most recently
+// reported usage of the filesystems attached to the machine.
+type Collector struct {
+	mu sync.Mutex
+
+	usedBytes  *prometheus.GaugeVec
+	freeBytes  *prometheus.GaugeVec
+	usedInodes *prometheus.GaugeVec
+	freeInodes *prometheus.GaugeVec
+}
+
+// NewMetricsCollector returns a new Collector.
+func NewMetricsCollector() *Collector {
+	newGaugeVec := func(name, help string) *prometheus.GaugeVec {
+		return prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      name,
+			Help:      help,
+		}, filesystemLabelNames)
+	}
+	return &Collector{
+		usedBytes:  newGaugeVec("used_bytes", "Number of bytes used on the filesystem."),
+		freeBytes:  newGaugeVec("free_bytes", "Number of bytes available on the filesystem."),
+		usedInodes: newGaugeVec("used_inodes", "Number of inodes used on the filesystem."),
+		freeInodes: newGaugeVec("free_inodes", "Number of inodes available on the filesystem."),
+	}
+}
+
+// Describe is part of the prometheus.Collector interface.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.usedBytes.Describe(ch)
+	c.freeBytes.Describe(ch)
+	c.usedInodes.Describe(ch)
+	c.freeInodes.Describe(ch)
+}
+
+// Collect is part of the prometheus.Collector interface.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.usedBytes.Collect(ch)
+	c.freeBytes.Collect(ch)
+	c.usedInodes.Collect(ch)
+	c.freeInodes.Collect(ch)
+}
+
+// update replaces the gauges' values with the supplied usage of the
+// filesystem attachments, so that filesystems no longer attached to
+// the machine are no longer reported.
+func (c *Collector) update(
+	attachments []params.FilesystemAttachment,
+	usage []params.FilesystemAttachmentUsage,
+) {
+	mountPoints := make(map[string]string)
+	for _, attachment := range attachments {
+		mountPoints[attachment.FilesystemTag] = attachment.Info.MountPoint
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.usedBytes.Reset()
+	c.freeBytes.Reset()
+	c.usedInodes.Reset()
+	c.freeInodes.Reset()
+	for _, u := range usage {
+		filesystem := u.FilesystemTag
+		if tag, err := names.ParseFilesystemTag(filesystem); err == nil {
+			filesystem = tag.Id()
+		}
+		labels := prometheus.Labels{
+			filesystemLabel: filesystem,
+			mountPointLabel: mountPoints[u.FilesystemTag],
+		}
+		c.usedBytes.With(labels).Set(float64(u.Usage.UsedBytes))
+		c.freeBytes.With(labels).Set(float64(u.Usage.FreeBytes))
+		c.usedInodes.With(labels).Set(float64(u.Usage.UsedInodes))
+		c.freeInodes.With(labels).Set(float64(u.Usage.FreeInodes))
+	}
+}
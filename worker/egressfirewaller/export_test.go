@@ -0,0 +1,9 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package egressfirewaller
+
+var (
+	ParseResolvers  = resolvers
+	ParseNTPServers = ntpServers
+)
@@ -0,0 +1,180 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package egressfirewaller
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/network"
+)
+
+var (
+	// resolvConfPaths holds the resolv.conf files naming the machine's
+	// resolvers. When systemd-resolved is in use, /etc/resolv.conf
+	// names the local stub resolver, and the upstream resolvers are
+	// in the second file.
+	resolvConfPaths = []string{
+		"/etc/resolv.conf",
+		"/run/systemd/resolve/resolv.conf",
+	}
+
+	// timesyncdConfGlobs match the systemd-timesyncd configuration
+	// files, which name NTP servers with NTP= and FallbackNTP=.
+	timesyncdConfGlobs = []string{
+		"/etc/systemd/timesyncd.conf",
+		"/etc/systemd/timesyncd.conf.d/*.conf",
+	}
+
+	// ntpConfGlobs match the chrony and ntpd configuration files,
+	// which name NTP servers with server and pool directives.
+	ntpConfGlobs = []string{
+		"/etc/chrony/chrony.conf",
+		"/etc/chrony/sources.d/*.sources",
+		"/etc/ntp.conf",
+	}
+)
+
+// defaultNTPServer is used by systemd-timesyncd on Ubuntu when
+// no NTP server is configured.
+const defaultNTPServer = "ntp.ubuntu.com"
+
+// Resolvers returns the IP addresses of the machine's DNS resolvers.
+func Resolvers() ([]string, error) {
+	return resolvers(resolvConfPaths)
+}
+
+// NTPServers returns the IP addresses of the machine's NTP servers,
+// resolving the names of those configured by name.
+func NTPServers() ([]string, error) {
+	return ntpServers(timesyncdConfGlobs, ntpConfGlobs, net.LookupHost)
+}
+
+func resolvers(paths []string) ([]string, error) {
+	var addrs []string
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		config, err := network.ParseResolvConf(path)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if config == nil {
+			continue
+		}
+		for _, ns := range config.Nameservers {
+			ip := net.ParseIP(ns.Value)
+			if ip == nil || ip.IsLoopback() || seen[ns.Value] {
+				// Loopback traffic is always allowed.
+				continue
+			}
+			seen[ns.Value] = true
+			addrs = append(addrs, ns.Value)
+		}
+	}
+	return addrs, nil
+}
+
+func ntpServers(timesyncdGlobs, ntpGlobs []string, lookupHost func(string) ([]string, error)) ([]string, error) {
+	var names []string
+	for _, glob := range timesyncdGlobs {
+		found, err := parseConfFiles(glob, timesyncdServers)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		names = append(names, found...)
+	}
+	for _, glob := range ntpGlobs {
+		found, err := parseConfFiles(glob, ntpConfServers)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		names = append(names, found...)
+	}
+	if len(names) == 0 {
+		names = []string{defaultNTPServer}
+	}
+
+	var addrs []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		resolved := []string{name}
+		if net.ParseIP(name) == nil {
+			var err error
+			if resolved, err = lookupHost(name); err != nil {
+				logger.Warningf("cannot resolve NTP server %q: %v", name, err)
+				continue
+			}
+		}
+		for _, addr := range resolved {
+			if !seen[addr] {
+				seen[addr] = true
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+	return addrs, nil
+}
+
+// parseConfFiles returns the servers found by parse in the files
+// matching glob.
+func parseConfFiles(glob string, parse func(line string) []string) ([]string, error) {
+	paths, err := filepath.Glob(glob)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var servers []string
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+				continue
+			}
+			servers = append(servers, parse(line)...)
+		}
+		err = scanner.Err()
+		file.Close()
+		if err != nil {
+			return nil, errors.Annotatef(err, "reading %q", path)
+		}
+	}
+	return servers, nil
+}
+
+// timesyncdServers parses the NTP= and FallbackNTP= settings, which
+// hold space separated server names.
+func timesyncdServers(line string) []string {
+	parts := strings.SplitN(line, "=", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+	switch strings.TrimSpace(parts[0]) {
+	case "NTP", "FallbackNTP":
+		return strings.Fields(parts[1])
+	}
+	return nil
+}
+
+// ntpConfServers parses the server and pool directives of chrony and
+// ntpd, which are followed by the server name and options.
+func ntpConfServers(line string) []string {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return nil
+	}
+	switch fields[0] {
+	case "server", "pool", "peer":
+		return fields[1:2]
+	}
+	return nil
+}
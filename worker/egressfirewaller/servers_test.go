@@ -0,0 +1,97 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package egressfirewaller_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/egressfirewaller"
+)
+
+type serversSuite struct {
+	testing.IsolationSuite
+	dir string
+}
+
+var _ = gc.Suite(&serversSuite{})
+
+func (s *serversSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+}
+
+func (s *serversSuite) writeFile(c *gc.C, name, content string) string {
+	path := filepath.Join(s.dir, name)
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+func (s *serversSuite) TestResolvers(c *gc.C) {
+	stub := s.writeFile(c, "resolv.conf", "nameserver 127.0.0.53\nsearch example.com\n")
+	upstream := s.writeFile(c, "upstream.conf", "nameserver 10.0.0.2\nnameserver fd00::2\nnameserver 10.0.0.2\n")
+	missing := filepath.Join(s.dir, "missing.conf")
+
+	addrs, err := egressfirewaller.ParseResolvers([]string{stub, upstream, missing})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addrs, jc.DeepEquals, []string{"10.0.0.2", "fd00::2"})
+}
+
+func (s *serversSuite) TestNTPServers(c *gc.C) {
+	timesyncd := s.writeFile(c, "timesyncd.conf", `
+[Time]
+#NTP=commented.example.com
+NTP=10.0.0.3 ntp.example.com
+FallbackNTP=ntp.example.com
+`)
+	chrony := s.writeFile(c, "chrony.conf", `
+# Use the local server.
+pool pool.example.com iburst maxsources 4
+server 10.0.0.4 iburst
+driftfile /var/lib/chrony/chrony.drift
+`)
+	lookup := func(name string) ([]string, error) {
+		switch name {
+		case "ntp.example.com":
+			return []string{"10.0.0.5"}, nil
+		case "pool.example.com":
+			return []string{"10.0.0.6", "10.0.0.7"}, nil
+		}
+		return nil, errors.NotFoundf("host %q", name)
+	}
+
+	addrs, err := egressfirewaller.ParseNTPServers([]string{timesyncd}, []string{chrony}, lookup)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addrs, jc.DeepEquals, []string{"10.0.0.3", "10.0.0.5", "10.0.0.6", "10.0.0.7", "10.0.0.4"})
+}
+
+func (s *serversSuite) TestNTPServersDefault(c *gc.C) {
+	var looked []string
+	lookup := func(name string) ([]string, error) {
+		looked = append(looked, name)
+		return []string{"10.0.0.8"}, nil
+	}
+	addrs, err := egressfirewaller.ParseNTPServers(
+		[]string{filepath.Join(s.dir, "missing.conf")}, nil, lookup,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addrs, jc.DeepEquals, []string{"10.0.0.8"})
+	c.Assert(looked, jc.DeepEquals, []string{"ntp.ubuntu.com"})
+}
+
+func (s *serversSuite) TestNTPServersUnresolved(c *gc.C) {
+	timesyncd := s.writeFile(c, "timesyncd.conf", "NTP=unknown.example.com 10.0.0.3\n")
+	lookup := func(name string) ([]string, error) {
+		return nil, errors.NotFoundf("host %q", name)
+	}
+	addrs, err := egressfirewaller.ParseNTPServers([]string{timesyncd}, nil, lookup)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addrs, jc.DeepEquals, []string{"10.0.0.3"})
+}
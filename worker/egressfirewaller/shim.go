@@ -0,0 +1,37 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package egressfirewaller
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/utils/exec"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/base"
+	apiegressfirewaller "github.com/juju/juju/api/egressfirewaller"
+)
+
+func NewFacade(apiCaller base.APICaller) (Facade, error) {
+	return apiegressfirewaller.NewFacade(apiCaller), nil
+}
+
+func NewWorker(config Config) (worker.Worker, error) {
+	worker, err := New(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return worker, nil
+}
+
+// RunCommand runs the given command with bash.
+func RunCommand(command string) error {
+	result, err := exec.RunCommands(exec.RunParams{Commands: command})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if result.Code != 0 {
+		return errors.Errorf("command failed with code %d: %s", result.Code, result.Stderr)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package egressfirewaller provides a worker which restricts egress
+// from its machine with iptables rules, when the machine's egress is
+// restricted and the provider does not restrict it instead.
+package egressfirewaller
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/network/iptables"
+)
+
+var logger = loggo.GetLogger("juju.worker.egressfirewaller")
+
+// Facade exposes controller functionality to a Worker.
+type Facade interface {
+	WatchEgressRules(tag names.MachineTag) (watcher.NotifyWatcher, error)
+	EgressRules(tag names.MachineTag) (params.MachineEgressRulesResult, error)
+}
+
+// Config defines the parameters of the egressfirewaller worker.
+type Config struct {
+	Facade     Facade
+	MachineTag names.MachineTag
+
+	// RunCommand runs the given bash command, returning an
+	// error if it fails.
+	RunCommand func(string) error
+
+	// Resolvers returns the IP addresses of the machine's DNS
+	// resolvers, which are always allowed.
+	Resolvers func() ([]string, error)
+
+	// NTPServers returns the IP addresses of the machine's NTP
+	// servers, which are always allowed.
+	NTPServers func() ([]string, error)
+}
+
+// Validate returns an error if Config cannot drive an egressfirewaller.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.MachineTag.Id() == "" {
+		return errors.NotValidf("empty MachineTag")
+	}
+	if config.RunCommand == nil {
+		return errors.NotValidf("nil RunCommand")
+	}
+	if config.Resolvers == nil {
+		return errors.NotValidf("nil Resolvers")
+	}
+	if config.NTPServers == nil {
+		return errors.NotValidf("nil NTPServers")
+	}
+	return nil
+}
+
+// New returns a Worker backed by config, or an error.
+func New(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w, err := watcher.NewNotifyWorker(watcher.NotifyConfig{
+		Handler: &egressFirewaller{config: config},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// egressFirewaller keeps the machine's iptables egress rules in
+// line with the machine's egress rules.
+type egressFirewaller struct {
+	config Config
+
+	// applied holds the command last run successfully, so
+	// the rules are only replaced when they change.
+	applied string
+}
+
+// SetUp is part of the watcher.NotifyHandler interface.
+func (f *egressFirewaller) SetUp() (watcher.NotifyWatcher, error) {
+	return f.config.Facade.WatchEgressRules(f.config.MachineTag)
+}
+
+// Handle is part of the watcher.NotifyHandler interface. The
+// restriction is always applied, or removed, when the worker starts,
+// as the rules may have changed while it was not running.
+func (f *egressFirewaller) Handle(_ <-chan struct{}) error {
+	result, err := f.config.Facade.EgressRules(f.config.MachineTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	command := iptables.EgressRulesCommand{Remove: true}
+	if result.Restricted && !result.ProviderEnforced {
+		rules, err := egressRules(result.Rules)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		resolvers, err := f.config.Resolvers()
+		if err != nil {
+			return errors.Annotate(err, "cannot find DNS resolvers")
+		}
+		ntpServers, err := f.config.NTPServers()
+		if err != nil {
+			return errors.Annotate(err, "cannot find NTP servers")
+		}
+		command = iptables.EgressRulesCommand{
+			Rules:      rules,
+			Resolvers:  resolvers,
+			NTPServers: ntpServers,
+		}
+	}
+	rendered := command.Render()
+	if rendered == f.applied {
+		return nil
+	}
+	if err := f.config.RunCommand(rendered); err != nil {
+		return errors.Annotate(err, "cannot update egress rules")
+	}
+	f.applied = rendered
+	if command.Remove {
+		logger.Infof("removed iptables egress restriction")
+	} else {
+		logger.Infof("restricted egress to %v", command.Rules)
+	}
+	return nil
+}
+
+// TearDown is part of the watcher.NotifyHandler interface.
+func (f *egressFirewaller) TearDown() error {
+	return nil
+}
+
+// egressRules returns the sorted egress rules for the given
+// machine egress rules.
+func egressRules(paramsRules []params.EgressRule) ([]network.EgressRule, error) {
+	var rules []network.EgressRule
+	for _, r := range paramsRules {
+		rule, err := network.NewEgressRule(r.PortRange.Protocol, r.PortRange.FromPort, r.PortRange.ToPort, r.DestinationCIDR)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		rules = append(rules, rule)
+	}
+	network.SortEgressRules(rules)
+	return rules, nil
+}
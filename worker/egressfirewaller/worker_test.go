@@ -0,0 +1,169 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package egressfirewaller_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/juju/worker.v1/workertest"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/core/watcher/watchertest"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/network/iptables"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/egressfirewaller"
+)
+
+type workerSuite struct {
+	testing.IsolationSuite
+
+	facade   *mockFacade
+	changes  chan struct{}
+	commands chan string
+}
+
+var _ = gc.Suite(&workerSuite{})
+
+var postgresRule = params.EgressRule{
+	DestinationCIDR: "10.0.0.0/8",
+	PortRange:       params.PortRange{FromPort: 5432, ToPort: 5432, Protocol: "tcp"},
+}
+
+func (s *workerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.changes = make(chan struct{}, 1)
+	s.commands = make(chan string, 10)
+	s.facade = &mockFacade{
+		watcher: watchertest.NewMockNotifyWatcher(s.changes),
+	}
+}
+
+func (s *workerSuite) startWorker(c *gc.C) (func(), error) {
+	w, err := egressfirewaller.New(egressfirewaller.Config{
+		Facade:     s.facade,
+		MachineTag: names.NewMachineTag("0"),
+		RunCommand: func(command string) error {
+			s.commands <- command
+			return s.facade.NextErr()
+		},
+		Resolvers:  func() ([]string, error) { return []string{"10.0.0.2"}, nil },
+		NTPServers: func() ([]string, error) { return []string{"10.0.0.3"}, nil },
+	})
+	if err != nil {
+		return nil, err
+	}
+	return func() { workertest.CleanKill(c, w) }, nil
+}
+
+func (s *workerSuite) assertCommand(c *gc.C, expected string) {
+	select {
+	case command := <-s.commands:
+		c.Assert(command, gc.Equals, expected)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for command")
+	}
+}
+
+func (s *workerSuite) assertNoCommand(c *gc.C) {
+	select {
+	case command := <-s.commands:
+		c.Fatalf("unexpected command %q", command)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *workerSuite) TestValidate(c *gc.C) {
+	_, err := egressfirewaller.New(egressfirewaller.Config{})
+	c.Assert(err, gc.ErrorMatches, "nil Facade not valid")
+}
+
+func (s *workerSuite) TestRestricts(c *gc.C) {
+	s.facade.setResult(params.MachineEgressRulesResult{
+		Restricted: true,
+		Rules:      []params.EgressRule{postgresRule},
+	})
+	s.changes <- struct{}{}
+	kill, err := s.startWorker(c)
+	c.Assert(err, jc.ErrorIsNil)
+	defer kill()
+
+	s.assertCommand(c, iptables.EgressRulesCommand{
+		Rules:      []network.EgressRule{network.MustNewEgressRule("tcp", 5432, 5432, "10.0.0.0/8")},
+		Resolvers:  []string{"10.0.0.2"},
+		NTPServers: []string{"10.0.0.3"},
+	}.Render())
+
+	// Unchanged rules are not applied again.
+	s.changes <- struct{}{}
+	s.assertNoCommand(c)
+
+	s.facade.setResult(params.MachineEgressRulesResult{})
+	s.changes <- struct{}{}
+	s.assertCommand(c, iptables.EgressRulesCommand{Remove: true}.Render())
+}
+
+func (s *workerSuite) TestProviderEnforced(c *gc.C) {
+	s.facade.setResult(params.MachineEgressRulesResult{
+		Restricted:       true,
+		ProviderEnforced: true,
+		Rules:            []params.EgressRule{postgresRule},
+	})
+	s.changes <- struct{}{}
+	kill, err := s.startWorker(c)
+	c.Assert(err, jc.ErrorIsNil)
+	defer kill()
+
+	// Any restriction left from before is removed.
+	s.assertCommand(c, iptables.EgressRulesCommand{Remove: true}.Render())
+}
+
+func (s *workerSuite) TestCommandError(c *gc.C) {
+	s.facade.SetErrors(errors.New("boom"))
+	s.changes <- struct{}{}
+	w, err := egressfirewaller.New(egressfirewaller.Config{
+		Facade:     s.facade,
+		MachineTag: names.NewMachineTag("0"),
+		RunCommand: func(string) error { return s.facade.NextErr() },
+		Resolvers:  func() ([]string, error) { return nil, nil },
+		NTPServers: func() ([]string, error) { return nil, nil },
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "cannot update egress rules: boom")
+}
+
+type mockFacade struct {
+	testing.Stub
+
+	watcher watcher.NotifyWatcher
+
+	mu     sync.Mutex
+	result params.MachineEgressRulesResult
+}
+
+func (f *mockFacade) setResult(result params.MachineEgressRulesResult) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.result = result
+}
+
+func (f *mockFacade) WatchEgressRules(tag names.MachineTag) (watcher.NotifyWatcher, error) {
+	f.MethodCall(f, "WatchEgressRules", tag)
+	return f.watcher, nil
+}
+
+func (f *mockFacade) EgressRules(tag names.MachineTag) (params.MachineEgressRulesResult, error) {
+	f.MethodCall(f, "EgressRules", tag)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.result, nil
+}
@@ -5,6 +5,7 @@ package firewaller
 
 import (
 	"io"
+	"reflect"
 	"strings"
 	"time"
 
@@ -47,6 +48,11 @@ type FirewallerAPI interface {
 	MacaroonForRelation(relationKey string) (*macaroon.Macaroon, error)
 	SetRelationStatus(relationKey string, status relation.Status, message string) error
 	FirewallRules(applicationNames ...string) ([]params.FirewallRule, error)
+	WatchFirewallRules() (watcher.NotifyWatcher, error)
+	ModelIngressRules() ([]params.FirewallRule, error)
+	WatchEgressRules() (watcher.NotifyWatcher, error)
+	MachineEgressRules(tag names.MachineTag) ([]params.EgressRule, bool, error)
+	SetMachineEgressEnforcedByProvider(tag names.MachineTag, enforced bool) error
 }
 
 // CrossModelFirewallerFacade exposes firewaller functionality on the
@@ -86,6 +92,11 @@ type Config struct {
 	EnvironFirewaller  EnvironFirewaller
 	EnvironInstances   EnvironInstances
 
+	// EnvironEgressFirewaller, if set, is used to restrict egress
+	// from instances in instance mode. Otherwise egress is restricted
+	// by the machine agents.
+	EnvironEgressFirewaller environs.EgressFirewaller
+
 	NewCrossModelFacadeFunc newCrossModelFacadeFunc
 
 	Clock  clock.Clock
@@ -134,9 +145,14 @@ type Firewaller struct {
 	remoteRelationsApi *remoterelations.Client
 	environFirewaller  EnvironFirewaller
 	environInstances   EnvironInstances
+	egressFirewaller   environs.EgressFirewaller
 
 	machinesWatcher      watcher.StringsWatcher
 	portsWatcher         watcher.StringsWatcher
+	rulesWatcher         watcher.NotifyWatcher
+	egressWatcher        watcher.NotifyWatcher
+	modelIngressRules    []params.FirewallRule
+	unprovisioned        map[names.MachineTag]bool
 	machineds            map[names.MachineTag]*machineData
 	unitsChange          chan *unitsChange
 	unitds               map[names.UnitTag]*unitData
@@ -175,6 +191,7 @@ func NewFirewaller(cfg Config) (worker.Worker, error) {
 		newRemoteFirewallerAPIFunc: cfg.NewCrossModelFacadeFunc,
 		modelUUID:                  cfg.ModelUUID,
 		machineds:                  make(map[names.MachineTag]*machineData),
+		unprovisioned:              make(map[names.MachineTag]bool),
 		unitsChange:                make(chan *unitsChange),
 		unitds:                     make(map[names.UnitTag]*unitData),
 		applicationids:             make(map[names.ApplicationTag]*applicationData),
@@ -198,6 +215,7 @@ func NewFirewaller(cfg Config) (worker.Worker, error) {
 
 	switch cfg.Mode {
 	case config.FwInstance:
+		fw.egressFirewaller = cfg.EnvironEgressFirewaller
 	case config.FwGlobal:
 		fw.globalMode = true
 		fw.globalIngressRuleRef = make(map[string]int)
@@ -234,6 +252,29 @@ func (fw *Firewaller) setUp() error {
 		return errors.Trace(err)
 	}
 
+	fw.rulesWatcher, err = fw.firewallerApi.WatchFirewallRules()
+	if errors.IsNotSupported(err) {
+		// The controller is too old to support model ingress rules.
+		fw.logger.Debugf("model ingress rules not supported: %v", err)
+	} else if err != nil {
+		return errors.Annotatef(err, "failed to start firewall rules watcher")
+	} else if err := fw.catacomb.Add(fw.rulesWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
+	if fw.egressFirewaller != nil {
+		fw.egressWatcher, err = fw.firewallerApi.WatchEgressRules()
+		if errors.IsNotSupported(err) {
+			// The controller is too old to support egress rules.
+			fw.logger.Debugf("egress rules not supported: %v", err)
+			fw.egressFirewaller = nil
+		} else if err != nil {
+			return errors.Annotatef(err, "failed to start egress rules watcher")
+		} else if err := fw.catacomb.Add(fw.egressWatcher); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	fw.remoteRelationsWatcher, err = fw.remoteRelationsApi.WatchRemoteRelations()
 	if err != nil {
 		return errors.Trace(err)
@@ -252,7 +293,21 @@ func (fw *Firewaller) loop() error {
 	}
 	var reconciled bool
 	portsChange := fw.portsWatcher.Changes()
+	var rulesChange watcher.NotifyChannel
+	if fw.rulesWatcher != nil {
+		rulesChange = fw.rulesWatcher.Changes()
+	}
+	var egressChange watcher.NotifyChannel
+	if fw.egressWatcher != nil {
+		egressChange = fw.egressWatcher.Changes()
+	}
+	// retryUnprovisioned is only set when a retry is not already
+	// pending, so that other events do not keep postponing it.
+	var retryUnprovisioned <-chan time.Time
 	for {
+		if retryUnprovisioned == nil && len(fw.unprovisioned) > 0 {
+			retryUnprovisioned = fw.pollClock.After(unprovisionedRetryDelay)
+		}
 		select {
 		case <-fw.catacomb.Dying():
 			return fw.catacomb.ErrDying()
@@ -290,6 +345,25 @@ func (fw *Firewaller) loop() error {
 					return errors.Trace(err)
 				}
 			}
+		case _, ok := <-rulesChange:
+			if !ok {
+				return errors.New("firewall rules watcher closed")
+			}
+			if err := fw.modelIngressRulesChanged(); err != nil {
+				return errors.Trace(err)
+			}
+		case _, ok := <-egressChange:
+			if !ok {
+				return errors.New("egress rules watcher closed")
+			}
+			if err := fw.egressRulesChanged(); err != nil {
+				return errors.Trace(err)
+			}
+		case <-retryUnprovisioned:
+			retryUnprovisioned = nil
+			if err := fw.flushUnprovisioned(); err != nil {
+				return errors.Trace(err)
+			}
 		case change, ok := <-fw.remoteRelationsWatcher.Changes():
 			if !ok {
 				return errors.New("remote relations watcher closed")
@@ -323,6 +397,51 @@ func (fw *Firewaller) loop() error {
 	}
 }
 
+// modelIngressRulesChanged reads the model's ingress rules and
+// flushes every machine they may apply to.
+func (fw *Firewaller) modelIngressRulesChanged() error {
+	rules, err := fw.firewallerApi.ModelIngressRules()
+	if err != nil {
+		return errors.Annotate(err, "cannot read model ingress rules")
+	}
+	fw.modelIngressRules = rules
+	for _, machined := range fw.machineds {
+		if err := fw.flushMachine(machined); err != nil {
+			return errors.Annotate(err, "cannot change firewall ports")
+		}
+	}
+	return nil
+}
+
+// egressRulesChanged flushes the egress rules of every machine.
+func (fw *Firewaller) egressRulesChanged() error {
+	for _, machined := range fw.machineds {
+		err := fw.flushMachineEgress(machined)
+		if errors.Cause(err) == errNotProvisioned {
+			fw.unprovisioned[machined.tag] = true
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// flushUnprovisioned retries flushing the machines whose instances
+// were not provisioned when they were last flushed.
+func (fw *Firewaller) flushUnprovisioned() error {
+	for tag := range fw.unprovisioned {
+		machined, ok := fw.machineds[tag]
+		if !ok {
+			delete(fw.unprovisioned, tag)
+			continue
+		}
+		if err := fw.flushMachine(machined); err != nil {
+			return errors.Annotate(err, "cannot change firewall ports")
+		}
+	}
+	return nil
+}
+
 func (fw *Firewaller) relationIngressChanged(change *remoteRelationNetworkChange) error {
 	fw.logger.Debugf("process remote relation ingress change for %v", change.relationTag)
 	relData, ok := fw.relationIngress[change.relationTag]
@@ -750,11 +869,115 @@ func (fw *Firewaller) flushMachine(machined *machineData) error {
 		return errors.Trace(err)
 	}
 	toOpen, toClose := diffRanges(machined.ingressRules, want)
-	machined.ingressRules = want
 	if fw.globalMode {
+		machined.ingressRules = want
 		return fw.flushGlobalPorts(toOpen, toClose)
 	}
-	return fw.flushInstancePorts(machined, toOpen, toClose)
+	err = fw.flushInstancePorts(machined, toOpen, toClose)
+	if errors.Cause(err) == errNotProvisioned {
+		// Keep the old rules so the change is made once
+		// the machine's instance has been provisioned.
+		fw.unprovisioned[machined.tag] = true
+		return nil
+	}
+	delete(fw.unprovisioned, machined.tag)
+	machined.ingressRules = want
+	if err != nil {
+		return err
+	}
+	err = fw.flushMachineEgress(machined)
+	if errors.Cause(err) == errNotProvisioned {
+		fw.unprovisioned[machined.tag] = true
+		return nil
+	}
+	return err
+}
+
+// flushMachineEgress restricts egress from the machine's instance to
+// the machine's egress rules, or lifts the restriction, when egress is
+// restricted by the provider. It records whether the provider enforces
+// the rules, so the machine agent enforces them when it does not.
+func (fw *Firewaller) flushMachineEgress(machined *machineData) (err error) {
+	if fw.egressFirewaller == nil {
+		return nil
+	}
+	defer func() {
+		if params.IsCodeNotFound(err) {
+			err = nil
+		}
+	}()
+	paramsRules, restricted, err := fw.firewallerApi.MachineEgressRules(machined.tag)
+	if err != nil {
+		return err
+	}
+	var want []network.EgressRule
+	if restricted {
+		if want, err = egressRules(paramsRules); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if machined.egressFlushed && reflect.DeepEqual(machined.egressRules, want) {
+		return nil
+	}
+	m, err := machined.machine()
+	if err != nil {
+		return err
+	}
+	instanceId, err := m.InstanceId()
+	if params.IsCodeNotProvisioned(err) {
+		if !restricted {
+			// New instances allow all egress.
+			return nil
+		}
+		return errNotProvisioned
+	}
+	if err != nil {
+		return err
+	}
+	// The machine agent keeps enforcing the rules itself unless
+	// the provider has applied them to the machine's instance. The
+	// provider may leave the instance partly restricted if it fails,
+	// so the machine agent enforces the new rules until the provider
+	// reports that every security group of the instance restricts
+	// egress.
+	if err := fw.firewallerApi.SetMachineEgressEnforcedByProvider(machined.tag, false); err != nil {
+		return errors.Annotatef(err, "cannot record egress enforcement of %q", machined.tag)
+	}
+	enforced := restricted
+	err = fw.egressFirewaller.SetEgressRules(fw.cloudCallContext, machined.tag.Id(), instanceId, want)
+	if errors.IsNotSupported(err) {
+		fw.logger.Warningf("cannot restrict egress from %q, leaving it to the machine agent: %v", machined.tag, err)
+		enforced = false
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot set egress rules of %q", machined.tag)
+	} else if restricted {
+		fw.logger.Infof("restricted egress from %q to %v", machined.tag, want)
+	} else {
+		fw.logger.Infof("lifted egress restriction from %q", machined.tag)
+	}
+	if enforced {
+		if err := fw.firewallerApi.SetMachineEgressEnforcedByProvider(machined.tag, true); err != nil {
+			return errors.Annotatef(err, "cannot record egress enforcement of %q", machined.tag)
+		}
+	}
+	machined.egressRules = want
+	machined.egressFlushed = true
+	return nil
+}
+
+// egressRules returns the sorted egress rules for the given
+// machine egress rules.
+func egressRules(paramsRules []params.EgressRule) ([]network.EgressRule, error) {
+	var rules []network.EgressRule
+	for _, r := range paramsRules {
+		rule, err := network.NewEgressRule(r.PortRange.Protocol, r.PortRange.FromPort, r.PortRange.ToPort, r.DestinationCIDR)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		rules = append(rules, rule)
+	}
+	network.SortEgressRules(rules)
+	return rules, nil
 }
 
 // gatherIngressRules returns the ingress rules to open and close
@@ -791,10 +1014,33 @@ func (fw *Firewaller) gatherIngressRules(machines ...*machineData) ([]network.In
 				}
 			}
 		}
+		for _, modelRule := range fw.modelIngressRules {
+			if !machined.hostsApplication(modelRule.Application) {
+				continue
+			}
+			rule, err := modelIngressRule(modelRule)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			want = append(want, rule)
+		}
 	}
 	return want, nil
 }
 
+// modelIngressRule returns the ingress rule for the
+// given model ingress firewall rule.
+func modelIngressRule(modelRule params.FirewallRule) (network.IngressRule, error) {
+	if modelRule.PortRange == nil {
+		return network.IngressRule{}, errors.NotValidf("firewall rule %q without port range", modelRule.Name)
+	}
+	if len(modelRule.WhitelistCIDRS) == 0 {
+		return network.IngressRule{}, errors.NotValidf("firewall rule %q without whitelist", modelRule.Name)
+	}
+	portRange := modelRule.PortRange
+	return network.NewIngressRule(portRange.Protocol, portRange.FromPort, portRange.ToPort, modelRule.WhitelistCIDRS...)
+}
+
 // TODO(wallyworld) - consider making this configurable.
 const maxAllowedCIDRS = 20
 
@@ -893,6 +1139,14 @@ func (fw *Firewaller) flushGlobalPorts(rawOpen, rawClose []network.IngressRule)
 	return nil
 }
 
+// errNotProvisioned is returned by flushInstancePorts when the
+// machine's instance has not been provisioned yet.
+var errNotProvisioned = errors.New("machine not provisioned")
+
+// unprovisionedRetryDelay is how long to wait before flushing
+// machines whose instances were not yet provisioned again.
+const unprovisionedRetryDelay = 30 * time.Second
+
 // flushInstancePorts opens and closes ports global on the machine.
 func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose []network.IngressRule) (err error) {
 	defer func() {
@@ -917,7 +1171,8 @@ func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose
 	instanceId, err := m.InstanceId()
 	if params.IsCodeNotProvisioned(err) {
 		// Not provisioned yet, so nothing to do for this instance
-		return nil
+		// until it is.
+		return errNotProvisioned
 	}
 	if err != nil {
 		return err
@@ -989,6 +1244,7 @@ func (fw *Firewaller) forgetMachine(machined *machineData) error {
 	// watch loop has stopped before we nuke the last data and return.
 	_ = worker.Stop(machined)
 	delete(fw.machineds, machined.tag)
+	delete(fw.unprovisioned, machined.tag)
 	fw.logger.Debugf("stopped watching %q", machined.tag)
 	return nil
 }
@@ -1048,12 +1304,27 @@ type machineData struct {
 	ingressRules []network.IngressRule
 	// ports defined by units on this machine
 	definedPorts map[names.UnitTag]portRanges
+	// egress rules last set on the machine's instance, if
+	// egressFlushed is true
+	egressRules   []network.EgressRule
+	egressFlushed bool
 }
 
 func (md *machineData) machine() (*firewaller.Machine, error) {
 	return md.fw.firewallerApi.Machine(md.tag)
 }
 
+// hostsApplication returns whether the machine hosts a unit of the
+// named application, or any unit if the name is empty.
+func (md *machineData) hostsApplication(name string) bool {
+	for _, unitd := range md.unitds {
+		if name == "" || unitd.applicationd.application.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
 // watchLoop watches the machine for units added or removed.
 func (md *machineData) watchLoop(unitw watcher.StringsWatcher) error {
 	if err := md.catacomb.Add(unitw); err != nil {
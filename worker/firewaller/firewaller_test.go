@@ -29,6 +29,7 @@ import (
 	apitesting "github.com/juju/juju/api/testing"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/crossmodel"
+	"github.com/juju/juju/core/instance"
 	corenetwork "github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs"
@@ -180,12 +181,15 @@ func (s *firewallerBaseSuite) startInstance(c *gc.C, m *state.Machine) instances
 
 type InstanceModeSuite struct {
 	firewallerBaseSuite
+
+	egressFirewaller environs.EgressFirewaller
 }
 
 var _ = gc.Suite(&InstanceModeSuite{})
 
 func (s *InstanceModeSuite) SetUpTest(c *gc.C) {
 	s.firewallerBaseSuite.setUpTest(c, config.FwInstance)
+	s.egressFirewaller = nil
 }
 
 // mockClock will panic if anything but After is called
@@ -223,9 +227,10 @@ func (s *InstanceModeSuite) newFirewallerWithClock(c *gc.C, clock clock.Clock) w
 		NewCrossModelFacadeFunc: func(*api.Info) (firewaller.CrossModelFirewallerFacadeCloser, error) {
 			return s.crossmodelFirewaller, nil
 		},
-		Clock:         s.clock,
-		Logger:        loggo.GetLogger("test"),
-		CredentialAPI: s.credentialsFacade,
+		EnvironEgressFirewaller: s.egressFirewaller,
+		Clock:                   s.clock,
+		Logger:                  loggo.GetLogger("test"),
+		CredentialAPI:           s.credentialsFacade,
 	}
 	fw, err := firewaller.NewFirewaller(cfg)
 	c.Assert(err, jc.ErrorIsNil)
@@ -359,6 +364,180 @@ func (s *InstanceModeSuite) TestMachineWithoutInstanceId(c *gc.C) {
 	})
 }
 
+func (s *InstanceModeSuite) TestModelIngressRules(c *gc.C) {
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	wordpress := s.AddTestingApplication(c, "wordpress", s.charm)
+	u1, m1 := s.addUnit(c, wordpress)
+	inst1 := s.startInstance(c, m1)
+	err := u1.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+	mysql := s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	_, m2 := s.addUnit(c, mysql)
+	inst2 := s.startInstance(c, m2)
+
+	rules := state.NewFirewallRules(s.State)
+	err = rules.Save(state.FirewallRule{
+		Name:           "node-exporter",
+		PortRange:      corenetwork.MustParsePortRange("9100/tcp"),
+		WhitelistCIDRs: []string{"10.0.0.0/24"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = rules.Save(state.FirewallRule{
+		Name:           "wordpress-admin",
+		PortRange:      corenetwork.MustParsePortRange("8000-8001/tcp"),
+		WhitelistCIDRs: []string{"0.0.0.0/0"},
+		Application:    "wordpress",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst1, m1.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 8000, 8001, "0.0.0.0/0"),
+		network.MustNewIngressRule("tcp", 9100, 9100, "10.0.0.0/24"),
+	})
+	s.assertPorts(c, inst2, m2.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 9100, 9100, "10.0.0.0/24"),
+	})
+
+	err = rules.RemoveIngressRule("node-exporter")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst1, m1.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 8000, 8001, "0.0.0.0/0"),
+	})
+	s.assertPorts(c, inst2, m2.Id(), nil)
+}
+
+type egressCall struct {
+	machineId  string
+	instanceId instance.Id
+	rules      []network.EgressRule
+}
+
+type mockEgressFirewaller struct {
+	calls chan egressCall
+	err   error
+}
+
+func (f *mockEgressFirewaller) SetEgressRules(ctx context.ProviderCallContext, machineId string, id instance.Id, rules []network.EgressRule) error {
+	f.calls <- egressCall{machineId, id, rules}
+	return f.err
+}
+
+func (s *InstanceModeSuite) assertEgressCall(c *gc.C, calls <-chan egressCall, machineId string, instanceId instance.Id) []network.EgressRule {
+	select {
+	case call := <-calls:
+		c.Assert(call.machineId, gc.Equals, machineId)
+		c.Assert(call.instanceId, gc.Equals, instanceId)
+		return call.rules
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for egress rules of machine %s", machineId)
+	}
+	return nil
+}
+
+func (s *InstanceModeSuite) assertEgressEnforcedByProvider(c *gc.C, m *state.Machine, enforced bool) {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		err := m.Refresh()
+		c.Assert(err, jc.ErrorIsNil)
+		if m.EgressEnforcedByProvider() == enforced {
+			return
+		}
+	}
+	c.Fatalf("egress enforcement of machine %s by the provider is not %v", m.Id(), enforced)
+}
+
+func (s *InstanceModeSuite) TestEgressRules(c *gc.C) {
+	egressFirewaller := &mockEgressFirewaller{calls: make(chan egressCall, 10)}
+	s.egressFirewaller = egressFirewaller
+
+	mysql := s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	_, m := s.addUnit(c, mysql)
+	inst := s.startInstance(c, m)
+
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	// The egress rules are reconciled once the machine is known.
+	rules := s.assertEgressCall(c, egressFirewaller.calls, m.Id(), inst.Id())
+	c.Assert(rules, gc.HasLen, 0)
+
+	err := mysql.SetEgressRules([]state.EgressRule{{
+		DestinationCIDR: "10.0.0.0/8",
+		PortRange:       corenetwork.MustParsePortRange("5432/tcp"),
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	s.BackingState.StartSync()
+	rules = s.assertEgressCall(c, egressFirewaller.calls, m.Id(), inst.Id())
+	// The controller API addresses are always allowed as well.
+	c.Assert(rules, jc.DeepContains, []network.EgressRule{
+		network.MustNewEgressRule("tcp", 5432, 5432, "10.0.0.0/8"),
+	})
+	s.assertEgressEnforcedByProvider(c, m, true)
+
+	err = mysql.SetEgressRules(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.BackingState.StartSync()
+	rules = s.assertEgressCall(c, egressFirewaller.calls, m.Id(), inst.Id())
+	c.Assert(rules, gc.HasLen, 0)
+	s.assertEgressEnforcedByProvider(c, m, false)
+}
+
+func (s *InstanceModeSuite) TestEgressRulesNotSupportedByProvider(c *gc.C) {
+	egressFirewaller := &mockEgressFirewaller{
+		calls: make(chan egressCall, 10),
+		err:   errors.NotSupportedf("egress rules"),
+	}
+	s.egressFirewaller = egressFirewaller
+
+	mysql := s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	_, m := s.addUnit(c, mysql)
+	inst := s.startInstance(c, m)
+	err := mysql.SetEgressRules([]state.EgressRule{{
+		DestinationCIDR: "10.0.0.0/8",
+		PortRange:       corenetwork.MustParsePortRange("5432/tcp"),
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetEgressEnforcedByProvider(true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	// The machine agent is left to enforce the rules.
+	s.assertEgressCall(c, egressFirewaller.calls, m.Id(), inst.Id())
+	s.assertEgressEnforcedByProvider(c, m, false)
+}
+
+func (s *InstanceModeSuite) TestEgressRulesProviderFailure(c *gc.C) {
+	egressFirewaller := &mockEgressFirewaller{
+		calls: make(chan egressCall, 10),
+		err:   errors.New("boom"),
+	}
+	s.egressFirewaller = egressFirewaller
+
+	mysql := s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	_, m := s.addUnit(c, mysql)
+	inst := s.startInstance(c, m)
+	err := mysql.SetEgressRules([]state.EgressRule{{
+		DestinationCIDR: "10.0.0.0/8",
+		PortRange:       corenetwork.MustParsePortRange("5432/tcp"),
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetEgressEnforcedByProvider(true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	fw := s.newFirewaller(c)
+
+	// The provider may have restricted only some of the instance's
+	// security groups, so the machine agent enforces the rules.
+	s.assertEgressCall(c, egressFirewaller.calls, m.Id(), inst.Id())
+	s.assertEgressEnforcedByProvider(c, m, false)
+	err = worker.Stop(fw)
+	c.Assert(err, gc.ErrorMatches, `.*cannot set egress rules of "machine-.*": boom`)
+}
+
 func (s *InstanceModeSuite) TestMultipleUnits(c *gc.C) {
 	fw := s.newFirewaller(c)
 	defer statetesting.AssertKillAndWait(c, fw)
@@ -138,12 +138,17 @@ func (cfg ManifoldConfig) start(context dependency.Context) (worker.Worker, erro
 		return nil, errors.Trace(err)
 	}
 
+	// Egress is restricted by the provider where it supports egress
+	// rules, and by the machine agents elsewhere.
+	egressEnv, _ := environ.(environs.EgressFirewaller)
+
 	w, err := cfg.NewFirewallerWorker(Config{
 		ModelUUID:               agent.CurrentConfig().Model().Id(),
 		RemoteRelationsApi:      remoteRelationsAPI,
 		FirewallerAPI:           firewallerAPI,
 		EnvironFirewaller:       fwEnv,
 		EnvironInstances:        environ,
+		EnvironEgressFirewaller: egressEnv,
 		Mode:                    mode,
 		NewCrossModelFacadeFunc: crossmodelFirewallerFacadeFunc(cfg.NewControllerConnection),
 		CredentialAPI:           credentialAPI,
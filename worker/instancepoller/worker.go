@@ -118,6 +118,11 @@ type pollGroupEntry struct {
 
 	shortPollInterval time.Duration
 	shortPollAt       time.Time
+
+	// interruptible records whether the cloud may reclaim the
+	// machine's instance, in which case it is kept in the short
+	// poll group so that interruption notices are seen in time.
+	interruptible bool
 }
 
 func (e *pollGroupEntry) resetShortPollInterval(clk clock.Clock) {
@@ -406,9 +411,22 @@ func (u *updaterWorker) processProviderInfo(entry *pollGroupEntry, info instance
 		Message: curStatus.Info,
 	}
 
+	// An interrupted instance is reported as a transient provisioning
+	// error, so that the provisioner replaces it.
+	var statusData map[string]interface{}
+	interrupted := u.instanceInterrupted(entry, info)
+	if interrupted {
+		providerStatus.Status = status.ProvisioningError
+		statusData = map[string]interface{}{"transient": true, "interrupted": true}
+	} else if notice := u.interruptionNotice(entry, info); notice != "" {
+		// The cloud is about to reclaim the instance; show the
+		// notice until the instance is interrupted.
+		providerStatus.Message = notice
+	}
+
 	if providerStatus != curInstStatus {
 		u.config.Logger.Infof("machine %q (instance ID %q) instance status changed from %q to %q", entry.m.Id(), entry.instanceID, curInstStatus, providerStatus)
-		if err = entry.m.SetInstanceStatus(providerStatus.Status, providerStatus.Message, nil); err != nil {
+		if err = entry.m.SetInstanceStatus(providerStatus.Status, providerStatus.Message, statusData); err != nil {
 			u.config.Logger.Errorf("cannot set instance status on %q: %v", entry.m, err)
 			return status.Unknown, errors.Trace(err)
 		}
@@ -421,6 +439,15 @@ func (u *updaterWorker) processProviderInfo(entry *pollGroupEntry, info instance
 		}
 	}
 
+	// Forget the interrupted instance, so that its replacement is polled
+	// once the provisioner has started it.
+	if interrupted {
+		u.config.Logger.Infof("machine %q (instance ID %q) was interrupted", entry.m.Id(), entry.instanceID)
+		delete(u.instanceIDToGroupEntry, entry.instanceID)
+		entry.instanceID = ""
+		return status.Unknown, nil
+	}
+
 	// We don't care about dead machines; they will be cleaned up when we
 	// process the following machine watcher events.
 	if entry.m.Life() == params.Dead {
@@ -450,6 +477,39 @@ func (u *updaterWorker) processProviderInfo(entry *pollGroupEntry, info instance
 	return providerStatus.Status, nil
 }
 
+// instanceInterrupted returns whether the cloud has reclaimed the
+// instance of an alive machine which has not yet been given a
+// replacement instance.
+func (u *updaterWorker) instanceInterrupted(entry *pollGroupEntry, info instances.Instance) bool {
+	interruptible, ok := info.(instances.InterruptibleInstance)
+	if !ok || !interruptible.Interrupted(u.callContext) {
+		return false
+	}
+	if entry.m.Life() != params.Alive {
+		return false
+	}
+	instID, err := entry.m.InstanceId()
+	if err != nil {
+		u.config.Logger.Warningf("cannot get current instance ID for machine %v: %v", entry.m.Id(), err)
+		return false
+	}
+	return instID == entry.instanceID
+}
+
+// interruptionNotice returns the notice the cloud has given that it is
+// about to reclaim the instance, if any, and records whether the cloud
+// may reclaim the instance at all.
+func (u *updaterWorker) interruptionNotice(entry *pollGroupEntry, info instances.Instance) string {
+	interruptible, ok := info.(instances.InterruptibleInstance)
+	if !ok {
+		entry.interruptible = false
+		return ""
+	}
+	notice, mayInterrupt := interruptible.InterruptionNotice(u.callContext)
+	entry.interruptible = mayInterrupt
+	return notice
+}
+
 func (u *updaterWorker) maybeSwitchPollGroup(curGroup pollGroupType, entry *pollGroupEntry, curProviderStatus, curMachineStatus status.Status) {
 	if curProviderStatus == status.Allocating || curProviderStatus == status.Pending {
 		// Keep the machine in the short poll group until it settles
@@ -468,6 +528,17 @@ func (u *updaterWorker) maybeSwitchPollGroup(curGroup pollGroupType, entry *poll
 		return
 	}
 
+	// Instances which the cloud may reclaim give only a short notice
+	// before they are interrupted, so they stay in the short poll group.
+	if entry.interruptible {
+		if curGroup == longPollGroup {
+			u.moveEntryToPollGroup(shortPollGroup, entry)
+		} else {
+			entry.bumpShortPollInterval(u.config.Clock)
+		}
+		return
+	}
+
 	// The machine has started and we have at least one address; move to
 	// the long poll group
 	if len(machAddrs) > 0 && curMachineStatus == status.Started {
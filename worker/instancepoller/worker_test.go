@@ -24,6 +24,7 @@ import (
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/core/watcher"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
 	"github.com/juju/juju/environs/instances"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/instancepoller/mocks"
@@ -214,6 +215,128 @@ func (s *workerSuite) TestUpdateOfStatusAndAddressDetails(c *gc.C) {
 	c.Assert(providerStatus, gc.Equals, status.Running)
 }
 
+type interruptibleInstance struct {
+	*mocks.MockInstance
+	interrupted bool
+	notice      string
+}
+
+func (i interruptibleInstance) Interrupted(context.ProviderCallContext) bool {
+	return i.interrupted
+}
+
+func (i interruptibleInstance) InterruptionNotice(context.ProviderCallContext) (string, bool) {
+	return i.notice, true
+}
+
+func (s *workerSuite) TestInterruptedInstanceMarkedForReplacement(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	w, _ := s.startWorker(c, ctrl)
+	defer workertest.CleanKill(c, w)
+	updWorker := w.(*updaterWorker)
+
+	machineTag := names.NewMachineTag("0")
+	machine := mocks.NewMockMachine(ctrl)
+	entry := &pollGroupEntry{
+		tag:        machineTag,
+		m:          machine,
+		instanceID: "b4dc0ffee",
+	}
+	updWorker.instanceIDToGroupEntry[entry.instanceID] = entry
+
+	machine.EXPECT().Id().Return("0").AnyTimes()
+	machine.EXPECT().Life().Return(params.Alive)
+	machine.EXPECT().InstanceId().Return(instance.Id("b4dc0ffee"), nil)
+	machine.EXPECT().InstanceStatus().Return(params.StatusResult{Status: string(status.Running)}, nil)
+
+	instInfo := mocks.NewMockInstance(ctrl)
+	instInfo.EXPECT().Status(gomock.Any()).Return(instance.Status{Status: status.Empty, Message: "spot instance interrupted"})
+
+	// The machine is flagged so that the provisioner replaces its
+	// instance, and the worker forgets the old instance ID.
+	machine.EXPECT().SetInstanceStatus(status.ProvisioningError, "spot instance interrupted", map[string]interface{}{
+		"transient":   true,
+		"interrupted": true,
+	}).Return(nil)
+
+	providerStatus, err := updWorker.processProviderInfo(entry, interruptibleInstance{MockInstance: instInfo, interrupted: true})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(providerStatus, gc.Equals, status.Unknown)
+	c.Assert(entry.instanceID, gc.Equals, instance.Id(""))
+	c.Assert(updWorker.instanceIDToGroupEntry, gc.HasLen, 0)
+}
+
+func (s *workerSuite) TestInterruptedInstanceAlreadyReplaced(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	w, _ := s.startWorker(c, ctrl)
+	defer workertest.CleanKill(c, w)
+	updWorker := w.(*updaterWorker)
+
+	machineTag := names.NewMachineTag("0")
+	machine := mocks.NewMockMachine(ctrl)
+	entry := &pollGroupEntry{
+		tag:        machineTag,
+		m:          machine,
+		instanceID: "b4dc0ffee",
+	}
+
+	// The provisioner has already started a replacement instance, so the
+	// status of the old one must not be recorded against the machine.
+	machine.EXPECT().Id().Return("0").AnyTimes()
+	machine.EXPECT().Life().Return(params.Alive).Times(2)
+	machine.EXPECT().InstanceId().Return(instance.Id("dec0ded"), nil)
+	machine.EXPECT().InstanceStatus().Return(params.StatusResult{Status: string(status.Empty), Info: "spot instance interrupted"}, nil)
+	machine.EXPECT().ProviderAddresses().Return(nil, nil)
+
+	instInfo := mocks.NewMockInstance(ctrl)
+	instInfo.EXPECT().Status(gomock.Any()).Return(instance.Status{Status: status.Empty, Message: "spot instance interrupted"})
+	instInfo.EXPECT().Addresses(gomock.Any()).Return(nil, nil)
+
+	providerStatus, err := updWorker.processProviderInfo(entry, interruptibleInstance{MockInstance: instInfo, interrupted: true})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(providerStatus, gc.Equals, status.Empty)
+}
+
+func (s *workerSuite) TestInterruptionNoticeSetAsInstanceStatusMessage(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	w, _ := s.startWorker(c, ctrl)
+	defer workertest.CleanKill(c, w)
+	updWorker := w.(*updaterWorker)
+
+	machineTag := names.NewMachineTag("0")
+	machine := mocks.NewMockMachine(ctrl)
+	entry := &pollGroupEntry{
+		tag:        machineTag,
+		m:          machine,
+		instanceID: "b4dc0ffee",
+	}
+
+	machine.EXPECT().Id().Return("0").AnyTimes()
+	machine.EXPECT().Life().Return(params.Alive)
+	machine.EXPECT().InstanceStatus().Return(params.StatusResult{Status: string(status.Running), Info: "running"}, nil)
+	machine.EXPECT().ProviderAddresses().Return(testAddrs, nil)
+
+	instInfo := mocks.NewMockInstance(ctrl)
+	instInfo.EXPECT().Status(gomock.Any()).Return(instance.Status{Status: status.Running, Message: "running"})
+	instInfo.EXPECT().Addresses(gomock.Any()).Return(testAddrs, nil)
+
+	// The instance is still running, but the cloud has given notice
+	// that it is about to reclaim it.
+	notice := "spot instance interruption notice (marked-for-termination): Your Spot Instance is marked for termination."
+	machine.EXPECT().SetInstanceStatus(status.Running, notice, nil).Return(nil)
+
+	providerStatus, err := updWorker.processProviderInfo(entry, interruptibleInstance{MockInstance: instInfo, notice: notice})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(providerStatus, gc.Equals, status.Running)
+	c.Assert(entry.interruptible, jc.IsTrue)
+}
+
 func (s *workerSuite) TestStartedMachineWithNetAddressesMovesToLongPollGroup(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
@@ -240,6 +363,30 @@ func (s *workerSuite) TestStartedMachineWithNetAddressesMovesToLongPollGroup(c *
 	c.Assert(updWorker.pollGroup[longPollGroup], gc.HasLen, 1)
 }
 
+func (s *workerSuite) TestInterruptibleMachineStaysInShortPollGroup(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	w, _ := s.startWorker(c, ctrl)
+	defer workertest.CleanKill(c, w)
+	updWorker := w.(*updaterWorker)
+
+	machineTag := names.NewMachineTag("0")
+	machine := mocks.NewMockMachine(ctrl)
+	machine.EXPECT().ProviderAddresses().Return(testAddrs, nil)
+	updWorker.appendToShortPollGroup(machineTag, machine)
+
+	// The instance may be reclaimed by the cloud, so it must keep being
+	// polled often enough to notice an interruption notice in time.
+	entry, _ := updWorker.lookupPolledMachine(machineTag)
+	entry.interruptible = true
+	updWorker.maybeSwitchPollGroup(shortPollGroup, entry, status.Running, status.Started)
+
+	c.Assert(updWorker.pollGroup[shortPollGroup], gc.HasLen, 1)
+	c.Assert(updWorker.pollGroup[longPollGroup], gc.HasLen, 0)
+	c.Assert(entry.shortPollInterval, gc.Equals, time.Duration(float64(ShortPoll)*ShortPollBackoff))
+}
+
 func (s *workerSuite) TestNonStartedMachinesGetBumpedPollInterval(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
@@ -0,0 +1,244 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machineactions
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/actions"
+)
+
+// connectivityPayload is sent by a probe and echoed back by the
+// listener, so the probe can tell it reached the listener and not
+// some other service that happens to use the same port.
+var connectivityPayload = []byte("juju-connectivity-check\n")
+
+// handleConnectivityListenAction opens a TCP and a UDP listener on the
+// requested address that echo back anything they receive. The listeners
+// are closed once the timeout expires; the action itself completes
+// straight away so that the probing side can be run while they are
+// open, even when both ends of the check are on the same machine.
+func handleConnectivityListenAction(params map[string]interface{}) (results map[string]interface{}, err error) {
+	address, _ := params["address"].(string)
+	port, _ := params["port"].(float64)
+	timeout, err := actionTimeout(params)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if timeout > actions.MaxConnectivityListenTimeout {
+		return nil, errors.NotValidf("timeout %v longer than %v", timeout, actions.MaxConnectivityListenTimeout)
+	}
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return nil, errors.NotValidf("address %q", address)
+	}
+	if port < 0 || port > 65535 {
+		return nil, errors.NotValidf("port %v", port)
+	}
+	// The agent runs as root, so it must not be used to
+	// impersonate system services.
+	if port > 0 && port < 1024 {
+		return nil, errors.NotValidf("privileged port %v", port)
+	}
+
+	listenAddr := net.JoinHostPort(ip.String(), strconv.Itoa(int(port)))
+	tcpListener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return nil, errors.Annotate(listenError(err, port), "opening TCP listener")
+	}
+	udpConn, err := net.ListenPacket("udp", listenAddr)
+	if err != nil {
+		_ = tcpListener.Close()
+		return nil, errors.Annotate(listenError(err, port), "opening UDP listener")
+	}
+	logger.Debugf("connectivity check listening on TCP %v and UDP %v for %v",
+		tcpListener.Addr(), udpConn.LocalAddr(), timeout)
+
+	go serveTCPEcho(tcpListener)
+	go serveUDPEcho(udpConn)
+	time.AfterFunc(timeout, func() {
+		_ = tcpListener.Close()
+		_ = udpConn.Close()
+	})
+
+	results = map[string]interface{}{
+		"tcp-port": strconv.Itoa(tcpListener.Addr().(*net.TCPAddr).Port),
+		"udp-port": strconv.Itoa(udpConn.LocalAddr().(*net.UDPAddr).Port),
+	}
+	storeInterfaceInfo(results, net.ParseIP(address))
+	return results, nil
+}
+
+// listenError makes the error returned when a listener cannot
+// be opened more helpful if the port is already in use.
+func listenError(err error, port float64) error {
+	if opErr, ok := err.(*net.OpError); ok {
+		if sysErr, ok := opErr.Err.(*os.SyscallError); ok && sysErr.Err == syscall.EADDRINUSE {
+			return errors.Errorf("port %v is already in use", port)
+		}
+	}
+	return err
+}
+
+func serveTCPEcho(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+			buf := make([]byte, len(connectivityPayload))
+			n, _ := conn.Read(buf)
+			_, _ = conn.Write(buf[:n])
+		}()
+	}
+}
+
+func serveUDPEcho(conn net.PacketConn) {
+	buf := make([]byte, 1500)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		_, _ = conn.WriteTo(buf[:n], addr)
+	}
+}
+
+// handleConnectivityProbeAction connects to the TCP and UDP listeners
+// opened by a juju-connectivity-listen action, and reports whether each
+// could be reached, the latency, and the reason for any failure. The
+// results also include the MTU of the local interface used to reach the
+// target address; this is not the path MTU, which may be smaller.
+func handleConnectivityProbeAction(params map[string]interface{}) (results map[string]interface{}, err error) {
+	address, _ := params["address"].(string)
+	tcpPort, _ := params["tcp-port"].(float64)
+	udpPort, _ := params["udp-port"].(float64)
+	timeout, err := actionTimeout(params)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if net.ParseIP(address) == nil {
+		return nil, errors.NotValidf("address %q", address)
+	}
+
+	results = map[string]interface{}{}
+	latency, err := probeTCP(net.JoinHostPort(address, strconv.Itoa(int(tcpPort))), timeout)
+	storeProbeResult(results, "tcp", latency, err, timeout)
+	latency, err = probeUDP(net.JoinHostPort(address, strconv.Itoa(int(udpPort))), timeout)
+	storeProbeResult(results, "udp", latency, err, timeout)
+
+	// Dialing UDP sends no packets, but tells us which local address
+	// (and so which interface) would be used to reach the target.
+	if conn, err := net.Dial("udp", net.JoinHostPort(address, "9")); err == nil {
+		storeInterfaceInfo(results, conn.LocalAddr().(*net.UDPAddr).IP)
+		_ = conn.Close()
+	}
+	return results, nil
+}
+
+func probeTCP(address string, timeout time.Duration) (time.Duration, error) {
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	latency := time.Since(start)
+	defer conn.Close()
+	return latency, errors.Trace(checkEcho(conn, timeout))
+}
+
+func probeUDP(address string, timeout time.Duration) (time.Duration, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	defer conn.Close()
+	start := time.Now()
+	if err := checkEcho(conn, timeout); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return time.Since(start), nil
+}
+
+// checkEcho writes the connectivity payload to the connection and
+// checks that the same payload is sent back.
+func checkEcho(conn net.Conn, timeout time.Duration) error {
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := conn.Write(connectivityPayload); err != nil {
+		return errors.Trace(err)
+	}
+	buf := make([]byte, len(connectivityPayload))
+	n, err := conn.Read(buf)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !bytes.Equal(buf[:n], connectivityPayload) {
+		return errors.New("unexpected response, another service may be using the port")
+	}
+	return nil
+}
+
+func storeProbeResult(results map[string]interface{}, proto string, latency time.Duration, err error, timeout time.Duration) {
+	if err == nil {
+		results[proto] = "ok"
+		results[proto+"-latency"] = latency.String()
+		return
+	}
+	results[proto] = "failed"
+	if netErr, ok := errors.Cause(err).(net.Error); ok && netErr.Timeout() {
+		results[proto+"-error"] = fmt.Sprintf("no response within %v, traffic may be filtered", timeout)
+	} else {
+		results[proto+"-error"] = errors.Cause(err).Error()
+	}
+}
+
+// storeInterfaceInfo records the name and MTU of the local interface
+// with the given address, if there is one.
+func storeInterfaceInfo(results map[string]interface{}, ip net.IP) {
+	if ip == nil {
+		return
+	}
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		logger.Warningf("cannot list network interfaces: %v", err)
+		return
+	}
+	for _, iface := range interfaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if ok && ipNet.IP.Equal(ip) {
+				results["interface"] = iface.Name
+				results["interface-mtu"] = strconv.Itoa(iface.MTU)
+				return
+			}
+		}
+	}
+}
+
+// actionTimeout returns the timeout of a connectivity action. As with
+// juju-run, it is passed in nanoseconds but arrives as a float64.
+func actionTimeout(params map[string]interface{}) (time.Duration, error) {
+	timeout, _ := params["timeout"].(float64)
+	if timeout <= 0 {
+		return 0, errors.NotValidf("timeout %v", time.Duration(timeout))
+	}
+	return time.Duration(timeout), nil
+}
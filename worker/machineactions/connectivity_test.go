@@ -0,0 +1,166 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machineactions_test
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/actions"
+	"github.com/juju/juju/worker/machineactions"
+)
+
+type ConnectivitySuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&ConnectivitySuite{})
+
+func (s *ConnectivitySuite) listen(c *gc.C) (tcpPort, udpPort float64, results map[string]interface{}) {
+	results, err := machineactions.HandleAction(actions.ConnectivityListenActionName, map[string]interface{}{
+		"address": "127.0.0.1",
+		"timeout": float64(10 * time.Second),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	tcp, err := strconv.Atoi(results["tcp-port"].(string))
+	c.Assert(err, jc.ErrorIsNil)
+	udp, err := strconv.Atoi(results["udp-port"].(string))
+	c.Assert(err, jc.ErrorIsNil)
+	return float64(tcp), float64(udp), results
+}
+
+func (s *ConnectivitySuite) probe(c *gc.C, tcpPort, udpPort float64) map[string]interface{} {
+	results, err := machineactions.HandleAction(actions.ConnectivityProbeActionName, map[string]interface{}{
+		"address":  "127.0.0.1",
+		"tcp-port": tcpPort,
+		"udp-port": udpPort,
+		"timeout":  float64(time.Second),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return results
+}
+
+func (s *ConnectivitySuite) TestListenReportsInterface(c *gc.C) {
+	_, _, results := s.listen(c)
+	c.Assert(results["interface"], gc.Not(gc.Equals), "")
+	mtu, err := strconv.Atoi(results["interface-mtu"].(string))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mtu > 0, jc.IsTrue)
+}
+
+func (s *ConnectivitySuite) TestProbeSuccess(c *gc.C) {
+	tcpPort, udpPort, _ := s.listen(c)
+	results := s.probe(c, tcpPort, udpPort)
+	c.Assert(results["tcp"], gc.Equals, "ok")
+	c.Assert(results["udp"], gc.Equals, "ok")
+	_, err := time.ParseDuration(results["tcp-latency"].(string))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = time.ParseDuration(results["udp-latency"].(string))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results["interface-mtu"], gc.NotNil)
+}
+
+func (s *ConnectivitySuite) TestProbeRefused(c *gc.C) {
+	// Find a TCP port that nothing is listening on.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	port := listener.Addr().(*net.TCPAddr).Port
+	c.Assert(listener.Close(), jc.ErrorIsNil)
+
+	results := s.probe(c, float64(port), float64(port))
+	c.Assert(results["tcp"], gc.Equals, "failed")
+	c.Assert(results["tcp-error"], gc.Matches, ".*connection refused")
+	c.Assert(results["udp"], gc.Equals, "failed")
+	c.Assert(results["tcp-latency"], gc.IsNil)
+}
+
+func (s *ConnectivitySuite) TestInvalidTimeout(c *gc.C) {
+	_, err := machineactions.HandleAction(actions.ConnectivityListenActionName, map[string]interface{}{
+		"address": "127.0.0.1",
+		"timeout": float64(0),
+	})
+	c.Assert(err, gc.ErrorMatches, "timeout 0s not valid")
+}
+
+func (s *ConnectivitySuite) TestListenTimeoutTooLong(c *gc.C) {
+	_, err := machineactions.HandleAction(actions.ConnectivityListenActionName, map[string]interface{}{
+		"address": "127.0.0.1",
+		"timeout": float64(time.Hour),
+	})
+	c.Assert(err, gc.ErrorMatches, "timeout 1h0m0s longer than 10m0s not valid")
+}
+
+func (s *ConnectivitySuite) TestListenBindsToAddress(c *gc.C) {
+	tcpPort, _, _ := s.listen(c)
+	// The listener is not reachable on other local addresses.
+	for _, addr := range allLocalIPs(c) {
+		if addr.IsLoopback() {
+			continue
+		}
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort(addr.String(), strconv.Itoa(int(tcpPort))), time.Second)
+		if err == nil {
+			_ = conn.Close()
+			c.Fatalf("listener reachable on %v", addr)
+		}
+	}
+}
+
+func allLocalIPs(c *gc.C) []net.IP {
+	addrs, err := net.InterfaceAddrs()
+	c.Assert(err, jc.ErrorIsNil)
+	var ips []net.IP
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok {
+			ips = append(ips, ipNet.IP)
+		}
+	}
+	return ips
+}
+
+func (s *ConnectivitySuite) TestListenAddressNotConfigured(c *gc.C) {
+	_, err := machineactions.HandleAction(actions.ConnectivityListenActionName, map[string]interface{}{
+		"address": "192.0.2.1",
+		"timeout": float64(time.Second),
+	})
+	c.Assert(err, gc.ErrorMatches, "opening TCP listener: .*")
+}
+
+func (s *ConnectivitySuite) TestListenPrivilegedPort(c *gc.C) {
+	_, err := machineactions.HandleAction(actions.ConnectivityListenActionName, map[string]interface{}{
+		"address": "127.0.0.1",
+		"port":    float64(22),
+		"timeout": float64(time.Second),
+	})
+	c.Assert(err, gc.ErrorMatches, "privileged port 22 not valid")
+}
+
+func (s *ConnectivitySuite) TestListenPortInUse(c *gc.C) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	_, err = machineactions.HandleAction(actions.ConnectivityListenActionName, map[string]interface{}{
+		"address": "127.0.0.1",
+		"port":    float64(port),
+		"timeout": float64(time.Second),
+	})
+	c.Assert(err, gc.ErrorMatches, fmt.Sprintf("opening TCP listener: port %d is already in use", port))
+}
+
+func (s *ConnectivitySuite) TestProbeInvalidAddress(c *gc.C) {
+	_, err := machineactions.HandleAction(actions.ConnectivityProbeActionName, map[string]interface{}{
+		"address":  "not-an-ip",
+		"tcp-port": float64(1),
+		"udp-port": float64(1),
+		"timeout":  float64(time.Second),
+	})
+	c.Assert(err, gc.ErrorMatches, `address "not-an-ip" not valid`)
+}
@@ -6,4 +6,5 @@ package machiner
 var (
 	InterfaceAddrs           = &interfaceAddrs
 	GetObservedNetworkConfig = &getObservedNetworkConfig
+	ApplyNetplan             = &applyNetplan
 )
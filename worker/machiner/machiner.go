@@ -4,7 +4,9 @@ package machiner
 
 import (
 	"net"
+	"time"
 
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"gopkg.in/juju/names.v3"
@@ -16,6 +18,7 @@ import (
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/core/watcher"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/network/netplan"
 	jworker "github.com/juju/juju/worker"
 )
 
@@ -33,6 +36,18 @@ type Config struct {
 	// ClearMachineAddressesOnStart indicates whether or not to clear
 	// the machine's machine addresses when the worker starts.
 	ClearMachineAddressesOnStart bool
+
+	// NetplanDirectory is the directory holding the machine's netplan
+	// configuration. If it is empty, the netplan configuration is not
+	// reported or managed.
+	NetplanDirectory string
+
+	// VerifyAPIConnection, if set, dials a new connection to the
+	// controller, which must succeed by the given deadline. It is used
+	// to verify a netplan configuration once applied, which is rolled
+	// back if the controller cannot be reached. If it is not set, the
+	// netplan configuration is reported but never applied.
+	VerifyAPIConnection func(deadline time.Time) error
 }
 
 // Validate reports whether or not the configuration is valid.
@@ -50,6 +65,12 @@ func (cfg *Config) Validate() error {
 type Machiner struct {
 	config  Config
 	machine Machine
+
+	// failedNetplan holds the last desired netplan configuration that
+	// could not be applied, and failedNetplanErr the reason why, so that
+	// it is not retried on every change.
+	failedNetplan    string
+	failedNetplanErr error
 }
 
 // NewMachiner returns a Worker that will wait for the identified machine
@@ -74,6 +95,18 @@ var NewMachiner = func(cfg Config) (worker.Worker, error) {
 
 var getObservedNetworkConfig = common.GetObservedNetworkConfig
 
+var applyNetplan = netplan.ApplyWithRollback
+
+const (
+	// netplanApplyTimeout is how long to wait for netplan to apply a
+	// new configuration before rolling it back.
+	netplanApplyTimeout = 5 * time.Minute
+
+	// netplanVerifyTimeout is how long the controller has to become
+	// reachable once a new netplan configuration has been applied.
+	netplanVerifyTimeout = time.Minute
+)
+
 func (mr *Machiner) SetUp() (watcher.NotifyWatcher, error) {
 	// Find which machine we're responsible for.
 	m, err := mr.config.MachineAccessor.Machine(mr.config.Tag)
@@ -179,6 +212,11 @@ func (mr *Machiner) Handle(_ <-chan struct{}) error {
 		}
 		logger.Debugf("observed network config updated for %q to %+v", mr.config.Tag, observedConfig)
 
+		if mr.config.NetplanDirectory != "" {
+			if err := mr.updateNetplan(); err != nil {
+				return errors.Annotate(err, "cannot update netplan config")
+			}
+		}
 		return nil
 	}
 	logger.Debugf("%q is now %s", mr.config.Tag, life)
@@ -208,6 +246,88 @@ func (mr *Machiner) Handle(_ <-chan struct{}) error {
 	return jworker.ErrTerminateAgent
 }
 
+// updateNetplan renders the desired netplan configuration of the machine
+// and, if the model is configured to manage netplan, applies it when it
+// differs from the configuration in effect. The configuration in effect
+// and any failure to apply the desired one are reported to the controller.
+func (mr *Machiner) updateNetplan() error {
+	devices, manage, err := mr.machine.NetplanConfig()
+	if errors.IsNotSupported(err) {
+		logger.Debugf("not updating netplan config: %v", err)
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	actual, err := netplan.ReadDirectory(mr.config.NetplanDirectory)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	var applyError string
+	if manage && len(devices) > 0 && mr.config.VerifyAPIConnection != nil {
+		applied, err := mr.applyNetplan(actual, devices)
+		if err != nil {
+			logger.Errorf("cannot apply netplan config for %q: %v", mr.config.Tag, err)
+			applyError = err.Error()
+		} else if applied {
+			if actual, err = netplan.ReadDirectory(mr.config.NetplanDirectory); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+
+	out, err := netplan.Marshal(&actual)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(mr.machine.SetNetplanStatus(string(out), applyError))
+}
+
+// applyNetplan applies the netplan configuration rendered from the given
+// devices if it differs from the actual configuration, and reports whether
+// it did. A configuration that failed to apply is not retried until the
+// desired configuration changes.
+func (mr *Machiner) applyNetplan(actual netplan.Netplan, devices []netplan.DeviceConfig) (bool, error) {
+	desired, err := netplan.Render(devices)
+	if err != nil {
+		return false, errors.Annotate(err, "rendering netplan")
+	}
+	changes, err := netplan.MergeDiff(actual, desired)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if len(changes) == 0 {
+		mr.failedNetplan, mr.failedNetplanErr = "", nil
+		return false, nil
+	}
+	out, err := netplan.Marshal(&desired)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if string(out) == mr.failedNetplan {
+		logger.Debugf("not retrying netplan config for %q that failed to apply", mr.config.Tag)
+		return false, mr.failedNetplanErr
+	}
+
+	logger.Infof("applying netplan config for %q: %v", mr.config.Tag, changes)
+	_, err = applyNetplan(netplan.ApplyParams{
+		Clock:     clock.WallClock,
+		Directory: mr.config.NetplanDirectory,
+		Netplan:   desired,
+		Timeout:   netplanApplyTimeout,
+		// The machine must still be able to reach the controller
+		// over a new connection with the new configuration in place.
+		Verify:        mr.config.VerifyAPIConnection,
+		VerifyTimeout: netplanVerifyTimeout,
+	})
+	if err != nil {
+		mr.failedNetplan, mr.failedNetplanErr = string(out), err
+		return false, errors.Trace(err)
+	}
+	mr.failedNetplan, mr.failedNetplanErr = "", nil
+	return true, nil
+}
+
 func (mr *Machiner) TearDown() error {
 	// Nothing to do here.
 	return nil
@@ -8,6 +8,7 @@ import (
 	"net"
 	"path/filepath"
 	stdtesting "testing"
+	"time"
 
 	"github.com/juju/errors"
 	gitjujutesting "github.com/juju/testing"
@@ -21,6 +22,7 @@ import (
 	corenetwork "github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/network/netplan"
 	coretesting "github.com/juju/juju/testing"
 	jworker "github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/machiner"
@@ -35,6 +37,8 @@ type MachinerSuite struct {
 	accessor   *mockMachineAccessor
 	machineTag names.MachineTag
 	addresses  []net.Addr
+
+	verifyDeadlines []time.Time
 }
 
 var _ = gc.Suite(&MachinerSuite{})
@@ -42,6 +46,7 @@ var _ = gc.Suite(&MachinerSuite{})
 func (s *MachinerSuite) SetUpTest(c *gc.C) {
 	s.BaseSuite.SetUpTest(c)
 	s.accessor = &mockMachineAccessor{}
+	s.verifyDeadlines = nil
 	s.accessor.machine.watcher.changes = make(chan struct{})
 	s.accessor.machine.life = params.Alive
 	s.machineTag = names.NewMachineTag("123")
@@ -82,7 +87,8 @@ func (s *MachinerSuite) TestMachinerSetUpMachineNotFound(c *gc.C) {
 		&params.Error{Code: params.CodeNotFound}, // Machine
 	)
 	w, err := machiner.NewMachiner(machiner.Config{
-		s.accessor, s.machineTag, false,
+		MachineAccessor: s.accessor,
+		Tag:             s.machineTag,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	err = stopWorker(w)
@@ -107,7 +113,8 @@ func (s *MachinerSuite) testMachinerMachineRefreshNotFoundOrUnauthorized(c *gc.C
 		&params.Error{Code: code}, // Refresh
 	)
 	w, err := machiner.NewMachiner(machiner.Config{
-		s.accessor, s.machineTag, false,
+		MachineAccessor: s.accessor,
+		Tag:             s.machineTag,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	s.accessor.machine.watcher.changes <- struct{}{}
@@ -433,6 +440,205 @@ func (s *MachinerSuite) TestAliveErrorGetObservedNetworkConfig(c *gc.C) {
 	)
 }
 
+var netplanDevices = []netplan.DeviceConfig{{
+	Name:       "eno1",
+	Type:       netplan.TypeEthernet,
+	MACAddress: "00:11:22:33:44:55",
+	ParentName: "bond0",
+}, {
+	Name:       "bond0",
+	Type:       netplan.TypeBond,
+	MACAddress: "00:11:22:33:44:55",
+	BondMode:   "802.3ad",
+	Addresses:  []string{"10.0.0.5/24"},
+}}
+
+func (s *MachinerSuite) makeNetplanMachiner(c *gc.C, dir string) worker.Worker {
+	w, err := machiner.NewMachiner(machiner.Config{
+		MachineAccessor:  s.accessor,
+		Tag:              s.machineTag,
+		NetplanDirectory: dir,
+		VerifyAPIConnection: func(deadline time.Time) error {
+			s.verifyDeadlines = append(s.verifyDeadlines, deadline)
+			return nil
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return w
+}
+
+func (s *MachinerSuite) TestNetplanStatusReported(c *gc.C) {
+	dir := c.MkDir()
+	actual := `
+network:
+  version: 2
+  ethernets:
+    eno1:
+      match:
+        macaddress: "00:11:22:33:44:55"
+      set-name: eno1
+`[1:]
+	err := ioutil.WriteFile(filepath.Join(dir, "50-cloud-init.yaml"), []byte(actual), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.accessor.machine.netplanDevices = netplanDevices
+	s.PatchValue(machiner.ApplyNetplan, func(netplan.ApplyParams) (*netplan.ActivationResult, error) {
+		c.Fatalf("unexpected netplan apply")
+		return nil, nil
+	})
+
+	mr := s.makeNetplanMachiner(c, dir)
+	s.accessor.machine.watcher.changes <- struct{}{}
+	c.Assert(stopWorker(mr), jc.ErrorIsNil)
+
+	s.accessor.machine.CheckCallNames(c,
+		"SetMachineAddresses",
+		"SetStatus",
+		"Watch",
+		"Refresh",
+		"Life",
+		"NetplanConfig",
+		"SetNetplanStatus",
+	)
+	s.accessor.machine.CheckCall(c, 6, "SetNetplanStatus", actual, "")
+}
+
+func (s *MachinerSuite) TestNetplanNotSupported(c *gc.C) {
+	s.accessor.machine.SetErrors(
+		nil,                                   // SetMachineAddresses
+		nil,                                   // SetStatus
+		nil,                                   // Watch
+		nil,                                   // Refresh
+		errors.NotSupportedf("NetplanConfig"), // NetplanConfig
+	)
+
+	mr := s.makeNetplanMachiner(c, c.MkDir())
+	s.accessor.machine.watcher.changes <- struct{}{}
+	c.Assert(stopWorker(mr), jc.ErrorIsNil)
+
+	s.accessor.machine.CheckCallNames(c,
+		"SetMachineAddresses",
+		"SetStatus",
+		"Watch",
+		"Refresh",
+		"Life",
+		"NetplanConfig",
+	)
+}
+
+func (s *MachinerSuite) TestNetplanApplied(c *gc.C) {
+	dir := c.MkDir()
+	s.accessor.machine.netplanDevices = netplanDevices
+	s.accessor.machine.manageNetplan = true
+	desired, err := netplan.Render(netplanDevices)
+	c.Assert(err, jc.ErrorIsNil)
+	out, err := netplan.Marshal(&desired)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var applied []netplan.ApplyParams
+	s.PatchValue(machiner.ApplyNetplan, func(args netplan.ApplyParams) (*netplan.ActivationResult, error) {
+		applied = append(applied, args)
+		err := ioutil.WriteFile(filepath.Join(dir, "99-juju.yaml"), out, 0644)
+		return &netplan.ActivationResult{}, err
+	})
+
+	mr := s.makeNetplanMachiner(c, dir)
+	s.accessor.machine.watcher.changes <- struct{}{}
+	// The applied configuration matches the desired one, so it is not
+	// applied again.
+	s.accessor.machine.watcher.changes <- struct{}{}
+	c.Assert(stopWorker(mr), jc.ErrorIsNil)
+
+	c.Assert(applied, gc.HasLen, 1)
+	c.Check(applied[0].Directory, gc.Equals, dir)
+	c.Check(applied[0].Netplan.Network, jc.DeepEquals, desired.Network)
+	c.Check(applied[0].VerifyTimeout, gc.Equals, time.Minute)
+	deadline := time.Now()
+	c.Assert(applied[0].Verify(deadline), jc.ErrorIsNil)
+	c.Check(s.verifyDeadlines, jc.DeepEquals, []time.Time{deadline})
+	s.accessor.machine.CheckCallNames(c,
+		"SetMachineAddresses",
+		"SetStatus",
+		"Watch",
+		"Refresh",
+		"Life",
+		"NetplanConfig",
+		"SetNetplanStatus",
+		"Refresh",
+		"Life",
+		"NetplanConfig",
+		"SetNetplanStatus",
+	)
+	s.accessor.machine.CheckCall(c, 6, "SetNetplanStatus", string(out), "")
+	s.accessor.machine.CheckCall(c, 10, "SetNetplanStatus", string(out), "")
+}
+
+func (s *MachinerSuite) TestNetplanOtherDevicesLeftAlone(c *gc.C) {
+	dir := c.MkDir()
+	s.accessor.machine.netplanDevices = netplanDevices
+	s.accessor.machine.manageNetplan = true
+	other := `
+network:
+  version: 2
+  ethernets:
+    eno9:
+      match:
+        macaddress: "00:11:22:33:44:99"
+      set-name: eno9
+`[1:]
+	err := ioutil.WriteFile(filepath.Join(dir, "50-cloud-init.yaml"), []byte(other), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	desired, err := netplan.Render(netplanDevices)
+	c.Assert(err, jc.ErrorIsNil)
+	out, err := netplan.Marshal(&desired)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(dir, "99-juju.yaml"), out, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(machiner.ApplyNetplan, func(netplan.ApplyParams) (*netplan.ActivationResult, error) {
+		c.Fatalf("unexpected netplan apply")
+		return nil, nil
+	})
+
+	// The desired configuration is merged with the other files, so the
+	// devices it does not mention are not removed.
+	mr := s.makeNetplanMachiner(c, dir)
+	s.accessor.machine.watcher.changes <- struct{}{}
+	c.Assert(stopWorker(mr), jc.ErrorIsNil)
+	s.accessor.machine.CheckCallNames(c,
+		"SetMachineAddresses",
+		"SetStatus",
+		"Watch",
+		"Refresh",
+		"Life",
+		"NetplanConfig",
+		"SetNetplanStatus",
+	)
+}
+
+func (s *MachinerSuite) TestNetplanApplyFailure(c *gc.C) {
+	dir := c.MkDir()
+	s.accessor.machine.netplanDevices = netplanDevices
+	s.accessor.machine.manageNetplan = true
+
+	var applied int
+	s.PatchValue(machiner.ApplyNetplan, func(netplan.ApplyParams) (*netplan.ActivationResult, error) {
+		applied++
+		return nil, errors.New("netplan apply error code 1")
+	})
+
+	mr := s.makeNetplanMachiner(c, dir)
+	s.accessor.machine.watcher.changes <- struct{}{}
+	// The same desired configuration is not applied again after it
+	// failed, but the failure is still reported.
+	s.accessor.machine.watcher.changes <- struct{}{}
+	c.Assert(stopWorker(mr), jc.ErrorIsNil)
+
+	c.Check(applied, gc.Equals, 1)
+	empty, err := netplan.Marshal(&netplan.Netplan{})
+	c.Assert(err, jc.ErrorIsNil)
+	s.accessor.machine.CheckCall(c, 6, "SetNetplanStatus", string(empty), "netplan apply error code 1")
+	s.accessor.machine.CheckCall(c, 10, "SetNetplanStatus", string(empty), "netplan apply error code 1")
+}
+
 func (s *MachinerSuite) makeMachiner(
 	c *gc.C,
 	ignoreAddresses bool,
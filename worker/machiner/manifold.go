@@ -4,17 +4,24 @@
 package machiner
 
 import (
+	"os"
+	"time"
+
 	"github.com/juju/errors"
 	"gopkg.in/juju/names.v3"
 	"gopkg.in/juju/worker.v1"
 	"gopkg.in/juju/worker.v1/dependency"
 
 	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api"
 	apiagent "github.com/juju/juju/api/agent"
 	"github.com/juju/juju/api/base"
 	apimachiner "github.com/juju/juju/api/machiner"
 )
 
+// netplanDirectory is the directory netplan reads its configuration from.
+const netplanDirectory = "/etc/netplan"
+
 // ManifoldConfig defines the names of the manifolds on which a
 // Manifold will depend.
 type ManifoldConfig struct {
@@ -85,11 +92,35 @@ func newWorker(a agent.Agent, apiCaller base.APICaller) (worker.Worker, error) {
 	if ignoreMachineAddresses {
 		logger.Infof("machine addresses not used, only addresses from provider")
 	}
+	// Only machines configured by netplan report or manage it.
+	var netplanDir string
+	if info, err := os.Stat(netplanDirectory); err == nil && info.IsDir() {
+		netplanDir = netplanDirectory
+	}
+	// A netplan configuration is verified by dialing the controller
+	// afresh, since the existing connection may outlive a broken
+	// network configuration.
+	verifyAPIConnection := func(deadline time.Time) error {
+		info, ok := currentConfig.APIInfo()
+		if !ok {
+			return errors.New("API info not available")
+		}
+		conn, err := api.Open(info, api.DialOpts{
+			Timeout:    deadline.Sub(time.Now()),
+			RetryDelay: time.Second,
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return errors.Trace(conn.Close())
+	}
 	accessor := APIMachineAccessor{apimachiner.NewState(apiCaller)}
 	w, err := NewMachiner(Config{
 		MachineAccessor:              accessor,
 		Tag:                          tag.(names.MachineTag),
 		ClearMachineAddressesOnStart: ignoreMachineAddresses,
+		NetplanDirectory:             netplanDir,
+		VerifyAPIConnection:          verifyAPIConnection,
 	})
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot start machiner worker")
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/network/netplan"
 	"github.com/juju/juju/worker/machiner"
 )
 
@@ -33,6 +34,9 @@ type mockMachine struct {
 	gitjujutesting.Stub
 	watcher mockWatcher
 	life    params.Life
+
+	netplanDevices []netplan.DeviceConfig
+	manageNetplan  bool
 }
 
 func (m *mockMachine) Refresh() error {
@@ -60,6 +64,19 @@ func (m *mockMachine) SetObservedNetworkConfig(netConfig []params.NetworkConfig)
 	return m.NextErr()
 }
 
+func (m *mockMachine) NetplanConfig() ([]netplan.DeviceConfig, bool, error) {
+	m.MethodCall(m, "NetplanConfig")
+	if err := m.NextErr(); err != nil {
+		return nil, false, err
+	}
+	return m.netplanDevices, m.manageNetplan, nil
+}
+
+func (m *mockMachine) SetNetplanStatus(actual, applyError string) error {
+	m.MethodCall(m, "SetNetplanStatus", actual, applyError)
+	return m.NextErr()
+}
+
 func (m *mockMachine) SetStatus(status status.Status, info string, data map[string]interface{}) error {
 	m.MethodCall(m, "SetStatus", status, info, data)
 	return m.NextErr()
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/network/netplan"
 )
 
 type MachineAccessor interface {
@@ -25,6 +26,8 @@ type Machine interface {
 	SetStatus(machineStatus status.Status, info string, data map[string]interface{}) error
 	Watch() (watcher.NotifyWatcher, error)
 	SetObservedNetworkConfig(netConfig []params.NetworkConfig) error
+	NetplanConfig() ([]netplan.DeviceConfig, bool, error)
+	SetNetplanStatus(actual, applyError string) error
 }
 
 type APIMachineAccessor struct {
@@ -233,6 +233,10 @@ func (task *provisionerTask) processMachinesWithTransientErrors() error {
 			continue
 		}
 		machine := result.Machine
+		if err := task.resetInterruptedInstance(machine); err != nil {
+			task.logger.Errorf("cannot replace interrupted instance of machine %q: %v", machine.Id(), err)
+			continue
+		}
 		if err := machine.SetStatus(status.Pending, "", nil); err != nil {
 			task.logger.Errorf("cannot reset status of machine %q: %v", machine.Id(), err)
 			continue
@@ -253,6 +257,24 @@ func (task *provisionerTask) processMachinesWithTransientErrors() error {
 	return task.startMachines(pending)
 }
 
+// resetInterruptedInstance stops the instance of a machine which the
+// cloud has interrupted, and clears its record so that the machine can
+// be provisioned again. Machines without an instance are left alone.
+func (task *provisionerTask) resetInterruptedInstance(machine apiprovisioner.MachineProvisioner) error {
+	instId, err := machine.InstanceId()
+	if params.IsCodeNotProvisioned(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	task.logger.Infof("replacing interrupted instance %q of machine %q", instId, machine.Id())
+	if err := task.broker.StopInstances(task.cloudCallCtx, instId); err != nil {
+		return errors.Annotate(err, "broker failed to stop instance")
+	}
+	task.removeMachineFromAZMap(machine)
+	return errors.Trace(machine.ResetInstance())
+}
+
 func (task *provisionerTask) processMachines(ids []string) error {
 	task.logger.Tracef("processMachines(%v)", ids)
 
@@ -268,7 +290,7 @@ func (task *provisionerTask) processMachines(ids []string) error {
 	}
 
 	// Stop all machines that are dead
-	stopping := task.instancesForDeadMachines(dead)
+	stopping, dead := task.instancesForDeadMachines(dead)
 
 	// Find running instances that have no machines associated
 	unknown, err := task.findUnknownInstances(stopping)
@@ -507,15 +529,31 @@ func (task *provisionerTask) findUnknownInstances(stopping []instances.Instance)
 
 // instancesForDeadMachines returns a list of instances.Instance that represent
 // the list of dead machines running in the provider. Missing machines are
-// omitted from the list.
-func (task *provisionerTask) instancesForDeadMachines(deadMachines []apiprovisioner.MachineProvisioner) []instances.Instance {
+// omitted from the list. It also returns the dead machines which may be
+// removed; machines which are protected from termination, or whose
+// protection cannot be determined, are left in place along with their
+// instances until they are unprotected.
+func (task *provisionerTask) instancesForDeadMachines(deadMachines []apiprovisioner.MachineProvisioner) ([]instances.Instance, []apiprovisioner.MachineProvisioner) {
 	var instances []instances.Instance
+	var removable []apiprovisioner.MachineProvisioner
 	for _, machine := range deadMachines {
 		instId, err := machine.InstanceId()
 		if err == nil {
 			keep, _ := machine.KeepInstance()
 			if keep {
 				task.logger.Debugf("machine %v is dead but keep-instance is true", instId)
+				removable = append(removable, machine)
+				continue
+			}
+			protected, err := machine.Protected()
+			if err != nil {
+				task.logger.Warningf("cannot determine protection for machine %v, not stopping instance %v: %v", machine, instId, err)
+				continue
+			}
+			if protected {
+				// The machine is kept in state, so its instance is not
+				// harvested as unknown, until it is unprotected.
+				task.logger.Warningf("machine %v is dead but protected from termination, not stopping instance %v", machine, instId)
 				continue
 			}
 			inst, found := task.instances[instId]
@@ -524,8 +562,9 @@ func (task *provisionerTask) instancesForDeadMachines(deadMachines []apiprovisio
 				instances = append(instances, inst)
 			}
 		}
+		removable = append(removable, machine)
 	}
-	return instances
+	return instances, removable
 }
 
 func (task *provisionerTask) stopInstances(instances []instances.Instance) error {
@@ -1200,6 +1239,7 @@ func (task *provisionerTask) startMachine(
 		}
 		return errors.Annotate(err, "cannot set instance info")
 	}
+	task.protectInstance(machine, result.Instance.Id())
 
 	task.logger.Infof(
 		"started machine %s as instance %s with hardware %q, network config %+v, "+
@@ -1216,6 +1256,27 @@ func (task *provisionerTask) startMachine(
 	return nil
 }
 
+// protectInstance applies the provider's native termination protection to
+// the instance if the machine is marked as protected. Failure is logged
+// rather than returned; the machine remains protected within Juju.
+func (task *provisionerTask) protectInstance(machine apiprovisioner.MachineProvisioner, instId instance.Id) {
+	protected, err := machine.Protected()
+	if err != nil {
+		task.logger.Warningf("cannot determine protection for machine %v: %v", machine, err)
+		return
+	}
+	if !protected {
+		return
+	}
+	protector, ok := task.broker.(environs.InstanceProtector)
+	if !ok {
+		return
+	}
+	if err := protector.SetInstanceProtection(task.cloudCallCtx, []instance.Id{instId}, true); err != nil {
+		task.logger.Warningf("cannot protect instance %v for machine %v: %v", instId, machine, err)
+	}
+}
+
 // gatherCharmLXDProfiles consumes the charms LXD Profiles from the different
 // sources. This includes getting the information from the broker.
 func (task *provisionerTask) gatherCharmLXDProfiles(instanceId, machineTag string, machineProfiles []string) []string {
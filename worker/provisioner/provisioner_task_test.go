@@ -173,6 +173,101 @@ func (s *ProvisionerTaskSuite) TestStopInstancesIgnoresMachinesWithKeep(c *gc.C)
 	c.Assert(m1.markForRemoval, jc.IsTrue)
 }
 
+func (s *ProvisionerTaskSuite) TestStopInstancesIgnoresProtectedMachines(c *gc.C) {
+	task := s.newProvisionerTask(c,
+		config.HarvestAll,
+		&mockDistributionGroupFinder{},
+		mockToolsFinder{},
+	)
+	defer workertest.CleanKill(c, task)
+
+	i0 := &testInstance{id: "zero"}
+	i1 := &testInstance{id: "one"}
+	s.instances = []instances.Instance{
+		i0,
+		i1,
+	}
+
+	m0 := &testMachine{
+		id:       "0",
+		life:     params.Dead,
+		instance: i0,
+	}
+	m1 := &testMachine{
+		id:        "1",
+		life:      params.Dead,
+		instance:  i1,
+		protected: true,
+	}
+
+	s.machinesResults = []apiprovisioner.MachineResult{
+		{Machine: m0},
+		{Machine: m1},
+	}
+
+	s.sendModelMachinesChange(c, "0", "1")
+
+	s.waitForTask(c, []string{"AllRunningInstances", "StopInstances"})
+
+	workertest.CleanKill(c, task)
+	close(s.instanceBroker.callsChan)
+	s.instanceBroker.CheckCalls(c, []testing.StubCall{
+		{"AllRunningInstances", []interface{}{s.callCtx}},
+		{"StopInstances", []interface{}{s.callCtx, []instance.Id{"zero"}}},
+	})
+	// The protected machine is kept, along with its instance, so the
+	// instance is not harvested as unknown.
+	c.Assert(m0.markForRemoval, jc.IsTrue)
+	c.Assert(m1.markForRemoval, jc.IsFalse)
+}
+
+func (s *ProvisionerTaskSuite) TestStopInstancesIgnoresMachinesWithUnknownProtection(c *gc.C) {
+	task := s.newProvisionerTask(c,
+		config.HarvestAll,
+		&mockDistributionGroupFinder{},
+		mockToolsFinder{},
+	)
+	defer workertest.CleanKill(c, task)
+
+	i0 := &testInstance{id: "zero"}
+	i1 := &testInstance{id: "one"}
+	s.instances = []instances.Instance{
+		i0,
+		i1,
+	}
+
+	m0 := &testMachine{
+		id:       "0",
+		life:     params.Dead,
+		instance: i0,
+	}
+	m1 := &testMachine{
+		id:           "1",
+		life:         params.Dead,
+		instance:     i1,
+		protectedErr: errors.New("boom"),
+	}
+
+	s.machinesResults = []apiprovisioner.MachineResult{
+		{Machine: m0},
+		{Machine: m1},
+	}
+
+	s.sendModelMachinesChange(c, "0", "1")
+
+	s.waitForTask(c, []string{"AllRunningInstances", "StopInstances"})
+
+	workertest.CleanKill(c, task)
+	close(s.instanceBroker.callsChan)
+	s.instanceBroker.CheckCalls(c, []testing.StubCall{
+		{"AllRunningInstances", []interface{}{s.callCtx}},
+		{"StopInstances", []interface{}{s.callCtx, []instance.Id{"zero"}}},
+	})
+	// The machine is left in place until its protection is known.
+	c.Assert(m0.markForRemoval, jc.IsTrue)
+	c.Assert(m1.markForRemoval, jc.IsFalse)
+}
+
 func (s *ProvisionerTaskSuite) TestProvisionerRetries(c *gc.C) {
 	s.instanceBroker.SetErrors(
 		errors.New("errors 1"),
@@ -524,6 +619,8 @@ type testMachine struct {
 
 	instance     *testInstance
 	keepInstance bool
+	protected    bool
+	protectedErr error
 
 	markForRemoval bool
 	constraints    string
@@ -557,6 +654,10 @@ func (m *testMachine) KeepInstance() (bool, error) {
 	return m.keepInstance, nil
 }
 
+func (m *testMachine) Protected() (bool, error) {
+	return m.protected, m.protectedErr
+}
+
 func (m *testMachine) MarkForRemoval() error {
 	m.markForRemoval = true
 	return nil
@@ -1483,6 +1483,29 @@ func (s *ProvisionerSuite) TestHarvestAllReapsAllTheThings(c *gc.C) {
 	s.waitForRemovalMark(c, m0)
 }
 
+func (s *ProvisionerSuite) TestProvisionerReplacesInterruptedInstance(c *gc.C) {
+	s.PatchValue(&apiserverprovisioner.ErrorRetryWaitDelay, 5*time.Millisecond)
+	p := s.newEnvironProvisioner(c)
+	defer workertest.CleanKill(c, p)
+
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	i0 := s.checkStartInstance(c, m)
+
+	now := time.Now()
+	err = m.SetInstanceStatus(status.StatusInfo{
+		Status:  status.ProvisioningError,
+		Message: "spot instance interrupted",
+		Data:    map[string]interface{}{"transient": true, "interrupted": true},
+		Since:   &now,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.checkStopInstances(c, i0)
+	i1 := s.checkStartInstance(c, m)
+	c.Assert(i1.Id(), gc.Not(gc.Equals), i0.Id())
+}
+
 func (s *ProvisionerSuite) TestProvisionerObservesMachineJobs(c *gc.C) {
 	s.PatchValue(&apiserverprovisioner.ErrorRetryWaitDelay, 5*time.Millisecond)
 	broker := &mockBroker{Environ: s.Environ, retryCount: make(map[string]int),
@@ -0,0 +1,92 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resourcetagger_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/dependency"
+	dt "gopkg.in/juju/worker.v1/dependency/testing"
+
+	"github.com/juju/juju/api/base"
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/worker/common"
+	"github.com/juju/juju/worker/resourcetagger"
+)
+
+type manifoldSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&manifoldSuite{})
+
+func (*manifoldSuite) TestInputs(c *gc.C) {
+	manifold := makeManifold(nil, nil)
+	c.Assert(manifold.Inputs, jc.SameContents, []string{"the-caller", "the-environ"})
+}
+
+func (*manifoldSuite) TestMissingCaller(c *gc.C) {
+	manifold := makeManifold(nil, nil)
+	result, err := manifold.Start(dt.StubContext(nil, map[string]interface{}{
+		"the-caller":  dependency.ErrMissing,
+		"the-environ": &fakeEnviron{},
+	}))
+	c.Assert(result, gc.IsNil)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrMissing)
+}
+
+func (*manifoldSuite) TestMissingEnviron(c *gc.C) {
+	manifold := makeManifold(nil, nil)
+	result, err := manifold.Start(dt.StubContext(nil, map[string]interface{}{
+		"the-caller":  apitesting.APICallerFunc(nil),
+		"the-environ": dependency.ErrMissing,
+	}))
+	c.Assert(result, gc.IsNil)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrMissing)
+}
+
+func (*manifoldSuite) TestWorkerError(c *gc.C) {
+	manifold := makeManifold(nil, errors.New("splat"))
+	result, err := manifold.Start(dt.StubContext(nil, map[string]interface{}{
+		"the-caller":  apitesting.APICallerFunc(nil),
+		"the-environ": &fakeEnviron{},
+	}))
+	c.Assert(result, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "splat")
+}
+
+func (*manifoldSuite) TestSuccess(c *gc.C) {
+	w := fakeWorker{name: "tagger"}
+	manifold := makeManifold(&w, nil)
+	result, err := manifold.Start(dt.StubContext(nil, map[string]interface{}{
+		"the-caller":  apitesting.APICallerFunc(nil),
+		"the-environ": &fakeEnviron{},
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, &w)
+}
+
+func makeManifold(workerResult worker.Worker, workerError error) dependency.Manifold {
+	return resourcetagger.Manifold(resourcetagger.ManifoldConfig{
+		APICallerName: "the-caller",
+		EnvironName:   "the-environ",
+		Logger:        loggo.GetLogger("test"),
+		NewWorker: func(resourcetagger.Facade, environs.Environ, common.CredentialAPI, resourcetagger.Logger) (worker.Worker, error) {
+			return workerResult, workerError
+		},
+		NewCredentialValidatorFacade: func(base.APICaller) (common.CredentialAPI, error) {
+			return &fakeCredentialAPI{}, nil
+		},
+	})
+}
+
+type fakeWorker struct {
+	worker.Worker
+	name string
+}
@@ -0,0 +1,162 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package resourcetagger provides a worker which applies changes to a
+// model's resource-tags config to the cloud resources already created
+// for the model. Resources created afterwards are tagged by the
+// provider when they are created.
+package resourcetagger
+
+import (
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/worker/common"
+)
+
+// Logger represents the methods used by the worker to log details.
+type Logger interface {
+	Debugf(string, ...interface{})
+	Infof(string, ...interface{})
+}
+
+// Facade defines the interface we require from the model
+// config facade.
+type Facade interface {
+	WatchForModelConfigChanges() (watcher.NotifyWatcher, error)
+	ModelConfig() (*config.Config, error)
+}
+
+// Tagger updates the tags of the model's cloud resources whenever
+// the model's resource-tags config changes.
+type Tagger struct {
+	API         Facade
+	Updater     environs.ResourceTagUpdater
+	CallContext context.ProviderCallContext
+	Logger      Logger
+
+	// applied holds the tags last applied by the tagger,
+	// so that tags removed from the config can be removed
+	// from the resources.
+	applied map[string]string
+}
+
+// NewWorker returns a worker which keeps the tags of the model's cloud
+// resources up to date with the model's resource-tags config. If the
+// environ cannot update resource tags, the worker does nothing.
+func NewWorker(api Facade, env environs.Environ, credentialAPI common.CredentialAPI, logger Logger) (worker.Worker, error) {
+	updater, _ := env.(environs.ResourceTagUpdater)
+	w, err := watcher.NewNotifyWorker(watcher.NotifyConfig{
+		Handler: &Tagger{
+			API:         api,
+			Updater:     updater,
+			CallContext: common.NewCloudCallContext(credentialAPI, nil),
+			Logger:      logger,
+		},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// SetUp (part of watcher.NotifyHandler) starts watching for
+// model config changes.
+func (t *Tagger) SetUp() (watcher.NotifyWatcher, error) {
+	return t.API.WatchForModelConfigChanges()
+}
+
+// Handle (part of watcher.NotifyHandler) applies the model's
+// resource tags if they have changed since they were last applied.
+//
+// The keys of the tags applied are recorded on the resources, as a
+// marker tag for each key, so that tags removed from the config while
+// the worker was not running are removed when it starts. The tags are applied in full when the
+// worker starts, as their values may also have changed.
+func (t *Tagger) Handle(<-chan struct{}) error {
+	if t.Updater == nil {
+		return nil
+	}
+	cfg, err := t.API.ModelConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resourceTags, _ := cfg.ResourceTags()
+
+	var previous set.Strings
+	if t.applied == nil {
+		if previous, err = t.recordedKeys(); err != nil {
+			return errors.Trace(err)
+		}
+	} else if tagsEqual(resourceTags, t.applied) {
+		return nil
+	} else {
+		previous = set.NewStrings()
+		for k := range t.applied {
+			previous.Add(k)
+		}
+	}
+	var removed []string
+	for _, k := range previous.SortedValues() {
+		if _, ok := resourceTags[k]; !ok {
+			removed = append(removed, k)
+		}
+	}
+	if len(resourceTags) == 0 && len(removed) == 0 {
+		t.applied = map[string]string{}
+		return nil
+	}
+
+	t.Logger.Infof("updating resource tags to %v, removing %v", resourceTags, removed)
+	update := make(map[string]string, 2*len(resourceTags))
+	for k, v := range resourceTags {
+		update[k] = v
+		update[tags.JujuResourceTagPrefix+k] = "true"
+	}
+	remove := make([]string, 0, 2*len(removed))
+	for _, k := range removed {
+		remove = append(remove, k, tags.JujuResourceTagPrefix+k)
+	}
+	if err := t.Updater.UpdateResourceTags(t.CallContext, update, remove); err != nil {
+		return errors.Annotate(err, "updating resource tags")
+	}
+	t.applied = make(map[string]string, len(resourceTags))
+	for k, v := range resourceTags {
+		t.applied[k] = v
+	}
+	return nil
+}
+
+// recordedKeys returns the keys of the tags recorded
+// as applied to any of the model's resources.
+func (t *Tagger) recordedKeys() (set.Strings, error) {
+	markers, err := t.Updater.ResourceTagKeys(t.CallContext, tags.JujuResourceTagPrefix)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting applied resource tags")
+	}
+	keys := set.NewStrings()
+	for _, marker := range markers {
+		keys.Add(strings.TrimPrefix(marker, tags.JujuResourceTagPrefix))
+	}
+	return keys, nil
+}
+
+func tagsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,225 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resourcetagger_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1/workertest"
+
+	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/core/watcher/watchertest"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/context"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/resourcetagger"
+)
+
+type taggerSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&taggerSuite{})
+
+func (s *taggerSuite) TestErrorWatching(c *gc.C) {
+	api := &fakeAPI{Stub: &testing.Stub{}}
+	api.SetErrors(errors.New("blam"))
+	w, err := resourcetagger.NewWorker(api, &fakeEnviron{}, &fakeCredentialAPI{}, loggo.GetLogger("test"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = workertest.CheckKilled(c, w)
+	c.Check(err, gc.ErrorMatches, "blam")
+	api.CheckCallNames(c, "WatchForModelConfigChanges")
+}
+
+func (s *taggerSuite) TestHandleAppliesTags(c *gc.C) {
+	api := &fakeAPI{Stub: &testing.Stub{}}
+	updater := &fakeEnviron{Stub: &testing.Stub{}}
+	t := s.newTagger(api, updater)
+
+	api.cfg = modelConfig(c, map[string]string{"owner": "fred"})
+	c.Assert(t.Handle(nil), jc.ErrorIsNil)
+	updater.CheckCalls(c, []testing.StubCall{
+		{"ResourceTagKeys", []interface{}{"juju-resource-tag-"}},
+		{"UpdateResourceTags", []interface{}{map[string]string{
+			"owner":                   "fred",
+			"juju-resource-tag-owner": "true",
+		}, []string{}}},
+	})
+}
+
+func (s *taggerSuite) TestHandleRemovesTags(c *gc.C) {
+	api := &fakeAPI{Stub: &testing.Stub{}}
+	updater := &fakeEnviron{Stub: &testing.Stub{}}
+	t := s.newTagger(api, updater)
+
+	api.cfg = modelConfig(c, map[string]string{"owner": "fred", "dept": "eng"})
+	c.Assert(t.Handle(nil), jc.ErrorIsNil)
+	api.cfg = modelConfig(c, map[string]string{"owner": "mary"})
+	c.Assert(t.Handle(nil), jc.ErrorIsNil)
+	updater.CheckCalls(c, []testing.StubCall{
+		{"ResourceTagKeys", []interface{}{"juju-resource-tag-"}},
+		{"UpdateResourceTags", []interface{}{map[string]string{
+			"owner":                   "fred",
+			"dept":                    "eng",
+			"juju-resource-tag-owner": "true",
+			"juju-resource-tag-dept":  "true",
+		}, []string{}}},
+		{"UpdateResourceTags", []interface{}{map[string]string{
+			"owner":                   "mary",
+			"juju-resource-tag-owner": "true",
+		}, []string{"dept", "juju-resource-tag-dept"}}},
+	})
+}
+
+func (s *taggerSuite) TestHandleRemovesRecordedTags(c *gc.C) {
+	api := &fakeAPI{Stub: &testing.Stub{}}
+	updater := &fakeEnviron{
+		Stub: &testing.Stub{},
+		keys: []string{
+			"juju-resource-tag-cost-centre",
+			"juju-resource-tag-dept",
+			// Keys may contain spaces.
+			"juju-resource-tag-owner name",
+		},
+	}
+	t := s.newTagger(api, updater)
+
+	// Tags applied before the worker started, and since
+	// removed from the config, are removed.
+	api.cfg = modelConfig(c, map[string]string{"owner": "mary"})
+	c.Assert(t.Handle(nil), jc.ErrorIsNil)
+	updater.CheckCalls(c, []testing.StubCall{
+		{"ResourceTagKeys", []interface{}{"juju-resource-tag-"}},
+		{"UpdateResourceTags", []interface{}{map[string]string{
+			"owner":                   "mary",
+			"juju-resource-tag-owner": "true",
+		}, []string{
+			"cost-centre", "juju-resource-tag-cost-centre",
+			"dept", "juju-resource-tag-dept",
+			"owner name", "juju-resource-tag-owner name",
+		}}},
+	})
+}
+
+func (s *taggerSuite) TestHandleRemovesAllRecordedTags(c *gc.C) {
+	api := &fakeAPI{Stub: &testing.Stub{}}
+	updater := &fakeEnviron{
+		Stub: &testing.Stub{},
+		keys: []string{"juju-resource-tag-owner"},
+	}
+	t := s.newTagger(api, updater)
+
+	api.cfg = modelConfig(c, nil)
+	c.Assert(t.Handle(nil), jc.ErrorIsNil)
+	updater.CheckCalls(c, []testing.StubCall{
+		{"ResourceTagKeys", []interface{}{"juju-resource-tag-"}},
+		{"UpdateResourceTags", []interface{}{
+			map[string]string{}, []string{"owner", "juju-resource-tag-owner"},
+		}},
+	})
+}
+
+func (s *taggerSuite) TestHandleUnchanged(c *gc.C) {
+	api := &fakeAPI{Stub: &testing.Stub{}}
+	updater := &fakeEnviron{Stub: &testing.Stub{}}
+	t := s.newTagger(api, updater)
+
+	api.cfg = modelConfig(c, map[string]string{"owner": "fred"})
+	c.Assert(t.Handle(nil), jc.ErrorIsNil)
+	c.Assert(t.Handle(nil), jc.ErrorIsNil)
+	updater.CheckCallNames(c, "ResourceTagKeys", "UpdateResourceTags")
+}
+
+func (s *taggerSuite) TestHandleNoTags(c *gc.C) {
+	api := &fakeAPI{Stub: &testing.Stub{}}
+	updater := &fakeEnviron{Stub: &testing.Stub{}}
+	t := s.newTagger(api, updater)
+
+	api.cfg = modelConfig(c, nil)
+	c.Assert(t.Handle(nil), jc.ErrorIsNil)
+	updater.CheckCallNames(c, "ResourceTagKeys")
+}
+
+func (s *taggerSuite) TestHandleUpdateError(c *gc.C) {
+	api := &fakeAPI{Stub: &testing.Stub{}}
+	updater := &fakeEnviron{Stub: &testing.Stub{}}
+	updater.SetErrors(nil, errors.New("boom"))
+	t := s.newTagger(api, updater)
+
+	api.cfg = modelConfig(c, map[string]string{"owner": "fred"})
+	c.Assert(t.Handle(nil), gc.ErrorMatches, "updating resource tags: boom")
+}
+
+func (s *taggerSuite) TestHandleRecordedTagsError(c *gc.C) {
+	api := &fakeAPI{Stub: &testing.Stub{}}
+	updater := &fakeEnviron{Stub: &testing.Stub{}}
+	updater.SetErrors(errors.New("boom"))
+	t := s.newTagger(api, updater)
+
+	api.cfg = modelConfig(c, map[string]string{"owner": "fred"})
+	c.Assert(t.Handle(nil), gc.ErrorMatches, "getting applied resource tags: boom")
+	updater.CheckCallNames(c, "ResourceTagKeys")
+}
+
+func (s *taggerSuite) TestHandleNoUpdater(c *gc.C) {
+	api := &fakeAPI{Stub: &testing.Stub{}}
+	t := resourcetagger.Tagger{API: api, Logger: loggo.GetLogger("test")}
+	c.Assert(t.Handle(nil), jc.ErrorIsNil)
+	api.CheckNoCalls(c)
+}
+
+func (s *taggerSuite) newTagger(api resourcetagger.Facade, updater environs.ResourceTagUpdater) *resourcetagger.Tagger {
+	return &resourcetagger.Tagger{
+		API:         api,
+		Updater:     updater,
+		CallContext: context.NewCloudCallContext(),
+		Logger:      loggo.GetLogger("test"),
+	}
+}
+
+func modelConfig(c *gc.C, tags map[string]string) *config.Config {
+	attrs := coretesting.Attrs{}
+	if tags != nil {
+		attrs[config.ResourceTagsKey] = tags
+	}
+	return coretesting.CustomModelConfig(c, attrs)
+}
+
+type fakeAPI struct {
+	*testing.Stub
+	cfg *config.Config
+}
+
+func (a *fakeAPI) WatchForModelConfigChanges() (watcher.NotifyWatcher, error) {
+	a.AddCall("WatchForModelConfigChanges")
+	if err := a.NextErr(); err != nil {
+		return nil, err
+	}
+	return watchertest.NewMockNotifyWatcher(make(chan struct{})), nil
+}
+
+func (a *fakeAPI) ModelConfig() (*config.Config, error) {
+	a.AddCall("ModelConfig")
+	return a.cfg, a.NextErr()
+}
+
+type fakeEnviron struct {
+	environs.Environ
+	*testing.Stub
+	keys []string
+}
+
+func (e *fakeEnviron) ResourceTagKeys(ctx context.ProviderCallContext, prefix string) ([]string, error) {
+	e.AddCall("ResourceTagKeys", prefix)
+	return e.keys, e.NextErr()
+}
+
+func (e *fakeEnviron) UpdateResourceTags(ctx context.ProviderCallContext, tags map[string]string, removed []string) error {
+	e.AddCall("UpdateResourceTags", tags, removed)
+	return e.NextErr()
+}
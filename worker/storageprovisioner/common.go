@@ -193,6 +193,10 @@ func sourceParams(
 	if baseStorageDir != "" {
 		storageDir := filepath.Join(baseStorageDir, sourceName)
 		attrs[storage.ConfigStorageDir] = storageDir
+		// Keys for encrypted volumes are kept beside, rather than
+		// within, the storage directory.
+		keyDir := filepath.Join(filepath.Dir(baseStorageDir), "storage-keys", sourceName)
+		attrs[storage.ConfigKeyDir] = keyDir
 	}
 	sourceConfig, err := storage.NewConfig(sourceName, providerType, attrs)
 	if err != nil {
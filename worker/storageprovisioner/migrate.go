@@ -0,0 +1,222 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	stdcontext "context"
+	"os/exec"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/worker.v1/catacomb"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// copyBlockDevice copies the contents of the block device at the
+// source path to the block device at the target path. The target
+// device must be at least as large as the source device. The source
+// device must not be in use; the controller only hands out the
+// parameters of a migration once the units using the storage have
+// run their storage-detaching hooks. The copy is abandoned, and the
+// dd process killed, if the context is cancelled.
+var copyBlockDevice = func(ctx stdcontext.Context, source, target string) error {
+	cmd := exec.CommandContext(
+		ctx,
+		"dd",
+		"if="+source,
+		"of="+target,
+		"bs=4M",
+		"conv=fsync",
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Annotatef(err, "dd failed: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// storageMigrationsChanged is called when the storage migration watcher
+// reports changes; a migration operation is scheduled for each of the
+// migrations reported.
+func storageMigrationsChanged(ctx *context, changes []string) error {
+	ops := make([]scheduleOp, len(changes))
+	for i, id := range changes {
+		op := &migrateStorageOp{id: id}
+		ctx.schedule.Remove(op.key())
+		ops[i] = op
+	}
+	scheduleOperations(ctx, ops...)
+	return nil
+}
+
+// migrateStorage starts copying the data of the storage migrations
+// with the specified IDs from their source block devices to their
+// target block devices. Each copy runs in its own worker, so that the
+// storage provisioner is not blocked for the length of the copy; the
+// outcome is reported back to the storage provisioner, which records
+// it in state. Migrations whose storage is still in use, or whose
+// target volume is not yet attached and visible on the machine, are
+// retried later; migrations that are no longer waiting to be copied,
+// or that are already being copied, are dropped.
+func migrateStorage(ctx *context, ops map[migrationKey]*migrateStorageOp) error {
+	ids := make([]string, 0, len(ops))
+	for key := range ops {
+		if _, ok := ctx.storageCopiers[key.id]; ok {
+			continue
+		}
+		ids = append(ids, key.id)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	results, err := ctx.config.Volumes.StorageMigrationParams(ids)
+	if err != nil {
+		return errors.Annotate(err, "getting storage migration parameters")
+	}
+	var reschedule []scheduleOp
+	for i, result := range results {
+		op := ops[migrationKey{ids[i]}]
+		if result.Error != nil {
+			switch {
+			case params.IsCodeNotFound(result.Error), params.IsCodeUnauthorized(result.Error):
+				// The migration has completed or failed, or
+				// is not this storage provisioner's to perform.
+			case params.IsCodeNotProvisioned(result.Error):
+				ctx.config.Logger.Debugf("storage migration %q is not ready: %v", ids[i], result.Error)
+				reschedule = append(reschedule, op)
+			default:
+				return errors.Annotatef(
+					result.Error, "getting parameters for storage migration %q", ids[i],
+				)
+			}
+			continue
+		}
+		if result.Result.MachineTag != ctx.config.Scope.String() {
+			continue
+		}
+		ctx.config.Logger.Debugf(
+			"copying storage migration %q from %s to %s",
+			ids[i], result.Result.SourceDevicePath, result.Result.TargetDevicePath,
+		)
+		w, err := newStorageCopier(
+			ids[i],
+			result.Result.SourceDevicePath,
+			result.Result.TargetDevicePath,
+			ctx.storageMigrationOutcomes,
+		)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := ctx.addWorker(w); err != nil {
+			return errors.Trace(err)
+		}
+		ctx.storageCopiers[ids[i]] = w
+	}
+	scheduleOperations(ctx, reschedule...)
+	return nil
+}
+
+// storageMigrationCopied is called when a storage copier reports the
+// outcome of copying a storage migration's data; the outcome is
+// recorded in state.
+func storageMigrationCopied(ctx *context, outcome params.StorageMigrationOutcome) error {
+	delete(ctx.storageCopiers, outcome.Id)
+	errorResults, err := ctx.config.Volumes.FinishStorageMigrations(
+		[]params.StorageMigrationOutcome{outcome},
+	)
+	if err != nil {
+		return errors.Annotate(err, "publishing storage migration to state")
+	}
+	if err := errorResults[0].Error; err != nil {
+		ctx.config.Logger.Errorf(
+			"publishing storage migration %q to state: %v",
+			outcome.Id, err,
+		)
+	}
+	return nil
+}
+
+// storageCopier is a worker that copies the data of a single storage
+// migration, and sends the outcome to the storage provisioner. If the
+// worker is killed before the copy completes, the copy is abandoned.
+type storageCopier struct {
+	catacomb catacomb.Catacomb
+	id       string
+	source   string
+	target   string
+	out      chan<- params.StorageMigrationOutcome
+}
+
+func newStorageCopier(
+	id, source, target string,
+	out chan<- params.StorageMigrationOutcome,
+) (*storageCopier, error) {
+	w := &storageCopier{
+		id:     id,
+		source: source,
+		target: target,
+		out:    out,
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+func (w *storageCopier) loop() error {
+	ctx, cancel := stdcontext.WithCancel(stdcontext.Background())
+	defer cancel()
+	copied := make(chan error, 1)
+	go func() {
+		copied <- copyBlockDevice(ctx, w.source, w.target)
+	}()
+
+	outcome := params.StorageMigrationOutcome{Id: w.id}
+	select {
+	case <-w.catacomb.Dying():
+		// Kill the copy, and wait for it to exit so that
+		// nothing is left writing to the target device.
+		cancel()
+		<-copied
+		return w.catacomb.ErrDying()
+	case err := <-copied:
+		if err != nil {
+			outcome.Error = errors.Annotate(err, "copying data").Error()
+		}
+	}
+	select {
+	case <-w.catacomb.Dying():
+		return w.catacomb.ErrDying()
+	case w.out <- outcome:
+	}
+	return nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *storageCopier) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *storageCopier) Wait() error {
+	return w.catacomb.Wait()
+}
+
+// migrationKey is the schedule key for storage migration operations.
+type migrationKey struct {
+	id string
+}
+
+type migrateStorageOp struct {
+	exponentialBackoff
+	id string
+}
+
+func (op *migrateStorageOp) key() interface{} {
+	return migrationKey{op.id}
+}
@@ -136,6 +136,9 @@ func (w *mockAttachmentPlansWatcher) Changes() watcher.MachineStorageIdsChannel
 
 type mockVolumeAccessor struct {
 	volumesWatcher         *mockStringsWatcher
+	resizesWatcher         *mockStringsWatcher
+	snapshotsWatcher       *mockStringsWatcher
+	migrationsWatcher      *mockStringsWatcher
 	attachmentsWatcher     *mockAttachmentsWatcher
 	attachmentPlansWatcher *mockAttachmentPlansWatcher
 	blockDevicesWatcher    *mockNotifyWatcher
@@ -143,8 +146,13 @@ type mockVolumeAccessor struct {
 	provisionedVolumes     map[string]params.Volume
 	provisionedAttachments map[params.MachineStorageId]params.VolumeAttachment
 	blockDevices           map[params.MachineStorageId]storage.BlockDevice
+	resizeParams           map[string]params.VolumeResizeParams
+	snapshotParams         map[string]params.VolumeSnapshotParams
 
 	setVolumeInfo               func([]params.Volume) ([]params.ErrorResult, error)
+	setVolumeSnapshotInfo       func([]params.VolumeSnapshotInfo) ([]params.ErrorResult, error)
+	storageMigrationParams      func([]string) ([]params.StorageMigrationParamsResult, error)
+	finishStorageMigrations     func([]params.StorageMigrationOutcome) ([]params.ErrorResult, error)
 	setVolumeAttachmentInfo     func([]params.VolumeAttachment) ([]params.ErrorResult, error)
 	createVolumeAttachmentPlans func([]params.VolumeAttachmentPlan) ([]params.ErrorResult, error)
 }
@@ -316,9 +324,75 @@ func (v *mockVolumeAccessor) VolumeAttachmentPlans([]params.MachineStorageId) ([
 	return []params.VolumeAttachmentPlanResult{}, nil
 }
 
+func (w *mockVolumeAccessor) WatchVolumeResizes(names.Tag) (watcher.StringsWatcher, error) {
+	return w.resizesWatcher, nil
+}
+
+func (v *mockVolumeAccessor) VolumeResizeParams(volumes []names.VolumeTag) ([]params.VolumeResizeParamsResult, error) {
+	results := make([]params.VolumeResizeParamsResult, len(volumes))
+	for i, tag := range volumes {
+		p, ok := v.resizeParams[tag.String()]
+		if !ok {
+			results[i].Error = common.ServerError(errors.NotFoundf("resize request for %s", tag.Id()))
+			continue
+		}
+		results[i].Result = p
+	}
+	return results, nil
+}
+
+func (w *mockVolumeAccessor) WatchVolumeSnapshots(names.Tag) (watcher.StringsWatcher, error) {
+	return w.snapshotsWatcher, nil
+}
+
+func (v *mockVolumeAccessor) VolumeSnapshotParams(ids []string) ([]params.VolumeSnapshotParamsResult, error) {
+	results := make([]params.VolumeSnapshotParamsResult, len(ids))
+	for i, id := range ids {
+		p, ok := v.snapshotParams[id]
+		if !ok {
+			results[i].Error = common.ServerError(errors.NotFoundf("pending storage snapshot %q", id))
+			continue
+		}
+		results[i].Result = p
+	}
+	return results, nil
+}
+
+func (v *mockVolumeAccessor) SetVolumeSnapshotInfo(snapshots []params.VolumeSnapshotInfo) ([]params.ErrorResult, error) {
+	if v.setVolumeSnapshotInfo != nil {
+		return v.setVolumeSnapshotInfo(snapshots)
+	}
+	return make([]params.ErrorResult, len(snapshots)), nil
+}
+
+func (w *mockVolumeAccessor) WatchStorageMigrations(names.MachineTag) (watcher.StringsWatcher, error) {
+	return w.migrationsWatcher, nil
+}
+
+func (v *mockVolumeAccessor) StorageMigrationParams(ids []string) ([]params.StorageMigrationParamsResult, error) {
+	if v.storageMigrationParams != nil {
+		return v.storageMigrationParams(ids)
+	}
+	results := make([]params.StorageMigrationParamsResult, len(ids))
+	for i, id := range ids {
+		results[i].Error = common.ServerError(errors.NotFoundf("pending storage migration %q", id))
+	}
+	return results, nil
+}
+
+func (v *mockVolumeAccessor) FinishStorageMigrations(outcomes []params.StorageMigrationOutcome) ([]params.ErrorResult, error) {
+	if v.finishStorageMigrations != nil {
+		return v.finishStorageMigrations(outcomes)
+	}
+	return make([]params.ErrorResult, len(outcomes)), nil
+}
+
 func newMockVolumeAccessor() *mockVolumeAccessor {
 	return &mockVolumeAccessor{
 		volumesWatcher:         newMockStringsWatcher(),
+		resizesWatcher:         newMockStringsWatcher(),
+		snapshotsWatcher:       newMockStringsWatcher(),
+		migrationsWatcher:      newMockStringsWatcher(),
 		attachmentsWatcher:     newMockAttachmentsWatcher(),
 		attachmentPlansWatcher: newMockAttachmentPlansWatcher(),
 		blockDevicesWatcher:    newMockNotifyWatcher(),
@@ -326,6 +400,8 @@ func newMockVolumeAccessor() *mockVolumeAccessor {
 		provisionedVolumes:     make(map[string]params.Volume),
 		provisionedAttachments: make(map[params.MachineStorageId]params.VolumeAttachment),
 		blockDevices:           make(map[params.MachineStorageId]storage.BlockDevice),
+		resizeParams:           make(map[string]params.VolumeResizeParams),
+		snapshotParams:         make(map[string]params.VolumeSnapshotParams),
 	}
 }
 
@@ -333,9 +409,11 @@ type mockFilesystemAccessor struct {
 	testing.Stub
 	filesystemsWatcher     *mockStringsWatcher
 	attachmentsWatcher     *mockAttachmentsWatcher
+	resizesWatcher         *mockStringsWatcher
 	provisionedMachines    map[string]instance.Id
 	provisionedFilesystems map[string]params.Filesystem
 	provisionedAttachments map[params.MachineStorageId]params.FilesystemAttachment
+	resizeParams           map[string]params.FilesystemResizeParams
 
 	setFilesystemInfo           func([]params.Filesystem) ([]params.ErrorResult, error)
 	setFilesystemAttachmentInfo func([]params.FilesystemAttachment) ([]params.ErrorResult, error)
@@ -461,13 +539,32 @@ func (f *mockFilesystemAccessor) SetFilesystemAttachmentInfo(filesystemAttachmen
 	return make([]params.ErrorResult, len(filesystemAttachments)), nil
 }
 
+func (w *mockFilesystemAccessor) WatchFilesystemResizes(names.Tag) (watcher.StringsWatcher, error) {
+	return w.resizesWatcher, nil
+}
+
+func (f *mockFilesystemAccessor) FilesystemResizeParams(filesystems []names.FilesystemTag) ([]params.FilesystemResizeParamsResult, error) {
+	results := make([]params.FilesystemResizeParamsResult, len(filesystems))
+	for i, tag := range filesystems {
+		p, ok := f.resizeParams[tag.String()]
+		if !ok {
+			results[i].Error = common.ServerError(errors.NotFoundf("resize request for %s", tag.Id()))
+			continue
+		}
+		results[i].Result = p
+	}
+	return results, nil
+}
+
 func newMockFilesystemAccessor() *mockFilesystemAccessor {
 	return &mockFilesystemAccessor{
 		filesystemsWatcher:     newMockStringsWatcher(),
 		attachmentsWatcher:     newMockAttachmentsWatcher(),
+		resizesWatcher:         newMockStringsWatcher(),
 		provisionedMachines:    make(map[string]instance.Id),
 		provisionedFilesystems: make(map[string]params.Filesystem),
 		provisionedAttachments: make(map[params.MachineStorageId]params.FilesystemAttachment),
+		resizeParams:           make(map[string]params.FilesystemResizeParams),
 	}
 }
 
@@ -556,6 +653,9 @@ type dummyProvider struct {
 	releaseFilesystemsFunc       func([]string) ([]error, error)
 	validateVolumeParamsFunc     func(storage.VolumeParams) error
 	validateFilesystemParamsFunc func(storage.FilesystemParams) error
+	resizeVolumesFunc            func([]storage.VolumeResizeParams) ([]storage.ResizeResult, error)
+	resizeFilesystemsFunc        func([]storage.FilesystemResizeParams) ([]storage.ResizeResult, error)
+	createVolumeSnapshotsFunc    func([]storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error)
 }
 
 type dummyVolumeSource struct {
@@ -671,6 +771,30 @@ func (s *dummyVolumeSource) DetachVolumes(ctx context.ProviderCallContext, param
 	return make([]error, len(params)), nil
 }
 
+// ResizeVolumes grows volumes to the requested size.
+func (s *dummyVolumeSource) ResizeVolumes(ctx context.ProviderCallContext, params []storage.VolumeResizeParams) ([]storage.ResizeResult, error) {
+	if s.provider != nil && s.provider.resizeVolumesFunc != nil {
+		return s.provider.resizeVolumesFunc(params)
+	}
+	results := make([]storage.ResizeResult, len(params))
+	for i, p := range params {
+		results[i].Size = p.Size
+	}
+	return results, nil
+}
+
+// CreateVolumeSnapshots snapshots volumes.
+func (s *dummyVolumeSource) CreateVolumeSnapshots(ctx context.ProviderCallContext, params []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+	if s.provider != nil && s.provider.createVolumeSnapshotsFunc != nil {
+		return s.provider.createVolumeSnapshotsFunc(params)
+	}
+	results := make([]storage.CreateVolumeSnapshotsResult, len(params))
+	for i, p := range params {
+		results[i].SnapshotId = "snap-" + p.Snapshot
+	}
+	return results, nil
+}
+
 func (s *dummyFilesystemSource) ValidateFilesystemParams(params storage.FilesystemParams) error {
 	if s.provider != nil && s.provider.validateFilesystemParamsFunc != nil {
 		return s.provider.validateFilesystemParamsFunc(params)
@@ -710,6 +834,18 @@ func (s *dummyFilesystemSource) DestroyFilesystems(ctx context.ProviderCallConte
 }
 
 // ReleaseFilesystems destroys filesystems.
+// ResizeFilesystems grows filesystems to the requested size.
+func (s *dummyFilesystemSource) ResizeFilesystems(ctx context.ProviderCallContext, params []storage.FilesystemResizeParams) ([]storage.ResizeResult, error) {
+	if s.provider != nil && s.provider.resizeFilesystemsFunc != nil {
+		return s.provider.resizeFilesystemsFunc(params)
+	}
+	results := make([]storage.ResizeResult, len(params))
+	for i, p := range params {
+		results[i].Size = p.Size
+	}
+	return results, nil
+}
+
 func (s *dummyFilesystemSource) ReleaseFilesystems(ctx context.ProviderCallContext, filesystemIds []string) ([]error, error) {
 	if s.provider.releaseFilesystemsFunc != nil {
 		return s.provider.releaseFilesystemsFunc(filesystemIds)
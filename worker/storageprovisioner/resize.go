@@ -0,0 +1,310 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/storage"
+)
+
+// volumeResizesChanged is called when the volume resize watcher reports
+// changes; a resize operation is scheduled for each of the volumes that
+// has a pending resize request.
+func volumeResizesChanged(ctx *context, changes []string) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	tags := make([]names.VolumeTag, len(changes))
+	for i, change := range changes {
+		tags[i] = names.NewVolumeTag(change)
+	}
+	results, err := ctx.config.Volumes.VolumeResizeParams(tags)
+	if err != nil {
+		return errors.Annotate(err, "getting volume resize parameters")
+	}
+	var ops []scheduleOp
+	for i, result := range results {
+		if result.Error != nil {
+			if isNoResizePending(result.Error) {
+				continue
+			}
+			return errors.Annotatef(
+				result.Error, "getting resize parameters for %s",
+				names.ReadableString(tags[i]),
+			)
+		}
+		op := &resizeVolumeOp{
+			args: storage.VolumeResizeParams{
+				Tag:      tags[i],
+				VolumeId: result.Result.Info.VolumeId,
+				Size:     result.Result.Size,
+			},
+			provider: storage.ProviderType(result.Result.Provider),
+			info:     result.Result.Info,
+		}
+		// Replace any previously scheduled resize of the volume,
+		// so that the latest requested size is used.
+		ctx.schedule.Remove(op.key())
+		ops = append(ops, op)
+	}
+	scheduleOperations(ctx, ops...)
+	return nil
+}
+
+// filesystemResizesChanged is called when the filesystem resize watcher
+// reports changes; a resize operation is scheduled for each of the
+// filesystems that has a pending resize request.
+func filesystemResizesChanged(ctx *context, changes []string) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	tags := make([]names.FilesystemTag, len(changes))
+	for i, change := range changes {
+		tags[i] = names.NewFilesystemTag(change)
+	}
+	results, err := ctx.config.Filesystems.FilesystemResizeParams(tags)
+	if err != nil {
+		return errors.Annotate(err, "getting filesystem resize parameters")
+	}
+	var ops []scheduleOp
+	for i, result := range results {
+		if result.Error != nil {
+			if isNoResizePending(result.Error) {
+				continue
+			}
+			return errors.Annotatef(
+				result.Error, "getting resize parameters for %s",
+				names.ReadableString(tags[i]),
+			)
+		}
+		op := &resizeFilesystemOp{
+			args: storage.FilesystemResizeParams{
+				Tag:          tags[i],
+				FilesystemId: result.Result.Info.FilesystemId,
+				Size:         result.Result.Size,
+			},
+			provider: storage.ProviderType(result.Result.Provider),
+			info:     result.Result.Info,
+		}
+		ctx.schedule.Remove(op.key())
+		ops = append(ops, op)
+	}
+	scheduleOperations(ctx, ops...)
+	return nil
+}
+
+// isNoResizePending reports whether the error returned when getting
+// resize parameters indicates that there is nothing to resize, either
+// because no resize is pending or because the entity has been removed.
+func isNoResizePending(err *params.Error) bool {
+	return params.IsCodeNotFound(err) || params.IsCodeUnauthorized(err)
+}
+
+// resizeVolumes grows volumes with the specified parameters.
+func resizeVolumes(ctx *context, ops map[resizeKey]*resizeVolumeOp) error {
+	volumeParams := make([]storage.VolumeParams, 0, len(ops))
+	opsByTag := make(map[names.VolumeTag]*resizeVolumeOp)
+	for _, op := range ops {
+		volumeParams = append(volumeParams, storage.VolumeParams{
+			Tag:      op.args.Tag,
+			Provider: op.provider,
+		})
+		opsByTag[op.args.Tag] = op
+	}
+	paramsBySource, volumeSources, err := volumeParamsBySource(
+		ctx.config.StorageDir, volumeParams, ctx.config.Registry,
+	)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var resized []params.Volume
+	var reschedule []scheduleOp
+	var statuses []params.EntityStatusArgs
+	for sourceName, volumeParams := range paramsBySource {
+		resizer, ok := volumeSources[sourceName].(storage.VolumeResizer)
+		if !ok {
+			for _, p := range volumeParams {
+				statuses = append(statuses, params.EntityStatusArgs{
+					Tag:    p.Tag.String(),
+					Status: status.Error.String(),
+					Info:   errors.NotSupportedf("resizing %q volumes", sourceName).Error(),
+				})
+			}
+			continue
+		}
+		args := make([]storage.VolumeResizeParams, len(volumeParams))
+		for i, p := range volumeParams {
+			args[i] = opsByTag[p.Tag].args
+		}
+		ctx.config.Logger.Debugf("resizing volumes from %q: %v", sourceName, args)
+		results, err := resizer.ResizeVolumes(ctx.config.CloudCallContext, args)
+		if err != nil {
+			return errors.Annotatef(err, "resizing volumes from source %q", sourceName)
+		}
+		for i, result := range results {
+			op := opsByTag[args[i].Tag]
+			if result.Error != nil {
+				// Failed to resize the volume; reschedule and update status.
+				reschedule = append(reschedule, op)
+				statuses = append(statuses, params.EntityStatusArgs{
+					Tag:    op.args.Tag.String(),
+					Status: status.Error.String(),
+					Info:   errors.Annotate(result.Error, "resizing volume").Error(),
+				})
+				continue
+			}
+			info := op.info
+			info.Size = result.Size
+			resized = append(resized, params.Volume{
+				VolumeTag: op.args.Tag.String(),
+				Info:      info,
+			})
+			if v, ok := ctx.volumes[op.args.Tag]; ok {
+				v.Size = result.Size
+				ctx.volumes[op.args.Tag] = v
+			}
+		}
+	}
+	scheduleOperations(ctx, reschedule...)
+	setStatus(ctx, statuses)
+	if len(resized) == 0 {
+		return nil
+	}
+	errorResults, err := ctx.config.Volumes.SetVolumeInfo(resized)
+	if err != nil {
+		return errors.Annotate(err, "publishing resized volumes to state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			ctx.config.Logger.Errorf(
+				"publishing resized volume %s to state: %v",
+				resized[i].VolumeTag,
+				result.Error,
+			)
+		}
+	}
+	return nil
+}
+
+// resizeFilesystems grows filesystems with the specified parameters.
+func resizeFilesystems(ctx *context, ops map[resizeKey]*resizeFilesystemOp) error {
+	filesystemParams := make([]storage.FilesystemParams, 0, len(ops))
+	opsByTag := make(map[names.FilesystemTag]*resizeFilesystemOp)
+	for _, op := range ops {
+		filesystemParams = append(filesystemParams, storage.FilesystemParams{
+			Tag:      op.args.Tag,
+			Provider: op.provider,
+		})
+		opsByTag[op.args.Tag] = op
+	}
+	paramsBySource, filesystemSources, err := filesystemParamsBySource(
+		ctx.config.StorageDir,
+		filesystemParams,
+		ctx.managedFilesystemSource,
+		ctx.config.Registry,
+	)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var resized []params.Filesystem
+	var reschedule []scheduleOp
+	var statuses []params.EntityStatusArgs
+	for sourceName, filesystemParams := range paramsBySource {
+		resizer, ok := filesystemSources[sourceName].(storage.FilesystemResizer)
+		if !ok {
+			for _, p := range filesystemParams {
+				statuses = append(statuses, params.EntityStatusArgs{
+					Tag:    p.Tag.String(),
+					Status: status.Error.String(),
+					Info:   errors.NotSupportedf("resizing %q filesystems", sourceName).Error(),
+				})
+			}
+			continue
+		}
+		args := make([]storage.FilesystemResizeParams, len(filesystemParams))
+		for i, p := range filesystemParams {
+			args[i] = opsByTag[p.Tag].args
+		}
+		ctx.config.Logger.Debugf("resizing filesystems from %q: %v", sourceName, args)
+		results, err := resizer.ResizeFilesystems(ctx.config.CloudCallContext, args)
+		if err != nil {
+			return errors.Annotatef(err, "resizing filesystems from source %q", sourceName)
+		}
+		for i, result := range results {
+			op := opsByTag[args[i].Tag]
+			if result.Error != nil {
+				// Failed to resize the filesystem; reschedule and update status.
+				reschedule = append(reschedule, op)
+				statuses = append(statuses, params.EntityStatusArgs{
+					Tag:    op.args.Tag.String(),
+					Status: status.Error.String(),
+					Info:   errors.Annotate(result.Error, "resizing filesystem").Error(),
+				})
+				continue
+			}
+			info := op.info
+			info.Size = result.Size
+			resized = append(resized, params.Filesystem{
+				FilesystemTag: op.args.Tag.String(),
+				Info:          info,
+			})
+			if f, ok := ctx.filesystems[op.args.Tag]; ok {
+				f.Size = result.Size
+				ctx.filesystems[op.args.Tag] = f
+			}
+		}
+	}
+	scheduleOperations(ctx, reschedule...)
+	setStatus(ctx, statuses)
+	if len(resized) == 0 {
+		return nil
+	}
+	errorResults, err := ctx.config.Filesystems.SetFilesystemInfo(resized)
+	if err != nil {
+		return errors.Annotate(err, "publishing resized filesystems to state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			ctx.config.Logger.Errorf(
+				"publishing resized filesystem %s to state: %v",
+				resized[i].FilesystemTag,
+				result.Error,
+			)
+		}
+	}
+	return nil
+}
+
+// resizeKey is the schedule key for resize operations. It is distinct
+// from the keys of the other operations, which are keyed on the tag of
+// the volume or filesystem alone.
+type resizeKey struct {
+	tag names.Tag
+}
+
+type resizeVolumeOp struct {
+	exponentialBackoff
+	args     storage.VolumeResizeParams
+	provider storage.ProviderType
+	info     params.VolumeInfo
+}
+
+func (op *resizeVolumeOp) key() interface{} {
+	return resizeKey{op.args.Tag}
+}
+
+type resizeFilesystemOp struct {
+	exponentialBackoff
+	args     storage.FilesystemResizeParams
+	provider storage.ProviderType
+	info     params.FilesystemInfo
+}
+
+func (op *resizeFilesystemOp) key() interface{} {
+	return resizeKey{op.args.Tag}
+}
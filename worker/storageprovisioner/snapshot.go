@@ -0,0 +1,154 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage"
+)
+
+// volumeSnapshotsChanged is called when the volume snapshot watcher
+// reports changes; a snapshot operation is scheduled for each of the
+// snapshots that is pending creation.
+func volumeSnapshotsChanged(ctx *context, changes []string) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	results, err := ctx.config.Volumes.VolumeSnapshotParams(changes)
+	if err != nil {
+		return errors.Annotate(err, "getting volume snapshot parameters")
+	}
+	var ops []scheduleOp
+	for i, result := range results {
+		if result.Error != nil {
+			// Snapshots that have already been created, or
+			// that have failed, are not retried.
+			if params.IsCodeNotFound(result.Error) || params.IsCodeUnauthorized(result.Error) {
+				continue
+			}
+			return errors.Annotatef(
+				result.Error, "getting parameters for storage snapshot %q", changes[i],
+			)
+		}
+		volumeTag, err := names.ParseVolumeTag(result.Result.VolumeTag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		op := &createSnapshotOp{
+			args: storage.VolumeSnapshotParams{
+				Tag:          volumeTag,
+				VolumeId:     result.Result.VolumeId,
+				Snapshot:     result.Result.Id,
+				ResourceTags: result.Result.Tags,
+			},
+			provider: storage.ProviderType(result.Result.Provider),
+		}
+		ctx.schedule.Remove(op.key())
+		ops = append(ops, op)
+	}
+	scheduleOperations(ctx, ops...)
+	return nil
+}
+
+// createVolumeSnapshots creates volume snapshots with the specified
+// parameters, and records the outcome in state. Snapshots that fail
+// are not retried; the failure is recorded against the snapshot.
+func createVolumeSnapshots(ctx *context, ops map[snapshotKey]*createSnapshotOp) error {
+	volumeParams := make([]storage.VolumeParams, 0, len(ops))
+	opsByTag := make(map[names.VolumeTag][]*createSnapshotOp)
+	for _, op := range ops {
+		if _, ok := opsByTag[op.args.Tag]; !ok {
+			volumeParams = append(volumeParams, storage.VolumeParams{
+				Tag:      op.args.Tag,
+				Provider: op.provider,
+			})
+		}
+		opsByTag[op.args.Tag] = append(opsByTag[op.args.Tag], op)
+	}
+	paramsBySource, volumeSources, err := volumeParamsBySource(
+		ctx.config.StorageDir, volumeParams, ctx.config.Registry,
+	)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var infos []params.VolumeSnapshotInfo
+	for sourceName, volumeParams := range paramsBySource {
+		var args []storage.VolumeSnapshotParams
+		for _, p := range volumeParams {
+			for _, op := range opsByTag[p.Tag] {
+				args = append(args, op.args)
+			}
+			delete(opsByTag, p.Tag)
+		}
+		snapshotter, ok := volumeSources[sourceName].(storage.VolumeSnapshotter)
+		if !ok {
+			for _, arg := range args {
+				infos = append(infos, params.VolumeSnapshotInfo{
+					Id:    arg.Snapshot,
+					Error: errors.NotSupportedf("snapshotting %q volumes", sourceName).Error(),
+				})
+			}
+			continue
+		}
+		ctx.config.Logger.Debugf("creating volume snapshots from %q: %v", sourceName, args)
+		results, err := snapshotter.CreateVolumeSnapshots(ctx.config.CloudCallContext, args)
+		if err != nil {
+			return errors.Annotatef(err, "creating volume snapshots from source %q", sourceName)
+		}
+		for i, result := range results {
+			info := params.VolumeSnapshotInfo{Id: args[i].Snapshot}
+			if result.Error != nil {
+				info.Error = errors.Annotate(result.Error, "creating volume snapshot").Error()
+			} else {
+				info.SnapshotId = result.SnapshotId
+				info.Size = result.Size
+			}
+			infos = append(infos, info)
+		}
+	}
+	// Any remaining volumes are managed by non-dynamic
+	// sources, which cannot create snapshots.
+	for _, ops := range opsByTag {
+		for _, op := range ops {
+			infos = append(infos, params.VolumeSnapshotInfo{
+				Id:    op.args.Snapshot,
+				Error: errors.NotSupportedf("snapshotting %q volumes", op.provider).Error(),
+			})
+		}
+	}
+	if len(infos) == 0 {
+		return nil
+	}
+	errorResults, err := ctx.config.Volumes.SetVolumeSnapshotInfo(infos)
+	if err != nil {
+		return errors.Annotate(err, "publishing volume snapshots to state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			ctx.config.Logger.Errorf(
+				"publishing storage snapshot %q to state: %v",
+				infos[i].Id, result.Error,
+			)
+		}
+	}
+	return nil
+}
+
+// snapshotKey is the schedule key for snapshot operations.
+type snapshotKey struct {
+	id string
+}
+
+type createSnapshotOp struct {
+	exponentialBackoff
+	args     storage.VolumeSnapshotParams
+	provider storage.ProviderType
+}
+
+func (op *createSnapshotOp) key() interface{} {
+	return snapshotKey{op.args.Snapshot}
+}
@@ -100,6 +100,38 @@ type VolumeAccessor interface {
 	CreateVolumeAttachmentPlans(volumeAttachmentPlans []params.VolumeAttachmentPlan) ([]params.ErrorResult, error)
 	RemoveVolumeAttachmentPlan([]params.MachineStorageId) ([]params.ErrorResult, error)
 	SetVolumeAttachmentPlanBlockInfo(volumeAttachmentPlans []params.VolumeAttachmentPlan) ([]params.ErrorResult, error)
+
+	// WatchVolumeResizes watches for requests to resize volumes that
+	// this storage provisioner is responsible for.
+	WatchVolumeResizes(scope names.Tag) (watcher.StringsWatcher, error)
+
+	// VolumeResizeParams returns the parameters for resizing the
+	// volumes with the specified tags.
+	VolumeResizeParams([]names.VolumeTag) ([]params.VolumeResizeParamsResult, error)
+
+	// WatchVolumeSnapshots watches for requests to snapshot volumes
+	// that this storage provisioner is responsible for.
+	WatchVolumeSnapshots(scope names.Tag) (watcher.StringsWatcher, error)
+
+	// VolumeSnapshotParams returns the parameters for creating the
+	// volume snapshots with the specified IDs.
+	VolumeSnapshotParams([]string) ([]params.VolumeSnapshotParamsResult, error)
+
+	// SetVolumeSnapshotInfo records the outcome of creating volume
+	// snapshots.
+	SetVolumeSnapshotInfo([]params.VolumeSnapshotInfo) ([]params.ErrorResult, error)
+
+	// WatchStorageMigrations watches for requests to migrate storage
+	// whose volumes are attached to the specified machine.
+	WatchStorageMigrations(scope names.MachineTag) (watcher.StringsWatcher, error)
+
+	// StorageMigrationParams returns the parameters for copying the
+	// data of the storage migrations with the specified IDs.
+	StorageMigrationParams([]string) ([]params.StorageMigrationParamsResult, error)
+
+	// FinishStorageMigrations records the outcome of copying the
+	// data of storage migrations.
+	FinishStorageMigrations([]params.StorageMigrationOutcome) ([]params.ErrorResult, error)
 }
 
 // FilesystemAccessor defines an interface used to allow a storage provisioner
@@ -138,6 +170,14 @@ type FilesystemAccessor interface {
 	// SetFilesystemAttachmentInfo records the details of newly provisioned
 	// filesystem attachments.
 	SetFilesystemAttachmentInfo([]params.FilesystemAttachment) ([]params.ErrorResult, error)
+
+	// WatchFilesystemResizes watches for requests to resize filesystems
+	// that this storage provisioner is responsible for.
+	WatchFilesystemResizes(scope names.Tag) (watcher.StringsWatcher, error)
+
+	// FilesystemResizeParams returns the parameters for resizing the
+	// filesystems with the specified tags.
+	FilesystemResizeParams([]names.FilesystemTag) ([]params.FilesystemResizeParamsResult, error)
 }
 
 // MachineAccessor defines an interface used to allow a storage provisioner
@@ -222,9 +262,14 @@ func (w *storageProvisioner) loop() error {
 		volumeAttachmentsChanges     watcher.MachineStorageIdsChannel
 		volumeAttachmentPlansChanges watcher.MachineStorageIdsChannel
 		filesystemAttachmentsChanges watcher.MachineStorageIdsChannel
+		volumeResizesChanges         watcher.StringsChannel
+		volumeSnapshotsChanges       watcher.StringsChannel
+		storageMigrationsChanges     watcher.StringsChannel
+		filesystemResizesChanges     watcher.StringsChannel
 		machineBlockDevicesChanges   <-chan struct{}
 	)
 	machineChanges := make(chan names.MachineTag)
+	storageMigrationOutcomes := make(chan params.StorageMigrationOutcome)
 
 	// Machine-scoped provisioners need to watch block devices, to create
 	// volume-backed filesystems.
@@ -260,6 +305,8 @@ func (w *storageProvisioner) loop() error {
 		filesystemAttachments:                make(map[params.MachineStorageId]storage.FilesystemAttachment),
 		machines:                             make(map[names.MachineTag]*machineWatcher),
 		machineChanges:                       machineChanges,
+		storageCopiers:                       make(map[string]*storageCopier),
+		storageMigrationOutcomes:             storageMigrationOutcomes,
 		schedule:                             schedule.NewSchedule(w.config.Clock),
 		incompleteVolumeParams:               make(map[names.VolumeTag]storage.VolumeParams),
 		incompleteVolumeAttachmentParams:     make(map[params.MachineStorageId]storage.VolumeAttachmentParams),
@@ -315,6 +362,62 @@ func (w *storageProvisioner) loop() error {
 	}
 	filesystemAttachmentsChanges = filesystemAttachmentsWatcher.Changes()
 
+	// Resize requests are not supported by older controllers, in
+	// which case the resize watchers are not started.
+	if !ctx.isApplicationKind() {
+		volumeResizesWatcher, err := w.config.Volumes.WatchVolumeResizes(w.config.Scope)
+		if errors.IsNotSupported(err) {
+			w.config.Logger.Debugf("volume resizing not supported: %v", err)
+		} else if err != nil {
+			return errors.Annotate(err, "watching volume resizes")
+		} else {
+			if err := w.catacomb.Add(volumeResizesWatcher); err != nil {
+				return errors.Trace(err)
+			}
+			volumeResizesChanges = volumeResizesWatcher.Changes()
+		}
+
+		volumeSnapshotsWatcher, err := w.config.Volumes.WatchVolumeSnapshots(w.config.Scope)
+		if errors.IsNotSupported(err) {
+			w.config.Logger.Debugf("volume snapshots not supported: %v", err)
+		} else if err != nil {
+			return errors.Annotate(err, "watching volume snapshots")
+		} else {
+			if err := w.catacomb.Add(volumeSnapshotsWatcher); err != nil {
+				return errors.Trace(err)
+			}
+			volumeSnapshotsChanges = volumeSnapshotsWatcher.Changes()
+		}
+	}
+
+	// The data of a storage migration is copied by the agent of the
+	// machine that the volumes are attached to.
+	if machineTag, ok := w.config.Scope.(names.MachineTag); ok {
+		storageMigrationsWatcher, err := w.config.Volumes.WatchStorageMigrations(machineTag)
+		if errors.IsNotSupported(err) {
+			w.config.Logger.Debugf("storage migrations not supported: %v", err)
+		} else if err != nil {
+			return errors.Annotate(err, "watching storage migrations")
+		} else {
+			if err := w.catacomb.Add(storageMigrationsWatcher); err != nil {
+				return errors.Trace(err)
+			}
+			storageMigrationsChanges = storageMigrationsWatcher.Changes()
+		}
+	}
+
+	filesystemResizesWatcher, err := w.config.Filesystems.WatchFilesystemResizes(w.config.Scope)
+	if errors.IsNotSupported(err) {
+		w.config.Logger.Debugf("filesystem resizing not supported: %v", err)
+	} else if err != nil {
+		return errors.Annotate(err, "watching filesystem resizes")
+	} else {
+		if err := w.catacomb.Add(filesystemResizesWatcher); err != nil {
+			return errors.Trace(err)
+		}
+		filesystemResizesChanges = filesystemResizesWatcher.Changes()
+	}
+
 	for {
 
 		// Check if block devices need to be refreshed.
@@ -360,6 +463,38 @@ func (w *storageProvisioner) loop() error {
 			if err := filesystemAttachmentsChanged(&ctx, changes); err != nil {
 				return errors.Trace(err)
 			}
+		case changes, ok := <-volumeResizesChanges:
+			if !ok {
+				return errors.New("volume resizes watcher closed")
+			}
+			if err := volumeResizesChanged(&ctx, changes); err != nil {
+				return errors.Trace(err)
+			}
+		case changes, ok := <-volumeSnapshotsChanges:
+			if !ok {
+				return errors.New("volume snapshots watcher closed")
+			}
+			if err := volumeSnapshotsChanged(&ctx, changes); err != nil {
+				return errors.Trace(err)
+			}
+		case changes, ok := <-storageMigrationsChanges:
+			if !ok {
+				return errors.New("storage migrations watcher closed")
+			}
+			if err := storageMigrationsChanged(&ctx, changes); err != nil {
+				return errors.Trace(err)
+			}
+		case outcome := <-storageMigrationOutcomes:
+			if err := storageMigrationCopied(&ctx, outcome); err != nil {
+				return errors.Trace(err)
+			}
+		case changes, ok := <-filesystemResizesChanges:
+			if !ok {
+				return errors.New("filesystem resizes watcher closed")
+			}
+			if err := filesystemResizesChanged(&ctx, changes); err != nil {
+				return errors.Trace(err)
+			}
 		case _, ok := <-machineBlockDevicesChanges:
 			if !ok {
 				return errors.New("machine block devices watcher closed")
@@ -391,6 +526,10 @@ func processSchedule(ctx *context) error {
 	removeFilesystemOps := make(map[names.FilesystemTag]*removeFilesystemOp)
 	attachFilesystemOps := make(map[params.MachineStorageId]*attachFilesystemOp)
 	detachFilesystemOps := make(map[params.MachineStorageId]*detachFilesystemOp)
+	resizeVolumeOps := make(map[resizeKey]*resizeVolumeOp)
+	resizeFilesystemOps := make(map[resizeKey]*resizeFilesystemOp)
+	createSnapshotOps := make(map[snapshotKey]*createSnapshotOp)
+	migrateStorageOps := make(map[migrationKey]*migrateStorageOp)
 	for _, item := range ready {
 		op := item.(scheduleOp)
 		key := op.key()
@@ -411,6 +550,14 @@ func processSchedule(ctx *context) error {
 			attachFilesystemOps[key.(params.MachineStorageId)] = op
 		case *detachFilesystemOp:
 			detachFilesystemOps[key.(params.MachineStorageId)] = op
+		case *resizeVolumeOp:
+			resizeVolumeOps[key.(resizeKey)] = op
+		case *resizeFilesystemOp:
+			resizeFilesystemOps[key.(resizeKey)] = op
+		case *createSnapshotOp:
+			createSnapshotOps[key.(snapshotKey)] = op
+		case *migrateStorageOp:
+			migrateStorageOps[key.(migrationKey)] = op
 		}
 	}
 	if len(removeVolumeOps) > 0 {
@@ -453,6 +600,26 @@ func processSchedule(ctx *context) error {
 			return errors.Annotate(err, "attaching filesystems")
 		}
 	}
+	if len(resizeVolumeOps) > 0 {
+		if err := resizeVolumes(ctx, resizeVolumeOps); err != nil {
+			return errors.Annotate(err, "resizing volumes")
+		}
+	}
+	if len(resizeFilesystemOps) > 0 {
+		if err := resizeFilesystems(ctx, resizeFilesystemOps); err != nil {
+			return errors.Annotate(err, "resizing filesystems")
+		}
+	}
+	if len(createSnapshotOps) > 0 {
+		if err := createVolumeSnapshots(ctx, createSnapshotOps); err != nil {
+			return errors.Annotate(err, "creating volume snapshots")
+		}
+	}
+	if len(migrateStorageOps) > 0 {
+		if err := migrateStorage(ctx, migrateStorageOps); err != nil {
+			return errors.Annotate(err, "migrating storage")
+		}
+	}
 	return nil
 }
 
@@ -485,6 +652,14 @@ type context struct {
 	// their machine is known to have been provisioned.
 	machineChanges chan<- names.MachineTag
 
+	// storageCopiers contains the workers copying the data of storage
+	// migrations, keyed by migration ID.
+	storageCopiers map[string]*storageCopier
+
+	// storageMigrationOutcomes is a channel that storage copiers will
+	// send to once they have finished copying a migration's data.
+	storageMigrationOutcomes chan<- params.StorageMigrationOutcome
+
 	// schedule is the schedule of storage operations.
 	schedule *schedule.Schedule
 
@@ -4,6 +4,8 @@
 package storageprovisioner_test
 
 import (
+	stdcontext "context"
+	"fmt"
 	"time"
 
 	"github.com/juju/clock"
@@ -2031,6 +2033,355 @@ func (s *caasStorageProvisionerSuite) TestRemoveFilesystems(c *gc.C) {
 	waitChannel(c, removed, "waiting for filesystem to be removed")
 }
 
+func (s *storageProvisionerSuite) TestVolumeResized(c *gc.C) {
+	volumeInfoSet := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.resizeParams["volume-1"] = params.VolumeResizeParams{
+		VolumeTag: "volume-1",
+		Provider:  "dummy",
+		Size:      2048,
+		Info: params.VolumeInfo{
+			VolumeId: "id-1",
+			Size:     1024,
+			Pool:     "dummy-pool",
+		},
+	}
+	volumeAccessor.setVolumeInfo = func(volumes []params.Volume) ([]params.ErrorResult, error) {
+		defer close(volumeInfoSet)
+		c.Assert(volumes, jc.DeepEquals, []params.Volume{{
+			VolumeTag: "volume-1",
+			Info: params.VolumeInfo{
+				VolumeId: "id-1",
+				Size:     2048,
+				Pool:     "dummy-pool",
+			},
+		}})
+		return make([]params.ErrorResult, len(volumes)), nil
+	}
+
+	var resizeArgs []storage.VolumeResizeParams
+	s.provider.resizeVolumesFunc = func(args []storage.VolumeResizeParams) ([]storage.ResizeResult, error) {
+		resizeArgs = append(resizeArgs, args...)
+		return []storage.ResizeResult{{Size: 2048}}, nil
+	}
+
+	args := &workerArgs{volumes: volumeAccessor, registry: s.registry}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	// Volumes without a pending resize request are ignored.
+	volumeAccessor.resizesWatcher.changes <- []string{"1", "2"}
+	waitChannel(c, volumeInfoSet, "waiting for volume info to be set")
+	c.Assert(resizeArgs, jc.DeepEquals, []storage.VolumeResizeParams{{
+		Tag:      names.NewVolumeTag("1"),
+		VolumeId: "id-1",
+		Size:     2048,
+	}})
+}
+
+func (s *storageProvisionerSuite) TestVolumeResizeError(c *gc.C) {
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.resizeParams["volume-1"] = params.VolumeResizeParams{
+		VolumeTag: "volume-1",
+		Provider:  "dummy",
+		Size:      2048,
+		Info:      params.VolumeInfo{VolumeId: "id-1", Size: 1024},
+	}
+	s.provider.resizeVolumesFunc = func(args []storage.VolumeResizeParams) ([]storage.ResizeResult, error) {
+		return []storage.ResizeResult{{Error: errors.New("badness")}}, nil
+	}
+	statusSet := make(chan []params.EntityStatusArgs, 1)
+	statusSetter := &mockStatusSetter{
+		setStatus: func(args []params.EntityStatusArgs) error {
+			select {
+			case statusSet <- args:
+			default:
+			}
+			return nil
+		},
+	}
+
+	args := &workerArgs{volumes: volumeAccessor, registry: s.registry, statusSetter: statusSetter}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	volumeAccessor.resizesWatcher.changes <- []string{"1"}
+	select {
+	case args := <-statusSet:
+		c.Assert(args, jc.DeepEquals, []params.EntityStatusArgs{
+			{Tag: "volume-1", Status: "error", Info: "resizing volume: badness"},
+		})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for status to be set")
+	}
+}
+
+func (s *storageProvisionerSuite) TestVolumeSnapshotCreated(c *gc.C) {
+	snapshotInfoSet := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.snapshotParams["3"] = params.VolumeSnapshotParams{
+		Id:        "3",
+		VolumeTag: "volume-1",
+		Provider:  "dummy",
+		VolumeId:  "id-1",
+		Tags:      map[string]string{"very": "fancy"},
+	}
+	volumeAccessor.setVolumeSnapshotInfo = func(snapshots []params.VolumeSnapshotInfo) ([]params.ErrorResult, error) {
+		defer close(snapshotInfoSet)
+		c.Assert(snapshots, jc.DeepEquals, []params.VolumeSnapshotInfo{{
+			Id:         "3",
+			SnapshotId: "snap-3",
+			Size:       1024,
+		}})
+		return make([]params.ErrorResult, len(snapshots)), nil
+	}
+
+	var snapshotArgs []storage.VolumeSnapshotParams
+	s.provider.createVolumeSnapshotsFunc = func(args []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+		snapshotArgs = append(snapshotArgs, args...)
+		return []storage.CreateVolumeSnapshotsResult{{SnapshotId: "snap-3", Size: 1024}}, nil
+	}
+
+	args := &workerArgs{volumes: volumeAccessor, registry: s.registry}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	// Snapshots that are not pending are ignored.
+	volumeAccessor.snapshotsWatcher.changes <- []string{"3", "4"}
+	waitChannel(c, snapshotInfoSet, "waiting for snapshot info to be set")
+	c.Assert(snapshotArgs, jc.DeepEquals, []storage.VolumeSnapshotParams{{
+		Tag:          names.NewVolumeTag("1"),
+		VolumeId:     "id-1",
+		Snapshot:     "3",
+		ResourceTags: map[string]string{"very": "fancy"},
+	}})
+}
+
+func (s *storageProvisionerSuite) TestVolumeSnapshotError(c *gc.C) {
+	snapshotInfoSet := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.snapshotParams["3"] = params.VolumeSnapshotParams{
+		Id:        "3",
+		VolumeTag: "volume-1",
+		Provider:  "dummy",
+		VolumeId:  "id-1",
+	}
+	volumeAccessor.setVolumeSnapshotInfo = func(snapshots []params.VolumeSnapshotInfo) ([]params.ErrorResult, error) {
+		defer close(snapshotInfoSet)
+		c.Assert(snapshots, jc.DeepEquals, []params.VolumeSnapshotInfo{{
+			Id:    "3",
+			Error: "creating volume snapshot: badness",
+		}})
+		return make([]params.ErrorResult, len(snapshots)), nil
+	}
+	s.provider.createVolumeSnapshotsFunc = func(args []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+		return []storage.CreateVolumeSnapshotsResult{{Error: errors.New("badness")}}, nil
+	}
+
+	args := &workerArgs{volumes: volumeAccessor, registry: s.registry}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	volumeAccessor.snapshotsWatcher.changes <- []string{"3"}
+	waitChannel(c, snapshotInfoSet, "waiting for snapshot info to be set")
+}
+
+func (s *storageProvisionerSuite) TestStorageMigration(c *gc.C) {
+	var copied [][2]string
+	s.PatchValue(storageprovisioner.CopyBlockDevice, func(_ stdcontext.Context, source, target string) error {
+		copied = append(copied, [2]string{source, target})
+		return nil
+	})
+
+	migrationsFinished := make(chan interface{})
+	var paramsCalls int
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.storageMigrationParams = func(ids []string) ([]params.StorageMigrationParamsResult, error) {
+		paramsCalls++
+		results := make([]params.StorageMigrationParamsResult, len(ids))
+		for i, id := range ids {
+			switch {
+			case id == "0/1" && paramsCalls == 1:
+				// The target volume is not visible on
+				// the machine at first.
+				results[i].Error = &params.Error{
+					Code:    params.CodeNotProvisioned,
+					Message: "block device for volume 0/2 not provisioned",
+				}
+			case id == "0/1":
+				results[i].Result = params.StorageMigrationParams{
+					Id:               id,
+					MachineTag:       "machine-0",
+					SourceDevicePath: "/dev/sda",
+					TargetDevicePath: "/dev/sdb",
+				}
+			default:
+				results[i].Error = &params.Error{
+					Code:    params.CodeNotFound,
+					Message: fmt.Sprintf("pending storage migration %q not found", id),
+				}
+			}
+		}
+		return results, nil
+	}
+	volumeAccessor.finishStorageMigrations = func(outcomes []params.StorageMigrationOutcome) ([]params.ErrorResult, error) {
+		defer close(migrationsFinished)
+		c.Assert(outcomes, jc.DeepEquals, []params.StorageMigrationOutcome{{Id: "0/1"}})
+		return make([]params.ErrorResult, len(outcomes)), nil
+	}
+
+	args := &workerArgs{
+		scope:    names.NewMachineTag("0"),
+		volumes:  volumeAccessor,
+		registry: s.registry,
+	}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	// Migrations that are not pending are ignored.
+	volumeAccessor.migrationsWatcher.changes <- []string{"0/1", "0/2"}
+	waitChannel(c, migrationsFinished, "waiting for storage migrations to be finished")
+	c.Assert(copied, jc.DeepEquals, [][2]string{{"/dev/sda", "/dev/sdb"}})
+}
+
+func (s *storageProvisionerSuite) TestStorageMigrationCopyError(c *gc.C) {
+	s.PatchValue(storageprovisioner.CopyBlockDevice, func(stdcontext.Context, string, string) error {
+		return errors.New("dd failed: no space left on device")
+	})
+
+	migrationsFinished := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.storageMigrationParams = func(ids []string) ([]params.StorageMigrationParamsResult, error) {
+		c.Assert(ids, jc.DeepEquals, []string{"0/1"})
+		return []params.StorageMigrationParamsResult{{
+			Result: params.StorageMigrationParams{
+				Id:               "0/1",
+				MachineTag:       "machine-0",
+				SourceDevicePath: "/dev/sda",
+				TargetDevicePath: "/dev/sdb",
+			},
+		}}, nil
+	}
+	volumeAccessor.finishStorageMigrations = func(outcomes []params.StorageMigrationOutcome) ([]params.ErrorResult, error) {
+		defer close(migrationsFinished)
+		c.Assert(outcomes, jc.DeepEquals, []params.StorageMigrationOutcome{{
+			Id:    "0/1",
+			Error: "copying data: dd failed: no space left on device",
+		}})
+		return make([]params.ErrorResult, len(outcomes)), nil
+	}
+
+	args := &workerArgs{
+		scope:    names.NewMachineTag("0"),
+		volumes:  volumeAccessor,
+		registry: s.registry,
+	}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	volumeAccessor.migrationsWatcher.changes <- []string{"0/1"}
+	waitChannel(c, migrationsFinished, "waiting for storage migrations to be finished")
+}
+
+func (s *storageProvisionerSuite) TestStorageMigrationCopyCancelledOnStop(c *gc.C) {
+	copyStarted := make(chan interface{})
+	copyCancelled := make(chan error, 1)
+	s.PatchValue(storageprovisioner.CopyBlockDevice, func(ctx stdcontext.Context, source, target string) error {
+		close(copyStarted)
+		<-ctx.Done()
+		copyCancelled <- ctx.Err()
+		return ctx.Err()
+	})
+
+	otherMigrationHandled := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.storageMigrationParams = func(ids []string) ([]params.StorageMigrationParamsResult, error) {
+		if ids[0] == "0/2" {
+			close(otherMigrationHandled)
+			return []params.StorageMigrationParamsResult{{
+				Error: &params.Error{Code: params.CodeNotFound},
+			}}, nil
+		}
+		c.Assert(ids, jc.DeepEquals, []string{"0/1"})
+		return []params.StorageMigrationParamsResult{{
+			Result: params.StorageMigrationParams{
+				Id:               "0/1",
+				MachineTag:       "machine-0",
+				SourceDevicePath: "/dev/sda",
+				TargetDevicePath: "/dev/sdb",
+			},
+		}}, nil
+	}
+	volumeAccessor.finishStorageMigrations = func(outcomes []params.StorageMigrationOutcome) ([]params.ErrorResult, error) {
+		c.Errorf("unexpected call to FinishStorageMigrations(%v)", outcomes)
+		return make([]params.ErrorResult, len(outcomes)), nil
+	}
+
+	args := &workerArgs{
+		scope:    names.NewMachineTag("0"),
+		volumes:  volumeAccessor,
+		registry: s.registry,
+	}
+	worker := newStorageProvisioner(c, args)
+	volumeAccessor.migrationsWatcher.changes <- []string{"0/1"}
+	waitChannel(c, copyStarted, "waiting for the copy to start")
+
+	// The storage provisioner keeps handling other changes while
+	// the copy is in progress; the migration being copied is not
+	// started again.
+	volumeAccessor.migrationsWatcher.changes <- []string{"0/1"}
+	volumeAccessor.migrationsWatcher.changes <- []string{"0/2"}
+	waitChannel(c, otherMigrationHandled, "waiting for other migration to be handled")
+
+	worker.Kill()
+	c.Assert(worker.Wait(), gc.IsNil)
+	select {
+	case err := <-copyCancelled:
+		c.Assert(err, gc.Equals, stdcontext.Canceled)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for the copy to be cancelled")
+	}
+}
+
+func (s *storageProvisionerSuite) TestFilesystemResized(c *gc.C) {
+	filesystemInfoSet := make(chan interface{})
+	filesystemAccessor := newMockFilesystemAccessor()
+	filesystemAccessor.resizeParams["filesystem-1"] = params.FilesystemResizeParams{
+		FilesystemTag: "filesystem-1",
+		Provider:      "dummy",
+		Size:          4096,
+		Info: params.FilesystemInfo{
+			FilesystemId: "fs-1",
+			Size:         1024,
+		},
+	}
+	filesystemAccessor.setFilesystemInfo = func(filesystems []params.Filesystem) ([]params.ErrorResult, error) {
+		defer close(filesystemInfoSet)
+		c.Assert(filesystems, jc.DeepEquals, []params.Filesystem{{
+			FilesystemTag: "filesystem-1",
+			Info: params.FilesystemInfo{
+				FilesystemId: "fs-1",
+				Size:         4096,
+			},
+		}})
+		return make([]params.ErrorResult, len(filesystems)), nil
+	}
+
+	args := &workerArgs{filesystems: filesystemAccessor, registry: s.registry}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	filesystemAccessor.resizesWatcher.changes <- []string{"1"}
+	waitChannel(c, filesystemInfoSet, "waiting for filesystem info to be set")
+}
+
 func newStorageProvisioner(c *gc.C, args *workerArgs) worker.Worker {
 	if args == nil {
 		args = &workerArgs{}
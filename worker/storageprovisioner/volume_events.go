@@ -553,6 +553,7 @@ func volumesFromStorage(in []storage.Volume) []params.Volume {
 				"", // pool
 				v.Size,
 				v.Persistent,
+				v.Encrypted,
 			},
 		}
 	}
@@ -597,6 +598,7 @@ func volumeFromParams(in params.Volume) (storage.Volume, error) {
 			in.Info.WWN,
 			in.Info.Size,
 			in.Info.Persistent,
+			in.Info.Encrypted,
 		},
 	}, nil
 }
@@ -639,12 +641,13 @@ func volumeParamsFromParams(in params.VolumeParams) (storage.VolumeParams, error
 		}
 	}
 	return storage.VolumeParams{
-		volumeTag,
-		in.Size,
-		providerType,
-		in.Attributes,
-		in.Tags,
-		attachment,
+		Tag:          volumeTag,
+		Size:         in.Size,
+		Provider:     providerType,
+		Attributes:   in.Attributes,
+		ResourceTags: in.Tags,
+		SnapshotId:   in.SnapshotId,
+		Attachment:   attachment,
 	}, nil
 }
 
@@ -113,4 +113,9 @@ type StorageSnapshot struct {
 	Life     params.Life
 	Attached bool
 	Location string
+	// Size is the size of the storage in MiB, if known.
+	Size uint64
+	// Migration is the status of the migration of the storage
+	// that the unit is taking part in, if any.
+	Migration string
 }
@@ -5,7 +5,9 @@ package context
 
 import (
 	"fmt"
+	"io/ioutil"
 	"math/rand"
+	"path/filepath"
 	"time"
 
 	"github.com/juju/errors"
@@ -16,6 +18,8 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/leadership"
 	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/core/relation"
+	"github.com/juju/juju/worker/uniter/charm"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
 )
@@ -82,6 +86,11 @@ type contextFactory struct {
 	getRelationInfos RelationsFunc
 	relationCaches   map[int]*RelationCache
 
+	// The relation settings schemas of the deployed charm, and the
+	// URL of the charm they were read from.
+	schemaCharmURL string
+	schemas        map[string]relation.EndpointSchema
+
 	// For generating "unique" context ids.
 	rand *rand.Rand
 }
@@ -228,7 +237,7 @@ func (f *contextFactory) HookContext(hookInfo hook.Info) (*HookContext, error) {
 		}
 		hookName = fmt.Sprintf("%s-%s", relation.Name(), hookInfo.Kind)
 	}
-	if hookInfo.Kind.IsStorage() {
+	if hook.IsStorage(hookInfo.Kind) {
 		ctx.storageTag = names.NewStorageTag(hookInfo.StorageId)
 		if _, err := ctx.storage.Storage(ctx.storageTag); err != nil {
 			return nil, errors.Annotatef(err, "could not retrieve storage for id: %v", hookInfo.StorageId)
@@ -271,6 +280,7 @@ func (f *contextFactory) getContextRelations() map[int]*ContextRelation {
 	contextRelations := map[int]*ContextRelation{}
 	relationInfos := f.getRelationInfos()
 	relationCaches := map[int]*RelationCache{}
+	schemas := f.relationSchemas()
 	for id, info := range relationInfos {
 		relationUnit := info.RelationUnit
 		memberNames := info.MemberNames
@@ -281,12 +291,51 @@ func (f *contextFactory) getContextRelations() map[int]*ContextRelation {
 			cache = NewRelationCache(relationUnit.ReadSettings, memberNames)
 		}
 		relationCaches[id] = cache
-		contextRelations[id] = NewContextRelation(relationUnit, cache)
+		contextRelation := NewContextRelation(relationUnit, cache)
+		contextRelation.schema = schemas[relationUnit.Endpoint().Name]
+		contextRelations[id] = contextRelation
 	}
 	f.relationCaches = relationCaches
 	return contextRelations
 }
 
+// relationSchemas returns the relation settings schemas declared by the
+// currently deployed charm, keyed by endpoint name. The schemas are read
+// once for each charm URL. Schemas that cannot be read are logged and
+// ignored; the controller validates settings written by the unit in any
+// case.
+func (f *contextFactory) relationSchemas() map[string]relation.EndpointSchema {
+	charmDir := f.paths.GetCharmDir()
+	var curl string
+	if url, err := charm.ReadCharmURL(filepath.Join(charmDir, charm.CharmURLPath)); err != nil {
+		logger.Debugf("cannot read deployed charm URL: %v", err)
+	} else {
+		curl = url.String()
+	}
+	if curl != "" && curl == f.schemaCharmURL {
+		return f.schemas
+	}
+	schemas := readRelationSchemas(charmDir)
+	if curl != "" {
+		f.schemaCharmURL, f.schemas = curl, schemas
+	}
+	return schemas
+}
+
+func readRelationSchemas(charmDir string) map[string]relation.EndpointSchema {
+	metadata, err := ioutil.ReadFile(filepath.Join(charmDir, "metadata.yaml"))
+	if err != nil {
+		logger.Warningf("cannot read charm metadata: %v", err)
+		return nil
+	}
+	schemas, err := relation.ParseSettingsSchemas(metadata)
+	if err != nil {
+		logger.Warningf("cannot read relation settings schemas: %v", err)
+		return nil
+	}
+	return schemas
+}
+
 // updateContext fills in all unspecialized fields that require an API call to
 // discover.
 //
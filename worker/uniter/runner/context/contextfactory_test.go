@@ -4,7 +4,9 @@
 package context_test
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/juju/clock/testclock"
@@ -15,6 +17,7 @@ import (
 	"github.com/juju/utils"
 	"github.com/juju/utils/fs"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6"
 	"gopkg.in/juju/charm.v6/hooks"
 	"gopkg.in/juju/names.v3"
 
@@ -28,6 +31,7 @@ import (
 	"github.com/juju/juju/testcharms"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
+	jujucharm "github.com/juju/juju/worker/uniter/charm"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/runner/context"
 	runnertesting "github.com/juju/juju/worker/uniter/runner/testing"
@@ -98,6 +102,16 @@ func (s *ContextFactorySuite) SetCharm(c *gc.C, name string) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *ContextFactorySuite) writeCharm(c *gc.C, url string, metadata string) {
+	charmDir := s.paths.GetCharmDir()
+	err := os.MkdirAll(charmDir, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = jujucharm.WriteCharmURL(filepath.Join(charmDir, jujucharm.CharmURLPath), charm.MustParseURL(url))
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(charmDir, "metadata.yaml"), []byte(metadata), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *ContextFactorySuite) getRelationInfos() map[int]*context.RelationInfo {
 	info := map[int]*context.RelationInfo{}
 	for relId, relUnit := range s.apiRelunits {
@@ -671,3 +685,36 @@ func (stub *StubLeadershipContext) IsLeader() (bool, error) {
 	stub.MethodCall(stub, "IsLeader")
 	return false, stub.NextErr()
 }
+
+const schemaMetadata = `
+name: mysql
+summary: a database
+description: a database
+provides:
+  db:
+    interface: mysql
+    schema:
+      provider:
+        unit:
+          type: object
+          properties:
+            port: {type: integer}
+`
+
+func (s *ContextFactorySuite) TestRelationSchemasCachedPerCharmURL(c *gc.C) {
+	s.writeCharm(c, "cs:quantal/mysql-1", schemaMetadata)
+	schemas := context.RelationSchemas(s.factory)
+	c.Assert(schemas, gc.HasLen, 1)
+	c.Assert(schemas["db"], gc.NotNil)
+
+	// The metadata is not read again for the same charm.
+	err := ioutil.WriteFile(filepath.Join(s.paths.GetCharmDir(), "metadata.yaml"), []byte("name: mysql\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	schemas = context.RelationSchemas(s.factory)
+	c.Assert(schemas, gc.HasLen, 1)
+
+	// It is read once the charm changes.
+	s.writeCharm(c, "cs:quantal/mysql-2", "name: mysql\n")
+	schemas = context.RelationSchemas(s.factory)
+	c.Assert(schemas, gc.HasLen, 0)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/leadership"
+	"github.com/juju/juju/core/relation"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
 )
 
@@ -172,6 +173,10 @@ func ContextMachineTag(hctx *HookContext) names.MachineTag {
 	return hctx.assignedMachineTag
 }
 
+func RelationSchemas(cf0 ContextFactory) map[string]relation.EndpointSchema {
+	return cf0.(*contextFactory).relationSchemas()
+}
+
 func UpdateCachedSettings(cf0 ContextFactory, relId int, unitName string, settings params.Settings) {
 	cf := cf0.(*contextFactory)
 	members := cf.relationCaches[relId].members
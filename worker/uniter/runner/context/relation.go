@@ -33,6 +33,10 @@ type ContextRelation struct {
 
 	// cache holds remote unit membership and settings.
 	cache *RelationCache
+
+	// schema holds the settings schema the charm declares for the
+	// relation endpoint, if any.
+	schema relation.EndpointSchema
 }
 
 // NewContextRelation creates a new context for the given relation unit.
@@ -105,6 +109,19 @@ func (ctx *ContextRelation) WriteSettings() error {
 	return errors.Trace(ctx.ru.UpdateRelationSettings(unitSettings, appSettings))
 }
 
+// ValidateSettings checks the supplied settings against the schema
+// declared by the charm for the relation endpoint.
+func (ctx *ContextRelation) ValidateSettings(settings params.Settings, application bool) error {
+	if ctx.schema == nil {
+		return nil
+	}
+	settingsMap := make(map[string]interface{}, len(settings))
+	for k, v := range settings {
+		settingsMap[k] = v
+	}
+	return errors.Trace(ctx.schema.ValidateSettings(ctx.ru.Endpoint().Role, application, settingsMap))
+}
+
 // Suspended returns true if the relation is suspended.
 func (ctx *ContextRelation) Suspended() bool {
 	return ctx.ru.Relation().Suspended()
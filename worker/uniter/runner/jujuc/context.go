@@ -283,6 +283,11 @@ type ContextRelation interface {
 	// ReadApplicationSettings returns the application settings of any remote unit in the relation.
 	ReadApplicationSettings(app string) (params.Settings, error)
 
+	// ValidateSettings checks the supplied unit settings, or application
+	// settings if application is true, against the schema declared by
+	// the charm for this relation.
+	ValidateSettings(settings params.Settings, application bool) error
+
 	// Suspended returns true if the relation is suspended.
 	Suspended() bool
 
@@ -157,3 +157,15 @@ func (m *MockContextRelation) UnitNames() []string {
 func (mr *MockContextRelationMockRecorder) UnitNames() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnitNames", reflect.TypeOf((*MockContextRelation)(nil).UnitNames))
 }
+
+// ValidateSettings mocks base method
+func (m *MockContextRelation) ValidateSettings(arg0 params.Settings, arg1 bool) error {
+	ret := m.ctrl.Call(m, "ValidateSettings", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateSettings indicates an expected call of ValidateSettings
+func (mr *MockContextRelationMockRecorder) ValidateSettings(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateSettings", reflect.TypeOf((*MockContextRelation)(nil).ValidateSettings), arg0, arg1)
+}
@@ -8,6 +8,7 @@ import (
 	"sort"
 
 	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6"
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/relation"
@@ -26,6 +27,10 @@ type Relation struct {
 	UnitName string
 	// ApplicationSettings is data for jujuc.ContextRelation
 	ApplicationSettings Settings
+	// Schema is data for jujuc.ContextRelation.
+	Schema relation.EndpointSchema
+	// Role is data for jujuc.ContextRelation.
+	Role charm.RelationRole
 }
 
 // Reset clears the Relation's settings.
@@ -137,6 +142,22 @@ func (r *ContextRelation) ReadApplicationSettings(name string) (params.Settings,
 	return r.info.ApplicationSettings.Map(), nil
 }
 
+// ValidateSettings implements jujuc.ContextRelation.
+func (r *ContextRelation) ValidateSettings(settings params.Settings, application bool) error {
+	r.stub.AddCall("ValidateSettings", settings, application)
+	if err := r.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+	if r.info.Schema == nil {
+		return nil
+	}
+	settingsMap := make(map[string]interface{}, len(settings))
+	for k, v := range settings {
+		settingsMap[k] = v
+	}
+	return r.info.Schema.ValidateSettings(r.info.Role, application, settingsMap)
+}
+
 // Suspended implements jujuc.ContextRelation.
 func (r *ContextRelation) Suspended() bool {
 	return true
@@ -28,6 +28,7 @@ type NetworkGetCommand struct {
 	bindAddress    bool
 	ingressAddress bool
 	egressSubnets  bool
+	family         string
 	keys           []string
 
 	// deprecated
@@ -52,7 +53,7 @@ func NewNetworkGetCommand(ctx Context) (_ cmd.Command, err error) {
 
 // Info is part of the cmd.Command interface.
 func (c *NetworkGetCommand) Info() *cmd.Info {
-	args := "<binding-name> [--ingress-address] [--bind-address] [--egress-subnets]"
+	args := "<binding-name> [--ingress-address] [--bind-address] [--egress-subnets] [--family <ipv4|ipv6>]"
 	doc := `
 network-get returns the network config for a given binding name. By default
 it returns the list of interfaces and associated addresses in the space for
@@ -64,6 +65,10 @@ If more than one flag is specified, a map of values is returned.
                     as the address that should be advertised to its peers.
     --ingress-address: the address the local unit should advertise as being used for incoming connections.
     --egress_subnets: subnets (in CIDR notation) from which traffic on this relation will originate.
+
+On dual-stack networks, addresses and subnets of both IP families are returned.
+The --family flag restricts the output, including the values of the flags
+above, to either "ipv4" or "ipv6".
 `
 	return jujucmd.Info(&cmd.Info{
 		Name:    "network-get",
@@ -80,6 +85,7 @@ func (c *NetworkGetCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.bindAddress, "bind-address", false, "get the address for the binding on which the unit should listen")
 	f.BoolVar(&c.ingressAddress, "ingress-address", false, "get the ingress address for the binding")
 	f.BoolVar(&c.egressSubnets, "egress-subnets", false, "get the egress subnets for the binding")
+	f.StringVar(&c.family, "family", "", `only return addresses of the given IP family ("ipv4" or "ipv6")`)
 	f.Var(c.relationIdProxy, "r", "specify a relation by id")
 	f.Var(c.relationIdProxy, "relation", "")
 }
@@ -90,6 +96,11 @@ const (
 	egressSubnetsKey  = "egress-subnets"
 )
 
+const (
+	familyIPv4 = "ipv4"
+	familyIPv6 = "ipv6"
+)
+
 // Init is part of the cmd.Command interface.
 func (c *NetworkGetCommand) Init(args []string) error {
 	if len(args) < 1 {
@@ -108,6 +119,9 @@ func (c *NetworkGetCommand) Init(args []string) error {
 	if c.egressSubnets {
 		c.keys = append(c.keys, egressSubnetsKey)
 	}
+	if c.family != "" && c.family != familyIPv4 && c.family != familyIPv6 {
+		return errors.Errorf("--family must be %q or %q, got %q", familyIPv4, familyIPv6, c.family)
+	}
 
 	return cmd.CheckEmpty(args[1:])
 }
@@ -127,6 +141,12 @@ func (c *NetworkGetCommand) Run(ctx *cmd.Context) error {
 	}
 
 	ni = resolveNetworkInfoAddresses(ni, LookupHost)
+	if c.family != "" {
+		ni = filterNetworkInfoFamily(ni, c.family)
+		if len(ni.Info) == 0 && len(ni.IngressAddresses) == 0 {
+			return fmt.Errorf("no %s addresses for binding %q", c.family, c.bindingName)
+		}
+	}
 
 	// If no specific attributes were asked for, write everything we know.
 	if !c.primaryAddress && len(c.keys) == 0 {
@@ -138,8 +158,8 @@ func (c *NetworkGetCommand) Run(ctx *cmd.Context) error {
 		if c.ingressAddress || c.egressSubnets || c.bindAddress {
 			return fmt.Errorf("--primary-address must be the only flag specified")
 		}
-		if len(ni.Info[0].Addresses) == 0 {
-			return fmt.Errorf("no addresses attached to space for binding %q", c.bindingName)
+		if len(ni.Info) == 0 || len(ni.Info[0].Addresses) == 0 {
+			return c.noAddressesError()
 		}
 		return c.out.Write(ctx, ni.Info[0].Addresses[0].Address)
 	}
@@ -152,8 +172,8 @@ func (c *NetworkGetCommand) Run(ctx *cmd.Context) error {
 	if c.ingressAddress {
 		var ingressAddress string
 		if len(ni.IngressAddresses) == 0 {
-			if len(ni.Info[0].Addresses) == 0 {
-				return fmt.Errorf("no addresses attached to space for binding %q", c.bindingName)
+			if len(ni.Info) == 0 || len(ni.Info[0].Addresses) == 0 {
+				return c.noAddressesError()
 			}
 			ingressAddress = ni.Info[0].Addresses[0].Address
 		} else {
@@ -162,6 +182,9 @@ func (c *NetworkGetCommand) Run(ctx *cmd.Context) error {
 		keyValues[ingressAddressKey] = ingressAddress
 	}
 	if c.bindAddress {
+		if len(ni.Info) == 0 || len(ni.Info[0].Addresses) == 0 {
+			return c.noAddressesError()
+		}
 		keyValues[bindAddressKey] = ni.Info[0].Addresses[0].Address
 	}
 	if len(c.keys) == 1 {
@@ -170,6 +193,15 @@ func (c *NetworkGetCommand) Run(ctx *cmd.Context) error {
 	return c.out.Write(ctx, keyValues)
 }
 
+// noAddressesError returns the error reported when the binding has no
+// addresses to satisfy the request, naming the family if one was given.
+func (c *NetworkGetCommand) noAddressesError() error {
+	if c.family != "" {
+		return fmt.Errorf("no %s addresses attached to space for binding %q", c.family, c.bindingName)
+	}
+	return fmt.Errorf("no addresses attached to space for binding %q", c.bindingName)
+}
+
 // TODO(externalreality) This addresses the immediate problem of
 // https://bugs.launchpad.net/juju/+bug/1721368, but the hostname can populate
 // both the egress subnet CIDR and the ingress addresses. These too should be
@@ -225,3 +257,47 @@ func resolveHostAddress(hostName string, lookupHost resolver) string {
 	}
 	return resolved[0]
 }
+
+// filterNetworkInfoFamily returns a copy of the input network info with only
+// the addresses and subnets of the input IP family.
+// Interfaces left without any addresses are omitted.
+func filterNetworkInfoFamily(netInfoResult params.NetworkInfoResult, family string) params.NetworkInfoResult {
+	result := params.NetworkInfoResult{Error: netInfoResult.Error}
+	for _, info := range netInfoResult.Info {
+		var addrs []params.InterfaceAddress
+		for _, addr := range info.Addresses {
+			if ipFamily(net.ParseIP(addr.Address)) == family {
+				addrs = append(addrs, addr)
+			}
+		}
+		if len(addrs) == 0 {
+			continue
+		}
+		info.Addresses = addrs
+		result.Info = append(result.Info, info)
+	}
+	for _, addr := range netInfoResult.IngressAddresses {
+		if ipFamily(net.ParseIP(addr)) == family {
+			result.IngressAddresses = append(result.IngressAddresses, addr)
+		}
+	}
+	for _, subnet := range netInfoResult.EgressSubnets {
+		if ip, _, err := net.ParseCIDR(subnet); err == nil && ipFamily(ip) == family {
+			result.EgressSubnets = append(result.EgressSubnets, subnet)
+		}
+	}
+	return result
+}
+
+// ipFamily returns the family of the input IP address,
+// or an empty string if it is not a valid address.
+func ipFamily(ip net.IP) string {
+	switch {
+	case ip == nil:
+		return ""
+	case ip.To4() != nil:
+		return familyIPv4
+	default:
+		return familyIPv6
+	}
+}
@@ -113,6 +113,49 @@ func (s *NetworkGetSuite) createCommand(c *gc.C) cmd.Command {
 		IngressAddresses: []string{"100.1.2.3", "100.4.3.2"},
 		EgressSubnets:    []string{"192.168.1.0/8", "10.0.0.0/8"},
 	}
+	// Simulate a dual-stack binding.
+	presetBindings["dual-stack"] = params.NetworkInfoResult{
+		Info: []params.NetworkInfo{
+			{MACAddress: "00:11:22:33:44:44",
+				InterfaceName: "eth4",
+				Addresses: []params.InterfaceAddress{
+					{
+						Address: "10.44.1.8",
+						CIDR:    "10.44.1.0/24",
+					},
+					{
+						Address: "2001:db8::8",
+						CIDR:    "2001:db8::/64",
+					},
+				},
+			},
+		},
+		IngressAddresses: []string{"10.44.1.8", "2001:db8::8"},
+		EgressSubnets:    []string{"10.44.1.8/32", "2001:db8::8/128"},
+	}
+	// Simulate a binding whose only IPv6 address is an ingress address.
+	presetBindings["ipv6-ingress-only"] = params.NetworkInfoResult{
+		Info: []params.NetworkInfo{
+			{MACAddress: "00:11:22:33:44:55",
+				InterfaceName: "eth5",
+				Addresses: []params.InterfaceAddress{
+					{
+						Address: "10.55.1.8",
+						CIDR:    "10.55.1.0/24",
+					},
+				},
+			},
+		},
+		IngressAddresses: []string{"2001:db8::55"},
+	}
+	// Simulate a binding with an interface but no addresses.
+	presetBindings["no-addresses"] = params.NetworkInfoResult{
+		Info: []params.NetworkInfo{
+			{MACAddress: "00:11:22:33:44:66",
+				InterfaceName: "eth6",
+			},
+		},
+	}
 
 	// This should not happen. A hostname should never populate the address
 	// field. However, until the code is updated to prevent addresses from
@@ -272,6 +315,64 @@ egress-subnets:
 ingress-addresses:
 - 100.1.2.3
 - 100.4.3.2`[1:],
+	}, {
+		summary: "dual-stack binding returns egress subnets for both families",
+		args:    []string{"dual-stack", "--egress-subnets"},
+		out: `
+- 10.44.1.8/32
+- 2001:db8::8/128`[1:],
+	}, {
+		summary: "dual-stack binding restricted to IPv6",
+		args:    []string{"dual-stack", "--family", "ipv6", "--ingress-address", "--bind-address", "--egress-subnets"},
+		out: `
+bind-address: 2001:db8::8
+egress-subnets:
+- 2001:db8::8/128
+ingress-address: 2001:db8::8`[1:],
+	}, {
+		summary: "dual-stack binding restricted to IPv4, no extra args",
+		args:    []string{"dual-stack", "--family", "ipv4"},
+		out: `
+bind-addresses:
+- macaddress: "00:11:22:33:44:44"
+  interfacename: eth4
+  addresses:
+  - hostname: ""
+    address: 10.44.1.8
+    cidr: 10.44.1.0/24
+egress-subnets:
+- 10.44.1.8/32
+ingress-addresses:
+- 10.44.1.8`[1:],
+	}, {
+		summary: "IPv4-only binding restricted to IPv6",
+		args:    []string{"known-unbound", "--family", "ipv6"},
+		code:    1,
+		out:     `no ipv6 addresses for binding "known-unbound"`,
+	}, {
+		summary: "IPv6 ingress address only, primary address restricted to IPv6",
+		args:    []string{"ipv6-ingress-only", "--primary-address", "--family", "ipv6"},
+		code:    1,
+		out:     `no ipv6 addresses attached to space for binding "ipv6-ingress-only"`,
+	}, {
+		summary: "IPv6 ingress address only, bind address restricted to IPv6",
+		args:    []string{"ipv6-ingress-only", "--bind-address", "--family", "ipv6"},
+		code:    1,
+		out:     `no ipv6 addresses attached to space for binding "ipv6-ingress-only"`,
+	}, {
+		summary: "IPv6 ingress address only, ingress address restricted to IPv6",
+		args:    []string{"ipv6-ingress-only", "--ingress-address", "--family", "ipv6"},
+		out:     `2001:db8::55`,
+	}, {
+		summary: "no addresses, with bind-address arg",
+		args:    []string{"no-addresses", "--bind-address"},
+		code:    1,
+		out:     `no addresses attached to space for binding "no-addresses"`,
+	}, {
+		summary: "invalid family",
+		args:    []string{"dual-stack", "--family", "ipx"},
+		code:    2,
+		out:     `--family must be "ipv4" or "ipv6", got "ipx"`,
 	}, {
 		summary: "a resolvable hostname as address, no args",
 		args:    []string{"resolvable-hostname"},
@@ -308,7 +409,7 @@ ingress-addresses:
 
 func (s *NetworkGetSuite) TestHelp(c *gc.C) {
 
-	helpLine := `Usage: network-get [options] <binding-name> [--ingress-address] [--bind-address] [--egress-subnets]`
+	helpLine := `Usage: network-get [options] <binding-name> [--ingress-address] [--bind-address] [--egress-subnets] [--family <ipv4|ipv6>]`
 
 	com := s.createCommand(c)
 	ctx := cmdtesting.Context(c)
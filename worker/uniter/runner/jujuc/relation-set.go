@@ -34,6 +34,12 @@ too long to fit within the command length limit of the shell or
 operating system. The file will contain a YAML map containing the
 settings.  Settings in the file will be overridden by any duplicate
 key-value arguments. A value of "-" for the filename means <stdin>.
+
+If the charm declares a schema for the relation in its metadata.yaml,
+the resulting settings are validated against it and nothing is
+changed if they do not conform. Values are converted to the types the
+schema declares for them, such as integer or boolean, before they are
+validated; they are still stored as strings.
 `
 
 // RelationSetCommand implements the relation-set command.
@@ -155,6 +161,20 @@ func (c *RelationSetCommand) Run(ctx *cmd.Context) (err error) {
 	if err != nil {
 		return errors.Annotate(err, "cannot read relation settings")
 	}
+	// Validate the settings as they will be once the changes are
+	// applied, so that a charm cannot write settings its own schema
+	// does not allow.
+	updated := settings.Map()
+	for k, v := range c.Settings {
+		if v != "" {
+			updated[k] = v
+		} else {
+			delete(updated, k)
+		}
+	}
+	if err := r.ValidateSettings(updated, c.Application); err != nil {
+		return errors.Annotate(err, "cannot set relation settings")
+	}
 	for k, v := range c.Settings {
 		if v != "" {
 			settings.Set(k, v)
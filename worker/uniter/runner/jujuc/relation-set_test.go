@@ -14,7 +14,9 @@ import (
 	"github.com/juju/cmd/cmdtesting"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6"
 
+	"github.com/juju/juju/core/relation"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
 	"github.com/juju/juju/worker/uniter/runner/jujuc/jujuctesting"
 )
@@ -387,6 +389,37 @@ func (s *RelationSetSuite) TestRun(c *gc.C) {
 	}
 }
 
+func (s *RelationSetSuite) TestRunValidatesSchema(c *gc.C) {
+	hctx, info := s.newHookContext(0, "", "")
+	basic := jujuctesting.Settings{"base": "value"}
+	info.rels[1].Units["u/0"] = basic
+	info.rels[1].Role = charm.RolePeer
+	info.rels[1].Schema = relation.EndpointSchema{
+		charm.RolePeer: {
+			Unit: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"port": map[string]interface{}{"type": "string", "pattern": "^[0-9]+$"},
+				},
+			},
+		},
+	}
+
+	com, err := jujuc.NewCommand(hctx, cmdString("relation-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	rset := com.(*jujuc.RelationSetCommand)
+	rset.RelationId = 1
+	rset.Settings = map[string]string{"port": "http"}
+	err = com.Run(cmdtesting.Context(c))
+	c.Assert(err, gc.ErrorMatches, `cannot set relation settings: unit settings: .* not valid`)
+	c.Assert(info.rels[1].Units["u/0"], gc.DeepEquals, jujuctesting.Settings{"base": "value"})
+
+	rset.Settings = map[string]string{"port": "8080"}
+	err = com.Run(cmdtesting.Context(c))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.rels[1].Units["u/0"], gc.DeepEquals, jujuctesting.Settings{"base": "value", "port": "8080"})
+}
+
 func (s *RelationSetSuite) TestRunDeprecationWarning(c *gc.C) {
 	hctx, _ := s.newHookContext(0, "", "")
 	com, _ := jujuc.NewCommand(hctx, cmdString("relation-set"))
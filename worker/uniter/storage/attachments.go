@@ -42,11 +42,21 @@ type StorageAccessor interface {
 	// with the specified unit and storage tags. This method is only
 	// expected to succeed if the storage attachment is Dying.
 	RemoveStorageAttachment(names.StorageTag, names.UnitTag) error
+
+	// AcknowledgeStorageMigration records that the unit has carried
+	// out its part in the current stage of the migration of the
+	// storage with the specified tag.
+	AcknowledgeStorageMigration(names.StorageTag, names.UnitTag) error
 }
 
 type storageAttachment struct {
 	*stateFile
-	jujuc.ContextStorageAttachment
+	*contextStorage
+
+	// migrating records whether the hook queued for the storage
+	// attachment is run as part of a migration of the storage,
+	// rather than in response to a change of its lifecycle.
+	migrating bool
 }
 
 // Attachments generates storage hooks in response to changes to
@@ -64,6 +74,12 @@ type Attachments struct {
 
 	// current storage attachments
 	storageAttachments map[names.StorageTag]storageAttachment
+
+	// migrationAcks records, for each storage attachment, the stage
+	// of its storage's migration last acknowledged, so that the
+	// acknowledgement is not repeated while the remote state catches
+	// up.
+	migrationAcks map[names.StorageTag]string
 }
 
 // NewAttachments returns a new Attachments.
@@ -78,6 +94,7 @@ func NewAttachments(
 		unitTag:            tag,
 		abort:              abort,
 		storageAttachments: make(map[names.StorageTag]storageAttachment),
+		migrationAcks:      make(map[names.StorageTag]string),
 		storageStateDir:    storageStateDir,
 		pending:            names.NewSet(),
 	}
@@ -131,11 +148,12 @@ func (a *Attachments) init() error {
 			)
 		}
 		a.storageAttachments[storageTag] = storageAttachment{
-			stateFile,
-			&contextStorage{
+			stateFile: stateFile,
+			contextStorage: &contextStorage{
 				tag:      storageTag,
 				kind:     storage.StorageKind(attachment.Kind),
 				location: attachment.Location,
+				size:     attachment.Size,
 			},
 		}
 	}
@@ -195,28 +213,50 @@ func (a *Attachments) StorageTags() ([]names.StorageTag, error) {
 
 // ValidateHook validates the hook against the current state.
 func (a *Attachments) ValidateHook(hi hook.Info) error {
-	storageState, err := a.storageStateForHook(hi)
+	attachment, err := a.storageAttachmentForHook(hi)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	return storageState.ValidateHook(hi)
+	return attachment.stateFile.ValidateHook(hi)
 }
 
 // CommitHook persists the state change encoded in the supplied storage
 // hook, or returns an error if the hook is invalid given current state.
 func (a *Attachments) CommitHook(hi hook.Info) error {
-	storageState, err := a.storageStateForHook(hi)
+	attachment, err := a.storageAttachmentForHook(hi)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	if err := storageState.CommitHook(hi); err != nil {
+	// Record the size of the storage reported to the hook, so
+	// we know when to run the storage-resized hook.
+	size := attachment.contextStorage.size
+	if err := attachment.stateFile.commitHook(hi, size); err != nil {
 		return err
 	}
 	storageTag := names.NewStorageTag(hi.StorageId)
 	switch hi.Kind {
 	case hooks.StorageAttached:
 		a.pending.Remove(storageTag)
+		if attachment.migrating {
+			// The charm has been told of the migrated storage,
+			// so the storage it was migrated from can be
+			// released.
+			if err := a.acknowledgeMigration(storageTag, params.StorageMigrationAttaching); err != nil {
+				return errors.Trace(err)
+			}
+		}
 	case hooks.StorageDetaching:
+		if attachment.migrating {
+			// The charm has stopped using the storage so that
+			// its data can be migrated; the storage-attached
+			// hook runs again once the migration is done.
+			if err := a.acknowledgeMigration(storageTag, params.StorageMigrationPending); err != nil {
+				return errors.Trace(err)
+			}
+			delete(a.storageAttachments, storageTag)
+			a.pending.Add(storageTag)
+			return nil
+		}
 		if err := a.removeStorageAttachment(storageTag); err != nil {
 			return errors.Trace(err)
 		}
@@ -224,22 +264,36 @@ func (a *Attachments) CommitHook(hi hook.Info) error {
 	return nil
 }
 
+// acknowledgeMigration records that the unit has carried out its part in
+// the specified stage of the migration of the storage with the given tag.
+func (a *Attachments) acknowledgeMigration(tag names.StorageTag, stage string) error {
+	if a.migrationAcks[tag] == stage {
+		return nil
+	}
+	if err := a.st.AcknowledgeStorageMigration(tag, a.unitTag); err != nil {
+		return errors.Annotate(err, "acknowledging storage migration")
+	}
+	a.migrationAcks[tag] = stage
+	return nil
+}
+
 func (a *Attachments) removeStorageAttachment(tag names.StorageTag) error {
 	if err := a.st.RemoveStorageAttachment(tag, a.unitTag); err != nil {
 		return errors.Annotate(err, "removing storage attachment")
 	}
 	a.pending.Remove(tag)
 	delete(a.storageAttachments, tag)
+	delete(a.migrationAcks, tag)
 	return nil
 }
 
-func (a *Attachments) storageStateForHook(hi hook.Info) (*stateFile, error) {
-	if !hi.Kind.IsStorage() {
-		return nil, errors.Errorf("not a storage hook: %#v", hi)
+func (a *Attachments) storageAttachmentForHook(hi hook.Info) (storageAttachment, error) {
+	if !hook.IsStorage(hi.Kind) {
+		return storageAttachment{}, errors.Errorf("not a storage hook: %#v", hi)
 	}
-	storageAttachment, ok := a.storageAttachments[names.NewStorageTag(hi.StorageId)]
+	attachment, ok := a.storageAttachments[names.NewStorageTag(hi.StorageId)]
 	if !ok {
-		return nil, errors.Errorf("unknown storage %q", hi.StorageId)
+		return storageAttachment{}, errors.Errorf("unknown storage %q", hi.StorageId)
 	}
-	return storageAttachment.stateFile, nil
+	return attachment, nil
 }
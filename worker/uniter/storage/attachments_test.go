@@ -334,6 +334,219 @@ func (s *attachmentsSuite) TestAttachmentsCommitHook(c *gc.C) {
 	c.Assert(removed, jc.IsTrue)
 }
 
+func (s *attachmentsSuite) TestAttachmentsStorageResized(c *gc.C) {
+	stateDir := c.MkDir()
+	unitTag := names.NewUnitTag("mysql/0")
+	abort := make(chan struct{})
+
+	storageTag := names.NewStorageTag("data/0")
+	st := &mockStorageAccessor{
+		unitStorageAttachments: func(u names.UnitTag) ([]params.StorageAttachmentId, error) {
+			return nil, nil
+		},
+	}
+
+	att, err := storage.NewAttachments(st, unitTag, stateDir, abort)
+	c.Assert(err, jc.ErrorIsNil)
+	r := storage.NewResolver(att, s.modelType)
+
+	localState := resolver.LocalState{State: operation.State{
+		Kind: operation.Continue,
+	}}
+	nextOp := func(size uint64) (operation.Operation, error) {
+		return r.NextOp(localState, remotestate.Snapshot{
+			Life: params.Alive,
+			Storage: map[names.StorageTag]remotestate.StorageSnapshot{
+				storageTag: {
+					Kind:     params.StorageKindBlock,
+					Life:     params.Alive,
+					Location: "/dev/sdb",
+					Attached: true,
+					Size:     size,
+				},
+			},
+		}, &mockOperations{})
+	}
+
+	op, err := nextOp(1024)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run hook storage-attached")
+	err = att.CommitHook(hook.Info{
+		Kind:      hooks.StorageAttached,
+		StorageId: storageTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadFile(filepath.Join(stateDir, "data-0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "attached: true\nsize: 1024\n")
+
+	// The size has not changed, so there is nothing to do.
+	_, err = nextOp(1024)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+
+	// The storage has grown, so the storage-resized hook runs.
+	op, err = nextOp(2048)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run hook storage-resized")
+	err = att.ValidateHook(hook.Info{
+		Kind:      hook.StorageResized,
+		StorageId: storageTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = att.CommitHook(hook.Info{
+		Kind:      hook.StorageResized,
+		StorageId: storageTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	data, err = ioutil.ReadFile(filepath.Join(stateDir, "data-0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "attached: true\nsize: 2048\n")
+
+	_, err = nextOp(2048)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+}
+
+func (s *attachmentsSuite) TestAttachmentsStorageMigration(c *gc.C) {
+	stateDir := c.MkDir()
+	unitTag := names.NewUnitTag("mysql/0")
+	abort := make(chan struct{})
+
+	storageTag := names.NewStorageTag("data/0")
+	var acknowledged int
+	st := &mockStorageAccessor{
+		unitStorageAttachments: func(u names.UnitTag) ([]params.StorageAttachmentId, error) {
+			return nil, nil
+		},
+		remove: func(s names.StorageTag, u names.UnitTag) error {
+			c.Fatalf("unexpected removal of %s", s.Id())
+			return nil
+		},
+		acknowledgeMigration: func(s names.StorageTag, u names.UnitTag) error {
+			c.Assert(s, gc.Equals, storageTag)
+			c.Assert(u, gc.Equals, unitTag)
+			acknowledged++
+			return nil
+		},
+	}
+
+	att, err := storage.NewAttachments(st, unitTag, stateDir, abort)
+	c.Assert(err, jc.ErrorIsNil)
+	r := storage.NewResolver(att, s.modelType)
+
+	localState := resolver.LocalState{State: operation.State{
+		Kind:      operation.Continue,
+		Installed: true,
+	}}
+	nextOp := func(location, migration string) (operation.Operation, error) {
+		return r.NextOp(localState, remotestate.Snapshot{
+			Life: params.Alive,
+			Storage: map[names.StorageTag]remotestate.StorageSnapshot{
+				storageTag: {
+					Kind:      params.StorageKindBlock,
+					Life:      params.Alive,
+					Location:  location,
+					Attached:  true,
+					Migration: migration,
+				},
+			},
+		}, &mockOperations{})
+	}
+	commitHook := func(kind hooks.Kind) {
+		hi := hook.Info{Kind: kind, StorageId: storageTag.Id()}
+		err := att.ValidateHook(hi)
+		c.Assert(err, jc.ErrorIsNil)
+		err = att.CommitHook(hi)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	stateFile := filepath.Join(stateDir, "data-0")
+
+	op, err := nextOp("/dev/sdb", "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run hook storage-attached")
+	commitHook(hooks.StorageAttached)
+
+	// The charm must stop using the storage before it is copied.
+	op, err = nextOp("/dev/sdb", params.StorageMigrationPending)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run hook storage-detaching")
+	commitHook(hooks.StorageDetaching)
+	c.Assert(acknowledged, gc.Equals, 1)
+	c.Assert(stateFile, jc.DoesNotExist)
+	c.Assert(att.Pending(), gc.Equals, 1)
+	assertStorageTags(c, att)
+
+	// Nothing more is done until the data has been copied.
+	_, err = nextOp("/dev/sdb", params.StorageMigrationPending)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	_, err = nextOp("/dev/sdb", params.StorageMigrationCopying)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	c.Assert(acknowledged, gc.Equals, 1)
+
+	// The charm is told of the migrated storage, and the
+	// migration is then acknowledged so that the source
+	// storage can be released.
+	op, err = nextOp("/dev/sdc", params.StorageMigrationAttaching)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run hook storage-attached")
+	commitHook(hooks.StorageAttached)
+	c.Assert(acknowledged, gc.Equals, 2)
+	c.Assert(att.Pending(), gc.Equals, 0)
+	c.Assert(stateFile, jc.IsNonEmptyFile)
+	ctx, err := att.Storage(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.Location(), gc.Equals, "/dev/sdc")
+
+	_, err = nextOp("/dev/sdc", params.StorageMigrationAttaching)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	_, err = nextOp("/dev/sdc", "")
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	c.Assert(acknowledged, gc.Equals, 2)
+}
+
+func (s *attachmentsSuite) TestAttachmentsStorageMigrationNotAttached(c *gc.C) {
+	stateDir := c.MkDir()
+	unitTag := names.NewUnitTag("mysql/0")
+	abort := make(chan struct{})
+
+	storageTag := names.NewStorageTag("data/0")
+	var acknowledged int
+	st := &mockStorageAccessor{
+		unitStorageAttachments: func(u names.UnitTag) ([]params.StorageAttachmentId, error) {
+			return nil, nil
+		},
+		acknowledgeMigration: func(s names.StorageTag, u names.UnitTag) error {
+			acknowledged++
+			return nil
+		},
+	}
+
+	att, err := storage.NewAttachments(st, unitTag, stateDir, abort)
+	c.Assert(err, jc.ErrorIsNil)
+	r := storage.NewResolver(att, s.modelType)
+
+	// The charm has not been told of the storage, so there is
+	// no need to wait for it to stop using the storage.
+	localState := resolver.LocalState{State: operation.State{
+		Kind:      operation.Continue,
+		Installed: true,
+	}}
+	_, err = r.NextOp(localState, remotestate.Snapshot{
+		Life: params.Alive,
+		Storage: map[names.StorageTag]remotestate.StorageSnapshot{
+			storageTag: {
+				Kind:      params.StorageKindBlock,
+				Life:      params.Alive,
+				Location:  "/dev/sdb",
+				Attached:  true,
+				Migration: params.StorageMigrationPending,
+			},
+		},
+	}, &mockOperations{})
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	c.Assert(acknowledged, gc.Equals, 1)
+	c.Assert(att.Pending(), gc.Equals, 1)
+}
+
 func (s *attachmentsSuite) TestAttachmentsSetDying(c *gc.C) {
 	stateDir := c.MkDir()
 	unitTag := names.NewUnitTag("mysql/0")
@@ -18,6 +18,7 @@ type mockStorageAccessor struct {
 	unitStorageAttachments        func(names.UnitTag) ([]params.StorageAttachmentId, error)
 	destroyUnitStorageAttachments func(names.UnitTag) error
 	remove                        func(names.StorageTag, names.UnitTag) error
+	acknowledgeMigration          func(names.StorageTag, names.UnitTag) error
 }
 
 func (m *mockStorageAccessor) StorageAttachment(s names.StorageTag, u names.UnitTag) (params.StorageAttachment, error) {
@@ -36,6 +37,10 @@ func (m *mockStorageAccessor) RemoveStorageAttachment(s names.StorageTag, u name
 	return m.remove(s, u)
 }
 
+func (m *mockStorageAccessor) AcknowledgeStorageMigration(s names.StorageTag, u names.UnitTag) error {
+	return m.acknowledgeMigration(s, u)
+}
+
 type mockOperations struct {
 	operation.Factory
 }
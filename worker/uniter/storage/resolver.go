@@ -139,13 +139,52 @@ func (s *storageResolver) nextHookOp(
 	}
 
 	hookInfo := hook.Info{StorageId: tag.Id()}
+	var migrating bool
 	switch snap.Life {
 	case params.Alive:
 		storageAttachment, ok := s.storage.storageAttachments[tag]
-		if ok && storageAttachment.attached {
-			// Once the storage is attached, we only care about
-			// lifecycle state changes.
+		attached := ok && storageAttachment.attached
+		switch snap.Migration {
+		case "":
+			delete(s.storage.migrationAcks, tag)
+		case params.StorageMigrationPending:
+			if attached {
+				// The storage is to be migrated, so the charm
+				// must stop using it before the data is copied.
+				hookInfo.Kind = hooks.StorageDetaching
+				migrating = true
+				break
+			}
+			// The charm is not using the storage, so there
+			// is nothing to wait for.
+			s.storage.pending.Add(tag)
+			if err := s.storage.acknowledgeMigration(tag, snap.Migration); err != nil {
+				return nil, errors.Trace(err)
+			}
+			return nil, resolver.ErrNoOperation
+		case params.StorageMigrationCopying:
+			// The storage's data is being copied; the charm
+			// is told of the storage once that is done.
+			s.storage.pending.Add(tag)
 			return nil, resolver.ErrNoOperation
+		case params.StorageMigrationAttaching:
+			if attached {
+				// The storage-attached hook has been committed
+				// against the migrated storage.
+				if err := s.storage.acknowledgeMigration(tag, snap.Migration); err != nil {
+					return nil, errors.Trace(err)
+				}
+				return nil, resolver.ErrNoOperation
+			}
+			migrating = true
+		}
+		if hookInfo.Kind == hooks.StorageDetaching {
+			break
+		}
+		if attached {
+			// Once the storage is attached, we only care about
+			// lifecycle state changes and growth of the storage.
+			return s.maybeResizeOp(storageAttachment, hookInfo, snap, opFactory)
 		}
 		// The storage-attached hook has not been committed, so add the
 		// storage to the pending set.
@@ -179,12 +218,42 @@ func (s *storageResolver) nextHookOp(
 		return nil, errors.Trace(err)
 	}
 	s.storage.storageAttachments[tag] = storageAttachment{
-		stateFile, &contextStorage{
+		stateFile: stateFile,
+		contextStorage: &contextStorage{
 			tag:      tag,
 			kind:     storage.StorageKind(snap.Kind),
 			location: snap.Location,
+			size:     snap.Size,
 		},
+		migrating: migrating,
 	}
 
 	return opFactory.NewRunHook(hookInfo)
 }
+
+// maybeResizeOp returns an operation to run the storage-resized hook
+// if the attached storage has grown since the charm was last informed
+// of its size.
+func (s *storageResolver) maybeResizeOp(
+	storageAttachment storageAttachment,
+	hookInfo hook.Info,
+	snap remotestate.StorageSnapshot,
+	opFactory operation.Factory,
+) (operation.Operation, error) {
+	recordedSize := storageAttachment.stateFile.size
+	if snap.Size <= recordedSize {
+		return nil, resolver.ErrNoOperation
+	}
+	storageAttachment.contextStorage.size = snap.Size
+	if recordedSize == 0 {
+		// The size of the storage was not known when the
+		// storage-attached hook ran, so there is nothing
+		// to compare against; just record the size.
+		if err := storageAttachment.stateFile.recordSize(snap.Size); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return nil, resolver.ErrNoOperation
+	}
+	hookInfo.Kind = hook.StorageResized
+	return opFactory.NewRunHook(hookInfo)
+}